@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// sdListenFDsStart is the first file descriptor systemd passes with socket activation.
+const sdListenFDsStart = 3
+
+// listen returns the server's listener. Under systemd socket activation it is the socket
+// systemd passed in, which keeps accepting connections into its backlog while Warden
+// restarts. Otherwise a new socket is bound to addr, with SO_REUSEPORT if reusePort is
+// set, so a new process can bind the port while the old one drains its requests.
+func listen(addr string, reusePort bool) (net.Listener, error) {
+	if ln, ok, err := systemdListener(); ok || err != nil {
+		return ln, err
+	}
+	lc := net.ListenConfig{}
+	if reusePort {
+		lc.Control = reusePortControl
+	}
+	return lc.Listen(context.Background(), "tcp", addr)
+}
+
+// systemdListener returns the first socket passed by systemd socket activation, and
+// false when the process wasn't socket-activated. Like sd_listen_fds, it unsets the
+// activation variables so child processes don't pick them up.
+func systemdListener() (net.Listener, bool, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, false, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, false, nil
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	f := os.NewFile(uintptr(sdListenFDsStart), "LISTEN_FD_3")
+	ln, err := net.FileListener(f)
+	_ = f.Close() // FileListener dups the descriptor
+	if err != nil {
+		return nil, true, fmt.Errorf("use socket from systemd: %w", err)
+	}
+	return ln, true, nil
+}
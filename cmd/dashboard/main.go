@@ -2,19 +2,32 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/projecthelena/warden/internal/agents"
 	"github.com/projecthelena/warden/internal/api"
 	"github.com/projecthelena/warden/internal/config"
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/demo"
+	"github.com/projecthelena/warden/internal/discovery"
+	"github.com/projecthelena/warden/internal/issues"
 	"github.com/projecthelena/warden/internal/logging"
+	"github.com/projecthelena/warden/internal/notifications"
+	"github.com/projecthelena/warden/internal/statusmirror"
 	"github.com/projecthelena/warden/internal/uptime"
 )
 
+// shutdownTimeout bounds a graceful shutdown, within the 10 seconds Docker waits after
+// SIGTERM before killing the container.
+const shutdownTimeout = 8 * time.Second
+
 // @title           Warden API
 // @version         1.0
 // @description     Self-hosted uptime monitoring API by Project Helena.
@@ -24,12 +37,33 @@ import (
 // @name            Authorization
 // @description     Enter "Bearer sk_live_..." — create keys in Settings > API Keys
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate-db" {
+		os.Exit(runMigrateDB(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	demoMode := flag.Bool("demo", false, "run a seeded demo in memory; checks and notifications never leave the process")
+	demoReset := flag.Duration("demo-reset", time.Hour, "how often demo mode discards changes and reseeds (0 disables)")
+	healthCheck := flag.Bool("health-check", false, "check whether the server on LISTEN_ADDR is ready and exit 0 if so (for Docker HEALTHCHECK)")
+	flag.Parse()
+
 	logger := logging.New("warden")
 
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Fatalf("load config: %v", err)
 	}
+	if *healthCheck {
+		os.Exit(runHealthCheck(cfg.ListenAddr, os.Stdout, os.Stderr))
+	}
+	if *demoMode {
+		// Everything lives in memory and goes with the process
+		cfg.Demo = true
+		cfg.DBType = db.DialectSQLite
+		cfg.DBPath = ":memory:"
+		cfg.ChecksDBURL = ""
+		cfg.HAMode = false
+		cfg.ReadOnly = false
+	}
 
 	// monitor := uptime.NewMonitor(cfg) // Removed
 
@@ -50,13 +84,53 @@ func main() {
 	}
 	log.Printf("Database initialized (dialect: %s)", store.Dialect())
 	defer func() { _ = store.Close() }()
+	if cfg.ChecksDBURL != "" {
+		checks, err := db.OpenCheckStore(cfg.ChecksDBURL)
+		if err != nil {
+			log.Fatal("Failed to init check store:", err)
+		}
+		store.SetCheckStore(checks)
+		scheme, _, _ := strings.Cut(cfg.ChecksDBURL, "://")
+		log.Printf("Check results stored in a separate %s database", scheme)
+	}
+
+	if !cfg.Demo {
+		created, err := api.BootstrapAdmin(store, cfg)
+		if err != nil {
+			log.Fatal("Failed to create admin user from environment:", err)
+		}
+		if created {
+			log.Printf("Setup completed from environment: admin user %q created", cfg.AdminUser)
+		}
+	}
 
 	// Init Uptime Manager
 	manager := uptime.NewManager(store)
-	manager.Start()
-	defer manager.Stop()
+	if cfg.Demo {
+		if err := demo.Seed(store, time.Now()); err != nil {
+			log.Fatal("Failed to seed demo data:", err)
+		}
+		transport := demo.NewTransport()
+		manager.SetCheckTransport(transport)
+		manager.SetTracer(demo.Trace)
+		notifications.SetTransport(transport)
+		issues.SetTransport(transport)
+		statusmirror.SetTransport(transport)
+	} else {
+		manager.SetSpool(uptime.NewCheckSpool(cfg.SpoolPath))
+	}
+	manager.SetBlockPrivateTargets(cfg.BlockPrivateTargets)
+	manager.SetHistorySize(cfg.HistorySize)
+	if cfg.HAMode {
+		// Serve the API right away but leave scheduling to whichever instance holds the lock
+		manager.SetStandby(true)
+	}
 
-	// Init Router
+	// Open a Jira or GitHub issue for every new incident, if configured
+	issues.NewService(store).Start()
+
+	// Init Router. The server starts before the manager loads monitors: /readyz reports
+	// warming up and dashboard responses show last-known statuses until it has.
 	r := api.NewRouter(manager, store, cfg) // Changed monitor to manager
 
 	srv := &http.Server{
@@ -65,22 +139,66 @@ func main() {
 		ReadHeaderTimeout: 10 * time.Second, // Prevent Slowloris attacks
 	}
 
+	ln, err := listen(cfg.ListenAddr, cfg.ReusePort)
+	if err != nil {
+		log.Fatalf("listen: %s\n", err)
+	}
 	go func() {
-		log.Printf("Starting server on %s", cfg.ListenAddr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Starting server on %s", ln.Addr())
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("listen: %s\n", err)
 		}
 	}()
 
+	manager.Start()
+	if cfg.HAMode {
+		log.Println("HA mode enabled, waiting for leader election")
+		go manager.RunLeaderElection(ctx, uptime.LeaderElectionInterval)
+	}
+
+	if cfg.Demo {
+		log.Printf("Demo mode: sign in as %q with password %q; the public status page is at /status/%s", demo.Username, demo.Password, demo.StatusPageSlug)
+		if *demoReset > 0 {
+			go demo.ResetEvery(ctx, store, manager, *demoReset)
+		}
+	} else {
+		// Init Cost Agent Poller. Demo mode has no agents and must not poll what visitors add.
+		poller := agents.NewPoller(store, manager)
+		poller.SetLeader(manager)
+		poller.Start()
+		defer poller.Stop()
+
+		// Manage monitors for labelled containers, if enabled
+		if cfg.DockerDiscovery {
+			docker, err := discovery.NewDocker(store, manager, cfg.DockerHost)
+			if err != nil {
+				log.Fatal("Docker discovery: ", err)
+			}
+			docker.Start()
+			defer docker.Stop()
+			log.Printf("Docker discovery enabled (%s)", cfg.DockerHost)
+		}
+	}
+
+	// Copy status pages to their hosted Statuspage.io or Instatus pages, if configured
+	mirror := statusmirror.NewSyncer(store, manager)
+	mirror.SetLeader(manager)
+	mirror.Start()
+	defer mirror.Stop()
+
 	// Wait for interrupt signal
 	<-ctx.Done()
 	log.Println("Shutting down server...")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
+	// Finish in-flight requests, then the checks already running, so a restart drops neither
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+	if err := manager.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Stopped before all running checks were saved: %v", err)
 	}
 
 	log.Println("Server exiting")
@@ -0,0 +1,19 @@
+//go:build linux && (amd64 || 386 || arm)
+
+package main
+
+import "syscall"
+
+// soReusePort is SO_REUSEPORT, which the syscall package lacks on these architectures.
+const soReusePort = 0xf
+
+// reusePortControl sets SO_REUSEPORT on a listening socket before it is bound.
+func reusePortControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !linux
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePortControl fails: SO_REUSEPORT isn't available on this platform.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return errors.New("REUSE_PORT is not supported on this platform")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -9,42 +10,98 @@ import (
 	"log"
 	"net/http"
 	"net/http/cookiejar"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
 )
 
-const baseURL = "http://localhost:9096" // Adjust port if running on different port
+// config holds the command line flags.
+type config struct {
+	baseURL     string
+	apiKey      string
+	username    string
+	password    string
+	count       int
+	delete      bool
+	scenarios   string
+	statusPage  string
+	concurrency int
+	duration    time.Duration
+}
 
 func main() {
-	count := flag.Int("count", 50, "Number of monitors to create")
-	delete := flag.Bool("delete", false, "Delete created monitors after wait")
+	var cfg config
+	flag.StringVar(&cfg.baseURL, "base-url", "http://localhost:9096", "Warden server to test")
+	flag.StringVar(&cfg.apiKey, "api-key", "", "API key to authenticate with instead of logging in")
+	flag.StringVar(&cfg.username, "username", "admin", "User to log in as when no API key is given")
+	flag.StringVar(&cfg.password, "password", "password", "Password to log in with when no API key is given")
+	flag.IntVar(&cfg.count, "count", 50, "Number of monitors to create")
+	flag.BoolVar(&cfg.delete, "delete", false, "Delete created monitors after wait")
+	flag.StringVar(&cfg.scenarios, "scenarios", "", "Comma-separated load scenarios to run: churn, status-page, incidents")
+	flag.StringVar(&cfg.statusPage, "status-page", "", "Slug of the public status page the status-page scenario reads")
+	flag.IntVar(&cfg.concurrency, "concurrency", 10, "Workers per scenario")
+	flag.DurationVar(&cfg.duration, "duration", 30*time.Second, "How long to run the scenarios")
 	flag.Parse()
 
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(cfg.scenarios, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if _, ok := scenarios[name]; !ok {
+			log.Fatalf("Unknown scenario %q (available: churn, status-page, incidents)", name)
+		}
+		selected[name] = true
+	}
+	if selected["status-page"] && cfg.statusPage == "" {
+		log.Fatal("The status-page scenario needs -status-page")
+	}
+	if cfg.concurrency < 1 {
+		log.Fatal("-concurrency must be at least 1")
+	}
+
 	// 1. Setup Client with Cookie Jar
 	jar, _ := cookiejar.New(nil)
-	client := &http.Client{
-		Jar:     jar,
-		Timeout: 10 * time.Second,
+	c := &client{
+		baseURL: strings.TrimRight(cfg.baseURL, "/"),
+		apiKey:  cfg.apiKey,
+		http: &http.Client{
+			Jar:     jar,
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				// One connection per worker, as many clients would open
+				MaxIdleConnsPerHost: cfg.concurrency * max(len(selected), 1),
+			},
+		},
+		rec: newRecorder(),
 	}
 
-	// 2. Login
-	log.Println("Logging in...")
-	if err := login(client, "admin", "password"); err != nil {
-		// Try admin/password if admin/admin fails? No, "admin" usually has password "admin" in dev seed.
-		// If fails, we can't proceed.
-		log.Fatalf("Login failed: %v", err)
+	// 2. Login, unless an API key authenticates each request
+	if cfg.apiKey == "" {
+		log.Println("Logging in...")
+		if err := login(c, cfg.username, cfg.password); err != nil {
+			log.Fatalf("Login failed: %v", err)
+		}
 	}
 
 	// 3. Create Group
-	groupID, err := createGroup(client, "Stress Test Group")
-	if err != nil {
-		log.Fatalf("Failed to create group: %v", err)
+	var groupID string
+	if cfg.count > 0 || selected["churn"] {
+		id, err := createGroup(c, "Stress Test Group")
+		if err != nil {
+			log.Fatalf("Failed to create group: %v", err)
+		}
+		groupID = id
+		log.Printf("Created group %s\n", groupID)
 	}
-	log.Printf("Created group %s\n", groupID)
 
 	// 4. Create Monitors
-	log.Printf("Creating %d monitors...\n", *count)
 	var monitorIDs []string
-	for i := 0; i < *count; i++ {
+	if cfg.count > 0 {
+		log.Printf("Creating %d monitors...\n", cfg.count)
+	}
+	for i := 0; i < cfg.count; i++ {
 		// Alternate between 200 and 500 to trigger notifications
 		status := 200
 		if i%2 == 0 {
@@ -54,7 +111,7 @@ func main() {
 		name := fmt.Sprintf("Stress Monitor %d (%d)", i, status)
 		url := fmt.Sprintf("https://httpbin.org/status/%d", status)
 
-		id, err := createMonitor(client, name, url, groupID)
+		id, err := createMonitor(c, name, url, groupID)
 		if err != nil {
 			log.Printf("Failed to create monitor %d: %v", i, err)
 			continue
@@ -67,110 +124,140 @@ func main() {
 		// Small sleep to not overwhelm completely
 		time.Sleep(50 * time.Millisecond)
 	}
-	fmt.Println("\nDone creating monitors.")
+	if cfg.count > 0 {
+		fmt.Println("\nDone creating monitors.")
+	}
+
+	// 5. Run the load scenarios; Ctrl-C stops them early and still prints the report
+	if len(selected) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+		running := make(map[string]scenario, len(selected))
+		for name := range selected {
+			running[name] = scenarios[name](cfg, groupID)
+		}
+		log.Printf("Running %s with %d workers each for %s...", cfg.scenarios, cfg.concurrency, cfg.duration)
+		run(ctx, c, cfg.concurrency, running)
+		cancel()
+		stop()
+	}
 
-	if *delete {
+	if cfg.delete && groupID != "" {
 		log.Println("Waiting 30 seconds before deletion...")
 		time.Sleep(30 * time.Second)
 		log.Println("Deleting monitors...")
 		for _, id := range monitorIDs {
-			if err := deleteMonitor(client, id); err != nil {
+			if err := deleteMonitor(c, id); err != nil {
 				log.Printf("Failed to delete monitor %s: %v", id, err)
 			}
 		}
 		log.Println("Deleting group...")
-		if err := deleteGroup(client, groupID); err != nil {
+		if err := deleteGroup(c, groupID); err != nil {
 			log.Printf("Failed to delete group: %v", err)
 		}
 		log.Println("Cleanup done.")
 	}
+
+	fmt.Println()
+	c.rec.write(os.Stdout)
 }
 
-func login(client *http.Client, username, password string) error {
-	payload := map[string]string{"username": username, "password": password}
-	data, _ := json.Marshal(payload)
-	resp, err := client.Post(baseURL+"/api/auth/login", "application/json", bytes.NewBuffer(data))
+// client sends API requests and records their latency under the endpoint's route.
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+	rec     *recorder
+}
+
+// do sends the request and decodes the response into out. endpoint names the route
+// in the report and defaults to the method and path. Responses of 400 and up are
+// errors.
+func (c *client) do(method, path, endpoint string, body, out interface{}) error {
+	if endpoint == "" {
+		endpoint = method + " " + path
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status %d", resp.StatusCode)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
-	return nil
-}
 
-func createGroup(client *http.Client, name string) (string, error) {
-	payload := map[string]string{"name": name}
-	data, _ := json.Marshal(payload)
-	resp, err := client.Post(baseURL+"/api/groups", "application/json", bytes.NewBuffer(data))
+	start := time.Now()
+	resp, err := c.http.Do(req) // #nosec G704 -- the operator chooses the server to test with -base-url
 	if err != nil {
-		return "", err
+		c.rec.record(endpoint, sample{latency: time.Since(start), failed: true})
+		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	data, err := io.ReadAll(resp.Body)
+	failed := err != nil || resp.StatusCode >= 400
+	c.rec.record(endpoint, sample{latency: time.Since(start), status: resp.StatusCode, failed: failed})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s: status %d: %s", endpoint, resp.StatusCode, bytes.TrimSpace(data))
 	}
-	var res map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func login(c *client, username, password string) error {
+	payload := map[string]string{"username": username, "password": password}
+	return c.do("POST", "/api/auth/login", "", payload, nil)
+}
+
+func createGroup(c *client, name string) (string, error) {
+	var res struct {
+		ID string `json:"id"`
+	}
+	if err := c.do("POST", "/api/groups", "", map[string]string{"name": name}, &res); err != nil {
 		return "", err
 	}
-	return res["id"].(string), nil
+	return res.ID, nil
 }
 
-func createMonitor(client *http.Client, name, url, groupID string) (string, error) {
+func createMonitor(c *client, name, url, groupID string) (string, error) {
 	payload := map[string]interface{}{
 		"name":     name,
 		"url":      url,
 		"groupId":  groupID,
 		"interval": 60,
+		// Stress monitors share a handful of URLs
+		"allowDuplicateUrl": true,
 	}
-	data, _ := json.Marshal(payload)
-	resp, err := client.Post(baseURL+"/api/monitors", "application/json", bytes.NewBuffer(data))
-	if err != nil {
-		return "", err
+	var res struct {
+		ID string `json:"id"`
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		return "", fmt.Errorf("status %d", resp.StatusCode)
-	}
-	var res map[string]interface{} // API currently returns the monitor object
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+	if err := c.do("POST", "/api/monitors", "", payload, &res); err != nil {
 		return "", err
 	}
-
-	// Depending on API response structure, ID might be direct or nested?
-	// Based on handlers_monitors.go, it returns the monitor JSON.
-	// Monitor struct has "id" json tag.
-	if id, ok := res["id"].(string); ok {
-		return id, nil
+	if res.ID == "" {
+		return "", fmt.Errorf("no id in response")
 	}
-	return "", fmt.Errorf("no id in response")
+	return res.ID, nil
 }
 
-func deleteMonitor(client *http.Client, id string) error {
-	req, _ := http.NewRequest("DELETE", baseURL+"/api/monitors/"+id, nil)
-	resp, err := client.Do(req) // #nosec G704 -- baseURL is hardcoded localhost
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status %d", resp.StatusCode)
-	}
-	return nil
+func deleteMonitor(c *client, id string) error {
+	return c.do("DELETE", "/api/monitors/"+id, "DELETE /api/monitors/{id}", nil, nil)
 }
 
-func deleteGroup(client *http.Client, id string) error {
-	req, _ := http.NewRequest("DELETE", baseURL+"/api/groups/"+id, nil)
-	resp, err := client.Do(req) // #nosec G704 -- baseURL is hardcoded localhost
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status %d", resp.StatusCode)
-	}
-	return nil
+func deleteGroup(c *client, id string) error {
+	return c.do("DELETE", "/api/groups/"+id, "DELETE /api/groups/{id}", nil, nil)
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// sample is the outcome of one request.
+type sample struct {
+	latency time.Duration
+	status  int // 0 when the request failed before a response
+	failed  bool
+}
+
+// recorder collects samples per endpoint. Endpoints are route patterns such as
+// "DELETE /api/monitors/{id}", so requests for different IDs add up.
+type recorder struct {
+	mu      sync.Mutex
+	started time.Time
+	samples map[string][]sample
+}
+
+func newRecorder() *recorder {
+	return &recorder{started: time.Now(), samples: make(map[string][]sample)}
+}
+
+func (r *recorder) record(endpoint string, s sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[endpoint] = append(r.samples[endpoint], s)
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(float64(len(sorted))*p/100+0.5) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// statusCodes summarizes the responses, e.g. "201x40 429x3 errx1".
+func statusCodes(samples []sample) string {
+	counts := make(map[string]int)
+	for _, s := range samples {
+		if s.status == 0 {
+			counts["err"]++
+		} else {
+			counts[fmt.Sprint(s.status)]++
+		}
+	}
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for i, code := range codes {
+		codes[i] = fmt.Sprintf("%sx%d", code, counts[code])
+	}
+	return strings.Join(codes, " ")
+}
+
+// write prints the latency and error summary, one line per endpoint.
+func (r *recorder) write(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoints := make([]string, 0, len(r.samples))
+	for endpoint := range r.samples {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	elapsed := time.Since(r.started)
+	total, totalErrors := 0, 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tERRORS\tP50\tP95\tMAX\tSTATUS")
+	for _, endpoint := range endpoints {
+		samples := r.samples[endpoint]
+		latencies := make([]time.Duration, 0, len(samples))
+		errors := 0
+		for _, s := range samples {
+			latencies = append(latencies, s.latency)
+			if s.failed {
+				errors++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		total += len(samples)
+		totalErrors += errors
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", endpoint, len(samples), errors,
+			percentile(latencies, 50).Round(time.Microsecond*100),
+			percentile(latencies, 95).Round(time.Microsecond*100),
+			latencies[len(latencies)-1].Round(time.Microsecond*100),
+			statusCodes(samples))
+	}
+	_ = tw.Flush()
+	_, _ = fmt.Fprintf(w, "\n%d requests, %d errors in %s (%.1f req/s)\n",
+		total, totalErrors, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+}
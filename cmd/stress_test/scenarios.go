@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// scenario is one iteration of a load pattern. Workers run it in a loop until the
+// test ends; n numbers the iterations across all workers.
+type scenario func(c *client, n int64) error
+
+// scenarios are the load patterns -scenarios selects from.
+var scenarios = map[string]func(cfg config, groupID string) scenario{
+	"churn":       monitorChurn,
+	"status-page": statusPageReads,
+	"incidents":   incidentCreation,
+}
+
+// monitorChurn creates a monitor and deletes it again, exercising the scheduler's
+// add and remove paths along with the CRUD endpoints.
+func monitorChurn(_ config, groupID string) scenario {
+	return func(c *client, n int64) error {
+		id, err := createMonitor(c, fmt.Sprintf("Stress Churn %d", n), "https://httpbin.org/status/200", groupID)
+		if err != nil {
+			return err
+		}
+		return deleteMonitor(c, id)
+	}
+}
+
+// statusPageReads loads a public status page and its incident archive, as visitors
+// and status widgets do during an outage.
+func statusPageReads(cfg config, _ string) scenario {
+	return func(c *client, n int64) error {
+		if n%2 == 0 {
+			return c.do("GET", "/api/s/"+cfg.statusPage, "GET /api/s/{slug}", nil, nil)
+		}
+		return c.do("GET", "/api/s/"+cfg.statusPage+"/incidents.json", "GET /api/s/{slug}/incidents.json", nil, nil)
+	}
+}
+
+// incidentCreation opens a private incident and deletes it, so status page
+// subscribers aren't notified.
+func incidentCreation(_ config, _ string) scenario {
+	return func(c *client, n int64) error {
+		payload := map[string]interface{}{
+			"title":     fmt.Sprintf("Stress Incident %d", n),
+			"severity":  "minor",
+			"status":    "investigating",
+			"startTime": time.Now().UTC().Format(time.RFC3339),
+			"public":    false,
+		}
+		var res struct {
+			ID string `json:"id"`
+		}
+		if err := c.do("POST", "/api/incidents", "", payload, &res); err != nil {
+			return err
+		}
+		return c.do("DELETE", "/api/incidents/"+res.ID, "DELETE /api/incidents/{id}", nil, nil)
+	}
+}
+
+// run starts concurrency workers per scenario and waits until ctx is done. Workers
+// finish their current iteration, so a scenario never leaves a monitor or incident
+// half-way.
+func run(ctx context.Context, c *client, concurrency int, selected map[string]scenario) {
+	var wg sync.WaitGroup
+	for name, sc := range selected {
+		var n atomic.Int64
+		var failures atomic.Int64
+		for range concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ctx.Err() == nil {
+					if err := sc(c, n.Add(1)); err != nil {
+						// Log the first few failures; the report counts them all
+						if failures.Add(1) <= 5 {
+							log.Printf("%s: %v", name, err)
+						}
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,91 @@
+package agents
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// BudgetStatus is a budget's spend for the current calendar month (UTC).
+type BudgetStatus struct {
+	Period         string  `json:"period"` // YYYY-MM
+	MonthToDate    float64 `json:"monthToDate"`
+	Projected      float64 `json:"projected"`
+	HoursObserved  int     `json:"hoursObserved"`
+	PercentOfLimit float64 `json:"percentOfLimit"` // Projected spend relative to the limit
+	Exceeded       bool    `json:"exceeded"`
+}
+
+// EvaluateBudget computes month-to-date and projected spend for a budget from the hourly
+// cost history. The projection extrapolates the average observed hourly rate to the whole
+// month, so gaps in polling do not skew it.
+func EvaluateBudget(store *db.Store, b db.CostBudget, now time.Time) (BudgetStatus, error) {
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	status := BudgetStatus{Period: monthStart.Format("2006-01")}
+
+	entries, err := store.GetCostHistory(b.AgentID, b.Scope, monthStart)
+	if err != nil {
+		return status, err
+	}
+
+	hours := make(map[time.Time]bool)
+	for _, e := range entries {
+		if e.Name != b.ScopeValue {
+			continue
+		}
+		status.MonthToDate += e.HourlyCost
+		hours[e.Bucket.UTC()] = true
+	}
+	status.HoursObserved = len(hours)
+	if status.HoursObserved > 0 {
+		avg := status.MonthToDate / float64(status.HoursObserved)
+		status.Projected = avg * monthEnd.Sub(monthStart).Hours()
+	}
+	if b.MonthlyLimit > 0 {
+		status.PercentOfLimit = status.Projected / b.MonthlyLimit * 100
+	}
+	status.Exceeded = status.Projected > b.MonthlyLimit
+	return status, nil
+}
+
+// evaluateBudgets notifies once per month for each enabled budget whose projected spend exceeds its limit.
+func (p *Poller) evaluateBudgets(now time.Time) {
+	budgets, err := p.store.GetCostBudgets()
+	if err != nil {
+		log.Printf("Agents: failed to load budgets: %v", err)
+		return
+	}
+
+	for _, b := range budgets {
+		if !b.Enabled {
+			continue
+		}
+		status, err := EvaluateBudget(p.store, b, now)
+		if err != nil {
+			log.Printf("Agents: failed to evaluate budget %d: %v", b.ID, err)
+			continue
+		}
+		if !status.Exceeded || b.LastNotifiedPeriod == status.Period {
+			continue
+		}
+
+		if p.notifier != nil {
+			p.notifier.Notify(notifications.NotificationEvent{
+				MonitorID:   fmt.Sprintf("budget-%d", b.ID),
+				MonitorName: b.Name,
+				Type:        notifications.EventBudgetExceeded,
+				Message: fmt.Sprintf("Projected %s spend is $%.2f, %.0f%% of the $%.2f monthly budget ($%.2f so far)",
+					status.Period, status.Projected, status.PercentOfLimit, b.MonthlyLimit, status.MonthToDate),
+				Time: now,
+			})
+		}
+		if err := p.store.SetCostBudgetNotified(b.ID, status.Period); err != nil {
+			log.Printf("Agents: failed to mark budget %d notified: %v", b.ID, err)
+		}
+	}
+}
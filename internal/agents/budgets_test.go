@@ -0,0 +1,82 @@
+package agents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+type fakeNotifier struct {
+	events []notifications.NotificationEvent
+}
+
+func (f *fakeNotifier) Notify(event notifications.NotificationEvent) {
+	f.events = append(f.events, event)
+}
+
+func TestEvaluateBudget(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "prod", URL: "http://agent", Active: true, Interval: 300})
+
+	// 10 hours observed at $2/h in a 30-day month: $20 so far, $1440 projected
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	var entries []db.CostHistoryEntry
+	for h := 0; h < 10; h++ {
+		bucket := time.Date(2026, 9, 1, h, 0, 0, 0, time.UTC)
+		entries = append(entries,
+			db.CostHistoryEntry{AgentID: "a1", Bucket: bucket, Scope: db.CostScopeCluster, HourlyCost: 2},
+			db.CostHistoryEntry{AgentID: "a1", Bucket: bucket, Scope: db.CostScopeNamespace, Name: "api", HourlyCost: 0.5},
+		)
+	}
+	if err := store.RecordCostHistory(entries); err != nil {
+		t.Fatalf("RecordCostHistory failed: %v", err)
+	}
+
+	status, err := EvaluateBudget(store, db.CostBudget{Scope: db.CostScopeCluster, MonthlyLimit: 1000}, now)
+	if err != nil {
+		t.Fatalf("EvaluateBudget failed: %v", err)
+	}
+	if status.Period != "2026-09" || status.MonthToDate != 20 || status.HoursObserved != 10 || status.Projected != 1440 || !status.Exceeded {
+		t.Errorf("Unexpected cluster status: %+v", status)
+	}
+
+	status, _ = EvaluateBudget(store, db.CostBudget{Scope: db.CostScopeNamespace, ScopeValue: "api", MonthlyLimit: 1000}, now)
+	if status.Projected != 360 || status.Exceeded {
+		t.Errorf("Unexpected namespace status: %+v", status)
+	}
+}
+
+func TestPoller_BudgetNotifiesOncePerPeriod(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "prod", URL: "http://agent", Active: true, Interval: 300})
+	now := time.Now().UTC()
+	_ = store.RecordCostHistory([]db.CostHistoryEntry{{AgentID: "a1", Bucket: now, Scope: db.CostScopeCluster, HourlyCost: 5}})
+	id, err := store.CreateCostBudget(db.CostBudget{Name: "Cluster", Scope: db.CostScopeCluster, MonthlyLimit: 100, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateCostBudget failed: %v", err)
+	}
+
+	notifier := &fakeNotifier{}
+	p := NewPoller(store, notifier)
+	p.evaluateBudgets(now)
+	p.evaluateBudgets(now)
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("Expected exactly 1 notification, got %d", len(notifier.events))
+	}
+	if notifier.events[0].Type != notifications.EventBudgetExceeded {
+		t.Errorf("Expected budget_exceeded event, got %s", notifier.events[0].Type)
+	}
+	b, _ := store.GetCostBudget(id)
+	if b.LastNotifiedPeriod != now.Format("2006-01") {
+		t.Errorf("Expected notified period to be recorded, got %q", b.LastNotifiedPeriod)
+	}
+}
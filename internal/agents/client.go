@@ -0,0 +1,159 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes caps a single agent response so a misbehaving agent cannot exhaust memory.
+const maxResponseBytes = 10 << 20
+
+// Summary mirrors the agent's GET /api/cost/summary response.
+type Summary struct {
+	ClusterName             string                 `json:"clusterName"`
+	Provider                string                 `json:"provider"`
+	Region                  string                 `json:"region"`
+	TotalHourlyCost         float64                `json:"totalHourlyCost"`
+	TotalCPUCores           float64                `json:"totalCpuCores"`
+	TotalCPURequestedCores  float64                `json:"totalCpuRequestedCores"`
+	TotalMemoryGiB          float64                `json:"totalMemoryGiB"`
+	TotalMemoryRequestedGiB float64                `json:"totalMemoryRequestedGiB"`
+	TopNamespaces           []NamespaceShare       `json:"topNamespaces"`
+	CostByLabel             map[string][]LabelCost `json:"costByLabel"`
+	CostByInstanceType      []InstanceTypeCost     `json:"costByInstanceType"`
+}
+
+type NamespaceShare struct {
+	Namespace  string  `json:"namespace"`
+	HourlyCost float64 `json:"hourlyCost"`
+}
+
+type LabelCost struct {
+	Value      string  `json:"value"`
+	HourlyCost float64 `json:"hourlyCost"`
+}
+
+type InstanceTypeCost struct {
+	InstanceType string  `json:"instanceType"`
+	NodeCount    int     `json:"nodeCount"`
+	HourlyCost   float64 `json:"hourlyCost"`
+}
+
+// Namespace mirrors an entry of GET /api/cost/namespaces.
+type Namespace struct {
+	Namespace          string  `json:"namespace"`
+	Team               string  `json:"team,omitempty"`
+	Env                string  `json:"env,omitempty"`
+	HourlyCost         float64 `json:"hourlyCost"`
+	CPURequestedCores  float64 `json:"cpuRequestedCores"`
+	CPUUsedCores       float64 `json:"cpuUsedCores"`
+	MemoryRequestedGiB float64 `json:"memoryRequestedGiB"`
+	MemoryUsedGiB      float64 `json:"memoryUsedGiB"`
+	PodCount           int     `json:"podCount"`
+}
+
+// Node mirrors an entry of GET /api/cost/nodes.
+type Node struct {
+	Name                 string  `json:"name"`
+	InstanceType         string  `json:"instanceType"`
+	AvailabilityZone     string  `json:"availabilityZone"`
+	RawNodePriceHourly   float64 `json:"rawNodePriceHourly"`
+	AllocatedCostHourly  float64 `json:"allocatedCostHourly"`
+	CPUAllocatableCores  float64 `json:"cpuAllocatableCores"`
+	CPURequestedCores    float64 `json:"cpuRequestedCores"`
+	CPUUsedCores         float64 `json:"cpuUsedCores"`
+	MemoryAllocatableGiB float64 `json:"memoryAllocatableGiB"`
+	MemoryRequestedGiB   float64 `json:"memoryRequestedGiB"`
+	MemoryUsedGiB        float64 `json:"memoryUsedGiB"`
+}
+
+// Workload mirrors an entry of GET /api/cost/workloads.
+type Workload struct {
+	Namespace          string   `json:"namespace"`
+	WorkloadKind       string   `json:"workloadKind"`
+	WorkloadName       string   `json:"workloadName"`
+	Team               string   `json:"team,omitempty"`
+	Env                string   `json:"env,omitempty"`
+	Replicas           int      `json:"replicas"`
+	HourlyCost         float64  `json:"hourlyCost"`
+	CPURequestedCores  float64  `json:"cpuRequestedCores"`
+	CPUUsedCores       float64  `json:"cpuUsedCores"`
+	MemoryRequestedGiB float64  `json:"memoryRequestedGiB"`
+	MemoryUsedGiB      float64  `json:"memoryUsedGiB"`
+	Nodes              []string `json:"nodes,omitempty"`
+}
+
+// Collection is everything gathered from an agent in a single poll.
+type Collection struct {
+	Summary    Summary
+	Namespaces []Namespace
+	Nodes      []Node
+	Workloads  []Workload
+}
+
+// Client talks to a single cost agent.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+// Collect fetches the summary, namespace, node and workload data from the agent.
+func (c *Client) Collect(ctx context.Context) (*Collection, error) {
+	var col Collection
+	if err := c.get(ctx, "/api/cost/summary", &col.Summary); err != nil {
+		return nil, err
+	}
+	if err := c.get(ctx, "/api/cost/namespaces", &col.Namespaces); err != nil {
+		return nil, err
+	}
+	if err := c.get(ctx, "/api/cost/nodes", &col.Nodes); err != nil {
+		return nil, err
+	}
+	if err := c.get(ctx, "/api/cost/workloads", &col.Workloads); err != nil {
+		return nil, err
+	}
+	return &col, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Warden-Agent-Poller/1.0")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req) // #nosec G704 -- agent URL is admin-configured and validated on registration
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+const (
+	// DefaultInterval is the poll interval for agents registered without one.
+	DefaultInterval = 300
+	// MinInterval is the shortest poll interval an agent may be configured with.
+	MinInterval = 30
+
+	tickInterval      = 15 * time.Second
+	budgetInterval    = 5 * time.Minute
+	snapshotRetention = 7 * 24 * time.Hour
+	historyRetention  = 400 * 24 * time.Hour
+)
+
+// Notifier delivers notifications through the configured channels (implemented by uptime.Manager).
+type Notifier interface {
+	Notify(event notifications.NotificationEvent)
+}
+
+// Leader reports whether this instance runs background jobs (implemented by uptime.Manager).
+type Leader interface {
+	IsLeader() bool
+}
+
+// Poller periodically collects cost data from every active agent and stores it as snapshots.
+type Poller struct {
+	store    *db.Store
+	notifier Notifier
+	leader   Leader
+	client   *http.Client
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+
+	mu              sync.Mutex
+	inFlight        map[string]bool
+	lastPrune       time.Time
+	lastBudgetCheck time.Time
+}
+
+// NewPoller creates a poller. notifier may be nil, in which case budget alerts are not sent.
+func NewPoller(store *db.Store, notifier Notifier) *Poller {
+	return &Poller{
+		store:    store,
+		notifier: notifier,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		stopCh:   make(chan struct{}),
+		inFlight: make(map[string]bool),
+	}
+}
+
+// SetLeader restricts polling to when l is the HA leader, so standby
+// instances don't poll agents twice or send duplicate budget alerts.
+func (p *Poller) SetLeader(l Leader) {
+	p.leader = l
+}
+
+func (p *Poller) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		p.pollDue()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.pollDue()
+			}
+		}
+	}()
+}
+
+func (p *Poller) Stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+}
+
+// pollDue polls every active agent whose interval has elapsed since its last poll.
+func (p *Poller) pollDue() {
+	if p.leader != nil && !p.leader.IsLeader() {
+		return
+	}
+	agents, err := p.store.GetAgents()
+	if err != nil {
+		log.Printf("Agents: failed to load agents: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, a := range agents {
+		if !a.Active {
+			continue
+		}
+		interval := a.Interval
+		if interval < MinInterval {
+			interval = MinInterval
+		}
+		if a.LastPolledAt != nil && now.Sub(*a.LastPolledAt) < time.Duration(interval)*time.Second {
+			continue
+		}
+		if !p.claim(a.ID) {
+			continue
+		}
+		p.wg.Add(1)
+		go func(a db.Agent) {
+			defer p.wg.Done()
+			defer p.release(a.ID)
+			_ = p.Poll(a)
+		}(a)
+	}
+
+	if now.Sub(p.lastBudgetCheck) > budgetInterval {
+		p.lastBudgetCheck = now
+		p.evaluateBudgets(now)
+	}
+
+	if now.Sub(p.lastPrune) > time.Hour {
+		p.lastPrune = now
+		if n, err := p.store.PruneAgentSnapshots(now.Add(-snapshotRetention)); err != nil {
+			log.Printf("Agents: failed to prune snapshots: %v", err)
+		} else if n > 0 {
+			log.Printf("Agents: pruned %d old snapshots", n)
+		}
+		if n, err := p.store.PruneCostHistory(now.Add(-historyRetention)); err != nil {
+			log.Printf("Agents: failed to prune cost history: %v", err)
+		} else if n > 0 {
+			log.Printf("Agents: pruned %d old cost history entries", n)
+		}
+	}
+}
+
+func (p *Poller) claim(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inFlight[id] {
+		return false
+	}
+	p.inFlight[id] = true
+	return true
+}
+
+func (p *Poller) release(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.inFlight, id)
+}
+
+// Poll collects data from one agent, stores a snapshot and records the outcome on the agent.
+func (p *Poller) Poll(a db.Agent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	col, err := NewClient(a.URL, a.Token, p.client).Collect(ctx)
+	if err != nil {
+		log.Printf("Agents: poll of %s failed: %v", a.ID, err)
+		if recErr := p.store.RecordAgentPoll(a.ID, now, err.Error()); recErr != nil {
+			log.Printf("Agents: failed to record poll for %s: %v", a.ID, recErr)
+		}
+		return err
+	}
+
+	snap, err := snapshotFromCollection(a.ID, col, now)
+	if err != nil {
+		return err
+	}
+	if err := p.store.SaveAgentSnapshot(snap); err != nil {
+		log.Printf("Agents: failed to save snapshot for %s: %v", a.ID, err)
+		return err
+	}
+	if err := p.store.RecordCostHistory(historyFromCollection(a.ID, col, now)); err != nil {
+		log.Printf("Agents: failed to record cost history for %s: %v", a.ID, err)
+	}
+	if err := p.store.ReplaceCostRecommendations(a.ID, Recommend(col.Workloads)); err != nil {
+		log.Printf("Agents: failed to refresh recommendations for %s: %v", a.ID, err)
+	}
+	return p.store.RecordAgentPoll(a.ID, now, "")
+}
+
+func snapshotFromCollection(agentID string, col *Collection, at time.Time) (db.AgentSnapshot, error) {
+	snap := db.AgentSnapshot{
+		AgentID:         agentID,
+		TotalHourlyCost: col.Summary.TotalHourlyCost,
+		CollectedAt:     at,
+	}
+	parts := []struct {
+		dst *string
+		v   interface{}
+	}{
+		{&snap.Summary, col.Summary},
+		{&snap.Namespaces, col.Namespaces},
+		{&snap.Nodes, col.Nodes},
+		{&snap.Workloads, col.Workloads},
+	}
+	for _, part := range parts {
+		b, err := json.Marshal(part.v)
+		if err != nil {
+			return snap, err
+		}
+		*part.dst = string(b)
+	}
+	return snap, nil
+}
+
+// historyFromCollection extracts the hourly cluster, per-namespace and per-label cost rates of a poll.
+func historyFromCollection(agentID string, col *Collection, at time.Time) []db.CostHistoryEntry {
+	entries := []db.CostHistoryEntry{{
+		AgentID:    agentID,
+		Bucket:     at,
+		Scope:      db.CostScopeCluster,
+		HourlyCost: col.Summary.TotalHourlyCost,
+	}}
+	for _, ns := range col.Namespaces {
+		entries = append(entries, db.CostHistoryEntry{
+			AgentID:    agentID,
+			Bucket:     at,
+			Scope:      db.CostScopeNamespace,
+			Name:       ns.Namespace,
+			HourlyCost: ns.HourlyCost,
+		})
+	}
+	for key, values := range col.Summary.CostByLabel {
+		for _, v := range values {
+			entries = append(entries, db.CostHistoryEntry{
+				AgentID:    agentID,
+				Bucket:     at,
+				Scope:      db.CostScopeLabel,
+				Name:       key + "=" + v.Value,
+				HourlyCost: v.HourlyCost,
+			})
+		}
+	}
+	return entries
+}
+
+// DecodeSnapshot turns a stored snapshot back into typed agent data.
+func DecodeSnapshot(snap *db.AgentSnapshot) (*Collection, error) {
+	var col Collection
+	parts := []struct {
+		raw string
+		dst interface{}
+	}{
+		{snap.Summary, &col.Summary},
+		{snap.Namespaces, &col.Namespaces},
+		{snap.Nodes, &col.Nodes},
+		{snap.Workloads, &col.Workloads},
+	}
+	for _, part := range parts {
+		if part.raw == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(part.raw), part.dst); err != nil {
+			return nil, err
+		}
+	}
+	return &col, nil
+}
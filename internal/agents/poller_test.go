@@ -0,0 +1,136 @@
+package agents
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func newFakeAgent(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, r *http.Request, v interface{}) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/api/cost/summary", func(w http.ResponseWriter, r *http.Request) {
+		write(w, r, Summary{ClusterName: "dev", TotalHourlyCost: 12.5, TotalCPUCores: 8})
+	})
+	mux.HandleFunc("/api/cost/namespaces", func(w http.ResponseWriter, r *http.Request) {
+		write(w, r, []Namespace{{Namespace: "payments", HourlyCost: 4.2}})
+	})
+	mux.HandleFunc("/api/cost/nodes", func(w http.ResponseWriter, r *http.Request) {
+		write(w, r, []Node{{Name: "node-1", InstanceType: "m5.large"}})
+	})
+	mux.HandleFunc("/api/cost/workloads", func(w http.ResponseWriter, r *http.Request) {
+		write(w, r, []Workload{{Namespace: "payments", WorkloadName: "api", CPURequestedCores: 2, CPUUsedCores: 0.5}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPoller_Poll(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	srv := newFakeAgent(t, "secret")
+
+	a := db.Agent{ID: "a-dev", Name: "dev", URL: srv.URL, Token: "secret", Active: true, Interval: DefaultInterval}
+	if err := store.CreateAgent(a); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+
+	p := NewPoller(store, nil)
+	if err := p.Poll(a); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	snap, err := store.GetLatestAgentSnapshot("a-dev")
+	if err != nil || snap == nil {
+		t.Fatalf("Expected snapshot, got %v (err %v)", snap, err)
+	}
+	if snap.TotalHourlyCost != 12.5 {
+		t.Errorf("Expected total 12.5, got %f", snap.TotalHourlyCost)
+	}
+
+	col, err := DecodeSnapshot(snap)
+	if err != nil {
+		t.Fatalf("DecodeSnapshot failed: %v", err)
+	}
+	if col.Summary.ClusterName != "dev" || len(col.Namespaces) != 1 || len(col.Nodes) != 1 || len(col.Workloads) != 1 {
+		t.Errorf("Unexpected decoded collection: %+v", col)
+	}
+
+	history, err := store.GetCostHistory("a-dev", "", time.Now().Add(-2*time.Hour))
+	if err != nil || len(history) != 2 {
+		t.Errorf("Expected cluster and namespace history entries, got %+v (err %v)", history, err)
+	}
+
+	recs, err := store.GetCostRecommendations("a-dev")
+	if err != nil || len(recs) != 1 || recs[0].WorkloadName != "api" {
+		t.Errorf("Expected a recommendation for the over-provisioned workload, got %+v (err %v)", recs, err)
+	}
+
+	stored, _ := store.GetAgent("a-dev")
+	if stored.LastPolledAt == nil || stored.LastError != "" {
+		t.Errorf("Expected successful poll to be recorded, got %+v", stored)
+	}
+}
+
+func TestPoller_PollFailureRecordsError(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	srv := newFakeAgent(t, "secret")
+
+	a := db.Agent{ID: "a-bad", Name: "bad", URL: srv.URL, Token: "wrong", Active: true, Interval: DefaultInterval}
+	_ = store.CreateAgent(a)
+
+	p := NewPoller(store, nil)
+	if err := p.Poll(a); err == nil {
+		t.Fatal("Expected poll with wrong token to fail")
+	}
+
+	stored, _ := store.GetAgent("a-bad")
+	if stored.LastError == "" {
+		t.Error("Expected last error to be recorded")
+	}
+	if snap, _ := store.GetLatestAgentSnapshot("a-bad"); snap != nil {
+		t.Error("Expected no snapshot after failed poll")
+	}
+}
+
+type fakeLeader bool
+
+func (f fakeLeader) IsLeader() bool { return bool(f) }
+
+func TestPoller_StandbySkipsPolling(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	srv := newFakeAgent(t, "secret")
+
+	a := db.Agent{ID: "a-standby", Name: "standby", URL: srv.URL, Token: "secret", Active: true, Interval: DefaultInterval}
+	_ = store.CreateAgent(a)
+
+	p := NewPoller(store, nil)
+	p.SetLeader(fakeLeader(false))
+	p.pollDue()
+	time.Sleep(100 * time.Millisecond)
+
+	stored, _ := store.GetAgent("a-standby")
+	if stored.LastPolledAt != nil {
+		t.Error("Expected a standby instance not to poll agents")
+	}
+}
@@ -0,0 +1,70 @@
+package agents
+
+import (
+	"math"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// recommendationHeadroom is applied on top of observed usage when sizing requests.
+	recommendationHeadroom = 1.3
+	// minReduction is the smallest relative request reduction worth recommending.
+	minReduction = 0.2
+	// Floors so that idle workloads are not sized down to nothing.
+	minCPUCores  = 0.05
+	minMemoryGiB = 0.125
+	// hoursPerMonth is the average number of hours in a month (8760 / 12).
+	hoursPerMonth = 730
+)
+
+// Recommend returns right-sizing recommendations for over-provisioned workloads.
+// Requests are sized to observed usage plus headroom; a workload is over-provisioned when
+// CPU or memory requests could shrink by at least 20%. Savings assume the workload's cost
+// is split evenly between CPU and memory requests.
+func Recommend(workloads []Workload) []db.CostRecommendation {
+	var recs []db.CostRecommendation
+	for _, w := range workloads {
+		floorCPU := minCPUCores * float64(max(w.Replicas, 1))
+		floorMem := minMemoryGiB * float64(max(w.Replicas, 1))
+
+		cpuRec := math.Min(w.CPURequestedCores, math.Max(w.CPUUsedCores*recommendationHeadroom, floorCPU))
+		memRec := math.Min(w.MemoryRequestedGiB, math.Max(w.MemoryUsedGiB*recommendationHeadroom, floorMem))
+
+		cpuReduction := reduction(w.CPURequestedCores, cpuRec)
+		memReduction := reduction(w.MemoryRequestedGiB, memRec)
+		if cpuReduction < minReduction && memReduction < minReduction {
+			continue
+		}
+
+		savings := w.HourlyCost * (0.5*cpuReduction + 0.5*memReduction) * hoursPerMonth
+		recs = append(recs, db.CostRecommendation{
+			Namespace:            w.Namespace,
+			WorkloadKind:         w.WorkloadKind,
+			WorkloadName:         w.WorkloadName,
+			Replicas:             w.Replicas,
+			CPURequestedCores:    w.CPURequestedCores,
+			CPUUsedCores:         w.CPUUsedCores,
+			CPURecommendedCores:  round(cpuRec, 3),
+			MemoryRequestedGiB:   w.MemoryRequestedGiB,
+			MemoryUsedGiB:        w.MemoryUsedGiB,
+			MemoryRecommendedGiB: round(memRec, 3),
+			HourlyCost:           w.HourlyCost,
+			MonthlySavings:       round(savings, 2),
+		})
+	}
+	return recs
+}
+
+// reduction returns the relative decrease from requested to recommended (0 when nothing is requested).
+func reduction(requested, recommended float64) float64 {
+	if requested <= 0 {
+		return 0
+	}
+	return math.Max(0, (requested-recommended)/requested)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
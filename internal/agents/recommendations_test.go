@@ -0,0 +1,36 @@
+package agents
+
+import "testing"
+
+func TestRecommend(t *testing.T) {
+	workloads := []Workload{
+		// Heavily over-provisioned on CPU and memory
+		{Namespace: "payments", WorkloadKind: "Deployment", WorkloadName: "api", Replicas: 2,
+			CPURequestedCores: 4, CPUUsedCores: 1, MemoryRequestedGiB: 8, MemoryUsedGiB: 2, HourlyCost: 1},
+		// Well sized: within headroom of observed usage
+		{Namespace: "payments", WorkloadKind: "Deployment", WorkloadName: "worker", Replicas: 1,
+			CPURequestedCores: 1, CPUUsedCores: 0.9, MemoryRequestedGiB: 2, MemoryUsedGiB: 1.8, HourlyCost: 1},
+		// Idle workload is floored rather than sized to zero
+		{Namespace: "tools", WorkloadKind: "Deployment", WorkloadName: "idle", Replicas: 1,
+			CPURequestedCores: 1, MemoryRequestedGiB: 1, HourlyCost: 0.1},
+	}
+
+	recs := Recommend(workloads)
+	if len(recs) != 2 {
+		t.Fatalf("Expected 2 recommendations, got %+v", recs)
+	}
+
+	api := recs[0]
+	if api.WorkloadName != "api" || api.CPURecommendedCores != 1.3 || api.MemoryRecommendedGiB != 2.6 {
+		t.Errorf("Unexpected api recommendation: %+v", api)
+	}
+	// Both requests shrink by 67.5%, half the cost each: 1 * 0.675 * 730
+	if api.MonthlySavings != 492.75 {
+		t.Errorf("Expected savings 492.75, got %f", api.MonthlySavings)
+	}
+
+	idle := recs[1]
+	if idle.CPURecommendedCores != minCPUCores || idle.MemoryRecommendedGiB != minMemoryGiB {
+		t.Errorf("Expected idle workload to be floored, got %+v", idle)
+	}
+}
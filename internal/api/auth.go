@@ -5,10 +5,13 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/projecthelena/warden/internal/config"
@@ -19,6 +22,10 @@ type contextKey string
 
 const contextKeyUserID contextKey = "userID"
 
+// contextKeyPersonalToken is set when the request was authenticated with a personal
+// API token rather than a session.
+const contextKeyPersonalToken contextKey = "personalToken"
+
 // APIKeyUserID is used to identify requests authenticated via API key
 // SECURITY: Use -1 to distinguish from real user IDs (which are positive)
 // This prevents authorization bypass if handlers assume userID > 0 means valid user
@@ -75,7 +82,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			h.loginLimiter.RecordFailure(ip)
 			h.loginLimiter.RecordUsernameFailure(req.Username)
 		}
-		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		writeErrorCode(w, http.StatusUnauthorized, ErrCodeInvalidCredentials, "invalid credentials")
 		return
 	}
 
@@ -91,7 +98,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Generate Token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
-		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 	token := hex.EncodeToString(tokenBytes)
@@ -174,7 +181,7 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 		avatar = "https://ui-avatars.com/api/?name=" + url.QueryEscape(displayName) + "&background=random"
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"user": map[string]any{
 			"username":    user.Username,
 			"id":          user.ID,
@@ -184,7 +191,12 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 			"avatar":      avatar,
 			"displayName": displayName,
 		},
-	})
+	}
+	// Lets the dashboard explain why changes are disabled
+	if enabled, notice := readOnlyState(h.store, h.config); enabled {
+		resp["readOnly"] = map[string]any{"notice": notice}
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 type UpdateUserRequest struct {
@@ -251,13 +263,8 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 // without writing a response. Used by handlers that need optional auth checks.
 func (h *AuthHandler) IsAuthenticated(r *http.Request) bool {
 	// Check Bearer token
-	authHeader := r.Header.Get("Authorization")
-	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		token := authHeader[7:]
-		valid, err := h.store.ValidateAPIKey(token)
-		if err == nil && valid {
-			return true
-		}
+	if _, ok, _ := h.bearerUser(r); ok {
+		return true
 	}
 	// Check session cookie
 	c, err := r.Cookie("auth_token")
@@ -268,36 +275,73 @@ func (h *AuthHandler) IsAuthenticated(r *http.Request) bool {
 	return err == nil && sess != nil
 }
 
+// bearerUser resolves the request's Bearer token. Personal tokens resolve to the user
+// who owns them, global API keys to APIKeyUserID. The error is db.ErrCredentialLocked
+// while the token's prefix is locked out after repeated wrong tokens.
+func (h *AuthHandler) bearerUser(r *http.Request) (int64, bool, error) {
+	authHeader := r.Header.Get("Authorization")
+	if len(authHeader) <= 7 || authHeader[:7] != "Bearer " {
+		return 0, false, nil
+	}
+	token := authHeader[7:]
+	if strings.HasPrefix(token, db.UserTokenPrefix) {
+		userID, valid, err := h.store.ValidateUserToken(token, extractIP(r))
+		if errors.Is(err, db.ErrCredentialLocked) {
+			return 0, false, err
+		}
+		if err != nil || !valid {
+			return 0, false, nil
+		}
+		return userID, true, nil
+	}
+	valid, err := h.store.ValidateAPIKey(token, extractIP(r))
+	if errors.Is(err, db.ErrCredentialLocked) {
+		return 0, false, err
+	}
+	if errors.Is(err, db.ErrAPIKeyNetworkDenied) {
+		log.Printf("AUDIT: [SECURITY] API key %s rejected from IP %s outside its allow-list", sanitizeLog(token[:min(len(token), 12)]), sanitizeLog(extractIP(r))) // #nosec G706 -- sanitized
+		return 0, false, nil
+	}
+	if err != nil || !valid {
+		return 0, false, nil
+	}
+	// Valid API Key - use special negative ID to distinguish from real users
+	// SECURITY: APIKeyUserID (-1) prevents confusion with real user IDs
+	return APIKeyUserID, true, nil
+}
+
 // Middleware
 
 func (h *AuthHandler) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 1. Check Bearer Token (API Key)
-		authHeader := r.Header.Get("Authorization")
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			token := authHeader[7:]
-			valid, err := h.store.ValidateAPIKey(token)
-			if err == nil && valid {
-				// Valid API Key - use special negative ID to distinguish from real users
-				// SECURITY: APIKeyUserID (-1) prevents confusion with real user IDs
-				ctx := context.WithValue(r.Context(), contextKeyUserID, APIKeyUserID)
-				next.ServeHTTP(w, r.WithContext(ctx))
-				return
+		// 1. Check Bearer Token (API key or personal token)
+		userID, ok, err := h.bearerUser(r)
+		if errors.Is(err, db.ErrCredentialLocked) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(db.CredentialLockout.Seconds())))
+			writeError(w, http.StatusTooManyRequests, "too many invalid API keys, try again later")
+			return
+		}
+		if ok {
+			ctx := context.WithValue(r.Context(), contextKeyUserID, userID)
+			if userID != APIKeyUserID {
+				ctx = context.WithValue(ctx, contextKeyPersonalToken, true)
 			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
 		}
 
 		// 2. Check Cookie
 		c, err := r.Cookie("auth_token")
 		if err != nil {
 			// No cookie
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
 			return
 		}
 
 		// 3. Validate Session
 		sess, err := h.store.GetSession(c.Value)
 		if err != nil || sess == nil {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
 			return
 		}
 
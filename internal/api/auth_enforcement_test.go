@@ -30,35 +30,101 @@ func TestAuthEnforcement(t *testing.T) {
 		path   string
 	}{
 		{"Overview", "GET", "/api/overview"},
+		{"Wallboard", "GET", "/api/wallboard"},
+		{"Create Wallboard Token", "POST", "/api/wallboard/token"},
+		{"Delete Wallboard Token", "DELETE", "/api/wallboard/token"},
 		{"Me", "GET", "/api/auth/me"},
 		{"Update User", "PATCH", "/api/auth/me"},
+		{"List Personal Tokens", "GET", "/api/auth/me/tokens"},
+		{"Create Personal Token", "POST", "/api/auth/me/tokens"},
+		{"Delete Personal Token", "DELETE", "/api/auth/me/tokens/1"},
 		{"Create Group", "POST", "/api/groups"},
 		{"Update Group", "PUT", "/api/groups/g-test"},
 		{"Delete Group", "DELETE", "/api/groups/g-test"},
 		{"Get Uptime", "GET", "/api/uptime"},
 		{"Create Monitor", "POST", "/api/monitors"},
+		{"Bulk Monitors", "POST", "/api/monitors/bulk"},
+		{"Preview Monitor", "POST", "/api/monitors/preview"},
+		{"Probe", "GET", "/api/probe?target=http://example.com"},
 		{"Update Monitor", "PUT", "/api/monitors/m-test"},
 		{"Delete Monitor", "DELETE", "/api/monitors/m-test"},
+		{"Check Monitor", "POST", "/api/monitors/m-test/check"},
 		{"Monitor Uptime", "GET", "/api/monitors/m-test/uptime"},
 		{"Monitor Latency", "GET", "/api/monitors/m-test/latency"},
+		{"Create Annotation", "POST", "/api/monitors/m-test/annotations"},
+		{"Create Fleet Annotation", "POST", "/api/annotations"},
+		{"Get Status Override", "GET", "/api/monitors/m-test/status-override"},
+		{"Set Status Override", "PUT", "/api/monitors/m-test/status-override"},
+		{"Clear Status Override", "DELETE", "/api/monitors/m-test/status-override"},
 		{"Get Incidents", "GET", "/api/incidents"},
 		{"Create Incident", "POST", "/api/incidents"},
+		{"Incidents Pending Publication", "GET", "/api/incidents/pending-publication"},
+		{"Edit Incident Update", "PUT", "/api/incidents/inc-1/updates/1"},
+		{"Delete Incident Update", "DELETE", "/api/incidents/inc-1/updates/1"},
+		{"Set Downtime Cost", "PUT", "/api/monitors/m-test/downtime-cost"},
+		{"Monitor Reliability", "GET", "/api/monitors/m-test/reliability"},
+		{"Get Check Exclusions", "GET", "/api/monitors/m-test/check-exclusions"},
+		{"Set Check Exclusions", "PUT", "/api/monitors/m-test/check-exclusions"},
+		{"Delete Check Exclusions", "DELETE", "/api/monitors/m-test/check-exclusions"},
+		{"List Outages", "GET", "/api/outages"},
+		{"Get Outage", "GET", "/api/outages/1"},
+		{"Acknowledge Outage", "POST", "/api/outages/1/acknowledge"},
 		{"Get Maintenance", "GET", "/api/maintenance"},
 		{"Create Maintenance", "POST", "/api/maintenance"},
 		{"Update Maintenance", "PUT", "/api/maintenance/1"},
 		{"Delete Maintenance", "DELETE", "/api/maintenance/1"},
+		{"Maintenance Calendar File", "GET", "/api/maintenance/1/ics"},
+		{"List Silences", "GET", "/api/silences"},
+		{"Create Silence", "POST", "/api/silences"},
+		{"Get Silence", "GET", "/api/silences/sil-1"},
+		{"Expire Silence", "DELETE", "/api/silences/sil-1"},
 		{"Get Settings", "GET", "/api/settings"},
 		{"Update Settings", "PATCH", "/api/settings"},
+		{"Get Settings Schema", "GET", "/api/settings/schema"},
 		{"List API Keys", "GET", "/api/api-keys"},
 		{"Create API Key", "POST", "/api/api-keys"},
+		{"Update API Key", "PATCH", "/api/api-keys/1"},
 		{"Delete API Key", "DELETE", "/api/api-keys/1"},
 		{"Get Stats", "GET", "/api/stats"},
+		{"Get Capacity", "GET", "/api/stats/capacity"},
+		{"Get Scheduler Stats", "GET", "/api/stats/scheduler"},
+		{"Get Request Metrics", "GET", "/api/stats/requests"},
+		{"Get Query Plans", "GET", "/api/admin/query-plans"},
 		{"List Notification Channels", "GET", "/api/notifications/channels"},
 		{"Create Notification Channel", "POST", "/api/notifications/channels"},
 		{"Delete Notification Channel", "DELETE", "/api/notifications/channels/1"},
+		{"List Push Devices", "GET", "/api/push/devices"},
+		{"Register Push Device", "POST", "/api/push/devices"},
+		{"Delete Push Device", "DELETE", "/api/push/devices/pd-1"},
+		{"Subscribe to Web Push", "POST", "/api/push/subscribe"},
+		{"Unsubscribe from Web Push", "POST", "/api/push/unsubscribe"},
+		{"Get VAPID Public Key", "GET", "/api/push/vapid-public-key"},
+		{"Rotate VAPID Key", "POST", "/api/push/vapid/rotate"},
 		{"Get Events", "GET", "/api/events"},
+		{"Get Event Log", "GET", "/api/events/log"},
 		{"List Status Pages", "GET", "/api/status-pages"},
+		{"Create Status Page", "POST", "/api/status-pages"},
 		{"Toggle Status Page", "PATCH", "/api/status-pages/slug"},
+		{"Delete Status Page", "DELETE", "/api/status-pages/slug"},
+		{"Create Ingest Token", "POST", "/api/monitors/m1/ingest-token"},
+		{"Get Shadow Checks", "GET", "/api/monitors/m1/shadow"},
+		{"Start Shadow Checks", "POST", "/api/monitors/m1/shadow"},
+		{"Cancel Shadow Checks", "DELETE", "/api/monitors/m1/shadow"},
+		{"Promote Shadow URL", "POST", "/api/monitors/m1/shadow/promote"},
+		{"Get Latency SLO", "GET", "/api/monitors/m1/slo"},
+		{"Set Latency SLO", "PUT", "/api/monitors/m1/slo"},
+		{"Delete Latency SLO", "DELETE", "/api/monitors/m1/slo"},
+		{"Get Monitor Regions", "GET", "/api/monitors/m1/regions"},
+		{"Ingest Alertmanager", "POST", "/api/ingest/alertmanager"},
+		{"Agent WebSocket", "GET", "/api/ws"},
+		{"Agent result batch", "POST", "/api/agent/results/batch"},
+		{"List Agents", "GET", "/api/agents"},
+		{"Create Agent", "POST", "/api/agents"},
+		{"Cost Summary", "GET", "/api/cost/summary"},
+		{"Cost History", "GET", "/api/cost/history"},
+		{"List Budgets", "GET", "/api/cost/budgets"},
+		{"Label Cost Report", "GET", "/api/cost/labels/team/report"},
+		{"Cost Recommendations", "GET", "/api/cost/recommendations"},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// weekStartSettingKey is the first day of the week used by the "this_week" and
+// "last_week" report ranges.
+const weekStartSettingKey = "reports.week_start"
+
+// defaultWeekStart follows ISO 8601.
+const defaultWeekStart = time.Monday
+
+// maxReportDays bounds "<n>d" report ranges.
+const maxReportDays = 365
+
+// Date range presets accepted by the reporting endpoints' range parameter, next to
+// "<n>d" for the last n days including today.
+const (
+	rangeToday       = "today"
+	rangeYesterday   = "yesterday"
+	rangeThisWeek    = "this_week"
+	rangeLastWeek    = "last_week"
+	rangeThisMonth   = "this_month"
+	rangeLastMonth   = "last_month"
+	rangeThisQuarter = "this_quarter"
+	rangeLastQuarter = "last_quarter"
+	rangeThisYear    = "this_year"
+	rangeLastYear    = "last_year"
+)
+
+// rangePresets lists the presets in the order they are documented.
+var rangePresets = []string{
+	rangeToday, rangeYesterday, rangeThisWeek, rangeLastWeek, rangeThisMonth,
+	rangeLastMonth, rangeThisQuarter, rangeLastQuarter, rangeThisYear, rangeLastYear,
+}
+
+// dateRange is the half-open interval [From, To) of whole days named by Name, with
+// day boundaries in the location of From and To. Ranges that include today end at
+// the following midnight.
+type dateRange struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// lastDay returns the last day of the range that has begun at now.
+func (d dateRange) lastDay(now time.Time) time.Time {
+	if now.Before(d.To) {
+		return startOfDay(now)
+	}
+	return d.To.AddDate(0, 0, -1)
+}
+
+// contains reports whether t falls within the range.
+func (d dateRange) contains(t time.Time) bool {
+	return !t.Before(d.From) && t.Before(d.To)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the midnight that starts t's week, with weeks starting on weekStart.
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	back := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return startOfDay(t).AddDate(0, 0, -back)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func startOfQuarter(t time.Time) time.Time {
+	month := time.Month((int(t.Month())-1)/3*3 + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// parseDateRange parses a report range, a preset or "<n>d", relative to now. Day,
+// week, month and quarter boundaries are taken in now's location. An empty value
+// gives def.
+func parseDateRange(value, def string, now time.Time, weekStart time.Weekday) (dateRange, bool) {
+	if value == "" {
+		value = def
+	}
+	today := startOfDay(now)
+	// AddDate normalizes, so month and year arithmetic stays on the 1st, and days
+	// stay at midnight across DST changes
+	var from, to time.Time
+	switch value {
+	case rangeToday:
+		from, to = today, today.AddDate(0, 0, 1)
+	case rangeYesterday:
+		from, to = today.AddDate(0, 0, -1), today
+	case rangeThisWeek:
+		from = startOfWeek(now, weekStart)
+		to = from.AddDate(0, 0, 7)
+	case rangeLastWeek:
+		to = startOfWeek(now, weekStart)
+		from = to.AddDate(0, 0, -7)
+	case rangeThisMonth:
+		from = startOfMonth(now)
+		to = from.AddDate(0, 1, 0)
+	case rangeLastMonth:
+		to = startOfMonth(now)
+		from = to.AddDate(0, -1, 0)
+	case rangeThisQuarter:
+		from = startOfQuarter(now)
+		to = from.AddDate(0, 3, 0)
+	case rangeLastQuarter:
+		to = startOfQuarter(now)
+		from = to.AddDate(0, -3, 0)
+	case rangeThisYear:
+		from = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		to = from.AddDate(1, 0, 0)
+	case rangeLastYear:
+		to = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		from = to.AddDate(-1, 0, 0)
+	default:
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || !strings.HasSuffix(value, "d") || days < 1 || days > maxReportDays {
+			return dateRange{}, false
+		}
+		to = today.AddDate(0, 0, 1)
+		from = to.AddDate(0, 0, -days)
+	}
+	return dateRange{Name: value, From: from, To: to}, true
+}
+
+// rangeHelp describes the accepted range values, for error messages.
+func rangeHelp() string {
+	return "range must be 1d to " + strconv.Itoa(maxReportDays) + "d or one of " + strings.Join(rangePresets, ", ")
+}
+
+// parseWeekday parses a lowercase English weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == s {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// resolveWeekStart returns the first day of the week for report ranges: the week_start
+// query parameter if given, otherwise the reports.week_start setting, or Monday.
+// ok is false when week_start is not a weekday name.
+func resolveWeekStart(r *http.Request, store *db.Store) (time.Weekday, bool) {
+	if v := r.URL.Query().Get("week_start"); v != "" {
+		return parseWeekday(strings.ToLower(v))
+	}
+	if v, err := store.GetSetting(weekStartSettingKey); err == nil {
+		if d, ok := parseWeekday(v); ok {
+			return d, true
+		}
+	}
+	return defaultWeekStart, true
+}
+
+// resolveReportRange reads a report's range, tz and week_start parameters and returns
+// the range in the requester's timezone, or writes a 400 and returns false.
+func resolveReportRange(w http.ResponseWriter, r *http.Request, store *db.Store, def string) (dateRange, time.Time, bool) {
+	loc, ok := resolveLocation(r, store)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid timezone")
+		return dateRange{}, time.Time{}, false
+	}
+	weekStart, ok := resolveWeekStart(r, store)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "week_start must be a weekday, e.g. monday or sunday")
+		return dateRange{}, time.Time{}, false
+	}
+	now := time.Now().In(loc)
+	rng, ok := parseDateRange(r.URL.Query().Get("range"), def, now, weekStart)
+	if !ok {
+		writeError(w, http.StatusBadRequest, rangeHelp())
+		return dateRange{}, time.Time{}, false
+	}
+	return rng, now, true
+}
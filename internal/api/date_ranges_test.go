@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestParseDateRange(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// Wednesday, 2026-03-11 09:30 in New York, days after the switch to daylight saving
+	now := time.Date(2026, 3, 11, 9, 30, 0, 0, ny)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, ny) }
+
+	tests := []struct {
+		value     string
+		weekStart time.Weekday
+		from, to  time.Time
+	}{
+		{"today", time.Monday, day(2026, 3, 11), day(2026, 3, 12)},
+		{"yesterday", time.Monday, day(2026, 3, 10), day(2026, 3, 11)},
+		{"this_week", time.Monday, day(2026, 3, 9), day(2026, 3, 16)},
+		{"this_week", time.Sunday, day(2026, 3, 8), day(2026, 3, 15)},
+		{"last_week", time.Monday, day(2026, 3, 2), day(2026, 3, 9)},
+		{"last_week", time.Sunday, day(2026, 3, 1), day(2026, 3, 8)},
+		{"this_week", time.Wednesday, day(2026, 3, 11), day(2026, 3, 18)},
+		{"this_month", time.Monday, day(2026, 3, 1), day(2026, 4, 1)},
+		{"last_month", time.Monday, day(2026, 2, 1), day(2026, 3, 1)},
+		{"this_quarter", time.Monday, day(2026, 1, 1), day(2026, 4, 1)},
+		{"last_quarter", time.Monday, day(2025, 10, 1), day(2026, 1, 1)},
+		{"this_year", time.Monday, day(2026, 1, 1), day(2027, 1, 1)},
+		{"last_year", time.Monday, day(2025, 1, 1), day(2026, 1, 1)},
+		{"7d", time.Monday, day(2026, 3, 5), day(2026, 3, 12)},
+		{"", time.Monday, day(2026, 2, 10), day(2026, 3, 12)}, // default 30d
+	}
+	for _, tt := range tests {
+		rng, ok := parseDateRange(tt.value, "30d", now, tt.weekStart)
+		if !ok {
+			t.Errorf("%q: not accepted", tt.value)
+			continue
+		}
+		if !rng.From.Equal(tt.from) || !rng.To.Equal(tt.to) {
+			t.Errorf("%q (week starts %s): got [%s, %s), want [%s, %s)", tt.value, tt.weekStart, rng.From, rng.To, tt.from, tt.to)
+		}
+	}
+
+	// A week starting Sunday the 8th spans the switch to daylight saving, so it is 167
+	// hours long, and still starts and ends at local midnight
+	if rng, _ := parseDateRange("this_week", "", now, time.Sunday); rng.To.Sub(rng.From) != 167*time.Hour {
+		t.Errorf("expected a 167h week across the DST change, got %s", rng.To.Sub(rng.From))
+	}
+
+	for _, bad := range []string{"0d", "366d", "d", "30", "next_week"} {
+		if _, ok := parseDateRange(bad, "30d", now, time.Monday); ok {
+			t.Errorf("%q: expected to be rejected", bad)
+		}
+	}
+}
+
+func TestDateRange_LastDay(t *testing.T) {
+	now := time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)
+	thisMonth, _ := parseDateRange("this_month", "", now, time.Monday)
+	if got := thisMonth.lastDay(now).Format("2006-01-02"); got != "2026-03-11" {
+		t.Errorf("this_month last day = %s, want today", got)
+	}
+	lastMonth, _ := parseDateRange("last_month", "", now, time.Monday)
+	if got := lastMonth.lastDay(now).Format("2006-01-02"); got != "2026-02-28" {
+		t.Errorf("last_month last day = %s, want 2026-02-28", got)
+	}
+}
+
+func TestGetMonitorUptime_Range(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	h := NewUptimeHandler(nil, s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-rng", GroupID: "g-default", Name: "Range", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+	now := time.Now()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	if err := s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-rng", Status: "up", Timestamp: yesterday},
+		{MonitorID: "m-rng", Status: "down", Timestamp: yesterday.Add(time.Minute)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks: %v", err)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "m-rng")
+		req := httptest.NewRequest("GET", "/api/monitors/m-rng/uptime?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.GetMonitorUptime(w, req)
+		return w
+	}
+
+	w := get("range=yesterday&tz=UTC")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp MonitorUptimeResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range == nil || resp.Range.Range != "yesterday" || resp.Range.Timezone != "UTC" {
+		t.Fatalf("unexpected range %+v", resp.Range)
+	}
+	if resp.Range.TotalChecks != 2 || resp.Range.UptimePercent == nil || *resp.Range.UptimePercent != 50 {
+		t.Errorf("expected 2 checks at 50%%, got %d at %v", resp.Range.TotalChecks, resp.Range.UptimePercent)
+	}
+
+	// Nothing today
+	w = get("range=today&tz=UTC")
+	resp = MonitorUptimeResponse{}
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range == nil || resp.Range.TotalChecks != 0 || resp.Range.UptimePercent != nil {
+		t.Errorf("expected no checks today, got %+v", resp.Range)
+	}
+
+	// Without range only the rolling stats are returned
+	w = get("")
+	resp = MonitorUptimeResponse{}
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range != nil {
+		t.Errorf("expected no range, got %+v", resp.Range)
+	}
+
+	for _, query := range []string{"range=next_week", "range=this_week&week_start=someday", "range=today&tz=Mars/Olympus"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
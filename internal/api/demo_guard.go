@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/projecthelena/warden/internal/config"
+)
+
+// demoLockedPaths are the account and credential routes demo mode keeps visitors from
+// changing. Everyone signs in with the same published credentials, so changing the
+// password or revoking tokens and keys would lock the others out until the next reset.
+var demoLockedPaths = []string{
+	"/api/auth/me",
+	"/api/auth/me/tokens",
+	"/api/api-keys",
+	"/api-keys", // Route without the /api prefix, for the Vite proxy
+}
+
+// DemoGuardMiddleware rejects changes to the demo user, its personal tokens and API
+// keys with 403 in demo mode. Reads go through.
+func DemoGuardMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Demo || !demoLocked(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeErrorCode(w, http.StatusForbidden, ErrCodeForbidden, "The demo account and its credentials can't be changed")
+		})
+	}
+}
+
+// demoLocked reports whether r changes one of demoLockedPaths or what's below it.
+func demoLocked(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	for _, p := range demoLockedPaths {
+		if r.URL.Path == p || strings.HasPrefix(r.URL.Path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
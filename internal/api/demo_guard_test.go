@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/config"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestDemoGuard(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	cfg := config.Default()
+	cfg.Demo = true
+	router := NewRouter(uptime.NewManager(store), store, &cfg)
+	seedAuthUser(t, store, "demo", "demo-session")
+	_, _ = store.CreateAPIKey("Shared", nil)
+	keys, _ := store.ListAPIKeys()
+	keyID := strconv.FormatInt(keys[0].ID, 10)
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: "demo-session"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, c := range []struct{ method, path, body string }{
+		{"PATCH", "/api/auth/me", `{"currentPassword":"password123","newPassword":"locked-out"}`},
+		{"POST", "/api/auth/me/tokens", `{"name":"mine"}`},
+		{"DELETE", "/api/auth/me/tokens/1", ""},
+		{"POST", "/api/api-keys", `{"name":"mine"}`},
+		{"DELETE", "/api/api-keys/" + keyID, ""},
+		{"DELETE", "/api-keys/" + keyID, ""},
+	} {
+		if code := do(c.method, c.path, c.body); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected 403 in demo mode, got %d", c.method, c.path, code)
+		}
+	}
+	if keys, _ := store.ListAPIKeys(); len(keys) != 1 {
+		t.Errorf("Expected the API key to be kept, got %d keys", len(keys))
+	}
+	if _, err := store.Authenticate("demo", "password123"); err != nil {
+		t.Errorf("Expected the shared password to be kept, got %v", err)
+	}
+
+	// Reads and the rest of the demo still work
+	if code := do("GET", "/api/auth/me", ""); code != http.StatusOK {
+		t.Errorf("Expected 200 for /auth/me, got %d", code)
+	}
+	if code := do("GET", "/api/api-keys", ""); code != http.StatusOK {
+		t.Errorf("Expected 200 listing API keys, got %d", code)
+	}
+	if code := do("POST", "/api/groups", `{"name":"Visitors"}`); code != http.StatusCreated {
+		t.Errorf("Expected 201 creating a group in demo mode, got %d", code)
+	}
+}
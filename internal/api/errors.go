@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Error codes, returned as "code" in JSON error responses so clients can tell errors
+// apart without parsing the message. Responses without a more specific code get the
+// generic one for their status.
+const (
+	ErrCodeValidationFailed = "validation_failed"
+	ErrCodeUnauthorized     = "unauthorized"
+	ErrCodeForbidden        = "forbidden"
+	ErrCodeNotFound         = "not_found"
+	ErrCodeMethodNotAllowed = "method_not_allowed"
+	ErrCodeConflict         = "conflict"
+	ErrCodePayloadTooLarge  = "payload_too_large"
+	ErrCodeRateLimited      = "rate_limited"
+	ErrCodeInternal         = "internal_error"
+	ErrCodeUnavailable      = "unavailable"
+	ErrCodeReadOnly         = "read_only"
+
+	ErrCodeMonitorNotFound     = "monitor_not_found"
+	ErrCodeGroupNotFound       = "group_not_found"
+	ErrCodeIncidentNotFound    = "incident_not_found"
+	ErrCodeMaintenanceNotFound = "maintenance_not_found"
+	ErrCodeStatusPageNotFound  = "status_page_not_found"
+	ErrCodeDuplicateMonitor    = "duplicate_monitor"
+	ErrCodeDuplicateURL        = "duplicate_url"
+	ErrCodeShadowMismatch      = "shadow_mismatch"
+	ErrCodeChannelTestFailed   = "channel_test_failed"
+	ErrCodeSetupCompleted      = "setup_completed"
+	ErrCodeSetupTokenRequired  = "setup_token_required"
+	ErrCodeInvalidCredentials  = "invalid_credentials"
+)
+
+// ErrorResponse is the body of every API error.
+type ErrorResponse struct {
+	Error     string `json:"error"`               // human-readable message
+	Code      string `json:"code"`                // machine-readable, e.g. "monitor_not_found"
+	RequestID string `json:"requestId,omitempty"` // set when RequestID has assigned one
+}
+
+// writeError writes a JSON error with the generic code for the status.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeErrorCode(w, status, statusErrorCode(status), message)
+}
+
+// writeErrorCode writes a JSON error with a specific code.
+func writeErrorCode(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorBody(w, code, message))
+}
+
+// errorBody builds an ErrorResponse, for error responses that embed it to carry more
+// fields.
+func errorBody(w http.ResponseWriter, code, message string) ErrorResponse {
+	return ErrorResponse{Error: message, Code: code, RequestID: w.Header().Get(RequestIDHeader)}
+}
+
+// writeStoreError writes an error returned by a store write: unique constraint
+// violations become 409, names the store rejects 422 and a missing monitor 404.
+// Anything else is a 500 with message.
+func writeStoreError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, db.ErrDuplicateMonitorName):
+		writeErrorCode(w, http.StatusConflict, ErrCodeDuplicateMonitor, "A monitor with this name already exists")
+	case errors.Is(err, db.ErrMonitorNotFound):
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+	case errors.Is(err, db.ErrConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, db.ErrMonitorNameTooLong):
+		validationErrors{"name": err.Error()}.write(w)
+	default:
+		writeError(w, http.StatusInternalServerError, message)
+	}
+}
+
+func statusErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrCodeValidationFailed
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	default:
+		return ErrCodeInternal
+	}
+}
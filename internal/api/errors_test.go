@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func decodeErrorResponse(t *testing.T, rr *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON error, got Content-Type %q: %s", ct, rr.Body.String())
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	return resp
+}
+
+func TestWriteError_Envelope(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusBadRequest, ErrCodeValidationFailed},
+		{http.StatusUnauthorized, ErrCodeUnauthorized},
+		{http.StatusNotFound, ErrCodeNotFound},
+		{http.StatusConflict, ErrCodeConflict},
+		{http.StatusTooManyRequests, ErrCodeRateLimited},
+		{http.StatusInternalServerError, ErrCodeInternal},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		rr.Header().Set(RequestIDHeader, "req-1")
+		writeError(rr, tt.status, "something failed")
+
+		if rr.Code != tt.status {
+			t.Errorf("status = %d, want %d", rr.Code, tt.status)
+		}
+		resp := decodeErrorResponse(t, rr)
+		if resp.Code != tt.code || resp.Error != "something failed" || resp.RequestID != "req-1" {
+			t.Errorf("status %d: got %+v, want code %q", tt.status, resp, tt.code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	writeErrorCode(rr, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeMonitorNotFound {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeMonitorNotFound)
+	}
+}
+
+func TestRouter_ErrorEnvelope(t *testing.T) {
+	crudH, _, _, router, _ := setupTest(t)
+
+	// Rejected by the auth middleware
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/uptime", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeUnauthorized {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeUnauthorized)
+	}
+
+	// Unknown API route
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/no-such-endpoint", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeNotFound {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeNotFound)
+	}
+
+	// Known route, wrong method
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/monitors", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rr.Code)
+	}
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeMethodNotAllowed {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeMethodNotAllowed)
+	}
+
+	// Resource-specific code
+	req := httptest.NewRequest("POST", "/api/monitors/missing/pause", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "missing")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr = httptest.NewRecorder()
+	crudH.PauseMonitor(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeMonitorNotFound {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeMonitorNotFound)
+	}
+
+	// Validation failure
+	req = httptest.NewRequest("POST", "/api/monitors", nil)
+	rr = httptest.NewRecorder()
+	crudH.CreateMonitor(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if resp := decodeErrorResponse(t, rr); resp.Code != ErrCodeValidationFailed {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeValidationFailed)
+	}
+}
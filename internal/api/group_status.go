@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Group status settings. The policy decides when the overview shows a whole group as
+// down, so one flaky low-priority check doesn't turn it red.
+const (
+	groupStatusPolicySettingKey = "overview.group_status_policy"
+	criticalTagSettingKey       = "overview.critical_tag"
+	criticalWeightSettingKey    = "overview.critical_weight"
+)
+
+// Group status policies
+const (
+	groupStatusAnyDown  = "any_down" // Down when any monitor is down
+	groupStatusMajority = "majority" // Down when more than half of the monitors are down
+	groupStatusWeighted = "weighted" // Down when monitors carrying more than half the weight are down
+)
+
+const (
+	defaultCriticalTag    = "critical"
+	defaultCriticalWeight = 5
+)
+
+func validateGroupStatusPolicy(val string) error {
+	switch val {
+	case groupStatusAnyDown, groupStatusMajority, groupStatusWeighted:
+		return nil
+	}
+	return errors.New(`must be "any_down", "majority" or "weighted"`)
+}
+
+func validateTag(val string) error {
+	if len(val) > maxTagLength || !tagPattern.MatchString(val) {
+		return fmt.Errorf("must be a tag of up to %d characters of a-z, 0-9, _ . : / -", maxTagLength)
+	}
+	return nil
+}
+
+// groupStatusPolicy rolls the statuses of a group's monitors up into the group's.
+type groupStatusPolicy struct {
+	name           string
+	criticalTag    string
+	criticalWeight int
+}
+
+// loadGroupStatusPolicy reads the group status settings, falling back to any_down.
+func loadGroupStatusPolicy(store *db.Store) groupStatusPolicy {
+	p := groupStatusPolicy{name: groupStatusAnyDown, criticalTag: defaultCriticalTag, criticalWeight: defaultCriticalWeight}
+	if v, err := store.GetSetting(groupStatusPolicySettingKey); err == nil && validateGroupStatusPolicy(v) == nil {
+		p.name = v
+	}
+	if v, err := store.GetSetting(criticalTagSettingKey); err == nil && v != "" {
+		p.criticalTag = v
+	}
+	if v, err := store.GetSetting(criticalWeightSettingKey); err == nil {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 {
+			p.criticalWeight = n
+		}
+	}
+	return p
+}
+
+// weight is how much a monitor counts towards its group's status.
+func (p groupStatusPolicy) weight(m db.Monitor) int {
+	if p.name == groupStatusWeighted && m.HasTag(p.criticalTag) {
+		return p.criticalWeight
+	}
+	return 1
+}
+
+// groupMonitorStatus is the up, down or degraded status of one of a group's monitors.
+type groupMonitorStatus struct {
+	monitor db.Monitor
+	status  string
+}
+
+// aggregate returns the group's status from those of its monitors with a known status.
+// Under majority and weighted, a group whose down monitors fall short of the threshold is
+// degraded rather than down.
+func (p groupStatusPolicy) aggregate(statuses []groupMonitorStatus) string {
+	var total, down int
+	anyDown, anyDegraded := false, false
+	for _, s := range statuses {
+		w := p.weight(s.monitor)
+		total += w
+		switch s.status {
+		case "down":
+			anyDown = true
+			down += w
+		case "degraded":
+			anyDegraded = true
+		}
+	}
+	switch {
+	case !anyDown && !anyDegraded:
+		return "up"
+	case anyDown && (p.name == groupStatusAnyDown || down*2 > total):
+		return "down"
+	default:
+		return "degraded"
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestGroupStatusPolicyAggregate(t *testing.T) {
+	mon := func(id string, tags ...string) db.Monitor { return db.Monitor{ID: id, Tags: tags} }
+	statuses := func(down, degraded, up int, downTags ...string) []groupMonitorStatus {
+		var out []groupMonitorStatus
+		for i := 0; i < down; i++ {
+			out = append(out, groupMonitorStatus{monitor: mon("down", downTags...), status: "down"})
+		}
+		for i := 0; i < degraded; i++ {
+			out = append(out, groupMonitorStatus{monitor: mon("slow"), status: "degraded"})
+		}
+		for i := 0; i < up; i++ {
+			out = append(out, groupMonitorStatus{monitor: mon("up"), status: "up"})
+		}
+		return out
+	}
+	anyDown := groupStatusPolicy{name: groupStatusAnyDown}
+	majority := groupStatusPolicy{name: groupStatusMajority, criticalTag: "critical", criticalWeight: 5}
+	weighted := groupStatusPolicy{name: groupStatusWeighted, criticalTag: "critical", criticalWeight: 5}
+
+	tests := []struct {
+		name     string
+		policy   groupStatusPolicy
+		statuses []groupMonitorStatus
+		want     string
+	}{
+		{"no monitors", anyDown, nil, "up"},
+		{"all up", majority, statuses(0, 0, 3), "up"},
+		{"any down", anyDown, statuses(1, 0, 4), "down"},
+		{"degraded", anyDown, statuses(0, 1, 4), "degraded"},
+		{"minority down", majority, statuses(1, 0, 4), "degraded"},
+		{"half down", majority, statuses(2, 0, 2), "degraded"},
+		{"majority down", majority, statuses(3, 0, 2), "down"},
+		{"majority ignores critical tags", majority, statuses(1, 0, 4, "critical"), "degraded"},
+		{"ordinary monitor down", weighted, statuses(1, 0, 4), "degraded"},
+		{"critical monitor down", weighted, statuses(1, 0, 4, "critical"), "down"},
+		{"critical monitor down in a large group", weighted, statuses(1, 0, 10, "critical"), "degraded"},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.aggregate(tt.statuses); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGetOverviewGroupStatusPolicy(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewUptimeHandler(uptime.NewManager(s), s)
+	_ = s.CreateGroup(db.Group{ID: "g-web", Name: "Web"})
+	for _, m := range []db.Monitor{
+		{ID: "m-home", GroupID: "g-web", Name: "Home", URL: "http://home", Interval: 60, Active: true, Tags: []string{"critical"}},
+		{ID: "m-blog", GroupID: "g-web", Name: "Blog", URL: "http://blog", Interval: 60, Active: true},
+		{ID: "m-docs", GroupID: "g-web", Name: "Docs", URL: "http://docs", Interval: 60, Active: true},
+	} {
+		_ = s.CreateMonitor(m)
+	}
+	now := time.Now()
+	_ = s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-home", Status: "up", Latency: 10, Timestamp: now},
+		{MonitorID: "m-blog", Status: "down", Timestamp: now},
+		{MonitorID: "m-docs", Status: "up", Latency: 10, Timestamp: now},
+	})
+
+	groupStatus := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.GetOverview(w, httptest.NewRequest("GET", "/api/overview", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		var resp OverviewResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		for _, g := range resp.Groups {
+			if g.ID == "g-web" {
+				return g.Status
+			}
+		}
+		t.Fatal("Group missing from the overview")
+		return ""
+	}
+
+	if got := groupStatus(); got != "down" {
+		t.Errorf("Expected any_down by default, got %s", got)
+	}
+	_ = s.SetSetting(groupStatusPolicySettingKey, groupStatusMajority)
+	if got := groupStatus(); got != "degraded" {
+		t.Errorf("Expected one of three down to degrade the group under majority, got %s", got)
+	}
+	_ = s.SetSetting(groupStatusPolicySettingKey, groupStatusWeighted)
+	_ = s.BatchInsertChecks([]db.CheckResult{{MonitorID: "m-home", Status: "down", Timestamp: now.Add(time.Second)}})
+	if got := groupStatus(); got != "down" {
+		t.Errorf("Expected the critical monitor to take the group down under weighted, got %s", got)
+	}
+}
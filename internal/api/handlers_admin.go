@@ -77,3 +77,26 @@ func (h *AdminHandler) performReset(w http.ResponseWriter, clientIP string) {
 	log.Printf("AUDIT: [ADMIN] Database reset COMPLETED successfully from IP %s", sanitizeLog(clientIP)) // #nosec G706 -- sanitized
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Database reset successfully"})
 }
+
+// GetQueryPlans returns the database's execution plan for the hot-path queries (check
+// history, outage tracking, session auth, maintenance sync) so a missing index shows up
+// as a full table scan.
+// @Summary      Explain hot-path queries
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{dialect=string,plans=[]db.QueryPlan}
+// @Failure      500  {object} ErrorResponse
+// @Router       /admin/query-plans [get]
+func (h *AdminHandler) GetQueryPlans(w http.ResponseWriter, r *http.Request) {
+	plans, err := h.store.ExplainHotQueries()
+	if err != nil {
+		log.Printf("ERROR: Failed to explain queries: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to explain queries")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"dialect": h.store.Dialect(),
+		"plans":   plans,
+	})
+}
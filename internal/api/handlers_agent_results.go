@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// Agent result batch limits.
+const (
+	maxAgentBatchResults   = 1000
+	maxAgentBatchBodyBytes = 1 << 20
+	maxIdempotencyKeyLen   = 128
+)
+
+// Per-result outcomes of a batch upload.
+const (
+	agentResultAccepted  = "accepted"
+	agentResultDuplicate = "duplicate"
+	agentResultRejected  = "rejected"
+	agentResultFailed    = "failed" // Valid, but couldn't be ingested; upload it again
+)
+
+// agentBatchResult is one result an agent buffered while it could not reach the server.
+type agentBatchResult struct {
+	IdempotencyKey string     `json:"idempotencyKey"`
+	MonitorID      string     `json:"monitorId"`
+	Status         string     `json:"status"` // up | down
+	Summary        string     `json:"summary,omitempty"`
+	LatencyMs      int64      `json:"latencyMs,omitempty"`
+	Timestamp      *time.Time `json:"timestamp"`
+	Region         string     `json:"region,omitempty"`
+}
+
+// agentBatchOutcome reports what became of one uploaded result.
+type agentBatchOutcome struct {
+	IdempotencyKey string `json:"idempotencyKey"`
+	Status         string `json:"status"` // accepted | duplicate | rejected | failed
+	Error          string `json:"error,omitempty"`
+}
+
+// agentBatchResponse summarizes a batch upload.
+type agentBatchResponse struct {
+	Accepted   int                 `json:"accepted"`
+	Duplicates int                 `json:"duplicates"`
+	Rejected   int                 `json:"rejected"`
+	Failed     int                 `json:"failed"`
+	Results    []agentBatchOutcome `json:"results"`
+}
+
+// validateBatchResult checks a buffered result, returning why it can't be ingested.
+func (h *WSHandler) validateBatchResult(res agentBatchResult, now time.Time) string {
+	switch {
+	case res.IdempotencyKey == "":
+		return "idempotencyKey is required"
+	case len(res.IdempotencyKey) > maxIdempotencyKeyLen:
+		return "idempotencyKey must be at most 128 characters"
+	case res.MonitorID == "":
+		return "monitorId is required"
+	case res.Status != "up" && res.Status != "down":
+		return `status must be "up" or "down"`
+	case res.LatencyMs < 0:
+		return "latencyMs must not be negative"
+	case len(res.Region) > maxRegionLen:
+		return "region must be at most 64 characters"
+	case res.Timestamp == nil:
+		return "timestamp is required"
+	case res.Timestamp.After(now.Add(wsMaxClockSkew)):
+		return "timestamp is in the future"
+	case res.Timestamp.Before(now.Add(-db.AgentResultKeyRetention)):
+		return "timestamp is older than 7 days"
+	}
+	if err := h.manager.ValidateExternalMonitor(res.MonitorID); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// IngestResultBatch accepts results an agent buffered while offline and uploads once it
+// reconnects. Each result carries an idempotency key, so a retried upload only ingests
+// the results the server hasn't seen. Results may arrive out of order: those newer than
+// anything the monitor has processed drive outages and notifications as if they had been
+// streamed, stamped with their own timestamps; older ones are backfilled into the check
+// history and past outages without notifying.
+// @Summary      Upload buffered agent results
+// @Tags         ingest
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body object{results=[]agentBatchResult} true "Up to 1000 results"
+// @Success      200  {object} agentBatchResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      503  {object} ErrorResponse "Nothing was ingested; retry the batch"
+// @Router       /agent/results/batch [post]
+func (h *WSHandler) IngestResultBatch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAgentBatchBodyBytes)
+
+	var req struct {
+		Results []agentBatchResult `json:"results"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Results) == 0 {
+		writeError(w, http.StatusBadRequest, "results must not be empty")
+		return
+	}
+	if len(req.Results) > maxAgentBatchResults {
+		writeError(w, http.StatusBadRequest, "at most 1000 results per batch")
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := agentBatchResponse{Results: make([]agentBatchOutcome, 0, len(req.Results))}
+	// Accepted results by monitor, with their keys and positions in resp.Results
+	type monitorBatch struct {
+		results  []uptime.ExternalResult
+		keys     []string
+		outcomes []int
+	}
+	batches := make(map[string]*monitorBatch)
+	var monitorOrder []string
+	for _, res := range req.Results {
+		outcome := agentBatchOutcome{IdempotencyKey: res.IdempotencyKey, Status: agentResultRejected}
+		if msg := h.validateBatchResult(res, now); msg != "" {
+			outcome.Error = msg
+			resp.Rejected++
+			resp.Results = append(resp.Results, outcome)
+			continue
+		}
+		ok, err := h.store.ClaimAgentResultKey(res.IdempotencyKey, res.MonitorID, now)
+		if err != nil {
+			for _, id := range monitorOrder {
+				h.releaseAgentResultKeys(id, batches[id].keys)
+			}
+			writeError(w, http.StatusServiceUnavailable, "failed to record idempotency keys")
+			return
+		}
+		if !ok {
+			outcome.Status = agentResultDuplicate
+			resp.Duplicates++
+			resp.Results = append(resp.Results, outcome)
+			continue
+		}
+		summary := res.Summary
+		if len(summary) > 1024 {
+			summary = summary[:1024]
+		}
+		b := batches[res.MonitorID]
+		if b == nil {
+			b = &monitorBatch{}
+			batches[res.MonitorID] = b
+			monitorOrder = append(monitorOrder, res.MonitorID)
+		}
+		b.results = append(b.results, uptime.ExternalResult{
+			MonitorID: res.MonitorID,
+			IsUp:      res.Status == "up",
+			Summary:   summary,
+			Latency:   res.LatencyMs,
+			Timestamp: *res.Timestamp,
+			Region:    res.Region,
+		})
+		b.keys = append(b.keys, res.IdempotencyKey)
+		b.outcomes = append(b.outcomes, len(resp.Results))
+		outcome.Status = agentResultAccepted
+		resp.Accepted++
+		resp.Results = append(resp.Results, outcome)
+	}
+
+	// Each monitor's results are ingested all or nothing, so when one fails only its keys
+	// are freed and its results reported for the agent to upload again
+	for _, id := range monitorOrder {
+		b := batches[id]
+		if err := h.manager.IngestExternalResults(b.results, true); err != nil {
+			log.Printf("Agent batch: failed to ingest %d results for %s: %v", len(b.results), sanitizeLog(id), err) // #nosec G706 -- sanitized
+			h.releaseAgentResultKeys(id, b.keys)
+			for _, i := range b.outcomes {
+				resp.Results[i].Status = agentResultFailed
+				resp.Results[i].Error = "failed to ingest results"
+			}
+			resp.Accepted -= len(b.outcomes)
+			resp.Failed += len(b.outcomes)
+		}
+	}
+	if resp.Failed > 0 && resp.Accepted == 0 {
+		writeError(w, http.StatusServiceUnavailable, "failed to ingest results")
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *WSHandler) releaseAgentResultKeys(monitorID string, keys []string) {
+	for _, key := range keys {
+		if err := h.store.ReleaseAgentResultKey(key, monitorID); err != nil {
+			log.Printf("Agent batch: failed to release idempotency key: %v", err)
+		}
+	}
+}
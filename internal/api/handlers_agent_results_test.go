@@ -0,0 +1,152 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestIngestResultBatch(t *testing.T) {
+	store, manager, _ := setupIngestTest(t)
+	r := chi.NewRouter()
+	r.Post("/api/agent/results/batch", NewWSHandler(store, manager).IngestResultBatch)
+
+	now := time.Now().UTC()
+	ts := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+	batch := map[string]any{"results": []map[string]any{
+		{"idempotencyKey": "k2", "monitorId": "ext1", "status": "up", "latencyMs": 40, "timestamp": ts(time.Minute)},
+		{"idempotencyKey": "k1", "monitorId": "ext1", "status": "down", "summary": "edge offline", "timestamp": ts(3 * time.Minute)},
+		{"idempotencyKey": "k3", "monitorId": "http1", "status": "up", "timestamp": ts(time.Minute)},
+		{"idempotencyKey": "k4", "monitorId": "ext1", "status": "up", "timestamp": ts(8 * 24 * time.Hour)},
+		{"idempotencyKey": "k5", "monitorId": "ext1", "status": "up"},
+		{"monitorId": "ext1", "status": "up", "timestamp": ts(time.Minute)},
+		{"idempotencyKey": strings.Repeat("k", 129), "monitorId": "ext1", "status": "up", "timestamp": ts(time.Minute)},
+	}}
+
+	rr := doShadowRequest(r, "POST", "/api/agent/results/batch", batch)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp agentBatchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Accepted != 2 || resp.Duplicates != 0 || resp.Rejected != 5 || len(resp.Results) != 7 {
+		t.Fatalf("Expected 2 accepted and 5 rejected, got %+v", resp)
+	}
+	if resp.Results[0].Status != agentResultAccepted || resp.Results[2].Status != agentResultRejected || resp.Results[2].Error == "" {
+		t.Errorf("Expected outcomes in request order, got %+v", resp.Results)
+	}
+
+	// Replayed in timestamp order: the down result first, then the recovery
+	waitForStatus(t, manager, "ext1", true)
+	if history := manager.GetMonitor("ext1").GetHistory(); len(history) != 2 || history[0].IsUp {
+		t.Errorf("Expected the down result before the up one, got %+v", history)
+	}
+
+	// A retried upload only ingests what the server hasn't seen
+	retry := map[string]any{"results": []map[string]any{
+		{"idempotencyKey": "k1", "monitorId": "ext1", "status": "down", "summary": "edge offline", "timestamp": ts(3 * time.Minute)},
+		{"idempotencyKey": "k2", "monitorId": "ext1", "status": "up", "latencyMs": 40, "timestamp": ts(time.Minute)},
+		{"idempotencyKey": "k6", "monitorId": "ext1", "status": "up", "latencyMs": 40, "timestamp": ts(30 * time.Second)},
+	}}
+	rr = doShadowRequest(r, "POST", "/api/agent/results/batch", retry)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	resp = agentBatchResponse{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Accepted != 1 || resp.Duplicates != 2 {
+		t.Errorf("Expected 1 accepted and 2 duplicates, got %+v", resp)
+	}
+
+	for _, body := range []map[string]any{
+		{"results": []map[string]any{}},
+		{"results": make([]map[string]any, maxAgentBatchResults+1)},
+	} {
+		if rr := doShadowRequest(r, "POST", "/api/agent/results/batch", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rr.Code)
+		}
+	}
+}
+
+func TestIngestResultBatch_MonitorFailsAlone(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "batch.db")
+	store, _ := db.NewStore(db.NewTestConfigWithPath(dbPath))
+	manager := uptime.NewManager(store)
+	manager.Start()
+	t.Cleanup(manager.Stop)
+	for _, id := range []string{"ext1", "ext2"} {
+		if err := store.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: id, Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+			t.Fatalf("Failed to create monitor: %v", err)
+		}
+	}
+	manager.Sync()
+	r := chi.NewRouter()
+	r.Post("/api/agent/results/batch", NewWSHandler(store, manager).IngestResultBatch)
+	upload := func(results ...map[string]any) agentBatchResponse {
+		t.Helper()
+		rr := doShadowRequest(r, "POST", "/api/agent/results/batch", map[string]any{"results": results})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp agentBatchResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		return resp
+	}
+
+	now := time.Now().UTC()
+	ts := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+	upload(map[string]any{"idempotencyKey": "k1", "monitorId": "ext2", "status": "up", "timestamp": ts(2 * time.Minute)})
+	waitForStatus(t, manager, "ext2", true)
+
+	// Backfilling ext2's late result fails the way a database error would
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Exec(`CREATE TRIGGER reject_ext2 BEFORE INSERT ON monitor_checks WHEN NEW.monitor_id = 'ext2' BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+
+	// Keys are per monitor, so ext1 can reuse k1
+	batch := []map[string]any{
+		{"idempotencyKey": "k1", "monitorId": "ext1", "status": "down", "summary": "edge offline", "timestamp": ts(time.Minute)},
+		{"idempotencyKey": "k2", "monitorId": "ext2", "status": "down", "summary": "edge offline", "timestamp": ts(5 * time.Minute)},
+		{"idempotencyKey": "k3", "monitorId": "ext2", "status": "down", "summary": "edge offline", "timestamp": ts(time.Minute)},
+	}
+	resp := upload(batch...)
+	if resp.Accepted != 1 || resp.Failed != 2 || resp.Results[0].Status != agentResultAccepted || resp.Results[1].Status != agentResultFailed || resp.Results[2].Status != agentResultFailed {
+		t.Fatalf("Expected ext1 accepted and ext2 failed, got %+v", resp)
+	}
+	waitForStatus(t, manager, "ext1", false)
+	// The live ext2 result waited for the backfill, so it wasn't processed either
+	time.Sleep(100 * time.Millisecond)
+	if history := manager.GetMonitor("ext2").GetHistory(); len(history) != 1 || !history[0].IsUp {
+		t.Errorf("Expected ext2 to keep only its first result, got %+v", history)
+	}
+
+	// The retry ingests only ext2's results
+	if _, err := conn.Exec(`DROP TRIGGER reject_ext2`); err != nil {
+		t.Fatalf("Failed to drop trigger: %v", err)
+	}
+	resp = upload(batch...)
+	if resp.Accepted != 2 || resp.Duplicates != 1 || resp.Results[0].Status != agentResultDuplicate {
+		t.Fatalf("Expected ext1 duplicate and ext2 accepted, got %+v", resp)
+	}
+	waitForStatus(t, manager, "ext2", false)
+	if history := manager.GetMonitor("ext1").GetHistory(); len(history) != 1 {
+		t.Errorf("Expected ext1's result once, got %+v", history)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -33,30 +34,45 @@ func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
 }
 
+// validateAllowedCIDRs checks an API key allow-list and returns it in canonical form.
+func validateAllowedCIDRs(errs validationErrors, cidrs []string) []string {
+	normalized, err := db.NormalizeCIDRs(cidrs)
+	if err != nil {
+		errs.add("allowedCidrs", "%s", err.Error())
+	}
+	return normalized
+}
+
 // CreateKey generates a new API key. The raw key is returned only once.
 // @Summary      Create API key
+// @Description  allowedCidrs optionally restricts the key to the given networks (CIDRs or single addresses).
 // @Tags         api-keys
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        body body object{name=string} true "Key name"
+// @Param        body body object{name=string,allowedCidrs=[]string} true "Key name and optional allow-list"
 // @Success      200  {object} object{key=string,message=string}
-// @Failure      400  {object} object{error=string} "Name is required"
+// @Failure      422  {object} ValidationErrorResponse
 // @Router       /api-keys [post]
 func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Name string `json:"name"`
+		Name         string   `json:"name"`
+		AllowedCIDRs []string `json:"allowedCidrs"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request")
 		return
 	}
+	errs := validationErrors{}
 	if req.Name == "" {
-		writeError(w, http.StatusBadRequest, "name is required")
+		errs.add("name", "Name is required")
+	}
+	cidrs := validateAllowedCIDRs(errs, req.AllowedCIDRs)
+	if errs.write(w) {
 		return
 	}
 
-	rawKey, err := h.store.CreateAPIKey(req.Name)
+	rawKey, err := h.store.CreateAPIKey(req.Name, cidrs)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to create key")
 		return
@@ -69,6 +85,49 @@ func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateKey replaces an API key's network allow-list. An empty list allows any address.
+// @Summary      Update API key allow-list
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path int true "Key ID"
+// @Param        body body object{allowedCidrs=[]string} true "Allow-list"
+// @Success      200  {object} object{message=string}
+// @Failure      400  {object} ErrorResponse "Invalid ID"
+// @Failure      404  {object} ErrorResponse "Key not found"
+// @Failure      422  {object} ValidationErrorResponse
+// @Router       /api-keys/{id} [patch]
+func (h *APIKeyHandler) UpdateKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	var req struct {
+		AllowedCIDRs []string `json:"allowedCidrs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	errs := validationErrors{}
+	cidrs := validateAllowedCIDRs(errs, req.AllowedCIDRs)
+	if errs.write(w) {
+		return
+	}
+
+	if err := h.store.SetAPIKeyAllowedCIDRs(id, cidrs); err != nil {
+		if errors.Is(err, db.ErrAPIKeyNotFound) {
+			writeError(w, http.StatusNotFound, "key not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update key")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "updated"})
+}
+
 // DeleteKey revokes an API key.
 // @Summary      Delete API key
 // @Tags         api-keys
@@ -76,7 +135,7 @@ func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id   path int true "Key ID"
 // @Success      200  {object} object{message=string}
-// @Failure      400  {object} object{error=string} "Invalid ID"
+// @Failure      400  {object} ErrorResponse "Invalid ID"
 // @Router       /api-keys/{id} [delete]
 func (h *APIKeyHandler) DeleteKey(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
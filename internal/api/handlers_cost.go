@@ -0,0 +1,905 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/agents"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+type CostHandler struct {
+	store   *db.Store
+	manager *uptime.Manager
+}
+
+func NewCostHandler(store *db.Store, manager *uptime.Manager) *CostHandler {
+	return &CostHandler{store: store, manager: manager}
+}
+
+// AgentDTO is an agent as returned by the API; the token itself is never exposed.
+type AgentDTO struct {
+	db.Agent
+	HasToken bool `json:"hasToken"`
+}
+
+type agentRequest struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Token    string `json:"token"`
+	Interval int    `json:"interval"`
+	Active   *bool  `json:"active,omitempty"`
+}
+
+// validate normalizes defaults and returns a user-facing error message, or "" when valid.
+func (req *agentRequest) validate() string {
+	if req.Name == "" || req.URL == "" {
+		return "name and url are required"
+	}
+	// SECURITY: Validate name length
+	if len(req.Name) > maxNameLength {
+		return "name too long (max 255 characters)"
+	}
+	parsedURL, err := url.ParseRequestURI(req.URL)
+	if err != nil {
+		return "invalid url format"
+	}
+	// SECURITY: Only allow http and https protocols to prevent SSRF
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return "only http and https urls are allowed"
+	}
+	if len(req.URL) > 2048 {
+		return "url too long (max 2048 characters)"
+	}
+	if len(req.Token) > 1024 {
+		return "token too long (max 1024 characters)"
+	}
+	if req.Interval == 0 {
+		req.Interval = agents.DefaultInterval
+	}
+	if req.Interval < agents.MinInterval {
+		return "interval must be at least 30 seconds"
+	}
+	return ""
+}
+
+func toAgentDTO(a db.Agent) AgentDTO {
+	return AgentDTO{Agent: a, HasToken: a.Token != ""}
+}
+
+// ListAgents returns all registered cost agents.
+// @Summary      List cost agents
+// @Tags         agents
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{agents=[]AgentDTO}
+// @Router       /agents [get]
+func (h *CostHandler) ListAgents(w http.ResponseWriter, r *http.Request) {
+	list, err := h.store.GetAgents()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list agents")
+		return
+	}
+	dtos := []AgentDTO{}
+	for _, a := range list {
+		dtos = append(dtos, toAgentDTO(a))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"agents": dtos})
+}
+
+// CreateAgent registers a cost agent. It is polled on its next scheduled tick, and its
+// health is watched by an implicit "agent" monitor.
+// @Summary      Register cost agent
+// @Tags         agents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{name=string,url=string,token=string,interval=int} true "Agent payload"
+// @Success      201  {object} AgentDTO
+// @Failure      400  {object} ErrorResponse
+// @Router       /agents [post]
+func (h *CostHandler) CreateAgent(w http.ResponseWriter, r *http.Request) {
+	var req agentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	a := db.Agent{
+		ID:       generateID(req.Name, "a-"),
+		Name:     req.Name,
+		URL:      req.URL,
+		Token:    req.Token,
+		Active:   req.Active == nil || *req.Active,
+		Interval: req.Interval,
+	}
+	if err := h.store.CreateAgent(a); err != nil {
+		writeStoreError(w, err, "failed to create agent")
+		return
+	}
+	h.manager.Sync()
+
+	created, err := h.store.GetAgent(a.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load agent")
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAgentDTO(*created))
+}
+
+// UpdateAgent updates a cost agent. An empty token keeps the current one.
+// @Summary      Update cost agent
+// @Tags         agents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Agent ID"
+// @Param        body body object{name=string,url=string,token=string,interval=int,active=bool} true "Agent payload"
+// @Success      200  {object} AgentDTO
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /agents/{id} [put]
+func (h *CostHandler) UpdateAgent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	existing, err := h.store.GetAgent(id)
+	if err != nil {
+		if errors.Is(err, db.ErrAgentNotFound) {
+			writeError(w, http.StatusNotFound, "agent not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load agent")
+		return
+	}
+
+	var req agentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	existing.Name = req.Name
+	existing.URL = req.URL
+	existing.Token = req.Token
+	existing.Interval = req.Interval
+	if req.Active != nil {
+		existing.Active = *req.Active
+	}
+	if err := h.store.UpdateAgent(*existing); err != nil {
+		writeStoreError(w, err, "failed to update agent")
+		return
+	}
+	h.manager.Sync()
+
+	updated, err := h.store.GetAgent(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load agent")
+		return
+	}
+	writeJSON(w, http.StatusOK, toAgentDTO(*updated))
+}
+
+// DeleteAgent removes a cost agent, its collected snapshots and its health monitor.
+// @Summary      Delete cost agent
+// @Tags         agents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Agent ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /agents/{id} [delete]
+func (h *CostHandler) DeleteAgent(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.DeleteAgent(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, db.ErrAgentNotFound) {
+			writeError(w, http.StatusNotFound, "agent not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete agent")
+		return
+	}
+	h.manager.Sync()
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
+
+// ClusterSummary is one agent's latest summary.
+type ClusterSummary struct {
+	AgentID     string    `json:"agentId"`
+	AgentName   string    `json:"agentName"`
+	CollectedAt time.Time `json:"collectedAt"`
+	agents.Summary
+}
+
+// CostSummaryResponse aggregates the latest summaries of all (or one) agents.
+type CostSummaryResponse struct {
+	TotalHourlyCost         float64          `json:"totalHourlyCost"`
+	TotalCPUCores           float64          `json:"totalCpuCores"`
+	TotalCPURequestedCores  float64          `json:"totalCpuRequestedCores"`
+	TotalMemoryGiB          float64          `json:"totalMemoryGiB"`
+	TotalMemoryRequestedGiB float64          `json:"totalMemoryRequestedGiB"`
+	Clusters                []ClusterSummary `json:"clusters"`
+}
+
+type AgentNamespaceCost struct {
+	AgentID string `json:"agentId"`
+	agents.Namespace
+}
+
+type AgentNodeCost struct {
+	AgentID string `json:"agentId"`
+	agents.Node
+}
+
+type AgentWorkloadCost struct {
+	AgentID string `json:"agentId"`
+	agents.Workload
+}
+
+// agentData pairs an agent with its most recently collected data.
+type agentData struct {
+	agent       db.Agent
+	collectedAt time.Time
+	data        *agents.Collection
+}
+
+// latestData loads the latest snapshot of every agent, or of the agent named by
+// the "agent" query parameter. Agents that were never polled successfully are omitted.
+func (h *CostHandler) latestData(w http.ResponseWriter, r *http.Request) ([]agentData, bool) {
+	var list []db.Agent
+	if id := r.URL.Query().Get("agent"); id != "" {
+		a, err := h.store.GetAgent(id)
+		if err != nil {
+			if errors.Is(err, db.ErrAgentNotFound) {
+				writeError(w, http.StatusNotFound, "agent not found")
+				return nil, false
+			}
+			writeError(w, http.StatusInternalServerError, "failed to load agent")
+			return nil, false
+		}
+		list = []db.Agent{*a}
+	} else {
+		all, err := h.store.GetAgents()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to list agents")
+			return nil, false
+		}
+		list = all
+	}
+
+	var out []agentData
+	for _, a := range list {
+		snap, err := h.store.GetLatestAgentSnapshot(a.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to load cost data")
+			return nil, false
+		}
+		if snap == nil {
+			continue
+		}
+		data, err := agents.DecodeSnapshot(snap)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to decode cost data")
+			return nil, false
+		}
+		out = append(out, agentData{agent: a, collectedAt: snap.CollectedAt, data: data})
+	}
+	return out, true
+}
+
+// GetSummary returns the latest cost summary aggregated across agents.
+// @Summary      Cost summary
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        agent query string false "Limit to one agent ID"
+// @Success      200  {object} CostSummaryResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/summary [get]
+func (h *CostHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.latestData(w, r)
+	if !ok {
+		return
+	}
+
+	resp := CostSummaryResponse{Clusters: []ClusterSummary{}}
+	for _, d := range list {
+		s := d.data.Summary
+		resp.TotalHourlyCost += s.TotalHourlyCost
+		resp.TotalCPUCores += s.TotalCPUCores
+		resp.TotalCPURequestedCores += s.TotalCPURequestedCores
+		resp.TotalMemoryGiB += s.TotalMemoryGiB
+		resp.TotalMemoryRequestedGiB += s.TotalMemoryRequestedGiB
+		resp.Clusters = append(resp.Clusters, ClusterSummary{
+			AgentID:     d.agent.ID,
+			AgentName:   d.agent.Name,
+			CollectedAt: d.collectedAt,
+			Summary:     s,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetNamespaces returns the latest per-namespace costs across agents, most expensive first.
+// @Summary      Cost by namespace
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        agent query string false "Limit to one agent ID"
+// @Success      200  {array} AgentNamespaceCost
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/namespaces [get]
+func (h *CostHandler) GetNamespaces(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.latestData(w, r)
+	if !ok {
+		return
+	}
+	out := []AgentNamespaceCost{}
+	for _, d := range list {
+		for _, ns := range d.data.Namespaces {
+			out = append(out, AgentNamespaceCost{AgentID: d.agent.ID, Namespace: ns})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].HourlyCost > out[j].HourlyCost })
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GetNodes returns the latest per-node costs across agents, most expensive first.
+// @Summary      Cost by node
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        agent query string false "Limit to one agent ID"
+// @Success      200  {array} AgentNodeCost
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/nodes [get]
+func (h *CostHandler) GetNodes(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.latestData(w, r)
+	if !ok {
+		return
+	}
+	out := []AgentNodeCost{}
+	for _, d := range list {
+		for _, n := range d.data.Nodes {
+			out = append(out, AgentNodeCost{AgentID: d.agent.ID, Node: n})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].AllocatedCostHourly > out[j].AllocatedCostHourly })
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GetWorkloads returns the latest per-workload costs across agents, most expensive first.
+// @Summary      Cost by workload
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        agent query string false "Limit to one agent ID"
+// @Success      200  {array} AgentWorkloadCost
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/workloads [get]
+func (h *CostHandler) GetWorkloads(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.latestData(w, r)
+	if !ok {
+		return
+	}
+	out := []AgentWorkloadCost{}
+	for _, d := range list {
+		for _, wl := range d.data.Workloads {
+			out = append(out, AgentWorkloadCost{AgentID: d.agent.ID, Workload: wl})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].HourlyCost > out[j].HourlyCost })
+	writeJSON(w, http.StatusOK, out)
+}
+
+// CostHistoryDay is the cost of one day, summed from hourly rates.
+type CostHistoryDay struct {
+	Date          string             `json:"date"` // YYYY-MM-DD in the requested timezone
+	Cost          float64            `json:"cost"`
+	AvgHourlyCost float64            `json:"avgHourlyCost"`
+	Hours         int                `json:"hours"` // Hours with data; less than 24 when polls were missed
+	Namespaces    map[string]float64 `json:"namespaces"`
+}
+
+type CostHistoryResponse struct {
+	Range string           `json:"range"`
+	From  string           `json:"from"` // first day, YYYY-MM-DD
+	To    string           `json:"to"`   // last day so far, YYYY-MM-DD
+	Days  []CostHistoryDay `json:"days"`
+}
+
+// GetHistory returns daily cost aggregated from the stored hourly history.
+// @Summary      Cost history
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        range      query string false "Range in days, e.g. 7d, 30d, 90d (default 30d, max 365d), or a preset: today, yesterday, this_week, last_week, this_month, last_month, this_quarter, last_quarter, this_year, last_year"
+// @Param        agent      query string false "Limit to one agent ID"
+// @Param        tz         query string false "IANA timezone for day boundaries (default: user's timezone)"
+// @Param        week_start query string false "First day of the week for week presets, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} CostHistoryResponse
+// @Failure      400  {object} ErrorResponse
+// @Router       /cost/history [get]
+func (h *CostHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	rng, now, ok := resolveReportRange(w, r, h.store, "30d")
+	if !ok {
+		return
+	}
+	loc := now.Location()
+
+	entries, err := h.store.GetCostHistory(r.URL.Query().Get("agent"), "", rng.From)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load cost history")
+		return
+	}
+
+	byDate := make(map[string]*CostHistoryDay)
+	hoursSeen := make(map[string]map[time.Time]bool)
+	var order []string
+	for _, e := range entries {
+		if !rng.contains(e.Bucket) {
+			continue
+		}
+		date := e.Bucket.In(loc).Format("2006-01-02")
+		day, exists := byDate[date]
+		if !exists {
+			day = &CostHistoryDay{Date: date, Namespaces: map[string]float64{}}
+			byDate[date] = day
+			hoursSeen[date] = map[time.Time]bool{}
+			order = append(order, date)
+		}
+		switch e.Scope {
+		case db.CostScopeCluster:
+			day.Cost += e.HourlyCost
+			hoursSeen[date][e.Bucket.UTC()] = true
+		case db.CostScopeNamespace:
+			day.Namespaces[e.Name] += e.HourlyCost
+		}
+	}
+
+	resp := CostHistoryResponse{
+		Range: rng.Name,
+		From:  rng.From.Format("2006-01-02"),
+		To:    rng.lastDay(now).Format("2006-01-02"),
+		Days:  []CostHistoryDay{},
+	}
+	for _, date := range order {
+		day := byDate[date]
+		day.Hours = len(hoursSeen[date])
+		if day.Hours > 0 {
+			day.AvgHourlyCost = day.Cost / float64(day.Hours)
+		}
+		resp.Days = append(resp.Days, *day)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// BudgetDTO is a budget together with its current month's spend.
+type BudgetDTO struct {
+	db.CostBudget
+	Status agents.BudgetStatus `json:"status"`
+}
+
+type budgetRequest struct {
+	Name         string  `json:"name"`
+	AgentID      string  `json:"agentId"`
+	Scope        string  `json:"scope"`
+	ScopeValue   string  `json:"scopeValue"`
+	MonthlyLimit float64 `json:"monthlyLimit"`
+	Enabled      *bool   `json:"enabled,omitempty"`
+}
+
+// validateBudget returns a user-facing error message, or "" when the request is valid.
+func (h *CostHandler) validateBudget(req *budgetRequest) string {
+	if req.Name == "" {
+		return "name is required"
+	}
+	if len(req.Name) > maxNameLength {
+		return "name too long (max 255 characters)"
+	}
+	switch req.Scope {
+	case db.CostScopeCluster:
+		req.ScopeValue = ""
+	case db.CostScopeNamespace:
+		if req.ScopeValue == "" {
+			return "scopeValue (namespace) is required for namespace budgets"
+		}
+	case db.CostScopeLabel:
+		if k, v, ok := strings.Cut(req.ScopeValue, "="); !ok || k == "" || v == "" {
+			return "scopeValue must be 'key=value' for label budgets"
+		}
+	default:
+		return "scope must be 'cluster', 'namespace' or 'label'"
+	}
+	if len(req.ScopeValue) > maxNameLength {
+		return "scopeValue too long (max 255 characters)"
+	}
+	if req.MonthlyLimit <= 0 {
+		return "monthlyLimit must be greater than 0"
+	}
+	if req.AgentID != "" {
+		if _, err := h.store.GetAgent(req.AgentID); err != nil {
+			return "agent not found"
+		}
+	}
+	return ""
+}
+
+func (h *CostHandler) budgetDTO(b db.CostBudget) (BudgetDTO, error) {
+	status, err := agents.EvaluateBudget(h.store, b, time.Now())
+	return BudgetDTO{CostBudget: b, Status: status}, err
+}
+
+// ListBudgets returns all cost budgets with their current month's spend.
+// @Summary      List cost budgets
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{budgets=[]BudgetDTO}
+// @Router       /cost/budgets [get]
+func (h *CostHandler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	budgets, err := h.store.GetCostBudgets()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list budgets")
+		return
+	}
+	dtos := []BudgetDTO{}
+	for _, b := range budgets {
+		dto, err := h.budgetDTO(b)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to evaluate budgets")
+			return
+		}
+		dtos = append(dtos, dto)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"budgets": dtos})
+}
+
+// CreateBudget defines a monthly cost budget.
+// @Summary      Create cost budget
+// @Tags         cost
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{name=string,agentId=string,scope=string,scopeValue=string,monthlyLimit=number} true "Budget (scope: cluster|namespace|label)"
+// @Success      201  {object} BudgetDTO
+// @Failure      400  {object} ErrorResponse
+// @Router       /cost/budgets [post]
+func (h *CostHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := h.validateBudget(&req); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	id, err := h.store.CreateCostBudget(db.CostBudget{
+		Name:         req.Name,
+		AgentID:      req.AgentID,
+		Scope:        req.Scope,
+		ScopeValue:   req.ScopeValue,
+		MonthlyLimit: req.MonthlyLimit,
+		Enabled:      req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create budget")
+		return
+	}
+
+	b, err := h.store.GetCostBudget(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load budget")
+		return
+	}
+	dto, err := h.budgetDTO(*b)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to evaluate budget")
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto)
+}
+
+// UpdateBudget replaces a cost budget's definition.
+// @Summary      Update cost budget
+// @Tags         cost
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path int true "Budget ID"
+// @Param        body body object{name=string,agentId=string,scope=string,scopeValue=string,monthlyLimit=number,enabled=bool} true "Budget"
+// @Success      200  {object} BudgetDTO
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/budgets/{id} [put]
+func (h *CostHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	existing, err := h.store.GetCostBudget(id)
+	if err != nil {
+		if errors.Is(err, db.ErrBudgetNotFound) {
+			writeError(w, http.StatusNotFound, "budget not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load budget")
+		return
+	}
+
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := h.validateBudget(&req); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	existing.Name = req.Name
+	existing.AgentID = req.AgentID
+	existing.Scope = req.Scope
+	existing.ScopeValue = req.ScopeValue
+	existing.MonthlyLimit = req.MonthlyLimit
+	if req.Enabled != nil {
+		existing.Enabled = *req.Enabled
+	}
+	if err := h.store.UpdateCostBudget(*existing); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update budget")
+		return
+	}
+
+	b, err := h.store.GetCostBudget(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load budget")
+		return
+	}
+	dto, err := h.budgetDTO(*b)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to evaluate budget")
+		return
+	}
+	writeJSON(w, http.StatusOK, dto)
+}
+
+// DeleteBudget removes a cost budget.
+// @Summary      Delete cost budget
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path int true "Budget ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/budgets/{id} [delete]
+func (h *CostHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	if err := h.store.DeleteCostBudget(id); err != nil {
+		if errors.Is(err, db.ErrBudgetNotFound) {
+			writeError(w, http.StatusNotFound, "budget not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete budget")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
+
+// RecommendationsResponse lists right-sizing recommendations and their combined savings.
+type RecommendationsResponse struct {
+	TotalMonthlySavings float64                 `json:"totalMonthlySavings"`
+	Recommendations     []db.CostRecommendation `json:"recommendations"`
+}
+
+// GetRecommendations returns right-sizing recommendations for over-provisioned workloads,
+// largest estimated savings first. They are refreshed on every agent poll.
+// @Summary      Right-sizing recommendations
+// @Tags         cost
+// @Produce      json
+// @Security     BearerAuth
+// @Param        agent query string false "Limit to one agent ID"
+// @Success      200  {object} RecommendationsResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /cost/recommendations [get]
+func (h *CostHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	agentID := r.URL.Query().Get("agent")
+	if agentID != "" {
+		if _, err := h.store.GetAgent(agentID); err != nil {
+			if errors.Is(err, db.ErrAgentNotFound) {
+				writeError(w, http.StatusNotFound, "agent not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "failed to load agent")
+			return
+		}
+	}
+
+	recs, err := h.store.GetCostRecommendations(agentID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load recommendations")
+		return
+	}
+	resp := RecommendationsResponse{Recommendations: []db.CostRecommendation{}}
+	for _, rec := range recs {
+		resp.TotalMonthlySavings += rec.MonthlySavings
+		resp.Recommendations = append(resp.Recommendations, rec)
+	}
+	resp.TotalMonthlySavings = math.Round(resp.TotalMonthlySavings*100) / 100
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// LabelValueCost is the cost attributed to one label value over the report range.
+type LabelValueCost struct {
+	Value string           `json:"value"`
+	Cost  float64          `json:"cost"`
+	Share float64          `json:"share"` // Percentage of the label's total cost
+	Daily []LabelDailyCost `json:"daily"`
+}
+
+type LabelDailyCost struct {
+	Date string  `json:"date"` // YYYY-MM-DD (UTC)
+	Cost float64 `json:"cost"`
+}
+
+type LabelReportResponse struct {
+	Key    string           `json:"key"`
+	Range  string           `json:"range"`
+	From   string           `json:"from"`
+	To     string           `json:"to"`
+	Total  float64          `json:"total"`
+	Values []LabelValueCost `json:"values"`
+}
+
+// GetLabelReport aggregates stored cost history by the values of one label, per day.
+// With format=csv the report is returned as a CSV attachment (one row per value and day).
+// @Summary      Cost allocation by label
+// @Tags         cost
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        key    path  string true  "Label key, e.g. team"
+// @Param        range      query string false "Range in days, e.g. 7d, 30d (default 30d, max 365d), or a preset such as last_month"
+// @Param        agent      query string false "Limit to one agent ID"
+// @Param        format     query string false "json (default) or csv"
+// @Param        tz         query string false "IANA timezone for day boundaries (default: user's timezone)"
+// @Param        week_start query string false "First day of the week for week presets, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} LabelReportResponse
+// @Failure      400  {object} ErrorResponse
+// @Router       /cost/labels/{key}/report [get]
+func (h *CostHandler) GetLabelReport(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	if key == "" || len(key) > maxNameLength || strings.Contains(key, "=") {
+		writeError(w, http.StatusBadRequest, "invalid label key")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "format must be 'json' or 'csv'")
+		return
+	}
+	rng, now, ok := resolveReportRange(w, r, h.store, "30d")
+	if !ok {
+		return
+	}
+
+	entries, err := h.store.GetCostHistory(r.URL.Query().Get("agent"), db.CostScopeLabel, rng.From)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load cost history")
+		return
+	}
+
+	prefix := key + "="
+	byValue := make(map[string]*LabelValueCost)
+	dailyIndex := make(map[string]map[string]int) // value -> date -> index into Daily
+	resp := LabelReportResponse{
+		Key:    key,
+		Range:  rng.Name,
+		From:   rng.From.Format("2006-01-02"),
+		To:     rng.lastDay(now).Format("2006-01-02"),
+		Values: []LabelValueCost{},
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name, prefix) || !rng.contains(e.Bucket) {
+			continue
+		}
+		value := strings.TrimPrefix(e.Name, prefix)
+		v, exists := byValue[value]
+		if !exists {
+			v = &LabelValueCost{Value: value, Daily: []LabelDailyCost{}}
+			byValue[value] = v
+			dailyIndex[value] = map[string]int{}
+		}
+		date := e.Bucket.In(now.Location()).Format("2006-01-02")
+		idx, seen := dailyIndex[value][date]
+		if !seen {
+			v.Daily = append(v.Daily, LabelDailyCost{Date: date})
+			idx = len(v.Daily) - 1
+			dailyIndex[value][date] = idx
+		}
+		v.Daily[idx].Cost += e.HourlyCost
+		v.Cost += e.HourlyCost
+		resp.Total += e.HourlyCost
+	}
+
+	for _, v := range byValue {
+		if resp.Total > 0 {
+			v.Share = v.Cost / resp.Total * 100
+		}
+		resp.Values = append(resp.Values, *v)
+	}
+	sort.Slice(resp.Values, func(i, j int) bool {
+		if resp.Values[i].Cost != resp.Values[j].Cost {
+			return resp.Values[i].Cost > resp.Values[j].Cost
+		}
+		return resp.Values[i].Value < resp.Values[j].Value
+	})
+
+	if format == "csv" {
+		writeLabelReportCSV(w, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeLabelReportCSV writes one row per label value and day.
+func writeLabelReportCSV(w http.ResponseWriter, report LabelReportResponse) {
+	filename := "cost-" + sanitizeFilename(report.Key) + "-" + report.From + "-" + report.To + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"label_key", "label_value", "date", "cost"})
+	for _, v := range report.Values {
+		for _, d := range v.Daily {
+			_ = cw.Write([]string{
+				csvSafe(report.Key),
+				csvSafe(v.Value),
+				d.Date,
+				strconv.FormatFloat(d.Cost, 'f', 4, 64),
+			})
+		}
+	}
+	cw.Flush()
+}
+
+// csvSafe neutralizes values that spreadsheet applications would interpret as formulas.
+// SECURITY: Label values come from the cluster and end up in finance spreadsheets.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// sanitizeFilename keeps only characters that are safe in a Content-Disposition filename.
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+}
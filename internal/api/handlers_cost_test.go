@@ -0,0 +1,374 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func setupCostTest(t *testing.T) (*db.Store, http.Handler) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	r := chi.NewRouter()
+	r.Get("/api/agents", h.ListAgents)
+	r.Post("/api/agents", h.CreateAgent)
+	r.Put("/api/agents/{id}", h.UpdateAgent)
+	r.Delete("/api/agents/{id}", h.DeleteAgent)
+	r.Get("/api/cost/summary", h.GetSummary)
+	r.Get("/api/cost/namespaces", h.GetNamespaces)
+	return store, r
+}
+
+func TestCostHandler_AgentCRUD(t *testing.T) {
+	_, router := setupCostTest(t)
+
+	// Validation
+	for _, body := range []string{
+		`{"name":"","url":"http://agent"}`,
+		`{"name":"a","url":"ftp://agent"}`,
+		`{"name":"a","url":"http://agent","interval":5}`,
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/agents", bytes.NewBufferString(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/agents", bytes.NewBufferString(`{"name":"Prod","url":"http://agent:8080","token":"secret"}`)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if bytes.Contains(rr.Body.Bytes(), []byte("secret")) {
+		t.Error("Agent token must not be returned")
+	}
+	var created map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if created["hasToken"] != true || created["interval"] != float64(300) {
+		t.Errorf("Unexpected create response: %v", created)
+	}
+	id := created["id"].(string)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", "/api/agents/"+id, bytes.NewBufferString(`{"name":"Prod EU","url":"http://agent:8080","active":false}`)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var updated map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &updated)
+	if updated["name"] != "Prod EU" || updated["active"] != false || updated["hasToken"] != true {
+		t.Errorf("Unexpected update response: %v", updated)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/api/agents/"+id, nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("DELETE", "/api/agents/"+id, nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_CreateAgentNameConflict(t *testing.T) {
+	store, router := setupCostTest(t)
+	// The agent's health monitor would be named "Prod (agent)"
+	if err := store.CreateMonitor(db.Monitor{ID: "m-taken", GroupID: "g-default", Name: "Prod (agent)", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/agents", bytes.NewBufferString(`{"name":"Prod","url":"http://agent:8080"}`)))
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("attempt %d: expected 409, got %d: %s", i+1, rr.Code, rr.Body.String())
+		}
+	}
+	if agents, _ := store.GetAgents(); len(agents) != 0 {
+		t.Errorf("Expected no agent to be left behind, got %+v", agents)
+	}
+}
+
+func TestCostHandler_Aggregates(t *testing.T) {
+	store, router := setupCostTest(t)
+
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "us", URL: "http://us", Active: true, Interval: 300})
+	_ = store.CreateAgent(db.Agent{ID: "a2", Name: "eu", URL: "http://eu", Active: true, Interval: 300})
+	_ = store.CreateAgent(db.Agent{ID: "a3", Name: "never-polled", URL: "http://x", Active: true, Interval: 300})
+	_ = store.SaveAgentSnapshot(db.AgentSnapshot{AgentID: "a1", TotalHourlyCost: 10,
+		Summary:    `{"clusterName":"us","totalHourlyCost":10,"totalCpuCores":32}`,
+		Namespaces: `[{"namespace":"api","hourlyCost":3}]`})
+	_ = store.SaveAgentSnapshot(db.AgentSnapshot{AgentID: "a2", TotalHourlyCost: 5,
+		Summary:    `{"clusterName":"eu","totalHourlyCost":5,"totalCpuCores":16}`,
+		Namespaces: `[{"namespace":"payments","hourlyCost":4}]`})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/summary", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	var summary CostSummaryResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &summary)
+	if summary.TotalHourlyCost != 15 || summary.TotalCPUCores != 48 || len(summary.Clusters) != 2 {
+		t.Errorf("Unexpected aggregate summary: %+v", summary)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/namespaces", nil))
+	var namespaces []AgentNamespaceCost
+	_ = json.Unmarshal(rr.Body.Bytes(), &namespaces)
+	if len(namespaces) != 2 || namespaces[0].Namespace.Namespace != "payments" || namespaces[0].AgentID != "a2" {
+		t.Errorf("Expected namespaces sorted by cost across agents, got %+v", namespaces)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/summary?agent=a1", nil))
+	_ = json.Unmarshal(rr.Body.Bytes(), &summary)
+	if summary.TotalHourlyCost != 10 || len(summary.Clusters) != 1 {
+		t.Errorf("Expected single-agent summary, got %+v", summary)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/summary?agent=missing", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown agent, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_History(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "us", URL: "http://us", Active: true, Interval: 300})
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	_ = store.RecordCostHistory([]db.CostHistoryEntry{
+		{AgentID: "a1", Bucket: yesterday.Add(1 * time.Hour), Scope: db.CostScopeCluster, HourlyCost: 2},
+		{AgentID: "a1", Bucket: yesterday.Add(2 * time.Hour), Scope: db.CostScopeCluster, HourlyCost: 4},
+		{AgentID: "a1", Bucket: yesterday.Add(2 * time.Hour), Scope: db.CostScopeNamespace, Name: "api", HourlyCost: 1},
+		{AgentID: "a1", Bucket: today, Scope: db.CostScopeCluster, HourlyCost: 5},
+		{AgentID: "a1", Bucket: today.AddDate(0, 0, -40), Scope: db.CostScopeCluster, HourlyCost: 100},
+	})
+
+	rr := httptest.NewRecorder()
+	h.GetHistory(rr, httptest.NewRequest("GET", "/api/cost/history?range=30d", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	var resp CostHistoryResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if len(resp.Days) != 2 {
+		t.Fatalf("Expected 2 days, got %+v", resp.Days)
+	}
+	first := resp.Days[0]
+	if first.Date != yesterday.Format("2006-01-02") || first.Cost != 6 || first.Hours != 2 || first.AvgHourlyCost != 3 || first.Namespaces["api"] != 1 {
+		t.Errorf("Unexpected first day: %+v", first)
+	}
+
+	// Presets cover whole days of the requested timezone
+	rr = httptest.NewRecorder()
+	h.GetHistory(rr, httptest.NewRequest("GET", "/api/cost/history?range=yesterday&tz=UTC", nil))
+	resp = CostHistoryResponse{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Range != "yesterday" || resp.From != yesterday.Format("2006-01-02") || resp.To != resp.From {
+		t.Errorf("Unexpected range %q from %s to %s", resp.Range, resp.From, resp.To)
+	}
+	if len(resp.Days) != 1 || resp.Days[0].Cost != 6 {
+		t.Errorf("Expected only yesterday's cost, got %+v", resp.Days)
+	}
+
+	rr = httptest.NewRecorder()
+	h.GetHistory(rr, httptest.NewRequest("GET", "/api/cost/history?range=abc", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid range, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_Budgets(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	r := chi.NewRouter()
+	r.Get("/api/cost/budgets", h.ListBudgets)
+	r.Post("/api/cost/budgets", h.CreateBudget)
+	r.Delete("/api/cost/budgets/{id}", h.DeleteBudget)
+
+	for _, body := range []string{
+		`{"name":"x","scope":"cluster","monthlyLimit":0}`,
+		`{"name":"x","scope":"namespace","monthlyLimit":10}`,
+		`{"name":"x","scope":"label","scopeValue":"team","monthlyLimit":10}`,
+		`{"name":"x","scope":"galaxy","monthlyLimit":10}`,
+		`{"name":"x","scope":"cluster","monthlyLimit":10,"agentId":"missing"}`,
+	} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("POST", "/api/cost/budgets", bytes.NewBufferString(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", "/api/cost/budgets", bytes.NewBufferString(`{"name":"Backend","scope":"label","scopeValue":"team=backend","monthlyLimit":1000}`)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created BudgetDTO
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if !created.Enabled || created.Status.Period == "" {
+		t.Errorf("Unexpected created budget: %+v", created)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/budgets", nil))
+	var list struct {
+		Budgets []BudgetDTO `json:"budgets"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list.Budgets) != 1 {
+		t.Errorf("Expected 1 budget, got %d", len(list.Budgets))
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("DELETE", "/api/cost/budgets/"+strconv.FormatInt(created.ID, 10), nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_LabelReport(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	r := chi.NewRouter()
+	r.Get("/api/cost/labels/{key}/report", h.GetLabelReport)
+
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "us", URL: "http://us", Active: true, Interval: 300})
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	_ = store.RecordCostHistory([]db.CostHistoryEntry{
+		{AgentID: "a1", Bucket: today, Scope: db.CostScopeLabel, Name: "team=backend", HourlyCost: 3},
+		{AgentID: "a1", Bucket: today.Add(time.Hour), Scope: db.CostScopeLabel, Name: "team=backend", HourlyCost: 3},
+		{AgentID: "a1", Bucket: today, Scope: db.CostScopeLabel, Name: "team==cmd", HourlyCost: 2},
+		{AgentID: "a1", Bucket: today, Scope: db.CostScopeLabel, Name: "env=prod", HourlyCost: 100},
+	})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/labels/team/report?range=7d", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var report LabelReportResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &report)
+	if report.Total != 8 || len(report.Values) != 2 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if report.Values[0].Value != "backend" || report.Values[0].Cost != 6 || report.Values[0].Share != 75 || len(report.Values[0].Daily) != 1 {
+		t.Errorf("Unexpected top value: %+v", report.Values[0])
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/labels/team/report?range=7d&format=csv", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected CSV content type, got %q", ct)
+	}
+	csvBody := rr.Body.String()
+	if !strings.HasPrefix(csvBody, "label_key,label_value,date,cost\n") {
+		t.Errorf("Unexpected CSV header: %q", csvBody)
+	}
+	if !strings.Contains(csvBody, "team,backend,"+today.Format("2006-01-02")+",6.0000") {
+		t.Errorf("Expected backend row in CSV, got %q", csvBody)
+	}
+	// Formula-looking values are neutralized
+	if !strings.Contains(csvBody, "team,'=cmd,") {
+		t.Errorf("Expected formula value to be escaped, got %q", csvBody)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/labels/team/report?format=xml", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown format, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_LabelReportTimezone(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	r := chi.NewRouter()
+	r.Get("/api/cost/labels/{key}/report", h.GetLabelReport)
+
+	// Two hours either side of midnight UTC: two days in UTC, one in Tokyo (UTC+9)
+	midnight := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "us", URL: "http://us", Active: true, Interval: 300})
+	_ = store.RecordCostHistory([]db.CostHistoryEntry{
+		{AgentID: "a1", Bucket: midnight.Add(-time.Hour), Scope: db.CostScopeLabel, Name: "team=backend", HourlyCost: 1},
+		{AgentID: "a1", Bucket: midnight.Add(time.Hour), Scope: db.CostScopeLabel, Name: "team=backend", HourlyCost: 2},
+	})
+
+	daily := func(tz string) []LabelDailyCost {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/labels/team/report?range=7d&tz="+tz, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("tz=%s: expected 200, got %d: %s", tz, rr.Code, rr.Body.String())
+		}
+		var report LabelReportResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &report)
+		if len(report.Values) != 1 {
+			t.Fatalf("tz=%s: unexpected report %+v", tz, report)
+		}
+		return report.Values[0].Daily
+	}
+
+	if days := daily("UTC"); len(days) != 2 {
+		t.Errorf("Expected 2 days in UTC, got %+v", days)
+	}
+	days := daily("Asia/Tokyo")
+	if len(days) != 1 || days[0].Date != midnight.Format("2006-01-02") || days[0].Cost != 3 {
+		t.Errorf("Expected a single Tokyo day on %s, got %+v", midnight.Format("2006-01-02"), days)
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/labels/team/report?tz=Mars/Olympus", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown timezone, got %d", rr.Code)
+	}
+}
+
+func TestCostHandler_Recommendations(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewCostHandler(store, uptime.NewManager(store))
+	r := chi.NewRouter()
+	r.Get("/api/cost/recommendations", h.GetRecommendations)
+
+	_ = store.CreateAgent(db.Agent{ID: "a1", Name: "us", URL: "http://us", Active: true, Interval: 300})
+	_ = store.ReplaceCostRecommendations("a1", []db.CostRecommendation{
+		{Namespace: "payments", WorkloadKind: "Deployment", WorkloadName: "api", MonthlySavings: 100.25},
+		{Namespace: "payments", WorkloadKind: "Deployment", WorkloadName: "worker", MonthlySavings: 20.5},
+	})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/recommendations?agent=a1", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp RecommendationsResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.TotalMonthlySavings != 120.75 || len(resp.Recommendations) != 2 || resp.Recommendations[0].WorkloadName != "api" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/api/cost/recommendations?agent=missing", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown agent, got %d", rr.Code)
+	}
+}
@@ -1,14 +1,17 @@
 package api
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -42,18 +45,184 @@ func generateID(name, prefix string) string {
 }
 
 // generateSlug creates a clean slug ID from a name without hash
-// e.g. "My Group" -> "g-my-group"
+// e.g. "My Group" -> "g-my-group". Names without any usable characters
+// (e.g. "!!!") get a random slug instead of an empty one.
 func generateSlug(name, prefix string) string {
-	slug := strings.ToLower(name)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	reg := regexp.MustCompile("[^a-z0-9-]+")
-	slug = reg.ReplaceAllString(slug, "")
+	slug := normalizeSlug(name)
+	if slug == "" {
+		slug = randomSlug()
+	}
 	return prefix + slug
 }
 
 // maxNameLength is the maximum allowed length for names (groups, monitors)
 const maxNameLength = 255
 
+// minMonitorInterval is the shortest check interval, in seconds.
+const minMonitorInterval = 10
+
+// validateName checks a required group or monitor name.
+func validateName(errs validationErrors, name string) {
+	if name == "" {
+		errs.add("name", "Name is required")
+		return
+	}
+	// SECURITY: Validate name length
+	if len(name) > maxNameLength {
+		errs.add("name", "Name too long (max %d characters)", maxNameLength)
+	}
+}
+
+// validateMonitorOverrides checks the per-monitor alerting overrides and request settings.
+func validateMonitorOverrides(errs validationErrors, confirmationThreshold, cooldownMinutes, latencyThreshold *int, cfg *db.RequestConfig) {
+	if confirmationThreshold != nil && (*confirmationThreshold < 1 || *confirmationThreshold > 100) {
+		errs.add("confirmationThreshold", "confirmationThreshold must be between 1 and 100")
+	}
+	if cooldownMinutes != nil && (*cooldownMinutes < 0 || *cooldownMinutes > 1440) {
+		errs.add("notificationCooldownMinutes", "notificationCooldownMinutes must be between 0 and 1440")
+	}
+	if latencyThreshold != nil && *latencyThreshold < 1 {
+		errs.add("latencyThreshold", "latencyThreshold must be at least 1")
+	}
+	errs.addErr("requestConfig", validateRequestConfig(cfg))
+}
+
+// Tag limits: tags are short lowercase tokens such as "prod" or "team:payments"
+const (
+	maxTags      = 20
+	maxTagLength = 50
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]*$`)
+
+// validateDegradedHysteresis checks per-monitor "N of the last M checks" overrides.
+// A window of 0 disables hysteresis for the monitor even when it is enabled globally.
+func validateDegradedHysteresis(thresholdChecks, windowChecks *int) error {
+	if windowChecks != nil && (*windowChecks < 0 || *windowChecks > uptime.MaxDegradedWindowChecks) {
+		return newFieldError("degradedWindowChecks", "degradedWindowChecks must be between 0 and %d", uptime.MaxDegradedWindowChecks)
+	}
+	if thresholdChecks != nil && (*thresholdChecks < 1 || *thresholdChecks > uptime.MaxDegradedWindowChecks) {
+		return newFieldError("degradedThresholdChecks", "degradedThresholdChecks must be between 1 and %d", uptime.MaxDegradedWindowChecks)
+	}
+	if thresholdChecks != nil && windowChecks != nil && *windowChecks > 0 && *thresholdChecks > *windowChecks {
+		return newFieldError("degradedThresholdChecks", "degradedThresholdChecks cannot exceed degradedWindowChecks")
+	}
+	return nil
+}
+
+// maxDownBackoffInterval caps how far checks can be slowed down while a monitor is down.
+const maxDownBackoffInterval = 86400
+
+// validateDownBackoff checks the per-monitor down backoff against the regular check interval.
+func validateDownBackoff(intervalSeconds, afterChecks *int, checkInterval int) error {
+	if intervalSeconds != nil && (*intervalSeconds <= checkInterval || *intervalSeconds > maxDownBackoffInterval) {
+		return newFieldError("downBackoffInterval", "downBackoffInterval must be greater than the check interval (%d) and at most %d seconds", checkInterval, maxDownBackoffInterval)
+	}
+	if afterChecks != nil && (*afterChecks < 1 || *afterChecks > 1000) {
+		return newFieldError("downBackoffAfterChecks", "downBackoffAfterChecks must be between 1 and 1000")
+	}
+	return nil
+}
+
+// normalizeTags lowercases, validates and de-duplicates monitor tags.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) > maxTags {
+		return nil, newFieldError("tags", "at most %d tags are allowed", maxTags)
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		// SECURITY: Tags are stored comma-separated, so only a safe character set is accepted
+		if len(t) > maxTagLength || !tagPattern.MatchString(t) {
+			return nil, newFieldError("tags", "invalid tag %q: use up to %d characters of a-z, 0-9, _ . : / -", t, maxTagLength)
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// maxDependencies bounds how many monitors a single monitor can depend on.
+const maxDependencies = 20
+
+// validateDependencies checks that monitorID may depend on each of dependsOn: every
+// dependency must be another existing monitor, and no chain of existing dependencies may
+// lead back to monitorID.
+func validateDependencies(monitorID string, dependsOn []string, monitors []db.Monitor, existing map[string][]string) error {
+	if len(dependsOn) > maxDependencies {
+		return newFieldError("dependsOn", "at most %d dependencies are allowed", maxDependencies)
+	}
+	known := make(map[string]bool, len(monitors))
+	for _, m := range monitors {
+		known[m.ID] = true
+	}
+	for _, id := range dependsOn {
+		if id == monitorID {
+			return newFieldError("dependsOn", "a monitor cannot depend on itself")
+		}
+		if !known[id] {
+			return newFieldError("dependsOn", "dependency %q does not exist", id)
+		}
+	}
+
+	// The monitor's current edges are being replaced, so only follow the others
+	visited := make(map[string]bool)
+	stack := append([]string(nil), dependsOn...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == monitorID {
+			return newFieldError("dependsOn", "dependencies cannot form a cycle")
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, existing[id]...)
+	}
+	return nil
+}
+
+// maxCompositeMembers bounds how many monitors a composite monitor can combine.
+const maxCompositeMembers = 50
+
+// validateCompositeRule checks a composite monitor's members and threshold. Members must be
+// existing, non-composite monitors other than the composite itself (compositeID, "" when
+// creating), and minUp must be reachable.
+func validateCompositeRule(compositeID string, members []string, minUp int, monitors []db.Monitor) error {
+	if len(members) == 0 {
+		return newFieldError("members", "composite monitors need at least one member")
+	}
+	if len(members) > maxCompositeMembers {
+		return newFieldError("members", "at most %d members are allowed", maxCompositeMembers)
+	}
+	types := make(map[string]string, len(monitors))
+	for _, m := range monitors {
+		types[m.ID] = m.Type
+	}
+	unique := make(map[string]bool, len(members))
+	for _, id := range members {
+		t, ok := types[id]
+		switch {
+		case id == compositeID:
+			return newFieldError("members", "a composite monitor cannot be its own member")
+		case !ok:
+			return newFieldError("members", "member %q does not exist", id)
+		case t == db.MonitorTypeComposite:
+			return newFieldError("members", "member %q is a composite monitor; composites cannot be nested", id)
+		}
+		unique[id] = true
+	}
+	if minUp < 1 || minUp > len(unique) {
+		return newFieldError("minUp", "minUp must be between 1 and %d", len(unique))
+	}
+	return nil
+}
+
 // CreateGroup creates a new monitor group.
 // @Summary      Create group
 // @Tags         groups
@@ -62,30 +231,37 @@ const maxNameLength = 255
 // @Security     BearerAuth
 // @Param        body body object{name=string} true "Group payload"
 // @Success      201  {object} db.Group
-// @Failure      400  {string} string "Name is required"
-// @Failure      409  {object} object{error=string} "Group already exists"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
+// @Failure      409  {object} ErrorResponse "Concurrent create took the same ID"
 // @Router       /groups [post]
 func (h *CRUDHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Name string `json:"name"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+	errs := validationErrors{}
+	validateName(errs, req.Name)
+	if errs.write(w) {
 		return
 	}
 
-	// SECURITY: Validate name length
-	if len(req.Name) > maxNameLength {
-		http.Error(w, "Name too long (max 255 characters)", http.StatusBadRequest)
+	// IDs are fixed at creation (renames only change the name), so pick a free one
+	// now: "Ops" -> g-ops, then g-ops-2, g-ops-3, ...
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to load groups")
 		return
 	}
-
-	id := generateSlug(req.Name, "g-")
+	taken := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		taken[g.ID] = true
+	}
+	id := uniqueSlug(generateSlug(req.Name, "g-"), func(id string) bool { return taken[id] })
 
 	g := db.Group{
 		ID:   id,
@@ -93,12 +269,8 @@ func (h *CRUDHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.store.CreateGroup(g); err != nil {
-		// Handle Duplicate ID error
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key") {
-			writeError(w, http.StatusConflict, "Group with this name already exists (ID: "+id+")")
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// A conflict means we lost a race with a concurrent create
+		writeStoreError(w, err, "Failed to create group")
 		return
 	}
 
@@ -113,16 +285,16 @@ func (h *CRUDHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id   path string true "Group ID"
 // @Success      200  "OK"
-// @Failure      400  {string} string "ID required"
+// @Failure      400  {object} ErrorResponse "ID required"
 // @Router       /groups/{id} [delete]
 func (h *CRUDHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 	if err := h.store.DeleteGroup(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	h.manager.Sync()
@@ -138,12 +310,13 @@ func (h *CRUDHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 // @Param        id   path string true "Group ID"
 // @Param        body body object{name=string} true "New name"
 // @Success      200  {object} object{name=string}
-// @Failure      400  {string} string "Name is required"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
 // @Router       /groups/{id} [put]
 func (h *CRUDHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 
@@ -151,23 +324,18 @@ func (h *CRUDHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 		Name string `json:"name"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	// SECURITY: Validate name length
-	if len(req.Name) > maxNameLength {
-		http.Error(w, "Name too long (max 255 characters)", http.StatusBadRequest)
+	errs := validationErrors{}
+	validateName(errs, req.Name)
+	if errs.write(w) {
 		return
 	}
 
 	if err := h.store.UpdateGroup(id, req.Name); err != nil {
-		http.Error(w, "Failed to update group", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to update group")
 		return
 	}
 
@@ -181,11 +349,12 @@ func (h *CRUDHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        body body object{name=string,url=string,groupId=string,interval=int} true "Monitor payload"
+// @Param        body body object{name=string,url=string,groupId=string,interval=int,type=string,tags=[]string,members=[]string,minUp=int} true "Monitor payload (type: http|external|composite; members and minUp are required for composite)"
 // @Success      201  {object} db.Monitor
-// @Failure      400  {string} string "Validation error"
-// @Failure      404  {string} string "Group not found"
-// @Failure      409  {string} string "Monitor name already exists"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      404  {object} ErrorResponse "Group not found"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
+// @Failure      409  {object} duplicateURLResponse "Monitor name already exists, or the URL is already monitored (resend with allowDuplicateUrl=true to create it anyway)"
 // @Router       /monitors [post]
 func (h *CRUDHandler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -193,57 +362,75 @@ func (h *CRUDHandler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		URL                     string            `json:"url"`
 		GroupID                 string            `json:"groupId"`
 		Interval                int               `json:"interval"`
+		Type                    string            `json:"type"`
 		ConfirmationThreshold   *int              `json:"confirmationThreshold,omitempty"`
 		NotificationCooldownMin *int              `json:"notificationCooldownMinutes,omitempty"`
 		LatencyThreshold        *int              `json:"latencyThreshold,omitempty"`
 		RequestConfig           *db.RequestConfig `json:"requestConfig,omitempty"`
+		Tags                    []string          `json:"tags,omitempty"`
+		DegradedThresholdChecks *int              `json:"degradedThresholdChecks,omitempty"`
+		DegradedWindowChecks    *int              `json:"degradedWindowChecks,omitempty"`
+		DownBackoffInterval     *int              `json:"downBackoffInterval,omitempty"`
+		DownBackoffAfterChecks  *int              `json:"downBackoffAfterChecks,omitempty"`
+		AllowDuplicateURL       bool              `json:"allowDuplicateUrl,omitempty"`
+		Members                 []string          `json:"members,omitempty"` // Composite monitors only
+		MinUp                   int               `json:"minUp,omitempty"`   // Composite monitors only
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// 1. Basic Validation
-	if req.Name == "" || req.URL == "" || req.GroupID == "" {
-		http.Error(w, "Name, URL, and GroupID are required", http.StatusBadRequest)
-		return
+	errs := validationErrors{}
+	if req.Type == "" {
+		req.Type = db.MonitorTypeHTTP
+	}
+	if req.Type != db.MonitorTypeHTTP && req.Type != db.MonitorTypeExternal && req.Type != db.MonitorTypeComposite {
+		errs.add("type", "Type must be 'http', 'external' or 'composite'")
 	}
+	isComposite := req.Type == db.MonitorTypeComposite
+	// External and composite monitors are never scheduled
+	unscheduled := req.Type == db.MonitorTypeExternal || isComposite
 
-	// SECURITY: Validate name length
-	if len(req.Name) > maxNameLength {
-		http.Error(w, "Name too long (max 255 characters)", http.StatusBadRequest)
-		return
+	// 1. Basic Validation (external and composite monitors need no URL)
+	validateName(errs, req.Name)
+	if req.GroupID == "" {
+		errs.add("groupId", "GroupID is required")
 	}
 
 	// 2. Validate URL
-	parsedURL, err := url.ParseRequestURI(req.URL)
-	if err != nil {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
-		return
+	if req.URL != "" {
+		errs.addErr("url", validateMonitorURL(r.Context(), req.URL, h.manager.BlocksPrivateTargets()))
+	} else if !unscheduled {
+		errs.add("url", "URL is required")
 	}
 
-	// SECURITY: Only allow http and https protocols to prevent SSRF
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		http.Error(w, "Only HTTP and HTTPS URLs are allowed", http.StatusBadRequest)
-		return
+	// 3. Validate Interval (unused by monitors that are never scheduled)
+	if unscheduled && req.Interval == 0 {
+		req.Interval = 60
+	}
+	if req.Interval < minMonitorInterval {
+		errs.add("interval", "Interval must be at least %d seconds", minMonitorInterval)
 	}
 
-	// SECURITY: Validate URL length
-	if len(req.URL) > 2048 {
-		http.Error(w, "URL too long (max 2048 characters)", http.StatusBadRequest)
-		return
+	// 4. Validate per-monitor overrides
+	validateMonitorOverrides(errs, req.ConfirmationThreshold, req.NotificationCooldownMin, req.LatencyThreshold, req.RequestConfig)
+	errs.addErr("degradedWindowChecks", validateDegradedHysteresis(req.DegradedThresholdChecks, req.DegradedWindowChecks))
+	if _, ok := errs["interval"]; !ok {
+		errs.addErr("downBackoffInterval", validateDownBackoff(req.DownBackoffInterval, req.DownBackoffAfterChecks, req.Interval))
 	}
 
-	// 3. Validate Interval
-	if req.Interval < 10 {
-		http.Error(w, "Interval must be at least 10 seconds", http.StatusBadRequest)
+	// 5. Validate Tags
+	tags, err := normalizeTags(req.Tags)
+	errs.addErr("tags", err)
+	if errs.write(w) {
 		return
 	}
 
-	// 4. Validate Group Exists
+	// 6. Validate Group Exists
 	groups, err := h.store.GetGroups()
 	if err != nil {
-		http.Error(w, "System error checking groups", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "System error checking groups")
 		return
 	}
 	groupExists := false
@@ -254,39 +441,37 @@ func (h *CRUDHandler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if !groupExists {
-		http.Error(w, "Selected group does not exist", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeGroupNotFound, "Selected group does not exist")
 		return
 	}
 
-	// 5. Validate Duplicate Name (Simulate unique constraint)
+	// 7. Detect a second monitor against the same endpoint; the client can reuse the
+	// existing one or resend with allowDuplicateUrl to check it twice on purpose.
+	// Duplicate names are rejected by the store.
 	monitors, err := h.store.GetMonitors()
 	if err == nil {
-		for _, m := range monitors {
-			if strings.EqualFold(m.Name, req.Name) {
-				http.Error(w, "A monitor with this name already exists", http.StatusConflict)
+		if !unscheduled && !req.AllowDuplicateURL {
+			if dup := findDuplicateURL(monitors, req.URL); dup != nil {
+				writeJSON(w, http.StatusConflict, duplicateURLResponse{
+					ErrorResponse: errorBody(w, ErrCodeDuplicateURL, fmt.Sprintf("Monitor %q already checks this URL", dup.Name)),
+					DuplicateOf: duplicateMonitorDTO{
+						ID:      dup.ID,
+						Name:    dup.Name,
+						GroupID: dup.GroupID,
+						URL:     dup.URL,
+					},
+				})
 				return
 			}
 		}
 	}
 
-	// 6. Validate per-monitor overrides
-	if req.ConfirmationThreshold != nil && (*req.ConfirmationThreshold < 1 || *req.ConfirmationThreshold > 100) {
-		http.Error(w, "confirmationThreshold must be between 1 and 100", http.StatusBadRequest)
-		return
-	}
-	if req.NotificationCooldownMin != nil && (*req.NotificationCooldownMin < 0 || *req.NotificationCooldownMin > 1440) {
-		http.Error(w, "notificationCooldownMinutes must be between 0 and 1440", http.StatusBadRequest)
-		return
-	}
-	if req.LatencyThreshold != nil && *req.LatencyThreshold < 1 {
-		http.Error(w, "latencyThreshold must be at least 1", http.StatusBadRequest)
-		return
-	}
-
-	// 7. Validate RequestConfig
-	if err := validateRequestConfig(req.RequestConfig); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	// 8. Validate composite members
+	if isComposite {
+		errs.addErr("members", validateCompositeRule("", req.Members, req.MinUp, monitors))
+		if errs.write(w) {
+			return
+		}
 	}
 
 	id := generateID(req.Name, "m-")
@@ -296,27 +481,39 @@ func (h *CRUDHandler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		GroupID:                 req.GroupID,
 		Name:                    req.Name,
 		URL:                     req.URL,
+		Type:                    req.Type,
 		Active:                  true,
 		Interval:                req.Interval,
 		ConfirmationThreshold:   req.ConfirmationThreshold,
 		NotificationCooldownMin: req.NotificationCooldownMin,
 		LatencyThreshold:        req.LatencyThreshold,
 		RequestConfig:           req.RequestConfig,
+		Tags:                    tags,
+		DegradedThresholdChecks: req.DegradedThresholdChecks,
+		DegradedWindowChecks:    req.DegradedWindowChecks,
+		DownBackoffInterval:     req.DownBackoffInterval,
+		DownBackoffAfterChecks:  req.DownBackoffAfterChecks,
 	}
 
 	if err := h.store.CreateMonitor(m); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to create monitor")
 		return
 	}
+	if isComposite {
+		if err := h.store.SetCompositeRule(db.CompositeRule{MonitorID: id, MinUp: req.MinUp, Members: req.Members}); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
 
 	// Notify Engine to start monitoring this new URL immediately
 	h.manager.Sync()
 
 	// Wait for the first ping results (max 5 seconds) to ensure "Wow effect" in UI
 	// This ensures that when the frontend fetches the list immediately after this returns,
-	// the first check is likely already done.
+	// the first check is likely already done. Unscheduled monitors have no checks to wait for.
 	deadline := time.Now().Add(5 * time.Second)
-	for time.Now().Before(deadline) {
+	for !unscheduled && time.Now().Before(deadline) {
 		mon := h.manager.GetMonitor(id)
 		if mon != nil && len(mon.GetHistory()) > 0 {
 			break
@@ -331,7 +528,7 @@ func (h *CRUDHandler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 func (h *CRUDHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
 	groups, err := h.store.GetGroups()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	_ = json.NewEncoder(w).Encode(groups)
@@ -344,14 +541,18 @@ func (h *CRUDHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id   path string true "Monitor ID"
-// @Param        body body object{name=string,url=string,interval=int} true "Fields to update"
+// @Param        body body object{name=string,url=string,interval=int,tags=[]string,dependsOn=[]string,members=[]string,minUp=int,shadowChecks=int} true "Fields to update (tags, dependsOn, members and minUp are left unchanged when omitted). With shadowChecks, a changed URL is first checked in shadow mode for that many checks instead of being applied."
 // @Success      200  "OK"
-// @Failure      400  {string} string "ID required"
+// @Success      202  {object} shadowReport "URL change started as shadow checks"
+// @Failure      400  {object} ErrorResponse "ID required"
+// @Failure      404  {object} ErrorResponse "Monitor not found"
+// @Failure      409  {object} ErrorResponse "Another monitor has this name"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
 // @Router       /monitors/{id} [put]
 func (h *CRUDHandler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 
@@ -363,37 +564,216 @@ func (h *CRUDHandler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
 		NotificationCooldownMin *int              `json:"notificationCooldownMinutes,omitempty"`
 		LatencyThreshold        *int              `json:"latencyThreshold,omitempty"`
 		RequestConfig           *db.RequestConfig `json:"requestConfig,omitempty"`
+		Tags                    *[]string         `json:"tags,omitempty"`
+		DegradedThresholdChecks *int              `json:"degradedThresholdChecks,omitempty"`
+		DegradedWindowChecks    *int              `json:"degradedWindowChecks,omitempty"`
+		DownBackoffInterval     *int              `json:"downBackoffInterval,omitempty"`
+		DownBackoffAfterChecks  *int              `json:"downBackoffAfterChecks,omitempty"`
+		DependsOn               *[]string         `json:"dependsOn,omitempty"`
+		Members                 *[]string         `json:"members,omitempty"` // Composite monitors only
+		MinUp                   *int              `json:"minUp,omitempty"`   // Composite monitors only
+		ShadowChecks            *int              `json:"shadowChecks,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Validate per-monitor overrides
-	if req.ConfirmationThreshold != nil && (*req.ConfirmationThreshold < 1 || *req.ConfirmationThreshold > 100) {
-		http.Error(w, "confirmationThreshold must be between 1 and 100", http.StatusBadRequest)
-		return
+	// Omitted hysteresis and down backoff fields keep their stored values, like tags and
+	// dependsOn; the dashboard's edit form doesn't send them
+	partialHysteresis := (req.DegradedThresholdChecks == nil) != (req.DegradedWindowChecks == nil)
+	partialBackoff := (req.DownBackoffInterval == nil) != (req.DownBackoffAfterChecks == nil)
+	if partialHysteresis || partialBackoff {
+		mon, err := h.store.GetMonitor(id)
+		if err != nil {
+			if errors.Is(err, db.ErrMonitorNotFound) {
+				writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if req.DegradedThresholdChecks == nil {
+			req.DegradedThresholdChecks = mon.DegradedThresholdChecks
+		}
+		if req.DegradedWindowChecks == nil {
+			req.DegradedWindowChecks = mon.DegradedWindowChecks
+		}
+		if req.DownBackoffInterval == nil {
+			req.DownBackoffInterval = mon.DownBackoffInterval
+		}
+		if req.DownBackoffAfterChecks == nil {
+			req.DownBackoffAfterChecks = mon.DownBackoffAfterChecks
+		}
 	}
-	if req.NotificationCooldownMin != nil && (*req.NotificationCooldownMin < 0 || *req.NotificationCooldownMin > 1440) {
-		http.Error(w, "notificationCooldownMinutes must be between 0 and 1440", http.StatusBadRequest)
-		return
+
+	errs := validationErrors{}
+	// SECURITY: Validate name length
+	if len(req.Name) > maxNameLength {
+		errs.add("name", "Name too long (max %d characters)", maxNameLength)
 	}
-	if req.LatencyThreshold != nil && *req.LatencyThreshold < 1 {
-		http.Error(w, "latencyThreshold must be at least 1", http.StatusBadRequest)
-		return
+	if req.URL != "" {
+		errs.addErr("url", validateMonitorURL(r.Context(), req.URL, h.manager.BlocksPrivateTargets()))
+	}
+	if req.Interval != 0 && req.Interval < minMonitorInterval {
+		errs.add("interval", "Interval must be at least %d seconds", minMonitorInterval)
 	}
+	validateMonitorOverrides(errs, req.ConfirmationThreshold, req.NotificationCooldownMin, req.LatencyThreshold, req.RequestConfig)
+	errs.addErr("degradedWindowChecks", validateDegradedHysteresis(req.DegradedThresholdChecks, req.DegradedWindowChecks))
 
-	if err := validateRequestConfig(req.RequestConfig); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	checkInterval := req.Interval
+	if checkInterval < 1 {
+		checkInterval = 60 // UpdateMonitor applies the same default
+	}
+	errs.addErr("downBackoffInterval", validateDownBackoff(req.DownBackoffInterval, req.DownBackoffAfterChecks, checkInterval))
+
+	var tags []string
+	if req.Tags != nil {
+		var err error
+		tags, err = normalizeTags(*req.Tags)
+		errs.addErr("tags", err)
+	}
+	var shadowChecks int
+	if req.ShadowChecks != nil {
+		var err error
+		shadowChecks, err = validateShadowChecks("shadowChecks", req.ShadowChecks)
+		errs.addErr("shadowChecks", err)
+	}
+	if errs.write(w) {
 		return
 	}
 
+	// Shadow mode: the monitor keeps its current URL while the new one is checked alongside
+	// it, to be promoted once the comparison looks right
+	var shadowMon *db.Monitor
+	var shadowURL string
+	if req.ShadowChecks != nil {
+		mon, err := h.store.GetMonitor(id)
+		if err != nil {
+			if errors.Is(err, db.ErrMonitorNotFound) {
+				writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if req.URL != "" && req.URL != mon.URL {
+			if mon.Type != db.MonitorTypeHTTP {
+				writeError(w, http.StatusBadRequest, "shadow checks are only available for HTTP monitors")
+				return
+			}
+			shadowMon, shadowURL = mon, req.URL
+			req.URL = mon.URL
+		}
+	}
+
+	if req.DependsOn != nil {
+		monitors, err := h.store.GetMonitors()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		existing, err := h.store.GetMonitorDependencies()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		errs.addErr("dependsOn", validateDependencies(id, *req.DependsOn, monitors, existing))
+		if errs.write(w) {
+			return
+		}
+	}
+
+	var compositeRule *db.CompositeRule
+	if req.Members != nil || req.MinUp != nil {
+		monitors, err := h.store.GetMonitors()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		isComposite := false
+		for _, m := range monitors {
+			if m.ID == id {
+				isComposite = m.Type == db.MonitorTypeComposite
+				break
+			}
+		}
+		if !isComposite {
+			errs.add("members", "members and minUp only apply to composite monitors")
+			errs.write(w)
+			return
+		}
+		rules, err := h.store.GetCompositeRules()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rule := rules[id]
+		rule.MonitorID = id
+		if req.Members != nil {
+			rule.Members = *req.Members
+		}
+		if req.MinUp != nil {
+			rule.MinUp = *req.MinUp
+		}
+		errs.addErr("members", validateCompositeRule(id, rule.Members, rule.MinUp, monitors))
+		if errs.write(w) {
+			return
+		}
+		compositeRule = &rule
+	}
+
 	if err := h.store.UpdateMonitor(id, req.Name, req.URL, req.Interval, req.ConfirmationThreshold, req.NotificationCooldownMin, req.LatencyThreshold, req.RequestConfig); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeStoreError(w, err, "Failed to update monitor")
 		return
 	}
+	if req.DegradedThresholdChecks != nil || req.DegradedWindowChecks != nil {
+		if err := h.store.SetMonitorDegradedHysteresis(id, req.DegradedThresholdChecks, req.DegradedWindowChecks); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if req.DownBackoffInterval != nil || req.DownBackoffAfterChecks != nil {
+		if err := h.store.SetMonitorDownBackoff(id, req.DownBackoffInterval, req.DownBackoffAfterChecks); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if req.Tags != nil {
+		if err := h.store.SetMonitorTags(id, tags); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if req.DependsOn != nil {
+		if err := h.store.SetMonitorDependencies(id, *req.DependsOn); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if compositeRule != nil {
+		if err := h.store.SetCompositeRule(*compositeRule); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if shadowMon != nil {
+		if status, err := h.startShadow(r, shadowMon, shadowURL, shadowChecks); err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+	}
 
 	h.manager.Sync()
+	if shadowMon != nil {
+		report, err := h.shadowReport(shadowMon)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusAccepted, report)
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -404,16 +784,16 @@ func (h *CRUDHandler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id   path string true "Monitor ID"
 // @Success      200  "OK"
-// @Failure      400  {string} string "ID required"
+// @Failure      400  {object} ErrorResponse "ID required"
 // @Router       /monitors/{id} [delete]
 func (h *CRUDHandler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 	if err := h.store.DeleteMonitor(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	h.manager.RemoveMonitor(id)
@@ -428,8 +808,8 @@ func (h *CRUDHandler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id   path string true "Monitor ID"
 // @Success      200  {object} object{message=string,active=bool}
-// @Failure      400  {object} object{error=string} "ID required"
-// @Failure      404  {object} object{error=string} "Monitor not found"
+// @Failure      400  {object} ErrorResponse "ID required"
+// @Failure      404  {object} ErrorResponse "Monitor not found"
 // @Router       /monitors/{id}/pause [post]
 func (h *CRUDHandler) PauseMonitor(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -440,7 +820,7 @@ func (h *CRUDHandler) PauseMonitor(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.store.SetMonitorActive(id, false); err != nil {
 		if errors.Is(err, db.ErrMonitorNotFound) {
-			writeError(w, http.StatusNotFound, "monitor not found")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
 			return
 		}
 		writeError(w, http.StatusInternalServerError, "failed to pause monitor")
@@ -458,8 +838,8 @@ func (h *CRUDHandler) PauseMonitor(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id   path string true "Monitor ID"
 // @Success      200  {object} object{message=string,active=bool}
-// @Failure      400  {object} object{error=string} "ID required"
-// @Failure      404  {object} object{error=string} "Monitor not found"
+// @Failure      400  {object} ErrorResponse "ID required"
+// @Failure      404  {object} ErrorResponse "Monitor not found"
 // @Router       /monitors/{id}/resume [post]
 func (h *CRUDHandler) ResumeMonitor(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -470,7 +850,7 @@ func (h *CRUDHandler) ResumeMonitor(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.store.SetMonitorActive(id, true); err != nil {
 		if errors.Is(err, db.ErrMonitorNotFound) {
-			writeError(w, http.StatusNotFound, "monitor not found")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
 			return
 		}
 		writeError(w, http.StatusInternalServerError, "failed to resume monitor")
@@ -481,7 +861,260 @@ func (h *CRUDHandler) ResumeMonitor(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"message": "monitor resumed", "active": true})
 }
 
+// maxBulkMonitors caps how many monitors a single bulk request may change.
+const maxBulkMonitors = 1000
+
+type bulkMonitorsRequest struct {
+	Action   string   `json:"action"`   // pause, resume, delete, set_interval, add_tag
+	Monitors []string `json:"monitors"` // Target monitor IDs
+	Groups   []string `json:"groups"`   // Target group IDs
+	Tags     []string `json:"tags"`     // Target monitors carrying any of these tags
+	Interval int      `json:"interval"` // For set_interval (seconds)
+	Tag      string   `json:"tag"`      // For add_tag
+}
+
+// BulkMonitorsResponse lists the monitors a bulk action was applied to.
+type BulkMonitorsResponse struct {
+	Action     string   `json:"action"`
+	MonitorIDs []string `json:"monitorIds"`
+}
+
+// BulkMonitors applies one action to many monitors selected by ID, group or tag.
+// All changes are made in a single transaction.
+// @Summary      Bulk monitor operations
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{action=string,monitors=[]string,groups=[]string,tags=[]string,interval=int,tag=string} true "Action (pause, resume, delete, set_interval, add_tag) and targets (at least one of monitors, groups or tags)"
+// @Success      200  {object} BulkMonitorsResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "No monitors match the targets"
+// @Router       /monitors/bulk [post]
+func (h *CRUDHandler) BulkMonitors(w http.ResponseWriter, r *http.Request) {
+	var req bulkMonitorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	action := db.BulkMonitorAction{Action: req.Action}
+	switch req.Action {
+	case db.BulkActionPause, db.BulkActionResume, db.BulkActionDelete:
+	case db.BulkActionSetInterval:
+		if req.Interval < 10 {
+			writeError(w, http.StatusBadRequest, "interval must be at least 10 seconds")
+			return
+		}
+		action.Interval = req.Interval
+	case db.BulkActionAddTag:
+		tags, err := normalizeTags([]string{req.Tag})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(tags) == 0 {
+			writeError(w, http.StatusBadRequest, "tag is required")
+			return
+		}
+		action.Tag = tags[0]
+	default:
+		writeError(w, http.StatusBadRequest, "action must be one of pause, resume, delete, set_interval, add_tag")
+		return
+	}
+
+	// SECURITY: Require explicit targets so a malformed request cannot delete or pause every monitor
+	if len(req.Monitors) == 0 && len(req.Groups) == 0 && len(req.Tags) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one of monitors, groups or tags is required")
+		return
+	}
+	filterTags, err := normalizeTags(req.Tags)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+
+	ids := toSet(req.Monitors)
+	groups := toSet(req.Groups)
+	resp := BulkMonitorsResponse{Action: req.Action, MonitorIDs: []string{}}
+	for _, m := range monitors {
+		if !ids[m.ID] && !groups[m.GroupID] && !hasAnyTag(m, filterTags) {
+			continue
+		}
+		if action.Action == db.BulkActionAddTag && len(m.Tags) >= maxTags && !m.HasTag(action.Tag) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("monitor %s already has %d tags", m.ID, maxTags))
+			return
+		}
+		resp.MonitorIDs = append(resp.MonitorIDs, m.ID)
+		delete(ids, m.ID)
+	}
+	if len(ids) > 0 {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found: "+strings.Join(sortedKeys(ids), ", "))
+		return
+	}
+	if len(resp.MonitorIDs) == 0 {
+		writeError(w, http.StatusNotFound, "no monitors match the targets")
+		return
+	}
+	if len(resp.MonitorIDs) > maxBulkMonitors {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d monitors can be changed at once", maxBulkMonitors))
+		return
+	}
+
+	if err := h.store.BulkUpdateMonitors(resp.MonitorIDs, action); err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			// Deleted concurrently; the transaction was rolled back
+			writeError(w, http.StatusConflict, "monitors changed during the request, nothing was applied")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to apply bulk action")
+		return
+	}
+
+	if action.Action == db.BulkActionDelete {
+		for _, id := range resp.MonitorIDs {
+			h.manager.RemoveMonitor(id)
+		}
+	}
+	h.manager.Sync()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// duplicateURLResponse is returned when a new monitor targets an already-monitored endpoint.
+type duplicateURLResponse struct {
+	ErrorResponse
+	DuplicateOf duplicateMonitorDTO `json:"duplicateOf"`
+}
+
+type duplicateMonitorDTO struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	GroupID string `json:"groupId"`
+	URL     string `json:"url"`
+}
+
+// findDuplicateURL returns the HTTP monitor whose normalized URL matches target, if any.
+func findDuplicateURL(monitors []db.Monitor, target string) *db.Monitor {
+	want := normalizeMonitorURL(target)
+	for i := range monitors {
+		m := &monitors[i]
+		if m.Type == db.MonitorTypeExternal || m.URL == "" {
+			continue
+		}
+		if normalizeMonitorURL(m.URL) == want {
+			return m
+		}
+	}
+	return nil
+}
+
+// normalizeMonitorURL reduces a URL to a comparable form: case-insensitive scheme and host,
+// default ports, trailing slashes, fragments and query parameter order don't make two
+// targets different.
+func normalizeMonitorURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+const maxMonitorURLLength = 2048
+
+// lookupHost resolves monitor hostnames during validation; replaced in tests.
+var lookupHost = net.DefaultResolver.LookupIPAddr
+
+// validateMonitorURL checks a monitor target before it is stored. With blockPrivate set it also
+// rejects hosts that are, or currently resolve to, private network addresses. This only gives
+// early feedback: workers re-check the address they actually connect to, so a later DNS change
+// can't slip past.
+func validateMonitorURL(ctx context.Context, raw string, blockPrivate bool) error {
+	// SECURITY: Validate URL length
+	if len(raw) > maxMonitorURLLength {
+		return fmt.Errorf("URL too long (max %d characters)", maxMonitorURLLength)
+	}
+	parsedURL, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return errors.New("Invalid URL format")
+	}
+	// SECURITY: Only allow http and https protocols to prevent SSRF
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return errors.New("Only HTTP and HTTPS URLs are allowed")
+	}
+	host := parsedURL.Hostname()
+	if host == "" {
+		return errors.New("URL must include a host")
+	}
+	// SECURITY: Hostnames can't start with a hyphen; one would read as an option to
+	// the traceroute run during outages
+	if strings.HasPrefix(host, "-") {
+		return errors.New("Invalid URL host")
+	}
+	if !blockPrivate {
+		return nil
+	}
+
+	// SECURITY: Keep shared instances from being used to probe their own network
+	if ip := net.ParseIP(host); ip != nil {
+		if uptime.IsPrivateTarget(ip) {
+			return errors.New("URL points to a private network address, which this server does not allow")
+		}
+		return nil
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return errors.New("URL points to a private network address, which this server does not allow")
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	addrs, err := lookupHost(lookupCtx, host)
+	if err != nil {
+		// Unresolvable hosts are allowed; the check reports them as down
+		return nil
+	}
+	for _, addr := range addrs {
+		if uptime.IsPrivateTarget(addr.IP) {
+			return errors.New("URL resolves to a private network address, which this server does not allow")
+		}
+	}
+	return nil
+}
+
 var validMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "DELETE": true}
+var headerNameRe = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
 var acceptedCodesRe = regexp.MustCompile(`^[1-5][0-9]{2}(-[1-5][0-9]{2})?(,[1-5][0-9]{2}(-[1-5][0-9]{2})?)*$`)
 
 func validateRequestConfig(cfg *db.RequestConfig) error {
@@ -511,5 +1144,16 @@ func validateRequestConfig(cfg *db.RequestConfig) error {
 	if cfg.RetryCount < 0 || cfg.RetryCount > 5 {
 		return fmt.Errorf("retryCount must be between 0 and 5")
 	}
+	if len(cfg.ExpectedHeaders) > 20 {
+		return fmt.Errorf("maximum 20 expected headers allowed")
+	}
+	for _, a := range cfg.ExpectedHeaders {
+		if !headerNameRe.MatchString(a.Name) {
+			return fmt.Errorf("expected header names must be valid HTTP header names")
+		}
+		if len(a.Name) > 256 || len(a.Value) > 1024 {
+			return fmt.Errorf("expected header name max 256 chars, value max 1024 chars")
+		}
+	}
 	return nil
 }
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
@@ -26,9 +29,13 @@ type IncidentDTO struct {
 	GroupID     string     `json:"groupId"`
 	Type        string     `json:"type"` // down, degraded, ssl_expiring
 	Message     string     `json:"message"`
+	ErrorKind   string     `json:"errorKind,omitempty"` // dns, timeout, tls, http_5xx, ...
 	StartedAt   time.Time  `json:"startedAt"`
 	ResolvedAt  *time.Time `json:"resolvedAt"` // Null if active
 	Duration    string     `json:"duration"`
+	// EstimatedCost is the business impact of the outage so far, for monitors with a
+	// downtime cost
+	EstimatedCost *float64 `json:"estimatedCost,omitempty"`
 }
 
 type SSLWarningDTO struct {
@@ -42,13 +49,55 @@ type SSLWarningDTO struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
-// GetSystemEvents returns active outages, recent history, and SSL warnings.
+// AnnotationMonitorDTO is a monitor an annotation is attached to.
+type AnnotationMonitorDTO struct {
+	MonitorID   string `json:"monitorId"`
+	MonitorName string `json:"monitorName"`
+	GroupID     string `json:"groupId"`
+	GroupName   string `json:"groupName"`
+}
+
+// AnnotationEventDTO is one annotation on the timeline; fleet-wide annotations list every affected monitor.
+type AnnotationEventDTO struct {
+	ID        string                 `json:"id"`
+	Kind      string                 `json:"kind"` // deploy, config, other
+	Message   string                 `json:"message"`
+	Timestamp time.Time              `json:"timestamp"`
+	Monitors  []AnnotationMonitorDTO `json:"monitors"`
+}
+
+// groupAnnotationEvents collapses the per-monitor rows of each fleet-wide annotation into one entry.
+func groupAnnotationEvents(events []db.AnnotationEvent) []AnnotationEventDTO {
+	out := []AnnotationEventDTO{}
+	index := make(map[string]int)
+	for _, e := range events {
+		key := e.BatchID
+		if key == "" {
+			key = fmt.Sprintf("%d", e.ID)
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, AnnotationEventDTO{ID: key, Kind: e.Kind, Message: e.Message, Timestamp: e.Timestamp})
+		}
+		out[i].Monitors = append(out[i].Monitors, AnnotationMonitorDTO{
+			MonitorID:   e.MonitorID,
+			MonitorName: e.MonitorName,
+			GroupID:     e.GroupID,
+			GroupName:   e.GroupName,
+		})
+	}
+	return out
+}
+
+// GetSystemEvents returns active outages, recent history, SSL warnings, and annotations.
 // @Summary      Get system events
 // @Tags         events
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {object} object{active=[]IncidentDTO,history=[]IncidentDTO,sslWarnings=[]SSLWarningDTO}
-// @Failure      500  {object} object{error=string}
+// @Success      200  {object} object{active=[]IncidentDTO,history=[]IncidentDTO,sslWarnings=[]SSLWarningDTO,annotations=[]AnnotationEventDTO}
+// @Failure      500  {object} ErrorResponse
 // @Router       /events [get]
 func (h *EventHandler) GetSystemEvents(w http.ResponseWriter, r *http.Request) {
 	activeOutages, err := h.store.GetActiveOutages()
@@ -64,18 +113,24 @@ func (h *EventHandler) GetSystemEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	now := time.Now()
+	estimateOutageCosts(h.store, activeOutages, now)
+	estimateOutageCosts(h.store, resolvedOutages, now)
+
 	var active []IncidentDTO
 	for _, o := range activeOutages {
 		active = append(active, IncidentDTO{
-			ID:          fmt.Sprintf("%d", o.ID),
-			MonitorID:   o.MonitorID,
-			MonitorName: o.MonitorName,
-			GroupName:   o.GroupName,
-			GroupID:     o.GroupID,
-			Type:        o.Type,
-			Message:     o.Summary,
-			StartedAt:   o.StartTime,
-			Duration:    formatDuration(time.Since(o.StartTime)),
+			ID:            fmt.Sprintf("%d", o.ID),
+			MonitorID:     o.MonitorID,
+			MonitorName:   o.MonitorName,
+			GroupName:     o.GroupName,
+			GroupID:       o.GroupID,
+			Type:          o.Type,
+			Message:       o.Summary,
+			ErrorKind:     o.ErrorKind,
+			StartedAt:     o.StartTime,
+			Duration:      formatDuration(now.Sub(o.StartTime)),
+			EstimatedCost: o.EstimatedCost,
 		})
 	}
 
@@ -86,16 +141,18 @@ func (h *EventHandler) GetSystemEvents(w http.ResponseWriter, r *http.Request) {
 			dur = formatDuration(o.EndTime.Sub(o.StartTime))
 		}
 		history = append(history, IncidentDTO{
-			ID:          fmt.Sprintf("%d", o.ID),
-			MonitorID:   o.MonitorID,
-			MonitorName: o.MonitorName,
-			GroupName:   o.GroupName,
-			GroupID:     o.GroupID,
-			Type:        o.Type,
-			Message:     o.Summary,
-			StartedAt:   o.StartTime,
-			ResolvedAt:  o.EndTime,
-			Duration:    dur,
+			ID:            fmt.Sprintf("%d", o.ID),
+			MonitorID:     o.MonitorID,
+			MonitorName:   o.MonitorName,
+			GroupName:     o.GroupName,
+			GroupID:       o.GroupID,
+			Type:          o.Type,
+			Message:       o.Summary,
+			ErrorKind:     o.ErrorKind,
+			StartedAt:     o.StartTime,
+			ResolvedAt:    o.EndTime,
+			Duration:      dur,
+			EstimatedCost: o.EstimatedCost,
 		})
 	}
 
@@ -120,6 +177,12 @@ func (h *EventHandler) GetSystemEvents(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	annotationEvents, err := h.store.GetAnnotationEvents(since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch annotations")
+		return
+	}
+
 	// Returns empty arrays if nil
 	if active == nil {
 		active = []IncidentDTO{}
@@ -135,9 +198,96 @@ func (h *EventHandler) GetSystemEvents(w http.ResponseWriter, r *http.Request) {
 		"active":      active,
 		"history":     history,
 		"sslWarnings": sslWarnings,
+		"annotations": groupAnnotationEvents(annotationEvents),
 	})
 }
 
+// EventLogResponse is one page of the monitor event log.
+type EventLogResponse struct {
+	Events []db.SystemEvent `json:"events"`
+	// NextBeforeID is set on a full page: pass it as before_id to get the next one
+	NextBeforeID *int64 `json:"nextBeforeId,omitempty"`
+}
+
+// GetEventLog pages through the events of all monitors, newest first.
+// @Summary      Get event log
+// @Tags         events
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit      query int    false "Events per page (default 100, max 1000)"
+// @Param        before_id  query int    false "Return events after this one (nextBeforeId of the previous page)"
+// @Param        monitor_id query string false "Only events of this monitor"
+// @Param        type       query string false "Comma-separated event types, e.g. down,up"
+// @Param        since      query string false "Only events at or after this time (RFC 3339)"
+// @Param        until      query string false "Only events before this time (RFC 3339)"
+// @Success      200  {object} EventLogResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /events/log [get]
+func (h *EventHandler) GetEventLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := db.SystemEventFilter{MonitorID: q.Get("monitor_id"), Limit: db.DefaultSystemEventsLimit}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > db.MaxSystemEventsLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", db.MaxSystemEventsLimit))
+			return
+		}
+		f.Limit = n
+	}
+	if v := q.Get("before_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 1 {
+			writeError(w, http.StatusBadRequest, "invalid before_id")
+			return
+		}
+		f.BeforeID = id
+	}
+	for _, t := range strings.Split(q.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			f.Types = append(f.Types, t)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, p.name+" must be an RFC 3339 time")
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		writeError(w, http.StatusBadRequest, "since must be before until")
+		return
+	}
+
+	events, err := h.store.GetSystemEvents(f)
+	if errors.Is(err, db.ErrEventNotFound) {
+		writeError(w, http.StatusBadRequest, "before_id does not match an event")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch events")
+		return
+	}
+
+	resp := EventLogResponse{Events: events}
+	if resp.Events == nil {
+		resp.Events = []db.SystemEvent{}
+	}
+	if len(events) == f.Limit {
+		next := events[len(events)-1].ID
+		resp.NextBeforeID = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Minute)
 	h := d / time.Hour
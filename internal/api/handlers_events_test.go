@@ -2,9 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/projecthelena/warden/internal/db"
 	"github.com/projecthelena/warden/internal/uptime"
@@ -212,3 +214,54 @@ func TestGetSystemEvents_MultipleSSLWarnings(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEventLog(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewEventHandler(s, uptime.NewManager(s))
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "https://api.example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	base := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	for i, eventType := range []string{"down", "up", "down"} {
+		if err := s.CreateEventAt("m1", eventType, eventType, base.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("CreateEventAt failed: %v", err)
+		}
+	}
+
+	get := func(query string) (int, EventLogResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.GetEventLog(w, httptest.NewRequest("GET", "/api/events/log"+query, nil))
+		var resp EventLogResponse
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	code, page := get("?limit=2")
+	if code != http.StatusOK || len(page.Events) != 2 || page.NextBeforeID == nil {
+		t.Fatalf("Expected a full first page with a cursor, got %d %+v", code, page)
+	}
+	code, page = get(fmt.Sprintf("?limit=2&before_id=%d", *page.NextBeforeID))
+	if code != http.StatusOK || len(page.Events) != 1 || !page.Events[0].Timestamp.Equal(base) || page.NextBeforeID != nil {
+		t.Fatalf("Expected the oldest event on the last page, got %d %+v", code, page)
+	}
+
+	_, page = get("?monitor_id=m1&type=down&since=2026-01-02T15:01:00Z")
+	if len(page.Events) != 1 || page.Events[0].Type != "down" || !page.Events[0].Timestamp.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("Expected the filtered down event, got %+v", page.Events)
+	}
+	_, page = get("?monitor_id=other")
+	if page.Events == nil || len(page.Events) != 0 {
+		t.Errorf("Expected an empty list for another monitor, got %+v", page.Events)
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=1001", "?before_id=x", "?before_id=999", "?since=yesterday", "?since=2026-01-02T16:00:00Z&until=2026-01-02T15:00:00Z"} {
+		if code, _ := get(query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, code)
+		}
+	}
+}
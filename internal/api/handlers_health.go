@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
 )
 
 const pingTimeout = 5 * time.Second
@@ -18,8 +19,10 @@ func Healthz(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Readyz is the readiness probe — confirms the app can serve traffic.
-func Readyz(store *db.Store) http.HandlerFunc {
+// Readyz is the readiness probe — confirms the app can serve traffic: the database is
+// reachable and the manager has loaded every monitor, so statuses are not reported as
+// down while it warms up after a restart.
+func Readyz(store *db.Store, manager *uptime.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
 		defer cancel()
@@ -31,6 +34,13 @@ func Readyz(store *db.Store) http.HandlerFunc {
 			})
 			return
 		}
+		if !manager.Hydrated() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+				"status": "warming_up",
+				"error":  "monitors are still loading",
+			})
+			return
+		}
 		writeJSON(w, http.StatusOK, map[string]any{
 			"status":    "ok",
 			"timestamp": time.Now().UTC(),
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -36,8 +37,10 @@ func TestReadyz(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to create store: %v", err)
 	}
+	manager := uptime.NewManager(store)
+	manager.Sync()
 
-	handler := Readyz(store)
+	handler := Readyz(store, manager)
 
 	req := httptest.NewRequest("GET", "/readyz", nil)
 	w := httptest.NewRecorder()
@@ -57,6 +60,35 @@ func TestReadyz(t *testing.T) {
 	}
 }
 
+func TestReadyz_WarmingUp(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	manager := uptime.NewManager(store)
+	handler := Readyz(store, manager)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the first sync, got %d", w.Code)
+	}
+	var resp map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["status"] != "warming_up" {
+		t.Errorf("expected status warming_up, got %v", resp["status"])
+	}
+
+	manager.Sync()
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after the first sync, got %d", w.Code)
+	}
+}
+
 func TestReadyz_DBDown(t *testing.T) {
 	store, err := db.NewStore(db.NewTestConfig())
 	if err != nil {
@@ -67,7 +99,7 @@ func TestReadyz_DBDown(t *testing.T) {
 		t.Fatalf("failed to close store: %v", err)
 	}
 
-	handler := Readyz(store)
+	handler := Readyz(store, uptime.NewManager(store))
 
 	req := httptest.NewRequest("GET", "/readyz", nil)
 	w := httptest.NewRecorder()
@@ -96,6 +128,7 @@ func TestHealthProbes_Integration(t *testing.T) {
 		t.Fatalf("failed to create store: %v", err)
 	}
 	manager := uptime.NewManager(store)
+	manager.Sync()
 	cfg := config.Default()
 	router := NewRouter(manager, store, &cfg)
 
@@ -142,3 +175,41 @@ func TestHealthProbes_Integration(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_CompressesResponses(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "compress-test.db")
+	store, err := db.NewStore(db.NewTestConfigWithPath(dbPath))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	manager := uptime.NewManager(store)
+	cfg := config.Default()
+	router := NewRouter(manager, store, &cfg)
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected Content-Encoding gzip, got %q", got)
+	}
+	zr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("failed to open gzip body: %v", err)
+	}
+	var body map[string]any
+	if err := json.NewDecoder(zr).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["status"] != "ok" {
+		t.Errorf("expected status ok, got %v", body["status"])
+	}
+
+	// Without Accept-Encoding the response stays plain
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	if got := rr.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("expected no Content-Encoding, got %q", got)
+	}
+}
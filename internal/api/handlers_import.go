@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/openapi"
+)
+
+// OpenAPI import limits.
+const (
+	maxOpenAPIDocumentBytes = 5 << 20
+	maxImportOperations     = 100
+)
+
+// ImportOpenAPIResponse lists the monitors an import created, and the selected
+// operations it left out with why.
+type ImportOpenAPIResponse struct {
+	Created []db.Monitor    `json:"created"`
+	Skipped []ImportSkipped `json:"skipped"`
+}
+
+// ImportSkipped is a selected operation that was not imported.
+type ImportSkipped struct {
+	Operation string `json:"operation"`
+	Reason    string `json:"reason"`
+}
+
+// ImportOpenAPI reads an OpenAPI 3 or Swagger 2 JSON document from url. Without
+// operations it lists the document's GET operations; with them it creates an HTTP
+// monitor for each selected operation, named and tagged from the document and
+// expecting its documented 2xx responses. Operations that need parameters, or whose
+// URL or name is already monitored, are skipped.
+// @Summary      Import monitors from an OpenAPI document
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{url=string,operations=[]string,groupId=string,baseUrl=string,interval=int,tags=[]string} true "Document URL and the operation IDs (e.g. \"GET /health\") to import"
+// @Success      200  {object} openapi.Spec "Operations of the document, when none were selected"
+// @Success      201  {object} ImportOpenAPIResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "Group not found"
+// @Failure      502  {object} ErrorResponse "The document could not be fetched"
+// @Router       /import/openapi [post]
+func (h *CRUDHandler) ImportOpenAPI(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL        string   `json:"url"`
+		Operations []string `json:"operations"`
+		GroupID    string   `json:"groupId"`
+		BaseURL    string   `json:"baseUrl"`
+		Interval   int      `json:"interval"`
+		Tags       []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	// SECURITY: Fetching the document is an outbound request from the server, validated
+	// like a monitor URL; the dialer blocks private targets as it does for checks.
+	if err := validateMonitorURL(r.Context(), req.URL, h.manager.BlocksPrivateTargets()); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Operations) > maxImportOperations {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d operations can be imported at once", maxImportOperations))
+		return
+	}
+	extraTags, err := normalizeTags(req.Tags)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Interval == 0 {
+		req.Interval = 60
+	}
+	if req.Interval < minMonitorInterval {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("interval must be at least %d seconds", minMonitorInterval))
+		return
+	}
+
+	data, err := h.manager.FetchDocument(r.Context(), req.URL, maxOpenAPIDocumentBytes)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "failed to fetch the document: "+err.Error())
+		return
+	}
+	spec, err := openapi.Parse(data, req.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Operations) == 0 {
+		writeJSON(w, http.StatusOK, spec)
+		return
+	}
+
+	base := strings.TrimSuffix(req.BaseURL, "/")
+	if base == "" {
+		base = spec.BaseURL
+	}
+	if base == "" {
+		writeError(w, http.StatusBadRequest, "the document does not say where the API is served; set baseUrl")
+		return
+	}
+	if err := validateMonitorURL(r.Context(), base, h.manager.BlocksPrivateTargets()); err != nil {
+		writeError(w, http.StatusBadRequest, "baseUrl: "+err.Error())
+		return
+	}
+	if req.GroupID == "" {
+		writeError(w, http.StatusBadRequest, "groupId is required")
+		return
+	}
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "System error checking groups")
+		return
+	}
+	groupExists := false
+	for _, g := range groups {
+		if g.ID == req.GroupID {
+			groupExists = true
+			break
+		}
+	}
+	if !groupExists {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeGroupNotFound, "Selected group does not exist")
+		return
+	}
+
+	byID := make(map[string]openapi.Operation, len(spec.Operations))
+	for _, op := range spec.Operations {
+		byID[op.ID] = op
+	}
+	for _, id := range req.Operations {
+		if _, ok := byID[id]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown operation %q", id))
+			return
+		}
+	}
+
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+	resp := ImportOpenAPIResponse{Created: []db.Monitor{}, Skipped: []ImportSkipped{}}
+	skip := func(id, reason string) {
+		resp.Skipped = append(resp.Skipped, ImportSkipped{Operation: id, Reason: reason})
+	}
+	seen := make(map[string]bool)
+	for _, id := range req.Operations {
+		op := byID[id]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !op.Monitorable() {
+			skip(id, op.Reason)
+			continue
+		}
+		target := base + op.Path
+		if dup := findDuplicateURL(monitors, target); dup != nil {
+			skip(id, fmt.Sprintf("already monitored by %q", dup.Name))
+			continue
+		}
+
+		m := db.Monitor{
+			ID:            generateID(op.Name, "m-"),
+			GroupID:       req.GroupID,
+			Name:          truncateName(op.Name),
+			URL:           target,
+			Type:          db.MonitorTypeHTTP,
+			Active:        true,
+			Interval:      req.Interval,
+			RequestConfig: &db.RequestConfig{AcceptedStatusCodes: op.ExpectedStatus},
+			Tags:          importTags(op.Tags, extraTags),
+		}
+		if err := h.store.CreateMonitor(m); err != nil {
+			if errors.Is(err, db.ErrDuplicateMonitorName) {
+				skip(id, fmt.Sprintf("a monitor named %q already exists", m.Name))
+				continue
+			}
+			writeError(w, http.StatusInternalServerError, "failed to create monitor")
+			return
+		}
+		monitors = append(monitors, m)
+		resp.Created = append(resp.Created, m)
+	}
+
+	if len(resp.Created) > 0 {
+		h.manager.Sync()
+	}
+	log.Printf("AUDIT: [MONITOR] Imported %d monitors from OpenAPI document %s", len(resp.Created), sanitizeLog(req.URL)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// importTags turns the document's tags into monitor tags, which allow fewer characters,
+// after the tags given with the import.
+func importTags(specTags, extra []string) []string {
+	tags := append([]string{}, extra...)
+	for _, t := range specTags {
+		t = strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || strings.ContainsRune("_.:/-", r) {
+				return r
+			}
+			return -1
+		}, strings.Join(strings.Fields(strings.ToLower(t)), "-"))
+		t = strings.TrimLeft(t, "_.:/-")
+		if len(t) > maxTagLength {
+			t = t[:maxTagLength]
+		}
+		tags = append(tags, t)
+	}
+	if len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	normalized, _ := normalizeTags(tags)
+	return normalized
+}
+
+// truncateName shortens a name to the longest a monitor may have, on a rune boundary.
+func truncateName(name string) string {
+	if len(name) <= maxNameLength {
+		return name
+	}
+	cut := maxNameLength
+	for cut > 0 && !utf8.RuneStart(name[cut]) {
+		cut--
+	}
+	return name[:cut]
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/openapi"
+)
+
+func TestImportOpenAPI(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"openapi": "3.0.0", "info": {"title": "Shop"}, "servers": [{"url": "/api"}],
+			"paths": {
+				"/orders": {"get": {"summary": "List orders", "tags": ["Orders", "Public API"], "responses": {"200": {}}}},
+				"/orders/{id}": {"get": {"summary": "Get order", "responses": {"200": {}}}},
+				"/health": {"get": {"operationId": "health", "responses": {"204": {}}}}
+			}}`))
+	}))
+	defer srv.Close()
+	if err := s.CreateMonitor(db.Monitor{ID: "m-health", GroupID: "g-default", Name: "Health", URL: srv.URL + "/api/health", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+
+	post := func(body map[string]any) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		rr := httptest.NewRecorder()
+		crudH.ImportOpenAPI(rr, httptest.NewRequest("POST", "/api/import/openapi", bytes.NewReader(b)))
+		return rr
+	}
+
+	// Without operations the document's operations are listed
+	rr := post(map[string]any{"url": srv.URL + "/openapi.json"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var spec openapi.Spec
+	_ = json.Unmarshal(rr.Body.Bytes(), &spec)
+	if spec.BaseURL != srv.URL+"/api" || len(spec.Operations) != 3 {
+		t.Fatalf("Unexpected spec %+v", spec)
+	}
+	if monitors, _ := s.GetMonitors(); len(monitors) != 1 {
+		t.Errorf("Expected listing not to create monitors, got %d", len(monitors))
+	}
+
+	rr = post(map[string]any{
+		"url":        srv.URL + "/openapi.json",
+		"groupId":    "g-default",
+		"operations": []string{"GET /orders", "GET /orders/{id}", "GET /health"},
+		"tags":       []string{"shop"},
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp ImportOpenAPIResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if len(resp.Created) != 1 || len(resp.Skipped) != 2 {
+		t.Fatalf("Expected 1 monitor and 2 skipped operations, got %+v", resp)
+	}
+	m := resp.Created[0]
+	if m.Name != "List orders" || m.URL != srv.URL+"/api/orders" || m.RequestConfig == nil || m.RequestConfig.AcceptedStatusCodes != "200" {
+		t.Errorf("Unexpected monitor %+v", m)
+	}
+	if len(m.Tags) != 3 || m.Tags[0] != "shop" || m.Tags[1] != "orders" || m.Tags[2] != "public-api" {
+		t.Errorf("Expected the import's and the document's tags, got %v", m.Tags)
+	}
+	if resp.Skipped[0].Operation != "GET /orders/{id}" || resp.Skipped[1].Reason != `already monitored by "Health"` {
+		t.Errorf("Unexpected skipped operations %+v", resp.Skipped)
+	}
+
+	for name, tc := range map[string]struct {
+		body map[string]any
+		code int
+	}{
+		"missing url":       {map[string]any{}, http.StatusBadRequest},
+		"unknown operation": {map[string]any{"url": srv.URL + "/openapi.json", "groupId": "g-default", "operations": []string{"GET /nope"}}, http.StatusBadRequest},
+		"unknown group":     {map[string]any{"url": srv.URL + "/openapi.json", "groupId": "g-nope", "operations": []string{"GET /orders"}}, http.StatusNotFound},
+		"not a document":    {map[string]any{"url": srv.URL + "/missing.json"}, http.StatusBadGateway},
+	} {
+		if rr := post(tc.body); rr.Code != tc.code {
+			t.Errorf("%s: expected %d, got %d: %s", name, tc.code, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -4,21 +4,29 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+	"github.com/projecthelena/warden/internal/uptime"
 	"github.com/go-chi/chi/v5"
 )
 
+// incidentAutoPublishSettingKey controls whether incidents promoted from outages are
+// public right away ("true") or start private and wait in the approval queue ("false").
+const incidentAutoPublishSettingKey = "incidents.auto_publish"
+
 type IncidentHandler struct {
-	store *db.Store
+	store   *db.Store
+	manager *uptime.Manager
 }
 
-func NewIncidentHandler(store *db.Store) *IncidentHandler {
-	return &IncidentHandler{store: store}
+func NewIncidentHandler(store *db.Store, manager *uptime.Manager) *IncidentHandler {
+	return &IncidentHandler{store: store, manager: manager}
 }
 
 func generateIncidentID() string {
@@ -29,22 +37,53 @@ func generateIncidentID() string {
 	return hex.EncodeToString(b)
 }
 
+// validateSeverity checks an incident severity; empty leaves it unset.
+func validateSeverity(errs validationErrors, severity string) {
+	switch severity {
+	case "", "minor", "major", "critical":
+	default:
+		errs.add("severity", "Severity must be minor, major or critical")
+	}
+}
+
 // IncidentResponseDTO is the API response structure for incidents
 type IncidentResponseDTO struct {
-	ID             string              `json:"id"`
-	Title          string              `json:"title"`
-	Description    string              `json:"description"`
-	Type           string              `json:"type"`
-	Severity       string              `json:"severity"`
-	Status         string              `json:"status"`
-	StartTime      time.Time           `json:"startTime"`
-	EndTime        *time.Time          `json:"endTime,omitempty"`
-	AffectedGroups []string            `json:"affectedGroups"`
-	CreatedAt      time.Time           `json:"createdAt"`
-	Source         string              `json:"source"`
-	OutageID       *int64              `json:"outageId,omitempty"`
-	Public         bool                `json:"public"`
-	Updates        []db.IncidentUpdate `json:"updates,omitempty"`
+	ID                 string              `json:"id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	Type               string              `json:"type"`
+	Severity           string              `json:"severity"`
+	Status             string              `json:"status"`
+	StartTime          time.Time           `json:"startTime"`
+	EndTime            *time.Time          `json:"endTime,omitempty"`
+	AffectedGroups     []string            `json:"affectedGroups"`
+	CreatedAt          time.Time           `json:"createdAt"`
+	Source             string              `json:"source"`
+	OutageID           *int64              `json:"outageId,omitempty"`
+	Public             bool                `json:"public"`
+	PendingPublication bool                `json:"pendingPublication"`
+	IssueKey           string              `json:"issueKey,omitempty"` // Jira or GitHub issue opened for the incident
+	IssueURL           string              `json:"issueUrl,omitempty"`
+	Updates            []db.IncidentUpdate `json:"updates,omitempty"`
+	// AffectedGroupDetails resolves AffectedGroups into names and the live status of
+	// their monitors. Groups that no longer exist are left out.
+	AffectedGroupDetails []IncidentAffectedGroup `json:"affectedGroupDetails,omitempty"`
+}
+
+// IncidentAffectedGroup is a group affected by an incident, with its monitors.
+type IncidentAffectedGroup struct {
+	ID       string                    `json:"id"`
+	Name     string                    `json:"name"`
+	Monitors []IncidentAffectedMonitor `json:"monitors"`
+}
+
+// IncidentAffectedMonitor is the current status of a monitor in an affected group.
+type IncidentAffectedMonitor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"` // up | degraded | down | paused
+	Latency   int64  `json:"latency"`
+	LastCheck string `json:"lastCheck,omitempty"` // RFC3339, empty until the first check
 }
 
 func incidentToDTO(i db.Incident, updates []db.IncidentUpdate) IncidentResponseDTO {
@@ -59,21 +98,87 @@ func incidentToDTO(i db.Incident, updates []db.IncidentUpdate) IncidentResponseD
 	}
 
 	return IncidentResponseDTO{
-		ID:             i.ID,
-		Title:          i.Title,
-		Description:    i.Description,
-		Type:           i.Type,
-		Severity:       i.Severity,
-		Status:         i.Status,
-		StartTime:      i.StartTime,
-		EndTime:        i.EndTime,
-		AffectedGroups: groups,
-		CreatedAt:      i.CreatedAt,
-		Source:         source,
-		OutageID:       i.OutageID,
-		Public:         i.Public,
-		Updates:        updates,
+		ID:                 i.ID,
+		Title:              i.Title,
+		Description:        i.Description,
+		Type:               i.Type,
+		Severity:           i.Severity,
+		Status:             i.Status,
+		StartTime:          i.StartTime,
+		EndTime:            i.EndTime,
+		AffectedGroups:     groups,
+		CreatedAt:          i.CreatedAt,
+		Source:             source,
+		OutageID:           i.OutageID,
+		Public:             i.Public,
+		PendingPublication: i.PendingPublication,
+		IssueKey:           i.IssueKey,
+		IssueURL:           i.IssueURL,
+		Updates:            updates,
+	}
+}
+
+// withAffectedGroups fills in the affected group details of the incidents, loading
+// groups and monitors once for all of them.
+func (h *IncidentHandler) withAffectedGroups(dtos []IncidentResponseDTO) error {
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		return err
+	}
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		return err
+	}
+	groupNames := make(map[string]string, len(groups))
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+	}
+	groupMonitors := make(map[string][]IncidentAffectedMonitor)
+	for _, m := range monitors {
+		groupMonitors[m.GroupID] = append(groupMonitors[m.GroupID], h.affectedMonitor(m))
+	}
+
+	for i := range dtos {
+		for _, id := range dtos[i].AffectedGroups {
+			name, ok := groupNames[id]
+			if !ok {
+				continue
+			}
+			mons := groupMonitors[id]
+			if mons == nil {
+				mons = []IncidentAffectedMonitor{}
+			}
+			dtos[i].AffectedGroupDetails = append(dtos[i].AffectedGroupDetails, IncidentAffectedGroup{ID: id, Name: name, Monitors: mons})
+		}
+	}
+	return nil
+}
+
+// affectedMonitor reports a monitor's live status from the manager, like the dashboard.
+func (h *IncidentHandler) affectedMonitor(meta db.Monitor) IncidentAffectedMonitor {
+	m := IncidentAffectedMonitor{ID: meta.ID, Name: meta.Name, Status: "down"}
+	task := h.manager.GetMonitor(meta.ID)
+	if task == nil {
+		if !meta.Active {
+			m.Status = "paused"
+		}
+		return m
+	}
+	history := task.GetHistory()
+	if len(history) == 0 {
+		m.Status = "up"
+		return m
+	}
+	last := history[len(history)-1]
+	if last.IsUp {
+		m.Status = "up"
+		if last.Latency > task.GetLatencyThreshold() || last.DegradedReason != "" {
+			m.Status = "degraded"
+		}
 	}
+	m.Latency = last.Latency
+	m.LastCheck = last.Timestamp.Format(time.RFC3339)
+	return m
 }
 
 // CreateIncident reports a new manual incident.
@@ -84,7 +189,8 @@ func incidentToDTO(i db.Incident, updates []db.IncidentUpdate) IncidentResponseD
 // @Security     BearerAuth
 // @Param        body body object{title=string,description=string,severity=string,status=string,startTime=string,affectedGroups=[]string} true "Incident payload"
 // @Success      201  {object} db.Incident
-// @Failure      400  {string} string "Invalid request body"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
 // @Router       /incidents [post]
 func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -98,19 +204,18 @@ func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	startTime, err := time.Parse(time.RFC3339, req.StartTime)
-	if err != nil {
-		http.Error(w, "Invalid start time format", http.StatusBadRequest)
+	errs := validationErrors{}
+	validateTitle(errs, req.Title)
+	validateSeverity(errs, req.Severity)
+	startTime := parseTimeField(errs, "startTime", req.StartTime)
+	if errs.write(w) {
 		return
 	}
 
-	// Ensure UTC storage
-	startTime = startTime.UTC()
-
 	affectedGroupsJSON, _ := json.Marshal(req.AffectedGroups)
 
 	incident := db.Incident{
@@ -128,37 +233,60 @@ func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request)
 
 	if err := h.store.CreateIncident(incident); err != nil {
 		log.Printf("ERROR: Failed to create incident: %v", err)
-		http.Error(w, "Failed to create incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to create incident")
 		return
 	}
+	h.notifyOpened(r, incident)
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(incidentToDTO(incident, nil))
 }
 
+// notifyOpened tells the status pages showing the incident that it opened, once it is
+// public. An incident published after it was resolved is announced as resolved.
+func (h *IncidentHandler) notifyOpened(r *http.Request, inc db.Incident) {
+	eventType := notifications.EventIncidentOpened
+	if inc.Status == "resolved" {
+		eventType = notifications.EventIncidentResolved
+	}
+	h.manager.NotifyIncident(eventType, inc, inc.Description, requestBaseURL(r))
+}
+
+// notifyStatus tells the status pages showing the incident about an update or its
+// resolution.
+func (h *IncidentHandler) notifyStatus(r *http.Request, inc db.Incident, message string) {
+	eventType := notifications.EventIncidentUpdated
+	if inc.Status == "resolved" {
+		eventType = notifications.EventIncidentResolved
+	}
+	h.manager.NotifyIncident(eventType, inc, message, requestBaseURL(r))
+}
+
 // GetIncidents returns incidents from the last 7 days.
 // @Summary      List incidents
 // @Tags         incidents
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {array} IncidentResponseDTO
-// @Failure      500  {string} string "Failed to fetch incidents"
+// @Failure      500  {object} ErrorResponse "Failed to fetch incidents"
 // @Router       /incidents [get]
 func (h *IncidentHandler) GetIncidents(w http.ResponseWriter, r *http.Request) {
 	since := time.Now().Add(-7 * 24 * time.Hour)
-	allEvents, err := h.store.GetIncidents(since)
+	incidents, err := h.store.GetIncidentsFiltered(db.IncidentFilter{Type: "incident", Since: since})
 	if err != nil {
-		http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch incidents")
 		return
 	}
 
 	var dtos []IncidentResponseDTO
-	for _, i := range allEvents {
-		if i.Type != "incident" {
-			continue
-		}
+	for _, i := range incidents {
 		dtos = append(dtos, incidentToDTO(i, nil))
 	}
+	if err := h.withAffectedGroups(dtos); err != nil {
+		log.Printf("ERROR: Failed to resolve affected groups: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch incidents")
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(dtos)
@@ -171,7 +299,7 @@ func (h *IncidentHandler) GetIncidents(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id path string true "Incident ID"
 // @Success      200  {object} IncidentResponseDTO
-// @Failure      404  {string} string "Incident not found"
+// @Failure      404  {object} ErrorResponse "Incident not found"
 // @Router       /incidents/{id} [get]
 func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -179,11 +307,11 @@ func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
 	incident, err := h.store.GetIncidentByID(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident: %v", err)
-		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
 		return
 	}
 	if incident == nil {
-		http.Error(w, "Incident not found", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeIncidentNotFound, "Incident not found")
 		return
 	}
 
@@ -194,8 +322,15 @@ func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
 		updates = nil
 	}
 
+	dtos := []IncidentResponseDTO{incidentToDTO(*incident, updates)}
+	if err := h.withAffectedGroups(dtos); err != nil {
+		log.Printf("ERROR: Failed to resolve affected groups: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(incidentToDTO(*incident, updates))
+	_ = json.NewEncoder(w).Encode(dtos[0])
 }
 
 // UpdateIncident updates an existing incident.
@@ -207,8 +342,9 @@ func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
 // @Param        id path string true "Incident ID"
 // @Param        body body object{title=string,description=string,severity=string,status=string,startTime=string,endTime=string,affectedGroups=[]string,public=bool} true "Incident payload"
 // @Success      200  {object} IncidentResponseDTO
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      404  {string} string "Incident not found"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      404  {object} ErrorResponse "Incident not found"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
 // @Router       /incidents/{id} [put]
 func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -216,11 +352,11 @@ func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request)
 	existing, err := h.store.GetIncidentByID(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident: %v", err)
-		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Incident not found", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeIncidentNotFound, "Incident not found")
 		return
 	}
 
@@ -236,26 +372,25 @@ func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	startTime, err := time.Parse(time.RFC3339, req.StartTime)
-	if err != nil {
-		http.Error(w, "Invalid start time format", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
+	errs := validationErrors{}
+	validateTitle(errs, req.Title)
+	validateSeverity(errs, req.Severity)
+	startTime := parseTimeField(errs, "startTime", req.StartTime)
 	var endTime *time.Time
 	if req.EndTime != nil && *req.EndTime != "" {
-		et, err := time.Parse(time.RFC3339, *req.EndTime)
-		if err != nil {
-			http.Error(w, "Invalid end time format", http.StatusBadRequest)
-			return
+		et := parseTimeField(errs, "endTime", *req.EndTime)
+		if errs["startTime"] == "" && errs["endTime"] == "" && et.Before(startTime) {
+			errs.add("endTime", "endTime must not be before startTime")
 		}
-		et = et.UTC()
 		endTime = &et
 	}
+	if errs.write(w) {
+		return
+	}
 
 	affectedGroupsJSON, _ := json.Marshal(req.AffectedGroups)
 
@@ -276,9 +411,14 @@ func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request)
 
 	if err := h.store.UpdateIncident(incident); err != nil {
 		log.Printf("ERROR: Failed to update incident: %v", err)
-		http.Error(w, "Failed to update incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to update incident")
 		return
 	}
+	if !existing.Public {
+		h.notifyOpened(r, incident)
+	} else if incident.Status != existing.Status {
+		h.notifyStatus(r, incident, incident.Description)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(incidentToDTO(incident, nil))
@@ -290,14 +430,14 @@ func (h *IncidentHandler) UpdateIncident(w http.ResponseWriter, r *http.Request)
 // @Security     BearerAuth
 // @Param        id path string true "Incident ID"
 // @Success      204
-// @Failure      500  {string} string "Failed to delete incident"
+// @Failure      500  {object} ErrorResponse "Failed to delete incident"
 // @Router       /incidents/{id} [delete]
 func (h *IncidentHandler) DeleteIncident(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.store.DeleteIncident(id); err != nil {
 		log.Printf("ERROR: Failed to delete incident: %v", err)
-		http.Error(w, "Failed to delete incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to delete incident")
 		return
 	}
 
@@ -311,27 +451,27 @@ func (h *IncidentHandler) DeleteIncident(w http.ResponseWriter, r *http.Request)
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path string true "Outage ID"
-// @Param        body body object{title=string,description=string,severity=string,affectedGroups=[]string} true "Incident details"
+// @Param        body body object{title=string,description=string,severity=string,affectedGroups=[]string,public=bool} true "Incident details (public defaults to the incidents.auto_publish setting)"
 // @Success      201  {object} IncidentResponseDTO
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      404  {string} string "Outage not found"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      404  {object} ErrorResponse "Outage not found"
 // @Router       /outages/{id}/promote [post]
 func (h *IncidentHandler) PromoteOutage(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	outageID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		http.Error(w, "Invalid outage ID", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid outage ID")
 		return
 	}
 
 	outage, err := h.store.GetOutageByID(outageID)
 	if err != nil {
 		log.Printf("ERROR: Failed to get outage: %v", err)
-		http.Error(w, "Failed to get outage", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get outage")
 		return
 	}
 	if outage == nil {
-		http.Error(w, "Outage not found", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, "Outage not found")
 		return
 	}
 
@@ -340,13 +480,26 @@ func (h *IncidentHandler) PromoteOutage(w http.ResponseWriter, r *http.Request)
 		Description    string   `json:"description"`
 		Severity       string   `json:"severity"`
 		AffectedGroups []string `json:"affectedGroups"`
+		Public         *bool    `json:"public"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
+	// Visibility follows the auto-publish policy unless chosen explicitly. Incidents
+	// left private by the policy wait in the approval queue.
+	public := false
+	pending := false
+	if req.Public != nil {
+		public = *req.Public
+	} else {
+		autoPublish, _ := h.store.GetSetting(incidentAutoPublishSettingKey)
+		public = autoPublish == "true"
+		pending = !public
+	}
+
 	// Use defaults from outage if not provided
 	title := req.Title
 	if title == "" {
@@ -376,25 +529,27 @@ func (h *IncidentHandler) PromoteOutage(w http.ResponseWriter, r *http.Request)
 	}
 
 	incident := db.Incident{
-		ID:             generateIncidentID(),
-		Title:          title,
-		Description:    description,
-		Type:           "incident",
-		Severity:       severity,
-		Status:         status,
-		StartTime:      outage.StartTime,
-		EndTime:        endTime,
-		AffectedGroups: string(affectedGroupsJSON),
-		Source:         "auto",
-		OutageID:       &outageID,
-		Public:         false, // Requires explicit approval to make public
+		ID:                 generateIncidentID(),
+		Title:              title,
+		Description:        description,
+		Type:               "incident",
+		Severity:           severity,
+		Status:             status,
+		StartTime:          outage.StartTime,
+		EndTime:            endTime,
+		AffectedGroups:     string(affectedGroupsJSON),
+		Source:             "auto",
+		OutageID:           &outageID,
+		Public:             public,
+		PendingPublication: pending,
 	}
 
 	if err := h.store.CreateIncident(incident); err != nil {
 		log.Printf("ERROR: Failed to create incident from outage: %v", err)
-		http.Error(w, "Failed to create incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to create incident")
 		return
 	}
+	h.notifyOpened(r, incident)
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(incidentToDTO(incident, nil))
@@ -409,8 +564,8 @@ func (h *IncidentHandler) PromoteOutage(w http.ResponseWriter, r *http.Request)
 // @Param        id path string true "Incident ID"
 // @Param        body body object{public=bool} true "Visibility setting"
 // @Success      200  {object} object{message=string,public=bool}
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      404  {string} string "Incident not found"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      404  {object} ErrorResponse "Incident not found"
 // @Router       /incidents/{id}/visibility [patch]
 func (h *IncidentHandler) SetVisibility(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -418,11 +573,11 @@ func (h *IncidentHandler) SetVisibility(w http.ResponseWriter, r *http.Request)
 	incident, err := h.store.GetIncidentByID(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident: %v", err)
-		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
 		return
 	}
 	if incident == nil {
-		http.Error(w, "Incident not found", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeIncidentNotFound, "Incident not found")
 		return
 	}
 
@@ -431,15 +586,19 @@ func (h *IncidentHandler) SetVisibility(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if err := h.store.SetIncidentPublic(id, req.Public); err != nil {
 		log.Printf("ERROR: Failed to set incident visibility: %v", err)
-		http.Error(w, "Failed to set visibility", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to set visibility")
 		return
 	}
+	if req.Public && !incident.Public {
+		incident.Public = true
+		h.notifyOpened(r, *incident)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
@@ -448,6 +607,34 @@ func (h *IncidentHandler) SetVisibility(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetPendingPublication returns the approval queue: private incidents nobody has
+// published or explicitly kept private yet, oldest first.
+// @Summary      List incidents pending publication
+// @Tags         incidents
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}  IncidentResponseDTO
+// @Router       /incidents/pending-publication [get]
+func (h *IncidentHandler) GetPendingPublication(w http.ResponseWriter, r *http.Request) {
+	incidents, err := h.store.GetIncidentsPendingPublication()
+	if err != nil {
+		log.Printf("ERROR: Failed to get incidents pending publication: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load incidents")
+		return
+	}
+
+	dtos := make([]IncidentResponseDTO, 0, len(incidents))
+	for _, i := range incidents {
+		dtos = append(dtos, incidentToDTO(i, nil))
+	}
+	if err := h.withAffectedGroups(dtos); err != nil {
+		log.Printf("ERROR: Failed to resolve affected groups: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load incidents")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos)
+}
+
 // AddUpdate adds a status update to an incident timeline.
 // @Summary      Add incident update
 // @Tags         incidents
@@ -457,8 +644,8 @@ func (h *IncidentHandler) SetVisibility(w http.ResponseWriter, r *http.Request)
 // @Param        id path string true "Incident ID"
 // @Param        body body object{status=string,message=string} true "Update details"
 // @Success      201  {object} db.IncidentUpdate
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      404  {string} string "Incident not found"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      404  {object} ErrorResponse "Incident not found"
 // @Router       /incidents/{id}/updates [post]
 func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -466,11 +653,11 @@ func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
 	incident, err := h.store.GetIncidentByID(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident: %v", err)
-		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
 		return
 	}
 	if incident == nil {
-		http.Error(w, "Incident not found", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeIncidentNotFound, "Incident not found")
 		return
 	}
 
@@ -480,18 +667,18 @@ func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	if req.Status == "" || req.Message == "" {
-		http.Error(w, "Status and message are required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Status and message are required")
 		return
 	}
 
 	if err := h.store.CreateIncidentUpdate(id, req.Status, req.Message); err != nil {
 		log.Printf("ERROR: Failed to create incident update: %v", err)
-		http.Error(w, "Failed to create update", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to create update")
 		return
 	}
 
@@ -505,6 +692,7 @@ func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
 		}
 		_ = h.store.UpdateIncident(*incident)
 	}
+	h.notifyStatus(r, *incident, req.Message)
 
 	// Return the latest updates
 	updates, _ := h.store.GetIncidentUpdates(id)
@@ -524,7 +712,7 @@ func (h *IncidentHandler) AddUpdate(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        id path string true "Incident ID"
 // @Success      200  {array} db.IncidentUpdate
-// @Failure      404  {string} string "Incident not found"
+// @Failure      404  {object} ErrorResponse "Incident not found"
 // @Router       /incidents/{id}/updates [get]
 func (h *IncidentHandler) GetUpdates(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -532,18 +720,18 @@ func (h *IncidentHandler) GetUpdates(w http.ResponseWriter, r *http.Request) {
 	incident, err := h.store.GetIncidentByID(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident: %v", err)
-		http.Error(w, "Failed to get incident", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get incident")
 		return
 	}
 	if incident == nil {
-		http.Error(w, "Incident not found", http.StatusNotFound)
+		writeErrorCode(w, http.StatusNotFound, ErrCodeIncidentNotFound, "Incident not found")
 		return
 	}
 
 	updates, err := h.store.GetIncidentUpdates(id)
 	if err != nil {
 		log.Printf("ERROR: Failed to get incident updates: %v", err)
-		http.Error(w, "Failed to get updates", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get updates")
 		return
 	}
 
@@ -554,3 +742,100 @@ func (h *IncidentHandler) GetUpdates(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(updates)
 }
+
+// loadIncidentUpdate parses the incident and update IDs of an update route, writing a
+// 400 or 404 and returning false when the update doesn't exist.
+func (h *IncidentHandler) loadIncidentUpdate(w http.ResponseWriter, r *http.Request) (*db.IncidentUpdate, bool) {
+	updateID, err := strconv.ParseInt(chi.URLParam(r, "updateId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid update ID")
+		return nil, false
+	}
+	u, err := h.store.GetIncidentUpdate(chi.URLParam(r, "id"), updateID)
+	if errors.Is(err, db.ErrIncidentUpdateNotFound) {
+		writeError(w, http.StatusNotFound, "incident update not found")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to get incident update: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load incident update")
+		return nil, false
+	}
+	return u, true
+}
+
+// EditUpdate corrects the status and message of a posted incident update, e.g. to fix a
+// typo. The update keeps its place in the timeline and is marked as edited; the
+// incident's own status is left alone.
+// @Summary      Edit incident update
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path string true "Incident ID"
+// @Param        updateId  path int    true "Update ID"
+// @Param        body      body object{status=string,message=string} true "Corrected update"
+// @Success      200  {object} db.IncidentUpdate
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /incidents/{id}/updates/{updateId} [put]
+func (h *IncidentHandler) EditUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Status == "" || req.Message == "" {
+		writeError(w, http.StatusBadRequest, "status and message are required")
+		return
+	}
+
+	u, ok := h.loadIncidentUpdate(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.EditIncidentUpdate(u.IncidentID, u.ID, req.Status, req.Message); err != nil {
+		log.Printf("ERROR: Failed to edit incident update: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to edit update")
+		return
+	}
+	edited, err := h.store.GetIncidentUpdate(u.IncidentID, u.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load incident update")
+		return
+	}
+
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+	log.Printf("AUDIT: [INCIDENT] User %d edited update %d of incident %s (was [%s] %q)", userID, u.ID, sanitizeLog(u.IncidentID), sanitizeLog(u.Status), sanitizeLog(u.Message)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, edited)
+}
+
+// DeleteUpdate removes a posted update from an incident's timeline.
+// @Summary      Delete incident update
+// @Tags         incidents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path string true "Incident ID"
+// @Param        updateId  path int    true "Update ID"
+// @Success      200  {object} object{message=string}
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /incidents/{id}/updates/{updateId} [delete]
+func (h *IncidentHandler) DeleteUpdate(w http.ResponseWriter, r *http.Request) {
+	u, ok := h.loadIncidentUpdate(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.DeleteIncidentUpdate(u.IncidentID, u.ID); err != nil {
+		log.Printf("ERROR: Failed to delete incident update: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete update")
+		return
+	}
+
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+	log.Printf("AUDIT: [INCIDENT] User %d deleted update %d of incident %s (was [%s] %q)", userID, u.ID, sanitizeLog(u.IncidentID), sanitizeLog(u.Status), sanitizeLog(u.Message)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "update deleted"})
+}
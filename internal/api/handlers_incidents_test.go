@@ -5,14 +5,18 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
 )
 
 func TestIncidentHandler(t *testing.T) {
 	s, _ := db.NewStore(db.NewTestConfig())
-	h := NewIncidentHandler(s)
+	h := NewIncidentHandler(s, uptime.NewManager(s))
 
 	// Create Incident
 	payload := map[string]string{
@@ -39,3 +43,210 @@ func TestIncidentHandler(t *testing.T) {
 		t.Errorf("GetIncidents failed: %d", w.Code)
 	}
 }
+
+func TestIncidentHandler_GetOutage(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewIncidentHandler(s, uptime.NewManager(s))
+	_ = s.CreateMonitor(db.Monitor{ID: "m-ev", GroupID: "g-default", Name: "Evidence", URL: "http://test.com", Interval: 60, Active: true})
+	_ = s.CreateOutageWithEvidence("m-ev", "down", "Monitor is down (Status: 503)", db.ErrorKindHTTP5xx, &db.OutageEvidence{
+		StatusCode:  503,
+		Headers:     map[string]string{"Retry-After": "60"},
+		BodySnippet: "Service Unavailable",
+	})
+	outages, _ := s.GetActiveOutages()
+	if len(outages) != 1 {
+		t.Fatalf("Expected 1 outage, got %d", len(outages))
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/outages/{id}", h.GetOutage)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	w := get("/api/outages/" + strconv.FormatInt(outages[0].ID, 10))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got db.MonitorOutage
+	_ = json.Unmarshal(w.Body.Bytes(), &got)
+	if got.ErrorKind != db.ErrorKindHTTP5xx || got.Evidence == nil || got.Evidence.Headers["Retry-After"] != "60" || got.Evidence.BodySnippet != "Service Unavailable" {
+		t.Errorf("Unexpected outage: %+v (evidence %+v)", got, got.Evidence)
+	}
+
+	if w := get("/api/outages/999"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+	if w := get("/api/outages/abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
+
+func TestIncidentHandler_PromoteVisibilityPolicy(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewIncidentHandler(s, uptime.NewManager(s))
+	_ = s.CreateMonitor(db.Monitor{ID: "m-pub", GroupID: "g-default", Name: "API", URL: "http://test.com", Interval: 60, Active: true})
+
+	r := chi.NewRouter()
+	r.Post("/api/outages/{id}/promote", h.PromoteOutage)
+	r.Get("/api/incidents/pending-publication", h.GetPendingPublication)
+	r.Patch("/api/incidents/{id}/visibility", h.SetVisibility)
+
+	promote := func(body string) IncidentResponseDTO {
+		t.Helper()
+		_ = s.CreateOutage("m-pub", "down", "Monitor is down")
+		outages, _ := s.GetActiveOutages()
+		if len(outages) == 0 {
+			t.Fatal("Expected an active outage")
+		}
+		req := httptest.NewRequest("POST", "/api/outages/"+strconv.FormatInt(outages[0].ID, 10)+"/promote", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("PromoteOutage failed: %d %s", w.Code, w.Body.String())
+		}
+		_ = s.CloseOutage("m-pub")
+		var dto IncidentResponseDTO
+		_ = json.NewDecoder(w.Body).Decode(&dto)
+		return dto
+	}
+	pending := func() []IncidentResponseDTO {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/api/incidents/pending-publication", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetPendingPublication failed: %d", w.Code)
+		}
+		var dtos []IncidentResponseDTO
+		_ = json.NewDecoder(w.Body).Decode(&dtos)
+		return dtos
+	}
+
+	// Default policy: private and queued for approval
+	queued := promote(`{}`)
+	if queued.Public || !queued.PendingPublication {
+		t.Errorf("Expected private pending incident, got public=%v pending=%v", queued.Public, queued.PendingPublication)
+	}
+
+	// Explicit choice skips the queue
+	if explicit := promote(`{"public": false}`); explicit.PendingPublication {
+		t.Error("Explicitly private incident should not be queued")
+	}
+
+	// Auto-publish policy
+	_ = s.SetSetting(incidentAutoPublishSettingKey, "true")
+	if published := promote(`{}`); !published.Public || published.PendingPublication {
+		t.Errorf("Expected public incident under auto-publish, got public=%v pending=%v", published.Public, published.PendingPublication)
+	}
+
+	list := pending()
+	if len(list) != 1 || list[0].ID != queued.ID {
+		t.Fatalf("Expected only %s pending, got %+v", queued.ID, list)
+	}
+
+	// Keeping it private is a decision too and empties the queue
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PATCH", "/api/incidents/"+queued.ID+"/visibility", bytes.NewBufferString(`{"public": false}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("SetVisibility failed: %d", w.Code)
+	}
+	if list := pending(); len(list) != 0 {
+		t.Errorf("Expected empty queue, got %d", len(list))
+	}
+}
+
+func TestIncidentHandler_EditAndDeleteUpdates(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewIncidentHandler(s, uptime.NewManager(s))
+	for _, id := range []string{"inc-1", "inc-2"} {
+		_ = s.CreateIncident(db.Incident{ID: id, Title: "API errors", Type: "incident", Severity: "major", Status: "identified", StartTime: time.Now()})
+	}
+	_ = s.CreateIncidentUpdate("inc-1", "identified", "Teh database is overloaded")
+	updates, _ := s.GetIncidentUpdates("inc-1")
+	if len(updates) != 1 {
+		t.Fatalf("Expected 1 update, got %d", len(updates))
+	}
+	updatePath := "/api/incidents/inc-1/updates/" + strconv.FormatInt(updates[0].ID, 10)
+
+	r := chi.NewRouter()
+	r.Put("/api/incidents/{id}/updates/{updateId}", h.EditUpdate)
+	r.Delete("/api/incidents/{id}/updates/{updateId}", h.DeleteUpdate)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		return w
+	}
+
+	if w := do("PUT", updatePath, `{"status": "identified", "message": ""}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a message, got %d", w.Code)
+	}
+	if w := do("PUT", "/api/incidents/inc-1/updates/abc", `{"status": "identified", "message": "x"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed update ID, got %d", w.Code)
+	}
+	if w := do("PUT", "/api/incidents/inc-2/updates/"+strconv.FormatInt(updates[0].ID, 10), `{"status": "identified", "message": "x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for another incident's update, got %d", w.Code)
+	}
+
+	w := do("PUT", updatePath, `{"status": "identified", "message": "The database is overloaded"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("EditUpdate failed: %d %s", w.Code, w.Body.String())
+	}
+	var edited db.IncidentUpdate
+	_ = json.Unmarshal(w.Body.Bytes(), &edited)
+	if edited.Message != "The database is overloaded" || edited.EditedAt == nil {
+		t.Errorf("Expected the corrected, edited update, got %+v", edited)
+	}
+
+	if w := do("DELETE", updatePath, ""); w.Code != http.StatusOK {
+		t.Errorf("DeleteUpdate failed: %d", w.Code)
+	}
+	if w := do("DELETE", updatePath, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting twice, got %d", w.Code)
+	}
+	if updates, _ := s.GetIncidentUpdates("inc-1"); len(updates) != 0 {
+		t.Errorf("Expected no updates left, got %+v", updates)
+	}
+}
+
+func TestIncidentHandler_AffectedGroupDetails(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	m := uptime.NewManager(s)
+	h := NewIncidentHandler(s, m)
+	_ = s.CreateGroup(db.Group{ID: "g-db", Name: "Databases"})
+	_ = s.CreateMonitor(db.Monitor{ID: "m-pg", GroupID: "g-db", Name: "Postgres", URL: "http://pg.test", Interval: 60, Active: true})
+	_ = s.CreateMonitor(db.Monitor{ID: "m-redis", GroupID: "g-db", Name: "Redis", URL: "http://redis.test", Interval: 60, Active: false})
+	m.Sync()
+	defer m.Stop()
+	m.GetMonitor("m-pg").RecordStatus(uptime.Status{Timestamp: time.Now(), IsUp: false, StatusCode: 503})
+
+	affected, _ := json.Marshal([]string{"g-db", "g-deleted"})
+	_ = s.CreateIncident(db.Incident{ID: "inc-1", Title: "DB outage", Type: "incident", Severity: "major", Status: "investigating", StartTime: time.Now(), AffectedGroups: string(affected)})
+
+	r := chi.NewRouter()
+	r.Get("/api/incidents/{id}", h.GetIncident)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/incidents/inc-1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetIncident failed: %d %s", w.Code, w.Body.String())
+	}
+	var dto IncidentResponseDTO
+	_ = json.Unmarshal(w.Body.Bytes(), &dto)
+
+	// The deleted group is left out of the details but kept in the IDs
+	if len(dto.AffectedGroups) != 2 || len(dto.AffectedGroupDetails) != 1 {
+		t.Fatalf("Expected 2 group IDs and 1 resolved group, got %+v", dto)
+	}
+	g := dto.AffectedGroupDetails[0]
+	if g.Name != "Databases" || len(g.Monitors) != 2 {
+		t.Fatalf("Expected Databases with 2 monitors, got %+v", g)
+	}
+	statuses := map[string]string{}
+	for _, mon := range g.Monitors {
+		statuses[mon.Name] = mon.Status
+	}
+	if statuses["Postgres"] != "down" || statuses["Redis"] != "paused" {
+		t.Errorf("Expected Postgres down and Redis paused, got %v", statuses)
+	}
+}
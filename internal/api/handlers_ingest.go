@@ -0,0 +1,322 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// maxIngestBodyBytes caps inbound alert payloads (Alertmanager batches can be large, but not unbounded).
+const maxIngestBodyBytes = 1 << 20
+
+// alertmanagerMonitorLabel is the alert label that selects the target external monitor.
+const alertmanagerMonitorLabel = "warden_monitor"
+
+type IngestHandler struct {
+	store   *db.Store
+	manager *uptime.Manager
+}
+
+func NewIngestHandler(store *db.Store, manager *uptime.Manager) *IngestHandler {
+	return &IngestHandler{store: store, manager: manager}
+}
+
+// alertmanagerPayload is the subset of the Alertmanager webhook (version 4) body we consume.
+type alertmanagerPayload struct {
+	Status string              `json:"status"`
+	Alerts []alertmanagerAlert `json:"alerts"`
+}
+
+type alertmanagerAlert struct {
+	Status      string            `json:"status"` // firing | resolved
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+// summary picks the most descriptive human-readable text for an alert.
+func (a alertmanagerAlert) summary() string {
+	for _, key := range []string{"summary", "description", "message"} {
+		if v := a.Annotations[key]; v != "" {
+			return v
+		}
+	}
+	if name := a.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "External alert firing"
+}
+
+// hashIngestToken returns the hex SHA-256 digest stored for webhook tokens.
+// Tokens are high-entropy random values, so a fast hash is sufficient for lookup.
+func hashIngestToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Alertmanager ingests a Prometheus Alertmanager webhook and maps each alert onto
+// an external monitor, selected by the "warden_monitor" label or the "monitor" query parameter.
+// @Summary      Ingest Alertmanager webhook
+// @Tags         ingest
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        monitor query string false "Fallback external monitor ID"
+// @Success      200  {object} object{processed=int,skipped=int}
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse "An alert could not be stored; the others were applied"
+// @Router       /ingest/alertmanager [post]
+func (h *IngestHandler) Alertmanager(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxIngestBodyBytes)
+
+	var payload alertmanagerPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid alertmanager payload")
+		return
+	}
+
+	fallbackMonitor := r.URL.Query().Get("monitor")
+	touched := make(map[string]bool)
+	processed, skipped, failed := 0, 0, 0
+
+	for _, a := range payload.Alerts {
+		monitorID := a.Labels[alertmanagerMonitorLabel]
+		if monitorID == "" {
+			monitorID = fallbackMonitor
+		}
+		if monitorID == "" || a.Fingerprint == "" {
+			skipped++
+			continue
+		}
+
+		mon, err := h.externalMonitor(monitorID)
+		if err != nil {
+			log.Printf("Ingest: skipping alertmanager alert for %s: %v", sanitizeLog(monitorID), err) // #nosec G706 -- sanitized
+			skipped++
+			continue
+		}
+
+		alert := db.ExternalAlert{
+			MonitorID:   mon.ID,
+			Source:      "alertmanager",
+			Fingerprint: a.Fingerprint,
+			Status:      db.ExternalAlertFiring,
+			Summary:     a.summary(),
+			StartsAt:    a.StartsAt.UTC(),
+		}
+		if alert.StartsAt.IsZero() {
+			alert.StartsAt = time.Now().UTC()
+		}
+		if a.Status == db.ExternalAlertResolved {
+			alert.Status = db.ExternalAlertResolved
+			endsAt := a.EndsAt.UTC()
+			if endsAt.IsZero() {
+				endsAt = time.Now().UTC()
+			}
+			alert.EndsAt = &endsAt
+		}
+
+		if err := h.store.UpsertExternalAlert(alert); err != nil {
+			// Keep going, so the alerts stored so far still reach their monitors
+			log.Printf("Ingest: failed to store alertmanager alert for %s: %v", mon.ID, err)
+			failed++
+			continue
+		}
+		touched[mon.ID] = true
+		processed++
+	}
+
+	for monitorID := range touched {
+		if err := h.applyExternalState(monitorID); err != nil {
+			log.Printf("Ingest: failed to apply state for %s: %v", monitorID, err)
+		}
+	}
+
+	if failed > 0 {
+		// Alertmanager resends the whole group; storing an alert again is harmless
+		writeError(w, http.StatusInternalServerError, "failed to store alert")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{"processed": processed, "skipped": skipped})
+}
+
+// Webhook ingests a generic alert for the external monitor owning the URL token.
+// @Summary      Ingest generic webhook alert
+// @Tags         ingest
+// @Accept       json
+// @Produce      json
+// @Param        token path string true "Ingest token"
+// @Param        body body object{status=string,key=string,summary=string} true "Alert payload (status: firing|resolved)"
+// @Success      200  {object} object{status=string}
+// @Success      202  {object} object{status=string} "Stored, monitor state not updated yet"
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      503  {object} ErrorResponse "Monitor is not active"
+// @Router       /ingest/webhook/{token} [post]
+func (h *IngestHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		writeError(w, http.StatusNotFound, "unknown token")
+		return
+	}
+
+	mon, err := h.store.GetMonitorByIngestToken(hashIngestToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeError(w, http.StatusNotFound, "unknown token")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to resolve token")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxIngestBodyBytes)
+	var req struct {
+		Status  string `json:"status"`
+		Key     string `json:"key"`
+		Summary string `json:"summary"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Status != db.ExternalAlertFiring && req.Status != db.ExternalAlertResolved {
+		writeError(w, http.StatusBadRequest, "status must be 'firing' or 'resolved'")
+		return
+	}
+	if req.Key == "" {
+		req.Key = "default"
+	}
+	if len(req.Key) > maxNameLength {
+		writeError(w, http.StatusBadRequest, "key too long (max 255 characters)")
+		return
+	}
+	if req.Summary == "" {
+		req.Summary = "External alert firing"
+	}
+	if len(req.Summary) > 1024 {
+		req.Summary = req.Summary[:1024]
+	}
+
+	// Refuse before storing, so the sender retries rather than the alert waiting unseen
+	if err := h.manager.ValidateExternalMonitor(mon.ID); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "monitor is not active")
+		return
+	}
+
+	now := time.Now().UTC()
+	alert := db.ExternalAlert{
+		MonitorID:   mon.ID,
+		Source:      "webhook",
+		Fingerprint: req.Key,
+		Status:      req.Status,
+		Summary:     req.Summary,
+		StartsAt:    now,
+	}
+	if req.Status == db.ExternalAlertResolved {
+		alert.EndsAt = &now
+	}
+
+	if err := h.store.UpsertExternalAlert(alert); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to store alert")
+		return
+	}
+	if err := h.applyExternalState(mon.ID); err != nil {
+		// The alert is stored and counts toward the state from the next alert on
+		log.Printf("Ingest: failed to apply state for %s: %v", mon.ID, err)
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "stored"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "accepted"})
+}
+
+// CreateIngestToken generates (or rotates) the webhook ingest token for an external monitor.
+// The raw token is returned only once.
+// @Summary      Create ingest token
+// @Tags         ingest
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{token=string,path=string}
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/ingest-token [post]
+func (h *IngestHandler) CreateIngestToken(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	mon, err := h.store.GetMonitor(id)
+	if err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return
+	}
+	if mon.Type != db.MonitorTypeExternal {
+		writeError(w, http.StatusBadRequest, "ingest tokens are only available for external monitors")
+		return
+	}
+
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+	token := "wh_" + hex.EncodeToString(b)
+
+	if err := h.store.SetMonitorIngestToken(id, hashIngestToken(token)); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"token":   token,
+		"path":    "/api/ingest/webhook/" + token,
+		"message": "Token created. Save it now, it will not be shown again.",
+	})
+}
+
+// externalMonitor loads a monitor and verifies it accepts ingested alerts.
+func (h *IngestHandler) externalMonitor(id string) (*db.Monitor, error) {
+	mon, err := h.store.GetMonitor(id)
+	if err != nil {
+		return nil, err
+	}
+	if mon.Type != db.MonitorTypeExternal {
+		return nil, errors.New("not an external monitor")
+	}
+	return mon, nil
+}
+
+// applyExternalState derives the monitor's state from its firing alerts and
+// feeds it into the uptime pipeline: down while any alert fires, up otherwise.
+func (h *IngestHandler) applyExternalState(monitorID string) error {
+	firing, err := h.store.GetFiringExternalAlerts(monitorID)
+	if err != nil {
+		return err
+	}
+
+	if len(firing) == 0 {
+		return h.manager.IngestExternalResult(monitorID, true, "", time.Now())
+	}
+
+	summary := firing[0].Summary
+	if len(firing) > 1 {
+		summary += " (+" + strconv.Itoa(len(firing)-1) + " more)"
+	}
+	return h.manager.IngestExternalResult(monitorID, false, summary, time.Now())
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func setupIngestTest(t *testing.T) (*db.Store, *uptime.Manager, http.Handler) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	manager := uptime.NewManager(store)
+	manager.Start()
+	t.Cleanup(manager.Stop)
+
+	if err := store.CreateMonitor(db.Monitor{ID: "ext1", GroupID: "g-default", Name: "Ext", Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	if err := store.CreateMonitor(db.Monitor{ID: "http1", GroupID: "g-default", Name: "HTTP", URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	manager.Sync()
+
+	h := NewIngestHandler(store, manager)
+	r := chi.NewRouter()
+	r.Post("/api/ingest/webhook/{token}", h.Webhook)
+	r.Post("/api/ingest/alertmanager", h.Alertmanager)
+	r.Post("/api/monitors/{id}/ingest-token", h.CreateIngestToken)
+	return store, manager, r
+}
+
+func waitForStatus(t *testing.T, manager *uptime.Manager, id string, wantUp bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if mon := manager.GetMonitor(id); mon != nil {
+			if isUp, _, hasHistory, _ := mon.GetLastStatus(); hasHistory && isUp == wantUp {
+				return
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Monitor %s did not reach up=%v", id, wantUp)
+}
+
+func TestIngestWebhook(t *testing.T) {
+	_, manager, router := setupIngestTest(t)
+
+	// Token only for external monitors
+	req := httptest.NewRequest("POST", "/api/monitors/http1/ingest-token", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for http monitor, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/api/monitors/ext1/ingest-token", nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var tokenResp map[string]string
+	_ = json.Unmarshal(rr.Body.Bytes(), &tokenResp)
+	if tokenResp["path"] == "" {
+		t.Fatal("Expected ingest path in response")
+	}
+
+	// Firing alert takes the monitor down
+	body, _ := json.Marshal(map[string]string{"status": "firing", "key": "disk", "summary": "Disk full"})
+	req = httptest.NewRequest("POST", tokenResp["path"], bytes.NewBuffer(body))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	waitForStatus(t, manager, "ext1", false)
+
+	// Resolving it brings the monitor back up
+	body, _ = json.Marshal(map[string]string{"status": "resolved", "key": "disk"})
+	req = httptest.NewRequest("POST", tokenResp["path"], bytes.NewBuffer(body))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	waitForStatus(t, manager, "ext1", true)
+
+	// Invalid status
+	body, _ = json.Marshal(map[string]string{"status": "bogus"})
+	req = httptest.NewRequest("POST", tokenResp["path"], bytes.NewBuffer(body))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid status, got %d", rr.Code)
+	}
+
+	// Unknown token
+	req = httptest.NewRequest("POST", "/api/ingest/webhook/wh_unknown", bytes.NewBufferString(`{"status":"firing"}`))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown token, got %d", rr.Code)
+	}
+}
+
+func TestIngestAlertmanager(t *testing.T) {
+	_, manager, router := setupIngestTest(t)
+
+	payload := `{
+		"status": "firing",
+		"alerts": [
+			{"status": "firing", "fingerprint": "f1", "labels": {"alertname": "HighLatency", "warden_monitor": "ext1"}, "annotations": {"summary": "p99 above 2s"}},
+			{"status": "firing", "fingerprint": "f2", "labels": {"warden_monitor": "http1"}},
+			{"status": "firing", "labels": {"warden_monitor": "ext1"}}
+		]
+	}`
+	req := httptest.NewRequest("POST", "/api/ingest/alertmanager", bytes.NewBufferString(payload))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp map[string]int
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp["processed"] != 1 || resp["skipped"] != 2 {
+		t.Errorf("Expected processed=1 skipped=2, got %v", resp)
+	}
+	waitForStatus(t, manager, "ext1", false)
+}
+
+func TestIngestAlertmanager_StoreFailureKeepsOtherAlerts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ingest.db")
+	store, _ := db.NewStore(db.NewTestConfigWithPath(dbPath))
+	manager := uptime.NewManager(store)
+	manager.Start()
+	t.Cleanup(manager.Stop)
+	if err := store.CreateMonitor(db.Monitor{ID: "ext1", GroupID: "g-default", Name: "Ext", Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	manager.Sync()
+
+	// Fail one alert the way a database error would
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Exec(`CREATE TRIGGER reject_f2 BEFORE INSERT ON external_alerts WHEN NEW.fingerprint = 'f2' BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+
+	h := NewIngestHandler(store, manager)
+	payload := `{
+		"status": "firing",
+		"alerts": [
+			{"status": "firing", "fingerprint": "f1", "labels": {"warden_monitor": "ext1"}, "annotations": {"summary": "p99 above 2s"}},
+			{"status": "firing", "fingerprint": "f2", "labels": {"warden_monitor": "ext1"}}
+		]
+	}`
+	rr := httptest.NewRecorder()
+	h.Alertmanager(rr, httptest.NewRequest("POST", "/api/ingest/alertmanager", bytes.NewBufferString(payload)))
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500 so Alertmanager retries, got %d: %s", rr.Code, rr.Body.String())
+	}
+	// The alert stored before the failure still takes the monitor down
+	waitForStatus(t, manager, "ext1", false)
+}
+
+func TestIngestWebhook_InactiveMonitor(t *testing.T) {
+	store, _, router := setupIngestTest(t)
+	if err := store.CreateMonitor(db.Monitor{ID: "ext2", GroupID: "g-default", Name: "Paused", Type: db.MonitorTypeExternal, Active: false, Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/monitors/ext2/ingest-token", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var tokenResp map[string]string
+	_ = json.Unmarshal(rr.Body.Bytes(), &tokenResp)
+
+	body, _ := json.Marshal(map[string]string{"status": "firing", "key": "disk"})
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", tokenResp["path"], bytes.NewBuffer(body)))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d: %s", rr.Code, rr.Body.String())
+	}
+	// Refused alerts aren't stored, so the sender's retry is the only copy
+	if firing, _ := store.GetFiringExternalAlerts("ext2"); len(firing) != 0 {
+		t.Errorf("Expected no stored alerts, got %v", firing)
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/issues"
+)
+
+// maxIssueWebhookBytes caps an issue tracker webhook body; GitHub sends up to 25MB, but
+// issue events are far smaller.
+const maxIssueWebhookBytes = 1 << 20
+
+type IssuesHandler struct {
+	store *db.Store
+}
+
+func NewIssuesHandler(store *db.Store) *IssuesHandler {
+	return &IssuesHandler{store: store}
+}
+
+// Webhook resolves the incident linked to a Jira or GitHub issue once the issue is
+// closed (GitHub) or moved to a done status (Jira). It is authenticated by the
+// HMAC-SHA256 signature the tracker computes with the integrations.issues.webhook_secret
+// setting, and disabled while that setting is empty.
+// @Summary      Issue tracker webhook
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Param        X-Hub-Signature-256 header string false "sha256=<hex> HMAC of the body (GitHub)"
+// @Param        X-Hub-Signature     header string false "sha256=<hex> HMAC of the body (Jira)"
+// @Success      200  {object} object{status=string}
+// @Failure      401  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /integrations/issues/webhook [post]
+func (h *IssuesHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	secret, _ := h.store.GetSetting(issues.WebhookSecretKey)
+	provider, _ := h.store.GetSetting(issues.ProviderKey)
+	if secret == "" || provider == "" {
+		writeError(w, http.StatusNotFound, "issue sync is not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIssueWebhookBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "webhook body too large")
+		return
+	}
+	if !issues.VerifySignature(secret, body, r.Header) {
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	update, err := issues.ParseWebhook(provider, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid webhook payload")
+		return
+	}
+	if update.Key == "" || !update.Closed {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored"})
+		return
+	}
+
+	incident, err := h.store.GetIncidentByIssueKey(update.Key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load incident")
+		return
+	}
+	if incident == nil || incident.Status == "resolved" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ignored"})
+		return
+	}
+
+	now := time.Now()
+	incident.Status = "resolved"
+	incident.EndTime = &now
+	if err := h.store.UpdateIncident(*incident); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to resolve incident")
+		return
+	}
+	_ = h.store.CreateIncidentUpdate(incident.ID, "resolved", "Resolved in "+update.Key)
+
+	log.Printf("AUDIT: [INCIDENT] Incident %s resolved by issue %s", sanitizeLog(incident.ID), sanitizeLog(update.Key)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"status": "resolved"})
+}
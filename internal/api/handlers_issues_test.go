@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/issues"
+)
+
+func setupIssuesTest(t *testing.T) (*db.Store, http.Handler) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.CreateIncident(db.Incident{ID: "inc-1", Title: "API down", Type: "incident", Severity: "major", Status: "investigating", StartTime: time.Now()}); err != nil {
+		t.Fatalf("Failed to create incident: %v", err)
+	}
+	if err := store.SetIncidentIssue("inc-1", "acme/ops#45", "https://github.com/acme/ops/issues/45"); err != nil {
+		t.Fatalf("Failed to link issue: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/integrations/issues/webhook", NewIssuesHandler(store).Webhook)
+	return store, r
+}
+
+func postIssueWebhook(router http.Handler, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/integrations/issues/webhook", bytes.NewBufferString(body))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+const closedIssueEvent = `{"action":"closed","issue":{"number":45},"repository":{"full_name":"acme/ops"}}`
+
+func TestIssueWebhook_Disabled(t *testing.T) {
+	store, router := setupIssuesTest(t)
+
+	if rr := postIssueWebhook(router, "", closedIssueEvent); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a secret, got %d", rr.Code)
+	}
+
+	// A secret alone is not enough: the provider decides how to read the event
+	_ = store.SetSetting(issues.WebhookSecretKey, "s3cret")
+	if rr := postIssueWebhook(router, "s3cret", closedIssueEvent); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a provider, got %d", rr.Code)
+	}
+}
+
+func TestIssueWebhook_ResolvesIncident(t *testing.T) {
+	store, router := setupIssuesTest(t)
+	_ = store.SetSetting(issues.ProviderKey, issues.ProviderGitHub)
+	_ = store.SetSetting(issues.WebhookSecretKey, "s3cret")
+
+	if rr := postIssueWebhook(router, "wrong", closedIssueEvent); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a bad signature, got %d", rr.Code)
+	}
+
+	// Other actions and unknown issues are acknowledged without changes
+	for _, body := range []string{
+		`{"action":"reopened","issue":{"number":45},"repository":{"full_name":"acme/ops"}}`,
+		`{"action":"closed","issue":{"number":99},"repository":{"full_name":"acme/ops"}}`,
+	} {
+		rr := postIssueWebhook(router, "s3cret", body)
+		if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "ignored") {
+			t.Errorf("Expected the event to be ignored, got %d %s", rr.Code, rr.Body.String())
+		}
+	}
+	if inc, _ := store.GetIncidentByID("inc-1"); inc.Status != "investigating" {
+		t.Fatalf("Incident changed before its issue closed: %s", inc.Status)
+	}
+
+	rr := postIssueWebhook(router, "s3cret", closedIssueEvent)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "resolved") {
+		t.Fatalf("Expected the incident to be resolved, got %d %s", rr.Code, rr.Body.String())
+	}
+	inc, _ := store.GetIncidentByID("inc-1")
+	if inc.Status != "resolved" || inc.EndTime == nil {
+		t.Errorf("Expected a resolved incident with an end time, got %s", inc.Status)
+	}
+	updates, _ := store.GetIncidentUpdates("inc-1")
+	if len(updates) != 1 || updates[0].Message != "Resolved in acme/ops#45" {
+		t.Errorf("Unexpected updates %+v", updates)
+	}
+
+	// Redelivery does not add another update
+	rr = postIssueWebhook(router, "s3cret", closedIssueEvent)
+	if !strings.Contains(rr.Body.String(), "ignored") {
+		t.Errorf("Expected a redelivered event to be ignored, got %s", rr.Body.String())
+	}
+	if updates, _ := store.GetIncidentUpdates("inc-1"); len(updates) != 1 {
+		t.Errorf("Expected one update after redelivery, got %d", len(updates))
+	}
+}
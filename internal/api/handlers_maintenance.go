@@ -1,16 +1,27 @@
 package api
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
 	"github.com/projecthelena/warden/internal/uptime"
 	"github.com/go-chi/chi/v5"
 )
 
+// maintenanceCalendarSecretKey holds the secret that signs the calendar links sent in
+// maintenance notifications. It is not in the settings schema, so GET /api/settings
+// never returns it.
+const maintenanceCalendarSecretKey = "maintenance.calendar_secret"
+
 type MaintenanceHandler struct {
 	store   *db.Store
 	manager *uptime.Manager
@@ -33,6 +44,19 @@ type MaintenanceResponse struct {
 	CreatedAt      time.Time  `json:"createdAt"`
 }
 
+// validateMaintenance checks the fields of a maintenance window and returns its
+// start and end in UTC.
+func validateMaintenance(title, start, end string) (time.Time, time.Time, validationErrors) {
+	errs := validationErrors{}
+	validateTitle(errs, title)
+	startTime := parseTimeField(errs, "startTime", start)
+	endTime := parseTimeField(errs, "endTime", end)
+	if errs["startTime"] == "" && errs["endTime"] == "" && !endTime.After(startTime) {
+		errs.add("endTime", "endTime must be after startTime")
+	}
+	return startTime, endTime, errs
+}
+
 // CreateMaintenance schedules a new maintenance window.
 // @Summary      Create maintenance
 // @Tags         maintenance
@@ -41,8 +65,9 @@ type MaintenanceResponse struct {
 // @Security     BearerAuth
 // @Param        body body object{title=string,description=string,status=string,startTime=string,endTime=string,affectedGroups=[]string} true "Maintenance payload"
 // @Success      201  {object} MaintenanceResponse
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      500  {string} string "Failed to schedule maintenance"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
+// @Failure      500  {object} ErrorResponse "Failed to schedule maintenance"
 // @Router       /maintenance [post]
 func (h *MaintenanceHandler) CreateMaintenance(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -55,26 +80,15 @@ func (h *MaintenanceHandler) CreateMaintenance(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	startTime, err := time.Parse(time.RFC3339, req.StartTime)
-	if err != nil {
-		http.Error(w, "Invalid start time format", http.StatusBadRequest)
+	startTime, endTime, errs := validateMaintenance(req.Title, req.StartTime, req.EndTime)
+	if errs.write(w) {
 		return
 	}
 
-	endTime, err := time.Parse(time.RFC3339, req.EndTime)
-	if err != nil {
-		http.Error(w, "Invalid end time format", http.StatusBadRequest)
-		return
-	}
-
-	// Ensure UTC storage
-	startTime = startTime.UTC()
-	endTime = endTime.UTC()
-
 	affectedGroupsJSON, _ := json.Marshal(req.AffectedGroups)
 
 	maintenance := db.Incident{
@@ -92,13 +106,30 @@ func (h *MaintenanceHandler) CreateMaintenance(w http.ResponseWriter, r *http.Re
 
 	if err := h.store.CreateIncident(maintenance); err != nil {
 		log.Printf("ERROR: Failed to schedule maintenance: %v", err)
-		http.Error(w, "Failed to schedule maintenance", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to schedule maintenance")
 		return
 	}
 
 	// Force triggers Manager to refresh active maintenance windows
 	go h.manager.Sync()
 
+	// Let notification channels offer the window as a calendar entry
+	h.manager.Notify(notifications.NotificationEvent{
+		MonitorID:   maintenance.ID,
+		MonitorName: maintenance.Title,
+		Type:        notifications.EventMaintenanceScheduled,
+		Message:     "Maintenance scheduled: " + maintenance.Title,
+		Time:        time.Now(),
+		Maintenance: &notifications.MaintenanceWindow{
+			ID:          maintenance.ID,
+			Title:       maintenance.Title,
+			Description: maintenance.Description,
+			Start:       startTime,
+			End:         endTime,
+			CalendarURL: h.calendarURL(r, maintenance.ID),
+		},
+	})
+
 	response := MaintenanceResponse{
 		ID:             maintenance.ID,
 		Title:          maintenance.Title,
@@ -122,23 +153,19 @@ func (h *MaintenanceHandler) CreateMaintenance(w http.ResponseWriter, r *http.Re
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {array}  MaintenanceResponse
-// @Failure      500  {string} string "Failed to fetch maintenance events"
+// @Failure      500  {object} ErrorResponse "Failed to fetch maintenance events"
 // @Router       /maintenance [get]
 func (h *MaintenanceHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
 	// Return all maintenance for now, or maybe only active/future?
 	// Using zero time returns all history + active
-	allEvents, err := h.store.GetIncidents(time.Time{})
+	windows, err := h.store.GetIncidentsFiltered(db.IncidentFilter{Type: "maintenance"})
 	if err != nil {
-		http.Error(w, "Failed to fetch maintenance events", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch maintenance events")
 		return
 	}
 
 	var dtos []MaintenanceResponse
-	for _, i := range allEvents {
-		if i.Type != "maintenance" {
-			continue
-		}
-
+	for _, i := range windows {
 		var groups []string
 		if i.AffectedGroups != "" {
 			_ = json.Unmarshal([]byte(i.AffectedGroups), &groups)
@@ -171,13 +198,14 @@ func (h *MaintenanceHandler) GetMaintenance(w http.ResponseWriter, r *http.Reque
 // @Param        id   path string true "Maintenance ID"
 // @Param        body body object{title=string,description=string,status=string,startTime=string,endTime=string,affectedGroups=[]string} true "Updated maintenance"
 // @Success      200  {object} MaintenanceResponse
-// @Failure      400  {string} string "Invalid request body"
-// @Failure      500  {string} string "Failed to update maintenance"
+// @Failure      400  {object} ErrorResponse "Invalid request body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
+// @Failure      500  {object} ErrorResponse "Failed to update maintenance"
 // @Router       /maintenance/{id} [put]
 func (h *MaintenanceHandler) UpdateMaintenance(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "Maintenance ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Maintenance ID required")
 		return
 	}
 
@@ -191,28 +219,23 @@ func (h *MaintenanceHandler) UpdateMaintenance(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	startTime, err := time.Parse(time.RFC3339, req.StartTime)
-	if err != nil {
-		http.Error(w, "Invalid start time format", http.StatusBadRequest)
+	startTime, endTime, errs := validateMaintenance(req.Title, req.StartTime, req.EndTime)
+	if errs.write(w) {
 		return
 	}
 
-	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	affectedGroupsJSON, _ := json.Marshal(req.AffectedGroups)
+
+	previous, err := h.store.GetIncidentByID(id)
 	if err != nil {
-		http.Error(w, "Invalid end time format", http.StatusBadRequest)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch maintenance")
 		return
 	}
 
-	// Ensure UTC
-	startTime = startTime.UTC()
-	endTime = endTime.UTC()
-
-	affectedGroupsJSON, _ := json.Marshal(req.AffectedGroups)
-
 	// Fetch existing to preserve type/created_at if needed, but we can overwrite most.
 	// Actually Store.UpdateIncident overwrites fields. Type should stay 'maintenance'.
 	// Severity 'minor' default. created_at is NOT updated in SQL.
@@ -232,13 +255,32 @@ func (h *MaintenanceHandler) UpdateMaintenance(w http.ResponseWriter, r *http.Re
 
 	if err := h.store.UpdateIncident(incident); err != nil {
 		log.Printf("ERROR: Failed to update maintenance %s: %v", sanitizeLog(id), err) // #nosec G706 -- sanitized
-		http.Error(w, "Failed to update maintenance", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to update maintenance")
 		return
 	}
 
 	// Refresh manager
 	go h.manager.Sync()
 
+	// Calendars that imported the window only pick up the new times from a fresh copy
+	if previous != nil && previous.Type == "maintenance" && rescheduled(previous, startTime, endTime) {
+		h.manager.Notify(notifications.NotificationEvent{
+			MonitorID:   incident.ID,
+			MonitorName: incident.Title,
+			Type:        notifications.EventMaintenanceRescheduled,
+			Message:     "Maintenance rescheduled: " + incident.Title,
+			Time:        time.Now(),
+			Maintenance: &notifications.MaintenanceWindow{
+				ID:          incident.ID,
+				Title:       incident.Title,
+				Description: incident.Description,
+				Start:       startTime,
+				End:         endTime,
+				CalendarURL: h.calendarURL(r, incident.ID),
+			},
+		})
+	}
+
 	response := MaintenanceResponse{
 		ID:             incident.ID,
 		Title:          incident.Title,
@@ -263,19 +305,19 @@ func (h *MaintenanceHandler) UpdateMaintenance(w http.ResponseWriter, r *http.Re
 // @Security     BearerAuth
 // @Param        id   path string true "Maintenance ID"
 // @Success      200  {object} object{success=bool}
-// @Failure      400  {string} string "Maintenance ID required"
-// @Failure      500  {string} string "Failed to delete maintenance"
+// @Failure      400  {object} ErrorResponse "Maintenance ID required"
+// @Failure      500  {object} ErrorResponse "Failed to delete maintenance"
 // @Router       /maintenance/{id} [delete]
 func (h *MaintenanceHandler) DeleteMaintenance(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "Maintenance ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Maintenance ID required")
 		return
 	}
 
 	if err := h.store.DeleteIncident(id); err != nil {
 		log.Printf("ERROR: Failed to delete maintenance %s: %v", sanitizeLog(id), err) // #nosec G706 -- sanitized
-		http.Error(w, "Failed to delete maintenance", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to delete maintenance")
 		return
 	}
 
@@ -285,3 +327,121 @@ func (h *MaintenanceHandler) DeleteMaintenance(w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`{"success":true}`))
 }
+
+// GetMaintenanceICS downloads a maintenance window as an iCalendar file.
+// @Summary      Maintenance calendar file
+// @Tags         maintenance
+// @Produce      text/calendar
+// @Security     BearerAuth
+// @Param        id   path string true "Maintenance ID"
+// @Success      200  {string} string "iCalendar (.ics) file"
+// @Failure      404  {object} ErrorResponse "Maintenance not found"
+// @Router       /maintenance/{id}/ics [get]
+func (h *MaintenanceHandler) GetMaintenanceICS(w http.ResponseWriter, r *http.Request) {
+	h.writeICS(w, chi.URLParam(r, "id"))
+}
+
+// GetMaintenanceCalendarFile serves the calendar link sent in maintenance notifications.
+// Recipients aren't signed in, so the token query parameter, signed for this window,
+// stands in for credentials.
+// @Summary      Maintenance calendar link
+// @Tags         maintenance
+// @Produce      text/calendar
+// @Param        id    path  string true "Maintenance ID"
+// @Param        token query string true "Token from the notification's calendar link"
+// @Success      200  {string} string "iCalendar (.ics) file"
+// @Failure      404  {object} ErrorResponse "Maintenance not found"
+// @Router       /maintenance/{id}/calendar.ics [get]
+func (h *MaintenanceHandler) GetMaintenanceCalendarFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	secret, _ := h.store.GetSetting(maintenanceCalendarSecretKey)
+	got, err := hex.DecodeString(r.URL.Query().Get("token"))
+	if secret == "" || err != nil || !hmac.Equal(got, signCalendarID(secret, id)) {
+		// Same answer as an unknown window, so tokens can't be probed per ID
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMaintenanceNotFound, "Maintenance not found")
+		return
+	}
+	h.writeICS(w, id)
+}
+
+// writeICS sends maintenance window id as an iCalendar file.
+func (h *MaintenanceHandler) writeICS(w http.ResponseWriter, id string) {
+	inc, err := h.store.GetIncidentByID(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to fetch maintenance")
+		return
+	}
+	if inc == nil || inc.Type != "maintenance" || inc.EndTime == nil {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMaintenanceNotFound, "Maintenance not found")
+		return
+	}
+
+	ics := notifications.BuildICS(notifications.MaintenanceWindow{
+		ID:          inc.ID,
+		Title:       inc.Title,
+		Description: inc.Description,
+		Start:       inc.StartTime,
+		End:         *inc.EndTime,
+	}, time.Now())
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="maintenance-`+sanitizeFilename(inc.ID)+`.ics"`)
+	_, _ = w.Write([]byte(ics))
+}
+
+// rescheduled reports whether a window moves from previous to start and end.
+func rescheduled(previous *db.Incident, start, end time.Time) bool {
+	return !previous.StartTime.Equal(start) || previous.EndTime == nil || !previous.EndTime.Equal(end)
+}
+
+// calendarURL builds an absolute link to the window's .ics file from the request that
+// created or changed it, signed so recipients can open it without signing in. Empty if
+// the Host header is not a plain host name or the link can't be signed.
+func (h *MaintenanceHandler) calendarURL(r *http.Request, id string) string {
+	base := requestBaseURL(r)
+	if base == "" {
+		return ""
+	}
+	secret, err := h.calendarSecret()
+	if err != nil {
+		log.Printf("ERROR: Failed to load the maintenance calendar secret: %v", err)
+		return ""
+	}
+	token := hex.EncodeToString(signCalendarID(secret, id))
+	return base + "/api/maintenance/" + url.PathEscape(id) + "/calendar.ics?token=" + token
+}
+
+// calendarSecret returns the secret calendar links are signed with, generating it on
+// first use.
+func (h *MaintenanceHandler) calendarSecret() (string, error) {
+	if secret, err := h.store.GetSetting(maintenanceCalendarSecretKey); err == nil && secret != "" {
+		return secret, nil
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	// Another request may have stored one first; links must all use the same secret
+	return h.store.SetSettingIfAbsent(maintenanceCalendarSecretKey, hex.EncodeToString(b))
+}
+
+// signCalendarID returns the HMAC-SHA256 of a maintenance window ID.
+func signCalendarID(secret, id string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id))
+	return mac.Sum(nil)
+}
+
+// requestBaseURL returns the scheme and host the request was made to, for links in
+// notifications, or "" when the Host header is not a plain host name.
+func requestBaseURL(r *http.Request) string {
+	// SECURITY: Validate Host header so notifications never link to an injected host
+	if !validHostPattern.MatchString(r.Host) {
+		return ""
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
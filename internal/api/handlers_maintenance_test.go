@@ -3,11 +3,16 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/config"
 	"github.com/projecthelena/warden/internal/db"
 	"github.com/projecthelena/warden/internal/uptime"
 )
@@ -54,3 +59,205 @@ func TestGetMaintenance(t *testing.T) {
 		t.Errorf("Expected 200, got %d", w.Code)
 	}
 }
+
+func TestCreateMaintenance_NotifiesWithCalendar(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer srv.Close()
+
+	s, _ := db.NewStore(db.NewTestConfig())
+	if err := s.CreateNotificationChannel(db.NotificationChannel{ID: "ch1", Type: "webhook", Name: "Hook", Config: `{"webhookUrl":"` + srv.URL + `"}`, Enabled: true}); err != nil {
+		t.Fatalf("CreateNotificationChannel failed: %v", err)
+	}
+	m := uptime.NewManager(s)
+	m.Start()
+	defer m.Stop()
+	h := NewMaintenanceHandler(s, m)
+
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	body, _ := json.Marshal(map[string]interface{}{
+		"title":     "DB upgrade",
+		"status":    "scheduled",
+		"startTime": start.Format(time.RFC3339),
+		"endTime":   start.Add(2 * time.Hour).Format(time.RFC3339),
+	})
+	req := httptest.NewRequest("POST", "http://warden.example.com/api/maintenance", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	h.CreateMaintenance(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created MaintenanceResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	select {
+	case payload := <-received:
+		if payload["event"] != "maintenance_scheduled" {
+			t.Errorf("Expected maintenance_scheduled event, got %v", payload["event"])
+		}
+		ics, _ := payload["ics"].(string)
+		if !strings.Contains(ics, "BEGIN:VEVENT") || !strings.Contains(ics, "DTSTART:"+start.Format("20060102T150405Z")) {
+			t.Errorf("Expected ICS with event start, got %q", ics)
+		}
+		mw, _ := payload["maintenance"].(map[string]interface{})
+		if link, _ := mw["calendarUrl"].(string); !strings.HasPrefix(link, "http://warden.example.com/api/maintenance/"+created.ID+"/calendar.ics?token=") {
+			t.Errorf("Unexpected calendar URL %v", mw["calendarUrl"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a webhook notification for the new maintenance window")
+	}
+}
+
+func TestUpdateMaintenance_NotifiesReschedule(t *testing.T) {
+	received := make(chan map[string]interface{}, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer srv.Close()
+
+	s, _ := db.NewStore(db.NewTestConfig())
+	if err := s.CreateNotificationChannel(db.NotificationChannel{ID: "ch1", Type: "webhook", Name: "Hook", Config: `{"webhookUrl":"` + srv.URL + `"}`, Enabled: true}); err != nil {
+		t.Fatalf("CreateNotificationChannel failed: %v", err)
+	}
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	end := start.Add(time.Hour)
+	if err := s.CreateIncident(db.Incident{ID: "mw-1", Title: "DB upgrade", Type: "maintenance", Severity: "minor", Status: "scheduled", StartTime: start, EndTime: &end, Public: true}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	m := uptime.NewManager(s)
+	m.Start()
+	defer m.Stop()
+	h := NewMaintenanceHandler(s, m)
+	r := chi.NewRouter()
+	r.Put("/api/maintenance/{id}", h.UpdateMaintenance)
+	update := func(title string, start time.Time) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"title":     title,
+			"status":    "scheduled",
+			"startTime": start.Format(time.RFC3339),
+			"endTime":   start.Add(time.Hour).Format(time.RFC3339),
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PUT", "http://warden.example.com/api/maintenance/mw-1", bytes.NewBuffer(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	// A new title alone keeps the calendar entry valid
+	update("Database upgrade", start)
+	moved := start.Add(2 * time.Hour)
+	update("Database upgrade", moved)
+
+	select {
+	case payload := <-received:
+		if payload["event"] != "maintenance_rescheduled" {
+			t.Errorf("Expected maintenance_rescheduled event, got %v", payload["event"])
+		}
+		if ics, _ := payload["ics"].(string); !strings.Contains(ics, "DTSTART:"+moved.Format("20060102T150405Z")) {
+			t.Errorf("Expected ICS with the new start, got %q", ics)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a webhook notification for the rescheduled window")
+	}
+	select {
+	case payload := <-received:
+		t.Errorf("Expected one notification, also got %v", payload)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestMaintenanceCalendarLink_WorksWithoutSignIn(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	cfg := config.Default()
+	router := NewRouter(uptime.NewManager(s), s, &cfg)
+
+	start := time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	for _, id := range []string{"mw-1", "mw-2"} {
+		if err := s.CreateIncident(db.Incident{ID: id, Title: "Network", Type: "maintenance", Severity: "minor", Status: "scheduled", StartTime: start, EndTime: &end, Public: true}); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+	link := NewMaintenanceHandler(s, nil).calendarURL(httptest.NewRequest("POST", "http://warden.example.com/api/maintenance", nil), "mw-1")
+	u, err := url.Parse(link)
+	if err != nil || u.Query().Get("token") == "" {
+		t.Fatalf("Expected a signed calendar link, got %q", link)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	w := get(u.RequestURI())
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 without a session, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "DTSTART:20260501T220000Z") {
+		t.Errorf("Expected the window in the ICS, got:\n%s", w.Body.String())
+	}
+
+	token := u.Query().Get("token")
+	for _, path := range []string{
+		"/api/maintenance/mw-1/calendar.ics",
+		"/api/maintenance/mw-1/calendar.ics?token=" + strings.Repeat("0", len(token)),
+		"/api/maintenance/mw-2/calendar.ics?token=" + token, // Signed for another window
+	} {
+		if w := get(path); w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestGetMaintenanceICS(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewMaintenanceHandler(s, uptime.NewManager(s))
+
+	start := time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	if err := s.CreateIncident(db.Incident{ID: "mw-1", Title: "Network, core", Type: "maintenance", Severity: "minor", Status: "scheduled", StartTime: start, EndTime: &end, Public: true}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := s.CreateIncident(db.Incident{ID: "inc-1", Title: "Outage", Type: "incident", Severity: "major", Status: "investigating", StartTime: start}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/maintenance/{id}/ics", h.GetMaintenanceICS)
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/api/maintenance/"+id+"/ics", nil))
+		return w
+	}
+
+	w := get("mw-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Expected text/calendar, got %s", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"DTSTART:20260501T220000Z", "DTEND:20260501T233000Z", `SUMMARY:Maintenance: Network\, core`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in ICS:\n%s", want, body)
+		}
+	}
+
+	if w := get("inc-1"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a non-maintenance incident, got %d", w.Code)
+	}
+	if w := get("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown id, got %d", w.Code)
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// maxCheckExclusionWindows bounds a monitor's check exclusion windows.
+const maxCheckExclusionWindows = 20
+
+// GetMonitorCheckExclusions returns the recurring windows in which a monitor isn't checked.
+// @Summary      Get check exclusions
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} db.MonitorCheckExclusions
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/check-exclusions [get]
+func (h *CRUDHandler) GetMonitorCheckExclusions(w http.ResponseWriter, r *http.Request) {
+	exclusions, err := h.store.GetMonitorCheckExclusions(chi.URLParam(r, "id"))
+	if errors.Is(err, db.ErrCheckExclusionsNotFound) {
+		writeError(w, http.StatusNotFound, "no check exclusions for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load check exclusions")
+		return
+	}
+	writeJSON(w, http.StatusOK, exclusions)
+}
+
+// SetMonitorCheckExclusions replaces the recurring windows in which a monitor isn't
+// checked, e.g. a nightly batch job that legitimately takes the endpoint offline. No
+// checks are recorded during a window, so it doesn't count against the monitor's uptime.
+// @Summary      Set check exclusions
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path string true "Monitor ID"
+// @Param        body  body object{timezone=string,windows=[]db.CheckExclusionWindow} true "Windows as HH:MM start and end times, on the given weekdays (default every day), in timezone (default the user's)"
+// @Success      200  {object} db.MonitorCheckExclusions
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/check-exclusions [put]
+func (h *CRUDHandler) SetMonitorCheckExclusions(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Timezone string                    `json:"timezone"`
+		Windows  []db.CheckExclusionWindow `json:"windows"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Windows) == 0 || len(req.Windows) > maxCheckExclusionWindows {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("windows must have between 1 and %d entries", maxCheckExclusionWindows))
+		return
+	}
+	for i := range req.Windows {
+		if msg := normalizeCheckExclusionWindow(&req.Windows[i]); msg != "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("windows[%d]: %s", i, msg))
+			return
+		}
+	}
+	if req.Timezone == "" {
+		loc, _ := resolveLocation(r, h.store)
+		req.Timezone = loc.String()
+	} else if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+		writeError(w, http.StatusBadRequest, "timezone must be an IANA timezone, e.g. Europe/Berlin")
+		return
+	}
+
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	if err := h.store.SetMonitorCheckExclusions(db.MonitorCheckExclusions{MonitorID: mon.ID, Timezone: req.Timezone, Windows: req.Windows}); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save check exclusions")
+		return
+	}
+	h.manager.Sync()
+
+	exclusions, err := h.store.GetMonitorCheckExclusions(mon.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load check exclusions")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s check exclusions set to %d windows in %s", sanitizeLog(mon.ID), len(exclusions.Windows), sanitizeLog(exclusions.Timezone)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, exclusions)
+}
+
+// normalizeCheckExclusionWindow validates a window, lowercasing and deduplicating its
+// days, and returns an error message, or "" when valid.
+func normalizeCheckExclusionWindow(win *db.CheckExclusionWindow) string {
+	start, ok := db.ParseClock(win.Start)
+	if !ok {
+		return "start must be an HH:MM time"
+	}
+	end, ok := db.ParseClock(win.End)
+	if !ok {
+		return "end must be an HH:MM time"
+	}
+	if start == end {
+		return "start and end must differ"
+	}
+	var days []string
+	seen := make(map[string]bool)
+	for _, d := range win.Days {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if _, ok := parseWeekday(d); !ok {
+			return fmt.Sprintf("invalid day %q: use weekday names such as monday", d)
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	win.Days = days
+	return ""
+}
+
+// DeleteMonitorCheckExclusions removes a monitor's check exclusion windows, so it is
+// checked around the clock again.
+// @Summary      Delete check exclusions
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/check-exclusions [delete]
+func (h *CRUDHandler) DeleteMonitorCheckExclusions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deleted, err := h.store.DeleteMonitorCheckExclusions(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete check exclusions")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "no check exclusions for this monitor")
+		return
+	}
+	h.manager.Sync()
+	log.Printf("AUDIT: [MONITOR] Monitor %s check exclusions deleted", sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "check exclusions deleted"})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitorCheckExclusions(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "http://example.com", Interval: 60, Active: true}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/check-exclusions", crudH.GetMonitorCheckExclusions)
+	r.Put("/api/monitors/{id}/check-exclusions", crudH.SetMonitorCheckExclusions)
+	r.Delete("/api/monitors/{id}/check-exclusions", crudH.DeleteMonitorCheckExclusions)
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/m1/check-exclusions", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without check exclusions, got %d", rr.Code)
+	}
+	nightly := map[string]any{"start": "23:00", "end": "02:00"}
+	for _, body := range []map[string]any{
+		{},
+		{"windows": []any{}},
+		{"windows": []any{map[string]any{"start": "25:00", "end": "02:00"}}},
+		{"windows": []any{map[string]any{"start": "01:00", "end": "2am"}}},
+		{"windows": []any{map[string]any{"start": "01:00", "end": "01:00"}}},
+		{"windows": []any{map[string]any{"start": "01:00", "end": "02:00", "days": []string{"funday"}}}},
+		{"windows": []any{nightly}, "timezone": "Mars/Olympus"},
+	} {
+		if rr := doShadowRequest(r, "PUT", "/api/monitors/m1/check-exclusions", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/monitors/missing/check-exclusions", map[string]any{"windows": []any{nightly}}); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown monitor, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(r, "PUT", "/api/monitors/m1/check-exclusions", map[string]any{
+		"timezone": "Europe/Berlin",
+		"windows":  []any{nightly, map[string]any{"start": "12:00", "end": "12:30", "days": []string{" Sunday ", "sunday"}}},
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var got db.MonitorCheckExclusions
+	_ = json.Unmarshal(rr.Body.Bytes(), &got)
+	if got.Timezone != "Europe/Berlin" || len(got.Windows) != 2 || len(got.Windows[1].Days) != 1 || got.Windows[1].Days[0] != "sunday" {
+		t.Errorf("Unexpected check exclusions %+v", got)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	mon := crudH.manager.GetMonitor("m1")
+	if mon == nil || !mon.InExclusion(time.Date(2026, 3, 4, 1, 0, 0, 0, berlin)) {
+		t.Error("Expected the running monitor to skip checks in the nightly window")
+	}
+
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/check-exclusions", nil); rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	if mon := crudH.manager.GetMonitor("m1"); mon == nil || mon.InExclusion(time.Date(2026, 3, 4, 1, 0, 0, 0, berlin)) {
+		t.Error("Expected the monitor to be checked around the clock again")
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/check-exclusions", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when nothing is left to delete, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// maxDowntimeCostPerMinute bounds a monitor's downtime cost.
+const maxDowntimeCostPerMinute = 1_000_000
+
+// GetMonitorDowntimeCost returns the approximate cost of a minute of a monitor's downtime.
+// @Summary      Get downtime cost
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} db.MonitorDowntimeCost
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/downtime-cost [get]
+func (h *CRUDHandler) GetMonitorDowntimeCost(w http.ResponseWriter, r *http.Request) {
+	cost, err := h.store.GetMonitorDowntimeCost(chi.URLParam(r, "id"))
+	if errors.Is(err, db.ErrDowntimeCostNotFound) {
+		writeError(w, http.StatusNotFound, "no downtime cost for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load downtime cost")
+		return
+	}
+	writeJSON(w, http.StatusOK, cost)
+}
+
+// SetMonitorDowntimeCost sets the approximate cost of a minute of a monitor's downtime,
+// in the currency the cost agents report in. Outages and uptime reports of the monitor
+// then carry an estimated cost.
+// @Summary      Set downtime cost
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path string true "Monitor ID"
+// @Param        body  body object{costPerMinute=number} true "Cost of a minute of downtime"
+// @Success      200  {object} db.MonitorDowntimeCost
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/downtime-cost [put]
+func (h *CRUDHandler) SetMonitorDowntimeCost(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CostPerMinute *float64 `json:"costPerMinute"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.CostPerMinute == nil {
+		writeError(w, http.StatusBadRequest, "costPerMinute is required")
+		return
+	}
+	if *req.CostPerMinute <= 0 || *req.CostPerMinute > maxDowntimeCostPerMinute || math.IsNaN(*req.CostPerMinute) {
+		writeError(w, http.StatusBadRequest, "costPerMinute must be greater than 0 and at most 1000000")
+		return
+	}
+
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	if err := h.store.SetMonitorDowntimeCost(mon.ID, *req.CostPerMinute); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save downtime cost")
+		return
+	}
+	cost, err := h.store.GetMonitorDowntimeCost(mon.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load downtime cost")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s downtime cost set to %g per minute", sanitizeLog(mon.ID), cost.CostPerMinute) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, cost)
+}
+
+// DeleteMonitorDowntimeCost removes a monitor's downtime cost.
+// @Summary      Delete downtime cost
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/downtime-cost [delete]
+func (h *CRUDHandler) DeleteMonitorDowntimeCost(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deleted, err := h.store.DeleteMonitorDowntimeCost(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete downtime cost")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "no downtime cost for this monitor")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s downtime cost deleted", sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "downtime cost deleted"})
+}
+
+// estimateOutageCosts sets the estimated cost of the outages of monitors with a downtime
+// cost, open outages counting until now. Outages are left without one when the costs
+// can't be loaded.
+func estimateOutageCosts(store *db.Store, outages []db.MonitorOutage, now time.Time) {
+	if len(outages) == 0 {
+		return
+	}
+	costs, err := store.GetDowntimeCosts()
+	if err != nil {
+		log.Printf("ERROR: Failed to load downtime costs: %v", err)
+		return
+	}
+	for i := range outages {
+		perMinute, ok := costs[outages[i].MonitorID]
+		if !ok {
+			continue
+		}
+		end := now
+		if outages[i].EndTime != nil {
+			end = *outages[i].EndTime
+		}
+		cost := db.EstimateDowntimeCost(perMinute, end.Sub(outages[i].StartTime))
+		outages[i].EstimatedCost = &cost
+	}
+}
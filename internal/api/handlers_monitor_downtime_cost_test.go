@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitorDowntimeCost(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Checkout", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	incidentH := NewIncidentHandler(s, crudH.manager)
+	uptimeH := NewUptimeHandler(crudH.manager, s)
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/downtime-cost", crudH.GetMonitorDowntimeCost)
+	r.Put("/api/monitors/{id}/downtime-cost", crudH.SetMonitorDowntimeCost)
+	r.Delete("/api/monitors/{id}/downtime-cost", crudH.DeleteMonitorDowntimeCost)
+	r.Get("/api/monitors/{id}/uptime", uptimeH.GetMonitorUptime)
+	r.Get("/api/outages", incidentH.ListOutages)
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/m1/downtime-cost", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a downtime cost, got %d", rr.Code)
+	}
+	for _, body := range []map[string]any{{}, {"costPerMinute": 0}, {"costPerMinute": -5}, {"costPerMinute": 2e6}} {
+		if rr := doShadowRequest(r, "PUT", "/api/monitors/m1/downtime-cost", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/monitors/missing/downtime-cost", map[string]any{"costPerMinute": 10}); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown monitor, got %d", rr.Code)
+	}
+	rr := doShadowRequest(r, "PUT", "/api/monitors/m1/downtime-cost", map[string]any{"costPerMinute": 12.5})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var cost db.MonitorDowntimeCost
+	_ = json.Unmarshal(rr.Body.Bytes(), &cost)
+	if cost.MonitorID != "m1" || cost.CostPerMinute != 12.5 {
+		t.Errorf("Unexpected downtime cost %+v", cost)
+	}
+
+	// A 20 minute outage yesterday costs 250
+	end := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Second)
+	if _, err := s.CreateClosedOutage("m1", "down", "Checkout down", "", end.Add(-20*time.Minute), end); err != nil {
+		t.Fatalf("CreateClosedOutage failed: %v", err)
+	}
+	rr = doShadowRequest(r, "GET", "/api/outages", nil)
+	var list OutageListResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list.Outages) != 1 || list.Outages[0].EstimatedCost == nil || *list.Outages[0].EstimatedCost != 250 {
+		t.Errorf("Expected the outage to cost 250, got %+v", list.Outages)
+	}
+
+	rr = doShadowRequest(r, "GET", "/api/monitors/m1/uptime?range=7d&tz=UTC", nil)
+	var uptime MonitorUptimeResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &uptime)
+	if uptime.Range == nil || uptime.Range.DowntimeMinutes != 20 || uptime.Range.EstimatedCost == nil || *uptime.Range.EstimatedCost != 250 {
+		t.Errorf("Expected 20 minutes of downtime costing 250, got %+v", uptime.Range)
+	}
+
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/downtime-cost", nil); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/downtime-cost", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 once deleted, got %d", rr.Code)
+	}
+	rr = doShadowRequest(r, "GET", "/api/outages", nil)
+	list = OutageListResponse{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &list)
+	if len(list.Outages) != 1 || list.Outages[0].EstimatedCost != nil {
+		t.Errorf("Expected no estimate without a downtime cost, got %+v", list.Outages)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// PreviewMonitor runs a one-off check of a monitor configuration without saving it, so
+// the configuration can be validated before the monitor is created or updated.
+// @Summary      Preview monitor check
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{url=string,type=string,requestConfig=db.RequestConfig,latencyThreshold=int} true "Monitor configuration to check (HTTP monitors only)"
+// @Success      200  {object} uptime.PreviewResult
+// @Failure      400  {object} ErrorResponse
+// @Router       /monitors/preview [post]
+func (h *CRUDHandler) PreviewMonitor(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL              string            `json:"url"`
+		Type             string            `json:"type"`
+		RequestConfig    *db.RequestConfig `json:"requestConfig,omitempty"`
+		LatencyThreshold *int              `json:"latencyThreshold,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Type != "" && req.Type != db.MonitorTypeHTTP {
+		writeError(w, http.StatusBadRequest, "preview is only available for HTTP monitors")
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	// SECURITY: The preview makes an outbound request from the server, so it gets the same
+	// URL validation (and private target blocking in the dialer) as a saved monitor.
+	if err := validateMonitorURL(r.Context(), req.URL, h.manager.BlocksPrivateTargets()); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateRequestConfig(req.RequestConfig); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	threshold := h.manager.GetLatencyThreshold()
+	if req.LatencyThreshold != nil {
+		if *req.LatencyThreshold < 1 {
+			writeError(w, http.StatusBadRequest, "latencyThreshold must be at least 1")
+			return
+		}
+		threshold = int64(*req.LatencyThreshold)
+	}
+
+	writeJSON(w, http.StatusOK, h.manager.PreviewCheck(req.URL, req.RequestConfig, threshold))
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestPreviewMonitor(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	defer target.Close()
+
+	preview := func(body map[string]any) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		rr := httptest.NewRecorder()
+		crudH.PreviewMonitor(rr, httptest.NewRequest("POST", "/api/monitors/preview", bytes.NewReader(b)))
+		return rr
+	}
+
+	rr := preview(map[string]any{"url": target.URL})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var res uptime.PreviewResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != "down" || res.StatusCode != http.StatusTeapot || res.ResolvedIP != "127.0.0.1" {
+		t.Errorf("Unexpected preview %+v", res)
+	}
+
+	// The request config is applied as it would be for a saved monitor
+	rr = preview(map[string]any{"url": target.URL, "requestConfig": map[string]any{"method": "HEAD"}})
+	_ = json.Unmarshal(rr.Body.Bytes(), &res)
+	if res.Status != "up" || res.StatusCode != http.StatusOK {
+		t.Errorf("Expected HEAD preview to be up, got %+v", res)
+	}
+
+	for name, body := range map[string]map[string]any{
+		"missing url":       {},
+		"external monitor":  {"url": target.URL, "type": "external"},
+		"invalid scheme":    {"url": "ftp://example.com"},
+		"invalid threshold": {"url": target.URL, "latencyThreshold": 0},
+	} {
+		if rr := preview(body); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, rr.Code)
+		}
+	}
+
+	// Nothing is saved
+	if monitors, _ := s.GetMonitors(); len(monitors) != 0 {
+		t.Errorf("Expected no monitors to be created, got %d", len(monitors))
+	}
+}
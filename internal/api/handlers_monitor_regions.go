@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// regionStaleIntervals is how many check intervals a region may go without reporting
+// before it is marked stale.
+const regionStaleIntervals = 3
+
+// monitorRegion is the current status and 24h uptime of one region reporting on a monitor.
+type monitorRegion struct {
+	db.RegionSummary
+	Stale bool `json:"stale"` // No check for several intervals, e.g. the agent went offline
+}
+
+type monitorRegionsResponse struct {
+	MonitorID string          `json:"monitorId"`
+	Regions   []monitorRegion `json:"regions"`
+}
+
+// GetMonitorRegions summarizes a monitor's checks per reporting region over the last
+// 24 hours: the status and time of each region's latest check and its uptime. Checks run
+// by the server itself are reported as the "local" region; agents name theirs when
+// reporting results.
+// @Summary      Get monitor regions
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} monitorRegionsResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/regions [get]
+func (h *CRUDHandler) GetMonitorRegions(w http.ResponseWriter, r *http.Request) {
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	summaries, err := h.store.GetRegionSummaries(mon.ID, now.Add(-24*time.Hour))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to summarize regions")
+		return
+	}
+
+	interval := time.Duration(mon.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	resp := monitorRegionsResponse{MonitorID: mon.ID, Regions: make([]monitorRegion, 0, len(summaries))}
+	for _, s := range summaries {
+		resp.Regions = append(resp.Regions, monitorRegion{
+			RegionSummary: s,
+			Stale:         now.Sub(s.LastCheck) > regionStaleIntervals*interval,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
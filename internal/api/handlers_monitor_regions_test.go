@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestGetMonitorRegions(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Edge", Type: db.MonitorTypeExternal, Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 40, Timestamp: now.Add(-time.Minute), Region: "eu-west"},
+		{MonitorID: "m1", Status: "down", Timestamp: now.Add(-30 * time.Minute), Region: "us-east"},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/regions", crudH.GetMonitorRegions)
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/missing/regions", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown monitor, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(r, "GET", "/api/monitors/m1/regions", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp monitorRegionsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Regions) != 2 {
+		t.Fatalf("Expected 2 regions, got %+v", resp.Regions)
+	}
+	eu, us := resp.Regions[0], resp.Regions[1]
+	if eu.Region != "eu-west" || eu.Status != "up" || eu.Stale {
+		t.Errorf("Expected eu-west up and current, got %+v", eu)
+	}
+	// Silent for 30 intervals
+	if us.Region != "us-east" || us.Status != "down" || us.Uptime != 0 || !us.Stale {
+		t.Errorf("Expected us-east down and stale, got %+v", us)
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Remediation action bounds.
+const (
+	defaultRemediationCooldownMinutes = 30
+	maxRemediationAfterChecks         = 100
+	maxRemediationCooldownMinutes     = 7 * 24 * 60
+)
+
+// GetMonitorRemediation returns the webhook a monitor calls when its outage opens.
+// @Summary      Get remediation action
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} db.MonitorRemediation
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/remediation [get]
+func (h *CRUDHandler) GetMonitorRemediation(w http.ResponseWriter, r *http.Request) {
+	remediation, err := h.store.GetMonitorRemediation(chi.URLParam(r, "id"))
+	if errors.Is(err, db.ErrRemediationNotFound) {
+		writeError(w, http.StatusNotFound, "no remediation action for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load remediation action")
+		return
+	}
+	writeJSON(w, http.StatusOK, remediation)
+}
+
+// SetMonitorRemediation creates or replaces the webhook a monitor calls when its outage
+// opens, e.g. to trigger a CI job or a restart automation. It runs once per outage after
+// afterChecks failed checks in a row (0 = as soon as the outage is confirmed), and not
+// again within cooldownMinutes. Without a body, a JSON description of the outage is sent.
+// @Summary      Set remediation action
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path string true "Monitor ID"
+// @Param        body  body object{url=string,method=string,headers=object,body=string,afterChecks=int,cooldownMinutes=int,enabled=bool} true "Webhook (method defaults to POST) and when to call it (cooldown defaults to 30 minutes)"
+// @Success      200  {object} db.MonitorRemediation
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/remediation [put]
+func (h *CRUDHandler) SetMonitorRemediation(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL             string            `json:"url"`
+		Method          string            `json:"method"`
+		Headers         map[string]string `json:"headers"`
+		Body            string            `json:"body"`
+		AfterChecks     int               `json:"afterChecks"`
+		CooldownMinutes *int              `json:"cooldownMinutes"`
+		Enabled         *bool             `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	if err := validateMonitorURL(r.Context(), req.URL, h.manager.BlocksPrivateTargets()); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Method == "" {
+		req.Method = http.MethodPost
+	}
+	if err := validateRequestConfig(&db.RequestConfig{Method: req.Method, Headers: req.Headers, Body: req.Body}); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for name := range req.Headers {
+		if !headerNameRe.MatchString(name) {
+			writeError(w, http.StatusBadRequest, "header names must be valid HTTP header names")
+			return
+		}
+	}
+	if req.AfterChecks < 0 || req.AfterChecks > maxRemediationAfterChecks {
+		writeError(w, http.StatusBadRequest, "afterChecks must be between 0 and 100")
+		return
+	}
+	cooldown := defaultRemediationCooldownMinutes
+	if req.CooldownMinutes != nil {
+		if *req.CooldownMinutes < 0 || *req.CooldownMinutes > maxRemediationCooldownMinutes {
+			writeError(w, http.StatusBadRequest, "cooldownMinutes must be between 0 and 10080")
+			return
+		}
+		cooldown = *req.CooldownMinutes
+	}
+
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+
+	if err := h.store.SetMonitorRemediation(db.MonitorRemediation{
+		MonitorID:       mon.ID,
+		URL:             req.URL,
+		Method:          req.Method,
+		Headers:         req.Headers,
+		Body:            req.Body,
+		AfterChecks:     req.AfterChecks,
+		CooldownMinutes: cooldown,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save remediation action")
+		return
+	}
+	h.manager.Sync()
+
+	remediation, err := h.store.GetMonitorRemediation(mon.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load remediation action")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s remediation action set to %s %s", sanitizeLog(mon.ID), remediation.Method, sanitizeLog(remediation.URL)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, remediation)
+}
+
+// DeleteMonitorRemediation removes a monitor's remediation action.
+// @Summary      Delete remediation action
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/remediation [delete]
+func (h *CRUDHandler) DeleteMonitorRemediation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deleted, err := h.store.DeleteMonitorRemediation(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete remediation action")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "no remediation action for this monitor")
+		return
+	}
+	h.manager.Sync()
+	log.Printf("AUDIT: [MONITOR] Monitor %s remediation action deleted", sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "remediation action deleted"})
+}
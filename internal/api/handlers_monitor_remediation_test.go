@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitorRemediation(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/remediation", crudH.GetMonitorRemediation)
+	r.Put("/api/monitors/{id}/remediation", crudH.SetMonitorRemediation)
+	r.Delete("/api/monitors/{id}/remediation", crudH.DeleteMonitorRemediation)
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/m1/remediation", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a remediation action, got %d", rr.Code)
+	}
+	for _, body := range []map[string]any{
+		{},
+		{"url": "ftp://jenkins.example.com/job"},
+		{"url": "https://jenkins.example.com/job", "method": "TRACE"},
+		{"url": "https://jenkins.example.com/job", "headers": map[string]string{"Bad Header": "x"}},
+		{"url": "https://jenkins.example.com/job", "afterChecks": 101},
+		{"url": "https://jenkins.example.com/job", "cooldownMinutes": -1},
+	} {
+		if rr := doShadowRequest(r, "PUT", "/api/monitors/m1/remediation", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/monitors/missing/remediation", map[string]any{"url": "https://jenkins.example.com/job"}); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown monitor, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(r, "PUT", "/api/monitors/m1/remediation", map[string]any{
+		"url":         "https://jenkins.example.com/job/restart-api/build",
+		"headers":     map[string]string{"Authorization": "Bearer token"},
+		"afterChecks": 5,
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var got db.MonitorRemediation
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != http.MethodPost || got.CooldownMinutes != 30 || !got.Enabled || got.AfterChecks != 5 {
+		t.Errorf("Expected a POST with the default 30 minute cooldown, enabled, got %+v", got)
+	}
+	if got.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Expected headers to be kept, got %v", got.Headers)
+	}
+
+	// Replacing the action keeps one per monitor
+	rr = doShadowRequest(r, "PUT", "/api/monitors/m1/remediation", map[string]any{
+		"url": "https://jenkins.example.com/job/restart-api/build", "method": "PUT", "enabled": false, "cooldownMinutes": 0,
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr = doShadowRequest(r, "GET", "/api/monitors/m1/remediation", nil); rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	got = db.MonitorRemediation{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != http.MethodPut || got.Enabled || got.CooldownMinutes != 0 || len(got.Headers) != 0 {
+		t.Errorf("Expected the replaced action, got %+v", got)
+	}
+
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/remediation", nil); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/remediation", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 once deleted, got %d", rr.Code)
+	}
+}
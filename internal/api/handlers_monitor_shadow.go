@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+	"github.com/go-chi/chi/v5"
+)
+
+// Shadow trial length bounds, in checks.
+const (
+	defaultShadowChecks = 5
+	maxShadowChecks     = 100
+)
+
+// shadowReport is a shadow trial with its comparison against the monitor's current URL.
+type shadowReport struct {
+	Shadow     db.MonitorShadow        `json:"shadow"`
+	CurrentURL string                  `json:"currentUrl"`
+	Comparison uptime.ShadowComparison `json:"comparison"`
+	Samples    []db.ShadowSample       `json:"samples"`
+}
+
+// shadowMismatchResponse is returned when promoting a shadow URL whose checks did not match.
+type shadowMismatchResponse struct {
+	ErrorResponse
+	Comparison uptime.ShadowComparison `json:"comparison"`
+}
+
+func (h *CRUDHandler) shadowReport(mon *db.Monitor) (*shadowReport, error) {
+	trial, err := h.store.GetMonitorShadow(mon.ID)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := h.store.GetMonitorShadowSamples(mon.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &shadowReport{
+		Shadow:     *trial,
+		CurrentURL: mon.URL,
+		Comparison: uptime.CompareShadow(*trial, samples),
+		Samples:    samples,
+	}, nil
+}
+
+// validateShadowChecks resolves the requested trial length, nil meaning the default.
+func validateShadowChecks(field string, checks *int) (int, error) {
+	if checks == nil {
+		return defaultShadowChecks, nil
+	}
+	if *checks < 1 || *checks > maxShadowChecks {
+		return 0, fmt.Errorf("%s must be between 1 and %d", field, maxShadowChecks)
+	}
+	return *checks, nil
+}
+
+// startShadow validates a candidate URL for the monitor and starts its trial.
+func (h *CRUDHandler) startShadow(r *http.Request, mon *db.Monitor, candidate string, checks int) (int, error) {
+	if mon.Type != db.MonitorTypeHTTP {
+		return http.StatusBadRequest, errors.New("shadow checks are only available for HTTP monitors")
+	}
+	if candidate == "" {
+		return http.StatusBadRequest, errors.New("url is required")
+	}
+	if candidate == mon.URL {
+		return http.StatusBadRequest, errors.New("url is already the monitor's URL")
+	}
+	if err := validateMonitorURL(r.Context(), candidate, h.manager.BlocksPrivateTargets()); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err := h.store.StartMonitorShadow(mon.ID, candidate, checks); err != nil {
+		return http.StatusInternalServerError, errors.New("failed to start shadow checks")
+	}
+	return 0, nil
+}
+
+// loadMonitor writes a 404 or 500 and returns nil when the monitor can't be loaded.
+func (h *CRUDHandler) loadMonitor(w http.ResponseWriter, id string) *db.Monitor {
+	mon, err := h.store.GetMonitor(id)
+	if errors.Is(err, db.ErrMonitorNotFound) {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+		return nil
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return nil
+	}
+	return mon
+}
+
+// StartMonitorShadow checks a candidate URL alongside the monitor's current one for a
+// number of checks, replacing any earlier trial. Shadow results are only recorded for
+// comparison; they never affect the monitor's status or send notifications.
+// @Summary      Start shadow checks
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path string true "Monitor ID"
+// @Param        body  body object{url=string,checks=int} true "Candidate URL and number of checks (default 5)"
+// @Success      201  {object} shadowReport
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/shadow [post]
+func (h *CRUDHandler) StartMonitorShadow(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL    string `json:"url"`
+		Checks *int   `json:"checks"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	checks, err := validateShadowChecks("checks", req.Checks)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	if status, err := h.startShadow(r, mon, req.URL, checks); err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	h.manager.Sync()
+
+	report, err := h.shadowReport(mon)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load shadow checks")
+		return
+	}
+	writeJSON(w, http.StatusCreated, report)
+}
+
+// GetMonitorShadow reports a monitor's shadow trial: the paired checks so far and how
+// the candidate URL compares with the current one.
+// @Summary      Get shadow check comparison
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} shadowReport
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/shadow [get]
+func (h *CRUDHandler) GetMonitorShadow(w http.ResponseWriter, r *http.Request) {
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	report, err := h.shadowReport(mon)
+	if errors.Is(err, db.ErrShadowNotFound) {
+		writeError(w, http.StatusNotFound, "no shadow checks for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load shadow checks")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// CancelMonitorShadow ends a monitor's shadow trial without changing its URL.
+// @Summary      Cancel shadow checks
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/shadow [delete]
+func (h *CRUDHandler) CancelMonitorShadow(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deleted, err := h.store.DeleteMonitorShadow(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to cancel shadow checks")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "no shadow checks for this monitor")
+		return
+	}
+	h.manager.Sync()
+	writeJSON(w, http.StatusOK, map[string]string{"message": "shadow checks cancelled"})
+}
+
+// PromoteMonitorShadow switches the monitor to its shadow trial's URL. Unless force is
+// set, the trial must have completed with every check agreeing.
+// @Summary      Promote shadow URL
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path  string true  "Monitor ID"
+// @Param        force  query bool   false "Promote even if the trial is pending or mismatched"
+// @Success      200  {object} object{message=string,url=string}
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} shadowMismatchResponse
+// @Router       /monitors/{id}/shadow/promote [post]
+func (h *CRUDHandler) PromoteMonitorShadow(w http.ResponseWriter, r *http.Request) {
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	report, err := h.shadowReport(mon)
+	if errors.Is(err, db.ErrShadowNotFound) {
+		writeError(w, http.StatusNotFound, "no shadow checks for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load shadow checks")
+		return
+	}
+	if report.Comparison.Verdict != uptime.ShadowVerdictMatch && r.URL.Query().Get("force") != "true" {
+		writeJSON(w, http.StatusConflict, shadowMismatchResponse{
+			ErrorResponse: errorBody(w, ErrCodeShadowMismatch, "shadow checks are "+report.Comparison.Verdict+"; pass force=true to switch anyway"),
+			Comparison:    report.Comparison,
+		})
+		return
+	}
+
+	url, err := h.store.PromoteMonitorShadow(mon.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to switch monitor URL")
+		return
+	}
+	h.manager.Sync()
+
+	log.Printf("AUDIT: [MONITOR] Monitor %s switched to shadow URL %s (%s)", sanitizeLog(mon.ID), sanitizeLog(url), report.Comparison.Verdict) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "monitor URL updated", "url": url})
+}
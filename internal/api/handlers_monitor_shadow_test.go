@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func setupShadowTest(t *testing.T) (*db.Store, http.Handler) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "http://old.example.com", Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	r.Get("/api/monitors/{id}/shadow", crudH.GetMonitorShadow)
+	r.Post("/api/monitors/{id}/shadow", crudH.StartMonitorShadow)
+	r.Delete("/api/monitors/{id}/shadow", crudH.CancelMonitorShadow)
+	r.Post("/api/monitors/{id}/shadow/promote", crudH.PromoteMonitorShadow)
+	return s, r
+}
+
+func doShadowRequest(router http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(method, path, &buf))
+	return rr
+}
+
+func TestUpdateMonitorStartsShadowChecks(t *testing.T) {
+	s, router := setupShadowTest(t)
+
+	rr := doShadowRequest(router, "PUT", "/api/monitors/m1", map[string]any{
+		"name": "API v2", "url": "http://new.example.com", "interval": 60, "shadowChecks": 3,
+	})
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var report shadowReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Shadow.URL != "http://new.example.com" || report.Shadow.TargetChecks != 3 || report.Comparison.Verdict != "pending" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	// Other fields are applied; the URL waits for promotion
+	mon, _ := s.GetMonitor("m1")
+	if mon.Name != "API v2" || mon.URL != "http://old.example.com" {
+		t.Errorf("Expected new name and old URL, got %q %q", mon.Name, mon.URL)
+	}
+
+	if rr := doShadowRequest(router, "PUT", "/api/monitors/m1", map[string]any{
+		"name": "API v2", "url": "http://new.example.com", "shadowChecks": 0,
+	}); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for shadowChecks 0, got %d", rr.Code)
+	}
+}
+
+func TestMonitorShadowPromotion(t *testing.T) {
+	s, router := setupShadowTest(t)
+
+	if rr := doShadowRequest(router, "GET", "/api/monitors/m1/shadow", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a trial, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow", map[string]any{"url": "http://old.example.com"}); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for the current URL, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow", map[string]any{"url": "http://new.example.com", "checks": 1})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Pending trials can't be promoted without force
+	if rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow/promote", nil); rr.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 for a pending trial, got %d", rr.Code)
+	}
+
+	ok := db.ShadowResult{Up: true, StatusCode: 200, Latency: 20}
+	if err := s.AddMonitorShadowSample("m1", db.ShadowSample{Timestamp: time.Now(), Primary: ok, Shadow: ok}); err != nil {
+		t.Fatal(err)
+	}
+	rr = doShadowRequest(router, "GET", "/api/monitors/m1/shadow", nil)
+	var report shadowReport
+	_ = json.Unmarshal(rr.Body.Bytes(), &report)
+	if report.Comparison.Verdict != "match" || len(report.Samples) != 1 || report.CurrentURL != "http://old.example.com" {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+
+	if rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow/promote", nil); rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mon, _ := s.GetMonitor("m1"); mon.URL != "http://new.example.com" {
+		t.Errorf("Expected promoted URL, got %s", mon.URL)
+	}
+	if rr := doShadowRequest(router, "DELETE", "/api/monitors/m1/shadow", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected the trial to end on promotion, got %d", rr.Code)
+	}
+}
+
+func TestMonitorShadowForcePromotion(t *testing.T) {
+	s, router := setupShadowTest(t)
+
+	if rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow", map[string]any{"url": "http://new.example.com"}); rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(router, "POST", "/api/monitors/m1/shadow/promote?force=true", nil); rr.Code != http.StatusOK {
+		t.Fatalf("Expected forced promotion, got %d", rr.Code)
+	}
+	if mon, _ := s.GetMonitor("m1"); mon.URL != "http://new.example.com" {
+		t.Errorf("Expected promoted URL, got %s", mon.URL)
+	}
+}
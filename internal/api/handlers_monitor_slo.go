@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// Latency SLO bounds.
+const (
+	defaultSLOWindowDays = 30
+	maxSLOWindowDays     = 90
+	maxSLOTargetPercent  = 99.99
+	maxSLOThresholdMs    = 60000
+)
+
+// sloReport is a latency SLO with its current attainment and burn rates.
+type sloReport struct {
+	SLO    db.LatencySLO           `json:"slo"`
+	Status uptime.LatencySLOStatus `json:"status"`
+}
+
+func (h *CRUDHandler) sloReport(slo db.LatencySLO) (*sloReport, error) {
+	status, err := h.manager.LatencySLOStatus(slo, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &sloReport{SLO: slo, Status: status}, nil
+}
+
+// GetMonitorSLO reports a monitor's latency SLO, how much of it is attained over its
+// window and how fast its error budget is burning.
+// @Summary      Get latency SLO
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} sloReport
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/slo [get]
+func (h *CRUDHandler) GetMonitorSLO(w http.ResponseWriter, r *http.Request) {
+	slo, err := h.store.GetLatencySLO(chi.URLParam(r, "id"))
+	if errors.Is(err, db.ErrLatencySLONotFound) {
+		writeError(w, http.StatusNotFound, "no latency SLO for this monitor")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load latency SLO")
+		return
+	}
+	report, err := h.sloReport(*slo)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to evaluate latency SLO")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// SetMonitorSLO creates or replaces a monitor's latency SLO: targetPercent of its checks
+// must be up and within thresholdMs over the last windowDays. Replacing an SLO restarts
+// its attainment from the checks still in the window.
+// @Summary      Set latency SLO
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path string true "Monitor ID"
+// @Param        body  body object{thresholdMs=int,targetPercent=number,windowDays=int} true "Latency threshold, target and window (default 30 days)"
+// @Success      200  {object} sloReport
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/slo [put]
+func (h *CRUDHandler) SetMonitorSLO(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ThresholdMs   int64   `json:"thresholdMs"`
+		TargetPercent float64 `json:"targetPercent"`
+		WindowDays    *int    `json:"windowDays"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.ThresholdMs < 1 || req.ThresholdMs > maxSLOThresholdMs {
+		writeError(w, http.StatusBadRequest, "thresholdMs must be between 1 and 60000")
+		return
+	}
+	if req.TargetPercent <= 0 || req.TargetPercent > maxSLOTargetPercent {
+		writeError(w, http.StatusBadRequest, "targetPercent must be above 0 and at most 99.99")
+		return
+	}
+	windowDays := defaultSLOWindowDays
+	if req.WindowDays != nil {
+		if *req.WindowDays < 1 || *req.WindowDays > maxSLOWindowDays {
+			writeError(w, http.StatusBadRequest, "windowDays must be between 1 and 90")
+			return
+		}
+		windowDays = *req.WindowDays
+	}
+
+	mon := h.loadMonitor(w, chi.URLParam(r, "id"))
+	if mon == nil {
+		return
+	}
+	if mon.Type == db.MonitorTypeComposite {
+		writeError(w, http.StatusBadRequest, "composite monitors have no latency to measure")
+		return
+	}
+
+	if err := h.store.SetLatencySLO(db.LatencySLO{
+		MonitorID:     mon.ID,
+		ThresholdMs:   req.ThresholdMs,
+		TargetPercent: req.TargetPercent,
+		WindowDays:    windowDays,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save latency SLO")
+		return
+	}
+	slo, err := h.store.GetLatencySLO(mon.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load latency SLO")
+		return
+	}
+	report, err := h.sloReport(*slo)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to evaluate latency SLO")
+		return
+	}
+
+	log.Printf("AUDIT: [MONITOR] Monitor %s latency SLO set to %.2f%% within %dms over %dd", sanitizeLog(mon.ID), slo.TargetPercent, slo.ThresholdMs, slo.WindowDays) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, report)
+}
+
+// DeleteMonitorSLO removes a monitor's latency SLO and stops its burn alerts.
+// @Summary      Delete latency SLO
+// @Tags         monitors
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/slo [delete]
+func (h *CRUDHandler) DeleteMonitorSLO(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	deleted, err := h.store.DeleteLatencySLO(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete latency SLO")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "no latency SLO for this monitor")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s latency SLO deleted", sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "latency SLO deleted"})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitorSLO(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: now.Add(-3 * time.Minute), StatusCode: 200},
+		{MonitorID: "m1", Status: "up", Latency: 900, Timestamp: now.Add(-2 * time.Minute), StatusCode: 200},
+		{MonitorID: "m1", Status: "down", Latency: 0, Timestamp: now.Add(-time.Minute), StatusCode: 503},
+		{MonitorID: "m1", Status: "up", Latency: 200, Timestamp: now.Add(-30 * time.Second), StatusCode: 200},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/slo", crudH.GetMonitorSLO)
+	r.Put("/api/monitors/{id}/slo", crudH.SetMonitorSLO)
+	r.Delete("/api/monitors/{id}/slo", crudH.DeleteMonitorSLO)
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/m1/slo", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without an SLO, got %d", rr.Code)
+	}
+	for _, body := range []map[string]any{
+		{"thresholdMs": 0, "targetPercent": 99},
+		{"thresholdMs": 300, "targetPercent": 100},
+		{"thresholdMs": 300, "targetPercent": 0},
+		{"thresholdMs": 300, "targetPercent": 99, "windowDays": 91},
+	} {
+		if rr := doShadowRequest(r, "PUT", "/api/monitors/m1/slo", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/monitors/missing/slo", map[string]any{"thresholdMs": 300, "targetPercent": 99}); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown monitor, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(r, "PUT", "/api/monitors/m1/slo", map[string]any{"thresholdMs": 300, "targetPercent": 95})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var report sloReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.SLO.WindowDays != 30 || report.SLO.ThresholdMs != 300 {
+		t.Errorf("Expected the default 30 day window, got %+v", report.SLO)
+	}
+	// The slow and the down check both miss the objective
+	if report.Status.Checks != 4 || report.Status.GoodChecks != 2 || !report.Status.Burning {
+		t.Errorf("Expected 2 of 4 good checks and a burning SLO, got %+v", report.Status)
+	}
+
+	if rr := doShadowRequest(r, "GET", "/api/monitors/m1/slo", nil); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/slo", nil); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/monitors/m1/slo", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 after deleting, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// maxWatchersPerMonitor caps the watchers of a single monitor.
+const maxWatchersPerMonitor = 20
+
+// ListWatchers returns the watchers of a monitor: channels that receive only its
+// notifications.
+// @Summary      List monitor watchers
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{watchers=[]db.NotificationChannel}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/watchers [get]
+func (h *NotificationChannelsHandler) ListWatchers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.monitorExists(w, id) {
+		return
+	}
+	watchers, err := h.store.GetMonitorWatchers(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch watchers")
+		return
+	}
+	if watchers == nil {
+		watchers = []db.NotificationChannel{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"watchers": watchers})
+}
+
+// CreateWatcher adds a watcher to a monitor: a Slack or webhook channel that receives the
+// monitor's notifications as they happen, apart from the global channels and the digest.
+// @Summary      Watch a monitor
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Param        body body object{type=string,name=string,config=object,enabled=bool} true "Channel config"
+// @Success      201  {object} db.NotificationChannel
+// @Failure      400  {object} ErrorResponse "Invalid body or too many watchers"
+// @Failure      404  {object} ErrorResponse
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
+// @Router       /monitors/{id}/watchers [post]
+func (h *NotificationChannelsHandler) CreateWatcher(w http.ResponseWriter, r *http.Request) {
+	monitorID := chi.URLParam(r, "id")
+	var body struct {
+		Type    string                 `json:"type"`
+		Name    string                 `json:"name"`
+		Config  map[string]interface{} `json:"config"`
+		Enabled *bool                  `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid body")
+		return
+	}
+	if validateChannel(body.Type, body.Name, body.Config).write(w) {
+		return
+	}
+	configBytes, err := json.Marshal(body.Config)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid config")
+		return
+	}
+
+	if !h.monitorExists(w, monitorID) {
+		return
+	}
+	watchers, err := h.store.GetMonitorWatchers(monitorID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch watchers")
+		return
+	}
+	if len(watchers) >= maxWatchersPerMonitor {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("a monitor can have at most %d watchers", maxWatchersPerMonitor))
+		return
+	}
+
+	watcher := db.NotificationChannel{
+		ID:        "nc-" + generateRandomString(8),
+		Type:      body.Type,
+		Name:      body.Name,
+		Config:    string(configBytes),
+		Enabled:   body.Enabled == nil || *body.Enabled,
+		MonitorID: monitorID,
+	}
+	if err := h.store.CreateNotificationChannel(watcher); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create watcher")
+		return
+	}
+	watcher.CreatedAt = time.Now()
+
+	log.Printf("AUDIT: [MONITOR] Monitor %s watched by %s (%s)", sanitizeLog(monitorID), sanitizeLog(watcher.Name), sanitizeLog(watcher.Type)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusCreated, watcher)
+}
+
+// DeleteWatcher removes a watcher from a monitor.
+// @Summary      Stop watching a monitor
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path string true "Monitor ID"
+// @Param        watcherId  path string true "Watcher ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/watchers/{watcherId} [delete]
+func (h *NotificationChannelsHandler) DeleteWatcher(w http.ResponseWriter, r *http.Request) {
+	monitorID, watcherID := chi.URLParam(r, "id"), chi.URLParam(r, "watcherId")
+	deleted, err := h.store.DeleteMonitorWatcher(monitorID, watcherID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete watcher")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "watcher not found")
+		return
+	}
+	log.Printf("AUDIT: [MONITOR] Monitor %s watcher %s removed", sanitizeLog(monitorID), sanitizeLog(watcherID)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "watcher removed"})
+}
+
+// monitorExists writes a 404 or 500 and returns false when the monitor can't be loaded.
+func (h *NotificationChannelsHandler) monitorExists(w http.ResponseWriter, id string) bool {
+	_, err := h.store.GetMonitor(id)
+	if errors.Is(err, db.ErrMonitorNotFound) {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+		return false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitorWatchers(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	h := NewNotificationChannelsHandler(s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-api", GroupID: "g-default", Name: "API", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+
+	call := func(handler http.HandlerFunc, method, monitorID, watcherID, body string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", monitorID)
+		rctx.URLParams.Add("watcherId", watcherID)
+		req := httptest.NewRequest(method, "/api/monitors/"+monitorID+"/watchers", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	body := `{"type":"webhook","name":"Payments team","config":{"webhookUrl":"https://example.com/hook"}}`
+	w := call(h.CreateWatcher, "POST", "m-api", "", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var watcher db.NotificationChannel
+	_ = json.NewDecoder(w.Body).Decode(&watcher)
+	if watcher.MonitorID != "m-api" || !watcher.Enabled {
+		t.Errorf("expected an enabled watcher of m-api, got %+v", watcher)
+	}
+
+	w = call(h.ListWatchers, "GET", "m-api", "", "")
+	var list struct {
+		Watchers []db.NotificationChannel `json:"watchers"`
+	}
+	_ = json.NewDecoder(w.Body).Decode(&list)
+	if w.Code != http.StatusOK || len(list.Watchers) != 1 || list.Watchers[0].ID != watcher.ID {
+		t.Fatalf("expected the watcher to be listed, got %d %+v", w.Code, list)
+	}
+
+	// Watchers are not global channels
+	req := httptest.NewRequest("GET", "/api/notifications/channels", nil)
+	rr := httptest.NewRecorder()
+	h.GetChannels(rr, req)
+	var channels map[string][]db.NotificationChannel
+	_ = json.NewDecoder(rr.Body).Decode(&channels)
+	if len(channels["channels"]) != 0 {
+		t.Errorf("expected no global channels, got %+v", channels["channels"])
+	}
+
+	if w := call(h.CreateWatcher, "POST", "m-nope", "", body); w.Code != http.StatusNotFound {
+		t.Errorf("unknown monitor: expected 404, got %d", w.Code)
+	}
+	if w := call(h.CreateWatcher, "POST", "m-api", "", `{"type":"webhook","name":"Bad","config":{}}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("missing URL: expected 422, got %d", w.Code)
+	}
+
+	if w := call(h.DeleteWatcher, "DELETE", "m-api", "nc-nope", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown watcher: expected 404, got %d", w.Code)
+	}
+	if w := call(h.DeleteWatcher, "DELETE", "m-api", watcher.ID, ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if watchers, _ := s.GetMonitorWatchers("m-api"); len(watchers) != 0 {
+		t.Errorf("expected the watcher to be removed, got %+v", watchers)
+	}
+}
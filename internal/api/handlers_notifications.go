@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -22,20 +23,27 @@ func NewNotificationChannelsHandler(store *db.Store) *NotificationChannelsHandle
 	return &NotificationChannelsHandler{store: store}
 }
 
-// GetChannels returns all configured notification channels.
+// GetChannels returns all configured notification channels, leaving out monitor watchers.
 // @Summary      List notification channels
 // @Tags         notifications
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object} object{channels=[]db.NotificationChannel}
-// @Failure      500  {string} string "Failed to fetch channels"
+// @Failure      500  {object} ErrorResponse "Failed to fetch channels"
 // @Router       /notifications/channels [get]
 func (h *NotificationChannelsHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
 	channels, err := h.store.GetNotificationChannels()
 	if err != nil {
-		http.Error(w, "Failed to fetch channels", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch channels")
 		return
 	}
+	global := make([]db.NotificationChannel, 0, len(channels))
+	for _, c := range channels {
+		if c.MonitorID == "" {
+			global = append(global, c)
+		}
+	}
+	channels = global
 	// Return as array directly to match frontend expectation or map?
 	// Frontend expects { channels: [] } ? Actually frontend likely expects array or wrapper.
 	// Store previously returned map for settings. Let's stick to wrapper.
@@ -50,7 +58,8 @@ func (h *NotificationChannelsHandler) GetChannels(w http.ResponseWriter, r *http
 // @Security     BearerAuth
 // @Param        body body object{type=string,name=string,config=object,enabled=bool} true "Channel config"
 // @Success      201  {object} db.NotificationChannel
-// @Failure      400  {string} string "Type and Name are required"
+// @Failure      400  {object} ErrorResponse "Invalid body"
+// @Failure      422  {object} ValidationErrorResponse "Invalid fields"
 // @Router       /notifications/channels [post]
 func (h *NotificationChannelsHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
 	var body struct {
@@ -61,33 +70,17 @@ func (h *NotificationChannelsHandler) CreateChannel(w http.ResponseWriter, r *ht
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid body", http.StatusBadRequest)
-		return
-	}
-
-	if body.Type == "" || body.Name == "" {
-		http.Error(w, "Type and Name are required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid body")
 		return
 	}
 
-	// SECURITY: Validate name length
-	if len(body.Name) > 255 {
-		http.Error(w, "Name too long (max 255 characters)", http.StatusBadRequest)
+	if validateChannel(body.Type, body.Name, body.Config).write(w) {
 		return
 	}
 
-	// SECURITY: Validate webhook URL for channel types that use it
-	if body.Type == "slack" || body.Type == "webhook" {
-		webhookURL := extractWebhookURL(body.Config)
-		if _, err := validateWebhookURL(webhookURL); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-
 	configBytes, err := json.Marshal(body.Config)
 	if err != nil {
-		http.Error(w, "Invalid config", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid config")
 		return
 	}
 
@@ -103,7 +96,7 @@ func (h *NotificationChannelsHandler) CreateChannel(w http.ResponseWriter, r *ht
 	}
 
 	if err := h.store.CreateNotificationChannel(channel); err != nil {
-		http.Error(w, "Failed to create channel", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to create channel")
 		return
 	}
 
@@ -119,23 +112,44 @@ func (h *NotificationChannelsHandler) CreateChannel(w http.ResponseWriter, r *ht
 // @Security     BearerAuth
 // @Param        id   path string true "Channel ID"
 // @Success      200  "OK"
-// @Failure      400  {string} string "Missing ID"
+// @Failure      400  {object} ErrorResponse "Missing ID"
 // @Router       /notifications/channels/{id} [delete]
 func (h *NotificationChannelsHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "Missing ID", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Missing ID")
 		return
 	}
 
 	if err := h.store.DeleteNotificationChannel(id); err != nil {
-		http.Error(w, "Failed to delete channel", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to delete channel")
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// validateChannel checks the fields of a notification channel.
+func validateChannel(channelType, name string, config map[string]interface{}) validationErrors {
+	errs := validationErrors{}
+	if channelType == "" {
+		errs.add("type", "Type is required")
+	}
+	validateName(errs, name)
+	// SECURITY: Validate webhook URL for channel types that use it
+	if channelType == "slack" || channelType == "webhook" {
+		if _, err := validateWebhookURL(extractWebhookURL(config)); err != nil {
+			errs.add("config.webhookUrl", "%s", err.Error())
+		}
+	}
+	if locale, ok := config["locale"]; ok {
+		if l, _ := locale.(string); !notifications.IsLocale(l) {
+			errs.add("config.locale", "Locale must be one of %s", strings.Join(notifications.Locales(), ", "))
+		}
+	}
+	return errs
+}
+
 // validateWebhookURL checks that a URL is valid HTTP(S) and within length limits.
 func validateWebhookURL(rawURL string) (string, error) {
 	if rawURL == "" {
@@ -169,7 +183,7 @@ func extractWebhookURL(config map[string]interface{}) string {
 func (h *NotificationChannelsHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "Missing ID", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Missing ID")
 		return
 	}
 
@@ -181,37 +195,22 @@ func (h *NotificationChannelsHandler) UpdateChannel(w http.ResponseWriter, r *ht
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid body")
 		return
 	}
 
-	if body.Type == "" || body.Name == "" {
-		http.Error(w, "Type and Name are required", http.StatusBadRequest)
+	if validateChannel(body.Type, body.Name, body.Config).write(w) {
 		return
 	}
 
-	if len(body.Name) > 255 {
-		http.Error(w, "Name too long (max 255 characters)", http.StatusBadRequest)
-		return
-	}
-
-	// Validate webhook URL for types that use it
-	if body.Type == "slack" || body.Type == "webhook" {
-		webhookURL := extractWebhookURL(body.Config)
-		if _, err := validateWebhookURL(webhookURL); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-
 	configBytes, err := json.Marshal(body.Config)
 	if err != nil {
-		http.Error(w, "Invalid config", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid config")
 		return
 	}
 
 	if err := h.store.UpdateNotificationChannel(id, body.Name, body.Type, string(configBytes), body.Enabled); err != nil {
-		http.Error(w, "Failed to update channel", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to update channel")
 		return
 	}
 
@@ -232,25 +231,25 @@ func (h *NotificationChannelsHandler) TestChannel(w http.ResponseWriter, r *http
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid body")
 		return
 	}
 
 	if body.Type == "" {
-		http.Error(w, "Type is required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Type is required")
 		return
 	}
 
 	// Validate webhook URL
 	webhookURL := extractWebhookURL(body.Config)
 	if _, err := validateWebhookURL(webhookURL); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	configBytes, err := json.Marshal(body.Config)
 	if err != nil {
-		http.Error(w, "Invalid config", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid config")
 		return
 	}
 
@@ -264,7 +263,7 @@ func (h *NotificationChannelsHandler) TestChannel(w http.ResponseWriter, r *http
 	}
 
 	if err := notifications.SendDirect(body.Type, string(configBytes), testEvent); err != nil {
-		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "Test failed: " + err.Error()})
+		writeErrorCode(w, http.StatusBadGateway, ErrCodeChannelTestFailed, "Test failed: "+err.Error())
 		return
 	}
 
@@ -176,22 +176,22 @@ func TestUpdateChannel_ValidationErrors(t *testing.T) {
 		{
 			name:       "missing type",
 			payload:    map[string]interface{}{"name": "Test", "config": map[string]string{"webhookUrl": "http://example.com"}},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name:       "missing name",
 			payload:    map[string]interface{}{"type": "slack", "config": map[string]string{"webhookUrl": "http://example.com"}},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name:       "invalid URL scheme",
 			payload:    map[string]interface{}{"type": "slack", "name": "Test", "config": map[string]string{"webhookUrl": "ftp://example.com"}},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name:       "empty webhook URL",
 			payload:    map[string]interface{}{"type": "webhook", "name": "Test", "config": map[string]string{"webhookUrl": ""}},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
 		},
 	}
 
@@ -254,7 +254,7 @@ func TestCreateChannel_WebhookValidation(t *testing.T) {
 				"type": "webhook", "name": "Test",
 				"config": map[string]string{},
 			},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "invalid scheme",
@@ -262,7 +262,23 @@ func TestCreateChannel_WebhookValidation(t *testing.T) {
 				"type": "webhook", "name": "Test",
 				"config": map[string]string{"webhookUrl": "ftp://bad.example.com"},
 			},
-			wantStatus: http.StatusBadRequest,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown locale",
+			payload: map[string]interface{}{
+				"type": "webhook", "name": "Test",
+				"config": map[string]string{"webhookUrl": "https://my-api.com/webhook", "locale": "xx"},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "locale",
+			payload: map[string]interface{}{
+				"type": "webhook", "name": "Test",
+				"config": map[string]string{"webhookUrl": "https://my-api.com/webhook", "locale": "de"},
+			},
+			wantStatus: http.StatusCreated,
 		},
 	}
 
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Context loaded around an outage by GetOutage
+const (
+	outageContextMargin = 5 * time.Minute // checks and events just before and after it
+	maxOutageChecks     = 500
+	maxOutageEvents     = 100
+)
+
+// OutageListResponse is a page of outages.
+type OutageListResponse struct {
+	Outages []db.MonitorOutage `json:"outages"`
+}
+
+// OutageIncidentLink names the incident an outage was promoted to.
+type OutageIncidentLink struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Public bool   `json:"public"`
+}
+
+// OutageDetailResponse is an outage with what happened around it.
+type OutageDetailResponse struct {
+	db.MonitorOutage
+	Checks          []db.CheckResult          `json:"checks"` // Oldest first, from just before it started until just after it ended
+	Events          []db.MonitorEvent         `json:"events"` // Oldest first, over the same window
+	Acknowledgments []db.OutageAcknowledgment `json:"acknowledgments"`
+	Incident        *OutageIncidentLink       `json:"incident"` // Set once promoted
+}
+
+// ListOutages returns active and resolved outages, latest first.
+// @Summary      List outages
+// @Tags         incidents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status     query string false "active or resolved (default both)"
+// @Param        monitor_id query string false "Only outages of this monitor"
+// @Param        group_id   query string false "Only outages of monitors in this group"
+// @Param        since      query string false "Only outages ongoing at or after this time (RFC 3339)"
+// @Param        until      query string false "Only outages that started before this time (RFC 3339)"
+// @Param        limit      query int    false "Maximum outages (default 100, max 1000)"
+// @Success      200  {object} OutageListResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /outages [get]
+func (h *IncidentHandler) ListOutages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := db.OutageFilter{MonitorID: q.Get("monitor_id"), GroupID: q.Get("group_id"), Limit: db.DefaultOutagesLimit}
+
+	switch status := q.Get("status"); status {
+	case "", db.OutageStatusActive, db.OutageStatusResolved:
+		f.Status = status
+	default:
+		writeError(w, http.StatusBadRequest, "status must be active or resolved")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > db.MaxOutagesLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", db.MaxOutagesLimit))
+			return
+		}
+		f.Limit = n
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, p.name+" must be an RFC 3339 time")
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		writeError(w, http.StatusBadRequest, "since must be before until")
+		return
+	}
+
+	outages, err := h.store.GetOutages(f)
+	if err != nil {
+		log.Printf("ERROR: Failed to list outages: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list outages")
+		return
+	}
+	estimateOutageCosts(h.store, outages, time.Now())
+	writeJSON(w, http.StatusOK, OutageListResponse{Outages: outages})
+}
+
+// GetOutage returns an outage with the response evidence captured when it opened, its
+// checks and events, who acknowledged it, and the incident it was promoted to.
+// @Summary      Get outage
+// @Tags         incidents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Outage ID"
+// @Success      200  {object} OutageDetailResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /outages/{id} [get]
+func (h *IncidentHandler) GetOutage(w http.ResponseWriter, r *http.Request) {
+	outage, ok := h.loadOutage(w, r)
+	if !ok {
+		return
+	}
+
+	// SECURITY: Evidence may contain response bodies of internal services, so it is
+	// only exposed on this authenticated endpoint and never on public status pages.
+	now := time.Now()
+	outages := []db.MonitorOutage{*outage}
+	estimateOutageCosts(h.store, outages, now)
+	resp := OutageDetailResponse{MonitorOutage: outages[0]}
+	since := outage.StartTime.Add(-outageContextMargin)
+	until := now
+	if outage.EndTime != nil {
+		until = outage.EndTime.Add(outageContextMargin)
+	}
+	var err error
+	if resp.Checks, err = h.store.GetChecksBetween(outage.MonitorID, since, until, maxOutageChecks); err != nil {
+		log.Printf("ERROR: Failed to get outage checks: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to get outage")
+		return
+	}
+	if resp.Events, err = h.store.GetMonitorEventsBetween(outage.MonitorID, since, until, maxOutageEvents); err != nil {
+		log.Printf("ERROR: Failed to get outage events: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to get outage")
+		return
+	}
+	if resp.Acknowledgments, err = h.store.GetOutageAcknowledgments(outage.ID); err != nil {
+		log.Printf("ERROR: Failed to get outage acknowledgments: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to get outage")
+		return
+	}
+	inc, err := h.store.GetIncidentByOutageID(outage.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get outage incident: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to get outage")
+		return
+	}
+	if inc != nil {
+		resp.Incident = &OutageIncidentLink{ID: inc.ID, Title: inc.Title, Status: inc.Status, Public: inc.Public}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// AcknowledgeOutage records that the current user is looking into an outage.
+// @Summary      Acknowledge outage
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Outage ID"
+// @Param        body body object{note=string} false "Optional note"
+// @Success      201  {object} db.OutageAcknowledgment
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /outages/{id}/acknowledge [post]
+func (h *IncidentHandler) AcknowledgeOutage(w http.ResponseWriter, r *http.Request) {
+	outage, ok := h.loadOutage(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > db.MaxOutageAckNoteLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("note must be at most %d characters", db.MaxOutageAckNoteLength))
+		return
+	}
+
+	ack := db.OutageAcknowledgment{OutageID: outage.ID, Note: req.Note, CreatedAt: time.Now()}
+	ack.UserID, _ = r.Context().Value(contextKeyUserID).(int64)
+	if user, err := h.store.GetUser(ack.UserID); err == nil && user != nil {
+		ack.Username = user.Username
+	}
+	id, err := h.store.AcknowledgeOutage(ack)
+	if err != nil {
+		log.Printf("ERROR: Failed to acknowledge outage: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to acknowledge outage")
+		return
+	}
+	ack.ID = id
+
+	log.Printf("AUDIT: [OUTAGE] User %d acknowledged outage %d of monitor %s", ack.UserID, outage.ID, sanitizeLog(outage.MonitorID)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusCreated, ack)
+}
+
+// loadOutage returns the outage named by the id URL parameter, writing an error response
+// and returning false if there is none.
+func (h *IncidentHandler) loadOutage(w http.ResponseWriter, r *http.Request) (*db.MonitorOutage, bool) {
+	outageID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid outage ID")
+		return nil, false
+	}
+	outage, err := h.store.GetOutageByID(outageID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get outage: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to get outage")
+		return nil, false
+	}
+	if outage == nil {
+		writeError(w, http.StatusNotFound, "outage not found")
+		return nil, false
+	}
+	return outage, true
+}
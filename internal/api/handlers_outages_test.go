@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestListOutages(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewIncidentHandler(s, uptime.NewManager(s))
+	_ = s.CreateGroup(db.Group{ID: "g-api", Name: "API"})
+	_ = s.CreateMonitor(db.Monitor{ID: "m-web", GroupID: "g-default", Name: "Web", URL: "http://web", Interval: 60})
+	_ = s.CreateMonitor(db.Monitor{ID: "m-api", GroupID: "g-api", Name: "API", URL: "http://api", Interval: 60})
+	now := time.Now().UTC().Truncate(time.Second)
+	_, _ = s.CreateClosedOutage("m-web", "down", "web down", "", now.Add(-48*time.Hour), now.Add(-47*time.Hour))
+	_ = s.CreateOutageAt("m-api", "down", "api down", "", nil, now.Add(-time.Hour))
+
+	list := func(query string) (int, []string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ListOutages(w, httptest.NewRequest("GET", "/api/outages"+query, nil))
+		var resp OutageListResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		var got []string
+		for _, o := range resp.Outages {
+			got = append(got, o.Summary)
+		}
+		return w.Code, got
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "api down,web down"},
+		{"?status=active", "api down"},
+		{"?status=resolved", "web down"},
+		{"?monitor_id=m-web", "web down"},
+		{"?group_id=g-api", "api down"},
+		{"?since=" + now.Add(-2*time.Hour).Format(time.RFC3339), "api down"},
+		{"?until=" + now.Add(-2*time.Hour).Format(time.RFC3339), "web down"},
+		{"?limit=1", "api down"},
+	}
+	for _, tt := range tests {
+		code, got := list(tt.query)
+		if code != http.StatusOK || strings.Join(got, ",") != tt.want {
+			t.Errorf("%q: expected 200 %q, got %d %q", tt.query, tt.want, code, strings.Join(got, ","))
+		}
+	}
+
+	for _, query := range []string{"?status=open", "?limit=0", "?since=yesterday",
+		"?since=" + now.Format(time.RFC3339) + "&until=" + now.Add(-time.Hour).Format(time.RFC3339)} {
+		if code, _ := list(query); code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, code)
+		}
+	}
+}
+
+func TestOutageDetailAndAcknowledge(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewIncidentHandler(s, uptime.NewManager(s))
+	_ = s.CreateMonitor(db.Monitor{ID: "m-1", GroupID: "g-default", Name: "Web", URL: "http://web", Interval: 60})
+	_ = s.CreateUser("alice", "password123", "UTC")
+	user, _ := s.Authenticate("alice", "password123")
+	start := time.Now().UTC().Add(-10 * time.Minute).Truncate(time.Second)
+	_ = s.CreateOutageAt("m-1", "down", "Connection refused", "", nil, start)
+	_ = s.CreateEventAt("m-1", "down", "Monitor is down", start)
+	_ = s.BatchInsertChecks([]db.CheckResult{{MonitorID: "m-1", Status: "down", Timestamp: start}})
+	outages, _ := s.GetActiveOutages()
+	if len(outages) != 1 {
+		t.Fatalf("Expected one outage, got %d", len(outages))
+	}
+	id := strconv.FormatInt(outages[0].ID, 10)
+
+	r := chi.NewRouter()
+	r.Get("/api/outages/{id}", h.GetOutage)
+	r.Post("/api/outages/{id}/acknowledge", h.AcknowledgeOutage)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, user.ID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	detail := func() OutageDetailResponse {
+		t.Helper()
+		w := do("GET", "/api/outages/"+id, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp OutageDetailResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	got := detail()
+	if got.Summary != "Connection refused" || len(got.Checks) != 1 || len(got.Events) != 1 ||
+		len(got.Acknowledgments) != 0 || got.Incident != nil {
+		t.Errorf("Unexpected detail %+v", got)
+	}
+
+	if w := do("POST", "/api/outages/"+id+"/acknowledge", `{"note":" on it "}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/outages/"+id+"/acknowledge", ""); w.Code != http.StatusCreated {
+		t.Errorf("Expected an acknowledgment without a body to be accepted, got %d", w.Code)
+	}
+	if w := do("POST", "/api/outages/"+id+"/acknowledge", `{"note":"`+strings.Repeat("x", db.MaxOutageAckNoteLength+1)+`"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a long note, got %d", w.Code)
+	}
+	if w := do("POST", "/api/outages/999/acknowledge", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+
+	outageID := outages[0].ID
+	_ = s.CreateIncident(db.Incident{ID: "inc-1", Title: "Web down", Type: "incident", Status: "investigating", StartTime: start, OutageID: &outageID})
+	got = detail()
+	if len(got.Acknowledgments) != 2 || got.Acknowledgments[0].Username != "alice" || got.Acknowledgments[0].Note != "on it" {
+		t.Errorf("Unexpected acknowledgments %+v", got.Acknowledgments)
+	}
+	if got.Incident == nil || got.Incident.ID != "inc-1" || got.Incident.Title != "Web down" {
+		t.Errorf("Expected the promoted incident to be linked, got %+v", got.Incident)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// probeModules are the blackbox exporter modules a probe can run, by name.
+var probeModules = map[string]db.RequestConfig{
+	"http_2xx":      {Method: http.MethodGet, AcceptedStatusCodes: "200-299"},
+	"http_post_2xx": {Method: http.MethodPost, AcceptedStatusCodes: "200-299"},
+}
+
+// Probe checks target once and returns the result as Prometheus metrics named like the
+// blackbox exporter's, so Prometheus can scrape Warden as a blackbox prober with the
+// usual relabeling. A failed check is still a 200 response with probe_success 0.
+// @Summary      Probe a target for Prometheus
+// @Tags         monitors
+// @Produce      plain
+// @Security     BearerAuth
+// @Param        target query string true  "URL to check; http:// is assumed without a scheme"
+// @Param        module query string false "http_2xx (default) or http_post_2xx"
+// @Success      200  {string} string "Prometheus text exposition format"
+// @Failure      400  {object} ErrorResponse
+// @Router       /probe [get]
+func (h *CRUDHandler) Probe(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		writeError(w, http.StatusBadRequest, "target is required")
+		return
+	}
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+	module := r.URL.Query().Get("module")
+	if module == "" {
+		module = "http_2xx"
+	}
+	cfg, ok := probeModules[module]
+	if !ok {
+		names := make([]string, 0, len(probeModules))
+		for name := range probeModules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown module %q (use %s)", module, strings.Join(names, ", ")))
+		return
+	}
+	// SECURITY: The probe makes an outbound request from the server, validated like a
+	// monitor URL; the dialer blocks private targets as it does for checks.
+	if err := validateMonitorURL(r.Context(), target, h.manager.BlocksPrivateTargets()); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start := time.Now()
+	res := h.manager.PreviewCheck(target, &cfg, h.manager.GetLatencyThreshold())
+	duration := time.Since(start)
+
+	var b strings.Builder
+	gauge := func(name, help string, value float64, labels ...string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s%s %g\n", name, help, name, name, promLabels(labels...), value)
+	}
+	success := 0.0
+	if res.Status != "down" {
+		success = 1
+	}
+	gauge("probe_success", "Whether the probe succeeded", success)
+	gauge("probe_duration_seconds", "How long the probe took in seconds", duration.Seconds())
+	gauge("probe_http_status_code", "Response HTTP status code", float64(res.StatusCode))
+	if t := res.Timing; t != nil {
+		gauge("probe_dns_lookup_time_seconds", "How long the DNS lookup took in seconds", msToSeconds(t.DNS))
+		fmt.Fprintf(&b, "# HELP probe_http_duration_seconds Duration of the HTTP request by phase in seconds\n# TYPE probe_http_duration_seconds gauge\n")
+		for _, phase := range []struct {
+			name string
+			ms   int64
+		}{{"resolve", t.DNS}, {"connect", t.Connect}, {"tls", t.TLS}, {"processing", t.TTFB}, {"transfer", t.Download}} {
+			fmt.Fprintf(&b, "probe_http_duration_seconds%s %g\n", promLabels("phase", phase.name), msToSeconds(phase.ms))
+		}
+		gauge("probe_http_uncompressed_body_length", "Length of the response body in bytes", float64(t.BodyBytes))
+	}
+	ssl := 0.0
+	if res.TLS != nil {
+		ssl = 1
+	}
+	gauge("probe_http_ssl", "Whether the final response was served over TLS", ssl)
+	if res.TLS != nil && !res.TLS.NotAfter.IsZero() {
+		gauge("probe_ssl_earliest_cert_expiry", "Expiry of the leaf certificate as a Unix timestamp", float64(res.TLS.NotAfter.Unix()))
+		gauge("probe_tls_version_info", "The TLS version of the connection", 1, "version", res.TLS.Version)
+	}
+	if res.ResolvedIP != "" {
+		family := 4.0
+		if strings.Contains(res.ResolvedIP, ":") {
+			family = 6
+		}
+		gauge("probe_ip_protocol", "IP protocol version of the connection", family)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// msToSeconds converts milliseconds to seconds.
+func msToSeconds(v int64) float64 {
+	return float64(v) / 1000
+}
+
+// promLabels formats name/value pairs as a Prometheus label set, e.g. {phase="tls"}.
+func promLabels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+escape.Replace(pairs[i+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	probe := func(query url.Values) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		crudH.Probe(rr, httptest.NewRequest("GET", "/api/probe?"+query.Encode(), nil))
+		return rr
+	}
+
+	rr := probe(url.Values{"target": {target.URL}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus text format, got %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE probe_success gauge\nprobe_success 1\n",
+		"probe_http_status_code 200\n",
+		"probe_http_ssl 0\n",
+		"probe_http_uncompressed_body_length 2\n",
+		`probe_http_duration_seconds{phase="connect"} `,
+		"probe_ip_protocol 4\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in:\n%s", want, body)
+		}
+	}
+
+	// A failed check is reported in the metrics, not the status code
+	rr = probe(url.Values{"target": {target.URL + "/missing"}})
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "probe_success 0\n") || !strings.Contains(rr.Body.String(), "probe_http_status_code 404\n") {
+		t.Errorf("Expected a failed probe, got %d:\n%s", rr.Code, rr.Body.String())
+	}
+	rr = probe(url.Values{"target": {target.URL}, "module": {"http_post_2xx"}})
+	if !strings.Contains(rr.Body.String(), "probe_success 0\n") {
+		t.Errorf("Expected http_post_2xx to POST, got:\n%s", rr.Body.String())
+	}
+	// Without a scheme, http:// is assumed
+	rr = probe(url.Values{"target": {strings.TrimPrefix(target.URL, "http://")}})
+	if !strings.Contains(rr.Body.String(), "probe_success 1\n") {
+		t.Errorf("Expected a target without scheme to be probed over http, got:\n%s", rr.Body.String())
+	}
+
+	for name, query := range map[string]url.Values{
+		"missing target": {},
+		"unknown module": {"target": {target.URL}, "module": {"icmp"}},
+		"invalid scheme": {"target": {"ftp://example.com"}},
+	} {
+		if rr := probe(query); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, rr.Code)
+		}
+	}
+
+	// Nothing is saved
+	if monitors, _ := s.GetMonitors(); len(monitors) != 0 {
+		t.Errorf("Expected no monitors, got %d", len(monitors))
+	}
+}
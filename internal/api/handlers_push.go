@@ -0,0 +1,348 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// maxPushTokenLength bounds device tokens and Web Push endpoint URLs.
+const maxPushTokenLength = 2048
+
+// pushChannelType is the notification channel type that sends to push devices.
+const pushChannelType = "push"
+
+// PushHandler manages the signed-in user's push devices. A "push" notification channel
+// sends outage alerts to every registered device; registering the first device creates
+// one.
+type PushHandler struct {
+	store *db.Store
+}
+
+func NewPushHandler(store *db.Store) *PushHandler {
+	return &PushHandler{store: store}
+}
+
+// pushOwner returns the user whose devices the request manages, or writes an error.
+// Global API keys have no user to own devices.
+func pushOwner(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, ok := r.Context().Value(contextKeyUserID).(int64)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, false
+	}
+	if userID == APIKeyUserID {
+		writeError(w, http.StatusForbidden, "API keys cannot register push devices")
+		return 0, false
+	}
+	return userID, true
+}
+
+// ListDevices returns the current user's push devices.
+// @Summary      List push devices
+// @Tags         push
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{devices=[]db.PushDevice}
+// @Failure      403  {object} ErrorResponse "Requested with a global API key"
+// @Router       /push/devices [get]
+func (h *PushHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pushOwner(w, r)
+	if !ok {
+		return
+	}
+	devices, err := h.store.ListPushDevices(userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list push devices")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"devices": devices})
+}
+
+// RegisterDevice registers a device of the current user for push notifications: a
+// mobile app by its FCM or APNs token, or a browser by its Web Push subscription.
+// Registering a known token again updates it.
+// @Summary      Register push device
+// @Tags         push
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{platform=string,token=string,name=string,keys=object{p256dh=string,auth=string}} true "Platform fcm, apns or web; for web, token is the subscription endpoint and keys its encryption keys"
+// @Success      201  {object} db.PushDevice
+// @Failure      403  {object} ErrorResponse "Requested with a global API key"
+// @Failure      422  {object} ValidationErrorResponse
+// @Router       /push/devices [post]
+func (h *PushHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pushOwner(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Platform string `json:"platform"`
+		Token    string `json:"token"`
+		Name     string `json:"name"`
+		Keys     struct {
+			P256DH string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	errs := validationErrors{}
+	switch req.Platform {
+	case db.PushPlatformFCM, db.PushPlatformAPNs, db.PushPlatformWeb:
+	default:
+		errs.add("platform", "Platform must be fcm, apns or web")
+	}
+	if req.Token == "" {
+		errs.add("token", "Token is required")
+	} else if len(req.Token) > maxPushTokenLength {
+		errs.add("token", "Token too long (max %d characters)", maxPushTokenLength)
+	}
+	if len(req.Name) > maxNameLength {
+		errs.add("name", "Name too long (max %d characters)", maxNameLength)
+	}
+	if req.Platform == db.PushPlatformWeb {
+		validateWebPushSubscription(errs, "token", req.Token, req.Keys.P256DH, req.Keys.Auth)
+	}
+	if errs.write(w) {
+		return
+	}
+
+	device, err := h.store.RegisterPushDevice(db.PushDevice{
+		ID:       "pd-" + generateRandomString(8),
+		UserID:   userID,
+		Platform: req.Platform,
+		Token:    req.Token,
+		P256DH:   req.Keys.P256DH,
+		Auth:     req.Keys.Auth,
+		Name:     req.Name,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to register push device")
+		return
+	}
+	log.Printf("AUDIT: [PUSH] User %d registered %s push device %s", userID, sanitizeLog(device.Platform), sanitizeLog(device.ID)) // #nosec G706 -- sanitized
+	h.ensurePushChannel()
+	writeJSON(w, http.StatusCreated, device)
+}
+
+// ensurePushChannel creates a push channel when there is none, so registered devices
+// are notified without further setup. A disabled push channel is left alone.
+func (h *PushHandler) ensurePushChannel() {
+	channels, err := h.store.GetNotificationChannels()
+	if err != nil {
+		log.Printf("Failed to fetch notification channels: %v", err)
+		return
+	}
+	for _, ch := range channels {
+		if ch.Type == pushChannelType && ch.MonitorID == "" {
+			return
+		}
+	}
+	ch := db.NotificationChannel{ID: "nc-" + generateRandomString(8), Type: pushChannelType, Name: "Push notifications", Config: "{}", Enabled: true}
+	if err := h.store.CreateNotificationChannel(ch); err != nil {
+		log.Printf("Failed to create push channel: %v", err)
+		return
+	}
+	log.Printf("AUDIT: [PUSH] Created notification channel %s for push devices", ch.ID) // #nosec G706 -- generated ID
+}
+
+// Subscribe registers the current user's browser from its PushSubscription, as returned
+// by PushManager.subscribe() and serialized with toJSON().
+// @Summary      Subscribe to Web Push
+// @Tags         push
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{endpoint=string,expirationTime=number,keys=object{p256dh=string,auth=string},name=string} true "PushSubscription JSON, with an optional device name"
+// @Success      201  {object} db.PushDevice
+// @Failure      403  {object} ErrorResponse "Requested with a global API key"
+// @Failure      422  {object} ValidationErrorResponse
+// @Router       /push/subscribe [post]
+func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pushOwner(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Endpoint       string   `json:"endpoint"`
+		ExpirationTime *float64 `json:"expirationTime"` // Milliseconds since the epoch, or null
+		Keys           struct {
+			P256DH string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	errs := validationErrors{}
+	if req.Endpoint == "" {
+		errs.add("endpoint", "Endpoint is required")
+	} else if len(req.Endpoint) > maxPushTokenLength {
+		errs.add("endpoint", "Endpoint too long (max %d characters)", maxPushTokenLength)
+	}
+	if len(req.Name) > maxNameLength {
+		errs.add("name", "Name too long (max %d characters)", maxNameLength)
+	}
+	var expiresAt *time.Time
+	if req.ExpirationTime != nil {
+		t := time.UnixMilli(int64(*req.ExpirationTime))
+		if !t.After(time.Now()) {
+			errs.add("expirationTime", "Subscription has already expired")
+		}
+		expiresAt = &t
+	}
+	validateWebPushSubscription(errs, "endpoint", req.Endpoint, req.Keys.P256DH, req.Keys.Auth)
+	if errs.write(w) {
+		return
+	}
+
+	device, err := h.store.RegisterPushDevice(db.PushDevice{
+		ID:        "pd-" + generateRandomString(8),
+		UserID:    userID,
+		Platform:  db.PushPlatformWeb,
+		Token:     req.Endpoint,
+		P256DH:    req.Keys.P256DH,
+		Auth:      req.Keys.Auth,
+		Name:      req.Name,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save push subscription")
+		return
+	}
+	log.Printf("AUDIT: [PUSH] User %d subscribed browser %s to push notifications", userID, sanitizeLog(device.ID)) // #nosec G706 -- sanitized
+	h.ensurePushChannel()
+	writeJSON(w, http.StatusCreated, device)
+}
+
+// Unsubscribe removes the current user's browser subscription with the given endpoint,
+// e.g. after PushSubscription.unsubscribe().
+// @Summary      Unsubscribe from Web Push
+// @Tags         push
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{endpoint=string} true "Subscription endpoint"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse "Subscription not found"
+// @Router       /push/unsubscribe [post]
+func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pushOwner(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Endpoint == "" {
+		writeError(w, http.StatusBadRequest, "endpoint is required")
+		return
+	}
+	if err := h.store.UnregisterPushDevice(userID, req.Endpoint); err != nil {
+		if errors.Is(err, db.ErrPushDeviceNotFound) {
+			writeError(w, http.StatusNotFound, "push subscription not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete push subscription")
+		return
+	}
+	log.Printf("AUDIT: [PUSH] User %d unsubscribed a browser from push notifications", userID) // #nosec G706 -- numeric ID
+	writeJSON(w, http.StatusOK, map[string]string{"message": "unsubscribed"})
+}
+
+// validateWebPushSubscription checks a browser's push endpoint, reported as field, and
+// its encryption keys.
+func validateWebPushSubscription(errs validationErrors, field, endpoint, p256dh, auth string) {
+	// SECURITY: Notifications are posted to the endpoint, so only HTTPS push services are accepted
+	if u, err := url.ParseRequestURI(endpoint); endpoint != "" && (err != nil || u.Scheme != "https" || u.Host == "") {
+		errs.add(field, "Must be the subscription's HTTPS endpoint")
+	}
+	if err := notifications.ValidateWebPushKeys(p256dh, auth); err != nil {
+		errs.add("keys", "%s", err.Error())
+	}
+}
+
+// DeleteDevice unregisters one of the current user's push devices.
+// @Summary      Delete push device
+// @Tags         push
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Device ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse "Device not found"
+// @Router       /push/devices/{id} [delete]
+func (h *PushHandler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := pushOwner(w, r)
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if err := h.store.DeletePushDevice(userID, id); err != nil {
+		if errors.Is(err, db.ErrPushDeviceNotFound) {
+			writeError(w, http.StatusNotFound, "push device not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete push device")
+		return
+	}
+	log.Printf("AUDIT: [PUSH] User %d unregistered push device %s", userID, sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
+
+// GetVAPIDPublicKey returns the application server key browsers pass to
+// PushManager.subscribe. The key pair is generated on first use.
+// @Summary      Get VAPID public key
+// @Tags         push
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{publicKey=string}
+// @Failure      500  {object} ErrorResponse
+// @Router       /push/vapid-public-key [get]
+func (h *PushHandler) GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
+	key, err := notifications.VAPIDPublicKey(h.store)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load VAPID key")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"publicKey": key})
+}
+
+// RotateVAPIDKey replaces the VAPID key pair, e.g. after the private key leaked. Browser
+// subscriptions are bound to the old key, so they are removed; browsers subscribe again
+// with the new public key.
+// @Summary      Rotate VAPID key
+// @Tags         push
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{publicKey=string,removedSubscriptions=int}
+// @Failure      500  {object} ErrorResponse
+// @Router       /push/vapid/rotate [post]
+func (h *PushHandler) RotateVAPIDKey(w http.ResponseWriter, r *http.Request) {
+	key, err := notifications.RotateVAPIDKey(h.store)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to rotate VAPID key")
+		return
+	}
+	removed, err := h.store.DeletePushDevicesByPlatform(db.PushPlatformWeb)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to remove push subscriptions")
+		return
+	}
+	log.Printf("AUDIT: [PUSH] %s rotated the VAPID key, removing %d browser subscriptions", sanitizeLog(requestActor(r, h.store)), removed) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]any{"publicKey": key, "removedSubscriptions": removed})
+}
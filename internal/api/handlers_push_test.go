@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// doSessionRequest sends a request signed in with the session token.
+func doSessionRequest(router http.Handler, method, path, session string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: session})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPushDevices(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	seedAuthUser(t, s, "alice", "alice-session")
+	seedAuthUser(t, s, "bob", "bob-session")
+	do := func(method, path, session string, body any) *httptest.ResponseRecorder {
+		return doSessionRequest(router, method, path, session, body)
+	}
+
+	rr := do("POST", "/api/push/devices", "alice-session", map[string]any{"platform": "fcm", "token": "fcm-token", "name": "Pixel"})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var phone db.PushDevice
+	_ = json.NewDecoder(rr.Body).Decode(&phone)
+	if phone.ID == "" || phone.Platform != db.PushPlatformFCM || phone.Name != "Pixel" {
+		t.Errorf("unexpected device %+v", phone)
+	}
+
+	// A browser registers its subscription's endpoint and keys
+	key, _ := ecdh.P256().GenerateKey(rand.Reader)
+	keys := map[string]string{
+		"p256dh": base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		"auth":   base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+	}
+	rr = do("POST", "/api/push/devices", "alice-session", map[string]any{"platform": "web", "token": "https://fcm.googleapis.com/fcm/send/abc", "keys": keys})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for a web subscription, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	for name, body := range map[string]map[string]any{
+		"unknown platform": {"platform": "sms", "token": "x"},
+		"missing token":    {"platform": "apns"},
+		"http endpoint":    {"platform": "web", "token": "http://push.example.com/abc", "keys": keys},
+		"missing keys":     {"platform": "web", "token": "https://push.example.com/abc"},
+	} {
+		if rr := do("POST", "/api/push/devices", "alice-session", body); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", name, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = do("GET", "/api/push/devices", "alice-session", nil)
+	var list struct {
+		Devices []map[string]any `json:"devices"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&list)
+	if len(list.Devices) != 2 {
+		t.Fatalf("expected alice's 2 devices, got %+v", list.Devices)
+	}
+	if _, ok := list.Devices[1]["auth"]; ok {
+		t.Error("expected the subscription's auth secret not to be returned")
+	}
+	if rr := do("GET", "/api/push/devices", "bob-session", nil); bytes.Contains(rr.Body.Bytes(), []byte(phone.ID)) {
+		t.Error("expected bob not to see alice's devices")
+	}
+
+	// Only the owner can delete a device
+	if rr := do("DELETE", "/api/push/devices/"+phone.ID, "bob-session", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 deleting another user's device, got %d", rr.Code)
+	}
+	if rr := do("DELETE", "/api/push/devices/"+phone.ID, "alice-session", nil); rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// API keys have no user to own devices
+	apiKey, _ := s.CreateAPIKey("Service", nil)
+	req := httptest.NewRequest("GET", "/api/push/devices", nil)
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 with an API key, got %d", rr.Code)
+	}
+}
+
+func TestGetVAPIDPublicKey(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	seedAuthUser(t, s, "alice", "alice-session")
+
+	get := func() string {
+		rr := doSessionRequest(router, "GET", "/api/push/vapid-public-key", "alice-session", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			PublicKey string `json:"publicKey"`
+		}
+		_ = json.NewDecoder(rr.Body).Decode(&resp)
+		return resp.PublicKey
+	}
+	key := get()
+	if raw, err := base64.RawURLEncoding.DecodeString(key); err != nil || len(raw) != 65 {
+		t.Errorf("expected a base64url P-256 public key, got %q", key)
+	}
+	if again := get(); again != key {
+		t.Errorf("expected the same key on every call, got %q then %q", key, again)
+	}
+
+	// The private key isn't exposed through the settings
+	rr := doSessionRequest(router, "GET", "/api/settings", "alice-session", nil)
+	if bytes.Contains(rr.Body.Bytes(), []byte("vapid_private_key")) {
+		t.Error("expected the VAPID private key to stay out of /settings")
+	}
+}
+
+func TestPushSubscribe(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	seedAuthUser(t, s, "alice", "alice-session")
+	seedAuthUser(t, s, "bob", "bob-session")
+
+	key, _ := ecdh.P256().GenerateKey(rand.Reader)
+	subscription := func(endpoint string, expirationTime any) map[string]any {
+		return map[string]any{
+			"endpoint":       endpoint,
+			"expirationTime": expirationTime,
+			"keys": map[string]string{
+				"p256dh": base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+				"auth":   base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+			},
+		}
+	}
+
+	// The PushSubscription JSON a browser produces is accepted as is
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Millisecond)
+	rr := doSessionRequest(router, "POST", "/api/push/subscribe", "alice-session", subscription("https://updates.push.services.mozilla.com/wpush/v2/abc", expires.UnixMilli()))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var device db.PushDevice
+	_ = json.NewDecoder(rr.Body).Decode(&device)
+	if device.Platform != db.PushPlatformWeb || device.ExpiresAt == nil || !device.ExpiresAt.Equal(expires) {
+		t.Errorf("unexpected device %+v", device)
+	}
+
+	// The first subscription creates a push channel, later ones reuse it
+	if rr := doSessionRequest(router, "POST", "/api/push/subscribe", "alice-session", subscription("https://fcm.googleapis.com/fcm/send/def", nil)); rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	channels, _ := s.GetNotificationChannels()
+	pushChannels := 0
+	for _, ch := range channels {
+		if ch.Type == "push" && ch.Enabled {
+			pushChannels++
+		}
+	}
+	if pushChannels != 1 {
+		t.Errorf("expected one enabled push channel, got %d", pushChannels)
+	}
+
+	for name, body := range map[string]map[string]any{
+		"expired":      subscription("https://push.example.com/old", time.Now().Add(-time.Hour).UnixMilli()),
+		"http":         subscription("http://push.example.com/abc", nil),
+		"missing keys": {"endpoint": "https://push.example.com/abc"},
+	} {
+		if rr := doSessionRequest(router, "POST", "/api/push/subscribe", "alice-session", body); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", name, rr.Code, rr.Body.String())
+		}
+	}
+
+	// Unsubscribing goes by endpoint, for the user's own subscriptions only
+	unsubscribe := map[string]string{"endpoint": "https://fcm.googleapis.com/fcm/send/def"}
+	if rr := doSessionRequest(router, "POST", "/api/push/unsubscribe", "bob-session", unsubscribe); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's subscription, got %d", rr.Code)
+	}
+	if rr := doSessionRequest(router, "POST", "/api/push/unsubscribe", "alice-session", unsubscribe); rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if devices, _ := s.ListPushDevices(device.UserID); len(devices) != 1 || devices[0].ID != device.ID {
+		t.Errorf("expected one subscription left, got %+v", devices)
+	}
+}
+
+func TestRotateVAPIDKey(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	seedAuthUser(t, s, "alice", "alice-session")
+	user, _ := s.Authenticate("alice", "password123")
+	for _, d := range []db.PushDevice{
+		{ID: "pd-web", UserID: user.ID, Platform: db.PushPlatformWeb, Token: "https://push.example.com/abc"},
+		{ID: "pd-app", UserID: user.ID, Platform: db.PushPlatformFCM, Token: "fcm-token"},
+	} {
+		if _, err := s.RegisterPushDevice(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rr := doSessionRequest(router, "GET", "/api/push/vapid-public-key", "alice-session", nil)
+	var before struct {
+		PublicKey string `json:"publicKey"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&before)
+
+	rr = doSessionRequest(router, "POST", "/api/push/vapid/rotate", "alice-session", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var rotated struct {
+		PublicKey            string `json:"publicKey"`
+		RemovedSubscriptions int    `json:"removedSubscriptions"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&rotated)
+	if rotated.PublicKey == "" || rotated.PublicKey == before.PublicKey || rotated.RemovedSubscriptions != 1 {
+		t.Errorf("unexpected rotation %+v (key before %q)", rotated, before.PublicKey)
+	}
+
+	// Browser subscriptions were bound to the old key; app devices don't use it
+	if devices, _ := s.GetPushDevices(); len(devices) != 1 || devices[0].ID != "pd-app" {
+		t.Errorf("expected only the app device to remain, got %+v", devices)
+	}
+	rr = doSessionRequest(router, "GET", "/api/push/vapid-public-key", "alice-session", nil)
+	if !bytes.Contains(rr.Body.Bytes(), []byte(rotated.PublicKey)) {
+		t.Errorf("expected the new key to be served, got %s", rr.Body.String())
+	}
+}
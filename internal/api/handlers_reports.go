@@ -0,0 +1,279 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// maxCompareMonitors bounds how many monitors one comparison covers.
+const maxCompareMonitors = 20
+
+// MonitorComparison is one monitor's column in an uptime comparison.
+type MonitorComparison struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	GroupID         string   `json:"groupId"`
+	UptimePercent   *float64 `json:"uptimePercent"` // Null when there are no checks in the range
+	TotalChecks     int      `json:"totalChecks"`
+	P95LatencyMs    *int64   `json:"p95LatencyMs"`    // Over checks that weren't down; null when there are none
+	Outages         int      `json:"outages"`         // Outages that started in the range
+	DegradedPeriods int      `json:"degradedPeriods"` // Degraded periods that started in the range
+}
+
+// CompareResponse holds the compared monitors in the order they were requested.
+type CompareResponse struct {
+	Range    string              `json:"range"`
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"` // Exclusive; ranges that include today end at the next midnight
+	Timezone string              `json:"timezone"`
+	Monitors []MonitorComparison `json:"monitors"`
+}
+
+// CompareMonitors returns the uptime, p95 latency and outage counts of several monitors
+// over the same range side by side, e.g. to compare providers or regions hosting the
+// same service.
+// @Summary      Compare monitors
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Param        monitors   query string true  "Comma-separated monitor IDs (up to 20)"
+// @Param        range      query string false "Report range: <n>d (default 30d, up to 365d) or a preset: today, yesterday, this_week, last_week, this_month, last_month, this_quarter, last_quarter, this_year, last_year"
+// @Param        tz         query string false "IANA timezone for the range (default: user's timezone)"
+// @Param        week_start query string false "First day of the week for week presets, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} CompareResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "Monitor not found"
+// @Router       /reports/compare [get]
+func (h *UptimeHandler) CompareMonitors(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("monitors"), ",") {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "monitors is required")
+		return
+	}
+	if len(ids) > maxCompareMonitors {
+		writeError(w, http.StatusBadRequest, "too many monitors (max 20)")
+		return
+	}
+	rng, now, ok := resolveReportRange(w, r, h.store, "30d")
+	if !ok {
+		return
+	}
+
+	resp := CompareResponse{
+		Range:    rng.Name,
+		From:     rng.From,
+		To:       rng.To,
+		Timezone: now.Location().String(),
+		Monitors: make([]MonitorComparison, 0, len(ids)),
+	}
+	for _, id := range ids {
+		m, err := h.store.GetMonitor(id)
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found: "+id)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to load monitor")
+			return
+		}
+		c := MonitorComparison{ID: m.ID, Name: m.Name, GroupID: m.GroupID}
+
+		total, up, err := h.store.GetUptimeCountsBetween(id, rng.From, rng.To, nil)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to calculate uptime")
+			return
+		}
+		c.TotalChecks = total
+		if total > 0 {
+			pct := float64(up) / float64(total) * 100.0
+			c.UptimePercent = &pct
+		}
+
+		p95, ok, err := h.store.GetLatencyPercentileBetween(id, rng.From, rng.To, 95)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to calculate latency")
+			return
+		}
+		if ok {
+			c.P95LatencyMs = &p95
+		}
+
+		if c.Outages, c.DegradedPeriods, err = h.store.CountOutagesBetween(id, rng.From, rng.To); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to count outages")
+			return
+		}
+		resp.Monitors = append(resp.Monitors, c)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HeatmapHour is one cell of a latency heatmap.
+type HeatmapHour struct {
+	Hour            int      `json:"hour"` // 0-23
+	Checks          int      `json:"checks"`
+	FailureRate     *float64 `json:"failureRate"`     // Percent of checks that were down; null when there are none
+	MedianLatencyMs *int64   `json:"medianLatencyMs"` // Of the checks that weren't down; null when there are none
+}
+
+// HeatmapDay is one weekday's row of a latency heatmap.
+type HeatmapDay struct {
+	Weekday string        `json:"weekday"` // Lowercase English name, e.g. monday
+	Hours   []HeatmapHour `json:"hours"`
+}
+
+// HeatmapResponse is a monitor's latency heatmap, with days starting on the week start.
+type HeatmapResponse struct {
+	Range    string       `json:"range"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"` // Exclusive; ranges that include today end at the next midnight
+	Timezone string       `json:"timezone"`
+	Days     []HeatmapDay `json:"days"`
+}
+
+// GetMonitorHeatmap returns the monitor's median latency and failure rate per weekday and
+// hour of day, so recurring patterns such as a nightly backup slowing it down stand out.
+// @Summary      Get monitor latency heatmap
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  string true  "Monitor ID"
+// @Param        range      query string false "Report range: <n>d (default 7d, up to 365d) or a preset: today, yesterday, this_week, last_week, this_month, last_month, this_quarter, last_quarter, this_year, last_year"
+// @Param        tz         query string false "IANA timezone for weekdays and hours (default: user's timezone)"
+// @Param        week_start query string false "First day of the week, which the days start on, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} HeatmapResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "Monitor not found"
+// @Router       /monitors/{id}/heatmap [get]
+func (h *UptimeHandler) GetMonitorHeatmap(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.store.GetMonitor(id); errors.Is(err, db.ErrMonitorNotFound) {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return
+	}
+	rng, now, ok := resolveReportRange(w, r, h.store, "7d")
+	if !ok {
+		return
+	}
+	weekStart, _ := resolveWeekStart(r, h.store) // validated with the range
+
+	cells, err := h.store.GetLatencyHeatmap(id, rng.From, rng.To, now.Location())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to build heatmap")
+		return
+	}
+
+	resp := HeatmapResponse{
+		Range:    rng.Name,
+		From:     rng.From,
+		To:       rng.To,
+		Timezone: now.Location().String(),
+		Days:     make([]HeatmapDay, 0, 7),
+	}
+	for i := range 7 {
+		day := (weekStart + time.Weekday(i)) % 7
+		row := HeatmapDay{Weekday: strings.ToLower(day.String()), Hours: make([]HeatmapHour, 24)}
+		for hour, c := range cells[day] {
+			row.Hours[hour] = HeatmapHour{Hour: hour, Checks: c.Checks, MedianLatencyMs: c.MedianLatencyMs}
+			if c.Checks > 0 {
+				rate := float64(c.Failed) / float64(c.Checks) * 100.0
+				row.Hours[hour].FailureRate = &rate
+			}
+		}
+		resp.Days = append(resp.Days, row)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ReliabilityResponse summarizes a monitor's outages over a range for SRE reviews.
+// Durations are in seconds.
+type ReliabilityResponse struct {
+	Range                string    `json:"range"`
+	From                 time.Time `json:"from"`
+	To                   time.Time `json:"to"` // Exclusive; ranges that include today end at the next midnight
+	Timezone             string    `json:"timezone"`
+	OutageCount          int       `json:"outageCount"`          // Outages that started in the range
+	ResolvedCount        int       `json:"resolvedCount"`        // Of those, the ones that ended
+	MTTRSeconds          *int64    `json:"mttrSeconds"`          // Mean time to recovery; null without resolved outages
+	MTBFSeconds          *int64    `json:"mtbfSeconds"`          // Mean time between failures; null without outages
+	LongestOutageSeconds *int64    `json:"longestOutageSeconds"` // Null without outages
+	LongestOutageID      *int64    `json:"longestOutageId"`
+	DowntimeSeconds      int64     `json:"downtimeSeconds"` // Outage time within the range
+}
+
+// GetMonitorReliability returns the monitor's mean time to recovery, mean time between
+// failures, longest outage and outage count over a range, from its down outages.
+// Degraded periods don't count as failures, and the part of the range after now doesn't
+// count as time up.
+// @Summary      Get monitor reliability
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path  string true  "Monitor ID"
+// @Param        range      query string false "Report range: <n>d (default 30d, up to 365d) or a preset: today, yesterday, this_week, last_week, this_month, last_month, this_quarter, last_quarter, this_year, last_year"
+// @Param        tz         query string false "IANA timezone for the range (default: user's timezone)"
+// @Param        week_start query string false "First day of the week for week presets, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} ReliabilityResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "Monitor not found"
+// @Router       /monitors/{id}/reliability [get]
+func (h *UptimeHandler) GetMonitorReliability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.store.GetMonitor(id); errors.Is(err, db.ErrMonitorNotFound) {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return
+	}
+	rng, now, ok := resolveReportRange(w, r, h.store, "30d")
+	if !ok {
+		return
+	}
+
+	rel, err := h.store.GetReliabilityBetween(id, rng.From, rng.To, now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to calculate reliability")
+		return
+	}
+
+	seconds := func(d time.Duration) *int64 {
+		s := int64(d.Round(time.Second) / time.Second)
+		return &s
+	}
+	resp := ReliabilityResponse{
+		Range:           rng.Name,
+		From:            rng.From,
+		To:              rng.To,
+		Timezone:        now.Location().String(),
+		OutageCount:     rel.Outages,
+		ResolvedCount:   rel.Resolved,
+		DowntimeSeconds: *seconds(rel.Downtime),
+	}
+	if rel.Resolved > 0 {
+		resp.MTTRSeconds = seconds(rel.MTTR)
+	}
+	if rel.Outages > 0 {
+		resp.MTBFSeconds = seconds(rel.MTBF)
+		resp.LongestOutageSeconds = seconds(rel.Longest)
+		resp.LongestOutageID = &rel.LongestID
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestCompareMonitors(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	h := NewUptimeHandler(nil, s)
+	for _, id := range []string{"m-aws", "m-gcp"} {
+		if err := s.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: id, URL: "http://example.com/" + id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor: %v", err)
+		}
+	}
+	now := time.Now()
+	if err := s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-aws", Status: "up", Latency: 100, Timestamp: now.Add(-3 * time.Hour)},
+		{MonitorID: "m-aws", Status: "up", Latency: 300, Timestamp: now.Add(-2 * time.Hour)},
+		{MonitorID: "m-aws", Status: "down", Latency: 10000, Timestamp: now.Add(-time.Hour)},
+		{MonitorID: "m-aws", Status: "up", Latency: 200, Timestamp: now.Add(-40 * 24 * time.Hour)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks: %v", err)
+	}
+	_, _ = s.CreateClosedOutage("m-aws", "down", "", "", now.Add(-time.Hour), now.Add(-30*time.Minute))
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/reports/compare?"+query, nil)
+		w := httptest.NewRecorder()
+		h.CompareMonitors(w, req)
+		return w
+	}
+
+	w := get("monitors=m-gcp,m-aws,m-gcp&tz=UTC")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CompareResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range != "30d" || len(resp.Monitors) != 2 || resp.Monitors[0].ID != "m-gcp" || resp.Monitors[1].ID != "m-aws" {
+		t.Fatalf("unexpected comparison %+v", resp)
+	}
+	if gcp := resp.Monitors[0]; gcp.TotalChecks != 0 || gcp.UptimePercent != nil || gcp.P95LatencyMs != nil || gcp.Outages != 0 {
+		t.Errorf("expected no data for m-gcp, got %+v", gcp)
+	}
+	aws := resp.Monitors[1]
+	if aws.TotalChecks != 3 || aws.UptimePercent == nil || int(*aws.UptimePercent) != 66 {
+		t.Errorf("expected 3 checks at 66%%, got %d at %v", aws.TotalChecks, aws.UptimePercent)
+	}
+	if aws.P95LatencyMs == nil || *aws.P95LatencyMs != 300 || aws.Outages != 1 {
+		t.Errorf("expected p95 of 300ms and 1 outage, got %v and %d", aws.P95LatencyMs, aws.Outages)
+	}
+
+	if w := get("monitors=m-aws,m-nope"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown monitor: expected 404, got %d", w.Code)
+	}
+	for _, query := range []string{"", "monitors=,", "monitors=m-aws&range=next_week"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetMonitorHeatmap(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	h := NewUptimeHandler(nil, s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-heat", GroupID: "g-default", Name: "Heat", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+	at := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Hour)
+	if err := s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-heat", Status: "up", Latency: 800, Timestamp: at},
+		{MonitorID: "m-heat", Status: "down", Timestamp: at.Add(time.Minute)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks: %v", err)
+	}
+
+	get := func(id, query string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req := httptest.NewRequest("GET", "/api/monitors/"+id+"/heatmap?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.GetMonitorHeatmap(w, req)
+		return w
+	}
+
+	w := get("m-heat", "tz=UTC&week_start=sunday")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp HeatmapResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range != "7d" || len(resp.Days) != 7 || resp.Days[0].Weekday != "sunday" || len(resp.Days[0].Hours) != 24 {
+		t.Fatalf("unexpected heatmap %+v", resp)
+	}
+	cell := resp.Days[at.Weekday()].Hours[at.Hour()]
+	if cell.Checks != 2 || cell.FailureRate == nil || *cell.FailureRate != 50 || cell.MedianLatencyMs == nil || *cell.MedianLatencyMs != 800 {
+		t.Errorf("unexpected cell %+v", cell)
+	}
+	if empty := resp.Days[(at.Weekday()+1)%7].Hours[at.Hour()]; empty.Checks != 0 || empty.FailureRate != nil || empty.MedianLatencyMs != nil {
+		t.Errorf("expected an empty cell, got %+v", empty)
+	}
+
+	if w := get("m-nope", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unknown monitor: expected 404, got %d", w.Code)
+	}
+	if w := get("m-heat", "range=next_week"); w.Code != http.StatusBadRequest {
+		t.Errorf("bad range: expected 400, got %d", w.Code)
+	}
+}
+
+func TestGetMonitorReliability(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	h := NewUptimeHandler(nil, s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-rel", GroupID: "g-default", Name: "Rel", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+	end := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	_, _ = s.CreateClosedOutage("m-rel", "down", "", "", end.Add(-10*time.Minute), end)
+	_, _ = s.CreateClosedOutage("m-rel", "down", "", "", end.Add(24*time.Hour-30*time.Minute), end.Add(24*time.Hour))
+
+	get := func(id, query string) *httptest.ResponseRecorder {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req := httptest.NewRequest("GET", "/api/monitors/"+id+"/reliability?"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		h.GetMonitorReliability(w, req)
+		return w
+	}
+
+	w := get("m-rel", "tz=UTC")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ReliabilityResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Range != "30d" || resp.OutageCount != 2 || resp.ResolvedCount != 2 || resp.DowntimeSeconds != 40*60 {
+		t.Fatalf("unexpected reliability %+v", resp)
+	}
+	if resp.MTTRSeconds == nil || *resp.MTTRSeconds != 20*60 {
+		t.Errorf("expected an MTTR of 20 minutes, got %v", resp.MTTRSeconds)
+	}
+	if resp.LongestOutageSeconds == nil || *resp.LongestOutageSeconds != 30*60 || resp.LongestOutageID == nil {
+		t.Errorf("expected the longest outage to last 30 minutes, got %v", resp.LongestOutageSeconds)
+	}
+	if resp.MTBFSeconds == nil || *resp.MTBFSeconds <= 0 {
+		t.Errorf("expected an MTBF, got %v", resp.MTBFSeconds)
+	}
+
+	w = get("m-rel", "range=today&tz=UTC")
+	resp = ReliabilityResponse{}
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || resp.OutageCount != 0 || resp.MTTRSeconds != nil || resp.MTBFSeconds != nil || resp.LongestOutageSeconds != nil {
+		t.Errorf("expected no outages today, got %d %+v", w.Code, resp)
+	}
+
+	if w := get("missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+	if w := get("m-rel", "range=forever"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/issues"
 	"github.com/projecthelena/warden/internal/uptime"
 )
 
@@ -26,25 +27,21 @@ func NewSettingsHandler(store *db.Store, manager *uptime.Manager) *SettingsHandl
 // @Success      200  {object} map[string]string
 // @Router       /settings [get]
 func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
-	// Latency Threshold
-	val, err := h.store.GetSetting("latency_threshold")
-	if err != nil {
-		val = "1000"
-	}
-
-	// Data Retention
-	retention, err := h.store.GetSetting("data_retention_days")
-	if err != nil {
-		retention = "365"
+	settings := make(map[string]string, len(settingsSchema)+3)
+	for _, spec := range settingsSchema {
+		if spec.Secret {
+			continue
+		}
+		val, err := h.store.GetSetting(spec.Key)
+		if err != nil || (val == "" && !spec.AllowEmpty) {
+			val = spec.Default
+		}
+		settings[spec.Key] = val
 	}
 
-	// Slack Notifications
-	slackEnabled, _ := h.store.GetSetting("notifications.slack.enabled")
-	slackWebhook, _ := h.store.GetSetting("notifications.slack.webhook_url")
-	slackNotifyOn, _ := h.store.GetSetting("notifications.slack.notify_on")
-
 	// SECURITY: Mask webhook URL to prevent exposure
 	// Only show that it's configured, not the actual URL
+	slackWebhook, _ := h.store.GetSetting("notifications.slack.webhook_url")
 	slackWebhookMasked := ""
 	if slackWebhook != "" {
 		if len(slackWebhook) > 30 {
@@ -53,101 +50,45 @@ func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
 			slackWebhookMasked = "***configured***"
 		}
 	}
-
-	// SSO Settings (mask the secret)
-	ssoGoogleEnabled, _ := h.store.GetSetting("sso.google.enabled")
-	ssoGoogleClientID, _ := h.store.GetSetting("sso.google.client_id")
-	ssoGoogleClientSecret, _ := h.store.GetSetting("sso.google.client_secret")
-	ssoGoogleRedirectURL, _ := h.store.GetSetting("sso.google.redirect_url")
-	ssoGoogleAllowedDomains, _ := h.store.GetSetting("sso.google.allowed_domains")
-	ssoGoogleAutoProvision, _ := h.store.GetSetting("sso.google.auto_provision")
+	settings["notifications.slack.webhook_url"] = slackWebhookMasked
+	settings["notifications.slack.webhook_configured"] = strconv.FormatBool(slackWebhook != "")
 
 	// Only indicate if secret is configured, don't return actual value
-	secretConfigured := "false"
-	if ssoGoogleClientSecret != "" {
-		secretConfigured = "true"
-	}
+	ssoGoogleClientSecret, _ := h.store.GetSetting("sso.google.client_secret")
+	settings["sso.google.secret_configured"] = strconv.FormatBool(ssoGoogleClientSecret != "")
 
-	// Notification Fatigue Settings
-	confirmThreshold, _ := h.store.GetSetting("notification.confirmation_threshold")
-	if confirmThreshold == "" {
-		confirmThreshold = "3"
-	}
-	cooldownMins, _ := h.store.GetSetting("notification.cooldown_minutes")
-	if cooldownMins == "" {
-		cooldownMins = "30"
+	for key, flag := range map[string]string{
+		issues.JiraTokenKey:     "integrations.jira.token_configured",
+		issues.GitHubTokenKey:   "integrations.github.token_configured",
+		issues.WebhookSecretKey: "integrations.issues.webhook_secret_configured",
+		uptime.WatchdogURLKey:   "watchdog.url_configured",
+	} {
+		val, _ := h.store.GetSetting(key)
+		settings[flag] = strconv.FormatBool(val != "")
 	}
-	flapEnabled, _ := h.store.GetSetting("notification.flap_detection_enabled")
-	if flapEnabled == "" {
-		flapEnabled = "true"
-	}
-	flapWindow, _ := h.store.GetSetting("notification.flap_window_checks")
-	if flapWindow == "" {
-		flapWindow = "21"
-	}
-	flapThreshold, _ := h.store.GetSetting("notification.flap_threshold_percent")
-	if flapThreshold == "" {
-		flapThreshold = "25"
-	}
-
-	// Event Type Toggles
-	eventDown, _ := h.store.GetSetting("notification.event.down.enabled")
-	if eventDown == "" { eventDown = "true" }
-	eventUp, _ := h.store.GetSetting("notification.event.up.enabled")
-	if eventUp == "" { eventUp = "true" }
-	eventDegraded, _ := h.store.GetSetting("notification.event.degraded.enabled")
-	if eventDegraded == "" { eventDegraded = "true" }
-	eventFlapping, _ := h.store.GetSetting("notification.event.flapping.enabled")
-	if eventFlapping == "" { eventFlapping = "true" }
-	eventStabilized, _ := h.store.GetSetting("notification.event.stabilized.enabled")
-	if eventStabilized == "" { eventStabilized = "true" }
-	eventSSL, _ := h.store.GetSetting("notification.event.ssl_expiring.enabled")
-	if eventSSL == "" { eventSSL = "true" }
 
-	// Recovery Confirmation
-	recoveryChecks, _ := h.store.GetSetting("notification.recovery_confirmation_checks")
-	if recoveryChecks == "" { recoveryChecks = "1" }
+	writeJSON(w, http.StatusOK, settings)
+}
 
-	// Digest Settings
-	digestEnabled, _ := h.store.GetSetting("notification.digest.enabled")
-	if digestEnabled == "" { digestEnabled = "false" }
-	digestTime, _ := h.store.GetSetting("notification.digest.time")
-	if digestTime == "" { digestTime = "09:00" }
-	digestEventTypes, _ := h.store.GetSetting("notification.digest.event_types")
-	if digestEventTypes == "" { digestEventTypes = "degraded,flapping,stabilized,ssl_expiring" }
+// GetSettingsSchema describes every writable setting: its type, range, format and default.
+// @Summary      Get settings schema
+// @Tags         settings
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array} SettingSpec
+// @Router       /settings/schema [get]
+func (h *SettingsHandler) GetSettingsSchema(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, settingsSchema)
+}
 
-	writeJSON(w, http.StatusOK, map[string]string{
-		"latency_threshold":                      val,
-		"data_retention_days":                    retention,
-		"notifications.slack.enabled":            slackEnabled,
-		"notifications.slack.webhook_url":        slackWebhookMasked, // SECURITY: Masked for display
-		"notifications.slack.webhook_configured": func() string { if slackWebhook != "" { return "true" }; return "false" }(),
-		"notifications.slack.notify_on":          slackNotifyOn,
-		"sso.google.enabled":                     ssoGoogleEnabled,
-		"sso.google.client_id":                   ssoGoogleClientID,
-		"sso.google.secret_configured":           secretConfigured,
-		"sso.google.redirect_url":                ssoGoogleRedirectURL,
-		"sso.google.allowed_domains":             ssoGoogleAllowedDomains,
-		"sso.google.auto_provision":              ssoGoogleAutoProvision,
-		"notification.confirmation_threshold":    confirmThreshold,
-		"notification.cooldown_minutes":          cooldownMins,
-		"notification.flap_detection_enabled":    flapEnabled,
-		"notification.flap_window_checks":        flapWindow,
-		"notification.flap_threshold_percent":    flapThreshold,
-		"notification.event.down.enabled":        eventDown,
-		"notification.event.up.enabled":          eventUp,
-		"notification.event.degraded.enabled":    eventDegraded,
-		"notification.event.flapping.enabled":    eventFlapping,
-		"notification.event.stabilized.enabled":  eventStabilized,
-		"notification.event.ssl_expiring.enabled": eventSSL,
-		"notification.recovery_confirmation_checks": recoveryChecks,
-		"notification.digest.enabled":            digestEnabled,
-		"notification.digest.time":               digestTime,
-		"notification.digest.event_types":        digestEventTypes,
-	})
+// settingsValidationError is returned when one or more settings in a PATCH are rejected.
+type settingsValidationError struct {
+	ErrorResponse
+	Fields map[string]string `json:"fields"` // setting key -> reason
 }
 
-// UpdateSettings patches application settings.
+// UpdateSettings patches application settings. Every key is validated against the
+// settings schema before anything is saved; unknown keys are rejected.
 // @Summary      Update settings
 // @Tags         settings
 // @Accept       json
@@ -155,161 +96,52 @@ func (h *SettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Param        body body map[string]string true "Key-value pairs to update"
 // @Success      200  {object} object{status=string}
-// @Failure      400  {string} string "Invalid body"
+// @Failure      400  {object} settingsValidationError "Invalid body or setting values"
 // @Router       /settings [patch]
 func (h *SettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	var body map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		http.Error(w, "Invalid body", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid body")
 		return
 	}
 
-	if val, ok := body["latency_threshold"]; ok {
-		// Validate int
-		i, err := strconv.Atoi(val)
-		if err != nil || i < 0 {
-			http.Error(w, "Invalid latency_threshold", http.StatusBadRequest)
-			return
+	fieldErrors := map[string]string{}
+	for key, val := range body {
+		spec, ok := settingSpecs[key]
+		if !ok {
+			fieldErrors[key] = "unknown setting"
+			continue
 		}
-
-		if err := h.store.SetSetting("latency_threshold", val); err != nil {
-			http.Error(w, "Failed to save latency_threshold", http.StatusInternalServerError)
-			return
+		if err := spec.Validate(val); err != nil {
+			fieldErrors[key] = err.Error()
 		}
-		h.manager.SetLatencyThreshold(int64(i))
 	}
 
-	if val, ok := body["data_retention_days"]; ok {
-		// Validate int
-		i, err := strconv.Atoi(val)
-		if err != nil || i < 1 {
-			http.Error(w, "Invalid data_retention_days", http.StatusBadRequest)
-			return
-		}
-
+	if val, ok := body["data_retention_days"]; ok && fieldErrors["data_retention_days"] == "" {
 		// Cross-validate: no status page should have uptime_days_range > new retention
+		days, _ := strconv.Atoi(val)
 		pages, err := h.store.GetStatusPages()
 		if err != nil {
-			http.Error(w, "Failed to validate against status pages", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, "Failed to validate against status pages")
 			return
 		}
 		for _, p := range pages {
-			if p.UptimeDaysRange > i {
-				writeError(w, http.StatusBadRequest, "cannot reduce retention below "+strconv.Itoa(p.UptimeDaysRange)+" days (used by status page \""+p.Title+"\")")
-				return
+			if p.UptimeDaysRange > days {
+				fieldErrors["data_retention_days"] = "cannot reduce retention below " + strconv.Itoa(p.UptimeDaysRange) + " days (used by status page \"" + p.Title + "\")"
+				break
 			}
 		}
-
-		if err := h.store.SetSetting("data_retention_days", val); err != nil {
-			http.Error(w, "Failed to save data_retention_days", http.StatusInternalServerError)
-			return
-		}
 	}
 
-	// Notifications Keys
-	notificationKeys := []string{
-		"notifications.slack.enabled",
-		"notifications.slack.webhook_url",
-		"notifications.slack.notify_on",
-	}
-
-	for _, key := range notificationKeys {
-		if val, ok := body[key]; ok {
-			if err := h.store.SetSetting(key, val); err != nil {
-				http.Error(w, "Failed to save "+key, http.StatusInternalServerError)
-				return
-			}
-		}
-	}
-
-	// SSO Settings Keys
-	ssoKeys := []string{
-		"sso.google.enabled",
-		"sso.google.client_id",
-		"sso.google.client_secret",
-		"sso.google.redirect_url",
-		"sso.google.allowed_domains",
-		"sso.google.auto_provision",
-	}
-
-	for _, key := range ssoKeys {
-		if val, ok := body[key]; ok {
-			if err := h.store.SetSetting(key, val); err != nil {
-				http.Error(w, "Failed to save "+key, http.StatusInternalServerError)
-				return
-			}
-		}
-	}
-
-	// Notification Fatigue Settings
-	notifFatigueChanged := false
-	notifFatigueIntKeys := map[string]struct{ min, max int }{
-		"notification.confirmation_threshold":      {1, 100},
-		"notification.cooldown_minutes":            {0, 1440},
-		"notification.flap_window_checks":          {3, 100},
-		"notification.flap_threshold_percent":       {1, 100},
-		"notification.recovery_confirmation_checks": {1, 20},
-	}
-
-	for key, bounds := range notifFatigueIntKeys {
-		if val, ok := body[key]; ok {
-			i, err := strconv.Atoi(val)
-			if err != nil || i < bounds.min || i > bounds.max {
-				http.Error(w, "Invalid "+key, http.StatusBadRequest)
-				return
-			}
-			if err := h.store.SetSetting(key, val); err != nil {
-				http.Error(w, "Failed to save "+key, http.StatusInternalServerError)
-				return
-			}
-			notifFatigueChanged = true
-		}
-	}
-
-	// Boolean notification settings
-	notifBoolKeys := []string{
-		"notification.flap_detection_enabled",
-		"notification.event.down.enabled",
-		"notification.event.up.enabled",
-		"notification.event.degraded.enabled",
-		"notification.event.flapping.enabled",
-		"notification.event.stabilized.enabled",
-		"notification.event.ssl_expiring.enabled",
-		"notification.digest.enabled",
-	}
-
-	for _, key := range notifBoolKeys {
-		if val, ok := body[key]; ok {
-			if val != "true" && val != "false" {
-				http.Error(w, "Invalid "+key, http.StatusBadRequest)
-				return
-			}
-			if err := h.store.SetSetting(key, val); err != nil {
-				http.Error(w, "Failed to save "+key, http.StatusInternalServerError)
-				return
-			}
-			notifFatigueChanged = true
-		}
-	}
-
-	// Digest string settings
-	digestStringKeys := []string{
-		"notification.digest.time",
-		"notification.digest.event_types",
-	}
-	for _, key := range digestStringKeys {
-		if val, ok := body[key]; ok {
-			if err := h.store.SetSetting(key, val); err != nil {
-				http.Error(w, "Failed to save "+key, http.StatusInternalServerError)
-				return
-			}
-			notifFatigueChanged = true
-		}
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusBadRequest, settingsValidationError{ErrorResponse: errorBody(w, ErrCodeValidationFailed, "invalid settings"), Fields: fieldErrors})
+		return
 	}
 
-	// Trigger Sync so monitors pick up new settings immediately
-	if notifFatigueChanged {
-		h.manager.Sync()
+	// Running subsystems pick the values up through the store's settings listeners
+	if err := h.store.SetSettings(body); err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to save settings")
+		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "updated"})
@@ -113,3 +113,163 @@ func TestUpdateSettings_LatencyThresholdUpdatesManager(t *testing.T) {
 		t.Errorf("Expected latency threshold 2000, got %d", m.GetLatencyThreshold())
 	}
 }
+
+func TestUpdateSettings_MaintenanceReminderHours(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	m := uptime.NewManager(s)
+	h := NewSettingsHandler(s, m)
+
+	patch := func(val string) int {
+		bodyBytes, _ := json.Marshal(map[string]string{"notification.maintenance.reminder_hours": val})
+		req := httptest.NewRequest("PATCH", "/api/settings", bytes.NewReader(bodyBytes))
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, req)
+		return w.Code
+	}
+
+	for _, bad := range []string{"0", "abc", "1,2,3,4,5,6", "1000"} {
+		if code := patch(bad); code != http.StatusBadRequest {
+			t.Errorf("reminder_hours %q: expected 400, got %d", bad, code)
+		}
+	}
+
+	if code := patch("48,2"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if val, _ := s.GetSetting("notification.maintenance.reminder_hours"); val != "48,2" {
+		t.Errorf("expected saved value '48,2', got %q", val)
+	}
+
+	// Empty disables reminders
+	if code := patch(""); code != http.StatusOK {
+		t.Fatalf("expected 200 for empty value, got %d", code)
+	}
+}
+
+func TestUpdateSettings_IncidentAutoPublish(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewSettingsHandler(store, uptime.NewManager(store))
+
+	for value, code := range map[string]int{"true": http.StatusOK, "yes": http.StatusBadRequest} {
+		body, _ := json.Marshal(map[string]string{incidentAutoPublishSettingKey: value})
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, httptest.NewRequest("PATCH", "/api/settings", bytes.NewBuffer(body)))
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", value, code, w.Code)
+		}
+	}
+	if v, _ := store.GetSetting(incidentAutoPublishSettingKey); v != "true" {
+		t.Errorf("Expected setting saved, got %q", v)
+	}
+}
+
+func TestUpdateSettings_SchemaValidation(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewSettingsHandler(store, uptime.NewManager(store))
+
+	patch := func(body map[string]string) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		h.UpdateSettings(w, httptest.NewRequest("PATCH", "/api/settings", bytes.NewReader(b)))
+		return w
+	}
+
+	w := patch(map[string]string{
+		"latency_threshold":               "750",
+		"notification.cooldown_minutes":   "5000",
+		"notification.digest.time":        "25:00",
+		"notification.digest.event_types": "down,bogus",
+		"sso.google.redirect_url":         "javascript:alert(1)",
+		"notifications.slack.enabled":     "yes",
+		"sso.google.secret_configured":    "true",
+		"something.made_up":               "1",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", w.Code)
+	}
+	var resp settingsValidationError
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	for _, key := range []string{
+		"notification.cooldown_minutes",
+		"notification.digest.time",
+		"notification.digest.event_types",
+		"sso.google.redirect_url",
+		"notifications.slack.enabled",
+		"sso.google.secret_configured",
+		"something.made_up",
+	} {
+		if resp.Fields[key] == "" {
+			t.Errorf("Expected a field error for %s, got %v", key, resp.Fields)
+		}
+	}
+	if _, ok := resp.Fields["latency_threshold"]; ok {
+		t.Errorf("latency_threshold is valid, got error %q", resp.Fields["latency_threshold"])
+	}
+	if resp.Fields["notification.cooldown_minutes"] != "must be between 0 and 1440" {
+		t.Errorf("Unexpected message: %q", resp.Fields["notification.cooldown_minutes"])
+	}
+	// Nothing is saved when any field is rejected
+	if v, _ := store.GetSetting("latency_threshold"); v == "750" {
+		t.Error("Expected latency_threshold not to be saved")
+	}
+
+	if w := patch(map[string]string{"notification.digest.time": "18:30", "notification.digest.event_types": "down, up"}); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetSettingsSchema(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewSettingsHandler(store, uptime.NewManager(store))
+
+	w := httptest.NewRecorder()
+	h.GetSettingsSchema(w, httptest.NewRequest("GET", "/api/settings/schema", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var specs []SettingSpec
+	if err := json.Unmarshal(w.Body.Bytes(), &specs); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(specs) != len(settingsSchema) {
+		t.Fatalf("Expected %d settings, got %d", len(settingsSchema), len(specs))
+	}
+	for _, s := range specs {
+		if s.Key == "data_retention_days" {
+			if s.Type != "integer" || s.Default != "365" || s.Min == nil || *s.Min != 1 || s.Max == nil || *s.Max != 3650 {
+				t.Errorf("Unexpected data_retention_days spec: %+v", s)
+			}
+			return
+		}
+	}
+	t.Error("data_retention_days missing from schema")
+}
+
+func TestGetSettings_HidesSecrets(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	h := NewSettingsHandler(store, uptime.NewManager(store))
+	_ = store.SetSetting("sso.google.client_secret", "super-secret")
+
+	w := httptest.NewRecorder()
+	h.GetSettings(w, httptest.NewRequest("GET", "/api/settings", nil))
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if _, ok := response["sso.google.client_secret"]; ok {
+		t.Error("Client secret must not be returned")
+	}
+	if response["sso.google.secret_configured"] != "true" {
+		t.Errorf("Expected secret_configured true, got %q", response["sso.google.secret_configured"])
+	}
+	// Explicitly empty reminder hours mean "disabled" and are not replaced by the default
+	_ = store.SetSetting("notification.maintenance.reminder_hours", "")
+	w = httptest.NewRecorder()
+	h.GetSettings(w, httptest.NewRequest("GET", "/api/settings", nil))
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if v := response["notification.maintenance.reminder_hours"]; v != "" {
+		t.Errorf("Expected empty reminder hours, got %q", v)
+	}
+}
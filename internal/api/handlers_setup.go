@@ -12,27 +12,39 @@ import (
 	"strings"
 	"time"
 
+	"github.com/projecthelena/warden/internal/config"
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/demo"
 )
 
 type SetupRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"` // #nosec G117 -- input-only DTO, never serialized in responses
 	Timezone string `json:"timezone"`
+	// SetupToken must match WARDEN_SETUP_TOKEN when it is set; X-Setup-Token also works
+	SetupToken string `json:"setupToken"` // #nosec G117 -- input-only DTO, never serialized in responses
 }
 
 func (h *Router) CheckSetup(w http.ResponseWriter, r *http.Request) {
 	hasUsers, err := h.store.HasUsers()
 	if err != nil {
-		http.Error(w, "Database error", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Database error")
 		return
 	}
 
 	val, _ := h.store.GetSetting("setup_completed")
 
-	_ = json.NewEncoder(w).Encode(map[string]bool{
+	resp := map[string]any{
 		"isSetup": hasUsers || val == "true",
-	})
+	}
+	if h.config.SetupToken != "" {
+		resp["setupTokenRequired"] = true
+	}
+	if h.config.Demo {
+		// Shown on the login page so visitors can sign in to the demo
+		resp["demo"] = map[string]string{"username": demo.Username, "password": demo.Password}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
@@ -43,12 +55,12 @@ func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
 	isComplete, err := h.store.IsSetupComplete()
 	if err != nil {
 		log.Printf("AUDIT: [SETUP] Database error checking setup status from IP %s: %v", sanitizeLog(clientIP), err) // #nosec G706 -- sanitized
-		http.Error(w, "Database error", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Database error")
 		return
 	}
 	if isComplete {
 		log.Printf("AUDIT: [SECURITY] Setup attempt from IP %s denied - setup already completed", sanitizeLog(clientIP)) // #nosec G706 -- sanitized
-		http.Error(w, "Setup already completed", http.StatusForbidden)
+		writeErrorCode(w, http.StatusForbidden, ErrCodeSetupCompleted, "Setup already completed")
 		return
 	}
 
@@ -79,53 +91,25 @@ func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
 
 	var req SetupRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Validation
-	if req.Username == "" || req.Password == "" {
-		http.Error(w, "Username and password required", http.StatusBadRequest)
-		return
-	}
-
-	// Username Validation
-	req.Username = strings.TrimSpace(req.Username)
-	if len(req.Username) > 32 {
-		http.Error(w, "Username too long (max 32 chars)", http.StatusBadRequest)
-		return
-	}
-	validUsername := regexp.MustCompile(`^[a-z0-9._-]+$`)
-	if !validUsername.MatchString(req.Username) {
-		http.Error(w, "Username invalid: must be lowercase, alphanumeric, dots, underscores, or dashes only", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	// Password validation: 8+ chars, at least one number, at least one special character
-	if len(req.Password) < 8 {
-		http.Error(w, "Password must be at least 8 characters", http.StatusBadRequest)
-		return
-	}
-	hasNumber := false
-	for _, c := range req.Password {
-		if c >= '0' && c <= '9' {
-			hasNumber = true
-			break
+	if h.config.SetupToken != "" {
+		token := r.Header.Get("X-Setup-Token")
+		if token == "" {
+			token = req.SetupToken
 		}
-	}
-	if !hasNumber {
-		http.Error(w, "Password must contain at least one number", http.StatusBadRequest)
-		return
-	}
-	hasSpecial := false
-	for _, c := range req.Password {
-		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
-			hasSpecial = true
-			break
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.config.SetupToken)) != 1 {
+			log.Printf("AUDIT: [SECURITY] Setup attempt from IP %s denied - invalid setup token", sanitizeLog(clientIP)) // #nosec G706 -- sanitized
+			writeErrorCode(w, http.StatusUnauthorized, ErrCodeSetupTokenRequired, "A valid setup token is required")
+			return
 		}
 	}
-	if !hasSpecial {
-		http.Error(w, "Password must contain at least one special character", http.StatusBadRequest)
+
+	req.Username = strings.TrimSpace(req.Username)
+	if msg := validateSetupCredentials(req.Username, req.Password); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
 		return
 	}
 
@@ -136,32 +120,13 @@ func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
 	// Create User
 	if err := h.store.CreateUser(req.Username, req.Password, req.Timezone); err != nil {
 		log.Printf("AUDIT: [SETUP] Failed to create user from IP %s: %v", sanitizeLog(clientIP), err) // #nosec G706 -- sanitized
-		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
 	log.Printf("AUDIT: [SETUP] Admin user '%s' created from IP %s", sanitizeLog(req.Username), sanitizeLog(clientIP)) // #nosec G706 -- sanitized
 
 	// Always create default monitors (no toggle needed - gives immediate value)
-	defaults := []struct{ Name, URL string }{
-		{"Google", "https://google.com"},
-		{"GitHub", "https://github.com"},
-		{"Cloudflare DNS", "https://1.1.1.1"},
-	}
-
-	for i, d := range defaults {
-		id := fmt.Sprintf("m-default-%d", i)
-		if err := h.store.CreateMonitor(db.Monitor{
-			ID:       id,
-			GroupID:  "g-default",
-			Name:     d.Name,
-			URL:      d.URL,
-			Active:   true,
-			Interval: 60,
-		}); err != nil {
-			// Best effort - ignore errors
-			_ = err
-		}
-	}
+	createDefaultMonitors(h.store)
 
 	// Mark as completed
 	_ = h.store.SetSetting("setup_completed", "true")
@@ -171,7 +136,10 @@ func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
 
 	// Wait for all default monitors to get their first ping (max 5s)
 	// This ensures the dashboard shows live data immediately after setup
-	monitorIDs := []string{"m-default-0", "m-default-1", "m-default-2"}
+	monitorIDs := make([]string, len(defaultMonitors))
+	for i := range defaultMonitors {
+		monitorIDs[i] = defaultMonitorID(i)
+	}
 	deadline := time.Now().Add(5 * time.Second)
 	for time.Now().Before(deadline) {
 		allReady := true
@@ -241,3 +209,111 @@ func (h *Router) PerformSetup(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 }
+
+// validateSetupCredentials returns why the admin username or password is not acceptable,
+// or "" if both are. The setup page and WARDEN_ADMIN_USER follow the same rules.
+func validateSetupCredentials(username, password string) string {
+	if username == "" || password == "" {
+		return "Username and password required"
+	}
+	if len(username) > 32 {
+		return "Username too long (max 32 chars)"
+	}
+	if !validSetupUsername.MatchString(username) {
+		return "Username invalid: must be lowercase, alphanumeric, dots, underscores, or dashes only"
+	}
+
+	// Password validation: 8+ chars, at least one number, at least one special character
+	if len(password) < 8 {
+		return "Password must be at least 8 characters"
+	}
+	hasNumber := false
+	for _, c := range password {
+		if c >= '0' && c <= '9' {
+			hasNumber = true
+			break
+		}
+	}
+	if !hasNumber {
+		return "Password must contain at least one number"
+	}
+	hasSpecial := false
+	for _, c := range password {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			hasSpecial = true
+			break
+		}
+	}
+	if !hasSpecial {
+		return "Password must contain at least one special character"
+	}
+	return ""
+}
+
+var validSetupUsername = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// defaultMonitors are created with the admin account, so the dashboard has live data
+// right away.
+var defaultMonitors = []struct{ Name, URL string }{
+	{"Google", "https://google.com"},
+	{"GitHub", "https://github.com"},
+	{"Cloudflare DNS", "https://1.1.1.1"},
+}
+
+func defaultMonitorID(i int) string {
+	return fmt.Sprintf("m-default-%d", i)
+}
+
+func createDefaultMonitors(store *db.Store) {
+	for i, d := range defaultMonitors {
+		if err := store.CreateMonitor(db.Monitor{
+			ID:       defaultMonitorID(i),
+			GroupID:  "g-default",
+			Name:     d.Name,
+			URL:      d.URL,
+			Active:   true,
+			Interval: 60,
+		}); err != nil {
+			// Best effort - ignore errors
+			_ = err
+		}
+	}
+}
+
+// BootstrapAdmin creates the admin account from WARDEN_ADMIN_USER and
+// WARDEN_ADMIN_PASSWORD on first start, with the same rules and default monitors as the
+// setup page, so deployments are provisioned without calling POST /api/setup. It does
+// nothing once setup is complete, and reports whether it created the account.
+func BootstrapAdmin(store *db.Store, cfg *config.Config) (bool, error) {
+	if cfg.AdminUser == "" {
+		return false, nil
+	}
+	isComplete, err := store.IsSetupComplete()
+	if err != nil {
+		return false, err
+	}
+	if isComplete {
+		return false, nil
+	}
+	if msg := validateSetupCredentials(cfg.AdminUser, cfg.AdminPassword); msg != "" {
+		return false, fmt.Errorf("WARDEN_ADMIN_USER/WARDEN_ADMIN_PASSWORD: %s", msg)
+	}
+	timezone := cfg.AdminTimezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	if err := store.CreateUser(cfg.AdminUser, cfg.AdminPassword, timezone); err != nil {
+		// Another instance starting with the same environment may have won the race
+		if isComplete, _ := store.IsSetupComplete(); isComplete {
+			return false, nil
+		}
+		return false, err
+	}
+	createDefaultMonitors(store)
+	if err := store.SetSetting("setup_completed", "true"); err != nil {
+		return false, err
+	}
+	log.Printf("AUDIT: [SETUP] Admin user '%s' created from WARDEN_ADMIN_USER", sanitizeLog(cfg.AdminUser)) // #nosec G706 -- sanitized
+	return true, nil
+}
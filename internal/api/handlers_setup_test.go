@@ -238,3 +238,96 @@ func TestPerformSetup_AutoLogin(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckSetup_Demo(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	cfg := &config.Config{}
+	r := &Router{Mux: chi.NewRouter(), manager: uptime.NewManager(s), store: s, config: cfg}
+
+	status := func() map[string]json.RawMessage {
+		w := httptest.NewRecorder()
+		r.CheckSetup(w, httptest.NewRequest("GET", "/api/setup/status", nil))
+		var resp map[string]json.RawMessage
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	if _, ok := status()["demo"]; ok {
+		t.Error("Expected no demo credentials outside demo mode")
+	}
+
+	cfg.Demo = true
+	var creds struct{ Username, Password string }
+	_ = json.Unmarshal(status()["demo"], &creds)
+	if creds.Username == "" || creds.Password == "" {
+		t.Errorf("Expected demo credentials in demo mode, got %+v", creds)
+	}
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	cfg := &config.Config{AdminUser: "ops", AdminPassword: "weak"}
+
+	if _, err := BootstrapAdmin(s, cfg); err == nil {
+		t.Fatal("Expected a password breaking the setup rules to be rejected")
+	}
+
+	cfg.AdminPassword = "Str0ng-pass!"
+	created, err := BootstrapAdmin(s, cfg)
+	if err != nil || !created {
+		t.Fatalf("Expected the admin user to be created, got %v, %v", created, err)
+	}
+	if _, err := s.Authenticate("ops", "Str0ng-pass!"); err != nil {
+		t.Errorf("Expected the bootstrapped admin to sign in: %v", err)
+	}
+	if mon, _ := s.GetMonitor(defaultMonitorID(0)); mon == nil {
+		t.Error("Expected the default monitors to be created")
+	}
+
+	// Later starts leave the account alone, even with another password configured
+	cfg.AdminPassword = "Chang3d-pass!"
+	if created, err := BootstrapAdmin(s, cfg); err != nil || created {
+		t.Errorf("Expected bootstrap to be skipped once setup is complete, got %v, %v", created, err)
+	}
+	if _, err := s.Authenticate("ops", "Str0ng-pass!"); err != nil {
+		t.Errorf("Expected the original password to keep working: %v", err)
+	}
+}
+
+func TestPerformSetup_SetupToken(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	cfg := &config.Config{SetupToken: "provision-me"}
+	r := &Router{Mux: chi.NewRouter(), manager: uptime.NewManager(s), store: s, config: cfg}
+
+	setup := func(header, bodyToken string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"username": "admin", "password": "Password1!", "setupToken": bodyToken})
+		req := httptest.NewRequest("POST", "/api/setup", bytes.NewBuffer(body))
+		if header != "" {
+			req.Header.Set("X-Setup-Token", header)
+		}
+		w := httptest.NewRecorder()
+		r.PerformSetup(w, req)
+		return w
+	}
+
+	w := setup("", "")
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), ErrCodeSetupTokenRequired) {
+		t.Fatalf("Expected 401 without the setup token, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := setup("wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 with a wrong setup token, got %d", w.Code)
+	}
+	if hasUsers, _ := s.HasUsers(); hasUsers {
+		t.Fatal("Expected no user before a valid setup token")
+	}
+
+	sw := httptest.NewRecorder()
+	r.CheckSetup(sw, httptest.NewRequest("GET", "/api/setup/status", nil))
+	if !strings.Contains(sw.Body.String(), `"setupTokenRequired":true`) {
+		t.Errorf("Expected setup status to ask for the token, got %s", sw.Body.String())
+	}
+
+	if w := setup("", "provision-me"); w.Code != http.StatusOK {
+		t.Fatalf("Expected setup with the token to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// Silence bounds
+const (
+	maxSilenceDuration     = 90 * 24 * time.Hour
+	maxSilenceReasonLength = 500
+)
+
+// SilenceHandler manages silences, which mute notifications without touching status.
+type SilenceHandler struct {
+	store   *db.Store
+	manager *uptime.Manager
+}
+
+func NewSilenceHandler(store *db.Store, manager *uptime.Manager) *SilenceHandler {
+	return &SilenceHandler{store: store, manager: manager}
+}
+
+func generateSilenceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "sil-" + time.Now().Format("20060102150405")
+	}
+	return "sil-" + hex.EncodeToString(b)
+}
+
+// SilenceResponse is a silence with whether it mutes notifications right now.
+type SilenceResponse struct {
+	db.Silence
+	Active bool `json:"active"`
+}
+
+func silenceResponse(sil db.Silence, now time.Time) SilenceResponse {
+	return SilenceResponse{Silence: sil, Active: sil.ActiveAt(now)}
+}
+
+// ListSilences returns the current and upcoming silences, latest start first.
+// @Summary      List silences
+// @Tags         silences
+// @Produce      json
+// @Security     BearerAuth
+// @Param        expired query bool false "Include expired silences"
+// @Success      200  {array}  SilenceResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /silences [get]
+func (h *SilenceHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	silences, err := h.store.GetSilences(r.URL.Query().Get("expired") == "true", now)
+	if err != nil {
+		log.Printf("ERROR: Failed to list silences: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to list silences")
+		return
+	}
+	resp := make([]SilenceResponse, 0, len(silences))
+	for _, sil := range silences {
+		resp = append(resp, silenceResponse(sil, now))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateSilence mutes the notifications of a monitor, a group or the monitors with a tag
+// for a while, like an Alertmanager silence. Their checks, status and outages carry on as
+// usual; use a maintenance window to show planned downtime on status pages instead.
+// @Summary      Create silence
+// @Tags         silences
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{scope=string,target=string,reason=string,startsAt=string,endsAt=string,durationMinutes=int} true "Scope (monitor, group or tag) and target, why, and when: endsAt or durationMinutes, from startsAt (default now)"
+// @Success      201  {object} SilenceResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /silences [post]
+func (h *SilenceHandler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Scope           string     `json:"scope"`
+		Target          string     `json:"target"`
+		Reason          string     `json:"reason"`
+		StartsAt        *time.Time `json:"startsAt"`
+		EndsAt          *time.Time `json:"endsAt"`
+		DurationMinutes int        `json:"durationMinutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	now := time.Now()
+	sil := db.Silence{
+		ID:        generateSilenceID(),
+		Scope:     req.Scope,
+		Target:    strings.TrimSpace(req.Target),
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: requestActor(r, h.store),
+		StartsAt:  now,
+		CreatedAt: now,
+	}
+	if msg := h.validateSilenceTarget(&sil); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if sil.Reason == "" {
+		writeError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+	if len(sil.Reason) > maxSilenceReasonLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("reason must be at most %d characters", maxSilenceReasonLength))
+		return
+	}
+	if req.StartsAt != nil && req.StartsAt.After(now) {
+		sil.StartsAt = *req.StartsAt
+	}
+	switch {
+	case req.EndsAt != nil && req.DurationMinutes != 0:
+		writeError(w, http.StatusBadRequest, "set endsAt or durationMinutes, not both")
+		return
+	case req.EndsAt != nil:
+		sil.EndsAt = *req.EndsAt
+	case req.DurationMinutes > 0:
+		sil.EndsAt = sil.StartsAt.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	default:
+		writeError(w, http.StatusBadRequest, "endsAt or a positive durationMinutes is required")
+		return
+	}
+	if !sil.EndsAt.After(sil.StartsAt) {
+		writeError(w, http.StatusBadRequest, "endsAt must be after startsAt and in the future")
+		return
+	}
+	if sil.EndsAt.Sub(sil.StartsAt) > maxSilenceDuration {
+		writeError(w, http.StatusBadRequest, "a silence can last at most 90 days")
+		return
+	}
+
+	if err := h.store.CreateSilence(sil); err != nil {
+		log.Printf("ERROR: Failed to create silence: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to create silence")
+		return
+	}
+	h.manager.Sync()
+
+	log.Printf("AUDIT: [SILENCE] %s silenced %s %s until %s: %s", sanitizeLog(sil.CreatedBy), sil.Scope, sanitizeLog(sil.Target), sil.EndsAt.UTC().Format(time.RFC3339), sanitizeLog(sil.Reason)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusCreated, silenceResponse(sil, now))
+}
+
+// validateSilenceTarget checks that the silence's target exists, normalizing tags, and
+// returns an error message, or "" when valid.
+func (h *SilenceHandler) validateSilenceTarget(sil *db.Silence) string {
+	if sil.Target == "" {
+		return "target is required"
+	}
+	switch sil.Scope {
+	case db.SilenceScopeMonitor:
+		if _, err := h.store.GetMonitor(sil.Target); err != nil {
+			return "monitor not found: " + sil.Target
+		}
+	case db.SilenceScopeGroup:
+		groups, err := h.store.GetGroups()
+		if err != nil {
+			return "failed to load groups"
+		}
+		for _, g := range groups {
+			if g.ID == sil.Target {
+				return ""
+			}
+		}
+		return "group not found: " + sil.Target
+	case db.SilenceScopeTag:
+		tags, err := normalizeTags([]string{sil.Target})
+		if err != nil {
+			return err.Error()
+		}
+		sil.Target = tags[0]
+	default:
+		return "scope must be monitor, group or tag"
+	}
+	return ""
+}
+
+// GetSilence returns a silence.
+// @Summary      Get silence
+// @Tags         silences
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Silence ID"
+// @Success      200  {object} SilenceResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /silences/{id} [get]
+func (h *SilenceHandler) GetSilence(w http.ResponseWriter, r *http.Request) {
+	sil, err := h.store.GetSilence(chi.URLParam(r, "id"))
+	if errors.Is(err, db.ErrSilenceNotFound) {
+		writeError(w, http.StatusNotFound, "silence not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load silence")
+		return
+	}
+	writeJSON(w, http.StatusOK, silenceResponse(*sil, time.Now()))
+}
+
+// ExpireSilence ends a silence now, so notifications resume. It is kept, expired, for
+// the record.
+// @Summary      Expire silence
+// @Tags         silences
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Silence ID"
+// @Success      200  {object} SilenceResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /silences/{id} [delete]
+func (h *SilenceHandler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	now := time.Now()
+	if err := h.store.ExpireSilence(id, now); errors.Is(err, db.ErrSilenceNotFound) {
+		writeError(w, http.StatusNotFound, "silence not found")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to expire silence")
+		return
+	}
+	h.manager.Sync()
+
+	sil, err := h.store.GetSilence(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load silence")
+		return
+	}
+	log.Printf("AUDIT: [SILENCE] %s expired silence %s", sanitizeLog(requestActor(r, h.store)), sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, silenceResponse(*sil, now))
+}
+
+// requestActor names who made an authenticated request: the user's name, or "API key".
+func requestActor(r *http.Request, store *db.Store) string {
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+	if userID == APIKeyUserID {
+		return "API key"
+	}
+	if user, err := store.GetUser(userID); err == nil && user != nil {
+		return user.Username
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestSilencesAPI(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	manager := uptime.NewManager(s)
+	h := NewSilenceHandler(s, manager)
+	_ = s.CreateMonitor(db.Monitor{ID: "m-1", GroupID: "g-default", Name: "Web", URL: "http://web", Interval: 60, Tags: []string{"prod"}})
+	_ = s.CreateUser("alice", "password123", "UTC")
+	user, _ := s.Authenticate("alice", "password123")
+
+	r := chi.NewRouter()
+	r.Get("/api/silences", h.ListSilences)
+	r.Post("/api/silences", h.CreateSilence)
+	r.Get("/api/silences/{id}", h.GetSilence)
+	r.Delete("/api/silences/{id}", h.ExpireSilence)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), contextKeyUserID, user.ID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/api/silences", `{"scope":"tag","target":" PROD ","reason":"Deploying","durationMinutes":30}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created SilenceResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if !strings.HasPrefix(created.ID, "sil-") || created.Target != "prod" || created.CreatedBy != "alice" || !created.Active ||
+		created.EndsAt.Sub(created.StartsAt) != 30*time.Minute {
+		t.Errorf("Unexpected silence %+v", created)
+	}
+	if !manager.IsMonitorSilenced("m-1", time.Now()) {
+		t.Error("Expected the monitor to be silenced right away")
+	}
+
+	later := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	end := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	if w := do("POST", "/api/silences", `{"scope":"monitor","target":"m-1","reason":"Migration","startsAt":"`+later+`","endsAt":"`+end+`"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for an upcoming silence, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for name, body := range map[string]string{
+		"bad scope":       `{"scope":"team","target":"x","reason":"r","durationMinutes":5}`,
+		"unknown monitor": `{"scope":"monitor","target":"m-x","reason":"r","durationMinutes":5}`,
+		"unknown group":   `{"scope":"group","target":"g-x","reason":"r","durationMinutes":5}`,
+		"bad tag":         `{"scope":"tag","target":"a b","reason":"r","durationMinutes":5}`,
+		"no reason":       `{"scope":"group","target":"g-default","durationMinutes":5}`,
+		"no end":          `{"scope":"group","target":"g-default","reason":"r"}`,
+		"both ends":       `{"scope":"group","target":"g-default","reason":"r","durationMinutes":5,"endsAt":"` + end + `"}`,
+		"ended":           `{"scope":"group","target":"g-default","reason":"r","endsAt":"2020-01-01T00:00:00Z"}`,
+		"too long":        `{"scope":"group","target":"g-default","reason":"r","durationMinutes":200000}`,
+		"reason too long": `{"scope":"group","target":"g-default","reason":"` + strings.Repeat("x", maxSilenceReasonLength+1) + `","durationMinutes":5}`,
+		"malformed json":  `{`,
+	} {
+		if w := do("POST", "/api/silences", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, w.Code)
+		}
+	}
+
+	list := func(query string) []SilenceResponse {
+		t.Helper()
+		var resp []SilenceResponse
+		_ = json.Unmarshal(do("GET", "/api/silences"+query, "").Body.Bytes(), &resp)
+		return resp
+	}
+	if got := list(""); len(got) != 2 || got[0].Scope != db.SilenceScopeMonitor || got[0].Active {
+		t.Errorf("Expected the upcoming silence first and inactive, got %+v", got)
+	}
+
+	if w := do("DELETE", "/api/silences/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if manager.IsMonitorSilenced("m-1", time.Now()) {
+		t.Error("Expected notifications to resume once the silence is expired")
+	}
+	if got := list(""); len(got) != 1 {
+		t.Errorf("Expected the expired silence to be hidden, got %+v", got)
+	}
+	if got := list("?expired=true"); len(got) != 2 {
+		t.Errorf("Expected the expired silence with expired=true, got %+v", got)
+	}
+	if w := do("GET", "/api/silences/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the expired silence to be kept, got %d", w.Code)
+	}
+	if w := do("GET", "/api/silences/sil-missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+	if w := do("DELETE", "/api/silences/sil-missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
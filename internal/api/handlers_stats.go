@@ -2,16 +2,19 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
 )
 
 type StatsHandler struct {
-	store *db.Store
+	store   *db.Store
+	manager *uptime.Manager
 }
 
-func NewStatsHandler(store *db.Store) *StatsHandler {
-	return &StatsHandler{store: store}
+func NewStatsHandler(store *db.Store, manager *uptime.Manager) *StatsHandler {
+	return &StatsHandler{store: store, manager: manager}
 }
 
 // GetStats returns system statistics including monitor counts and DB size.
@@ -20,18 +23,18 @@ func NewStatsHandler(store *db.Store) *StatsHandler {
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object} object{version=string,dbSize=int,stats=db.SystemStats}
-// @Failure      500  {string} string "Failed to get stats"
+// @Failure      500  {object} ErrorResponse "Failed to get stats"
 // @Router       /stats [get]
 func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.store.GetSystemStats()
 	if err != nil {
-		http.Error(w, "Failed to get stats", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get stats")
 		return
 	}
 
 	dbSize, err := h.store.GetDBSize()
 	if err != nil {
-		http.Error(w, "Failed to get db size", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to get db size")
 		return
 	}
 
@@ -43,3 +46,45 @@ func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, response)
 }
+
+// GetCapacity estimates daily checks and bandwidth per group and overall, and warns
+// when the scheduled checks exceed what the worker pool can sustain.
+// @Summary      Get check capacity estimate
+// @Tags         stats
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} uptime.CapacityEstimate
+// @Failure      500  {object} ErrorResponse
+// @Router       /stats/capacity [get]
+func (h *StatsHandler) GetCapacity(w http.ResponseWriter, r *http.Request) {
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load groups")
+		return
+	}
+	latencies, err := h.store.GetAverageLatencies(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load latency stats")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, uptime.EstimateCapacity(monitors, groups, latencies))
+}
+
+// GetScheduler reports job queue usage and per-monitor scheduled, executed, dropped
+// and late check counts. Monitors whose recent ticks were all dropped because the
+// queue was full are flagged as skipping.
+// @Summary      Get scheduler metrics
+// @Tags         stats
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} uptime.SchedulerStats
+// @Router       /stats/scheduler [get]
+func (h *StatsHandler) GetScheduler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.manager.SchedulerStats())
+}
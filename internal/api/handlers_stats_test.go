@@ -1,16 +1,19 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
 )
 
 func TestGetStats(t *testing.T) {
 	s, _ := db.NewStore(db.NewTestConfig())
-	h := NewStatsHandler(s)
+	h := NewStatsHandler(s, nil)
 
 	req := httptest.NewRequest("GET", "/api/stats", nil)
 	w := httptest.NewRecorder()
@@ -21,3 +24,52 @@ func TestGetStats(t *testing.T) {
 		t.Errorf("Expected 200, got %d", w.Code)
 	}
 }
+
+func TestGetCapacity(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewStatsHandler(s, nil)
+	_ = s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "M1", URL: "http://a.com", Interval: 60, Active: true})
+	_ = s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: time.Now(), StatusCode: 200},
+		{MonitorID: "m1", Status: "up", Latency: 300, Timestamp: time.Now(), StatusCode: 200},
+	})
+
+	w := httptest.NewRecorder()
+	h.GetCapacity(w, httptest.NewRequest("GET", "/api/stats/capacity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var est uptime.CapacityEstimate
+	if err := json.Unmarshal(w.Body.Bytes(), &est); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if est.Workers != uptime.WorkerCount || est.Global.ChecksPerDay != 1440 {
+		t.Errorf("Unexpected estimate: %+v", est)
+	}
+	// 200ms average latency lets each worker run 5 checks per second
+	if est.MaxChecksPerSecond != float64(uptime.WorkerCount)*5 {
+		t.Errorf("Expected max %d checks/s, got %v", uptime.WorkerCount*5, est.MaxChecksPerSecond)
+	}
+	if len(est.Groups) != 1 || est.Groups[0].GroupID != "g-default" || est.Groups[0].Monitors != 1 {
+		t.Errorf("Unexpected groups: %+v", est.Groups)
+	}
+}
+
+func TestGetScheduler(t *testing.T) {
+	s, _ := db.NewStore(db.NewTestConfig())
+	h := NewStatsHandler(s, uptime.NewManager(s))
+
+	w := httptest.NewRecorder()
+	h.GetScheduler(w, httptest.NewRequest("GET", "/api/stats/scheduler", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var resp uptime.SchedulerStats
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Workers != uptime.WorkerCount || resp.QueueCapacity == 0 {
+		t.Errorf("unexpected scheduler stats %+v", resp)
+	}
+}
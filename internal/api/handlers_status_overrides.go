@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxStatusOverrideMessage bounds the note shown next to an overridden status.
+const maxStatusOverrideMessage = 500
+
+// GetStatusOverride returns a monitor's active status page override and its audit history.
+// @Summary      Get status page override
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{active=db.StatusOverride,history=[]db.StatusOverride}
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/status-override [get]
+func (h *StatusPageHandler) GetStatusOverride(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !h.monitorExists(w, id) {
+		return
+	}
+
+	history, err := h.store.GetStatusOverrides(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status overrides")
+		return
+	}
+
+	var active *db.StatusOverride
+	now := time.Now()
+	for i := range history {
+		if history[i].Active(now) {
+			active = &history[i]
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"active":  active,
+		"history": history,
+	})
+}
+
+// SetStatusOverride shows a manual status for a monitor on public status pages, replacing
+// any override already in effect. Alerting and the live status are unaffected.
+// @Summary      Set status page override
+// @Tags         status-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Param        request body object{status=string,message=string,expiresAt=string} true "Override (status: up, degraded or down; expiresAt RFC3339, omit to keep until cleared)"
+// @Success      200  {object} db.StatusOverride
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/status-override [put]
+func (h *StatusPageHandler) SetStatusOverride(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		Status    string `json:"status"`
+		Message   string `json:"message"`
+		ExpiresAt string `json:"expiresAt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	switch req.Status {
+	case db.OverrideStatusUp, db.OverrideStatusDegraded, db.OverrideStatusDown:
+	default:
+		writeError(w, http.StatusBadRequest, "status must be up, degraded or down")
+		return
+	}
+	if len(req.Message) > maxStatusOverrideMessage {
+		writeError(w, http.StatusBadRequest, "message is too long")
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "expiresAt must be an RFC3339 timestamp")
+			return
+		}
+		if !t.After(time.Now()) {
+			writeError(w, http.StatusBadRequest, "expiresAt must be in the future")
+			return
+		}
+		expiresAt = &t
+	}
+
+	if !h.monitorExists(w, id) {
+		return
+	}
+
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+	o := db.StatusOverride{
+		MonitorID: id,
+		Status:    req.Status,
+		Message:   req.Message,
+		CreatedBy: userID,
+		ExpiresAt: expiresAt,
+	}
+	overrideID, err := h.store.SetStatusOverride(o)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save status override")
+		return
+	}
+	o.ID = overrideID
+	o.CreatedAt = time.Now().UTC()
+
+	h.cache.invalidate()
+	log.Printf("AUDIT: [STATUS] User %d overrode status page status of monitor %s to %s", userID, sanitizeLog(id), sanitizeLog(req.Status)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, o)
+}
+
+// ClearStatusOverride returns a monitor to its live status on public status pages.
+// @Summary      Clear status page override
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse "No override in effect"
+// @Router       /monitors/{id}/status-override [delete]
+func (h *StatusPageHandler) ClearStatusOverride(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+
+	cleared, err := h.store.ClearStatusOverride(id, userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to clear status override")
+		return
+	}
+	if !cleared {
+		writeError(w, http.StatusNotFound, "no status override in effect")
+		return
+	}
+
+	h.cache.invalidate()
+	log.Printf("AUDIT: [STATUS] User %d cleared status page override of monitor %s", userID, sanitizeLog(id)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "cleared"})
+}
+
+// monitorExists writes a 404 or 500 and returns false if the monitor can't be loaded.
+func (h *StatusPageHandler) monitorExists(w http.ResponseWriter, id string) bool {
+	if _, err := h.store.GetMonitor(id); err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+			return false
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return false
+	}
+	return true
+}
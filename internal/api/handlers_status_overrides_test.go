@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// overrideRequest builds an authenticated request for the status override endpoints.
+func overrideRequest(method, monitorID string, body interface{}) *http.Request {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, "/api/monitors/"+monitorID+"/status-override", &buf)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", monitorID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, contextKeyUserID, int64(1))
+	return req.WithContext(ctx)
+}
+
+func TestStatusOverride_LayeredOverPublicStatus(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-ov", "Override Group")
+	seedMonitor(t, store, "m-ov", "g-ov", "API")
+	gid := "g-ov"
+	seedPage(t, store, "ov", "Override Page", &gid, true, true)
+
+	expires := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	w := httptest.NewRecorder()
+	spH.SetStatusOverride(w, overrideRequest("PUT", "m-ov", map[string]string{
+		"status": "degraded", "message": "Elevated error rates in EU", "expiresAt": expires,
+	}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/ov", "ov", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	groups := decodeJSON(t, w)["groups"].([]interface{})
+	mon := groups[0].(map[string]interface{})["monitors"].([]interface{})[0].(map[string]interface{})
+	if mon["status"] != "degraded" || mon["statusMessage"] != "Elevated error rates in EU" {
+		t.Errorf("expected overridden status, got status=%v message=%v", mon["status"], mon["statusMessage"])
+	}
+
+	// Audit trail records who set it
+	w = httptest.NewRecorder()
+	spH.GetStatusOverride(w, overrideRequest("GET", "m-ov", nil))
+	body := decodeJSON(t, w)
+	active := body["active"].(map[string]interface{})
+	if active["createdBy"].(float64) != 1 || active["expiresAt"] == nil {
+		t.Errorf("unexpected active override: %+v", active)
+	}
+
+	w = httptest.NewRecorder()
+	spH.ClearStatusOverride(w, overrideRequest("DELETE", "m-ov", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 on clear, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/ov", "ov", nil))
+	groups = decodeJSON(t, w)["groups"].([]interface{})
+	mon = groups[0].(map[string]interface{})["monitors"].([]interface{})[0].(map[string]interface{})
+	if mon["status"] == "degraded" || mon["statusMessage"] != nil {
+		t.Errorf("expected live status after clear, got status=%v message=%v", mon["status"], mon["statusMessage"])
+	}
+
+	w = httptest.NewRecorder()
+	spH.ClearStatusOverride(w, overrideRequest("DELETE", "m-ov", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when nothing to clear, got %d", w.Code)
+	}
+}
+
+func TestStatusOverride_Validation(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-ov2", "G")
+	seedMonitor(t, store, "m-ov2", "g-ov2", "M")
+
+	cases := []struct {
+		name string
+		id   string
+		body map[string]string
+		want int
+	}{
+		{"bad status", "m-ov2", map[string]string{"status": "on-fire"}, http.StatusBadRequest},
+		{"bad expiry", "m-ov2", map[string]string{"status": "down", "expiresAt": "tomorrow"}, http.StatusBadRequest},
+		{"past expiry", "m-ov2", map[string]string{"status": "down", "expiresAt": time.Now().Add(-time.Hour).Format(time.RFC3339)}, http.StatusBadRequest},
+		{"unknown monitor", "nope", map[string]string{"status": "down"}, http.StatusNotFound},
+		{"no expiry", "m-ov2", map[string]string{"status": "up"}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		spH.SetStatusOverride(w, overrideRequest("PUT", tc.id, tc.body))
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d (body: %s)", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/go-chi/chi/v5"
+)
+
+// incidentArchiveSchemaVersion is bumped whenever a field of the archive is renamed,
+// removed or changes meaning. Additive changes keep the version.
+const incidentArchiveSchemaVersion = 1
+
+const (
+	incidentArchiveDefaultPageSize = 50
+	incidentArchiveMaxPageSize     = 100
+	jsonAPIContentType             = "application/vnd.api+json"
+)
+
+// archiveResource is a JSON:API resource object.
+type archiveResource struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id"`
+	Attributes    any                            `json:"attributes"`
+	Relationships map[string]archiveRelationship `json:"relationships,omitempty"`
+}
+
+type archiveRelationship struct {
+	Data []archiveResourceID `json:"data"`
+}
+
+type archiveResourceID struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type archiveIncidentAttributes struct {
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Kind           string     `json:"kind"`     // incident | maintenance
+	Severity       string     `json:"severity"` // empty for maintenance
+	Status         string     `json:"status"`
+	StartedAt      time.Time  `json:"startedAt"`
+	EndedAt        *time.Time `json:"endedAt"`
+	AffectedGroups []string   `json:"affectedGroups"` // empty = every group
+}
+
+type archiveUpdateAttributes struct {
+	Status    string     `json:"status"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+}
+
+// incidentArchiveResponse is a JSON:API document listing a status page's incident history.
+type incidentArchiveResponse struct {
+	JSONAPI  map[string]string `json:"jsonapi"`
+	Meta     map[string]any    `json:"meta"`
+	Links    map[string]string `json:"links"`
+	Data     []archiveResource `json:"data"`
+	Included []archiveResource `json:"included"`
+}
+
+// jsonAPIError writes a JSON:API error document.
+func jsonAPIError(w http.ResponseWriter, status int, detail string) {
+	apiErr := map[string]any{"status": strconv.Itoa(status), "code": statusErrorCode(status), "detail": detail}
+	if id := w.Header().Get(RequestIDHeader); id != "" {
+		apiErr["meta"] = map[string]string{"requestId": id}
+	}
+	w.Header().Set("Content-Type", jsonAPIContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]any{apiErr}})
+}
+
+// GetIncidentArchive returns the public incident and maintenance history of a status page
+// as a JSON:API document, newest first, for ingestion into external tooling.
+// @Summary      Status page incident archive
+// @Tags         status-pages
+// @Produce      application/vnd.api+json
+// @Param        slug           path  string true  "Status page slug"
+// @Param        schema_version query int    false "Schema version the client expects (currently 1)"
+// @Param        page[number]   query int    false "Page number, starting at 1"
+// @Param        page[size]     query int    false "Incidents per page (max 100, default 50)"
+// @Param        filter[since]  query string false "Only incidents started at or after this RFC 3339 time"
+// @Success      200  {object} incidentArchiveResponse
+// @Failure      400  {object} object{errors=[]object{status=string,code=string,detail=string}} "Invalid parameter or unsupported schema version"
+// @Failure      401  {object} object{errors=[]object{status=string,code=string,detail=string}} "Status page is private"
+// @Failure      404  {object} object{errors=[]object{status=string,code=string,detail=string}} "Status page not found"
+// @Router       /s/{slug}/incidents.json [get]
+func (h *StatusPageHandler) GetIncidentArchive(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	q := r.URL.Query()
+
+	if v := q.Get("schema_version"); v != "" && v != strconv.Itoa(incidentArchiveSchemaVersion) {
+		jsonAPIError(w, http.StatusBadRequest, "unsupported schema_version; the current version is "+strconv.Itoa(incidentArchiveSchemaVersion))
+		return
+	}
+
+	pageNumber, pageSize := 1, incidentArchiveDefaultPageSize
+	if v := q.Get("page[number]"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			jsonAPIError(w, http.StatusBadRequest, "page[number] must be a positive integer")
+			return
+		}
+		pageNumber = n
+	}
+	if v := q.Get("page[size]"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > incidentArchiveMaxPageSize {
+			jsonAPIError(w, http.StatusBadRequest, "page[size] must be between 1 and "+strconv.Itoa(incidentArchiveMaxPageSize))
+			return
+		}
+		pageSize = n
+	}
+	var since time.Time
+	if v := q.Get("filter[since]"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			jsonAPIError(w, http.StatusBadRequest, "filter[since] must be an RFC 3339 timestamp")
+			return
+		}
+		since = t
+	}
+
+	page, err := h.store.GetStatusPageBySlug(slug)
+	if err != nil {
+		jsonAPIError(w, http.StatusInternalServerError, "error fetching status page")
+		return
+	}
+	if page == nil || !page.Enabled {
+		jsonAPIError(w, http.StatusNotFound, "status page not found")
+		return
+	}
+	if !page.Public && !h.auth.IsAuthenticated(r) {
+		jsonAPIError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	filter := db.IncidentFilter{Since: since}
+	if page.GroupID != nil {
+		filter.GroupID = *page.GroupID
+	}
+	incidents, err := h.store.GetIncidentsFiltered(filter)
+	if err != nil {
+		jsonAPIError(w, http.StatusInternalServerError, "failed to load incidents")
+		return
+	}
+
+	type archived struct {
+		inc    db.Incident
+		groups []string
+	}
+	var matched []archived
+	for _, inc := range incidents {
+		// SECURITY: Only published incidents are part of the archive
+		if !inc.Public || inc.StartTime.Before(since) {
+			continue
+		}
+		groups := []string{}
+		if inc.AffectedGroups != "" {
+			_ = json.Unmarshal([]byte(inc.AffectedGroups), &groups)
+		}
+		matched = append(matched, archived{inc: inc, groups: groups})
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].inc.StartTime.Equal(matched[j].inc.StartTime) {
+			return matched[i].inc.StartTime.After(matched[j].inc.StartTime)
+		}
+		return matched[i].inc.ID < matched[j].inc.ID
+	})
+
+	total := len(matched)
+	start := (pageNumber - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	resp := incidentArchiveResponse{
+		JSONAPI: map[string]string{"version": "1.1"},
+		Meta: map[string]any{
+			"schemaVersion": incidentArchiveSchemaVersion,
+			"statusPage":    slug,
+			"generatedAt":   time.Now().UTC(),
+			"total":         total,
+		},
+		Links:    map[string]string{"self": archivePageLink(slug, q, pageNumber, pageSize)},
+		Data:     []archiveResource{},
+		Included: []archiveResource{},
+	}
+	if end < total {
+		resp.Links["next"] = archivePageLink(slug, q, pageNumber+1, pageSize)
+	}
+	if pageNumber > 1 {
+		resp.Links["prev"] = archivePageLink(slug, q, pageNumber-1, pageSize)
+	}
+
+	for _, a := range matched[start:end] {
+		inc := a.inc
+		severity := inc.Severity
+		if inc.Type == "maintenance" {
+			severity = ""
+		}
+		resource := archiveResource{
+			Type: "incidents",
+			ID:   inc.ID,
+			Attributes: archiveIncidentAttributes{
+				Title:          inc.Title,
+				Description:    inc.Description,
+				Kind:           inc.Type,
+				Severity:       severity,
+				Status:         inc.Status,
+				StartedAt:      inc.StartTime,
+				EndedAt:        inc.EndTime,
+				AffectedGroups: a.groups,
+			},
+			Relationships: map[string]archiveRelationship{"updates": {Data: []archiveResourceID{}}},
+		}
+
+		updates, err := h.store.GetIncidentUpdates(inc.ID)
+		if err != nil {
+			jsonAPIError(w, http.StatusInternalServerError, "failed to load incident updates")
+			return
+		}
+		for _, u := range updates {
+			id := strconv.FormatInt(u.ID, 10)
+			rel := resource.Relationships["updates"]
+			rel.Data = append(rel.Data, archiveResourceID{Type: "incident-updates", ID: id})
+			resource.Relationships["updates"] = rel
+			resp.Included = append(resp.Included, archiveResource{
+				Type:       "incident-updates",
+				ID:         id,
+				Attributes: archiveUpdateAttributes{Status: u.Status, Message: u.Message, CreatedAt: u.CreatedAt, EditedAt: u.EditedAt},
+			})
+		}
+		resp.Data = append(resp.Data, resource)
+	}
+
+	w.Header().Set("Content-Type", jsonAPIContentType)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// archivePageLink builds the relative URL of an archive page, keeping the caller's filters.
+func archivePageLink(slug string, q url.Values, number, size int) string {
+	params := url.Values{}
+	for k, v := range q {
+		params[k] = v
+	}
+	params.Set("page[number]", strconv.Itoa(number))
+	params.Set("page[size]", strconv.Itoa(size))
+	return "/api/s/" + url.PathEscape(slug) + "/incidents.json?" + params.Encode()
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeArchive(t *testing.T, w *httptest.ResponseRecorder) incidentArchiveResponse {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != jsonAPIContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, jsonAPIContentType)
+	}
+	var resp struct {
+		incidentArchiveResponse
+		Data []struct {
+			archiveResource
+			Attributes    archiveIncidentAttributes      `json:"attributes"`
+			Relationships map[string]archiveRelationship `json:"relationships"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	out := resp.incidentArchiveResponse
+	out.Data = nil
+	for _, d := range resp.Data {
+		res := d.archiveResource
+		res.Attributes = d.Attributes
+		res.Relationships = d.Relationships
+		out.Data = append(out.Data, res)
+	}
+	return out
+}
+
+func TestGetIncidentArchive(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-arch", "Target")
+	seedGroup(t, store, "g-arch-other", "Other")
+	gid := "g-arch"
+	seedPage(t, store, "arch", "Archive", &gid, true, true)
+
+	seedIncident(t, store, "old", "Old outage", "incident", "major", "resolved", true, []string{"g-arch"}, -400*24*time.Hour)
+	seedIncident(t, store, "global", "Global outage", "incident", "critical", "resolved", true, nil, -48*time.Hour)
+	seedIncident(t, store, "recent", "Recent outage", "incident", "minor", "investigating", true, []string{"g-arch"}, -time.Hour)
+	seedIncident(t, store, "private", "Private", "incident", "major", "investigating", false, []string{"g-arch"}, -time.Hour)
+	seedIncident(t, store, "other", "Other group", "incident", "major", "resolved", true, []string{"g-arch-other"}, -time.Hour)
+	seedMaintenance(t, store, "maint", "completed", []string{"g-arch"}, -72*time.Hour, time.Hour)
+	if err := store.CreateIncidentUpdate("recent", "identified", "Found it"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/arch/incidents.json", "arch", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+	resp := decodeArchive(t, w)
+
+	var ids []string
+	for _, d := range resp.Data {
+		ids = append(ids, d.ID)
+	}
+	if got := strings.Join(ids, ","); got != "recent,global,maint,old" {
+		t.Fatalf("incidents = %q, want recent,global,maint,old", got)
+	}
+	if v, _ := resp.Meta["schemaVersion"].(float64); int(v) != incidentArchiveSchemaVersion {
+		t.Errorf("meta.schemaVersion = %v", resp.Meta["schemaVersion"])
+	}
+
+	recent := resp.Data[0]
+	attrs := recent.Attributes.(archiveIncidentAttributes)
+	if attrs.Kind != "incident" || attrs.Severity != "minor" || attrs.Status != "investigating" {
+		t.Errorf("unexpected attributes %+v", attrs)
+	}
+	if rel := recent.Relationships["updates"].Data; len(rel) != 1 || rel[0].Type != "incident-updates" {
+		t.Fatalf("expected one update relationship, got %+v", rel)
+	}
+	if len(resp.Included) != 1 || resp.Included[0].ID != recent.Relationships["updates"].Data[0].ID {
+		t.Fatalf("expected the update in included, got %+v", resp.Included)
+	}
+	if maint := resp.Data[2].Attributes.(archiveIncidentAttributes); maint.Kind != "maintenance" || maint.Severity != "" {
+		t.Errorf("unexpected maintenance attributes %+v", maint)
+	}
+}
+
+func TestGetIncidentArchive_PaginationAndFilters(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "arch-all", "Archive", nil, true, true)
+	for i, id := range []string{"a", "b", "c"} {
+		seedIncident(t, store, id, id, "incident", "minor", "resolved", true, nil, -time.Duration(i+1)*24*time.Hour)
+	}
+
+	w := httptest.NewRecorder()
+	spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/arch-all/incidents.json?page[size]=2", "arch-all", nil))
+	resp := decodeArchive(t, w)
+	if len(resp.Data) != 2 || resp.Data[0].ID != "a" || resp.Links["next"] == "" || resp.Links["prev"] != "" {
+		t.Fatalf("unexpected first page: %+v links=%v", resp.Data, resp.Links)
+	}
+	if total, _ := resp.Meta["total"].(float64); total != 3 {
+		t.Errorf("meta.total = %v, want 3", resp.Meta["total"])
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/arch-all/incidents.json?page[size]=2&page[number]=2", "arch-all", nil))
+	resp = decodeArchive(t, w)
+	if len(resp.Data) != 1 || resp.Data[0].ID != "c" || resp.Links["next"] != "" || resp.Links["prev"] == "" {
+		t.Fatalf("unexpected second page: %+v links=%v", resp.Data, resp.Links)
+	}
+
+	since := time.Now().Add(-36 * time.Hour).UTC().Format(time.RFC3339)
+	w = httptest.NewRecorder()
+	spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/arch-all/incidents.json?filter[since]="+since, "arch-all", nil))
+	resp = decodeArchive(t, w)
+	if len(resp.Data) != 1 || resp.Data[0].ID != "a" {
+		t.Fatalf("expected only a since %s, got %+v", since, resp.Data)
+	}
+
+	for _, query := range []string{"schema_version=2", "page[size]=101", "page[number]=0", "filter[since]=yesterday"} {
+		w = httptest.NewRecorder()
+		spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/arch-all/incidents.json?"+query, "arch-all", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetIncidentArchive_Access(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "arch-private", "Private", nil, false, true)
+	seedPage(t, store, "arch-disabled", "Disabled", nil, true, false)
+
+	tests := []struct {
+		slug string
+		want int
+	}{
+		{"arch-private", http.StatusUnauthorized},
+		{"arch-disabled", http.StatusNotFound},
+		{"missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		spH.GetIncidentArchive(w, makeRequest("GET", "/api/s/"+tt.slug+"/incidents.json", tt.slug, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.slug, tt.want, w.Code)
+		}
+	}
+}
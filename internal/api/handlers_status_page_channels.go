@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxStatusPageChannels bounds how many notification channels a status page tells about
+// its incidents.
+const maxStatusPageChannels = 20
+
+// GetChannels returns the IDs of the notification channels told when a public incident
+// shown on the status page opens, is updated or resolves.
+// @Summary      Get status page channels
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} object{channelIds=[]string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /status-pages/{slug}/channels [get]
+func (h *StatusPageHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	if h.loadStatusPage(w, slug) == nil {
+		return
+	}
+	ids, err := h.store.GetStatusPageChannels(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page channels")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"channelIds": ids})
+}
+
+// SetChannels replaces the notification channels told about the status page's incidents.
+// Those channels get incident events for this page only; an empty list stops them.
+// @Summary      Set status page channels
+// @Tags         status-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug path string true "Status page slug"
+// @Param        body body object{channelIds=[]string} true "Notification channel IDs"
+// @Success      200  {object} object{channelIds=[]string}
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /status-pages/{slug}/channels [put]
+func (h *StatusPageHandler) SetChannels(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChannelIDs []string `json:"channelIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.ChannelIDs) > maxStatusPageChannels {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d channels are allowed", maxStatusPageChannels))
+		return
+	}
+
+	slug := chi.URLParam(r, "slug")
+	if h.loadStatusPage(w, slug) == nil {
+		return
+	}
+	channels, err := h.store.GetNotificationChannels()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load notification channels")
+		return
+	}
+	global := make(map[string]bool, len(channels))
+	for _, ch := range channels {
+		global[ch.ID] = ch.MonitorID == ""
+	}
+	for _, id := range req.ChannelIDs {
+		isGlobal, ok := global[id]
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("notification channel %q does not exist", id))
+			return
+		}
+		// Watchers belong to their monitor
+		if !isGlobal {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("notification channel %q watches a monitor and can't be used for a status page", id))
+			return
+		}
+	}
+
+	if err := h.store.SetStatusPageChannels(slug, req.ChannelIDs); err != nil {
+		log.Printf("ERROR: Failed to set status page channels: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to save status page channels")
+		return
+	}
+	log.Printf("AUDIT: [STATUS] Status page %s notifies %d channels of its incidents", sanitizeLog(slug), len(req.ChannelIDs)) // #nosec G706 -- sanitized
+	ids, err := h.store.GetStatusPageChannels(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page channels")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"channelIds": ids})
+}
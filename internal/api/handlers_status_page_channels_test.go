@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestStatusPageChannels(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	_ = s.CreateGroup(db.Group{ID: "g-eu", Name: "EU"})
+	_ = s.CreateGroup(db.Group{ID: "g-us", Name: "US"})
+	eu, us := "g-eu", "g-us"
+	_ = s.CreateStatusPage(db.StatusPageInput{Slug: "eu", Title: "EU Status", GroupID: &eu, Enabled: true})
+	_ = s.CreateStatusPage(db.StatusPageInput{Slug: "us", Title: "US Status", GroupID: &us, Enabled: true})
+	_ = s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-eu", Name: "API", URL: "http://example.com", Interval: 60})
+	for _, ch := range []db.NotificationChannel{
+		{ID: "nc-eu", Type: "webhook", Name: "EU subscribers", Config: "{}", Enabled: true},
+		{ID: "nc-us", Type: "webhook", Name: "US subscribers", Config: "{}", Enabled: true},
+		{ID: "nc-oncall", Type: "webhook", Name: "On-call", Config: "{}", Enabled: true},
+		{ID: "nc-watcher", Type: "webhook", Name: "Watcher", Config: "{}", Enabled: true, MonitorID: "m1"},
+	} {
+		if err := s.CreateNotificationChannel(ch); err != nil {
+			t.Fatalf("CreateNotificationChannel failed: %v", err)
+		}
+	}
+
+	m := uptime.NewManager(s)
+	h := NewStatusPageHandler(s, m, nil)
+	r := chi.NewRouter()
+	r.Get("/api/status-pages/{slug}/channels", h.GetChannels)
+	r.Put("/api/status-pages/{slug}/channels", h.SetChannels)
+
+	for _, body := range []map[string]any{
+		{"channelIds": []string{"nc-missing"}},
+		{"channelIds": []string{"nc-watcher"}},
+	} {
+		if rr := doShadowRequest(r, "PUT", "/api/status-pages/eu/channels", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/status-pages/missing/channels", map[string]any{"channelIds": []string{}}); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown page, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/status-pages/eu/channels", map[string]any{"channelIds": []string{"nc-eu"}}); rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	_ = doShadowRequest(r, "PUT", "/api/status-pages/us/channels", map[string]any{"channelIds": []string{"nc-us"}})
+	rr := doShadowRequest(r, "GET", "/api/status-pages/eu/channels", nil)
+	var got struct {
+		ChannelIDs []string `json:"channelIds"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &got)
+	if len(got.ChannelIDs) != 1 || got.ChannelIDs[0] != "nc-eu" {
+		t.Errorf("Expected nc-eu, got %s", rr.Body.String())
+	}
+
+	// A public EU incident reaches the EU page's channel only, each step of the way
+	incH := NewIncidentHandler(s, m)
+	ir := chi.NewRouter()
+	ir.Post("/api/incidents", incH.CreateIncident)
+	ir.Post("/api/incidents/{id}/updates", incH.AddUpdate)
+	rr = doShadowRequest(ir, "POST", "/api/incidents", map[string]any{
+		"title": "EU API outage", "description": "Investigating", "severity": "major", "status": "investigating",
+		"startTime": time.Now().UTC().Format(time.RFC3339), "affectedGroups": []string{"g-eu"}, "public": true,
+	})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var inc struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &inc)
+	_ = doShadowRequest(ir, "POST", "/api/incidents/"+inc.ID+"/updates", map[string]any{"status": "resolved", "message": "Fixed"})
+
+	// A private incident reaches nobody
+	_ = doShadowRequest(ir, "POST", "/api/incidents", map[string]any{
+		"title": "Internal", "severity": "minor", "status": "investigating",
+		"startTime": time.Now().UTC().Format(time.RFC3339), "affectedGroups": []string{"g-eu"},
+	})
+
+	items, err := s.ClaimDueNotifications(time.Now().Add(time.Minute), time.Minute, 100)
+	if err != nil {
+		t.Fatalf("ClaimDueNotifications failed: %v", err)
+	}
+	var sent []string
+	for _, item := range items {
+		var event notifications.NotificationEvent
+		_ = json.Unmarshal([]byte(item.Payload), &event)
+		if event.Incident == nil || event.Incident.PageSlug != "eu" || event.Incident.PageTitle != "EU Status" {
+			t.Errorf("Expected the EU page in the event, got %+v", event.Incident)
+		}
+		sent = append(sent, item.ChannelID+" "+string(event.Type))
+	}
+	sort.Strings(sent)
+	want := []string{"nc-eu incident_opened", "nc-eu incident_resolved"}
+	if len(sent) != len(want) || sent[0] != want[0] || sent[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, sent)
+	}
+}
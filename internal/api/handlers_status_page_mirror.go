@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/statusmirror"
+)
+
+// maxMirrorComponents bounds how many monitors and groups a mirror maps to components.
+const maxMirrorComponents = 100
+
+// hostedIDRe matches the page and component IDs of Statuspage.io and Instatus.
+var hostedIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// statusPageMirrorDTO is a mirror as returned by the API: its API key is write-only.
+type statusPageMirrorDTO struct {
+	db.StatusPageMirror
+	APIKeyConfigured bool `json:"apiKeyConfigured"`
+}
+
+// loadStatusPage returns the page with the slug, or writes a 404 and returns nil.
+func (h *StatusPageHandler) loadStatusPage(w http.ResponseWriter, slug string) *db.StatusPage {
+	page, err := h.store.GetStatusPageBySlug(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page")
+		return nil
+	}
+	if page == nil {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
+		return nil
+	}
+	return page
+}
+
+// GetMirror returns how a status page is copied to a hosted Statuspage.io or Instatus page.
+// @Summary      Get status page mirror
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} statusPageMirrorDTO
+// @Failure      404  {object} ErrorResponse
+// @Router       /status-pages/{slug}/mirror [get]
+func (h *StatusPageHandler) GetMirror(w http.ResponseWriter, r *http.Request) {
+	mirror, err := h.store.GetStatusPageMirror(chi.URLParam(r, "slug"))
+	if errors.Is(err, db.ErrStatusPageMirrorNotFound) {
+		writeError(w, http.StatusNotFound, "status page is not mirrored")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page mirror")
+		return
+	}
+	writeJSON(w, http.StatusOK, statusPageMirrorDTO{StatusPageMirror: *mirror, APIKeyConfigured: mirror.APIKey != ""})
+}
+
+// SetMirror creates or replaces a status page's mirror: the statuses of the monitors and
+// groups in components are pushed to the hosted page's components they map to, and the
+// page's public incidents are opened and updated there. apiKey may be omitted to keep
+// the stored one.
+// @Summary      Set status page mirror
+// @Tags         status-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug  path string true "Status page slug"
+// @Param        body  body object{provider=string,pageId=string,apiKey=string,components=object,enabled=bool} true "Hosted page (provider is statuspage or instatus) and monitor or group ID to component ID map"
+// @Success      200  {object} statusPageMirrorDTO
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /status-pages/{slug}/mirror [put]
+func (h *StatusPageHandler) SetMirror(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Provider   string            `json:"provider"`
+		PageID     string            `json:"pageId"`
+		APIKey     string            `json:"apiKey"`
+		Components map[string]string `json:"components"`
+		Enabled    *bool             `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Provider != statusmirror.ProviderStatuspage && req.Provider != statusmirror.ProviderInstatus {
+		writeError(w, http.StatusBadRequest, `provider must be "statuspage" or "instatus"`)
+		return
+	}
+	if !hostedIDRe.MatchString(req.PageID) {
+		writeError(w, http.StatusBadRequest, "pageId must be the hosted page's ID")
+		return
+	}
+	if len(req.APIKey) > 256 || strings.ContainsAny(req.APIKey, " \t\r\n") {
+		writeError(w, http.StatusBadRequest, "invalid apiKey")
+		return
+	}
+	if len(req.Components) > maxMirrorComponents {
+		writeError(w, http.StatusBadRequest, "too many components (max 100)")
+		return
+	}
+	for _, componentID := range req.Components {
+		if !hostedIDRe.MatchString(componentID) {
+			writeError(w, http.StatusBadRequest, "components must map to the hosted page's component IDs")
+			return
+		}
+	}
+
+	slug := chi.URLParam(r, "slug")
+	page := h.loadStatusPage(w, slug)
+	if page == nil {
+		return
+	}
+
+	// Only what the page shows can be mirrored
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load groups")
+		return
+	}
+	shown := make(map[string]bool)
+	for _, g := range groups {
+		if page.GroupID == nil || g.ID == *page.GroupID {
+			shown[g.ID] = true
+		}
+	}
+	for _, m := range monitors {
+		if shown[m.GroupID] {
+			shown[m.ID] = true
+		}
+	}
+	for key := range req.Components {
+		if !shown[key] {
+			writeError(w, http.StatusBadRequest, "components must be keyed by monitors or groups on this status page")
+			return
+		}
+	}
+
+	if req.APIKey == "" {
+		existing, err := h.store.GetStatusPageMirror(slug)
+		if err != nil && !errors.Is(err, db.ErrStatusPageMirrorNotFound) {
+			writeError(w, http.StatusInternalServerError, "failed to load status page mirror")
+			return
+		}
+		if existing == nil {
+			writeError(w, http.StatusBadRequest, "apiKey is required")
+			return
+		}
+		req.APIKey = existing.APIKey
+	}
+
+	if err := h.store.SetStatusPageMirror(db.StatusPageMirror{
+		Slug:       slug,
+		Provider:   req.Provider,
+		PageID:     req.PageID,
+		APIKey:     req.APIKey,
+		Components: req.Components,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save status page mirror")
+		return
+	}
+
+	mirror, err := h.store.GetStatusPageMirror(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page mirror")
+		return
+	}
+	log.Printf("AUDIT: [STATUS] Status page %s mirrored to %s page %s", sanitizeLog(slug), mirror.Provider, sanitizeLog(mirror.PageID)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, statusPageMirrorDTO{StatusPageMirror: *mirror, APIKeyConfigured: true})
+}
+
+// DeleteMirror stops copying a status page to its hosted page. What was copied stays there.
+// @Summary      Delete status page mirror
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /status-pages/{slug}/mirror [delete]
+func (h *StatusPageHandler) DeleteMirror(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	deleted, err := h.store.DeleteStatusPageMirror(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete status page mirror")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "status page is not mirrored")
+		return
+	}
+	log.Printf("AUDIT: [STATUS] Status page %s no longer mirrored", sanitizeLog(slug)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
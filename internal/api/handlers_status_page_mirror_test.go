@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestStatusPageMirror(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	group := "g-default"
+	if err := s.CreateStatusPage(db.StatusPageInput{Slug: "ops", Title: "Ops", GroupID: &group, Enabled: true}); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	_ = s.CreateGroup(db.Group{ID: "g-other", Name: "Other"})
+	_ = s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "API", URL: "http://example.com", Interval: 60})
+	_ = s.CreateMonitor(db.Monitor{ID: "m2", GroupID: "g-other", Name: "Other", URL: "http://example.org", Interval: 60})
+
+	h := NewStatusPageHandler(s, uptime.NewManager(s), nil)
+	r := chi.NewRouter()
+	r.Get("/api/status-pages/{slug}/mirror", h.GetMirror)
+	r.Put("/api/status-pages/{slug}/mirror", h.SetMirror)
+	r.Delete("/api/status-pages/{slug}/mirror", h.DeleteMirror)
+
+	if rr := doShadowRequest(r, "GET", "/api/status-pages/ops/mirror", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a mirror, got %d", rr.Code)
+	}
+	valid := map[string]any{"provider": "statuspage", "pageId": "pg1", "apiKey": "sp-key", "components": map[string]string{"m1": "c1", "g-default": "c2"}}
+	for _, body := range []map[string]any{
+		{"provider": "pagerduty", "pageId": "pg1", "apiKey": "k"},
+		{"provider": "statuspage", "pageId": "../pg1", "apiKey": "k"},
+		{"provider": "statuspage", "pageId": "pg1"},
+		{"provider": "statuspage", "pageId": "pg1", "apiKey": "k", "components": map[string]string{"m2": "c1"}},
+		{"provider": "statuspage", "pageId": "pg1", "apiKey": "k", "components": map[string]string{"m1": "c 1"}},
+	} {
+		if rr := doShadowRequest(r, "PUT", "/api/status-pages/ops/mirror", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, rr.Code)
+		}
+	}
+	if rr := doShadowRequest(r, "PUT", "/api/status-pages/missing/mirror", valid); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown page, got %d", rr.Code)
+	}
+
+	rr := doShadowRequest(r, "PUT", "/api/status-pages/ops/mirror", valid)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if strings.Contains(rr.Body.String(), "sp-key") {
+		t.Error("API key must not be returned")
+	}
+	var got struct {
+		db.StatusPageMirror
+		APIKeyConfigured bool `json:"apiKeyConfigured"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &got)
+	if got.Provider != "statuspage" || got.PageID != "pg1" || !got.Enabled || !got.APIKeyConfigured || got.Components["g-default"] != "c2" {
+		t.Errorf("Unexpected mirror %+v", got)
+	}
+
+	// The stored key is kept when none is sent
+	rr = doShadowRequest(r, "PUT", "/api/status-pages/ops/mirror", map[string]any{"provider": "instatus", "pageId": "page-9", "enabled": false})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if stored, _ := s.GetStatusPageMirror("ops"); stored.APIKey != "sp-key" || stored.Provider != "instatus" || stored.Enabled {
+		t.Errorf("Unexpected stored mirror %+v", stored)
+	}
+
+	if rr := doShadowRequest(r, "DELETE", "/api/status-pages/ops/mirror", nil); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rr.Code)
+	}
+	if rr := doShadowRequest(r, "DELETE", "/api/status-pages/ops/mirror", nil); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 after deletion, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/go-chi/chi/v5"
+)
+
+// robotsTxtSettingKey enables the generated /robots.txt (off by default, so deployments
+// that serve their own robots.txt from a proxy are unaffected).
+const robotsTxtSettingKey = "status_pages.robots_txt_enabled"
+
+// statusPageSEO returns the search engine metadata for a page, falling back to the
+// page description and logo when no dedicated values are set.
+func statusPageSEO(page *db.StatusPage) map[string]any {
+	description := page.MetaDescription
+	if description == "" {
+		description = page.Description
+	}
+	if description == "" {
+		description = "Status updates for " + page.Title
+	}
+
+	ogImage := page.OGImageURL
+	if ogImage == "" && (strings.HasPrefix(page.LogoURL, "http://") || strings.HasPrefix(page.LogoURL, "https://")) {
+		ogImage = page.LogoURL
+	}
+
+	return map[string]any{
+		"noindex":         page.Noindex || !page.Public,
+		"metaDescription": description,
+		"ogImageUrl":      ogImage,
+	}
+}
+
+// setRobotsHeader tells crawlers not to index responses for noindex or private pages.
+func setRobotsHeader(w http.ResponseWriter, page *db.StatusPage) {
+	if page.Noindex || !page.Public {
+		w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	}
+}
+
+// GetPageMeta returns the title and search engine metadata of a status page, for
+// rendering <head> tags and link previews.
+// @Summary      Status page metadata
+// @Tags         status-pages
+// @Produce      json
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} object{title=string,locale=string,url=string,noindex=bool,metaDescription=string,ogImageUrl=string}
+// @Failure      401  {object} ErrorResponse "Status page is private"
+// @Failure      404  {object} ErrorResponse "Status page not found"
+// @Router       /s/{slug}/meta [get]
+func (h *StatusPageHandler) GetPageMeta(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	page, err := h.store.GetStatusPageBySlug(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching status page")
+		return
+	}
+	if page == nil || !page.Enabled {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
+		return
+	}
+	if !page.Public && !h.auth.IsAuthenticated(r) {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	locale := page.Locale
+	if locale == "" {
+		locale = DefaultStatusPageLocale
+	}
+
+	meta := statusPageSEO(page)
+	meta["title"] = page.Title
+	meta["locale"] = locale
+	// SECURITY: Only build an absolute URL from a well-formed Host header
+	if validHostPattern.MatchString(r.Host) {
+		scheme := "http"
+		if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		meta["url"] = scheme + "://" + r.Host + "/status/" + slug
+	}
+
+	setRobotsHeader(w, page)
+	writeJSON(w, http.StatusOK, meta)
+}
+
+// RobotsTxt serves a robots.txt that keeps crawlers out of the API and of status pages
+// that are private, disabled or marked noindex. Returns 404 unless enabled in settings.
+func (h *StatusPageHandler) RobotsTxt(w http.ResponseWriter, r *http.Request) {
+	if enabled, _ := h.store.GetSetting(robotsTxtSettingKey); enabled != "true" {
+		http.NotFound(w, r)
+		return
+	}
+
+	pages, err := h.store.GetStatusPages()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status pages")
+		return
+	}
+
+	var hidden []string
+	for _, p := range pages {
+		if p.Noindex || !p.Public || !p.Enabled {
+			hidden = append(hidden, p.Slug)
+		}
+	}
+	sort.Strings(hidden)
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Disallow: /api/\n")
+	for _, slug := range hidden {
+		// SECURITY: Escape slugs so a crafted slug can't inject robots.txt directives
+		b.WriteString("Disallow: /status/" + url.PathEscape(slug) + "\n")
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusPageSEO_SavedAndServed(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "seo", "SEO Page", nil, true, true)
+
+	payload := map[string]interface{}{
+		"public": true, "enabled": true, "title": "SEO Page",
+		"noindex": true, "metaDescription": "Live status of Acme APIs", "ogImageUrl": "https://cdn.example.com/og.png",
+	}
+	w := httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/seo", "seo", payload))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPageMeta(w, makeRequest("GET", "/api/s/seo/meta", "seo", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("X-Robots-Tag"); got != "noindex, nofollow" {
+		t.Errorf("Expected X-Robots-Tag noindex, got %q", got)
+	}
+	meta := decodeJSON(t, w)
+	if meta["noindex"] != true || meta["metaDescription"] != "Live status of Acme APIs" || meta["ogImageUrl"] != "https://cdn.example.com/og.png" {
+		t.Errorf("unexpected meta: %+v", meta)
+	}
+	if meta["url"] != "http://example.com/status/seo" {
+		t.Errorf("Expected canonical url, got %v", meta["url"])
+	}
+
+	// Public payload carries the same metadata
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/seo", "seo", nil))
+	seo := decodeJSON(t, w)["config"].(map[string]interface{})["seo"].(map[string]interface{})
+	if seo["noindex"] != true {
+		t.Errorf("Expected noindex in public config, got %+v", seo)
+	}
+}
+
+func TestStatusPageSEO_Defaults(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "seo-default", "Acme", nil, true, true)
+
+	w := httptest.NewRecorder()
+	spH.GetPageMeta(w, makeRequest("GET", "/api/s/seo-default/meta", "seo-default", nil))
+	if w.Header().Get("X-Robots-Tag") != "" {
+		t.Errorf("public page should be indexable")
+	}
+	meta := decodeJSON(t, w)
+	if meta["noindex"] != false || meta["metaDescription"] != "Status updates for Acme" {
+		t.Errorf("unexpected defaults: %+v", meta)
+	}
+}
+
+func TestStatusPageSEO_InvalidOGImageRejected(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "seo-bad", "Bad", nil, true, true)
+
+	for _, payload := range []map[string]interface{}{
+		{"public": true, "enabled": true, "title": "Bad", "ogImageUrl": "javascript:alert(1)"},
+		{"public": true, "enabled": true, "title": "Bad", "metaDescription": strings.Repeat("x", 301)},
+	} {
+		w := httptest.NewRecorder()
+		spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/seo-bad", "seo-bad", payload))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", payload, w.Code)
+		}
+	}
+}
+
+func TestRobotsTxt(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "open", "Open", nil, true, true)
+	seedPage(t, store, "private", "Private", nil, false, true)
+	if err := store.UpsertStatusPage("hidden", "Hidden", nil, true, true); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/hidden", "hidden", map[string]interface{}{
+		"public": true, "enabled": true, "title": "Hidden", "noindex": true,
+	}))
+
+	// Disabled by default
+	w = httptest.NewRecorder()
+	spH.RobotsTxt(w, httptest.NewRequest("GET", "/robots.txt", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 when disabled, got %d", w.Code)
+	}
+
+	if err := store.SetSetting(robotsTxtSettingKey, "true"); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	spH.RobotsTxt(w, httptest.NewRequest("GET", "/robots.txt", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"Disallow: /api/", "Disallow: /status/hidden", "Disallow: /status/private"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("robots.txt missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "/status/open") {
+		t.Errorf("indexable page should not be disallowed:\n%s", body)
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// slaWindowDays is the rolling window of the uptime banner on public status pages.
+const slaWindowDays = 90
+
+// slaMethodology explains how the banner's number is computed, for the public payload.
+const slaMethodology = "Uptime is the share of successful checks across all services shown on this page over the last 90 days. " +
+	"Monitors that are checked more often count proportionally more. Checks taken during announced maintenance windows are excluded."
+
+// maintenanceExclusion is a public maintenance window and the groups it covers
+// (none = every group).
+type maintenanceExclusion struct {
+	window db.TimeWindow
+	groups []string
+}
+
+// maintenanceExclusions returns the public maintenance windows that overlap [since, now].
+// Windows still in progress end at now; windows without an end time that are not
+// in progress are skipped since their extent is unknown.
+func maintenanceExclusions(incidents []db.Incident, since, now time.Time) []maintenanceExclusion {
+	var out []maintenanceExclusion
+	for _, inc := range incidents {
+		// Only announced maintenance counts, so the published number can be reproduced
+		if inc.Type != "maintenance" || !inc.Public || inc.StartTime.After(now) {
+			continue
+		}
+		end := now
+		if inc.EndTime != nil {
+			if inc.EndTime.Before(now) {
+				end = *inc.EndTime
+			}
+		} else if inc.Status != "in_progress" {
+			continue
+		}
+		if !end.After(since) || !end.After(inc.StartTime) {
+			continue
+		}
+
+		var groups []string
+		if inc.AffectedGroups != "" {
+			_ = json.Unmarshal([]byte(inc.AffectedGroups), &groups)
+		}
+		out = append(out, maintenanceExclusion{
+			window: db.TimeWindow{Start: inc.StartTime, End: end},
+			groups: groups,
+		})
+	}
+	return out
+}
+
+// windowsForGroup returns the maintenance windows that apply to monitors in groupID.
+func windowsForGroup(exclusions []maintenanceExclusion, groupID string) []db.TimeWindow {
+	var windows []db.TimeWindow
+	for _, e := range exclusions {
+		applies := len(e.groups) == 0
+		for _, g := range e.groups {
+			if g == groupID {
+				applies = true
+				break
+			}
+		}
+		if applies {
+			windows = append(windows, e.window)
+		}
+	}
+	return windows
+}
+
+// statusPageSLA computes the rolling uptime of the monitors shown on a page by pooling
+// their checks, leaving out checks taken during public maintenance of their group.
+// uptimePercent is nil when there are no checks in the window.
+func (h *StatusPageHandler) statusPageSLA(monitors []db.Monitor, now time.Time) (map[string]any, error) {
+	since := now.AddDate(0, 0, -slaWindowDays)
+
+	incidents, err := h.store.GetIncidents(since)
+	if err != nil {
+		return nil, err
+	}
+	exclusions := maintenanceExclusions(incidents, since, now)
+
+	var totalChecks, upChecks int
+	for _, m := range monitors {
+		total, up, err := h.store.GetUptimeCounts(m.ID, since, windowsForGroup(exclusions, m.GroupID))
+		if err != nil {
+			return nil, err
+		}
+		totalChecks += total
+		upChecks += up
+	}
+
+	var uptime *float64
+	if totalChecks > 0 {
+		pct := float64(upChecks) / float64(totalChecks) * 100.0
+		uptime = &pct
+	}
+
+	return map[string]any{
+		"uptimePercent": uptime,
+		"days":          slaWindowDays,
+		"totalChecks":   totalChecks,
+		"methodology":   slaMethodology,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func seedChecks(t *testing.T, store *db.Store, monitorID string, start time.Time, up, down int) {
+	t.Helper()
+	var checks []db.CheckResult
+	for i := 0; i < up+down; i++ {
+		status := "up"
+		if i >= up {
+			status = "down"
+		}
+		checks = append(checks, db.CheckResult{
+			MonitorID: monitorID, Status: status, Latency: 50,
+			Timestamp: start.Add(time.Duration(i) * time.Minute), StatusCode: 200,
+		})
+	}
+	if err := store.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("Failed to insert checks: %v", err)
+	}
+}
+
+func TestGetPublicStatus_SLA(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-sla", "SLA")
+	seedMonitor(t, store, "m-sla-1", "g-sla", "API")
+	seedMonitor(t, store, "m-sla-2", "g-sla", "Web")
+	gid := "g-sla"
+	seedPage(t, store, "sla", "SLA Page", &gid, true, true)
+
+	start := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Minute)
+	// Pooled: 9 up of 10 for m1, 8 up of 10 for m2 => 17/20 = 85%
+	seedChecks(t, store, "m-sla-1", start, 9, 1)
+	seedChecks(t, store, "m-sla-2", start, 8, 2)
+
+	// Hidden by default
+	w := httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/sla", "sla", nil))
+	config := decodeJSON(t, w)["config"].(map[string]interface{})
+	if _, ok := config["sla"]; ok {
+		t.Fatalf("SLA should not be included unless enabled")
+	}
+
+	w = httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/sla", "sla", map[string]interface{}{
+		"public": true, "enabled": true, "title": "SLA Page", "groupId": "g-sla", "showSla": true,
+	}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/sla", "sla", nil))
+	sla := decodeJSON(t, w)["config"].(map[string]interface{})["sla"].(map[string]interface{})
+	if sla["uptimePercent"] != 85.0 || sla["totalChecks"] != 20.0 || sla["days"] != 90.0 {
+		t.Errorf("unexpected sla: %+v", sla)
+	}
+	if sla["methodology"] == "" {
+		t.Errorf("Expected methodology note")
+	}
+}
+
+func TestGetPublicStatus_SLAExcludesMaintenance(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-slam", "SLA")
+	seedMonitor(t, store, "m-slam", "g-slam", "API")
+	gid := "g-slam"
+	seedPage(t, store, "slam", "SLA Page", &gid, true, true)
+
+	w := httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/slam", "slam", map[string]interface{}{
+		"public": true, "enabled": true, "title": "SLA Page", "groupId": "g-slam", "showSla": true,
+	}))
+
+	// 10 up, then 10 down inside a completed maintenance window
+	start := time.Now().UTC().Add(-5 * time.Hour).Truncate(time.Minute)
+	seedChecks(t, store, "m-slam", start, 10, 10)
+	end := start.Add(30 * time.Minute)
+	if err := store.CreateIncident(db.Incident{
+		ID: "mw", Title: "mw", Type: "maintenance", Severity: "minor", Status: "completed",
+		StartTime: start.Add(10 * time.Minute), EndTime: &end, AffectedGroups: `["g-slam"]`, Public: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/slam", "slam", nil))
+	sla := decodeJSON(t, w)["config"].(map[string]interface{})["sla"].(map[string]interface{})
+	if sla["uptimePercent"] != 100.0 || sla["totalChecks"] != 10.0 {
+		t.Errorf("Expected maintenance checks excluded, got %+v", sla)
+	}
+}
+
+func TestMaintenanceExclusions(t *testing.T) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -slaWindowDays)
+	past := now.Add(-time.Hour)
+	incidents := []db.Incident{
+		{ID: "private", Type: "maintenance", Public: false, Status: "completed", StartTime: now.Add(-2 * time.Hour), EndTime: &past},
+		{ID: "future", Type: "maintenance", Public: true, Status: "scheduled", StartTime: now.Add(time.Hour)},
+		{ID: "incident", Type: "incident", Public: true, Status: "resolved", StartTime: now.Add(-2 * time.Hour), EndTime: &past},
+		{ID: "open-ended", Type: "maintenance", Public: true, Status: "in_progress", StartTime: now.Add(-time.Hour), AffectedGroups: `["g1"]`},
+		{ID: "no-end", Type: "maintenance", Public: true, Status: "completed", StartTime: now.Add(-time.Hour)},
+	}
+
+	got := maintenanceExclusions(incidents, since, now)
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 exclusion, got %d", len(got))
+	}
+	if !got[0].window.End.Equal(now) {
+		t.Errorf("in-progress window should end now, got %v", got[0].window.End)
+	}
+	if len(windowsForGroup(got, "g1")) != 1 || len(windowsForGroup(got, "g2")) != 0 {
+		t.Errorf("windows should only apply to affected groups")
+	}
+}
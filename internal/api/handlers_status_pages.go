@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,14 +17,24 @@ import (
 
 var hexColorRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
 
+// statusPageSlugPattern limits slugs to lowercase URL-safe tokens, as they appear in /status/{slug}.
+var statusPageSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// globalStatusPageSlug is the built-in page covering all groups.
+const globalStatusPageSlug = "all"
+
+// maxMetaDescriptionLength keeps descriptions within what search engines display.
+const maxMetaDescriptionLength = 300
+
 type StatusPageHandler struct {
 	store   *db.Store
 	manager *uptime.Manager
 	auth    *AuthHandler
+	cache   *statusPageCache
 }
 
 func NewStatusPageHandler(store *db.Store, manager *uptime.Manager, auth *AuthHandler) *StatusPageHandler {
-	return &StatusPageHandler{store: store, manager: manager, auth: auth}
+	return &StatusPageHandler{store: store, manager: manager, auth: auth, cache: newStatusPageCache()}
 }
 
 // GetAll returns all status page configurations merged with groups.
@@ -31,7 +43,7 @@ func NewStatusPageHandler(store *db.Store, manager *uptime.Manager, auth *AuthHa
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object} object{pages=[]object{slug=string,title=string,groupId=string,public=bool}}
-// @Failure      500  {object} object{error=string}
+// @Failure      500  {object} ErrorResponse
 // @Router       /status-pages [get]
 func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	// 1. Fetch Configured Pages
@@ -67,6 +79,11 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		HeaderContent        string  `json:"headerContent"`
 		HeaderAlignment      string  `json:"headerAlignment"`
 		HeaderArrangement    string  `json:"headerArrangement"`
+		Locale               string  `json:"locale"`
+		Noindex              bool    `json:"noindex"`
+		MetaDescription      string  `json:"metaDescription"`
+		OGImageURL           string  `json:"ogImageUrl"`
+		ShowSLA              bool    `json:"showSla"`
 	}
 
 	var result []StatusPageDTO
@@ -74,13 +91,16 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	// Map configured pages by GroupID (and handle Global "all")
 	configMap := make(map[string]db.StatusPage)
 	var globalPage *db.StatusPage
+	var extraPages []db.StatusPage // created pages covering all groups
 
 	for _, p := range pages {
-		if p.Slug == "all" {
+		if p.Slug == globalStatusPageSlug {
 			global := p // Copy
 			globalPage = &global
 		} else if p.GroupID != nil {
 			configMap[*p.GroupID] = p
+		} else {
+			extraPages = append(extraPages, p)
 		}
 	}
 
@@ -99,6 +119,7 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		HeaderContent:        "logo-title",
 		HeaderAlignment:      "center",
 		HeaderArrangement:    "stacked",
+		Locale:               DefaultStatusPageLocale,
 	}
 	if globalPage != nil {
 		globalDTO.Title = globalPage.Title
@@ -116,6 +137,11 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		globalDTO.HeaderContent = globalPage.HeaderContent
 		globalDTO.HeaderAlignment = globalPage.HeaderAlignment
 		globalDTO.HeaderArrangement = globalPage.HeaderArrangement
+		globalDTO.Locale = globalPage.Locale
+		globalDTO.Noindex = globalPage.Noindex
+		globalDTO.MetaDescription = globalPage.MetaDescription
+		globalDTO.OGImageURL = globalPage.OGImageURL
+		globalDTO.ShowSLA = globalPage.ShowSLA
 		if globalDTO.UptimeDaysRange == 0 {
 			globalDTO.UptimeDaysRange = 90
 		}
@@ -131,13 +157,36 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		if globalDTO.HeaderArrangement == "" {
 			globalDTO.HeaderArrangement = "stacked"
 		}
+		if globalDTO.Locale == "" {
+			globalDTO.Locale = DefaultStatusPageLocale
+		}
 	}
 	result = append(result, globalDTO)
 
+	// Unconfigured groups get a default slug derived from their (stable) ID that can't
+	// collide with, and so overwrite, another page when first saved. Assigning in ID
+	// order keeps the slugs stable when groups are renamed.
+	takenSlugs := map[string]bool{globalStatusPageSlug: true}
+	for _, p := range pages {
+		takenSlugs[p.Slug] = true
+	}
+	var unconfigured []string
+	for _, g := range groups {
+		if _, ok := configMap[g.ID]; !ok {
+			unconfigured = append(unconfigured, g.ID)
+		}
+	}
+	sort.Strings(unconfigured)
+	defaultSlugs := make(map[string]string, len(unconfigured))
+	for _, id := range unconfigured {
+		slug := defaultGroupPageSlug(id, func(slug string) bool { return takenSlugs[slug] })
+		takenSlugs[slug] = true
+		defaultSlugs[id] = slug
+	}
+
 	// B. Group Pages
 	for _, g := range groups {
 		dto := StatusPageDTO{
-			Slug:                 strings.TrimPrefix(g.ID, "g-"), // default slug (clean)
 			Title:                g.Name,
 			GroupID:              &g.ID,
 			Public:               false,
@@ -150,9 +199,13 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			HeaderContent:        "logo-title",
 			HeaderAlignment:      "center",
 			HeaderArrangement:    "stacked",
+			Locale:               DefaultStatusPageLocale,
 		}
 
-		if cfg, ok := configMap[g.ID]; ok {
+		cfg, ok := configMap[g.ID]
+		if !ok {
+			dto.Slug = defaultSlugs[g.ID]
+		} else {
 			dto.Slug = cfg.Slug
 			dto.Title = cfg.Title
 			dto.Public = cfg.Public
@@ -169,6 +222,11 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			dto.HeaderContent = cfg.HeaderContent
 			dto.HeaderAlignment = cfg.HeaderAlignment
 			dto.HeaderArrangement = cfg.HeaderArrangement
+			dto.Locale = cfg.Locale
+			dto.Noindex = cfg.Noindex
+			dto.MetaDescription = cfg.MetaDescription
+			dto.OGImageURL = cfg.OGImageURL
+			dto.ShowSLA = cfg.ShowSLA
 			if dto.UptimeDaysRange == 0 {
 				dto.UptimeDaysRange = 90
 			}
@@ -184,11 +242,41 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 			if dto.HeaderArrangement == "" {
 				dto.HeaderArrangement = "stacked"
 			}
+			if dto.Locale == "" {
+				dto.Locale = DefaultStatusPageLocale
+			}
 		}
 
 		result = append(result, dto)
 	}
 
+	// C. Additional pages covering all groups
+	for _, p := range extraPages {
+		result = append(result, StatusPageDTO{
+			Slug:                 p.Slug,
+			Title:                p.Title,
+			Public:               p.Public,
+			Enabled:              p.Enabled,
+			Description:          p.Description,
+			LogoURL:              p.LogoURL,
+			FaviconURL:           p.FaviconURL,
+			AccentColor:          p.AccentColor,
+			Theme:                p.Theme,
+			ShowUptimeBars:       p.ShowUptimeBars,
+			ShowUptimePercentage: p.ShowUptimePercentage,
+			ShowIncidentHistory:  p.ShowIncidentHistory,
+			UptimeDaysRange:      p.UptimeDaysRange,
+			HeaderContent:        p.HeaderContent,
+			HeaderAlignment:      p.HeaderAlignment,
+			HeaderArrangement:    p.HeaderArrangement,
+			Locale:               p.Locale,
+			Noindex:              p.Noindex,
+			MetaDescription:      p.MetaDescription,
+			OGImageURL:           p.OGImageURL,
+			ShowSLA:              p.ShowSLA,
+		})
+	}
+
 	writeJSON(w, http.StatusOK, map[string]any{"pages": result})
 }
 
@@ -201,7 +289,7 @@ func (h *StatusPageHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Param        slug path string true "Status page slug"
 // @Param        body body object{public=bool,title=string,groupId=string} true "Toggle payload"
 // @Success      200  {object} object{message=string}
-// @Failure      400  {object} object{error=string} "Invalid request"
+// @Failure      400  {object} ErrorResponse "Invalid request"
 // @Router       /status-pages/{slug} [patch]
 func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -222,6 +310,11 @@ func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 		HeaderContent        *string `json:"headerContent"`
 		HeaderAlignment      *string `json:"headerAlignment"`
 		HeaderArrangement    *string `json:"headerArrangement"`
+		Locale               *string `json:"locale"`
+		Noindex              *bool   `json:"noindex"`
+		MetaDescription      *string `json:"metaDescription"`
+		OGImageURL           *string `json:"ogImageUrl"`
+		ShowSLA              *bool   `json:"showSla"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request")
@@ -300,9 +393,62 @@ func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 		headerArrangement = *req.HeaderArrangement
 	}
 
+	// Validate locale if provided
+	locale := DefaultStatusPageLocale
+	if req.Locale != nil && *req.Locale != "" {
+		if !isStatusPageLocale(*req.Locale) {
+			writeError(w, http.StatusBadRequest, "invalid locale (must be one of "+strings.Join(StatusPageLocales(), ", ")+")")
+			return
+		}
+		locale = *req.Locale
+	}
+
+	// Validate SEO fields if provided. Crawlers can't fetch data: URIs, so the
+	// OpenGraph image must be an http/https URL.
+	if req.MetaDescription != nil && len(*req.MetaDescription) > maxMetaDescriptionLength {
+		writeError(w, http.StatusBadRequest, "meta description must be at most "+strconv.Itoa(maxMetaDescriptionLength)+" characters")
+		return
+	}
+	if req.OGImageURL != nil && *req.OGImageURL != "" {
+		og := *req.OGImageURL
+		if !strings.HasPrefix(og, "http://") && !strings.HasPrefix(og, "https://") {
+			writeError(w, http.StatusBadRequest, "invalid OpenGraph image URL (must be http/https URL)")
+			return
+		}
+	}
+
 	// Get existing page to preserve defaults
 	existing, _ := h.store.GetStatusPageBySlug(slug)
 
+	// The upsert replaces whatever page holds the slug, so refuse to let one
+	// group's page overwrite another's or give a group a second page
+	if req.GroupID != nil && *req.GroupID == "" {
+		req.GroupID = nil
+	}
+	if existing != nil && !sameGroup(existing.GroupID, req.GroupID) {
+		writeError(w, http.StatusConflict, "slug is used by another status page")
+		return
+	}
+	if existing == nil {
+		if !statusPageSlugPattern.MatchString(slug) {
+			writeError(w, http.StatusBadRequest, "invalid slug (lowercase letters, digits and dashes, max 64 characters)")
+			return
+		}
+		if req.GroupID != nil {
+			pages, err := h.store.GetStatusPages()
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, "failed to fetch status pages")
+				return
+			}
+			for _, p := range pages {
+				if p.GroupID != nil && *p.GroupID == *req.GroupID {
+					writeError(w, http.StatusConflict, "group already has a status page ("+p.Slug+")")
+					return
+				}
+			}
+		}
+	}
+
 	// Validate uptimeDaysRange if provided
 	uptimeDaysRange := 90
 	if req.UptimeDaysRange != nil {
@@ -346,6 +492,7 @@ func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 		HeaderContent:        headerContent,
 		HeaderAlignment:      headerAlignment,
 		HeaderArrangement:    headerArrangement,
+		Locale:               locale,
 	}
 
 	// Apply existing values as defaults
@@ -381,9 +528,19 @@ func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 				input.HeaderArrangement = "stacked"
 			}
 		}
+		if req.Locale == nil {
+			input.Locale = existing.Locale
+			if input.Locale == "" {
+				input.Locale = DefaultStatusPageLocale
+			}
+		}
 		input.ShowUptimeBars = existing.ShowUptimeBars
 		input.ShowUptimePercentage = existing.ShowUptimePercentage
 		input.ShowIncidentHistory = existing.ShowIncidentHistory
+		input.Noindex = existing.Noindex
+		input.MetaDescription = existing.MetaDescription
+		input.OGImageURL = existing.OGImageURL
+		input.ShowSLA = existing.ShowSLA
 		if req.UptimeDaysRange == nil {
 			input.UptimeDaysRange = existing.UptimeDaysRange
 			if input.UptimeDaysRange == 0 {
@@ -405,23 +562,185 @@ func (h *StatusPageHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 	if req.ShowIncidentHistory != nil {
 		input.ShowIncidentHistory = *req.ShowIncidentHistory
 	}
+	if req.Noindex != nil {
+		input.Noindex = *req.Noindex
+	}
+	if req.MetaDescription != nil {
+		input.MetaDescription = *req.MetaDescription
+	}
+	if req.OGImageURL != nil {
+		input.OGImageURL = *req.OGImageURL
+	}
+	if req.ShowSLA != nil {
+		input.ShowSLA = *req.ShowSLA
+	}
 
 	if err := h.store.UpsertStatusPageFull(input); err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to update status page")
 		return
 	}
+	h.cache.invalidate()
 
 	writeJSON(w, http.StatusOK, map[string]string{"message": "updated"})
 }
 
+// Create adds a status page with an explicit slug and scope. Pages scoped to a
+// group are limited to one per group; pages without a group cover all groups.
+// @Summary      Create status page
+// @Tags         status-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{slug=string,title=string,groupId=string,public=bool,enabled=bool} true "Status page (omit groupId for all groups)"
+// @Success      201  {object} db.StatusPage
+// @Failure      400  {object} ErrorResponse "Invalid request"
+// @Failure      409  {object} ErrorResponse "Slug or group already has a page"
+// @Router       /status-pages [post]
+func (h *StatusPageHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Slug    string  `json:"slug"`
+		Title   string  `json:"title"`
+		GroupID *string `json:"groupId"`
+		Public  bool    `json:"public"`
+		Enabled bool    `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if !statusPageSlugPattern.MatchString(req.Slug) {
+		writeError(w, http.StatusBadRequest, "invalid slug (lowercase letters, digits and dashes, max 64 characters)")
+		return
+	}
+	if req.Slug == globalStatusPageSlug {
+		writeError(w, http.StatusConflict, "slug is reserved")
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+	if len(req.Title) > maxNameLength {
+		writeError(w, http.StatusBadRequest, "title too long (max 255 characters)")
+		return
+	}
+
+	pages, err := h.store.GetStatusPages()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch status pages")
+		return
+	}
+	if req.GroupID != nil && *req.GroupID == "" {
+		req.GroupID = nil
+	}
+	if req.GroupID != nil {
+		groups, err := h.store.GetGroups()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to fetch groups")
+			return
+		}
+		found := false
+		for _, g := range groups {
+			if g.ID == *req.GroupID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			writeError(w, http.StatusBadRequest, "group not found")
+			return
+		}
+		for _, p := range pages {
+			if p.GroupID != nil && *p.GroupID == *req.GroupID {
+				writeError(w, http.StatusConflict, "group already has a status page ("+p.Slug+")")
+				return
+			}
+		}
+	}
+	for _, p := range pages {
+		if p.Slug == req.Slug {
+			writeError(w, http.StatusConflict, "a status page with this slug already exists")
+			return
+		}
+	}
+
+	input := db.StatusPageInput{
+		Slug:                 req.Slug,
+		Title:                req.Title,
+		GroupID:              req.GroupID,
+		Public:               req.Public,
+		Enabled:              req.Enabled,
+		Theme:                "system",
+		ShowUptimeBars:       true,
+		ShowUptimePercentage: true,
+		ShowIncidentHistory:  true,
+		UptimeDaysRange:      90,
+		HeaderContent:        "logo-title",
+		HeaderAlignment:      "center",
+		HeaderArrangement:    "stacked",
+		Locale:               DefaultStatusPageLocale,
+	}
+	if err := h.store.CreateStatusPage(input); err != nil {
+		// A conflict means we lost a race with another create
+		writeStoreError(w, err, "failed to create status page")
+		return
+	}
+
+	page, err := h.store.GetStatusPageBySlug(req.Slug)
+	if err != nil || page == nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status page")
+		return
+	}
+	h.cache.invalidate()
+	writeJSON(w, http.StatusCreated, page)
+}
+
+// Delete removes a status page and its configuration. The global page can only be
+// disabled, not deleted.
+// @Summary      Delete status page
+// @Tags         status-pages
+// @Produce      json
+// @Security     BearerAuth
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} object{message=string}
+// @Failure      400  {object} ErrorResponse "Global page can't be deleted"
+// @Failure      404  {object} ErrorResponse "Status page not found"
+// @Router       /status-pages/{slug} [delete]
+func (h *StatusPageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	if slug == globalStatusPageSlug {
+		writeError(w, http.StatusBadRequest, "the global status page can't be deleted; disable it instead")
+		return
+	}
+
+	deleted, err := h.store.DeleteStatusPage(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete status page")
+		return
+	}
+	if !deleted {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
+		return
+	}
+
+	h.cache.invalidate()
+
+	userID, _ := r.Context().Value(contextKeyUserID).(int64)
+	log.Printf("AUDIT: [STATUS] User %d deleted status page %s", userID, sanitizeLog(slug)) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
+
 // GetPublicStatus returns real-time status data for a public status page.
 // @Summary      Public status page
 // @Tags         status-pages
 // @Produce      json
 // @Param        slug path string true "Status page slug"
+// @Param        tz   query string false "IANA timezone for daily uptime bars (default: admin's timezone)"
 // @Success      200  {object} object{title=string,public=bool,groups=[]object{id=string,name=string},incidents=[]object{id=string,title=string}}
-// @Failure      403  {object} object{error=string} "Status page is private"
-// @Failure      404  {object} object{error=string} "Status page not found"
+// @Failure      403  {object} ErrorResponse "Status page is private"
+// @Failure      404  {object} ErrorResponse "Status page not found"
 // @Router       /s/{slug} [get]
 func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -433,7 +752,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	if page == nil || !page.Enabled {
-		writeError(w, http.StatusNotFound, "status page not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
 		return
 	}
 	if !page.Public {
@@ -443,6 +762,22 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	// Repeated views within a few seconds are served the same rendered page
+	key := slug + "\x00" + r.URL.Query().Get("tz")
+	if cached, ok := h.cache.get(key); ok {
+		cached.writeTo(w)
+		return
+	}
+	buf := newBufferedResponse(w.Header())
+	h.renderPublicStatus(buf, r, page)
+	if buf.status == http.StatusOK && h.manager.Hydrated() {
+		h.cache.set(key, buf)
+	}
+	buf.writeTo(w)
+}
+
+// renderPublicStatus writes the status data of an enabled page the viewer may see.
+func (h *StatusPageHandler) renderPublicStatus(w http.ResponseWriter, r *http.Request, page *db.StatusPage) {
 	// 2. Fetch Layout from DB (Groups + Monitors Metadata)
 	groups, err := h.store.GetGroups()
 	if err != nil {
@@ -484,6 +819,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 	for _, m := range monitorsMeta {
 		groupMap[m.GroupID] = append(groupMap[m.GroupID], m)
 	}
+	lastKnown, _ := lastKnownChecks(h.manager, h.store)
 
 	// 5. Construct Response (Reusing Logic from UptimeHandler)
 	type MonitorDTO struct {
@@ -496,6 +832,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		LastCheck      string              `json:"lastCheck"`
 		UptimeDays     []db.DailyUptimeStat `json:"uptimeDays"`
 		OverallUptime  float64             `json:"overallUptime"`
+		StatusMessage  string              `json:"statusMessage,omitempty"` // note from a manual override
 	}
 
 	type GroupDTO struct {
@@ -504,18 +841,35 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		Monitors []MonitorDTO `json:"monitors"`
 	}
 
+	// Daily uptime bars follow the viewer's calendar days
+	loc, ok := resolveLocation(r, h.store)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid timezone")
+		return
+	}
+
+	// Manual overrides are layered over the live status
+	overrides, err := h.store.GetActiveStatusOverrides(time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load status overrides")
+		return
+	}
+
 	groupDTOs := []GroupDTO{}
+	var shownMonitors []db.Monitor
 
 	for _, g := range targetGroups {
 		monitorDTOs := []MonitorDTO{}
 
 		for _, meta := range groupMap[g.ID] {
+			shownMonitors = append(shownMonitors, meta)
+
 			// Get Live Status from Manager
 			task := h.manager.GetMonitor(meta.ID)
 
 			statusStr := "down" // Default if not running
 			latency := int64(0)
-			lastCheck := "Never"
+			lastCheck := "" // RFC3339, empty until the first check
 			var historyPoints []HistoryPoint
 
 			if task != nil {
@@ -525,18 +879,18 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 					threshold := task.GetLatencyThreshold()
 					if last.IsUp {
 						statusStr = "up"
-						if last.Latency > threshold {
+						if last.Latency > threshold || last.DegradedReason != "" {
 							statusStr = "degraded"
 						}
 					}
 					latency = last.Latency
-					lastCheck = last.Timestamp.Format("15:04:05")
+					lastCheck = last.Timestamp.Format(time.RFC3339)
 
 					for _, h := range history {
 						s := "down"
 						if h.IsUp {
 							s = "up"
-							if h.Latency > threshold {
+							if h.Latency > threshold || h.DegradedReason != "" {
 								s = "degraded"
 							}
 						}
@@ -550,18 +904,29 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 				} else {
 					statusStr = "up" // Optimistic or "pending"
 				}
+			} else if c, ok := lastKnown[meta.ID]; ok && meta.Active {
+				// Manager still warming up: show the last persisted check
+				statusStr = checkStatus(c, meta, h.manager.GetLatencyThreshold())
+				latency = c.Latency
+				lastCheck = c.Timestamp.Format(time.RFC3339)
 			} else {
 				if !meta.Active {
 					statusStr = "paused"
 				}
 			}
 
+			statusMessage := ""
+			if o, ok := overrides[meta.ID]; ok {
+				statusStr = o.Status
+				statusMessage = o.Message
+			}
+
 			// Fetch daily uptime stats from DB (configurable range)
 			daysRange := page.UptimeDaysRange
 			if daysRange == 0 {
 				daysRange = 90
 			}
-			uptimeDays, _ := h.store.GetDailyUptimeStats(meta.ID, daysRange)
+			uptimeDays, _ := h.store.GetDailyUptimeStatsInLocation(meta.ID, daysRange, loc)
 			if uptimeDays == nil {
 				uptimeDays = []db.DailyUptimeStat{}
 			}
@@ -587,6 +952,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 				LastCheck:     lastCheck,
 				UptimeDays:    uptimeDays,
 				OverallUptime: overallUptime,
+				StatusMessage: statusMessage,
 			})
 		}
 
@@ -601,9 +967,10 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 
 	// 6. Fetch Incidents and Outages
 	type IncidentUpdateDTO struct {
-		Status    string    `json:"status"`
-		Message   string    `json:"message"`
-		CreatedAt time.Time `json:"createdAt"`
+		Status    string     `json:"status"`
+		Message   string     `json:"message"`
+		CreatedAt time.Time  `json:"createdAt"`
+		EditedAt  *time.Time `json:"editedAt,omitempty"`
 	}
 
 	type IncidentResponseDTO struct {
@@ -617,7 +984,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		EndTime        *time.Time          `json:"endTime,omitempty"`
 		AffectedGroups []string            `json:"affectedGroups"`
 		Source         string              `json:"source,omitempty"`
-		Duration       string              `json:"duration,omitempty"`
+		DurationSecs   int64               `json:"durationSeconds,omitempty"`
 		Updates        []IncidentUpdateDTO `json:"updates,omitempty"`
 	}
 
@@ -701,6 +1068,7 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 				Status:    u.Status,
 				Message:   u.Message,
 				CreatedAt: u.CreatedAt,
+				EditedAt:  u.EditedAt,
 			})
 		}
 
@@ -763,18 +1131,14 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 					Status:    u.Status,
 					Message:   u.Message,
 					CreatedAt: u.CreatedAt,
+					EditedAt:  u.EditedAt,
 				})
 			}
 
-			// Calculate duration
-			var duration string
+			// Calculate duration (formatted by the client)
+			var durationSecs int64
 			if inc.EndTime != nil {
-				d := inc.EndTime.Sub(inc.StartTime)
-				if d < time.Hour {
-					duration = formatDurationMinutes(d)
-				} else {
-					duration = formatDurationHours(d)
-				}
+				durationSecs = int64(inc.EndTime.Sub(inc.StartTime).Seconds())
 			}
 
 			source := inc.Source
@@ -793,13 +1157,17 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 				EndTime:        inc.EndTime,
 				AffectedGroups: mappedGroups,
 				Source:         source,
-				Duration:       duration,
+				DurationSecs:   durationSecs,
 				Updates:        updateDTOs,
 			})
 		}
 	}
 
 	// Build config object for public page
+	locale := page.Locale
+	if locale == "" {
+		locale = DefaultStatusPageLocale
+	}
 	uptimeDaysRange := page.UptimeDaysRange
 	if uptimeDaysRange == 0 {
 		uptimeDaysRange = 90
@@ -817,8 +1185,22 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		"headerContent":       page.HeaderContent,
 		"headerAlignment":     page.HeaderAlignment,
 		"headerArrangement":   page.HeaderArrangement,
+		"locale":              locale,
+		"labels":              statusPageLabels(locale), // visitor-facing strings in the page's language
+		"seo":                 statusPageSEO(page),
+		"showSla":             page.ShowSLA,
+	}
+	if page.ShowSLA {
+		sla, err := h.statusPageSLA(shownMonitors, time.Now())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to compute uptime")
+			return
+		}
+		config["sla"] = sla
 	}
 
+	setRobotsHeader(w, page)
+
 	writeJSON(w, http.StatusOK, map[string]any{
 		"title":         page.Title,
 		"public":        page.Public,
@@ -826,32 +1208,17 @@ func (h *StatusPageHandler) GetPublicStatus(w http.ResponseWriter, r *http.Reque
 		"incidents":     activeIncidents,
 		"pastIncidents": pastIncidents,
 		"config":        config,
+		"timezone":      loc.String(), // zone of uptimeDays; clients localize RFC3339 timestamps with it
 	})
 }
 
-func formatDurationMinutes(d time.Duration) string {
-	mins := int(d.Minutes())
-	if mins < 1 {
-		return "<1m"
-	}
-	return strings.TrimSpace(strings.Replace(d.Truncate(time.Minute).String(), "0s", "", 1))
-}
-
-func formatDurationHours(d time.Duration) string {
-	mins := int(d.Minutes()) % 60
-	if mins == 0 {
-		return strings.TrimSpace(d.Truncate(time.Hour).String())
-	}
-	return strings.TrimSpace(d.Truncate(time.Minute).String())
-}
-
 // GetRSSFeed returns an RSS 2.0 feed of recent incidents for a public status page.
 // @Summary      RSS feed for status page
 // @Tags         status-pages
 // @Produce      application/rss+xml
 // @Param        slug path string true "Status page slug"
 // @Success      200  {string} string "RSS 2.0 XML feed"
-// @Failure      404  {object} object{error=string} "Status page not found"
+// @Failure      404  {object} ErrorResponse "Status page not found"
 // @Router       /s/{slug}/rss [get]
 func (h *StatusPageHandler) GetRSSFeed(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -863,11 +1230,11 @@ func (h *StatusPageHandler) GetRSSFeed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if page == nil || !page.Enabled {
-		writeError(w, http.StatusNotFound, "status page not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
 		return
 	}
 	if !page.Public {
-		writeError(w, http.StatusNotFound, "status page not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
 		return
 	}
 
@@ -991,3 +1358,108 @@ func xmlEscape(s string) string {
 	s = strings.ReplaceAll(s, "'", "&apos;")
 	return s
 }
+
+// maintenanceCalendarDays is how far back completed maintenance windows are listed.
+const maintenanceCalendarDays = 30
+
+// GetMaintenanceCalendar returns upcoming (including in-progress) and recently ended public
+// maintenance windows for a status page.
+// @Summary      Status page maintenance calendar
+// @Tags         status-pages
+// @Produce      json
+// @Param        slug path string true "Status page slug"
+// @Success      200  {object} object{upcoming=[]object{id=string,title=string,startTime=string,endTime=string},recent=[]object{id=string,title=string,startTime=string,endTime=string}}
+// @Failure      401  {object} ErrorResponse "Status page is private"
+// @Failure      404  {object} ErrorResponse "Status page not found"
+// @Router       /s/{slug}/maintenance [get]
+func (h *StatusPageHandler) GetMaintenanceCalendar(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	page, err := h.store.GetStatusPageBySlug(slug)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching status page")
+		return
+	}
+	if page == nil || !page.Enabled {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeStatusPageNotFound, "status page not found")
+		return
+	}
+	if !page.Public && !h.auth.IsAuthenticated(r) {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	type MaintenanceWindowDTO struct {
+		ID             string     `json:"id"`
+		Title          string     `json:"title"`
+		Description    string     `json:"description"`
+		Status         string     `json:"status"`
+		StartTime      time.Time  `json:"startTime"`
+		EndTime        *time.Time `json:"endTime,omitempty"`
+		AffectedGroups []string   `json:"affectedGroups"`
+	}
+
+	now := time.Now()
+	incidents, err := h.store.GetIncidents(now.Add(-maintenanceCalendarDays * 24 * time.Hour))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load maintenance windows")
+		return
+	}
+
+	upcoming := []MaintenanceWindowDTO{}
+	recent := []MaintenanceWindowDTO{}
+	for _, inc := range incidents {
+		// SECURITY: Only public maintenance windows are shown to visitors
+		if inc.Type != "maintenance" || !inc.Public {
+			continue
+		}
+
+		var mappedGroups []string
+		if inc.AffectedGroups != "" {
+			_ = json.Unmarshal([]byte(inc.AffectedGroups), &mappedGroups)
+		}
+
+		// Filter by group if this is a group-specific status page (global windows show everywhere)
+		if page.GroupID != nil && len(mappedGroups) > 0 {
+			affected := false
+			for _, gID := range mappedGroups {
+				if gID == *page.GroupID {
+					affected = true
+					break
+				}
+			}
+			if !affected {
+				continue
+			}
+		}
+		if mappedGroups == nil {
+			mappedGroups = []string{}
+		}
+
+		dto := MaintenanceWindowDTO{
+			ID:             inc.ID,
+			Title:          inc.Title,
+			Description:    inc.Description,
+			Status:         inc.Status,
+			StartTime:      inc.StartTime,
+			EndTime:        inc.EndTime,
+			AffectedGroups: mappedGroups,
+		}
+
+		ended := inc.Status == "completed" || (inc.EndTime != nil && inc.EndTime.Before(now))
+		if ended {
+			recent = append(recent, dto)
+		} else {
+			upcoming = append(upcoming, dto)
+		}
+	}
+
+	// Upcoming soonest first, recent most recent first
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].StartTime.Before(upcoming[j].StartTime) })
+	sort.Slice(recent, func(i, j int) bool { return recent[i].StartTime.After(recent[j].StartTime) })
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"upcoming": upcoming,
+		"recent":   recent,
+	})
+}
@@ -1681,3 +1681,270 @@ func TestPhase4_RSSFeedAtomSelfLink(t *testing.T) {
 		t.Error("Expected rel='self' in Atom link")
 	}
 }
+
+func TestPublicStatus_TimestampsForClientLocalization(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+
+	seedGroup(t, store, "g-ts", "Timestamp Group")
+	seedMonitor(t, store, "m-ts", "g-ts", "Timestamp Monitor")
+	seedMonitor(t, store, "m-ts-new", "g-ts", "Unchecked Monitor")
+	seedPage(t, store, "ts-test", "Timestamp Test", nil, true, true)
+	seedResolvedIncident(t, store, "inc-ts", "Timed Incident", "minor", true, nil, -2*24*time.Hour)
+
+	spH.manager.Sync()
+	defer spH.manager.Reset()
+	checkedAt := time.Date(2026, 3, 1, 23, 15, 0, 0, time.UTC)
+	spH.manager.GetMonitor("m-ts").RecordResult(true, 40, checkedAt, 200, "", false)
+
+	w := httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/ts-test?tz=Europe/Berlin", "ts-test", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	body := decodeJSON(t, w)
+
+	if body["timezone"] != "Europe/Berlin" {
+		t.Errorf("Expected timezone Europe/Berlin, got %v", body["timezone"])
+	}
+
+	groups := body["groups"].([]interface{})
+	checked := findMonitorInGroups(groups, "Timestamp Monitor")
+	ts, err := time.Parse(time.RFC3339, checked["lastCheck"].(string))
+	if err != nil || !ts.Equal(checkedAt) {
+		t.Errorf("Expected RFC3339 lastCheck %v, got %v (err %v)", checkedAt, checked["lastCheck"], err)
+	}
+	if unchecked := findMonitorInGroups(groups, "Unchecked Monitor"); unchecked["lastCheck"] != "" {
+		t.Errorf("Expected empty lastCheck before the first check, got %v", unchecked["lastCheck"])
+	}
+
+	for _, i := range body["pastIncidents"].([]interface{}) {
+		inc := i.(map[string]interface{})
+		if inc["title"] != "Timed Incident" {
+			continue
+		}
+		if inc["durationSeconds"] != float64(3600) {
+			t.Errorf("Expected durationSeconds 3600, got %v", inc["durationSeconds"])
+		}
+		if _, ok := inc["duration"]; ok {
+			t.Error("Expected no server-formatted duration")
+		}
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/ts-test?tz=Not/AZone", "ts-test", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid tz, got %d", w.Code)
+	}
+}
+
+// seedMaintenance creates a public maintenance window starting startOffset from now.
+func seedMaintenance(t *testing.T, store *db.Store, id, status string, groups []string, startOffset, duration time.Duration) {
+	t.Helper()
+	start := time.Now().Add(startOffset)
+	end := start.Add(duration)
+	groupsJSON, _ := json.Marshal(groups)
+	if err := store.CreateIncident(db.Incident{
+		ID: id, Title: id, Type: "maintenance", Severity: "minor", Status: status,
+		StartTime: start, EndTime: &end, AffectedGroups: string(groupsJSON), Public: true,
+	}); err != nil {
+		t.Fatalf("Failed to create maintenance %s: %v", id, err)
+	}
+}
+
+func TestGetMaintenanceCalendar(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+
+	seedGroup(t, store, "g-mc", "Target")
+	seedGroup(t, store, "g-mc-other", "Other")
+	gid := "g-mc"
+	seedPage(t, store, "mc", "Maintenance Calendar", &gid, true, true)
+
+	seedMaintenance(t, store, "later", "scheduled", []string{"g-mc"}, 48*time.Hour, time.Hour)
+	seedMaintenance(t, store, "soon", "scheduled", nil, 2*time.Hour, time.Hour)
+	seedMaintenance(t, store, "done", "completed", []string{"g-mc"}, -72*time.Hour, time.Hour)
+	seedMaintenance(t, store, "other-group", "scheduled", []string{"g-mc-other"}, time.Hour, time.Hour)
+	seedMaintenance(t, store, "too-old", "completed", []string{"g-mc"}, -60*24*time.Hour, time.Hour)
+	seedIncident(t, store, "inc-mc", "Not maintenance", "incident", "major", "investigating", true, nil, 0)
+
+	w := httptest.NewRecorder()
+	spH.GetMaintenanceCalendar(w, makeRequest("GET", "/api/s/mc/maintenance", "mc", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+
+	body := decodeJSON(t, w)
+	ids := func(key string) []string {
+		var out []string
+		for _, item := range body[key].([]interface{}) {
+			out = append(out, item.(map[string]interface{})["id"].(string))
+		}
+		return out
+	}
+
+	if got := strings.Join(ids("upcoming"), ","); got != "soon,later" {
+		t.Errorf("upcoming = %q, want soon,later", got)
+	}
+	if got := strings.Join(ids("recent"), ","); got != "done" {
+		t.Errorf("recent = %q, want done", got)
+	}
+}
+
+func TestGetMaintenanceCalendar_PrivatePageRequiresAuth(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "mc-private", "Private", nil, false, true)
+
+	w := httptest.NewRecorder()
+	spH.GetMaintenanceCalendar(w, makeRequest("GET", "/api/s/mc-private/maintenance", "mc-private", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetMaintenanceCalendar(w, makeRequest("GET", "/api/s/missing/maintenance", "missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
+
+// --- Create / Delete Handler Tests ---
+
+func TestCreateStatusPage(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-create", "Create")
+
+	create := func(payload map[string]interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		spH.Create(w, makeRequest("POST", "/api/status-pages", "", payload))
+		return w
+	}
+
+	w := create(map[string]interface{}{"slug": "public-api", "title": "Public API", "groupId": "g-create", "public": true, "enabled": true})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d (body: %s)", w.Code, w.Body.String())
+	}
+	page, _ := store.GetStatusPageBySlug("public-api")
+	if page == nil || page.GroupID == nil || *page.GroupID != "g-create" || !page.Public {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	// All-groups page with its own slug is listed by GetAll
+	if w := create(map[string]interface{}{"slug": "everything", "title": "Everything"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d (body: %s)", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	spH.GetAll(w, makeRequest("GET", "/api/status-pages", "", nil))
+	listed := false
+	for _, p := range decodeJSON(t, w)["pages"].([]interface{}) {
+		if p.(map[string]interface{})["slug"] == "everything" {
+			listed = true
+		}
+	}
+	if !listed {
+		t.Error("Expected created all-groups page in GetAll")
+	}
+
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		code    int
+	}{
+		{"duplicate slug", map[string]interface{}{"slug": "public-api", "title": "Again"}, http.StatusConflict},
+		{"group already has page", map[string]interface{}{"slug": "other", "title": "Other", "groupId": "g-create"}, http.StatusConflict},
+		{"reserved slug", map[string]interface{}{"slug": "all", "title": "All"}, http.StatusConflict},
+		{"invalid slug", map[string]interface{}{"slug": "Bad Slug!", "title": "Bad"}, http.StatusBadRequest},
+		{"missing title", map[string]interface{}{"slug": "no-title", "title": " "}, http.StatusBadRequest},
+		{"unknown group", map[string]interface{}{"slug": "ghost", "title": "Ghost", "groupId": "g-missing"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := create(tt.payload); w.Code != tt.code {
+				t.Errorf("Expected %d, got %d (body: %s)", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteStatusPage(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "doomed", "Doomed", nil, true, true)
+
+	w := httptest.NewRecorder()
+	spH.Delete(w, makeRequest("DELETE", "/api/status-pages/doomed", "doomed", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if page, _ := store.GetStatusPageBySlug("doomed"); page != nil {
+		t.Error("Expected page to be deleted")
+	}
+
+	w = httptest.NewRecorder()
+	spH.Delete(w, makeRequest("DELETE", "/api/status-pages/doomed", "doomed", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing page, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	spH.Delete(w, makeRequest("DELETE", "/api/status-pages/all", "all", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for global page, got %d", w.Code)
+	}
+}
+
+func TestGetAll_DefaultSlugAvoidsCollisions(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-all", "All")
+	seedGroup(t, store, "g-docs", "Docs")
+	seedPage(t, store, "docs", "Docs (all groups)", nil, true, true)
+
+	w := httptest.NewRecorder()
+	spH.GetAll(w, makeRequest("GET", "/api/status-pages", "", nil))
+	slugs := map[string]string{}
+	for _, p := range decodeJSON(t, w)["pages"].([]interface{}) {
+		page := p.(map[string]interface{})
+		if gid, ok := page["groupId"].(string); ok {
+			slugs[gid] = page["slug"].(string)
+		}
+	}
+	if slugs["g-all"] != "all-2" {
+		t.Errorf("Expected g-all to avoid the global slug, got %q", slugs["g-all"])
+	}
+	if slugs["g-docs"] != "docs-2" {
+		t.Errorf("Expected g-docs to avoid the existing docs page, got %q", slugs["g-docs"])
+	}
+}
+
+func TestToggle_RejectsCrossScopeOverwrite(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-a", "A")
+	seedGroup(t, store, "g-b", "B")
+	gid := "g-a"
+	seedPage(t, store, "team-a", "Team A", &gid, true, true)
+
+	tests := []struct {
+		name    string
+		slug    string
+		payload map[string]interface{}
+		code    int
+	}{
+		{"other group's slug", "team-a", map[string]interface{}{"title": "B", "groupId": "g-b"}, http.StatusConflict},
+		{"global over group page", "team-a", map[string]interface{}{"title": "Global"}, http.StatusConflict},
+		{"second page for group", "team-a-2", map[string]interface{}{"title": "A2", "groupId": "g-a"}, http.StatusConflict},
+		{"invalid new slug", "Team B!", map[string]interface{}{"title": "B", "groupId": "g-b"}, http.StatusBadRequest},
+		{"same group", "team-a", map[string]interface{}{"title": "Team A", "groupId": "g-a", "public": true, "enabled": true}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/slug", tt.slug, tt.payload))
+			if w.Code != tt.code {
+				t.Errorf("Expected %d, got %d (body: %s)", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	page, _ := store.GetStatusPageBySlug("team-a")
+	if page == nil || page.GroupID == nil || *page.GroupID != "g-a" {
+		t.Errorf("team-a page should still belong to g-a, got %+v", page)
+	}
+}
@@ -2,11 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/projecthelena/warden/internal/config"
 	"github.com/projecthelena/warden/internal/db"
@@ -492,22 +498,22 @@ func TestCreateMonitor_NotifFatigueValidation(t *testing.T) {
 		{
 			name:     "threshold_0",
 			payload:  map[string]interface{}{"name": "T0", "url": "http://test.com", "groupId": "g-default", "interval": 60, "confirmationThreshold": 0},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "threshold_101",
 			payload:  map[string]interface{}{"name": "T101", "url": "http://test.com", "groupId": "g-default", "interval": 60, "confirmationThreshold": 101},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "cooldown_negative",
 			payload:  map[string]interface{}{"name": "CN", "url": "http://test.com", "groupId": "g-default", "interval": 60, "notificationCooldownMinutes": -1},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "cooldown_1441",
 			payload:  map[string]interface{}{"name": "C1441", "url": "http://test.com", "groupId": "g-default", "interval": 60, "notificationCooldownMinutes": 1441},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "valid_boundaries_min",
@@ -522,12 +528,12 @@ func TestCreateMonitor_NotifFatigueValidation(t *testing.T) {
 		{
 			name:     "latency_threshold_0",
 			payload:  map[string]interface{}{"name": "LT0", "url": "http://test.com", "groupId": "g-default", "interval": 60, "latencyThreshold": 0},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "latency_threshold_negative",
 			payload:  map[string]interface{}{"name": "LTN", "url": "http://test.com", "groupId": "g-default", "interval": 60, "latencyThreshold": -1},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "latency_threshold_valid",
@@ -539,6 +545,36 @@ func TestCreateMonitor_NotifFatigueValidation(t *testing.T) {
 			payload:  map[string]interface{}{"name": "NoOv", "url": "http://test.com", "groupId": "g-default", "interval": 60},
 			expected: http.StatusCreated,
 		},
+		{
+			name:     "degraded_hysteresis_valid",
+			payload:  map[string]interface{}{"name": "DH", "url": "http://test.com", "groupId": "g-default", "interval": 60, "degradedThresholdChecks": 3, "degradedWindowChecks": 5},
+			expected: http.StatusCreated,
+		},
+		{
+			name:     "degraded_threshold_exceeds_window",
+			payload:  map[string]interface{}{"name": "DHX", "url": "http://test.com", "groupId": "g-default", "interval": 60, "degradedThresholdChecks": 6, "degradedWindowChecks": 5},
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "degraded_window_too_large",
+			payload:  map[string]interface{}{"name": "DHW", "url": "http://test.com", "groupId": "g-default", "interval": 60, "degradedWindowChecks": 51},
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "down_backoff_valid",
+			payload:  map[string]interface{}{"name": "BO", "url": "http://test.com", "groupId": "g-default", "interval": 60, "downBackoffInterval": 300, "downBackoffAfterChecks": 10},
+			expected: http.StatusCreated,
+		},
+		{
+			name:     "down_backoff_not_slower",
+			payload:  map[string]interface{}{"name": "BOS", "url": "http://test.com", "groupId": "g-default", "interval": 60, "downBackoffInterval": 60},
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "down_backoff_after_zero",
+			payload:  map[string]interface{}{"name": "BOZ", "url": "http://test.com", "groupId": "g-default", "interval": 60, "downBackoffInterval": 300, "downBackoffAfterChecks": 0},
+			expected: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tc := range tests {
@@ -569,22 +605,22 @@ func TestUpdateMonitor_NotifFatigueValidation(t *testing.T) {
 		{
 			name:     "threshold_0",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "confirmationThreshold": 0},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "threshold_101",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "confirmationThreshold": 101},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "cooldown_negative",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "notificationCooldownMinutes": -1},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "cooldown_1441",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "notificationCooldownMinutes": 1441},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "valid_update",
@@ -594,12 +630,12 @@ func TestUpdateMonitor_NotifFatigueValidation(t *testing.T) {
 		{
 			name:     "latency_threshold_0",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "latencyThreshold": 0},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "latency_threshold_negative",
 			payload:  map[string]interface{}{"name": "Test", "url": "http://test.com", "interval": 60, "latencyThreshold": -1},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "latency_threshold_valid",
@@ -637,6 +673,84 @@ func TestUpdateMonitor_NotifFatigueValidation(t *testing.T) {
 	}
 }
 
+func TestUpdateMonitor_KeepsDownBackoff(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	backoff, after := 300, 10
+	if err := s.CreateMonitor(db.Monitor{ID: "m-bo", GroupID: "g-default", Name: "Test", URL: "http://test.com", Interval: 60, Active: true, DownBackoffInterval: &backoff, DownBackoffAfterChecks: &after}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	r := chi.NewRouter()
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	update := func(payload map[string]interface{}) {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PUT", "/api/monitors/m-bo", bytes.NewBuffer(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	check := func(wantInterval, wantAfter int) {
+		t.Helper()
+		mon, err := s.GetMonitor("m-bo")
+		if err != nil {
+			t.Fatalf("GetMonitor failed: %v", err)
+		}
+		if mon.DownBackoffInterval == nil || *mon.DownBackoffInterval != wantInterval || mon.DownBackoffAfterChecks == nil || *mon.DownBackoffAfterChecks != wantAfter {
+			t.Errorf("Expected backoff %d after %d, got %v after %v", wantInterval, wantAfter, mon.DownBackoffInterval, mon.DownBackoffAfterChecks)
+		}
+	}
+
+	// The dashboard's edit form sends neither field
+	update(map[string]interface{}{"name": "Renamed", "url": "http://test.com", "interval": 60})
+	check(300, 10)
+
+	// One field changes just that field
+	update(map[string]interface{}{"name": "Renamed", "url": "http://test.com", "interval": 60, "downBackoffAfterChecks": 3})
+	check(300, 3)
+}
+
+func TestUpdateMonitor_KeepsDegradedHysteresis(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	threshold, window := 3, 5
+	if err := s.CreateMonitor(db.Monitor{ID: "m-dh", GroupID: "g-default", Name: "Test", URL: "http://test.com", Interval: 60, Active: true, DegradedThresholdChecks: &threshold, DegradedWindowChecks: &window}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	r := chi.NewRouter()
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	update := func(payload map[string]interface{}) int {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PUT", "/api/monitors/m-dh", bytes.NewBuffer(body)))
+		return w.Code
+	}
+	check := func(wantThreshold, wantWindow int) {
+		t.Helper()
+		mon, err := s.GetMonitor("m-dh")
+		if err != nil {
+			t.Fatalf("GetMonitor failed: %v", err)
+		}
+		if mon.DegradedThresholdChecks == nil || *mon.DegradedThresholdChecks != wantThreshold || mon.DegradedWindowChecks == nil || *mon.DegradedWindowChecks != wantWindow {
+			t.Errorf("Expected %d of %d, got %v of %v", wantThreshold, wantWindow, mon.DegradedThresholdChecks, mon.DegradedWindowChecks)
+		}
+	}
+
+	// The dashboard's edit form sends neither field
+	if code := update(map[string]interface{}{"name": "Renamed", "url": "http://test.com", "interval": 60}); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	check(3, 5)
+
+	// One field is validated against the stored other
+	if code := update(map[string]interface{}{"name": "Renamed", "url": "http://test.com", "interval": 60, "degradedThresholdChecks": 6}); code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a threshold above the stored window, got %d", code)
+	}
+	if code := update(map[string]interface{}{"name": "Renamed", "url": "http://test.com", "interval": 60, "degradedThresholdChecks": 4}); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	check(4, 5)
+}
+
 func TestGetUptime_IncludesOverrideFields(t *testing.T) {
 	crudH, _, _, _, s := setupTest(t)
 	manager := uptime.NewManager(s)
@@ -947,7 +1061,7 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Invalid Method", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"method": "PATCH"},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "timeout_too_high",
@@ -955,7 +1069,7 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Timeout High", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"timeoutSeconds": 121},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "retry_too_high",
@@ -963,7 +1077,7 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Retry High", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"retryCount": 6},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "too_many_headers",
@@ -971,7 +1085,7 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Headers", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"headers": makeLargeHeaders(51)},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "body_too_large",
@@ -979,7 +1093,7 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Body Large", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"body": makeLargeString(10241)},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "invalid_accepted_codes",
@@ -987,7 +1101,15 @@ func TestValidateRequestConfig(t *testing.T) {
 				"name": "Bad Codes", "url": "http://test.com", "groupId": "g-default", "interval": 60,
 				"requestConfig": map[string]interface{}{"acceptedStatusCodes": "abc-xyz"},
 			},
-			expected: http.StatusBadRequest,
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid_expected_header",
+			payload: map[string]interface{}{
+				"name": "Bad Header", "url": "http://test.com", "groupId": "g-default", "interval": 60,
+				"requestConfig": map[string]interface{}{"expectedHeaders": []map[string]string{{"name": "Content Type"}}},
+			},
+			expected: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "valid_config",
@@ -998,6 +1120,7 @@ func TestValidateRequestConfig(t *testing.T) {
 					"timeoutSeconds":      10,
 					"retryCount":          2,
 					"acceptedStatusCodes": "200-299,301",
+					"expectedHeaders":     []map[string]string{{"name": "Content-Type", "value": "application/json"}, {"name": "Strict-Transport-Security"}},
 				},
 			},
 			expected: http.StatusCreated,
@@ -1131,3 +1254,733 @@ func TestGetUptime_IncludesRequestConfig(t *testing.T) {
 
 	_ = crudH // used in setup
 }
+
+func TestMonitorAnnotations(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	uptimeH := NewUptimeHandler(uptime.NewManager(s), s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-ann", GroupID: "g-default", Name: "Annotated", URL: "http://test.com", Interval: 60, Active: true}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors/{id}/annotations", uptimeH.CreateAnnotation)
+	r.Get("/api/monitors/{id}/latency", uptimeH.GetMonitorLatency)
+
+	post := func(id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors/"+id+"/annotations", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post("m-ann", `{"kind":"deploy","message":"Deployed v2.3.1"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("m-ann", `{"kind":"party","message":"x"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown kind, got %d", w.Code)
+	}
+	if w := post("m-ann", `{"message":"   "}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty message, got %d", w.Code)
+	}
+	if w := post("missing", `{"message":"x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown monitor, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/monitors/m-ann/latency?range=24h", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp LatencyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Points == nil || len(resp.Annotations) != 1 || resp.Annotations[0].Message != "Deployed v2.3.1" {
+		t.Errorf("Expected latency response with annotation, got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/monitors/m-ann/latency?range=7d&tz=Europe/Berlin", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with tz, got %d: %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/monitors/m-ann/latency?tz=Mars/Olympus", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid tz, got %d", w.Code)
+	}
+}
+
+func TestFleetAnnotations(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	uptimeH := NewUptimeHandler(uptime.NewManager(s), s)
+	eventH := NewEventHandler(s, uptime.NewManager(s))
+	_ = s.CreateGroup(db.Group{ID: "g-edge", Name: "Edge"})
+	for _, m := range []db.Monitor{
+		{ID: "m-api", GroupID: "g-default", Name: "API", URL: "http://api.test", Interval: 60, Tags: []string{"prod"}},
+		{ID: "m-web", GroupID: "g-default", Name: "Web", URL: "http://web.test", Interval: 60},
+		{ID: "m-cdn", GroupID: "g-edge", Name: "CDN", URL: "http://cdn.test", Interval: 60},
+	} {
+		if err := s.CreateMonitor(m); err != nil {
+			t.Fatalf("Failed to create monitor: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/annotations", uptimeH.CreateFleetAnnotation)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/annotations", strings.NewReader(body)))
+		return w
+	}
+
+	w := post(`{"message":"Deployed 4f2c1a","tags":["PROD"],"groups":["g-edge"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp FleetAnnotationResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Kind != db.AnnotationKindDeploy || len(resp.MonitorIDs) != 2 {
+		t.Errorf("Expected tag and group targets to match 2 monitors, got %+v", resp)
+	}
+
+	// No targets applies to the whole fleet
+	if w := post(`{"kind":"config","message":"Rotated certificates"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", w.Code)
+	} else {
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if len(resp.MonitorIDs) != 3 {
+			t.Errorf("Expected all 3 monitors, got %+v", resp.MonitorIDs)
+		}
+	}
+
+	if w := post(`{"message":"x","tags":["nope"]}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when nothing matches, got %d", w.Code)
+	}
+	if w := post(`{"message":"x","tags":["bad,tag"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid tag, got %d", w.Code)
+	}
+
+	// The events timeline shows each fleet-wide annotation once
+	ew := httptest.NewRecorder()
+	eventH.GetSystemEvents(ew, httptest.NewRequest("GET", "/api/events", nil))
+	var events struct {
+		Annotations []AnnotationEventDTO `json:"annotations"`
+	}
+	_ = json.Unmarshal(ew.Body.Bytes(), &events)
+	if len(events.Annotations) != 2 {
+		t.Fatalf("Expected 2 timeline annotations, got %+v", events.Annotations)
+	}
+	counts := map[string]int{}
+	for _, a := range events.Annotations {
+		counts[a.Message] = len(a.Monitors)
+	}
+	if counts["Deployed 4f2c1a"] != 2 || counts["Rotated certificates"] != 3 {
+		t.Errorf("Unexpected timeline monitors per annotation: %v", counts)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{" Prod ", "prod", "team:payments", ""})
+	if err != nil || len(tags) != 2 || tags[0] != "prod" || tags[1] != "team:payments" {
+		t.Errorf("Unexpected normalized tags: %v (err %v)", tags, err)
+	}
+	for _, bad := range []string{"a,b", "-lead", "with space", strings.Repeat("x", maxTagLength+1)} {
+		if _, err := normalizeTags([]string{bad}); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestMonitorUptimeErrorKinds(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	uptimeH := NewUptimeHandler(uptime.NewManager(s), s)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-err", GroupID: "g-default", Name: "Flaky", URL: "http://test.com", Interval: 60, Active: true}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	now := time.Now()
+	_ = s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-err", Status: "up", Latency: 20, Timestamp: now, StatusCode: 200},
+		{MonitorID: "m-err", Status: "down", Timestamp: now, ErrorKind: db.ErrorKindTimeout},
+		{MonitorID: "m-err", Status: "down", Timestamp: now, StatusCode: 502, ErrorKind: db.ErrorKindHTTP5xx},
+		{MonitorID: "m-err", Status: "down", Timestamp: now, StatusCode: 503, ErrorKind: db.ErrorKindHTTP5xx},
+	})
+
+	r := chi.NewRouter()
+	r.Get("/api/monitors/{id}/uptime", uptimeH.GetMonitorUptime)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/monitors/m-err/uptime", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MonitorUptimeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.ErrorKinds[db.ErrorKindHTTP5xx] != 2 || resp.ErrorKinds[db.ErrorKindTimeout] != 1 {
+		t.Errorf("Unexpected error kinds: %v", resp.ErrorKinds)
+	}
+	if resp.Uptime24h <= 0 || resp.Uptime24h >= 100 {
+		t.Errorf("Expected partial uptime, got %v", resp.Uptime24h)
+	}
+}
+
+func TestCheckMonitorNow(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	manager := uptime.NewManager(s)
+	manager.Start()
+	defer manager.Stop()
+	uptimeH := NewUptimeHandler(manager, s)
+
+	if err := s.CreateMonitor(db.Monitor{ID: "m-now", GroupID: "g-default", Name: "Now", URL: target.URL, Interval: 3600, Active: true}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	if err := s.CreateMonitor(db.Monitor{ID: "m-paused", GroupID: "g-default", Name: "Paused", URL: target.URL, Interval: 3600, Active: false}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	manager.Sync()
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors/{id}/check", uptimeH.CheckMonitor)
+	post := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors/"+id+"/check", nil))
+		return w
+	}
+
+	before := time.Now().UTC()
+	w := post("m-now")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ManualCheckResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Pending || resp.Result == nil || !resp.Result.IsUp || resp.Result.StatusCode != 200 || resp.Result.Timestamp.Before(before) {
+		t.Errorf("Expected a fresh successful result, got %+v (result %+v)", resp, resp.Result)
+	}
+
+	if w := post("m-paused"); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for paused monitor, got %d", w.Code)
+	}
+	if w := post("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown monitor, got %d", w.Code)
+	}
+}
+
+func TestCheckMonitorNow_ScheduledCheckRecordedLast(t *testing.T) {
+	_, _, _, _, s := setupTest(t)
+	// The scheduled check starts first but finishes only after the manual one
+	scheduledStarted := make(chan struct{})
+	release := make(chan struct{})
+	var requests atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(scheduledStarted)
+			select {
+			case <-release:
+			case <-time.After(3 * time.Second):
+			}
+		} else {
+			time.AfterFunc(20*time.Millisecond, func() { close(release) })
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	manager := uptime.NewManager(s)
+	manager.Start()
+	defer manager.Stop()
+	uptimeH := NewUptimeHandler(manager, s)
+
+	if err := s.CreateMonitor(db.Monitor{ID: "m-now", GroupID: "g-default", Name: "Now", URL: target.URL, Interval: 3600, Active: true}); err != nil {
+		t.Fatalf("Failed to create monitor: %v", err)
+	}
+	manager.Sync()
+	select {
+	case <-scheduledStarted:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the scheduled check to start")
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors/{id}/check", uptimeH.CheckMonitor)
+	before := time.Now().UTC()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors/m-now/check", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ManualCheckResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Result == nil || resp.Result.Timestamp.Before(before) {
+		t.Errorf("Expected the manual check's result, got %+v", resp.Result)
+	}
+}
+
+func TestBulkMonitors(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	_ = s.CreateGroup(db.Group{ID: "g-web", Name: "Web"})
+	seed := []db.Monitor{
+		{ID: "m-a", GroupID: "g-default", Name: "A", URL: "http://a.com", Interval: 60, Active: true, Tags: []string{"prod"}},
+		{ID: "m-b", GroupID: "g-default", Name: "B", URL: "http://b.com", Interval: 60, Active: true},
+		{ID: "m-c", GroupID: "g-web", Name: "C", URL: "http://c.com", Interval: 60, Active: true},
+	}
+	for _, m := range seed {
+		if err := s.CreateMonitor(m); err != nil {
+			t.Fatalf("Failed to create monitor: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors/bulk", crudH.BulkMonitors)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors/bulk", strings.NewReader(body)))
+		return w
+	}
+
+	// Tag filter and group filter are combined
+	w := post(`{"action":"set_interval","interval":300,"tags":["prod"],"groups":["g-web"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp BulkMonitorsResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.MonitorIDs) != 2 {
+		t.Errorf("Expected 2 monitors changed, got %v", resp.MonitorIDs)
+	}
+	if m, _ := s.GetMonitor("m-c"); m.Interval != 300 {
+		t.Errorf("Expected m-c interval 300, got %d", m.Interval)
+	}
+	if m, _ := s.GetMonitor("m-b"); m.Interval != 60 {
+		t.Errorf("Expected m-b untouched, got interval %d", m.Interval)
+	}
+
+	if w := post(`{"action":"add_tag","tag":"Team:Web","monitors":["m-b","m-c"]}`); w.Code != http.StatusOK {
+		t.Fatalf("add_tag: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if m, _ := s.GetMonitor("m-b"); !m.HasTag("team:web") {
+		t.Errorf("Expected m-b to be tagged, got %v", m.Tags)
+	}
+
+	if w := post(`{"action":"pause","tags":["team:web"]}`); w.Code != http.StatusOK {
+		t.Fatalf("pause: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if m, _ := s.GetMonitor("m-c"); m.Active {
+		t.Error("Expected m-c to be paused")
+	}
+
+	// An unknown ID fails the whole request without changes
+	if w := post(`{"action":"delete","monitors":["m-a","missing"]}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown monitor, got %d", w.Code)
+	}
+	if _, err := s.GetMonitor("m-a"); err != nil {
+		t.Error("Expected m-a to survive a failed bulk delete")
+	}
+
+	for _, body := range []string{
+		`{"action":"delete"}`,
+		`{"action":"explode","monitors":["m-a"]}`,
+		`{"action":"set_interval","interval":5,"monitors":["m-a"]}`,
+		`{"action":"add_tag","tag":"bad tag","monitors":["m-a"]}`,
+	} {
+		if w := post(body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+
+	if w := post(`{"action":"delete","groups":["g-web"]}`); w.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := s.GetMonitor("m-c"); !errors.Is(err, db.ErrMonitorNotFound) {
+		t.Errorf("Expected m-c to be deleted, got %v", err)
+	}
+}
+
+func TestCreateGroup_NumbersDuplicateIDs(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+
+	create := func(name string) string {
+		t.Helper()
+		body, _ := json.Marshal(map[string]string{"name": name})
+		w := httptest.NewRecorder()
+		crudH.CreateGroup(w, httptest.NewRequest("POST", "/api/groups", bytes.NewBuffer(body)))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("CreateGroup(%q) failed: %d %s", name, w.Code, w.Body.String())
+		}
+		var g db.Group
+		_ = json.NewDecoder(w.Body).Decode(&g)
+		return g.ID
+	}
+
+	if id := create("Ops"); id != "g-ops" {
+		t.Errorf("Expected g-ops, got %s", id)
+	}
+	if id := create("ops!"); id != "g-ops-2" {
+		t.Errorf("Expected g-ops-2, got %s", id)
+	}
+	if id := create("???"); id == "g-" || !strings.HasPrefix(id, "g-") {
+		t.Errorf("Expected a generated ID for a symbol-only name, got %s", id)
+	}
+
+	// Renaming keeps the ID
+	if err := s.UpdateGroup("g-ops", "Operations"); err != nil {
+		t.Fatal(err)
+	}
+	groups, _ := s.GetGroups()
+	found := false
+	for _, g := range groups {
+		if g.ID == "g-ops" && g.Name == "Operations" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected renamed group to keep its ID")
+	}
+}
+
+func TestCreateMonitor_URLValidation(t *testing.T) {
+	crudH, _, _, _, _ := setupTest(t)
+	origLookup := lookupHost
+	defer func() { lookupHost = origLookup }()
+	lookupHost = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "internal.example":
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.5")}}, nil
+		case "public.example":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors", crudH.CreateMonitor)
+	created := 0
+	create := func(target string) int {
+		created++
+		body, _ := json.Marshal(map[string]interface{}{"name": fmt.Sprintf("Target %d", created), "url": target, "groupId": "g-default", "interval": 60})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors", bytes.NewBuffer(body)))
+		return w.Code
+	}
+
+	// Scheme allow-list applies regardless of the private-target setting
+	for _, target := range []string{"ftp://example.com", "file:///etc/passwd", "gopher://example.com"} {
+		if code := create(target); code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d", target, code)
+		}
+	}
+	if err := validateMonitorURL(context.Background(), "http://127.0.0.1:8080/health", false); err != nil {
+		t.Fatalf("private targets should be allowed by default, got %v", err)
+	}
+	if code := create("http://--report-wide/"); code != http.StatusUnprocessableEntity {
+		t.Errorf("host starting with a hyphen: expected 422, got %d", code)
+	}
+
+	crudH.manager.SetBlockPrivateTargets(true)
+	blocked := []string{
+		"http://127.0.0.1:8080/health",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/",
+		"http://localhost:9096/",
+		"https://internal.example/",
+	}
+	for _, target := range blocked {
+		if code := create(target); code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422 with private targets blocked, got %d", target, code)
+		}
+	}
+	if code := create("https://public.example/"); code != http.StatusCreated {
+		t.Errorf("public host: expected 201, got %d", code)
+	}
+	// Unresolvable hosts are left to the check itself
+	if err := validateMonitorURL(context.Background(), "https://unresolvable.example/", true); err != nil {
+		t.Errorf("unresolvable host: expected no error, got %v", err)
+	}
+}
+
+func TestUpdateMonitor_URLValidation(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Web", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+	crudH.manager.SetBlockPrivateTargets(true)
+
+	r := chi.NewRouter()
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	for _, target := range []string{"ftp://example.com", "http://10.0.0.1/", "not a url"} {
+		body, _ := json.Marshal(map[string]interface{}{"name": "Web", "url": target, "interval": 60})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PUT", "/api/monitors/m1", bytes.NewBuffer(body)))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d", target, w.Code)
+		}
+	}
+
+	m, err := s.GetMonitor("m1")
+	if err != nil {
+		t.Fatalf("GetMonitor: %v", err)
+	}
+	if m.URL != "http://example.com" {
+		t.Errorf("URL should be unchanged, got %q", m.URL)
+	}
+}
+
+func TestNormalizeMonitorURL(t *testing.T) {
+	same := [][2]string{
+		{"https://Example.com/", "https://example.com"},
+		{"https://example.com:443/health/", "https://example.com/health"},
+		{"http://example.com:80", "HTTP://EXAMPLE.COM"},
+		{"https://example.com/?b=2&a=1", "https://example.com?a=1&b=2"},
+		{"https://example.com/page#section", "https://example.com/page"},
+		{"https://example.com./", "https://example.com"},
+	}
+	for _, c := range same {
+		if a, b := normalizeMonitorURL(c[0]), normalizeMonitorURL(c[1]); a != b {
+			t.Errorf("expected %q and %q to match, got %q vs %q", c[0], c[1], a, b)
+		}
+	}
+	different := [][2]string{
+		{"http://example.com", "https://example.com"},
+		{"https://example.com:8443", "https://example.com"},
+		{"https://example.com/a", "https://example.com/b"},
+		{"https://example.com/?a=1", "https://example.com/?a=2"},
+	}
+	for _, c := range different {
+		if normalizeMonitorURL(c[0]) == normalizeMonitorURL(c[1]) {
+			t.Errorf("expected %q and %q to differ", c[0], c[1])
+		}
+	}
+}
+
+func TestCreateMonitor_DuplicateURL(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	if err := s.CreateMonitor(db.Monitor{ID: "m-api", GroupID: "g-default", Name: "API", URL: "https://api.example.com/health", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors", crudH.CreateMonitor)
+	post := func(payload map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/monitors", bytes.NewBuffer(body)))
+		return w
+	}
+
+	w := post(map[string]interface{}{"name": "API again", "url": "https://API.example.com:443/health/", "groupId": "g-default", "interval": 60})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate URL, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp duplicateURLResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.DuplicateOf.ID != "m-api" || resp.DuplicateOf.GroupID != "g-default" {
+		t.Errorf("expected duplicateOf to point at m-api, got %+v", resp.DuplicateOf)
+	}
+
+	// External monitors have no URL to collide on
+	w = post(map[string]interface{}{"name": "Alerts", "type": "external", "groupId": "g-default"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for external monitor, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = post(map[string]interface{}{"name": "API again", "url": "https://api.example.com/health", "groupId": "g-default", "interval": 60, "allowDuplicateUrl": true})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 with allowDuplicateUrl, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestMonitor_DuplicateName(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	for _, id := range []string{"API", "Web"} {
+		if err := s.CreateMonitor(db.Monitor{ID: "m-" + id, GroupID: "g-default", Name: id, URL: "https://example.com/" + id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors", crudH.CreateMonitor)
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	do := func(method, path string, payload map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBuffer(body)))
+		return w
+	}
+
+	w := do("POST", "/api/monitors", map[string]interface{}{"name": "api", "type": "external", "groupId": "g-default"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a taken name, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeErrorResponse(t, w); resp.Code != ErrCodeDuplicateMonitor {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeDuplicateMonitor)
+	}
+
+	w = do("PUT", "/api/monitors/m-Web", map[string]interface{}{"name": "API", "url": "https://example.com/Web", "interval": 60})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 when renaming to a taken name, got %d: %s", w.Code, w.Body.String())
+	}
+	if resp := decodeErrorResponse(t, w); resp.Code != ErrCodeDuplicateMonitor {
+		t.Errorf("code = %q, want %q", resp.Code, ErrCodeDuplicateMonitor)
+	}
+
+	w = do("PUT", "/api/monitors/m-missing", map[string]interface{}{"name": "Missing", "url": "https://example.com/missing", "interval": 60})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing monitor, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateMonitor_Dependencies(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	for _, id := range []string{"lb", "api", "web"} {
+		if err := s.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: id, URL: "http://example.com/" + id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	update := func(id string, dependsOn []string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"name": id, "url": "http://example.com/" + id, "interval": 60, "dependsOn": dependsOn})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PUT", "/api/monitors/"+id, bytes.NewBuffer(body)))
+		return w
+	}
+
+	if w := update("api", []string{"lb"}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := update("web", []string{"api"}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	rejected := []struct {
+		name      string
+		id        string
+		dependsOn []string
+	}{
+		{"self", "lb", []string{"lb"}},
+		{"unknown", "lb", []string{"missing"}},
+		{"direct cycle", "lb", []string{"api"}},
+		{"indirect cycle", "lb", []string{"web"}},
+	}
+	for _, tc := range rejected {
+		if w := update(tc.id, tc.dependsOn); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", tc.name, w.Code, w.Body.String())
+		}
+	}
+
+	// Replacing a monitor's own edges is not a cycle
+	if w := update("web", []string{"lb"}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	deps, err := s.GetMonitorDependencies()
+	if err != nil {
+		t.Fatalf("GetMonitorDependencies: %v", err)
+	}
+	if len(deps) != 2 || deps["api"][0] != "lb" || deps["web"][0] != "lb" {
+		t.Fatalf("unexpected dependencies: %v", deps)
+	}
+
+	// Omitting dependsOn leaves them unchanged; an empty list clears them
+	body, _ := json.Marshal(map[string]interface{}{"name": "api", "url": "http://example.com/api", "interval": 60})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PUT", "/api/monitors/api", bytes.NewBuffer(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if w := update("api", []string{}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	deps, _ = s.GetMonitorDependencies()
+	if _, ok := deps["api"]; ok {
+		t.Fatalf("expected api dependencies cleared, got %v", deps)
+	}
+	if len(deps["web"]) != 1 {
+		t.Fatalf("expected web dependencies untouched, got %v", deps)
+	}
+}
+
+func TestCreateMonitor_Composite(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	for _, id := range []string{"eu", "us", "ap"} {
+		if err := s.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: id, URL: "http://" + id + ".example.com", Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/monitors", crudH.CreateMonitor)
+	r.Put("/api/monitors/{id}", crudH.UpdateMonitor)
+	send := func(method, url string, payload map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, url, bytes.NewBuffer(body)))
+		return w
+	}
+
+	invalid := []map[string]interface{}{
+		{"name": "No members", "type": "composite", "groupId": "g-default", "minUp": 1},
+		{"name": "Unknown", "type": "composite", "groupId": "g-default", "members": []string{"eu", "missing"}, "minUp": 1},
+		{"name": "Unreachable", "type": "composite", "groupId": "g-default", "members": []string{"eu", "us"}, "minUp": 3},
+		{"name": "Zero", "type": "composite", "groupId": "g-default", "members": []string{"eu", "us"}, "minUp": 0},
+	}
+	for _, payload := range invalid {
+		if w := send("POST", "/api/monitors", payload); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", payload["name"], w.Code, w.Body.String())
+		}
+	}
+
+	w := send("POST", "/api/monitors", map[string]interface{}{
+		"name": "Global", "type": "composite", "groupId": "g-default", "members": []string{"eu", "us", "ap"}, "minUp": 2,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created db.Monitor
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.Type != db.MonitorTypeComposite {
+		t.Fatalf("expected composite type, got %q", created.Type)
+	}
+
+	// Composites cannot be nested
+	w = send("POST", "/api/monitors", map[string]interface{}{
+		"name": "Nested", "type": "composite", "groupId": "g-default", "members": []string{created.ID, "eu"}, "minUp": 1,
+	})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for nested composite, got %d", w.Code)
+	}
+
+	w = send("PUT", "/api/monitors/"+created.ID, map[string]interface{}{"name": "Global", "interval": 60, "minUp": 3})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = send("PUT", "/api/monitors/"+created.ID, map[string]interface{}{"name": "Global", "interval": 60, "members": []string{"eu", "us"}})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 when members drop below minUp, got %d", w.Code)
+	}
+	w = send("PUT", "/api/monitors/eu", map[string]interface{}{"name": "eu", "url": "http://eu.example.com", "interval": 60, "minUp": 1})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for minUp on a non-composite monitor, got %d", w.Code)
+	}
+
+	rules, err := s.GetCompositeRules()
+	if err != nil {
+		t.Fatalf("GetCompositeRules: %v", err)
+	}
+	if rule := rules[created.ID]; rule.MinUp != 3 || len(rule.Members) != 3 {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+}
@@ -2,8 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
@@ -39,16 +42,18 @@ func getEventsForDTO(store *db.Store, monitorID string) []MonitorEvent {
 
 // Response Structures matching Frontend Store
 type HistoryPoint struct {
-	Status     string    `json:"status"`
-	Latency    int64     `json:"latency"`
-	Timestamp  time.Time `json:"timestamp"`
-	StatusCode int       `json:"statusCode"`
+	Status     string          `json:"status"`
+	Latency    int64           `json:"latency"`
+	Timestamp  time.Time       `json:"timestamp"`
+	StatusCode int             `json:"statusCode"`
+	Timing     *db.CheckTiming `json:"timing,omitempty"` // Request phase breakdown of HTTP checks
 }
 
 type MonitorDTO struct {
 	ID                      string            `json:"id"`
 	Name                    string            `json:"name"`
 	URL                     string            `json:"url"`
+	Type                    string            `json:"type"`
 	Status                  string            `json:"status"`
 	Active                  bool              `json:"active"`
 	Latency                 int64             `json:"latency"`
@@ -60,6 +65,15 @@ type MonitorDTO struct {
 	NotificationCooldownMin *int              `json:"notificationCooldownMinutes,omitempty"`
 	LatencyThreshold        *int              `json:"latencyThreshold,omitempty"`
 	RequestConfig           *db.RequestConfig `json:"requestConfig,omitempty"`
+	Tags                    []string          `json:"tags,omitempty"`
+	DegradedThresholdChecks *int              `json:"degradedThresholdChecks,omitempty"`
+	DegradedWindowChecks    *int              `json:"degradedWindowChecks,omitempty"`
+	DownBackoffInterval     *int              `json:"downBackoffInterval,omitempty"`
+	DownBackoffAfterChecks  *int              `json:"downBackoffAfterChecks,omitempty"`
+	DependsOn               []string          `json:"dependsOn,omitempty"`  // IDs of monitors this one depends on
+	AffectedBy              string            `json:"affectedBy,omitempty"` // Down dependency blamed for this monitor being down
+	Members                 []string          `json:"members,omitempty"`    // Composite monitors: member monitor IDs
+	MinUp                   int               `json:"minUp,omitempty"`      // Composite monitors: members that must be up
 }
 
 type MonitorEvent struct {
@@ -78,15 +92,17 @@ type GroupDTO struct {
 type GroupOverviewDTO struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
-	Status string `json:"status"` // up, down, degraded
+	Status string `json:"status"` // up, down, degraded, maintenance
 }
 
 type OverviewResponse struct {
-	Groups []GroupOverviewDTO `json:"groups"`
+	Groups    []GroupOverviewDTO `json:"groups"`
+	WarmingUp bool               `json:"warmingUp,omitempty"` // Monitors are still loading after a restart
 }
 
 type UptimeResponse struct {
-	Groups []GroupDTO `json:"groups"`
+	Groups    []GroupDTO `json:"groups"`
+	WarmingUp bool       `json:"warmingUp,omitempty"` // Monitors are still loading; statuses are the last persisted checks
 }
 
 // GetHistory returns all monitors grouped by group with ping history.
@@ -95,20 +111,45 @@ type UptimeResponse struct {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        group_id query string false "Filter by group ID"
+// @Param        history  query int    false "History points per monitor (default 50, up to the configured history size)"
 // @Success      200  {object} UptimeResponse
-// @Failure      500  {string} string "Internal error"
+// @Failure      400  {object} ErrorResponse "Invalid history"
+// @Failure      500  {object} ErrorResponse "Internal error"
 // @Router       /uptime [get]
 func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	// Heartbeat bar density: wide views ask for more of the in-memory history
+	maxPoints := uptime.DefaultHistorySize
+	if v := r.URL.Query().Get("history"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > h.manager.HistorySize() {
+			writeError(w, http.StatusBadRequest, "history must be between 0 and "+strconv.Itoa(h.manager.HistorySize()))
+			return
+		}
+		maxPoints = n
+	}
+
 	// 1. Fetch Layout from DB (Groups + Monitors Metadata)
 	groups, err := h.store.GetGroups()
 	if err != nil {
-		http.Error(w, "Failed to load groups", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to load groups")
 		return
 	}
 
 	monitorsMeta, err := h.store.GetMonitors()
 	if err != nil {
-		http.Error(w, "Failed to load monitors", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to load monitors")
+		return
+	}
+
+	dependencies, err := h.store.GetMonitorDependencies()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to load monitor dependencies")
+		return
+	}
+
+	composites, err := h.store.GetCompositeRules()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to load composite monitors")
 		return
 	}
 
@@ -118,6 +159,8 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		groupMap[m.GroupID] = append(groupMap[m.GroupID], m)
 	}
 
+	lastKnown, warmingUp := lastKnownChecks(h.manager, h.store)
+
 	// 3. Construct Response
 	var groupDTOs []GroupDTO
 	filterGroupID := r.URL.Query().Get("group_id")
@@ -134,19 +177,24 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 
 			statusStr := "down" // Default if not running
 			latency := int64(0)
-			lastCheck := "Never"
+			lastCheck := "" // RFC3339, empty until the first check
+			affectedBy := ""
 			var historyPoints []HistoryPoint
 
 			if task != nil {
 				// It is running
 				history := task.GetHistory()
+				affectedBy = task.AffectedBy()
 
 				if len(history) > 0 {
 					last := history[len(history)-1]
+					if len(history) > maxPoints {
+						history = history[len(history)-maxPoints:]
+					}
 					threshold := task.GetLatencyThreshold()
 					if last.IsUp {
 						statusStr = "up"
-						if last.Latency > threshold {
+						if last.Latency > threshold || last.DegradedReason != "" {
 							statusStr = "degraded"
 						}
 					}
@@ -157,7 +205,7 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 						s := "down"
 						if h.IsUp {
 							s = "up"
-							if h.Latency > threshold {
+							if h.Latency > threshold || h.DegradedReason != "" {
 								s = "degraded"
 							}
 						}
@@ -166,12 +214,18 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 							Latency:    h.Latency,
 							Timestamp:  h.Timestamp,
 							StatusCode: h.StatusCode,
+							Timing:     h.Timing,
 						})
 					}
 				} else {
 					// Running but no history yet?
 					statusStr = "up" // Optimistic?
 				}
+			} else if c, ok := lastKnown[meta.ID]; ok && meta.Active {
+				// Manager still warming up: report the last persisted check
+				statusStr = checkStatus(c, meta, h.manager.GetLatencyThreshold())
+				latency = c.Latency
+				lastCheck = c.Timestamp.Format(time.RFC3339)
 			} else {
 				// Not running (inactive or manager hasn't synced yet)
 				if !meta.Active {
@@ -183,6 +237,7 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 				ID:                      meta.ID,
 				Name:                    meta.Name,
 				URL:                     meta.URL,
+				Type:                    meta.Type,
 				Status:                  statusStr,
 				Active:                  meta.Active,
 				Latency:                 latency,
@@ -194,6 +249,15 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 				NotificationCooldownMin: meta.NotificationCooldownMin,
 				LatencyThreshold:        meta.LatencyThreshold,
 				RequestConfig:           meta.RequestConfig,
+				Tags:                    meta.Tags,
+				DegradedThresholdChecks: meta.DegradedThresholdChecks,
+				DegradedWindowChecks:    meta.DegradedWindowChecks,
+				DownBackoffInterval:     meta.DownBackoffInterval,
+				DownBackoffAfterChecks:  meta.DownBackoffAfterChecks,
+				DependsOn:               dependencies[meta.ID],
+				AffectedBy:              affectedBy,
+				Members:                 composites[meta.ID].Members,
+				MinUp:                   composites[meta.ID].MinUp,
 			})
 		}
 
@@ -205,60 +269,141 @@ func (h *UptimeHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := UptimeResponse{
-		Groups: groupDTOs,
+		Groups:    groupDTOs,
+		WarmingUp: warmingUp,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// GetMonitorUptime returns uptime percentages for 24h, 7d, and 30d.
+// MonitorUptimeResponse holds uptime percentages and a breakdown of recent failures.
+type MonitorUptimeResponse struct {
+	Uptime24h  float64        `json:"uptime24h"`
+	Uptime7d   float64        `json:"uptime7d"`
+	Uptime30d  float64        `json:"uptime30d"`
+	ErrorKinds map[string]int `json:"errorKinds"`      // Failed checks per error kind over the last 7 days
+	Range      *UptimeRange   `json:"range,omitempty"` // Set when a range was requested
+}
+
+// UptimeRange is a monitor's uptime over a requested report range, e.g. last month.
+type UptimeRange struct {
+	Range         string    `json:"range"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"` // Exclusive; ranges that include today end at the next midnight
+	Timezone      string    `json:"timezone"`
+	UptimePercent *float64  `json:"uptimePercent"` // Null when there are no checks in the range
+	TotalChecks   int       `json:"totalChecks"`
+	// DowntimeMinutes is how long the monitor's outages lasted within the range
+	DowntimeMinutes float64 `json:"downtimeMinutes"`
+	// EstimatedCost is the business impact of that downtime, for monitors with a downtime cost
+	EstimatedCost *float64 `json:"estimatedCost,omitempty"`
+}
+
+// GetMonitorUptime returns uptime percentages for 24h, 7d, and 30d, and failure counts per error kind.
+// With range, it also returns the uptime over that range, with day, week, month and
+// quarter boundaries in the requester's timezone.
 // @Summary      Get monitor uptime stats
 // @Tags         uptime
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path string true "Monitor ID"
-// @Success      200  {object} object{uptime24h=number,uptime7d=number,uptime30d=number}
-// @Failure      400  {string} string "ID required"
-// @Failure      500  {string} string "Failed to calculate stats"
+// @Param        id         path  string true  "Monitor ID"
+// @Param        range      query string false "Report range: <n>d (up to 365d) or a preset: today, yesterday, this_week, last_week, this_month, last_month, this_quarter, last_quarter, this_year, last_year"
+// @Param        tz         query string false "IANA timezone for the range (default: user's timezone)"
+// @Param        week_start query string false "First day of the week for week presets, e.g. sunday (default: reports.week_start setting)"
+// @Success      200  {object} MonitorUptimeResponse
+// @Failure      400  {object} ErrorResponse "ID required"
+// @Failure      500  {object} ErrorResponse "Failed to calculate stats"
 // @Router       /monitors/{id}/uptime [get]
 func (h *UptimeHandler) GetMonitorUptime(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 
 	u24, u7, u30, err := h.store.GetUptimeStats(id)
 	if err != nil {
-		http.Error(w, "Failed to calculate stats: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to calculate stats: "+err.Error())
+		return
+	}
+
+	errorKinds, err := h.store.GetErrorKindCounts(id, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to count failures: "+err.Error())
 		return
 	}
 
-	resp := map[string]float64{
-		"uptime24h": u24,
-		"uptime7d":  u7,
-		"uptime30d": u30,
+	resp := MonitorUptimeResponse{
+		Uptime24h:  u24,
+		Uptime7d:   u7,
+		Uptime30d:  u30,
+		ErrorKinds: errorKinds,
+	}
+
+	if r.URL.Query().Has("range") {
+		rng, now, ok := resolveReportRange(w, r, h.store, "")
+		if !ok {
+			return
+		}
+		total, up, err := h.store.GetUptimeCountsBetween(id, rng.From, rng.To, nil)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "Failed to calculate stats: "+err.Error())
+			return
+		}
+		resp.Range = &UptimeRange{
+			Range:       rng.Name,
+			From:        rng.From,
+			To:          rng.To,
+			Timezone:    now.Location().String(),
+			TotalChecks: total,
+		}
+		if total > 0 {
+			pct := float64(up) / float64(total) * 100.0
+			resp.Range.UptimePercent = &pct
+		}
+		downtime, err := h.store.GetDowntimeBetween(id, rng.From, rng.To, now)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "Failed to calculate downtime: "+err.Error())
+			return
+		}
+		resp.Range.DowntimeMinutes = math.Round(downtime.Minutes()*10) / 10
+		cost, err := h.store.GetMonitorDowntimeCost(id)
+		if err != nil && !errors.Is(err, db.ErrDowntimeCostNotFound) {
+			writeError(w, http.StatusInternalServerError, "Failed to load downtime cost: "+err.Error())
+			return
+		}
+		if cost != nil {
+			estimate := db.EstimateDowntimeCost(cost.CostPerMinute, downtime)
+			resp.Range.EstimatedCost = &estimate
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
-// GetMonitorLatency returns latency datapoints over a time range.
+// LatencyResponse holds latency datapoints and the annotations within the same range.
+type LatencyResponse struct {
+	Points      []db.LatencyPoint `json:"points"`
+	Annotations []db.Annotation   `json:"annotations"`
+}
+
+// GetMonitorLatency returns latency datapoints and annotations over a time range.
 // @Summary      Get monitor latency history
 // @Tags         uptime
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id    path  string true  "Monitor ID"
 // @Param        range query string false "Time range: 1h, 24h, 7d, 30d (default 24h)"
-// @Success      200   {array} db.CheckResult
-// @Failure      400   {string} string "ID required"
-// @Failure      500   {string} string "Failed to fetch latency stats"
+// @Param        tz    query string false "IANA timezone for hour/day buckets (default: user's timezone)"
+// @Success      200   {object} LatencyResponse
+// @Failure      400   {object} ErrorResponse "ID required"
+// @Failure      500   {object} ErrorResponse "Failed to fetch latency stats"
 // @Router       /monitors/{id}/latency [get]
 func (h *UptimeHandler) GetMonitorLatency(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "ID required")
 		return
 	}
 
@@ -277,34 +422,332 @@ func (h *UptimeHandler) GetMonitorLatency(w http.ResponseWriter, r *http.Request
 		hours = 24
 	}
 
-	points, err := h.store.GetLatencyStats(id, hours)
+	loc, ok := resolveLocation(r, h.store)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid timezone")
+		return
+	}
+
+	points, err := h.store.GetLatencyStatsInLocation(id, hours, loc)
 	if err != nil {
-		http.Error(w, "Failed to fetch latency stats: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to fetch latency stats: "+err.Error())
 		return
 	}
 
+	annotations, err := h.store.GetAnnotations(id, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to fetch annotations")
+		return
+	}
+
+	resp := LatencyResponse{Points: points, Annotations: annotations}
+	if resp.Points == nil {
+		resp.Points = []db.LatencyPoint{}
+	}
+	if resp.Annotations == nil {
+		resp.Annotations = []db.Annotation{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(points)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// maxAnnotationLength is the maximum allowed length of an annotation message
+const maxAnnotationLength = 1000
+
+type annotationRequest struct {
+	Kind      string     `json:"kind"`
+	Message   string     `json:"message"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
-// GetOverview returns a high-level status for each group.
+// validate normalizes the request and returns an error message, or "" when valid.
+func (req *annotationRequest) validate() string {
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		return "message is required"
+	}
+	// SECURITY: Limit message length
+	if len(req.Message) > maxAnnotationLength {
+		return "message must be 1000 characters or less"
+	}
+	if req.Kind == "" {
+		req.Kind = db.AnnotationKindDeploy
+	}
+	switch req.Kind {
+	case db.AnnotationKindDeploy, db.AnnotationKindConfig, db.AnnotationKindOther:
+		return ""
+	default:
+		return "kind must be deploy, config or other"
+	}
+}
+
+func (req *annotationRequest) timestamp() time.Time {
+	if req.Timestamp != nil {
+		return req.Timestamp.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// manualCheckWait bounds how long CheckMonitor waits for the triggered check to finish.
+const manualCheckWait = 5 * time.Second
+
+// ManualCheckResponse is the outcome of an on-demand check.
+type ManualCheckResponse struct {
+	MonitorID string         `json:"monitorId"`
+	Pending   bool           `json:"pending"`          // The check is queued but did not finish within the wait
+	Result    *uptime.Status `json:"result,omitempty"` // Set once the check has completed
+}
+
+// CheckMonitor runs a check immediately instead of waiting for the next interval.
+// @Summary      Check monitor now
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Success      200  {object} ManualCheckResponse "Check completed"
+// @Success      202  {object} ManualCheckResponse "Check queued, result not yet available"
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Failure      409  {object} ErrorResponse "Monitor is paused"
+// @Failure      503  {object} ErrorResponse "Check queue is full"
+// @Router       /monitors/{id}/check [post]
+func (h *UptimeHandler) CheckMonitor(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "ID required")
+		return
+	}
+
+	dbMon, err := h.store.GetMonitor(id)
+	if err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return
+	}
+	if dbMon.Type == db.MonitorTypeExternal {
+		writeError(w, http.StatusBadRequest, "external monitors are updated by their alert source")
+		return
+	}
+	if dbMon.Type == db.MonitorTypeComposite {
+		writeError(w, http.StatusBadRequest, "composite monitors follow their members; check the members instead")
+		return
+	}
+
+	mon := h.manager.GetMonitor(id)
+	if !dbMon.Active || mon == nil {
+		writeError(w, http.StatusConflict, "monitor is paused")
+		return
+	}
+
+	triggeredAt := time.Now().UTC()
+	if !mon.CheckNow() {
+		writeError(w, http.StatusServiceUnavailable, "check queue is full, try again shortly")
+		return
+	}
+
+	// Wait briefly for the fresh result, like CreateMonitor does for the first check
+	deadline := time.NewTimer(manualCheckWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		// Checks are stamped when they start, so a scheduled check that began before this
+		// one can finish after it and be recorded last
+		history := mon.GetHistory()
+		for i := len(history) - 1; i >= 0; i-- {
+			if res := history[i]; !res.Timestamp.Before(triggeredAt) {
+				writeJSON(w, http.StatusOK, ManualCheckResponse{MonitorID: id, Result: &res})
+				return
+			}
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			writeJSON(w, http.StatusAccepted, ManualCheckResponse{MonitorID: id, Pending: true})
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateAnnotation attaches an annotation (deploy, config change) to a monitor's latency chart.
+// @Summary      Create monitor annotation
+// @Tags         uptime
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path string true "Monitor ID"
+// @Param        request body object{kind=string,message=string,timestamp=string} true "Annotation (kind: deploy, config or other; timestamp defaults to now)"
+// @Success      201  {object} db.Annotation
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse
+// @Router       /monitors/{id}/annotations [post]
+func (h *UptimeHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "ID required")
+		return
+	}
+
+	var req annotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if _, err := h.store.GetMonitor(id); err != nil {
+		if errors.Is(err, db.ErrMonitorNotFound) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeMonitorNotFound, "monitor not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to load monitor")
+		return
+	}
+
+	a := db.Annotation{MonitorID: id, Kind: req.Kind, Message: req.Message, Timestamp: req.timestamp()}
+	annotationID, err := h.store.CreateAnnotation(a)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create annotation")
+		return
+	}
+	a.ID = annotationID
+	a.CreatedAt = time.Now().UTC()
+	writeJSON(w, http.StatusCreated, a)
+}
+
+type fleetAnnotationRequest struct {
+	annotationRequest
+	Monitors []string `json:"monitors,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// FleetAnnotationResponse reports the monitors a fleet-wide annotation was attached to.
+type FleetAnnotationResponse struct {
+	BatchID    string    `json:"batchId"`
+	Kind       string    `json:"kind"`
+	Message    string    `json:"message"`
+	Timestamp  time.Time `json:"timestamp"`
+	MonitorIDs []string  `json:"monitorIds"`
+}
+
+// CreateFleetAnnotation records an annotation on every monitor matching the targets, e.g. from a
+// CI pipeline on deploy. A monitor matches when it is listed, in a listed group, or carries a
+// listed tag; without any target the annotation applies to all monitors.
+// @Summary      Create fleet-wide annotation
+// @Tags         uptime
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object{kind=string,message=string,timestamp=string,monitors=[]string,groups=[]string,tags=[]string} true "Annotation and targets"
+// @Success      201  {object} FleetAnnotationResponse
+// @Failure      400  {object} ErrorResponse
+// @Failure      404  {object} ErrorResponse "No monitors match the targets"
+// @Router       /annotations [post]
+func (h *UptimeHandler) CreateFleetAnnotation(w http.ResponseWriter, r *http.Request) {
+	var req fleetAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+
+	matchAll := len(req.Monitors) == 0 && len(req.Groups) == 0 && len(tags) == 0
+	ids := toSet(req.Monitors)
+	groups := toSet(req.Groups)
+
+	resp := FleetAnnotationResponse{
+		BatchID:    generateID("annotation", "b-"),
+		Kind:       req.Kind,
+		Message:    req.Message,
+		Timestamp:  req.timestamp(),
+		MonitorIDs: []string{},
+	}
+	var annotations []db.Annotation
+	for _, m := range monitors {
+		if !matchAll && !ids[m.ID] && !groups[m.GroupID] && !hasAnyTag(m, tags) {
+			continue
+		}
+		annotations = append(annotations, db.Annotation{
+			MonitorID: m.ID,
+			BatchID:   resp.BatchID,
+			Kind:      resp.Kind,
+			Message:   resp.Message,
+			Timestamp: resp.Timestamp,
+		})
+		resp.MonitorIDs = append(resp.MonitorIDs, m.ID)
+	}
+	if len(annotations) == 0 {
+		writeError(w, http.StatusNotFound, "no monitors match the targets")
+		return
+	}
+
+	if err := h.store.CreateAnnotations(annotations); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create annotations")
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func hasAnyTag(m db.Monitor, tags []string) bool {
+	for _, t := range tags {
+		if m.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOverview returns a high-level status for each group, rolled up from its monitors by
+// the overview.group_status_policy setting.
 // @Summary      Dashboard overview
 // @Tags         uptime
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object} OverviewResponse
-// @Failure      500  {string} string "Internal error"
+// @Failure      500  {object} ErrorResponse "Internal error"
 // @Router       /overview [get]
 func (h *UptimeHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 	groups, err := h.store.GetGroups()
 	if err != nil {
-		http.Error(w, "Failed to load groups", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to load groups")
 		return
 	}
 
 	monitorsMeta, err := h.store.GetMonitors()
 	if err != nil {
-		http.Error(w, "Failed to load monitors", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "Failed to load monitors")
 		return
 	}
 
@@ -313,44 +756,24 @@ func (h *UptimeHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 		groupMap[m.GroupID] = append(groupMap[m.GroupID], m)
 	}
 
+	lastKnown, warmingUp := lastKnownChecks(h.manager, h.store)
+	policy := loadGroupStatusPolicy(h.store)
+
 	var overview []GroupOverviewDTO
 
 	for _, g := range groups {
-		monitors := groupMap[g.ID]
-		status := "up" // Default to up if no monitors or all up
-
-		if h.manager.IsGroupInMaintenance(g.ID) {
-			status = "maintenance"
-		} else {
-			if len(monitors) == 0 {
-				status = "up"
-			} else {
-				anyDown := false
-				anyDegraded := false
-
-				for _, m := range monitors {
-					if !m.Active {
-						continue
-					}
-					task := h.manager.GetMonitor(m.ID)
-					if task != nil {
-						isUp, latency, hasHistory, isDegraded := task.GetLastStatus()
-						if hasHistory && !isUp {
-							anyDown = true
-							break // Critical priority
-						}
-						if hasHistory && isUp && (isDegraded || latency > task.GetLatencyThreshold()) {
-							anyDegraded = true
-						}
-					}
+		status := "maintenance"
+		if !h.manager.IsGroupInMaintenance(g.ID) {
+			var statuses []groupMonitorStatus
+			for _, m := range groupMap[g.ID] {
+				if !m.Active {
+					continue
 				}
-
-				if anyDown {
-					status = "down"
-				} else if anyDegraded {
-					status = "degraded"
+				if st, _ := h.monitorStatus(m, lastKnown); st != "" {
+					statuses = append(statuses, groupMonitorStatus{monitor: m, status: st})
 				}
 			}
+			status = policy.aggregate(statuses)
 		}
 
 		overview = append(overview, GroupOverviewDTO{
@@ -361,5 +784,27 @@ func (h *UptimeHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(OverviewResponse{Groups: overview})
+	_ = json.NewEncoder(w).Encode(OverviewResponse{Groups: overview, WarmingUp: warmingUp})
+}
+
+// monitorStatus returns a monitor's up, down or degraded status and latency from its
+// latest check, or "" before its first.
+func (h *UptimeHandler) monitorStatus(m db.Monitor, lastKnown map[string]db.CheckResult) (string, int64) {
+	task := h.manager.GetMonitor(m.ID)
+	if task == nil {
+		if c, ok := lastKnown[m.ID]; ok {
+			return checkStatus(c, m, h.manager.GetLatencyThreshold()), c.Latency
+		}
+		return "", 0
+	}
+	isUp, latency, hasHistory, isDegraded := task.GetLastStatus()
+	switch {
+	case !hasHistory:
+		return "", 0
+	case !isUp:
+		return "down", latency
+	case isDegraded || latency > task.GetLatencyThreshold():
+		return "degraded", latency
+	}
+	return "up", latency
 }
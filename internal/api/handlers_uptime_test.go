@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestGetHistory_HistoryPoints(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	manager := uptime.NewManager(store)
+	manager.SetHistorySize(200)
+	h := NewUptimeHandler(manager, store)
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Batch", Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	now := time.Now().UTC()
+	var checks []db.CheckResult
+	for i := 0; i < 150; i++ {
+		checks = append(checks, db.CheckResult{MonitorID: "m1", Status: "up", Latency: int64(i), Timestamp: now.Add(time.Duration(i-150) * time.Minute)})
+	}
+	if err := store.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+	manager.Sync()
+
+	tests := []struct {
+		query  string
+		status int
+		points int
+	}{
+		{"", http.StatusOK, 50},
+		{"?history=120", http.StatusOK, 120},
+		{"?history=200", http.StatusOK, 150},
+		{"?history=0", http.StatusOK, 0},
+		{"?history=201", http.StatusBadRequest, 0},
+		{"?history=-1", http.StatusBadRequest, 0},
+		{"?history=dense", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.GetHistory(w, httptest.NewRequest("GET", "/api/uptime"+tt.query, nil))
+		if w.Code != tt.status {
+			t.Errorf("%q: expected %d, got %d", tt.query, tt.status, w.Code)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp UptimeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		mon := resp.Groups[0].Monitors[0]
+		if len(mon.History) != tt.points {
+			t.Errorf("%q: expected %d history points, got %d", tt.query, tt.points, len(mon.History))
+		}
+		if tt.points > 0 && mon.History[len(mon.History)-1].Latency != 149 {
+			t.Errorf("%q: expected the newest checks, got latency %d last", tt.query, mon.History[len(mon.History)-1].Latency)
+		}
+		if mon.Latency != 149 {
+			t.Errorf("%q: expected the live latency of the newest check, got %d", tt.query, mon.Latency)
+		}
+	}
+}
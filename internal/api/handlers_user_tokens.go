@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// UserTokenHandler manages the signed-in user's personal API tokens. Unlike global API
+// keys, requests made with a personal token act as its owner.
+type UserTokenHandler struct {
+	store *db.Store
+}
+
+func NewUserTokenHandler(store *db.Store) *UserTokenHandler {
+	return &UserTokenHandler{store: store}
+}
+
+// tokenOwner returns the user whose tokens the request manages, or writes an error.
+// Global API keys have no user to own tokens.
+func tokenOwner(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, ok := r.Context().Value(contextKeyUserID).(int64)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, false
+	}
+	// SECURITY: API keys are not tied to a user
+	if userID == APIKeyUserID {
+		writeError(w, http.StatusForbidden, "API keys cannot manage personal tokens")
+		return 0, false
+	}
+	return userID, true
+}
+
+// ListTokens returns the current user's personal tokens (secrets are not included).
+// @Summary      List personal tokens
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{tokens=[]db.UserToken}
+// @Failure      403  {object} ErrorResponse "Requested with a global API key"
+// @Router       /auth/me/tokens [get]
+func (h *UserTokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := tokenOwner(w, r)
+	if !ok {
+		return
+	}
+	tokens, err := h.store.ListUserTokens(userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list tokens")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tokens": tokens})
+}
+
+// CreateToken generates a personal token for the current user. The raw token is
+// returned only once.
+// @Summary      Create personal token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body body object{name=string} true "Token name"
+// @Success      201  {object} object{token=string,message=string}
+// @Failure      403  {object} ErrorResponse "Requested with an API key or personal token"
+// @Failure      422  {object} ValidationErrorResponse
+// @Router       /auth/me/tokens [post]
+func (h *UserTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := tokenOwner(w, r)
+	if !ok {
+		return
+	}
+	// SECURITY: A leaked token must not be able to mint replacements for itself
+	if viaToken, _ := r.Context().Value(contextKeyPersonalToken).(bool); viaToken {
+		writeError(w, http.StatusForbidden, "personal tokens cannot create tokens")
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	errs := validationErrors{}
+	validateName(errs, req.Name)
+	if errs.write(w) {
+		return
+	}
+
+	rawToken, err := h.store.CreateUserToken(userID, req.Name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to create token")
+		return
+	}
+	log.Printf("AUDIT: [AUTH] User %d created personal token '%s' from IP %s", userID, sanitizeLog(req.Name), sanitizeLog(extractIP(r))) // #nosec G706 -- sanitized
+
+	// Return the raw token ONLY ONCE
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"token":   rawToken,
+		"message": "Token created. Save it now, it will not be shown again.",
+	})
+}
+
+// DeleteToken revokes one of the current user's personal tokens.
+// @Summary      Delete personal token
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path int true "Token ID"
+// @Success      200  {object} object{message=string}
+// @Failure      400  {object} ErrorResponse "Invalid ID"
+// @Failure      404  {object} ErrorResponse "Token not found"
+// @Router       /auth/me/tokens/{id} [delete]
+func (h *UserTokenHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := tokenOwner(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.store.DeleteUserToken(userID, id); err != nil {
+		if errors.Is(err, db.ErrUserTokenNotFound) {
+			writeError(w, http.StatusNotFound, "token not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete token")
+		return
+	}
+	log.Printf("AUDIT: [AUTH] User %d revoked personal token %d from IP %s", userID, id, sanitizeLog(extractIP(r))) // #nosec G706 -- sanitized
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "deleted"})
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestUserTokens_ActAsOwner(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	seedAuthUser(t, s, "alice", "alice-session")
+
+	do := func(method, path string, body any, auth func(*http.Request)) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		auth(req)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	session := func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "auth_token", Value: "alice-session"}) }
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	rr := do("POST", "/api/auth/me/tokens", map[string]string{"name": "CI"}, session)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Token string `json:"token"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&created)
+
+	// The token resolves to alice, so user endpoints work with it
+	rr = do("GET", "/api/auth/me", nil, bearer(created.Token))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /auth/me with a personal token, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var me struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&me)
+	if me.User.Username != "alice" {
+		t.Errorf("expected alice, got %q", me.User.Username)
+	}
+
+	// A token cannot mint more tokens, and a global API key has no user to own them
+	if rr := do("POST", "/api/auth/me/tokens", map[string]string{"name": "Again"}, bearer(created.Token)); rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 creating a token with a token, got %d", rr.Code)
+	}
+	key, _ := s.CreateAPIKey("Service", nil)
+	if rr := do("GET", "/api/auth/me/tokens", nil, bearer(key)); rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 listing tokens with an API key, got %d", rr.Code)
+	}
+	if rr := do("POST", "/api/auth/me/tokens", map[string]string{"name": ""}, session); rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a missing name, got %d", rr.Code)
+	}
+
+	// Revoking the token locks it out
+	rr = do("GET", "/api/auth/me/tokens", nil, bearer(created.Token))
+	var list struct {
+		Tokens []struct {
+			ID int64 `json:"id"`
+		} `json:"tokens"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&list)
+	if len(list.Tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(list.Tokens))
+	}
+	path := "/api/auth/me/tokens/" + strconv.FormatInt(list.Tokens[0].ID, 10)
+	if rr := do("DELETE", path, nil, session); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 deleting the token, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do("DELETE", path, nil, session); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 deleting it again, got %d", rr.Code)
+	}
+	if rr := do("GET", "/api/auth/me", nil, bearer(created.Token)); rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a revoked token, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// wallboardTokenSettingKey holds the hash of the read-only wallboard token. It is not in
+// the settings schema, so it can't be read or written through /settings.
+const wallboardTokenSettingKey = "wallboard.token_hash"
+
+// Wallboard snapshot bounds
+const (
+	wallboardTopLatencies   = 10
+	wallboardRefreshSeconds = 15 // Suggested polling interval for kiosk displays
+)
+
+// WallboardResponse is a compact snapshot of the whole fleet for a TV or kiosk display.
+type WallboardResponse struct {
+	GeneratedAt    time.Time          `json:"generatedAt"`
+	RefreshSeconds int                `json:"refreshSeconds"` // Suggested polling interval
+	WarmingUp      bool               `json:"warmingUp,omitempty"`
+	Counts         WallboardCounts    `json:"counts"` // Monitors by status
+	Groups         []WallboardGroup   `json:"groups"`
+	Outages        []WallboardOutage  `json:"outages"`      // Active, longest first
+	TopLatencies   []WallboardLatency `json:"topLatencies"` // Slowest monitors by latest check
+}
+
+// WallboardCounts counts monitors by status.
+type WallboardCounts struct {
+	Up       int `json:"up"`
+	Degraded int `json:"degraded"`
+	Down     int `json:"down"`
+	Pending  int `json:"pending"` // Not checked yet
+	Paused   int `json:"paused"`
+}
+
+// WallboardGroup is a group with its rolled up status and its monitors.
+type WallboardGroup struct {
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	Status   string             `json:"status"` // up, down, degraded, maintenance
+	Monitors []WallboardMonitor `json:"monitors"`
+}
+
+// WallboardMonitor is a monitor's latest status.
+type WallboardMonitor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"` // up, down, degraded, pending, paused
+	LatencyMs int64  `json:"latencyMs,omitempty"`
+}
+
+// WallboardOutage is an active outage with its monitor and group names.
+type WallboardOutage struct {
+	MonitorID       string    `json:"monitorId"`
+	MonitorName     string    `json:"monitorName"`
+	GroupName       string    `json:"groupName"`
+	Type            string    `json:"type"`
+	Summary         string    `json:"summary"`
+	Since           time.Time `json:"since"`
+	DurationSeconds int64     `json:"durationSeconds"`
+}
+
+// WallboardLatency is a monitor's latest latency.
+type WallboardLatency struct {
+	MonitorID string `json:"monitorId"`
+	Name      string `json:"name"`
+	GroupName string `json:"groupName"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// GetWallboard returns a compact, denormalized snapshot of groups, monitor statuses,
+// active outages and the slowest monitors, for an auto-refreshing TV or kiosk display.
+// Besides the usual credentials, it accepts the read-only wallboard token as the token
+// query parameter or a Bearer token.
+// @Summary      Wallboard snapshot
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Param        token query string false "Read-only wallboard token"
+// @Success      200  {object} WallboardResponse
+// @Failure      401  {object} ErrorResponse
+// @Failure      500  {object} ErrorResponse
+// @Router       /wallboard [get]
+func (h *UptimeHandler) GetWallboard(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.store.GetGroups()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load groups")
+		return
+	}
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load monitors")
+		return
+	}
+	outages, err := h.store.GetActiveOutages()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load outages")
+		return
+	}
+
+	now := time.Now()
+	lastKnown, warmingUp := lastKnownChecks(h.manager, h.store)
+	policy := loadGroupStatusPolicy(h.store)
+	resp := WallboardResponse{
+		GeneratedAt:    now.UTC(),
+		RefreshSeconds: wallboardRefreshSeconds,
+		WarmingUp:      warmingUp,
+		Groups:         make([]WallboardGroup, 0, len(groups)),
+		Outages:        make([]WallboardOutage, 0, len(outages)),
+		TopLatencies:   []WallboardLatency{},
+	}
+
+	byGroup := make(map[string][]db.Monitor)
+	for _, m := range monitors {
+		byGroup[m.GroupID] = append(byGroup[m.GroupID], m)
+	}
+	for _, g := range groups {
+		group := WallboardGroup{ID: g.ID, Name: g.Name, Monitors: []WallboardMonitor{}}
+		var statuses []groupMonitorStatus
+		for _, m := range byGroup[g.ID] {
+			mon := WallboardMonitor{ID: m.ID, Name: m.Name, Status: "paused"}
+			if m.Active {
+				status, latency := h.monitorStatus(m, lastKnown)
+				mon.Status, mon.LatencyMs = status, latency
+				if status == "" {
+					mon.Status = "pending"
+				} else {
+					statuses = append(statuses, groupMonitorStatus{monitor: m, status: status})
+				}
+				// A failed check's latency says nothing about speed, so it isn't ranked
+				if status == "up" || status == "degraded" {
+					resp.TopLatencies = append(resp.TopLatencies, WallboardLatency{MonitorID: m.ID, Name: m.Name, GroupName: g.Name, LatencyMs: latency})
+				}
+			}
+			resp.Counts.add(mon.Status)
+			group.Monitors = append(group.Monitors, mon)
+		}
+		group.Status = "maintenance"
+		if !h.manager.IsGroupInMaintenance(g.ID) {
+			group.Status = policy.aggregate(statuses)
+		}
+		resp.Groups = append(resp.Groups, group)
+	}
+
+	sort.SliceStable(resp.TopLatencies, func(i, j int) bool { return resp.TopLatencies[i].LatencyMs > resp.TopLatencies[j].LatencyMs })
+	if len(resp.TopLatencies) > wallboardTopLatencies {
+		resp.TopLatencies = resp.TopLatencies[:wallboardTopLatencies]
+	}
+
+	for _, o := range outages {
+		resp.Outages = append(resp.Outages, WallboardOutage{
+			MonitorID:       o.MonitorID,
+			MonitorName:     o.MonitorName,
+			GroupName:       o.GroupName,
+			Type:            o.Type,
+			Summary:         o.Summary,
+			Since:           o.StartTime,
+			DurationSeconds: int64(now.Sub(o.StartTime).Seconds()),
+		})
+	}
+	sort.SliceStable(resp.Outages, func(i, j int) bool { return resp.Outages[i].Since.Before(resp.Outages[j].Since) })
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (c *WallboardCounts) add(status string) {
+	switch status {
+	case "up":
+		c.Up++
+	case "degraded":
+		c.Degraded++
+	case "down":
+		c.Down++
+	case "pending":
+		c.Pending++
+	case "paused":
+		c.Paused++
+	}
+}
+
+// CreateWallboardToken generates, or rotates, the read-only token that lets a kiosk
+// display fetch GET /api/wallboard without signing in. The token is returned only once.
+// @Summary      Create wallboard token
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{token=string,path=string}
+// @Failure      500  {object} ErrorResponse
+// @Router       /wallboard/token [post]
+func (h *UptimeHandler) CreateWallboardToken(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+	token := "wb_" + hex.EncodeToString(b)
+	if err := h.store.SetSetting(wallboardTokenSettingKey, hashIngestToken(token)); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to save token")
+		return
+	}
+	log.Printf("AUDIT: [WALLBOARD] %s created a wallboard token", sanitizeLog(requestActor(r, h.store))) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{
+		"token":   token,
+		"path":    "/api/wallboard?token=" + token,
+		"message": "Token created. Save it now, it will not be shown again.",
+	})
+}
+
+// DeleteWallboardToken revokes the wallboard token.
+// @Summary      Delete wallboard token
+// @Tags         uptime
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} object{message=string}
+// @Failure      404  {object} ErrorResponse
+// @Router       /wallboard/token [delete]
+func (h *UptimeHandler) DeleteWallboardToken(w http.ResponseWriter, r *http.Request) {
+	if hash, _ := h.store.GetSetting(wallboardTokenSettingKey); hash == "" {
+		writeError(w, http.StatusNotFound, "no wallboard token")
+		return
+	}
+	if err := h.store.SetSetting(wallboardTokenSettingKey, ""); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to revoke token")
+		return
+	}
+	log.Printf("AUDIT: [WALLBOARD] %s revoked the wallboard token", sanitizeLog(requestActor(r, h.store))) // #nosec G706 -- sanitized
+	writeJSON(w, http.StatusOK, map[string]string{"message": "wallboard token revoked"})
+}
+
+// WallboardAuth lets requests carrying the wallboard token through, as the token query
+// parameter or a "wb_" Bearer token, and hands any other request to the regular auth
+// middleware.
+func WallboardAuth(store *db.Store, authH *AuthHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := authH.AuthMiddleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get("token")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && strings.HasPrefix(bearer, "wb_") {
+				token = bearer
+			}
+			if token == "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+			hash, _ := store.GetSetting(wallboardTokenSettingKey)
+			if hash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(hashIngestToken(token))) != 1 {
+				writeError(w, http.StatusUnauthorized, "invalid wallboard token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
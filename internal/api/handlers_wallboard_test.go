@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestWallboard(t *testing.T) {
+	_, _, _, router, s := setupTest(t)
+	key, _ := s.CreateAPIKey("Admin", nil)
+	_ = s.CreateGroup(db.Group{ID: "g-shop", Name: "Shop"})
+	for _, m := range []db.Monitor{
+		{ID: "m-home", GroupID: "g-default", Name: "Home", URL: "http://home", Interval: 60, Active: true},
+		{ID: "m-api", GroupID: "g-default", Name: "API", URL: "http://api", Interval: 60, Active: true},
+		{ID: "m-cart", GroupID: "g-shop", Name: "Cart", URL: "http://cart", Interval: 60, Active: true},
+		{ID: "m-new", GroupID: "g-shop", Name: "New", URL: "http://new", Interval: 60, Active: true},
+		{ID: "m-old", GroupID: "g-shop", Name: "Old", URL: "http://old", Interval: 60},
+	} {
+		_ = s.CreateMonitor(m)
+	}
+	now := time.Now()
+	_ = s.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-home", Status: "up", Latency: 120, Timestamp: now},
+		{MonitorID: "m-api", Status: "up", Latency: 5000, Timestamp: now},
+		{MonitorID: "m-cart", Status: "down", Latency: 9000, Timestamp: now},
+	})
+	_ = s.CreateOutageAt("m-cart", "down", "Connection refused", "", nil, now.Add(-10*time.Minute))
+
+	do := func(method, path, bearer string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do("GET", "/api/wallboard", ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", rr.Code)
+	}
+	rr := do("GET", "/api/wallboard", key)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with an API key, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var got WallboardResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &got)
+	if got.Counts != (WallboardCounts{Up: 1, Degraded: 1, Down: 1, Pending: 1, Paused: 1}) {
+		t.Errorf("Unexpected counts %+v", got.Counts)
+	}
+	statuses := map[string]string{}
+	for _, g := range got.Groups {
+		statuses[g.ID] = g.Status
+		for _, m := range g.Monitors {
+			statuses[m.ID] = m.Status
+		}
+	}
+	if statuses["g-default"] != "degraded" || statuses["g-shop"] != "down" || statuses["m-new"] != "pending" || statuses["m-old"] != "paused" {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+	if len(got.Outages) != 1 || got.Outages[0].MonitorName != "Cart" || got.Outages[0].GroupName != "Shop" || got.Outages[0].DurationSeconds < 600 {
+		t.Errorf("Unexpected outages %+v", got.Outages)
+	}
+	if len(got.TopLatencies) != 2 || got.TopLatencies[0].MonitorID != "m-api" || got.TopLatencies[1].MonitorID != "m-home" {
+		t.Errorf("Expected the answered monitors, slowest first, got %+v", got.TopLatencies)
+	}
+
+	// Read-only token
+	if rr := do("DELETE", "/api/wallboard/token", key); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without a token, got %d", rr.Code)
+	}
+	rr = do("POST", "/api/wallboard/token", key)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Token string `json:"token"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if rr := do("GET", "/api/wallboard?token="+created.Token, ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected the token in the query to be accepted, got %d", rr.Code)
+	}
+	if rr := do("GET", "/api/wallboard", created.Token); rr.Code != http.StatusOK {
+		t.Errorf("Expected the token as a Bearer token to be accepted, got %d", rr.Code)
+	}
+	if rr := do("GET", "/api/wallboard?token=wb_wrong", ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong token, got %d", rr.Code)
+	}
+	if rr := do("GET", "/api/overview", created.Token); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the token to only open the wallboard, got %d", rr.Code)
+	}
+
+	if rr := do("DELETE", "/api/wallboard/token", key); rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rr.Code)
+	}
+	if rr := do("GET", "/api/wallboard?token="+created.Token, ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a revoked token to be rejected, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+	"github.com/projecthelena/warden/internal/websocket"
+	"golang.org/x/time/rate"
+)
+
+const (
+	wsPingInterval   = 30 * time.Second
+	wsReadTimeout    = 2 * wsPingInterval // a pong or message must arrive within this
+	wsWriteTimeout   = 10 * time.Second
+	wsMaxMessageSize = 64 << 10
+	// wsMaxClockSkew bounds how far in the future an agent's result timestamp may be.
+	wsMaxClockSkew = 5 * time.Minute
+	// maxRegionLen bounds the region an agent reports its results from.
+	maxRegionLen = 64
+)
+
+// WebSocket message types. Agents send "result" and "config"; the server sends
+// "config", "ack" and "error".
+const (
+	wsTypeResult = "result"
+	wsTypeConfig = "config"
+	wsTypeAck    = "ack"
+	wsTypeError  = "error"
+)
+
+// wsInbound is a message from an agent.
+type wsInbound struct {
+	Type string `json:"type"`
+	// ID is an optional client-chosen correlation ID echoed in the ack or error.
+	ID        string     `json:"id,omitempty"`
+	MonitorID string     `json:"monitorId,omitempty"`
+	Status    string     `json:"status,omitempty"` // up | down
+	Summary   string     `json:"summary,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Region    string     `json:"region,omitempty"` // Where the agent checks from
+}
+
+// wsOutbound is an "ack" or "error" reply to an agent.
+type wsOutbound struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// wsConfigMessage pushes the external monitors an agent should report on.
+type wsConfigMessage struct {
+	Type     string            `json:"type"`
+	Monitors []wsMonitorConfig `json:"monitors"`
+}
+
+// wsMonitorConfig is the part of an external monitor's configuration an agent needs
+// to check it and report results.
+type wsMonitorConfig struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	GroupID  string `json:"groupId"`
+	URL      string `json:"url"`
+	Interval int    `json:"interval"`
+}
+
+type WSHandler struct {
+	store   *db.Store
+	manager *uptime.Manager
+}
+
+func NewWSHandler(store *db.Store, manager *uptime.Manager) *WSHandler {
+	return &WSHandler{store: store, manager: manager}
+}
+
+// Serve upgrades to a WebSocket over which remote agents stream check results for
+// external monitors and receive their configuration. The server sends a "config"
+// message on connect and again whenever the set of external monitors changes.
+// @Summary      Agent WebSocket
+// @Description  Send {"type":"result","monitorId":"...","status":"up|down","summary":"...","timestamp":"RFC 3339","region":"..."}; each is answered with "ack" or "error". Send {"type":"config"} to request the configuration again.
+// @Tags         ingest
+// @Security     BearerAuth
+// @Success      101  "Switching Protocols"
+// @Failure      403  {object} ErrorResponse "Cross-origin upgrade"
+// @Failure      426  {object} ErrorResponse "Not a WebSocket handshake"
+// @Router       /ws [get]
+func (h *WSHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	// SECURITY: Browsers attach the session cookie to cross-site WebSocket handshakes, so
+	// only same-origin pages may connect. Agents send no Origin header.
+	if !sameOriginRequest(r) {
+		writeError(w, http.StatusForbidden, "cross-origin websocket connections are not allowed")
+		return
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	conn.SetReadLimit(wsMaxMessageSize)
+	log.Printf("WebSocket: agent connected from %s", sanitizeLog(extractIP(r))) // #nosec G706 -- sanitized
+
+	s := &wsSession{
+		out:        make(chan wsOutbound, 16),
+		resend:     make(chan struct{}, 1),
+		readDone:   make(chan struct{}),
+		writerDone: make(chan struct{}),
+	}
+	go h.writeLoop(conn, s)
+
+	err = h.readLoop(conn, s)
+	close(s.readDone)
+	var closeErr *websocket.CloseError
+	if err != nil && !errors.As(err, &closeErr) && !errors.Is(err, net.ErrClosed) {
+		log.Printf("WebSocket: connection from %s ended: %v", sanitizeLog(extractIP(r)), err) // #nosec G706 -- sanitized
+	}
+	_ = conn.Close(websocket.CloseNormal, "")
+}
+
+// wsSession connects a connection's read loop to its write loop.
+type wsSession struct {
+	out        chan wsOutbound
+	resend     chan struct{} // buffered; a pending config resend absorbs further requests
+	readDone   chan struct{}
+	writerDone chan struct{}
+}
+
+// reply queues a message for the write loop, giving up once the write loop has exited.
+func (s *wsSession) reply(msg wsOutbound) {
+	select {
+	case s.out <- msg:
+	case <-s.writerDone:
+	}
+}
+
+// readLoop handles agent messages until the connection fails or closes.
+func (h *WSHandler) readLoop(conn *websocket.Conn, s *wsSession) error {
+	// Results feed the same pipeline as scheduled checks; cap a misbehaving agent
+	limiter := rate.NewLimiter(rate.Limit(50), 100)
+
+	_ = conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	conn.SetPongHandler(func() { _ = conn.SetReadDeadline(time.Now().Add(wsReadTimeout)) })
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+
+		var msg wsInbound
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.reply(wsOutbound{Type: wsTypeError, Error: "invalid JSON message"})
+			continue
+		}
+
+		switch msg.Type {
+		case wsTypeConfig:
+			select {
+			case s.resend <- struct{}{}:
+			default:
+			}
+		case wsTypeResult:
+			if !limiter.Allow() {
+				s.reply(wsOutbound{Type: wsTypeError, ID: msg.ID, Error: "rate limit exceeded"})
+				continue
+			}
+			if err := h.ingest(msg); err != nil {
+				s.reply(wsOutbound{Type: wsTypeError, ID: msg.ID, Error: err.Error()})
+				continue
+			}
+			s.reply(wsOutbound{Type: wsTypeAck, ID: msg.ID})
+		default:
+			s.reply(wsOutbound{Type: wsTypeError, ID: msg.ID, Error: "unknown message type"})
+		}
+	}
+}
+
+// ingest validates an agent's result and feeds it to the monitor's result pipeline.
+func (h *WSHandler) ingest(msg wsInbound) error {
+	if msg.MonitorID == "" {
+		return errors.New("monitorId is required")
+	}
+	if msg.Status != "up" && msg.Status != "down" {
+		return errors.New(`status must be "up" or "down"`)
+	}
+	ts := time.Now()
+	if msg.Timestamp != nil {
+		if msg.Timestamp.After(ts.Add(wsMaxClockSkew)) {
+			return errors.New("timestamp is in the future")
+		}
+		ts = *msg.Timestamp
+	}
+	if len(msg.Region) > maxRegionLen {
+		return errors.New("region must be at most 64 characters")
+	}
+	summary := msg.Summary
+	if len(summary) > 1024 {
+		summary = summary[:1024]
+	}
+	return h.manager.IngestExternalResults([]uptime.ExternalResult{{
+		MonitorID: msg.MonitorID,
+		IsUp:      msg.Status == "up",
+		Summary:   summary,
+		Timestamp: ts,
+		Region:    msg.Region,
+	}}, false)
+}
+
+// writeLoop owns all writes besides the read loop's automatic pongs: queued replies,
+// config pushes after each manager Sync that changed the external monitors, and pings.
+func (h *WSHandler) writeLoop(conn *websocket.Conn, s *wsSession) {
+	defer close(s.writerDone)
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	var sent []wsMonitorConfig
+	pushConfig := func(force bool) bool {
+		monitors, err := h.externalMonitors()
+		if err != nil {
+			log.Printf("WebSocket: failed to load monitor config: %v", err)
+			return true
+		}
+		if !force && sent != nil && reflect.DeepEqual(monitors, sent) {
+			return true
+		}
+		sent = monitors
+		return h.write(conn, wsConfigMessage{Type: wsTypeConfig, Monitors: monitors})
+	}
+
+	if !pushConfig(true) {
+		_ = conn.Close(websocket.CloseGoingAway, "write failed")
+		return
+	}
+	synced := h.manager.SyncNotify()
+	for {
+		ok := true
+		select {
+		case <-s.readDone:
+			return
+		case msg := <-s.out:
+			ok = h.write(conn, msg)
+		case <-s.resend:
+			ok = pushConfig(true)
+		case <-synced:
+			synced = h.manager.SyncNotify()
+			ok = pushConfig(false)
+		case <-ping.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			ok = conn.WriteMessage(websocket.PingMessage, nil) == nil
+		}
+		if !ok {
+			// Unblock the reader; it returns once the connection is closed
+			_ = conn.Close(websocket.CloseGoingAway, "write failed")
+			return
+		}
+	}
+}
+
+func (h *WSHandler) write(conn *websocket.Conn, msg any) bool {
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return conn.WriteJSON(msg) == nil
+}
+
+// externalMonitors lists the active external monitors, the ones agents report on.
+func (h *WSHandler) externalMonitors() ([]wsMonitorConfig, error) {
+	monitors, err := h.store.GetMonitors()
+	if err != nil {
+		return nil, err
+	}
+	configs := []wsMonitorConfig{}
+	for _, m := range monitors {
+		if m.Type != db.MonitorTypeExternal || !m.Active {
+			continue
+		}
+		configs = append(configs, wsMonitorConfig{ID: m.ID, Name: m.Name, GroupID: m.GroupID, URL: m.URL, Interval: m.Interval})
+	}
+	return configs, nil
+}
+
+// sameOriginRequest reports whether a request has no Origin header (non-browser clients)
+// or one matching the host it was sent to.
+func sameOriginRequest(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// wsTestClient is a bare-bones WebSocket client: masked single-frame text messages out,
+// unfragmented frames in.
+type wsTestClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialWS(t *testing.T, srv *httptest.Server) *wsTestClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req := "GET /api/ws HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101, got %d", resp.StatusCode)
+	}
+	return &wsTestClient{conn: conn, br: br}
+}
+
+func (c *wsTestClient) sendJSON(t *testing.T, v any) {
+	t.Helper()
+	payload, _ := json.Marshal(v)
+	frame := []byte{0x81, 0x80 | byte(len(payload))} // payloads here stay under 126 bytes
+	frame = append(frame, 0, 0, 0, 0)                // zero mask key
+	frame = append(frame, payload...)
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readJSON decodes the next text message, skipping pings.
+func (c *wsTestClient) readJSON(t *testing.T) map[string]any {
+	t.Helper()
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(c.br, head[:]); err != nil {
+			t.Fatal(err)
+		}
+		n := uint64(head[1] & 0x7f)
+		if n == 126 {
+			var ext [2]byte
+			_, _ = io.ReadFull(c.br, ext[:])
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			t.Fatal(err)
+		}
+		if head[0]&0x0f != 1 {
+			continue
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", payload, err)
+		}
+		return msg
+	}
+}
+
+func setupWSTest(t *testing.T) (*db.Store, *uptime.Manager, *httptest.Server) {
+	store, manager, _ := setupIngestTest(t)
+	r := chi.NewRouter()
+	r.Get("/api/ws", NewWSHandler(store, manager).Serve)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return store, manager, srv
+}
+
+func monitorIDs(msg map[string]any) []string {
+	var ids []string
+	monitors, _ := msg["monitors"].([]any)
+	for _, m := range monitors {
+		ids = append(ids, m.(map[string]any)["id"].(string))
+	}
+	return ids
+}
+
+func TestWSResultStreaming(t *testing.T) {
+	_, _, srv := setupWSTest(t)
+	c := dialWS(t, srv)
+
+	// Only the external monitor is pushed
+	cfg := c.readJSON(t)
+	if cfg["type"] != "config" {
+		t.Fatalf("Expected config message first, got %v", cfg)
+	}
+	if ids := monitorIDs(cfg); len(ids) != 1 || ids[0] != "ext1" {
+		t.Fatalf("Expected config for ext1 only, got %v", ids)
+	}
+
+	c.sendJSON(t, map[string]string{"type": "result", "id": "r1", "monitorId": "ext1", "status": "down", "summary": "agent says no"})
+	if msg := c.readJSON(t); msg["type"] != "ack" || msg["id"] != "r1" {
+		t.Fatalf("Expected ack for r1, got %v", msg)
+	}
+
+	c.sendJSON(t, map[string]string{"type": "result", "id": "r2", "monitorId": "http1", "status": "up"})
+	if msg := c.readJSON(t); msg["type"] != "error" || msg["id"] != "r2" {
+		t.Fatalf("Expected error for non-external monitor, got %v", msg)
+	}
+
+	c.sendJSON(t, map[string]string{"type": "result", "id": "r3", "monitorId": "ext1", "status": "maybe"})
+	if msg := c.readJSON(t); msg["type"] != "error" || msg["id"] != "r3" {
+		t.Fatalf("Expected error for invalid status, got %v", msg)
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	c.sendJSON(t, map[string]string{"type": "result", "id": "r4", "monitorId": "ext1", "status": "up", "timestamp": future})
+	if msg := c.readJSON(t); msg["type"] != "error" || msg["id"] != "r4" {
+		t.Fatalf("Expected error for future timestamp, got %v", msg)
+	}
+}
+
+func TestWSConfigPush(t *testing.T) {
+	store, manager, srv := setupWSTest(t)
+	c := dialWS(t, srv)
+	c.readJSON(t)
+
+	// An explicit request is always answered
+	c.sendJSON(t, map[string]string{"type": "config"})
+	if msg := c.readJSON(t); msg["type"] != "config" || len(monitorIDs(msg)) != 1 {
+		t.Fatalf("Expected config resend, got %v", msg)
+	}
+
+	// A new external monitor is pushed after the next Sync
+	if err := store.CreateMonitor(db.Monitor{ID: "ext2", GroupID: "g-default", Name: "Ext 2", Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	manager.Sync()
+	if msg := c.readJSON(t); msg["type"] != "config" || len(monitorIDs(msg)) != 2 {
+		t.Fatalf("Expected pushed config with 2 monitors, got %v", msg)
+	}
+}
+
+func TestWSRejectsCrossOrigin(t *testing.T) {
+	_, _, srv := setupWSTest(t)
+
+	req, _ := http.NewRequest("GET", srv.URL+"/api/ws", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", resp.StatusCode)
+	}
+}
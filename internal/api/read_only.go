@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/projecthelena/warden/internal/config"
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Settings that switch the API to read-only at runtime, e.g. for database maintenance.
+// READ_ONLY=true does the same for the whole life of the process, for reporting replicas.
+const (
+	readOnlySettingKey       = "read_only.enabled"
+	readOnlyNoticeSettingKey = "read_only.notice"
+)
+
+// defaultReadOnlyNotice is returned with rejected writes when no notice is set.
+const defaultReadOnlyNotice = "Warden is in read-only mode. Changes are disabled until it is switched back."
+
+// maxReadOnlySettingsBody bounds the settings body read to tell whether it only
+// switches read-only mode.
+const maxReadOnlySettingsBody = 64 << 10
+
+// readOnlyState reports whether the API is read-only and the notice to show.
+func readOnlyState(store *db.Store, cfg *config.Config) (enabled bool, notice string) {
+	enabled = cfg.ReadOnly
+	if !enabled {
+		val, _ := store.GetSetting(readOnlySettingKey)
+		enabled = val == "true"
+	}
+	if !enabled {
+		return false, ""
+	}
+	notice, _ = store.GetSetting(readOnlyNoticeSettingKey)
+	if notice == "" {
+		notice = defaultReadOnlyNotice
+	}
+	return true, notice
+}
+
+// ReadOnlyMiddleware rejects writes with 503 while the API is read-only. Reads, signing
+// in and out, and, unless READ_ONLY forces the mode, settings changes that only touch
+// the read-only settings themselves still go through, so the mode can be switched off.
+func ReadOnlyMiddleware(store *db.Store, cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.URL.Path == "/api/auth/login" || r.URL.Path == "/api/auth/logout" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			enabled, notice := readOnlyState(store, cfg)
+			if !enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !cfg.ReadOnly && r.Method == http.MethodPatch && r.URL.Path == "/api/settings" && onlyReadOnlySettings(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeErrorCode(w, http.StatusServiceUnavailable, ErrCodeReadOnly, notice)
+		})
+	}
+}
+
+// onlyReadOnlySettings reports whether a settings body changes nothing but the
+// read-only settings. The body is put back for the handler.
+func onlyReadOnlySettings(r *http.Request) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReadOnlySettingsBody+1))
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) > maxReadOnlySettingsBody {
+		return false
+	}
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(body, &settings); err != nil || len(settings) == 0 {
+		return false
+	}
+	for key := range settings {
+		if key != readOnlySettingKey && key != readOnlyNoticeSettingKey {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/config"
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestReadOnlyMode_Setting(t *testing.T) {
+	_, _, _, router, store := setupTest(t)
+	seedAuthUser(t, store, "admin", "admin-session")
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: "admin-session"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("POST", "/api/groups", `{"name":"Before"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 before read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := do("PATCH", "/api/settings", `{"read_only.enabled":"true","read_only.notice":"Back at 14:00 UTC"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 enabling read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := do("POST", "/api/groups", `{"name":"During"}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 in read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+	var errResp ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &errResp)
+	if errResp.Code != ErrCodeReadOnly || errResp.Error != "Back at 14:00 UTC" {
+		t.Errorf("Expected the read-only notice, got %+v", errResp)
+	}
+
+	// Other settings are writes like any other
+	if w := do("PATCH", "/api/settings", `{"read_only.enabled":"false","latency_threshold":"500"}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for a settings change beyond read-only mode, got %d", w.Code)
+	}
+
+	// Reads still work and report the mode
+	w = do("GET", "/api/auth/me", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for /auth/me, got %d", w.Code)
+	}
+	var me struct {
+		ReadOnly *struct {
+			Notice string `json:"notice"`
+		} `json:"readOnly"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &me)
+	if me.ReadOnly == nil || me.ReadOnly.Notice != "Back at 14:00 UTC" {
+		t.Errorf("Expected /auth/me to report read-only mode, got %s", w.Body.String())
+	}
+
+	if w := do("PATCH", "/api/settings", `{"read_only.enabled":"false"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 disabling read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", "/api/groups", `{"name":"After"}`); w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 after read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReadOnlyMode_Config(t *testing.T) {
+	store, _ := db.NewStore(db.NewTestConfig())
+	cfg := config.Default()
+	cfg.ReadOnly = true
+	router := NewRouter(uptime.NewManager(store), store, &cfg)
+	seedAuthUser(t, store, "admin", "admin-session")
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: "admin-session"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// READ_ONLY cannot be switched off through the API
+	if code := do("PATCH", "/api/settings", `{"read_only.enabled":"false"}`); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for settings under READ_ONLY, got %d", code)
+	}
+	if code := do("DELETE", "/api/monitors/m1", ""); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for a delete under READ_ONLY, got %d", code)
+	}
+	if code := do("GET", "/api/overview", ""); code != http.StatusOK {
+		t.Errorf("Expected 200 for a read under READ_ONLY, got %d", code)
+	}
+	if code := do("POST", "/api/auth/login", `{"username":"admin","password":"password123"}`); code != http.StatusOK {
+		t.Errorf("Expected login to work under READ_ONLY, got %d", code)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = middleware.GetReqID(r.Context())
+		writeError(w, http.StatusBadRequest, "bad input")
+	}))
+
+	tests := []struct {
+		name    string
+		inbound string
+		keep    bool
+	}{
+		{"generated", "", false},
+		{"inbound honored", "lb-7f3a:42", true},
+		{"unsafe inbound replaced", "abc def\"}", false},
+		{"overlong inbound replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/x", nil)
+			if tt.inbound != "" {
+				req.Header.Set(RequestIDHeader, tt.inbound)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if id == "" || id != seen {
+				t.Fatalf("Expected matching header and context IDs, got %q and %q", id, seen)
+			}
+			if (id == tt.inbound) != tt.keep {
+				t.Errorf("inbound %q: got ID %q", tt.inbound, id)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if body["requestId"] != id {
+				t.Errorf("Expected requestId %q in error body, got %v", id, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// latencyBucketsMs are the upper bounds of the request latency histogram buckets.
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// unmatchedRoute groups requests that matched no route, so arbitrary paths can't grow
+// the metrics without bound.
+const unmatchedRoute = "unmatched"
+
+type endpointKey struct {
+	method string
+	route  string
+}
+
+type endpointStats struct {
+	count   int64
+	errors  int64 // 5xx responses
+	sumMs   float64
+	maxMs   float64
+	buckets []int64 // per latencyBucketsMs, plus one for slower requests
+}
+
+// RequestMetrics records a latency histogram per route.
+type RequestMetrics struct {
+	mu        sync.Mutex
+	endpoints map[endpointKey]*endpointStats
+	since     time.Time
+}
+
+// NewRequestMetrics creates an empty request metrics registry.
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{
+		endpoints: make(map[endpointKey]*endpointStats),
+		since:     time.Now(),
+	}
+}
+
+// Middleware times each request and records it under its route pattern
+// (e.g. /api/monitors/{id}) rather than the raw path.
+func (m *RequestMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.observe(endpointKey{method: r.Method, route: route}, status, time.Since(start))
+	})
+}
+
+func (m *RequestMetrics) observe(key endpointKey, status int, elapsed time.Duration) {
+	ms := float64(elapsed.Microseconds()) / 1000
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.endpoints[key]
+	if !ok {
+		s = &endpointStats{buckets: make([]int64, len(latencyBucketsMs)+1)}
+		m.endpoints[key] = s
+	}
+	s.count++
+	if status >= 500 {
+		s.errors++
+	}
+	s.sumMs += ms
+	if ms > s.maxMs {
+		s.maxMs = ms
+	}
+	i := sort.SearchFloat64s(latencyBucketsMs, ms)
+	s.buckets[i]++
+}
+
+// LatencyBucket is one cumulative histogram bucket: requests that took at most LeMs.
+// Requests slower than the last bucket are only included in EndpointMetrics.Count.
+type LatencyBucket struct {
+	LeMs  float64 `json:"leMs"`
+	Count int64   `json:"count"`
+}
+
+// EndpointMetrics summarizes the requests served by one route.
+type EndpointMetrics struct {
+	Method  string          `json:"method"`
+	Route   string          `json:"route"`
+	Count   int64           `json:"count"`
+	Errors  int64           `json:"errors"`
+	AvgMs   float64         `json:"avgMs"`
+	MaxMs   float64         `json:"maxMs"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// RequestMetricsSnapshot is the request metrics collected since the server started.
+type RequestMetricsSnapshot struct {
+	Since     time.Time         `json:"since"`
+	Endpoints []EndpointMetrics `json:"endpoints"`
+}
+
+// Snapshot returns the current metrics, busiest routes first.
+func (m *RequestMetrics) Snapshot() RequestMetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoints := make([]EndpointMetrics, 0, len(m.endpoints))
+	for key, s := range m.endpoints {
+		e := EndpointMetrics{
+			Method:  key.method,
+			Route:   key.route,
+			Count:   s.count,
+			Errors:  s.errors,
+			AvgMs:   s.sumMs / float64(s.count),
+			MaxMs:   s.maxMs,
+			Buckets: make([]LatencyBucket, len(latencyBucketsMs)),
+		}
+		var cumulative int64
+		for i, le := range latencyBucketsMs {
+			cumulative += s.buckets[i]
+			e.Buckets[i] = LatencyBucket{LeMs: le, Count: cumulative}
+		}
+		endpoints = append(endpoints, e)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Count != endpoints[j].Count {
+			return endpoints[i].Count > endpoints[j].Count
+		}
+		if endpoints[i].Route != endpoints[j].Route {
+			return endpoints[i].Route < endpoints[j].Route
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return RequestMetricsSnapshot{Since: m.since, Endpoints: endpoints}
+}
+
+// GetRequestMetrics reports per-route request counts, 5xx errors and latency histograms
+// since the server started.
+// @Summary      Get API request metrics
+// @Tags         stats
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object} RequestMetricsSnapshot
+// @Router       /stats/requests [get]
+func (m *RequestMetrics) GetRequestMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, m.Snapshot())
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	m := NewRequestMetrics()
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/api/monitors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "boom" {
+			writeError(w, http.StatusInternalServerError, "boom")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"ok": "true"})
+	})
+
+	for _, path := range []string{"/api/monitors/a", "/api/monitors/b", "/api/monitors/boom", "/nope/1", "/nope/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	snap := m.Snapshot()
+	if len(snap.Endpoints) != 2 {
+		t.Fatalf("Expected 2 endpoints, got %+v", snap.Endpoints)
+	}
+	e := snap.Endpoints[0]
+	if e.Route != "/api/monitors/{id}" || e.Method != "GET" || e.Count != 3 || e.Errors != 1 {
+		t.Errorf("Unexpected endpoint metrics: %+v", e)
+	}
+	if last := e.Buckets[len(e.Buckets)-1]; last.Count != 3 {
+		t.Errorf("Expected all 3 requests in the cumulative buckets, got %+v", e.Buckets)
+	}
+	if u := snap.Endpoints[1]; u.Route != unmatchedRoute || u.Count != 2 {
+		t.Errorf("Expected unknown paths grouped as %q, got %+v", unmatchedRoute, u)
+	}
+}
+
+func TestRequestMetrics_Buckets(t *testing.T) {
+	m := NewRequestMetrics()
+	key := endpointKey{method: "GET", route: "/x"}
+	m.observe(key, 200, 3*time.Millisecond)
+	m.observe(key, 200, 40*time.Millisecond)
+	m.observe(key, 200, 20*time.Second)
+
+	e := m.Snapshot().Endpoints[0]
+	want := map[float64]int64{5: 1, 25: 1, 50: 2, 10000: 2}
+	for _, b := range e.Buckets {
+		if n, ok := want[b.LeMs]; ok && b.Count != n {
+			t.Errorf("bucket le=%v: got %d, want %d", b.LeMs, b.Count, n)
+		}
+	}
+	if e.Count != 3 || e.MaxMs != 20000 {
+		t.Errorf("Unexpected totals: %+v", e)
+	}
+}
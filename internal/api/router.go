@@ -58,7 +58,10 @@ func SecureHeadersWithConfig(cookieSecure bool) func(http.Handler) http.Handler
 // NewRouter builds the HTTP router serving both JSON APIs and static assets.
 func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) http.Handler {
 	r := chi.NewRouter()
+	requestMetrics := NewRequestMetrics()
+	r.Use(RequestID)
 	r.Use(middleware.Logger)
+	r.Use(requestMetrics.Middleware)
 	r.Use(middleware.Recoverer)
 
 	// SECURITY: Only trust X-Forwarded-For headers when behind a trusted reverse proxy.
@@ -70,6 +73,11 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 
 	r.Use(SecureHeadersWithConfig(cfg.CookieSecure))
 
+	// Compress JSON, status pages and any static asset without a precompressed variant
+	// for clients that accept gzip or deflate. Responses that already carry a
+	// Content-Encoding (precompressed assets) pass through unchanged.
+	r.Use(middleware.Compress(5))
+
 	// Rate limiter for general API requests (100 requests/second with burst of 200)
 	// This is high enough to not interfere with normal usage but prevents abuse
 	apiLimiter := NewIPRateLimiter(rate.Limit(100), 200)
@@ -105,23 +113,43 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 	ssoH := NewSSOHandler(store, cfg)
 	uptimeH := NewUptimeHandler(manager, store)
 	crudH := NewCRUDHandler(store, manager)
-	statsH := NewStatsHandler(store)
+	statsH := NewStatsHandler(store, manager)
 	settingsH := NewSettingsHandler(store, manager)
 	apiKeyH := NewAPIKeyHandler(store)
+	userTokenH := NewUserTokenHandler(store)
 	adminH := NewAdminHandler(store, manager, cfg)
-	incidentH := NewIncidentHandler(store)
+	incidentH := NewIncidentHandler(store, manager)
 	maintH := NewMaintenanceHandler(store, manager)
+	silenceH := NewSilenceHandler(store, manager)
 	eventH := NewEventHandler(store, manager)
 	statusPageH := NewStatusPageHandler(store, manager, authH)
 	notifH := NewNotificationChannelsHandler(store)
+	pushH := NewPushHandler(store)
+	ingestH := NewIngestHandler(store, manager)
+	issuesH := NewIssuesHandler(store)
+	costH := NewCostHandler(store, manager)
+	wsH := NewWSHandler(store, manager)
 
 	// Kubernetes health probes (unauthenticated, no rate limiting)
 	r.Get("/healthz", Healthz)
-	r.Get("/readyz", Readyz(store))
+	r.Get("/readyz", Readyz(store, manager))
+
+	// Generated robots.txt for public status pages (opt-in via settings)
+	r.Get("/robots.txt", statusPageH.RobotsTxt)
 
 	r.Route("/api", func(api chi.Router) {
 		// Apply general rate limiting to all API routes
 		api.Use(RateLimitMiddleware(apiLimiter))
+		api.Use(ReadOnlyMiddleware(store, cfg))
+		api.Use(DemoGuardMiddleware(cfg))
+
+		// Unknown API routes get a JSON error rather than the frontend
+		api.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusNotFound, "no such API endpoint")
+		})
+		api.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		})
 
 		// Public routes with stricter rate limiting for auth
 		api.Group(func(auth chi.Router) {
@@ -137,9 +165,27 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 			auth.Get("/auth/sso/google/callback", ssoH.GoogleCallback)
 		})
 
-		// Public Status Pages
-		api.Get("/s/{slug}", statusPageH.GetPublicStatus)
-		api.Get("/s/{slug}/rss", statusPageH.GetRSSFeed)
+		// Public Status Pages (ETag and Cache-Control for browsers and CDNs)
+		api.Group(func(public chi.Router) {
+			public.Use(statusPageH.CacheHeaders)
+			public.Get("/s/{slug}", statusPageH.GetPublicStatus)
+			public.Get("/s/{slug}/rss", statusPageH.GetRSSFeed)
+			public.Get("/s/{slug}/maintenance", statusPageH.GetMaintenanceCalendar)
+			public.Get("/s/{slug}/incidents.json", statusPageH.GetIncidentArchive)
+			public.Get("/s/{slug}/meta", statusPageH.GetPageMeta)
+		})
+
+		// Wallboard snapshot (usual credentials or the read-only wallboard token)
+		api.With(WallboardAuth(store, authH)).Get("/wallboard", uptimeH.GetWallboard)
+
+		// Maintenance calendar link from notifications (authenticated by its signed token)
+		api.Get("/maintenance/{id}/calendar.ics", maintH.GetMaintenanceCalendarFile)
+
+		// Inbound alert webhook (authenticated by the per-monitor token in the path)
+		api.Post("/ingest/webhook/{token}", ingestH.Webhook)
+
+		// Issue tracker webhook (authenticated by its HMAC signature)
+		api.Post("/integrations/issues/webhook", issuesH.Webhook)
 
 		// API Documentation (Swagger UI)
 		api.Get("/docs/*", httpSwagger.Handler(
@@ -153,11 +199,18 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 
 		api.Group(func(protected chi.Router) {
 			protected.Use(authH.AuthMiddleware)
+			// Any admin write may change what status pages show
+			protected.Use(statusPageH.InvalidateCacheOnWrite)
 			protected.Get("/auth/me", authH.Me)
 			protected.Patch("/auth/me", authH.UpdateUser)
+			protected.Get("/auth/me/tokens", userTokenH.ListTokens)
+			protected.Post("/auth/me/tokens", userTokenH.CreateToken)
+			protected.Delete("/auth/me/tokens/{id}", userTokenH.DeleteToken)
 
 			// Dashboard Overview
 			protected.Get("/overview", uptimeH.GetOverview)
+			protected.Post("/wallboard/token", uptimeH.CreateWallboardToken)
+			protected.Delete("/wallboard/token", uptimeH.DeleteWallboardToken)
 
 			// Groups
 			protected.Post("/groups", crudH.CreateGroup)
@@ -168,24 +221,95 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 			// /uptime maps to GetHistory in handlers_uptime.go (returns list of monitors with history)
 			protected.Get("/uptime", uptimeH.GetHistory)
 			protected.Post("/monitors", crudH.CreateMonitor)
+			protected.Post("/monitors/bulk", crudH.BulkMonitors)
+			protected.Post("/monitors/preview", crudH.PreviewMonitor)
+			protected.Get("/probe", crudH.Probe)
+			protected.Post("/import/openapi", crudH.ImportOpenAPI)
 			protected.Put("/monitors/{id}", crudH.UpdateMonitor)
 			protected.Delete("/monitors/{id}", crudH.DeleteMonitor)
 			protected.Post("/monitors/{id}/pause", crudH.PauseMonitor)
 			protected.Post("/monitors/{id}/resume", crudH.ResumeMonitor)
+			protected.Post("/monitors/{id}/check", uptimeH.CheckMonitor)
 			protected.Get("/monitors/{id}/uptime", uptimeH.GetMonitorUptime)
 			protected.Get("/monitors/{id}/latency", uptimeH.GetMonitorLatency)
+			protected.Get("/monitors/{id}/heatmap", uptimeH.GetMonitorHeatmap)
+			protected.Get("/monitors/{id}/reliability", uptimeH.GetMonitorReliability)
+			protected.Post("/monitors/{id}/annotations", uptimeH.CreateAnnotation)
+			protected.Post("/monitors/{id}/ingest-token", ingestH.CreateIngestToken)
+			protected.Get("/monitors/{id}/status-override", statusPageH.GetStatusOverride)
+			protected.Put("/monitors/{id}/status-override", statusPageH.SetStatusOverride)
+			protected.Delete("/monitors/{id}/status-override", statusPageH.ClearStatusOverride)
+			protected.Get("/monitors/{id}/shadow", crudH.GetMonitorShadow)
+			protected.Post("/monitors/{id}/shadow", crudH.StartMonitorShadow)
+			protected.Delete("/monitors/{id}/shadow", crudH.CancelMonitorShadow)
+			protected.Post("/monitors/{id}/shadow/promote", crudH.PromoteMonitorShadow)
+			protected.Get("/monitors/{id}/slo", crudH.GetMonitorSLO)
+			protected.Put("/monitors/{id}/slo", crudH.SetMonitorSLO)
+			protected.Delete("/monitors/{id}/slo", crudH.DeleteMonitorSLO)
+			protected.Get("/monitors/{id}/remediation", crudH.GetMonitorRemediation)
+			protected.Put("/monitors/{id}/remediation", crudH.SetMonitorRemediation)
+			protected.Delete("/monitors/{id}/remediation", crudH.DeleteMonitorRemediation)
+			protected.Get("/monitors/{id}/check-exclusions", crudH.GetMonitorCheckExclusions)
+			protected.Put("/monitors/{id}/check-exclusions", crudH.SetMonitorCheckExclusions)
+			protected.Delete("/monitors/{id}/check-exclusions", crudH.DeleteMonitorCheckExclusions)
+			protected.Get("/monitors/{id}/downtime-cost", crudH.GetMonitorDowntimeCost)
+			protected.Put("/monitors/{id}/downtime-cost", crudH.SetMonitorDowntimeCost)
+			protected.Delete("/monitors/{id}/downtime-cost", crudH.DeleteMonitorDowntimeCost)
+			protected.Get("/monitors/{id}/regions", crudH.GetMonitorRegions)
+			protected.Get("/monitors/{id}/watchers", notifH.ListWatchers)
+			protected.Post("/monitors/{id}/watchers", notifH.CreateWatcher)
+			protected.Delete("/monitors/{id}/watchers/{watcherId}", notifH.DeleteWatcher)
+
+			// Side-by-side uptime of monitors, e.g. the same service at several providers
+			protected.Get("/reports/compare", uptimeH.CompareMonitors)
+
+			// Fleet-wide annotations (e.g. posted by CI on deploy with an API key)
+			protected.Post("/annotations", uptimeH.CreateFleetAnnotation)
+
+			// External alert ingestion
+			protected.Post("/ingest/alertmanager", ingestH.Alertmanager)
+
+			// Agent result streaming and config push
+			protected.Get("/ws", wsH.Serve)
+			// Buffered agent results uploaded after a connectivity gap
+			protected.Post("/agent/results/batch", wsH.IngestResultBatch)
+
+			// Cost agents
+			protected.Get("/agents", costH.ListAgents)
+			protected.Post("/agents", costH.CreateAgent)
+			protected.Put("/agents/{id}", costH.UpdateAgent)
+			protected.Delete("/agents/{id}", costH.DeleteAgent)
+
+			// Cost data (latest agent snapshots)
+			protected.Get("/cost/summary", costH.GetSummary)
+			protected.Get("/cost/namespaces", costH.GetNamespaces)
+			protected.Get("/cost/nodes", costH.GetNodes)
+			protected.Get("/cost/workloads", costH.GetWorkloads)
+			protected.Get("/cost/history", costH.GetHistory)
+			protected.Get("/cost/labels/{key}/report", costH.GetLabelReport)
+			protected.Get("/cost/recommendations", costH.GetRecommendations)
+			protected.Get("/cost/budgets", costH.ListBudgets)
+			protected.Post("/cost/budgets", costH.CreateBudget)
+			protected.Put("/cost/budgets/{id}", costH.UpdateBudget)
+			protected.Delete("/cost/budgets/{id}", costH.DeleteBudget)
 
 			// Incidents
 			protected.Get("/incidents", incidentH.GetIncidents)
 			protected.Post("/incidents", incidentH.CreateIncident)
+			protected.Get("/incidents/pending-publication", incidentH.GetPendingPublication)
 			protected.Get("/incidents/{id}", incidentH.GetIncident)
 			protected.Put("/incidents/{id}", incidentH.UpdateIncident)
 			protected.Delete("/incidents/{id}", incidentH.DeleteIncident)
 			protected.Patch("/incidents/{id}/visibility", incidentH.SetVisibility)
 			protected.Get("/incidents/{id}/updates", incidentH.GetUpdates)
 			protected.Post("/incidents/{id}/updates", incidentH.AddUpdate)
+			protected.Put("/incidents/{id}/updates/{updateId}", incidentH.EditUpdate)
+			protected.Delete("/incidents/{id}/updates/{updateId}", incidentH.DeleteUpdate)
 
-			// Outages (promote to incident)
+			// Outages (evidence, promote to incident)
+			protected.Get("/outages", incidentH.ListOutages)
+			protected.Get("/outages/{id}", incidentH.GetOutage)
+			protected.Post("/outages/{id}/acknowledge", incidentH.AcknowledgeOutage)
 			protected.Post("/outages/{id}/promote", incidentH.PromoteOutage)
 
 			// Maintenance
@@ -193,10 +317,18 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 			protected.Get("/maintenance", maintH.GetMaintenance)
 			protected.Put("/maintenance/{id}", maintH.UpdateMaintenance)
 			protected.Delete("/maintenance/{id}", maintH.DeleteMaintenance)
+			protected.Get("/maintenance/{id}/ics", maintH.GetMaintenanceICS)
+
+			// Silences
+			protected.Get("/silences", silenceH.ListSilences)
+			protected.Post("/silences", silenceH.CreateSilence)
+			protected.Get("/silences/{id}", silenceH.GetSilence)
+			protected.Delete("/silences/{id}", silenceH.ExpireSilence)
 
 			// Settings
 			protected.Get("/settings", settingsH.GetSettings)
 			protected.Patch("/settings", settingsH.UpdateSettings)
+			protected.Get("/settings/schema", settingsH.GetSettingsSchema)
 
 			// SSO Settings (admin only)
 			protected.Post("/settings/sso/test", ssoH.TestSSOConfig)
@@ -204,10 +336,15 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 			// API Keys
 			protected.Get("/api-keys", apiKeyH.ListKeys)
 			protected.Post("/api-keys", apiKeyH.CreateKey)
+			protected.Patch("/api-keys/{id}", apiKeyH.UpdateKey)
 			protected.Delete("/api-keys/{id}", apiKeyH.DeleteKey)
 
 			// Stats
 			protected.Get("/stats", statsH.GetStats)
+			protected.Get("/stats/capacity", statsH.GetCapacity)
+			protected.Get("/stats/scheduler", statsH.GetScheduler)
+			protected.Get("/stats/requests", requestMetrics.GetRequestMetrics)
+			protected.Get("/admin/query-plans", adminH.GetQueryPlans)
 
 			// Notifications
 			protected.Get("/notifications/channels", notifH.GetChannels)
@@ -216,43 +353,40 @@ func NewRouter(manager *uptime.Manager, store *db.Store, cfg *config.Config) htt
 			protected.Put("/notifications/channels/{id}", notifH.UpdateChannel)
 			protected.Delete("/notifications/channels/{id}", notifH.DeleteChannel)
 
+			// Push devices
+			protected.Get("/push/devices", pushH.ListDevices)
+			protected.Post("/push/devices", pushH.RegisterDevice)
+			protected.Delete("/push/devices/{id}", pushH.DeleteDevice)
+			protected.Post("/push/subscribe", pushH.Subscribe)
+			protected.Post("/push/unsubscribe", pushH.Unsubscribe)
+			protected.Get("/push/vapid-public-key", pushH.GetVAPIDPublicKey)
+			protected.Post("/push/vapid/rotate", pushH.RotateVAPIDKey)
+
 			// Events (for history)
 			protected.Get("/events", eventH.GetSystemEvents)
+			protected.Get("/events/log", eventH.GetEventLog)
 
 			// Status Pages Management
 			protected.Get("/status-pages", statusPageH.GetAll)
-			// Note: Create/Upd/Del methods need to be verified in handlers_status_pages.go
-			// Based on GetAll, it likely has Toggle.
-			// Let's assume standard names or check Step 1189 view.
-			// Step 1189 shows: GetAll, Toggle, GetPublicStatus.
-			// It does NOT show CreateStatusPage, UpdateStatusPage, DeleteStatusPage explicitly in the view
-			// (view truncated? No, showed 1-284 which seemed to be whole file?).
-			// Wait, Step 1189 showed lines 1-284 for handlers_status_pages.go.
-			// It handled GetAll and Toggle and GetPublicStatus.
-			// There is NO Create/Delete?
-			// The store has UpsertStatusPage used in Toggle.
-			// Maybe there is no Create? Just Toggle?
-			// The routes in Step 1146 (original) were:
-			// protected.Post("/status-pages", apiRouter.CreateStatusPage)
-			// protected.Patch("/status-pages/{slug}", apiRouter.UpdateStatusPage)
-			// protected.Delete("/status-pages/{slug}", apiRouter.DeleteStatusPage)
-
-			// If handlers_status_pages.go only has Toggle, then "UpdateStatusPage" mapping to Toggle is correct.
-			// What about Create/Delete?
-			// Maybe they were missing or I missed them in search?
-			// If they are missing, I should ommit or fix.
-			// Toggle does Upsert. So maybe Post -> Toggle?
+			protected.Post("/status-pages", statusPageH.Create)
 			protected.Patch("/status-pages/{slug}", statusPageH.Toggle)
-
-			// If Create/Delete are missing, I'll comment them out for now to avoid compilation error.
+			protected.Delete("/status-pages/{slug}", statusPageH.Delete)
+			protected.Get("/status-pages/{slug}/mirror", statusPageH.GetMirror)
+			protected.Put("/status-pages/{slug}/mirror", statusPageH.SetMirror)
+			protected.Delete("/status-pages/{slug}/mirror", statusPageH.DeleteMirror)
+			protected.Get("/status-pages/{slug}/channels", statusPageH.GetChannels)
+			protected.Put("/status-pages/{slug}/channels", statusPageH.SetChannels)
 		})
 	})
 
 	// Workaround for Vite Proxy stripping /api prefix for api-keys
 	r.Group(func(r chi.Router) {
+		r.Use(ReadOnlyMiddleware(store, cfg))
+		r.Use(DemoGuardMiddleware(cfg))
 		r.Use(authH.AuthMiddleware)
 		r.Get("/api-keys", apiKeyH.ListKeys)
 		r.Post("/api-keys", apiKeyH.CreateKey)
+		r.Patch("/api-keys/{id}", apiKeyH.UpdateKey)
 		r.Delete("/api-keys/{id}", apiKeyH.DeleteKey)
 	})
 
@@ -270,6 +404,3 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
-}
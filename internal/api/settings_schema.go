@@ -0,0 +1,226 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/issues"
+	"github.com/projecthelena/warden/internal/notifications"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// Value types of a setting. Every value is stored and transported as a string.
+const (
+	settingInteger = "integer"
+	settingBoolean = "boolean"
+	settingString  = "string"
+)
+
+// SettingSpec describes one application setting accepted by PATCH /settings.
+type SettingSpec struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"` // integer | boolean | string
+	Default     string `json:"default"`
+	Min         *int   `json:"min,omitempty"`
+	Max         *int   `json:"max,omitempty"`
+	Format      string `json:"format,omitempty"` // further constraint on strings, e.g. "url" or "HH:MM"
+	Description string `json:"description"`
+	// Secret settings are write-only; GET /settings only reports whether they are set.
+	Secret bool `json:"secret,omitempty"`
+	// AllowEmpty settings keep an explicit empty value instead of falling back to Default.
+	AllowEmpty bool `json:"allowEmpty,omitempty"`
+
+	validate func(string) error // format check run after the type check
+}
+
+// Validate reports why val is not acceptable for the setting, or nil.
+func (s SettingSpec) Validate(val string) error {
+	switch s.Type {
+	case settingInteger:
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		if (s.Min != nil && i < *s.Min) || (s.Max != nil && i > *s.Max) {
+			switch {
+			case s.Max == nil:
+				return fmt.Errorf("must be at least %d", *s.Min)
+			case s.Min == nil:
+				return fmt.Errorf("must be at most %d", *s.Max)
+			default:
+				return fmt.Errorf("must be between %d and %d", *s.Min, *s.Max)
+			}
+		}
+	case settingBoolean:
+		if val != "true" && val != "false" {
+			return errors.New(`must be "true" or "false"`)
+		}
+	}
+	if s.validate != nil {
+		return s.validate(val)
+	}
+	return nil
+}
+
+func intBound(v int) *int { return &v }
+
+// digestEventTypes are the notification events that can be batched into the daily digest.
+var digestEventTypes = []string{"down", "up", "degraded", "flapping", "stabilized", "ssl_expiring", "anomaly"}
+
+// settingsSchema lists every writable setting, in the order the schema endpoint reports them.
+var settingsSchema = []SettingSpec{
+	{Key: "latency_threshold", Type: settingInteger, Default: "1000", Min: intBound(0), Description: "Response time in ms above which a check counts as degraded"},
+	{Key: "data_retention_days", Type: settingInteger, Default: "365", Min: intBound(1), Max: intBound(3650), Description: "Days of check history to keep"},
+
+	{Key: uptime.WatchdogURLKey, Type: settingString, Format: "url", Secret: true, validate: validateOptionalURL, Description: "URL pinged every interval so a dead man's switch such as Healthchecks.io alerts when Warden stops; empty disables it"},
+	{Key: uptime.WatchdogIntervalKey, Type: settingInteger, Default: "60", Min: intBound(10), Max: intBound(3600), Description: "Seconds between watchdog pings"},
+
+	{Key: "notifications.slack.enabled", Type: settingBoolean, Default: "false", Description: "Send notifications to the legacy Slack webhook"},
+	{Key: "notifications.slack.webhook_url", Type: settingString, Format: "url", Secret: true, validate: validateOptionalURL, Description: "Legacy Slack incoming webhook URL"},
+	{Key: "notifications.slack.notify_on", Type: settingString, Description: "Events sent to the legacy Slack webhook"},
+
+	{Key: "sso.google.enabled", Type: settingBoolean, Default: "false", Description: "Allow signing in with Google"},
+	{Key: "sso.google.client_id", Type: settingString, Description: "Google OAuth client ID"},
+	{Key: "sso.google.client_secret", Type: settingString, Secret: true, Description: "Google OAuth client secret"},
+	{Key: "sso.google.redirect_url", Type: settingString, Format: "url", validate: validateOptionalURL, Description: "OAuth callback URL; derived from the request when empty"},
+	{Key: "sso.google.allowed_domains", Type: settingString, Description: "Comma-separated email domains allowed to sign in; empty allows any"},
+	{Key: "sso.google.auto_provision", Type: settingBoolean, Default: "false", Description: "Create accounts for first-time Google sign-ins"},
+
+	{Key: issues.ProviderKey, Type: settingString, Format: "jira | github", validate: validateIssueProvider, Description: "Tracker an issue is opened in for every new incident; empty disables it"},
+	{Key: issues.TitleTemplateKey, Type: settingString, Default: issues.DefaultTitleTemplate, Format: "template", validate: issues.ValidateTemplate, Description: "Go template for the issue title, e.g. {{.Severity}} and {{.Title}}"},
+	{Key: issues.BodyTemplateKey, Type: settingString, Default: issues.DefaultBodyTemplate, Format: "template", validate: issues.ValidateTemplate, Description: "Go template for the issue body, with the incident's ID, Title, Description, Severity, Status, Source, StartTime and AffectedGroups"},
+	{Key: issues.WebhookSecretKey, Type: settingString, Secret: true, Description: "Secret the tracker signs its webhook with; when set, closing an issue resolves its incident"},
+	{Key: issues.JiraBaseURLKey, Type: settingString, Format: "url", validate: validateOptionalURL, Description: "Jira site URL, e.g. https://example.atlassian.net"},
+	{Key: issues.JiraEmailKey, Type: settingString, Description: "Jira account email the API token belongs to; empty sends the token as a Data Center personal access token"},
+	{Key: issues.JiraTokenKey, Type: settingString, Secret: true, Description: "Jira API token"},
+	{Key: issues.JiraProjectKey, Type: settingString, Description: "Key of the Jira project issues are opened in"},
+	{Key: issues.JiraIssueTypeKey, Type: settingString, Default: issues.DefaultJiraIssueType, Description: "Jira issue type"},
+	{Key: issues.GitHubRepoKey, Type: settingString, Format: "owner/name", validate: validateGitHubRepo, Description: "GitHub repository issues are opened in"},
+	{Key: issues.GitHubTokenKey, Type: settingString, Secret: true, Description: "GitHub token allowed to write issues in the repository"},
+	{Key: issues.GitHubLabelsKey, Type: settingString, Default: issues.DefaultGitHubLabels, Format: "comma-separated list", AllowEmpty: true, Description: "Labels added to GitHub issues"},
+	{Key: issues.GitHubAPIURLKey, Type: settingString, Default: issues.DefaultGitHubAPIURL, Format: "url", validate: validateOptionalURL, Description: "GitHub API URL; change it for GitHub Enterprise Server"},
+
+	{Key: "notification.confirmation_threshold", Type: settingInteger, Default: "3", Min: intBound(1), Max: intBound(100), Description: "Consecutive failed checks before a monitor is confirmed down"},
+	{Key: "notification.cooldown_minutes", Type: settingInteger, Default: "30", Min: intBound(0), Max: intBound(1440), Description: "Minimum minutes between repeated notifications for a monitor"},
+	{Key: "notification.flap_detection_enabled", Type: settingBoolean, Default: "true", Description: "Detect monitors oscillating between up and down"},
+	{Key: "notification.flap_window_checks", Type: settingInteger, Default: "21", Min: intBound(3), Max: intBound(100), Description: "Checks considered for flap detection"},
+	{Key: "notification.flap_threshold_percent", Type: settingInteger, Default: "25", Min: intBound(1), Max: intBound(100), Description: "State changes within the window, in percent, that count as flapping"},
+	{Key: "notification.recovery_confirmation_checks", Type: settingInteger, Default: "1", Min: intBound(1), Max: intBound(20), Description: "Consecutive successful checks before a recovery is confirmed"},
+	{Key: "notification.degraded_window_checks", Type: settingInteger, Default: "0", Min: intBound(0), Max: intBound(uptime.MaxDegradedWindowChecks), Description: "Checks considered for degraded hysteresis; 0 disables it"},
+	{Key: "notification.degraded_threshold_checks", Type: settingInteger, Default: "0", Min: intBound(0), Max: intBound(uptime.MaxDegradedWindowChecks), Description: "Slow checks within the window before a monitor is degraded"},
+	{Key: "notification.anomaly_deviations", Type: settingInteger, Default: "0", Min: intBound(0), Max: intBound(20), Description: "Deviations above a monitor's learned latency, sustained for 3 checks, that count as a latency anomaly; 0 disables anomaly detection"},
+	{Key: "notification.storm.threshold_percent", Type: settingInteger, Default: "0", Min: intBound(0), Max: intBound(100), Description: "Share of monitors, in percent, going down within the storm window that is collapsed into one platform event; 0 disables storm dampening"},
+	{Key: "notification.storm.window_seconds", Type: settingInteger, Default: strconv.Itoa(uptime.DefaultStormWindowSeconds), Min: intBound(1), Max: intBound(3600), Description: "Seconds within which mass failures count as one platform event; down notifications are held this long while storm dampening is enabled"},
+	{Key: "notification.storm.min_monitors", Type: settingInteger, Default: strconv.Itoa(uptime.DefaultStormMinMonitors), Min: intBound(2), Max: intBound(10000), Description: "Fewest monitors down at once that can make a platform event"},
+	{Key: "diagnostics.traceroute_after_checks", Type: settingInteger, Default: "0", Min: intBound(0), Max: intBound(100), Description: "Consecutive failures before a traceroute is captured; 0 disables it"},
+
+	{Key: "notification.event.down.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor goes down"},
+	{Key: "notification.event.up.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor recovers"},
+	{Key: "notification.event.degraded.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor is degraded"},
+	{Key: "notification.event.flapping.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor starts flapping"},
+	{Key: "notification.event.stabilized.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor stops flapping"},
+	{Key: "notification.event.ssl_expiring.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a certificate is about to expire"},
+	{Key: "notification.event.anomaly.enabled", Type: settingBoolean, Default: "true", Description: "Notify when a monitor's latency is anomalous"},
+
+	{Key: "notification.digest.enabled", Type: settingBoolean, Default: "false", Description: "Batch selected events into a daily digest"},
+	{Key: "notification.digest.time", Type: settingString, Default: "09:00", Format: "HH:MM", validate: validateClockTime, Description: "Time of day the digest is sent, in the admin's timezone"},
+	{Key: "notification.digest.event_types", Type: settingString, Default: "degraded,flapping,stabilized,ssl_expiring", Format: "comma-separated list", validate: validateDigestEventTypes, Description: "Events batched into the digest: " + strings.Join(digestEventTypes, ", ")},
+	{Key: notifications.MaintenanceReminderHoursKey, Type: settingString, Default: notifications.DefaultMaintenanceReminderHours, Format: "comma-separated hours", AllowEmpty: true, validate: validateReminderHours, Description: "Hours before a maintenance window to send reminders; empty disables them"},
+
+	{Key: notifications.PushGatewayURLKey, Type: settingString, Format: "url", validate: validateOptionalURL, Description: "Push gateway that forwards notifications to the FCM and APNs devices of push channels"},
+	{Key: notifications.PushGatewayTokenKey, Type: settingString, Secret: true, Description: "Bearer token sent to the push gateway"},
+	{Key: notifications.VAPIDSubjectKey, Type: settingString, Default: notifications.DefaultVAPIDSubject, Format: "mailto: or https: URL", validate: validateVAPIDSubject, Description: "Contact the push services of browsers can reach you at, sent with Web Push notifications"},
+
+	{Key: robotsTxtSettingKey, Type: settingBoolean, Default: "false", Description: "Serve a generated robots.txt for status pages"},
+	{Key: incidentAutoPublishSettingKey, Type: settingBoolean, Default: "false", Description: "Publish incidents promoted from outages without review"},
+	{Key: readOnlySettingKey, Type: settingBoolean, Default: "false", Description: "Reject API writes with 503, e.g. during database maintenance"},
+	{Key: readOnlyNoticeSettingKey, Type: settingString, Description: "Message returned with writes rejected in read-only mode"},
+	{Key: weekStartSettingKey, Type: settingString, Default: "monday", Format: "weekday", validate: validateWeekday, Description: "First day of the week for the this_week and last_week report ranges"},
+	{Key: groupStatusPolicySettingKey, Type: settingString, Default: groupStatusAnyDown, Format: "any_down | majority | weighted", validate: validateGroupStatusPolicy, Description: "When the overview shows a group as down: any_down when any monitor is down, majority when more than half are, weighted when monitors carrying more than half the weight are; groups short of it show as degraded"},
+	{Key: criticalTagSettingKey, Type: settingString, Default: defaultCriticalTag, validate: validateTag, Description: "Tag of the monitors that weigh more under the weighted group status policy"},
+	{Key: criticalWeightSettingKey, Type: settingInteger, Default: strconv.Itoa(defaultCriticalWeight), Min: intBound(1), Max: intBound(100), Description: "Weight of critical monitors under the weighted group status policy; other monitors weigh 1"},
+}
+
+// settingSpecs indexes settingsSchema by key.
+var settingSpecs = func() map[string]SettingSpec {
+	specs := make(map[string]SettingSpec, len(settingsSchema))
+	for _, s := range settingsSchema {
+		specs[s.Key] = s
+	}
+	return specs
+}()
+
+func validateOptionalURL(val string) error {
+	if val == "" {
+		return nil
+	}
+	u, err := url.Parse(val)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func validateVAPIDSubject(val string) error {
+	u, err := url.Parse(val)
+	if err != nil || !((u.Scheme == "mailto" && u.Opaque != "") || (u.Scheme == "https" && u.Host != "")) {
+		return errors.New("must be a mailto: or https: URL")
+	}
+	return nil
+}
+
+func validateIssueProvider(val string) error {
+	switch val {
+	case "", issues.ProviderJira, issues.ProviderGitHub:
+		return nil
+	}
+	return errors.New(`must be "jira", "github" or empty`)
+}
+
+// githubRepoRe matches an owner/name repository.
+var githubRepoRe = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+func validateGitHubRepo(val string) error {
+	if val != "" && !githubRepoRe.MatchString(val) {
+		return errors.New("must be a repository as owner/name")
+	}
+	return nil
+}
+
+func validateWeekday(val string) error {
+	if _, ok := parseWeekday(val); !ok {
+		return errors.New("must be a lowercase weekday, e.g. monday or sunday")
+	}
+	return nil
+}
+
+func validateClockTime(val string) error {
+	if _, err := time.Parse("15:04", val); err != nil || len(val) != 5 {
+		return errors.New("must be a time of day as HH:MM")
+	}
+	return nil
+}
+
+func validateDigestEventTypes(val string) error {
+	for _, t := range strings.Split(val, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !slices.Contains(digestEventTypes, t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
+
+func validateReminderHours(val string) error {
+	_, err := notifications.ParseReminderHours(val)
+	return err
+}
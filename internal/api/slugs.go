@@ -0,0 +1,93 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// maxSlugLength bounds generated slugs (excluding any prefix or collision suffix).
+const maxSlugLength = 64
+
+// slugFold maps common accented Latin letters to ASCII so "Café Ops" becomes "cafe-ops"
+// rather than "caf-ops".
+var slugFold = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+}
+
+// normalizeSlug lowercases s and reduces it to [a-z0-9] runs joined by single dashes,
+// e.g. "  My -- Group! " -> "my-group". Returns "" if nothing usable remains.
+func normalizeSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if f, ok := slugFold[r]; ok {
+			b.WriteString(f)
+			dash = false
+			continue
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// randomSlug returns a short random token for names that normalize to nothing.
+func randomSlug() string {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "rnd"
+	}
+	return hex.EncodeToString(b)
+}
+
+// uniqueSlug returns base if it is free, otherwise base-2, base-3, ... The taken
+// callback reports whether a candidate is already in use.
+func uniqueSlug(base string, taken func(string) bool) string {
+	if !taken(base) {
+		return base
+	}
+	for n := 2; n <= 100; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+	// Pathological number of duplicates; fall back to a random suffix
+	return base + "-" + randomSlug()
+}
+
+// defaultGroupPageSlug derives the status page slug of a group from its ID
+// ("g-payments" -> "payments"), numbering it if the slug is taken or reserved.
+func defaultGroupPageSlug(groupID string, taken func(string) bool) string {
+	base := normalizeSlug(strings.TrimPrefix(groupID, "g-"))
+	if base == "" {
+		base = "group"
+	}
+	return uniqueSlug(base, taken)
+}
+
+// sameGroup reports whether two optional group IDs refer to the same scope.
+func sameGroup(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"My Group", "my-group"},
+		{"  My -- Group! ", "my-group"},
+		{"Café Ops", "cafe-ops"},
+		{"API/v2_prod", "api-v2-prod"},
+		{"!!!", ""},
+		{"日本", ""},
+		{strings.Repeat("a", 70), strings.Repeat("a", 64)},
+		{strings.Repeat("a", 63) + " b", strings.Repeat("a", 63)},
+	}
+	for _, tt := range tests {
+		if got := normalizeSlug(tt.in); got != tt.want {
+			t.Errorf("normalizeSlug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSlug_NeverEmpty(t *testing.T) {
+	slug := generateSlug("@#$%", "g-")
+	if slug == "g-" || !strings.HasPrefix(slug, "g-") {
+		t.Errorf("Expected non-empty prefixed slug, got %q", slug)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	taken := map[string]bool{"ops": true, "ops-2": true}
+	if got := uniqueSlug("ops", func(s string) bool { return taken[s] }); got != "ops-3" {
+		t.Errorf("Expected ops-3, got %q", got)
+	}
+	if got := uniqueSlug("web", func(s string) bool { return taken[s] }); got != "web" {
+		t.Errorf("Expected web, got %q", got)
+	}
+}
+
+func TestDefaultGroupPageSlug(t *testing.T) {
+	taken := map[string]bool{"all": true}
+	isTaken := func(s string) bool { return taken[s] }
+	if got := defaultGroupPageSlug("g-payments", isTaken); got != "payments" {
+		t.Errorf("Expected payments, got %q", got)
+	}
+	// A group named "All" must not map onto the global page
+	if got := defaultGroupPageSlug("g-all", isTaken); got != "all-2" {
+		t.Errorf("Expected all-2, got %q", got)
+	}
+	if got := defaultGroupPageSlug("g-", isTaken); got != "group" {
+		t.Errorf("Expected group, got %q", got)
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// statusPageCacheTTL is how long a rendered status page is served from memory, and
+	// how long browsers and CDNs may reuse a public status page response.
+	statusPageCacheTTL = 10 * time.Second
+	// statusPageCacheMaxEntries bounds the cache; one entry per page and requested timezone.
+	statusPageCacheMaxEntries = 1000
+)
+
+// statusPageCache keeps recently rendered status page responses, so anonymous traffic
+// doesn't run the page's queries on every request. Entries expire after a short TTL,
+// which bounds how stale live monitor status can get, and are all dropped after any
+// write through the admin API.
+type statusPageCache struct {
+	mu      sync.Mutex
+	entries map[string]statusPageCacheEntry
+}
+
+type statusPageCacheEntry struct {
+	resp    *bufferedResponse
+	expires time.Time
+}
+
+func newStatusPageCache() *statusPageCache {
+	return &statusPageCache{entries: make(map[string]statusPageCacheEntry)}
+}
+
+func (c *statusPageCache) get(key string) (*bufferedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.resp, true
+}
+
+func (c *statusPageCache) set(key string, resp *bufferedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= statusPageCacheMaxEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= statusPageCacheMaxEntries {
+			c.entries = make(map[string]statusPageCacheEntry)
+		}
+	}
+	c.entries[key] = statusPageCacheEntry{resp: resp, expires: now.Add(statusPageCacheTTL)}
+}
+
+// invalidate drops every cached page.
+func (c *statusPageCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]statusPageCacheEntry)
+}
+
+// InvalidateCacheOnWrite drops the cached status pages after every request that may have
+// changed what they show, i.e. anything but a read.
+func (h *StatusPageHandler) InvalidateCacheOnWrite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			h.cache.invalidate()
+		}
+	})
+}
+
+// CacheHeaders gives public status page responses an ETag, answers conditional requests
+// whose ETag still matches with 304 Not Modified, and lets browsers and CDNs reuse
+// anonymous responses for a few seconds. Responses to signed-in viewers may include
+// private pages, so only the browser may keep them, and it must revalidate.
+func (h *StatusPageHandler) CacheHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		buf := newBufferedResponse(w.Header())
+		next.ServeHTTP(buf, r)
+
+		if buf.status != http.StatusOK {
+			buf.header.Set("Cache-Control", "no-cache")
+			buf.writeTo(w)
+			return
+		}
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		buf.header.Set("ETag", etag)
+		if h.auth.IsAuthenticated(r) {
+			buf.header.Set("Cache-Control", "private, no-cache")
+		} else {
+			buf.header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(statusPageCacheTTL.Seconds())))
+		}
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			copyHeader(w.Header(), buf.header)
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		buf.writeTo(w)
+	})
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse captures a response so it can be inspected, cached or replayed.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// newBufferedResponse starts from a copy of the headers set so far, such as the request ID.
+func newBufferedResponse(header http.Header) *bufferedResponse {
+	return &bufferedResponse{header: header.Clone(), status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// writeTo sends the captured response. Headers the destination already has, such as its
+// own request ID, are kept.
+func (b *bufferedResponse) writeTo(w http.ResponseWriter) {
+	copyHeader(w.Header(), b.header)
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func groupNames(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var names []string
+	for _, g := range decodeJSON(t, w)["groups"].([]interface{}) {
+		names = append(names, g.(map[string]interface{})["name"].(string))
+	}
+	return names
+}
+
+func TestStatusPageCache_InvalidatedByWrites(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-c", "Before")
+	seedMonitor(t, store, "m-c", "g-c", "API")
+	gid := "g-c"
+	seedPage(t, store, "cached", "Cached", &gid, true, true)
+	// Pages are only cached once the manager has loaded monitors
+	spH.manager.Sync()
+
+	get := func() []string {
+		w := httptest.NewRecorder()
+		spH.GetPublicStatus(w, makeRequest("GET", "/api/s/cached", "cached", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		return groupNames(t, w)
+	}
+	if names := get(); len(names) != 1 || names[0] != "Before" {
+		t.Fatalf("Expected the group, got %v", names)
+	}
+
+	// A change that bypasses the API is only picked up once the entry expires
+	if err := store.UpdateGroup("g-c", "After"); err != nil {
+		t.Fatal(err)
+	}
+	if names := get(); names[0] != "Before" {
+		t.Errorf("Expected the cached page, got %v", names)
+	}
+
+	r := chi.NewRouter()
+	r.Use(spH.InvalidateCacheOnWrite)
+	r.Get("/api/groups", func(w http.ResponseWriter, r *http.Request) {})
+	r.Put("/api/groups/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/groups", nil))
+	if names := get(); names[0] != "Before" {
+		t.Errorf("Expected a read to keep the cache, got %v", names)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/api/groups/g-c", nil))
+	if names := get(); names[0] != "After" {
+		t.Errorf("Expected a write to drop the cache, got %v", names)
+	}
+
+	// A private page still requires a session when served from the cache
+	seedPage(t, store, "cached", "Cached", &gid, false, true)
+	w := httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/cached", "cached", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a private page, got %d", w.Code)
+	}
+}
+
+func TestStatusPageCacheHeaders(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedGroup(t, store, "g-h", "G")
+	seedMonitor(t, store, "m-h", "g-h", "API")
+	seedPage(t, store, "etag", "ETag", nil, true, true)
+
+	r := chi.NewRouter()
+	r.Use(spH.CacheHeaders)
+	r.Get("/api/s/{slug}", spH.GetPublicStatus)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/s/etag", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Cache-Control") != "public, max-age=10" {
+		t.Errorf("Expected an ETag and public caching, got ETag %q, Cache-Control %q", etag, w.Header().Get("Cache-Control"))
+	}
+
+	req := httptest.NewRequest("GET", "/api/s/etag", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+		t.Errorf("Expected 304 without a body, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+
+	req = httptest.NewRequest("GET", "/api/s/etag", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Errorf("Expected the full page for a stale ETag, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/s/missing", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("Expected an uncached 404, got %d with ETag %q and Cache-Control %q", w.Code, w.Header().Get("ETag"), w.Header().Get("Cache-Control"))
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultStatusPageLocale is used for pages without a locale and as the fallback
+// for labels a translation does not define.
+const DefaultStatusPageLocale = "en"
+
+// statusPageTranslations maps a locale to the visitor-facing labels of a public status
+// page. Keys are stable identifiers the frontend looks up; values are display text.
+var (
+	statusPageTranslationsMu sync.RWMutex
+	statusPageTranslations   = map[string]map[string]string{
+		"en": {
+			"status.operational":            "Operational",
+			"status.degraded":               "Degraded",
+			"status.down":                   "Down",
+			"status.paused":                 "Paused",
+			"status.maintenance":            "Maintenance",
+			"overall.operational":           "All Systems Operational",
+			"overall.degraded":              "Partially Degraded Service",
+			"overall.down":                  "System Outage",
+			"overall.maintenance":           "System Under Maintenance",
+			"severity.minor":                "Minor",
+			"severity.major":                "Major",
+			"severity.critical":             "Critical",
+			"incident.investigating":        "Investigating",
+			"incident.identified":           "Identified",
+			"incident.monitoring":           "Monitoring",
+			"incident.resolved":             "Resolved",
+			"maintenance.scheduled":         "Scheduled",
+			"maintenance.in_progress":       "In Progress",
+			"maintenance.completed":         "Completed",
+			"section.active_incidents":      "Active Incidents",
+			"section.scheduled_maintenance": "Scheduled Maintenance",
+			"section.past_incidents":        "Past Incidents",
+			"label.uptime":                  "Uptime",
+			"label.last_updated":            "Last updated",
+			"label.no_incidents":            "No incidents reported",
+			"label.sla":                     "Uptime over the last 90 days",
+			"label.sla_methodology":         "Share of successful checks across the services on this page. Announced maintenance is excluded.",
+		},
+		"es": {
+			"status.operational":            "Operativo",
+			"status.degraded":               "Rendimiento degradado",
+			"status.down":                   "Interrupción",
+			"status.paused":                 "En pausa",
+			"status.maintenance":            "En mantenimiento",
+			"overall.operational":           "Todos los sistemas operativos",
+			"overall.degraded":              "Servicio parcialmente degradado",
+			"overall.down":                  "Interrupción del sistema",
+			"overall.maintenance":           "Sistema en mantenimiento",
+			"severity.minor":                "Menor",
+			"severity.major":                "Mayor",
+			"severity.critical":             "Crítico",
+			"incident.investigating":        "Investigando",
+			"incident.identified":           "Identificado",
+			"incident.monitoring":           "Supervisando",
+			"incident.resolved":             "Resuelto",
+			"maintenance.scheduled":         "Programado",
+			"maintenance.in_progress":       "En curso",
+			"maintenance.completed":         "Completado",
+			"section.active_incidents":      "Incidentes activos",
+			"section.scheduled_maintenance": "Mantenimiento programado",
+			"section.past_incidents":        "Incidentes anteriores",
+			"label.uptime":                  "Disponibilidad",
+			"label.last_updated":            "Última actualización",
+			"label.no_incidents":            "No se han reportado incidentes",
+			"label.sla":                     "Disponibilidad en los últimos 90 días",
+			"label.sla_methodology":         "Porcentaje de comprobaciones correctas de los servicios de esta página. Se excluye el mantenimiento anunciado.",
+		},
+		"fr": {
+			"status.operational":            "Opérationnel",
+			"status.degraded":               "Performances dégradées",
+			"status.down":                   "Panne",
+			"status.paused":                 "En pause",
+			"status.maintenance":            "En maintenance",
+			"overall.operational":           "Tous les systèmes sont opérationnels",
+			"overall.degraded":              "Service partiellement dégradé",
+			"overall.down":                  "Panne du système",
+			"overall.maintenance":           "Système en maintenance",
+			"severity.minor":                "Mineur",
+			"severity.major":                "Majeur",
+			"severity.critical":             "Critique",
+			"incident.investigating":        "Enquête en cours",
+			"incident.identified":           "Identifié",
+			"incident.monitoring":           "Surveillance",
+			"incident.resolved":             "Résolu",
+			"maintenance.scheduled":         "Planifiée",
+			"maintenance.in_progress":       "En cours",
+			"maintenance.completed":         "Terminée",
+			"section.active_incidents":      "Incidents en cours",
+			"section.scheduled_maintenance": "Maintenance planifiée",
+			"section.past_incidents":        "Incidents passés",
+			"label.uptime":                  "Disponibilité",
+			"label.last_updated":            "Dernière mise à jour",
+			"label.no_incidents":            "Aucun incident signalé",
+			"label.sla":                     "Disponibilité sur les 90 derniers jours",
+			"label.sla_methodology":         "Part des vérifications réussies pour les services de cette page. La maintenance annoncée est exclue.",
+		},
+		"de": {
+			"status.operational":            "Betriebsbereit",
+			"status.degraded":               "Eingeschränkte Leistung",
+			"status.down":                   "Ausfall",
+			"status.paused":                 "Pausiert",
+			"status.maintenance":            "In Wartung",
+			"overall.operational":           "Alle Systeme betriebsbereit",
+			"overall.degraded":              "Teilweise eingeschränkter Dienst",
+			"overall.down":                  "Systemausfall",
+			"overall.maintenance":           "System in Wartung",
+			"severity.minor":                "Gering",
+			"severity.major":                "Erheblich",
+			"severity.critical":             "Kritisch",
+			"incident.investigating":        "Wird untersucht",
+			"incident.identified":           "Identifiziert",
+			"incident.monitoring":           "Wird beobachtet",
+			"incident.resolved":             "Behoben",
+			"maintenance.scheduled":         "Geplant",
+			"maintenance.in_progress":       "Läuft",
+			"maintenance.completed":         "Abgeschlossen",
+			"section.active_incidents":      "Aktive Vorfälle",
+			"section.scheduled_maintenance": "Geplante Wartung",
+			"section.past_incidents":        "Vergangene Vorfälle",
+			"label.uptime":                  "Verfügbarkeit",
+			"label.last_updated":            "Zuletzt aktualisiert",
+			"label.no_incidents":            "Keine Vorfälle gemeldet",
+			"label.sla":                     "Verfügbarkeit der letzten 90 Tage",
+			"label.sla_methodology":         "Anteil erfolgreicher Prüfungen der Dienste auf dieser Seite. Angekündigte Wartungen sind ausgenommen.",
+		},
+		"pt": {
+			"status.operational":            "Operacional",
+			"status.degraded":               "Desempenho degradado",
+			"status.down":                   "Interrupção",
+			"status.paused":                 "Pausado",
+			"status.maintenance":            "Em manutenção",
+			"overall.operational":           "Todos os sistemas operacionais",
+			"overall.degraded":              "Serviço parcialmente degradado",
+			"overall.down":                  "Interrupção do sistema",
+			"overall.maintenance":           "Sistema em manutenção",
+			"severity.minor":                "Menor",
+			"severity.major":                "Maior",
+			"severity.critical":             "Crítico",
+			"incident.investigating":        "Investigando",
+			"incident.identified":           "Identificado",
+			"incident.monitoring":           "Monitorando",
+			"incident.resolved":             "Resolvido",
+			"maintenance.scheduled":         "Agendada",
+			"maintenance.in_progress":       "Em andamento",
+			"maintenance.completed":         "Concluída",
+			"section.active_incidents":      "Incidentes ativos",
+			"section.scheduled_maintenance": "Manutenção agendada",
+			"section.past_incidents":        "Incidentes anteriores",
+			"label.uptime":                  "Disponibilidade",
+			"label.last_updated":            "Última atualização",
+			"label.no_incidents":            "Nenhum incidente relatado",
+			"label.sla":                     "Disponibilidade nos últimos 90 dias",
+			"label.sla_methodology":         "Percentual de verificações bem-sucedidas dos serviços desta página. Manutenções anunciadas são excluídas.",
+		},
+	}
+)
+
+// RegisterStatusPageLocale adds or replaces the labels for a locale. Keys missing
+// from labels fall back to English, so partial translations are fine.
+func RegisterStatusPageLocale(locale string, labels map[string]string) {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	statusPageTranslationsMu.Lock()
+	defer statusPageTranslationsMu.Unlock()
+	statusPageTranslations[locale] = copied
+}
+
+// StatusPageLocales returns the registered locale codes, sorted.
+func StatusPageLocales() []string {
+	statusPageTranslationsMu.RLock()
+	defer statusPageTranslationsMu.RUnlock()
+	locales := make([]string, 0, len(statusPageTranslations))
+	for l := range statusPageTranslations {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func isStatusPageLocale(locale string) bool {
+	statusPageTranslationsMu.RLock()
+	defer statusPageTranslationsMu.RUnlock()
+	_, ok := statusPageTranslations[locale]
+	return ok
+}
+
+// statusPageLabels returns the full label set for a locale, filling gaps from English.
+// Unknown locales get English.
+func statusPageLabels(locale string) map[string]string {
+	statusPageTranslationsMu.RLock()
+	defer statusPageTranslationsMu.RUnlock()
+	labels := make(map[string]string, len(statusPageTranslations[DefaultStatusPageLocale]))
+	for k, v := range statusPageTranslations[DefaultStatusPageLocale] {
+		labels[k] = v
+	}
+	for k, v := range statusPageTranslations[locale] {
+		labels[k] = v
+	}
+	return labels
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusPageLabels_FallsBackToEnglish(t *testing.T) {
+	RegisterStatusPageLocale("xx-test", map[string]string{"overall.operational": "XX ok"})
+	t.Cleanup(func() {
+		statusPageTranslationsMu.Lock()
+		delete(statusPageTranslations, "xx-test")
+		statusPageTranslationsMu.Unlock()
+	})
+
+	labels := statusPageLabels("xx-test")
+	if labels["overall.operational"] != "XX ok" {
+		t.Errorf("expected translated label, got %q", labels["overall.operational"])
+	}
+	if labels["severity.critical"] != "Critical" {
+		t.Errorf("expected English fallback for missing key, got %q", labels["severity.critical"])
+	}
+	if got := statusPageLabels("nope")["severity.major"]; got != "Major" {
+		t.Errorf("unknown locale should use English, got %q", got)
+	}
+
+	// Every built-in translation covers the full English key set
+	for _, locale := range []string{"es", "fr", "de", "pt"} {
+		for key := range statusPageTranslations[DefaultStatusPageLocale] {
+			if _, ok := statusPageTranslations[locale][key]; !ok {
+				t.Errorf("locale %s is missing %s", locale, key)
+			}
+		}
+	}
+}
+
+func TestStatusPageLocale_SavedAndReturnedInPublicPayload(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "locale-page", "Locale Page", nil, true, true)
+
+	payload := map[string]interface{}{"public": true, "enabled": true, "title": "Locale Page", "locale": "de"}
+	w := httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/locale-page", "locale-page", payload))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (body: %s)", w.Code, w.Body.String())
+	}
+
+	// Locale is preserved when a later update omits it
+	payload = map[string]interface{}{"public": true, "enabled": true, "title": "Locale Page"}
+	w = httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/locale-page", "locale-page", payload))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	spH.GetPublicStatus(w, makeRequest("GET", "/api/s/locale-page", "locale-page", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	config := decodeJSON(t, w)["config"].(map[string]interface{})
+	if config["locale"] != "de" {
+		t.Errorf("Expected locale 'de', got %v", config["locale"])
+	}
+	labels := config["labels"].(map[string]interface{})
+	if labels["overall.operational"] != "Alle Systeme betriebsbereit" {
+		t.Errorf("Expected German label, got %v", labels["overall.operational"])
+	}
+}
+
+func TestStatusPageLocale_InvalidRejected(t *testing.T) {
+	store, spH := newStatusPageTestEnv(t)
+	seedPage(t, store, "locale-bad", "Locale Bad", nil, true, true)
+
+	payload := map[string]interface{}{"public": true, "enabled": true, "title": "Locale Bad", "locale": "klingon"}
+	w := httptest.NewRecorder()
+	spH.Toggle(w, makeRequest("PATCH", "/api/status-pages/locale-bad", "locale-bad", payload))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
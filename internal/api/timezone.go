@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// resolveLocation returns the timezone used to bucket stats into hours and days:
+// the tz query parameter if given, otherwise the signed-in user's stored timezone.
+// API keys and public pages fall back to the admin's timezone, then UTC.
+// ok is false when tz is not a valid IANA zone name.
+func resolveLocation(r *http.Request, store *db.Store) (*time.Location, bool) {
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, false
+		}
+		return loc, true
+	}
+
+	userID, ok := r.Context().Value(contextKeyUserID).(int64)
+	if !ok || userID == APIKeyUserID {
+		userID = 1
+	}
+	if user, err := store.GetUser(userID); err == nil && user.Timezone != "" {
+		if loc, err := time.LoadLocation(user.Timezone); err == nil {
+			return loc, true
+		}
+	}
+	return time.UTC, true
+}
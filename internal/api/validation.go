@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValidationErrorResponse is returned with 422 when fields of a request body are
+// invalid. It lists every invalid field, not just the first.
+type ValidationErrorResponse struct {
+	ErrorResponse
+	Fields map[string]string `json:"fields"` // JSON field name -> problem
+}
+
+// fieldError is a validation failure of one request field. Validators return it so
+// handlers can report the field it belongs to.
+type fieldError struct {
+	field   string
+	message string
+}
+
+func (e *fieldError) Error() string { return e.message }
+
+func newFieldError(field, format string, args ...any) error {
+	return &fieldError{field: field, message: fmt.Sprintf(format, args...)}
+}
+
+// validationErrors collects the invalid fields of a request, keyed by JSON field name.
+type validationErrors map[string]string
+
+// add records a problem with field, keeping the first one reported for it.
+func (v validationErrors) add(field, format string, args ...any) {
+	if _, ok := v[field]; !ok {
+		v[field] = fmt.Sprintf(format, args...)
+	}
+}
+
+// addErr records err under its own field when it is a fieldError, otherwise under field.
+// A nil err is ignored.
+func (v validationErrors) addErr(field string, err error) {
+	if err == nil {
+		return
+	}
+	var fe *fieldError
+	if errors.As(err, &fe) {
+		field = fe.field
+	}
+	v.add(field, "%s", err.Error())
+}
+
+// write sends the collected problems as a 422 response and reports whether there were any.
+func (v validationErrors) write(w http.ResponseWriter) bool {
+	if len(v) == 0 {
+		return false
+	}
+	fields := make([]string, 0, len(v))
+	for f := range v {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = v[f]
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+		ErrorResponse: errorBody(w, ErrCodeValidationFailed, strings.Join(messages, "; ")),
+		Fields:        v,
+	})
+	return true
+}
+
+// validateTitle checks the title of an incident or maintenance window.
+func validateTitle(errs validationErrors, title string) {
+	if strings.TrimSpace(title) == "" {
+		errs.add("title", "Title is required")
+		return
+	}
+	if len(title) > maxNameLength {
+		errs.add("title", "Title too long (max %d characters)", maxNameLength)
+	}
+}
+
+// parseTimeField parses an RFC 3339 timestamp, recording a problem with field when it
+// is missing or malformed.
+func parseTimeField(errs validationErrors, field, value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		errs.add(field, "Invalid %s, expected an RFC 3339 time", field)
+		return time.Time{}
+	}
+	return t.UTC()
+}
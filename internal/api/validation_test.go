@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// invalidFields checks for a 422 validation response and returns its invalid fields, sorted.
+func invalidFields(t *testing.T, rr *httptest.ResponseRecorder) []string {
+	t.Helper()
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp ValidationErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Code != ErrCodeValidationFailed || resp.Error == "" {
+		t.Errorf("unexpected envelope %+v", resp.ErrorResponse)
+	}
+	fields := make([]string, 0, len(resp.Fields))
+	for f, msg := range resp.Fields {
+		if msg == "" {
+			t.Errorf("field %q has no message", f)
+		}
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestValidationErrors_ListEveryField(t *testing.T) {
+	crudH, _, _, _, s := setupTest(t)
+	incidentH := NewIncidentHandler(s, uptime.NewManager(s))
+	maintenanceH := NewMaintenanceHandler(s, uptime.NewManager(s))
+	channelsH := NewNotificationChannelsHandler(s)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		payload map[string]interface{}
+		fields  []string
+	}{
+		{
+			name:    "monitor",
+			handler: crudH.CreateMonitor,
+			payload: map[string]interface{}{"name": strings.Repeat("n", 300), "url": "not a url", "groupId": "g-default", "interval": 5},
+			fields:  []string{"interval", "name", "url"},
+		},
+		{
+			name:    "group",
+			handler: crudH.CreateGroup,
+			payload: map[string]interface{}{"name": ""},
+			fields:  []string{"name"},
+		},
+		{
+			name:    "incident",
+			handler: incidentH.CreateIncident,
+			payload: map[string]interface{}{"title": "", "severity": "apocalyptic", "startTime": "yesterday"},
+			fields:  []string{"severity", "startTime", "title"},
+		},
+		{
+			name:    "maintenance",
+			handler: maintenanceH.CreateMaintenance,
+			payload: map[string]interface{}{"title": "Upgrade", "startTime": "2026-01-02T10:00:00Z", "endTime": "2026-01-02T09:00:00Z"},
+			fields:  []string{"endTime"},
+		},
+		{
+			name:    "channel",
+			handler: channelsH.CreateChannel,
+			payload: map[string]interface{}{"type": "webhook", "name": strings.Repeat("n", 300), "config": map[string]string{"webhookUrl": "ftp://example.com"}},
+			fields:  []string{"config.webhookUrl", "name"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(tc.payload)
+			rr := httptest.NewRecorder()
+			tc.handler(rr, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)))
+
+			if got := invalidFields(t, rr); !reflect.DeepEqual(got, tc.fields) {
+				t.Errorf("invalid fields = %v, want %v", got, tc.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"log"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// lastKnownChecks reports whether the manager is still loading monitors after a restart
+// and, if so, each monitor's last persisted check. Handlers report those for monitors
+// the manager has not loaded yet instead of calling them down.
+func lastKnownChecks(manager *uptime.Manager, store *db.Store) (map[string]db.CheckResult, bool) {
+	if manager.Hydrated() {
+		return nil, false
+	}
+	checks, err := store.GetLatestChecks()
+	if err != nil {
+		log.Printf("Failed to load last-known monitor statuses: %v", err)
+	}
+	return checks, true
+}
+
+// checkStatus maps a persisted check to a monitor status: up, degraded or down.
+func checkStatus(c db.CheckResult, meta db.Monitor, defaultThreshold int64) string {
+	if c.Status != "up" {
+		return "down"
+	}
+	threshold := defaultThreshold
+	if meta.LatencyThreshold != nil {
+		threshold = int64(*meta.LatencyThreshold)
+	}
+	if c.Latency > threshold || c.ErrorKind == db.ErrorKindHeaderMismatch {
+		return "degraded"
+	}
+	return "up"
+}
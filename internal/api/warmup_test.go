@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func TestGetHistory_WarmingUp(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	manager := uptime.NewManager(store)
+	h := NewUptimeHandler(manager, store)
+
+	slow := 100
+	for _, m := range []db.Monitor{
+		{ID: "m-up", Name: "Up"},
+		{ID: "m-slow", Name: "Slow", LatencyThreshold: &slow},
+		{ID: "m-down", Name: "Down"},
+		{ID: "m-new", Name: "New"},
+	} {
+		m.GroupID, m.Type, m.Active, m.Interval = "g-default", db.MonitorTypeExternal, true, 60
+		if err := store.CreateMonitor(m); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := store.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-up", Status: "down", Timestamp: now.Add(-2 * time.Minute)},
+		{MonitorID: "m-up", Status: "up", Latency: 20, Timestamp: now.Add(-time.Minute)},
+		{MonitorID: "m-slow", Status: "up", Latency: 250, Timestamp: now.Add(-time.Minute)},
+		{MonitorID: "m-down", Status: "down", Timestamp: now.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	get := func() UptimeResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.GetHistory(w, httptest.NewRequest("GET", "/api/uptime", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		var resp UptimeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	}
+	statuses := func(resp UptimeResponse) map[string]MonitorDTO {
+		byID := make(map[string]MonitorDTO)
+		for _, g := range resp.Groups {
+			for _, m := range g.Monitors {
+				byID[m.ID] = m
+			}
+		}
+		return byID
+	}
+
+	// Before the first sync, statuses come from the last persisted checks
+	resp := get()
+	if !resp.WarmingUp {
+		t.Error("Expected warmingUp before the first sync")
+	}
+	mons := statuses(resp)
+	want := map[string]string{"m-up": "up", "m-slow": "degraded", "m-down": "down", "m-new": "down"}
+	for id, status := range want {
+		if mons[id].Status != status {
+			t.Errorf("%s: expected status %q while warming up, got %q", id, status, mons[id].Status)
+		}
+	}
+	if mons["m-up"].Latency != 20 || mons["m-up"].LastCheck != now.Add(-time.Minute).Format(time.RFC3339) {
+		t.Errorf("Expected m-up's last check, got latency %d at %q", mons["m-up"].Latency, mons["m-up"].LastCheck)
+	}
+	if mons["m-new"].LastCheck != "" {
+		t.Errorf("Expected no last check for a monitor without checks, got %q", mons["m-new"].LastCheck)
+	}
+
+	// Once hydrated, the live monitors answer
+	manager.Sync()
+	resp = get()
+	if resp.WarmingUp {
+		t.Error("Expected warmingUp to clear after the first sync")
+	}
+	mons = statuses(resp)
+	if mons["m-up"].Status != "up" || len(mons["m-up"].History) != 2 {
+		t.Errorf("Expected m-up's hydrated history, got %+v", mons["m-up"])
+	}
+}
@@ -1,8 +1,19 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+)
+
+// Bounds of MONITOR_HISTORY_SIZE
+const (
+	MinHistorySize = 50
+	MaxHistorySize = 1000
 )
 
 // Database types
@@ -12,13 +23,27 @@ const (
 )
 
 type Config struct {
-	ListenAddr   string
-	DBType       string // "sqlite" or "postgres"
-	DBPath       string // SQLite file path (only used when DBType is "sqlite")
-	DBURL        string // PostgreSQL connection URL (only used when DBType is "postgres")
-	CookieSecure bool
-	AdminSecret  string
-	TrustProxy   bool // Trust X-Forwarded-For headers (only enable behind a trusted reverse proxy)
+	ListenAddr          string
+	DBType              string // "sqlite" or "postgres"
+	DBPath              string // SQLite file path (only used when DBType is "sqlite")
+	DBURL               string // PostgreSQL connection URL (only used when DBType is "postgres")
+	ChecksDBURL         string // Separate database for raw check results; empty keeps them in the main database
+	CookieSecure        bool
+	AdminSecret         string
+	TrustProxy          bool   // Trust X-Forwarded-For headers (only enable behind a trusted reverse proxy)
+	HAMode              bool   // Run as one of several instances sharing a PostgreSQL database
+	SpoolPath           string // Local file holding check results the database failed to accept
+	BlockPrivateTargets bool   // Refuse monitors pointing at loopback, private or link-local addresses
+	HistorySize         int    // Recent checks each monitor keeps in memory for heartbeat bars
+	ReadOnly            bool   // Reject API writes, e.g. on a reporting replica
+	Demo                bool   // Seeded in-memory demo (--demo); checks never leave the process
+	AdminUser           string // Admin account created on first start instead of through the setup page
+	AdminPassword       string
+	AdminTimezone       string
+	SetupToken          string // Required by POST /api/setup while setup is pending
+	ReusePort           bool   // Bind with SO_REUSEPORT so a new process can take over the port
+	DockerDiscovery     bool   // Manage monitors for containers labelled warden.enable=true
+	DockerHost          string // Docker daemon Docker discovery connects to
 }
 
 func Default() Config {
@@ -27,6 +52,8 @@ func Default() Config {
 		DBType:       DBTypeSQLite,
 		DBPath:       "warden.db",
 		CookieSecure: false,
+		HistorySize:  MinHistorySize,
+		DockerHost:   "unix:///var/run/docker.sock",
 	}
 }
 
@@ -58,6 +85,20 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// CHECKS_DB_URL: Keep raw check results in a separate time-series database, such as
+	// postgres://... for PostgreSQL or TimescaleDB, while monitors and settings stay in the
+	// main database. Other backends can be added with db.RegisterCheckStore.
+	if checksURL := os.Getenv("CHECKS_DB_URL"); checksURL != "" {
+		cfg.ChecksDBURL = checksURL
+	}
+
+	// CHECK_SPOOL_PATH: Where check results are kept while the database rejects writes
+	// (locked, disk full, unreachable). Defaults to a file next to the SQLite database.
+	cfg.SpoolPath = filepath.Join(filepath.Dir(cfg.DBPath), "warden-checks.spool")
+	if spoolPath := os.Getenv("CHECK_SPOOL_PATH"); spoolPath != "" {
+		cfg.SpoolPath = spoolPath
+	}
+
 	if os.Getenv("COOKIE_SECURE") == "true" {
 		cfg.CookieSecure = true
 	}
@@ -74,5 +115,92 @@ func Load() (*Config, error) {
 		cfg.TrustProxy = true
 	}
 
+	// HA_MODE: Run several instances against one PostgreSQL database. All of them serve
+	// the API; a database advisory lock elects the single instance that schedules checks
+	// and sends notifications, and a standby takes over if the leader goes away.
+	if os.Getenv("HA_MODE") == "true" {
+		cfg.HAMode = true
+		if cfg.DBType != DBTypePostgres {
+			return nil, errors.New("HA_MODE requires DB_TYPE=postgres")
+		}
+	}
+
+	// BLOCK_PRIVATE_TARGETS: Refuse monitor URLs that point at loopback, private, link-local
+	// (including cloud metadata) or carrier-grade NAT addresses, so users of a shared instance
+	// can't use it to probe the server's internal network. Off by default because most installs
+	// monitor internal services on purpose.
+	if os.Getenv("BLOCK_PRIVATE_TARGETS") == "true" {
+		cfg.BlockPrivateTargets = true
+	}
+
+	// READ_ONLY: Reject every API write with 503 for the life of the process, e.g. when
+	// pointed at a read replica for reporting. For temporary maintenance, the
+	// read_only.enabled setting does the same and can be switched back off.
+	if os.Getenv("READ_ONLY") == "true" {
+		cfg.ReadOnly = true
+	}
+
+	// REUSE_PORT: Open the listening socket with SO_REUSEPORT, so a new Warden process can
+	// bind the port before the old one stops, which then finishes its requests and
+	// running checks. Not needed under systemd socket activation, which hands the same
+	// socket to every restart.
+	if os.Getenv("REUSE_PORT") == "true" {
+		cfg.ReusePort = true
+	}
+
+	// WARDEN_ADMIN_USER / WARDEN_ADMIN_PASSWORD: Create the admin account on first start, so
+	// Docker and Kubernetes deployments are provisioned without the setup page. They are
+	// ignored once setup is complete and can stay set. WARDEN_ADMIN_PASSWORD_FILE reads the
+	// password from a file instead, such as a mounted secret; WARDEN_ADMIN_TIMEZONE sets the
+	// account's timezone (default UTC).
+	cfg.AdminUser = strings.TrimSpace(os.Getenv("WARDEN_ADMIN_USER"))
+	cfg.AdminPassword = os.Getenv("WARDEN_ADMIN_PASSWORD")
+	if path := os.Getenv("WARDEN_ADMIN_PASSWORD_FILE"); path != "" {
+		if cfg.AdminPassword != "" {
+			return nil, errors.New("set WARDEN_ADMIN_PASSWORD or WARDEN_ADMIN_PASSWORD_FILE, not both")
+		}
+		b, err := os.ReadFile(path) // #nosec G304 -- path comes from the operator
+		if err != nil {
+			return nil, fmt.Errorf("read WARDEN_ADMIN_PASSWORD_FILE: %w", err)
+		}
+		cfg.AdminPassword = strings.TrimRight(string(b), "\r\n")
+	}
+	if (cfg.AdminUser == "") != (cfg.AdminPassword == "") {
+		return nil, errors.New("WARDEN_ADMIN_USER and WARDEN_ADMIN_PASSWORD must be set together")
+	}
+	cfg.AdminTimezone = "UTC"
+	if tz := os.Getenv("WARDEN_ADMIN_TIMEZONE"); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("WARDEN_ADMIN_TIMEZONE: %w", err)
+		}
+		cfg.AdminTimezone = tz
+	}
+
+	// WARDEN_SETUP_TOKEN: Require this token to complete setup through POST /api/setup, so a
+	// fresh instance reachable from the network can't be claimed by whoever finds it first.
+	cfg.SetupToken = os.Getenv("WARDEN_SETUP_TOKEN")
+
+	// DOCKER_DISCOVERY: Create, update and delete monitors for the containers of the Docker
+	// daemon labelled warden.enable=true, e.g. a homelab's compose stacks. DOCKER_HOST is
+	// the daemon to ask, unix:///var/run/docker.sock by default; mount the socket read-only
+	// or point it at a socket proxy (tcp://host:2375) that only allows listing containers.
+	if os.Getenv("DOCKER_DISCOVERY") == "true" {
+		cfg.DockerDiscovery = true
+	}
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		cfg.DockerHost = host
+	}
+
+	// MONITOR_HISTORY_SIZE: Recent checks each monitor keeps in memory, and so the most
+	// heartbeat bars GET /api/uptime?history=N can return. Raise it for wide dashboards;
+	// memory grows with monitors × size.
+	if size := os.Getenv("MONITOR_HISTORY_SIZE"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < MinHistorySize || n > MaxHistorySize {
+			return nil, errors.New("MONITOR_HISTORY_SIZE must be a number between 50 and 1000")
+		}
+		cfg.HistorySize = n
+	}
+
 	return &cfg, nil
 }
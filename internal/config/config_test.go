@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -48,3 +49,84 @@ func TestLoad(t *testing.T) {
 		}
 	})
 }
+
+func TestLoad_HAMode(t *testing.T) {
+	t.Setenv("HA_MODE", "true")
+
+	t.Run("Requires Postgres", func(t *testing.T) {
+		t.Setenv("DB_TYPE", "sqlite")
+		if _, err := Load(); err == nil {
+			t.Fatal("Expected HA_MODE with sqlite to fail")
+		}
+	})
+
+	t.Run("Postgres", func(t *testing.T) {
+		t.Setenv("DB_TYPE", "")
+		t.Setenv("DB_URL", "postgres://localhost/warden")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if !cfg.HAMode {
+			t.Error("Expected HAMode to be enabled")
+		}
+	})
+}
+
+func TestLoad_HistorySize(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.HistorySize != 50 {
+		t.Errorf("Expected default history size 50, got %d", cfg.HistorySize)
+	}
+
+	t.Setenv("MONITOR_HISTORY_SIZE", "200")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.HistorySize != 200 {
+		t.Errorf("Expected history size 200, got %d", cfg.HistorySize)
+	}
+
+	for _, invalid := range []string{"10", "5000", "many"} {
+		t.Setenv("MONITOR_HISTORY_SIZE", invalid)
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected MONITOR_HISTORY_SIZE=%s to fail", invalid)
+		}
+	}
+}
+
+func TestLoad_AdminBootstrap(t *testing.T) {
+	t.Setenv("WARDEN_ADMIN_USER", "ops")
+	if _, err := Load(); err == nil {
+		t.Error("Expected WARDEN_ADMIN_USER without a password to fail")
+	}
+
+	secret := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secret, []byte("s3cret-pass!\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WARDEN_ADMIN_PASSWORD_FILE", secret)
+	t.Setenv("WARDEN_ADMIN_TIMEZONE", "Europe/Berlin")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.AdminUser != "ops" || cfg.AdminPassword != "s3cret-pass!" || cfg.AdminTimezone != "Europe/Berlin" {
+		t.Errorf("Expected the admin account from the environment, got %q %q %q", cfg.AdminUser, cfg.AdminPassword, cfg.AdminTimezone)
+	}
+
+	t.Setenv("WARDEN_ADMIN_PASSWORD", "other1!pass")
+	if _, err := Load(); err == nil {
+		t.Error("Expected both WARDEN_ADMIN_PASSWORD and WARDEN_ADMIN_PASSWORD_FILE to fail")
+	}
+
+	t.Setenv("WARDEN_ADMIN_PASSWORD_FILE", "")
+	t.Setenv("WARDEN_ADMIN_TIMEZONE", "Mars/Olympus")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an unknown WARDEN_ADMIN_TIMEZONE to fail")
+	}
+}
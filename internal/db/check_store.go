@@ -0,0 +1,165 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckStore holds raw check results. By default they live in the monitor_checks table
+// of the main database; installs with many monitors can point them at a separate
+// time-series database with SetCheckStore, while monitors, outages and everything else
+// stay in the main database.
+//
+// Implementations must be safe for concurrent use. Checks are kept per monitor ID with
+// no reference to the monitors table, so a store is told about deleted monitors through
+// DeleteChecks.
+type CheckStore interface {
+	// InsertChecks stores a batch of check results.
+	InsertChecks(checks []CheckResult) error
+	// RecentChecks returns up to perMonitor of the newest checks of each monitor, newest
+	// first, keyed by monitor ID. Monitors without checks are absent.
+	RecentChecks(monitorIDs []string, perMonitor int) (map[string][]CheckResult, error)
+	// LatestChecks returns the newest check of every monitor, keyed by monitor ID.
+	LatestChecks() (map[string]CheckResult, error)
+	// ScanChecks calls fn for each of the monitor's checks since the given time, in no
+	// particular order, stopping at the first error.
+	ScanChecks(monitorID string, since time.Time, fn func(CheckResult) error) error
+	// CountChecks returns the number of the monitor's checks in [since, until) and how
+	// many of them were up, leaving out checks inside the excluded windows. A zero until
+	// leaves the range open.
+	CountChecks(monitorID string, since, until time.Time, exclude []TimeWindow) (total, up int, err error)
+	// CheckSlots aggregates the monitor's checks since the given time into UTC slots of
+	// the given width, a whole number of seconds. Slots without checks are absent.
+	CheckSlots(monitorID string, since time.Time, width time.Duration) ([]CheckSlot, error)
+	// RegionSummaries summarizes the monitor's checks since the given time per region,
+	// as GetRegionSummaries documents.
+	RegionSummaries(monitorID string, since time.Time) ([]RegionSummary, error)
+	// ErrorKindCounts returns the number of down checks per error kind since the given time.
+	ErrorKindCounts(monitorID string, since time.Time) (map[string]int, error)
+	// AverageLatencies returns the mean latency per monitor since the given time.
+	AverageLatencies(since time.Time) (map[string]float64, error)
+	// DeleteChecks removes all checks of the given monitors.
+	DeleteChecks(monitorIDs []string) error
+	// PruneChecks removes all checks older than before.
+	PruneChecks(before time.Time) error
+	// Close releases the store's connections.
+	Close() error
+}
+
+// CheckSlot aggregates the checks of one time slot.
+type CheckSlot struct {
+	Start      time.Time
+	Checks     int64
+	Up         int64
+	LatencySum int64
+	// TimingSum adds up the timing of the Timed checks that recorded one; nil if none did.
+	TimingSum *CheckTiming
+	Timed     int64
+}
+
+// CheckStoreOpener opens a check store from its URL.
+type CheckStoreOpener func(url string) (CheckStore, error)
+
+var (
+	checkStoresMu sync.RWMutex
+	checkStores   = make(map[string]CheckStoreOpener)
+)
+
+// RegisterCheckStore makes a check store available to OpenCheckStore under a URL scheme,
+// such as "clickhouse" for "clickhouse://host/db". It is meant to be called from the
+// init function of the package implementing the store, and panics if the scheme is
+// already taken.
+func RegisterCheckStore(scheme string, open CheckStoreOpener) {
+	checkStoresMu.Lock()
+	defer checkStoresMu.Unlock()
+	if open == nil {
+		panic("db: RegisterCheckStore opener is nil")
+	}
+	if _, dup := checkStores[scheme]; dup {
+		panic("db: RegisterCheckStore called twice for scheme " + scheme)
+	}
+	checkStores[scheme] = open
+}
+
+// OpenCheckStore opens the check store registered for the scheme of rawURL. PostgreSQL
+// (including TimescaleDB) and SQLite are built in, as "postgres://..." and
+// "sqlite:///path/to/checks.db".
+func OpenCheckStore(rawURL string) (CheckStore, error) {
+	scheme, _, ok := strings.Cut(rawURL, "://")
+	if !ok || scheme == "" {
+		return nil, fmt.Errorf("check store URL must start with a scheme, such as postgres://")
+	}
+	checkStoresMu.RLock()
+	open, ok := checkStores[strings.ToLower(scheme)]
+	checkStoresMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown check store %q (available: %s)", scheme, strings.Join(CheckStoreSchemes(), ", "))
+	}
+	return open(rawURL)
+}
+
+// CheckStoreSchemes returns the registered check store schemes, sorted.
+func CheckStoreSchemes() []string {
+	checkStoresMu.RLock()
+	defer checkStoresMu.RUnlock()
+	schemes := make([]string, 0, len(checkStores))
+	for s := range checkStores {
+		schemes = append(schemes, s)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+func init() {
+	openPostgres := func(url string) (CheckStore, error) {
+		return openSQLCheckStore(DialectPostgres, url)
+	}
+	RegisterCheckStore("postgres", openPostgres)
+	RegisterCheckStore("postgresql", openPostgres)
+	RegisterCheckStore("sqlite", func(url string) (CheckStore, error) {
+		return openSQLCheckStore(DialectSQLite, strings.TrimPrefix(url, "sqlite://"))
+	})
+}
+
+// SetCheckStore moves check results to cs, which the store closes with itself. Checks
+// already in the main database are not moved and are no longer read.
+func (s *Store) SetCheckStore(cs CheckStore) {
+	s.checks = cs
+	s.externalChecks = true
+}
+
+// dropChecks removes the checks of deleted monitors from an external check store. The
+// built-in table loses them through its foreign key to monitors.
+func (s *Store) dropChecks(ids ...string) error {
+	if !s.externalChecks || len(ids) == 0 {
+		return nil
+	}
+	return s.checks.DeleteChecks(ids)
+}
+
+// monitorIDs returns the IDs of the monitors matching where, or of all monitors if
+// where is empty.
+func (s *Store) monitorIDs(where string, args ...any) ([]string, error) {
+	query := "SELECT id FROM monitors"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
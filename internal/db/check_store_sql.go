@@ -0,0 +1,411 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// checkColumns are the monitor_checks columns read into a CheckResult, in field order.
+const checkColumns = "monitor_id, status, latency, timestamp, COALESCE(status_code, 0), COALESCE(error_kind, ''), COALESCE(region, ''), " + timingColumns
+
+// recentChecksBatch caps the monitor IDs per RecentChecks or DeleteChecks query, well
+// below the bind parameter limits of both databases.
+const recentChecksBatch = 500
+
+// sqlCheckStore keeps checks in a monitor_checks table: the one in the main database
+// by default, or the same table in a database of its own.
+type sqlCheckStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// checkStoreSchema creates the table of a standalone check store. Unlike the main
+// database's table it has neither an id column nor a foreign key to monitors, so it can
+// be turned into a TimescaleDB hypertable on timestamp.
+var checkStoreSchema = map[string][]string{
+	DialectPostgres: {
+		`CREATE TABLE IF NOT EXISTS monitor_checks (
+			monitor_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			latency INTEGER NOT NULL,
+			status_code INTEGER DEFAULT 0,
+			timestamp TIMESTAMP NOT NULL,
+			error_kind TEXT DEFAULT NULL,
+			region TEXT DEFAULT NULL,
+			dns_ms INTEGER DEFAULT NULL,
+			connect_ms INTEGER DEFAULT NULL,
+			tls_ms INTEGER DEFAULT NULL,
+			ttfb_ms INTEGER DEFAULT NULL,
+			download_ms INTEGER DEFAULT NULL,
+			body_bytes INTEGER DEFAULT NULL
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_monitor_checks_monitor_id_ts ON monitor_checks(monitor_id, timestamp DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_monitor_checks_ts ON monitor_checks(timestamp)",
+	},
+	DialectSQLite: {
+		`CREATE TABLE IF NOT EXISTS monitor_checks (
+			monitor_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			latency INTEGER NOT NULL,
+			status_code INTEGER DEFAULT 0,
+			timestamp DATETIME NOT NULL,
+			error_kind TEXT DEFAULT NULL,
+			region TEXT DEFAULT NULL,
+			dns_ms INTEGER DEFAULT NULL,
+			connect_ms INTEGER DEFAULT NULL,
+			tls_ms INTEGER DEFAULT NULL,
+			ttfb_ms INTEGER DEFAULT NULL,
+			download_ms INTEGER DEFAULT NULL,
+			body_bytes INTEGER DEFAULT NULL
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_monitor_checks_monitor_id_ts ON monitor_checks(monitor_id, timestamp DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_monitor_checks_ts ON monitor_checks(timestamp)",
+	},
+}
+
+// openSQLCheckStore opens a standalone check store and creates its table.
+func openSQLCheckStore(dialect, dsn string) (*sqlCheckStore, error) {
+	driver := "sqlite3"
+	if dialect == DialectPostgres {
+		driver = "postgres"
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open check store: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping check store: %w", err)
+	}
+	if dialect == DialectSQLite {
+		db.SetMaxOpenConns(1)
+	}
+	for _, stmt := range checkStoreSchema[dialect] {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to create check store schema: %w", err)
+		}
+	}
+	return &sqlCheckStore{db: db, dialect: dialect}, nil
+}
+
+func (c *sqlCheckStore) rebind(query string) string {
+	return rebindQuery(c.dialect, query)
+}
+
+// timeRange returns the expressions comparing timestamp with a bound and converts the
+// bound. SQLite stores timestamps as text, so both sides go through datetime().
+func (c *sqlCheckStore) timeRange() (tsExpr, argExpr string, arg func(time.Time) any) {
+	if c.dialect == DialectPostgres {
+		return "timestamp", "?", func(t time.Time) any { return t.UTC() }
+	}
+	return "datetime(timestamp)", "datetime(?)", func(t time.Time) any { return t.UTC().Format("2006-01-02 15:04:05") }
+}
+
+func scanCheck(row rowScanner) (CheckResult, error) {
+	var c CheckResult
+	var timing nullTiming
+	if err := row.Scan(append([]any{&c.MonitorID, &c.Status, &c.Latency, &c.Timestamp, &c.StatusCode, &c.ErrorKind, &c.Region}, timing.dest()...)...); err != nil {
+		return c, err
+	}
+	c.Timing = timing.timing(1)
+	return c, nil
+}
+
+func (c *sqlCheckStore) InsertChecks(checks []CheckResult) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(c.rebind("INSERT INTO monitor_checks (monitor_id, status, latency, timestamp, status_code, error_kind, region, " + timingColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, ch := range checks {
+		args := append([]any{ch.MonitorID, ch.Status, ch.Latency, ch.Timestamp, ch.StatusCode, nullableErrorKind(ch.ErrorKind), nullableRegion(ch.Region)}, timingArgs(ch.Timing)...)
+		if _, err := stmt.Exec(args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *sqlCheckStore) RecentChecks(monitorIDs []string, perMonitor int) (map[string][]CheckResult, error) {
+	checks := make(map[string][]CheckResult, len(monitorIDs))
+	for start := 0; start < len(monitorIDs); start += recentChecksBatch {
+		batch := monitorIDs[start:min(start+recentChecksBatch, len(monitorIDs))]
+		if err := c.loadRecentChecks(checks, batch, perMonitor); err != nil {
+			return nil, err
+		}
+	}
+	return checks, nil
+}
+
+// loadRecentChecks adds the newest checks of a batch of monitors to checks. Each
+// monitor's checks are read through the (monitor_id, timestamp) index, stopping at the
+// limit: a LATERAL join on PostgreSQL, a correlated subquery on SQLite.
+func (c *sqlCheckStore) loadRecentChecks(checks map[string][]CheckResult, ids []string, limit int) error {
+	with := "WITH m(id) AS (VALUES (CAST(? AS TEXT))" + strings.Repeat(", (CAST(? AS TEXT))", len(ids)-1) + ") "
+	var query string
+	if c.dialect == DialectPostgres {
+		query = with + `SELECT ` + checkColumns + ` FROM m
+			CROSS JOIN LATERAL (SELECT * FROM monitor_checks WHERE monitor_id = m.id ORDER BY timestamp DESC LIMIT ?) c
+			ORDER BY monitor_id, timestamp DESC`
+	} else {
+		// rowid is also the id column of the main database's table
+		query = with + `SELECT ` + checkColumns + ` FROM m
+			JOIN monitor_checks c ON c.rowid IN (SELECT rowid FROM monitor_checks WHERE monitor_id = m.id ORDER BY timestamp DESC LIMIT ?)
+			ORDER BY monitor_id, timestamp DESC`
+	}
+	args := make([]any, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, limit)
+
+	rows, err := c.db.Query(c.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		ch, err := scanCheck(rows)
+		if err != nil {
+			return err
+		}
+		checks[ch.MonitorID] = append(checks[ch.MonitorID], ch)
+	}
+	return rows.Err()
+}
+
+func (c *sqlCheckStore) LatestChecks() (map[string]CheckResult, error) {
+	rows, err := c.db.Query(`SELECT c.monitor_id, c.status, c.latency, c.timestamp, COALESCE(c.status_code, 0), COALESCE(c.error_kind, ''), COALESCE(c.region, '') FROM monitor_checks c
+			  JOIN (SELECT monitor_id, MAX(timestamp) AS latest FROM monitor_checks GROUP BY monitor_id) l
+			  ON l.monitor_id = c.monitor_id AND l.latest = c.timestamp`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	checks := make(map[string]CheckResult)
+	for rows.Next() {
+		var ch CheckResult
+		if err := rows.Scan(&ch.MonitorID, &ch.Status, &ch.Latency, &ch.Timestamp, &ch.StatusCode, &ch.ErrorKind, &ch.Region); err != nil {
+			return nil, err
+		}
+		checks[ch.MonitorID] = ch
+	}
+	return checks, rows.Err()
+}
+
+func (c *sqlCheckStore) ScanChecks(monitorID string, since time.Time, fn func(CheckResult) error) error {
+	rows, err := c.db.Query(c.rebind(`SELECT `+checkColumns+` FROM monitor_checks
+		WHERE monitor_id = ? AND timestamp >= ?`), monitorID, since.UTC())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		ch, err := scanCheck(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(ch); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (c *sqlCheckStore) CountChecks(monitorID string, since, until time.Time, exclude []TimeWindow) (total, up int, err error) {
+	tsExpr, argExpr, arg := c.timeRange()
+
+	var b strings.Builder
+	b.WriteString("SELECT COUNT(*), COALESCE(SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END), 0) FROM monitor_checks WHERE monitor_id = ? AND ")
+	b.WriteString(tsExpr + " >= " + argExpr)
+	args := []any{monitorID, arg(since)}
+	if !until.IsZero() {
+		b.WriteString(" AND " + tsExpr + " < " + argExpr)
+		args = append(args, arg(until))
+	}
+	for _, w := range exclude {
+		b.WriteString(" AND NOT (" + tsExpr + " >= " + argExpr + " AND " + tsExpr + " < " + argExpr + ")")
+		args = append(args, arg(w.Start), arg(w.End))
+	}
+
+	err = c.db.QueryRow(c.rebind(b.String()), args...).Scan(&total, &up)
+	return total, up, err
+}
+
+func (c *sqlCheckStore) CheckSlots(monitorID string, since time.Time, width time.Duration) ([]CheckSlot, error) {
+	seconds := int64(width / time.Second)
+	if seconds < 1 || width%time.Second != 0 {
+		return nil, fmt.Errorf("invalid slot width %s: must be a whole number of seconds", width)
+	}
+	slot := fmt.Sprintf("CAST(strftime('%%s', timestamp) AS INTEGER) / %d * %d", seconds, seconds)
+	if c.dialect == DialectPostgres {
+		slot = fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM timestamp) / %d) * %d AS BIGINT)", seconds, seconds)
+	}
+	tsExpr, argExpr, arg := c.timeRange()
+
+	rows, err := c.db.Query(c.rebind(`
+		SELECT
+			`+slot+` AS slot,
+			COUNT(*),
+			SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END),
+			SUM(latency),
+			SUM(dns_ms), SUM(connect_ms), SUM(tls_ms), SUM(ttfb_ms), SUM(download_ms), SUM(body_bytes),
+			COUNT(ttfb_ms)
+		FROM monitor_checks
+		WHERE monitor_id = ? AND `+tsExpr+` >= `+argExpr+`
+		GROUP BY slot
+		ORDER BY slot
+	`), monitorID, arg(since))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var slots []CheckSlot
+	for rows.Next() {
+		var s CheckSlot
+		var start int64
+		var timing nullTiming
+		if err := rows.Scan(append(append([]any{&start, &s.Checks, &s.Up, &s.LatencySum}, timing.dest()...), &s.Timed)...); err != nil {
+			return nil, err
+		}
+		s.Start = time.Unix(start, 0).UTC()
+		s.TimingSum = timing.timing(1)
+		slots = append(slots, s)
+	}
+	return slots, rows.Err()
+}
+
+func (c *sqlCheckStore) RegionSummaries(monitorID string, since time.Time) ([]RegionSummary, error) {
+	rows, err := c.db.Query(c.rebind(`
+		SELECT COALESCE(region, ''), COUNT(*), SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END)
+		FROM monitor_checks
+		WHERE monitor_id = ? AND timestamp >= ?
+		GROUP BY COALESCE(region, '')
+		ORDER BY COALESCE(region, '') ASC
+	`), monitorID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	var summaries []RegionSummary
+	for rows.Next() {
+		var r RegionSummary
+		if err := rows.Scan(&r.Region, &r.Checks, &r.UpChecks); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		if r.Checks > 0 {
+			r.Uptime = float64(r.UpChecks) / float64(r.Checks) * 100
+		}
+		summaries = append(summaries, r)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		r := &summaries[i]
+		if err := c.db.QueryRow(c.rebind(`
+			SELECT status, latency, timestamp FROM monitor_checks
+			WHERE monitor_id = ? AND COALESCE(region, '') = ?
+			ORDER BY timestamp DESC LIMIT 1
+		`), monitorID, r.Region).Scan(&r.Status, &r.Latency, &r.LastCheck); err != nil {
+			return nil, err
+		}
+		if r.Region == "" {
+			r.Region = LocalCheckRegion
+		}
+	}
+	return summaries, nil
+}
+
+func (c *sqlCheckStore) ErrorKindCounts(monitorID string, since time.Time) (map[string]int, error) {
+	rows, err := c.db.Query(c.rebind(`
+		SELECT error_kind, COUNT(*)
+		FROM monitor_checks
+		WHERE monitor_id = ? AND status = 'down' AND error_kind IS NOT NULL AND timestamp >= ?
+		GROUP BY error_kind
+	`), monitorID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var kind string
+		var n int
+		if err := rows.Scan(&kind, &n); err != nil {
+			return nil, err
+		}
+		counts[kind] = n
+	}
+	return counts, rows.Err()
+}
+
+func (c *sqlCheckStore) AverageLatencies(since time.Time) (map[string]float64, error) {
+	rows, err := c.db.Query(c.rebind(`
+		SELECT monitor_id, AVG(latency)
+		FROM monitor_checks
+		WHERE timestamp >= ?
+		GROUP BY monitor_id
+	`), since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	avg := make(map[string]float64)
+	for rows.Next() {
+		var id string
+		var latency float64
+		if err := rows.Scan(&id, &latency); err != nil {
+			return nil, err
+		}
+		avg[id] = latency
+	}
+	return avg, rows.Err()
+}
+
+func (c *sqlCheckStore) DeleteChecks(monitorIDs []string) error {
+	for start := 0; start < len(monitorIDs); start += recentChecksBatch {
+		batch := monitorIDs[start:min(start+recentChecksBatch, len(monitorIDs))]
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		in := "?" + strings.Repeat(", ?", len(batch)-1)
+		if _, err := c.db.Exec(c.rebind("DELETE FROM monitor_checks WHERE monitor_id IN ("+in+")"), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *sqlCheckStore) PruneChecks(before time.Time) error {
+	tsExpr, argExpr, arg := c.timeRange()
+	_, err := c.db.Exec(c.rebind("DELETE FROM monitor_checks WHERE "+tsExpr+" < "+argExpr), arg(before))
+	return err
+}
+
+func (c *sqlCheckStore) Close() error {
+	return c.db.Close()
+}
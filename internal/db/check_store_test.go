@@ -0,0 +1,139 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckStore_External(t *testing.T) {
+	s := newTestStore(t)
+	checks, err := OpenCheckStore("sqlite://" + filepath.Join(t.TempDir(), "checks.db"))
+	if err != nil {
+		t.Fatalf("OpenCheckStore failed: %v", err)
+	}
+	s.SetCheckStore(checks)
+	t.Cleanup(func() { _ = s.Close() })
+
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	for _, id := range []string{"m1", "m2"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	batch := []CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: now.Add(-3 * time.Minute), Timing: &CheckTiming{TTFB: 40}},
+		{MonitorID: "m1", Status: "up", Latency: 200, Timestamp: now.Add(-2 * time.Minute), Timing: &CheckTiming{TTFB: 80}},
+		{MonitorID: "m1", Status: "down", Latency: 0, Timestamp: now.Add(-1 * time.Minute), ErrorKind: ErrorKindTimeout, Region: "eu-west"},
+		{MonitorID: "m2", Status: "up", Latency: 50, Timestamp: now.Add(-1 * time.Minute)},
+	}
+	if err := s.BatchInsertChecks(batch); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	var inMain int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM monitor_checks").Scan(&inMain); err != nil {
+		t.Fatalf("count main checks: %v", err)
+	}
+	if inMain != 0 {
+		t.Errorf("Expected no checks in the main database, got %d", inMain)
+	}
+
+	recent, err := s.GetMonitorChecks("m1", 2)
+	if err != nil {
+		t.Fatalf("GetMonitorChecks failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Status != "down" || recent[1].Latency != 200 || recent[1].Timing == nil || recent[1].Timing.TTFB != 80 {
+		t.Errorf("Unexpected recent checks: %+v", recent)
+	}
+
+	total, up, err := s.GetUptimeCounts("m1", now.Add(-time.Hour), nil)
+	if err != nil {
+		t.Fatalf("GetUptimeCounts failed: %v", err)
+	}
+	if total != 3 || up != 2 {
+		t.Errorf("Expected 2 of 3 checks up, got %d of %d", up, total)
+	}
+
+	points, err := s.GetLatencyStats("m1", 1)
+	if err != nil {
+		t.Fatalf("GetLatencyStats failed: %v", err)
+	}
+	if len(points) != 3 || points[0].Latency != 100 || points[0].Timing == nil || points[0].Timing.TTFB != 40 || !points[2].Failed {
+		t.Errorf("Unexpected latency points: %+v", points)
+	}
+
+	daily, err := s.GetDailyUptimeStats("m1", 1)
+	if err != nil {
+		t.Fatalf("GetDailyUptimeStats failed: %v", err)
+	}
+	// The checks may straddle midnight UTC
+	if daily[0].Total == 0 && now.Add(-3*time.Minute).Day() == now.Day() {
+		t.Errorf("Expected today's checks in daily stats, got %+v", daily)
+	}
+
+	regions, err := s.GetRegionSummaries("m1", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetRegionSummaries failed: %v", err)
+	}
+	if len(regions) != 2 || regions[0].Region != LocalCheckRegion || regions[1].Region != "eu-west" {
+		t.Errorf("Unexpected region summaries: %+v", regions)
+	}
+
+	kinds, err := s.GetErrorKindCounts("m1", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetErrorKindCounts failed: %v", err)
+	}
+	if kinds[ErrorKindTimeout] != 1 {
+		t.Errorf("Expected 1 timeout, got %v", kinds)
+	}
+
+	// Deleting a monitor drops its checks from the external store
+	if err := s.DeleteMonitor("m1"); err != nil {
+		t.Fatalf("DeleteMonitor failed: %v", err)
+	}
+	latest, err := s.GetLatestChecks()
+	if err != nil {
+		t.Fatalf("GetLatestChecks failed: %v", err)
+	}
+	if _, ok := latest["m1"]; ok || latest["m2"].Latency != 50 {
+		t.Errorf("Expected only m2's check after deleting m1, got %+v", latest)
+	}
+
+	// So does deleting a group
+	if err := s.DeleteGroup("g1"); err != nil {
+		t.Fatalf("DeleteGroup failed: %v", err)
+	}
+	if latest, _ := s.GetLatestChecks(); len(latest) != 0 {
+		t.Errorf("Expected no checks after deleting the group, got %+v", latest)
+	}
+}
+
+type fakeCheckStore struct {
+	CheckStore
+	url string
+}
+
+func TestOpenCheckStore(t *testing.T) {
+	RegisterCheckStore("fake-test", func(url string) (CheckStore, error) {
+		return &fakeCheckStore{url: url}, nil
+	})
+
+	cs, err := OpenCheckStore("fake-test://host/db")
+	if err != nil {
+		t.Fatalf("OpenCheckStore failed: %v", err)
+	}
+	if f, ok := cs.(*fakeCheckStore); !ok || f.url != "fake-test://host/db" {
+		t.Errorf("Expected the registered store to be opened with the URL, got %+v", cs)
+	}
+
+	if _, err := OpenCheckStore("nope://host"); err == nil || !strings.Contains(err.Error(), "postgres") {
+		t.Errorf("Expected an unknown scheme error listing the available stores, got %v", err)
+	}
+	if _, err := OpenCheckStore("/var/lib/checks.db"); err == nil {
+		t.Error("Expected an error for a URL without a scheme")
+	}
+}
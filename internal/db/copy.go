@@ -0,0 +1,279 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDestinationNotEmpty is returned by CopyTo when the destination already has users
+// or monitors.
+var ErrDestinationNotEmpty = errors.New("destination database is not empty")
+
+// copyBatchParams bounds the placeholders of one multi-row INSERT, well under the
+// limits of both SQLite (32766) and PostgreSQL (65535).
+const copyBatchParams = 30000
+
+// copyMaxBatchRows bounds the rows of one multi-row INSERT.
+const copyMaxBatchRows = 500
+
+// CopyProgress reports how far CopyTo has got with a table.
+type CopyProgress struct {
+	Table  string
+	Copied int64
+	Total  int64
+}
+
+// TableCount is a table's row count in the source and destination after CopyTo.
+type TableCount struct {
+	Table  string
+	Source int64
+	Dest   int64
+}
+
+// CopyTo copies every table of s, which must be SQLite, into dst, which may be either
+// dialect and must be freshly created: rows seeded on creation are replaced, but any
+// users or monitors give ErrDestinationNotEmpty. The source is read from one snapshot
+// and the destination written in one transaction, so a failed copy leaves dst as it
+// was. progress, if not nil, is called after each batch. Row counts are compared once
+// the copy is committed, and a mismatch is an error.
+func (s *Store) CopyTo(ctx context.Context, dst *Store, progress func(CopyProgress)) ([]TableCount, error) {
+	if s.dialect != DialectSQLite {
+		return nil, fmt.Errorf("copy source must be SQLite, got %s", s.dialect)
+	}
+	for _, table := range []string{"users", "monitors"} {
+		var n int64
+		if err := dst.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil {
+			return nil, fmt.Errorf("check destination: %w", err)
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: %d rows in %s", ErrDestinationNotEmpty, n, table)
+		}
+	}
+
+	src, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = src.Rollback() }()
+
+	tables, err := sqliteTablesInCopyOrder(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := dst.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Clear seeded rows, children first
+	for i := len(tables) - 1; i >= 0; i-- {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoteIdent(tables[i])); err != nil {
+			return nil, fmt.Errorf("clear %s: %w", tables[i], err)
+		}
+	}
+
+	counts := make([]TableCount, 0, len(tables))
+	for _, table := range tables {
+		var total int64
+		if err := src.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdent(table)).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count %s: %w", table, err)
+		}
+		if err := dst.copyTable(ctx, src, tx, table, total, progress); err != nil {
+			return nil, fmt.Errorf("copy %s: %w", table, err)
+		}
+		counts = append(counts, TableCount{Table: table, Source: total})
+	}
+
+	if dst.dialect == DialectPostgres {
+		if err := resetSerialSequences(ctx, tx, tables); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Verify
+	for i := range counts {
+		if err := dst.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdent(counts[i].Table)).Scan(&counts[i].Dest); err != nil {
+			return counts, fmt.Errorf("verify %s: %w", counts[i].Table, err)
+		}
+		if counts[i].Dest != counts[i].Source {
+			return counts, fmt.Errorf("verify %s: copied %d of %d rows", counts[i].Table, counts[i].Dest, counts[i].Source)
+		}
+	}
+	return counts, nil
+}
+
+// copyTable copies one table from src into tx in multi-row INSERT batches.
+func (s *Store) copyTable(ctx context.Context, src, tx *sql.Tx, table string, total int64, progress func(CopyProgress)) error {
+	rows, err := src.QueryContext(ctx, "SELECT * FROM "+quoteIdent(table))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = quoteIdent(c)
+	}
+	insert := "INSERT INTO " + quoteIdent(table) + " (" + strings.Join(quoted, ", ") + ") VALUES "
+	rowPlaceholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")"
+	batchRows := min(copyMaxBatchRows, max(1, copyBatchParams/len(cols)))
+
+	var copied int64
+	args := make([]any, 0, batchRows*len(cols))
+	flush := func() error {
+		n := len(args) / len(cols)
+		if n == 0 {
+			return nil
+		}
+		query := insert + strings.TrimSuffix(strings.Repeat(rowPlaceholders+", ", n), ", ")
+		if _, err := tx.ExecContext(ctx, s.rebind(query), args...); err != nil {
+			return err
+		}
+		copied += int64(n)
+		args = args[:0]
+		if progress != nil {
+			progress(CopyProgress{Table: table, Copied: copied, Total: total})
+		}
+		return nil
+	}
+
+	for rows.Next() {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			// No column holds binary data; SQLite hands back some text as bytes
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		args = append(args, values...)
+		if len(args)/len(cols) >= batchRows {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if copied == 0 && progress != nil {
+		progress(CopyProgress{Table: table, Total: total})
+	}
+	return nil
+}
+
+// sqliteTablesInCopyOrder lists the application tables so that every table comes
+// after the tables its foreign keys reference.
+func sqliteTablesInCopyOrder(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'goose_db_version' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string][]string, len(names))
+	for _, name := range names {
+		fks, err := tx.QueryContext(ctx, "SELECT \"table\" FROM pragma_foreign_key_list(?)", name)
+		if err != nil {
+			return nil, err
+		}
+		for fks.Next() {
+			var parent string
+			if err := fks.Scan(&parent); err != nil {
+				_ = fks.Close()
+				return nil, err
+			}
+			if parent != name {
+				parents[name] = append(parents[name], parent)
+			}
+		}
+		_ = fks.Close()
+	}
+
+	ordered := make([]string, 0, len(names))
+	state := make(map[string]int, len(names)) // 1 visiting, 2 done
+	var visit func(string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("foreign key cycle through %s", name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, p := range parents[name] {
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// resetSerialSequences moves each copied table's id sequence past the copied ids, so
+// new rows do not collide with them.
+func resetSerialSequences(ctx context.Context, tx *sql.Tx, tables []string) error {
+	for _, table := range tables {
+		// Looked up through information_schema so a table without an id column gives
+		// no row rather than an error, which would abort the transaction
+		var seq sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT pg_get_serial_sequence($1, 'id') FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = 'id'`, table).Scan(&seq)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && !seq.Valid) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("find sequence of %s: %w", table, err)
+		}
+		if _, err := tx.ExecContext(ctx, `SELECT setval($1, COALESCE((SELECT MAX(id) FROM `+quoteIdent(table)+`), 0) + 1, false)`, seq.String); err != nil {
+			return fmt.Errorf("reset sequence of %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// quoteIdent quotes a table or column name for both SQLite and PostgreSQL.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCopyTo(t *testing.T) {
+	RunTestWithBothDBs(t, "CopyTo", func(t *testing.T, dst *Store) {
+		src, err := NewStore(NewTestConfig())
+		if err != nil {
+			t.Fatalf("NewStore failed: %v", err)
+		}
+		defer func() { _ = src.Close() }()
+
+		if err := src.CreateUser("admin", "password123", "UTC"); err != nil {
+			t.Fatalf("CreateUser failed: %v", err)
+		}
+		admin, _ := src.Authenticate("admin", "password123")
+		if err := src.CreateMonitor(Monitor{ID: "m-1", GroupID: "g-default", Name: "API", URL: "https://example.com", Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		var checks []CheckResult
+		for i := 0; i < 1200; i++ {
+			checks = append(checks, CheckResult{MonitorID: "m-1", Status: "up", Latency: int64(i), Timestamp: now.Add(-time.Duration(i) * time.Minute), StatusCode: 200})
+		}
+		if err := src.BatchInsertChecks(checks); err != nil {
+			t.Fatalf("BatchInsertChecks failed: %v", err)
+		}
+		key, _ := src.CreateAPIKey("CI", []string{"10.0.0.0/8"})
+		token, _ := src.CreateUserToken(admin.ID, "Script")
+
+		var progressed []CopyProgress
+		counts, err := src.CopyTo(context.Background(), dst, func(p CopyProgress) { progressed = append(progressed, p) })
+		if err != nil {
+			t.Fatalf("CopyTo failed: %v", err)
+		}
+
+		byTable := map[string]TableCount{}
+		for _, c := range counts {
+			byTable[c.Table] = c
+		}
+		for table, want := range map[string]int64{"users": 1, "groups": 1, "monitors": 1, "monitor_checks": 1200, "api_keys": 1, "user_tokens": 1} {
+			if got := byTable[table]; got.Source != want || got.Dest != want {
+				t.Errorf("%s: got %+v, want %d rows", table, got, want)
+			}
+		}
+		if len(progressed) == 0 || progressed[len(progressed)-1].Copied != progressed[len(progressed)-1].Total {
+			t.Errorf("expected progress ending with a finished table, got %v", progressed)
+		}
+
+		// The copy works as a database of its own
+		if _, err := dst.Authenticate("admin", "password123"); err != nil {
+			t.Errorf("expected the copied user to sign in: %v", err)
+		}
+		if valid, err := dst.ValidateAPIKey(key, "10.1.2.3"); !valid || err != nil {
+			t.Errorf("expected the copied API key to be valid, got (%v, %v)", valid, err)
+		}
+		if userID, ok, _ := dst.ValidateUserToken(token, ""); !ok || userID != admin.ID {
+			t.Errorf("expected the copied token to resolve to user %d, got (%d, %v)", admin.ID, userID, ok)
+		}
+		if _, err := dst.CreateAPIKey("After", nil); err != nil {
+			t.Errorf("expected new rows after the copy not to collide: %v", err)
+		}
+
+		// A second copy would overwrite real data
+		if _, err := src.CopyTo(context.Background(), dst, nil); !errors.Is(err, ErrDestinationNotEmpty) {
+			t.Errorf("expected ErrDestinationNotEmpty, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+const (
+	// credentialCacheTTL is how long a validated credential is trusted without another
+	// bcrypt comparison. It also bounds how long a credential revoked on another
+	// instance keeps working here.
+	credentialCacheTTL = 30 * time.Second
+
+	// credentialFailureLimit wrong credentials sharing a prefix from one client within
+	// credentialFailureWindow lock that client out of the prefix for CredentialLockout.
+	credentialFailureLimit  = 10
+	credentialFailureWindow = time.Minute
+
+	// credentialSweepSize is the map size at which expired entries are swept out.
+	credentialSweepSize = 256
+)
+
+// CredentialLockout is how long a client is locked out of an API key or personal
+// token prefix after repeated wrong credentials.
+const CredentialLockout = 5 * time.Minute
+
+// cachedCredential is a credential that recently passed its bcrypt comparison.
+type cachedCredential struct {
+	id      int64
+	ownerID int64  // Owning user of a personal token
+	cidrs   string // Allow-list of an API key, as stored
+	expires time.Time
+}
+
+type prefixFailures struct {
+	count       int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// credentialGuard sits in front of bcrypt-hashed credential lookups. It caches
+// credentials that validated, keyed by a SHA-256 of the raw value, so repeat requests
+// skip bcrypt, and it locks clients out of prefixes they keep failing under, so keys
+// cannot be guessed at bcrypt speed. Lockouts are per client address: a prefix has only
+// 16 bits, so locking the prefix itself would let anyone lock a real key out.
+// Guessing under an unknown prefix costs no bcrypt and is not counted.
+type credentialGuard struct {
+	mu       sync.Mutex
+	valid    map[[sha256.Size]byte]cachedCredential
+	failures map[string]*prefixFailures
+}
+
+func newCredentialGuard() *credentialGuard {
+	return &credentialGuard{
+		valid:    make(map[[sha256.Size]byte]cachedCredential),
+		failures: make(map[string]*prefixFailures),
+	}
+}
+
+// lookup returns the cached credential for raw, if it validated recently.
+func (g *credentialGuard) lookup(raw string) (cachedCredential, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c, ok := g.valid[sha256.Sum256([]byte(raw))]
+	if !ok || time.Now().After(c.expires) {
+		return cachedCredential{}, false
+	}
+	return c, true
+}
+
+// remember caches raw as valid and clears the client's failures under its prefix.
+func (g *credentialGuard) remember(raw, clientIP, prefix string, c cachedCredential) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	if len(g.valid) >= credentialSweepSize {
+		for k, v := range g.valid {
+			if now.After(v.expires) {
+				delete(g.valid, k)
+			}
+		}
+	}
+	c.expires = now.Add(credentialCacheTTL)
+	g.valid[sha256.Sum256([]byte(raw))] = c
+	delete(g.failures, failureKey(clientIP, prefix))
+}
+
+// forget drops cached credentials with the given ID, after it is revoked or changed.
+func (g *credentialGuard) forget(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for k, v := range g.valid {
+		if v.id == id {
+			delete(g.valid, k)
+		}
+	}
+}
+
+// failureKey identifies a client's failures under a prefix.
+func failureKey(clientIP, prefix string) string {
+	return clientIP + " " + prefix
+}
+
+// locked reports whether the client is locked out of prefix after repeated failures.
+func (g *credentialGuard) locked(clientIP, prefix string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, ok := g.failures[failureKey(clientIP, prefix)]
+	return ok && time.Now().Before(f.lockedUntil)
+}
+
+// recordFailure counts a wrong credential from the client under prefix and reports
+// whether that started a lockout.
+func (g *credentialGuard) recordFailure(clientIP, prefix string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	if len(g.failures) >= credentialSweepSize {
+		for k, v := range g.failures {
+			if now.Sub(v.windowStart) > credentialFailureWindow && now.After(v.lockedUntil) {
+				delete(g.failures, k)
+			}
+		}
+	}
+	key := failureKey(clientIP, prefix)
+	f, ok := g.failures[key]
+	if !ok {
+		f = &prefixFailures{windowStart: now}
+		g.failures[key] = f
+	}
+	if now.Sub(f.windowStart) > credentialFailureWindow {
+		f.count, f.windowStart = 0, now
+	}
+	f.count++
+	if f.count < credentialFailureLimit {
+		return false
+	}
+	f.count, f.windowStart = 0, now
+	f.lockedUntil = now.Add(CredentialLockout)
+	return true
+}
+
+// reset drops all cached credentials and failures, after the database is reset.
+func (g *credentialGuard) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.valid = make(map[[sha256.Size]byte]cachedCredential)
+	g.failures = make(map[string]*prefixFailures)
+}
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Monitor type: "http" (actively checked) or "external" (driven by ingested alerts)
+ALTER TABLE monitors ADD COLUMN monitor_type TEXT DEFAULT 'http';
+-- SHA-256 of the generic webhook token for external monitors
+ALTER TABLE monitors ADD COLUMN ingest_token_hash TEXT DEFAULT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_ingest_token_hash ON monitors(ingest_token_hash);
+
+CREATE TABLE IF NOT EXISTS external_alerts (
+    id SERIAL PRIMARY KEY,
+    monitor_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status TEXT NOT NULL,
+    summary TEXT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    UNIQUE(monitor_id, source, fingerprint)
+);
+CREATE INDEX IF NOT EXISTS idx_external_alerts_monitor_status ON external_alerts(monitor_id, status);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_external_alerts_monitor_status;
+DROP TABLE IF EXISTS external_alerts;
+DROP INDEX IF EXISTS idx_monitors_ingest_token_hash;
+ALTER TABLE monitors DROP COLUMN IF EXISTS ingest_token_hash;
+ALTER TABLE monitors DROP COLUMN IF EXISTS monitor_type;
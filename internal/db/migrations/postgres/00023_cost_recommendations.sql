@@ -0,0 +1,25 @@
+-- +goose Up
+-- Right-sizing recommendations, recomputed from each agent poll
+CREATE TABLE IF NOT EXISTS cost_recommendations (
+    id SERIAL PRIMARY KEY,
+    agent_id TEXT NOT NULL,
+    namespace TEXT NOT NULL,
+    workload_kind TEXT NOT NULL,
+    workload_name TEXT NOT NULL,
+    replicas INTEGER DEFAULT 0,
+    cpu_requested_cores DOUBLE PRECISION DEFAULT 0,
+    cpu_used_cores DOUBLE PRECISION DEFAULT 0,
+    cpu_recommended_cores DOUBLE PRECISION DEFAULT 0,
+    memory_requested_gib DOUBLE PRECISION DEFAULT 0,
+    memory_used_gib DOUBLE PRECISION DEFAULT 0,
+    memory_recommended_gib DOUBLE PRECISION DEFAULT 0,
+    hourly_cost DOUBLE PRECISION DEFAULT 0,
+    monthly_savings DOUBLE PRECISION DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(agent_id) REFERENCES agents(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_cost_recommendations_agent ON cost_recommendations(agent_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_cost_recommendations_agent;
+DROP TABLE IF EXISTS cost_recommendations;
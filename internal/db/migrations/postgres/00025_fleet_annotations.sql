@@ -0,0 +1,11 @@
+-- +goose Up
+-- Comma-separated monitor tags, used to target fleet-wide operations
+ALTER TABLE monitors ADD COLUMN tags TEXT DEFAULT '';
+-- Groups the per-monitor rows of one fleet-wide annotation (e.g. a deploy)
+ALTER TABLE monitor_annotations ADD COLUMN batch_id TEXT DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_monitor_annotations_timestamp ON monitor_annotations(timestamp);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitor_annotations_timestamp;
+ALTER TABLE monitor_annotations DROP COLUMN IF EXISTS batch_id;
+ALTER TABLE monitors DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Per-monitor degraded hysteresis: N of the last M checks must agree to enter/leave degraded
+ALTER TABLE monitors ADD COLUMN degraded_threshold_checks INTEGER DEFAULT NULL;
+ALTER TABLE monitors ADD COLUMN degraded_window_checks INTEGER DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_window_checks;
+ALTER TABLE monitors DROP COLUMN IF EXISTS degraded_threshold_checks;
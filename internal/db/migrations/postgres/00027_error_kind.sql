@@ -0,0 +1,8 @@
+-- +goose Up
+-- Structured failure classification (dns, timeout, tls, http_5xx, ...)
+ALTER TABLE monitor_checks ADD COLUMN error_kind TEXT DEFAULT NULL;
+ALTER TABLE monitor_outages ADD COLUMN error_kind TEXT DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE monitor_outages DROP COLUMN IF EXISTS error_kind;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS error_kind;
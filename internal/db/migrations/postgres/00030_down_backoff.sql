@@ -0,0 +1,8 @@
+-- +goose Up
+-- Per-monitor check interval used after prolonged downtime (NULL = disabled)
+ALTER TABLE monitors ADD COLUMN down_backoff_interval_seconds INTEGER DEFAULT NULL;
+ALTER TABLE monitors ADD COLUMN down_backoff_after_checks INTEGER DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE monitors DROP COLUMN IF EXISTS down_backoff_after_checks;
+ALTER TABLE monitors DROP COLUMN IF EXISTS down_backoff_interval_seconds;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Manual overrides of a monitor's displayed status on public status pages.
+-- Rows are kept after they expire or are cleared as an audit trail.
+CREATE TABLE IF NOT EXISTS status_overrides (
+    id SERIAL PRIMARY KEY,
+    monitor_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP DEFAULT NULL,
+    cleared_at TIMESTAMP DEFAULT NULL,
+    cleared_by INTEGER DEFAULT NULL,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_status_overrides_monitor ON status_overrides(monitor_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_status_overrides_monitor;
+DROP TABLE IF EXISTS status_overrides;
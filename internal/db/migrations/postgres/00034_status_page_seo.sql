@@ -0,0 +1,10 @@
+-- +goose Up
+-- Search engine controls for public status pages
+ALTER TABLE status_pages ADD COLUMN noindex BOOLEAN DEFAULT FALSE;
+ALTER TABLE status_pages ADD COLUMN meta_description TEXT DEFAULT '';
+ALTER TABLE status_pages ADD COLUMN og_image_url TEXT DEFAULT '';
+
+-- +goose Down
+ALTER TABLE status_pages DROP COLUMN IF EXISTS noindex;
+ALTER TABLE status_pages DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE status_pages DROP COLUMN IF EXISTS og_image_url;
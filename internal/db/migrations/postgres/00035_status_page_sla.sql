@@ -0,0 +1,6 @@
+-- +goose Up
+-- Rolling uptime (SLA) banner on public status pages
+ALTER TABLE status_pages ADD COLUMN show_sla BOOLEAN DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE status_pages DROP COLUMN IF EXISTS show_sla;
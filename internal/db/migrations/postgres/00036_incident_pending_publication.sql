@@ -0,0 +1,6 @@
+-- +goose Up
+-- Private incidents awaiting a publish/keep-private decision
+ALTER TABLE incidents ADD COLUMN pending_publication BOOLEAN DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE incidents DROP COLUMN IF EXISTS pending_publication;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Monitors that depend on another (e.g. services behind a shared load balancer). While a
+-- dependency is down, dependents are marked as affected instead of opening their own outages.
+CREATE TABLE IF NOT EXISTS monitor_dependencies (
+    monitor_id TEXT NOT NULL,
+    depends_on_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (monitor_id, depends_on_id),
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    FOREIGN KEY(depends_on_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_monitor_dependencies_depends_on ON monitor_dependencies(depends_on_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitor_dependencies_depends_on;
+DROP TABLE IF EXISTS monitor_dependencies;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Incident lists are filtered by type/status in SQL (the monitor manager loads active
+-- maintenance windows every sync); recent history is selected by start_time.
+CREATE INDEX IF NOT EXISTS idx_incidents_type_status ON incidents(type, status);
+CREATE INDEX IF NOT EXISTS idx_incidents_start_time ON incidents(start_time);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_incidents_start_time;
+DROP INDEX IF EXISTS idx_incidents_type_status;
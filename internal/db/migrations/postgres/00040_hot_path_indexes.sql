@@ -0,0 +1,12 @@
+-- +goose Up
+-- monitor_checks(monitor_id, timestamp) and incidents(type, status) already exist
+-- (00001, 00039). Open outages are looked up per monitor by end_time IS NULL, and
+-- expired sessions are found by expires_at.
+CREATE INDEX IF NOT EXISTS idx_monitor_outages_monitor_end ON monitor_outages(monitor_id, end_time);
+DROP INDEX IF EXISTS idx_monitor_outages_monitor_id;
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+CREATE INDEX IF NOT EXISTS idx_monitor_outages_monitor_id ON monitor_outages(monitor_id);
+DROP INDEX IF EXISTS idx_monitor_outages_monitor_end;
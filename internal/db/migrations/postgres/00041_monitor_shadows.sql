@@ -0,0 +1,32 @@
+-- +goose Up
+-- Shadow (dark-launch) trials: a candidate URL checked alongside a monitor's current URL
+-- for a number of checks, so the results can be compared before switching.
+CREATE TABLE IF NOT EXISTS monitor_shadows (
+    monitor_id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    target_checks INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- One row per check of a trial: the current URL's result next to the candidate's.
+CREATE TABLE IF NOT EXISTS monitor_shadow_samples (
+    id SERIAL PRIMARY KEY,
+    monitor_id TEXT NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    primary_up BOOLEAN NOT NULL,
+    primary_status_code INTEGER NOT NULL DEFAULT 0,
+    primary_latency INTEGER NOT NULL DEFAULT 0,
+    primary_error TEXT NOT NULL DEFAULT '',
+    shadow_up BOOLEAN NOT NULL,
+    shadow_status_code INTEGER NOT NULL DEFAULT 0,
+    shadow_latency INTEGER NOT NULL DEFAULT 0,
+    shadow_error TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY(monitor_id) REFERENCES monitor_shadows(monitor_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_monitor_shadow_samples_monitor ON monitor_shadow_samples(monitor_id, timestamp);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitor_shadow_samples_monitor;
+DROP TABLE IF EXISTS monitor_shadow_samples;
+DROP TABLE IF EXISTS monitor_shadows;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Per-check transfer timing (ms) and response body size, NULL for checks without a response
+ALTER TABLE monitor_checks ADD COLUMN dns_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN connect_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN tls_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN ttfb_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN download_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN body_bytes INTEGER DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS body_bytes;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS download_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS ttfb_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS tls_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS connect_ms;
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS dns_ms;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Latency SLOs: the share of checks (target_percent) that must finish within threshold_ms
+-- over a rolling window of window_days.
+CREATE TABLE IF NOT EXISTS latency_slos (
+    monitor_id TEXT PRIMARY KEY,
+    threshold_ms INTEGER NOT NULL,
+    target_percent REAL NOT NULL,
+    window_days INTEGER NOT NULL DEFAULT 30,
+    alerting BOOLEAN DEFAULT FALSE,
+    last_alert_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- Good (up and within the threshold) and total checks per SLO and 5-minute bucket, so burn
+-- rates don't rescan raw checks and survive check retention shorter than the window.
+CREATE TABLE IF NOT EXISTS latency_slo_rollups (
+    monitor_id TEXT NOT NULL,
+    bucket TIMESTAMP NOT NULL,
+    good INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (monitor_id, bucket),
+    FOREIGN KEY(monitor_id) REFERENCES latency_slos(monitor_id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS latency_slo_rollups;
+DROP TABLE IF EXISTS latency_slos;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- When an incident update was last edited, NULL if never
+ALTER TABLE incident_updates ADD COLUMN edited_at TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE incident_updates DROP COLUMN IF EXISTS edited_at;
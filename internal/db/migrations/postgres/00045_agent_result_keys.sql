@@ -0,0 +1,14 @@
+-- +goose Up
+-- Idempotency keys of agent results uploaded in batches, so a retried upload is not
+-- counted twice
+CREATE TABLE IF NOT EXISTS agent_result_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    monitor_id TEXT NOT NULL,
+    received_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_agent_result_keys_received_at ON agent_result_keys(received_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_agent_result_keys_received_at;
+DROP TABLE IF EXISTS agent_result_keys;
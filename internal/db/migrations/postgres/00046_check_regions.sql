@@ -0,0 +1,6 @@
+-- +goose Up
+-- Region or agent that reported a check; NULL for checks run by the server itself
+ALTER TABLE monitor_checks ADD COLUMN region TEXT DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE monitor_checks DROP COLUMN IF EXISTS region;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- The event log is read newest first, across all monitors or for one, and paged by
+-- (timestamp, id).
+CREATE INDEX IF NOT EXISTS idx_monitor_events_timestamp ON monitor_events(timestamp, id);
+CREATE INDEX IF NOT EXISTS idx_monitor_events_monitor_ts ON monitor_events(monitor_id, timestamp);
+DROP INDEX IF EXISTS idx_monitor_events_monitor_id;
+
+-- +goose Down
+CREATE INDEX IF NOT EXISTS idx_monitor_events_monitor_id ON monitor_events(monitor_id);
+DROP INDEX IF EXISTS idx_monitor_events_monitor_ts;
+DROP INDEX IF EXISTS idx_monitor_events_timestamp;
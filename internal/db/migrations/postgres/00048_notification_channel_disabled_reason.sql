@@ -0,0 +1,6 @@
+-- +goose Up
+-- Why the notifier disabled a channel after sustained delivery failures; cleared on edit
+ALTER TABLE notification_channels ADD COLUMN disabled_reason TEXT DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE notification_channels DROP COLUMN IF EXISTS disabled_reason;
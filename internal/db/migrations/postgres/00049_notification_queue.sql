@@ -0,0 +1,21 @@
+-- +goose Up
+-- Notifications waiting to be delivered, one row per channel, so they survive a restart.
+-- Delivered rows are kept for a while so their dedup key keeps catching duplicates.
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id SERIAL PRIMARY KEY,
+    dedup_key TEXT NOT NULL UNIQUE,
+    channel_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    FOREIGN KEY(channel_id) REFERENCES notification_channels(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_notification_queue_pending ON notification_queue(next_attempt_at) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notification_queue_delivered_at ON notification_queue(delivered_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notification_queue_delivered_at;
+DROP INDEX IF EXISTS idx_notification_queue_pending;
+DROP TABLE IF EXISTS notification_queue;
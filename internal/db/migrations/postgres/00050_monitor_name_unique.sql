@@ -0,0 +1,13 @@
+-- +goose Up
+-- Monitor names are unique, ignoring case. Existing clashes keep the oldest monitor's
+-- name; the others get their ID appended.
+UPDATE monitors SET name = name || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM monitors older
+    WHERE LOWER(older.name) = LOWER(monitors.name)
+      AND (older.created_at < monitors.created_at OR (older.created_at = monitors.created_at AND older.id < monitors.id))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_name_lower ON monitors (LOWER(name));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitors_name_lower;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Comma-separated networks an API key may be used from; empty allows any address
+ALTER TABLE api_keys ADD COLUMN allowed_cidrs TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE api_keys DROP COLUMN IF EXISTS allowed_cidrs;
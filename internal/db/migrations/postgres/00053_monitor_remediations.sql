@@ -0,0 +1,23 @@
+-- +goose Up
+-- Remediation actions: a webhook called when a monitor's outage opens, e.g. to trigger a
+-- restart job. after_checks and cooldown_minutes confirm the outage and throttle reruns.
+CREATE TABLE IF NOT EXISTS monitor_remediations (
+    monitor_id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT 'POST',
+    headers TEXT NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL DEFAULT '',
+    after_checks INTEGER NOT NULL DEFAULT 0,
+    cooldown_minutes INTEGER NOT NULL DEFAULT 30,
+    enabled BOOLEAN DEFAULT TRUE,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- The result of the remediation action run for an outage
+ALTER TABLE monitor_outages ADD COLUMN remediation TEXT DEFAULT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_remediations;
+ALTER TABLE monitor_outages DROP COLUMN IF EXISTS remediation;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- The Jira or GitHub issue opened for an incident, e.g. OPS-123 or owner/repo#45
+ALTER TABLE incidents ADD COLUMN issue_key TEXT DEFAULT NULL;
+ALTER TABLE incidents ADD COLUMN issue_url TEXT DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_issue_key ON incidents(issue_key);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_incidents_issue_key;
+ALTER TABLE incidents DROP COLUMN IF EXISTS issue_url;
+ALTER TABLE incidents DROP COLUMN IF EXISTS issue_key;
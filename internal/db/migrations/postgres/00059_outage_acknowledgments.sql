@@ -0,0 +1,16 @@
+-- +goose Up
+-- Who acknowledged an outage and when, so on-call knows someone is on it
+CREATE TABLE IF NOT EXISTS outage_acknowledgments (
+    id SERIAL PRIMARY KEY,
+    outage_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    username TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(outage_id) REFERENCES monitor_outages(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_outage_acknowledgments_outage_id ON outage_acknowledgments(outage_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outage_acknowledgments_outage_id;
+DROP TABLE IF EXISTS outage_acknowledgments;
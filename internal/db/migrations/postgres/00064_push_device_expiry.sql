@@ -0,0 +1,6 @@
+-- +goose Up
+-- Browsers may give a push subscription an expiration time, after which it is useless
+ALTER TABLE push_devices ADD COLUMN expires_at TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE push_devices DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Idempotency keys only have to be unique per monitor, so agents that happen to pick the
+-- same key for different monitors don't see each other's results as duplicates
+ALTER TABLE agent_result_keys DROP CONSTRAINT IF EXISTS agent_result_keys_pkey;
+ALTER TABLE agent_result_keys ADD PRIMARY KEY (monitor_id, idempotency_key);
+
+-- +goose Down
+DELETE FROM agent_result_keys a USING agent_result_keys b
+    WHERE a.idempotency_key = b.idempotency_key AND a.monitor_id > b.monitor_id;
+ALTER TABLE agent_result_keys DROP CONSTRAINT IF EXISTS agent_result_keys_pkey;
+ALTER TABLE agent_result_keys ADD PRIMARY KEY (idempotency_key);
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Remote cost agents polled by the dashboard (see cmd/mockagent for the API shape)
+CREATE TABLE IF NOT EXISTS agents (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    token TEXT,
+    active BOOLEAN DEFAULT TRUE,
+    interval_seconds INTEGER DEFAULT 300,
+    last_polled_at DATETIME,
+    last_error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Raw agent payloads captured on each poll
+CREATE TABLE IF NOT EXISTS agent_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    agent_id TEXT NOT NULL,
+    total_hourly_cost REAL DEFAULT 0,
+    summary TEXT,
+    namespaces TEXT,
+    nodes TEXT,
+    workloads TEXT,
+    collected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(agent_id) REFERENCES agents(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_agent_snapshots_agent_ts ON agent_snapshots(agent_id, collected_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_agent_snapshots_agent_ts;
+DROP TABLE IF EXISTS agent_snapshots;
+DROP TABLE IF EXISTS agents;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Hourly cost rollups per agent. scope is "cluster" (name empty) or "namespace" (name = namespace).
+CREATE TABLE IF NOT EXISTS cost_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    agent_id TEXT NOT NULL,
+    bucket DATETIME NOT NULL,
+    scope TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    hourly_cost REAL NOT NULL DEFAULT 0,
+    FOREIGN KEY(agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    UNIQUE(agent_id, bucket, scope, name)
+);
+CREATE INDEX IF NOT EXISTS idx_cost_history_bucket ON cost_history(bucket);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_cost_history_bucket;
+DROP TABLE IF EXISTS cost_history;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Monthly cost budgets evaluated against cost_history.
+-- scope: "cluster" | "namespace" (scope_value = namespace) | "label" (scope_value = "key=value")
+CREATE TABLE IF NOT EXISTS cost_budgets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    agent_id TEXT,
+    scope TEXT NOT NULL,
+    scope_value TEXT NOT NULL DEFAULT '',
+    monthly_limit REAL NOT NULL,
+    enabled BOOLEAN DEFAULT TRUE,
+    last_notified_period TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(agent_id) REFERENCES agents(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS cost_budgets;
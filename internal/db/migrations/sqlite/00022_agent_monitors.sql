@@ -0,0 +1,11 @@
+-- +goose Up
+-- Every cost agent gets an implicit "agent" monitor (same ID) checking its /api/health.
+-- Backfill for agents registered before agent monitors existed.
+INSERT OR IGNORE INTO groups (id, name, icon)
+SELECT 'g-agents', 'Agents', 'Server' WHERE EXISTS (SELECT 1 FROM agents);
+
+INSERT OR IGNORE INTO monitors (id, group_id, name, url, active, interval_seconds, monitor_type)
+SELECT id, 'g-agents', name || ' (agent)', RTRIM(url, '/') || '/api/health', active, 60, 'agent' FROM agents;
+
+-- +goose Down
+DELETE FROM monitors WHERE monitor_type = 'agent';
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Annotations (deploys, config changes) shown on monitor latency charts
+CREATE TABLE IF NOT EXISTS monitor_annotations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    monitor_id TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'deploy',
+    message TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_monitor_annotations_monitor_ts ON monitor_annotations(monitor_id, timestamp);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitor_annotations_monitor_ts;
+DROP TABLE IF EXISTS monitor_annotations;
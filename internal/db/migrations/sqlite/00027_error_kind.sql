@@ -0,0 +1,7 @@
+-- +goose Up
+-- Structured failure classification (dns, timeout, tls, http_5xx, ...)
+ALTER TABLE monitor_checks ADD COLUMN error_kind TEXT DEFAULT NULL;
+ALTER TABLE monitor_outages ADD COLUMN error_kind TEXT DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Response evidence (key headers, truncated body) captured when an outage opens
+ALTER TABLE monitor_outages ADD COLUMN evidence TEXT DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Traceroute/MTR report attached to outages on sustained failures
+ALTER TABLE monitor_outages ADD COLUMN path_report TEXT DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Per-monitor check interval used after prolonged downtime (NULL = disabled)
+ALTER TABLE monitors ADD COLUMN down_backoff_interval_seconds INTEGER DEFAULT NULL;
+ALTER TABLE monitors ADD COLUMN down_backoff_after_checks INTEGER DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
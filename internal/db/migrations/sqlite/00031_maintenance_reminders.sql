@@ -0,0 +1,13 @@
+-- +goose Up
+-- Tracks which pre-start reminders have been sent for each maintenance window
+CREATE TABLE IF NOT EXISTS maintenance_reminders (
+    incident_id TEXT NOT NULL,
+    hours_before INTEGER NOT NULL,
+    starts_at DATETIME NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (incident_id, hours_before, starts_at),
+    FOREIGN KEY(incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS maintenance_reminders;
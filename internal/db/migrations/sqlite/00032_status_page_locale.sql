@@ -0,0 +1,6 @@
+-- +goose Up
+-- Language of visitor-facing labels on the public status page
+ALTER TABLE status_pages ADD COLUMN locale TEXT DEFAULT 'en';
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Search engine controls for public status pages
+ALTER TABLE status_pages ADD COLUMN noindex BOOLEAN DEFAULT FALSE;
+ALTER TABLE status_pages ADD COLUMN meta_description TEXT DEFAULT '';
+ALTER TABLE status_pages ADD COLUMN og_image_url TEXT DEFAULT '';
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
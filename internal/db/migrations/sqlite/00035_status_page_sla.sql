@@ -0,0 +1,6 @@
+-- +goose Up
+-- Rolling uptime (SLA) banner on public status pages
+ALTER TABLE status_pages ADD COLUMN show_sla BOOLEAN DEFAULT FALSE;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Private incidents awaiting a publish/keep-private decision
+ALTER TABLE incidents ADD COLUMN pending_publication BOOLEAN DEFAULT FALSE;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
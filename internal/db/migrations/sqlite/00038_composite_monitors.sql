@@ -0,0 +1,20 @@
+-- +goose Up
+-- Composite monitors are up while at least min_up of their member monitors are up
+CREATE TABLE IF NOT EXISTS composite_monitors (
+    monitor_id TEXT PRIMARY KEY,
+    min_up INTEGER NOT NULL DEFAULT 1,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS composite_monitor_members (
+    composite_id TEXT NOT NULL,
+    member_id TEXT NOT NULL,
+    PRIMARY KEY (composite_id, member_id),
+    FOREIGN KEY(composite_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    FOREIGN KEY(member_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_composite_monitor_members_member ON composite_monitor_members(member_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_composite_monitor_members_member;
+DROP TABLE IF EXISTS composite_monitor_members;
+DROP TABLE IF EXISTS composite_monitors;
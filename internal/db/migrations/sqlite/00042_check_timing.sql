@@ -0,0 +1,11 @@
+-- +goose Up
+-- Per-check transfer timing (ms) and response body size, NULL for checks without a response
+ALTER TABLE monitor_checks ADD COLUMN dns_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN connect_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN tls_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN ttfb_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN download_ms INTEGER DEFAULT NULL;
+ALTER TABLE monitor_checks ADD COLUMN body_bytes INTEGER DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
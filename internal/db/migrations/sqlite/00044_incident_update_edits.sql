@@ -0,0 +1,6 @@
+-- +goose Up
+-- When an incident update was last edited, NULL if never
+ALTER TABLE incident_updates ADD COLUMN edited_at DATETIME DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
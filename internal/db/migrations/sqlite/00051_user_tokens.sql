@@ -0,0 +1,20 @@
+-- +goose Up
+-- Personal API tokens belong to a user, so requests made with them act as that user.
+-- Global api_keys remain for service integrations.
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_prefix TEXT NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_used_at DATETIME,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_prefix ON user_tokens(token_prefix);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user ON user_tokens(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_tokens_user;
+DROP INDEX IF EXISTS idx_user_tokens_prefix;
+DROP TABLE IF EXISTS user_tokens;
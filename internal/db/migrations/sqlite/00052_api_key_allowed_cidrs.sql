@@ -0,0 +1,6 @@
+-- +goose Up
+-- Comma-separated networks an API key may be used from; empty allows any address
+ALTER TABLE api_keys ADD COLUMN allowed_cidrs TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
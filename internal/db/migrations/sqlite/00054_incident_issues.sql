@@ -0,0 +1,9 @@
+-- +goose Up
+-- The Jira or GitHub issue opened for an incident, e.g. OPS-123 or owner/repo#45
+ALTER TABLE incidents ADD COLUMN issue_key TEXT DEFAULT NULL;
+ALTER TABLE incidents ADD COLUMN issue_url TEXT DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_issue_key ON incidents(issue_key);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_incidents_issue_key;
+-- SQLite does not support DROP COLUMN before 3.35.0
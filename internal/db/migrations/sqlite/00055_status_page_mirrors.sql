@@ -0,0 +1,33 @@
+-- +goose Up
+-- Status page mirrors: push a status page's component statuses and incidents to a hosted
+-- Statuspage.io or Instatus page. components maps Warden monitor or group IDs to the
+-- hosted page's component IDs.
+CREATE TABLE IF NOT EXISTS status_page_mirrors (
+    slug TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    page_id TEXT NOT NULL,
+    api_key TEXT NOT NULL,
+    components TEXT NOT NULL DEFAULT '{}',
+    enabled BOOLEAN DEFAULT TRUE,
+    last_sync_at DATETIME,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(slug) REFERENCES status_pages(slug) ON DELETE CASCADE
+);
+
+-- The hosted incident each mirrored incident was copied to, with the status and latest
+-- update pushed so far
+CREATE TABLE IF NOT EXISTS status_page_mirror_incidents (
+    slug TEXT NOT NULL,
+    incident_id TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    last_update_id INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (slug, incident_id),
+    FOREIGN KEY(slug) REFERENCES status_page_mirrors(slug) ON DELETE CASCADE,
+    FOREIGN KEY(incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS status_page_mirror_incidents;
+DROP TABLE IF EXISTS status_page_mirrors;
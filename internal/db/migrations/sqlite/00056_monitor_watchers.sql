@@ -0,0 +1,8 @@
+-- +goose Up
+-- Watchers are notification channels scoped to one monitor: they only receive its notifications
+ALTER TABLE notification_channels ADD COLUMN monitor_id TEXT DEFAULT NULL REFERENCES monitors(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_notification_channels_monitor_id ON notification_channels(monitor_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notification_channels_monitor_id;
+-- SQLite does not support DROP COLUMN before 3.35.0
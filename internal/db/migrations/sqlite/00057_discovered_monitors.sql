@@ -0,0 +1,15 @@
+-- +goose Up
+-- Monitors managed by a discovery provider, such as Docker container labels. source_key
+-- identifies what the monitor was created for (the container name), so it is updated
+-- when the labels change and deleted when the container goes away.
+CREATE TABLE IF NOT EXISTS discovered_monitors (
+    provider TEXT NOT NULL,
+    source_key TEXT NOT NULL,
+    monitor_id TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, source_key),
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS discovered_monitors;
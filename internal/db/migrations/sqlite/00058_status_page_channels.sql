@@ -0,0 +1,15 @@
+-- +goose Up
+-- The notification channels told when a public incident shown on a status page opens,
+-- is updated or resolves, so each brand or region's page reaches its own audience
+CREATE TABLE IF NOT EXISTS status_page_channels (
+    slug TEXT NOT NULL,
+    channel_id TEXT NOT NULL,
+    PRIMARY KEY (slug, channel_id),
+    FOREIGN KEY(slug) REFERENCES status_pages(slug) ON DELETE CASCADE,
+    FOREIGN KEY(channel_id) REFERENCES notification_channels(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_status_page_channels_channel_id ON status_page_channels(channel_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_status_page_channels_channel_id;
+DROP TABLE IF EXISTS status_page_channels;
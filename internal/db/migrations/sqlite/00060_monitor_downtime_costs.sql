@@ -0,0 +1,12 @@
+-- +goose Up
+-- The approximate business cost of a minute of a monitor's downtime, used to estimate
+-- the impact of its outages
+CREATE TABLE IF NOT EXISTS monitor_downtime_costs (
+    monitor_id TEXT PRIMARY KEY,
+    cost_per_minute REAL NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_downtime_costs;
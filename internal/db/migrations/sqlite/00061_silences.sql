@@ -0,0 +1,18 @@
+-- +goose Up
+-- Silences mute the notifications of a monitor, group or tag for a while without
+-- affecting its status, unlike maintenance windows
+CREATE TABLE IF NOT EXISTS silences (
+    id TEXT PRIMARY KEY,
+    scope TEXT NOT NULL,
+    target TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_silences_ends_at ON silences(ends_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_silences_ends_at;
+DROP TABLE IF EXISTS silences;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Recurring windows, e.g. a nightly batch job, in which a monitor's checks are skipped so
+-- the expected downtime doesn't count against its uptime. windows is a JSON array.
+CREATE TABLE IF NOT EXISTS monitor_check_exclusions (
+    monitor_id TEXT PRIMARY KEY,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    windows TEXT NOT NULL DEFAULT '[]',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_check_exclusions;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Devices a user registered for push notifications: mobile apps reached through the
+-- push gateway by their FCM or APNs token, and browsers by their Web Push endpoint
+CREATE TABLE IF NOT EXISTS push_devices (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    platform TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL DEFAULT '',
+    auth TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_push_devices_user ON push_devices(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_push_devices_user;
+DROP TABLE IF EXISTS push_devices;
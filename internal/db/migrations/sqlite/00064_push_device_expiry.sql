@@ -0,0 +1,6 @@
+-- +goose Up
+-- Browsers may give a push subscription an expiration time, after which it is useless
+ALTER TABLE push_devices ADD COLUMN expires_at DATETIME DEFAULT NULL;
+
+-- +goose Down
+-- SQLite does not support DROP COLUMN before 3.35.0
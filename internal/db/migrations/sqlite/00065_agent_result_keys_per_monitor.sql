@@ -0,0 +1,28 @@
+-- +goose Up
+-- Idempotency keys only have to be unique per monitor, so agents that happen to pick the
+-- same key for different monitors don't see each other's results as duplicates
+CREATE TABLE agent_result_keys_new (
+    idempotency_key TEXT NOT NULL,
+    monitor_id TEXT NOT NULL,
+    received_at DATETIME NOT NULL,
+    PRIMARY KEY (monitor_id, idempotency_key),
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+INSERT INTO agent_result_keys_new (idempotency_key, monitor_id, received_at)
+    SELECT idempotency_key, monitor_id, received_at FROM agent_result_keys;
+DROP TABLE agent_result_keys;
+ALTER TABLE agent_result_keys_new RENAME TO agent_result_keys;
+CREATE INDEX IF NOT EXISTS idx_agent_result_keys_received_at ON agent_result_keys(received_at);
+
+-- +goose Down
+CREATE TABLE agent_result_keys_old (
+    idempotency_key TEXT PRIMARY KEY,
+    monitor_id TEXT NOT NULL,
+    received_at DATETIME NOT NULL,
+    FOREIGN KEY(monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+INSERT OR IGNORE INTO agent_result_keys_old (idempotency_key, monitor_id, received_at)
+    SELECT idempotency_key, monitor_id, received_at FROM agent_result_keys;
+DROP TABLE agent_result_keys;
+ALTER TABLE agent_result_keys_old RENAME TO agent_result_keys;
+CREATE INDEX IF NOT EXISTS idx_agent_result_keys_received_at ON agent_result_keys(received_at);
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
+	"strings"
+	"sync"
 
-	_ "github.com/lib/pq"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 	"github.com/pressly/goose/v3"
 )
 
@@ -35,6 +38,22 @@ type DBConfig struct {
 type Store struct {
 	db      *sql.DB
 	dialect string
+
+	// Caches and lockouts in front of bcrypt for API keys and personal tokens
+	apiKeyGuard    *credentialGuard
+	userTokenGuard *credentialGuard
+
+	// Raw check results, in the main database unless SetCheckStore moved them
+	checks         CheckStore
+	externalChecks bool
+
+	// Told about every saved setting, see OnSettingsChange
+	settingsMu        sync.RWMutex
+	settingsListeners []SettingsListener
+
+	// Told about every created incident, see OnIncidentCreate
+	incidentMu        sync.RWMutex
+	incidentListeners []IncidentListener
 }
 
 // NewStore creates a new store with the given configuration.
@@ -77,7 +96,8 @@ func NewStore(cfg DBConfig) (*Store, error) {
 		}
 	}
 
-	s := &Store{db: db, dialect: dialect}
+	s := &Store{db: db, dialect: dialect, apiKeyGuard: newCredentialGuard(), userTokenGuard: newCredentialGuard()}
+	s.checks = &sqlCheckStore{db: db, dialect: dialect}
 	if err := s.migrate(); err != nil {
 		return nil, err
 	}
@@ -97,7 +117,11 @@ func (s *Store) Dialect() string {
 // rebind converts ? placeholders to $1, $2, etc. for PostgreSQL
 // SQLite queries pass through unchanged
 func (s *Store) rebind(query string) string {
-	if s.dialect != DialectPostgres {
+	return rebindQuery(s.dialect, query)
+}
+
+func rebindQuery(dialect, query string) string {
+	if dialect != DialectPostgres {
 		return query
 	}
 	var result []byte
@@ -130,9 +154,46 @@ func (s *Store) PingContext(ctx context.Context) error {
 }
 
 func (s *Store) Close() error {
+	if s.externalChecks {
+		_ = s.checks.Close()
+	}
 	return s.db.Close()
 }
 
+// ErrConflict is matched (errors.Is) by the errors returned when a write would violate
+// a unique constraint, such as ErrDuplicateMonitorName.
+var ErrConflict = errors.New("conflict")
+
+// conflictError is a unique constraint violation, described for the caller.
+type conflictError struct{ msg string }
+
+func (e *conflictError) Error() string        { return e.msg }
+func (e *conflictError) Is(target error) bool { return target == ErrConflict }
+
+func newConflictError(msg string) error { return &conflictError{msg: msg} }
+
+// uniqueViolation reports whether err comes from a unique or primary key constraint,
+// and names the constraint: the index for SQLite unique indexes and PostgreSQL, or the
+// columns (e.g. "monitors.id") for SQLite's inline constraints.
+func uniqueViolation(err error) (constraint string, ok bool) {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique && sqliteErr.ExtendedCode != sqlite3.ErrConstraintPrimaryKey {
+			return "", false
+		}
+		constraint = strings.TrimPrefix(sqliteErr.Error(), "UNIQUE constraint failed: ")
+		if name, found := strings.CutPrefix(constraint, "index '"); found {
+			constraint = strings.TrimSuffix(name, "'")
+		}
+		return constraint, true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
+
 func (s *Store) migrate() error {
 	// Select the appropriate migration filesystem and Goose dialect
 	var embedFS embed.FS
@@ -198,19 +259,49 @@ func (s *Store) seed() error {
 // allowedResetTables is a whitelist of table names that can be dropped during reset.
 // SECURITY: This prevents potential SQL injection if table names were ever derived from user input.
 var allowedResetTables = map[string]bool{
-	"users":                 true,
-	"sessions":              true,
-	"groups":                true,
-	"monitors":              true,
-	"monitor_checks":        true,
-	"monitor_events":        true,
-	"status_pages":          true,
-	"api_keys":              true,
-	"settings":              true,
-	"monitor_outages":       true,
-	"notification_channels": true,
-	"incidents":             true,
-	"goose_db_version":      true,
+	"users":                        true,
+	"sessions":                     true,
+	"groups":                       true,
+	"monitors":                     true,
+	"monitor_checks":               true,
+	"monitor_events":               true,
+	"status_pages":                 true,
+	"api_keys":                     true,
+	"settings":                     true,
+	"monitor_outages":              true,
+	"notification_channels":        true,
+	"incidents":                    true,
+	"incident_updates":             true,
+	"external_alerts":              true,
+	"agents":                       true,
+	"agent_snapshots":              true,
+	"cost_history":                 true,
+	"cost_budgets":                 true,
+	"cost_recommendations":         true,
+	"monitor_annotations":          true,
+	"maintenance_reminders":        true,
+	"status_overrides":             true,
+	"monitor_dependencies":         true,
+	"composite_monitors":           true,
+	"composite_monitor_members":    true,
+	"monitor_shadows":              true,
+	"monitor_shadow_samples":       true,
+	"latency_slos":                 true,
+	"latency_slo_rollups":          true,
+	"agent_result_keys":            true,
+	"notification_queue":           true,
+	"user_tokens":                  true,
+	"monitor_remediations":         true,
+	"status_page_mirrors":          true,
+	"status_page_mirror_incidents": true,
+	"discovered_monitors":          true,
+	"status_page_channels":         true,
+	"outage_acknowledgments":       true,
+	"monitor_downtime_costs":       true,
+	"silences":                     true,
+	"monitor_check_exclusions":     true,
+	"push_devices":                 true,
+	"goose_db_version":             true,
 }
 
 // isValidTableName checks if a table name is in the allowed whitelist.
@@ -220,6 +311,16 @@ func isValidTableName(table string) bool {
 }
 
 func (s *Store) Reset() error {
+	if s.externalChecks {
+		ids, err := s.monitorIDs("")
+		if err != nil {
+			return err
+		}
+		if err := s.checks.DeleteChecks(ids); err != nil {
+			return err
+		}
+	}
+
 	// Disable FKs to allow dropping tables regardless of order
 	if s.dialect == DialectSQLite {
 		if _, err := s.db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
@@ -230,7 +331,14 @@ func (s *Store) Reset() error {
 	tables := []string{
 		"users", "sessions", "groups", "monitors", "monitor_checks",
 		"monitor_events", "status_pages", "api_keys", "settings", "monitor_outages",
-		"notification_channels", "incidents",
+		"notification_channels", "incidents", "incident_updates", "external_alerts", "agents", "agent_snapshots",
+		"cost_history", "cost_budgets", "cost_recommendations", "monitor_annotations",
+		"maintenance_reminders", "status_overrides", "monitor_dependencies",
+		"composite_monitors", "composite_monitor_members", "monitor_shadows", "monitor_shadow_samples",
+		"latency_slos", "latency_slo_rollups", "agent_result_keys", "notification_queue",
+		"user_tokens", "monitor_remediations", "status_page_mirrors", "status_page_mirror_incidents",
+		"discovered_monitors", "status_page_channels", "outage_acknowledgments", "monitor_downtime_costs",
+		"silences", "monitor_check_exclusions", "push_devices",
 		"goose_db_version", // Goose migration tracking table
 	}
 
@@ -259,10 +367,13 @@ func (s *Store) Reset() error {
 		}
 	}
 
+	// Dropped credentials must not stay valid from the cache
+	s.apiKeyGuard.reset()
+	s.userTokenGuard.reset()
+
 	// Re-run migrations and seeds
 	if err := s.migrate(); err != nil {
 		return err
 	}
 	return s.seed()
 }
-
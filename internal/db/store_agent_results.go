@@ -0,0 +1,39 @@
+package db
+
+import "time"
+
+// AgentResultKeyRetention is how long the idempotency keys of uploaded agent results are
+// kept. Agents must not upload results older than this, or a retry could be counted twice.
+const AgentResultKeyRetention = 7 * 24 * time.Hour
+
+// ClaimAgentResultKey records the idempotency key of an uploaded agent result. Keys are
+// unique per monitor. It reports false if the key was already claimed for the monitor,
+// meaning the result is a duplicate.
+func (s *Store) ClaimAgentResultKey(key, monitorID string, at time.Time) (bool, error) {
+	res, err := s.db.Exec(s.rebind(`
+		INSERT INTO agent_result_keys (idempotency_key, monitor_id, received_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (monitor_id, idempotency_key) DO NOTHING
+	`), key, monitorID, at.UTC())
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ReleaseAgentResultKey forgets a claimed key so the agent can upload the result again,
+// for when it could not be ingested after all.
+func (s *Store) ReleaseAgentResultKey(key, monitorID string) error {
+	_, err := s.db.Exec(s.rebind("DELETE FROM agent_result_keys WHERE monitor_id = ? AND idempotency_key = ?"), monitorID, key)
+	return err
+}
+
+// PruneAgentResultKeys deletes keys received before the given time.
+func (s *Store) PruneAgentResultKeys(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM agent_result_keys WHERE received_at < ?"), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
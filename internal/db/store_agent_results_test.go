@@ -0,0 +1,104 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgentResultKeys(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "Edge", Type: MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+
+	ok, err := s.ClaimAgentResultKey("k1", "m1", now.Add(-8*24*time.Hour))
+	if err != nil || !ok {
+		t.Fatalf("Expected the first claim to succeed, got %v, %v", ok, err)
+	}
+	if ok, err := s.ClaimAgentResultKey("k1", "m1", now); err != nil || ok {
+		t.Fatalf("Expected a second claim of the same key to report a duplicate, got %v, %v", ok, err)
+	}
+	if ok, err := s.ClaimAgentResultKey("k2", "m1", now); err != nil || !ok {
+		t.Fatalf("Expected a different key to be claimed, got %v, %v", ok, err)
+	}
+
+	// A released key can be claimed again
+	if err := s.ReleaseAgentResultKey("k2", "m1"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.ClaimAgentResultKey("k2", "m1", now); err != nil || !ok {
+		t.Fatalf("Expected a released key to be claimable, got %v, %v", ok, err)
+	}
+
+	// Keys are unique per monitor
+	if err := s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "Edge 2", Type: MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.ClaimAgentResultKey("k2", "m2", now); err != nil || !ok {
+		t.Fatalf("Expected another monitor to claim the same key, got %v, %v", ok, err)
+	}
+
+	n, err := s.PruneAgentResultKeys(now.Add(-AgentResultKeyRetention))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Expected the expired key to be pruned, pruned %d", n)
+	}
+	if ok, err := s.ClaimAgentResultKey("k1", "m1", now); err != nil || !ok {
+		t.Errorf("Expected a pruned key to be claimable, got %v, %v", ok, err)
+	}
+}
+
+func TestCreateClosedOutage(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "Edge", Type: MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+
+	created, err := s.CreateClosedOutage("m1", "down", "unreachable", ErrorKindExternal, base, base.Add(10*time.Minute))
+	if err != nil || !created {
+		t.Fatalf("Expected the outage to be created, got %v, %v", created, err)
+	}
+	// Overlapping the recorded outage
+	if created, err := s.CreateClosedOutage("m1", "down", "unreachable", ErrorKindExternal, base.Add(5*time.Minute), base.Add(20*time.Minute)); err != nil || created {
+		t.Errorf("Expected an overlapping outage to be skipped, got %v, %v", created, err)
+	}
+	if created, err := s.CreateClosedOutage("m1", "down", "unreachable", ErrorKindExternal, base.Add(30*time.Minute), base.Add(40*time.Minute)); err != nil || !created {
+		t.Errorf("Expected a later outage to be created, got %v, %v", created, err)
+	}
+
+	outages, err := s.GetResolvedOutages(base.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outages) != 2 {
+		t.Fatalf("Expected 2 resolved outages, got %d", len(outages))
+	}
+	first := outages[1] // newest end time first
+	if !first.StartTime.Equal(base) || first.EndTime == nil || !first.EndTime.Equal(base.Add(10*time.Minute)) || first.ErrorKind != ErrorKindExternal {
+		t.Errorf("Expected the outage to keep its times, got %+v", first)
+	}
+
+	// An outage still open blocks anything after its start
+	if err := s.CreateOutageAt("m1", "down", "unreachable", ErrorKindExternal, nil, base.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := s.CreateClosedOutage("m1", "down", "unreachable", ErrorKindExternal, base.Add(70*time.Minute), base.Add(80*time.Minute)); err != nil || created {
+		t.Errorf("Expected an outage overlapping the open one to be skipped, got %v, %v", created, err)
+	}
+	if err := s.CloseOutageAt("m1", base.Add(90*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	active, err := s.GetActiveOutages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 0 {
+		t.Errorf("Expected no active outages after closing, got %d", len(active))
+	}
+}
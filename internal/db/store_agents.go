@@ -0,0 +1,234 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrAgentNotFound is returned when an agent is not found
+var ErrAgentNotFound = errors.New("agent not found")
+
+// Agent is a remote cost agent the dashboard polls for cluster cost data.
+type Agent struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url"`
+	Token        string     `json:"-"` // Sent as Bearer token to the agent, never returned by the API
+	Active       bool       `json:"active"`
+	Interval     int        `json:"interval"` // Poll interval in seconds
+	LastPolledAt *time.Time `json:"lastPolledAt,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// AgentSnapshot holds the raw JSON payloads collected from an agent in one poll.
+type AgentSnapshot struct {
+	ID              int64     `json:"id"`
+	AgentID         string    `json:"agentId"`
+	TotalHourlyCost float64   `json:"totalHourlyCost"`
+	Summary         string    `json:"-"`
+	Namespaces      string    `json:"-"`
+	Nodes           string    `json:"-"`
+	Workloads       string    `json:"-"`
+	CollectedAt     time.Time `json:"collectedAt"`
+}
+
+// AgentMonitorGroupID is the group holding the implicit health monitors of cost agents.
+const AgentMonitorGroupID = "g-agents"
+
+// agentMonitorInterval is the check interval (seconds) of agent health monitors.
+const agentMonitorInterval = 60
+
+// AgentHealthURL returns the health endpoint checked by an agent's implicit monitor.
+func AgentHealthURL(agentURL string) string {
+	return strings.TrimRight(agentURL, "/") + "/api/health"
+}
+
+const agentColumns = "id, name, url, COALESCE(token, ''), active, interval_seconds, last_polled_at, COALESCE(last_error, ''), created_at"
+
+func scanAgent(row rowScanner) (Agent, error) {
+	var a Agent
+	var lastPolled sql.NullTime
+	if err := row.Scan(&a.ID, &a.Name, &a.URL, &a.Token, &a.Active, &a.Interval, &lastPolled, &a.LastError, &a.CreatedAt); err != nil {
+		return a, err
+	}
+	if lastPolled.Valid {
+		a.LastPolledAt = &lastPolled.Time
+	}
+	return a, nil
+}
+
+// CreateAgent inserts an agent together with its implicit "agent" monitor. Neither is
+// stored when the monitor can't be, e.g. because its name is taken.
+func (s *Store) CreateAgent(a Agent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec(s.rebind("INSERT INTO agents (id, name, url, token, active, interval_seconds, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		a.ID, a.Name, a.URL, a.Token, a.Active, a.Interval, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.upsertAgentMonitor(tx, a); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertAgentMonitor creates or refreshes the monitor (same ID as the agent) that checks the agent's health.
+func (s *Store) upsertAgentMonitor(tx *sql.Tx, a Agent) error {
+	if _, err := tx.Exec(s.rebind("INSERT INTO groups (id, name, icon) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING"),
+		AgentMonitorGroupID, "Agents", "Server"); err != nil {
+		return err
+	}
+	_, err := tx.Exec(s.rebind(`
+		INSERT INTO monitors (id, group_id, name, url, active, interval_seconds, monitor_type, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, url = excluded.url, active = excluded.active
+	`), a.ID, AgentMonitorGroupID, a.Name+" (agent)", AgentHealthURL(a.URL), a.Active, agentMonitorInterval, MonitorTypeAgent, time.Now())
+	return monitorWriteError(err)
+}
+
+func (s *Store) GetAgents() ([]Agent, error) {
+	rows, err := s.db.Query("SELECT " + agentColumns + " FROM agents ORDER BY created_at ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var agents []Agent
+	for rows.Next() {
+		a, err := scanAgent(rows)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, a)
+	}
+	return agents, nil
+}
+
+// GetAgent returns a single agent by ID, or ErrAgentNotFound.
+func (s *Store) GetAgent(id string) (*Agent, error) {
+	a, err := scanAgent(s.db.QueryRow(s.rebind("SELECT "+agentColumns+" FROM agents WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrAgentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UpdateAgent updates an agent's editable fields and its monitor. An empty token keeps
+// the stored one.
+func (s *Store) UpdateAgent(a Agent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var res sql.Result
+	if a.Token != "" {
+		res, err = tx.Exec(s.rebind("UPDATE agents SET name = ?, url = ?, token = ?, active = ?, interval_seconds = ? WHERE id = ?"),
+			a.Name, a.URL, a.Token, a.Active, a.Interval, a.ID)
+	} else {
+		res, err = tx.Exec(s.rebind("UPDATE agents SET name = ?, url = ?, active = ?, interval_seconds = ? WHERE id = ?"),
+			a.Name, a.URL, a.Active, a.Interval, a.ID)
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAgentNotFound
+	}
+	if err := s.upsertAgentMonitor(tx, a); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteAgent removes an agent, its snapshots and its implicit monitor.
+func (s *Store) DeleteAgent(id string) error {
+	if _, err := s.db.Exec(s.rebind("DELETE FROM monitors WHERE id = ? AND monitor_type = ?"), id, MonitorTypeAgent); err != nil {
+		return err
+	}
+	if err := s.dropChecks(id); err != nil {
+		return err
+	}
+	res, err := s.db.Exec(s.rebind("DELETE FROM agents WHERE id = ?"), id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAgentNotFound
+	}
+	return nil
+}
+
+// RecordAgentPoll stores the time and outcome of the latest poll. An empty pollErr clears the last error.
+func (s *Store) RecordAgentPoll(id string, at time.Time, pollErr string) error {
+	var lastErr sql.NullString
+	if pollErr != "" {
+		lastErr = sql.NullString{String: pollErr, Valid: true}
+	}
+	_, err := s.db.Exec(s.rebind("UPDATE agents SET last_polled_at = ?, last_error = ? WHERE id = ?"), at, lastErr, id)
+	return err
+}
+
+func (s *Store) SaveAgentSnapshot(snap AgentSnapshot) error {
+	if snap.CollectedAt.IsZero() {
+		snap.CollectedAt = time.Now()
+	}
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO agent_snapshots (agent_id, total_hourly_cost, summary, namespaces, nodes, workloads, collected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`), snap.AgentID, snap.TotalHourlyCost, snap.Summary, snap.Namespaces, snap.Nodes, snap.Workloads, snap.CollectedAt)
+	return err
+}
+
+// GetLatestAgentSnapshot returns the most recent snapshot for an agent, or nil if none exists yet.
+func (s *Store) GetLatestAgentSnapshot(agentID string) (*AgentSnapshot, error) {
+	var snap AgentSnapshot
+	var summary, namespaces, nodes, workloads sql.NullString
+	err := s.db.QueryRow(s.rebind(`
+		SELECT id, agent_id, total_hourly_cost, summary, namespaces, nodes, workloads, collected_at
+		FROM agent_snapshots
+		WHERE agent_id = ?
+		ORDER BY collected_at DESC, id DESC
+		LIMIT 1
+	`), agentID).Scan(&snap.ID, &snap.AgentID, &snap.TotalHourlyCost, &summary, &namespaces, &nodes, &workloads, &snap.CollectedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snap.Summary = summary.String
+	snap.Namespaces = namespaces.String
+	snap.Nodes = nodes.String
+	snap.Workloads = workloads.String
+	return &snap, nil
+}
+
+// PruneAgentSnapshots deletes snapshots collected before the cutoff.
+func (s *Store) PruneAgentSnapshots(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM agent_snapshots WHERE collected_at < ?"), before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAgentCRUD(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.CreateAgent(Agent{ID: "a1", Name: "prod", URL: "http://agent:8080", Token: "t1", Active: true, Interval: 300}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+
+	a, err := s.GetAgent("a1")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if a.Token != "t1" || !a.Active || a.Interval != 300 || a.LastPolledAt != nil {
+		t.Errorf("Unexpected agent: %+v", a)
+	}
+
+	// Empty token keeps the stored one
+	a.Name = "prod-eu"
+	a.Token = ""
+	if err := s.UpdateAgent(*a); err != nil {
+		t.Fatalf("UpdateAgent failed: %v", err)
+	}
+	a, _ = s.GetAgent("a1")
+	if a.Name != "prod-eu" || a.Token != "t1" {
+		t.Errorf("Expected name updated and token kept, got %+v", a)
+	}
+
+	if err := s.RecordAgentPoll("a1", time.Now(), "connection refused"); err != nil {
+		t.Fatalf("RecordAgentPoll failed: %v", err)
+	}
+	a, _ = s.GetAgent("a1")
+	if a.LastPolledAt == nil || a.LastError != "connection refused" {
+		t.Errorf("Expected poll outcome recorded, got %+v", a)
+	}
+
+	// Implicit health monitor follows the agent
+	mon, err := s.GetMonitor("a1")
+	if err != nil {
+		t.Fatalf("Expected agent monitor: %v", err)
+	}
+	if mon.Type != MonitorTypeAgent || mon.GroupID != AgentMonitorGroupID || mon.Name != "prod-eu (agent)" {
+		t.Errorf("Unexpected agent monitor: %+v", mon)
+	}
+
+	if err := s.DeleteAgent("a1"); err != nil {
+		t.Fatalf("DeleteAgent failed: %v", err)
+	}
+	if _, err := s.GetMonitor("a1"); !errors.Is(err, ErrMonitorNotFound) {
+		t.Errorf("Expected agent monitor to be deleted, got %v", err)
+	}
+	if _, err := s.GetAgent("a1"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Expected ErrAgentNotFound, got %v", err)
+	}
+	if err := s.DeleteAgent("a1"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Expected ErrAgentNotFound on second delete, got %v", err)
+	}
+}
+
+func TestAgentSnapshots(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateAgent(Agent{ID: "a1", Name: "prod", URL: "http://agent:8080", Active: true, Interval: 300})
+
+	if snap, err := s.GetLatestAgentSnapshot("a1"); err != nil || snap != nil {
+		t.Fatalf("Expected no snapshot, got %v (err %v)", snap, err)
+	}
+
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	_ = s.SaveAgentSnapshot(AgentSnapshot{AgentID: "a1", TotalHourlyCost: 1, Summary: "{}", CollectedAt: old})
+	_ = s.SaveAgentSnapshot(AgentSnapshot{AgentID: "a1", TotalHourlyCost: 2, Summary: `{"clusterName":"prod"}`})
+
+	snap, err := s.GetLatestAgentSnapshot("a1")
+	if err != nil || snap == nil {
+		t.Fatalf("GetLatestAgentSnapshot failed: %v", err)
+	}
+	if snap.TotalHourlyCost != 2 || snap.Summary != `{"clusterName":"prod"}` {
+		t.Errorf("Expected latest snapshot, got %+v", snap)
+	}
+
+	n, err := s.PruneAgentSnapshots(time.Now().Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("PruneAgentSnapshots failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 pruned snapshot, got %d", n)
+	}
+}
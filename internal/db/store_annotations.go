@@ -0,0 +1,136 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Annotation kinds
+const (
+	AnnotationKindDeploy = "deploy"
+	AnnotationKindConfig = "config"
+	AnnotationKindOther  = "other"
+)
+
+// Annotation marks an event such as a deploy or config change on a monitor's latency chart.
+type Annotation struct {
+	ID        int64     `json:"id"`
+	MonitorID string    `json:"monitorId"`
+	BatchID   string    `json:"batchId,omitempty"` // Shared by all rows of one fleet-wide annotation
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AnnotationEvent is an annotation joined with its monitor and group, for the events timeline.
+type AnnotationEvent struct {
+	Annotation
+	MonitorName string `json:"monitorName"`
+	GroupID     string `json:"groupId"`
+	GroupName   string `json:"groupName"`
+}
+
+const annotationInsert = "INSERT INTO monitor_annotations (monitor_id, batch_id, kind, message, timestamp, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+
+// CreateAnnotation inserts an annotation and returns its ID.
+func (s *Store) CreateAnnotation(a Annotation) (int64, error) {
+	if a.Timestamp.IsZero() {
+		a.Timestamp = time.Now()
+	}
+	batchID := sql.NullString{String: a.BatchID, Valid: a.BatchID != ""}
+	if s.IsPostgres() {
+		var id int64
+		err := s.db.QueryRow(s.rebind(annotationInsert+" RETURNING id"),
+			a.MonitorID, batchID, a.Kind, a.Message, a.Timestamp.UTC(), time.Now()).Scan(&id)
+		return id, err
+	}
+	res, err := s.db.Exec(annotationInsert, a.MonitorID, batchID, a.Kind, a.Message, a.Timestamp.UTC(), time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// CreateAnnotations inserts the per-monitor rows of a fleet-wide annotation in one transaction.
+func (s *Store) CreateAnnotations(annotations []Annotation) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(s.rebind(annotationInsert))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	now := time.Now()
+	for _, a := range annotations {
+		if a.Timestamp.IsZero() {
+			a.Timestamp = now
+		}
+		batchID := sql.NullString{String: a.BatchID, Valid: a.BatchID != ""}
+		if _, err := stmt.Exec(a.MonitorID, batchID, a.Kind, a.Message, a.Timestamp.UTC(), now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAnnotations returns a monitor's annotations since the given time, oldest first.
+func (s *Store) GetAnnotations(monitorID string, since time.Time) ([]Annotation, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT id, monitor_id, COALESCE(batch_id, ''), kind, message, timestamp, created_at
+		FROM monitor_annotations
+		WHERE monitor_id = ? AND timestamp >= ?
+		ORDER BY timestamp ASC
+	`), monitorID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var annotations []Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.ID, &a.MonitorID, &a.BatchID, &a.Kind, &a.Message, &a.Timestamp, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, nil
+}
+
+// GetAnnotationEvents returns annotations of all monitors since the given time, newest first.
+func (s *Store) GetAnnotationEvents(since time.Time) ([]AnnotationEvent, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT a.id, a.monitor_id, COALESCE(a.batch_id, ''), a.kind, a.message, a.timestamp, a.created_at,
+			m.name, m.group_id, COALESCE(g.name, '')
+		FROM monitor_annotations a
+		JOIN monitors m ON a.monitor_id = m.id
+		LEFT JOIN groups g ON m.group_id = g.id
+		WHERE a.timestamp >= ?
+		ORDER BY a.timestamp DESC, a.id ASC
+	`), since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []AnnotationEvent
+	for rows.Next() {
+		var e AnnotationEvent
+		if err := rows.Scan(&e.ID, &e.MonitorID, &e.BatchID, &e.Kind, &e.Message, &e.Timestamp, &e.CreatedAt,
+			&e.MonitorName, &e.GroupID, &e.GroupName); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
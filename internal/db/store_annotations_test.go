@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", URL: "http://test.com", Interval: 60})
+
+	now := time.Now()
+	id, err := s.CreateAnnotation(Annotation{MonitorID: "m1", Kind: AnnotationKindDeploy, Message: "v1.2.0", Timestamp: now.Add(-time.Hour)})
+	if err != nil || id == 0 {
+		t.Fatalf("CreateAnnotation failed: id=%d err=%v", id, err)
+	}
+	_, _ = s.CreateAnnotation(Annotation{MonitorID: "m1", Kind: AnnotationKindConfig, Message: "old", Timestamp: now.Add(-48 * time.Hour)})
+
+	list, err := s.GetAnnotations("m1", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetAnnotations failed: %v", err)
+	}
+	if len(list) != 1 || list[0].Message != "v1.2.0" || list[0].Kind != AnnotationKindDeploy {
+		t.Errorf("Expected only the recent annotation, got %+v", list)
+	}
+
+	// Annotations go away with their monitor
+	_ = s.DeleteMonitor("m1")
+	list, _ = s.GetAnnotations("m1", now.Add(-72*time.Hour))
+	if len(list) != 0 {
+		t.Errorf("Expected annotations to be deleted with the monitor, got %+v", list)
+	}
+}
+
+func TestFleetAnnotationsAndTags(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", URL: "http://a.com", Interval: 60, Tags: []string{"prod", "api"}})
+	_ = s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "M2", URL: "http://b.com", Interval: 60})
+
+	m, err := s.GetMonitor("m1")
+	if err != nil || !m.HasTag("prod") || !m.HasTag("api") || m.HasTag("staging") {
+		t.Fatalf("Expected tags to round-trip, got %+v (err %v)", m, err)
+	}
+	if err := s.SetMonitorTags("m2", []string{"staging"}); err != nil {
+		t.Fatalf("SetMonitorTags failed: %v", err)
+	}
+	if m2, _ := s.GetMonitor("m2"); !m2.HasTag("staging") {
+		t.Errorf("Expected m2 to be tagged staging, got %+v", m2.Tags)
+	}
+	if err := s.SetMonitorTags("missing", nil); err != ErrMonitorNotFound {
+		t.Errorf("Expected ErrMonitorNotFound, got %v", err)
+	}
+
+	now := time.Now()
+	err = s.CreateAnnotations([]Annotation{
+		{MonitorID: "m1", BatchID: "b1", Kind: AnnotationKindDeploy, Message: "v2", Timestamp: now},
+		{MonitorID: "m2", BatchID: "b1", Kind: AnnotationKindDeploy, Message: "v2", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnotations failed: %v", err)
+	}
+
+	events, err := s.GetAnnotationEvents(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetAnnotationEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].BatchID != "b1" || events[0].GroupName != "G1" || events[0].MonitorName == "" {
+		t.Errorf("Unexpected annotation events: %+v", events)
+	}
+}
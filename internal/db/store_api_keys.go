@@ -4,20 +4,91 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrCredentialLocked is returned by ValidateAPIKey and ValidateUserToken while the
+// client is locked out of a prefix after repeated wrong credentials.
+var ErrCredentialLocked = errors.New("too many invalid attempts, try again later")
+
+// ErrAPIKeyNetworkDenied is returned by ValidateAPIKey for a valid key used from an
+// address outside its allow-list.
+var ErrAPIKeyNetworkDenied = errors.New("API key not allowed from this address")
+
 type APIKey struct {
-	ID        int64      `json:"id"`
-	KeyPrefix string     `json:"keyPrefix"`
-	Name      string     `json:"name"`
-	CreatedAt time.Time  `json:"createdAt"`
-	LastUsed  *time.Time `json:"lastUsed,omitempty"`
+	ID           int64      `json:"id"`
+	KeyPrefix    string     `json:"keyPrefix"`
+	Name         string     `json:"name"`
+	AllowedCIDRs []string   `json:"allowedCidrs,omitempty"` // Networks the key may be used from; empty allows any
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsed     *time.Time `json:"lastUsed,omitempty"`
+}
+
+// NormalizeCIDRs parses an API key allow-list of CIDRs or bare addresses, which
+// become single-address networks, and returns it in canonical form.
+func NormalizeCIDRs(cidrs []string) ([]string, error) {
+	out := make([]string, 0, len(cidrs))
+	for _, c := range cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		var prefix netip.Prefix
+		if strings.Contains(c, "/") {
+			p, err := netip.ParsePrefix(c)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q", c)
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(c)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP address %q", c)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		out = append(out, prefix.String())
+	}
+	return out, nil
 }
 
-func (s *Store) CreateAPIKey(name string) (string, error) {
+// allowedFrom reports whether ip falls within one of cidrs, a comma-separated
+// allow-list as stored. An empty list allows any address.
+func allowedFrom(cidrs, ip string) bool {
+	if cidrs == "" {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, c := range strings.Split(cidrs, ",") {
+		if prefix, err := netip.ParsePrefix(c); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey creates a global API key usable from allowedCIDRs, or from anywhere
+// when empty, and returns the raw key, which is not stored.
+func (s *Store) CreateAPIKey(name string, allowedCIDRs []string) (string, error) {
+	cidrs, err := NormalizeCIDRs(allowedCIDRs)
+	if err != nil {
+		return "", err
+	}
+
 	// Generate random key with 256-bit entropy (32 bytes)
 	// SECURITY: 256 bits provides adequate security strength for long-lived credentials
 	keyBytes := make([]byte, 32)
@@ -33,8 +104,8 @@ func (s *Store) CreateAPIKey(name string) (string, error) {
 		return "", err
 	}
 
-	_, err = s.db.Exec(s.rebind("INSERT INTO api_keys (key_prefix, key_hash, name) VALUES (?, ?, ?)"),
-		prefix, string(hash), name)
+	_, err = s.db.Exec(s.rebind("INSERT INTO api_keys (key_prefix, key_hash, name, allowed_cidrs) VALUES (?, ?, ?, ?)"),
+		prefix, string(hash), name, strings.Join(cidrs, ","))
 	if err != nil {
 		return "", err
 	}
@@ -43,7 +114,7 @@ func (s *Store) CreateAPIKey(name string) (string, error) {
 }
 
 func (s *Store) ListAPIKeys() ([]APIKey, error) {
-	rows, err := s.db.Query("SELECT id, key_prefix, name, created_at, last_used_at FROM api_keys ORDER BY created_at DESC")
+	rows, err := s.db.Query("SELECT id, key_prefix, name, allowed_cidrs, created_at, last_used_at FROM api_keys ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -53,9 +124,13 @@ func (s *Store) ListAPIKeys() ([]APIKey, error) {
 	for rows.Next() {
 		var k APIKey
 		var lastUsed sql.NullTime
-		if err := rows.Scan(&k.ID, &k.KeyPrefix, &k.Name, &k.CreatedAt, &lastUsed); err != nil {
+		var cidrs string
+		if err := rows.Scan(&k.ID, &k.KeyPrefix, &k.Name, &cidrs, &k.CreatedAt, &lastUsed); err != nil {
 			return nil, err
 		}
+		if cidrs != "" {
+			k.AllowedCIDRs = strings.Split(cidrs, ",")
+		}
 		if lastUsed.Valid {
 			k.LastUsed = &lastUsed.Time
 		}
@@ -64,41 +139,85 @@ func (s *Store) ListAPIKeys() ([]APIKey, error) {
 	return keys, nil
 }
 
+// SetAPIKeyAllowedCIDRs replaces a key's allow-list; an empty list allows any address.
+func (s *Store) SetAPIKeyAllowedCIDRs(id int64, allowedCIDRs []string) error {
+	cidrs, err := NormalizeCIDRs(allowedCIDRs)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.Exec(s.rebind("UPDATE api_keys SET allowed_cidrs = ? WHERE id = ?"), strings.Join(cidrs, ","), id)
+	if err != nil {
+		return err
+	}
+	s.apiKeyGuard.forget(id)
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
 func (s *Store) DeleteAPIKey(id int64) error {
 	_, err := s.db.Exec(s.rebind("DELETE FROM api_keys WHERE id = ?"), id)
+	s.apiKeyGuard.forget(id)
 	return err
 }
 
-func (s *Store) ValidateAPIKey(key string) (bool, error) {
+// ValidateAPIKey reports whether key is a valid API key for a request from clientIP.
+// A valid key used from outside its allow-list gives ErrAPIKeyNetworkDenied, and a
+// client locked out of the key's prefix after repeated wrong keys gets
+// ErrCredentialLocked.
+func (s *Store) ValidateAPIKey(key, clientIP string) (bool, error) {
 	if len(key) < 12 {
 		return false, nil
 	}
 	prefix := key[:12]
 
+	// Recently validated keys skip bcrypt
+	if c, ok := s.apiKeyGuard.lookup(key); ok {
+		if !allowedFrom(c.cidrs, clientIP) {
+			return false, ErrAPIKeyNetworkDenied
+		}
+		return true, nil
+	}
+	// SECURITY: Stop guessing under a prefix before it costs another round of bcrypt
+	if s.apiKeyGuard.locked(clientIP, prefix) {
+		return false, ErrCredentialLocked
+	}
+
 	// Find candidates by prefix
-	rows, err := s.db.Query(s.rebind("SELECT id, key_hash FROM api_keys WHERE key_prefix = ?"), prefix)
+	rows, err := s.db.Query(s.rebind("SELECT id, key_hash, allowed_cidrs FROM api_keys WHERE key_prefix = ?"), prefix)
 	if err != nil {
 		return false, err
 	}
 	defer func() { _ = rows.Close() }()
 
+	candidates := 0
 	for rows.Next() {
 		var id int64
-		var hash string
-		if err := rows.Scan(&id, &hash); err != nil {
+		var hash, cidrs string
+		if err := rows.Scan(&id, &hash, &cidrs); err != nil {
 			continue
 		}
+		candidates++
 
 		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(key)); err == nil {
-			// update last used async
+			s.apiKeyGuard.remember(key, clientIP, prefix, cachedCredential{id: id, cidrs: cidrs})
+			// SECURITY: A leaked key is useless outside the networks it is meant for
+			if !allowedFrom(cidrs, clientIP) {
+				return false, ErrAPIKeyNetworkDenied
+			}
+			// update last used async; cached validations skip this, so it is accurate
+			// to within the cache TTL
 			go func(keyId int64) {
-				// Create a new generic db execution context or ignore error
-				// Since we are inside store method, s.db is safe to use concurrently? sql.DB is threadsafe.
 				_, _ = s.db.Exec(s.rebind("UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = ?"), keyId)
 			}(id)
 			return true, nil
 		}
 	}
 
+	if candidates > 0 && s.apiKeyGuard.recordFailure(clientIP, prefix) {
+		// The prefix matched a stored key, so it is not arbitrary input
+		log.Printf("AUDIT: [SECURITY] IP %s locked out of API key prefix %q for %s after repeated invalid keys", clientIP, prefix, CredentialLockout)
+	}
 	return false, nil
 }
@@ -1,6 +1,9 @@
 package db
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -8,7 +11,7 @@ func TestAPIKeys(t *testing.T) {
 	s := newTestStore(t)
 
 	// Create
-	key, err := s.CreateAPIKey("Test Key")
+	key, err := s.CreateAPIKey("Test Key", nil)
 	if err != nil {
 		t.Fatalf("CreateAPIKey failed: %v", err)
 	}
@@ -17,7 +20,7 @@ func TestAPIKeys(t *testing.T) {
 	}
 
 	// Validate Access
-	valid, err := s.ValidateAPIKey(key)
+	valid, err := s.ValidateAPIKey(key, "127.0.0.1")
 	if err != nil {
 		t.Fatalf("ValidateAPIKey failed: %v", err)
 	}
@@ -26,7 +29,7 @@ func TestAPIKeys(t *testing.T) {
 	}
 
 	// Validate Fail
-	valid, _ = s.ValidateAPIKey("sk_live_WRONG")
+	valid, _ = s.ValidateAPIKey("sk_live_WRONG", "127.0.0.1")
 	if valid {
 		t.Error("Expected invalid key to be rejected")
 	}
@@ -46,8 +49,100 @@ func TestAPIKeys(t *testing.T) {
 	}
 
 	// Verify Gone
-	valid, _ = s.ValidateAPIKey(key)
+	valid, _ = s.ValidateAPIKey(key, "127.0.0.1")
 	if valid {
 		t.Error("Key should be invalid after deletion")
 	}
 }
+
+func TestAPIKeys_AllowedCIDRs(t *testing.T) {
+	s := newTestStore(t)
+
+	key, err := s.CreateAPIKey("CI", []string{"10.1.2.0/24", " 2001:db8::1 ", "192.168.7.9/16"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	keys, _ := s.ListAPIKeys()
+	want := []string{"10.1.2.0/24", "2001:db8::1/128", "192.168.0.0/16"}
+	if len(keys) != 1 || !reflect.DeepEqual(keys[0].AllowedCIDRs, want) {
+		t.Fatalf("AllowedCIDRs = %v, want %v", keys[0].AllowedCIDRs, want)
+	}
+
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"10.1.2.77", true},
+		{"::ffff:10.1.2.77", true},
+		{"192.168.200.1", true},
+		{"2001:db8::1", true},
+		{"10.1.3.1", false},
+		{"2001:db8::2", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		valid, err := s.ValidateAPIKey(key, tt.ip)
+		if tt.allowed && (!valid || err != nil) {
+			t.Errorf("%q: expected key to be accepted, got (%v, %v)", tt.ip, valid, err)
+		}
+		if !tt.allowed && (valid || !errors.Is(err, ErrAPIKeyNetworkDenied)) {
+			t.Errorf("%q: expected ErrAPIKeyNetworkDenied, got (%v, %v)", tt.ip, valid, err)
+		}
+	}
+
+	// Clearing the allow-list allows any address
+	if err := s.SetAPIKeyAllowedCIDRs(keys[0].ID, nil); err != nil {
+		t.Fatalf("SetAPIKeyAllowedCIDRs failed: %v", err)
+	}
+	if valid, err := s.ValidateAPIKey(key, "203.0.113.5"); !valid || err != nil {
+		t.Errorf("expected key to be accepted anywhere, got (%v, %v)", valid, err)
+	}
+
+	if _, err := s.CreateAPIKey("Bad", []string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an invalid CIDR to be rejected")
+	}
+	if err := s.SetAPIKeyAllowedCIDRs(9999, nil); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestAPIKeys_Lockout(t *testing.T) {
+	s := newTestStore(t)
+	const attacker, client = "203.0.113.9", "198.51.100.7"
+
+	cached, _ := s.CreateAPIKey("Cached", nil)
+	if valid, err := s.ValidateAPIKey(cached, client); !valid || err != nil {
+		t.Fatalf("expected key to be valid, got (%v, %v)", valid, err)
+	}
+	key, _ := s.CreateAPIKey("Fresh", nil)
+
+	// Wrong keys under each stored prefix count towards the client's lockout
+	for _, k := range []string{cached, key} {
+		wrong := k[:12] + strings.Repeat("0", len(k)-12)
+		for i := 0; i < credentialFailureLimit; i++ {
+			if valid, err := s.ValidateAPIKey(wrong, attacker); valid || err != nil {
+				t.Fatalf("attempt %d: expected (false, nil), got (%v, %v)", i+1, valid, err)
+			}
+		}
+	}
+
+	// A locked client is refused without checking the key, unless it validated recently
+	if valid, err := s.ValidateAPIKey(key, attacker); valid || !errors.Is(err, ErrCredentialLocked) {
+		t.Errorf("expected ErrCredentialLocked, got (%v, %v)", valid, err)
+	}
+	if valid, err := s.ValidateAPIKey(cached, attacker); !valid || err != nil {
+		t.Errorf("expected the cached key to keep working, got (%v, %v)", valid, err)
+	}
+
+	// Other clients are not locked out, so a guesser can't lock the real key out
+	if valid, err := s.ValidateAPIKey(key, client); !valid || err != nil {
+		t.Errorf("expected the key to keep working from another IP, got (%v, %v)", valid, err)
+	}
+
+	// Guessing under a prefix no key has costs nothing and is not counted
+	for i := 0; i < credentialFailureLimit+1; i++ {
+		if _, err := s.ValidateAPIKey("sk_live_zzzz"+strings.Repeat("0", 60), attacker); err != nil {
+			t.Fatalf("expected unknown prefixes not to be locked out, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrCheckExclusionsNotFound is returned when a monitor has no check exclusion windows.
+var ErrCheckExclusionsNotFound = errors.New("check exclusions not found")
+
+// CheckExclusionWindow is a recurring time of day in which a monitor isn't checked.
+// Start and End are "HH:MM" clock times; a window whose End is not after its Start runs
+// past midnight into the next day.
+type CheckExclusionWindow struct {
+	Days  []string `json:"days,omitempty"` // Lowercase weekdays the window starts on; empty means every day
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// ParseClock parses an "HH:MM" clock time into minutes since midnight.
+func ParseClock(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// startsOn reports whether the window starts on the given weekday.
+func (w CheckExclusionWindow) startsOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	name := strings.ToLower(day.String())
+	for _, d := range w.Days {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers reports whether the window covers t, a time in the exclusions' timezone.
+func (w CheckExclusionWindow) Covers(t time.Time) bool {
+	start, ok := ParseClock(w.Start)
+	if !ok {
+		return false
+	}
+	end, ok := ParseClock(w.End)
+	if !ok {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return w.startsOn(t.Weekday()) && minute >= start && minute < end
+	}
+	// Past midnight: the evening part belongs to today, the morning part to yesterday
+	return (w.startsOn(t.Weekday()) && minute >= start) || (w.startsOn((t.Weekday()+6)%7) && minute < end)
+}
+
+// MonitorCheckExclusions are the recurring windows, e.g. a nightly batch job, in which a
+// monitor's checks are skipped. No checks are recorded then, so the expected downtime
+// doesn't count against its uptime.
+type MonitorCheckExclusions struct {
+	MonitorID string                 `json:"monitorId"`
+	Timezone  string                 `json:"timezone"` // IANA name the windows' clock times are in
+	Windows   []CheckExclusionWindow `json:"windows"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+// Location returns the exclusions' timezone, UTC when it can't be loaded.
+func (e MonitorCheckExclusions) Location() *time.Location {
+	if loc, err := time.LoadLocation(e.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Excludes reports whether one of the windows covers t.
+func (e MonitorCheckExclusions) Excludes(t time.Time) bool {
+	t = t.In(e.Location())
+	for _, w := range e.Windows {
+		if w.Covers(t) {
+			return true
+		}
+	}
+	return false
+}
+
+const checkExclusionColumns = "monitor_id, timezone, windows, updated_at"
+
+func scanCheckExclusions(row rowScanner) (MonitorCheckExclusions, error) {
+	var e MonitorCheckExclusions
+	var windows string
+	if err := row.Scan(&e.MonitorID, &e.Timezone, &windows, &e.UpdatedAt); err != nil {
+		return e, err
+	}
+	if err := json.Unmarshal([]byte(windows), &e.Windows); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// SetMonitorCheckExclusions creates or replaces a monitor's check exclusion windows.
+func (s *Store) SetMonitorCheckExclusions(e MonitorCheckExclusions) error {
+	if e.Windows == nil {
+		e.Windows = []CheckExclusionWindow{}
+	}
+	if e.Timezone == "" {
+		e.Timezone = "UTC"
+	}
+	windows, err := json.Marshal(e.Windows)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.rebind(`
+		INSERT INTO monitor_check_exclusions (monitor_id, timezone, windows, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (monitor_id) DO UPDATE SET timezone = excluded.timezone, windows = excluded.windows, updated_at = excluded.updated_at`),
+		e.MonitorID, e.Timezone, string(windows), time.Now().UTC())
+	return err
+}
+
+// GetMonitorCheckExclusions returns a monitor's check exclusion windows, or
+// ErrCheckExclusionsNotFound.
+func (s *Store) GetMonitorCheckExclusions(monitorID string) (*MonitorCheckExclusions, error) {
+	e, err := scanCheckExclusions(s.db.QueryRow(s.rebind("SELECT "+checkExclusionColumns+" FROM monitor_check_exclusions WHERE monitor_id = ?"), monitorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCheckExclusionsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetAllMonitorCheckExclusions returns the check exclusion windows of every monitor that
+// has some, keyed by monitor ID.
+func (s *Store) GetAllMonitorCheckExclusions() (map[string]MonitorCheckExclusions, error) {
+	rows, err := s.db.Query("SELECT " + checkExclusionColumns + " FROM monitor_check_exclusions")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	exclusions := make(map[string]MonitorCheckExclusions)
+	for rows.Next() {
+		e, err := scanCheckExclusions(rows)
+		if err != nil {
+			return nil, err
+		}
+		exclusions[e.MonitorID] = e
+	}
+	return exclusions, rows.Err()
+}
+
+// DeleteMonitorCheckExclusions removes a monitor's check exclusion windows and reports
+// whether it had any.
+func (s *Store) DeleteMonitorCheckExclusions(monitorID string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM monitor_check_exclusions WHERE monitor_id = ?"), monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
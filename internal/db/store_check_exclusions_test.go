@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorCheckExclusions(t *testing.T) {
+	store := newTestStore(t)
+	_ = store.CreateGroup(Group{ID: "g-1", Name: "Batch"})
+	_ = store.CreateMonitor(Monitor{ID: "m-1", GroupID: "g-1", Name: "ETL", URL: "http://etl", Interval: 60})
+
+	if _, err := store.GetMonitorCheckExclusions("m-1"); !errors.Is(err, ErrCheckExclusionsNotFound) {
+		t.Errorf("Expected ErrCheckExclusionsNotFound, got %v", err)
+	}
+	if err := store.SetMonitorCheckExclusions(MonitorCheckExclusions{MonitorID: "m-1", Windows: []CheckExclusionWindow{{Start: "01:00", End: "03:00"}}}); err != nil {
+		t.Fatalf("SetMonitorCheckExclusions failed: %v", err)
+	}
+	_ = store.SetMonitorCheckExclusions(MonitorCheckExclusions{MonitorID: "m-1", Timezone: "America/New_York",
+		Windows: []CheckExclusionWindow{{Days: []string{"saturday"}, Start: "22:00", End: "04:00"}}})
+	e, err := store.GetMonitorCheckExclusions("m-1")
+	if err != nil || e.Timezone != "America/New_York" || len(e.Windows) != 1 || e.Windows[0].Days[0] != "saturday" {
+		t.Errorf("Expected the exclusions to be replaced, got %+v (%v)", e, err)
+	}
+	if all, _ := store.GetAllMonitorCheckExclusions(); len(all) != 1 || all["m-1"].Windows[0].End != "04:00" {
+		t.Errorf("Unexpected exclusions %v", all)
+	}
+	if deleted, _ := store.DeleteMonitorCheckExclusions("m-1"); !deleted {
+		t.Error("Expected the exclusions to be deleted")
+	}
+	if deleted, _ := store.DeleteMonitorCheckExclusions("m-1"); deleted {
+		t.Error("Expected nothing to delete")
+	}
+}
+
+func TestCheckExclusionsExcludes(t *testing.T) {
+	e := MonitorCheckExclusions{Timezone: "America/New_York", Windows: []CheckExclusionWindow{
+		{Start: "01:00", End: "03:00"},
+		{Days: []string{"saturday"}, Start: "22:00", End: "04:00"},
+	}}
+	ny, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, minute int) time.Time { // March 2026: the 7th is a Saturday
+		return time.Date(2026, 3, day, hour, minute, 0, 0, ny).UTC()
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"daily window", at(4, 2, 0), true},
+		{"daily window start", at(4, 1, 0), true},
+		{"daily window end is exclusive", at(4, 3, 0), false},
+		{"outside", at(4, 12, 0), false},
+		{"saturday night", at(7, 23, 30), true},
+		{"past midnight into sunday", at(8, 3, 59), true},
+		{"friday night", at(6, 23, 30), false},
+		{"monday early morning after the daily window", at(9, 3, 30), false},
+	}
+	for _, tt := range tests {
+		if got := e.Excludes(tt.t); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
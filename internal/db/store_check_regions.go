@@ -0,0 +1,32 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LocalCheckRegion names the checks the server runs itself, as opposed to results an
+// agent reports from its region.
+const LocalCheckRegion = "local"
+
+// RegionSummary is the latest check and uptime of one region reporting on a monitor.
+type RegionSummary struct {
+	Region    string    `json:"region"`
+	Status    string    `json:"status"` // Of the latest check: up | down
+	Latency   int64     `json:"latency"`
+	LastCheck time.Time `json:"lastCheck"`
+	Checks    int       `json:"checks"`
+	UpChecks  int       `json:"upChecks"`
+	Uptime    float64   `json:"uptime"` // Percentage of up checks
+}
+
+func nullableRegion(region string) sql.NullString {
+	return sql.NullString{String: region, Valid: region != "" && region != LocalCheckRegion}
+}
+
+// GetRegionSummaries summarizes the monitor's checks since the given time per region,
+// with the server's own checks first and then regions by name. Regions with no checks in
+// that time are left out.
+func (s *Store) GetRegionSummaries(monitorID string, since time.Time) ([]RegionSummary, error) {
+	return s.checks.RegionSummaries(monitorID, since)
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetRegionSummaries(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	if err := s.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 80, Timestamp: now.Add(-3 * time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 90, Timestamp: now.Add(-time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 200, Timestamp: now.Add(-4 * time.Minute), Region: "eu-west"},
+		{MonitorID: "m1", Status: "down", Timestamp: now.Add(-2 * time.Minute), Region: "eu-west"},
+		{MonitorID: "m1", Status: "up", Latency: 150, Timestamp: now.Add(-5 * time.Minute), Region: "ap-south"},
+		// Outside the window
+		{MonitorID: "m1", Status: "down", Timestamp: now.Add(-2 * 24 * time.Hour), Region: "us-east"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	summaries, err := s.GetRegionSummaries("m1", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetRegionSummaries: %v", err)
+	}
+	if len(summaries) != 3 {
+		t.Fatalf("Expected 3 regions, got %+v", summaries)
+	}
+	local, ap, eu := summaries[0], summaries[1], summaries[2]
+	if local.Region != LocalCheckRegion || local.Checks != 2 || local.Uptime != 100 || local.Latency != 90 {
+		t.Errorf("Unexpected local summary: %+v", local)
+	}
+	if ap.Region != "ap-south" || ap.Status != "up" || ap.Checks != 1 {
+		t.Errorf("Unexpected ap-south summary: %+v", ap)
+	}
+	if eu.Region != "eu-west" || eu.Status != "down" || eu.Checks != 2 || eu.UpChecks != 1 || eu.Uptime != 50 {
+		t.Errorf("Unexpected eu-west summary: %+v", eu)
+	}
+	if !eu.LastCheck.Equal(now.Add(-2 * time.Minute)) {
+		t.Errorf("Expected the latest eu-west check time, got %v", eu.LastCheck)
+	}
+
+	checks, err := s.GetMonitorChecks("m1", 1)
+	if err != nil || len(checks) != 1 || checks[0].Region != "" {
+		t.Errorf("Expected the latest check to be local, got %+v, %v", checks, err)
+	}
+}
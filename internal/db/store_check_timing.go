@@ -0,0 +1,74 @@
+package db
+
+import "database/sql"
+
+// CheckTiming breaks an HTTP check down into sequential phases, in milliseconds, so
+// network slowness can be told apart from backend slowness. DNS, Connect and TLS are
+// zero when a pooled connection is reused; TTFB runs from the request being sent to the
+// first response byte (the server's share) and Download covers reading the body.
+type CheckTiming struct {
+	DNS       int64 `json:"dnsMs"`
+	Connect   int64 `json:"connectMs"`
+	TLS       int64 `json:"tlsMs"`
+	TTFB      int64 `json:"ttfbMs"`
+	Download  int64 `json:"downloadMs"`
+	BodyBytes int64 `json:"bodyBytes"`
+}
+
+// timingColumns are the monitor_checks columns holding a CheckTiming, in field order.
+const timingColumns = "dns_ms, connect_ms, tls_ms, ttfb_ms, download_ms, body_bytes"
+
+// timingArgs returns the column values of t, all NULL when the check has no timing.
+func timingArgs(t *CheckTiming) []any {
+	if t == nil {
+		return []any{nil, nil, nil, nil, nil, nil}
+	}
+	return []any{t.DNS, t.Connect, t.TLS, t.TTFB, t.Download, t.BodyBytes}
+}
+
+// nullTiming scans the timing columns of a check or the sums of an aggregate.
+type nullTiming [6]sql.NullInt64
+
+func (n *nullTiming) dest() []any {
+	return []any{&n[0], &n[1], &n[2], &n[3], &n[4], &n[5]}
+}
+
+// timing returns the scanned timing divided by count, or nil if no timing was recorded.
+func (n *nullTiming) timing(count int64) *CheckTiming {
+	if !n[3].Valid || count < 1 {
+		return nil
+	}
+	return &CheckTiming{
+		DNS:       n[0].Int64 / count,
+		Connect:   n[1].Int64 / count,
+		TLS:       n[2].Int64 / count,
+		TTFB:      n[3].Int64 / count,
+		Download:  n[4].Int64 / count,
+		BodyBytes: n[5].Int64 / count,
+	}
+}
+
+// add accumulates the timing sums of another slot into t.
+func (t *CheckTiming) add(o CheckTiming) {
+	t.DNS += o.DNS
+	t.Connect += o.Connect
+	t.TLS += o.TLS
+	t.TTFB += o.TTFB
+	t.Download += o.Download
+	t.BodyBytes += o.BodyBytes
+}
+
+// average returns the timing sums in t divided by count, or nil if count is zero.
+func (t CheckTiming) average(count int64) *CheckTiming {
+	if count < 1 {
+		return nil
+	}
+	return &CheckTiming{
+		DNS:       t.DNS / count,
+		Connect:   t.Connect / count,
+		TLS:       t.TLS / count,
+		TTFB:      t.TTFB / count,
+		Download:  t.Download / count,
+		BodyBytes: t.BodyBytes / count,
+	}
+}
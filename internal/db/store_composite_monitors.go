@@ -0,0 +1,82 @@
+package db
+
+import "sort"
+
+// CompositeRule defines a composite monitor: it is up while at least MinUp of its
+// member monitors are up.
+type CompositeRule struct {
+	MonitorID string   `json:"monitorId"`
+	MinUp     int      `json:"minUp"`
+	Members   []string `json:"members"`
+}
+
+// GetCompositeRules returns the rules of all composite monitors, keyed by monitor ID.
+// Members are sorted by ID.
+func (s *Store) GetCompositeRules() (map[string]CompositeRule, error) {
+	rows, err := s.db.Query("SELECT monitor_id, min_up FROM composite_monitors")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rules := make(map[string]CompositeRule)
+	for rows.Next() {
+		var r CompositeRule
+		if err := rows.Scan(&r.MonitorID, &r.MinUp); err != nil {
+			return nil, err
+		}
+		rules[r.MonitorID] = r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	memberRows, err := s.db.Query("SELECT composite_id, member_id FROM composite_monitor_members ORDER BY composite_id, member_id")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = memberRows.Close() }()
+
+	for memberRows.Next() {
+		var compositeID, memberID string
+		if err := memberRows.Scan(&compositeID, &memberID); err != nil {
+			return nil, err
+		}
+		if r, ok := rules[compositeID]; ok {
+			r.Members = append(r.Members, memberID)
+			rules[compositeID] = r
+		}
+	}
+	return rules, memberRows.Err()
+}
+
+// SetCompositeRule creates or replaces a composite monitor's rule and member list.
+func (s *Store) SetCompositeRule(r CompositeRule) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM composite_monitor_members WHERE composite_id = ?"), r.MonitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("DELETE FROM composite_monitors WHERE monitor_id = ?"), r.MonitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("INSERT INTO composite_monitors (monitor_id, min_up) VALUES (?, ?)"), r.MonitorID, r.MinUp); err != nil {
+		return err
+	}
+
+	members := append([]string(nil), r.Members...)
+	sort.Strings(members)
+	for i, id := range members {
+		if i > 0 && members[i-1] == id {
+			continue
+		}
+		if _, err := tx.Exec(s.rebind("INSERT INTO composite_monitor_members (composite_id, member_id) VALUES (?, ?)"), r.MonitorID, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
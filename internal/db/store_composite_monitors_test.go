@@ -0,0 +1,54 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompositeRules(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"eu", "us", "ap"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, URL: "http://" + id + ".example.com", Active: true, Interval: 60}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CreateMonitor(Monitor{ID: "global", GroupID: "g1", Name: "Global", Type: MonitorTypeComposite, Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetCompositeRule(CompositeRule{MonitorID: "global", MinUp: 1, Members: []string{"eu", "us"}}); err != nil {
+		t.Fatalf("SetCompositeRule: %v", err)
+	}
+	// Replacing the rule replaces the member list
+	if err := s.SetCompositeRule(CompositeRule{MonitorID: "global", MinUp: 2, Members: []string{"us", "eu", "ap", "us"}}); err != nil {
+		t.Fatalf("SetCompositeRule: %v", err)
+	}
+
+	rules, err := s.GetCompositeRules()
+	if err != nil {
+		t.Fatalf("GetCompositeRules: %v", err)
+	}
+	want := CompositeRule{MonitorID: "global", MinUp: 2, Members: []string{"ap", "eu", "us"}}
+	if len(rules) != 1 || !reflect.DeepEqual(rules["global"], want) {
+		t.Fatalf("expected %+v, got %+v", want, rules)
+	}
+
+	// Deleting a member drops it from the rule; deleting the composite drops the rule
+	if err := s.DeleteMonitor("ap"); err != nil {
+		t.Fatal(err)
+	}
+	rules, _ = s.GetCompositeRules()
+	if got := rules["global"].Members; !reflect.DeepEqual(got, []string{"eu", "us"}) {
+		t.Fatalf("expected members [eu us] after deleting ap, got %v", got)
+	}
+	if err := s.DeleteMonitor("global"); err != nil {
+		t.Fatal(err)
+	}
+	rules, _ = s.GetCompositeRules()
+	if len(rules) != 0 {
+		t.Fatalf("expected no rules after deleting the composite, got %+v", rules)
+	}
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrBudgetNotFound is returned when a cost budget is not found
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// CostBudget is a monthly spend limit for a cluster, namespace, or label value.
+type CostBudget struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	AgentID            string    `json:"agentId,omitempty"` // Empty = all agents
+	Scope              string    `json:"scope"`             // cluster | namespace | label
+	ScopeValue         string    `json:"scopeValue"`        // Namespace name, or "key=value" for labels
+	MonthlyLimit       float64   `json:"monthlyLimit"`
+	Enabled            bool      `json:"enabled"`
+	LastNotifiedPeriod string    `json:"lastNotifiedPeriod,omitempty"` // YYYY-MM of the last exceeded notification
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+const budgetColumns = "id, name, COALESCE(agent_id, ''), scope, scope_value, monthly_limit, enabled, COALESCE(last_notified_period, ''), created_at"
+
+func scanBudget(row rowScanner) (CostBudget, error) {
+	var b CostBudget
+	err := row.Scan(&b.ID, &b.Name, &b.AgentID, &b.Scope, &b.ScopeValue, &b.MonthlyLimit, &b.Enabled, &b.LastNotifiedPeriod, &b.CreatedAt)
+	return b, err
+}
+
+func nullableAgentID(id string) sql.NullString {
+	return sql.NullString{String: id, Valid: id != ""}
+}
+
+// CreateCostBudget inserts a budget and returns its ID.
+func (s *Store) CreateCostBudget(b CostBudget) (int64, error) {
+	if s.IsPostgres() {
+		var id int64
+		err := s.db.QueryRow("INSERT INTO cost_budgets (name, agent_id, scope, scope_value, monthly_limit, enabled, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+			b.Name, nullableAgentID(b.AgentID), b.Scope, b.ScopeValue, b.MonthlyLimit, b.Enabled, time.Now()).Scan(&id)
+		return id, err
+	}
+	res, err := s.db.Exec("INSERT INTO cost_budgets (name, agent_id, scope, scope_value, monthly_limit, enabled, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		b.Name, nullableAgentID(b.AgentID), b.Scope, b.ScopeValue, b.MonthlyLimit, b.Enabled, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (s *Store) GetCostBudgets() ([]CostBudget, error) {
+	rows, err := s.db.Query("SELECT " + budgetColumns + " FROM cost_budgets ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var budgets []CostBudget
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
+
+// GetCostBudget returns a single budget by ID, or ErrBudgetNotFound.
+func (s *Store) GetCostBudget(id int64) (*CostBudget, error) {
+	b, err := scanBudget(s.db.QueryRow(s.rebind("SELECT "+budgetColumns+" FROM cost_budgets WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// UpdateCostBudget updates a budget's definition. Changing it re-arms the monthly notification.
+func (s *Store) UpdateCostBudget(b CostBudget) error {
+	res, err := s.db.Exec(s.rebind("UPDATE cost_budgets SET name = ?, agent_id = ?, scope = ?, scope_value = ?, monthly_limit = ?, enabled = ?, last_notified_period = NULL WHERE id = ?"),
+		b.Name, nullableAgentID(b.AgentID), b.Scope, b.ScopeValue, b.MonthlyLimit, b.Enabled, b.ID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+func (s *Store) DeleteCostBudget(id int64) error {
+	res, err := s.db.Exec(s.rebind("DELETE FROM cost_budgets WHERE id = ?"), id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// SetCostBudgetNotified records the period (YYYY-MM) for which the exceeded notification was sent.
+func (s *Store) SetCostBudgetNotified(id int64, period string) error {
+	_, err := s.db.Exec(s.rebind("UPDATE cost_budgets SET last_notified_period = ? WHERE id = ?"), period, id)
+	return err
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCostBudgetCRUD(t *testing.T) {
+	s := newTestStore(t)
+
+	id, err := s.CreateCostBudget(CostBudget{Name: "Payments", Scope: CostScopeNamespace, ScopeValue: "payments", MonthlyLimit: 500, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateCostBudget failed: %v", err)
+	}
+
+	b, err := s.GetCostBudget(id)
+	if err != nil {
+		t.Fatalf("GetCostBudget failed: %v", err)
+	}
+	if b.AgentID != "" || b.ScopeValue != "payments" || b.MonthlyLimit != 500 || !b.Enabled {
+		t.Errorf("Unexpected budget: %+v", b)
+	}
+
+	if err := s.SetCostBudgetNotified(id, "2026-10"); err != nil {
+		t.Fatalf("SetCostBudgetNotified failed: %v", err)
+	}
+
+	// Updating re-arms the notification
+	b.MonthlyLimit = 800
+	if err := s.UpdateCostBudget(*b); err != nil {
+		t.Fatalf("UpdateCostBudget failed: %v", err)
+	}
+	b, _ = s.GetCostBudget(id)
+	if b.MonthlyLimit != 800 || b.LastNotifiedPeriod != "" {
+		t.Errorf("Expected limit updated and notification re-armed, got %+v", b)
+	}
+
+	list, _ := s.GetCostBudgets()
+	if len(list) != 1 {
+		t.Errorf("Expected 1 budget, got %d", len(list))
+	}
+
+	if err := s.DeleteCostBudget(id); err != nil {
+		t.Fatalf("DeleteCostBudget failed: %v", err)
+	}
+	if _, err := s.GetCostBudget(id); !errors.Is(err, ErrBudgetNotFound) {
+		t.Errorf("Expected ErrBudgetNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"time"
+)
+
+// Cost history scopes. Label entries are named "key=value".
+const (
+	CostScopeCluster   = "cluster"
+	CostScopeNamespace = "namespace"
+	CostScopeLabel     = "label"
+)
+
+// CostHistoryEntry is the hourly cost rate of one scope (cluster, namespace or label value) for one agent and hour.
+type CostHistoryEntry struct {
+	AgentID    string    `json:"agentId"`
+	Bucket     time.Time `json:"bucket"` // Start of the hour (UTC)
+	Scope      string    `json:"scope"`
+	Name       string    `json:"name"`
+	HourlyCost float64   `json:"hourlyCost"`
+}
+
+// RecordCostHistory upserts the hourly cost rates for an agent. Entries in the same
+// hour overwrite each other, so the last poll of the hour wins.
+func (s *Store) RecordCostHistory(entries []CostHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(s.rebind(`
+		INSERT INTO cost_history (agent_id, bucket, scope, name, hourly_cost)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (agent_id, bucket, scope, name) DO UPDATE SET hourly_cost = excluded.hourly_cost
+	`))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, e := range entries {
+		if _, err := stmt.Exec(e.AgentID, e.Bucket.UTC().Truncate(time.Hour), e.Scope, e.Name, e.HourlyCost); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCostHistory returns hourly entries since the given time, oldest first.
+// An empty agentID returns entries for all agents; an empty scope returns all scopes.
+func (s *Store) GetCostHistory(agentID, scope string, since time.Time) ([]CostHistoryEntry, error) {
+	query := "SELECT agent_id, bucket, scope, name, hourly_cost FROM cost_history WHERE bucket >= ?"
+	args := []interface{}{since.UTC()}
+	if agentID != "" {
+		query += " AND agent_id = ?"
+		args = append(args, agentID)
+	}
+	if scope != "" {
+		query += " AND scope = ?"
+		args = append(args, scope)
+	}
+	query += " ORDER BY bucket ASC"
+
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []CostHistoryEntry
+	for rows.Next() {
+		var e CostHistoryEntry
+		if err := rows.Scan(&e.AgentID, &e.Bucket, &e.Scope, &e.Name, &e.HourlyCost); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// PruneCostHistory deletes hourly entries older than the cutoff.
+func (s *Store) PruneCostHistory(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM cost_history WHERE bucket < ?"), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
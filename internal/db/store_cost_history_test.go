@@ -0,0 +1,45 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCostHistory(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateAgent(Agent{ID: "a1", Name: "prod", URL: "http://agent", Active: true, Interval: 300})
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	entries := []CostHistoryEntry{
+		{AgentID: "a1", Bucket: hour.Add(5 * time.Minute), Scope: CostScopeCluster, HourlyCost: 10},
+		{AgentID: "a1", Bucket: hour.Add(5 * time.Minute), Scope: CostScopeNamespace, Name: "api", HourlyCost: 4},
+	}
+	if err := s.RecordCostHistory(entries); err != nil {
+		t.Fatalf("RecordCostHistory failed: %v", err)
+	}
+
+	// A later poll in the same hour overwrites the rate
+	if err := s.RecordCostHistory([]CostHistoryEntry{
+		{AgentID: "a1", Bucket: hour.Add(40 * time.Minute), Scope: CostScopeCluster, HourlyCost: 12},
+	}); err != nil {
+		t.Fatalf("RecordCostHistory failed: %v", err)
+	}
+
+	got, err := s.GetCostHistory("a1", CostScopeCluster, hour.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetCostHistory failed: %v", err)
+	}
+	if len(got) != 1 || got[0].HourlyCost != 12 || !got[0].Bucket.Equal(hour) {
+		t.Errorf("Expected one cluster entry of 12 at %v, got %+v", hour, got)
+	}
+
+	all, _ := s.GetCostHistory("", "", hour.Add(-time.Hour))
+	if len(all) != 2 {
+		t.Errorf("Expected 2 entries across scopes, got %d", len(all))
+	}
+
+	n, err := s.PruneCostHistory(hour.Add(time.Hour))
+	if err != nil || n != 2 {
+		t.Errorf("Expected 2 pruned entries, got %d (err %v)", n, err)
+	}
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"time"
+)
+
+// CostRecommendation suggests smaller resource requests for an over-provisioned workload.
+type CostRecommendation struct {
+	AgentID              string    `json:"agentId"`
+	Namespace            string    `json:"namespace"`
+	WorkloadKind         string    `json:"workloadKind"`
+	WorkloadName         string    `json:"workloadName"`
+	Replicas             int       `json:"replicas"`
+	CPURequestedCores    float64   `json:"cpuRequestedCores"`
+	CPUUsedCores         float64   `json:"cpuUsedCores"`
+	CPURecommendedCores  float64   `json:"cpuRecommendedCores"`
+	MemoryRequestedGiB   float64   `json:"memoryRequestedGiB"`
+	MemoryUsedGiB        float64   `json:"memoryUsedGiB"`
+	MemoryRecommendedGiB float64   `json:"memoryRecommendedGiB"`
+	HourlyCost           float64   `json:"hourlyCost"`
+	MonthlySavings       float64   `json:"monthlySavings"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// ReplaceCostRecommendations swaps all recommendations of an agent for a freshly computed set.
+func (s *Store) ReplaceCostRecommendations(agentID string, recs []CostRecommendation) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM cost_recommendations WHERE agent_id = ?"), agentID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(s.rebind(`
+		INSERT INTO cost_recommendations (agent_id, namespace, workload_kind, workload_name, replicas,
+			cpu_requested_cores, cpu_used_cores, cpu_recommended_cores,
+			memory_requested_gib, memory_used_gib, memory_recommended_gib,
+			hourly_cost, monthly_savings, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	now := time.Now()
+	for _, r := range recs {
+		if _, err := stmt.Exec(agentID, r.Namespace, r.WorkloadKind, r.WorkloadName, r.Replicas,
+			r.CPURequestedCores, r.CPUUsedCores, r.CPURecommendedCores,
+			r.MemoryRequestedGiB, r.MemoryUsedGiB, r.MemoryRecommendedGiB,
+			r.HourlyCost, r.MonthlySavings, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCostRecommendations returns recommendations ordered by estimated savings.
+// An empty agentID returns recommendations for all agents.
+func (s *Store) GetCostRecommendations(agentID string) ([]CostRecommendation, error) {
+	query := `SELECT agent_id, namespace, workload_kind, workload_name, replicas,
+		cpu_requested_cores, cpu_used_cores, cpu_recommended_cores,
+		memory_requested_gib, memory_used_gib, memory_recommended_gib,
+		hourly_cost, monthly_savings, updated_at
+		FROM cost_recommendations`
+	var args []interface{}
+	if agentID != "" {
+		query += " WHERE agent_id = ?"
+		args = append(args, agentID)
+	}
+	query += " ORDER BY monthly_savings DESC"
+
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var recs []CostRecommendation
+	for rows.Next() {
+		var r CostRecommendation
+		if err := rows.Scan(&r.AgentID, &r.Namespace, &r.WorkloadKind, &r.WorkloadName, &r.Replicas,
+			&r.CPURequestedCores, &r.CPUUsedCores, &r.CPURecommendedCores,
+			&r.MemoryRequestedGiB, &r.MemoryUsedGiB, &r.MemoryRecommendedGiB,
+			&r.HourlyCost, &r.MonthlySavings, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		recs = append(recs, r)
+	}
+	return recs, nil
+}
@@ -0,0 +1,37 @@
+package db
+
+import "testing"
+
+func TestCostRecommendations(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateAgent(Agent{ID: "a-rec", Name: "rec", URL: "http://rec", Active: true, Interval: 300})
+	_ = s.CreateAgent(Agent{ID: "a-other", Name: "other", URL: "http://other", Active: true, Interval: 300})
+
+	if err := s.ReplaceCostRecommendations("a-rec", []CostRecommendation{
+		{Namespace: "ns", WorkloadKind: "Deployment", WorkloadName: "small", MonthlySavings: 10},
+		{Namespace: "ns", WorkloadKind: "Deployment", WorkloadName: "big", MonthlySavings: 90},
+	}); err != nil {
+		t.Fatalf("ReplaceCostRecommendations failed: %v", err)
+	}
+	_ = s.ReplaceCostRecommendations("a-other", []CostRecommendation{{Namespace: "ns", WorkloadName: "x", MonthlySavings: 50}})
+
+	recs, err := s.GetCostRecommendations("a-rec")
+	if err != nil {
+		t.Fatalf("GetCostRecommendations failed: %v", err)
+	}
+	if len(recs) != 2 || recs[0].WorkloadName != "big" || recs[0].AgentID != "a-rec" {
+		t.Errorf("Expected recommendations ordered by savings, got %+v", recs)
+	}
+
+	all, _ := s.GetCostRecommendations("")
+	if len(all) != 3 {
+		t.Errorf("Expected 3 recommendations across agents, got %d", len(all))
+	}
+
+	// A new poll replaces the previous set
+	_ = s.ReplaceCostRecommendations("a-rec", nil)
+	recs, _ = s.GetCostRecommendations("a-rec")
+	if len(recs) != 0 {
+		t.Errorf("Expected recommendations to be cleared, got %+v", recs)
+	}
+}
@@ -0,0 +1,34 @@
+package db
+
+// GetDiscoveredMonitors returns the monitors a discovery provider manages, by the key of
+// what each was created for.
+func (s *Store) GetDiscoveredMonitors(provider string) (map[string]string, error) {
+	rows, err := s.db.Query(s.rebind("SELECT source_key, monitor_id FROM discovered_monitors WHERE provider = ?"), provider)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	monitors := make(map[string]string)
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, err
+		}
+		monitors[key] = id
+	}
+	return monitors, rows.Err()
+}
+
+// CreateDiscoveredMonitor creates a monitor managed by a discovery provider. The record
+// goes when the monitor is deleted.
+func (s *Store) CreateDiscoveredMonitor(provider, key string, m Monitor) error {
+	if err := s.CreateMonitor(m); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(s.rebind("INSERT INTO discovered_monitors (provider, source_key, monitor_id) VALUES (?, ?, ?)"), provider, key, m.ID)
+	if err != nil {
+		_ = s.DeleteMonitor(m.ID)
+	}
+	return err
+}
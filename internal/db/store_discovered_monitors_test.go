@@ -0,0 +1,44 @@
+package db
+
+import "testing"
+
+func TestDiscoveredMonitors(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateGroup(Group{ID: "g2", Name: "G2"})
+
+	if err := s.CreateDiscoveredMonitor("docker", "photos", Monitor{ID: "m1", GroupID: "g1", Name: "Photos", URL: "http://photos", Interval: 60}); err != nil {
+		t.Fatalf("CreateDiscoveredMonitor failed: %v", err)
+	}
+	// A name taken by another monitor leaves neither the monitor nor a record behind
+	_ = s.CreateMonitor(Monitor{ID: "m-manual", GroupID: "g1", Name: "Wiki", Interval: 60})
+	if err := s.CreateDiscoveredMonitor("docker", "wiki", Monitor{ID: "m2", GroupID: "g1", Name: "Wiki", Interval: 60}); err == nil {
+		t.Fatal("Expected a duplicate name to fail")
+	}
+
+	discovered, err := s.GetDiscoveredMonitors("docker")
+	if err != nil {
+		t.Fatalf("GetDiscoveredMonitors failed: %v", err)
+	}
+	if len(discovered) != 1 || discovered["photos"] != "m1" {
+		t.Fatalf("Expected photos -> m1, got %v", discovered)
+	}
+	if other, _ := s.GetDiscoveredMonitors("kubernetes"); len(other) != 0 {
+		t.Errorf("Expected no monitors for another provider, got %v", other)
+	}
+
+	if err := s.SetMonitorGroup("m1", "g2"); err != nil {
+		t.Fatalf("SetMonitorGroup failed: %v", err)
+	}
+	if m, _ := s.GetMonitor("m1"); m == nil || m.GroupID != "g2" {
+		t.Errorf("Expected m1 in g2, got %+v", m)
+	}
+
+	// Deleting the monitor, from the UI or by discovery, drops its record
+	if err := s.DeleteMonitor("m1"); err != nil {
+		t.Fatalf("DeleteMonitor failed: %v", err)
+	}
+	if discovered, _ := s.GetDiscoveredMonitors("docker"); len(discovered) != 0 {
+		t.Errorf("Expected the record to go with the monitor, got %v", discovered)
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrDowntimeCostNotFound is returned when a monitor has no downtime cost.
+var ErrDowntimeCostNotFound = errors.New("downtime cost not found")
+
+// MonitorDowntimeCost is the approximate business cost of a minute of a monitor's
+// downtime, in the currency the cost agents report in.
+type MonitorDowntimeCost struct {
+	MonitorID     string    `json:"monitorId"`
+	CostPerMinute float64   `json:"costPerMinute"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// SetMonitorDowntimeCost creates or replaces a monitor's downtime cost.
+func (s *Store) SetMonitorDowntimeCost(monitorID string, costPerMinute float64) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO monitor_downtime_costs (monitor_id, cost_per_minute, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (monitor_id) DO UPDATE SET cost_per_minute = excluded.cost_per_minute, updated_at = excluded.updated_at`),
+		monitorID, costPerMinute, time.Now().UTC())
+	return err
+}
+
+// GetMonitorDowntimeCost returns a monitor's downtime cost, or ErrDowntimeCostNotFound.
+func (s *Store) GetMonitorDowntimeCost(monitorID string) (*MonitorDowntimeCost, error) {
+	var c MonitorDowntimeCost
+	err := s.db.QueryRow(s.rebind("SELECT monitor_id, cost_per_minute, updated_at FROM monitor_downtime_costs WHERE monitor_id = ?"), monitorID).
+		Scan(&c.MonitorID, &c.CostPerMinute, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDowntimeCostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetDowntimeCosts returns the cost per minute of every monitor that has one, by
+// monitor ID.
+func (s *Store) GetDowntimeCosts() (map[string]float64, error) {
+	rows, err := s.db.Query("SELECT monitor_id, cost_per_minute FROM monitor_downtime_costs")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	costs := make(map[string]float64)
+	for rows.Next() {
+		var id string
+		var cost float64
+		if err := rows.Scan(&id, &cost); err != nil {
+			return nil, err
+		}
+		costs[id] = cost
+	}
+	return costs, rows.Err()
+}
+
+// DeleteMonitorDowntimeCost removes a monitor's downtime cost and reports whether it
+// had one.
+func (s *Store) DeleteMonitorDowntimeCost(monitorID string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM monitor_downtime_costs WHERE monitor_id = ?"), monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// EstimateDowntimeCost returns the cost of d of downtime at costPerMinute, rounded to
+// cents.
+func EstimateDowntimeCost(costPerMinute float64, d time.Duration) float64 {
+	return math.Round(costPerMinute*d.Minutes()*100) / 100
+}
+
+// GetDowntimeBetween returns how long a monitor's outages lasted within [from, to),
+// counting outages still open until now.
+func (s *Store) GetDowntimeBetween(monitorID string, from, to, now time.Time) (time.Duration, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT start_time, end_time FROM monitor_outages
+		WHERE monitor_id = ? AND start_time < ? AND (end_time IS NULL OR end_time > ?)`),
+		monitorID, to.UTC(), from.UTC())
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var total time.Duration
+	for rows.Next() {
+		var start time.Time
+		var end sql.NullTime
+		if err := rows.Scan(&start, &end); err != nil {
+			return 0, err
+		}
+		stop := now
+		if end.Valid {
+			stop = end.Time
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if stop.After(to) {
+			stop = to
+		}
+		if stop.After(start) {
+			total += stop.Sub(start)
+		}
+	}
+	return total, rows.Err()
+}
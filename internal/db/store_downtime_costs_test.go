@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorDowntimeCosts(t *testing.T) {
+	store := newTestStore(t)
+	_ = store.CreateGroup(Group{ID: "g-1", Name: "Shop"})
+	_ = store.CreateMonitor(Monitor{ID: "m-1", GroupID: "g-1", Name: "Checkout", URL: "http://shop", Interval: 60})
+
+	if _, err := store.GetMonitorDowntimeCost("m-1"); !errors.Is(err, ErrDowntimeCostNotFound) {
+		t.Errorf("Expected ErrDowntimeCostNotFound, got %v", err)
+	}
+	if err := store.SetMonitorDowntimeCost("m-1", 10); err != nil {
+		t.Fatalf("SetMonitorDowntimeCost failed: %v", err)
+	}
+	_ = store.SetMonitorDowntimeCost("m-1", 12.5)
+	if c, err := store.GetMonitorDowntimeCost("m-1"); err != nil || c.CostPerMinute != 12.5 {
+		t.Errorf("Expected the cost to be replaced, got %+v (%v)", c, err)
+	}
+	if costs, _ := store.GetDowntimeCosts(); len(costs) != 1 || costs["m-1"] != 12.5 {
+		t.Errorf("Unexpected costs %v", costs)
+	}
+	if deleted, _ := store.DeleteMonitorDowntimeCost("m-1"); !deleted {
+		t.Error("Expected the cost to be deleted")
+	}
+	if deleted, _ := store.DeleteMonitorDowntimeCost("m-1"); deleted {
+		t.Error("Expected nothing to delete")
+	}
+}
+
+func TestGetDowntimeBetween(t *testing.T) {
+	store := newTestStore(t)
+	_ = store.CreateGroup(Group{ID: "g-1", Name: "Shop"})
+	_ = store.CreateMonitor(Monitor{ID: "m-1", GroupID: "g-1", Name: "Checkout", URL: "http://shop", Interval: 60})
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	// Straddles the start of the month: 30 minutes count
+	_, _ = store.CreateClosedOutage("m-1", "down", "a", "", from.Add(-30*time.Minute), from.Add(30*time.Minute))
+	// Inside: 15 minutes
+	_, _ = store.CreateClosedOutage("m-1", "down", "b", "", from.Add(48*time.Hour), from.Add(48*time.Hour+15*time.Minute))
+	// After the month: nothing
+	_, _ = store.CreateClosedOutage("m-1", "down", "c", "", to.Add(time.Hour), to.Add(2*time.Hour))
+
+	got, err := store.GetDowntimeBetween("m-1", from, to, to.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetDowntimeBetween failed: %v", err)
+	}
+	if got != 45*time.Minute {
+		t.Errorf("Expected 45m of downtime, got %v", got)
+	}
+
+	// An open outage counts until now
+	_ = store.CreateOutageAt("m-1", "down", "d", "", nil, to.Add(-10*time.Minute))
+	got, _ = store.GetDowntimeBetween("m-1", from, to, to.Add(-5*time.Minute))
+	if got != 50*time.Minute {
+		t.Errorf("Expected 50m with the open outage, got %v", got)
+	}
+}
+
+func TestEstimateDowntimeCost(t *testing.T) {
+	if got := EstimateDowntimeCost(12.5, 90*time.Second); got != 18.75 {
+		t.Errorf("Expected 18.75, got %v", got)
+	}
+	if got := EstimateDowntimeCost(1, 20*time.Second); got != 0.33 {
+		t.Errorf("Expected 0.33, got %v", got)
+	}
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Error kinds classify why a check failed. They are stored with checks and outages.
+const (
+	ErrorKindDNS               = "dns"
+	ErrorKindConnectionRefused = "connection_refused"
+	ErrorKindTimeout           = "timeout"
+	ErrorKindTLS               = "tls"
+	ErrorKindHTTP5xx           = "http_5xx"
+	ErrorKindHTTP4xx           = "http_4xx"
+	ErrorKindHTTPStatus        = "http_status"     // Any other status code outside the accepted set
+	ErrorKindHeaderMismatch    = "header_mismatch" // Up, but response headers failed the monitor's assertions (degraded)
+	ErrorKindAgentReporting    = "agent_not_reporting"
+	ErrorKindExternal          = "external_alert"
+	ErrorKindMembersDown       = "members_down" // Too few members of a composite monitor are up
+	ErrorKindUnknown           = "unknown"
+)
+
+func nullableErrorKind(kind string) sql.NullString {
+	return sql.NullString{String: kind, Valid: kind != ""}
+}
+
+// GetErrorKindCounts returns the number of failed checks per error kind since the given time.
+func (s *Store) GetErrorKindCounts(monitorID string, since time.Time) (map[string]int, error) {
+	return s.checks.ErrorKindCounts(monitorID, since)
+}
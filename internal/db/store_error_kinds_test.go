@@ -0,0 +1,55 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestErrorKinds(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	now := time.Now()
+	checks := []CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 50, Timestamp: now, StatusCode: 200},
+		{MonitorID: "m1", Status: "down", Timestamp: now, ErrorKind: ErrorKindDNS},
+		{MonitorID: "m1", Status: "down", Timestamp: now, ErrorKind: ErrorKindDNS},
+		{MonitorID: "m1", Status: "down", Timestamp: now, StatusCode: 503, ErrorKind: ErrorKindHTTP5xx},
+		{MonitorID: "m1", Status: "down", Timestamp: now.Add(-48 * time.Hour), ErrorKind: ErrorKindTimeout},
+	}
+	if err := s.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	counts, err := s.GetErrorKindCounts("m1", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetErrorKindCounts failed: %v", err)
+	}
+	if len(counts) != 2 || counts[ErrorKindDNS] != 2 || counts[ErrorKindHTTP5xx] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+
+	history, _ := s.GetMonitorChecks("m1", 10)
+	found := false
+	for _, c := range history {
+		if c.ErrorKind == ErrorKindHTTP5xx && c.StatusCode == 503 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected error kind to round-trip through GetMonitorChecks, got %+v", history)
+	}
+
+	// Outages carry the kind of the failure that opened them
+	if err := s.CreateOutageWithErrorKind("m1", "down", "no such host", ErrorKindDNS); err != nil {
+		t.Fatalf("CreateOutageWithErrorKind failed: %v", err)
+	}
+	outages, err := s.GetActiveOutages()
+	if err != nil {
+		t.Fatalf("GetActiveOutages failed: %v", err)
+	}
+	if len(outages) != 1 || outages[0].ErrorKind != ErrorKindDNS {
+		t.Errorf("Expected active outage with dns error kind, got %+v", outages)
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// External alert states
+const (
+	ExternalAlertFiring   = "firing"
+	ExternalAlertResolved = "resolved"
+)
+
+// ExternalAlert is an alert received from an outside system (Alertmanager,
+// generic webhook) and attached to an external monitor.
+type ExternalAlert struct {
+	ID          int64      `json:"id"`
+	MonitorID   string     `json:"monitorId"`
+	Source      string     `json:"source"`      // "alertmanager" | "webhook"
+	Fingerprint string     `json:"fingerprint"` // Dedup key within (monitor, source)
+	Status      string     `json:"status"`      // firing | resolved
+	Summary     string     `json:"summary"`
+	StartsAt    time.Time  `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// UpsertExternalAlert inserts an alert or updates the existing one with the
+// same (monitor, source, fingerprint).
+func (s *Store) UpsertExternalAlert(a ExternalAlert) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO external_alerts (monitor_id, source, fingerprint, status, summary, starts_at, ends_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (monitor_id, source, fingerprint) DO UPDATE SET
+			status = excluded.status,
+			summary = excluded.summary,
+			starts_at = excluded.starts_at,
+			ends_at = excluded.ends_at,
+			updated_at = excluded.updated_at
+	`), a.MonitorID, a.Source, a.Fingerprint, a.Status, a.Summary, a.StartsAt, a.EndsAt, time.Now())
+	return err
+}
+
+// GetFiringExternalAlerts returns all currently firing alerts for a monitor, oldest first.
+func (s *Store) GetFiringExternalAlerts(monitorID string) ([]ExternalAlert, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT id, monitor_id, source, fingerprint, status, COALESCE(summary, ''), starts_at, ends_at, updated_at
+		FROM external_alerts
+		WHERE monitor_id = ? AND status = ?
+		ORDER BY starts_at ASC
+	`), monitorID, ExternalAlertFiring)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var alerts []ExternalAlert
+	for rows.Next() {
+		var a ExternalAlert
+		var endsAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.MonitorID, &a.Source, &a.Fingerprint, &a.Status, &a.Summary, &a.StartsAt, &endsAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if endsAt.Valid {
+			a.EndsAt = &endsAt.Time
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+// SetMonitorIngestToken stores the SHA-256 hash of a monitor's webhook ingest token.
+func (s *Store) SetMonitorIngestToken(monitorID, tokenHash string) error {
+	res, err := s.db.Exec(s.rebind("UPDATE monitors SET ingest_token_hash = ? WHERE id = ?"), tokenHash, monitorID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// GetMonitorByIngestToken looks up the monitor owning the given token hash.
+func (s *Store) GetMonitorByIngestToken(tokenHash string) (*Monitor, error) {
+	m, err := scanMonitor(s.db.QueryRow(s.rebind("SELECT "+monitorColumns+" FROM monitors WHERE ingest_token_hash = ?"), tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExternalAlerts(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "ext1", GroupID: "g1", Name: "Ext", Type: MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	mon, err := s.GetMonitor("ext1")
+	if err != nil {
+		t.Fatalf("GetMonitor failed: %v", err)
+	}
+	if mon.Type != MonitorTypeExternal {
+		t.Errorf("Expected type external, got %q", mon.Type)
+	}
+
+	now := time.Now().UTC()
+	if err := s.UpsertExternalAlert(ExternalAlert{MonitorID: "ext1", Source: "webhook", Fingerprint: "a", Status: ExternalAlertFiring, Summary: "disk full", StartsAt: now}); err != nil {
+		t.Fatalf("UpsertExternalAlert failed: %v", err)
+	}
+	if err := s.UpsertExternalAlert(ExternalAlert{MonitorID: "ext1", Source: "webhook", Fingerprint: "b", Status: ExternalAlertFiring, Summary: "cpu high", StartsAt: now}); err != nil {
+		t.Fatalf("UpsertExternalAlert failed: %v", err)
+	}
+
+	firing, err := s.GetFiringExternalAlerts("ext1")
+	if err != nil {
+		t.Fatalf("GetFiringExternalAlerts failed: %v", err)
+	}
+	if len(firing) != 2 {
+		t.Fatalf("Expected 2 firing alerts, got %d", len(firing))
+	}
+
+	// Resolving the same fingerprint updates in place
+	if err := s.UpsertExternalAlert(ExternalAlert{MonitorID: "ext1", Source: "webhook", Fingerprint: "a", Status: ExternalAlertResolved, Summary: "disk full", StartsAt: now, EndsAt: &now}); err != nil {
+		t.Fatalf("UpsertExternalAlert (resolve) failed: %v", err)
+	}
+	firing, _ = s.GetFiringExternalAlerts("ext1")
+	if len(firing) != 1 || firing[0].Fingerprint != "b" {
+		t.Errorf("Expected only alert 'b' firing, got %+v", firing)
+	}
+}
+
+func TestMonitorIngestToken(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "ext1", GroupID: "g1", Name: "Ext", Type: MonitorTypeExternal, Interval: 60})
+
+	if err := s.SetMonitorIngestToken("ext1", "hash-1"); err != nil {
+		t.Fatalf("SetMonitorIngestToken failed: %v", err)
+	}
+	mon, err := s.GetMonitorByIngestToken("hash-1")
+	if err != nil {
+		t.Fatalf("GetMonitorByIngestToken failed: %v", err)
+	}
+	if mon.ID != "ext1" {
+		t.Errorf("Expected ext1, got %s", mon.ID)
+	}
+
+	if _, err := s.GetMonitorByIngestToken("unknown"); !errors.Is(err, ErrMonitorNotFound) {
+		t.Errorf("Expected ErrMonitorNotFound, got %v", err)
+	}
+	if err := s.SetMonitorIngestToken("missing", "hash-2"); !errors.Is(err, ErrMonitorNotFound) {
+		t.Errorf("Expected ErrMonitorNotFound for missing monitor, got %v", err)
+	}
+}
@@ -15,12 +15,25 @@ type Group struct {
 
 func (s *Store) CreateGroup(g Group) error {
 	_, err := s.db.Exec(s.rebind("INSERT INTO groups (id, name, created_at) VALUES (?, ?, ?)"), g.ID, g.Name, time.Now())
+	if _, ok := uniqueViolation(err); ok {
+		return newConflictError("a group with this ID already exists")
+	}
 	return err
 }
 
 func (s *Store) DeleteGroup(id string) error {
-	_, err := s.db.Exec(s.rebind("DELETE FROM groups WHERE id = ?"), id)
-	return err
+	// The group's monitors go with it
+	var monitorIDs []string
+	if s.externalChecks {
+		var err error
+		if monitorIDs, err = s.monitorIDs("group_id = ?", id); err != nil {
+			return err
+		}
+	}
+	if _, err := s.db.Exec(s.rebind("DELETE FROM groups WHERE id = ?"), id); err != nil {
+		return err
+	}
+	return s.dropChecks(monitorIDs...)
 }
 
 func (s *Store) UpdateGroup(id, name string) error {
@@ -2,9 +2,14 @@ package db
 
 import (
 	"database/sql"
+	"errors"
+	"strings"
 	"time"
 )
 
+// ErrIncidentUpdateNotFound is returned when an incident has no update with the given ID.
+var ErrIncidentUpdateNotFound = errors.New("incident update not found")
+
 type Incident struct {
 	ID             string     `json:"id"`
 	Title          string     `json:"title"`
@@ -19,14 +24,20 @@ type Incident struct {
 	Source         string     `json:"source"`            // "auto" | "manual"
 	OutageID       *int64     `json:"outageId"`          // nullable FK to monitor_outages
 	Public         bool       `json:"public"`            // visible on public status page
+	// PendingPublication marks private incidents nobody has decided to publish or keep private yet.
+	PendingPublication bool `json:"pendingPublication"`
+	// IssueKey and IssueURL link the Jira or GitHub issue opened for the incident.
+	IssueKey string `json:"issueKey,omitempty"`
+	IssueURL string `json:"issueUrl,omitempty"`
 }
 
 type IncidentUpdate struct {
-	ID         int64     `json:"id"`
-	IncidentID string    `json:"incidentId"`
-	Status     string    `json:"status"`
-	Message    string    `json:"message"`
-	CreatedAt  time.Time `json:"createdAt"`
+	ID         int64      `json:"id"`
+	IncidentID string     `json:"incidentId"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	EditedAt   *time.Time `json:"editedAt,omitempty"` // Set once the update has been corrected
 }
 
 func (s *Store) CreateIncident(i Incident) error {
@@ -37,22 +48,109 @@ func (s *Store) CreateIncident(i Incident) error {
 	}
 
 	_, err := s.db.Exec(s.rebind(`
-		INSERT INTO incidents (id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at, source, outage_id, public)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`), i.ID, i.Title, i.Description, i.Type, i.Severity, i.Status, i.StartTime, i.EndTime, i.AffectedGroups, time.Now(), source, i.OutageID, i.Public)
+		INSERT INTO incidents (id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at, source, outage_id, public, pending_publication)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`), i.ID, i.Title, i.Description, i.Type, i.Severity, i.Status, i.StartTime, i.EndTime, i.AffectedGroups, time.Now(), source, i.OutageID, i.Public, i.PendingPublication && !i.Public)
+	if err != nil {
+		return err
+	}
+	i.Source = source
+	s.notifyIncidentCreated(i)
+	return nil
+}
+
+// IncidentListener is called with every incident created through the store. It must
+// not block for long: creating the incident waits for every listener.
+type IncidentListener func(incident Incident)
+
+// OnIncidentCreate registers fn to be called after an incident or maintenance window is
+// created through this store, e.g. to open an issue for it in an external tracker.
+func (s *Store) OnIncidentCreate(fn IncidentListener) {
+	s.incidentMu.Lock()
+	defer s.incidentMu.Unlock()
+	s.incidentListeners = append(s.incidentListeners, fn)
+}
+
+func (s *Store) notifyIncidentCreated(i Incident) {
+	s.incidentMu.RLock()
+	listeners := s.incidentListeners
+	s.incidentMu.RUnlock()
+	for _, fn := range listeners {
+		fn(i)
+	}
+}
+
+// SetIncidentIssue links the issue opened for an incident in an external tracker.
+func (s *Store) SetIncidentIssue(id, key, url string) error {
+	_, err := s.db.Exec(s.rebind("UPDATE incidents SET issue_key = ?, issue_url = ? WHERE id = ?"), key, url, id)
 	return err
 }
 
+// GetIncidentByIssueKey returns the incident linked to an external issue, or nil.
+func (s *Store) GetIncidentByIssueKey(key string) (*Incident, error) {
+	var id string
+	err := s.db.QueryRow(s.rebind("SELECT id FROM incidents WHERE issue_key = ?"), key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIncidentByID(id)
+}
+
+// IncidentFilter narrows GetIncidentsFiltered. Zero-valued fields don't filter.
+type IncidentFilter struct {
+	Type     string   // incident | maintenance
+	Statuses []string // any of these statuses
+	// GroupID keeps incidents affecting this group plus global ones (no affected groups).
+	GroupID string
+	// ActiveOnly drops resolved/completed incidents. Otherwise those are kept when they
+	// started at or after Since (the zero time keeps the whole history).
+	ActiveOnly bool
+	Since      time.Time
+}
+
+// GetIncidents returns every unresolved incident plus those started at or after since.
 func (s *Store) GetIncidents(since time.Time) ([]Incident, error) {
+	return s.GetIncidentsFiltered(IncidentFilter{Since: since})
+}
+
+// GetIncidentsFiltered returns incidents matching f, newest first.
+func (s *Store) GetIncidentsFiltered(f IncidentFilter) ([]Incident, error) {
+	var where []string
+	var args []any
+	if f.ActiveOnly {
+		where = append(where, "status NOT IN ('resolved', 'completed')")
+	} else {
+		where = append(where, "(status NOT IN ('resolved', 'completed') OR start_time >= ?)")
+		args = append(args, f.Since)
+	}
+	if f.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, f.Type)
+	}
+	if len(f.Statuses) > 0 {
+		where = append(where, "status IN (?"+strings.Repeat(", ?", len(f.Statuses)-1)+")")
+		for _, st := range f.Statuses {
+			args = append(args, st)
+		}
+	}
+	if f.GroupID != "" {
+		// affected_groups is a JSON array of IDs; match the quoted ID so "g-1" doesn't match "g-10"
+		where = append(where, `(affected_groups IS NULL OR affected_groups IN ('', '[]', 'null') OR affected_groups LIKE ? ESCAPE '\')`)
+		args = append(args, "%"+escapeLike(`"`+f.GroupID+`"`)+"%")
+	}
+
 	query := s.rebind(`
 		SELECT id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at,
-		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public
+		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public, COALESCE(pending_publication, FALSE),
+		       COALESCE(issue_key, ''), COALESCE(issue_url, '')
 		FROM incidents
-		WHERE (status != 'resolved' AND status != 'completed')
-		OR start_time >= ?
+		WHERE ` + strings.Join(where, " AND ") + `
 		ORDER BY created_at DESC
 	`)
-	rows, err := s.db.Query(query, since)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +161,7 @@ func (s *Store) GetIncidents(since time.Time) ([]Incident, error) {
 		var i Incident
 		var endTime sql.NullTime
 		var outageID sql.NullInt64
-		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public); err != nil {
+		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public, &i.PendingPublication, &i.IssueKey, &i.IssueURL); err != nil {
 			return nil, err
 		}
 		if endTime.Valid {
@@ -74,20 +172,26 @@ func (s *Store) GetIncidents(since time.Time) ([]Incident, error) {
 		}
 		incidents = append(incidents, i)
 	}
-	return incidents, nil
+	return incidents, rows.Err()
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally (with ESCAPE '\').
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (s *Store) GetIncidentByID(id string) (*Incident, error) {
 	query := s.rebind(`
 		SELECT id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at,
-		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public
+		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public, COALESCE(pending_publication, FALSE),
+		       COALESCE(issue_key, ''), COALESCE(issue_url, '')
 		FROM incidents
 		WHERE id = ?
 	`)
 	var i Incident
 	var endTime sql.NullTime
 	var outageID sql.NullInt64
-	err := s.db.QueryRow(query, id).Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public)
+	err := s.db.QueryRow(query, id).Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public, &i.PendingPublication, &i.IssueKey, &i.IssueURL)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -106,17 +210,55 @@ func (s *Store) GetIncidentByID(id string) (*Incident, error) {
 func (s *Store) UpdateIncident(i Incident) error {
 	_, err := s.db.Exec(s.rebind(`
 		UPDATE incidents
-		SET title=?, description=?, type=?, severity=?, status=?, start_time=?, end_time=?, affected_groups=?, source=?, outage_id=?, public=?
+		SET title=?, description=?, type=?, severity=?, status=?, start_time=?, end_time=?, affected_groups=?, source=?, outage_id=?, public=?,
+			pending_publication = CASE WHEN ? THEN FALSE ELSE COALESCE(pending_publication, FALSE) END
 		WHERE id=?
-	`), i.Title, i.Description, i.Type, i.Severity, i.Status, i.StartTime, i.EndTime, i.AffectedGroups, i.Source, i.OutageID, i.Public, i.ID)
+	`), i.Title, i.Description, i.Type, i.Severity, i.Status, i.StartTime, i.EndTime, i.AffectedGroups, i.Source, i.OutageID, i.Public, i.Public, i.ID)
 	return err
 }
 
+// SetIncidentPublic sets an incident's visibility. Either choice is a publication
+// decision, so the incident leaves the approval queue.
 func (s *Store) SetIncidentPublic(id string, public bool) error {
-	_, err := s.db.Exec(s.rebind(`UPDATE incidents SET public = ? WHERE id = ?`), public, id)
+	_, err := s.db.Exec(s.rebind(`UPDATE incidents SET public = ?, pending_publication = FALSE WHERE id = ?`), public, id)
 	return err
 }
 
+// GetIncidentsPendingPublication returns private incidents awaiting a publication
+// decision, oldest first.
+func (s *Store) GetIncidentsPendingPublication() ([]Incident, error) {
+	rows, err := s.db.Query(`
+		SELECT id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at,
+		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public, COALESCE(pending_publication, FALSE),
+		       COALESCE(issue_key, ''), COALESCE(issue_url, '')
+		FROM incidents
+		WHERE pending_publication = TRUE AND COALESCE(public, FALSE) = FALSE
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	incidents := []Incident{}
+	for rows.Next() {
+		var i Incident
+		var endTime sql.NullTime
+		var outageID sql.NullInt64
+		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public, &i.PendingPublication, &i.IssueKey, &i.IssueURL); err != nil {
+			return nil, err
+		}
+		if endTime.Valid {
+			i.EndTime = &endTime.Time
+		}
+		if outageID.Valid {
+			i.OutageID = &outageID.Int64
+		}
+		incidents = append(incidents, i)
+	}
+	return incidents, rows.Err()
+}
+
 func (s *Store) DeleteIncident(id string) error {
 	_, err := s.db.Exec(s.rebind("DELETE FROM incidents WHERE id = ?"), id)
 	return err
@@ -124,17 +266,32 @@ func (s *Store) DeleteIncident(id string) error {
 
 // CreateIncidentUpdate adds a timeline entry to an incident
 func (s *Store) CreateIncidentUpdate(incidentID, status, message string) error {
+	return s.CreateIncidentUpdateAt(incidentID, status, message, time.Now())
+}
+
+// CreateIncidentUpdateAt posts an incident update as of the given time rather than now.
+func (s *Store) CreateIncidentUpdateAt(incidentID, status, message string, at time.Time) error {
 	_, err := s.db.Exec(s.rebind(`
 		INSERT INTO incident_updates (incident_id, status, message, created_at)
 		VALUES (?, ?, ?, ?)
-	`), incidentID, status, message, time.Now())
+	`), incidentID, status, message, at)
 	return err
 }
 
+func scanIncidentUpdate(row rowScanner) (IncidentUpdate, error) {
+	var u IncidentUpdate
+	var editedAt sql.NullTime
+	err := row.Scan(&u.ID, &u.IncidentID, &u.Status, &u.Message, &u.CreatedAt, &editedAt)
+	if editedAt.Valid {
+		u.EditedAt = &editedAt.Time
+	}
+	return u, err
+}
+
 // GetIncidentUpdates returns all updates for an incident in chronological order
 func (s *Store) GetIncidentUpdates(incidentID string) ([]IncidentUpdate, error) {
 	query := s.rebind(`
-		SELECT id, incident_id, status, message, created_at
+		SELECT id, incident_id, status, message, created_at, edited_at
 		FROM incident_updates
 		WHERE incident_id = ?
 		ORDER BY created_at ASC
@@ -147,8 +304,8 @@ func (s *Store) GetIncidentUpdates(incidentID string) ([]IncidentUpdate, error)
 
 	var updates []IncidentUpdate
 	for rows.Next() {
-		var u IncidentUpdate
-		if err := rows.Scan(&u.ID, &u.IncidentID, &u.Status, &u.Message, &u.CreatedAt); err != nil {
+		u, err := scanIncidentUpdate(rows)
+		if err != nil {
 			return nil, err
 		}
 		updates = append(updates, u)
@@ -156,12 +313,58 @@ func (s *Store) GetIncidentUpdates(incidentID string) ([]IncidentUpdate, error)
 	return updates, nil
 }
 
+// GetIncidentUpdate returns one update of an incident, or ErrIncidentUpdateNotFound.
+func (s *Store) GetIncidentUpdate(incidentID string, updateID int64) (*IncidentUpdate, error) {
+	u, err := scanIncidentUpdate(s.db.QueryRow(s.rebind(`
+		SELECT id, incident_id, status, message, created_at, edited_at
+		FROM incident_updates
+		WHERE incident_id = ? AND id = ?
+	`), incidentID, updateID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrIncidentUpdateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// EditIncidentUpdate corrects the status and message of an incident update, keeping its
+// place in the timeline and stamping when it was edited.
+func (s *Store) EditIncidentUpdate(incidentID string, updateID int64, status, message string) error {
+	res, err := s.db.Exec(s.rebind(`
+		UPDATE incident_updates SET status = ?, message = ?, edited_at = ?
+		WHERE incident_id = ? AND id = ?
+	`), status, message, time.Now(), incidentID, updateID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrIncidentUpdateNotFound
+	}
+	return nil
+}
+
+// DeleteIncidentUpdate removes an update from an incident's timeline, or returns
+// ErrIncidentUpdateNotFound.
+func (s *Store) DeleteIncidentUpdate(incidentID string, updateID int64) error {
+	res, err := s.db.Exec(s.rebind("DELETE FROM incident_updates WHERE incident_id = ? AND id = ?"), incidentID, updateID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrIncidentUpdateNotFound
+	}
+	return nil
+}
+
 // GetPublicResolvedIncidents returns resolved/completed incidents marked as public since the given time.
 // Only returns actual incidents (type='incident'), not maintenance windows.
 func (s *Store) GetPublicResolvedIncidents(since time.Time) ([]Incident, error) {
 	query := s.rebind(`
 		SELECT id, title, description, type, severity, status, start_time, end_time, affected_groups, created_at,
-		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public
+		       COALESCE(source, 'manual') as source, outage_id, COALESCE(public, FALSE) as public, COALESCE(pending_publication, FALSE),
+		       COALESCE(issue_key, ''), COALESCE(issue_url, '')
 		FROM incidents
 		WHERE public = TRUE
 		AND type = 'incident'
@@ -180,7 +383,7 @@ func (s *Store) GetPublicResolvedIncidents(since time.Time) ([]Incident, error)
 		var i Incident
 		var endTime sql.NullTime
 		var outageID sql.NullInt64
-		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public); err != nil {
+		if err := rows.Scan(&i.ID, &i.Title, &i.Description, &i.Type, &i.Severity, &i.Status, &i.StartTime, &endTime, &i.AffectedGroups, &i.CreatedAt, &i.Source, &outageID, &i.Public, &i.PendingPublication, &i.IssueKey, &i.IssueURL); err != nil {
 			return nil, err
 		}
 		if endTime.Valid {
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -246,6 +247,60 @@ func TestIncidentUpdates(t *testing.T) {
 	_ = s.DeleteIncident("inc-updates-1")
 }
 
+func TestEditAndDeleteIncidentUpdates(t *testing.T) {
+	s := newTestStore(t)
+
+	for _, id := range []string{"inc-edit-1", "inc-edit-2"} {
+		if err := s.CreateIncident(Incident{ID: id, Title: "Test", Type: "incident", Severity: "major", Status: "investigating", StartTime: time.Now()}); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+	if err := s.CreateIncidentUpdate("inc-edit-1", "investigating", "Lookign into it"); err != nil {
+		t.Fatalf("CreateIncidentUpdate failed: %v", err)
+	}
+	if err := s.CreateIncidentUpdate("inc-edit-1", "identified", "Root cause found"); err != nil {
+		t.Fatalf("CreateIncidentUpdate failed: %v", err)
+	}
+	updates, _ := s.GetIncidentUpdates("inc-edit-1")
+	if len(updates) != 2 || updates[0].EditedAt != nil {
+		t.Fatalf("Expected 2 unedited updates, got %+v", updates)
+	}
+	first, second := updates[0], updates[1]
+
+	if err := s.EditIncidentUpdate("inc-edit-1", first.ID, "investigating", "Looking into it"); err != nil {
+		t.Fatalf("EditIncidentUpdate failed: %v", err)
+	}
+	edited, err := s.GetIncidentUpdate("inc-edit-1", first.ID)
+	if err != nil {
+		t.Fatalf("GetIncidentUpdate failed: %v", err)
+	}
+	if edited.Message != "Looking into it" || edited.EditedAt == nil || !edited.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("Expected the corrected message with an edit time and the original creation time, got %+v", edited)
+	}
+
+	// Updates are scoped to their incident
+	if err := s.EditIncidentUpdate("inc-edit-2", first.ID, "investigating", "x"); !errors.Is(err, ErrIncidentUpdateNotFound) {
+		t.Errorf("Expected ErrIncidentUpdateNotFound editing through another incident, got %v", err)
+	}
+	if _, err := s.GetIncidentUpdate("inc-edit-2", first.ID); !errors.Is(err, ErrIncidentUpdateNotFound) {
+		t.Errorf("Expected ErrIncidentUpdateNotFound, got %v", err)
+	}
+	if err := s.DeleteIncidentUpdate("inc-edit-2", second.ID); !errors.Is(err, ErrIncidentUpdateNotFound) {
+		t.Errorf("Expected ErrIncidentUpdateNotFound deleting through another incident, got %v", err)
+	}
+
+	if err := s.DeleteIncidentUpdate("inc-edit-1", second.ID); err != nil {
+		t.Fatalf("DeleteIncidentUpdate failed: %v", err)
+	}
+	updates, _ = s.GetIncidentUpdates("inc-edit-1")
+	if len(updates) != 1 || updates[0].ID != first.ID {
+		t.Errorf("Expected only the first update to remain, got %+v", updates)
+	}
+	if err := s.DeleteIncidentUpdate("inc-edit-1", second.ID); !errors.Is(err, ErrIncidentUpdateNotFound) {
+		t.Errorf("Expected ErrIncidentUpdateNotFound deleting twice, got %v", err)
+	}
+}
+
 func TestGetPublicResolvedIncidents(t *testing.T) {
 	s := newTestStore(t)
 
@@ -380,3 +435,58 @@ func TestGetPublicResolvedIncidentsExcludesMaintenance(t *testing.T) {
 	_ = s.DeleteIncident("inc-resolved-1")
 	_ = s.DeleteIncident("maint-completed-1")
 }
+
+func TestGetIncidentsFiltered(t *testing.T) {
+	s := newTestStore(t)
+
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	for _, i := range []Incident{
+		{ID: "inc-open", Type: "incident", Status: "investigating", StartTime: old, AffectedGroups: `["g-1"]`},
+		{ID: "inc-old", Type: "incident", Status: "resolved", StartTime: old, AffectedGroups: `["g-1"]`},
+		{ID: "inc-recent", Type: "incident", Status: "resolved", StartTime: now, AffectedGroups: `["g-10"]`},
+		{ID: "inc-global", Type: "incident", Status: "monitoring", StartTime: now, AffectedGroups: `[]`},
+		{ID: "maint-active", Type: "maintenance", Status: "in_progress", StartTime: now, AffectedGroups: `["g_1"]`},
+		{ID: "maint-done", Type: "maintenance", Status: "completed", StartTime: now},
+	} {
+		i.Title = i.ID
+		if err := s.CreateIncident(i); err != nil {
+			t.Fatalf("CreateIncident %s failed: %v", i.ID, err)
+		}
+	}
+
+	ids := func(f IncidentFilter) map[string]bool {
+		t.Helper()
+		incidents, err := s.GetIncidentsFiltered(f)
+		if err != nil {
+			t.Fatalf("GetIncidentsFiltered(%+v) failed: %v", f, err)
+		}
+		got := map[string]bool{}
+		for _, i := range incidents {
+			got[i.ID] = true
+		}
+		return got
+	}
+	expect := func(name string, got map[string]bool, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+		for _, id := range want {
+			if !got[id] {
+				t.Errorf("%s: got %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+
+	since := now.Add(-24 * time.Hour)
+	expect("unfiltered", ids(IncidentFilter{Since: since}), "inc-open", "inc-recent", "inc-global", "maint-active", "maint-done")
+	expect("type", ids(IncidentFilter{Type: "incident", Since: since}), "inc-open", "inc-recent", "inc-global")
+	expect("whole history", ids(IncidentFilter{Type: "incident"}), "inc-open", "inc-old", "inc-recent", "inc-global")
+	expect("active maintenance", ids(IncidentFilter{Type: "maintenance", ActiveOnly: true}), "maint-active")
+	expect("statuses", ids(IncidentFilter{Statuses: []string{"investigating", "monitoring"}}), "inc-open", "inc-global")
+	// g-1 must not match g-10, and "_" in g_1 must not act as a wildcard
+	expect("group", ids(IncidentFilter{GroupID: "g-1"}), "inc-open", "inc-old", "inc-global", "maint-done")
+}
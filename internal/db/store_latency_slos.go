@@ -0,0 +1,217 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrLatencySLONotFound is returned when a monitor has no latency SLO.
+var ErrLatencySLONotFound = errors.New("latency SLO not found")
+
+// LatencySLORollupBucket is the width of a latency SLO rollup bucket.
+const LatencySLORollupBucket = 5 * time.Minute
+
+// LatencySLO is a latency objective for a monitor: TargetPercent of its checks must be up
+// and finish within ThresholdMs over a rolling window of WindowDays.
+type LatencySLO struct {
+	MonitorID     string     `json:"monitorId"`
+	ThresholdMs   int64      `json:"thresholdMs"`
+	TargetPercent float64    `json:"targetPercent"`
+	WindowDays    int        `json:"windowDays"`
+	Alerting      bool       `json:"alerting"` // A burn alert was sent and the burn rate hasn't recovered since
+	LastAlertAt   *time.Time `json:"lastAlertAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// LatencySLORollup counts the good (up and within the threshold) and total checks of an
+// SLO in one bucket.
+type LatencySLORollup struct {
+	Bucket time.Time `json:"bucket"`
+	Good   int64     `json:"good"`
+	Total  int64     `json:"total"`
+}
+
+const latencySLOColumns = "monitor_id, threshold_ms, target_percent, window_days, alerting, last_alert_at, created_at"
+
+func scanLatencySLO(row rowScanner) (LatencySLO, error) {
+	var slo LatencySLO
+	var lastAlert sql.NullTime
+	err := row.Scan(&slo.MonitorID, &slo.ThresholdMs, &slo.TargetPercent, &slo.WindowDays, &slo.Alerting, &lastAlert, &slo.CreatedAt)
+	if lastAlert.Valid {
+		slo.LastAlertAt = &lastAlert.Time
+	}
+	return slo, err
+}
+
+// SetLatencySLO creates or replaces a monitor's latency SLO. Replacing one discards its
+// rollups and alert state, since they were counted against the old threshold.
+func (s *Store) SetLatencySLO(slo LatencySLO) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM latency_slo_rollups WHERE monitor_id = ?"), slo.MonitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("DELETE FROM latency_slos WHERE monitor_id = ?"), slo.MonitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("INSERT INTO latency_slos (monitor_id, threshold_ms, target_percent, window_days, alerting, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
+		slo.MonitorID, slo.ThresholdMs, slo.TargetPercent, slo.WindowDays, false, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetLatencySLO returns the monitor's latency SLO, or ErrLatencySLONotFound.
+func (s *Store) GetLatencySLO(monitorID string) (*LatencySLO, error) {
+	slo, err := scanLatencySLO(s.db.QueryRow(s.rebind("SELECT "+latencySLOColumns+" FROM latency_slos WHERE monitor_id = ?"), monitorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrLatencySLONotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &slo, nil
+}
+
+// GetLatencySLOs returns every latency SLO.
+func (s *Store) GetLatencySLOs() ([]LatencySLO, error) {
+	rows, err := s.db.Query("SELECT " + latencySLOColumns + " FROM latency_slos ORDER BY monitor_id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var slos []LatencySLO
+	for rows.Next() {
+		slo, err := scanLatencySLO(rows)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, slo)
+	}
+	return slos, rows.Err()
+}
+
+// DeleteLatencySLO removes a monitor's latency SLO and its rollups. It reports whether
+// there was one.
+func (s *Store) DeleteLatencySLO(monitorID string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM latency_slo_rollups WHERE monitor_id = ?"), monitorID); err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(s.rebind("DELETE FROM latency_slos WHERE monitor_id = ?"), monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, tx.Commit()
+}
+
+// SetLatencySLOAlerting records whether a burn alert is outstanding for the SLO. Raising
+// an alert also stamps its time.
+func (s *Store) SetLatencySLOAlerting(monitorID string, alerting bool, at time.Time) error {
+	if alerting {
+		_, err := s.db.Exec(s.rebind("UPDATE latency_slos SET alerting = ?, last_alert_at = ? WHERE monitor_id = ?"), true, at.UTC(), monitorID)
+		return err
+	}
+	_, err := s.db.Exec(s.rebind("UPDATE latency_slos SET alerting = ? WHERE monitor_id = ?"), false, monitorID)
+	return err
+}
+
+// RollupLatencySLO counts the monitor's checks since the given time into rollup buckets,
+// overwriting the buckets it touches so a partial bucket is completed on the next run.
+// It also drops buckets older than the SLO window.
+func (s *Store) RollupLatencySLO(slo LatencySLO, since, now time.Time) error {
+	since = since.UTC().Truncate(LatencySLORollupBucket)
+	buckets := make(map[time.Time]*LatencySLORollup)
+	err := s.checks.ScanChecks(slo.MonitorID, since, func(c CheckResult) error {
+		key := c.Timestamp.UTC().Truncate(LatencySLORollupBucket)
+		b, ok := buckets[key]
+		if !ok {
+			b = &LatencySLORollup{Bucket: key}
+			buckets[key] = b
+		}
+		b.Total++
+		if c.Status == "up" && c.Latency <= slo.ThresholdMs {
+			b.Good++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(s.rebind(`
+		INSERT INTO latency_slo_rollups (monitor_id, bucket, good, total)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (monitor_id, bucket) DO UPDATE SET good = excluded.good, total = excluded.total
+	`))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, b := range buckets {
+		if _, err := stmt.Exec(slo.MonitorID, b.Bucket, b.Good, b.Total); err != nil {
+			return err
+		}
+	}
+	cutoff := now.UTC().AddDate(0, 0, -slo.WindowDays).Truncate(LatencySLORollupBucket)
+	if _, err := tx.Exec(s.rebind("DELETE FROM latency_slo_rollups WHERE monitor_id = ? AND bucket < ?"), slo.MonitorID, cutoff); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetLatencySLORollups returns the SLO's rollups since the given time, oldest first.
+func (s *Store) GetLatencySLORollups(monitorID string, since time.Time) ([]LatencySLORollup, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT bucket, good, total FROM latency_slo_rollups
+		WHERE monitor_id = ? AND bucket >= ?
+		ORDER BY bucket ASC
+	`), monitorID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var rollups []LatencySLORollup
+	for rows.Next() {
+		var r LatencySLORollup
+		if err := rows.Scan(&r.Bucket, &r.Good, &r.Total); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, r)
+	}
+	return rollups, rows.Err()
+}
+
+// GetLatestLatencySLORollup returns the start of the SLO's most recent rollup bucket, or
+// false if nothing has been rolled up yet.
+func (s *Store) GetLatestLatencySLORollup(monitorID string) (time.Time, bool, error) {
+	var latest time.Time
+	err := s.db.QueryRow(s.rebind("SELECT bucket FROM latency_slo_rollups WHERE monitor_id = ? ORDER BY bucket DESC LIMIT 1"), monitorID).Scan(&latest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return latest, true, nil
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencySLOs(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetLatencySLO("m1"); !errors.Is(err, ErrLatencySLONotFound) {
+		t.Fatalf("expected ErrLatencySLONotFound, got %v", err)
+	}
+
+	slo := LatencySLO{MonitorID: "m1", ThresholdMs: 300, TargetPercent: 95, WindowDays: 30}
+	if err := s.SetLatencySLO(slo); err != nil {
+		t.Fatalf("SetLatencySLO: %v", err)
+	}
+
+	now := time.Now().UTC()
+	bucket := now.Add(-time.Hour).Truncate(LatencySLORollupBucket)
+	if err := s.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: bucket.Add(time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 500, Timestamp: bucket.Add(2 * time.Minute)},
+		{MonitorID: "m1", Status: "down", Latency: 50, Timestamp: bucket.Add(3 * time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 300, Timestamp: bucket.Add(6 * time.Minute)},
+		// Before the rollup range
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: bucket.Add(-time.Hour)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := s.GetLatestLatencySLORollup("m1"); err != nil || ok {
+		t.Fatalf("expected no rollups yet, got %v %v", ok, err)
+	}
+	if err := s.RollupLatencySLO(slo, bucket, now); err != nil {
+		t.Fatalf("RollupLatencySLO: %v", err)
+	}
+	// Rolling up again overwrites rather than double counts
+	if err := s.RollupLatencySLO(slo, bucket, now); err != nil {
+		t.Fatalf("RollupLatencySLO: %v", err)
+	}
+
+	rollups, err := s.GetLatencySLORollups("m1", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetLatencySLORollups: %v", err)
+	}
+	if len(rollups) != 2 || !rollups[0].Bucket.Equal(bucket) ||
+		rollups[0].Good != 1 || rollups[0].Total != 3 || rollups[1].Good != 1 || rollups[1].Total != 1 {
+		t.Fatalf("unexpected rollups %+v", rollups)
+	}
+	if latest, ok, err := s.GetLatestLatencySLORollup("m1"); err != nil || !ok || !latest.Equal(bucket.Add(LatencySLORollupBucket)) {
+		t.Errorf("unexpected latest rollup %v %v %v", latest, ok, err)
+	}
+
+	if err := s.SetLatencySLOAlerting("m1", true, now); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetLatencySLO("m1")
+	if err != nil || !got.Alerting || got.LastAlertAt == nil || got.ThresholdMs != 300 || got.TargetPercent != 95 {
+		t.Fatalf("unexpected SLO %+v (%v)", got, err)
+	}
+
+	// Replacing the SLO discards rollups counted against the old threshold
+	slo.ThresholdMs = 1000
+	if err := s.SetLatencySLO(slo); err != nil {
+		t.Fatal(err)
+	}
+	if rollups, _ := s.GetLatencySLORollups("m1", now.Add(-24*time.Hour)); len(rollups) != 0 {
+		t.Errorf("expected rollups to be discarded, got %+v", rollups)
+	}
+	if got, _ := s.GetLatencySLO("m1"); got.Alerting {
+		t.Error("expected the alert state to reset")
+	}
+
+	if slos, err := s.GetLatencySLOs(); err != nil || len(slos) != 1 {
+		t.Errorf("GetLatencySLOs: %v %+v", err, slos)
+	}
+	if ok, err := s.DeleteLatencySLO("m1"); err != nil || !ok {
+		t.Fatalf("DeleteLatencySLO: %v %v", ok, err)
+	}
+	if ok, _ := s.DeleteLatencySLO("m1"); ok {
+		t.Error("expected a second delete to report nothing deleted")
+	}
+}
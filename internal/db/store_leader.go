@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrLeaderElectionUnsupported is returned when leader election is requested on a
+// database without session-level advisory locks.
+var ErrLeaderElectionUnsupported = errors.New("leader election requires PostgreSQL")
+
+// leaderLockKey is the advisory lock id shared by every Warden instance on a database.
+const leaderLockKey int64 = 0x77617264656e // "warden"
+
+// LeaderLock is a held PostgreSQL advisory lock. The lock lives as long as the
+// dedicated connection that acquired it, so a crashed leader frees it as soon as
+// the server notices the session is gone.
+type LeaderLock struct {
+	conn *sql.Conn
+}
+
+// TryAcquireLeaderLock attempts to take the leader lock without blocking.
+// It returns a nil lock and nil error when another instance already holds it.
+func (s *Store) TryAcquireLeaderLock(ctx context.Context) (*LeaderLock, error) {
+	if !s.IsPostgres() {
+		return nil, ErrLeaderElectionUnsupported
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", leaderLockKey).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, nil
+	}
+	return &LeaderLock{conn: conn}, nil
+}
+
+// Check verifies the session holding the lock is still alive.
+func (l *LeaderLock) Check(ctx context.Context) error {
+	var one int
+	return l.conn.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// Release unlocks and closes the dedicated connection. The connection returns
+// to the pool, so the unlock must happen first; if it fails the session is
+// already broken and the server has dropped the lock with it.
+func (l *LeaderLock) Release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _ = l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", leaderLockKey)
+	_ = l.conn.Close()
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTryAcquireLeaderLock_SQLiteUnsupported(t *testing.T) {
+	s := newTestStore(t)
+	lock, err := s.TryAcquireLeaderLock(context.Background())
+	if !errors.Is(err, ErrLeaderElectionUnsupported) {
+		t.Fatalf("expected ErrLeaderElectionUnsupported, got %v", err)
+	}
+	if lock != nil {
+		t.Fatal("expected nil lock on SQLite")
+	}
+}
+
+func TestTryAcquireLeaderLock_Postgres(t *testing.T) {
+	cfg := NewPostgresTestConfig()
+	if cfg == nil {
+		t.Skip("TEST_POSTGRES_URL not set")
+	}
+	a, err := NewStore(*cfg)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer func() { _ = a.Close() }()
+	b, err := NewStore(*cfg)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	ctx := context.Background()
+	lock, err := a.TryAcquireLeaderLock(ctx)
+	if err != nil || lock == nil {
+		t.Fatalf("first instance should acquire the lock: lock=%v err=%v", lock, err)
+	}
+	if err := lock.Check(ctx); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	other, err := b.TryAcquireLeaderLock(ctx)
+	if err != nil {
+		t.Fatalf("TryAcquireLeaderLock: %v", err)
+	}
+	if other != nil {
+		other.Release()
+		t.Fatal("second instance must not acquire a held lock")
+	}
+
+	lock.Release()
+	other, err = b.TryAcquireLeaderLock(ctx)
+	if err != nil || other == nil {
+		t.Fatalf("second instance should acquire after release: lock=%v err=%v", other, err)
+	}
+	other.Release()
+}
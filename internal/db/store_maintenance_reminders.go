@@ -0,0 +1,22 @@
+package db
+
+import "time"
+
+// MarkMaintenanceReminderSent records that the reminder sent hoursBefore a maintenance
+// window's start has gone out. It returns false if it was already recorded, so each
+// reminder fires once per start time even across restarts or HA failovers.
+func (s *Store) MarkMaintenanceReminderSent(incidentID string, hoursBefore int, startsAt time.Time) (bool, error) {
+	res, err := s.db.Exec(s.rebind(`
+		INSERT INTO maintenance_reminders (incident_id, hours_before, starts_at, sent_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (incident_id, hours_before, starts_at) DO NOTHING
+	`), incidentID, hoursBefore, startsAt.UTC(), time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarkMaintenanceReminderSent(t *testing.T) {
+	s := newTestStore(t)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	end := start.Add(time.Hour)
+	if err := s.CreateIncident(Incident{
+		ID: "maint-1", Title: "Upgrade", Type: "maintenance", Severity: "minor",
+		Status: "scheduled", StartTime: start, EndTime: &end, AffectedGroups: "[]",
+	}); err != nil {
+		t.Fatalf("CreateIncident: %v", err)
+	}
+
+	inserted, err := s.MarkMaintenanceReminderSent("maint-1", 24, start)
+	if err != nil || !inserted {
+		t.Fatalf("first mark: inserted=%v err=%v", inserted, err)
+	}
+	inserted, err = s.MarkMaintenanceReminderSent("maint-1", 24, start)
+	if err != nil || inserted {
+		t.Fatalf("second mark: inserted=%v err=%v, want already recorded", inserted, err)
+	}
+
+	// A different threshold or a rescheduled start is tracked separately
+	if inserted, _ := s.MarkMaintenanceReminderSent("maint-1", 1, start); !inserted {
+		t.Error("expected 1h reminder to be recorded separately")
+	}
+	if inserted, _ := s.MarkMaintenanceReminderSent("maint-1", 24, start.Add(time.Hour)); !inserted {
+		t.Error("expected rescheduled window to get a fresh reminder")
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Bulk monitor actions
+const (
+	BulkActionPause       = "pause"
+	BulkActionResume      = "resume"
+	BulkActionDelete      = "delete"
+	BulkActionSetInterval = "set_interval"
+	BulkActionAddTag      = "add_tag"
+)
+
+// BulkMonitorAction describes one change applied to many monitors.
+type BulkMonitorAction struct {
+	Action   string
+	Interval int    // For BulkActionSetInterval
+	Tag      string // For BulkActionAddTag, already normalized
+}
+
+// BulkUpdateMonitors applies an action to all given monitors in a single transaction.
+// If any monitor does not exist, nothing is changed and ErrMonitorNotFound is returned.
+func (s *Store) BulkUpdateMonitors(ids []string, action BulkMonitorAction) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, id := range ids {
+		var res sql.Result
+		switch action.Action {
+		case BulkActionPause, BulkActionResume:
+			res, err = tx.Exec(s.rebind("UPDATE monitors SET active = ? WHERE id = ?"), action.Action == BulkActionResume, id)
+		case BulkActionDelete:
+			res, err = s.deleteMonitor(tx, id)
+		case BulkActionSetInterval:
+			res, err = tx.Exec(s.rebind("UPDATE monitors SET interval_seconds = ? WHERE id = ?"), action.Interval, id)
+		case BulkActionAddTag:
+			res, err = s.addMonitorTag(tx, id, action.Tag)
+		default:
+			return fmt.Errorf("unknown bulk action %q", action.Action)
+		}
+		if err != nil {
+			return err
+		}
+		if res != nil {
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				return ErrMonitorNotFound
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if action.Action == BulkActionDelete {
+		return s.dropChecks(ids...)
+	}
+	return nil
+}
+
+// addMonitorTag appends a tag to a monitor inside tx. It returns a nil result when
+// the monitor already has the tag.
+func (s *Store) addMonitorTag(tx *sql.Tx, id, tag string) (sql.Result, error) {
+	var tags string
+	err := tx.QueryRow(s.rebind("SELECT COALESCE(tags, '') FROM monitors WHERE id = ?"), id).Scan(&tags)
+	if err == sql.ErrNoRows {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := Monitor{Tags: splitTags(tags)}
+	if m.HasTag(tag) {
+		return nil, nil
+	}
+	return tx.Exec(s.rebind("UPDATE monitors SET tags = ? WHERE id = ?"), joinTags(append(m.Tags, tag)), id)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBulkUpdateMonitors(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	for _, id := range []string{"m1", "m2", "m3"} {
+		_ = s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, URL: "http://" + id + ".com", Interval: 60, Active: true, Tags: []string{"prod"}})
+	}
+
+	if err := s.BulkUpdateMonitors([]string{"m1", "m2"}, BulkMonitorAction{Action: BulkActionPause}); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if err := s.BulkUpdateMonitors([]string{"m1", "m2"}, BulkMonitorAction{Action: BulkActionSetInterval, Interval: 300}); err != nil {
+		t.Fatalf("set_interval failed: %v", err)
+	}
+	if err := s.BulkUpdateMonitors([]string{"m1", "m2"}, BulkMonitorAction{Action: BulkActionAddTag, Tag: "team:web"}); err != nil {
+		t.Fatalf("add_tag failed: %v", err)
+	}
+	// Adding an existing tag is a no-op
+	if err := s.BulkUpdateMonitors([]string{"m1"}, BulkMonitorAction{Action: BulkActionAddTag, Tag: "prod"}); err != nil {
+		t.Fatalf("add_tag with existing tag failed: %v", err)
+	}
+
+	m1, _ := s.GetMonitor("m1")
+	if m1.Active || m1.Interval != 300 || len(m1.Tags) != 2 || !m1.HasTag("team:web") {
+		t.Errorf("Unexpected m1 after bulk updates: %+v", m1)
+	}
+	m3, _ := s.GetMonitor("m3")
+	if !m3.Active || m3.Interval != 60 || m3.HasTag("team:web") {
+		t.Errorf("m3 should be untouched: %+v", m3)
+	}
+
+	// A missing monitor rolls back the whole batch
+	err := s.BulkUpdateMonitors([]string{"m3", "missing"}, BulkMonitorAction{Action: BulkActionDelete})
+	if !errors.Is(err, ErrMonitorNotFound) {
+		t.Fatalf("Expected ErrMonitorNotFound, got %v", err)
+	}
+	if _, err := s.GetMonitor("m3"); err != nil {
+		t.Errorf("Expected m3 to survive the rolled back delete, got %v", err)
+	}
+
+	if err := s.BulkUpdateMonitors([]string{"m1", "m2"}, BulkMonitorAction{Action: BulkActionDelete}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	mons, _ := s.GetMonitors()
+	if len(mons) != 1 || mons[0].ID != "m3" {
+		t.Errorf("Expected only m3 to remain, got %+v", mons)
+	}
+
+	if err := s.BulkUpdateMonitors([]string{"m3"}, BulkMonitorAction{Action: "explode"}); err == nil {
+		t.Error("Expected unknown action to fail")
+	}
+}
+
+func TestBulkUpdateMonitors_DeleteAgentMonitor(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateAgent(Agent{ID: "a1", Name: "prod", URL: "http://agent:8080", Active: true, Interval: 300}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "m1", URL: "http://m1.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	// A missing monitor keeps the agent too
+	if err := s.BulkUpdateMonitors([]string{"a1", "missing"}, BulkMonitorAction{Action: BulkActionDelete}); !errors.Is(err, ErrMonitorNotFound) {
+		t.Fatalf("Expected ErrMonitorNotFound, got %v", err)
+	}
+	if _, err := s.GetAgent("a1"); err != nil {
+		t.Fatalf("Expected the agent to survive the rolled back delete, got %v", err)
+	}
+
+	if err := s.BulkUpdateMonitors([]string{"a1", "m1"}, BulkMonitorAction{Action: BulkActionDelete}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := s.GetAgent("a1"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Expected the agent to be deleted with its monitor, got %v", err)
+	}
+}
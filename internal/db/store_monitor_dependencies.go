@@ -0,0 +1,49 @@
+package db
+
+import "sort"
+
+// GetMonitorDependencies returns every declared dependency, keyed by the dependent
+// monitor's ID. Each list holds the IDs it depends on, sorted.
+func (s *Store) GetMonitorDependencies() (map[string][]string, error) {
+	rows, err := s.db.Query("SELECT monitor_id, depends_on_id FROM monitor_dependencies ORDER BY monitor_id, depends_on_id")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	deps := make(map[string][]string)
+	for rows.Next() {
+		var monitorID, dependsOn string
+		if err := rows.Scan(&monitorID, &dependsOn); err != nil {
+			return nil, err
+		}
+		deps[monitorID] = append(deps[monitorID], dependsOn)
+	}
+	return deps, rows.Err()
+}
+
+// SetMonitorDependencies replaces the monitors the given monitor depends on.
+// An empty list removes all of its dependencies.
+func (s *Store) SetMonitorDependencies(monitorID string, dependsOn []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_dependencies WHERE monitor_id = ?"), monitorID); err != nil {
+		return err
+	}
+
+	ids := append([]string(nil), dependsOn...)
+	sort.Strings(ids)
+	for i, id := range ids {
+		if i > 0 && ids[i-1] == id {
+			continue
+		}
+		if _, err := tx.Exec(s.rebind("INSERT INTO monitor_dependencies (monitor_id, depends_on_id) VALUES (?, ?)"), monitorID, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMonitorDependencies(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"lb", "api", "web"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, URL: "http://example.com/" + id, Active: true, Interval: 60}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.SetMonitorDependencies("api", []string{"lb", "web", "lb"}); err != nil {
+		t.Fatalf("SetMonitorDependencies: %v", err)
+	}
+	if err := s.SetMonitorDependencies("web", []string{"lb"}); err != nil {
+		t.Fatalf("SetMonitorDependencies: %v", err)
+	}
+	// Replacing drops the old list
+	if err := s.SetMonitorDependencies("api", []string{"lb"}); err != nil {
+		t.Fatalf("SetMonitorDependencies: %v", err)
+	}
+
+	deps, err := s.GetMonitorDependencies()
+	if err != nil {
+		t.Fatalf("GetMonitorDependencies: %v", err)
+	}
+	want := map[string][]string{"api": {"lb"}, "web": {"lb"}}
+	if !reflect.DeepEqual(deps, want) {
+		t.Fatalf("expected %v, got %v", want, deps)
+	}
+
+	// Deleting the dependency removes the edges pointing at it
+	if err := s.DeleteMonitor("lb"); err != nil {
+		t.Fatal(err)
+	}
+	deps, err = s.GetMonitorDependencies()
+	if err != nil {
+		t.Fatalf("GetMonitorDependencies: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Fatalf("expected no dependencies after deleting lb, got %v", deps)
+	}
+
+	if err := s.SetMonitorDependencies("api", nil); err != nil {
+		t.Fatalf("SetMonitorDependencies(nil): %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrShadowNotFound is returned when a monitor has no shadow trial.
+var ErrShadowNotFound = errors.New("shadow trial not found")
+
+// MonitorShadow is a dark-launch trial: a candidate URL checked alongside the monitor's
+// current URL for TargetChecks checks, without affecting its status or notifications.
+type MonitorShadow struct {
+	MonitorID       string    `json:"monitorId"`
+	URL             string    `json:"url"`
+	TargetChecks    int       `json:"targetChecks"`
+	CompletedChecks int       `json:"completedChecks"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// Complete reports whether the trial has run all of its checks.
+func (m MonitorShadow) Complete() bool {
+	return m.CompletedChecks >= m.TargetChecks
+}
+
+// ShadowResult is the outcome of one check against one URL.
+type ShadowResult struct {
+	Up         bool   `json:"up"`
+	StatusCode int    `json:"statusCode"`
+	Latency    int64  `json:"latency"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ShadowSample pairs a check of the current URL with the check of the candidate run
+// right after it.
+type ShadowSample struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Primary   ShadowResult `json:"primary"`
+	Shadow    ShadowResult `json:"shadow"`
+}
+
+// StartMonitorShadow starts a shadow trial for the monitor, replacing any existing
+// trial and its samples.
+func (s *Store) StartMonitorShadow(monitorID, url string, checks int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_shadow_samples WHERE monitor_id = ?"), monitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_shadows WHERE monitor_id = ?"), monitorID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind("INSERT INTO monitor_shadows (monitor_id, url, target_checks, created_at) VALUES (?, ?, ?, ?)"),
+		monitorID, url, checks, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const monitorShadowQuery = `
+	SELECT s.monitor_id, s.url, s.target_checks, s.created_at,
+		(SELECT COUNT(*) FROM monitor_shadow_samples x WHERE x.monitor_id = s.monitor_id)
+	FROM monitor_shadows s`
+
+func scanMonitorShadow(row rowScanner) (MonitorShadow, error) {
+	var m MonitorShadow
+	err := row.Scan(&m.MonitorID, &m.URL, &m.TargetChecks, &m.CreatedAt, &m.CompletedChecks)
+	return m, err
+}
+
+// GetMonitorShadow returns the monitor's shadow trial, or ErrShadowNotFound.
+func (s *Store) GetMonitorShadow(monitorID string) (*MonitorShadow, error) {
+	m, err := scanMonitorShadow(s.db.QueryRow(s.rebind(monitorShadowQuery+" WHERE s.monitor_id = ?"), monitorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShadowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetMonitorShadows returns every shadow trial, finished or not, keyed by monitor ID.
+func (s *Store) GetMonitorShadows() (map[string]MonitorShadow, error) {
+	rows, err := s.db.Query(monitorShadowQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	shadows := make(map[string]MonitorShadow)
+	for rows.Next() {
+		m, err := scanMonitorShadow(rows)
+		if err != nil {
+			return nil, err
+		}
+		shadows[m.MonitorID] = m
+	}
+	return shadows, rows.Err()
+}
+
+// AddMonitorShadowSample records one paired check of a shadow trial.
+func (s *Store) AddMonitorShadowSample(monitorID string, sample ShadowSample) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO monitor_shadow_samples (monitor_id, timestamp,
+			primary_up, primary_status_code, primary_latency, primary_error,
+			shadow_up, shadow_status_code, shadow_latency, shadow_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		monitorID, sample.Timestamp.UTC(),
+		sample.Primary.Up, sample.Primary.StatusCode, sample.Primary.Latency, sample.Primary.Error,
+		sample.Shadow.Up, sample.Shadow.StatusCode, sample.Shadow.Latency, sample.Shadow.Error)
+	return err
+}
+
+// GetMonitorShadowSamples returns the samples of the monitor's shadow trial, oldest first.
+func (s *Store) GetMonitorShadowSamples(monitorID string) ([]ShadowSample, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT timestamp, primary_up, primary_status_code, primary_latency, primary_error,
+			shadow_up, shadow_status_code, shadow_latency, shadow_error
+		FROM monitor_shadow_samples WHERE monitor_id = ? ORDER BY timestamp, id`), monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	samples := []ShadowSample{}
+	for rows.Next() {
+		var x ShadowSample
+		if err := rows.Scan(&x.Timestamp, &x.Primary.Up, &x.Primary.StatusCode, &x.Primary.Latency, &x.Primary.Error,
+			&x.Shadow.Up, &x.Shadow.StatusCode, &x.Shadow.Latency, &x.Shadow.Error); err != nil {
+			return nil, err
+		}
+		samples = append(samples, x)
+	}
+	return samples, rows.Err()
+}
+
+// DeleteMonitorShadow cancels the monitor's shadow trial. Returns false if there was none.
+func (s *Store) DeleteMonitorShadow(monitorID string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_shadow_samples WHERE monitor_id = ?"), monitorID); err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(s.rebind("DELETE FROM monitor_shadows WHERE monitor_id = ?"), monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, tx.Commit()
+}
+
+// PromoteMonitorShadow switches the monitor to its shadow trial's URL and ends the
+// trial, returning the new URL.
+func (s *Store) PromoteMonitorShadow(monitorID string) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var url string
+	err = tx.QueryRow(s.rebind("SELECT url FROM monitor_shadows WHERE monitor_id = ?"), monitorID).Scan(&url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrShadowNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(s.rebind("UPDATE monitors SET url = ? WHERE id = ?"), url, monitorID); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_shadow_samples WHERE monitor_id = ?"), monitorID); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(s.rebind("DELETE FROM monitor_shadows WHERE monitor_id = ?"), monitorID); err != nil {
+		return "", err
+	}
+	return url, tx.Commit()
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorShadows(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", URL: "http://old.example.com", Active: true, Interval: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetMonitorShadow("m1"); !errors.Is(err, ErrShadowNotFound) {
+		t.Fatalf("expected ErrShadowNotFound, got %v", err)
+	}
+
+	if err := s.StartMonitorShadow("m1", "http://new.example.com", 2); err != nil {
+		t.Fatalf("StartMonitorShadow: %v", err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	samples := []ShadowSample{
+		{Timestamp: now, Primary: ShadowResult{Up: true, StatusCode: 200, Latency: 40}, Shadow: ShadowResult{Up: true, StatusCode: 200, Latency: 60}},
+		{Timestamp: now.Add(time.Minute), Primary: ShadowResult{Up: true, StatusCode: 200, Latency: 40}, Shadow: ShadowResult{StatusCode: 504, Latency: 60, Error: "status 504"}},
+	}
+	for _, sample := range samples {
+		if err := s.AddMonitorShadowSample("m1", sample); err != nil {
+			t.Fatalf("AddMonitorShadowSample: %v", err)
+		}
+	}
+
+	shadow, err := s.GetMonitorShadow("m1")
+	if err != nil {
+		t.Fatalf("GetMonitorShadow: %v", err)
+	}
+	if shadow.URL != "http://new.example.com" || shadow.TargetChecks != 2 || shadow.CompletedChecks != 2 || !shadow.Complete() {
+		t.Fatalf("unexpected shadow %+v", shadow)
+	}
+	got, err := s.GetMonitorShadowSamples("m1")
+	if err != nil {
+		t.Fatalf("GetMonitorShadowSamples: %v", err)
+	}
+	if len(got) != 2 || !got[0].Shadow.Up || got[1].Shadow.Up || got[1].Shadow.StatusCode != 504 || got[1].Shadow.Error != "status 504" {
+		t.Fatalf("unexpected samples %+v", got)
+	}
+
+	// Restarting the trial discards the old samples
+	if err := s.StartMonitorShadow("m1", "http://newer.example.com", 3); err != nil {
+		t.Fatalf("StartMonitorShadow: %v", err)
+	}
+	all, err := s.GetMonitorShadows()
+	if err != nil {
+		t.Fatalf("GetMonitorShadows: %v", err)
+	}
+	if trial := all["m1"]; len(all) != 1 || trial.URL != "http://newer.example.com" || trial.CompletedChecks != 0 {
+		t.Fatalf("unexpected shadows %+v", all)
+	}
+
+	url, err := s.PromoteMonitorShadow("m1")
+	if err != nil || url != "http://newer.example.com" {
+		t.Fatalf("PromoteMonitorShadow = %q, %v", url, err)
+	}
+	mon, err := s.GetMonitor("m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mon.URL != "http://newer.example.com" {
+		t.Errorf("expected promoted URL, got %s", mon.URL)
+	}
+	if _, err := s.PromoteMonitorShadow("m1"); !errors.Is(err, ErrShadowNotFound) {
+		t.Errorf("expected ErrShadowNotFound after promotion, got %v", err)
+	}
+
+	// Cancelling
+	if err := s.StartMonitorShadow("m1", "http://other.example.com", 1); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := s.DeleteMonitorShadow("m1"); err != nil || !deleted {
+		t.Fatalf("DeleteMonitorShadow = %v, %v", deleted, err)
+	}
+	if deleted, err := s.DeleteMonitorShadow("m1"); err != nil || deleted {
+		t.Fatalf("second DeleteMonitorShadow = %v, %v", deleted, err)
+	}
+}
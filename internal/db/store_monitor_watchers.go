@@ -0,0 +1,33 @@
+package db
+
+// Watchers are notification channels scoped to one monitor: they receive only that
+// monitor's notifications, and are kept apart from the global channels.
+
+// GetMonitorWatchers returns the watchers of a monitor, newest first.
+func (s *Store) GetMonitorWatchers(monitorID string) ([]NotificationChannel, error) {
+	rows, err := s.db.Query(s.rebind("SELECT "+notificationChannelColumns+" FROM notification_channels WHERE monitor_id = ? ORDER BY created_at DESC"), monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var watchers []NotificationChannel
+	for rows.Next() {
+		var c NotificationChannel
+		if err := rows.Scan(&c.ID, &c.Type, &c.Name, &c.Config, &c.Enabled, &c.CreatedAt, &c.DisabledReason, &c.MonitorID); err != nil {
+			return nil, err
+		}
+		watchers = append(watchers, c)
+	}
+	return watchers, rows.Err()
+}
+
+// DeleteMonitorWatcher removes a watcher of the monitor, reporting whether it existed.
+func (s *Store) DeleteMonitorWatcher(monitorID, id string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM notification_channels WHERE id = ? AND monitor_id = ?"), id, monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
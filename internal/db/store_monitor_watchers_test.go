@@ -0,0 +1,53 @@
+package db
+
+import "testing"
+
+func TestMonitorWatchers(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+	_ = s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "M2", Interval: 60})
+
+	for _, c := range []NotificationChannel{
+		{ID: "nc-global", Type: "slack", Name: "Global", Config: "{}", Enabled: true},
+		{ID: "nc-w1", Type: "webhook", Name: "Watcher", Config: "{}", Enabled: true, MonitorID: "m1"},
+	} {
+		if err := s.CreateNotificationChannel(c); err != nil {
+			t.Fatalf("CreateNotificationChannel failed: %v", err)
+		}
+	}
+
+	watchers, err := s.GetMonitorWatchers("m1")
+	if err != nil {
+		t.Fatalf("GetMonitorWatchers failed: %v", err)
+	}
+	if len(watchers) != 1 || watchers[0].ID != "nc-w1" || watchers[0].MonitorID != "m1" {
+		t.Fatalf("Expected the m1 watcher, got %+v", watchers)
+	}
+	if watchers, _ := s.GetMonitorWatchers("m2"); len(watchers) != 0 {
+		t.Errorf("Expected no watchers for m2, got %+v", watchers)
+	}
+
+	if ok, err := s.DeleteMonitorWatcher("m2", "nc-w1"); err != nil || ok {
+		t.Errorf("Expected another monitor's watcher not to be deleted, got %v %v", ok, err)
+	}
+	if ok, err := s.DeleteMonitorWatcher("m1", "nc-global"); err != nil || ok {
+		t.Errorf("Expected a global channel not to be deleted as a watcher, got %v %v", ok, err)
+	}
+	if ok, err := s.DeleteMonitorWatcher("m1", "nc-w1"); err != nil || !ok {
+		t.Fatalf("DeleteMonitorWatcher failed: %v %v", ok, err)
+	}
+	channels, _ := s.GetNotificationChannels()
+	if len(channels) != 1 || channels[0].ID != "nc-global" {
+		t.Errorf("Expected only the global channel left, got %+v", channels)
+	}
+
+	// Deleting the monitor removes its watchers
+	_ = s.CreateNotificationChannel(NotificationChannel{ID: "nc-w2", Type: "webhook", Name: "Watcher", Config: "{}", Enabled: true, MonitorID: "m2"})
+	if err := s.DeleteMonitor("m2"); err != nil {
+		t.Fatalf("DeleteMonitor failed: %v", err)
+	}
+	if channels, _ := s.GetNotificationChannels(); len(channels) != 1 {
+		t.Errorf("Expected the watcher to go with its monitor, got %+v", channels)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -17,23 +19,69 @@ type RequestConfig struct {
 	FollowRedirects     *bool             `json:"followRedirects,omitempty"`
 	AcceptedStatusCodes string            `json:"acceptedStatusCodes,omitempty"`
 	RetryCount          int               `json:"retryCount,omitempty"`
+	FreshConnection     bool              `json:"freshConnection,omitempty"` // Open a new connection for every check instead of reusing a pooled one
+	ExpectedHeaders     []HeaderAssertion `json:"expectedHeaders,omitempty"` // Response headers that must match, or the check is degraded
+}
+
+// HeaderAssertion expects a response header to be present and, when Value is set, to
+// contain Value (case-insensitive), e.g. Content-Type containing "application/json".
+type HeaderAssertion struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
 }
 
 // IsEmpty returns true if all fields are at their zero/default values.
 func (rc *RequestConfig) IsEmpty() bool {
 	return rc.Method == "" && len(rc.Headers) == 0 && rc.Body == "" &&
 		rc.TimeoutSeconds == 0 && rc.FollowRedirects == nil &&
-		rc.AcceptedStatusCodes == "" && rc.RetryCount == 0
+		rc.AcceptedStatusCodes == "" && rc.RetryCount == 0 && !rc.FreshConnection &&
+		len(rc.ExpectedHeaders) == 0
 }
 
 // ErrMonitorNotFound is returned when a monitor is not found
 var ErrMonitorNotFound = errors.New("monitor not found")
 
+// MaxMonitorNameLength is the longest monitor name the store accepts, in bytes.
+const MaxMonitorNameLength = 255
+
+// monitorNameIndex enforces that monitor names are unique, ignoring case.
+const monitorNameIndex = "idx_monitors_name_lower"
+
+var (
+	// ErrMonitorNameTooLong is returned when a monitor name exceeds MaxMonitorNameLength.
+	ErrMonitorNameTooLong = fmt.Errorf("monitor name too long (max %d characters)", MaxMonitorNameLength)
+	// ErrDuplicateMonitorName is returned when another monitor already has the name,
+	// ignoring case. It matches ErrConflict.
+	ErrDuplicateMonitorName = newConflictError("a monitor with this name already exists")
+)
+
+// monitorWriteError translates a unique constraint violation from writing a monitor.
+func monitorWriteError(err error) error {
+	constraint, ok := uniqueViolation(err)
+	switch {
+	case !ok:
+		return err
+	case constraint == monitorNameIndex:
+		return ErrDuplicateMonitorName
+	default:
+		return newConflictError("a monitor with this ID already exists")
+	}
+}
+
+// Monitor types
+const (
+	MonitorTypeHTTP      = "http"      // Actively checked by the uptime workers
+	MonitorTypeExternal  = "external"  // State driven by ingested external alerts
+	MonitorTypeAgent     = "agent"     // Implicit monitor of a cost agent's health and reporting
+	MonitorTypeComposite = "composite" // Status computed from member monitors
+)
+
 type Monitor struct {
 	ID                      string    `json:"id"`
 	GroupID                 string    `json:"groupId"`
 	Name                    string    `json:"name"`
 	URL                     string    `json:"url"`
+	Type                    string    `json:"type"` // http | external | agent
 	Active                  bool      `json:"active"`
 	Interval                int       `json:"interval"` // Seconds
 	CreatedAt               time.Time `json:"createdAt"`
@@ -41,14 +89,44 @@ type Monitor struct {
 	NotificationCooldownMin *int           `json:"notificationCooldownMinutes,omitempty"`
 	LatencyThreshold        *int           `json:"latencyThreshold,omitempty"`
 	RequestConfig           *RequestConfig `json:"requestConfig,omitempty"`
+	Tags                    []string       `json:"tags,omitempty"`
+	DegradedThresholdChecks *int           `json:"degradedThresholdChecks,omitempty"`
+	DegradedWindowChecks    *int           `json:"degradedWindowChecks,omitempty"`
+	DownBackoffInterval     *int           `json:"downBackoffInterval,omitempty"`    // Seconds between checks after prolonged downtime
+	DownBackoffAfterChecks  *int           `json:"downBackoffAfterChecks,omitempty"` // Consecutive failures before backing off
+}
+
+// HasTag reports whether the monitor carries the given tag.
+func (m Monitor) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// joinTags and splitTags convert between the tag list and its comma-separated column value.
+func joinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+func splitTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
 }
 
 type CheckResult struct {
-	MonitorID  string    `json:"monitorId"`
-	Status     string    `json:"status"`
-	Latency    int64     `json:"latency"`
-	Timestamp  time.Time `json:"timestamp"`
-	StatusCode int       `json:"statusCode"`
+	MonitorID  string       `json:"monitorId"`
+	Status     string       `json:"status"`
+	Latency    int64        `json:"latency"`
+	Timestamp  time.Time    `json:"timestamp"`
+	StatusCode int          `json:"statusCode"`
+	ErrorKind  string       `json:"errorKind,omitempty"`
+	Timing     *CheckTiming `json:"timing,omitempty"`
+	Region     string       `json:"region,omitempty"` // Reporting region or agent, empty for checks run by the server
 }
 
 type MonitorEvent struct {
@@ -60,29 +138,43 @@ type MonitorEvent struct {
 }
 
 type MonitorOutage struct {
-	ID          int64      `json:"id"`
-	MonitorID   string     `json:"monitorId"`
-	Type        string     `json:"type"`
-	Summary     string     `json:"summary"`
-	StartTime   time.Time  `json:"startTime"`
-	EndTime     *time.Time `json:"endTime"`
-	MonitorName string     `json:"monitorName"` // Joined
-	GroupName   string     `json:"groupName"`   // Joined
-	GroupID     string     `json:"groupId"`     // Joined
+	ID          int64           `json:"id"`
+	MonitorID   string          `json:"monitorId"`
+	Type        string          `json:"type"`
+	Summary     string          `json:"summary"`
+	ErrorKind   string          `json:"errorKind,omitempty"`
+	Evidence    *OutageEvidence `json:"evidence,omitempty"`    // Only loaded by GetOutageByID
+	PathReport  string          `json:"pathReport,omitempty"`  // Traceroute/MTR output, only loaded by GetOutageByID
+	Remediation *RemediationRun `json:"remediation,omitempty"` // Remediation action result, only loaded by GetOutageByID
+	StartTime   time.Time       `json:"startTime"`
+	EndTime     *time.Time      `json:"endTime"`
+	MonitorName string          `json:"monitorName"` // Joined
+	GroupName   string          `json:"groupName"`   // Joined
+	GroupID     string          `json:"groupId"`     // Joined
+	// EstimatedCost is the outage's business impact so far, set by the API for monitors
+	// with a downtime cost
+	EstimatedCost *float64 `json:"estimatedCost,omitempty"`
 }
 
 type LatencyPoint struct {
-	Timestamp time.Time `json:"timestamp"`
-	Latency   int64     `json:"latency"`
-	Failed    bool      `json:"failed"`
+	Timestamp time.Time    `json:"timestamp"`
+	Latency   int64        `json:"latency"`
+	Failed    bool         `json:"failed"`
+	Timing    *CheckTiming `json:"timing,omitempty"` // Average breakdown of the checks that recorded one
 }
 
 // Monitor CRUD
 
 func (s *Store) CreateMonitor(m Monitor) error {
+	if len(m.Name) > MaxMonitorNameLength {
+		return ErrMonitorNameTooLong
+	}
 	if m.Interval < 1 {
 		m.Interval = 60 // Default safety
 	}
+	if m.Type == "" {
+		m.Type = MonitorTypeHTTP
+	}
 	var reqCfg sql.NullString
 	if m.RequestConfig != nil && !m.RequestConfig.IsEmpty() {
 		b, err := json.Marshal(m.RequestConfig)
@@ -91,12 +183,15 @@ func (s *Store) CreateMonitor(m Monitor) error {
 		}
 		reqCfg = sql.NullString{String: string(b), Valid: true}
 	}
-	_, err := s.db.Exec(s.rebind("INSERT INTO monitors (id, group_id, name, url, active, interval_seconds, created_at, confirmation_threshold, notification_cooldown_minutes, latency_threshold, request_config) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
-		m.ID, m.GroupID, m.Name, m.URL, m.Active, m.Interval, time.Now(), toNullInt64(m.ConfirmationThreshold), toNullInt64(m.NotificationCooldownMin), toNullInt64(m.LatencyThreshold), reqCfg)
-	return err
+	_, err := s.db.Exec(s.rebind("INSERT INTO monitors (id, group_id, name, url, monitor_type, active, interval_seconds, created_at, confirmation_threshold, notification_cooldown_minutes, latency_threshold, request_config, tags, degraded_threshold_checks, degraded_window_checks, down_backoff_interval_seconds, down_backoff_after_checks) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		m.ID, m.GroupID, m.Name, m.URL, m.Type, m.Active, m.Interval, time.Now(), toNullInt64(m.ConfirmationThreshold), toNullInt64(m.NotificationCooldownMin), toNullInt64(m.LatencyThreshold), reqCfg, joinTags(m.Tags), toNullInt64(m.DegradedThresholdChecks), toNullInt64(m.DegradedWindowChecks), toNullInt64(m.DownBackoffInterval), toNullInt64(m.DownBackoffAfterChecks))
+	return monitorWriteError(err)
 }
 
 func (s *Store) UpdateMonitor(id, name, url string, interval int, confirmThreshold *int, cooldownMins *int, latencyThreshold *int, reqConfig *RequestConfig) error {
+	if len(name) > MaxMonitorNameLength {
+		return ErrMonitorNameTooLong
+	}
 	if interval < 1 {
 		interval = 60
 	}
@@ -111,6 +206,56 @@ func (s *Store) UpdateMonitor(id, name, url string, interval int, confirmThresho
 	// Don't modify active flag - it's managed separately via SetMonitorActive
 	res, err := s.db.Exec(s.rebind("UPDATE monitors SET name = ?, url = ?, interval_seconds = ?, confirmation_threshold = ?, notification_cooldown_minutes = ?, latency_threshold = ?, request_config = ? WHERE id = ?"),
 		name, url, interval, toNullInt64(confirmThreshold), toNullInt64(cooldownMins), toNullInt64(latencyThreshold), reqCfg, id)
+	if err != nil {
+		return monitorWriteError(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// SetMonitorDegradedHysteresis sets or clears (nil) a monitor's degraded hysteresis overrides.
+func (s *Store) SetMonitorDegradedHysteresis(id string, thresholdChecks, windowChecks *int) error {
+	res, err := s.db.Exec(s.rebind("UPDATE monitors SET degraded_threshold_checks = ?, degraded_window_checks = ? WHERE id = ?"),
+		toNullInt64(thresholdChecks), toNullInt64(windowChecks), id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// SetMonitorDownBackoff sets or clears (nil) a monitor's down backoff interval and trigger.
+func (s *Store) SetMonitorDownBackoff(id string, intervalSeconds, afterChecks *int) error {
+	res, err := s.db.Exec(s.rebind("UPDATE monitors SET down_backoff_interval_seconds = ?, down_backoff_after_checks = ? WHERE id = ?"),
+		toNullInt64(intervalSeconds), toNullInt64(afterChecks), id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// SetMonitorTags replaces a monitor's tags.
+func (s *Store) SetMonitorTags(id string, tags []string) error {
+	res, err := s.db.Exec(s.rebind("UPDATE monitors SET tags = ? WHERE id = ?"), joinTags(tags), id)
 	if err != nil {
 		return err
 	}
@@ -119,14 +264,50 @@ func (s *Store) UpdateMonitor(id, name, url string, interval int, confirmThresho
 		return err
 	}
 	if rows == 0 {
-		return fmt.Errorf("monitor not found")
+		return ErrMonitorNotFound
+	}
+	return nil
+}
+
+// SetMonitorGroup moves a monitor to another group.
+func (s *Store) SetMonitorGroup(id, groupID string) error {
+	res, err := s.db.Exec(s.rebind("UPDATE monitors SET group_id = ? WHERE id = ?"), groupID, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrMonitorNotFound
 	}
 	return nil
 }
 
 func (s *Store) DeleteMonitor(id string) error {
-	_, err := s.db.Exec(s.rebind("DELETE FROM monitors WHERE id = ?"), id)
-	return err
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := s.deleteMonitor(tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.dropChecks(id)
+}
+
+// deleteMonitor removes a monitor inside tx, together with the cost agent whose
+// implicit monitor it is, so deleting an agent monitor doesn't leave the agent behind.
+func (s *Store) deleteMonitor(tx *sql.Tx, id string) (sql.Result, error) {
+	if _, err := tx.Exec(s.rebind("DELETE FROM agents WHERE id = ? AND EXISTS (SELECT 1 FROM monitors WHERE id = ? AND monitor_type = ?)"), id, id, MonitorTypeAgent); err != nil {
+		return nil, err
+	}
+	return tx.Exec(s.rebind("DELETE FROM monitors WHERE id = ?"), id)
 }
 
 func (s *Store) SetMonitorActive(id string, active bool) error {
@@ -144,9 +325,64 @@ func (s *Store) SetMonitorActive(id string, active bool) error {
 	return nil
 }
 
+// monitorColumns is the column list scanned by scanMonitor.
+const monitorColumns = "id, group_id, name, url, COALESCE(monitor_type, 'http'), active, interval_seconds, created_at, confirmation_threshold, notification_cooldown_minutes, latency_threshold, request_config, COALESCE(tags, ''), degraded_threshold_checks, degraded_window_checks, down_backoff_interval_seconds, down_backoff_after_checks"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMonitor(row rowScanner) (Monitor, error) {
+	var m Monitor
+	var confirmThreshold, cooldownMins, latencyThresh, degThreshold, degWindow, backoffInterval, backoffAfter sql.NullInt64
+	var reqCfgStr sql.NullString
+	var tags string
+	if err := row.Scan(&m.ID, &m.GroupID, &m.Name, &m.URL, &m.Type, &m.Active, &m.Interval, &m.CreatedAt, &confirmThreshold, &cooldownMins, &latencyThresh, &reqCfgStr, &tags, &degThreshold, &degWindow, &backoffInterval, &backoffAfter); err != nil {
+		return m, err
+	}
+	m.Tags = splitTags(tags)
+	if degThreshold.Valid {
+		v := int(degThreshold.Int64)
+		m.DegradedThresholdChecks = &v
+	}
+	if degWindow.Valid {
+		v := int(degWindow.Int64)
+		m.DegradedWindowChecks = &v
+	}
+	if backoffInterval.Valid {
+		v := int(backoffInterval.Int64)
+		m.DownBackoffInterval = &v
+	}
+	if backoffAfter.Valid {
+		v := int(backoffAfter.Int64)
+		m.DownBackoffAfterChecks = &v
+	}
+	if confirmThreshold.Valid {
+		v := int(confirmThreshold.Int64)
+		m.ConfirmationThreshold = &v
+	}
+	if cooldownMins.Valid {
+		v := int(cooldownMins.Int64)
+		m.NotificationCooldownMin = &v
+	}
+	if latencyThresh.Valid {
+		v := int(latencyThresh.Int64)
+		m.LatencyThreshold = &v
+	}
+	if reqCfgStr.Valid && reqCfgStr.String != "" {
+		var rc RequestConfig
+		if err := json.Unmarshal([]byte(reqCfgStr.String), &rc); err != nil {
+			return m, fmt.Errorf("failed to unmarshal request_config for monitor %s: %w", m.ID, err)
+		}
+		m.RequestConfig = &rc
+	}
+	return m, nil
+}
+
 // GetMonitors returns all monitors
 func (s *Store) GetMonitors() ([]Monitor, error) {
-	rows, err := s.db.Query("SELECT id, group_id, name, url, active, interval_seconds, created_at, confirmation_threshold, notification_cooldown_minutes, latency_threshold, request_config FROM monitors ORDER BY created_at ASC")
+	rows, err := s.db.Query("SELECT " + monitorColumns + " FROM monitors ORDER BY created_at ASC")
 	if err != nil {
 		return nil, err
 	}
@@ -154,36 +390,27 @@ func (s *Store) GetMonitors() ([]Monitor, error) {
 
 	var monitors []Monitor
 	for rows.Next() {
-		var m Monitor
-		var confirmThreshold, cooldownMins, latencyThresh sql.NullInt64
-		var reqCfgStr sql.NullString
-		if err := rows.Scan(&m.ID, &m.GroupID, &m.Name, &m.URL, &m.Active, &m.Interval, &m.CreatedAt, &confirmThreshold, &cooldownMins, &latencyThresh, &reqCfgStr); err != nil {
+		m, err := scanMonitor(rows)
+		if err != nil {
 			return nil, err
 		}
-		if confirmThreshold.Valid {
-			v := int(confirmThreshold.Int64)
-			m.ConfirmationThreshold = &v
-		}
-		if cooldownMins.Valid {
-			v := int(cooldownMins.Int64)
-			m.NotificationCooldownMin = &v
-		}
-		if latencyThresh.Valid {
-			v := int(latencyThresh.Int64)
-			m.LatencyThreshold = &v
-		}
-		if reqCfgStr.Valid && reqCfgStr.String != "" {
-			var rc RequestConfig
-			if err := json.Unmarshal([]byte(reqCfgStr.String), &rc); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal request_config for monitor %s: %w", m.ID, err)
-			}
-			m.RequestConfig = &rc
-		}
 		monitors = append(monitors, m)
 	}
 	return monitors, nil
 }
 
+// GetMonitor returns a single monitor by ID, or ErrMonitorNotFound.
+func (s *Store) GetMonitor(id string) (*Monitor, error) {
+	m, err := scanMonitor(s.db.QueryRow(s.rebind("SELECT "+monitorColumns+" FROM monitors WHERE id = ?"), id))
+	if err == sql.ErrNoRows {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 // Events & Checks
 
 func (s *Store) CreateEvent(monitorID, eventType, message string) error {
@@ -193,8 +420,22 @@ func (s *Store) CreateEvent(monitorID, eventType, message string) error {
 }
 
 func (s *Store) CreateOutage(monitorID, eventType, summary string) error {
-	_, err := s.db.Exec(s.rebind("INSERT INTO monitor_outages (monitor_id, type, summary) VALUES (?, ?, ?)"),
-		monitorID, eventType, summary)
+	return s.CreateOutageWithErrorKind(monitorID, eventType, summary, "")
+}
+
+// CreateOutageWithErrorKind opens an outage classified by the failure that caused it.
+func (s *Store) CreateOutageWithErrorKind(monitorID, eventType, summary, errorKind string) error {
+	return s.CreateOutageWithEvidence(monitorID, eventType, summary, errorKind, nil)
+}
+
+// CreateOutageWithEvidence opens an outage and keeps the response evidence of the failing check.
+func (s *Store) CreateOutageWithEvidence(monitorID, eventType, summary, errorKind string, evidence *OutageEvidence) error {
+	evidenceJSON, err := marshalEvidence(evidence)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.rebind("INSERT INTO monitor_outages (monitor_id, type, summary, error_kind, evidence) VALUES (?, ?, ?, ?, ?)"),
+		monitorID, eventType, summary, nullableErrorKind(errorKind), evidenceJSON)
 	return err
 }
 
@@ -204,9 +445,51 @@ func (s *Store) CloseOutage(monitorID string) error {
 	return err
 }
 
+// CreateEventAt records a monitor event that happened at the given time rather than now.
+func (s *Store) CreateEventAt(monitorID, eventType, message string, at time.Time) error {
+	_, err := s.db.Exec(s.rebind("INSERT INTO monitor_events (monitor_id, type, message, timestamp) VALUES (?, ?, ?, ?)"),
+		monitorID, eventType, message, at.UTC())
+	return err
+}
+
+// CreateOutageAt opens an outage that started at the given time rather than now.
+func (s *Store) CreateOutageAt(monitorID, eventType, summary, errorKind string, evidence *OutageEvidence, start time.Time) error {
+	evidenceJSON, err := marshalEvidence(evidence)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.rebind("INSERT INTO monitor_outages (monitor_id, type, summary, error_kind, evidence, start_time) VALUES (?, ?, ?, ?, ?, ?)"),
+		monitorID, eventType, summary, nullableErrorKind(errorKind), evidenceJSON, start.UTC())
+	return err
+}
+
+// CloseOutageAt closes the monitor's active outages as of the given time rather than now.
+func (s *Store) CloseOutageAt(monitorID string, end time.Time) error {
+	_, err := s.db.Exec(s.rebind("UPDATE monitor_outages SET end_time = ? WHERE monitor_id = ? AND end_time IS NULL"), end.UTC(), monitorID)
+	return err
+}
+
+// CreateClosedOutage records an outage that already ended, unless it overlaps one the
+// monitor already has. It reports whether the outage was recorded.
+func (s *Store) CreateClosedOutage(monitorID, eventType, summary, errorKind string, start, end time.Time) (bool, error) {
+	var overlapping int
+	if err := s.db.QueryRow(s.rebind(`
+		SELECT COUNT(*) FROM monitor_outages
+		WHERE monitor_id = ? AND start_time <= ? AND (end_time IS NULL OR end_time >= ?)
+	`), monitorID, end.UTC(), start.UTC()).Scan(&overlapping); err != nil {
+		return false, err
+	}
+	if overlapping > 0 {
+		return false, nil
+	}
+	_, err := s.db.Exec(s.rebind("INSERT INTO monitor_outages (monitor_id, type, summary, error_kind, start_time, end_time) VALUES (?, ?, ?, ?, ?, ?)"),
+		monitorID, eventType, summary, nullableErrorKind(errorKind), start.UTC(), end.UTC())
+	return err == nil, err
+}
+
 func (s *Store) GetActiveOutages() ([]MonitorOutage, error) {
 	query := `
-		SELECT o.id, o.monitor_id, o.type, o.summary, o.start_time, m.name, g.name, g.id
+		SELECT o.id, o.monitor_id, o.type, o.summary, COALESCE(o.error_kind, ''), o.start_time, m.name, g.name, g.id
 		FROM monitor_outages o
 		JOIN monitors m ON o.monitor_id = m.id
 		JOIN groups g ON m.group_id = g.id
@@ -222,7 +505,7 @@ func (s *Store) GetActiveOutages() ([]MonitorOutage, error) {
 	var outages []MonitorOutage
 	for rows.Next() {
 		var o MonitorOutage
-		if err := rows.Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.StartTime, &o.MonitorName, &o.GroupName, &o.GroupID); err != nil {
+		if err := rows.Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.ErrorKind, &o.StartTime, &o.MonitorName, &o.GroupName, &o.GroupID); err != nil {
 			return nil, err
 		}
 		outages = append(outages, o)
@@ -232,7 +515,7 @@ func (s *Store) GetActiveOutages() ([]MonitorOutage, error) {
 
 func (s *Store) GetResolvedOutages(since time.Time) ([]MonitorOutage, error) {
 	query := `
-		SELECT o.id, o.monitor_id, o.type, o.summary, o.start_time, o.end_time, m.name, g.name, g.id
+		SELECT o.id, o.monitor_id, o.type, o.summary, COALESCE(o.error_kind, ''), o.start_time, o.end_time, m.name, g.name, g.id
 		FROM monitor_outages o
 		JOIN monitors m ON o.monitor_id = m.id
 		JOIN groups g ON m.group_id = g.id
@@ -249,7 +532,7 @@ func (s *Store) GetResolvedOutages(since time.Time) ([]MonitorOutage, error) {
 	for rows.Next() {
 		var o MonitorOutage
 		var endTime sql.NullTime
-		if err := rows.Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.StartTime, &endTime, &o.MonitorName, &o.GroupName, &o.GroupID); err != nil {
+		if err := rows.Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.ErrorKind, &o.StartTime, &endTime, &o.MonitorName, &o.GroupName, &o.GroupID); err != nil {
 			return nil, err
 		}
 		if endTime.Valid {
@@ -263,7 +546,7 @@ func (s *Store) GetResolvedOutages(since time.Time) ([]MonitorOutage, error) {
 // GetOutageByID returns a single outage by its ID
 func (s *Store) GetOutageByID(id int64) (*MonitorOutage, error) {
 	query := `
-		SELECT o.id, o.monitor_id, o.type, o.summary, o.start_time, o.end_time, m.name, g.name, g.id
+		SELECT o.id, o.monitor_id, o.type, o.summary, COALESCE(o.error_kind, ''), o.evidence, COALESCE(o.path_report, ''), o.remediation, o.start_time, o.end_time, m.name, g.name, g.id
 		FROM monitor_outages o
 		JOIN monitors m ON o.monitor_id = m.id
 		JOIN groups g ON m.group_id = g.id
@@ -271,7 +554,8 @@ func (s *Store) GetOutageByID(id int64) (*MonitorOutage, error) {
 	`
 	var o MonitorOutage
 	var endTime sql.NullTime
-	err := s.db.QueryRow(s.rebind(query), id).Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.StartTime, &endTime, &o.MonitorName, &o.GroupName, &o.GroupID)
+	var evidence, remediation sql.NullString
+	err := s.db.QueryRow(s.rebind(query), id).Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.ErrorKind, &evidence, &o.PathReport, &remediation, &o.StartTime, &endTime, &o.MonitorName, &o.GroupName, &o.GroupID)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -281,56 +565,43 @@ func (s *Store) GetOutageByID(id int64) (*MonitorOutage, error) {
 	if endTime.Valid {
 		o.EndTime = &endTime.Time
 	}
+	if o.Evidence, err = unmarshalEvidence(evidence); err != nil {
+		return nil, err
+	}
+	if o.Remediation, err = unmarshalRemediation(remediation); err != nil {
+		return nil, err
+	}
 	return &o, nil
 }
 
 func (s *Store) BatchInsertChecks(checks []CheckResult) error {
-	if len(checks) == 0 {
-		return nil
-	}
-
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	stmt, err := tx.Prepare(s.rebind("INSERT INTO monitor_checks (monitor_id, status, latency, timestamp, status_code) VALUES (?, ?, ?, ?, ?)"))
-	if err != nil {
-		return err
-	}
-	defer func() { _ = stmt.Close() }()
-
-	for _, c := range checks {
-		_, err := stmt.Exec(c.MonitorID, c.Status, c.Latency, c.Timestamp, c.StatusCode)
-		if err != nil {
-			return err
-		}
-	}
-
-	return tx.Commit()
+	return s.checks.InsertChecks(checks)
 }
 
 // GetMonitorChecks returns the last N checks for a monitor
 func (s *Store) GetMonitorChecks(monitorID string, limit int) ([]CheckResult, error) {
-	query := s.rebind(`SELECT monitor_id, status, latency, timestamp, COALESCE(status_code, 0) FROM monitor_checks
-			  WHERE monitor_id = ? ORDER BY timestamp DESC LIMIT ?`)
-
-	rows, err := s.db.Query(query, monitorID, limit)
+	checks, err := s.checks.RecentChecks([]string{monitorID}, limit)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = rows.Close() }()
+	return checks[monitorID], nil
+}
 
-	var checks []CheckResult
-	for rows.Next() {
-		var c CheckResult
-		if err := rows.Scan(&c.MonitorID, &c.Status, &c.Latency, &c.Timestamp, &c.StatusCode); err != nil {
-			return nil, err
-		}
-		checks = append(checks, c)
+// GetRecentChecksForMonitors returns up to perMonitorLimit of the newest checks of each
+// monitor, newest first like GetMonitorChecks, keyed by monitor ID. Monitors without
+// checks are absent. It replaces one GetMonitorChecks call per monitor when a large
+// fleet is loaded at once.
+func (s *Store) GetRecentChecksForMonitors(ids []string, perMonitorLimit int) (map[string][]CheckResult, error) {
+	if len(ids) == 0 {
+		return map[string][]CheckResult{}, nil
 	}
-	return checks, nil
+	return s.checks.RecentChecks(ids, perMonitorLimit)
+}
+
+// GetLatestChecks returns the most recent persisted check of every monitor, keyed by
+// monitor ID, so last-known statuses can be shown before monitors are hydrated.
+func (s *Store) GetLatestChecks() (map[string]CheckResult, error) {
+	return s.checks.LatestChecks()
 }
 
 func (s *Store) PruneMonitorChecks(days int) error {
@@ -338,59 +609,57 @@ func (s *Store) PruneMonitorChecks(days int) error {
 	if days < 1 || days > 3650 { // Max 10 years
 		return fmt.Errorf("invalid retention days: must be between 1 and 3650")
 	}
+	return s.checks.PruneChecks(time.Now().AddDate(0, 0, -days))
+}
 
-	var err error
-	if s.IsPostgres() {
-		// PostgreSQL: Use parameterized INTERVAL with MAKE_INTERVAL function
-		_, err = s.db.Exec("DELETE FROM monitor_checks WHERE timestamp < NOW() - MAKE_INTERVAL(days => $1)", days)
-	} else {
-		// SQLite: Use parameterized query
-		_, err = s.db.Exec("DELETE FROM monitor_checks WHERE timestamp < datetime('now', '-' || ? || ' days')", days)
+// PruneResolvedOutages deletes outages that ended before the cutoff. Outages promoted to an
+// incident are kept, since the incident still references them.
+func (s *Store) PruneResolvedOutages(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind(`
+		DELETE FROM monitor_outages
+		WHERE end_time IS NOT NULL AND end_time < ?
+		AND id NOT IN (SELECT outage_id FROM incidents WHERE outage_id IS NOT NULL)
+	`), before)
+	if err != nil {
+		return 0, err
 	}
-	return err
+	return res.RowsAffected()
 }
 
-func (s *Store) GetUptimeStats(monitorID string) (float64, float64, float64, error) {
-	var query string
-	if s.IsPostgres() {
-		query = `
-			SELECT
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '1 days' THEN 1 END) as total_24h,
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '1 days' AND status = 'up' THEN 1 END) as up_24h,
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '7 days' THEN 1 END) as total_7d,
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '7 days' AND status = 'up' THEN 1 END) as up_7d,
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '30 days' THEN 1 END) as total_30d,
-				COUNT(CASE WHEN timestamp > NOW() - INTERVAL '30 days' AND status = 'up' THEN 1 END) as up_30d
-			FROM monitor_checks
-			WHERE monitor_id = $1
-		`
-	} else {
-		query = `
-			SELECT
-				COUNT(CASE WHEN timestamp > datetime('now', '-1 days') THEN 1 END) as total_24h,
-				COUNT(CASE WHEN timestamp > datetime('now', '-1 days') AND status = 'up' THEN 1 END) as up_24h,
-				COUNT(CASE WHEN timestamp > datetime('now', '-7 days') THEN 1 END) as total_7d,
-				COUNT(CASE WHEN timestamp > datetime('now', '-7 days') AND status = 'up' THEN 1 END) as up_7d,
-				COUNT(CASE WHEN timestamp > datetime('now', '-30 days') THEN 1 END) as total_30d,
-				COUNT(CASE WHEN timestamp > datetime('now', '-30 days') AND status = 'up' THEN 1 END) as up_30d
-			FROM monitor_checks
-			WHERE monitor_id = ?
-		`
-	}
-	var t24, u24, t7, u7, t30, u30 int
-	err := s.db.QueryRow(query, monitorID).Scan(&t24, &u24, &t7, &u7, &t30, &u30)
+// PruneMonitorEvents keeps only the newest perMonitor events of each monitor.
+func (s *Store) PruneMonitorEvents(perMonitor int) (int64, error) {
+	if perMonitor < 1 {
+		return 0, fmt.Errorf("invalid event cap: must be at least 1")
+	}
+	res, err := s.db.Exec(s.rebind(`
+		DELETE FROM monitor_events WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY monitor_id ORDER BY timestamp DESC, id DESC) AS rn
+				FROM monitor_events
+			) ranked
+			WHERE rn > ?
+		)
+	`), perMonitor)
 	if err != nil {
-		return 0, 0, 0, err
+		return 0, err
 	}
+	return res.RowsAffected()
+}
 
-	calc := func(up, total int) float64 {
-		if total == 0 {
-			return 100.0 // Assume 100% if no data
+func (s *Store) GetUptimeStats(monitorID string) (float64, float64, float64, error) {
+	now := time.Now()
+	var pct [3]float64
+	for i, days := range []int{1, 7, 30} {
+		total, up, err := s.checks.CountChecks(monitorID, now.AddDate(0, 0, -days), time.Time{}, nil)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		pct[i] = 100.0 // Assume 100% if no data
+		if total > 0 {
+			pct[i] = (float64(up) / float64(total)) * 100.0
 		}
-		return (float64(up) / float64(total)) * 100.0
 	}
-
-	return calc(u24, t24), calc(u7, t7), calc(u30, t30), nil
+	return pct[0], pct[1], pct[2], nil
 }
 
 func (s *Store) GetMonitorEvents(monitorID string, limit int) ([]MonitorEvent, error) {
@@ -486,65 +755,44 @@ type DailyUptimeStat struct {
 	UptimePercent float64 `json:"uptimePercent"`
 }
 
-// GetDailyUptimeStats returns per-day uptime percentages for the last N days.
+// GetDailyUptimeStats returns per-day uptime percentages for the last N days, with days in UTC.
 func (s *Store) GetDailyUptimeStats(monitorID string, days int) ([]DailyUptimeStat, error) {
+	return s.GetDailyUptimeStatsInLocation(monitorID, days, time.UTC)
+}
+
+// GetDailyUptimeStatsInLocation returns per-day uptime percentages for the last N
+// calendar days in loc, so a "day" matches the viewer's midnight-to-midnight.
+func (s *Store) GetDailyUptimeStatsInLocation(monitorID string, days int, loc *time.Location) ([]DailyUptimeStat, error) {
 	if days < 1 || days > 365 {
 		return nil, fmt.Errorf("invalid days: must be between 1 and 365")
 	}
 
-	var query string
-	if s.IsPostgres() {
-		query = `
-			SELECT
-				TO_CHAR(timestamp, 'YYYY-MM-DD') as day,
-				COUNT(*) as total,
-				SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as up_count
-			FROM monitor_checks
-			WHERE monitor_id = $1
-			AND timestamp >= NOW() - MAKE_INTERVAL(days => $2)
-			GROUP BY day
-			ORDER BY day ASC
-		`
-	} else {
-		query = `
-			SELECT
-				DATE(timestamp) as day,
-				COUNT(*) as total,
-				SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as up_count
-			FROM monitor_checks
-			WHERE monitor_id = ?
-			AND timestamp >= datetime('now', '-' || ? || ' days')
-			GROUP BY day
-			ORDER BY day ASC
-		`
-	}
+	now := time.Now().In(loc)
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -(days - 1))
 
-	rows, err := s.db.Query(s.rebind(query), monitorID, days)
+	// Group by 15-minute UTC slots (every timezone offset is a multiple of 15 minutes)
+	// and assign each slot to its local day below.
+	slots, err := s.checks.CheckSlots(monitorID, since, 15*time.Minute)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = rows.Close() }()
 
-	// Collect results from DB into a map for quick lookup
 	dayMap := make(map[string]DailyUptimeStat)
-	for rows.Next() {
-		var stat DailyUptimeStat
-		if err := rows.Scan(&stat.Date, &stat.Total, &stat.Up); err != nil {
-			return nil, err
-		}
-		if stat.Total > 0 {
-			stat.UptimePercent = (float64(stat.Up) / float64(stat.Total)) * 100.0
-		}
-		dayMap[stat.Date] = stat
+	for _, slot := range slots {
+		date := slot.Start.In(loc).Format("2006-01-02")
+		stat := dayMap[date]
+		stat.Date = date
+		stat.Total += int(slot.Checks)
+		stat.Up += int(slot.Up)
+		dayMap[date] = stat
 	}
 
 	// Build a complete slice with all days (fill gaps with no-data entries)
-	now := time.Now().UTC()
 	result := make([]DailyUptimeStat, days)
 	for i := 0; i < days; i++ {
-		d := now.AddDate(0, 0, -(days-1-i))
-		dateStr := d.Format("2006-01-02")
-		if stat, ok := dayMap[dateStr]; ok {
+		dateStr := since.AddDate(0, 0, i).Format("2006-01-02")
+		if stat, ok := dayMap[dateStr]; ok && stat.Total > 0 {
+			stat.UptimePercent = (float64(stat.Up) / float64(stat.Total)) * 100.0
 			result[i] = stat
 		} else {
 			result[i] = DailyUptimeStat{Date: dateStr, Total: 0, UptimePercent: -1} // -1 = no data
@@ -554,6 +802,24 @@ func (s *Store) GetDailyUptimeStats(monitorID string, days int) ([]DailyUptimeSt
 	return result, nil
 }
 
+// TimeWindow is a half-open [Start, End) time interval.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GetUptimeCounts returns the number of checks and of successful checks for a monitor
+// since the given time, ignoring checks that fall inside any of the excluded windows.
+func (s *Store) GetUptimeCounts(monitorID string, since time.Time, exclude []TimeWindow) (total, up int, err error) {
+	return s.GetUptimeCountsBetween(monitorID, since, time.Time{}, exclude)
+}
+
+// GetUptimeCountsBetween is GetUptimeCounts for the checks before until; a zero until
+// leaves the range open.
+func (s *Store) GetUptimeCountsBetween(monitorID string, since, until time.Time, exclude []TimeWindow) (total, up int, err error) {
+	return s.checks.CountChecks(monitorID, since, until, exclude)
+}
+
 // toNullInt64 converts an *int to sql.NullInt64 for nullable column storage.
 func toNullInt64(v *int) sql.NullInt64 {
 	if v == nil {
@@ -562,83 +828,76 @@ func toNullInt64(v *int) sql.NullInt64 {
 	return sql.NullInt64{Int64: int64(*v), Valid: true}
 }
 
+// GetLatencyStats returns average latency per bucket over the last N hours, bucketed in UTC.
 func (s *Store) GetLatencyStats(monitorID string, hours int) ([]LatencyPoint, error) {
+	return s.GetLatencyStatsInLocation(monitorID, hours, time.UTC)
+}
+
+// GetLatencyStatsInLocation returns average latency per minute (1h), hour (up to 7d)
+// or day (longer ranges), with hour and day boundaries taken in loc.
+func (s *Store) GetLatencyStatsInLocation(monitorID string, hours int, loc *time.Location) ([]LatencyPoint, error) {
 	// SECURITY: Validate input
 	if hours < 1 || hours > 8760 { // Max 1 year
 		return nil, fmt.Errorf("invalid hours: must be between 1 and 8760")
 	}
 
-	var query string
-	var groupBy string
-
-	if s.IsPostgres() {
-		if hours <= 1 {
-			groupBy = "TO_CHAR(timestamp, 'YYYY-MM-DD HH24:MI:00')"
-		} else if hours <= 168 {
-			groupBy = "TO_CHAR(timestamp, 'YYYY-MM-DD HH24:00:00')"
-		} else {
-			groupBy = "TO_CHAR(timestamp, 'YYYY-MM-DD')"
-		}
-		// Use MAKE_INTERVAL for parameterized interval
-		query = fmt.Sprintf(`
-			SELECT
-				%s as ts_group,
-				CAST(AVG(latency) AS INTEGER) as avg_latency,
-				MAX(CASE WHEN status != 'up' THEN 1 ELSE 0 END) as failed
-			FROM monitor_checks
-			WHERE monitor_id = $1
-			AND timestamp > NOW() - MAKE_INTERVAL(hours => $2)
-			GROUP BY ts_group
-			ORDER BY ts_group ASC
-		`, groupBy)
-	} else {
-		if hours <= 1 {
-			groupBy = "strftime('%Y-%m-%d %H:%M:00', timestamp)"
-		} else if hours <= 168 {
-			groupBy = "strftime('%Y-%m-%d %H:00:00', timestamp)"
-		} else {
-			groupBy = "strftime('%Y-%m-%d', timestamp)"
-		}
-		query = fmt.Sprintf(`
-			SELECT
-				%s as ts_group,
-				CAST(AVG(latency) AS INTEGER) as avg_latency,
-				MAX(CASE WHEN status != 'up' THEN 1 ELSE 0 END) as failed
-			FROM monitor_checks
-			WHERE monitor_id = ?
-			AND datetime(timestamp) > datetime('now', '-' || ? || ' hours')
-			GROUP BY ts_group
-			ORDER BY ts_group ASC
-		`, groupBy)
-	}
-
-	var rows *sql.Rows
-	var err error
-	if s.IsPostgres() {
-		rows, err = s.db.Query(query, monitorID, hours)
-	} else {
-		rows, err = s.db.Query(query, monitorID, hours)
+	// Aggregate per UTC minute (1h) or 15-minute slot, then regroup into local buckets
+	// so non-UTC and half-hour offsets line up with the viewer's clock.
+	width := 15 * time.Minute
+	if hours <= 1 {
+		width = time.Minute
 	}
+	slots, err := s.checks.CheckSlots(monitorID, time.Now().Add(-time.Duration(hours)*time.Hour), width)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = rows.Close() }()
 
-	var points []LatencyPoint
-	for rows.Next() {
-		var p LatencyPoint
-		var tsStr string
-		if err := rows.Scan(&tsStr, &p.Latency, &p.Failed); err != nil {
-			return nil, err
+	type bucket struct {
+		total  int64
+		checks int64
+		failed bool
+		timing CheckTiming
+		timed  int64
+	}
+	buckets := make(map[time.Time]*bucket)
+	for _, slot := range slots {
+		local := slot.Start.In(loc)
+		var key time.Time
+		switch {
+		case hours <= 1:
+			key = local
+		case hours <= 168:
+			key = time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+		default:
+			key = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{}
+			buckets[key] = b
 		}
+		b.total += slot.LatencySum
+		b.checks += slot.Checks
+		b.failed = b.failed || slot.Up < slot.Checks
+		if slot.TimingSum != nil {
+			b.timing.add(*slot.TimingSum)
+			b.timed += slot.Timed
+		}
+	}
 
-		// Parse timestamp string
-		if len(tsStr) == 10 { // YYYY-MM-DD
-			p.Timestamp, _ = time.Parse("2006-01-02", tsStr)
-		} else {
-			p.Timestamp, _ = time.Parse("2006-01-02 15:04:05", tsStr)
+	points := make([]LatencyPoint, 0, len(buckets))
+	for ts, b := range buckets {
+		p := LatencyPoint{Timestamp: ts, Failed: b.failed, Timing: b.timing.average(b.timed)}
+		if b.checks > 0 {
+			p.Latency = b.total / b.checks
 		}
 		points = append(points, p)
 	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
 	return points, nil
 }
+
+// GetAverageLatencies returns the mean check latency in milliseconds per monitor since the given time.
+func (s *Store) GetAverageLatencies(since time.Time) (map[string]float64, error) {
+	return s.checks.AverageLatencies(since)
+}
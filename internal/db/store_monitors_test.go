@@ -1,6 +1,9 @@
 package db
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -69,6 +72,48 @@ func TestMonitorCRUD(t *testing.T) {
 	}
 }
 
+func TestMonitorNameConstraints(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", URL: "http://a.com"}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "Web", URL: "http://b.com"}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	// Names are unique ignoring case
+	err := s.CreateMonitor(Monitor{ID: "m3", GroupID: "g1", Name: "api", URL: "http://c.com"})
+	if !errors.Is(err, ErrDuplicateMonitorName) || !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrDuplicateMonitorName, got %v", err)
+	}
+	if err := s.UpdateMonitor("m2", "Api", "http://b.com", 60, nil, nil, nil, nil); !errors.Is(err, ErrDuplicateMonitorName) {
+		t.Errorf("expected ErrDuplicateMonitorName on rename, got %v", err)
+	}
+	// Keeping its own name, or changing its case, is not a clash
+	if err := s.UpdateMonitor("m1", "api", "http://a.com", 60, nil, nil, nil, nil); err != nil {
+		t.Errorf("UpdateMonitor failed: %v", err)
+	}
+
+	// Reusing an ID is a conflict too, but not a duplicate name
+	err = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "Other", URL: "http://d.com"})
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrDuplicateMonitorName) {
+		t.Errorf("expected an ID conflict, got %v", err)
+	}
+
+	long := strings.Repeat("n", MaxMonitorNameLength+1)
+	if err := s.CreateMonitor(Monitor{ID: "m4", GroupID: "g1", Name: long}); !errors.Is(err, ErrMonitorNameTooLong) {
+		t.Errorf("expected ErrMonitorNameTooLong, got %v", err)
+	}
+	if err := s.UpdateMonitor("m1", long, "http://a.com", 60, nil, nil, nil, nil); !errors.Is(err, ErrMonitorNameTooLong) {
+		t.Errorf("expected ErrMonitorNameTooLong on update, got %v", err)
+	}
+
+	if err := s.UpdateMonitor("missing", "Missing", "http://e.com", 60, nil, nil, nil, nil); !errors.Is(err, ErrMonitorNotFound) {
+		t.Errorf("expected ErrMonitorNotFound, got %v", err)
+	}
+}
+
 func TestMonitorChecksAndEvents(t *testing.T) {
 	s := newTestStore(t)
 	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
@@ -103,6 +148,96 @@ func TestMonitorChecksAndEvents(t *testing.T) {
 	}
 }
 
+func TestGetRecentChecksForMonitors(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	for _, id := range []string{"m1", "m2", "m3"} {
+		_ = s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, Interval: 60})
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var checks []CheckResult
+	for i := 0; i < 5; i++ {
+		checks = append(checks, CheckResult{MonitorID: "m1", Status: "up", Latency: int64(i), Timestamp: now.Add(time.Duration(i) * time.Minute)})
+	}
+	checks = append(checks, CheckResult{MonitorID: "m2", Status: "down", ErrorKind: "timeout", Region: "eu-west", Timestamp: now})
+	if err := s.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	// Span more than one query batch, including IDs that do not exist
+	ids := []string{"m1"}
+	for i := 0; i < recentChecksBatch; i++ {
+		ids = append(ids, fmt.Sprintf("missing-%d", i))
+	}
+	ids = append(ids, "m2", "m3")
+
+	recent, err := s.GetRecentChecksForMonitors(ids, 3)
+	if err != nil {
+		t.Fatalf("GetRecentChecksForMonitors failed: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("Expected checks for 2 monitors, got %d", len(recent))
+	}
+	m1 := recent["m1"]
+	if len(m1) != 3 {
+		t.Fatalf("Expected the limit of 3 checks for m1, got %d", len(m1))
+	}
+	for i, c := range m1 {
+		if want := int64(4 - i); c.Latency != want {
+			t.Errorf("m1[%d]: expected the newest checks first (latency %d), got %d", i, want, c.Latency)
+		}
+	}
+	if m2 := recent["m2"]; len(m2) != 1 || m2[0].ErrorKind != "timeout" || m2[0].Region != "eu-west" {
+		t.Errorf("Expected m2's check, got %+v", m2)
+	}
+
+	// Same checks as one GetMonitorChecks call
+	single, err := s.GetMonitorChecks("m1", 3)
+	if err != nil {
+		t.Fatalf("GetMonitorChecks failed: %v", err)
+	}
+	for i := range single {
+		if !single[i].Timestamp.Equal(m1[i].Timestamp) {
+			t.Errorf("m1[%d]: expected %v, got %v", i, single[i].Timestamp, m1[i].Timestamp)
+		}
+	}
+}
+
+func TestGetLatestChecks(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+	_ = s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "M2", Interval: 60})
+	_ = s.CreateMonitor(Monitor{ID: "m3", GroupID: "g1", Name: "M3", Interval: 60})
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := s.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m1", Status: "down", Timestamp: now.Add(-2 * time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 42, StatusCode: 200, Timestamp: now.Add(-time.Minute)},
+		{MonitorID: "m2", Status: "down", ErrorKind: "timeout", Timestamp: now.Add(-3 * time.Minute)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	latest, err := s.GetLatestChecks()
+	if err != nil {
+		t.Fatalf("GetLatestChecks failed: %v", err)
+	}
+	if len(latest) != 2 {
+		t.Fatalf("Expected checks for 2 monitors, got %d: %+v", len(latest), latest)
+	}
+	if c := latest["m1"]; c.Status != "up" || c.Latency != 42 || c.StatusCode != 200 || !c.Timestamp.Equal(now.Add(-time.Minute)) {
+		t.Errorf("Expected m1's newest check, got %+v", c)
+	}
+	if c := latest["m2"]; c.Status != "down" || c.ErrorKind != "timeout" {
+		t.Errorf("Expected m2's down check, got %+v", c)
+	}
+	if _, ok := latest["m3"]; ok {
+		t.Error("Expected no entry for a monitor without checks")
+	}
+}
+
 func TestCascadingDeletion(t *testing.T) {
 	s := newTestStore(t)
 
@@ -204,6 +339,108 @@ func TestMonitorOutages(t *testing.T) {
 	}
 }
 
+func TestPruneResolvedOutages(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+		if err := s.CreateOutage(id, "down", "Connection refused"); err != nil {
+			t.Fatalf("CreateOutage failed: %v", err)
+		}
+	}
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -400)
+	// m1: old and resolved; m2: old and promoted to an incident; m3: recently resolved; m4: still open
+	for _, id := range []string{"m1", "m2"} {
+		if _, err := s.db.Exec(s.rebind("UPDATE monitor_outages SET start_time = ?, end_time = ? WHERE monitor_id = ?"), old, old, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.db.Exec(s.rebind("UPDATE monitor_outages SET end_time = ? WHERE monitor_id = ?"), now, "m3"); err != nil {
+		t.Fatal(err)
+	}
+	var promoted int64
+	if err := s.db.QueryRow(s.rebind("SELECT id FROM monitor_outages WHERE monitor_id = ?"), "m2").Scan(&promoted); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateIncident(Incident{ID: "inc-1", Title: "Promoted", Type: "incident", Status: "resolved", StartTime: old, OutageID: &promoted}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	n, err := s.PruneResolvedOutages(now.AddDate(0, 0, -365))
+	if err != nil {
+		t.Fatalf("PruneResolvedOutages failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 pruned outage, got %d", n)
+	}
+
+	var remaining []string
+	rows, err := s.db.Query("SELECT monitor_id FROM monitor_outages ORDER BY monitor_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id string
+		_ = rows.Scan(&id)
+		remaining = append(remaining, id)
+	}
+	if strings.Join(remaining, ",") != "m2,m3,m4" {
+		t.Errorf("Expected outages of m2,m3,m4 to remain, got %v", remaining)
+	}
+}
+
+func TestPruneMonitorEvents(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	for _, id := range []string{"m1", "m2"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := s.CreateEvent("m1", "down", fmt.Sprintf("event %d", i)); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+	if err := s.CreateEvent("m2", "down", "only event"); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	n, err := s.PruneMonitorEvents(2)
+	if err != nil {
+		t.Fatalf("PruneMonitorEvents failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 pruned events, got %d", n)
+	}
+
+	events, err := s.GetMonitorEvents("m1", 10)
+	if err != nil {
+		t.Fatalf("GetMonitorEvents failed: %v", err)
+	}
+	// Events created within the same second share a timestamp, so compare regardless of order
+	kept := map[string]bool{}
+	for _, e := range events {
+		kept[e.Message] = true
+	}
+	if len(events) != 2 || !kept["event 3"] || !kept["event 4"] {
+		t.Errorf("Expected the newest 2 events to remain, got %+v", events)
+	}
+
+	if _, err := s.PruneMonitorEvents(0); err == nil {
+		t.Error("Expected error for a zero cap")
+	}
+}
+
 func TestGetActiveSSLWarnings_Empty(t *testing.T) {
 	s := newTestStore(t)
 
@@ -888,6 +1125,115 @@ func TestGetDailyUptimeStats_GapFilling(t *testing.T) {
 	}
 }
 
+func TestGetDailyUptimeStats_Timezone(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	// 23:30 UTC two days ago is already the next day at UTC+2
+	now := time.Now().UTC()
+	ts := time.Date(now.Year(), now.Month(), now.Day(), 23, 30, 0, 0, time.UTC).AddDate(0, 0, -2)
+	if err := s.BatchInsertChecks([]CheckResult{{MonitorID: "m1", Status: "down", Timestamp: ts}}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	totals := func(loc *time.Location) map[string]int {
+		stats, err := s.GetDailyUptimeStatsInLocation("m1", 7, loc)
+		if err != nil {
+			t.Fatalf("GetDailyUptimeStatsInLocation failed: %v", err)
+		}
+		if len(stats) != 7 {
+			t.Fatalf("Expected 7 days, got %d", len(stats))
+		}
+		m := make(map[string]int)
+		for _, d := range stats {
+			m[d.Date] = d.Total
+		}
+		return m
+	}
+
+	utcDay := ts.Format("2006-01-02")
+	localDay := ts.Add(24 * time.Hour).Format("2006-01-02")
+	if got := totals(time.UTC); got[utcDay] != 1 || got[localDay] != 0 {
+		t.Errorf("UTC: expected check on %s, got %v", utcDay, got)
+	}
+	if got := totals(time.FixedZone("UTC+2", 2*3600)); got[localDay] != 1 || got[utcDay] != 0 {
+		t.Errorf("UTC+2: expected check on %s, got %v", localDay, got)
+	}
+}
+
+func TestGetLatencyStats_Timezone(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	hour := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Hour)
+	if err := s.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: hour.Add(10 * time.Minute), StatusCode: 200},
+		{MonitorID: "m1", Status: "up", Latency: 300, Timestamp: hour.Add(45 * time.Minute), StatusCode: 200},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	points, err := s.GetLatencyStatsInLocation("m1", 24, time.UTC)
+	if err != nil {
+		t.Fatalf("GetLatencyStatsInLocation failed: %v", err)
+	}
+	if len(points) != 1 || !points[0].Timestamp.Equal(hour) || points[0].Latency != 200 {
+		t.Fatalf("UTC: expected one hourly point at %v averaging 200ms, got %+v", hour, points)
+	}
+
+	// At UTC+5:30 local hours start on the half hour (UTC), splitting the two checks
+	points, err = s.GetLatencyStatsInLocation("m1", 24, time.FixedZone("IST", 5*3600+1800))
+	if err != nil {
+		t.Fatalf("GetLatencyStatsInLocation failed: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("UTC+5:30: expected two hourly points, got %+v", points)
+	}
+	if !points[0].Timestamp.Equal(hour.Add(-30*time.Minute)) || points[0].Latency != 100 ||
+		!points[1].Timestamp.Equal(hour.Add(30*time.Minute)) || points[1].Latency != 300 {
+		t.Errorf("UTC+5:30: unexpected buckets %+v", points)
+	}
+}
+
+func TestCheckTiming(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	hour := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Hour)
+	if err := s.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: hour.Add(10 * time.Minute), StatusCode: 200,
+			Timing: &CheckTiming{DNS: 10, Connect: 20, TLS: 30, TTFB: 40, Download: 5, BodyBytes: 1000}},
+		{MonitorID: "m1", Status: "up", Latency: 300, Timestamp: hour.Add(20 * time.Minute), StatusCode: 200,
+			Timing: &CheckTiming{TTFB: 280, Download: 15, BodyBytes: 3000}},
+		// Checks without a response have no timing and don't count towards the averages
+		{MonitorID: "m1", Status: "down", Latency: 5000, Timestamp: hour.Add(30 * time.Minute), ErrorKind: ErrorKindTimeout},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	checks, err := s.GetMonitorChecks("m1", 10)
+	if err != nil || len(checks) != 3 {
+		t.Fatalf("GetMonitorChecks: %v, %d checks", err, len(checks))
+	}
+	if checks[0].Timing != nil {
+		t.Errorf("Expected no timing for the failed check, got %+v", checks[0].Timing)
+	}
+	if got := checks[2].Timing; got == nil || *got != (CheckTiming{DNS: 10, Connect: 20, TLS: 30, TTFB: 40, Download: 5, BodyBytes: 1000}) {
+		t.Errorf("Expected timing to round-trip, got %+v", got)
+	}
+
+	points, err := s.GetLatencyStatsInLocation("m1", 24, time.UTC)
+	if err != nil || len(points) != 1 {
+		t.Fatalf("GetLatencyStatsInLocation: %v, %+v", err, points)
+	}
+	if got := points[0].Timing; got == nil || *got != (CheckTiming{DNS: 5, Connect: 10, TLS: 15, TTFB: 160, Download: 10, BodyBytes: 2000}) {
+		t.Errorf("Expected averaged timing, got %+v", got)
+	}
+}
+
 // ============== PER-MONITOR OVERRIDE CRUD TESTS ==============
 
 func intPtr(v int) *int { return &v }
@@ -1334,3 +1680,74 @@ func TestMonitorCRUD_RequestConfig(t *testing.T) {
 		t.Errorf("Expected RequestConfig to be nil after clearing, got %+v", found.RequestConfig)
 	}
 }
+
+func TestMonitorDownBackoff(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	interval, after := 300, 10
+	if err := s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", URL: "http://a.com", Interval: 60, DownBackoffInterval: &interval, DownBackoffAfterChecks: &after}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m, _ := s.GetMonitor("m1")
+	if m.DownBackoffInterval == nil || *m.DownBackoffInterval != 300 || m.DownBackoffAfterChecks == nil || *m.DownBackoffAfterChecks != 10 {
+		t.Errorf("Unexpected backoff settings: %v %v", m.DownBackoffInterval, m.DownBackoffAfterChecks)
+	}
+
+	if err := s.SetMonitorDownBackoff("m1", nil, nil); err != nil {
+		t.Fatalf("SetMonitorDownBackoff failed: %v", err)
+	}
+	m, _ = s.GetMonitor("m1")
+	if m.DownBackoffInterval != nil || m.DownBackoffAfterChecks != nil {
+		t.Error("Expected backoff settings to be cleared")
+	}
+	if err := s.SetMonitorDownBackoff("missing", &interval, nil); err != ErrMonitorNotFound {
+		t.Errorf("Expected ErrMonitorNotFound, got %v", err)
+	}
+}
+
+func TestGetUptimeCounts_ExcludesWindows(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	base := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Minute)
+
+	// 6 up, then 4 down during a maintenance window
+	var checks []CheckResult
+	for i := 0; i < 10; i++ {
+		status := "up"
+		if i >= 6 {
+			status = "down"
+		}
+		checks = append(checks, CheckResult{
+			MonitorID: "m1", Status: status, Latency: 50,
+			Timestamp: base.Add(time.Duration(i) * time.Minute), StatusCode: 200,
+		})
+	}
+	if err := s.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	total, up, err := s.GetUptimeCounts("m1", base.Add(-time.Hour), nil)
+	if err != nil {
+		t.Fatalf("GetUptimeCounts failed: %v", err)
+	}
+	if total != 10 || up != 6 {
+		t.Errorf("Expected 10 total / 6 up, got %d / %d", total, up)
+	}
+
+	window := []TimeWindow{{Start: base.Add(6 * time.Minute), End: base.Add(10 * time.Minute)}}
+	total, up, err = s.GetUptimeCounts("m1", base.Add(-time.Hour), window)
+	if err != nil {
+		t.Fatalf("GetUptimeCounts failed: %v", err)
+	}
+	if total != 6 || up != 6 {
+		t.Errorf("Expected maintenance checks excluded (6 / 6), got %d / %d", total, up)
+	}
+
+	// since filters out older checks
+	total, _, _ = s.GetUptimeCounts("m1", base.Add(5*time.Minute), nil)
+	if total != 5 {
+		t.Errorf("Expected 5 checks since cutoff, got %d", total)
+	}
+}
@@ -270,7 +270,7 @@ func TestMultiDB_Incidents(t *testing.T) {
 func TestMultiDB_APIKeys(t *testing.T) {
 	RunTestWithBothDBs(t, "APIKeys", func(t *testing.T, s *Store) {
 		// Create API key
-		key, err := s.CreateAPIKey("Test Key")
+		key, err := s.CreateAPIKey("Test Key", nil)
 		if err != nil {
 			t.Fatalf("CreateAPIKey failed: %v", err)
 		}
@@ -279,7 +279,7 @@ func TestMultiDB_APIKeys(t *testing.T) {
 		}
 
 		// Validate key
-		valid, err := s.ValidateAPIKey(key)
+		valid, err := s.ValidateAPIKey(key, "127.0.0.1")
 		if err != nil {
 			t.Fatalf("ValidateAPIKey failed: %v", err)
 		}
@@ -288,7 +288,7 @@ func TestMultiDB_APIKeys(t *testing.T) {
 		}
 
 		// Invalid key should fail
-		valid, _ = s.ValidateAPIKey("sk_live_INVALID")
+		valid, _ = s.ValidateAPIKey("sk_live_INVALID", "127.0.0.1")
 		if valid {
 			t.Error("Expected invalid key to be rejected")
 		}
@@ -362,6 +362,22 @@ func TestMultiDB_NotificationChannels(t *testing.T) {
 			t.Errorf("Expected 1 channel, got %d", len(channels))
 		}
 
+		// Disable channel, then re-enable it
+		if err := s.DisableNotificationChannel("nc-1", "endpoint unreachable"); err != nil {
+			t.Fatalf("DisableNotificationChannel failed: %v", err)
+		}
+		channels, _ = s.GetNotificationChannels()
+		if len(channels) != 1 || channels[0].Enabled || channels[0].DisabledReason != "endpoint unreachable" {
+			t.Errorf("Expected disabled channel with reason, got %+v", channels)
+		}
+		if err := s.UpdateNotificationChannel("nc-1", channel.Name, channel.Type, channel.Config, true); err != nil {
+			t.Fatalf("UpdateNotificationChannel failed: %v", err)
+		}
+		channels, _ = s.GetNotificationChannels()
+		if len(channels) != 1 || !channels[0].Enabled || channels[0].DisabledReason != "" {
+			t.Errorf("Expected re-enabled channel without reason, got %+v", channels)
+		}
+
 		// Delete channel
 		if err := s.DeleteNotificationChannel("nc-1"); err != nil {
 			t.Fatalf("DeleteNotificationChannel failed: %v", err)
@@ -499,7 +515,7 @@ func TestMultiDB_Events(t *testing.T) {
 		}
 
 		// Get system events
-		sysEvents, err := s.GetSystemEvents(10)
+		sysEvents, err := s.GetSystemEvents(SystemEventFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetSystemEvents failed: %v", err)
 		}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"strings"
+	"time"
+)
+
+// NotificationDedupWindow is how long delivered notifications are kept, so that one
+// enqueued again under the same dedup key is not delivered twice.
+const NotificationDedupWindow = 24 * time.Hour
+
+// QueuedNotification is a notification waiting to be delivered to one channel.
+type QueuedNotification struct {
+	ID            int64
+	DedupKey      string
+	ChannelID     string
+	Payload       string // the event, JSON-encoded by the notifier
+	Attempts      int
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+// EnqueueNotifications stores notifications for delivery, skipping those whose dedup key
+// is already queued or was delivered within NotificationDedupWindow. It returns how
+// many were added.
+func (s *Store) EnqueueNotifications(items []QueuedNotification) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(s.rebind(`
+		INSERT INTO notification_queue (dedup_key, channel_id, payload, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, 0, ?, ?)
+		ON CONFLICT (dedup_key) DO NOTHING
+	`))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	added := 0
+	for _, n := range items {
+		res, err := stmt.Exec(n.DedupKey, n.ChannelID, n.Payload, n.NextAttemptAt.UTC(), n.CreatedAt.UTC())
+		if err != nil {
+			return 0, err
+		}
+		if affected, _ := res.RowsAffected(); affected > 0 {
+			added++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// ClaimDueNotifications returns undelivered notifications of enabled channels whose
+// next attempt is due, in the order they became due, and leases them: they are not
+// returned again before the lease expires, which lets them be retried if the process
+// stops while delivering.
+func (s *Store) ClaimDueNotifications(now time.Time, lease time.Duration, limit int) ([]QueuedNotification, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query(s.rebind(`
+		SELECT q.id, q.dedup_key, q.channel_id, q.payload, q.attempts, q.next_attempt_at, q.created_at
+		FROM notification_queue q
+		JOIN notification_channels c ON c.id = q.channel_id
+		WHERE q.delivered_at IS NULL AND q.next_attempt_at <= ? AND c.enabled = ?
+		ORDER BY q.next_attempt_at, q.id
+		LIMIT ?
+	`), now.UTC(), true, limit)
+	if err != nil {
+		return nil, err
+	}
+	var items []QueuedNotification
+	for rows.Next() {
+		var n QueuedNotification
+		if err := rows.Scan(&n.ID, &n.DedupKey, &n.ChannelID, &n.Payload, &n.Attempts, &n.NextAttemptAt, &n.CreatedAt); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		items = append(items, n)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{now.Add(lease).UTC()}
+	for _, n := range items {
+		args = append(args, n.ID)
+	}
+	if _, err := tx.Exec(s.rebind("UPDATE notification_queue SET next_attempt_at = ? WHERE id IN (?"+strings.Repeat(", ?", len(items)-1)+")"), args...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkNotificationDelivered records that a queued notification was delivered.
+func (s *Store) MarkNotificationDelivered(id int64, at time.Time) error {
+	_, err := s.db.Exec(s.rebind("UPDATE notification_queue SET delivered_at = ? WHERE id = ?"), at.UTC(), id)
+	return err
+}
+
+// RescheduleNotification sets when a queued notification is attempted next, and how
+// many attempts it has used.
+func (s *Store) RescheduleNotification(id int64, attempts int, next time.Time) error {
+	_, err := s.db.Exec(s.rebind("UPDATE notification_queue SET attempts = ?, next_attempt_at = ? WHERE id = ?"),
+		attempts, next.UTC(), id)
+	return err
+}
+
+// DeleteQueuedNotification removes a notification that will not be delivered.
+func (s *Store) DeleteQueuedNotification(id int64) error {
+	_, err := s.db.Exec(s.rebind("DELETE FROM notification_queue WHERE id = ?"), id)
+	return err
+}
+
+// PruneDeliveredNotifications deletes notifications delivered before the given time.
+func (s *Store) PruneDeliveredNotifications(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM notification_queue WHERE delivered_at < ?"), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// dropQueuedNotifications discards the undelivered notifications of a channel that was
+// turned off, so they are not sent late once it is enabled again.
+func (s *Store) dropQueuedNotifications(channelID string) error {
+	_, err := s.db.Exec(s.rebind("DELETE FROM notification_queue WHERE channel_id = ? AND delivered_at IS NULL"), channelID)
+	return err
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationQueue(t *testing.T) {
+	s := newTestStore(t)
+	for _, id := range []string{"nc1", "nc2"} {
+		if err := s.CreateNotificationChannel(NotificationChannel{ID: id, Type: "webhook", Name: id, Config: "{}", Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now().UTC()
+
+	added, err := s.EnqueueNotifications([]QueuedNotification{
+		{DedupKey: "e1|nc1", ChannelID: "nc1", Payload: "{}", NextAttemptAt: now, CreatedAt: now},
+		{DedupKey: "e1|nc2", ChannelID: "nc2", Payload: "{}", NextAttemptAt: now, CreatedAt: now},
+		{DedupKey: "e1|nc1", ChannelID: "nc1", Payload: "{}", NextAttemptAt: now, CreatedAt: now},
+	})
+	if err != nil {
+		t.Fatalf("EnqueueNotifications: %v", err)
+	}
+	if added != 2 {
+		t.Fatalf("Expected the duplicate key to be skipped, added %d", added)
+	}
+
+	items, err := s.ClaimDueNotifications(now, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueNotifications: %v", err)
+	}
+	if len(items) != 2 || items[0].ChannelID != "nc1" || items[1].ChannelID != "nc2" {
+		t.Fatalf("Expected both notifications in order, got %+v", items)
+	}
+	if items, _ := s.ClaimDueNotifications(now, time.Minute, 10); len(items) != 0 {
+		t.Fatalf("Expected leased notifications not to be claimed again, got %d", len(items))
+	}
+	items, _ = s.ClaimDueNotifications(now.Add(time.Minute), time.Minute, 10)
+	if len(items) != 2 {
+		t.Fatalf("Expected notifications to be claimable once the lease expired, got %d", len(items))
+	}
+
+	// Deliver one, retry the other later
+	if err := s.MarkNotificationDelivered(items[0].ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RescheduleNotification(items[1].ID, 1, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := s.ClaimDueNotifications(now.Add(30*time.Minute), time.Minute, 10); len(items) != 0 {
+		t.Fatalf("Expected nothing due before the retry, got %+v", items)
+	}
+	items, _ = s.ClaimDueNotifications(now.Add(time.Hour), time.Minute, 10)
+	if len(items) != 1 || items[0].ChannelID != "nc2" || items[0].Attempts != 1 {
+		t.Fatalf("Expected the rescheduled notification with 1 attempt, got %+v", items)
+	}
+
+	// A delivered key still deduplicates
+	if added, _ := s.EnqueueNotifications([]QueuedNotification{{DedupKey: "e1|nc1", ChannelID: "nc1", Payload: "{}", NextAttemptAt: now, CreatedAt: now}}); added != 0 {
+		t.Errorf("Expected a delivered key to be skipped, added %d", added)
+	}
+
+	// Turning a channel off drops what is pending for it
+	if err := s.DisableNotificationChannel("nc2", "failing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateNotificationChannel("nc2", "nc2", "webhook", "{}", true); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := s.ClaimDueNotifications(now.Add(2*time.Hour), time.Minute, 10); len(items) != 0 {
+		t.Errorf("Expected the disabled channel's notification to be dropped, got %+v", items)
+	}
+
+	n, err := s.PruneDeliveredNotifications(now.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Expected the delivered notification to be pruned, pruned %d", n)
+	}
+}
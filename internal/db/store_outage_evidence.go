@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// OutageEvidence is what the failing check saw when an outage opened.
+type OutageEvidence struct {
+	StatusCode  int               `json:"statusCode,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`     // Server, Content-Type, Retry-After
+	BodySnippet string            `json:"bodySnippet,omitempty"` // Truncated response body
+	Error       string            `json:"error,omitempty"`       // Transport error when no response was received
+}
+
+// SetOutagePathReport attaches a path diagnostic report to the monitor's active outage.
+func (s *Store) SetOutagePathReport(monitorID, report string) error {
+	_, err := s.db.Exec(s.rebind("UPDATE monitor_outages SET path_report = ? WHERE monitor_id = ? AND end_time IS NULL"), report, monitorID)
+	return err
+}
+
+// SetOutageRemediation records the result of the remediation action run for the monitor's
+// latest down outage, which may already have ended if the action fixed it quickly.
+func (s *Store) SetOutageRemediation(monitorID string, run RemediationRun) error {
+	b, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.rebind(`
+		UPDATE monitor_outages SET remediation = ?
+		WHERE id = (SELECT MAX(id) FROM monitor_outages WHERE monitor_id = ? AND type = 'down')`), string(b), monitorID)
+	return err
+}
+
+func marshalEvidence(e *OutageEvidence) (sql.NullString, error) {
+	if e == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func unmarshalEvidence(s sql.NullString) (*OutageEvidence, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+	var e OutageEvidence
+	if err := json.Unmarshal([]byte(s.String), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func unmarshalRemediation(s sql.NullString) (*RemediationRun, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+	var r RemediationRun
+	if err := json.Unmarshal([]byte(s.String), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import "testing"
+
+func TestOutageEvidence(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	ev := &OutageEvidence{
+		StatusCode:  502,
+		Headers:     map[string]string{"Server": "envoy", "Content-Type": "text/plain"},
+		BodySnippet: "upstream connect error",
+	}
+	if err := s.CreateOutageWithEvidence("m1", "down", "Monitor is down (Status: 502)", ErrorKindHTTP5xx, ev); err != nil {
+		t.Fatalf("CreateOutageWithEvidence failed: %v", err)
+	}
+	if err := s.CreateOutage("m1", "degraded", "slow"); err != nil {
+		t.Fatalf("CreateOutage failed: %v", err)
+	}
+
+	outages, _ := s.GetActiveOutages()
+	if len(outages) != 2 {
+		t.Fatalf("Expected 2 outages, got %d", len(outages))
+	}
+	for _, o := range outages {
+		got, err := s.GetOutageByID(o.ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetOutageByID(%d) failed: %v", o.ID, err)
+		}
+		switch got.Type {
+		case "down":
+			if got.Evidence == nil || got.Evidence.StatusCode != 502 || got.Evidence.Headers["Server"] != "envoy" || got.Evidence.BodySnippet != "upstream connect error" {
+				t.Errorf("Unexpected evidence: %+v", got.Evidence)
+			}
+		case "degraded":
+			if got.Evidence != nil {
+				t.Errorf("Expected no evidence for outage without it, got %+v", got.Evidence)
+			}
+		}
+	}
+}
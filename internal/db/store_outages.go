@@ -0,0 +1,209 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Outage statuses of OutageFilter
+const (
+	OutageStatusActive   = "active"
+	OutageStatusResolved = "resolved"
+)
+
+// Page sizes of GetOutages
+const (
+	DefaultOutagesLimit = 100
+	MaxOutagesLimit     = 1000
+)
+
+// OutageFilter selects outages for GetOutages. Zero values match everything.
+type OutageFilter struct {
+	Status    string // OutageStatusActive or OutageStatusResolved
+	MonitorID string
+	GroupID   string
+	Since     time.Time // ongoing at or after
+	Until     time.Time // started before
+	Limit     int       // DefaultOutagesLimit when <= 0, at most MaxOutagesLimit
+}
+
+// GetOutages returns the outages matching f, latest start first. An outage matches a
+// date range when any part of it falls within the range.
+func (s *Store) GetOutages(f OutageFilter) ([]MonitorOutage, error) {
+	var where []string
+	var args []any
+	switch f.Status {
+	case OutageStatusActive:
+		where = append(where, "o.end_time IS NULL")
+	case OutageStatusResolved:
+		where = append(where, "o.end_time IS NOT NULL")
+	}
+	if f.MonitorID != "" {
+		where = append(where, "o.monitor_id = ?")
+		args = append(args, f.MonitorID)
+	}
+	if f.GroupID != "" {
+		where = append(where, "m.group_id = ?")
+		args = append(args, f.GroupID)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "(o.end_time IS NULL OR o.end_time >= ?)")
+		args = append(args, f.Since.UTC())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "o.start_time < ?")
+		args = append(args, f.Until.UTC())
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultOutagesLimit
+	}
+	if limit > MaxOutagesLimit {
+		limit = MaxOutagesLimit
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT o.id, o.monitor_id, o.type, o.summary, COALESCE(o.error_kind, ''), o.start_time, o.end_time, m.name, g.name, g.id
+		FROM monitor_outages o
+		JOIN monitors m ON o.monitor_id = m.id
+		JOIN groups g ON m.group_id = g.id`
+	if len(where) > 0 {
+		query += `
+		WHERE ` + strings.Join(where, " AND ")
+	}
+	query += `
+		ORDER BY o.start_time DESC, o.id DESC
+		LIMIT ?`
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	outages := []MonitorOutage{}
+	for rows.Next() {
+		var o MonitorOutage
+		var endTime sql.NullTime
+		if err := rows.Scan(&o.ID, &o.MonitorID, &o.Type, &o.Summary, &o.ErrorKind, &o.StartTime, &endTime, &o.MonitorName, &o.GroupName, &o.GroupID); err != nil {
+			return nil, err
+		}
+		if endTime.Valid {
+			o.EndTime = &endTime.Time
+		}
+		outages = append(outages, o)
+	}
+	return outages, rows.Err()
+}
+
+// GetMonitorEventsBetween returns up to limit events of a monitor in [since, until),
+// oldest first.
+func (s *Store) GetMonitorEventsBetween(monitorID string, since, until time.Time, limit int) ([]MonitorEvent, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT id, monitor_id, type, message, timestamp FROM monitor_events
+		WHERE monitor_id = ? AND timestamp >= ? AND timestamp < ?
+		ORDER BY timestamp, id LIMIT ?`), monitorID, since.UTC(), until.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	events := []MonitorEvent{}
+	for rows.Next() {
+		var e MonitorEvent
+		if err := rows.Scan(&e.ID, &e.MonitorID, &e.Type, &e.Message, &e.Timestamp); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetChecksBetween returns up to limit of a monitor's checks in [since, until), oldest
+// first.
+func (s *Store) GetChecksBetween(monitorID string, since, until time.Time, limit int) ([]CheckResult, error) {
+	checks := []CheckResult{}
+	err := s.checks.ScanChecks(monitorID, since, func(c CheckResult) error {
+		if c.Timestamp.Before(until) {
+			checks = append(checks, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Timestamp.Before(checks[j].Timestamp) })
+	if len(checks) > limit {
+		checks = checks[:limit]
+	}
+	return checks, nil
+}
+
+// GetIncidentByOutageID returns the incident an outage was promoted to, or nil if it
+// was not.
+func (s *Store) GetIncidentByOutageID(outageID int64) (*Incident, error) {
+	var id string
+	err := s.db.QueryRow(s.rebind("SELECT id FROM incidents WHERE outage_id = ? ORDER BY created_at LIMIT 1"), outageID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIncidentByID(id)
+}
+
+// MaxOutageAckNoteLength bounds the note of an acknowledgment.
+const MaxOutageAckNoteLength = 1000
+
+// OutageAcknowledgment records that someone is looking into an outage.
+type OutageAcknowledgment struct {
+	ID        int64     `json:"id"`
+	OutageID  int64     `json:"outageId"`
+	UserID    int64     `json:"userId"`
+	Username  string    `json:"username"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const outageAckInsert = "INSERT INTO outage_acknowledgments (outage_id, user_id, username, note, created_at) VALUES (?, ?, ?, ?, ?)"
+
+// AcknowledgeOutage records an acknowledgment and returns its ID.
+func (s *Store) AcknowledgeOutage(a OutageAcknowledgment) (int64, error) {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	if s.IsPostgres() {
+		var id int64
+		err := s.db.QueryRow(s.rebind(outageAckInsert+" RETURNING id"),
+			a.OutageID, a.UserID, a.Username, a.Note, a.CreatedAt.UTC()).Scan(&id)
+		return id, err
+	}
+	res, err := s.db.Exec(outageAckInsert, a.OutageID, a.UserID, a.Username, a.Note, a.CreatedAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetOutageAcknowledgments returns the acknowledgments of an outage, oldest first.
+func (s *Store) GetOutageAcknowledgments(outageID int64) ([]OutageAcknowledgment, error) {
+	rows, err := s.db.Query(s.rebind(`SELECT id, outage_id, user_id, username, note, created_at
+		FROM outage_acknowledgments WHERE outage_id = ? ORDER BY created_at, id`), outageID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	acks := []OutageAcknowledgment{}
+	for rows.Next() {
+		var a OutageAcknowledgment
+		if err := rows.Scan(&a.ID, &a.OutageID, &a.UserID, &a.Username, &a.Note, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		acks = append(acks, a)
+	}
+	return acks, rows.Err()
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetOutages(t *testing.T) {
+	store := newTestStore(t)
+	for _, g := range []Group{{ID: "g-web", Name: "Web"}, {ID: "g-api", Name: "API"}} {
+		if err := store.CreateGroup(g); err != nil {
+			t.Fatalf("CreateGroup failed: %v", err)
+		}
+	}
+	_ = store.CreateMonitor(Monitor{ID: "m-web", GroupID: "g-web", Name: "Web", URL: "http://web", Interval: 60})
+	_ = store.CreateMonitor(Monitor{ID: "m-api", GroupID: "g-api", Name: "API", URL: "http://api", Interval: 60})
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, _ = store.CreateClosedOutage("m-web", "down", "old", "", now.Add(-72*time.Hour), now.Add(-71*time.Hour))
+	_, _ = store.CreateClosedOutage("m-api", "down", "yesterday", "", now.Add(-26*time.Hour), now.Add(-25*time.Hour))
+	_ = store.CreateOutageAt("m-api", "down", "now", "", nil, now.Add(-time.Hour))
+
+	summaries := func(f OutageFilter) []string {
+		t.Helper()
+		outages, err := store.GetOutages(f)
+		if err != nil {
+			t.Fatalf("GetOutages failed: %v", err)
+		}
+		var got []string
+		for _, o := range outages {
+			got = append(got, o.Summary)
+		}
+		return got
+	}
+	tests := []struct {
+		name   string
+		filter OutageFilter
+		want   []string
+	}{
+		{"all", OutageFilter{}, []string{"now", "yesterday", "old"}},
+		{"active", OutageFilter{Status: OutageStatusActive}, []string{"now"}},
+		{"resolved", OutageFilter{Status: OutageStatusResolved}, []string{"yesterday", "old"}},
+		{"monitor", OutageFilter{MonitorID: "m-web"}, []string{"old"}},
+		{"group", OutageFilter{GroupID: "g-api"}, []string{"now", "yesterday"}},
+		{"since", OutageFilter{Since: now.Add(-25*time.Hour - 30*time.Minute)}, []string{"now", "yesterday"}},
+		{"until", OutageFilter{Until: now.Add(-2 * time.Hour)}, []string{"yesterday", "old"}},
+		{"limit", OutageFilter{Limit: 1}, []string{"now"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summaries(tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOutageDetails(t *testing.T) {
+	store := newTestStore(t)
+	_ = store.CreateGroup(Group{ID: "g-1", Name: "Web"})
+	_ = store.CreateMonitor(Monitor{ID: "m-1", GroupID: "g-1", Name: "Web", URL: "http://web", Interval: 60})
+	now := time.Now().UTC().Truncate(time.Second)
+	start, end := now.Add(-time.Hour), now.Add(-30*time.Minute)
+	if _, err := store.CreateClosedOutage("m-1", "down", "Connection refused", "", start, end); err != nil {
+		t.Fatalf("CreateClosedOutage failed: %v", err)
+	}
+	outages, _ := store.GetOutages(OutageFilter{})
+	if len(outages) != 1 {
+		t.Fatalf("Expected one outage, got %d", len(outages))
+	}
+	outageID := outages[0].ID
+
+	_ = store.CreateEventAt("m-1", "down", "before", start.Add(-time.Hour))
+	_ = store.CreateEventAt("m-1", "down", "went down", start)
+	_ = store.CreateEventAt("m-1", "recovered", "came back", end.Add(-time.Minute))
+	events, err := store.GetMonitorEventsBetween("m-1", start, end, 10)
+	if err != nil {
+		t.Fatalf("GetMonitorEventsBetween failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Message != "went down" || events[1].Message != "came back" {
+		t.Errorf("Expected the two events of the outage oldest first, got %+v", events)
+	}
+
+	_ = store.BatchInsertChecks([]CheckResult{
+		{MonitorID: "m-1", Status: "up", Timestamp: start.Add(-time.Minute)},
+		{MonitorID: "m-1", Status: "down", Timestamp: start.Add(2 * time.Minute)},
+		{MonitorID: "m-1", Status: "down", Timestamp: start},
+		{MonitorID: "m-1", Status: "up", Timestamp: end},
+	})
+	checks, err := store.GetChecksBetween("m-1", start, end, 10)
+	if err != nil {
+		t.Fatalf("GetChecksBetween failed: %v", err)
+	}
+	if len(checks) != 2 || !checks[0].Timestamp.Equal(start) {
+		t.Errorf("Expected the two checks of the outage oldest first, got %+v", checks)
+	}
+	if checks, _ := store.GetChecksBetween("m-1", start, end, 1); len(checks) != 1 {
+		t.Errorf("Expected the limit to apply, got %d checks", len(checks))
+	}
+
+	if inc, err := store.GetIncidentByOutageID(outageID); err != nil || inc != nil {
+		t.Errorf("Expected no incident before promotion, got %+v (%v)", inc, err)
+	}
+	_ = store.CreateIncident(Incident{ID: "inc-1", Title: "Web down", Type: "incident", Status: "investigating", StartTime: start, OutageID: &outageID})
+	if inc, err := store.GetIncidentByOutageID(outageID); err != nil || inc == nil || inc.ID != "inc-1" {
+		t.Errorf("Expected the promoted incident, got %+v (%v)", inc, err)
+	}
+
+	if _, err := store.AcknowledgeOutage(OutageAcknowledgment{OutageID: outageID, UserID: 1, Username: "alice", Note: "on it"}); err != nil {
+		t.Fatalf("AcknowledgeOutage failed: %v", err)
+	}
+	acks, err := store.GetOutageAcknowledgments(outageID)
+	if err != nil {
+		t.Fatalf("GetOutageAcknowledgments failed: %v", err)
+	}
+	if len(acks) != 1 || acks[0].Username != "alice" || acks[0].Note != "on it" {
+		t.Errorf("Unexpected acknowledgments %+v", acks)
+	}
+}
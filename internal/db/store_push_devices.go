@@ -0,0 +1,148 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrPushDeviceNotFound is returned when a user has no push device with the given ID.
+var ErrPushDeviceNotFound = errors.New("push device not found")
+
+// Push device platforms
+const (
+	PushPlatformFCM  = "fcm"  // Android app, through the push gateway
+	PushPlatformAPNs = "apns" // iOS app, through the push gateway
+	PushPlatformWeb  = "web"  // Browser, with Web Push
+)
+
+// PushDevice is a device a user registered for push notifications.
+type PushDevice struct {
+	ID       string `json:"id"`
+	UserID   int64  `json:"userId"`
+	Platform string `json:"platform"` // fcm | apns | web
+	// Token is the FCM or APNs device token, or the Web Push endpoint URL
+	Token string `json:"token"`
+	// P256DH and Auth are a browser's Web Push encryption keys, base64url encoded
+	P256DH    string    `json:"-"`
+	Auth      string    `json:"-"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is when a browser's subscription stops working, if the browser said
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ExpiredAt reports whether the device's subscription has expired by t.
+func (d PushDevice) ExpiredAt(t time.Time) bool {
+	return d.ExpiresAt != nil && !t.Before(*d.ExpiresAt)
+}
+
+const pushDeviceColumns = "id, user_id, platform, token, p256dh, auth, name, created_at, expires_at"
+
+func scanPushDevice(row rowScanner) (PushDevice, error) {
+	var d PushDevice
+	var expiresAt sql.NullTime
+	if err := row.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.P256DH, &d.Auth, &d.Name, &d.CreatedAt, &expiresAt); err != nil {
+		return d, err
+	}
+	if expiresAt.Valid {
+		d.ExpiresAt = &expiresAt.Time
+	}
+	return d, nil
+}
+
+func (s *Store) queryPushDevices(query string, args ...any) ([]PushDevice, error) {
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	devices := []PushDevice{}
+	for rows.Next() {
+		d, err := scanPushDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// RegisterPushDevice stores a device, or updates the device already registered with its
+// token, which then moves to d's user, and returns it as stored.
+func (s *Store) RegisterPushDevice(d PushDevice) (*PushDevice, error) {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	var expiresAt sql.NullTime
+	if d.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: d.ExpiresAt.UTC(), Valid: true}
+	}
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO push_devices (`+pushDeviceColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (token) DO UPDATE SET user_id = excluded.user_id, platform = excluded.platform,
+			p256dh = excluded.p256dh, auth = excluded.auth, name = excluded.name, expires_at = excluded.expires_at`),
+		d.ID, d.UserID, d.Platform, d.Token, d.P256DH, d.Auth, d.Name, d.CreatedAt.UTC(), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := scanPushDevice(s.db.QueryRow(s.rebind("SELECT "+pushDeviceColumns+" FROM push_devices WHERE token = ?"), d.Token))
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// ListPushDevices returns userID's push devices, oldest first.
+func (s *Store) ListPushDevices(userID int64) ([]PushDevice, error) {
+	return s.queryPushDevices("SELECT "+pushDeviceColumns+" FROM push_devices WHERE user_id = ? ORDER BY created_at, id", userID)
+}
+
+// GetPushDevices returns the push devices of every user.
+func (s *Store) GetPushDevices() ([]PushDevice, error) {
+	return s.queryPushDevices("SELECT " + pushDeviceColumns + " FROM push_devices ORDER BY created_at, id")
+}
+
+// DeletePushDevice removes one of userID's push devices. Devices of other users are
+// reported as not found.
+func (s *Store) DeletePushDevice(userID int64, id string) error {
+	res, err := s.db.Exec(s.rebind("DELETE FROM push_devices WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrPushDeviceNotFound
+	}
+	return nil
+}
+
+// DeletePushDeviceByToken removes the device registered with token, e.g. after the push
+// service reported it gone. It is not an error if there is none.
+func (s *Store) DeletePushDeviceByToken(token string) error {
+	_, err := s.db.Exec(s.rebind("DELETE FROM push_devices WHERE token = ?"), token)
+	return err
+}
+
+// UnregisterPushDevice removes userID's device registered with token, e.g. a browser
+// unsubscribing by its endpoint. Devices of other users are reported as not found.
+func (s *Store) UnregisterPushDevice(userID int64, token string) error {
+	res, err := s.db.Exec(s.rebind("DELETE FROM push_devices WHERE token = ? AND user_id = ?"), token, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrPushDeviceNotFound
+	}
+	return nil
+}
+
+// DeletePushDevicesByPlatform removes every device of a platform and returns how many
+// there were.
+func (s *Store) DeletePushDevicesByPlatform(platform string) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM push_devices WHERE platform = ?"), platform)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
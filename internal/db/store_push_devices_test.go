@@ -0,0 +1,105 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPushDevices(t *testing.T) {
+	s := newTestStore(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := s.CreateUser(name, "password123", "UTC"); err != nil {
+			t.Fatalf("CreateUser %s: %v", name, err)
+		}
+	}
+	alice, _ := s.Authenticate("alice", "password123")
+	bob, _ := s.Authenticate("bob", "password123")
+
+	phone, err := s.RegisterPushDevice(PushDevice{ID: "pd-phone", UserID: alice.ID, Platform: PushPlatformFCM, Token: "fcm-token", Name: "Pixel"})
+	if err != nil {
+		t.Fatalf("RegisterPushDevice failed: %v", err)
+	}
+	if phone.ID != "pd-phone" || phone.Name != "Pixel" || phone.CreatedAt.IsZero() {
+		t.Errorf("Unexpected device %+v", phone)
+	}
+	if _, err := s.RegisterPushDevice(PushDevice{ID: "pd-browser", UserID: alice.ID, Platform: PushPlatformWeb, Token: "https://push.example.com/abc", P256DH: "key", Auth: "secret"}); err != nil {
+		t.Fatalf("RegisterPushDevice failed: %v", err)
+	}
+
+	// Registering a known token again updates it and keeps its ID
+	moved, err := s.RegisterPushDevice(PushDevice{ID: "pd-other", UserID: bob.ID, Platform: PushPlatformFCM, Token: "fcm-token", Name: "Pixel 9"})
+	if err != nil {
+		t.Fatalf("RegisterPushDevice failed: %v", err)
+	}
+	if moved.ID != "pd-phone" || moved.UserID != bob.ID || moved.Name != "Pixel 9" {
+		t.Errorf("Expected the device to move to bob, got %+v", moved)
+	}
+
+	devices, err := s.ListPushDevices(alice.ID)
+	if err != nil || len(devices) != 1 || devices[0].ID != "pd-browser" || devices[0].P256DH != "key" || devices[0].Auth != "secret" {
+		t.Errorf("Expected alice's browser, got %+v (%v)", devices, err)
+	}
+	if all, _ := s.GetPushDevices(); len(all) != 2 {
+		t.Errorf("Expected 2 devices in total, got %d", len(all))
+	}
+
+	// Users can't delete each other's devices
+	if err := s.DeletePushDevice(alice.ID, "pd-phone"); !errors.Is(err, ErrPushDeviceNotFound) {
+		t.Errorf("Expected ErrPushDeviceNotFound, got %v", err)
+	}
+	if err := s.DeletePushDevice(bob.ID, "pd-phone"); err != nil {
+		t.Errorf("DeletePushDevice failed: %v", err)
+	}
+	if err := s.DeletePushDeviceByToken("https://push.example.com/abc"); err != nil {
+		t.Errorf("DeletePushDeviceByToken failed: %v", err)
+	}
+	if all, _ := s.GetPushDevices(); len(all) != 0 {
+		t.Errorf("Expected no devices left, got %+v", all)
+	}
+}
+
+func TestPushDevices_UnregisterAndExpiry(t *testing.T) {
+	s := newTestStore(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := s.CreateUser(name, "password123", "UTC"); err != nil {
+			t.Fatalf("CreateUser %s: %v", name, err)
+		}
+	}
+	alice, _ := s.Authenticate("alice", "password123")
+	bob, _ := s.Authenticate("bob", "password123")
+
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	for _, d := range []PushDevice{
+		{ID: "pd-1", UserID: alice.ID, Platform: PushPlatformWeb, Token: "https://push.example.com/1", ExpiresAt: &expires},
+		{ID: "pd-2", UserID: alice.ID, Platform: PushPlatformWeb, Token: "https://push.example.com/2"},
+		{ID: "pd-3", UserID: bob.ID, Platform: PushPlatformAPNs, Token: "apns-token"},
+	} {
+		if _, err := s.RegisterPushDevice(d); err != nil {
+			t.Fatalf("RegisterPushDevice failed: %v", err)
+		}
+	}
+
+	devices, _ := s.ListPushDevices(alice.ID)
+	if len(devices) != 2 || devices[0].ExpiresAt == nil || !devices[0].ExpiresAt.Equal(expires) || devices[1].ExpiresAt != nil {
+		t.Fatalf("Unexpected expiry in %+v", devices)
+	}
+	if devices[0].ExpiredAt(expires.Add(-time.Minute)) || !devices[0].ExpiredAt(expires) || devices[1].ExpiredAt(expires) {
+		t.Error("Unexpected ExpiredAt result")
+	}
+
+	// Browsers unsubscribe by endpoint, and only their own user's
+	if err := s.UnregisterPushDevice(bob.ID, "https://push.example.com/1"); !errors.Is(err, ErrPushDeviceNotFound) {
+		t.Errorf("Expected ErrPushDeviceNotFound, got %v", err)
+	}
+	if err := s.UnregisterPushDevice(alice.ID, "https://push.example.com/1"); err != nil {
+		t.Errorf("UnregisterPushDevice failed: %v", err)
+	}
+
+	if n, err := s.DeletePushDevicesByPlatform(PushPlatformWeb); err != nil || n != 1 {
+		t.Errorf("Expected 1 web device deleted, got %d (%v)", n, err)
+	}
+	if all, _ := s.GetPushDevices(); len(all) != 1 || all[0].ID != "pd-3" {
+		t.Errorf("Expected only the APNs device to remain, got %+v", all)
+	}
+}
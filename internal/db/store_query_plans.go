@@ -0,0 +1,122 @@
+package db
+
+import (
+	"strings"
+	"time"
+)
+
+// QueryPlan is the database's execution plan for one hot-path query.
+type QueryPlan struct {
+	Name  string   `json:"name"`
+	Query string   `json:"query"`
+	Plan  []string `json:"plan"`
+	// FullScan is set when the plan reads a whole table instead of going through an index.
+	// Postgres may legitimately choose this for very small tables.
+	FullScan bool `json:"fullScan"`
+}
+
+// hotQuery is a query run often enough that losing its index is a regression.
+type hotQuery struct {
+	name  string
+	query string
+	args  []any
+}
+
+// hotQueries mirrors the shape of the queries behind check history, outage tracking,
+// session auth and the manager's maintenance sync.
+func hotQueries() []hotQuery {
+	now := time.Now()
+	return []hotQuery{
+		{
+			name:  "monitor_check_history",
+			query: "SELECT monitor_id, status, latency, timestamp FROM monitor_checks WHERE monitor_id = ? ORDER BY timestamp DESC LIMIT ?",
+			args:  []any{"m-example", 100},
+		},
+		{
+			name:  "open_outage_for_monitor",
+			query: "SELECT id FROM monitor_outages WHERE monitor_id = ? AND end_time IS NULL",
+			args:  []any{"m-example"},
+		},
+		{
+			name:  "session_lookup",
+			query: "SELECT token, user_id, expires_at FROM sessions WHERE token = ? AND expires_at > ?",
+			args:  []any{"token", now},
+		},
+		{
+			name:  "expired_sessions",
+			query: "SELECT token FROM sessions WHERE expires_at <= ?",
+			args:  []any{now},
+		},
+		{
+			name:  "active_maintenance",
+			query: "SELECT id FROM incidents WHERE type = ? AND status NOT IN ('resolved', 'completed')",
+			args:  []any{"maintenance"},
+		},
+	}
+}
+
+// ExplainHotQueries returns the execution plan of each hot-path query.
+func (s *Store) ExplainHotQueries() ([]QueryPlan, error) {
+	queries := hotQueries()
+	plans := make([]QueryPlan, 0, len(queries))
+	for _, q := range queries {
+		lines, err := s.explain(q.query, q.args)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, QueryPlan{
+			Name:     q.name,
+			Query:    q.query,
+			Plan:     lines,
+			FullScan: s.isFullScan(lines),
+		})
+	}
+	return plans, nil
+}
+
+func (s *Store) explain(query string, args []any) ([]string, error) {
+	prefix := "EXPLAIN QUERY PLAN "
+	if s.IsPostgres() {
+		prefix = "EXPLAIN "
+	}
+	rows, err := s.db.Query(s.rebind(prefix+query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	lines := []string{}
+	for rows.Next() {
+		var line string
+		if s.IsPostgres() {
+			if err := rows.Scan(&line); err != nil {
+				return nil, err
+			}
+		} else {
+			// SQLite: id, parent, notused, detail
+			var id, parent, notUsed int
+			if err := rows.Scan(&id, &parent, &notUsed, &line); err != nil {
+				return nil, err
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// isFullScan reports whether any step of the plan reads a table without an index.
+func (s *Store) isFullScan(plan []string) bool {
+	for _, line := range plan {
+		if s.IsPostgres() {
+			if strings.Contains(line, "Seq Scan") {
+				return true
+			}
+			continue
+		}
+		// SQLite reports "SCAN t" for a table scan and "SCAN t USING [COVERING] INDEX i" otherwise
+		if strings.HasPrefix(line, "SCAN ") && !strings.Contains(line, " USING ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainHotQueries(t *testing.T) {
+	s := newTestStore(t)
+
+	plans, err := s.ExplainHotQueries()
+	if err != nil {
+		t.Fatalf("ExplainHotQueries: %v", err)
+	}
+	if len(plans) != len(hotQueries()) {
+		t.Fatalf("got %d plans, want %d", len(plans), len(hotQueries()))
+	}
+	for _, p := range plans {
+		if len(p.Plan) == 0 {
+			t.Errorf("%s: empty plan", p.Name)
+		}
+		// Every hot query has an index; a table scan here is a regression
+		if p.FullScan {
+			t.Errorf("%s: full table scan: %s", p.Name, strings.Join(p.Plan, " | "))
+		}
+	}
+}
+
+func TestIsFullScan(t *testing.T) {
+	s := newTestStore(t)
+	tests := []struct {
+		plan []string
+		want bool
+	}{
+		{[]string{"SEARCH monitor_checks USING INDEX idx_monitor_checks_monitor_id_ts (monitor_id=?)"}, false},
+		{[]string{"SCAN incidents USING INDEX idx_incidents_type_status"}, false},
+		{[]string{"SCAN sessions"}, true},
+	}
+	for _, tt := range tests {
+		if got := s.isFullScan(tt.plan); got != tt.want {
+			t.Errorf("isFullScan(%q) = %v, want %v", tt.plan, got, tt.want)
+		}
+	}
+}
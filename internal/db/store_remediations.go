@@ -0,0 +1,123 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrRemediationNotFound is returned when a monitor has no remediation action.
+var ErrRemediationNotFound = errors.New("remediation action not found")
+
+// MonitorRemediation is a webhook called when the monitor's outage opens, e.g. to trigger
+// a CI job or a restart automation. It runs once per outage, after AfterChecks failed
+// checks in a row (0 = as soon as the outage is confirmed), and not again within
+// CooldownMinutes of its last run.
+type MonitorRemediation struct {
+	MonitorID       string            `json:"monitorId"`
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"` // Empty sends a JSON description of the outage
+	AfterChecks     int               `json:"afterChecks"`
+	CooldownMinutes int               `json:"cooldownMinutes"`
+	Enabled         bool              `json:"enabled"`
+	LastRunAt       *time.Time        `json:"lastRunAt,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// RemediationRun is the result of a remediation action, kept with the outage it ran for.
+type RemediationRun struct {
+	StartedAt   time.Time `json:"startedAt"`
+	DurationMs  int64     `json:"durationMs"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Success     bool      `json:"success"`               // The webhook answered 2xx
+	BodySnippet string    `json:"bodySnippet,omitempty"` // Truncated response body
+	Error       string    `json:"error,omitempty"`       // Transport error when no response was received
+}
+
+const remediationColumns = "monitor_id, url, method, headers, body, after_checks, cooldown_minutes, enabled, last_run_at, created_at"
+
+func scanRemediation(row rowScanner) (MonitorRemediation, error) {
+	var r MonitorRemediation
+	var headers string
+	var lastRun sql.NullTime
+	if err := row.Scan(&r.MonitorID, &r.URL, &r.Method, &headers, &r.Body, &r.AfterChecks, &r.CooldownMinutes, &r.Enabled, &lastRun, &r.CreatedAt); err != nil {
+		return r, err
+	}
+	if lastRun.Valid {
+		r.LastRunAt = &lastRun.Time
+	}
+	if err := json.Unmarshal([]byte(headers), &r.Headers); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// SetMonitorRemediation creates or replaces a monitor's remediation action. The time of
+// its last run is kept, so replacing an action doesn't reset its cooldown.
+func (s *Store) SetMonitorRemediation(r MonitorRemediation) error {
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+	headers, err := json.Marshal(r.Headers)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.rebind(`
+		INSERT INTO monitor_remediations (monitor_id, url, method, headers, body, after_checks, cooldown_minutes, enabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (monitor_id) DO UPDATE SET url = excluded.url, method = excluded.method, headers = excluded.headers,
+			body = excluded.body, after_checks = excluded.after_checks, cooldown_minutes = excluded.cooldown_minutes, enabled = excluded.enabled`),
+		r.MonitorID, r.URL, r.Method, string(headers), r.Body, r.AfterChecks, r.CooldownMinutes, r.Enabled, time.Now().UTC())
+	return err
+}
+
+// GetMonitorRemediation returns the monitor's remediation action, or ErrRemediationNotFound.
+func (s *Store) GetMonitorRemediation(monitorID string) (*MonitorRemediation, error) {
+	r, err := scanRemediation(s.db.QueryRow(s.rebind("SELECT "+remediationColumns+" FROM monitor_remediations WHERE monitor_id = ?"), monitorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRemediationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetMonitorRemediations returns every remediation action, keyed by monitor ID.
+func (s *Store) GetMonitorRemediations() (map[string]MonitorRemediation, error) {
+	rows, err := s.db.Query("SELECT " + remediationColumns + " FROM monitor_remediations")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	remediations := make(map[string]MonitorRemediation)
+	for rows.Next() {
+		r, err := scanRemediation(rows)
+		if err != nil {
+			return nil, err
+		}
+		remediations[r.MonitorID] = r
+	}
+	return remediations, rows.Err()
+}
+
+// DeleteMonitorRemediation removes a monitor's remediation action. It reports whether
+// there was one.
+func (s *Store) DeleteMonitorRemediation(monitorID string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM monitor_remediations WHERE monitor_id = ?"), monitorID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// MarkRemediationRun stamps when the monitor's remediation action last ran.
+func (s *Store) MarkRemediationRun(monitorID string, at time.Time) error {
+	_, err := s.db.Exec(s.rebind("UPDATE monitor_remediations SET last_run_at = ? WHERE monitor_id = ?"), at.UTC(), monitorID)
+	return err
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"time"
+)
+
+// GetLatencyPercentileBetween returns the p-th percentile (0-100) of the latency in ms of
+// the monitor's checks in [since, until) that weren't down, by nearest rank. ok is false
+// when there are no such checks.
+func (s *Store) GetLatencyPercentileBetween(monitorID string, since, until time.Time, p float64) (ms int64, ok bool, err error) {
+	var latencies []int64
+	err = s.checks.ScanChecks(monitorID, since, func(c CheckResult) error {
+		if c.Status != "down" && c.Timestamp.Before(until) {
+			latencies = append(latencies, c.Latency)
+		}
+		return nil
+	})
+	if err != nil || len(latencies) == 0 {
+		return 0, false, err
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(latencies))))
+	rank = min(max(rank, 1), len(latencies))
+	return latencies[rank-1], true, nil
+}
+
+// CountOutagesBetween returns how many of the monitor's outages started in [since, until):
+// down is the number of outages, degraded the number of degraded periods.
+func (s *Store) CountOutagesBetween(monitorID string, since, until time.Time) (down, degraded int, err error) {
+	err = s.db.QueryRow(s.rebind(`
+		SELECT
+			COALESCE(SUM(CASE WHEN type = 'down' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN type = 'degraded' THEN 1 ELSE 0 END), 0)
+		FROM monitor_outages
+		WHERE monitor_id = ? AND start_time >= ? AND start_time < ?
+	`), monitorID, since.UTC(), until.UTC()).Scan(&down, &degraded)
+	return down, degraded, err
+}
+
+// HeatmapCell aggregates the checks of one weekday and hour.
+type HeatmapCell struct {
+	Checks          int
+	Failed          int
+	MedianLatencyMs *int64 // Of the checks that weren't down; nil when there are none
+}
+
+// GetLatencyHeatmap aggregates the monitor's checks in [since, until) by weekday and hour
+// of day in loc, indexed by time.Weekday, to show recurring patterns such as a nightly
+// backup slowing the service down.
+func (s *Store) GetLatencyHeatmap(monitorID string, since, until time.Time, loc *time.Location) ([7][24]HeatmapCell, error) {
+	var cells [7][24]HeatmapCell
+	var latencies [7][24][]int64
+	err := s.checks.ScanChecks(monitorID, since, func(c CheckResult) error {
+		if !c.Timestamp.Before(until) {
+			return nil
+		}
+		t := c.Timestamp.In(loc)
+		day, hour := t.Weekday(), t.Hour()
+		cells[day][hour].Checks++
+		if c.Status == "down" {
+			cells[day][hour].Failed++
+		} else {
+			latencies[day][hour] = append(latencies[day][hour], c.Latency)
+		}
+		return nil
+	})
+	if err != nil {
+		return cells, err
+	}
+	for day := range latencies {
+		for hour, l := range latencies[day] {
+			if len(l) == 0 {
+				continue
+			}
+			sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+			median := l[len(l)/2]
+			if len(l)%2 == 0 {
+				median = (l[len(l)/2-1] + l[len(l)/2]) / 2
+			}
+			cells[day][hour].MedianLatencyMs = &median
+		}
+	}
+	return cells, nil
+}
+
+// Reliability summarizes a monitor's down outages over a range.
+type Reliability struct {
+	Outages   int           // Outages that started in the range
+	Resolved  int           // Of those, the ones that ended
+	MTTR      time.Duration // Mean duration of the resolved ones; 0 when none
+	MTBF      time.Duration // Time up in the range so far per outage; 0 without outages
+	Longest   time.Duration // Longest of the outages, open ones counting until now
+	LongestID int64
+	Downtime  time.Duration // Outage time within the range, including outages that started before it
+}
+
+// GetReliabilityBetween computes the monitor's reliability over [since, until) from its
+// down outages; degraded periods don't count as failures. Time after now isn't counted.
+func (s *Store) GetReliabilityBetween(monitorID string, since, until, now time.Time) (Reliability, error) {
+	var rel Reliability
+	rows, err := s.db.Query(s.rebind(`SELECT id, start_time, end_time FROM monitor_outages
+		WHERE monitor_id = ? AND type = 'down' AND start_time < ? AND (end_time IS NULL OR end_time > ?)`),
+		monitorID, until.UTC(), since.UTC())
+	if err != nil {
+		return rel, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if now.Before(until) {
+		until = now
+	}
+	var repairs time.Duration
+	for rows.Next() {
+		var id int64
+		var start time.Time
+		var end sql.NullTime
+		if err := rows.Scan(&id, &start, &end); err != nil {
+			return rel, err
+		}
+		stop := now
+		if end.Valid {
+			stop = end.Time
+		}
+		if !start.Before(since) {
+			rel.Outages++
+			if end.Valid {
+				rel.Resolved++
+				repairs += stop.Sub(start)
+			}
+			if d := stop.Sub(start); d > rel.Longest {
+				rel.Longest, rel.LongestID = d, id
+			}
+		}
+		if start.Before(since) {
+			start = since
+		}
+		if stop.After(until) {
+			stop = until
+		}
+		if stop.After(start) {
+			rel.Downtime += stop.Sub(start)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return rel, err
+	}
+	if rel.Resolved > 0 {
+		rel.MTTR = repairs / time.Duration(rel.Resolved)
+	}
+	if rel.Outages > 0 && until.After(since) {
+		rel.MTBF = max(until.Sub(since)-rel.Downtime, 0) / time.Duration(rel.Outages)
+	}
+	return rel, nil
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyPercentileBetween(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	now := time.Now().Truncate(time.Second)
+	var checks []CheckResult
+	for i := 1; i <= 20; i++ {
+		checks = append(checks, CheckResult{MonitorID: "m1", Status: "up", Latency: int64(i * 10), Timestamp: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	checks = append(checks,
+		CheckResult{MonitorID: "m1", Status: "down", Latency: 30000, Timestamp: now.Add(-time.Minute)},
+		CheckResult{MonitorID: "m1", Status: "up", Latency: 9000, Timestamp: now.Add(-48 * time.Hour)},
+	)
+	if err := s.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	p95, ok, err := s.GetLatencyPercentileBetween("m1", now.Add(-24*time.Hour), now.Add(time.Minute), 95)
+	if err != nil || !ok {
+		t.Fatalf("GetLatencyPercentileBetween failed: %v %v", ok, err)
+	}
+	if p95 != 190 {
+		t.Errorf("Expected p95 of 190ms leaving out the failed and older checks, got %d", p95)
+	}
+
+	if _, ok, _ := s.GetLatencyPercentileBetween("m1", now.Add(-72*time.Hour), now.Add(-60*time.Hour), 95); ok {
+		t.Error("Expected no percentile without checks in the range")
+	}
+}
+
+func TestCountOutagesBetween(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	now := time.Now()
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", now.Add(-10*time.Hour), now.Add(-9*time.Hour))
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", now.Add(-5*time.Hour), now.Add(-4*time.Hour))
+	_, _ = s.CreateClosedOutage("m1", "degraded", "", "", now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", now.Add(-50*time.Hour), now.Add(-49*time.Hour))
+
+	down, degraded, err := s.CountOutagesBetween("m1", now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("CountOutagesBetween failed: %v", err)
+	}
+	if down != 2 || degraded != 1 {
+		t.Errorf("Expected 2 outages and 1 degraded period, got %d and %d", down, degraded)
+	}
+}
+
+func TestLatencyHeatmap(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	// 02:00 on the last two Tuesdays is slow, with a failure
+	now := time.Now().UTC()
+	tuesday := time.Date(now.Year(), now.Month(), now.Day(), 2, 0, 0, 0, time.UTC)
+	for tuesday.Weekday() != time.Tuesday || !tuesday.Add(3*time.Hour).Before(now) {
+		tuesday = tuesday.AddDate(0, 0, -1)
+	}
+	checks := []CheckResult{
+		{MonitorID: "m1", Status: "up", Latency: 900, Timestamp: tuesday.Add(5 * time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 700, Timestamp: tuesday.AddDate(0, 0, -7).Add(10 * time.Minute)},
+		{MonitorID: "m1", Status: "down", Latency: 30000, Timestamp: tuesday.Add(20 * time.Minute)},
+		{MonitorID: "m1", Status: "up", Latency: 100, Timestamp: tuesday.Add(2 * time.Hour)},
+		{MonitorID: "m1", Status: "up", Latency: 50, Timestamp: tuesday.AddDate(0, 0, -30)},
+	}
+	if err := s.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	cells, err := s.GetLatencyHeatmap("m1", now.AddDate(0, 0, -21), now.Add(time.Minute), time.UTC)
+	if err != nil {
+		t.Fatalf("GetLatencyHeatmap failed: %v", err)
+	}
+	slow := cells[time.Tuesday][2]
+	if slow.Checks != 3 || slow.Failed != 1 || slow.MedianLatencyMs == nil || *slow.MedianLatencyMs != 800 {
+		t.Errorf("Unexpected 02:00 cell %+v", slow)
+	}
+	if c := cells[time.Tuesday][4]; c.Checks != 1 || c.MedianLatencyMs == nil || *c.MedianLatencyMs != 100 {
+		t.Errorf("Unexpected 04:00 cell %+v", c)
+	}
+	if c := cells[time.Monday][2]; c.Checks != 0 || c.MedianLatencyMs != nil {
+		t.Errorf("Expected an empty cell, got %+v", c)
+	}
+
+	// Hours are taken in the requested timezone
+	tokyo := time.FixedZone("UTC+9", 9*3600)
+	cells, _ = s.GetLatencyHeatmap("m1", now.AddDate(0, 0, -21), now.Add(time.Minute), tokyo)
+	if c := cells[time.Tuesday][11]; c.Checks != 3 {
+		t.Errorf("Expected the checks at 11:00 in UTC+9, got %+v", c)
+	}
+}
+
+func TestReliabilityBetween(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "M1", Interval: 60})
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	// Started before the range: only its downtime counts
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", from.Add(-30*time.Minute), from.Add(30*time.Minute))
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", from.Add(4*24*time.Hour), from.Add(4*24*time.Hour+20*time.Minute))
+	_, _ = s.CreateClosedOutage("m1", "down", "", "", from.Add(9*24*time.Hour), from.Add(9*24*time.Hour+time.Hour))
+	_, _ = s.CreateClosedOutage("m1", "degraded", "", "", from.Add(11*24*time.Hour), from.Add(12*24*time.Hour))
+	_ = s.CreateOutageAt("m1", "down", "", "", nil, to.Add(-time.Hour))
+
+	now := to.Add(9 * 24 * time.Hour)
+	rel, err := s.GetReliabilityBetween("m1", from, to, now)
+	if err != nil {
+		t.Fatalf("GetReliabilityBetween failed: %v", err)
+	}
+	if rel.Outages != 3 || rel.Resolved != 2 {
+		t.Errorf("Expected 3 outages with 2 resolved, got %d and %d", rel.Outages, rel.Resolved)
+	}
+	if rel.MTTR != 40*time.Minute {
+		t.Errorf("Expected an MTTR of 40m, got %v", rel.MTTR)
+	}
+	if rel.Longest != now.Sub(to.Add(-time.Hour)) || rel.LongestID == 0 {
+		t.Errorf("Expected the open outage to be the longest, got %v (%d)", rel.Longest, rel.LongestID)
+	}
+	if rel.Downtime != 170*time.Minute {
+		t.Errorf("Expected 170m of downtime, got %v", rel.Downtime)
+	}
+	if want := (to.Sub(from) - 170*time.Minute) / 3; rel.MTBF != want {
+		t.Errorf("Expected an MTBF of %v, got %v", want, rel.MTBF)
+	}
+
+	// Time after now is not up time
+	now = to.Add(-30 * time.Minute)
+	rel, _ = s.GetReliabilityBetween("m1", from, to, now)
+	if want := (to.Sub(from) - 30*time.Minute - 140*time.Minute) / 3; rel.Downtime != 140*time.Minute || rel.MTBF != want {
+		t.Errorf("Expected 140m of downtime and an MTBF of %v, got %v and %v", want, rel.Downtime, rel.MTBF)
+	}
+
+	if rel, _ := s.GetReliabilityBetween("m1", to.AddDate(1, 0, 0), to.AddDate(1, 1, 0), now); rel.Outages != 0 || rel.MTBF != 0 {
+		t.Errorf("Expected nothing in an empty range, got %+v", rel)
+	}
+}
@@ -1,7 +1,11 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -17,14 +21,81 @@ func (s *Store) GetSetting(key string) (string, error) {
 }
 
 func (s *Store) SetSetting(key, value string) error {
-	var err error
+	if _, err := s.db.Exec(s.upsertSettingQuery(), key, value); err != nil {
+		return err
+	}
+	s.notifySettings(map[string]string{key: value})
+	return nil
+}
+
+// SetSettingIfAbsent stores value under key unless the key is already set, and returns
+// the stored value. Instances generating a setting at the same time all get the one
+// saved first.
+func (s *Store) SetSettingIfAbsent(key, value string) (string, error) {
+	res, err := s.db.Exec(s.rebind("INSERT INTO settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO NOTHING"), key, value)
+	if err != nil {
+		return "", err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		s.notifySettings(map[string]string{key: value})
+		return value, nil
+	}
+	return s.GetSetting(key)
+}
+
+// SetSettings saves several settings at once: either all of them or, on error, none.
+// Listeners hear about them in a single call.
+func (s *Store) SetSettings(values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := s.upsertSettingQuery()
+	for key, value := range values {
+		if _, err := tx.Exec(query, key, value); err != nil {
+			return fmt.Errorf("save %s: %w", key, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.notifySettings(values)
+	return nil
+}
+
+func (s *Store) upsertSettingQuery() string {
 	if s.IsPostgres() {
-		_, err = s.db.Exec("INSERT INTO settings (key, value) VALUES ($1, $2) ON CONFLICT(key) DO UPDATE SET value = excluded.value", key, value)
-	} else {
-		// SQLite: INSERT OR REPLACE
-		_, err = s.db.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value)
+		return "INSERT INTO settings (key, value) VALUES ($1, $2) ON CONFLICT(key) DO UPDATE SET value = excluded.value"
+	}
+	// SQLite: INSERT OR REPLACE
+	return "INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)"
+}
+
+// SettingsListener is called with the settings just saved, keyed by setting name.
+// It must not block for long: saving waits for every listener.
+type SettingsListener func(changed map[string]string)
+
+// OnSettingsChange registers fn to be called after settings are saved through this
+// store, so running subsystems can apply them immediately rather than on their next
+// periodic reload. Changes made by other instances sharing the database are not seen.
+func (s *Store) OnSettingsChange(fn SettingsListener) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.settingsListeners = append(s.settingsListeners, fn)
+}
+
+func (s *Store) notifySettings(changed map[string]string) {
+	s.settingsMu.RLock()
+	listeners := s.settingsListeners
+	s.settingsMu.RUnlock()
+	for _, fn := range listeners {
+		fn(changed)
 	}
-	return err
 }
 
 // Notification Channels
@@ -36,16 +107,22 @@ type NotificationChannel struct {
 	Config    string    `json:"config"` // JSON string
 	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"createdAt"`
+	// DisabledReason is set when the notifier turned the channel off after sustained failures
+	DisabledReason string `json:"disabledReason,omitempty"`
+	// MonitorID is set for watchers: channels that only receive that monitor's notifications
+	MonitorID string `json:"monitorId,omitempty"`
 }
 
+const notificationChannelColumns = "id, type, name, config, enabled, created_at, COALESCE(disabled_reason, ''), COALESCE(monitor_id, '')"
+
 func (s *Store) CreateNotificationChannel(c NotificationChannel) error {
-	_, err := s.db.Exec(s.rebind("INSERT INTO notification_channels (id, type, name, config, enabled, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
-		c.ID, c.Type, c.Name, c.Config, c.Enabled, time.Now())
+	_, err := s.db.Exec(s.rebind("INSERT INTO notification_channels (id, type, name, config, enabled, created_at, monitor_id) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		c.ID, c.Type, c.Name, c.Config, c.Enabled, time.Now(), sql.NullString{String: c.MonitorID, Valid: c.MonitorID != ""})
 	return err
 }
 
 func (s *Store) GetNotificationChannels() ([]NotificationChannel, error) {
-	rows, err := s.db.Query("SELECT id, type, name, config, enabled, created_at FROM notification_channels ORDER BY created_at DESC")
+	rows, err := s.db.Query("SELECT " + notificationChannelColumns + " FROM notification_channels ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +131,7 @@ func (s *Store) GetNotificationChannels() ([]NotificationChannel, error) {
 	var channels []NotificationChannel
 	for rows.Next() {
 		var c NotificationChannel
-		if err := rows.Scan(&c.ID, &c.Type, &c.Name, &c.Config, &c.Enabled, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Type, &c.Name, &c.Config, &c.Enabled, &c.CreatedAt, &c.DisabledReason, &c.MonitorID); err != nil {
 			return nil, err
 		}
 		channels = append(channels, c)
@@ -63,9 +140,23 @@ func (s *Store) GetNotificationChannels() ([]NotificationChannel, error) {
 }
 
 func (s *Store) UpdateNotificationChannel(id, name, channelType, config string, enabled bool) error {
-	_, err := s.db.Exec(s.rebind("UPDATE notification_channels SET name = ?, type = ?, config = ?, enabled = ? WHERE id = ?"),
+	_, err := s.db.Exec(s.rebind("UPDATE notification_channels SET name = ?, type = ?, config = ?, enabled = ?, disabled_reason = NULL WHERE id = ?"),
 		name, channelType, config, enabled, id)
-	return err
+	if err != nil || enabled {
+		return err
+	}
+	return s.dropQueuedNotifications(id)
+}
+
+// DisableNotificationChannel turns a channel off and records why. The notifier uses it
+// for channels that kept failing; editing the channel clears the reason.
+func (s *Store) DisableNotificationChannel(id, reason string) error {
+	_, err := s.db.Exec(s.rebind("UPDATE notification_channels SET enabled = ?, disabled_reason = ? WHERE id = ?"),
+		false, reason, id)
+	if err != nil {
+		return err
+	}
+	return s.dropQueuedNotifications(id)
 }
 
 func (s *Store) DeleteNotificationChannel(id string) error {
@@ -93,15 +184,87 @@ type SystemEvent struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
-// GetSystemEvents returns all events for all monitors
-func (s *Store) GetSystemEvents(limit int) ([]SystemEvent, error) {
+// ErrEventNotFound is returned when a SystemEventFilter cursor names no event.
+var ErrEventNotFound = errors.New("event not found")
+
+// Page sizes of GetSystemEvents
+const (
+	DefaultSystemEventsLimit = 100
+	MaxSystemEventsLimit     = 1000
+)
+
+// SystemEventFilter selects monitor events for GetSystemEvents. Zero values match everything.
+type SystemEventFilter struct {
+	MonitorID string
+	Types     []string  // any of these event types
+	Since     time.Time // at or after
+	Until     time.Time // before
+	// BeforeID continues a listing after the event with this ID, the last one of the
+	// previous page.
+	BeforeID int64
+	Limit    int // DefaultSystemEventsLimit when <= 0, at most MaxSystemEventsLimit
+}
+
+// GetSystemEvents returns events of all monitors matching f, newest first. Events are
+// ordered by (timestamp, id), so paging with BeforeID neither skips nor repeats events
+// while new ones are recorded.
+func (s *Store) GetSystemEvents(f SystemEventFilter) ([]SystemEvent, error) {
+	var where []string
+	var args []any
+	if f.MonitorID != "" {
+		where = append(where, "e.monitor_id = ?")
+		args = append(args, f.MonitorID)
+	}
+	if len(f.Types) > 0 {
+		where = append(where, "e.type IN (?"+strings.Repeat(", ?", len(f.Types)-1)+")")
+		for _, t := range f.Types {
+			args = append(args, t)
+		}
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "e.timestamp >= ?")
+		args = append(args, f.Since.UTC())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "e.timestamp < ?")
+		args = append(args, f.Until.UTC())
+	}
+	if f.BeforeID > 0 {
+		var id int64
+		err := s.db.QueryRow(s.rebind("SELECT id FROM monitor_events WHERE id = ?"), f.BeforeID).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Compare with the stored timestamp itself rather than a round-tripped copy,
+		// which SQLite may hold in a different text format
+		cursor := "(SELECT timestamp FROM monitor_events WHERE id = ?)"
+		where = append(where, "(e.timestamp < "+cursor+" OR (e.timestamp = "+cursor+" AND e.id < ?))")
+		args = append(args, f.BeforeID, f.BeforeID, f.BeforeID)
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultSystemEventsLimit
+	}
+	if limit > MaxSystemEventsLimit {
+		limit = MaxSystemEventsLimit
+	}
+	args = append(args, limit)
+
 	query := `
 		SELECT e.id, e.monitor_id, m.name, e.type, e.message, e.timestamp
 		FROM monitor_events e
-		JOIN monitors m ON e.monitor_id = m.id
-		ORDER BY e.timestamp ASC
-	`
-	rows, err := s.db.Query(query)
+		JOIN monitors m ON e.monitor_id = m.id`
+	if len(where) > 0 {
+		query += `
+		WHERE ` + strings.Join(where, " AND ")
+	}
+	query += `
+		ORDER BY e.timestamp DESC, e.id DESC
+		LIMIT ?`
+	rows, err := s.db.Query(s.rebind(query), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +278,7 @@ func (s *Store) GetSystemEvents(limit int) ([]SystemEvent, error) {
 		}
 		events = append(events, e)
 	}
-	return events, nil
+	return events, rows.Err()
 }
 
 func (s *Store) GetSystemStats() (*SystemStats, error) {
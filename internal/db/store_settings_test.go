@@ -1,7 +1,10 @@
 package db
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSettingsResult(t *testing.T) {
@@ -31,6 +34,50 @@ func TestSettingsResult(t *testing.T) {
 	}
 }
 
+func TestSetSettingIfAbsent(t *testing.T) {
+	s := newTestStore(t)
+
+	// The first instance to save wins; the others get its value back
+	got, err := s.SetSettingIfAbsent("secret", "first")
+	if err != nil || got != "first" {
+		t.Fatalf("Expected 'first' to be stored, got (%q, %v)", got, err)
+	}
+	if got, err := s.SetSettingIfAbsent("secret", "second"); err != nil || got != "first" {
+		t.Errorf("Expected the stored 'first' back, got (%q, %v)", got, err)
+	}
+	if val, _ := s.GetSetting("secret"); val != "first" {
+		t.Errorf("Expected 'first' to be kept, got %q", val)
+	}
+}
+
+func TestSettingsChangeListeners(t *testing.T) {
+	s := newTestStore(t)
+
+	var calls []map[string]string
+	s.OnSettingsChange(func(changed map[string]string) {
+		calls = append(calls, changed)
+	})
+
+	if err := s.SetSetting("foo", "bar"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	if err := s.SetSettings(map[string]string{"a": "1", "b": "2"}); err != nil {
+		t.Fatalf("SetSettings failed: %v", err)
+	}
+	if len(calls) != 2 || calls[0]["foo"] != "bar" || len(calls[1]) != 2 || calls[1]["b"] != "2" {
+		t.Fatalf("Expected one call per save with the saved values, got %v", calls)
+	}
+	if val, _ := s.GetSetting("a"); val != "1" {
+		t.Errorf("Expected 'a' to be saved, got %q", val)
+	}
+
+	// Nothing saved, nobody told
+	_ = s.SetSettings(nil)
+	if len(calls) != 2 {
+		t.Errorf("Expected no call for an empty save, got %v", calls[2:])
+	}
+}
+
 func TestSystemStats(t *testing.T) {
 	s := newTestStore(t)
 	stats, err := s.GetSystemStats()
@@ -45,3 +92,103 @@ func TestSystemStats(t *testing.T) {
 		t.Logf("Total monitors: %d", stats.TotalMonitors)
 	}
 }
+
+func TestGetSystemEvents(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", Interval: 60})
+	_ = s.CreateMonitor(Monitor{ID: "m2", GroupID: "g1", Name: "Web", Interval: 60})
+
+	base := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		monitorID, eventType := "m1", "down"
+		if i%2 == 1 {
+			monitorID, eventType = "m2", "up"
+		}
+		// Two events per timestamp, so paging must break ties by ID
+		if err := s.CreateEventAt(monitorID, eventType, fmt.Sprintf("event %d", i), base.Add(time.Duration(i/2)*time.Minute)); err != nil {
+			t.Fatalf("CreateEventAt failed: %v", err)
+		}
+	}
+
+	messages := func(events []SystemEvent) []string {
+		var out []string
+		for _, e := range events {
+			out = append(out, e.Message)
+		}
+		return out
+	}
+
+	all, err := s.GetSystemEvents(SystemEventFilter{})
+	if err != nil {
+		t.Fatalf("GetSystemEvents failed: %v", err)
+	}
+	if got := strings.Join(messages(all), ","); got != "event 5,event 4,event 3,event 2,event 1,event 0" {
+		t.Errorf("Expected events newest first, got %s", got)
+	}
+	if all[0].MonitorName != "Web" {
+		t.Errorf("Expected the monitor name, got %q", all[0].MonitorName)
+	}
+
+	// Pages of two cover every event once
+	var paged []string
+	f := SystemEventFilter{Limit: 2}
+	for {
+		page, err := s.GetSystemEvents(f)
+		if err != nil {
+			t.Fatalf("GetSystemEvents failed: %v", err)
+		}
+		paged = append(paged, messages(page)...)
+		if len(page) < f.Limit {
+			break
+		}
+		f.BeforeID = page[len(page)-1].ID
+	}
+	if got := strings.Join(paged, ","); got != "event 5,event 4,event 3,event 2,event 1,event 0" {
+		t.Errorf("Expected pages to cover every event once, got %s", got)
+	}
+
+	filtered, err := s.GetSystemEvents(SystemEventFilter{MonitorID: "m1", Types: []string{"down"}, Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatalf("GetSystemEvents failed: %v", err)
+	}
+	if got := strings.Join(messages(filtered), ","); got != "event 2" {
+		t.Errorf("Expected only event 2, got %s", got)
+	}
+
+	if _, err := s.GetSystemEvents(SystemEventFilter{BeforeID: 9999}); err != ErrEventNotFound {
+		t.Errorf("Expected ErrEventNotFound for an unknown cursor, got %v", err)
+	}
+}
+
+func TestGetSystemEvents_PagesDefaultTimestamps(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateGroup(Group{ID: "g1", Name: "G1"})
+	_ = s.CreateMonitor(Monitor{ID: "m1", GroupID: "g1", Name: "API", Interval: 60})
+	// Events stamped by the database default, mostly within the same second
+	for i := 0; i < 4; i++ {
+		if err := s.CreateEvent("m1", "down", fmt.Sprintf("event %d", i)); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	f := SystemEventFilter{Limit: 1}
+	for i := 0; i < 5; i++ {
+		page, err := s.GetSystemEvents(f)
+		if err != nil {
+			t.Fatalf("GetSystemEvents failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		if seen[page[0].ID] {
+			t.Fatalf("Event %d returned twice", page[0].ID)
+		}
+		seen[page[0].ID] = true
+		f.BeforeID = page[0].ID
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected to page through 4 events, got %d", len(seen))
+	}
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrSilenceNotFound is returned when no silence has the given ID.
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// Silence scopes
+const (
+	SilenceScopeMonitor = "monitor"
+	SilenceScopeGroup   = "group"
+	SilenceScopeTag     = "tag"
+)
+
+// Silence mutes the notifications of the monitors in its scope between StartsAt and
+// EndsAt. Unlike a maintenance window it leaves their status alone.
+type Silence struct {
+	ID        string    `json:"id"`
+	Scope     string    `json:"scope"`  // monitor | group | tag
+	Target    string    `json:"target"` // Monitor ID, group ID or tag
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"createdBy"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ActiveAt reports whether the silence mutes notifications at t.
+func (s Silence) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Matches reports whether the monitor is in the silence's scope.
+func (s Silence) Matches(m Monitor) bool {
+	switch s.Scope {
+	case SilenceScopeMonitor:
+		return m.ID == s.Target
+	case SilenceScopeGroup:
+		return m.GroupID == s.Target
+	case SilenceScopeTag:
+		return m.HasTag(s.Target)
+	}
+	return false
+}
+
+const silenceColumns = "id, scope, target, reason, created_by, starts_at, ends_at, created_at"
+
+func scanSilence(row rowScanner) (Silence, error) {
+	var s Silence
+	err := row.Scan(&s.ID, &s.Scope, &s.Target, &s.Reason, &s.CreatedBy, &s.StartsAt, &s.EndsAt, &s.CreatedAt)
+	return s, err
+}
+
+// CreateSilence stores a new silence.
+func (s *Store) CreateSilence(sil Silence) error {
+	if sil.CreatedAt.IsZero() {
+		sil.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(s.rebind("INSERT INTO silences ("+silenceColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)"),
+		sil.ID, sil.Scope, sil.Target, sil.Reason, sil.CreatedBy, sil.StartsAt.UTC(), sil.EndsAt.UTC(), sil.CreatedAt.UTC())
+	return err
+}
+
+// GetSilence returns a silence, or ErrSilenceNotFound.
+func (s *Store) GetSilence(id string) (*Silence, error) {
+	sil, err := scanSilence(s.db.QueryRow(s.rebind("SELECT "+silenceColumns+" FROM silences WHERE id = ?"), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sil, nil
+}
+
+// GetSilences returns the silences that haven't ended by now, or all of them with
+// expired, latest start first.
+func (s *Store) GetSilences(expired bool, now time.Time) ([]Silence, error) {
+	query := "SELECT " + silenceColumns + " FROM silences"
+	var args []any
+	if !expired {
+		query += " WHERE ends_at > ?"
+		args = append(args, now.UTC())
+	}
+	rows, err := s.db.Query(s.rebind(query+" ORDER BY starts_at DESC, id"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	silences := []Silence{}
+	for rows.Next() {
+		sil, err := scanSilence(rows)
+		if err != nil {
+			return nil, err
+		}
+		silences = append(silences, sil)
+	}
+	return silences, rows.Err()
+}
+
+// ExpireSilence ends a silence at the given time, keeping it for the record; one that
+// hasn't started yet is shortened to nothing. Expiring an expired silence does nothing.
+// It returns ErrSilenceNotFound for an unknown ID.
+func (s *Store) ExpireSilence(id string, at time.Time) error {
+	res, err := s.db.Exec(s.rebind(`UPDATE silences SET ends_at = ?, starts_at = CASE WHEN starts_at > ? THEN ? ELSE starts_at END
+		WHERE id = ? AND ends_at > ?`), at.UTC(), at.UTC(), at.UTC(), id, at.UTC())
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	if _, err := s.GetSilence(id); err != nil {
+		return err
+	}
+	return nil // Already expired
+}
+
+// PruneExpiredSilences deletes silences that ended before the cutoff.
+func (s *Store) PruneExpiredSilences(before time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM silences WHERE ends_at < ?"), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSilences(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, sil := range []Silence{
+		{ID: "sil-past", Scope: SilenceScopeMonitor, Target: "m-1", Reason: "old", StartsAt: now.Add(-3 * time.Hour), EndsAt: now.Add(-2 * time.Hour)},
+		{ID: "sil-now", Scope: SilenceScopeGroup, Target: "g-1", Reason: "deploy", CreatedBy: "alice", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: "sil-next", Scope: SilenceScopeTag, Target: "db", Reason: "migration", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+	} {
+		if err := store.CreateSilence(sil); err != nil {
+			t.Fatalf("CreateSilence failed: %v", err)
+		}
+	}
+
+	ids := func(silences []Silence) []string {
+		var out []string
+		for _, sil := range silences {
+			out = append(out, sil.ID)
+		}
+		return out
+	}
+	if got, _ := store.GetSilences(false, now); len(got) != 2 || got[0].ID != "sil-next" || got[1].ID != "sil-now" {
+		t.Errorf("Expected the current and upcoming silences, latest start first, got %v", ids(got))
+	}
+	if got, _ := store.GetSilences(true, now); len(got) != 3 {
+		t.Errorf("Expected all silences with expired, got %v", ids(got))
+	}
+
+	sil, err := store.GetSilence("sil-now")
+	if err != nil || sil.CreatedBy != "alice" || !sil.EndsAt.Equal(now.Add(time.Hour)) || !sil.ActiveAt(now) {
+		t.Errorf("Unexpected silence %+v (%v)", sil, err)
+	}
+	if _, err := store.GetSilence("sil-missing"); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("Expected ErrSilenceNotFound, got %v", err)
+	}
+
+	// Expiring ends an active silence now and an upcoming one before it starts
+	if err := store.ExpireSilence("sil-now", now); err != nil {
+		t.Fatalf("ExpireSilence failed: %v", err)
+	}
+	_ = store.ExpireSilence("sil-next", now)
+	if got, _ := store.GetSilences(false, now); len(got) != 0 {
+		t.Errorf("Expected no silences left, got %v", ids(got))
+	}
+	if sil, _ := store.GetSilence("sil-next"); !sil.StartsAt.Equal(now) || !sil.EndsAt.Equal(now) {
+		t.Errorf("Expected the upcoming silence to be shortened to nothing, got %+v", sil)
+	}
+	if err := store.ExpireSilence("sil-past", now); err != nil {
+		t.Errorf("Expected expiring an expired silence to do nothing, got %v", err)
+	}
+	if err := store.ExpireSilence("sil-missing", now); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("Expected ErrSilenceNotFound, got %v", err)
+	}
+
+	if n, _ := store.PruneExpiredSilences(now.Add(-time.Minute)); n != 1 {
+		t.Errorf("Expected only the long expired silence to be pruned, got %d", n)
+	}
+}
+
+func TestSilenceMatches(t *testing.T) {
+	mon := Monitor{ID: "m-1", GroupID: "g-1", Tags: []string{"prod"}}
+	tests := []struct {
+		scope, target string
+		want          bool
+	}{
+		{SilenceScopeMonitor, "m-1", true},
+		{SilenceScopeMonitor, "m-2", false},
+		{SilenceScopeGroup, "g-1", true},
+		{SilenceScopeGroup, "g-2", false},
+		{SilenceScopeTag, "prod", true},
+		{SilenceScopeTag, "staging", false},
+		{"team", "g-1", false},
+	}
+	for _, tt := range tests {
+		if got := (Silence{Scope: tt.scope, Target: tt.target}).Matches(mon); got != tt.want {
+			t.Errorf("%s %s: expected %v, got %v", tt.scope, tt.target, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Status override values, matching the statuses shown on public status pages.
+const (
+	OverrideStatusUp       = "up"
+	OverrideStatusDegraded = "degraded"
+	OverrideStatusDown     = "down"
+)
+
+// StatusOverride replaces a monitor's live status on public status pages until it
+// expires or is cleared. Cleared and expired rows are kept as an audit trail.
+type StatusOverride struct {
+	ID        int64      `json:"id"`
+	MonitorID string     `json:"monitorId"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message"`
+	CreatedBy int64      `json:"createdBy"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // nil = until cleared
+	ClearedAt *time.Time `json:"clearedAt,omitempty"`
+	ClearedBy *int64     `json:"clearedBy,omitempty"`
+}
+
+// Active reports whether the override is in effect at the given time.
+func (o StatusOverride) Active(now time.Time) bool {
+	return o.ClearedAt == nil && (o.ExpiresAt == nil || o.ExpiresAt.After(now))
+}
+
+const statusOverrideColumns = "id, monitor_id, status, message, created_by, created_at, expires_at, cleared_at, cleared_by"
+
+func scanStatusOverride(scanner rowScanner) (StatusOverride, error) {
+	var o StatusOverride
+	var expiresAt, clearedAt sql.NullTime
+	var clearedBy sql.NullInt64
+	if err := scanner.Scan(&o.ID, &o.MonitorID, &o.Status, &o.Message, &o.CreatedBy, &o.CreatedAt,
+		&expiresAt, &clearedAt, &clearedBy); err != nil {
+		return o, err
+	}
+	if expiresAt.Valid {
+		o.ExpiresAt = &expiresAt.Time
+	}
+	if clearedAt.Valid {
+		o.ClearedAt = &clearedAt.Time
+	}
+	if clearedBy.Valid {
+		o.ClearedBy = &clearedBy.Int64
+	}
+	return o, nil
+}
+
+// SetStatusOverride clears any override in effect for the monitor and records a new
+// one, returning its ID.
+func (s *Store) SetStatusOverride(o StatusOverride) (int64, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind(`
+		UPDATE status_overrides SET cleared_at = ?, cleared_by = ?
+		WHERE monitor_id = ? AND cleared_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+	`), now, o.CreatedBy, o.MonitorID, now); err != nil {
+		return 0, err
+	}
+
+	var expiresAt any
+	if o.ExpiresAt != nil {
+		expiresAt = o.ExpiresAt.UTC()
+	}
+
+	const insert = "INSERT INTO status_overrides (monitor_id, status, message, created_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)"
+	var id int64
+	if s.IsPostgres() {
+		if err := tx.QueryRow(s.rebind(insert+" RETURNING id"),
+			o.MonitorID, o.Status, o.Message, o.CreatedBy, now, expiresAt).Scan(&id); err != nil {
+			return 0, err
+		}
+	} else {
+		res, err := tx.Exec(insert, o.MonitorID, o.Status, o.Message, o.CreatedBy, now, expiresAt)
+		if err != nil {
+			return 0, err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// ClearStatusOverride ends the monitor's override, recording who cleared it.
+// Returns false if no override was in effect.
+func (s *Store) ClearStatusOverride(monitorID string, clearedBy int64) (bool, error) {
+	now := time.Now().UTC()
+	res, err := s.db.Exec(s.rebind(`
+		UPDATE status_overrides SET cleared_at = ?, cleared_by = ?
+		WHERE monitor_id = ? AND cleared_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+	`), now, clearedBy, monitorID, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetActiveStatusOverrides returns the overrides in effect at the given time, keyed by monitor ID.
+func (s *Store) GetActiveStatusOverrides(now time.Time) (map[string]StatusOverride, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT `+statusOverrideColumns+`
+		FROM status_overrides
+		WHERE cleared_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at ASC, id ASC
+	`), now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	overrides := make(map[string]StatusOverride)
+	for rows.Next() {
+		o, err := scanStatusOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides[o.MonitorID] = o // later rows win
+	}
+	return overrides, rows.Err()
+}
+
+// GetStatusOverrides returns a monitor's override history, newest first.
+func (s *Store) GetStatusOverrides(monitorID string) ([]StatusOverride, error) {
+	rows, err := s.db.Query(s.rebind(`
+		SELECT `+statusOverrideColumns+`
+		FROM status_overrides
+		WHERE monitor_id = ?
+		ORDER BY created_at DESC, id DESC
+	`), monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	overrides := []StatusOverride{}
+	for rows.Next() {
+		o, err := scanStatusOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
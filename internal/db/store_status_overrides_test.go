@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusOverrides(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateGroup(Group{ID: "g1", Name: "G1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"m1", "m2"} {
+		if err := s.CreateMonitor(Monitor{ID: id, GroupID: "g1", Name: id, URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := s.SetStatusOverride(StatusOverride{MonitorID: "m1", Status: OverrideStatusDegraded, Message: "slow", CreatedBy: 1}); err != nil {
+		t.Fatalf("SetStatusOverride: %v", err)
+	}
+	// Replacing the override clears the previous one
+	if _, err := s.SetStatusOverride(StatusOverride{MonitorID: "m1", Status: OverrideStatusDown, CreatedBy: 2}); err != nil {
+		t.Fatalf("SetStatusOverride: %v", err)
+	}
+	// An already expired override is never active
+	past := time.Now().Add(-time.Minute)
+	if _, err := s.SetStatusOverride(StatusOverride{MonitorID: "m2", Status: OverrideStatusDown, CreatedBy: 1, ExpiresAt: &past}); err != nil {
+		t.Fatalf("SetStatusOverride: %v", err)
+	}
+
+	active, err := s.GetActiveStatusOverrides(time.Now())
+	if err != nil {
+		t.Fatalf("GetActiveStatusOverrides: %v", err)
+	}
+	if len(active) != 1 || active["m1"].Status != OverrideStatusDown {
+		t.Fatalf("expected only m1=down active, got %+v", active)
+	}
+
+	history, err := s.GetStatusOverrides("m1")
+	if err != nil {
+		t.Fatalf("GetStatusOverrides: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history rows, got %d", len(history))
+	}
+	replaced := history[1]
+	if replaced.ClearedAt == nil || replaced.ClearedBy == nil || *replaced.ClearedBy != 2 {
+		t.Errorf("expected replaced override to be cleared by user 2, got %+v", replaced)
+	}
+
+	cleared, err := s.ClearStatusOverride("m1", 3)
+	if err != nil || !cleared {
+		t.Fatalf("ClearStatusOverride: cleared=%v err=%v", cleared, err)
+	}
+	if cleared, _ := s.ClearStatusOverride("m1", 3); cleared {
+		t.Error("expected nothing left to clear")
+	}
+	if cleared, _ := s.ClearStatusOverride("m2", 3); cleared {
+		t.Error("expired override should not be cleared")
+	}
+	if active, _ := s.GetActiveStatusOverrides(time.Now()); len(active) != 0 {
+		t.Errorf("expected no active overrides, got %+v", active)
+	}
+}
@@ -0,0 +1,60 @@
+package db
+
+// GetStatusPageChannels returns the IDs of the notification channels told about the
+// incidents of a status page.
+func (s *Store) GetStatusPageChannels(slug string) ([]string, error) {
+	rows, err := s.db.Query(s.rebind("SELECT channel_id FROM status_page_channels WHERE slug = ? ORDER BY channel_id"), slug)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetAllStatusPageChannels returns the channel IDs of every status page that has any,
+// by slug.
+func (s *Store) GetAllStatusPageChannels() (map[string][]string, error) {
+	rows, err := s.db.Query("SELECT slug, channel_id FROM status_page_channels ORDER BY slug, channel_id")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	channels := make(map[string][]string)
+	for rows.Next() {
+		var slug, id string
+		if err := rows.Scan(&slug, &id); err != nil {
+			return nil, err
+		}
+		channels[slug] = append(channels[slug], id)
+	}
+	return channels, rows.Err()
+}
+
+// SetStatusPageChannels replaces the channels told about a status page's incidents.
+func (s *Store) SetStatusPageChannels(slug string, channelIDs []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind("DELETE FROM status_page_channels WHERE slug = ?"), slug); err != nil {
+		return err
+	}
+	for _, id := range channelIDs {
+		if _, err := tx.Exec(s.rebind("INSERT INTO status_page_channels (slug, channel_id) VALUES (?, ?) ON CONFLICT DO NOTHING"), slug, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStatusPageChannels(t *testing.T) {
+	store := newTestStore(t)
+	for _, slug := range []string{"eu", "us"} {
+		if err := store.CreateStatusPage(StatusPageInput{Slug: slug, Title: slug, Enabled: true}); err != nil {
+			t.Fatalf("Failed to create status page: %v", err)
+		}
+	}
+	for _, id := range []string{"nc-1", "nc-2"} {
+		if err := store.CreateNotificationChannel(NotificationChannel{ID: id, Type: "webhook", Name: id, Config: "{}", Enabled: true}); err != nil {
+			t.Fatalf("CreateNotificationChannel failed: %v", err)
+		}
+	}
+
+	if err := store.SetStatusPageChannels("eu", []string{"nc-2", "nc-1", "nc-1"}); err != nil {
+		t.Fatalf("SetStatusPageChannels failed: %v", err)
+	}
+	_ = store.SetStatusPageChannels("us", []string{"nc-2"})
+	if ids, _ := store.GetStatusPageChannels("eu"); !slices.Equal(ids, []string{"nc-1", "nc-2"}) {
+		t.Errorf("Expected eu to have nc-1 and nc-2, got %v", ids)
+	}
+
+	// Replacing the list drops the channels left out
+	_ = store.SetStatusPageChannels("eu", []string{"nc-1"})
+	all, err := store.GetAllStatusPageChannels()
+	if err != nil {
+		t.Fatalf("GetAllStatusPageChannels failed: %v", err)
+	}
+	if !slices.Equal(all["eu"], []string{"nc-1"}) || !slices.Equal(all["us"], []string{"nc-2"}) {
+		t.Errorf("Unexpected mapping %v", all)
+	}
+
+	// Deleting a channel or a page removes it from the mapping
+	if err := store.DeleteNotificationChannel("nc-2"); err != nil {
+		t.Fatalf("DeleteNotificationChannel failed: %v", err)
+	}
+	if _, err := store.DeleteStatusPage("eu"); err != nil {
+		t.Fatalf("DeleteStatusPage failed: %v", err)
+	}
+	if all, _ := store.GetAllStatusPageChannels(); len(all) != 0 {
+		t.Errorf("Expected an empty mapping, got %v", all)
+	}
+}
+
+func TestStatusPageChannels_KeptWhenPageUpdated(t *testing.T) {
+	store := newTestStore(t)
+	page := StatusPageInput{Slug: "eu", Title: "EU", Enabled: true}
+	if err := store.CreateStatusPage(page); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	if err := store.CreateNotificationChannel(NotificationChannel{ID: "nc-1", Type: "webhook", Name: "nc-1", Config: "{}", Enabled: true}); err != nil {
+		t.Fatalf("CreateNotificationChannel failed: %v", err)
+	}
+	_ = store.SetStatusPageChannels("eu", []string{"nc-1"})
+
+	page.Public = true
+	if err := store.UpsertStatusPageFull(page); err != nil {
+		t.Fatalf("UpsertStatusPageFull failed: %v", err)
+	}
+	if ids, _ := store.GetStatusPageChannels("eu"); !slices.Equal(ids, []string{"nc-1"}) {
+		t.Errorf("Expected the channel to survive the update, got %v", ids)
+	}
+}
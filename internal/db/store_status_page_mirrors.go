@@ -0,0 +1,165 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrStatusPageMirrorNotFound is returned when a status page is not mirrored.
+var ErrStatusPageMirrorNotFound = errors.New("status page mirror not found")
+
+// StatusPageMirror copies a status page's component statuses and public incidents to a
+// hosted Statuspage.io or Instatus page, for teams that keep their existing page.
+type StatusPageMirror struct {
+	Slug       string            `json:"slug"`
+	Provider   string            `json:"provider"` // statuspage | instatus
+	PageID     string            `json:"pageId"`   // ID of the hosted page
+	APIKey     string            `json:"-"`
+	Components map[string]string `json:"components"` // Warden monitor or group ID -> hosted component ID
+	Enabled    bool              `json:"enabled"`
+	LastSyncAt *time.Time        `json:"lastSyncAt,omitempty"`
+	LastError  string            `json:"lastError,omitempty"` // Why the last push failed, empty once one succeeds
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+// MirroredIncident links an incident to the hosted incident it was copied to, with the
+// status and the latest incident update pushed so far.
+type MirroredIncident struct {
+	IncidentID   string
+	ExternalID   string
+	Status       string
+	LastUpdateID int64
+}
+
+const statusPageMirrorColumns = "slug, provider, page_id, api_key, components, enabled, last_sync_at, last_error, created_at"
+
+func scanStatusPageMirror(row rowScanner) (StatusPageMirror, error) {
+	var m StatusPageMirror
+	var components string
+	var lastSync sql.NullTime
+	if err := row.Scan(&m.Slug, &m.Provider, &m.PageID, &m.APIKey, &components, &m.Enabled, &lastSync, &m.LastError, &m.CreatedAt); err != nil {
+		return m, err
+	}
+	if lastSync.Valid {
+		m.LastSyncAt = &lastSync.Time
+	}
+	if err := json.Unmarshal([]byte(components), &m.Components); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// SetStatusPageMirror creates or replaces a status page's mirror. Moving it to another
+// provider or hosted page forgets which incidents were copied, so they are copied anew.
+func (s *Store) SetStatusPageMirror(m StatusPageMirror) error {
+	if m.Components == nil {
+		m.Components = map[string]string{}
+	}
+	components, err := json.Marshal(m.Components)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(s.rebind(`
+		DELETE FROM status_page_mirror_incidents WHERE slug = ? AND NOT EXISTS (
+			SELECT 1 FROM status_page_mirrors WHERE slug = ? AND provider = ? AND page_id = ?)`),
+		m.Slug, m.Slug, m.Provider, m.PageID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(s.rebind(`
+		INSERT INTO status_page_mirrors (slug, provider, page_id, api_key, components, enabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (slug) DO UPDATE SET provider = excluded.provider, page_id = excluded.page_id, api_key = excluded.api_key,
+			components = excluded.components, enabled = excluded.enabled`),
+		m.Slug, m.Provider, m.PageID, m.APIKey, string(components), m.Enabled, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetStatusPageMirror returns the status page's mirror, or ErrStatusPageMirrorNotFound.
+func (s *Store) GetStatusPageMirror(slug string) (*StatusPageMirror, error) {
+	m, err := scanStatusPageMirror(s.db.QueryRow(s.rebind("SELECT "+statusPageMirrorColumns+" FROM status_page_mirrors WHERE slug = ?"), slug))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrStatusPageMirrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetStatusPageMirrors returns every status page mirror.
+func (s *Store) GetStatusPageMirrors() ([]StatusPageMirror, error) {
+	rows, err := s.db.Query("SELECT " + statusPageMirrorColumns + " FROM status_page_mirrors ORDER BY slug")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var mirrors []StatusPageMirror
+	for rows.Next() {
+		m, err := scanStatusPageMirror(rows)
+		if err != nil {
+			return nil, err
+		}
+		mirrors = append(mirrors, m)
+	}
+	return mirrors, rows.Err()
+}
+
+// DeleteStatusPageMirror stops mirroring a status page. It reports whether it was mirrored.
+func (s *Store) DeleteStatusPageMirror(slug string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM status_page_mirrors WHERE slug = ?"), slug)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// SetStatusPageMirrorResult records a push to the hosted page: when it happened and why
+// it failed, or an empty message when it succeeded.
+func (s *Store) SetStatusPageMirrorResult(slug string, at time.Time, errMsg string) error {
+	_, err := s.db.Exec(s.rebind("UPDATE status_page_mirrors SET last_sync_at = ?, last_error = ? WHERE slug = ?"), at.UTC(), errMsg, slug)
+	return err
+}
+
+// GetMirroredIncidents returns the incidents copied to the status page's hosted page,
+// keyed by incident ID.
+func (s *Store) GetMirroredIncidents(slug string) (map[string]MirroredIncident, error) {
+	rows, err := s.db.Query(s.rebind("SELECT incident_id, external_id, status, last_update_id FROM status_page_mirror_incidents WHERE slug = ?"), slug)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	incidents := make(map[string]MirroredIncident)
+	for rows.Next() {
+		var m MirroredIncident
+		if err := rows.Scan(&m.IncidentID, &m.ExternalID, &m.Status, &m.LastUpdateID); err != nil {
+			return nil, err
+		}
+		incidents[m.IncidentID] = m
+	}
+	return incidents, rows.Err()
+}
+
+// SetMirroredIncident records what was pushed of an incident to the status page's hosted page.
+func (s *Store) SetMirroredIncident(slug string, m MirroredIncident) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO status_page_mirror_incidents (slug, incident_id, external_id, status, last_update_id)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (slug, incident_id) DO UPDATE SET external_id = excluded.external_id, status = excluded.status,
+			last_update_id = excluded.last_update_id`),
+		slug, m.IncidentID, m.ExternalID, m.Status, m.LastUpdateID)
+	return err
+}
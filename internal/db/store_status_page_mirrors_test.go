@@ -0,0 +1,61 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusPageMirror_MovingForgetsIncidents(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.CreateStatusPage(StatusPageInput{Slug: "ops", Title: "Ops", Enabled: true}); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	m := StatusPageMirror{Slug: "ops", Provider: "statuspage", PageID: "pg1", APIKey: "k", Enabled: true}
+	_ = store.SetStatusPageMirror(m)
+	_ = store.CreateIncident(Incident{ID: "inc-1", Title: "Down", Type: "incident", Status: "investigating", StartTime: time.Now()})
+	_ = store.SetMirroredIncident("ops", MirroredIncident{IncidentID: "inc-1", ExternalID: "x", Status: "investigating"})
+
+	m.Components = map[string]string{"m1": "c1"}
+	_ = store.SetStatusPageMirror(m)
+	if got, _ := store.GetMirroredIncidents("ops"); len(got) != 1 {
+		t.Fatalf("Expected the mirrored incident to be kept, got %v", got)
+	}
+
+	m.PageID = "pg2"
+	_ = store.SetStatusPageMirror(m)
+	if got, _ := store.GetMirroredIncidents("ops"); len(got) != 0 {
+		t.Errorf("Expected mirrored incidents to be forgotten, got %v", got)
+	}
+
+	// Deleting the status page deletes its mirror
+	if _, err := store.DeleteStatusPage("ops"); err != nil {
+		t.Fatalf("DeleteStatusPage failed: %v", err)
+	}
+	if _, err := store.GetStatusPageMirror("ops"); err != ErrStatusPageMirrorNotFound {
+		t.Errorf("Expected the mirror to be deleted, got %v", err)
+	}
+}
+
+func TestStatusPageMirror_KeptWhenPageUpdated(t *testing.T) {
+	store := newTestStore(t)
+	page := StatusPageInput{Slug: "ops", Title: "Ops", Enabled: true}
+	if err := store.CreateStatusPage(page); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	if err := store.SetStatusPageMirror(StatusPageMirror{Slug: "ops", Provider: "statuspage", PageID: "pg1", APIKey: "k", Enabled: true}); err != nil {
+		t.Fatalf("SetStatusPageMirror failed: %v", err)
+	}
+	_ = store.CreateIncident(Incident{ID: "inc-1", Title: "Down", Type: "incident", Status: "investigating", StartTime: time.Now()})
+	_ = store.SetMirroredIncident("ops", MirroredIncident{IncidentID: "inc-1", ExternalID: "x", Status: "investigating"})
+
+	page.Title = "Operations"
+	if err := store.UpsertStatusPageFull(page); err != nil {
+		t.Fatalf("UpsertStatusPageFull failed: %v", err)
+	}
+	if _, err := store.GetStatusPageMirror("ops"); err != nil {
+		t.Errorf("Expected the mirror to survive the update, got %v", err)
+	}
+	if got, _ := store.GetMirroredIncidents("ops"); len(got) != 1 {
+		t.Errorf("Expected the mirrored incident to survive the update, got %v", got)
+	}
+}
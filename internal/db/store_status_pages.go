@@ -26,6 +26,11 @@ type StatusPage struct {
 	HeaderContent     string `json:"headerContent"`     // 'logo-title', 'logo-only', 'title-only'
 	HeaderAlignment   string `json:"headerAlignment"`   // 'left', 'center', 'right'
 	HeaderArrangement string `json:"headerArrangement"` // 'stacked', 'inline'
+	Locale            string `json:"locale"`            // visitor-facing label language, e.g. 'en', 'de'
+	Noindex           bool   `json:"noindex"`           // ask search engines not to index the page
+	MetaDescription   string `json:"metaDescription"`   // search result / link preview text
+	OGImageURL        string `json:"ogImageUrl"`        // OpenGraph preview image
+	ShowSLA           bool   `json:"showSla"`           // show the rolling uptime banner
 }
 
 // GetStatusPages returns all status page configs
@@ -33,7 +38,8 @@ func (s *Store) GetStatusPages() ([]StatusPage, error) {
 	rows, err := s.db.Query(`SELECT id, slug, title, group_id, public, enabled, created_at,
 		COALESCE(description, ''), COALESCE(logo_url, ''), COALESCE(favicon_url, ''), COALESCE(accent_color, ''), COALESCE(theme, 'system'),
 		COALESCE(show_uptime_bars, TRUE), COALESCE(show_uptime_percentage, TRUE), COALESCE(show_incident_history, TRUE),
-		COALESCE(uptime_days_range, 90), COALESCE(header_content, 'logo-title'), COALESCE(header_alignment, 'center'), COALESCE(header_arrangement, 'inline')
+		COALESCE(uptime_days_range, 90), COALESCE(header_content, 'logo-title'), COALESCE(header_alignment, 'center'), COALESCE(header_arrangement, 'inline'), COALESCE(locale, 'en'),
+		COALESCE(noindex, FALSE), COALESCE(meta_description, ''), COALESCE(og_image_url, ''), COALESCE(show_sla, FALSE)
 		FROM status_pages`)
 	if err != nil {
 		return nil, err
@@ -47,7 +53,8 @@ func (s *Store) GetStatusPages() ([]StatusPage, error) {
 		if err := rows.Scan(&p.ID, &p.Slug, &p.Title, &groupID, &p.Public, &p.Enabled, &p.CreatedAt,
 			&p.Description, &p.LogoURL, &p.FaviconURL, &p.AccentColor, &p.Theme,
 			&p.ShowUptimeBars, &p.ShowUptimePercentage, &p.ShowIncidentHistory, &p.UptimeDaysRange,
-			&p.HeaderContent, &p.HeaderAlignment, &p.HeaderArrangement); err != nil {
+			&p.HeaderContent, &p.HeaderAlignment, &p.HeaderArrangement, &p.Locale,
+			&p.Noindex, &p.MetaDescription, &p.OGImageURL, &p.ShowSLA); err != nil {
 			return nil, err
 		}
 		if groupID.Valid {
@@ -66,12 +73,14 @@ func (s *Store) GetStatusPageBySlug(slug string) (*StatusPage, error) {
 	err := s.db.QueryRow(s.rebind(`SELECT id, slug, title, group_id, public, enabled, created_at,
 		COALESCE(description, ''), COALESCE(logo_url, ''), COALESCE(favicon_url, ''), COALESCE(accent_color, ''), COALESCE(theme, 'system'),
 		COALESCE(show_uptime_bars, TRUE), COALESCE(show_uptime_percentage, TRUE), COALESCE(show_incident_history, TRUE),
-		COALESCE(uptime_days_range, 90), COALESCE(header_content, 'logo-title'), COALESCE(header_alignment, 'center'), COALESCE(header_arrangement, 'inline')
+		COALESCE(uptime_days_range, 90), COALESCE(header_content, 'logo-title'), COALESCE(header_alignment, 'center'), COALESCE(header_arrangement, 'inline'), COALESCE(locale, 'en'),
+		COALESCE(noindex, FALSE), COALESCE(meta_description, ''), COALESCE(og_image_url, ''), COALESCE(show_sla, FALSE)
 		FROM status_pages WHERE slug = ?`), slug).
 		Scan(&p.ID, &p.Slug, &p.Title, &groupID, &p.Public, &p.Enabled, &p.CreatedAt,
 			&p.Description, &p.LogoURL, &p.FaviconURL, &p.AccentColor, &p.Theme,
 			&p.ShowUptimeBars, &p.ShowUptimePercentage, &p.ShowIncidentHistory, &p.UptimeDaysRange,
-			&p.HeaderContent, &p.HeaderAlignment, &p.HeaderArrangement)
+			&p.HeaderContent, &p.HeaderAlignment, &p.HeaderArrangement, &p.Locale,
+			&p.Noindex, &p.MetaDescription, &p.OGImageURL, &p.ShowSLA)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -104,6 +113,11 @@ type StatusPageInput struct {
 	HeaderContent     string
 	HeaderAlignment   string
 	HeaderArrangement string
+	Locale            string
+	Noindex           bool
+	MetaDescription   string
+	OGImageURL        string
+	ShowSLA           bool
 }
 
 // UpsertStatusPage creates or updates a status page config
@@ -126,50 +140,77 @@ func (s *Store) UpsertStatusPage(slug, title string, groupID *string, public boo
 		HeaderContent:        "logo-title",
 		HeaderAlignment:      "center",
 		HeaderArrangement:    "stacked",
+		Locale:               "en",
 	})
 }
 
-// UpsertStatusPageFull creates or updates a status page config with all fields
+// UpsertStatusPageFull creates or updates a status page config with all fields.
+// It updates the row in place: SQLite's INSERT OR REPLACE would delete it first and
+// cascade to the page's mirror and channels.
 func (s *Store) UpsertStatusPageFull(input StatusPageInput) error {
-	var err error
-	if s.IsPostgres() {
-		_, err = s.db.Exec(`
-			INSERT INTO status_pages (slug, title, group_id, public, enabled, description, logo_url, favicon_url, accent_color, theme, show_uptime_bars, show_uptime_percentage, show_incident_history, uptime_days_range, header_content, header_alignment, header_arrangement)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-			ON CONFLICT(slug) DO UPDATE SET
-				title=excluded.title,
-				group_id=excluded.group_id,
-				public=excluded.public,
-				enabled=excluded.enabled,
-				description=excluded.description,
-				logo_url=excluded.logo_url,
-				favicon_url=excluded.favicon_url,
-				accent_color=excluded.accent_color,
-				theme=excluded.theme,
-				show_uptime_bars=excluded.show_uptime_bars,
-				show_uptime_percentage=excluded.show_uptime_percentage,
-				show_incident_history=excluded.show_incident_history,
-				uptime_days_range=excluded.uptime_days_range,
-				header_content=excluded.header_content,
-				header_alignment=excluded.header_alignment,
-				header_arrangement=excluded.header_arrangement
-		`, input.Slug, input.Title, input.GroupID, input.Public, input.Enabled,
-			input.Description, input.LogoURL, input.FaviconURL, input.AccentColor, input.Theme,
-			input.ShowUptimeBars, input.ShowUptimePercentage, input.ShowIncidentHistory, input.UptimeDaysRange,
-			input.HeaderContent, input.HeaderAlignment, input.HeaderArrangement)
-	} else {
-		// SQLite: INSERT OR REPLACE (slug has UNIQUE constraint)
-		_, err = s.db.Exec(`
-			INSERT OR REPLACE INTO status_pages (slug, title, group_id, public, enabled, description, logo_url, favicon_url, accent_color, theme, show_uptime_bars, show_uptime_percentage, show_incident_history, uptime_days_range, header_content, header_alignment, header_arrangement)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, input.Slug, input.Title, input.GroupID, input.Public, input.Enabled,
-			input.Description, input.LogoURL, input.FaviconURL, input.AccentColor, input.Theme,
-			input.ShowUptimeBars, input.ShowUptimePercentage, input.ShowIncidentHistory, input.UptimeDaysRange,
-			input.HeaderContent, input.HeaderAlignment, input.HeaderArrangement)
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO status_pages (slug, title, group_id, public, enabled, description, logo_url, favicon_url, accent_color, theme, show_uptime_bars, show_uptime_percentage, show_incident_history, uptime_days_range, header_content, header_alignment, header_arrangement, locale, noindex, meta_description, og_image_url, show_sla)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(slug) DO UPDATE SET
+			title=excluded.title,
+			group_id=excluded.group_id,
+			public=excluded.public,
+			enabled=excluded.enabled,
+			description=excluded.description,
+			logo_url=excluded.logo_url,
+			favicon_url=excluded.favicon_url,
+			accent_color=excluded.accent_color,
+			theme=excluded.theme,
+			show_uptime_bars=excluded.show_uptime_bars,
+			show_uptime_percentage=excluded.show_uptime_percentage,
+			show_incident_history=excluded.show_incident_history,
+			uptime_days_range=excluded.uptime_days_range,
+			header_content=excluded.header_content,
+			header_alignment=excluded.header_alignment,
+			header_arrangement=excluded.header_arrangement,
+			locale=excluded.locale,
+			noindex=excluded.noindex,
+			meta_description=excluded.meta_description,
+			og_image_url=excluded.og_image_url,
+			show_sla=excluded.show_sla
+	`), input.Slug, input.Title, input.GroupID, input.Public, input.Enabled,
+		input.Description, input.LogoURL, input.FaviconURL, input.AccentColor, input.Theme,
+		input.ShowUptimeBars, input.ShowUptimePercentage, input.ShowIncidentHistory, input.UptimeDaysRange,
+		input.HeaderContent, input.HeaderAlignment, input.HeaderArrangement, input.Locale,
+		input.Noindex, input.MetaDescription, input.OGImageURL, input.ShowSLA)
+	return err
+}
+
+// CreateStatusPage inserts a new status page, failing on a duplicate slug
+// rather than replacing the existing page.
+func (s *Store) CreateStatusPage(input StatusPageInput) error {
+	_, err := s.db.Exec(s.rebind(`
+		INSERT INTO status_pages (slug, title, group_id, public, enabled, description, logo_url, favicon_url, accent_color, theme, show_uptime_bars, show_uptime_percentage, show_incident_history, uptime_days_range, header_content, header_alignment, header_arrangement, locale, noindex, meta_description, og_image_url, show_sla)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`), input.Slug, input.Title, input.GroupID, input.Public, input.Enabled,
+		input.Description, input.LogoURL, input.FaviconURL, input.AccentColor, input.Theme,
+		input.ShowUptimeBars, input.ShowUptimePercentage, input.ShowIncidentHistory, input.UptimeDaysRange,
+		input.HeaderContent, input.HeaderAlignment, input.HeaderArrangement, input.Locale,
+		input.Noindex, input.MetaDescription, input.OGImageURL, input.ShowSLA)
+	if _, ok := uniqueViolation(err); ok {
+		return newConflictError("a status page with this slug already exists")
 	}
 	return err
 }
 
+// DeleteStatusPage removes a status page. Returns false if no page has the slug.
+func (s *Store) DeleteStatusPage(slug string) (bool, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM status_pages WHERE slug = ?"), slug)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 // ToggleStatusPage toggles the public status
 func (s *Store) ToggleStatusPage(slug string, public bool) error {
 	_, err := s.db.Exec(s.rebind("UPDATE status_pages SET public = ? WHERE slug = ?"), public, slug)
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserTokenPrefix starts every personal API token, telling them apart from global
+// API keys ("sk_live_") before any lookup.
+const UserTokenPrefix = "wpt_"
+
+var ErrUserTokenNotFound = errors.New("user token not found")
+
+// UserToken is a personal API token. Requests made with it act as the owning user.
+type UserToken struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"userId"`
+	TokenPrefix string     `json:"tokenPrefix"`
+	Name        string     `json:"name"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsed    *time.Time `json:"lastUsed,omitempty"`
+}
+
+// CreateUserToken creates a personal token for userID and returns the raw token,
+// which is not stored.
+func (s *Store) CreateUserToken(userID int64, name string) (string, error) {
+	// SECURITY: 256 bits of entropy, as for global API keys
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	rawToken := UserTokenPrefix + hex.EncodeToString(tokenBytes)
+	prefix := rawToken[:12] // "wpt_" + first 8 hex chars
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawToken), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.db.Exec(s.rebind("INSERT INTO user_tokens (user_id, token_prefix, token_hash, name) VALUES (?, ?, ?, ?)"),
+		userID, prefix, string(hash), name)
+	if err != nil {
+		return "", err
+	}
+	return rawToken, nil
+}
+
+// ListUserTokens returns userID's personal tokens, newest first.
+func (s *Store) ListUserTokens(userID int64) ([]UserToken, error) {
+	rows, err := s.db.Query(s.rebind("SELECT id, user_id, token_prefix, name, created_at, last_used_at FROM user_tokens WHERE user_id = ? ORDER BY created_at DESC, id DESC"), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tokens := []UserToken{}
+	for rows.Next() {
+		var t UserToken
+		var lastUsed sql.NullTime
+		if err := rows.Scan(&t.ID, &t.UserID, &t.TokenPrefix, &t.Name, &t.CreatedAt, &lastUsed); err != nil {
+			return nil, err
+		}
+		if lastUsed.Valid {
+			t.LastUsed = &lastUsed.Time
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
+
+// DeleteUserToken revokes one of userID's tokens. Tokens of other users are reported
+// as not found.
+func (s *Store) DeleteUserToken(userID, id int64) error {
+	res, err := s.db.Exec(s.rebind("DELETE FROM user_tokens WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	s.userTokenGuard.forget(id)
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrUserTokenNotFound
+	}
+	return nil
+}
+
+// ValidateUserToken returns the ID of the user owning token, and false if token is
+// not a valid personal token. A client locked out of the token's prefix after repeated
+// wrong tokens from clientIP gets ErrCredentialLocked.
+func (s *Store) ValidateUserToken(token, clientIP string) (int64, bool, error) {
+	if len(token) < 12 || !strings.HasPrefix(token, UserTokenPrefix) {
+		return 0, false, nil
+	}
+	prefix := token[:12]
+
+	if c, ok := s.userTokenGuard.lookup(token); ok {
+		return c.ownerID, true, nil
+	}
+	if s.userTokenGuard.locked(clientIP, prefix) {
+		return 0, false, ErrCredentialLocked
+	}
+
+	rows, err := s.db.Query(s.rebind("SELECT id, user_id, token_hash FROM user_tokens WHERE token_prefix = ?"), prefix)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	candidates := 0
+	for rows.Next() {
+		var id, userID int64
+		var hash string
+		if err := rows.Scan(&id, &userID, &hash); err != nil {
+			continue
+		}
+		candidates++
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(token)); err == nil {
+			s.userTokenGuard.remember(token, clientIP, prefix, cachedCredential{id: id, ownerID: userID})
+			go func(tokenID int64) {
+				_, _ = s.db.Exec(s.rebind("UPDATE user_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = ?"), tokenID)
+			}(id)
+			return userID, true, nil
+		}
+	}
+
+	if candidates > 0 && s.userTokenGuard.recordFailure(clientIP, prefix) {
+		log.Printf("AUDIT: [SECURITY] IP %s locked out of personal token prefix %q for %s after repeated invalid tokens", clientIP, prefix, CredentialLockout)
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUserTokens(t *testing.T) {
+	s := newTestStore(t)
+	for _, name := range []string{"alice", "bob"} {
+		if err := s.CreateUser(name, "password123", "UTC"); err != nil {
+			t.Fatalf("CreateUser %s: %v", name, err)
+		}
+	}
+	alice, _ := s.Authenticate("alice", "password123")
+	bob, _ := s.Authenticate("bob", "password123")
+
+	token, err := s.CreateUserToken(alice.ID, "CI")
+	if err != nil {
+		t.Fatalf("CreateUserToken failed: %v", err)
+	}
+	if !strings.HasPrefix(token, UserTokenPrefix) {
+		t.Errorf("expected token to start with %q, got %q", UserTokenPrefix, token)
+	}
+
+	// Resolves to the owner
+	userID, ok, err := s.ValidateUserToken(token, "")
+	if err != nil || !ok || userID != alice.ID {
+		t.Fatalf("ValidateUserToken = (%d, %v, %v), want (%d, true, nil)", userID, ok, err, alice.ID)
+	}
+	if _, ok, _ := s.ValidateUserToken(token[:len(token)-1]+"x", ""); ok {
+		t.Error("expected a wrong token to be rejected")
+	}
+
+	// Personal tokens and API keys are not interchangeable
+	key, _ := s.CreateAPIKey("Service", nil)
+	if _, ok, _ := s.ValidateUserToken(key, ""); ok {
+		t.Error("expected an API key to be rejected as a personal token")
+	}
+	if valid, _ := s.ValidateAPIKey(token, ""); valid {
+		t.Error("expected a personal token to be rejected as an API key")
+	}
+
+	tokens, err := s.ListUserTokens(alice.ID)
+	if err != nil {
+		t.Fatalf("ListUserTokens failed: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Name != "CI" || tokens[0].UserID != alice.ID || tokens[0].TokenPrefix != token[:12] {
+		t.Fatalf("unexpected tokens %+v", tokens)
+	}
+	if others, _ := s.ListUserTokens(bob.ID); len(others) != 0 {
+		t.Errorf("expected bob to have no tokens, got %d", len(others))
+	}
+
+	// Only the owner can revoke
+	if err := s.DeleteUserToken(bob.ID, tokens[0].ID); !errors.Is(err, ErrUserTokenNotFound) {
+		t.Errorf("expected ErrUserTokenNotFound deleting another user's token, got %v", err)
+	}
+	if err := s.DeleteUserToken(alice.ID, tokens[0].ID); err != nil {
+		t.Fatalf("DeleteUserToken failed: %v", err)
+	}
+	if _, ok, _ := s.ValidateUserToken(token, ""); ok {
+		t.Error("expected a revoked token to be rejected")
+	}
+}
+
+func TestUserTokens_LockoutIsPerClient(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateUser("alice", "password123", "UTC"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	alice, _ := s.Authenticate("alice", "password123")
+	token, _ := s.CreateUserToken(alice.ID, "CI")
+
+	wrong := token[:12] + strings.Repeat("0", len(token)-12)
+	for i := 0; i < credentialFailureLimit; i++ {
+		_, _, _ = s.ValidateUserToken(wrong, "203.0.113.9")
+	}
+	if _, ok, err := s.ValidateUserToken(token, "203.0.113.9"); ok || !errors.Is(err, ErrCredentialLocked) {
+		t.Errorf("expected ErrCredentialLocked for the guessing IP, got (%v, %v)", ok, err)
+	}
+	if userID, ok, err := s.ValidateUserToken(token, "198.51.100.7"); !ok || err != nil || userID != alice.ID {
+		t.Errorf("expected the token to keep working from another IP, got (%d, %v, %v)", userID, ok, err)
+	}
+}
@@ -160,6 +160,15 @@ func (s *Store) DeleteSession(token string) error {
 	return err
 }
 
+// DeleteExpiredSessions deletes sessions that expired before now.
+func (s *Store) DeleteExpiredSessions(now time.Time) (int64, error) {
+	res, err := s.db.Exec(s.rebind("DELETE FROM sessions WHERE expires_at <= ?"), now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // DeleteUserSessions deletes all sessions for a user.
 // If exceptToken is non-empty, that session will be preserved (e.g., current session).
 func (s *Store) DeleteUserSessions(userID int64, exceptToken string) error {
@@ -96,6 +96,31 @@ func TestSessions(t *testing.T) {
 	}
 }
 
+func TestDeleteExpiredSessions(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.CreateUser("user1", "pass", "UTC")
+	u, _ := s.Authenticate("user1", "pass")
+
+	now := time.Now()
+	if err := s.CreateSession(u.ID, "expired", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := s.CreateSession(u.ID, "valid", now.Add(time.Hour)); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	n, err := s.DeleteExpiredSessions(now)
+	if err != nil {
+		t.Fatalf("DeleteExpiredSessions failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 deleted session, got %d", n)
+	}
+	if sess, _ := s.GetSession("valid"); sess == nil {
+		t.Error("Valid session should be kept")
+	}
+}
+
 func TestHasUsers(t *testing.T) {
 	s := newTestStore(t)
 
@@ -0,0 +1,361 @@
+// Package demo runs Warden against a made-up company: it seeds groups, monitors, a
+// month of check history, incidents and a public status page, and answers every check
+// itself instead of going to the network. Demo mode keeps everything in an in-memory
+// database, so people can try Warden (and a public demo can be hosted) without wiring
+// real targets, and nothing visitors change outlives the process or the next reset.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+// Credentials of the seeded demo account, shown on the login page in demo mode.
+const (
+	Username = "demo"
+	Password = "demo"
+)
+
+// StatusPageSlug is the public status page the demo seeds, served at /status/acme.
+const StatusPageSlug = "acme"
+
+// HistoryDays is how much synthetic check history Seed writes.
+const HistoryDays = 30
+
+// historyStep spaces the synthetic checks. Live checks run every minute once the
+// manager starts, so only the backfilled history is this coarse.
+const historyStep = 10 * time.Minute
+
+// target describes one seeded monitor and how its made-up host behaves.
+type target struct {
+	id       string
+	group    string
+	name     string
+	host     string
+	path     string
+	latency  int64   // Typical response time, ms
+	jitter   int64   // Random spread around it, ms
+	failRate float64 // Share of checks that fail outside scripted outages
+	tags     []string
+}
+
+func (t target) url() string {
+	return "https://" + t.host + t.path
+}
+
+const (
+	groupProduction = "g-demo-production"
+	groupInternal   = "g-demo-internal"
+	groupMarketing  = "g-demo-marketing"
+)
+
+var groups = []db.Group{
+	{ID: groupProduction, Name: "Production"},
+	{ID: groupInternal, Name: "Internal Tools"},
+	{ID: groupMarketing, Name: "Marketing"},
+}
+
+var targets = []target{
+	{id: "m-demo-api", group: groupProduction, name: "Public API", host: "api.acme.example", path: "/health", latency: 120, jitter: 40, failRate: 0.001, tags: []string{"tier-1", "backend"}},
+	{id: "m-demo-app", group: groupProduction, name: "Web App", host: "app.acme.example", path: "/", latency: 180, jitter: 60, failRate: 0.001, tags: []string{"tier-1", "frontend"}},
+	{id: "m-demo-checkout", group: groupProduction, name: "Checkout", host: "checkout.acme.example", path: "/health", latency: 240, jitter: 80, failRate: 0.004, tags: []string{"tier-1", "payments"}},
+	{id: "m-demo-auth", group: groupProduction, name: "Auth Service", host: "auth.acme.example", path: "/healthz", latency: 90, jitter: 25, failRate: 0.0005, tags: []string{"tier-1", "backend"}},
+	{id: "m-demo-cdn", group: groupProduction, name: "CDN Edge", host: "cdn.acme.example", path: "/status", latency: 35, jitter: 10, tags: []string{"edge"}},
+	{id: "m-demo-grafana", group: groupInternal, name: "Grafana", host: "grafana.acme.example", path: "/api/health", latency: 60, jitter: 20, failRate: 0.002, tags: []string{"observability"}},
+	{id: "m-demo-ci", group: groupInternal, name: "CI Runners", host: "ci.acme.example", path: "/status", latency: 650, jitter: 250, failRate: 0.003, tags: []string{"devtools"}},
+	{id: "m-demo-registry", group: groupInternal, name: "Container Registry", host: "registry.acme.example", path: "/v2/", latency: 150, jitter: 50, failRate: 0.001, tags: []string{"devtools"}},
+	{id: "m-demo-www", group: groupMarketing, name: "Website", host: "www.acme.example", path: "/", latency: 220, jitter: 70, failRate: 0.001, tags: []string{"frontend"}},
+	{id: "m-demo-blog", group: groupMarketing, name: "Blog", host: "blog.acme.example", path: "/", latency: 380, jitter: 120, failRate: 0.002, tags: []string{"frontend"}},
+	{id: "m-demo-docs", group: groupMarketing, name: "Docs", host: "docs.acme.example", path: "/", latency: 160, jitter: 50, failRate: 0.001, tags: []string{"frontend"}},
+}
+
+// outage is a scripted stretch of failed checks in the seeded history.
+type outage struct {
+	monitorID string
+	ago       time.Duration // How long before the seed time it started
+	length    time.Duration
+	errorKind string
+	summary   string
+}
+
+var outages = []outage{
+	{monitorID: "m-demo-checkout", ago: 6*24*time.Hour + 3*time.Hour, length: 40 * time.Minute, errorKind: db.ErrorKindHTTP5xx, summary: "HTTP 503 Service Unavailable"},
+	{monitorID: "m-demo-blog", ago: 2*24*time.Hour + 9*time.Hour, length: 20 * time.Minute, errorKind: db.ErrorKindTimeout, summary: "context deadline exceeded (Client.Timeout exceeded while awaiting headers)"},
+	{monitorID: "m-demo-grafana", ago: 13*24*time.Hour + 5*time.Hour, length: 30 * time.Minute, errorKind: db.ErrorKindConnectionRefused, summary: "dial tcp 203.0.113.21:443: connect: connection refused"},
+}
+
+// Seed fills a freshly created store with the demo company as of now: the demo user,
+// groups and monitors, HistoryDays of check history with a few outages, incidents, a
+// deploy annotation and the public status page. The store's default group is removed.
+// History is generated from a fixed seed, so every reset looks the same.
+func Seed(store *db.Store, now time.Time) error {
+	now = now.UTC()
+	if err := store.CreateUser(Username, Password, "UTC"); err != nil {
+		return fmt.Errorf("create demo user: %w", err)
+	}
+	if err := store.DeleteGroup("g-default"); err != nil {
+		return fmt.Errorf("remove default group: %w", err)
+	}
+	for _, g := range groups {
+		if err := store.CreateGroup(g); err != nil {
+			return fmt.Errorf("create group %s: %w", g.Name, err)
+		}
+	}
+	for _, t := range targets {
+		m := db.Monitor{
+			ID:       t.id,
+			GroupID:  t.group,
+			Name:     t.name,
+			URL:      t.url(),
+			Active:   true,
+			Interval: 60,
+			Tags:     t.tags,
+		}
+		if err := store.CreateMonitor(m); err != nil {
+			return fmt.Errorf("create monitor %s: %w", t.name, err)
+		}
+	}
+
+	if err := seedHistory(store, now); err != nil {
+		return err
+	}
+	if err := seedIncidents(store, now); err != nil {
+		return err
+	}
+
+	if _, err := store.CreateAnnotation(db.Annotation{
+		MonitorID: "m-demo-checkout",
+		Kind:      db.AnnotationKindDeploy,
+		Message:   "checkout v2.14.0: retry payment provider timeouts",
+		Timestamp: now.Add(-6*24*time.Hour - 2*time.Hour),
+	}); err != nil {
+		return fmt.Errorf("create annotation: %w", err)
+	}
+
+	if err := store.CreateStatusPage(db.StatusPageInput{
+		Slug:                 StatusPageSlug,
+		Title:                "Acme Status",
+		Public:               true,
+		Enabled:              true,
+		Description:          "Live status of Acme's public services.",
+		ShowUptimeBars:       true,
+		ShowUptimePercentage: true,
+		ShowIncidentHistory:  true,
+		UptimeDaysRange:      HistoryDays,
+	}); err != nil {
+		return fmt.Errorf("create status page: %w", err)
+	}
+	return nil
+}
+
+// seedHistory writes the synthetic checks of every monitor, plus the outages and their
+// down and recovered events.
+func seedHistory(store *db.Store, now time.Time) error {
+	rng := rand.New(rand.NewPCG(20240601, 7))
+	start := now.Add(-HistoryDays * 24 * time.Hour).Truncate(historyStep)
+
+	for _, t := range targets {
+		var scripted []outage
+		for _, o := range outages {
+			if o.monitorID == t.id {
+				scripted = append(scripted, o)
+			}
+		}
+
+		batch := make([]db.CheckResult, 0, HistoryDays*24*int(time.Hour/historyStep))
+		for ts := start; ts.Before(now); ts = ts.Add(historyStep) {
+			check := t.check(rng, ts)
+			for _, o := range scripted {
+				begin := now.Add(-o.ago)
+				if !ts.Before(begin) && ts.Before(begin.Add(o.length)) {
+					check = failedCheck(t.id, ts, o.errorKind)
+				}
+			}
+			batch = append(batch, check)
+		}
+		if err := store.BatchInsertChecks(batch); err != nil {
+			return fmt.Errorf("insert checks for %s: %w", t.name, err)
+		}
+
+		for _, o := range scripted {
+			begin := now.Add(-o.ago)
+			end := begin.Add(o.length)
+			if _, err := store.CreateClosedOutage(t.id, "down", o.summary, o.errorKind, begin, end); err != nil {
+				return fmt.Errorf("create outage for %s: %w", t.name, err)
+			}
+			if err := store.CreateEventAt(t.id, "down", o.summary, begin); err != nil {
+				return err
+			}
+			if err := store.CreateEventAt(t.id, "recovered", "Monitor recovered", end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check returns a synthetic check of t at ts: usually up, with a latency that rises
+// during European business hours, and failed at t's fail rate.
+func (t target) check(rng *rand.Rand, ts time.Time) db.CheckResult {
+	if t.failRate > 0 && rng.Float64() < t.failRate {
+		return failedCheck(t.id, ts, db.ErrorKindHTTP5xx)
+	}
+	latency := t.latencyAt(ts, rng.Float64())
+	return db.CheckResult{
+		MonitorID:  t.id,
+		Status:     "up",
+		Latency:    latency,
+		Timestamp:  ts,
+		StatusCode: 200,
+		Timing:     splitLatency(latency),
+	}
+}
+
+// latencyAt is t's response time at ts for a uniform random r in [0, 1).
+func (t target) latencyAt(ts time.Time, r float64) int64 {
+	hour := float64(ts.Hour()) + float64(ts.Minute())/60
+	load := 1 + 0.3*math.Max(0, math.Sin((hour-7)*math.Pi/12))
+	latency := int64(float64(t.latency)*load) + int64((r*2-1)*float64(t.jitter))
+	return max(latency, 5)
+}
+
+func failedCheck(monitorID string, ts time.Time, errorKind string) db.CheckResult {
+	c := db.CheckResult{MonitorID: monitorID, Status: "down", Timestamp: ts, ErrorKind: errorKind}
+	switch errorKind {
+	case db.ErrorKindHTTP5xx:
+		c.StatusCode = 503
+		c.Latency = 45
+	case db.ErrorKindTimeout:
+		c.Latency = 5000
+	}
+	return c
+}
+
+// splitLatency divides a response time into plausible request phases.
+func splitLatency(latency int64) *db.CheckTiming {
+	dns := latency / 20
+	connect := latency / 10
+	tls := latency / 8
+	download := latency / 25
+	return &db.CheckTiming{
+		DNS:       dns,
+		Connect:   connect,
+		TLS:       tls,
+		TTFB:      latency - dns - connect - tls - download,
+		Download:  download,
+		BodyBytes: 2048 + latency*3,
+	}
+}
+
+// seedIncidents posts a resolved incident for the checkout outage, an ongoing one for the
+// slow CI runners and an upcoming maintenance window.
+func seedIncidents(store *db.Store, now time.Time) error {
+	checkoutStart := now.Add(-6*24*time.Hour - 3*time.Hour)
+	checkoutEnd := checkoutStart.Add(40 * time.Minute)
+	resolved := db.Incident{
+		ID:             "inc-demo-checkout",
+		Title:          "Checkout errors",
+		Description:    "Some customers saw errors when paying for their orders.",
+		Type:           "incident",
+		Severity:       "major",
+		Status:         "resolved",
+		StartTime:      checkoutStart,
+		EndTime:        &checkoutEnd,
+		AffectedGroups: `["` + groupProduction + `"]`,
+		Public:         true,
+	}
+	if err := store.CreateIncident(resolved); err != nil {
+		return fmt.Errorf("create incident: %w", err)
+	}
+	updates := []struct {
+		after   time.Duration
+		status  string
+		message string
+	}{
+		{5 * time.Minute, "investigating", "We're investigating failed payments at checkout."},
+		{18 * time.Minute, "identified", "Our payment provider is timing out. We're rolling out retries."},
+		{32 * time.Minute, "monitoring", "Retries are live and payments are going through. We're monitoring."},
+		{55 * time.Minute, "resolved", "Checkout has been healthy for 15 minutes. Sorry for the trouble."},
+	}
+	for _, u := range updates {
+		if err := store.CreateIncidentUpdateAt(resolved.ID, u.status, u.message, checkoutStart.Add(u.after)); err != nil {
+			return fmt.Errorf("create incident update: %w", err)
+		}
+	}
+
+	ongoing := db.Incident{
+		ID:             "inc-demo-ci",
+		Title:          "Slow CI builds",
+		Description:    "Builds are queueing longer than usual.",
+		Type:           "incident",
+		Severity:       "minor",
+		Status:         "identified",
+		StartTime:      now.Add(-90 * time.Minute),
+		AffectedGroups: `["` + groupInternal + `"]`,
+	}
+	if err := store.CreateIncident(ongoing); err != nil {
+		return fmt.Errorf("create incident: %w", err)
+	}
+	if err := store.CreateIncidentUpdateAt(ongoing.ID, "identified", "Two runners are down for disk replacement; builds are queueing on the rest.", now.Add(-75*time.Minute)); err != nil {
+		return fmt.Errorf("create incident update: %w", err)
+	}
+
+	maintStart := now.Add(48 * time.Hour).Truncate(24 * time.Hour).Add(2 * time.Hour)
+	maintEnd := maintStart.Add(time.Hour)
+	maintenance := db.Incident{
+		ID:             "inc-demo-db-upgrade",
+		Title:          "Database upgrade",
+		Description:    "The API and web app may be briefly unavailable while we upgrade the primary database.",
+		Type:           "maintenance",
+		Severity:       "minor",
+		Status:         "scheduled",
+		StartTime:      maintStart,
+		EndTime:        &maintEnd,
+		AffectedGroups: `["` + groupProduction + `"]`,
+		Public:         true,
+	}
+	if err := store.CreateIncident(maintenance); err != nil {
+		return fmt.Errorf("create maintenance: %w", err)
+	}
+	return nil
+}
+
+// Reset wipes the store and seeds it again, stopping the manager's monitors first and
+// reloading them after, as the admin reset endpoint does.
+func Reset(store *db.Store, manager *uptime.Manager) error {
+	manager.Reset()
+	if err := store.Reset(); err != nil {
+		return err
+	}
+	if err := Seed(store, time.Now()); err != nil {
+		return err
+	}
+	manager.Sync()
+	return nil
+}
+
+// ResetEvery resets the demo every interval until ctx is done, so what visitors change
+// on a public demo doesn't stay around for the next ones. Sessions are reset too.
+func ResetEvery(ctx context.Context, store *db.Store, manager *uptime.Manager, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := Reset(store, manager); err != nil {
+				log.Printf("ERROR: Demo reset failed: %v", err)
+				continue
+			}
+			log.Println("Demo data reset")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package demo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/uptime"
+)
+
+func newSeededStore(t *testing.T, now time.Time) *db.Store {
+	t.Helper()
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := Seed(store, now); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	return store
+}
+
+func TestSeed(t *testing.T) {
+	now := time.Now().UTC()
+	store := newSeededStore(t, now)
+
+	if _, err := store.Authenticate(Username, Password); err != nil {
+		t.Errorf("Expected the demo user to sign in: %v", err)
+	}
+
+	groups, err := store.GetGroups()
+	if err != nil {
+		t.Fatalf("GetGroups failed: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Errorf("Expected the 3 demo groups without the default one, got %d", len(groups))
+	}
+	monitors, err := store.GetMonitors()
+	if err != nil {
+		t.Fatalf("GetMonitors failed: %v", err)
+	}
+	if len(monitors) != len(targets) {
+		t.Errorf("Expected %d monitors, got %d", len(targets), len(monitors))
+	}
+
+	// A month of history, down during the scripted checkout outage
+	total, up, err := store.GetUptimeCounts("m-demo-checkout", now.Add(-HistoryDays*24*time.Hour), nil)
+	if err != nil {
+		t.Fatalf("GetUptimeCounts failed: %v", err)
+	}
+	if want := HistoryDays * 24 * 6; total < want-1 || total > want+1 {
+		t.Errorf("Expected about %d checks, got %d", want, total)
+	}
+	if total-up < 4 {
+		t.Errorf("Expected the checkout outage in the history, got %d failed checks", total-up)
+	}
+	if kinds, _ := store.GetErrorKindCounts("m-demo-checkout", now.Add(-7*24*time.Hour)); kinds[db.ErrorKindHTTP5xx] < 4 {
+		t.Errorf("Expected the outage's 5xx checks, got %v", kinds)
+	}
+
+	updates, err := store.GetIncidentUpdates("inc-demo-checkout")
+	if err != nil {
+		t.Fatalf("GetIncidentUpdates failed: %v", err)
+	}
+	if len(updates) != 4 || updates[3].Status != "resolved" || !updates[0].CreatedAt.Before(now.Add(-6*24*time.Hour)) {
+		t.Errorf("Expected the incident's backdated updates, got %+v", updates)
+	}
+
+	page, err := store.GetStatusPageBySlug(StatusPageSlug)
+	if err != nil || page == nil || !page.Public || !page.Enabled {
+		t.Errorf("Expected a public status page, got %+v (err %v)", page, err)
+	}
+}
+
+func TestSeed_Deterministic(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	a := newSeededStore(t, now)
+	b := newSeededStore(t, now)
+
+	for _, id := range []string{"m-demo-api", "m-demo-ci"} {
+		ca, _ := a.GetMonitorChecks(id, 50)
+		cb, _ := b.GetMonitorChecks(id, 50)
+		if len(ca) != 50 || len(cb) != 50 {
+			t.Fatalf("Expected 50 checks of %s, got %d and %d", id, len(ca), len(cb))
+		}
+		for i := range ca {
+			if ca[i].Latency != cb[i].Latency || ca[i].Status != cb[i].Status {
+				t.Fatalf("Expected identical history for %s, check %d differs: %+v vs %+v", id, i, ca[i], cb[i])
+			}
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	client := &http.Client{Transport: NewTransport()}
+
+	for _, url := range []string{"https://cdn.acme.example/status", "http://anything.invalid:8080/"} {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", url, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected status %d for %s", resp.StatusCode, url)
+		}
+		if https := strings.HasPrefix(url, "https"); https != (resp.TLS != nil) {
+			t.Errorf("Expected TLS state only for https, got %v for %s", resp.TLS, url)
+		}
+	}
+
+	// Slow hosts give up with the request's context
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("GET", "https://ci.acme.example/status", nil).WithContext(ctx)
+	req.RequestURI = ""
+	if _, err := client.Do(req); err == nil {
+		t.Error("Expected the request to time out")
+	}
+}
+
+func TestTransport_Preview(t *testing.T) {
+	store := newSeededStore(t, time.Now())
+	manager := uptime.NewManager(store)
+	manager.SetCheckTransport(NewTransport())
+
+	res := manager.PreviewCheck("https://auth.acme.example/healthz", nil, 1000)
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a made-up response, got %+v", res)
+	}
+	if !strings.HasPrefix(res.ResolvedIP, "203.0.113.") {
+		t.Errorf("Expected a documentation address, got %q", res.ResolvedIP)
+	}
+	if res.TLS == nil || res.TLS.Issuer != "Acme Demo CA" || res.TLS.DaysRemaining < 59 {
+		t.Errorf("Expected the demo certificate, got %+v", res.TLS)
+	}
+	if res.Timing == nil || res.Timing.TTFB <= 0 {
+		t.Errorf("Expected request phases to be timed, got %+v", res.Timing)
+	}
+}
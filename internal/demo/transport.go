@@ -0,0 +1,204 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// Transport answers HTTP requests with made-up responses instead of going to the
+// network. Seeded hosts behave as their monitor's profile describes, with the same
+// latency curve and fail rate as the seeded history; any other host is healthy with a
+// latency derived from its name. Request phases are reported through httptrace like a
+// real connection, so check timings and previews look real.
+//
+// Set it as the check and notification transport in demo mode: a public demo then
+// never contacts the hosts or webhooks visitors enter.
+type Transport struct {
+	now func() time.Time
+}
+
+// NewTransport returns a Transport answering as of the current time.
+func NewTransport() *Transport {
+	return &Transport{now: time.Now}
+}
+
+// RoundTrip answers req after the made-up latency of its host, or fails once the
+// request's context is done.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	host := strings.ToLower(req.URL.Hostname())
+	profile := profileFor(host)
+	check := profile.check(rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), t.now().UTC())
+
+	ctx := req.Context()
+	trace := httptrace.ContextClientTrace(ctx)
+	addr := fakeAddr(host) + ":" + portFor(req)
+	phase := func(d int64, start, done func()) error {
+		if start != nil {
+			start()
+		}
+		if err := sleep(ctx, time.Duration(d)*time.Millisecond); err != nil {
+			return err
+		}
+		if done != nil {
+			done()
+		}
+		return nil
+	}
+
+	timing := splitLatency(check.Latency)
+	if trace != nil && trace.GetConn != nil {
+		trace.GetConn(req.URL.Host)
+	}
+	err := phase(timing.DNS, func() {
+		if trace != nil && trace.DNSStart != nil {
+			trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+		}
+	}, func() {
+		if trace != nil && trace.DNSDone != nil {
+			trace.DNSDone(httptrace.DNSDoneInfo{})
+		}
+	})
+	if err == nil {
+		err = phase(timing.Connect, func() {
+			if trace != nil && trace.ConnectStart != nil {
+				trace.ConnectStart("tcp", addr)
+			}
+		}, func() {
+			if trace != nil && trace.ConnectDone != nil {
+				trace.ConnectDone("tcp", addr, nil)
+			}
+		})
+	}
+	var state *tls.ConnectionState
+	if err == nil && req.URL.Scheme == "https" {
+		state = fakeTLS(host, t.now())
+		err = phase(timing.TLS, func() {
+			if trace != nil && trace.TLSHandshakeStart != nil {
+				trace.TLSHandshakeStart()
+			}
+		}, func() {
+			if trace != nil && trace.TLSHandshakeDone != nil {
+				trace.TLSHandshakeDone(*state, nil)
+			}
+		})
+	}
+	if err == nil {
+		if trace != nil && trace.WroteRequest != nil {
+			trace.WroteRequest(httptrace.WroteRequestInfo{})
+		}
+		err = phase(timing.TTFB, nil, func() {
+			if trace != nil && trace.GotFirstResponseByte != nil {
+				trace.GotFirstResponseByte()
+			}
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status, body := http.StatusOK, fmt.Sprintf(`{"status":"ok","service":%q}`, host)
+	if check.Status != "up" {
+		status, body = http.StatusServiceUnavailable, `{"status":"unavailable"}`
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}, "Server": {"acme-edge"}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+		TLS:           state,
+	}, nil
+}
+
+// profileFor returns the seeded target for host, or a healthy one for any other host.
+func profileFor(host string) target {
+	for _, t := range targets {
+		if t.host == host {
+			return t
+		}
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(host))
+	sum := int64(h.Sum32())
+	return target{host: host, latency: 80 + sum%220, jitter: 20 + sum%40}
+}
+
+// fakeAddr maps host to a stable address in TEST-NET-3, reserved for documentation.
+func fakeAddr(host string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(host))
+	return fmt.Sprintf("203.0.113.%d", 1+h.Sum32()%254)
+}
+
+func portFor(req *http.Request) string {
+	if port := req.URL.Port(); port != "" {
+		return port
+	}
+	if req.URL.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// fakeTLS returns a TLS 1.3 connection state whose certificate for host is always
+// two months from expiry.
+func fakeTLS(host string, now time.Time) *tls.ConnectionState {
+	day := now.UTC().Truncate(24 * time.Hour)
+	cert := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: host},
+		Issuer:    pkix.Name{CommonName: "Acme Demo CA"},
+		DNSNames:  []string{host},
+		NotBefore: day.Add(-30 * 24 * time.Hour),
+		NotAfter:  day.Add(60 * 24 * time.Hour),
+	}
+	return &tls.ConnectionState{
+		Version:           tls.VersionTLS13,
+		HandshakeComplete: true,
+		CipherSuite:       tls.TLS_AES_128_GCM_SHA256,
+		ServerName:        host,
+		PeerCertificates:  []*x509.Certificate{cert},
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Trace stands in for traceroute in demo mode with a report through made-up hops.
+func Trace(_ context.Context, host string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "traceroute to %s (%s), 20 hops max (demo)\n", host, fakeAddr(host))
+	hops := []string{"192.0.2.1", "198.51.100.14", "198.51.100.77", fakeAddr(host)}
+	for i, hop := range hops {
+		fmt.Fprintf(&b, "%2d  %s  %.3f ms\n", i+1, hop, 0.8+float64(i)*4.2)
+	}
+	return b.String(), nil
+}
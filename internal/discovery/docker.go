@@ -0,0 +1,453 @@
+// Package discovery manages monitors for the containers of the local Docker daemon that
+// opt in with labels, so homelab stacks get monitored as they are deployed:
+//
+//	labels:
+//	  warden.enable: "true"
+//	  warden.url: "https://photos.home.example.com"
+//
+// Without warden.url, the host of a Traefik router rule is used.
+package discovery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Container labels read by Docker discovery.
+const (
+	LabelEnable   = "warden.enable"   // "true" to monitor the container
+	LabelURL      = "warden.url"      // URL to check; defaults to the Traefik router host
+	LabelName     = "warden.name"     // Monitor name; defaults to the container name
+	LabelGroup    = "warden.group"    // Group name, created if missing; defaults to DefaultGroup
+	LabelInterval = "warden.interval" // Check interval in seconds
+	LabelTags     = "warden.tags"     // Comma-separated tags
+)
+
+const (
+	// ProviderDocker identifies monitors managed by Docker discovery.
+	ProviderDocker = "docker"
+	// DefaultGroup holds discovered monitors without a warden.group label.
+	DefaultGroup = "Docker"
+	// PollInterval is how often containers are listed.
+	PollInterval = 30 * time.Second
+
+	defaultInterval = 60
+	minInterval     = 10
+	requestTimeout  = 10 * time.Second
+
+	// The limits the API puts on monitors
+	maxNameLength = 255
+	maxTags       = 20
+	maxTagLength  = 50
+)
+
+// Manager reloads monitors after changes and reports whether this instance runs
+// background jobs (implemented by uptime.Manager).
+type Manager interface {
+	Sync()
+	RemoveMonitor(id string)
+	IsLeader() bool
+}
+
+// Target is the monitor a container asks for.
+type Target struct {
+	Key      string // Container name, stable across re-creation by docker compose
+	Name     string
+	URL      string
+	Group    string
+	Interval int
+	Tags     []string
+}
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]*$`)
+
+// traefikRule matches the first host of a Traefik router rule, e.g. Host(`app.example.com`).
+var traefikRule = regexp.MustCompile("Host\\(\\s*`([^`]+)`")
+
+// TargetFromLabels reads the monitor a container asks for from its labels. ok is false
+// when the container doesn't opt in; an error when its labels can't be used.
+func TargetFromLabels(containerName string, labels map[string]string) (t Target, ok bool, err error) {
+	if enabled, _ := strconv.ParseBool(labels[LabelEnable]); !enabled {
+		return Target{}, false, nil
+	}
+	t = Target{
+		Key:      containerName,
+		Name:     strings.TrimSpace(labels[LabelName]),
+		URL:      strings.TrimSpace(labels[LabelURL]),
+		Group:    strings.TrimSpace(labels[LabelGroup]),
+		Interval: defaultInterval,
+	}
+	if t.Name == "" {
+		t.Name = containerName
+	}
+	if t.Group == "" {
+		t.Group = DefaultGroup
+	}
+	if t.URL == "" {
+		t.URL = traefikURL(labels)
+	}
+	if t.URL == "" {
+		return t, true, fmt.Errorf("no %s label or Traefik router host", LabelURL)
+	}
+	if u, err := url.ParseRequestURI(t.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return t, true, fmt.Errorf("%s must be an http or https URL", LabelURL)
+	}
+	if v := labels[LabelInterval]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < minInterval {
+			return t, true, fmt.Errorf("%s must be at least %d seconds", LabelInterval, minInterval)
+		}
+		t.Interval = n
+	}
+	if len(t.Name) > maxNameLength {
+		return t, true, fmt.Errorf("%s must be at most %d characters", LabelName, maxNameLength)
+	}
+	for _, tag := range strings.Split(labels[LabelTags], ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(t.Tags, tag) {
+			continue
+		}
+		// Tags are stored comma-separated, so only the character set the API accepts is used
+		if len(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+			return t, true, fmt.Errorf("invalid tag %q: use up to %d characters of a-z, 0-9, _ . : / -", tag, maxTagLength)
+		}
+		t.Tags = append(t.Tags, tag)
+	}
+	if len(t.Tags) > maxTags {
+		return t, true, fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	return t, true, nil
+}
+
+// traefikURL returns the URL of the first Traefik router with a Host rule, https when
+// the router has TLS or listens on a websecure entrypoint.
+func traefikURL(labels map[string]string) string {
+	var routers []string
+	for k := range labels {
+		if r, ok := strings.CutPrefix(k, "traefik.http.routers."); ok && strings.HasSuffix(r, ".rule") {
+			routers = append(routers, strings.TrimSuffix(r, ".rule"))
+		}
+	}
+	slices.Sort(routers)
+	for _, r := range routers {
+		m := traefikRule.FindStringSubmatch(labels["traefik.http.routers."+r+".rule"])
+		if m == nil {
+			continue
+		}
+		prefix := "traefik.http.routers." + r + "."
+		scheme := "http"
+		if tls, _ := strconv.ParseBool(labels[prefix+"tls"]); tls || labels[prefix+"tls.certresolver"] != "" ||
+			strings.Contains(labels[prefix+"entrypoints"], "websecure") {
+			scheme = "https"
+		}
+		return scheme + "://" + m[1]
+	}
+	return ""
+}
+
+// container is the part of a Docker API container listing discovery uses.
+type container struct {
+	Names  []string          `json:"Names"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// Docker keeps the monitors of labelled containers in line with the daemon.
+type Docker struct {
+	store   *db.Store
+	manager Manager
+	client  *http.Client
+	baseURL string
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+
+	mu       sync.Mutex
+	warnings map[string]string // Last label error per container, so it is logged once
+
+	skipped map[string]string // Targets whose name is taken, only touched by poll
+}
+
+// NewDocker connects to the daemon at host: unix:///path/to/docker.sock, or
+// tcp://host:port for a daemon (or socket proxy) on the network.
+func NewDocker(store *db.Store, manager Manager, host string) (*Docker, error) {
+	d := &Docker{store: store, manager: manager, stopCh: make(chan struct{}), warnings: make(map[string]string), skipped: make(map[string]string)}
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		socket := strings.TrimPrefix(host, "unix://")
+		dialer := &net.Dialer{}
+		d.client = &http.Client{Timeout: requestTimeout, Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}}
+		d.baseURL = "http://docker"
+	case strings.HasPrefix(host, "tcp://"), strings.HasPrefix(host, "http://"):
+		d.client = &http.Client{Timeout: requestTimeout}
+		d.baseURL = "http://" + strings.TrimPrefix(strings.TrimPrefix(host, "tcp://"), "http://")
+	default:
+		return nil, fmt.Errorf("unsupported Docker host %q (use unix:// or tcp://)", host)
+	}
+	return d, nil
+}
+
+func (d *Docker) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			if d.manager.IsLeader() {
+				d.poll()
+			}
+			select {
+			case <-d.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (d *Docker) Stop() {
+	close(d.stopCh)
+	d.wg.Wait()
+}
+
+func (d *Docker) poll() {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	containers, err := d.listContainers(ctx)
+	if err != nil {
+		// Leave the monitors alone: an unreachable daemon is not every container gone
+		log.Printf("Docker discovery: failed to list containers: %v", err)
+		return
+	}
+
+	var targets []Target
+	d.mu.Lock()
+	for _, c := range containers {
+		if len(c.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(c.Names[0], "/")
+		t, ok, err := TargetFromLabels(name, c.Labels)
+		if !ok {
+			continue
+		}
+		if err != nil {
+			if d.warnings[name] != err.Error() {
+				log.Printf("Docker discovery: container %s: %v", name, err)
+				d.warnings[name] = err.Error()
+			}
+			continue
+		}
+		delete(d.warnings, name)
+		targets = append(targets, t)
+	}
+	d.mu.Unlock()
+
+	if err := Reconcile(d.store, d.manager, ProviderDocker, targets, d.skipped); err != nil {
+		log.Printf("Docker discovery: %v", err)
+	}
+}
+
+// listContainers lists the containers that opted in, stopped ones included so their
+// monitors report them down instead of disappearing.
+func (d *Docker) listContainers(ctx context.Context) ([]container, error) {
+	filters, _ := json.Marshal(map[string][]string{"label": {LabelEnable}})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.baseURL+"/containers/json?all=true&filters="+url.QueryEscape(string(filters)), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req) // #nosec G704 -- Docker host from the operator's environment
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var containers []container
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// Reconcile makes the monitors a provider manages match targets: missing ones are
+// created, changed ones updated and those whose target is gone deleted. Monitors the
+// provider did not create are never touched; a target whose name is taken by one is
+// skipped. skipped maps the keys of those targets to the taken name, so each is
+// logged once until its name changes.
+func Reconcile(store *db.Store, manager Manager, provider string, targets []Target, skipped map[string]string) error {
+	existing, err := store.GetDiscoveredMonitors(provider)
+	if err != nil {
+		return fmt.Errorf("failed to load discovered monitors: %w", err)
+	}
+	groups, err := groupIDs(store)
+	if err != nil {
+		return fmt.Errorf("failed to load groups: %w", err)
+	}
+
+	changed := false
+	wanted := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		wanted[t.Key] = true
+		groupID, err := ensureGroup(store, groups, t.Group)
+		if err != nil {
+			log.Printf("Discovery: failed to create group %q: %v", t.Group, err)
+			continue
+		}
+
+		id, ok := existing[t.Key]
+		if !ok {
+			m := db.Monitor{
+				ID:       "m-" + provider + "-" + randomHex(6),
+				GroupID:  groupID,
+				Name:     t.Name,
+				URL:      t.URL,
+				Type:     db.MonitorTypeHTTP,
+				Active:   true,
+				Interval: t.Interval,
+				Tags:     t.Tags,
+			}
+			if err := store.CreateDiscoveredMonitor(provider, t.Key, m); err != nil {
+				if errors.Is(err, db.ErrDuplicateMonitorName) {
+					skip(skipped, t)
+					continue
+				}
+				log.Printf("Discovery: failed to create monitor %q for %s: %v", t.Name, t.Key, err)
+				continue
+			}
+			delete(skipped, t.Key)
+			log.Printf("Discovery: monitoring %s as %q", t.Key, t.Name)
+			changed = true
+			continue
+		}
+
+		updated, err := updateMonitor(store, id, groupID, t)
+		if errors.Is(err, db.ErrDuplicateMonitorName) {
+			skip(skipped, t)
+			continue
+		}
+		delete(skipped, t.Key)
+		if err != nil {
+			log.Printf("Discovery: failed to update monitor %s for %s: %v", id, t.Key, err)
+			continue
+		}
+		changed = changed || updated
+	}
+
+	for key := range skipped {
+		if !wanted[key] {
+			delete(skipped, key)
+		}
+	}
+	for key, id := range existing {
+		if wanted[key] {
+			continue
+		}
+		if err := store.DeleteMonitor(id); err != nil {
+			log.Printf("Discovery: failed to delete monitor %s: %v", id, err)
+			continue
+		}
+		manager.RemoveMonitor(id)
+		log.Printf("Discovery: %s is gone, deleted its monitor", key)
+		changed = true
+	}
+
+	if changed {
+		manager.Sync()
+	}
+	return nil
+}
+
+// skip notes a target left out because another monitor has its name, logging it
+// the first time.
+func skip(skipped map[string]string, t Target) {
+	if skipped[t.Key] == t.Name {
+		return
+	}
+	log.Printf("Discovery: skipping %s, a monitor named %q already exists", t.Key, t.Name)
+	skipped[t.Key] = t.Name
+}
+
+// updateMonitor brings a discovered monitor in line with its target, keeping the
+// settings labels don't cover.
+func updateMonitor(store *db.Store, id, groupID string, t Target) (bool, error) {
+	m, err := store.GetMonitor(id)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	if m.Name != t.Name || m.URL != t.URL || m.Interval != t.Interval {
+		if err := store.UpdateMonitor(id, t.Name, t.URL, t.Interval, m.ConfirmationThreshold, m.NotificationCooldownMin, m.LatencyThreshold, m.RequestConfig); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if m.GroupID != groupID {
+		if err := store.SetMonitorGroup(id, groupID); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if !slices.Equal(m.Tags, t.Tags) {
+		if err := store.SetMonitorTags(id, t.Tags); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// groupIDs maps group names to IDs.
+func groupIDs(store *db.Store) (map[string]string, error) {
+	groups, err := store.GetGroups()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(groups))
+	for _, g := range groups {
+		ids[g.Name] = g.ID
+	}
+	return ids, nil
+}
+
+// ensureGroup returns the ID of the named group, creating it if needed.
+func ensureGroup(store *db.Store, groups map[string]string, name string) (string, error) {
+	if id, ok := groups[name]; ok {
+		return id, nil
+	}
+	id := "g-" + randomHex(6)
+	if err := store.CreateGroup(db.Group{ID: id, Name: name}); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			return "", fmt.Errorf("group ID %s is taken", id)
+		}
+		return "", err
+	}
+	groups[name] = id
+	return id, nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,232 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestTargetFromLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		ok     bool
+		err    bool
+		want   Target
+	}{
+		{name: "not enabled", labels: map[string]string{LabelURL: "http://app"}},
+		{name: "disabled", labels: map[string]string{LabelEnable: "false", LabelURL: "http://app"}},
+		{
+			name:   "defaults",
+			labels: map[string]string{LabelEnable: "true", LabelURL: "http://app:8080/health"},
+			ok:     true,
+			want:   Target{Key: "app", Name: "app", URL: "http://app:8080/health", Group: DefaultGroup, Interval: defaultInterval},
+		},
+		{
+			name: "all labels",
+			labels: map[string]string{
+				LabelEnable: "true", LabelURL: "https://photos.example.com", LabelName: "Photos",
+				LabelGroup: "Media", LabelInterval: "30", LabelTags: "Media, home,media",
+			},
+			ok:   true,
+			want: Target{Key: "app", Name: "Photos", URL: "https://photos.example.com", Group: "Media", Interval: 30, Tags: []string{"media", "home"}},
+		},
+		{
+			name: "traefik router",
+			labels: map[string]string{
+				LabelEnable:                                 "true",
+				"traefik.http.routers.app.rule":             "Host(`app.example.com`) && PathPrefix(`/`)",
+				"traefik.http.routers.app.entrypoints":      "websecure",
+				"traefik.http.routers.app-http.entrypoints": "web",
+			},
+			ok:   true,
+			want: Target{Key: "app", Name: "app", URL: "https://app.example.com", Group: DefaultGroup, Interval: defaultInterval},
+		},
+		{
+			name:   "traefik router without TLS",
+			labels: map[string]string{LabelEnable: "true", "traefik.http.routers.app.rule": "Host(`app.lan`)"},
+			ok:     true,
+			want:   Target{Key: "app", Name: "app", URL: "http://app.lan", Group: DefaultGroup, Interval: defaultInterval},
+		},
+		{name: "no url", labels: map[string]string{LabelEnable: "true"}, ok: true, err: true},
+		{name: "not http", labels: map[string]string{LabelEnable: "true", LabelURL: "ftp://app"}, ok: true, err: true},
+		{name: "interval too short", labels: map[string]string{LabelEnable: "true", LabelURL: "http://app", LabelInterval: "5"}, ok: true, err: true},
+		{name: "invalid tag", labels: map[string]string{LabelEnable: "true", LabelURL: "http://app", LabelTags: "a b"}, ok: true, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := TargetFromLabels("app", tt.labels)
+			if ok != tt.ok || (err != nil) != tt.err {
+				t.Fatalf("Expected ok=%v err=%v, got ok=%v err=%v", tt.ok, tt.err, ok, err)
+			}
+			if !tt.ok || tt.err {
+				return
+			}
+			if got.Key != tt.want.Key || got.Name != tt.want.Name || got.URL != tt.want.URL || got.Group != tt.want.Group ||
+				got.Interval != tt.want.Interval || !slices.Equal(got.Tags, tt.want.Tags) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+type fakeManager struct {
+	mu      sync.Mutex
+	syncs   int
+	removed []string
+}
+
+func (m *fakeManager) Sync() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syncs++
+}
+
+func (m *fakeManager) RemoveMonitor(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removed = append(m.removed, id)
+}
+
+func (m *fakeManager) IsLeader() bool { return true }
+
+// fakeDaemon serves a container listing like the Docker API.
+type fakeDaemon struct {
+	mu         sync.Mutex
+	containers []container
+	status     int
+}
+
+func (d *fakeDaemon) set(status int, containers ...container) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status = status
+	d.containers = containers
+}
+
+func newFakeDaemon(t *testing.T) (*fakeDaemon, string) {
+	t.Helper()
+	d := &fakeDaemon{status: http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/json" || r.URL.Query().Get("all") != "true" ||
+			!strings.Contains(r.URL.Query().Get("filters"), LabelEnable) {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		w.WriteHeader(d.status)
+		_ = json.NewEncoder(w).Encode(d.containers)
+	}))
+	t.Cleanup(srv.Close)
+	return d, "tcp://" + strings.TrimPrefix(srv.URL, "http://")
+}
+
+func labelled(name, url string, extra ...string) container {
+	labels := map[string]string{LabelEnable: "true", LabelURL: url}
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels[extra[i]] = extra[i+1]
+	}
+	return container{Names: []string{"/" + name}, Labels: labels}
+}
+
+func TestDockerPoll(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	daemon, host := newFakeDaemon(t)
+	manager := &fakeManager{}
+	d, err := NewDocker(store, manager, host)
+	if err != nil {
+		t.Fatalf("NewDocker failed: %v", err)
+	}
+	// A monitor created by hand is never touched
+	if err := store.CreateMonitor(db.Monitor{ID: "m-manual", GroupID: "g-default", Name: "Manual", URL: "http://manual", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	daemon.set(http.StatusOK,
+		labelled("photos", "http://photos:2342", LabelTags, "media"),
+		labelled("wiki", "http://wiki", LabelGroup, "Docs"),
+		labelled("manual", "http://other", LabelName, "Manual"),
+		labelled("broken", "not a url"),
+	)
+	d.poll()
+
+	discovered, _ := store.GetDiscoveredMonitors(ProviderDocker)
+	if len(discovered) != 2 || discovered["photos"] == "" || discovered["wiki"] == "" {
+		t.Fatalf("Expected photos and wiki to be discovered, got %v", discovered)
+	}
+	photos, _ := store.GetMonitor(discovered["photos"])
+	wiki, _ := store.GetMonitor(discovered["wiki"])
+	groups, _ := groupIDs(store)
+	if photos.URL != "http://photos:2342" || photos.GroupID != groups[DefaultGroup] || !slices.Equal(photos.Tags, []string{"media"}) {
+		t.Errorf("Unexpected photos monitor %+v", photos)
+	}
+	if wiki.GroupID != groups["Docs"] || groups["Docs"] == "" {
+		t.Errorf("Expected wiki in a new Docs group, got %+v (groups %v)", wiki, groups)
+	}
+	if manager.syncs != 1 {
+		t.Errorf("Expected one sync, got %d", manager.syncs)
+	}
+
+	// Nothing changed: nothing is written, and the taken name is not logged again
+	d.poll()
+	if manager.syncs != 1 {
+		t.Errorf("Expected no sync without changes, got %d", manager.syncs)
+	}
+	if n := strings.Count(logs.String(), "skipping manual"); n != 1 {
+		t.Errorf("Expected the taken name to be logged once, got %d in %q", n, logs.String())
+	}
+	if strings.Contains(logs.String(), "failed to create") {
+		t.Errorf("Expected the taken name not to be logged as a failure, got %q", logs.String())
+	}
+
+	// Labels changed and a container went away
+	daemon.set(http.StatusOK, labelled("photos", "https://photos.example.com", LabelName, "Photos", LabelInterval, "30"))
+	d.poll()
+	photos, _ = store.GetMonitor(discovered["photos"])
+	if photos.Name != "Photos" || photos.URL != "https://photos.example.com" || photos.Interval != 30 || len(photos.Tags) != 0 {
+		t.Errorf("Expected photos to follow its labels, got %+v", photos)
+	}
+	if _, err := store.GetMonitor(discovered["wiki"]); err == nil {
+		t.Error("Expected the wiki monitor to be deleted")
+	}
+	if !slices.Equal(manager.removed, []string{discovered["wiki"]}) {
+		t.Errorf("Expected the wiki monitor to be stopped, got %v", manager.removed)
+	}
+
+	// An unreachable daemon deletes nothing
+	daemon.set(http.StatusInternalServerError)
+	d.poll()
+	if _, err := store.GetMonitor(discovered["photos"]); err != nil {
+		t.Errorf("Expected photos to survive a daemon error, got %v", err)
+	}
+	if _, err := store.GetMonitor("m-manual"); err != nil {
+		t.Errorf("Expected the manual monitor to be untouched, got %v", err)
+	}
+}
+
+func TestNewDockerHost(t *testing.T) {
+	for _, host := range []string{"unix:///var/run/docker.sock", "tcp://127.0.0.1:2375", "http://proxy:2375"} {
+		if _, err := NewDocker(nil, nil, host); err != nil {
+			t.Errorf("Expected %s to be accepted, got %v", host, err)
+		}
+	}
+	if _, err := NewDocker(nil, nil, "ssh://user@host"); err == nil {
+		t.Error("Expected ssh:// to be rejected")
+	}
+}
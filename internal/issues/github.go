@@ -0,0 +1,62 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// githubTracker opens issues in a GitHub (or GitHub Enterprise) repository, given as
+// owner/name, with a token allowed to write issues.
+type githubTracker struct {
+	apiURL string
+	repo   string
+	token  string
+	labels []string
+}
+
+func (t githubTracker) create(ctx context.Context, title, body string) (Issue, error) {
+	payload := map[string]any{"title": title, "body": body}
+	if len(t.labels) > 0 {
+		payload["labels"] = t.labels
+	}
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := postJSON(ctx, t.apiURL+"/repos/"+t.repo+"/issues", payload, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}, &created)
+	if err != nil {
+		return Issue{}, err
+	}
+	return Issue{Key: t.repo + "#" + strconv.Itoa(created.Number), URL: created.HTMLURL}, nil
+}
+
+// githubWebhook is the part of a GitHub "issues" webhook that tells whether the issue
+// was closed.
+type githubWebhook struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number int `json:"number"`
+	} `json:"issue"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func parseGitHubWebhook(body []byte) (Update, error) {
+	var hook githubWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return Update{}, err
+	}
+	if hook.Issue.Number == 0 || hook.Repository.FullName == "" {
+		return Update{}, nil
+	}
+	return Update{
+		Key:    hook.Repository.FullName + "#" + strconv.Itoa(hook.Issue.Number),
+		Closed: hook.Action == "closed",
+	}, nil
+}
@@ -0,0 +1,263 @@
+// Package issues opens a Jira or GitHub issue for every new incident, and resolves the
+// incident when its issue is closed.
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Issue tracker settings. An empty provider disables the integration.
+const (
+	ProviderKey      = "integrations.issues.provider"
+	TitleTemplateKey = "integrations.issues.title_template"
+	BodyTemplateKey  = "integrations.issues.body_template"
+	WebhookSecretKey = "integrations.issues.webhook_secret"
+
+	JiraBaseURLKey   = "integrations.jira.base_url"
+	JiraEmailKey     = "integrations.jira.email"
+	JiraTokenKey     = "integrations.jira.api_token"
+	JiraProjectKey   = "integrations.jira.project_key"
+	JiraIssueTypeKey = "integrations.jira.issue_type"
+
+	GitHubRepoKey   = "integrations.github.repo"
+	GitHubTokenKey  = "integrations.github.token"
+	GitHubLabelsKey = "integrations.github.labels"
+	GitHubAPIURLKey = "integrations.github.api_url"
+)
+
+// Providers and setting defaults.
+const (
+	ProviderJira   = "jira"
+	ProviderGitHub = "github"
+
+	DefaultTitleTemplate = "[{{.Severity}}] {{.Title}}"
+	DefaultBodyTemplate  = "{{.Description}}\n\nSeverity: {{.Severity}}\nStatus: {{.Status}}\nStarted: {{.StartTime.Format \"2006-01-02 15:04 MST\"}}\n{{if .AffectedGroups}}Affected: {{join .AffectedGroups \", \"}}\n{{end}}Warden incident: {{.ID}}"
+	DefaultJiraIssueType = "Task"
+	DefaultGitHubAPIURL  = "https://api.github.com"
+	DefaultGitHubLabels  = "incident"
+
+	requestTimeout = 15 * time.Second
+)
+
+// TemplateData is what the title and body templates are rendered with.
+type TemplateData struct {
+	ID             string
+	Title          string
+	Description    string
+	Severity       string
+	Status         string
+	Source         string // auto | manual
+	StartTime      time.Time
+	AffectedGroups []string // Group names
+}
+
+var templateFuncs = template.FuncMap{"join": strings.Join}
+
+// ValidateTemplate reports whether text is a template that renders for an incident.
+func ValidateTemplate(text string) error {
+	tmpl, err := template.New("issue").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	sample := TemplateData{ID: "inc-1", Title: "API down", Severity: "major", Status: "investigating", StartTime: time.Now(), AffectedGroups: []string{"Production"}}
+	return tmpl.Execute(io.Discard, sample)
+}
+
+func render(text string, data TemplateData) (string, error) {
+	tmpl, err := template.New("issue").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Issue is an issue opened in a tracker: its key, e.g. OPS-123 or owner/repo#45, and
+// the page showing it.
+type Issue struct {
+	Key string
+	URL string
+}
+
+type tracker interface {
+	create(ctx context.Context, title, body string) (Issue, error)
+}
+
+// transport carries every request to a tracker; nil uses the default transport.
+var transport http.RoundTripper
+
+// SetTransport sends tracker requests through rt instead of the network, as demo mode
+// does. Call before Start.
+func SetTransport(rt http.RoundTripper) {
+	transport = rt
+}
+
+// Service opens an issue for every incident created through the store.
+type Service struct {
+	store *db.Store
+}
+
+func NewService(store *db.Store) *Service {
+	return &Service{store: store}
+}
+
+// Start opens issues for the incidents created from now on, with the tracker configured
+// when each is created. Maintenance windows are left alone.
+func (s *Service) Start() {
+	s.store.OnIncidentCreate(func(incident db.Incident) {
+		if incident.Type != "incident" {
+			return
+		}
+		t, err := s.tracker()
+		if err != nil {
+			log.Printf("Issues: not opening an issue for incident %s: %v", incident.ID, err)
+			return
+		}
+		if t != nil {
+			go s.open(t, incident)
+		}
+	})
+}
+
+func (s *Service) setting(key, def string) string {
+	if val, err := s.store.GetSetting(key); err == nil && val != "" {
+		return val
+	}
+	return def
+}
+
+// tracker builds the configured tracker, or returns nil when the integration is off.
+func (s *Service) tracker() (tracker, error) {
+	switch provider := s.setting(ProviderKey, ""); provider {
+	case "":
+		return nil, nil
+	case ProviderJira:
+		t := jiraTracker{
+			baseURL:   strings.TrimRight(s.setting(JiraBaseURLKey, ""), "/"),
+			email:     s.setting(JiraEmailKey, ""),
+			token:     s.setting(JiraTokenKey, ""),
+			project:   s.setting(JiraProjectKey, ""),
+			issueType: s.setting(JiraIssueTypeKey, DefaultJiraIssueType),
+		}
+		if t.baseURL == "" || t.token == "" || t.project == "" {
+			return nil, errors.New("jira base URL, API token and project key are required")
+		}
+		return t, nil
+	case ProviderGitHub:
+		t := githubTracker{
+			apiURL: strings.TrimRight(s.setting(GitHubAPIURLKey, DefaultGitHubAPIURL), "/"),
+			repo:   s.setting(GitHubRepoKey, ""),
+			token:  s.setting(GitHubTokenKey, ""),
+		}
+		for _, l := range strings.Split(s.setting(GitHubLabelsKey, DefaultGitHubLabels), ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				t.labels = append(t.labels, l)
+			}
+		}
+		if t.repo == "" || t.token == "" {
+			return nil, errors.New("github repository and token are required")
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+// templateData describes the incident, with its affected groups resolved to names.
+func (s *Service) templateData(incident db.Incident) TemplateData {
+	data := TemplateData{
+		ID:          incident.ID,
+		Title:       incident.Title,
+		Description: incident.Description,
+		Severity:    incident.Severity,
+		Status:      incident.Status,
+		Source:      incident.Source,
+		StartTime:   incident.StartTime,
+	}
+	var ids []string
+	_ = json.Unmarshal([]byte(incident.AffectedGroups), &ids)
+	if len(ids) == 0 {
+		return data
+	}
+	names := make(map[string]string)
+	if groups, err := s.store.GetGroups(); err == nil {
+		for _, g := range groups {
+			names[g.ID] = g.Name
+		}
+	}
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			data.AffectedGroups = append(data.AffectedGroups, name)
+		}
+	}
+	return data
+}
+
+// open creates the incident's issue and links it to the incident.
+func (s *Service) open(t tracker, incident db.Incident) {
+	data := s.templateData(incident)
+	title, err := render(s.setting(TitleTemplateKey, DefaultTitleTemplate), data)
+	if err != nil {
+		log.Printf("Issues: invalid title template: %v", err)
+		return
+	}
+	body, err := render(s.setting(BodyTemplateKey, DefaultBodyTemplate), data)
+	if err != nil {
+		log.Printf("Issues: invalid body template: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	issue, err := t.create(ctx, title, body)
+	if err != nil {
+		log.Printf("Issues: failed to open an issue for incident %s: %v", incident.ID, err)
+		return
+	}
+	if err := s.store.SetIncidentIssue(incident.ID, issue.Key, issue.URL); err != nil {
+		log.Printf("Issues: failed to link issue %s to incident %s: %v", issue.Key, incident.ID, err)
+		return
+	}
+	log.Printf("Issues: opened %s for incident %s", issue.Key, incident.ID)
+}
+
+// postJSON sends payload to a tracker API and decodes its answer into out.
+func postJSON(ctx context.Context, url string, payload any, auth func(*http.Request), out any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	auth(req)
+
+	resp, err := (&http.Client{Transport: transport}).Do(req) // #nosec G704 -- URL from admin settings
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body[:min(len(body), 512)])))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,216 @@
+package issues
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func newTestStore(t *testing.T) *db.Store {
+	t.Helper()
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func waitForIssue(t *testing.T, store *db.Store, id string) *db.Incident {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		inc, err := store.GetIncidentByID(id)
+		if err != nil {
+			t.Fatalf("GetIncidentByID failed: %v", err)
+		}
+		if inc.IssueKey != "" {
+			return inc
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("Issue was never linked to the incident")
+	return nil
+}
+
+func TestService_OpensGitHubIssue(t *testing.T) {
+	var got struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels"`
+	}
+	var auth, path string
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, path = r.Header.Get("Authorization"), r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 45, "html_url": "https://github.com/acme/ops/issues/45"}`))
+	}))
+	defer gh.Close()
+
+	store := newTestStore(t)
+	_ = store.SetSetting(ProviderKey, ProviderGitHub)
+	_ = store.SetSetting(GitHubAPIURLKey, gh.URL)
+	_ = store.SetSetting(GitHubRepoKey, "acme/ops")
+	_ = store.SetSetting(GitHubTokenKey, "ghp_test")
+	_ = store.SetSetting(GitHubLabelsKey, "incident, sev")
+	NewService(store).Start()
+
+	if err := store.CreateIncident(db.Incident{
+		ID: "inc-1", Title: "API down", Description: "Requests fail", Type: "incident", Severity: "major",
+		Status: "investigating", StartTime: time.Now(), AffectedGroups: `["g-default"]`,
+	}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	inc := waitForIssue(t, store, "inc-1")
+	if inc.IssueKey != "acme/ops#45" || inc.IssueURL != "https://github.com/acme/ops/issues/45" {
+		t.Errorf("Unexpected issue link %q %q", inc.IssueKey, inc.IssueURL)
+	}
+	if path != "/repos/acme/ops/issues" || auth != "Bearer ghp_test" {
+		t.Errorf("Unexpected request to %s with %q", path, auth)
+	}
+	if got.Title != "[major] API down" {
+		t.Errorf("Unexpected title %q", got.Title)
+	}
+	if !strings.Contains(got.Body, "Requests fail") || !strings.Contains(got.Body, "Affected: Default") || !strings.Contains(got.Body, "inc-1") {
+		t.Errorf("Unexpected body %q", got.Body)
+	}
+	if strings.Join(got.Labels, ",") != "incident,sev" {
+		t.Errorf("Unexpected labels %v", got.Labels)
+	}
+}
+
+func TestService_OpensJiraIssue(t *testing.T) {
+	var summary, user, pass string
+	jira := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ = r.BasicAuth()
+		var req struct {
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		summary = req.Fields.Summary
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "10001", "key": "OPS-7"}`))
+	}))
+	defer jira.Close()
+
+	store := newTestStore(t)
+	_ = store.SetSetting(ProviderKey, ProviderJira)
+	_ = store.SetSetting(JiraBaseURLKey, jira.URL+"/")
+	_ = store.SetSetting(JiraEmailKey, "ops@example.com")
+	_ = store.SetSetting(JiraTokenKey, "jira-token")
+	_ = store.SetSetting(JiraProjectKey, "OPS")
+	_ = store.SetSetting(TitleTemplateKey, "Warden: {{.Title}} ({{.Source}})")
+	NewService(store).Start()
+
+	if err := store.CreateIncident(db.Incident{ID: "inc-2", Title: "Checkout slow", Type: "incident", Severity: "minor", Status: "investigating", StartTime: time.Now()}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	inc := waitForIssue(t, store, "inc-2")
+	if inc.IssueKey != "OPS-7" || inc.IssueURL != jira.URL+"/browse/OPS-7" {
+		t.Errorf("Unexpected issue link %q %q", inc.IssueKey, inc.IssueURL)
+	}
+	if user != "ops@example.com" || pass != "jira-token" {
+		t.Errorf("Unexpected credentials %q %q", user, pass)
+	}
+	if summary != "Warden: Checkout slow (manual)" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+}
+
+func TestService_SkipsMaintenanceAndDisabled(t *testing.T) {
+	var calls atomic.Int32
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"number": 1, "html_url": "https://github.com/acme/ops/issues/1"}`))
+	}))
+	defer gh.Close()
+
+	store := newTestStore(t)
+	_ = store.SetSetting(GitHubAPIURLKey, gh.URL)
+	_ = store.SetSetting(GitHubRepoKey, "acme/ops")
+	_ = store.SetSetting(GitHubTokenKey, "ghp_test")
+	NewService(store).Start()
+
+	// No provider configured
+	_ = store.CreateIncident(db.Incident{ID: "inc-off", Title: "Off", Type: "incident", Status: "investigating", StartTime: time.Now()})
+
+	_ = store.SetSetting(ProviderKey, ProviderGitHub)
+	_ = store.CreateIncident(db.Incident{ID: "maint-1", Title: "Upgrade", Type: "maintenance", Status: "scheduled", StartTime: time.Now()})
+
+	time.Sleep(200 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Expected no issues, got %d", n)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	for _, ok := range []string{DefaultTitleTemplate, DefaultBodyTemplate, "{{.Title}} in {{join .AffectedGroups \", \"}}"} {
+		if err := ValidateTemplate(ok); err != nil {
+			t.Errorf("ValidateTemplate(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"{{.Title", "{{.Nope}}"} {
+		if err := ValidateTemplate(bad); err == nil {
+			t.Errorf("Expected ValidateTemplate(%q) to fail", bad)
+		}
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	cases := []struct {
+		provider string
+		body     string
+		want     Update
+	}{
+		{ProviderGitHub, `{"action":"closed","issue":{"number":45},"repository":{"full_name":"acme/ops"}}`, Update{Key: "acme/ops#45", Closed: true}},
+		{ProviderGitHub, `{"action":"labeled","issue":{"number":45},"repository":{"full_name":"acme/ops"}}`, Update{Key: "acme/ops#45"}},
+		{ProviderGitHub, `{"zen":"Keep it logically awesome.","hook_id":1}`, Update{}},
+		{ProviderJira, `{"webhookEvent":"jira:issue_updated","issue":{"key":"OPS-7","fields":{"status":{"statusCategory":{"key":"done"}}}}}`, Update{Key: "OPS-7", Closed: true}},
+		{ProviderJira, `{"webhookEvent":"jira:issue_updated","issue":{"key":"OPS-7","fields":{"status":{"statusCategory":{"key":"indeterminate"}}}}}`, Update{Key: "OPS-7"}},
+	}
+	for _, c := range cases {
+		got, err := ParseWebhook(c.provider, []byte(c.body))
+		if err != nil {
+			t.Errorf("ParseWebhook(%s, %s) failed: %v", c.provider, c.body, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseWebhook(%s, %s) = %+v, want %+v", c.provider, c.body, got, c.want)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"action":"closed"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	sig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, name := range []string{"X-Hub-Signature-256", "X-Hub-Signature"} {
+		h := http.Header{}
+		h.Set(name, sig)
+		if !VerifySignature("s3cret", body, h) {
+			t.Errorf("Expected a valid signature in %s", name)
+		}
+		if VerifySignature("other", body, h) {
+			t.Errorf("Expected a signature with another secret to fail")
+		}
+	}
+	if VerifySignature("s3cret", body, http.Header{}) {
+		t.Error("Expected a missing signature to fail")
+	}
+}
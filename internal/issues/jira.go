@@ -0,0 +1,64 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// jiraTracker opens issues through the Jira REST API (v2, which takes a plain-text
+// description), authenticated with an account email and API token.
+type jiraTracker struct {
+	baseURL   string
+	email     string
+	token     string
+	project   string
+	issueType string
+}
+
+func (t jiraTracker) create(ctx context.Context, title, body string) (Issue, error) {
+	payload := map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": t.project},
+			"summary":     title,
+			"description": body,
+			"issuetype":   map[string]string{"name": t.issueType},
+		},
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	err := postJSON(ctx, t.baseURL+"/rest/api/2/issue", payload, func(req *http.Request) {
+		if t.email != "" {
+			req.SetBasicAuth(t.email, t.token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+t.token) // Data Center personal access token
+		}
+	}, &created)
+	if err != nil {
+		return Issue{}, err
+	}
+	return Issue{Key: created.Key, URL: t.baseURL + "/browse/" + created.Key}, nil
+}
+
+// jiraWebhook is the part of a Jira issue webhook that tells whether the issue is done.
+type jiraWebhook struct {
+	Issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"` // new | indeterminate | done
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue"`
+}
+
+func parseJiraWebhook(body []byte) (Update, error) {
+	var hook jiraWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return Update{}, err
+	}
+	return Update{Key: hook.Issue.Key, Closed: hook.Issue.Fields.Status.StatusCategory.Key == "done"}, nil
+}
@@ -0,0 +1,50 @@
+package issues
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Update is what a tracker webhook reports about an issue. An empty Key means the
+// event is not about an issue.
+type Update struct {
+	Key    string
+	Closed bool
+}
+
+// ParseWebhook reads an issue event sent by the provider's webhook.
+func ParseWebhook(provider string, body []byte) (Update, error) {
+	switch provider {
+	case ProviderJira:
+		return parseJiraWebhook(body)
+	case ProviderGitHub:
+		return parseGitHubWebhook(body)
+	default:
+		return Update{}, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+// VerifySignature reports whether the webhook body was signed with secret, as an
+// HMAC-SHA256 in X-Hub-Signature-256 (GitHub) or X-Hub-Signature (Jira), formatted
+// sha256=<hex>.
+func VerifySignature(secret string, body []byte, header http.Header) bool {
+	sig := header.Get("X-Hub-Signature-256")
+	if sig == "" {
+		sig = header.Get("X-Hub-Signature")
+	}
+	hexSig, ok := strings.CutPrefix(sig, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
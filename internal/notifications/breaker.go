@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// BreakerFailureThreshold is how many consecutive failed sends open a channel's
+	// circuit. While it is open the channel is skipped instead of holding up delivery
+	// with timeouts.
+	BreakerFailureThreshold = 3
+	// BreakerCooldown is how long an open circuit skips its channel before letting
+	// one trial send through.
+	BreakerCooldown = time.Minute
+	// BreakerDisableAfter is how long a channel may keep failing before it is
+	// disabled, and the other channels are alerted.
+	BreakerDisableAfter = time.Hour
+)
+
+// breakerState tracks the current failure streak of one channel.
+type breakerState struct {
+	failures     int       // consecutive failed sends
+	failingSince time.Time // first failure of the streak
+	openUntil    time.Time // while the circuit is open, skip sends until then
+}
+
+// channelBreakers is a circuit breaker per notification channel, keyed by channel ID.
+type channelBreakers struct {
+	mu     sync.Mutex
+	states map[string]*breakerState
+}
+
+func newChannelBreakers() *channelBreakers {
+	return &channelBreakers{states: make(map[string]*breakerState)}
+}
+
+// allow reports whether a send to the channel may go ahead: always while its circuit
+// is closed, and once per cooldown while it is open.
+func (b *channelBreakers) allow(id string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.states[id]
+	if st == nil || st.failures < BreakerFailureThreshold {
+		return true
+	}
+	if now.Before(st.openUntil) {
+		return false
+	}
+	// Trial send; further sends wait for its outcome or the next cooldown
+	st.openUntil = now.Add(BreakerCooldown)
+	return true
+}
+
+// success closes the channel's circuit.
+func (b *channelBreakers) success(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.states, id)
+}
+
+// failure records a failed send. It reports whether this failure opened the circuit,
+// and for how long the channel has been failing.
+func (b *channelBreakers) failure(id string, now time.Time) (opened bool, failingFor time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := b.states[id]
+	if st == nil {
+		st = &breakerState{failingSince: now}
+		b.states[id] = st
+	}
+	st.failures++
+	if st.failures >= BreakerFailureThreshold {
+		st.openUntil = now.Add(BreakerCooldown)
+	}
+	return st.failures == BreakerFailureThreshold, now.Sub(st.failingSince)
+}
@@ -0,0 +1,195 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestChannelBreakers(t *testing.T) {
+	b := newChannelBreakers()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i < BreakerFailureThreshold; i++ {
+		if opened, _ := b.failure("c1", now); opened {
+			t.Fatalf("circuit opened after %d failures", i)
+		}
+		if !b.allow("c1", now) {
+			t.Fatalf("send blocked after %d failures", i)
+		}
+	}
+	opened, _ := b.failure("c1", now)
+	if !opened {
+		t.Fatal("expected circuit to open at the threshold")
+	}
+	if b.allow("c1", now.Add(BreakerCooldown/2)) {
+		t.Error("expected sends to be skipped during the cooldown")
+	}
+	if !b.allow("other", now) {
+		t.Error("other channels must not be affected")
+	}
+
+	// One trial send per cooldown
+	later := now.Add(BreakerCooldown)
+	if !b.allow("c1", later) {
+		t.Fatal("expected a trial send after the cooldown")
+	}
+	if b.allow("c1", later) {
+		t.Error("expected only one trial send per cooldown")
+	}
+
+	_, failingFor := b.failure("c1", later)
+	if failingFor != BreakerCooldown {
+		t.Errorf("failingFor = %s, want %s", failingFor, BreakerCooldown)
+	}
+
+	b.success("c1")
+	if !b.allow("c1", later) {
+		t.Error("expected success to close the circuit")
+	}
+	if _, failingFor := b.failure("c1", later); failingFor != 0 {
+		t.Errorf("expected a new failure streak, failingFor = %s", failingFor)
+	}
+}
+
+func TestService_DisablesFailingChannel(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+
+	var mu sync.Mutex
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+
+	var failingHits atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingHits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	received := make(chan map[string]interface{}, 10)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	}))
+	defer healthy.Close()
+
+	for _, ch := range []db.NotificationChannel{
+		{ID: "bad", Type: "webhook", Name: "Broken", Config: `{"webhookUrl":"` + failing.URL + `"}`, Enabled: true, CreatedAt: now},
+		{ID: "good", Type: "webhook", Name: "Working", Config: `{"webhookUrl":"` + healthy.URL + `"}`, Enabled: true, CreatedAt: now},
+	} {
+		if err := store.CreateNotificationChannel(ch); err != nil {
+			t.Fatalf("CreateNotificationChannel: %v", err)
+		}
+	}
+	channels, err := store.GetNotificationChannels()
+	if err != nil {
+		t.Fatalf("GetNotificationChannels: %v", err)
+	}
+	var bad db.NotificationChannel
+	for _, ch := range channels {
+		if ch.ID == "bad" {
+			bad = ch
+		}
+	}
+
+	// Repeated failures open the circuit, so further sends are skipped
+	for i := 0; i < BreakerFailureThreshold+2; i++ {
+		svc.deliver(delivery{channel: bad, event: sampleEvent()})
+	}
+	if got := failingHits.Load(); got != BreakerFailureThreshold {
+		t.Errorf("expected %d requests before the circuit opened, got %d", BreakerFailureThreshold, got)
+	}
+
+	// Still failing an hour later: the channel is disabled and the others are alerted
+	advance(BreakerDisableAfter)
+	svc.Start()
+	svc.deliver(delivery{channel: bad, event: sampleEvent()})
+
+	channels, _ = store.GetNotificationChannels()
+	for _, ch := range channels {
+		if ch.ID == "bad" && (ch.Enabled || ch.DisabledReason == "") {
+			t.Errorf("expected channel to be disabled with a reason, got %+v", ch)
+		}
+		if ch.ID == "good" && !ch.Enabled {
+			t.Error("healthy channel must stay enabled")
+		}
+	}
+
+	select {
+	case payload := <-received:
+		if payload["event"] != string(EventChannelDisabled) || payload["monitorName"] != "Broken" {
+			t.Errorf("unexpected alert payload: %v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the healthy channel to be alerted")
+	}
+	if got := failingHits.Load(); got != BreakerFailureThreshold+1 {
+		t.Errorf("disabled channel must not be alerted about itself, got %d requests", got)
+	}
+}
+
+func TestService_DispatchDoesNotWaitForSlowChannel(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release) // unblock the handler before closing the server
+
+	received := make(chan struct{}, 10)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer fast.Close()
+
+	// Pick IDs that land on different delivery workers
+	slowID, fastID := "slow", ""
+	for i := 0; fastID == ""; i++ {
+		id := "fast" + string(rune('a'+i))
+		if shard(id) != shard(slowID) {
+			fastID = id
+		}
+	}
+	for _, ch := range []db.NotificationChannel{
+		{ID: slowID, Type: "webhook", Name: "Slow", Config: `{"webhookUrl":"` + slow.URL + `"}`, Enabled: true, CreatedAt: time.Now()},
+		{ID: fastID, Type: "webhook", Name: "Fast", Config: `{"webhookUrl":"` + fast.URL + `"}`, Enabled: true, CreatedAt: time.Now()},
+	} {
+		if err := store.CreateNotificationChannel(ch); err != nil {
+			t.Fatalf("CreateNotificationChannel: %v", err)
+		}
+	}
+
+	svc.Start()
+	first, second := sampleEvent(), sampleEvent()
+	second.Time = first.Time.Add(time.Minute)
+	svc.Enqueue(first)
+	svc.Enqueue(second)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("fast channel was held up by the slow one")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package notifications
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow describes a scheduled maintenance carried by an EventMaintenanceScheduled
+// or EventMaintenanceRescheduled notification, so channels can offer it as a calendar entry.
+type MaintenanceWindow struct {
+	ID          string
+	Title       string
+	Description string
+	Start       time.Time
+	End         time.Time
+	CalendarURL string // Optional link that downloads the window as an .ics file
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+// BuildICS renders the window as an iCalendar (RFC 5545) file with a single event.
+// now is used for DTSTAMP and SEQUENCE, so a later copy of a rescheduled window
+// replaces the event calendars imported before.
+func BuildICS(w MaintenanceWindow, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Project Helena//Warden//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:" + escapeICSText(w.ID) + "@warden",
+		"DTSTAMP:" + now.UTC().Format(icsTimeFormat),
+		"SEQUENCE:" + strconv.FormatInt(now.Unix(), 10),
+		"DTSTART:" + w.Start.UTC().Format(icsTimeFormat),
+		"DTEND:" + w.End.UTC().Format(icsTimeFormat),
+		"SUMMARY:" + escapeICSText("Maintenance: "+w.Title),
+	}
+	if w.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICSText(w.Description))
+	}
+	lines = append(lines,
+		"TRANSP:OPAQUE",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(foldICSLine(l))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// escapeICSText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return strings.ReplaceAll(s, "\r", `\n`)
+}
+
+// foldICSLine splits lines longer than 75 octets, continuing with a leading space,
+// without breaking a multi-byte UTF-8 character.
+func foldICSLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
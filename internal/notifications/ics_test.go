@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildICS(t *testing.T) {
+	start := time.Date(2026, 5, 1, 22, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	ics := BuildICS(MaintenanceWindow{
+		ID:          "inc-123",
+		Title:       "DB upgrade; phase 1",
+		Description: "Line one\nLine two, with comma",
+		Start:       start,
+		End:         start.Add(time.Hour),
+	}, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:inc-123@warden\r\n",
+		"DTSTAMP:20260401T000000Z\r\n",
+		"SEQUENCE:1775001600\r\n",
+		"DTSTART:20260501T200000Z\r\n",
+		"DTEND:20260501T210000Z\r\n",
+		`SUMMARY:Maintenance: DB upgrade\; phase 1` + "\r\n",
+		`DESCRIPTION:Line one\nLine two\, with comma` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 80)
+	folded := foldICSLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line exceeds 75 octets: %d", len(part))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Error("unfolding should restore the original line")
+	}
+}
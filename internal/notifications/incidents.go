@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// IncidentNotice describes the incident of an incident event and the status page it is
+// shown on.
+type IncidentNotice struct {
+	ID        string
+	Title     string
+	Status    string
+	Severity  string
+	PageSlug  string
+	PageTitle string
+	PageURL   string // Empty when the server's address is unknown
+}
+
+// EnqueueIncident tells each status page showing the public incident inc about it,
+// through the channels mapped to that page: one event per page, so a channel mapped to
+// several pages hears about each. Pages without channels, and private incidents, are
+// skipped. baseURL, when set, links the page.
+func (s *Service) EnqueueIncident(eventType EventType, inc db.Incident, message, baseURL string) {
+	if !inc.Public || inc.Type != "incident" {
+		return
+	}
+	mapping, err := s.store.GetAllStatusPageChannels()
+	if err != nil {
+		log.Printf("Failed to fetch status page channels: %v", err)
+		return
+	}
+	if len(mapping) == 0 {
+		return
+	}
+	pages, err := s.store.GetStatusPages()
+	if err != nil {
+		log.Printf("Failed to fetch status pages: %v", err)
+		return
+	}
+
+	now := s.now()
+	for _, page := range pages {
+		channelIDs := mapping[page.Slug]
+		if len(channelIDs) == 0 || !page.Enabled || !pageShows(page, inc) {
+			continue
+		}
+		notice := &IncidentNotice{
+			ID:        inc.ID,
+			Title:     inc.Title,
+			Status:    inc.Status,
+			Severity:  inc.Severity,
+			PageSlug:  page.Slug,
+			PageTitle: page.Title,
+		}
+		if baseURL != "" {
+			notice.PageURL = baseURL + "/status/" + url.PathEscape(page.Slug)
+		}
+		event := NotificationEvent{
+			MonitorID:   inc.ID,
+			MonitorName: inc.Title,
+			MonitorURL:  notice.PageURL,
+			Type:        eventType,
+			Message:     message,
+			Time:        now,
+			DedupKey:    fmt.Sprintf("%s:%s:%s:%d", eventType, inc.ID, page.Slug, now.UnixNano()),
+			Incident:    notice,
+		}
+		s.enqueue(event, func(ch db.NotificationChannel) bool {
+			return ch.MonitorID == "" && slices.Contains(channelIDs, ch.ID)
+		})
+	}
+}
+
+// pageShows reports whether a status page lists the incident: pages of every group show
+// them all, a group's page those affecting its group.
+func pageShows(page db.StatusPage, inc db.Incident) bool {
+	if page.GroupID == nil {
+		return true
+	}
+	var groups []string
+	if inc.AffectedGroups != "" {
+		_ = json.Unmarshal([]byte(inc.AffectedGroups), &groups)
+	}
+	return slices.Contains(groups, *page.GroupID)
+}
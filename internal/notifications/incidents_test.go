@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestEnqueueIncident(t *testing.T) {
+	store := newTestStore(t)
+	eu := "g-eu"
+	_ = store.CreateGroup(db.Group{ID: "g-eu", Name: "EU"})
+	_ = store.CreateStatusPage(db.StatusPageInput{Slug: "global", Title: "Everything", Enabled: true})
+	_ = store.CreateStatusPage(db.StatusPageInput{Slug: "eu", Title: "EU", GroupID: &eu, Enabled: true})
+	_ = store.CreateStatusPage(db.StatusPageInput{Slug: "us", Title: "US", Enabled: false})
+	for _, id := range []string{"nc-all", "nc-eu", "nc-us", "nc-other"} {
+		_ = store.CreateNotificationChannel(db.NotificationChannel{ID: id, Type: "webhook", Name: id, Config: "{}", Enabled: true})
+	}
+	_ = store.SetStatusPageChannels("global", []string{"nc-all", "nc-eu"})
+	_ = store.SetStatusPageChannels("eu", []string{"nc-eu"})
+	_ = store.SetStatusPageChannels("us", []string{"nc-us"})
+
+	s := NewService(store)
+	inc := db.Incident{ID: "inc-1", Title: "Outage", Type: "incident", Status: "investigating", AffectedGroups: `["g-other"]`, Public: true}
+	s.EnqueueIncident(EventIncidentOpened, inc, "Looking into it", "https://warden.example.com")
+	// Private incidents and maintenance windows are not announced this way
+	s.EnqueueIncident(EventIncidentOpened, db.Incident{ID: "inc-2", Type: "incident", Public: false}, "", "")
+	s.EnqueueIncident(EventIncidentOpened, db.Incident{ID: "inc-3", Type: "maintenance", Public: true}, "", "")
+
+	items, err := store.ClaimDueNotifications(time.Now().Add(time.Minute), time.Minute, 100)
+	if err != nil {
+		t.Fatalf("ClaimDueNotifications failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, item := range items {
+		var event NotificationEvent
+		_ = json.Unmarshal([]byte(item.Payload), &event)
+		got[item.ChannelID] = event.Incident.PageSlug + " " + event.Incident.PageURL
+	}
+	// Only the page of every group shows an incident of another group; the disabled page is skipped
+	want := map[string]string{
+		"nc-all": "global https://warden.example.com/status/global",
+		"nc-eu":  "global https://warden.example.com/status/global",
+	}
+	if len(got) != len(want) || got["nc-all"] != want["nc-all"] || got["nc-eu"] != want["nc-eu"] {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestWebhookNotifier_IncidentPayload(t *testing.T) {
+	var received map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer srv.Close()
+
+	event := NotificationEvent{
+		MonitorID:   "inc-1",
+		MonitorName: "Outage",
+		Type:        EventIncidentResolved,
+		Message:     "Fixed",
+		Time:        time.Now(),
+		Incident:    &IncidentNotice{ID: "inc-1", Title: "Outage", Status: "resolved", PageSlug: "eu", PageTitle: "EU", PageURL: "https://x/status/eu"},
+	}
+	if err := SendDirect("webhook", `{"webhookUrl":"`+srv.URL+`"}`, event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	inc, _ := received["incident"].(map[string]interface{})
+	if received["event"] != "incident_resolved" || inc["pageSlug"] != "eu" || inc["status"] != "resolved" {
+		t.Errorf("Unexpected payload %v", received)
+	}
+
+	var slack struct {
+		Text string `json:"text"`
+	}
+	slackSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &slack)
+	}))
+	defer slackSrv.Close()
+	if err := SendDirect("slack", `{"webhookUrl":"`+slackSrv.URL+`","locale":"de"}`, event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !strings.HasPrefix(slack.Text, "*Störung behoben*: Outage") {
+		t.Errorf("Unexpected Slack text %q", slack.Text)
+	}
+}
@@ -0,0 +1,263 @@
+package notifications
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used for channels without a locale and as the fallback for
+// messages a translation does not define.
+const DefaultLocale = "en"
+
+// translations maps a locale to the text of notifications sent to people: Slack
+// messages and digest titles. Webhook payloads stay machine-readable and untranslated.
+// Keys are stable identifiers; "title.<event type>" is the headline of an event.
+var (
+	translationsMu sync.RWMutex
+	translations   = map[string]map[string]string{
+		"en": {
+			"title.up":                      "Monitor Recovered",
+			"title.down":                    "Monitor Down",
+			"title.degraded":                "Monitor Degraded",
+			"title.ssl_expiring":            "SSL Certificate Expiring",
+			"title.flapping":                "Monitor Flapping",
+			"title.stabilized":              "Monitor Stabilized",
+			"title.budget_exceeded":         "Cost Budget Exceeded",
+			"title.slo_burn":                "Latency SLO Burning",
+			"title.anomaly":                 "Latency Anomaly",
+			"title.maintenance_scheduled":   "Maintenance Scheduled",
+			"title.maintenance_rescheduled": "Maintenance Rescheduled",
+			"title.maintenance_reminder":    "Maintenance Reminder",
+			"title.channel_disabled":        "Notification Channel Disabled",
+			"title.platform_down":           "Platform Event",
+			"title.platform_recovered":      "Platform Event Recovered",
+			"title.incident_opened":         "Incident Opened",
+			"title.incident_updated":        "Incident Updated",
+			"title.incident_resolved":       "Incident Resolved",
+			"field.monitor":                 "Monitor",
+			"field.channel":                 "Channel",
+			"field.scope":                   "Scope",
+			"field.url":                     "URL",
+			"field.message":                 "Message",
+			"field.time":                    "Time",
+			"field.starts":                  "Starts",
+			"field.ends":                    "Ends",
+			"field.calendar":                "Calendar",
+			"field.status_page":             "Status Page",
+			"field.status":                  "Status",
+			"label.add_to_calendar":         "Add to calendar (.ics)",
+			"digest.title":                  "Daily Monitoring Summary ({count} events)",
+		},
+		"es": {
+			"title.up":                      "Monitor recuperado",
+			"title.down":                    "Monitor caído",
+			"title.degraded":                "Monitor degradado",
+			"title.ssl_expiring":            "Certificado SSL a punto de caducar",
+			"title.flapping":                "Monitor inestable",
+			"title.stabilized":              "Monitor estabilizado",
+			"title.budget_exceeded":         "Presupuesto de costes superado",
+			"title.slo_burn":                "SLO de latencia en riesgo",
+			"title.anomaly":                 "Anomalía de latencia",
+			"title.maintenance_scheduled":   "Mantenimiento programado",
+			"title.maintenance_rescheduled": "Mantenimiento reprogramado",
+			"title.maintenance_reminder":    "Recordatorio de mantenimiento",
+			"title.channel_disabled":        "Canal de notificación desactivado",
+			"title.platform_down":           "Incidente de plataforma",
+			"title.platform_recovered":      "Incidente de plataforma resuelto",
+			"title.incident_opened":         "Incidente abierto",
+			"title.incident_updated":        "Incidente actualizado",
+			"title.incident_resolved":       "Incidente resuelto",
+			"field.monitor":                 "Monitor",
+			"field.channel":                 "Canal",
+			"field.scope":                   "Alcance",
+			"field.url":                     "URL",
+			"field.message":                 "Mensaje",
+			"field.time":                    "Hora",
+			"field.starts":                  "Inicio",
+			"field.ends":                    "Fin",
+			"field.calendar":                "Calendario",
+			"field.status_page":             "Página de estado",
+			"field.status":                  "Estado",
+			"label.add_to_calendar":         "Añadir al calendario (.ics)",
+			"digest.title":                  "Resumen diario de monitorización ({count} eventos)",
+		},
+		"fr": {
+			"title.up":                      "Moniteur rétabli",
+			"title.down":                    "Moniteur en panne",
+			"title.degraded":                "Moniteur dégradé",
+			"title.ssl_expiring":            "Certificat SSL bientôt expiré",
+			"title.flapping":                "Moniteur instable",
+			"title.stabilized":              "Moniteur stabilisé",
+			"title.budget_exceeded":         "Budget de coûts dépassé",
+			"title.slo_burn":                "SLO de latence menacé",
+			"title.anomaly":                 "Anomalie de latence",
+			"title.maintenance_scheduled":   "Maintenance planifiée",
+			"title.maintenance_rescheduled": "Maintenance replanifiée",
+			"title.maintenance_reminder":    "Rappel de maintenance",
+			"title.channel_disabled":        "Canal de notification désactivé",
+			"title.platform_down":           "Incident de plateforme",
+			"title.platform_recovered":      "Incident de plateforme résolu",
+			"title.incident_opened":         "Incident ouvert",
+			"title.incident_updated":        "Incident mis à jour",
+			"title.incident_resolved":       "Incident résolu",
+			"field.monitor":                 "Moniteur",
+			"field.channel":                 "Canal",
+			"field.scope":                   "Périmètre",
+			"field.url":                     "URL",
+			"field.message":                 "Message",
+			"field.time":                    "Heure",
+			"field.starts":                  "Début",
+			"field.ends":                    "Fin",
+			"field.calendar":                "Calendrier",
+			"field.status_page":             "Page de statut",
+			"field.status":                  "Statut",
+			"label.add_to_calendar":         "Ajouter au calendrier (.ics)",
+			"digest.title":                  "Résumé quotidien de la surveillance ({count} événements)",
+		},
+		"de": {
+			"title.up":                      "Monitor wiederhergestellt",
+			"title.down":                    "Monitor ausgefallen",
+			"title.degraded":                "Monitor beeinträchtigt",
+			"title.ssl_expiring":            "SSL-Zertifikat läuft ab",
+			"title.flapping":                "Monitor instabil",
+			"title.stabilized":              "Monitor stabilisiert",
+			"title.budget_exceeded":         "Kostenbudget überschritten",
+			"title.slo_burn":                "Latenz-SLO gefährdet",
+			"title.anomaly":                 "Latenzanomalie",
+			"title.maintenance_scheduled":   "Wartung geplant",
+			"title.maintenance_rescheduled": "Wartung verschoben",
+			"title.maintenance_reminder":    "Wartungserinnerung",
+			"title.channel_disabled":        "Benachrichtigungskanal deaktiviert",
+			"title.platform_down":           "Plattformstörung",
+			"title.platform_recovered":      "Plattformstörung behoben",
+			"title.incident_opened":         "Störung eröffnet",
+			"title.incident_updated":        "Störung aktualisiert",
+			"title.incident_resolved":       "Störung behoben",
+			"field.monitor":                 "Monitor",
+			"field.channel":                 "Kanal",
+			"field.scope":                   "Bereich",
+			"field.url":                     "URL",
+			"field.message":                 "Meldung",
+			"field.time":                    "Zeit",
+			"field.starts":                  "Beginn",
+			"field.ends":                    "Ende",
+			"field.calendar":                "Kalender",
+			"field.status_page":             "Statusseite",
+			"field.status":                  "Status",
+			"label.add_to_calendar":         "Zum Kalender hinzufügen (.ics)",
+			"digest.title":                  "Tägliche Überwachungsübersicht ({count} Ereignisse)",
+		},
+		"pt": {
+			"title.up":                      "Monitor recuperado",
+			"title.down":                    "Monitor fora do ar",
+			"title.degraded":                "Monitor degradado",
+			"title.ssl_expiring":            "Certificado SSL prestes a expirar",
+			"title.flapping":                "Monitor instável",
+			"title.stabilized":              "Monitor estabilizado",
+			"title.budget_exceeded":         "Orçamento de custos excedido",
+			"title.slo_burn":                "SLO de latência em risco",
+			"title.anomaly":                 "Anomalia de latência",
+			"title.maintenance_scheduled":   "Manutenção agendada",
+			"title.maintenance_rescheduled": "Manutenção reagendada",
+			"title.maintenance_reminder":    "Lembrete de manutenção",
+			"title.channel_disabled":        "Canal de notificação desativado",
+			"title.platform_down":           "Incidente de plataforma",
+			"title.platform_recovered":      "Incidente de plataforma resolvido",
+			"title.incident_opened":         "Incidente aberto",
+			"title.incident_updated":        "Incidente atualizado",
+			"title.incident_resolved":       "Incidente resolvido",
+			"field.monitor":                 "Monitor",
+			"field.channel":                 "Canal",
+			"field.scope":                   "Escopo",
+			"field.url":                     "URL",
+			"field.message":                 "Mensagem",
+			"field.time":                    "Horário",
+			"field.starts":                  "Início",
+			"field.ends":                    "Fim",
+			"field.calendar":                "Calendário",
+			"field.status_page":             "Página de status",
+			"field.status":                  "Status",
+			"label.add_to_calendar":         "Adicionar ao calendário (.ics)",
+			"digest.title":                  "Resumo diário do monitoramento ({count} eventos)",
+		},
+	}
+)
+
+// RegisterLocale adds or replaces the messages for a locale. Keys missing from
+// messages fall back to English, so partial translations are fine.
+func RegisterLocale(locale string, messages map[string]string) {
+	copied := make(map[string]string, len(messages))
+	for k, v := range messages {
+		copied[k] = v
+	}
+	translationsMu.Lock()
+	defer translationsMu.Unlock()
+	translations[locale] = copied
+}
+
+// Locales returns the registered locale codes, sorted.
+func Locales() []string {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+	locales := make([]string, 0, len(translations))
+	for l := range translations {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsLocale reports whether messages are registered for the locale.
+func IsLocale(locale string) bool {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+	_, ok := translations[locale]
+	return ok
+}
+
+// messages is the text of one locale.
+type messages struct {
+	locale string
+}
+
+// messagesFor returns the messages of a locale. Unknown locales get English.
+func messagesFor(locale string) messages {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	return messages{locale: locale}
+}
+
+// get returns the message for key, falling back to English and then to the key itself.
+func (m messages) get(key string) string {
+	translationsMu.RLock()
+	defer translationsMu.RUnlock()
+	if v, ok := translations[m.locale][key]; ok {
+		return v
+	}
+	if v, ok := translations[DefaultLocale][key]; ok {
+		return v
+	}
+	return key
+}
+
+// title returns the headline of an event type; types without one read as a recovery.
+func (m messages) title(t EventType) string {
+	if title := m.get("title." + string(t)); title != "title."+string(t) {
+		return title
+	}
+	return m.get("title.up")
+}
+
+// digestTitle returns the title of a digest of count events.
+func (m messages) digestTitle(count int) string {
+	return strings.ReplaceAll(m.get("digest.title"), "{count}", strconv.Itoa(count))
+}
+
+// configLocale returns the locale set in a channel's config, if any.
+func configLocale(config map[string]interface{}) string {
+	locale, _ := config["locale"].(string)
+	return locale
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMessagesFor(t *testing.T) {
+	if got := messagesFor("de").title(EventDown); got != "Monitor ausgefallen" {
+		t.Errorf("Expected the German title, got %q", got)
+	}
+	if got := messagesFor("").title(EventDown); got != "Monitor Down" {
+		t.Errorf("Expected English without a locale, got %q", got)
+	}
+	if got := messagesFor("xx").get("field.url"); got != "URL" {
+		t.Errorf("Expected an unknown locale to fall back to English, got %q", got)
+	}
+	if got := messagesFor("fr").title(EventType("custom")); got != "Moniteur rétabli" {
+		t.Errorf("Expected an unknown event type to read as a recovery, got %q", got)
+	}
+	if got := messagesFor("es").digestTitle(3); got != "Resumen diario de monitorización (3 eventos)" {
+		t.Errorf("Unexpected digest title %q", got)
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("nl", map[string]string{"title.down": "Monitor offline"})
+	if !IsLocale("nl") {
+		t.Fatal("Expected nl to be registered")
+	}
+	msgs := messagesFor("nl")
+	if got := msgs.title(EventDown); got != "Monitor offline" {
+		t.Errorf("Expected the registered title, got %q", got)
+	}
+	if got := msgs.get("field.time"); got != "Time" {
+		t.Errorf("Expected missing messages to fall back to English, got %q", got)
+	}
+}
+
+func TestSlackNotifier_Locale(t *testing.T) {
+	var received struct {
+		Text        string `json:"text"`
+		Attachments []struct {
+			Fields []struct {
+				Title string `json:"title"`
+			} `json:"fields"`
+		} `json:"attachments"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	config := `{"webhookUrl":"` + srv.URL + `","locale":"pt"}`
+	if err := SendDirect("slack", config, sampleEvent()); err != nil {
+		t.Fatalf("SendDirect failed: %v", err)
+	}
+	if !strings.HasPrefix(received.Text, "*Monitor fora do ar*") {
+		t.Errorf("Expected a Portuguese title, got %q", received.Text)
+	}
+	if len(received.Attachments) != 1 || len(received.Attachments[0].Fields) != 4 || received.Attachments[0].Fields[2].Title != "Mensagem" {
+		t.Errorf("Expected Portuguese field titles, got %+v", received.Attachments)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"net/http"
 	"net/url"
@@ -25,6 +26,45 @@ const (
 	EventSSLExpiring EventType = "ssl_expiring"
 	EventFlapping    EventType = "flapping"
 	EventStabilized  EventType = "stabilized"
+	// EventBudgetExceeded is sent when projected monthly cost exceeds a cost budget.
+	EventBudgetExceeded EventType = "budget_exceeded"
+	// EventMaintenanceScheduled is sent when a maintenance window is created.
+	EventMaintenanceScheduled EventType = "maintenance_scheduled"
+	// EventMaintenanceRescheduled is sent when the start or end of a maintenance window changes.
+	EventMaintenanceRescheduled EventType = "maintenance_rescheduled"
+	// EventMaintenanceReminder is sent a configured number of hours before a maintenance window starts.
+	EventMaintenanceReminder EventType = "maintenance_reminder"
+	// EventSLOBurn is sent when a monitor's latency SLO burns its error budget fast enough to be violated.
+	EventSLOBurn EventType = "slo_burn"
+	// EventAnomaly is sent when a monitor's latency stays well above its learned baseline, even below the degraded threshold.
+	EventAnomaly EventType = "anomaly"
+	// EventChannelDisabled is sent to the remaining channels when one was disabled after failing for BreakerDisableAfter.
+	EventChannelDisabled EventType = "channel_disabled"
+	// EventPlatformDown summarizes a platform event: many monitors down at once, notified together.
+	EventPlatformDown EventType = "platform_down"
+	// EventPlatformRecovered is sent once every monitor of a platform event recovered.
+	EventPlatformRecovered EventType = "platform_recovered"
+	// EventIncidentOpened, EventIncidentUpdated and EventIncidentResolved follow a public
+	// incident on a status page; they only go to the channels of the pages showing it.
+	EventIncidentOpened   EventType = "incident_opened"
+	EventIncidentUpdated  EventType = "incident_updated"
+	EventIncidentResolved EventType = "incident_resolved"
+)
+
+const (
+	// DeliveryWorkers send notifications to channels concurrently. A channel is always
+	// served by the same worker, so its notifications arrive in order.
+	DeliveryWorkers = 4
+	// MaxDeliveryAttempts is how many times a notification is sent to a channel before
+	// it is given up on.
+	MaxDeliveryAttempts = 5
+
+	deliveryBuffer    = 25               // queued deliveries per worker
+	deliveryLease     = 5 * time.Minute  // longer than a full worker buffer takes to send
+	retryBackoff      = 30 * time.Second // doubled with every failed attempt
+	queuePollInterval = 2 * time.Second
+	queueBatchSize    = 100
+	queuePruneEvery   = time.Hour
 )
 
 // NotificationEvent represents the data needed to send a notification
@@ -35,6 +75,17 @@ type NotificationEvent struct {
 	Type        EventType
 	Message     string
 	Time        time.Time
+
+	// DedupKey identifies the event, so that enqueueing it twice delivers it once.
+	// Defaults to its type, monitor and time.
+	DedupKey string
+
+	// Maintenance is set for EventMaintenanceScheduled, EventMaintenanceRescheduled and
+	// EventMaintenanceReminder
+	Maintenance *MaintenanceWindow
+
+	// Incident is set for the incident events
+	Incident *IncidentNotice
 }
 
 // Notifier interfaces for different notification providers
@@ -42,63 +93,277 @@ type Notifier interface {
 	Send(event NotificationEvent) error
 }
 
+// delivery is one queued notification bound for one channel.
+type delivery struct {
+	id       int64 // notification_queue row
+	attempts int   // failed attempts so far
+	channel  db.NotificationChannel
+	event    NotificationEvent
+}
+
 // Service manages the notification queue and dispatching
 type Service struct {
-	store *db.Store
-	queue chan NotificationEvent
+	store      *db.Store
+	wake       chan struct{}   // signals the queue pump that notifications were enqueued
+	deliveries []chan delivery // one per delivery worker
+	breakers   *channelBreakers
+	stopCh     chan struct{}
+	now        func() time.Time
+	lastPrune  time.Time // owned by the queue pump
+
+	// isLeader gates scheduled notifications (maintenance reminders); nil = always run
+	isLeader func() bool
+
+	// Wakes the reminder scheduler after the reminder hours changed
+	reminderWake chan struct{}
 }
 
 func NewService(store *db.Store) *Service {
-	return &Service{
-		store: store,
-		queue: make(chan NotificationEvent, 100),
+	s := &Service{
+		store:        store,
+		wake:         make(chan struct{}, 1),
+		deliveries:   make([]chan delivery, DeliveryWorkers),
+		breakers:     newChannelBreakers(),
+		stopCh:       make(chan struct{}),
+		now:          time.Now,
+		reminderWake: make(chan struct{}, 1),
+	}
+	for i := range s.deliveries {
+		s.deliveries[i] = make(chan delivery, deliveryBuffer)
 	}
+	store.OnSettingsChange(s.settingsChanged)
+	return s
 }
 
 func (s *Service) Start() {
-	go s.worker()
+	go s.pump()
+	for _, deliveries := range s.deliveries {
+		go s.deliveryWorker(deliveries)
+	}
+	go s.reminderWorker()
+}
+
+// Stop ends the reminder scheduler and the queue pump. Undelivered notifications stay
+// queued for the next start.
+func (s *Service) Stop() {
+	close(s.stopCh)
 }
 
-func (s *Service) worker() {
-	for event := range s.queue {
-		s.dispatch(event)
+// pump hands due notifications from the queue to the delivery workers, so a slow or
+// unreachable channel does not hold up the others. Only the leader delivers.
+func (s *Service) pump() {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+		if s.isLeader != nil && !s.isLeader() {
+			continue
+		}
+		s.drainQueue()
 	}
 }
 
-func (s *Service) dispatch(event NotificationEvent) {
+func (s *Service) drainQueue() {
+	now := s.now()
+	if now.Sub(s.lastPrune) >= queuePruneEvery {
+		s.lastPrune = now
+		if _, err := s.store.PruneDeliveredNotifications(now.Add(-db.NotificationDedupWindow)); err != nil {
+			log.Printf("Failed to prune delivered notifications: %v", err)
+		}
+	}
+
+	items, err := s.store.ClaimDueNotifications(now, deliveryLease, queueBatchSize)
+	if err != nil {
+		log.Printf("Failed to read notification queue: %v", err)
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
 	channels, err := s.store.GetNotificationChannels()
 	if err != nil {
 		log.Printf("Failed to fetch notification channels: %v", err)
 		return
 	}
-
+	byID := make(map[string]db.NotificationChannel, len(channels))
 	for _, ch := range channels {
-		if !ch.Enabled {
+		byID[ch.ID] = ch
+	}
+
+	for _, item := range items {
+		d := delivery{id: item.ID, attempts: item.Attempts, channel: byID[item.ChannelID]}
+		if err := json.Unmarshal([]byte(item.Payload), &d.event); err != nil {
+			log.Printf("Dropping unreadable queued notification %d: %v", item.ID, err)
+			s.drop(d)
 			continue
 		}
-
-		var notifier Notifier
-		switch ch.Type {
-		case "slack":
-			notifier = NewSlackNotifier(ch.Config)
-		case "webhook":
-			notifier = NewWebhookNotifier(ch.Config)
+		select {
+		case s.deliveries[shard(item.ChannelID)] <- d:
 		default:
-			log.Printf("Unknown channel type: %s", ch.Type)
-			continue
+			// The worker is backed up; try again on a later poll
+			s.reschedule(d, d.attempts, queuePollInterval)
 		}
+	}
+}
+
+// shard picks the delivery worker serving a channel.
+func shard(channelID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channelID))
+	return int(h.Sum32() % DeliveryWorkers)
+}
 
-		if err := notifier.Send(event); err != nil {
-			log.Printf("Failed to send notification to %s (%s): %v", ch.Name, ch.Type, err)
+func (s *Service) deliveryWorker(deliveries <-chan delivery) {
+	for d := range deliveries {
+		s.deliver(d)
+	}
+}
+
+// deliver sends one notification unless the channel's circuit is open, and updates its
+// queue entry: delivered, retried later with backoff, or given up on.
+func (s *Service) deliver(d delivery) {
+	ch := d.channel
+	if !s.breakers.allow(ch.ID, s.now()) {
+		// Keep it queued until the channel's next trial send
+		s.reschedule(d, d.attempts, BreakerCooldown)
+		return
+	}
+
+	notifier, err := s.notifier(ch)
+	if err != nil {
+		log.Printf("Unknown channel type: %s", ch.Type)
+		s.drop(d)
+		return
+	}
+
+	if err := notifier.Send(d.event); err != nil {
+		log.Printf("Failed to send notification to %s (%s): %v", ch.Name, ch.Type, err)
+		s.recordFailure(ch, err)
+		attempts := d.attempts + 1
+		if attempts >= MaxDeliveryAttempts {
+			log.Printf("Giving up on %s notification to %s (%s) after %d attempts", d.event.Type, ch.Name, ch.Type, attempts)
+			s.drop(d)
+			return
 		}
+		s.reschedule(d, attempts, retryBackoff<<(attempts-1))
+		return
+	}
+	s.breakers.success(ch.ID)
+	if err := s.store.MarkNotificationDelivered(d.id, s.now()); err != nil {
+		log.Printf("Failed to mark notification %d delivered: %v", d.id, err)
+	}
+}
+
+func (s *Service) reschedule(d delivery, attempts int, after time.Duration) {
+	if err := s.store.RescheduleNotification(d.id, attempts, s.now().Add(after)); err != nil {
+		log.Printf("Failed to reschedule notification %d: %v", d.id, err)
+	}
+}
+
+func (s *Service) drop(d delivery) {
+	if err := s.store.DeleteQueuedNotification(d.id); err != nil {
+		log.Printf("Failed to drop notification %d: %v", d.id, err)
+	}
+}
+
+// recordFailure opens the channel's circuit after repeated failures, and disables the
+// channel once it has been failing for BreakerDisableAfter, alerting the others.
+func (s *Service) recordFailure(ch db.NotificationChannel, sendErr error) {
+	now := s.now()
+	opened, failingFor := s.breakers.failure(ch.ID, now)
+	if opened {
+		log.Printf("Notification channel %s (%s) failed %d times in a row, pausing it for %s between attempts", ch.Name, ch.Type, BreakerFailureThreshold, BreakerCooldown)
+	}
+	if failingFor < BreakerDisableAfter {
+		return
+	}
+
+	reason := fmt.Sprintf("Disabled after failing for %s: %v", BreakerDisableAfter, sendErr)
+	if err := s.store.DisableNotificationChannel(ch.ID, reason); err != nil {
+		log.Printf("Failed to disable notification channel %s: %v", ch.Name, err)
+		return
 	}
+	// Start over if the channel is enabled again
+	s.breakers.success(ch.ID)
+	log.Printf("Disabled notification channel %s (%s): %s", ch.Name, ch.Type, reason)
+
+	s.Enqueue(NotificationEvent{
+		MonitorID:   ch.ID,
+		MonitorName: ch.Name,
+		Type:        EventChannelDisabled,
+		Message:     fmt.Sprintf("Notification channel %q (%s) was disabled after failing for %s: %v. Fix it and enable it again in the notification settings.", ch.Name, ch.Type, BreakerDisableAfter, sendErr),
+		Time:        now,
+	})
 }
 
+// Enqueue stores the event for delivery to every enabled channel, and to the watchers of
+// its monitor. It is delivered in the background, at least once, even across restarts; an
+// event whose DedupKey is already queued, or was delivered within db.NotificationDedupWindow,
+// is ignored.
 func (s *Service) Enqueue(event NotificationEvent) {
+	s.enqueue(event, func(ch db.NotificationChannel) bool { return watches(ch, event.MonitorID) })
+}
+
+// EnqueueForWatchers stores the event for delivery to the watchers of its monitor only. It
+// is used for events held back for the digest, which watchers still get as they happen.
+func (s *Service) EnqueueForWatchers(event NotificationEvent) {
+	s.enqueue(event, func(ch db.NotificationChannel) bool { return ch.MonitorID != "" && watches(ch, event.MonitorID) })
+}
+
+// watches reports whether the channel should receive the monitor's events: global channels
+// receive every monitor's, watchers only their own.
+func watches(ch db.NotificationChannel, monitorID string) bool {
+	return ch.MonitorID == "" || ch.MonitorID == monitorID
+}
+
+// enqueue stores the event for delivery to the enabled channels it is for.
+func (s *Service) enqueue(event NotificationEvent, isFor func(db.NotificationChannel) bool) {
+	channels, err := s.store.GetNotificationChannels()
+	if err != nil {
+		log.Printf("Failed to fetch notification channels: %v", err)
+		return
+	}
+
+	now := s.now()
+	if event.Time.IsZero() {
+		event.Time = now
+	}
+	key := event.DedupKey
+	if key == "" {
+		key = fmt.Sprintf("%s:%s:%d", event.Type, event.MonitorID, event.Time.UnixNano())
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode notification for %s: %v", event.MonitorID, err)
+		return
+	}
+
+	var items []db.QueuedNotification
+	for _, ch := range channels {
+		if !ch.Enabled || !isFor(ch) {
+			continue
+		}
+		items = append(items, db.QueuedNotification{
+			DedupKey:      key + "|" + ch.ID,
+			ChannelID:     ch.ID,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	if _, err := s.store.EnqueueNotifications(items); err != nil {
+		log.Printf("Failed to queue notification for %s: %v", event.MonitorID, err)
+		return
+	}
+
 	select {
-	case s.queue <- event:
+	case s.wake <- struct{}{}:
 	default:
-		log.Printf("Notification queue full, dropping event for %s", event.MonitorID)
 	}
 }
 
@@ -131,6 +396,16 @@ func (n *SlackNotifier) Send(event NotificationEvent) error {
 		color = "#9b59b6" // Purple
 	case EventStabilized:
 		color = "#3498db" // Blue
+	case EventBudgetExceeded:
+		color = "#e67e22" // Dark orange
+	case EventSLOBurn, EventAnomaly:
+		color = "#ff8c00" // Orange
+	case EventMaintenanceScheduled, EventMaintenanceRescheduled, EventMaintenanceReminder:
+		color = "#3498db" // Blue
+	case EventChannelDisabled, EventPlatformDown, EventIncidentOpened:
+		color = "#dc3545" // Red
+	case EventIncidentUpdated:
+		color = "#ffc107" // Yellow
 	}
 
 	emoji := ":white_check_mark:"
@@ -145,20 +420,41 @@ func (n *SlackNotifier) Send(event NotificationEvent) error {
 		emoji = ":cyclone:"
 	case EventStabilized:
 		emoji = ":large_blue_circle:"
+	case EventBudgetExceeded:
+		emoji = ":moneybag:"
+	case EventSLOBurn:
+		emoji = ":fire:"
+	case EventAnomaly:
+		emoji = ":chart_with_upwards_trend:"
+	case EventMaintenanceScheduled:
+		emoji = ":wrench:"
+	case EventMaintenanceRescheduled:
+		emoji = ":calendar:"
+	case EventMaintenanceReminder:
+		emoji = ":alarm_clock:"
+	case EventChannelDisabled:
+		emoji = ":no_bell:"
+	case EventPlatformDown, EventIncidentOpened:
+		emoji = ":rotating_light:"
+	case EventIncidentUpdated:
+		emoji = ":memo:"
 	}
 
-	title := "Monitor Recovered"
+	msgs := messagesFor(configLocale(n.config))
+	title := msgs.title(event.Type)
+	subject := msgs.get("field.monitor")
 	switch event.Type {
-	case EventDown:
-		title = "Monitor Down"
-	case EventDegraded:
-		title = "Monitor Degraded"
-	case EventSSLExpiring:
-		title = "SSL Certificate Expiring"
-	case EventFlapping:
-		title = "Monitor Flapping"
-	case EventStabilized:
-		title = "Monitor Stabilized"
+	case EventChannelDisabled:
+		subject = msgs.get("field.channel")
+	case EventPlatformDown, EventPlatformRecovered:
+		subject = msgs.get("field.scope")
+	}
+
+	if event.Maintenance != nil {
+		return sendJSON(url, slackMaintenancePayload(msgs, title, color, emoji, event))
+	}
+	if event.Incident != nil {
+		return sendJSON(url, slackIncidentPayload(msgs, title, color, emoji, event))
 	}
 
 	payload := map[string]interface{}{
@@ -168,22 +464,22 @@ func (n *SlackNotifier) Send(event NotificationEvent) error {
 				"color": color,
 				"fields": []map[string]interface{}{
 					{
-						"title": "Monitor",
+						"title": subject,
 						"value": event.MonitorName,
 						"short": true,
 					},
 					{
-						"title": "URL",
+						"title": msgs.get("field.url"),
 						"value": event.MonitorURL,
 						"short": true,
 					},
 					{
-						"title": "Message",
+						"title": msgs.get("field.message"),
 						"value": emoji + " " + event.Message,
 						"short": false,
 					},
 					{
-						"title": "Time",
+						"title": msgs.get("field.time"),
 						"value": event.Time.Format(time.RFC1123),
 						"short": true,
 					},
@@ -195,6 +491,50 @@ func (n *SlackNotifier) Send(event NotificationEvent) error {
 	return sendJSON(url, payload)
 }
 
+// slackMaintenancePayload shows the window's times and links to its calendar file.
+func slackMaintenancePayload(msgs messages, title, color, emoji string, event NotificationEvent) map[string]interface{} {
+	mw := event.Maintenance
+	fields := []map[string]interface{}{
+		{"title": msgs.get("field.starts"), "value": mw.Start.UTC().Format(time.RFC1123), "short": true},
+		{"title": msgs.get("field.ends"), "value": mw.End.UTC().Format(time.RFC1123), "short": true},
+		{"title": msgs.get("field.message"), "value": emoji + " " + event.Message, "short": false},
+	}
+	if mw.CalendarURL != "" {
+		fields = append(fields, map[string]interface{}{
+			"title": msgs.get("field.calendar"),
+			"value": "<" + mw.CalendarURL + "|" + msgs.get("label.add_to_calendar") + ">",
+			"short": false,
+		})
+	}
+	return map[string]interface{}{
+		"text": "*" + title + "*: " + mw.Title,
+		"attachments": []map[string]interface{}{
+			{"color": color, "fields": fields},
+		},
+	}
+}
+
+// slackIncidentPayload names the status page and links to it.
+func slackIncidentPayload(msgs messages, title, color, emoji string, event NotificationEvent) map[string]interface{} {
+	inc := event.Incident
+	page := inc.PageTitle
+	if inc.PageURL != "" {
+		page = "<" + inc.PageURL + "|" + inc.PageTitle + ">"
+	}
+	fields := []map[string]interface{}{
+		{"title": msgs.get("field.status_page"), "value": page, "short": true},
+		{"title": msgs.get("field.status"), "value": inc.Status, "short": true},
+		{"title": msgs.get("field.message"), "value": emoji + " " + event.Message, "short": false},
+		{"title": msgs.get("field.time"), "value": event.Time.Format(time.RFC1123), "short": true},
+	}
+	return map[string]interface{}{
+		"text": "*" + title + "*: " + inc.Title,
+		"attachments": []map[string]interface{}{
+			{"color": color, "fields": fields},
+		},
+	}
+}
+
 // WebhookNotifier sends a clean JSON payload to a generic webhook endpoint
 type WebhookNotifier struct {
 	config map[string]interface{}
@@ -221,25 +561,68 @@ func (n *WebhookNotifier) Send(event NotificationEvent) error {
 		"timestamp":   event.Time.Format(time.RFC3339),
 	}
 
+	if mw := event.Maintenance; mw != nil {
+		maintenance := map[string]interface{}{
+			"id":          mw.ID,
+			"title":       mw.Title,
+			"description": mw.Description,
+			"startTime":   mw.Start.UTC().Format(time.RFC3339),
+			"endTime":     mw.End.UTC().Format(time.RFC3339),
+		}
+		if mw.CalendarURL != "" {
+			maintenance["calendarUrl"] = mw.CalendarURL
+		}
+		payload["maintenance"] = maintenance
+		// Inline calendar file so receivers can attach it to emails or tickets
+		payload["ics"] = BuildICS(*mw, event.Time)
+	}
+	if inc := event.Incident; inc != nil {
+		payload["incident"] = map[string]interface{}{
+			"id":        inc.ID,
+			"title":     inc.Title,
+			"status":    inc.Status,
+			"severity":  inc.Severity,
+			"pageSlug":  inc.PageSlug,
+			"pageTitle": inc.PageTitle,
+			"pageUrl":   inc.PageURL,
+		}
+	}
+
 	return sendJSON(webhookURL, payload)
 }
 
 // SendDirect dispatches a NotificationEvent through the appropriate notifier
 // without going through the queue. Used for test notifications.
 func SendDirect(channelType, configJSON string, event NotificationEvent) error {
-	var notifier Notifier
+	notifier, err := newNotifier(channelType, configJSON)
+	if err != nil {
+		return err
+	}
+	return notifier.Send(event)
+}
+
+// notifier returns the notifier of a queued channel. Push channels send to the devices
+// registered in the store.
+func (s *Service) notifier(ch db.NotificationChannel) (Notifier, error) {
+	if ch.Type == "push" {
+		return NewPushNotifier(s.store, ch.Config), nil
+	}
+	return newNotifier(ch.Type, ch.Config)
+}
+
+func newNotifier(channelType, configJSON string) (Notifier, error) {
 	switch channelType {
 	case "slack":
-		notifier = NewSlackNotifier(configJSON)
+		return NewSlackNotifier(configJSON), nil
 	case "webhook":
-		notifier = NewWebhookNotifier(configJSON)
+		return NewWebhookNotifier(configJSON), nil
 	default:
-		return fmt.Errorf("unsupported channel type: %s", channelType)
+		return nil, fmt.Errorf("unsupported channel type: %s", channelType)
 	}
-	return notifier.Send(event)
 }
 
 // SendDigest dispatches a daily digest summary to all enabled notification channels.
+// Watchers are left out: they received the events as they happened.
 func (s *Service) SendDigest(events []db.DigestEvent) {
 	if len(events) == 0 {
 		return
@@ -286,26 +669,34 @@ func (s *Service) SendDigest(events []db.DigestEvent) {
 		lines = append(lines, "- "+me.name+": "+strings.Join(parts, ", "))
 	}
 
-	title := "Daily Monitoring Summary (" + strconv.Itoa(len(events)) + " events)"
 	body := strings.Join(lines, "\n")
 
 	for _, ch := range channels {
-		if !ch.Enabled {
+		if !ch.Enabled || ch.MonitorID != "" {
+			continue
+		}
+		if !s.breakers.allow(ch.ID, s.now()) {
+			log.Printf("Digest: skipping %s (%s), channel is failing", ch.Name, ch.Type)
 			continue
 		}
 
+		var err error
 		switch ch.Type {
 		case "slack":
 			n := NewSlackNotifier(ch.Config)
-			if err := n.sendDigest(title, body); err != nil {
-				log.Printf("Digest: failed to send to Slack (%s): %v", ch.Name, err)
-			}
+			err = n.sendDigest(messagesFor(configLocale(n.config)).digestTitle(len(events)), body)
 		case "webhook":
 			n := NewWebhookNotifier(ch.Config)
-			if err := n.sendDigest(title, body, events); err != nil {
-				log.Printf("Digest: failed to send to webhook (%s): %v", ch.Name, err)
-			}
+			err = n.sendDigest(messagesFor(configLocale(n.config)).digestTitle(len(events)), body, events)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Printf("Digest: failed to send to %s (%s): %v", ch.Name, ch.Type, err)
+			s.recordFailure(ch, err)
+			continue
 		}
+		s.breakers.success(ch.ID)
 	}
 }
 
@@ -345,6 +736,15 @@ func (n *WebhookNotifier) sendDigest(title, body string, events []db.DigestEvent
 	return sendJSON(webhookURL, payload)
 }
 
+// transport carries every outgoing notification; nil uses the default transport.
+var transport http.RoundTripper
+
+// SetTransport sends notifications through rt instead of the network, as demo mode does
+// so a public demo can't be used to post to arbitrary webhooks. Call before Start.
+func SetTransport(rt http.RoundTripper) {
+	transport = rt
+}
+
 func sendJSON(targetURL string, payload interface{}) error {
 	// SECURITY: Validate URL scheme to prevent SSRF if database is compromised
 	parsedURL, err := url.Parse(targetURL)
@@ -366,7 +766,7 @@ func sendJSON(targetURL string, payload interface{}) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
 	resp, err := client.Do(req) // #nosec G704 -- URL scheme validated above
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -316,3 +317,197 @@ func TestSendDirect_UnsupportedType(t *testing.T) {
 		t.Error("expected error for unsupported type")
 	}
 }
+
+func TestWebhookNotifier_MaintenancePayload(t *testing.T) {
+	var received map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer srv.Close()
+
+	start := time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)
+	event := NotificationEvent{
+		MonitorID:   "inc-1",
+		MonitorName: "DB upgrade",
+		Type:        EventMaintenanceScheduled,
+		Message:     "Maintenance scheduled: DB upgrade",
+		Time:        start.Add(-time.Hour),
+		Maintenance: &MaintenanceWindow{ID: "inc-1", Title: "DB upgrade", Start: start, End: start.Add(time.Hour), CalendarURL: "https://warden.example.com/api/maintenance/inc-1/ics"},
+	}
+	if err := NewWebhookNotifier(`{"webhookUrl":"` + srv.URL + `"}`).Send(event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	mw, ok := received["maintenance"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected maintenance object, got %v", received)
+	}
+	if mw["startTime"] != "2026-05-01T22:00:00Z" || mw["calendarUrl"] != "https://warden.example.com/api/maintenance/inc-1/ics" {
+		t.Errorf("unexpected maintenance payload %v", mw)
+	}
+	if ics, _ := received["ics"].(string); !strings.Contains(ics, "DTSTART:20260501T220000Z") {
+		t.Errorf("expected inline ICS, got %q", ics)
+	}
+}
+
+// runQueue claims due notifications and delivers them synchronously.
+func runQueue(svc *Service) {
+	svc.drainQueue()
+	for _, deliveries := range svc.deliveries {
+		for len(deliveries) > 0 {
+			svc.deliver(<-deliveries)
+		}
+	}
+}
+
+func TestService_QueueSurvivesRestart(t *testing.T) {
+	store := newTestStore(t)
+
+	received := make(chan map[string]interface{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload
+	}))
+	defer srv.Close()
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc1", Type: "webhook", Name: "Hook", Config: `{"webhookUrl":"` + srv.URL + `"}`, Enabled: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queued, but the process stops before delivering
+	NewService(store).Enqueue(sampleEvent())
+
+	svc := NewService(store)
+	svc.Start()
+	defer svc.Stop()
+
+	select {
+	case payload := <-received:
+		if payload["monitorId"] != "mon-123" {
+			t.Errorf("unexpected payload: %v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the queued notification to be delivered after a restart")
+	}
+}
+
+func TestService_EnqueueDeduplicates(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc1", Type: "webhook", Name: "Hook", Config: `{"webhookUrl":"` + srv.URL + `"}`, Enabled: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.Enqueue(sampleEvent())
+	svc.Enqueue(sampleEvent())
+	runQueue(svc)
+	svc.Enqueue(sampleEvent()) // already delivered
+	runQueue(svc)
+	if hits != 1 {
+		t.Fatalf("expected one delivery of a duplicated event, got %d", hits)
+	}
+
+	other := sampleEvent()
+	other.DedupKey = "another"
+	svc.Enqueue(other)
+	runQueue(svc)
+	if hits != 2 {
+		t.Errorf("expected an event with its own dedup key to be delivered, got %d deliveries", hits)
+	}
+}
+
+func TestService_RetriesFailedDelivery(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc1", Type: "webhook", Name: "Hook", Config: `{"webhookUrl":"` + srv.URL + `"}`, Enabled: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.Enqueue(sampleEvent())
+	runQueue(svc)
+	runQueue(svc)
+	if hits != 1 {
+		t.Fatalf("expected the retry to wait for its backoff, got %d attempts", hits)
+	}
+
+	now = now.Add(retryBackoff)
+	runQueue(svc)
+	now = now.Add(2 * retryBackoff)
+	runQueue(svc)
+	if hits != 3 {
+		t.Fatalf("expected delivery on the third attempt, got %d attempts", hits)
+	}
+
+	now = now.Add(time.Hour)
+	runQueue(svc)
+	if hits != 3 {
+		t.Errorf("expected no more attempts after delivery, got %d", hits)
+	}
+}
+
+func TestService_EnqueueWatchers(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+	_ = store.CreateGroup(db.Group{ID: "g1", Name: "G1"})
+	for _, id := range []string{"m1", "m2"} {
+		if err := store.CreateMonitor(db.Monitor{ID: id, GroupID: "g1", Name: id, URL: "http://example.com", Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor: %v", err)
+		}
+	}
+	addTestChannel(t, store)
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc-watch", Type: "webhook", Name: "Watcher", Config: `{"webhookUrl":"http://127.0.0.1:1"}`, Enabled: true, MonitorID: "m1",
+	}); err != nil {
+		t.Fatalf("CreateNotificationChannel: %v", err)
+	}
+
+	queuedFor := func() []string {
+		items, err := store.ClaimDueNotifications(time.Now().Add(24*time.Hour), time.Minute, 1000)
+		if err != nil {
+			t.Fatalf("ClaimDueNotifications: %v", err)
+		}
+		var channels []string
+		for _, item := range items {
+			channels = append(channels, item.ChannelID)
+			_ = store.DeleteQueuedNotification(item.ID)
+		}
+		return channels
+	}
+
+	svc.Enqueue(NotificationEvent{MonitorID: "m1", Type: EventDown, Time: time.Now()})
+	if got := queuedFor(); len(got) != 2 {
+		t.Errorf("Expected the watched monitor's event on both channels, got %v", got)
+	}
+	svc.Enqueue(NotificationEvent{MonitorID: "m2", Type: EventDown, Time: time.Now()})
+	if got := queuedFor(); len(got) != 1 || got[0] != "nc-test" {
+		t.Errorf("Expected another monitor's event on the global channel only, got %v", got)
+	}
+	svc.EnqueueForWatchers(NotificationEvent{MonitorID: "m1", Type: EventDegraded, Time: time.Now()})
+	if got := queuedFor(); len(got) != 1 || got[0] != "nc-watch" {
+		t.Errorf("Expected a digested event on the watcher only, got %v", got)
+	}
+}
@@ -0,0 +1,225 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// PushGatewayURLKey is the setting holding the push gateway mobile apps are reached
+	// through. The gateway holds the apps' FCM and APNs credentials, which a self-hosted
+	// server can't have, and forwards each notification to Google or Apple.
+	PushGatewayURLKey = "push.gateway_url"
+	// PushGatewayTokenKey is the setting holding the Bearer token sent to the gateway.
+	PushGatewayTokenKey = "push.gateway_token"
+)
+
+// pushEvents are the outage events push channels deliver; the rest stay in chat and
+// webhooks.
+var pushEvents = map[EventType]bool{
+	EventDown:              true,
+	EventUp:                true,
+	EventPlatformDown:      true,
+	EventPlatformRecovered: true,
+}
+
+// pushMessage is the notification a push device receives.
+type pushMessage struct {
+	Event       EventType `json:"event"`
+	MonitorID   string    `json:"monitorId"`
+	MonitorName string    `json:"monitorName"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	Tag         string    `json:"tag"` // Replaces the monitor's previous notification on the device
+	Timestamp   string    `json:"timestamp"`
+}
+
+// gatewayDevice is a mobile device as sent to the push gateway.
+type gatewayDevice struct {
+	Platform string `json:"platform"`
+	Token    string `json:"token"`
+}
+
+// PushNotifier sends outage notifications to every registered push device: mobile apps
+// through the push gateway, browsers directly with Web Push.
+type PushNotifier struct {
+	store  *db.Store
+	config map[string]interface{}
+}
+
+func NewPushNotifier(store *db.Store, configJSON string) *PushNotifier {
+	var config map[string]interface{}
+	_ = json.Unmarshal([]byte(configJSON), &config)
+	return &PushNotifier{store: store, config: config}
+}
+
+// Send delivers the event to every device. Devices the push service reports gone are
+// unregistered. A retry would send it again to the devices that got it, so it only fails
+// when no device did.
+func (n *PushNotifier) Send(event NotificationEvent) error {
+	if !pushEvents[event.Type] {
+		return nil
+	}
+	devices, err := n.store.GetPushDevices()
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	msgs := messagesFor(configLocale(n.config))
+	// Platform events have no monitor; their recovery replaces the outage notification too
+	tag := "monitor-" + event.MonitorID
+	if event.MonitorID == "" {
+		tag = "platform"
+	}
+	msg := pushMessage{
+		Event:       event.Type,
+		MonitorID:   event.MonitorID,
+		MonitorName: event.MonitorName,
+		Title:       msgs.title(event.Type) + ": " + event.MonitorName,
+		Body:        event.Message,
+		Tag:         tag,
+		Timestamp:   event.Time.Format(time.RFC3339),
+	}
+
+	var mobile, web []db.PushDevice
+	for _, d := range devices {
+		if d.Platform == db.PushPlatformWeb {
+			web = append(web, d)
+		} else {
+			mobile = append(mobile, d)
+		}
+	}
+
+	delivered := 0
+	var lastErr error
+	if len(mobile) > 0 {
+		sent, err := n.sendGateway(msg, mobile)
+		delivered += sent
+		if err != nil {
+			lastErr = err
+		}
+	}
+	if len(web) > 0 {
+		sent, err := n.sendWeb(msg, web)
+		delivered += sent
+		if err != nil {
+			lastErr = err
+		}
+	}
+	if lastErr != nil && delivered == 0 {
+		return lastErr
+	}
+	if lastErr != nil {
+		log.Printf("Push notification reached %d of %d devices: %v", delivered, len(devices), lastErr)
+	}
+	return nil
+}
+
+// sendGateway posts the message and the mobile devices to the push gateway, which
+// answers with the tokens FCM or APNs rejected, and returns how many devices it reached.
+func (n *PushNotifier) sendGateway(msg pushMessage, devices []db.PushDevice) (int, error) {
+	gatewayURL, _ := n.store.GetSetting(PushGatewayURLKey)
+	if gatewayURL == "" {
+		return 0, fmt.Errorf("%s is not set, can't reach %d mobile devices", PushGatewayURLKey, len(devices))
+	}
+	// SECURITY: Validate URL scheme to prevent SSRF if database is compromised
+	parsedURL, err := url.Parse(gatewayURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return 0, errors.New("invalid push gateway URL")
+	}
+
+	payload := struct {
+		Notification pushMessage     `json:"notification"`
+		Devices      []gatewayDevice `json:"devices"`
+	}{Notification: msg}
+	for _, d := range devices {
+		payload.Devices = append(payload.Devices, gatewayDevice{Platform: d.Platform, Token: d.Token})
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest("POST", gatewayURL, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token, _ := n.store.GetSetting(PushGatewayTokenKey); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+	resp, err := client.Do(req) // #nosec G704 -- URL scheme validated above
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("push gateway returned status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Rejected []string `json:"rejected"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	for _, token := range result.Rejected {
+		if err := n.store.DeletePushDeviceByToken(token); err != nil {
+			log.Printf("Failed to unregister rejected push device: %v", err)
+		}
+	}
+	return max(len(devices)-len(result.Rejected), 0), nil
+}
+
+// sendWeb sends the message to each browser with Web Push and returns how many it
+// reached.
+func (n *PushNotifier) sendWeb(msg pushMessage, devices []db.PushDevice) (int, error) {
+	key, err := vapidKey(n.store)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load VAPID key: %w", err)
+	}
+	subject, _ := n.store.GetSetting(VAPIDSubjectKey)
+	if subject == "" {
+		subject = DefaultVAPIDSubject
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+	urgency := "normal"
+	if msg.Event == EventDown || msg.Event == EventPlatformDown {
+		urgency = "high"
+	}
+
+	now := time.Now()
+	sent := 0
+	var lastErr error
+	for _, d := range devices {
+		err := errPushDeviceGone
+		if !d.ExpiredAt(now) {
+			err = sendWebPush(d, payload, urgency, key, subject)
+		}
+		switch {
+		case errors.Is(err, errPushDeviceGone):
+			if err := n.store.DeletePushDeviceByToken(d.Token); err != nil {
+				log.Printf("Failed to unregister expired push subscription: %v", err)
+			}
+		case err != nil:
+			lastErr = err
+		default:
+			sent++
+		}
+	}
+	return sent, lastErr
+}
@@ -0,0 +1,206 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func createPushUser(t *testing.T, store *db.Store) int64 {
+	t.Helper()
+	if err := store.CreateUser("alice", "password123", "UTC"); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	user, err := store.Authenticate("alice", "password123")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	return user.ID
+}
+
+func registerDevice(t *testing.T, store *db.Store, d db.PushDevice) {
+	t.Helper()
+	if _, err := store.RegisterPushDevice(d); err != nil {
+		t.Fatalf("RegisterPushDevice failed: %v", err)
+	}
+}
+
+func pushTestEvent(eventType EventType) NotificationEvent {
+	return NotificationEvent{MonitorID: "m1", MonitorName: "API", Type: eventType, Message: "connection refused", Time: time.Now()}
+}
+
+func TestPushNotifier_Gateway(t *testing.T) {
+	store := newTestStore(t)
+	userID := createPushUser(t, store)
+	registerDevice(t, store, db.PushDevice{ID: "pd-1", UserID: userID, Platform: db.PushPlatformFCM, Token: "fcm-live"})
+	registerDevice(t, store, db.PushDevice{ID: "pd-2", UserID: userID, Platform: db.PushPlatformAPNs, Token: "apns-stale"})
+
+	var received struct {
+		Notification pushMessage     `json:"notification"`
+		Devices      []gatewayDevice `json:"devices"`
+	}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"rejected":["apns-stale"]}`))
+	}))
+	defer server.Close()
+
+	n := NewPushNotifier(store, `{}`)
+	// Without a gateway, mobile devices can't be reached
+	if err := n.Send(pushTestEvent(EventDown)); err == nil || !strings.Contains(err.Error(), PushGatewayURLKey) {
+		t.Errorf("Expected a missing gateway error, got %v", err)
+	}
+
+	_ = store.SetSetting(PushGatewayURLKey, server.URL)
+	_ = store.SetSetting(PushGatewayTokenKey, "gw-secret")
+	if err := n.Send(pushTestEvent(EventDown)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if auth != "Bearer gw-secret" {
+		t.Errorf("Expected the gateway token, got %q", auth)
+	}
+	if len(received.Devices) != 2 || received.Notification.Event != EventDown || received.Notification.Title != "Monitor Down: API" || received.Notification.Tag != "monitor-m1" {
+		t.Errorf("Unexpected gateway payload %+v", received)
+	}
+
+	// Rejected tokens are unregistered
+	devices, _ := store.GetPushDevices()
+	if len(devices) != 1 || devices[0].Token != "fcm-live" {
+		t.Errorf("Expected only the live device to remain, got %+v", devices)
+	}
+
+	// Other events aren't pushed
+	received.Devices = nil
+	if err := n.Send(pushTestEvent(EventDegraded)); err != nil || received.Devices != nil {
+		t.Errorf("Expected degraded events to be skipped, got %v, %+v", err, received)
+	}
+}
+
+func TestPushNotifier_WebPush(t *testing.T) {
+	store := newTestStore(t)
+	userID := createPushUser(t, store)
+	sub := newTestSubscription(t)
+
+	var mu sync.Mutex
+	var bodies [][]byte
+	var headers []http.Header
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		headers = append(headers, r.Header)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	SetTransport(server.Client().Transport)
+	defer SetTransport(nil)
+
+	registerDevice(t, store, db.PushDevice{ID: "pd-1", UserID: userID, Platform: db.PushPlatformWeb, Token: server.URL + "/push/live", P256DH: sub.p256dh(), Auth: sub.authSecret()})
+	registerDevice(t, store, db.PushDevice{ID: "pd-2", UserID: userID, Platform: db.PushPlatformWeb, Token: server.URL + "/push/gone", P256DH: sub.p256dh(), Auth: sub.authSecret()})
+
+	if err := NewPushNotifier(store, `{"locale":"de"}`).Send(pushTestEvent(EventDown)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("Expected one push, got %d", len(bodies))
+	}
+	h := headers[0]
+	if h.Get("Content-Encoding") != "aes128gcm" || h.Get("Urgency") != "high" || !strings.HasPrefix(h.Get("Authorization"), "vapid t=") {
+		t.Errorf("Unexpected headers %v", h)
+	}
+	var msg pushMessage
+	if err := json.Unmarshal(sub.decrypt(t, bodies[0]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Title != "Monitor ausgefallen: API" || msg.Body != "connection refused" || msg.MonitorID != "m1" {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+
+	// The expired subscription was unregistered
+	devices, _ := store.GetPushDevices()
+	if len(devices) != 1 || devices[0].ID != "pd-1" {
+		t.Errorf("Expected only the live subscription to remain, got %+v", devices)
+	}
+}
+
+func TestPushNotifier_FailsOnlyWhenNoDeviceReached(t *testing.T) {
+	store := newTestStore(t)
+	userID := createPushUser(t, store)
+	registerDevice(t, store, db.PushDevice{ID: "pd-1", UserID: userID, Platform: db.PushPlatformFCM, Token: "fcm-1"})
+
+	status := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	_ = store.SetSetting(PushGatewayURLKey, server.URL)
+
+	n := NewPushNotifier(store, `{}`)
+	if err := n.Send(pushTestEvent(EventUp)); err == nil {
+		t.Error("Expected an error when the gateway fails")
+	}
+	status = http.StatusOK
+	if err := n.Send(pushTestEvent(EventUp)); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+}
+
+func TestPushNotifier_PlatformEventsAndExpiry(t *testing.T) {
+	store := newTestStore(t)
+	userID := createPushUser(t, store)
+	sub := newTestSubscription(t)
+
+	var mu sync.Mutex
+	var paths []string
+	var bodies [][]byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	SetTransport(server.Client().Transport)
+	defer SetTransport(nil)
+
+	expired := time.Now().Add(-time.Minute)
+	registerDevice(t, store, db.PushDevice{ID: "pd-1", UserID: userID, Platform: db.PushPlatformWeb, Token: server.URL + "/live", P256DH: sub.p256dh(), Auth: sub.authSecret()})
+	registerDevice(t, store, db.PushDevice{ID: "pd-2", UserID: userID, Platform: db.PushPlatformWeb, Token: server.URL + "/expired", P256DH: sub.p256dh(), Auth: sub.authSecret(), ExpiresAt: &expired})
+
+	event := NotificationEvent{MonitorName: "12 monitors", Type: EventPlatformDown, Message: "12 of 40 monitors down", Time: time.Now()}
+	if err := NewPushNotifier(store, `{}`).Send(event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	// Expired subscriptions are dropped without being sent to
+	if len(paths) != 1 || paths[0] != "/live" {
+		t.Fatalf("Expected a push to the live subscription only, got %v", paths)
+	}
+	if devices, _ := store.GetPushDevices(); len(devices) != 1 || devices[0].ID != "pd-1" {
+		t.Errorf("Expected the expired subscription to be unregistered, got %+v", devices)
+	}
+
+	var msg pushMessage
+	if err := json.Unmarshal(sub.decrypt(t, bodies[0]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Event != EventPlatformDown || msg.Tag != "platform" || msg.Title != "Platform Event: 12 monitors" {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+}
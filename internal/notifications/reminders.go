@@ -0,0 +1,179 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// MaintenanceReminderHoursKey is the setting holding a comma-separated list of
+	// hours before a maintenance window starts at which reminders are sent.
+	MaintenanceReminderHoursKey = "notification.maintenance.reminder_hours"
+	// DefaultMaintenanceReminderHours applies when the setting was never saved.
+	// Saving an empty value disables reminders.
+	DefaultMaintenanceReminderHours = "24,1"
+	MaxMaintenanceReminders         = 5
+	MaxMaintenanceReminderHours     = 720 // 30 days
+
+	reminderInterval = time.Minute
+)
+
+// ParseReminderHours parses a reminder_hours setting into distinct thresholds,
+// largest first. An empty string yields no reminders.
+func ParseReminderHours(val string) ([]int, error) {
+	var hours []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(val, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		h, err := strconv.Atoi(part)
+		if err != nil || h < 1 || h > MaxMaintenanceReminderHours {
+			return nil, fmt.Errorf("reminder hours must be between 1 and %d", MaxMaintenanceReminderHours)
+		}
+		if !seen[h] {
+			seen[h] = true
+			hours = append(hours, h)
+		}
+	}
+	if len(hours) > MaxMaintenanceReminders {
+		return nil, fmt.Errorf("at most %d reminders are allowed", MaxMaintenanceReminders)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(hours)))
+	return hours, nil
+}
+
+// SetLeaderCheck makes the reminder scheduler and queue delivery run only while
+// isLeader reports true, so HA standbys don't send duplicates.
+func (s *Service) SetLeaderCheck(isLeader func() bool) {
+	s.isLeader = isLeader
+}
+
+func (s *Service) reminderWorker() {
+	ticker := time.NewTicker(reminderInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.reminderWake:
+		}
+		if s.isLeader != nil && !s.isLeader() {
+			continue
+		}
+		s.sendMaintenanceReminders(time.Now())
+	}
+}
+
+// settingsChanged sends reminders that new reminder hours make due right away, rather
+// than on the scheduler's next tick.
+func (s *Service) settingsChanged(changed map[string]string) {
+	if _, ok := changed[MaintenanceReminderHoursKey]; !ok {
+		return
+	}
+	select {
+	case s.reminderWake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) reminderHours() []int {
+	val, err := s.store.GetSetting(MaintenanceReminderHoursKey)
+	if err != nil {
+		val = DefaultMaintenanceReminderHours
+	}
+	hours, err := ParseReminderHours(val)
+	if err != nil {
+		log.Printf("Invalid %s %q: %v", MaintenanceReminderHoursKey, val, err)
+		return nil
+	}
+	return hours
+}
+
+// sendMaintenanceReminders enqueues a reminder for every upcoming maintenance window
+// that has crossed one of the configured thresholds since the last run. Only the
+// closest threshold is announced when several pass at once (e.g. after downtime).
+// Thresholds that had already passed when the window was created are skipped, since
+// the scheduling notification covered them.
+//
+// Note: reminders go to notification channels only; status pages have no
+// subscriber list to deliver to.
+func (s *Service) sendMaintenanceReminders(now time.Time) {
+	hours := s.reminderHours()
+	if len(hours) == 0 {
+		return
+	}
+
+	incidents, err := s.store.GetIncidents(now)
+	if err != nil {
+		log.Printf("Failed to fetch maintenance windows for reminders: %v", err)
+		return
+	}
+
+	for _, inc := range incidents {
+		if inc.Type != "maintenance" || inc.Status == "completed" || inc.EndTime == nil || !inc.StartTime.After(now) {
+			continue
+		}
+
+		announce := 0
+		for _, h := range hours {
+			remindAt := inc.StartTime.Add(-time.Duration(h) * time.Hour)
+			if remindAt.After(now) {
+				continue
+			}
+			inserted, err := s.store.MarkMaintenanceReminderSent(inc.ID, h, inc.StartTime)
+			if err != nil {
+				log.Printf("Failed to record maintenance reminder for %s: %v", inc.ID, err)
+				continue
+			}
+			// hours is sorted largest first, so the last match is the closest threshold
+			if inserted && remindAt.After(inc.CreatedAt) {
+				announce = h
+			}
+		}
+		if announce == 0 {
+			continue
+		}
+
+		s.Enqueue(maintenanceReminderEvent(inc, announce, now))
+	}
+}
+
+func maintenanceReminderEvent(inc db.Incident, hoursBefore int, now time.Time) NotificationEvent {
+	return NotificationEvent{
+		MonitorID:   inc.ID,
+		MonitorName: inc.Title,
+		Type:        EventMaintenanceReminder,
+		Message:     fmt.Sprintf("Maintenance %q starts in %s", inc.Title, formatReminderLead(hoursBefore)),
+		Time:        now,
+		Maintenance: &MaintenanceWindow{
+			ID:          inc.ID,
+			Title:       inc.Title,
+			Description: inc.Description,
+			Start:       inc.StartTime,
+			End:         *inc.EndTime,
+		},
+	}
+}
+
+// formatReminderLead renders a threshold as "1 hour", "6 hours" or "2 days".
+func formatReminderLead(hours int) string {
+	switch {
+	case hours == 1:
+		return "1 hour"
+	case hours == 24:
+		return "1 day"
+	case hours%24 == 0:
+		return strconv.Itoa(hours/24) + " days"
+	default:
+		return strconv.Itoa(hours) + " hours"
+	}
+}
@@ -0,0 +1,183 @@
+package notifications
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestParseReminderHours(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"24,1", []int{24, 1}, false},
+		{" 1, 24 ,6", []int{24, 6, 1}, false},
+		{"1,1,24", []int{24, 1}, false},
+		{"", nil, false},
+		{"0", nil, true},
+		{"721", nil, true},
+		{"abc", nil, true},
+		{"1,2,3,4,5,6", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReminderHours(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReminderHours(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseReminderHours(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func createMaintenance(t *testing.T, store *db.Store, id string, start time.Time) {
+	t.Helper()
+	end := start.Add(2 * time.Hour)
+	if err := store.CreateIncident(db.Incident{
+		ID: id, Title: "DB upgrade", Type: "maintenance", Severity: "minor",
+		Status: "scheduled", StartTime: start, EndTime: &end, AffectedGroups: "[]",
+	}); err != nil {
+		t.Fatalf("CreateIncident: %v", err)
+	}
+}
+
+// addTestChannel creates an enabled channel, so that enqueued events are stored.
+func addTestChannel(t *testing.T, store *db.Store) {
+	t.Helper()
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc-test", Type: "webhook", Name: "Test", Config: `{"webhookUrl":"http://127.0.0.1:1"}`, Enabled: true,
+	}); err != nil {
+		t.Fatalf("CreateNotificationChannel: %v", err)
+	}
+}
+
+// drainQueue takes every queued notification off the queue.
+func drainQueue(t *testing.T, store *db.Store) []NotificationEvent {
+	t.Helper()
+	items, err := store.ClaimDueNotifications(time.Now().Add(24*time.Hour), time.Minute, 1000)
+	if err != nil {
+		t.Fatalf("ClaimDueNotifications: %v", err)
+	}
+	var events []NotificationEvent
+	for _, item := range items {
+		var e NotificationEvent
+		if err := json.Unmarshal([]byte(item.Payload), &e); err != nil {
+			t.Fatalf("unreadable payload: %v", err)
+		}
+		events = append(events, e)
+		if err := store.DeleteQueuedNotification(item.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return events
+}
+
+func TestSendMaintenanceReminders(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+	addTestChannel(t, store)
+
+	now := time.Now()
+	start := now.Add(48 * time.Hour)
+	createMaintenance(t, store, "maint-1", start)
+
+	// Before any threshold
+	svc.sendMaintenanceReminders(now.Add(time.Hour))
+	if events := drainQueue(t, store); len(events) != 0 {
+		t.Fatalf("expected no reminders yet, got %d", len(events))
+	}
+
+	// 24h threshold crossed
+	svc.sendMaintenanceReminders(start.Add(-23 * time.Hour))
+	events := drainQueue(t, store)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 reminder, got %d", len(events))
+	}
+	if events[0].Type != EventMaintenanceReminder || events[0].Maintenance == nil || events[0].Maintenance.ID != "maint-1" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+	if !strings.Contains(events[0].Message, "1 day") {
+		t.Errorf("message = %q, want lead time of 1 day", events[0].Message)
+	}
+
+	// Same threshold is not sent twice
+	svc.sendMaintenanceReminders(start.Add(-22 * time.Hour))
+	if events := drainQueue(t, store); len(events) != 0 {
+		t.Fatalf("expected no duplicate reminder, got %d", len(events))
+	}
+
+	// 1h threshold crossed
+	svc.sendMaintenanceReminders(start.Add(-30 * time.Minute))
+	events = drainQueue(t, store)
+	if len(events) != 1 || !strings.Contains(events[0].Message, "1 hour") {
+		t.Fatalf("expected the 1 hour reminder, got %+v", events)
+	}
+
+	// Window already started
+	svc.sendMaintenanceReminders(start.Add(time.Minute))
+	if events := drainQueue(t, store); len(events) != 0 {
+		t.Fatalf("expected no reminders after start, got %d", len(events))
+	}
+}
+
+func TestSendMaintenanceReminders_SkipsThresholdsBeforeCreation(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+	addTestChannel(t, store)
+
+	// Both default thresholds had passed when this window was scheduled
+	now := time.Now()
+	createMaintenance(t, store, "maint-late", now.Add(30*time.Minute))
+
+	svc.sendMaintenanceReminders(now.Add(time.Minute))
+	if events := drainQueue(t, store); len(events) != 0 {
+		t.Fatalf("expected no reminders, got %d", len(events))
+	}
+}
+
+func TestSendMaintenanceReminders_Disabled(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+	addTestChannel(t, store)
+	if err := store.SetSetting(MaintenanceReminderHoursKey, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(48 * time.Hour)
+	createMaintenance(t, store, "maint-off", start)
+
+	svc.sendMaintenanceReminders(start.Add(-30 * time.Minute))
+	if events := drainQueue(t, store); len(events) != 0 {
+		t.Fatalf("expected no reminders when disabled, got %d", len(events))
+	}
+}
+
+func TestReminderHoursChangeWakesScheduler(t *testing.T) {
+	store := newTestStore(t)
+	svc := NewService(store)
+
+	if err := store.SetSetting("notification.digest.enabled", "true"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-svc.reminderWake:
+		t.Fatal("expected other settings to leave the scheduler alone")
+	default:
+	}
+
+	if err := store.SetSetting(MaintenanceReminderHoursKey, "48,24"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-svc.reminderWake:
+	default:
+		t.Fatal("expected new reminder hours to wake the scheduler")
+	}
+}
@@ -0,0 +1,289 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// VAPIDPrivateKeySettingKey holds the private VAPID key (RFC 8292) that identifies
+	// Warden to browsers' push services. It is not in the settings schema, so it can't be
+	// read or written through /settings.
+	VAPIDPrivateKeySettingKey = "push.vapid_private_key"
+	// VAPIDSubjectKey is the setting holding the contact, a mailto: or https: URL, that
+	// push services can reach the operator at.
+	VAPIDSubjectKey     = "push.vapid_subject"
+	DefaultVAPIDSubject = "https://github.com/projecthelena/warden"
+
+	webPushTTL        = 24 * time.Hour // How long a push service keeps a notification for an offline browser
+	webPushRecordSize = 4096
+	vapidTokenTTL     = 12 * time.Hour
+)
+
+// errPushDeviceGone is returned for a device the push service no longer knows, which
+// should be unregistered.
+var errPushDeviceGone = errors.New("push subscription is gone")
+
+// VAPIDPublicKey returns the public VAPID key browsers subscribe with, base64url encoded,
+// generating the key pair on first use.
+func VAPIDPublicKey(store *db.Store) (string, error) {
+	key, err := vapidKey(store)
+	if err != nil {
+		return "", err
+	}
+	return vapidPublicKey(key)
+}
+
+// vapidKey loads the VAPID private key, or generates and stores one. Concurrent first
+// sends, here or on other instances, all use the key stored first, so browsers never
+// subscribe with a public key that lost the race.
+func vapidKey(store *db.Store) (*ecdsa.PrivateKey, error) {
+	raw, err := store.GetSetting(VAPIDPrivateKeySettingKey)
+	if err != nil || raw == "" {
+		fresh, err := newVAPIDKey()
+		if err != nil {
+			return nil, err
+		}
+		if raw, err = store.SetSettingIfAbsent(VAPIDPrivateKeySettingKey, fresh); err != nil {
+			return nil, err
+		}
+	}
+	return parseVAPIDKey(raw)
+}
+
+// RotateVAPIDKey replaces the VAPID key pair and returns the new public key. Browsers
+// subscribed with the old key can't receive notifications anymore and have to subscribe
+// again.
+func RotateVAPIDKey(store *db.Store) (string, error) {
+	raw, err := newVAPIDKey()
+	if err != nil {
+		return "", err
+	}
+	key, err := parseVAPIDKey(raw)
+	if err != nil {
+		return "", err
+	}
+	if err := store.SetSetting(VAPIDPrivateKeySettingKey, raw); err != nil {
+		return "", err
+	}
+	return vapidPublicKey(key)
+}
+
+// newVAPIDKey generates a VAPID private key, encoded as stored.
+func newVAPIDKey() (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	ecdhKey, err := key.ECDH()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(ecdhKey.Bytes()), nil
+}
+
+// parseVAPIDKey decodes a stored VAPID private key.
+func parseVAPIDKey(raw string) (*ecdsa.PrivateKey, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID key: %w", err)
+	}
+	return ecdsa.ParseRawPrivateKey(elliptic.P256(), b)
+}
+
+func vapidPublicKey(key *ecdsa.PrivateKey) (string, error) {
+	pub, err := key.PublicKey.ECDH()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(pub.Bytes()), nil
+}
+
+// vapidAuthorization returns the Authorization header for a push to endpoint: a JWT
+// signed with the VAPID key, for the endpoint's origin, and the public key.
+func vapidAuthorization(endpoint *url.URL, key *ecdsa.PrivateKey, subject string, now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": now.Add(vapidTokenTTL).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS wants the raw 32-byte R and S, not ASN.1
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	pub, err := vapidPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return "vapid t=" + unsigned + "." + base64.RawURLEncoding.EncodeToString(sig) + ", k=" + pub, nil
+}
+
+// decodeKey decodes a base64url subscription key, padded or not.
+func decodeKey(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// ValidateWebPushKeys checks a browser subscription's p256dh key and auth secret.
+func ValidateWebPushKeys(p256dh, auth string) error {
+	key, err := decodeKey(p256dh)
+	if err != nil {
+		return errors.New("p256dh must be base64url encoded")
+	}
+	if _, err := ecdh.P256().NewPublicKey(key); err != nil {
+		return errors.New("p256dh must be a P-256 public key")
+	}
+	if secret, err := decodeKey(auth); err != nil || len(secret) != 16 {
+		return errors.New("auth must be a base64url encoded 16-byte secret")
+	}
+	return nil
+}
+
+// encryptWebPush encrypts payload for a browser's subscription keys as a single
+// aes128gcm record (RFC 8291, RFC 8188).
+func encryptWebPush(payload []byte, p256dh, auth string) ([]byte, error) {
+	uaKey, err := decodeKey(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decodeKey(auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("invalid auth secret")
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	if len(payload) > webPushRecordSize-17 {
+		return nil, errors.New("payload too large")
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	secret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mix the shared secret with the browser's auth secret, then derive the content
+	// key and nonce from a random salt
+	prkKey, err := hkdf.Extract(sha256.New, secret, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaKey) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 marks the last (and only) record
+	record := gcm.Seal(nil, nonce, append(append([]byte{}, payload...), 0x02), nil)
+
+	// Header: salt, record size, key ID length and our ephemeral public key as key ID
+	var body bytes.Buffer
+	body.Write(salt)
+	_ = binary.Write(&body, binary.BigEndian, uint32(webPushRecordSize))
+	body.WriteByte(byte(len(asPublic)))
+	body.Write(asPublic)
+	body.Write(record)
+	return body.Bytes(), nil
+}
+
+// sendWebPush encrypts payload for a browser and posts it to its push service.
+func sendWebPush(device db.PushDevice, payload []byte, urgency string, key *ecdsa.PrivateKey, subject string) error {
+	// SECURITY: Push services are only reached over HTTPS
+	endpoint, err := url.Parse(device.Token)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return errors.New("invalid push endpoint")
+	}
+	body, err := encryptWebPush(payload, device.P256DH, device.Auth)
+	if err != nil {
+		return err
+	}
+	authorization, err := vapidAuthorization(endpoint, key, subject, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", urgency)
+	req.Header.Set("Authorization", authorization)
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+	resp, err := client.Do(req) // #nosec G704 -- HTTPS endpoint validated above
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errPushDeviceGone
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("push service returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
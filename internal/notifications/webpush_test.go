@@ -0,0 +1,184 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSubscription is a browser's side of a push subscription.
+type testSubscription struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+func newTestSubscription(t *testing.T) testSubscription {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+	return testSubscription{private: key, auth: auth}
+}
+
+func (s testSubscription) p256dh() string {
+	return base64.RawURLEncoding.EncodeToString(s.private.PublicKey().Bytes())
+}
+
+func (s testSubscription) authSecret() string {
+	return base64.RawURLEncoding.EncodeToString(s.auth)
+}
+
+// decrypt reverses encryptWebPush as the browser would.
+func (s testSubscription) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != webPushRecordSize {
+		t.Fatalf("Unexpected record size %d", rs)
+	}
+	idLen := int(body[20])
+	asKey, err := ecdh.P256().NewPublicKey(body[21 : 21+idLen])
+	if err != nil {
+		t.Fatalf("Invalid key ID: %v", err)
+	}
+	secret, err := s.private.ECDH(asKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prkKey, _ := hkdf.Extract(sha256.New, secret, s.auth)
+	ikm, _ := hkdf.Expand(sha256.New, prkKey, "WebPush: info\x00"+string(s.private.PublicKey().Bytes())+string(asKey.Bytes()), 32)
+	prk, _ := hkdf.Extract(sha256.New, ikm, salt)
+	cek, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("Failed to decrypt: %v", err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("Expected the last record delimiter, got %x", plain[len(plain)-1])
+	}
+	return plain[:len(plain)-1]
+}
+
+func TestEncryptWebPush(t *testing.T) {
+	sub := newTestSubscription(t)
+	payload := []byte(`{"title":"Monitor Down: API"}`)
+
+	body, err := encryptWebPush(payload, sub.p256dh(), sub.authSecret())
+	if err != nil {
+		t.Fatalf("encryptWebPush failed: %v", err)
+	}
+	if got := sub.decrypt(t, body); !bytes.Equal(got, payload) {
+		t.Errorf("Expected %s, got %s", payload, got)
+	}
+
+	// Padded keys, as some browsers send them, work too
+	if _, err := encryptWebPush(payload, sub.p256dh()+"=", sub.authSecret()+"=="); err != nil {
+		t.Errorf("Expected padded keys to be accepted, got %v", err)
+	}
+	if _, err := encryptWebPush(payload, "bm90LWEta2V5", sub.authSecret()); err == nil {
+		t.Error("Expected an invalid p256dh key to fail")
+	}
+	if _, err := encryptWebPush(payload, sub.p256dh(), "c2hvcnQ"); err == nil {
+		t.Error("Expected a short auth secret to fail")
+	}
+	if _, err := encryptWebPush(make([]byte, webPushRecordSize), sub.p256dh(), sub.authSecret()); err == nil {
+		t.Error("Expected an oversized payload to fail")
+	}
+}
+
+func TestVAPIDKey(t *testing.T) {
+	store := newTestStore(t)
+	pub, err := VAPIDPublicKey(store)
+	if err != nil {
+		t.Fatalf("VAPIDPublicKey failed: %v", err)
+	}
+	if raw, _ := base64.RawURLEncoding.DecodeString(pub); len(raw) != 65 || raw[0] != 0x04 {
+		t.Errorf("Expected an uncompressed P-256 point, got %q", pub)
+	}
+	// Generated once, then reused
+	if again, _ := VAPIDPublicKey(store); again != pub {
+		t.Errorf("Expected the stored key to be reused, got %q then %q", pub, again)
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint, _ := url.Parse("https://push.example.com/send/abc")
+	now := time.Unix(1700000000, 0)
+
+	header, err := vapidAuthorization(endpoint, key, "mailto:ops@example.com", now)
+	if err != nil {
+		t.Fatalf("vapidAuthorization failed: %v", err)
+	}
+	token, pub, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !ok || !strings.HasPrefix(header, "vapid t=") {
+		t.Fatalf("Unexpected header %q", header)
+	}
+	if want, _ := vapidPublicKey(key); pub != want {
+		t.Errorf("Expected k=%s, got %s", want, pub)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected a JWT, got %q", token)
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	raw, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Aud != "https://push.example.com" || claims.Sub != "mailto:ops@example.com" || claims.Exp != now.Add(vapidTokenTTL).Unix() {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if len(sig) != 64 || !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+		t.Error("Expected a valid ES256 signature")
+	}
+}
+
+func TestRotateVAPIDKey(t *testing.T) {
+	store := newTestStore(t)
+	before, err := VAPIDPublicKey(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := RotateVAPIDKey(store)
+	if err != nil {
+		t.Fatalf("RotateVAPIDKey failed: %v", err)
+	}
+	if rotated == before {
+		t.Error("Expected a new key")
+	}
+	if current, _ := VAPIDPublicKey(store); current != rotated {
+		t.Errorf("Expected the rotated key to be stored, got %q", current)
+	}
+}
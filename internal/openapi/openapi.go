@@ -0,0 +1,220 @@
+// Package openapi reads the GET operations of an OpenAPI 3 or Swagger 2 document, so
+// the API they describe can be monitored without entering each endpoint by hand.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Spec is the part of an API description needed to monitor its endpoints.
+type Spec struct {
+	Title      string      `json:"title"`
+	Version    string      `json:"version"`
+	BaseURL    string      `json:"baseUrl"` // Empty when the document doesn't say where the API is served
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is a GET operation of the document.
+type Operation struct {
+	ID          string   `json:"id"` // "GET /path", unique within the document
+	OperationID string   `json:"operationId,omitempty"`
+	Path        string   `json:"path"`
+	Name        string   `json:"name"` // Summary, else operationId, else ID
+	Tags        []string `json:"tags,omitempty"`
+	// ExpectedStatus is the documented 2xx responses in accepted status code syntax,
+	// e.g. "200" or "200,204"; "200-299" for a 2XX range or a default response.
+	ExpectedStatus string `json:"expectedStatus,omitempty"`
+	// Reason is set when the operation can't be checked without input, such as a
+	// path parameter, or has no success response.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Monitorable reports whether the operation can be checked as it is.
+func (o Operation) Monitorable() bool {
+	return o.Reason == ""
+}
+
+// ErrNotOpenAPI is returned for documents that are neither OpenAPI 3 nor Swagger 2 JSON.
+var ErrNotOpenAPI = errors.New("not an OpenAPI 3 or Swagger 2 JSON document")
+
+type document struct {
+	OpenAPI string `json:"openapi"`
+	Swagger string `json:"swagger"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Host     string                                `json:"host"`
+	BasePath string                                `json:"basePath"`
+	Schemes  []string                              `json:"schemes"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type parameter struct {
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type operation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []parameter                `json:"parameters"`
+	Responses   map[string]json.RawMessage `json:"responses"`
+}
+
+// Parse reads a JSON OpenAPI 3 or Swagger 2 document fetched from specURL, which
+// relative server URLs are resolved against. Operations are sorted by path.
+func Parse(data []byte, specURL string) (*Spec, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, ErrNotOpenAPI
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") && doc.Swagger != "2.0" {
+		return nil, ErrNotOpenAPI
+	}
+
+	spec := &Spec{Title: doc.Info.Title, Version: doc.Info.Version, Operations: []Operation{}}
+	base, _ := url.Parse(specURL)
+	spec.BaseURL = baseURL(doc, base)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		item := doc.Paths[p]
+		raw, ok := item["get"]
+		if !ok {
+			continue
+		}
+		var op operation
+		if err := json.Unmarshal(raw, &op); err != nil {
+			continue
+		}
+		var shared []parameter
+		if rawParams, ok := item["parameters"]; ok {
+			_ = json.Unmarshal(rawParams, &shared)
+		}
+		spec.Operations = append(spec.Operations, newOperation(p, op, append(shared, op.Parameters...)))
+	}
+	return spec, nil
+}
+
+func newOperation(path string, op operation, params []parameter) Operation {
+	o := Operation{
+		ID:             "GET " + path,
+		OperationID:    op.OperationID,
+		Path:           path,
+		Name:           strings.TrimSpace(op.Summary),
+		Tags:           op.Tags,
+		ExpectedStatus: expectedStatus(op.Responses),
+	}
+	if o.Name == "" {
+		o.Name = o.OperationID
+	}
+	if o.Name == "" {
+		o.Name = o.ID
+	}
+
+	switch {
+	case strings.Contains(path, "{"):
+		o.Reason = "needs path parameters"
+	case requiresInput(params):
+		o.Reason = "needs required query or header parameters"
+	case o.ExpectedStatus == "":
+		o.Reason = "documents no 2xx response"
+	}
+	return o
+}
+
+func requiresInput(params []parameter) bool {
+	for _, p := range params {
+		if p.Required && (p.In == "query" || p.In == "header" || p.In == "path") {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedStatus turns the documented success responses into accepted status codes.
+func expectedStatus(responses map[string]json.RawMessage) string {
+	var codes []int
+	for code := range responses {
+		switch c := strings.ToUpper(code); {
+		case c == "2XX":
+			return "200-299"
+		case len(c) == 3 && c[0] == '2':
+			if n, err := strconv.Atoi(c); err == nil {
+				codes = append(codes, n)
+			}
+		}
+	}
+	if len(codes) == 0 {
+		if _, ok := responses["default"]; ok {
+			return "200-299"
+		}
+		return ""
+	}
+	sort.Ints(codes)
+	parts := make([]string, len(codes))
+	for i, c := range codes {
+		parts[i] = strconv.Itoa(c)
+	}
+	return strings.Join(parts, ",")
+}
+
+// baseURL returns where the API is served: the first server of an OpenAPI 3 document,
+// or the scheme, host and base path of a Swagger 2 one, defaulting to the document's
+// own location. Server URLs with variables are left to the caller.
+func baseURL(doc document, specURL *url.URL) string {
+	if doc.Swagger != "" {
+		u := url.URL{Host: doc.Host, Path: doc.BasePath}
+		if specURL != nil {
+			u.Scheme = specURL.Scheme
+			if u.Host == "" {
+				u.Host = specURL.Host
+			}
+		}
+		for _, s := range doc.Schemes {
+			if s == "https" || u.Scheme == "" {
+				u.Scheme = s
+			}
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return ""
+		}
+		return strings.TrimSuffix(u.String(), "/")
+	}
+
+	if len(doc.Servers) == 0 {
+		if specURL == nil || specURL.Host == "" {
+			return ""
+		}
+		return specURL.Scheme + "://" + specURL.Host
+	}
+	server := doc.Servers[0].URL
+	if strings.Contains(server, "{") {
+		return ""
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	if !u.IsAbs() {
+		if specURL == nil || !specURL.IsAbs() {
+			return ""
+		}
+		u = specURL.ResolveReference(u)
+	}
+	return strings.TrimSuffix(u.String(), "/")
+}
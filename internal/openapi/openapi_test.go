@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+)
+
+const petstore = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Petstore", "version": "1.2"},
+  "servers": [{"url": "/v1"}],
+  "paths": {
+    "/pets": {
+      "get": {"summary": "List pets", "tags": ["Pet Store"], "responses": {"200": {}, "404": {}}},
+      "post": {"summary": "Create a pet", "responses": {"201": {}}}
+    },
+    "/pets/{id}": {"get": {"operationId": "getPet", "responses": {"200": {}}}},
+    "/search": {"get": {"parameters": [{"name": "q", "in": "query", "required": true}], "responses": {"200": {}}}},
+    "/health": {"get": {"operationId": "health", "responses": {"2XX": {}}}},
+    "/legacy": {"get": {"responses": {"301": {}}}}
+  }
+}`
+
+func TestParse_OpenAPI3(t *testing.T) {
+	spec, err := Parse([]byte(petstore), "https://api.example.com/openapi.json")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if spec.Title != "Petstore" || spec.BaseURL != "https://api.example.com/v1" {
+		t.Errorf("Unexpected spec %q at %q", spec.Title, spec.BaseURL)
+	}
+	if len(spec.Operations) != 5 {
+		t.Fatalf("Expected the 5 GET operations, got %+v", spec.Operations)
+	}
+	want := map[string]Operation{
+		"GET /health":    {Name: "health", ExpectedStatus: "200-299"},
+		"GET /legacy":    {Name: "GET /legacy", Reason: "documents no 2xx response"},
+		"GET /pets":      {Name: "List pets", ExpectedStatus: "200"},
+		"GET /pets/{id}": {Name: "getPet", ExpectedStatus: "200", Reason: "needs path parameters"},
+		"GET /search":    {Name: "GET /search", ExpectedStatus: "200", Reason: "needs required query or header parameters"},
+	}
+	for _, op := range spec.Operations {
+		w, ok := want[op.ID]
+		if !ok {
+			t.Errorf("Unexpected operation %s", op.ID)
+			continue
+		}
+		if op.Name != w.Name || op.ExpectedStatus != w.ExpectedStatus || op.Reason != w.Reason {
+			t.Errorf("%s: got %+v, want %+v", op.ID, op, w)
+		}
+	}
+	if spec.Operations[2].ID != "GET /pets" || len(spec.Operations[2].Tags) != 1 {
+		t.Errorf("Expected operations sorted by path with their tags, got %+v", spec.Operations[2])
+	}
+}
+
+func TestParse_Swagger2(t *testing.T) {
+	doc := `{"swagger": "2.0", "info": {"title": "Legacy"}, "host": "legacy.example.com", "basePath": "/api/",
+		"schemes": ["http", "https"], "paths": {"/status": {"get": {"responses": {"204": {}, "200": {}}}}}}`
+	spec, err := Parse([]byte(doc), "http://docs.example.com/swagger.json")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if spec.BaseURL != "https://legacy.example.com/api" {
+		t.Errorf("Expected the https base URL, got %q", spec.BaseURL)
+	}
+	if len(spec.Operations) != 1 || spec.Operations[0].ExpectedStatus != "200,204" || !spec.Operations[0].Monitorable() {
+		t.Errorf("Unexpected operations %+v", spec.Operations)
+	}
+}
+
+func TestParse_BaseURL(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{`{"openapi": "3.1.0", "paths": {}}`, "https://docs.example.com"},
+		{`{"openapi": "3.1.0", "servers": [{"url": "https://{region}.example.com"}], "paths": {}}`, ""},
+		{`{"openapi": "3.1.0", "servers": [{"url": "https://api.example.com/"}], "paths": {}}`, "https://api.example.com"},
+	}
+	for _, tt := range tests {
+		spec, err := Parse([]byte(tt.doc), "https://docs.example.com/spec.json")
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if spec.BaseURL != tt.want {
+			t.Errorf("%s: expected base URL %q, got %q", tt.doc, tt.want, spec.BaseURL)
+		}
+	}
+}
+
+func TestParse_NotOpenAPI(t *testing.T) {
+	for _, doc := range []string{`openapi: 3.0.0`, `{"name": "not a spec"}`, `{"swagger": "1.2"}`} {
+		if _, err := Parse([]byte(doc), ""); !errors.Is(err, ErrNotOpenAPI) {
+			t.Errorf("%s: expected ErrNotOpenAPI, got %v", doc, err)
+		}
+	}
+}
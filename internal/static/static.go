@@ -1,16 +1,43 @@
 package static
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"io/fs"
+	"mime"
 	"net/http"
-	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 //go:embed dist/*
 var dist embed.FS
 
+const (
+	// cacheImmutable lets browsers keep hashed build output for good: its name changes
+	// whenever its content does.
+	cacheImmutable = "public, max-age=31536000, immutable"
+	// cacheRevalidate makes browsers check index.html and other unhashed files on every
+	// use, so a new release is picked up right away. The ETag keeps that check cheap.
+	cacheRevalidate = "no-cache"
+)
+
+// hashedAsset matches build output carrying a content hash, e.g. assets/index-B1c2D3e4.js.
+var hashedAsset = regexp.MustCompile(`^assets/.+-[A-Za-z0-9_-]{8,}\.[A-Za-z0-9]+$`)
+
+// precompressed lists the encodings the frontend build stores next to each compressible
+// file (app.js.br, app.js.gz), in order of preference.
+var precompressed = []struct{ encoding, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
 // Handler serves the compiled frontend assets from the embedded dist directory.
 func Handler() http.Handler {
 	return NewHandler(dist)
@@ -18,6 +45,9 @@ func Handler() http.Handler {
 
 // NewHandler serves assets from the provided filesystem.
 // It expects the filesystem to contain a "dist" directory.
+// Unknown paths get index.html, so the single-page app can route them. Responses carry
+// an ETag and cache headers, and a precompressed variant of the file is sent when the
+// client accepts its encoding.
 func NewHandler(assets fs.FS) http.Handler {
 	sub, err := fs.Sub(assets, "dist")
 	if err != nil {
@@ -26,31 +56,75 @@ func NewHandler(assets fs.FS) http.Handler {
 		})
 	}
 
-	fileServer := http.FileServer(http.FS(sub))
+	var etags sync.Map // file name -> ETag of its uncompressed content
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		upath := strings.TrimPrefix(r.URL.Path, "/")
-		if upath == "" {
-			upath = "index.html"
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
 		}
-
-		// If file exists, serve it
-		if _, err := fs.Stat(sub, upath); err == nil {
-			fileServer.ServeHTTP(w, r)
+		// Fallback to index.html for SPA routes
+		if info, err := fs.Stat(sub, name); err != nil || info.IsDir() {
+			name = "index.html"
+		}
+		data, err := fs.ReadFile(sub, name)
+		if err != nil {
+			http.Error(w, "frontend assets not found - build the web app", http.StatusNotFound)
 			return
 		}
 
-		// Fallback to index.html for SPA
-		// Deep copy the URL to avoid modifying the original request unexpectedly (though here it's fine)
-		// and explicitely set path to index.html
-		r2 := new(http.Request)
-		*r2 = *r
-		r2.URL = new(url.URL)
-		*r2.URL = *r.URL
-		r2.URL.Path = "/"
+		h := w.Header()
+		if hashedAsset.MatchString(name) {
+			h.Set("Cache-Control", cacheImmutable)
+		} else {
+			h.Set("Cache-Control", cacheRevalidate)
+		}
+		if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+			h.Set("Content-Type", ctype)
+		}
 
-		// Important: Clear RawQuery if specific behavior needed, or keep it.
-		// fileServer uses URL.Path.
+		etag, ok := etags.Load(name)
+		if !ok {
+			sum := sha256.Sum256(data)
+			etag, _ = etags.LoadOrStore(name, hex.EncodeToString(sum[:16]))
+		}
+		tag := etag.(string)
 
-		fileServer.ServeHTTP(w, r2)
+		content := data
+		varies := false
+		for _, p := range precompressed {
+			compressed, err := fs.ReadFile(sub, name+p.ext)
+			if err != nil {
+				continue
+			}
+			varies = true
+			if h.Get("Content-Encoding") == "" && acceptsEncoding(r, p.encoding) {
+				content = compressed
+				h.Set("Content-Encoding", p.encoding)
+				tag += "-" + p.encoding
+			}
+		}
+		if varies {
+			h.Add("Vary", "Accept-Encoding")
+		}
+		h.Set("ETag", `"`+tag+`"`)
+
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
 	})
 }
+
+// acceptsEncoding reports whether the request's Accept-Encoding allows the encoding.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		token, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(token), encoding) {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
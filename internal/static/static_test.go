@@ -79,3 +79,90 @@ func TestNewHandler_MissingDist(t *testing.T) {
 		t.Errorf("Expected 404 when dist missing, got %d", w.Code)
 	}
 }
+
+func TestNewHandler_CacheHeaders(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"dist/index.html":               &fstest.MapFile{Data: []byte("<html></html>")},
+		"dist/assets/index-B1c2D3e4.js": &fstest.MapFile{Data: []byte("console.log(1)")},
+		"dist/favicon.svg":              &fstest.MapFile{Data: []byte("<svg></svg>")},
+	}
+	handler := NewHandler(mockFS)
+
+	tests := []struct {
+		path         string
+		cacheControl string
+		contentType  string
+	}{
+		{"/", "no-cache", "text/html; charset=utf-8"},
+		{"/status/acme", "no-cache", "text/html; charset=utf-8"},
+		{"/assets/index-B1c2D3e4.js", "public, max-age=31536000, immutable", "text/javascript; charset=utf-8"},
+		{"/favicon.svg", "no-cache", "image/svg+xml"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", tt.path, w.Code)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("%s: expected Cache-Control %q, got %q", tt.path, tt.cacheControl, got)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s: expected Content-Type %q, got %q", tt.path, tt.contentType, got)
+		}
+	}
+
+	// Revalidating with the ETag costs no body
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for a matching ETag, got %d", w.Code)
+	}
+}
+
+func TestNewHandler_Precompressed(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"dist/index.html":                &fstest.MapFile{Data: []byte("<html></html>")},
+		"dist/assets/app-Ab12Cd34.js":    &fstest.MapFile{Data: []byte("plain")},
+		"dist/assets/app-Ab12Cd34.js.br": &fstest.MapFile{Data: []byte("brotli")},
+		"dist/assets/app-Ab12Cd34.js.gz": &fstest.MapFile{Data: []byte("gzip")},
+	}
+	handler := NewHandler(mockFS)
+
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+		body           string
+	}{
+		{"gzip, deflate, br", "br", "brotli"},
+		{"gzip", "gzip", "gzip"},
+		{"br;q=0, gzip", "gzip", "gzip"},
+		{"", "", "plain"},
+	}
+	etags := make(map[string]bool)
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/assets/app-Ab12Cd34.js", nil)
+		if tt.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("Accept-Encoding %q: expected Content-Encoding %q, got %q", tt.acceptEncoding, tt.encoding, got)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("Accept-Encoding %q: expected body %q, got %q", tt.acceptEncoding, tt.body, w.Body.String())
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" || w.Header().Get("Content-Type") != "text/javascript; charset=utf-8" {
+			t.Errorf("Accept-Encoding %q: unexpected headers %v", tt.acceptEncoding, w.Header())
+		}
+		etags[w.Header().Get("ETag")] = true
+	}
+	if len(etags) != 3 {
+		t.Errorf("Expected a distinct ETag per encoding, got %v", etags)
+	}
+}
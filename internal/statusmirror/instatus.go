@@ -0,0 +1,68 @@
+package statusmirror
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// instatusAPIURL is the Instatus REST API.
+var instatusAPIURL = "https://api.instatus.com/v1"
+
+// instatusProvider updates an Instatus page with an API key of its account.
+type instatusProvider struct {
+	pageID string
+	apiKey string
+}
+
+func (p instatusProvider) url(path string) string {
+	return instatusAPIURL + "/" + url.PathEscape(p.pageID) + path
+}
+
+// instatusStatus spells a Statuspage.io component or incident status the way Instatus
+// does, e.g. major_outage as MAJOROUTAGE.
+func instatusStatus(status string) string {
+	return strings.ToUpper(strings.ReplaceAll(status, "_", ""))
+}
+
+func (p instatusProvider) setComponent(ctx context.Context, componentID, status string) error {
+	payload := map[string]string{"status": instatusStatus(status)}
+	return send(ctx, http.MethodPut, p.url("/components/"+url.PathEscape(componentID)), "Bearer "+p.apiKey, payload, nil)
+}
+
+func (p instatusProvider) incidentPayload(inc hostedIncident, started time.Time) map[string]any {
+	ids := make([]string, 0, len(inc.Components))
+	statuses := make([]map[string]string, 0, len(inc.Components))
+	for id, status := range inc.Components {
+		ids = append(ids, id)
+		statuses = append(statuses, map[string]string{"id": id, "status": instatusStatus(status)})
+	}
+	return map[string]any{
+		"name":       inc.Name,
+		"message":    inc.Message,
+		"status":     instatusStatus(inc.Status),
+		"started":    started.UTC().Format(time.RFC3339),
+		"components": ids,
+		"statuses":   statuses,
+		"notify":     true,
+	}
+}
+
+func (p instatusProvider) createIncident(ctx context.Context, inc hostedIncident) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := send(ctx, http.MethodPost, p.url("/incidents"), "Bearer "+p.apiKey, p.incidentPayload(inc, inc.Started), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// updateIncident posts an incident update, which also moves the incident to its status.
+func (p instatusProvider) updateIncident(ctx context.Context, id string, inc hostedIncident) error {
+	payload := p.incidentPayload(inc, time.Now())
+	delete(payload, "name")
+	return send(ctx, http.MethodPost, p.url("/incidents/"+url.PathEscape(id)+"/incident-updates"), "Bearer "+p.apiKey, payload, nil)
+}
@@ -0,0 +1,540 @@
+// Package statusmirror copies status pages to hosted Statuspage.io or Instatus pages,
+// for teams that must keep their existing public page: component statuses follow the
+// monitors mapped to them, and public incidents are opened and updated there.
+package statusmirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// Providers a status page can be mirrored to.
+const (
+	ProviderStatuspage = "statuspage"
+	ProviderInstatus   = "instatus"
+)
+
+// Component statuses, as named by Statuspage.io.
+const (
+	componentOperational = "operational"
+	componentDegraded    = "degraded_performance"
+	componentPartial     = "partial_outage"
+	componentMajor       = "major_outage"
+	componentMaintenance = "under_maintenance"
+)
+
+const (
+	// SyncInterval is how often mirrors are brought up to date; new incidents are
+	// pushed right away.
+	SyncInterval = 30 * time.Second
+
+	requestTimeout = 15 * time.Second
+)
+
+// componentSeverity orders component statuses, so a component several monitors map to
+// shows the worst of them.
+var componentSeverity = map[string]int{
+	componentOperational: 0,
+	componentMaintenance: 1,
+	componentDegraded:    2,
+	componentPartial:     3,
+	componentMajor:       4,
+}
+
+// Statuses reports the live status of a monitor: up, degraded, down, or empty before
+// its first check (implemented by uptime.Manager).
+type Statuses interface {
+	MonitorStatus(id string) string
+}
+
+// Leader reports whether this instance runs background jobs (implemented by uptime.Manager).
+type Leader interface {
+	IsLeader() bool
+}
+
+// transport carries every request to a hosted page; nil uses the default transport.
+var transport http.RoundTripper
+
+// SetTransport sends hosted page requests through rt instead of the network, as demo
+// mode does. Call before Start.
+func SetTransport(rt http.RoundTripper) {
+	transport = rt
+}
+
+// hostedIncident is an incident as pushed to a hosted page.
+type hostedIncident struct {
+	Name       string
+	Status     string // investigating | identified | monitoring | resolved
+	Impact     string // minor | major | critical
+	Message    string
+	Started    time.Time
+	Components map[string]string // Hosted component ID -> component status
+}
+
+type provider interface {
+	setComponent(ctx context.Context, componentID, status string) error
+	createIncident(ctx context.Context, inc hostedIncident) (string, error)
+	updateIncident(ctx context.Context, id string, inc hostedIncident) error
+}
+
+func newProvider(m db.StatusPageMirror) provider {
+	if m.Provider == ProviderInstatus {
+		return instatusProvider{pageID: m.PageID, apiKey: m.APIKey}
+	}
+	return statuspageProvider{pageID: m.PageID, apiKey: m.APIKey}
+}
+
+// Syncer keeps every enabled mirror up to date.
+type Syncer struct {
+	store    *db.Store
+	statuses Statuses
+	leader   Leader
+	stopCh   chan struct{}
+	nudge    chan struct{}
+	wg       sync.WaitGroup
+
+	mu sync.Mutex
+	// pushed is the component status last pushed, by mirror and component, so unchanged
+	// statuses aren't sent every interval. A restart sends them all once.
+	pushed map[string]string
+}
+
+func NewSyncer(store *db.Store, statuses Statuses) *Syncer {
+	return &Syncer{
+		store:    store,
+		statuses: statuses,
+		stopCh:   make(chan struct{}),
+		nudge:    make(chan struct{}, 1),
+		pushed:   make(map[string]string),
+	}
+}
+
+// SetLeader restricts syncing to when l is the HA leader, so standby instances don't
+// open every incident twice.
+func (s *Syncer) SetLeader(l Leader) {
+	s.leader = l
+}
+
+func (s *Syncer) Start() {
+	s.store.OnIncidentCreate(func(db.Incident) { s.Trigger() })
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(SyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+			case <-s.nudge:
+			}
+			s.syncAll()
+		}
+	}()
+}
+
+func (s *Syncer) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Trigger syncs the mirrors now instead of at the next interval.
+func (s *Syncer) Trigger() {
+	select {
+	case s.nudge <- struct{}{}:
+	default:
+	}
+}
+
+// snapshot is what every mirror is synced from, loaded once per pass.
+type snapshot struct {
+	monitors        []db.Monitor
+	overrides       map[string]db.StatusOverride
+	maintenance     map[string]bool // Groups in an ongoing maintenance window
+	activeIncidents []db.Incident
+}
+
+func (s *Syncer) loadSnapshot(now time.Time) (snapshot, error) {
+	var snap snapshot
+	var err error
+	if snap.monitors, err = s.store.GetMonitors(); err != nil {
+		return snap, err
+	}
+	if snap.overrides, err = s.store.GetActiveStatusOverrides(now); err != nil {
+		return snap, err
+	}
+	windows, err := s.store.GetIncidentsFiltered(db.IncidentFilter{Type: "maintenance", Statuses: []string{"in_progress"}, ActiveOnly: true})
+	if err != nil {
+		return snap, err
+	}
+	snap.maintenance = make(map[string]bool)
+	for _, w := range windows {
+		for _, g := range affectedGroups(w) {
+			snap.maintenance[g] = true
+		}
+	}
+	snap.activeIncidents, err = s.store.GetIncidentsFiltered(db.IncidentFilter{Type: "incident", ActiveOnly: true})
+	return snap, err
+}
+
+// syncAll pushes what changed since the last pass to every enabled mirror.
+func (s *Syncer) syncAll() {
+	if s.leader != nil && !s.leader.IsLeader() {
+		return
+	}
+	mirrors, err := s.store.GetStatusPageMirrors()
+	if err != nil {
+		log.Printf("Status mirror: failed to load mirrors: %v", err)
+		return
+	}
+	if len(mirrors) == 0 {
+		return
+	}
+	now := time.Now()
+	snap, err := s.loadSnapshot(now)
+	if err != nil {
+		log.Printf("Status mirror: failed to load statuses: %v", err)
+		return
+	}
+
+	for _, m := range mirrors {
+		if !m.Enabled {
+			continue
+		}
+		page, err := s.store.GetStatusPageBySlug(m.Slug)
+		if err != nil || page == nil {
+			continue
+		}
+		pushed, err := s.syncMirror(m, page, snap)
+		if !pushed {
+			continue
+		}
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+			log.Printf("Status mirror: failed to update %s page %s for %s: %v", m.Provider, m.PageID, m.Slug, err)
+		}
+		if err := s.store.SetStatusPageMirrorResult(m.Slug, now, msg); err != nil {
+			log.Printf("Status mirror: failed to record sync of %s: %v", m.Slug, err)
+		}
+	}
+}
+
+// syncMirror pushes the mirror's changed component statuses and incidents. It reports
+// whether anything was sent, and the first error.
+func (s *Syncer) syncMirror(m db.StatusPageMirror, page *db.StatusPage, snap snapshot) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SyncInterval)
+	defer cancel()
+
+	p := newProvider(m)
+	components := s.componentStatuses(m, snap)
+	pushed := false
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for id, status := range components {
+		key := m.Slug + "\x00" + m.Provider + "\x00" + m.PageID + "\x00" + id
+		s.mu.Lock()
+		last := s.pushed[key]
+		s.mu.Unlock()
+		if last == status {
+			continue
+		}
+		pushed = true
+		if err := p.setComponent(ctx, id, status); err != nil {
+			fail(err)
+			continue
+		}
+		s.mu.Lock()
+		s.pushed[key] = status
+		s.mu.Unlock()
+	}
+
+	mirrored, err := s.store.GetMirroredIncidents(m.Slug)
+	if err != nil {
+		return pushed, err
+	}
+	active := make(map[string]db.Incident)
+	for _, inc := range snap.activeIncidents {
+		active[inc.ID] = inc
+		if _, ok := mirrored[inc.ID]; ok || !inc.Public || !pageShows(page, inc) {
+			continue
+		}
+		pushed = true
+		if err := s.openIncident(ctx, p, m, inc, components, snap); err != nil {
+			fail(err)
+		}
+	}
+	for _, mi := range mirrored {
+		if mi.Status == "resolved" {
+			continue
+		}
+		inc, ok := active[mi.IncidentID]
+		if !ok {
+			// Resolved since the last pass
+			loaded, err := s.store.GetIncidentByID(mi.IncidentID)
+			if err != nil || loaded == nil {
+				continue
+			}
+			inc = *loaded
+		}
+		if !inc.Public {
+			continue
+		}
+		sent, err := s.updateIncident(ctx, p, m, mi, inc, components, snap)
+		pushed = pushed || sent
+		if err != nil {
+			fail(err)
+		}
+	}
+	return pushed, firstErr
+}
+
+// openIncident copies a new incident to the hosted page.
+func (s *Syncer) openIncident(ctx context.Context, p provider, m db.StatusPageMirror, inc db.Incident, components map[string]string, snap snapshot) error {
+	updates, err := s.store.GetIncidentUpdates(inc.ID)
+	if err != nil {
+		return err
+	}
+	message := inc.Description
+	var lastUpdateID int64
+	if n := len(updates); n > 0 {
+		message = updates[n-1].Message
+		lastUpdateID = updates[n-1].ID
+	}
+	id, err := p.createIncident(ctx, hostedIncident{
+		Name:       inc.Title,
+		Status:     inc.Status,
+		Impact:     inc.Severity,
+		Message:    message,
+		Started:    inc.StartTime,
+		Components: incidentComponents(m, inc, components, snap),
+	})
+	if err != nil {
+		return err
+	}
+	return s.store.SetMirroredIncident(m.Slug, db.MirroredIncident{IncidentID: inc.ID, ExternalID: id, Status: inc.Status, LastUpdateID: lastUpdateID})
+}
+
+// updateIncident pushes an incident's new status or latest update to the hosted page. It
+// reports whether there was anything to push.
+func (s *Syncer) updateIncident(ctx context.Context, p provider, m db.StatusPageMirror, mi db.MirroredIncident, inc db.Incident, components map[string]string, snap snapshot) (bool, error) {
+	updates, err := s.store.GetIncidentUpdates(inc.ID)
+	if err != nil {
+		return false, err
+	}
+	var latest *db.IncidentUpdate
+	for i := range updates {
+		if updates[i].ID > mi.LastUpdateID {
+			latest = &updates[i]
+		}
+	}
+	if latest == nil && inc.Status == mi.Status {
+		return false, nil
+	}
+
+	message := "Status changed to " + inc.Status
+	if latest != nil {
+		message = latest.Message
+		mi.LastUpdateID = latest.ID
+	}
+	if err := p.updateIncident(ctx, mi.ExternalID, hostedIncident{
+		Name:       inc.Title,
+		Status:     inc.Status,
+		Impact:     inc.Severity,
+		Message:    message,
+		Started:    inc.StartTime,
+		Components: incidentComponents(m, inc, components, snap),
+	}); err != nil {
+		return true, err
+	}
+	mi.Status = inc.Status
+	return true, s.store.SetMirroredIncident(m.Slug, mi)
+}
+
+// componentStatuses works out the status of every mapped component that has one: a
+// monitor's live status, or overridden status, or its group's aggregate status. Paused
+// monitors and monitors not checked yet leave their component alone.
+func (s *Syncer) componentStatuses(m db.StatusPageMirror, snap snapshot) map[string]string {
+	byGroup := make(map[string][]db.Monitor)
+	byID := make(map[string]db.Monitor)
+	for _, mon := range snap.monitors {
+		byGroup[mon.GroupID] = append(byGroup[mon.GroupID], mon)
+		byID[mon.ID] = mon
+	}
+
+	statuses := make(map[string]string)
+	for key, componentID := range m.Components {
+		var status string
+		if mon, ok := byID[key]; ok {
+			status = s.monitorComponentStatus(mon, snap)
+		} else if members, ok := byGroup[key]; ok {
+			status = s.groupComponentStatus(key, members, snap)
+		}
+		if status == "" {
+			continue
+		}
+		if prev, ok := statuses[componentID]; !ok || componentSeverity[status] > componentSeverity[prev] {
+			statuses[componentID] = status
+		}
+	}
+	return statuses
+}
+
+// liveStatus is the monitor's status as shown on status pages: up, degraded, down, or
+// empty when it's paused or unchecked.
+func (s *Syncer) liveStatus(mon db.Monitor, snap snapshot) string {
+	if !mon.Active {
+		return ""
+	}
+	if o, ok := snap.overrides[mon.ID]; ok {
+		return o.Status
+	}
+	return s.statuses.MonitorStatus(mon.ID)
+}
+
+func (s *Syncer) monitorComponentStatus(mon db.Monitor, snap snapshot) string {
+	status := s.liveStatus(mon, snap)
+	if status == "" {
+		return ""
+	}
+	if snap.maintenance[mon.GroupID] {
+		return componentMaintenance
+	}
+	switch status {
+	case "down":
+		return componentMajor
+	case "degraded":
+		return componentDegraded
+	default:
+		return componentOperational
+	}
+}
+
+// groupComponentStatus is a major outage when all of the group's monitors are down, a
+// partial outage when some are, and degraded when any is.
+func (s *Syncer) groupComponentStatus(groupID string, members []db.Monitor, snap snapshot) string {
+	var known, down, degraded int
+	for _, mon := range members {
+		switch s.liveStatus(mon, snap) {
+		case "":
+			continue
+		case "down":
+			down++
+		case "degraded":
+			degraded++
+		}
+		known++
+	}
+	switch {
+	case known == 0:
+		return ""
+	case snap.maintenance[groupID]:
+		return componentMaintenance
+	case down == known:
+		return componentMajor
+	case down > 0:
+		return componentPartial
+	case degraded > 0:
+		return componentDegraded
+	default:
+		return componentOperational
+	}
+}
+
+// incidentComponents picks the mapped components the incident affects, with their
+// current statuses.
+func incidentComponents(m db.StatusPageMirror, inc db.Incident, statuses map[string]string, snap snapshot) map[string]string {
+	groups := make(map[string]bool)
+	for _, g := range affectedGroups(inc) {
+		groups[g] = true
+	}
+	groupOf := make(map[string]string)
+	for _, mon := range snap.monitors {
+		groupOf[mon.ID] = mon.GroupID
+	}
+
+	components := make(map[string]string)
+	for key, componentID := range m.Components {
+		if !groups[key] && !groups[groupOf[key]] {
+			continue
+		}
+		status, ok := statuses[componentID]
+		if !ok {
+			status = componentOperational
+		}
+		components[componentID] = status
+	}
+	return components
+}
+
+// pageShows reports whether the status page lists the incident: pages scoped to a group
+// show the incidents affecting that group.
+func pageShows(page *db.StatusPage, inc db.Incident) bool {
+	if page.GroupID == nil {
+		return true
+	}
+	for _, g := range affectedGroups(inc) {
+		if g == *page.GroupID {
+			return true
+		}
+	}
+	return false
+}
+
+func affectedGroups(inc db.Incident) []string {
+	var groups []string
+	if inc.AffectedGroups != "" {
+		_ = json.Unmarshal([]byte(inc.AffectedGroups), &groups)
+	}
+	return groups
+}
+
+// send calls a hosted page API with a JSON payload and decodes its answer into out,
+// unless out is nil.
+func send(ctx context.Context, method, url, authorization string, payload, out any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := (&http.Client{Transport: transport}).Do(req) // #nosec G704 -- fixed provider API URL
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(body[:min(len(body), 512)])))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,228 @@
+package statusmirror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+type fakeStatuses map[string]string
+
+func (f fakeStatuses) MonitorStatus(id string) string { return f[id] }
+
+type fakeLeader bool
+
+func (f fakeLeader) IsLeader() bool { return bool(f) }
+
+type hostedRequest struct {
+	Method string
+	Path   string
+	Auth   string
+	Body   map[string]any
+}
+
+// hostedPage records the requests sent to a fake hosted page API.
+type hostedPage struct {
+	mu       sync.Mutex
+	requests []hostedRequest
+	status   int
+}
+
+func (p *hostedPage) take() []hostedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reqs := p.requests
+	p.requests = nil
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Path < reqs[j].Path })
+	return reqs
+}
+
+func newHostedPage(t *testing.T, apiURL *string) *hostedPage {
+	t.Helper()
+	page := &hostedPage{status: http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := hostedRequest{Method: r.Method, Path: r.URL.Path, Auth: r.Header.Get("Authorization")}
+		_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		page.mu.Lock()
+		page.requests = append(page.requests, req)
+		status := page.status
+		page.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id": "ext-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	prev := *apiURL
+	*apiURL = srv.URL
+	t.Cleanup(func() { *apiURL = prev })
+	return page
+}
+
+func newTestStore(t *testing.T) *db.Store {
+	t.Helper()
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	group := "g-default"
+	if err := store.CreateStatusPage(db.StatusPageInput{Slug: "ops", Title: "Ops", GroupID: &group, Enabled: true, Public: true}); err != nil {
+		t.Fatalf("Failed to create status page: %v", err)
+	}
+	for _, id := range []string{"m1", "m2"} {
+		if err := store.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: "Monitor " + id, URL: "http://example.com/" + id, Active: true, Interval: 60}); err != nil {
+			t.Fatalf("Failed to create monitor: %v", err)
+		}
+	}
+	return store
+}
+
+func TestSyncer_Statuspage(t *testing.T) {
+	hosted := newHostedPage(t, &statuspageAPIURL)
+	store := newTestStore(t)
+	if err := store.SetStatusPageMirror(db.StatusPageMirror{
+		Slug: "ops", Provider: ProviderStatuspage, PageID: "pg1", APIKey: "sp-key", Enabled: true,
+		Components: map[string]string{"m1": "c1", "g-default": "c2"},
+	}); err != nil {
+		t.Fatalf("SetStatusPageMirror failed: %v", err)
+	}
+	s := NewSyncer(store, fakeStatuses{"m1": "down", "m2": "up"})
+
+	s.syncAll()
+	reqs := hosted.take()
+	if len(reqs) != 2 {
+		t.Fatalf("Expected 2 component updates, got %+v", reqs)
+	}
+	want := map[string]string{"/pages/pg1/components/c1": componentMajor, "/pages/pg1/components/c2": componentPartial}
+	for _, r := range reqs {
+		status, _ := r.Body["component"].(map[string]any)["status"].(string)
+		if r.Method != http.MethodPatch || want[r.Path] != status || r.Auth != "OAuth sp-key" {
+			t.Errorf("Unexpected component update %s %s %q %v", r.Method, r.Path, r.Auth, r.Body)
+		}
+	}
+
+	// Public incidents on the page are opened; private ones and other groups' are not
+	_ = store.CreateIncident(db.Incident{ID: "inc-1", Title: "API down", Description: "Looking into it", Type: "incident", Severity: "major", Status: "investigating", StartTime: time.Now(), AffectedGroups: `["g-default"]`, Public: true})
+	_ = store.CreateIncident(db.Incident{ID: "inc-private", Title: "Internal", Type: "incident", Severity: "minor", Status: "investigating", StartTime: time.Now(), AffectedGroups: `["g-default"]`})
+	_ = store.CreateIncident(db.Incident{ID: "inc-other", Title: "Elsewhere", Type: "incident", Severity: "minor", Status: "investigating", StartTime: time.Now(), AffectedGroups: `["g-other"]`, Public: true})
+	s.syncAll()
+	reqs = hosted.take()
+	if len(reqs) != 1 || reqs[0].Method != http.MethodPost || reqs[0].Path != "/pages/pg1/incidents" {
+		t.Fatalf("Expected the incident to be opened, got %+v", reqs)
+	}
+	inc := reqs[0].Body["incident"].(map[string]any)
+	if inc["name"] != "API down" || inc["status"] != "investigating" || inc["impact_override"] != "major" || inc["body"] != "Looking into it" {
+		t.Errorf("Unexpected incident %v", inc)
+	}
+	if ids, _ := inc["component_ids"].([]any); len(ids) != 2 {
+		t.Errorf("Expected both components attached, got %v", inc["component_ids"])
+	}
+
+	// Nothing changed
+	s.syncAll()
+	if reqs := hosted.take(); len(reqs) != 0 {
+		t.Fatalf("Expected no requests, got %+v", reqs)
+	}
+
+	// Resolution with an update
+	_ = store.CreateIncidentUpdate("inc-1", "resolved", "Fixed by a rollback")
+	resolved, _ := store.GetIncidentByID("inc-1")
+	resolved.Status = "resolved"
+	_ = store.UpdateIncident(*resolved)
+	s.syncAll()
+	reqs = hosted.take()
+	if len(reqs) != 1 || reqs[0].Method != http.MethodPatch || reqs[0].Path != "/pages/pg1/incidents/ext-1" {
+		t.Fatalf("Expected the incident to be updated, got %+v", reqs)
+	}
+	inc = reqs[0].Body["incident"].(map[string]any)
+	if inc["status"] != "resolved" || inc["body"] != "Fixed by a rollback" {
+		t.Errorf("Unexpected update %v", inc)
+	}
+	s.syncAll()
+	if reqs := hosted.take(); len(reqs) != 0 {
+		t.Fatalf("Expected no requests after the resolution, got %+v", reqs)
+	}
+
+	mirror, _ := store.GetStatusPageMirror("ops")
+	if mirror.LastSyncAt == nil || mirror.LastError != "" {
+		t.Errorf("Expected a successful sync, got %v %q", mirror.LastSyncAt, mirror.LastError)
+	}
+}
+
+func TestSyncer_Instatus(t *testing.T) {
+	hosted := newHostedPage(t, &instatusAPIURL)
+	store := newTestStore(t)
+	_ = store.SetStatusPageMirror(db.StatusPageMirror{
+		Slug: "ops", Provider: ProviderInstatus, PageID: "page-9", APIKey: "is-key", Enabled: true,
+		Components: map[string]string{"m2": "comp-2"},
+	})
+	_, _ = store.SetStatusOverride(db.StatusOverride{MonitorID: "m2", Status: db.OverrideStatusDegraded, Message: "Slow"})
+	s := NewSyncer(store, fakeStatuses{"m1": "up", "m2": "up"})
+
+	_ = store.CreateIncident(db.Incident{ID: "inc-1", Title: "Slow checkout", Type: "incident", Severity: "minor", Status: "identified", StartTime: time.Now(), AffectedGroups: `["g-default"]`, Public: true})
+	s.syncAll()
+	reqs := hosted.take()
+	if len(reqs) != 2 {
+		t.Fatalf("Expected a component update and an incident, got %+v", reqs)
+	}
+	if r := reqs[0]; r.Method != http.MethodPut || r.Path != "/page-9/components/comp-2" || r.Body["status"] != "DEGRADEDPERFORMANCE" || r.Auth != "Bearer is-key" {
+		t.Errorf("Unexpected component update %+v", r)
+	}
+	if r := reqs[1]; r.Method != http.MethodPost || r.Path != "/page-9/incidents" || r.Body["status"] != "IDENTIFIED" || r.Body["name"] != "Slow checkout" {
+		t.Errorf("Unexpected incident %+v", r)
+	}
+
+	_ = store.CreateIncidentUpdate("inc-1", "monitoring", "Fix deployed")
+	inc, _ := store.GetIncidentByID("inc-1")
+	inc.Status = "monitoring"
+	_ = store.UpdateIncident(*inc)
+	s.syncAll()
+	reqs = hosted.take()
+	if len(reqs) != 1 || reqs[0].Path != "/page-9/incidents/ext-1/incident-updates" || reqs[0].Body["status"] != "MONITORING" || reqs[0].Body["message"] != "Fix deployed" {
+		t.Fatalf("Expected an incident update, got %+v", reqs)
+	}
+}
+
+func TestSyncer_SkipsStandbyAndRecordsErrors(t *testing.T) {
+	hosted := newHostedPage(t, &statuspageAPIURL)
+	store := newTestStore(t)
+	_ = store.SetStatusPageMirror(db.StatusPageMirror{
+		Slug: "ops", Provider: ProviderStatuspage, PageID: "pg1", APIKey: "bad", Enabled: true,
+		Components: map[string]string{"m1": "c1"},
+	})
+	s := NewSyncer(store, fakeStatuses{"m1": "down"})
+
+	s.SetLeader(fakeLeader(false))
+	s.syncAll()
+	if reqs := hosted.take(); len(reqs) != 0 {
+		t.Fatalf("Expected a standby not to sync, got %+v", reqs)
+	}
+
+	hosted.status = http.StatusUnauthorized
+	s.SetLeader(fakeLeader(true))
+	s.syncAll()
+	mirror, _ := store.GetStatusPageMirror("ops")
+	if !strings.Contains(mirror.LastError, "401") {
+		t.Errorf("Expected the failure to be recorded, got %q", mirror.LastError)
+	}
+
+	// A failed push is retried on the next pass
+	hosted.take()
+	hosted.status = http.StatusOK
+	s.syncAll()
+	if reqs := hosted.take(); len(reqs) != 1 {
+		t.Fatalf("Expected the component update to be retried, got %+v", reqs)
+	}
+	mirror, _ = store.GetStatusPageMirror("ops")
+	if mirror.LastError != "" {
+		t.Errorf("Expected the error to clear, got %q", mirror.LastError)
+	}
+}
@@ -0,0 +1,54 @@
+package statusmirror
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// statuspageAPIURL is the Statuspage.io REST API.
+var statuspageAPIURL = "https://api.statuspage.io/v1"
+
+// statuspageProvider updates a Statuspage.io page with an API key of its organization.
+type statuspageProvider struct {
+	pageID string
+	apiKey string
+}
+
+func (p statuspageProvider) url(path string) string {
+	return statuspageAPIURL + "/pages/" + url.PathEscape(p.pageID) + path
+}
+
+func (p statuspageProvider) setComponent(ctx context.Context, componentID, status string) error {
+	payload := map[string]any{"component": map[string]string{"status": status}}
+	return send(ctx, http.MethodPatch, p.url("/components/"+url.PathEscape(componentID)), "OAuth "+p.apiKey, payload, nil)
+}
+
+func (p statuspageProvider) incidentPayload(inc hostedIncident) map[string]any {
+	ids := make([]string, 0, len(inc.Components))
+	for id := range inc.Components {
+		ids = append(ids, id)
+	}
+	return map[string]any{"incident": map[string]any{
+		"name":            inc.Name,
+		"status":          inc.Status,
+		"impact_override": inc.Impact,
+		"body":            inc.Message,
+		"component_ids":   ids,
+		"components":      inc.Components,
+	}}
+}
+
+func (p statuspageProvider) createIncident(ctx context.Context, inc hostedIncident) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := send(ctx, http.MethodPost, p.url("/incidents"), "OAuth "+p.apiKey, p.incidentPayload(inc), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (p statuspageProvider) updateIncident(ctx context.Context, id string, inc hostedIncident) error {
+	return send(ctx, http.MethodPatch, p.url("/incidents/"+url.PathEscape(id)), "OAuth "+p.apiKey, p.incidentPayload(inc), nil)
+}
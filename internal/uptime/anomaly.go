@@ -0,0 +1,115 @@
+package uptime
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// Latency anomaly detection learns a monitor's usual latency as an exponentially weighted
+// moving average (EWMA) of its up checks, and their usual spread as an EWMA of the absolute
+// deviation from it. A check is anomalous when its latency is more than the configured
+// number of deviations above the average, even if it is below the degraded threshold.
+const (
+	// AnomalyWarmupChecks are learned before any check is judged.
+	AnomalyWarmupChecks = 30
+	// AnomalyConfirmChecks anomalous (or normal) checks in a row enter (or leave) an anomaly.
+	AnomalyConfirmChecks = 3
+
+	anomalyAlpha     = 0.05  // weight of a normal check in the baseline, roughly the last 40 checks
+	anomalySlowAlpha = 0.005 // weight of an anomalous one, so a lasting shift becomes the new normal
+	anomalyMinDelta  = 50.0  // ms above the average below which latency is never anomalous
+)
+
+// latencyBaseline is a monitor's learned latency and its anomaly state.
+type latencyBaseline struct {
+	mean      float64
+	deviation float64
+	samples   int
+
+	anomalous    bool
+	anomalyCount int // anomalous checks in a row
+	normalCount  int // normal checks in a row
+}
+
+// learn moves the baseline towards x. Early samples weigh more, so the first
+// checks settle it quickly.
+func (b *latencyBaseline) learn(x, alpha float64) {
+	alpha = math.Max(alpha, 1/float64(b.samples+1))
+	b.deviation += alpha * (math.Abs(x-b.mean) - b.deviation)
+	b.mean += alpha * (x - b.mean)
+	b.samples++
+}
+
+func (b *latencyBaseline) resetStreaks() {
+	b.anomalous = false
+	b.anomalyCount = 0
+	b.normalCount = 0
+}
+
+// RecordLatencySample feeds the latency of an up check into the monitor's baseline.
+// entered is true for the check that confirms an anomaly and left for the one that
+// confirms it is over; usual is the average latency before this check.
+func (m *Monitor) RecordLatencySample(latency int64) (entered, left bool, usual float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := &m.latencyBaseline
+	usual = b.mean
+	x := float64(latency)
+	if m.anomalyDeviations <= 0 || b.samples < AnomalyWarmupChecks {
+		b.learn(x, anomalyAlpha)
+		return false, false, usual
+	}
+
+	if x-b.mean > math.Max(float64(m.anomalyDeviations)*b.deviation, anomalyMinDelta) {
+		b.learn(x, anomalySlowAlpha)
+		b.anomalyCount++
+		b.normalCount = 0
+	} else {
+		b.learn(x, anomalyAlpha)
+		b.normalCount++
+		b.anomalyCount = 0
+	}
+
+	switch {
+	case !b.anomalous && b.anomalyCount >= AnomalyConfirmChecks:
+		b.anomalous = true
+		return true, false, usual
+	case b.anomalous && b.normalCount >= AnomalyConfirmChecks:
+		b.anomalous = false
+		return false, true, usual
+	}
+	return false, false, usual
+}
+
+// processLatencyAnomaly learns from an up check's latency and notifies when it is confirmed
+// anomalous. Checks already degraded are left to the degraded notification, and not learned
+// from so the threshold's outages don't skew the baseline.
+func (m *Manager) processLatencyAnomaly(res CheckResult, mon *Monitor, isDegraded, isMaint bool, eventFilter NotificationEventFilter) {
+	if !res.Status || isDegraded {
+		return
+	}
+	entered, left, usual := mon.RecordLatencySample(res.Latency)
+	if entered {
+		msg := fmt.Sprintf("Latency anomaly: %dms, usually around %.0fms", res.Latency, usual)
+		m.recordEvent(res, "anomaly", msg)
+		if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("anomaly") && eventFilter.IsEnabled("anomaly") {
+			m.enqueueOrDigest(notifications.NotificationEvent{
+				MonitorID:   res.MonitorID,
+				MonitorName: mon.GetName(),
+				MonitorURL:  mon.GetTargetURL(),
+				Type:        notifications.EventAnomaly,
+				Message:     msg,
+				Time:        res.Timestamp,
+			})
+			mon.MarkNotified("anomaly")
+		}
+		log.Printf("Monitor %s latency is ANOMALOUS", res.MonitorID)
+	} else if left {
+		m.recordEvent(res, "anomaly_resolved", "Latency back to usual")
+		log.Printf("Monitor %s latency back to usual", res.MonitorID)
+	}
+}
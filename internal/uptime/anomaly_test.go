@@ -0,0 +1,113 @@
+package uptime
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// warmUp feeds a monitor enough checks around 100ms to learn its baseline.
+func warmUp(t *testing.T, m *Monitor) {
+	t.Helper()
+	for i := 0; i < AnomalyWarmupChecks*2; i++ {
+		if entered, _, _ := m.RecordLatencySample(int64(90 + i%3*10)); entered {
+			t.Fatalf("Unexpected anomaly at check %d of the warmup", i)
+		}
+	}
+}
+
+func TestMonitor_LatencyAnomaly(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{AnomalyDeviations: 5})
+	warmUp(t, m)
+
+	// A single slow check is not an anomaly
+	if entered, _, _ := m.RecordLatencySample(400); entered {
+		t.Fatal("Expected a single slow check not to be an anomaly")
+	}
+	m.RecordLatencySample(100)
+
+	var entered, left bool
+	var usual float64
+	for i := 0; i < AnomalyConfirmChecks; i++ {
+		entered, _, usual = m.RecordLatencySample(400)
+	}
+	if !entered {
+		t.Fatalf("Expected %d slow checks in a row to be an anomaly", AnomalyConfirmChecks)
+	}
+	if usual < 90 || usual > 120 {
+		t.Errorf("Expected the usual latency around 100ms, got %.0f", usual)
+	}
+	if entered, _, _ := m.RecordLatencySample(400); entered {
+		t.Error("Expected the anomaly to be reported once")
+	}
+
+	for i := 0; i < AnomalyConfirmChecks; i++ {
+		_, left, _ = m.RecordLatencySample(100)
+	}
+	if !left {
+		t.Fatal("Expected the anomaly to end after normal checks")
+	}
+}
+
+func TestMonitor_LatencyAnomaly_SmallChangesIgnored(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{AnomalyDeviations: 1})
+	for i := 0; i < AnomalyWarmupChecks*2; i++ {
+		m.RecordLatencySample(10)
+	}
+	// Tripling a 10ms latency is within the minimum delta
+	for i := 0; i < AnomalyConfirmChecks*2; i++ {
+		if entered, _, _ := m.RecordLatencySample(30); entered {
+			t.Fatal("Expected a few ms more not to be an anomaly")
+		}
+	}
+}
+
+func TestMonitor_LatencyAnomaly_Disabled(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{})
+	warmUp(t, m)
+	for i := 0; i < AnomalyConfirmChecks*2; i++ {
+		if entered, _, _ := m.RecordLatencySample(5000); entered {
+			t.Fatal("Expected no anomaly while detection is disabled")
+		}
+	}
+}
+
+func TestManager_LatencyAnomalyEvents(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:anomaly%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	if err := store.CreateMonitor(db.Monitor{ID: "test", GroupID: "g-default", Name: "Test", URL: "http://example.com", Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m := NewManager(store)
+	mon := newTestMonitorWithConfig(MonitorConfig{AnomalyDeviations: 5})
+	warmUp(t, mon)
+
+	// Replayed results record their events synchronously
+	check := func(latency int64) {
+		res := CheckResult{MonitorID: "test", Status: true, Latency: latency, Timestamp: time.Now(), Replayed: true}
+		m.processLatencyAnomaly(res, mon, false, false, NotificationEventFilter{})
+	}
+	for i := 0; i < AnomalyConfirmChecks; i++ {
+		check(400)
+	}
+	for i := 0; i < AnomalyConfirmChecks; i++ {
+		check(100)
+	}
+
+	events, err := store.GetMonitorEvents("test", 10)
+	if err != nil {
+		t.Fatalf("GetMonitorEvents failed: %v", err)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	if !slices.Equal(types, []string{"anomaly_resolved", "anomaly"}) {
+		t.Errorf("Expected an anomaly and its resolution, got %v", types)
+	}
+}
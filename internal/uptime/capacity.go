@@ -0,0 +1,127 @@
+package uptime
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// EstimatedBytesPerCheck approximates the traffic of one check: request, response
+	// headers and the start of the body read before the connection is reused.
+	EstimatedBytesPerCheck = 4 * 1024
+	// DefaultLatencyEstimateMs is assumed for monitors without recent checks.
+	DefaultLatencyEstimateMs = 500
+	// CapacityWarnUtilization is the worker pool utilization above which a warning is raised.
+	CapacityWarnUtilization = 0.8
+)
+
+// CheckLoad is the scheduled check volume of a set of monitors.
+type CheckLoad struct {
+	Monitors             int     `json:"monitors"`
+	ChecksPerDay         float64 `json:"checksPerDay"`
+	BandwidthBytesPerDay int64   `json:"bandwidthBytesPerDay"`
+	AvgIntervalSeconds   float64 `json:"avgIntervalSeconds"` // Aggregate spacing between checks (86400 / checksPerDay)
+	WorkerBusySeconds    float64 `json:"workerBusySeconds"`  // Worker time needed per second of wall clock
+}
+
+// GroupCheckLoad is the check load of a single group.
+type GroupCheckLoad struct {
+	GroupID   string `json:"groupId"`
+	GroupName string `json:"groupName"`
+	CheckLoad
+}
+
+// CapacityEstimate compares the scheduled check load with what the worker pool can sustain.
+type CapacityEstimate struct {
+	Workers            int              `json:"workers"`
+	MaxChecksPerSecond float64          `json:"maxChecksPerSecond"` // At the observed average latency
+	Utilization        float64          `json:"utilization"`        // Busy workers / WorkerCount, > 1 means checks fall behind
+	Global             CheckLoad        `json:"global"`
+	Groups             []GroupCheckLoad `json:"groups"`
+	Warnings           []string         `json:"warnings"`
+}
+
+// EstimateCapacity computes check volume, bandwidth and worker pool utilization for the
+// active, scheduled monitors. avgLatencyMs holds recent mean latency per monitor.
+func EstimateCapacity(monitors []db.Monitor, groups []db.Group, avgLatencyMs map[string]float64) CapacityEstimate {
+	est := CapacityEstimate{Workers: WorkerCount, Groups: []GroupCheckLoad{}, Warnings: []string{}}
+
+	byGroup := make(map[string]*GroupCheckLoad, len(groups))
+	for _, g := range groups {
+		byGroup[g.ID] = &GroupCheckLoad{GroupID: g.ID, GroupName: g.Name}
+	}
+
+	for _, m := range monitors {
+		// External and composite monitors are never scheduled, paused ones are not checked
+		if !m.Active || m.Type == db.MonitorTypeExternal || m.Type == db.MonitorTypeComposite {
+			continue
+		}
+		interval := m.Interval
+		if interval < 1 {
+			interval = 60
+		}
+		latency, ok := avgLatencyMs[m.ID]
+		if !ok || latency <= 0 {
+			latency = DefaultLatencyEstimateMs
+		}
+
+		perDay := 86400 / float64(interval)
+		busy := latency / 1000 / float64(interval)
+
+		gl, ok := byGroup[m.GroupID]
+		if !ok {
+			gl = &GroupCheckLoad{GroupID: m.GroupID}
+			byGroup[m.GroupID] = gl
+		}
+		gl.add(perDay, busy)
+		est.Global.add(perDay, busy)
+	}
+
+	for _, gl := range byGroup {
+		gl.finish()
+		est.Groups = append(est.Groups, *gl)
+	}
+	sort.Slice(est.Groups, func(i, j int) bool { return est.Groups[i].ChecksPerDay > est.Groups[j].ChecksPerDay })
+
+	est.Utilization = round2(est.Global.WorkerBusySeconds / float64(WorkerCount))
+	if est.Global.WorkerBusySeconds > 0 {
+		// Mean seconds a worker spends per check, weighted by check volume
+		secPerCheck := est.Global.WorkerBusySeconds / (est.Global.ChecksPerDay / 86400)
+		est.MaxChecksPerSecond = round2(float64(WorkerCount) / secPerCheck)
+	}
+	est.Global.finish()
+
+	switch {
+	case est.Utilization >= 1:
+		est.Warnings = append(est.Warnings, fmt.Sprintf(
+			"Scheduled checks need %.0f%% of the %d workers; checks will be delayed or skipped. Increase intervals or reduce slow monitors.",
+			est.Utilization*100, WorkerCount))
+	case est.Utilization >= CapacityWarnUtilization:
+		est.Warnings = append(est.Warnings, fmt.Sprintf(
+			"Scheduled checks use %.0f%% of the %d workers; latency spikes may delay checks.",
+			est.Utilization*100, WorkerCount))
+	}
+	return est
+}
+
+func (l *CheckLoad) add(perDay, busy float64) {
+	l.Monitors++
+	l.ChecksPerDay += perDay
+	l.WorkerBusySeconds += busy
+}
+
+func (l *CheckLoad) finish() {
+	l.BandwidthBytesPerDay = int64(math.Round(l.ChecksPerDay * EstimatedBytesPerCheck))
+	if l.ChecksPerDay > 0 {
+		l.AvgIntervalSeconds = round2(86400 / l.ChecksPerDay)
+	}
+	l.ChecksPerDay = math.Round(l.ChecksPerDay)
+	l.WorkerBusySeconds = round2(l.WorkerBusySeconds)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
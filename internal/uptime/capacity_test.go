@@ -0,0 +1,64 @@
+package uptime
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestEstimateCapacity(t *testing.T) {
+	groups := []db.Group{{ID: "g1", Name: "API"}, {ID: "g2", Name: "Web"}}
+	monitors := []db.Monitor{
+		{ID: "m1", GroupID: "g1", Active: true, Interval: 60},
+		{ID: "m2", GroupID: "g1", Active: true, Interval: 30},
+		{ID: "m3", GroupID: "g2", Active: true, Interval: 600},
+		{ID: "paused", GroupID: "g2", Active: false, Interval: 10},
+		{ID: "ext", GroupID: "g2", Active: true, Interval: 10, Type: db.MonitorTypeExternal},
+	}
+	latency := map[string]float64{"m1": 200, "m2": 100}
+
+	est := EstimateCapacity(monitors, groups, latency)
+
+	// 1440 + 2880 + 144 checks per day
+	if est.Global.Monitors != 3 || est.Global.ChecksPerDay != 4464 {
+		t.Errorf("Unexpected global load: %+v", est.Global)
+	}
+	if est.Global.BandwidthBytesPerDay != 4464*EstimatedBytesPerCheck {
+		t.Errorf("Unexpected bandwidth: %d", est.Global.BandwidthBytesPerDay)
+	}
+	if len(est.Groups) != 2 || est.Groups[0].GroupID != "g1" || est.Groups[0].ChecksPerDay != 4320 || est.Groups[0].AvgIntervalSeconds != 20 {
+		t.Errorf("Unexpected group loads: %+v", est.Groups)
+	}
+	// m3 has no latency history and uses the default estimate
+	wantBusy := 0.2/60 + 0.1/30 + DefaultLatencyEstimateMs/1000.0/600
+	if est.Global.WorkerBusySeconds != round2(wantBusy) {
+		t.Errorf("Expected %.2f busy worker seconds, got %v", wantBusy, est.Global.WorkerBusySeconds)
+	}
+	if len(est.Warnings) != 0 {
+		t.Errorf("Expected no warnings for a light load, got %v", est.Warnings)
+	}
+}
+
+func TestEstimateCapacity_Overloaded(t *testing.T) {
+	var monitors []db.Monitor
+	latency := map[string]float64{}
+	// 200 monitors every 10s at 5s latency need 100 busy workers
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("m%d", i)
+		monitors = append(monitors, db.Monitor{ID: id, GroupID: "g1", Active: true, Interval: 10})
+		latency[id] = 5000
+	}
+
+	est := EstimateCapacity(monitors, nil, latency)
+	if est.Utilization != 2 {
+		t.Errorf("Expected utilization 2, got %v", est.Utilization)
+	}
+	if est.MaxChecksPerSecond != 10 {
+		t.Errorf("Expected 10 checks/s at 5s latency, got %v", est.MaxChecksPerSecond)
+	}
+	if len(est.Warnings) != 1 || !strings.Contains(est.Warnings[0], "delayed") {
+		t.Errorf("Expected an overload warning, got %v", est.Warnings)
+	}
+}
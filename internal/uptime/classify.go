@@ -0,0 +1,77 @@
+package uptime
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// ClassifyError maps a failed check to a db.ErrorKind* value. err is the transport error
+// (nil when a response was received), statusCode the response status otherwise.
+func ClassifyError(err error, statusCode int) string {
+	if err == nil {
+		switch {
+		case statusCode >= 500:
+			return db.ErrorKindHTTP5xx
+		case statusCode >= 400:
+			return db.ErrorKindHTTP4xx
+		case statusCode > 0:
+			return db.ErrorKindHTTPStatus
+		}
+		return db.ErrorKindUnknown
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return db.ErrorKindTimeout
+		}
+		return db.ErrorKindDNS
+	}
+
+	if isTLSError(err) {
+		return db.ErrorKindTLS
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return db.ErrorKindConnectionRefused
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return db.ErrorKindTimeout
+	}
+
+	// Fall back to message matching for errors that lose their type (e.g. wrapped by proxies)
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "no such host"):
+		return db.ErrorKindDNS
+	case strings.Contains(msg, "connection refused"):
+		return db.ErrorKindConnectionRefused
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return db.ErrorKindTimeout
+	case strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:"):
+		return db.ErrorKindTLS
+	}
+	return db.ErrorKindUnknown
+}
+
+func isTLSError(err error) bool {
+	var (
+		verifyErr   *tls.CertificateVerificationError
+		recordErr   tls.RecordHeaderError
+		alertErr    tls.AlertError
+		unknownAuth x509.UnknownAuthorityError
+		invalidCert x509.CertificateInvalidError
+		hostnameErr x509.HostnameError
+	)
+	return errors.As(err, &verifyErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &unknownAuth) || errors.As(err, &invalidCert) || errors.As(err, &hostnameErr)
+}
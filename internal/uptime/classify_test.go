@@ -0,0 +1,73 @@
+package uptime
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestClassifyError_Status(t *testing.T) {
+	cases := map[int]string{
+		503: db.ErrorKindHTTP5xx,
+		500: db.ErrorKindHTTP5xx,
+		404: db.ErrorKindHTTP4xx,
+		302: db.ErrorKindHTTPStatus,
+		0:   db.ErrorKindUnknown,
+	}
+	for code, want := range cases {
+		if got := ClassifyError(nil, code); got != want {
+			t.Errorf("ClassifyError(nil, %d) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestClassifyError_Transport(t *testing.T) {
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://example.test", Err: err}
+	}
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"dns", wrap(&net.DNSError{Err: "no such host", Name: "example.test", IsNotFound: true}), db.ErrorKindDNS},
+		{"dns timeout", wrap(&net.DNSError{Err: "i/o timeout", Name: "example.test", IsTimeout: true}), db.ErrorKindTimeout},
+		{"deadline", wrap(context.DeadlineExceeded), db.ErrorKindTimeout},
+		{"x509", wrap(x509.UnknownAuthorityError{}), db.ErrorKindTLS},
+		{"refused string", errors.New("dial tcp 10.0.0.1:80: connect: connection refused"), db.ErrorKindConnectionRefused},
+		{"tls string", fmt.Errorf("proxy: %s", "tls: handshake failure"), db.ErrorKindTLS},
+		{"other", errors.New("EOF"), db.ErrorKindUnknown},
+	}
+	for _, tc := range cases {
+		if got := ClassifyError(tc.err, 0); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestClassifyError_ConnectionRefused(t *testing.T) {
+	// Grab a free port and close it so the dial is refused
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	_, err = client.Get("http://" + addr)
+	if err == nil {
+		t.Fatal("Expected request to a closed port to fail")
+	}
+	if got := ClassifyError(err, 0); got != db.ErrorKindConnectionRefused {
+		t.Errorf("Expected connection_refused, got %q (%v)", got, err)
+	}
+}
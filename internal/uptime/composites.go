@@ -0,0 +1,92 @@
+package uptime
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// setComposites replaces the composite rules and rebuilds the member index. Callers hold m.mu.
+func (m *Manager) setComposites(rules map[string]db.CompositeRule) {
+	m.composites = rules
+	m.compositesByMember = make(map[string][]string)
+	for id, rule := range rules {
+		for _, member := range rule.Members {
+			m.compositesByMember[member] = append(m.compositesByMember[member], id)
+		}
+	}
+}
+
+// compositeStatus computes a composite monitor's status from its members' latest results.
+// ok is false while a running member has not been checked yet, so a restart doesn't
+// report the composite down before its members have results. Paused or missing members
+// count as not up.
+func (m *Manager) compositeStatus(rule db.CompositeRule) (isUp bool, summary string, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	up := 0
+	for _, id := range rule.Members {
+		member, running := m.monitors[id]
+		if !running {
+			continue
+		}
+		memberUp, _, hasHistory, _ := member.GetLastStatus()
+		if !hasHistory {
+			return false, "", false
+		}
+		if memberUp {
+			up++
+		}
+	}
+
+	// Deleted members shrink the rule; never require more members than remain
+	need := rule.MinUp
+	if need > len(rule.Members) {
+		need = len(rule.Members)
+	}
+	if need < 1 {
+		return false, "", false
+	}
+	summary = fmt.Sprintf("%d of %d members up (needs %d)", up, len(rule.Members), need)
+	return up >= need, summary, true
+}
+
+// evaluateComposites feeds a recomputed result into the pipeline for every composite
+// monitor the checked monitor is a member of.
+func (m *Manager) evaluateComposites(res CheckResult) {
+	m.mu.RLock()
+	var rules []db.CompositeRule
+	for _, id := range m.compositesByMember[res.MonitorID] {
+		if _, running := m.monitors[id]; running {
+			rules = append(rules, m.composites[id])
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, rule := range rules {
+		isUp, summary, ok := m.compositeStatus(rule)
+		if !ok {
+			continue
+		}
+		composite := CheckResult{
+			MonitorID: rule.MonitorID,
+			Status:    isUp,
+			Timestamp: res.Timestamp,
+			Summary:   summary,
+		}
+		if !isUp {
+			composite.Error = summary
+			composite.ErrorKind = db.ErrorKindMembersDown
+		}
+		// Queued from a goroutine: the result processor calling this is the queue's only reader
+		go func() {
+			select {
+			case m.resultQueue <- composite:
+			case <-m.stopCh:
+				log.Printf("Dropped composite result for %s: manager stopped", composite.MonitorID)
+			}
+		}()
+	}
+}
@@ -0,0 +1,81 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestEvaluateComposites(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	for _, id := range []string{"eu", "us", "ap"} {
+		m.monitors[id] = NewMonitor(id, "g1", id, "http://"+id+".example.com", time.Minute, nil, time.Time{}, nil)
+	}
+	global := NewMonitor("global", "g1", "Global", "", time.Minute, nil, time.Time{}, nil)
+	global.SetMonitorType(db.MonitorTypeComposite)
+	m.monitors["global"] = global
+	m.setComposites(map[string]db.CompositeRule{
+		"global": {MonitorID: "global", MinUp: 2, Members: []string{"ap", "eu", "us"}},
+	})
+
+	next := func() (CheckResult, bool) {
+		select {
+		case res := <-m.resultQueue:
+			return res, true
+		case <-time.After(200 * time.Millisecond):
+			return CheckResult{}, false
+		}
+	}
+
+	now := time.Now()
+	m.monitors["eu"].RecordResult(true, 10, now, 200, "", false)
+	m.monitors["us"].RecordResult(true, 10, now, 200, "", false)
+
+	// ap has no result yet: the composite waits for it
+	m.evaluateComposites(CheckResult{MonitorID: "us", Timestamp: now})
+	if res, ok := next(); ok {
+		t.Fatalf("expected no composite result before all members were checked, got %+v", res)
+	}
+
+	m.monitors["ap"].RecordResult(false, 0, now, 0, "timeout", false)
+	m.evaluateComposites(CheckResult{MonitorID: "ap", Timestamp: now})
+	res, ok := next()
+	if !ok || res.MonitorID != "global" || !res.Status {
+		t.Fatalf("expected global up with 2 of 3 members up, got %+v (ok=%v)", res, ok)
+	}
+
+	m.monitors["eu"].RecordResult(false, 0, now, 0, "timeout", false)
+	m.evaluateComposites(CheckResult{MonitorID: "eu", Timestamp: now})
+	res, ok = next()
+	if !ok || res.Status || res.ErrorKind != db.ErrorKindMembersDown {
+		t.Fatalf("expected global down with 1 of 3 members up, got %+v (ok=%v)", res, ok)
+	}
+	if res.Summary != "1 of 3 members up (needs 2)" {
+		t.Errorf("unexpected summary %q", res.Summary)
+	}
+
+	// Non-members don't trigger an evaluation
+	m.evaluateComposites(CheckResult{MonitorID: "other", Timestamp: now})
+	if res, ok := next(); ok {
+		t.Fatalf("expected no composite result for a non-member, got %+v", res)
+	}
+}
+
+func TestCompositeStatus_PausedMembersCountAsDown(t *testing.T) {
+	m, _ := newTestManager(t)
+	eu := NewMonitor("eu", "g1", "eu", "http://eu.example.com", time.Minute, nil, time.Time{}, nil)
+	eu.RecordResult(true, 10, time.Now(), 200, "", false)
+	m.monitors["eu"] = eu
+
+	// "us" is paused (not running)
+	isUp, _, ok := m.compositeStatus(db.CompositeRule{MonitorID: "global", MinUp: 2, Members: []string{"eu", "us"}})
+	if !ok || isUp {
+		t.Fatalf("expected down with a paused member, got up=%v ok=%v", isUp, ok)
+	}
+	isUp, _, ok = m.compositeStatus(db.CompositeRule{MonitorID: "global", MinUp: 1, Members: []string{"eu", "us"}})
+	if !ok || !isUp {
+		t.Fatalf("expected up with 1 of 2 needed, got up=%v ok=%v", isUp, ok)
+	}
+}
@@ -0,0 +1,51 @@
+package uptime
+
+import (
+	"log"
+)
+
+// downDependency returns the first monitor that monitorID depends on which is currently
+// down: confirmed down, or failing its latest check (dependents are often checked before
+// the dependency's own confirmation completes). Returns nil when all dependencies are up.
+func (m *Manager) downDependency(monitorID string) *Monitor {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, id := range m.dependencies[monitorID] {
+		dep, ok := m.monitors[id]
+		if !ok {
+			continue // paused or removed dependencies can't be blamed
+		}
+		if dep.IsConfirmedDown() {
+			return dep
+		}
+		if isUp, _, hasHistory, _ := dep.GetLastStatus(); hasHistory && !isUp {
+			return dep
+		}
+	}
+	return nil
+}
+
+// confirmDown counts a failed check and reports whether it should open an outage and notify.
+// While a dependency is down the monitor is marked as affected by it instead. If the
+// monitor is still down once its dependencies are back, its own outage opens then.
+func (m *Manager) confirmDown(res CheckResult, mon *Monitor) bool {
+	confirmed := mon.IncrementDown()
+	if !confirmed && mon.AffectedBy() == "" {
+		return false
+	}
+
+	if dep := m.downDependency(res.MonitorID); dep != nil {
+		if prev := mon.SetAffectedBy(dep.id); prev != dep.id {
+			message := "Affected by dependency: " + dep.GetName() + " is down"
+			m.recordEvent(res, "affected", message)
+			log.Printf("Monitor %s is DOWN, affected by dependency %s", res.MonitorID, dep.id)
+		}
+		return false
+	}
+
+	if mon.SetAffectedBy("") != "" {
+		log.Printf("Monitor %s still down after its dependencies recovered", res.MonitorID)
+		return true
+	}
+	return confirmed
+}
@@ -0,0 +1,57 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfirmDown_AttributesFailuresToDownDependency(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	lb := NewMonitor("lb", "g1", "Load Balancer", "http://lb.example.com", time.Minute, nil, time.Time{}, nil)
+	api := NewMonitor("api", "g1", "API", "http://api.example.com", time.Minute, nil, time.Time{}, nil)
+	api.confirmationThreshold = 1
+	m.monitors["lb"] = lb
+	m.monitors["api"] = api
+	m.dependencies = map[string][]string{"api": {"lb"}}
+
+	// Dependency up: a failure opens the monitor's own outage
+	lb.RecordResult(true, 10, time.Now(), 200, "", false)
+	if !m.confirmDown(CheckResult{MonitorID: "api"}, api) {
+		t.Fatal("expected outage while dependency is up")
+	}
+	api.ResetDown()
+
+	// Dependency failing: suppressed and attributed to it
+	lb.RecordResult(false, 0, time.Now(), 0, "connection refused", false)
+	if m.confirmDown(CheckResult{MonitorID: "api"}, api) {
+		t.Fatal("expected no outage while dependency is down")
+	}
+	if got := api.AffectedBy(); got != "lb" {
+		t.Fatalf("expected api affected by lb, got %q", got)
+	}
+	if m.confirmDown(CheckResult{MonitorID: "api"}, api) {
+		t.Fatal("expected continued suppression while dependency is down")
+	}
+
+	// Dependency back but the monitor still fails: its own outage opens now
+	lb.RecordResult(true, 10, time.Now(), 200, "", false)
+	if !m.confirmDown(CheckResult{MonitorID: "api"}, api) {
+		t.Fatal("expected outage once the dependency recovered")
+	}
+	if got := api.AffectedBy(); got != "" {
+		t.Fatalf("expected affectedBy cleared, got %q", got)
+	}
+	// Already confirmed: later failures don't reopen it
+	if m.confirmDown(CheckResult{MonitorID: "api"}, api) {
+		t.Fatal("expected a single outage")
+	}
+}
+
+func TestDownDependency_IgnoresUnknownMonitors(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.dependencies = map[string][]string{"api": {"paused"}}
+	if dep := m.downDependency("api"); dep != nil {
+		t.Fatalf("expected no down dependency, got %s", dep.id)
+	}
+}
@@ -0,0 +1,45 @@
+package uptime
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// MaxEvidenceBodyBytes caps how much of a failing response body is kept with the outage.
+const MaxEvidenceBodyBytes = 1024
+
+// evidenceHeaders are the response headers worth keeping to explain a failure.
+var evidenceHeaders = []string{"Server", "Content-Type", "Retry-After"}
+
+// captureEvidence records key headers and the start of the body of a failing response.
+// It reads at most MaxEvidenceBodyBytes; the caller still closes the body.
+func captureEvidence(resp *http.Response) *db.OutageEvidence {
+	ev := &db.OutageEvidence{StatusCode: resp.StatusCode}
+	for _, h := range evidenceHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if ev.Headers == nil {
+				ev.Headers = make(map[string]string)
+			}
+			ev.Headers[h] = v
+		}
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, MaxEvidenceBodyBytes))
+	ev.BodySnippet = truncateSnippet(b)
+	return ev
+}
+
+// truncateSnippet drops a multi-byte rune cut off by the byte limit and any invalid UTF-8.
+func truncateSnippet(b []byte) string {
+	for i := 0; i < utf8.UTFMax && len(b) > 0; i++ {
+		if r, size := utf8.DecodeLastRune(b); r != utf8.RuneError || size != 1 {
+			break
+		}
+		b = b[:len(b)-1]
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(string(b), ""))
+}
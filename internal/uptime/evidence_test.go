@@ -0,0 +1,47 @@
+package uptime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureEvidence(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.25")
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Retry-After", "120")
+		w.Header().Set("X-Internal", "ignored")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("  <h1>Maintenance</h1>" + strings.Repeat("x", 4*MaxEvidenceBodyBytes)))
+	}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	ev := captureEvidence(resp)
+	if ev.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", ev.StatusCode)
+	}
+	if len(ev.Headers) != 3 || ev.Headers["Server"] != "nginx/1.25" || ev.Headers["Retry-After"] != "120" {
+		t.Errorf("Unexpected headers: %v", ev.Headers)
+	}
+	if !strings.HasPrefix(ev.BodySnippet, "<h1>Maintenance</h1>") || len(ev.BodySnippet) > MaxEvidenceBodyBytes {
+		t.Errorf("Unexpected snippet (%d bytes): %.40q", len(ev.BodySnippet), ev.BodySnippet)
+	}
+}
+
+func TestTruncateSnippet(t *testing.T) {
+	// "é" is two bytes; cutting after the first leaves an incomplete rune
+	if got := truncateSnippet([]byte("caf\xc3")); got != "caf" {
+		t.Errorf("Expected partial rune to be dropped, got %q", got)
+	}
+	if got := truncateSnippet([]byte("ok \xff\xfe body")); got != "ok  body" {
+		t.Errorf("Expected invalid bytes to be dropped, got %q", got)
+	}
+}
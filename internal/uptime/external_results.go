@@ -0,0 +1,202 @@
+package uptime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// ExternalResult is a check result reported for an external monitor by an agent or
+// another outside source.
+type ExternalResult struct {
+	MonitorID string
+	IsUp      bool
+	Summary   string
+	Latency   int64
+	Timestamp time.Time
+	Region    string // Where the result was checked from, e.g. the agent's region
+}
+
+// ValidateExternalMonitor returns an error unless the monitor is running and accepts
+// results from outside.
+func (m *Manager) ValidateExternalMonitor(monitorID string) error {
+	mon := m.GetMonitor(monitorID)
+	if mon == nil {
+		return fmt.Errorf("monitor %s is not running", monitorID)
+	}
+	if !mon.IsExternal() {
+		return fmt.Errorf("monitor %s is not an external monitor", monitorID)
+	}
+	return nil
+}
+
+// IngestExternalResults feeds results for external monitors into the result pipeline in
+// timestamp order. Results newer than anything a monitor has processed drive its state
+// transitions, notifications included. Older ones arrived too late to do so and are
+// backfilled instead: stored as checks, with their confirmed down streaks recorded as
+// closed outages. The backfill runs first, so when it fails no result has been fed into
+// the pipeline.
+//
+// Replayed results were buffered by the agent while it was offline; their events and
+// outages are stamped with the time they were checked rather than the time they arrived.
+func (m *Manager) IngestExternalResults(results []ExternalResult, replayed bool) error {
+	for _, r := range results {
+		if err := m.ValidateExternalMonitor(r.MonitorID); err != nil {
+			return err
+		}
+	}
+	sorted := make([]ExternalResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	m.ingestMu.Lock()
+	defer m.ingestMu.Unlock()
+
+	// Split the results without moving any watermark; ingestMu keeps them where they are
+	// until the live results are fed in below
+	type liveResult struct {
+		mon *Monitor
+		ExternalResult
+	}
+	var live []liveResult
+	marks := make(map[string]time.Time)
+	late := make(map[string][]ExternalResult)
+	var lateOrder []string
+	for _, r := range sorted {
+		r.Timestamp = r.Timestamp.UTC()
+		mon := m.GetMonitor(r.MonitorID)
+		if mon == nil {
+			return fmt.Errorf("monitor %s is not running", r.MonitorID)
+		}
+		mark, ok := marks[r.MonitorID]
+		if !ok {
+			mark = mon.IngestWatermark()
+		}
+		if !r.Timestamp.After(mark) {
+			if _, ok := late[r.MonitorID]; !ok {
+				lateOrder = append(lateOrder, r.MonitorID)
+			}
+			late[r.MonitorID] = append(late[r.MonitorID], r)
+			continue
+		}
+		marks[r.MonitorID] = r.Timestamp
+		live = append(live, liveResult{mon: mon, ExternalResult: r})
+	}
+
+	for _, id := range lateOrder {
+		if err := m.backfillExternalResults(m.GetMonitor(id), late[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range live {
+		r.mon.AdvanceIngestWatermark(r.Timestamp)
+		res := CheckResult{
+			MonitorID: r.MonitorID,
+			URL:       r.mon.GetTargetURL(),
+			Status:    r.IsUp,
+			Latency:   r.Latency,
+			Timestamp: r.Timestamp,
+			Summary:   r.Summary,
+			Replayed:  replayed,
+			Region:    r.Region,
+		}
+		if !r.IsUp {
+			res.Error = r.Summary
+			res.ErrorKind = db.ErrorKindExternal
+		}
+		select {
+		case m.resultQueue <- res:
+		case <-m.stopCh:
+			return fmt.Errorf("manager stopped")
+		}
+	}
+	return nil
+}
+
+// backfillExternalResults records results, in timestamp order, that are older than what
+// the monitor has already processed. They can't change its current state or notify, so
+// they are stored as checks, and each run of down results long enough to have been
+// confirmed becomes a closed outage ending at the next up result, unless the monitor
+// already has an outage over that time. Outages are written before the checks: recording
+// one again is a no-op, so retrying after a failure doesn't duplicate anything.
+func (m *Manager) backfillExternalResults(mon *Monitor, results []ExternalResult) error {
+	if mon == nil || len(results) == 0 {
+		return nil
+	}
+
+	threshold := mon.GetConfirmationThreshold()
+	if threshold < 1 {
+		threshold = 1
+	}
+	for i := 0; i < len(results); {
+		if results[i].IsUp {
+			i++
+			continue
+		}
+		j := i
+		for j < len(results) && !results[j].IsUp {
+			j++
+		}
+		if j-i >= threshold {
+			first := results[i]
+			end := results[j-1].Timestamp
+			if j < len(results) {
+				end = results[j].Timestamp
+			}
+			summary := first.Summary
+			if summary == "" {
+				summary = "Monitor is down"
+			}
+			if _, err := m.store.CreateClosedOutage(first.MonitorID, "down", summary, db.ErrorKindExternal, first.Timestamp, end); err != nil {
+				return err
+			}
+		}
+		i = j
+	}
+
+	checks := make([]db.CheckResult, 0, len(results))
+	for _, r := range results {
+		check := db.CheckResult{MonitorID: r.MonitorID, Status: "up", Latency: r.Latency, Timestamp: r.Timestamp, Region: r.Region}
+		if !r.IsUp {
+			check.Status = "down"
+			check.ErrorKind = db.ErrorKindExternal
+		}
+		checks = append(checks, check)
+	}
+	return m.store.BatchInsertChecks(checks)
+}
+
+// recordEvent writes a monitor event for a result. Replayed results are written in order
+// and stamped with their check time; live ones in the background.
+func (m *Manager) recordEvent(res CheckResult, eventType, message string) {
+	if res.Replayed {
+		_ = m.store.CreateEventAt(res.MonitorID, eventType, message, res.Timestamp)
+		return
+	}
+	go func() { _ = m.store.CreateEvent(res.MonitorID, eventType, message) }()
+}
+
+// openOutage closes the monitor's active outage and opens a new one started by the result.
+func (m *Manager) openOutage(res CheckResult, outageType, summary, errorKind string, evidence *db.OutageEvidence) {
+	if res.Replayed {
+		_ = m.store.CloseOutageAt(res.MonitorID, res.Timestamp)
+		_ = m.store.CreateOutageAt(res.MonitorID, outageType, summary, errorKind, evidence, res.Timestamp)
+		return
+	}
+	go func() {
+		_ = m.store.CloseOutage(res.MonitorID)
+		_ = m.store.CreateOutageWithEvidence(res.MonitorID, outageType, summary, errorKind, evidence)
+	}()
+}
+
+// closeOutage closes the monitor's active outage as of the result.
+func (m *Manager) closeOutage(res CheckResult) {
+	if res.Replayed {
+		_ = m.store.CloseOutageAt(res.MonitorID, res.Timestamp)
+		return
+	}
+	go func() { _ = m.store.CloseOutage(res.MonitorID) }()
+}
@@ -0,0 +1,121 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestIngestExternalResults_ReplayAndBackfill(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	_ = store.SetSetting("notification.flap_detection_enabled", "false")
+
+	m := NewManager(store)
+	m.Start()
+	defer m.Stop()
+
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-edge", GroupID: "g-default", Name: "Edge", Type: db.MonitorTypeExternal, Active: true, Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m.Sync()
+
+	if err := m.IngestExternalResults([]ExternalResult{{MonitorID: "missing", IsUp: true, Timestamp: time.Now()}}, true); err == nil {
+		t.Error("Expected an error for a monitor that isn't running")
+	}
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	// A buffered upload arrives shuffled; it is replayed in timestamp order
+	if err := m.IngestExternalResults([]ExternalResult{
+		{MonitorID: "m-edge", IsUp: true, Latency: 40, Timestamp: at(4)},
+		{MonitorID: "m-edge", IsUp: false, Summary: "unreachable", Timestamp: at(2)},
+		{MonitorID: "m-edge", IsUp: true, Latency: 40, Timestamp: at(0)},
+		{MonitorID: "m-edge", IsUp: false, Summary: "unreachable", Timestamp: at(1)},
+		{MonitorID: "m-edge", IsUp: false, Summary: "unreachable", Timestamp: at(3)},
+		{MonitorID: "m-edge", IsUp: true, Latency: 40, Timestamp: at(5)},
+	}, true); err != nil {
+		t.Fatalf("IngestExternalResults failed: %v", err)
+	}
+
+	mon := m.GetMonitor("m-edge")
+	deadline := time.Now().Add(3 * time.Second)
+	for len(mon.GetHistory()) < 6 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 6 results in history, got %d", len(mon.GetHistory()))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	history := mon.GetHistory()
+	for i := 1; i < len(history); i++ {
+		if !history[i].Timestamp.After(history[i-1].Timestamp) {
+			t.Fatalf("Expected history in timestamp order, got %v before %v", history[i-1].Timestamp, history[i].Timestamp)
+		}
+	}
+
+	// External monitors confirm on the first failure: the outage runs from the first down
+	// result to the recovery, not from upload time
+	outages, err := store.GetResolvedOutages(base.Add(-2 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetResolvedOutages failed: %v", err)
+	}
+	if len(outages) != 1 {
+		t.Fatalf("Expected 1 resolved outage, got %+v", outages)
+	}
+	if !outages[0].StartTime.Equal(at(1)) || !outages[0].EndTime.Equal(at(4)) {
+		t.Errorf("Expected the outage from %v to %v, got %v to %v", at(1), at(4), outages[0].StartTime, outages[0].EndTime)
+	}
+	events, err := store.GetMonitorEvents("m-edge", 10)
+	if err != nil {
+		t.Fatalf("GetMonitorEvents failed: %v", err)
+	}
+	for _, e := range events {
+		if e.Timestamp.After(at(5)) {
+			t.Errorf("Expected replayed events stamped with their check time, got %s at %v", e.Type, e.Timestamp)
+		}
+	}
+
+	// Results older than what was replayed are backfilled as checks and a closed outage
+	if err := m.IngestExternalResults([]ExternalResult{
+		{MonitorID: "m-edge", IsUp: false, Summary: "timeout", Timestamp: at(-30)},
+		{MonitorID: "m-edge", IsUp: false, Summary: "timeout", Timestamp: at(-29)},
+		{MonitorID: "m-edge", IsUp: true, Latency: 40, Timestamp: at(-28)},
+		// Within the replayed outage, which already covers it
+		{MonitorID: "m-edge", IsUp: false, Summary: "timeout", Timestamp: at(3).Add(30 * time.Second)},
+	}, true); err != nil {
+		t.Fatalf("IngestExternalResults failed: %v", err)
+	}
+	if n := len(mon.GetHistory()); n != 6 {
+		t.Errorf("Expected late results to stay out of the live history, got %d results", n)
+	}
+	checks, err := store.GetMonitorChecks("m-edge", 100)
+	if err != nil {
+		t.Fatalf("GetMonitorChecks failed: %v", err)
+	}
+	backfilled := 0
+	for _, c := range checks {
+		if c.Timestamp.Before(at(0)) {
+			backfilled++
+		}
+	}
+	if backfilled != 3 {
+		t.Errorf("Expected 3 backfilled checks, got %d", backfilled)
+	}
+	outages, err = store.GetResolvedOutages(base.Add(-2 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetResolvedOutages failed: %v", err)
+	}
+	if len(outages) != 2 {
+		t.Fatalf("Expected the backfilled outage alongside the replayed one, got %+v", outages)
+	}
+	late := outages[1]
+	if !late.StartTime.Equal(at(-30)) || !late.EndTime.Equal(at(-28)) || late.Summary != "timeout" {
+		t.Errorf("Expected the backfilled outage from %v to %v, got %+v", at(-30), at(-28), late)
+	}
+}
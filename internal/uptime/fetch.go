@@ -0,0 +1,46 @@
+package uptime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const fetchTimeout = 15 * time.Second
+
+// FetchDocument downloads a document a user pointed the server at, such as an OpenAPI
+// spec, through the same dialer as checks so private targets stay blocked when they
+// are. Responses other than 2xx and bodies over maxBytes are errors.
+func (m *Manager) FetchDocument(ctx context.Context, target string, maxBytes int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Warden")
+	req.Header.Set("Accept", "application/json")
+
+	transport := m.checkTransport
+	if transport == nil {
+		transport = &http.Transport{DialContext: checkDialer(m.BlocksPrivateTargets()), DisableKeepAlives: true}
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req) // #nosec G704 -- URL validated by the caller, dialer blocks private targets
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("document larger than %d bytes", maxBytes)
+	}
+	return data, nil
+}
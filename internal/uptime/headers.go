@@ -0,0 +1,43 @@
+package uptime
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// HeaderMismatchReason is the degraded reason of checks restored from the database, which
+// only keep the error kind of a header mismatch.
+const HeaderMismatchReason = "Unexpected response headers"
+
+// maxHeaderValueInReason caps how much of an unexpected header value is quoted.
+const maxHeaderValueInReason = 100
+
+// checkExpectedHeaders returns why the response headers fail the assertions, or "" when
+// all of them hold.
+func checkExpectedHeaders(h http.Header, expected []db.HeaderAssertion) string {
+	var problems []string
+	for _, a := range expected {
+		values := h.Values(a.Name)
+		if len(values) == 0 {
+			problems = append(problems, a.Name+" is missing")
+			continue
+		}
+		if a.Value == "" {
+			continue
+		}
+		got := strings.Join(values, ", ")
+		if !strings.Contains(strings.ToLower(got), strings.ToLower(a.Value)) {
+			if len(got) > maxHeaderValueInReason {
+				got = got[:maxHeaderValueInReason] + "..."
+			}
+			problems = append(problems, fmt.Sprintf("%s is %q, expected %q", a.Name, got, a.Value))
+		}
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+	return HeaderMismatchReason + ": " + strings.Join(problems, "; ")
+}
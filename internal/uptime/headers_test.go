@@ -0,0 +1,134 @@
+package uptime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestCheckExpectedHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("Strict-Transport-Security", "max-age=63072000")
+
+	tests := []struct {
+		name     string
+		expected []db.HeaderAssertion
+		want     string
+	}{
+		{"present", []db.HeaderAssertion{{Name: "strict-transport-security"}}, ""},
+		{"value contained", []db.HeaderAssertion{{Name: "Content-Type", Value: "Application/JSON"}}, ""},
+		{"missing", []db.HeaderAssertion{{Name: "X-Frame-Options"}}, "X-Frame-Options is missing"},
+		{"wrong value", []db.HeaderAssertion{{Name: "Content-Type", Value: "text/html"}},
+			`Content-Type is "application/json; charset=utf-8", expected "text/html"`},
+	}
+	for _, tc := range tests {
+		got := checkExpectedHeaders(h, tc.expected)
+		if tc.want == "" && got != "" {
+			t.Errorf("%s: expected no mismatch, got %q", tc.name, got)
+		}
+		if tc.want != "" && got != HeaderMismatchReason+": "+tc.want {
+			t.Errorf("%s: unexpected reason %q", tc.name, got)
+		}
+	}
+
+	// All failing assertions are reported together
+	got := checkExpectedHeaders(h, []db.HeaderAssertion{{Name: "X-Frame-Options"}, {Name: "Content-Type", Value: "xml"}})
+	if !strings.Contains(got, "X-Frame-Options is missing; Content-Type is") {
+		t.Errorf("Expected both mismatches, got %q", got)
+	}
+}
+
+func TestRunHTTPCheckExpectedHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer srv.Close()
+
+	cfg := &db.RequestConfig{ExpectedHeaders: []db.HeaderAssertion{{Name: "Content-Type", Value: "application/json"}}}
+	c := runHTTPCheck(srv.Client().Transport, srv.URL, cfg)
+	if !c.isUp || !strings.Contains(c.headerMismatch, "Content-Type") {
+		t.Errorf("Expected an up check with a header mismatch, got up=%v %q", c.isUp, c.headerMismatch)
+	}
+
+	// A preview reports the mismatch as degraded
+	res := (&Manager{}).PreviewCheck(srv.URL, cfg, 1000)
+	if res.Status != "degraded" || res.DegradedReason == "" || res.ErrorKind != db.ErrorKindHeaderMismatch {
+		t.Errorf("Expected a degraded preview, got %+v", res)
+	}
+}
+
+func TestManager_HeaderMismatchDegrades(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	m := NewManager(store)
+	m.SetLatencyThreshold(5000)
+	m.Start()
+	defer m.Stop()
+
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-hsts", GroupID: "g-default", Name: "HSTS", URL: srv.URL, Active: true, Interval: 1,
+		RequestConfig: &db.RequestConfig{ExpectedHeaders: []db.HeaderAssertion{{Name: "Strict-Transport-Security"}}},
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m.Sync()
+	time.Sleep(2500 * time.Millisecond)
+
+	history := m.GetMonitor("m-hsts").GetHistory()
+	if len(history) == 0 {
+		t.Fatal("Expected checks to have run")
+	}
+	last := history[len(history)-1]
+	if !last.IsUp || !last.IsDegraded || !strings.Contains(last.DegradedReason, "Strict-Transport-Security is missing") {
+		t.Errorf("Expected an up, degraded check, got %+v", last)
+	}
+
+	events, _ := store.GetMonitorEvents("m-hsts", 5)
+	found := false
+	for _, e := range events {
+		found = found || (e.Type == "degraded" && strings.Contains(e.Message, "Strict-Transport-Security"))
+	}
+	if !found {
+		t.Errorf("Expected a degraded event naming the header, got %+v", events)
+	}
+}
+
+func TestManager_Sync_HydrateHeaderMismatch(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := NewManager(store)
+	m.SetLatencyThreshold(1000)
+
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-hdr", GroupID: "g-default", Name: "Headers", URL: "http://example.com", Active: true, Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := store.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-hdr", Status: "up", Latency: 50, Timestamp: time.Now().Add(-time.Minute), StatusCode: 200, ErrorKind: db.ErrorKindHeaderMismatch},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	m.Sync()
+	defer m.Stop()
+
+	history := m.GetMonitor("m-hdr").GetHistory()
+	if len(history) != 1 || !history[0].IsDegraded || history[0].DegradedReason != HeaderMismatchReason {
+		t.Errorf("Expected the header mismatch to hydrate as degraded, got %+v", history)
+	}
+}
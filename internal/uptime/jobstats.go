@@ -0,0 +1,111 @@
+package uptime
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+const (
+	// LateCheckThreshold is how long a job may wait in the queue before its check counts as late.
+	LateCheckThreshold = 5 * time.Second
+	// SkippedWarnStreak is the number of consecutive dropped checks after which a
+	// monitor is reported as being skipped by the scheduler.
+	SkippedWarnStreak = 3
+)
+
+// JobStats counts how checks moved through the shared job queue.
+type JobStats struct {
+	Scheduled int64 `json:"scheduled"` // jobs accepted by the queue
+	Executed  int64 `json:"executed"`  // jobs picked up by a worker
+	Dropped   int64 `json:"dropped"`   // ticks skipped because the queue was full
+	Late      int64 `json:"late"`      // jobs that waited longer than LateCheckThreshold
+}
+
+// MonitorJobStats is the per-monitor view of JobStats.
+type MonitorJobStats struct {
+	MonitorID string `json:"monitorId"`
+	Name      string `json:"name"`
+	JobStats
+	ConsecutiveDrops int  `json:"consecutiveDrops"`
+	Skipping         bool `json:"skipping"` // dropped at least SkippedWarnStreak checks in a row
+}
+
+// SchedulerStats describes the job queue and per-monitor scheduling counters.
+// Counters start when a monitor is (re)scheduled and live only in memory.
+type SchedulerStats struct {
+	Workers          int               `json:"workers"`
+	QueueLength      int               `json:"queueLength"`
+	QueueCapacity    int               `json:"queueCapacity"`
+	Totals           JobStats          `json:"totals"`
+	SkippingMonitors int               `json:"skippingMonitors"`
+	Monitors         []MonitorJobStats `json:"monitors"`
+}
+
+// recordEnqueue updates the counters after an attempt to queue a check.
+func (m *Monitor) recordEnqueue(queued bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if queued {
+		m.jobStats.Scheduled++
+		m.consecutiveDrops = 0
+		return
+	}
+	m.jobStats.Dropped++
+	m.consecutiveDrops++
+	if m.consecutiveDrops == SkippedWarnStreak {
+		log.Printf("Scheduler: monitor %s skipped %d consecutive checks, job queue is full", m.name, m.consecutiveDrops)
+	}
+}
+
+// RecordExecution counts a job picked up by a worker after waiting in the queue.
+func (m *Monitor) RecordExecution(wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobStats.Executed++
+	if wait > LateCheckThreshold {
+		m.jobStats.Late++
+	}
+}
+
+// GetJobStats returns the monitor's queue counters and current drop streak.
+func (m *Monitor) GetJobStats() (JobStats, int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.jobStats, m.consecutiveDrops
+}
+
+// SchedulerStats snapshots the job queue and every monitor's scheduling counters.
+func (m *Manager) SchedulerStats() SchedulerStats {
+	m.mu.RLock()
+	stats := SchedulerStats{
+		Workers:       WorkerCount,
+		QueueLength:   len(m.jobQueue),
+		QueueCapacity: cap(m.jobQueue),
+		Monitors:      make([]MonitorJobStats, 0, len(m.monitors)),
+	}
+	for id, mon := range m.monitors {
+		js, drops := mon.GetJobStats()
+		entry := MonitorJobStats{
+			MonitorID:        id,
+			Name:             mon.GetName(),
+			JobStats:         js,
+			ConsecutiveDrops: drops,
+			Skipping:         drops >= SkippedWarnStreak,
+		}
+		stats.Totals.Scheduled += js.Scheduled
+		stats.Totals.Executed += js.Executed
+		stats.Totals.Dropped += js.Dropped
+		stats.Totals.Late += js.Late
+		if entry.Skipping {
+			stats.SkippingMonitors++
+		}
+		stats.Monitors = append(stats.Monitors, entry)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(stats.Monitors, func(i, j int) bool {
+		return stats.Monitors[i].MonitorID < stats.Monitors[j].MonitorID
+	})
+	return stats
+}
@@ -0,0 +1,87 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitor_JobStatsCountsDrops(t *testing.T) {
+	queue := make(chan Job, 1)
+	mon := NewMonitor("m1", "g1", "M1", "http://example.com", time.Minute, queue, time.Now(), nil)
+
+	if !mon.CheckNow() {
+		t.Fatal("first job should be queued")
+	}
+	for i := 0; i < SkippedWarnStreak; i++ {
+		if mon.CheckNow() {
+			t.Fatal("queue is full, job should be dropped")
+		}
+	}
+
+	stats, drops := mon.GetJobStats()
+	if stats.Scheduled != 1 || stats.Dropped != int64(SkippedWarnStreak) || drops != SkippedWarnStreak {
+		t.Fatalf("unexpected stats %+v drops=%d", stats, drops)
+	}
+
+	job := <-queue
+	if job.ScheduledAt.IsZero() {
+		t.Error("job should carry its queue time")
+	}
+	mon.RecordExecution(time.Second)
+	mon.RecordExecution(LateCheckThreshold + time.Second)
+
+	// A successful enqueue ends the drop streak
+	if !mon.CheckNow() {
+		t.Fatal("job should be queued after draining")
+	}
+	stats, drops = mon.GetJobStats()
+	if stats.Executed != 2 || stats.Late != 1 || stats.Scheduled != 2 || drops != 0 {
+		t.Fatalf("unexpected stats %+v drops=%d", stats, drops)
+	}
+}
+
+func TestManager_SchedulerStats(t *testing.T) {
+	m, s := newTestManager(t)
+	defer m.Reset()
+	for _, id := range []string{"m-sched-b", "m-sched-a"} {
+		if err := s.CreateMonitor(db.Monitor{ID: id, GroupID: "g-default", Name: id, URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+	}
+	m.Sync()
+
+	mon := m.GetMonitor("m-sched-a")
+	for len(m.jobQueue) < cap(m.jobQueue) {
+		m.jobQueue <- Job{MonitorID: "filler"}
+	}
+	for i := 0; i < SkippedWarnStreak; i++ {
+		mon.CheckNow()
+	}
+
+	stats := m.SchedulerStats()
+	if stats.QueueLength != stats.QueueCapacity || stats.Workers != WorkerCount {
+		t.Errorf("unexpected queue stats %+v", stats)
+	}
+	if stats.SkippingMonitors != 1 || stats.Totals.Dropped < int64(SkippedWarnStreak) {
+		t.Errorf("expected one skipping monitor, got %+v", stats)
+	}
+	var found bool
+	for _, ms := range stats.Monitors {
+		if ms.MonitorID == "m-sched-a" {
+			found = true
+			if !ms.Skipping || ms.ConsecutiveDrops != SkippedWarnStreak {
+				t.Errorf("m-sched-a should be skipping, got %+v", ms)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("m-sched-a missing from stats")
+	}
+	for i := 1; i < len(stats.Monitors); i++ {
+		if stats.Monitors[i-1].MonitorID > stats.Monitors[i].MonitorID {
+			t.Fatal("monitors should be sorted by id")
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package uptime
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// LeaderElectionInterval is how often a standby tries to take over and the
+// leader re-verifies that it still holds the lock.
+const LeaderElectionInterval = 5 * time.Second
+
+// RunLeaderElection keeps the manager in standby until this instance holds the
+// database leader lock, then promotes it. If the lock's session is lost the
+// manager steps down so a healthy instance can take over. It blocks until ctx
+// is cancelled and releases the lock on return.
+func (m *Manager) RunLeaderElection(ctx context.Context, interval time.Duration) {
+	m.SetStandby(true)
+
+	var lock *db.LeaderLock
+	defer func() {
+		if lock != nil {
+			lock.Release()
+		}
+	}()
+
+	step := func() {
+		if lock != nil {
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			err := lock.Check(checkCtx)
+			cancel()
+			if err == nil {
+				return
+			}
+			log.Printf("HA: lost leader lock, stepping down: %v", err)
+			lock.Release()
+			lock = nil
+			m.SetStandby(true)
+		}
+
+		l, err := m.store.TryAcquireLeaderLock(ctx)
+		if err != nil {
+			log.Printf("HA: leader election failed: %v", err)
+			return
+		}
+		if l == nil {
+			return
+		}
+		lock = l
+		log.Println("HA: acquired leader lock, scheduling checks")
+		m.SetStandby(false)
+	}
+
+	step()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			step()
+		}
+	}
+}
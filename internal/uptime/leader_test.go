@@ -0,0 +1,85 @@
+package uptime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func newStandbyTestManager(t *testing.T) (*Manager, *db.Store) {
+	t.Helper()
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:leader%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	if err := store.CreateMonitor(db.Monitor{
+		ID:       "m-ha",
+		GroupID:  "g-default",
+		Name:     "HA Test",
+		URL:      "http://example.com",
+		Active:   true,
+		Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	return NewManager(store), store
+}
+
+func TestManager_StandbyDoesNotSchedule(t *testing.T) {
+	m, _ := newStandbyTestManager(t)
+	defer m.Reset()
+
+	if !m.IsLeader() {
+		t.Fatal("a standalone manager should be the leader")
+	}
+	m.SetStandby(true)
+	if m.IsLeader() {
+		t.Fatal("expected standby after SetStandby(true)")
+	}
+
+	if m.GetMonitor("m-ha") == nil {
+		t.Fatal("standby should still expose monitors for the API")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := len(m.jobQueue); n != 0 {
+		t.Fatalf("standby must not schedule checks, got %d queued jobs", n)
+	}
+
+	m.SetStandby(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(m.jobQueue) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(m.jobQueue) == 0 {
+		t.Fatal("leader should schedule an immediate check after promotion")
+	}
+}
+
+func TestManager_StandbyMirrorsLeaderHistory(t *testing.T) {
+	m, s := newStandbyTestManager(t)
+	defer m.Reset()
+	m.SetStandby(true)
+
+	if h := m.GetMonitor("m-ha").GetHistory(); len(h) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(h))
+	}
+
+	// Another instance (the leader) records a check
+	if err := s.BatchInsertChecks([]db.CheckResult{{
+		MonitorID:  "m-ha",
+		Status:     "down",
+		Latency:    120,
+		Timestamp:  time.Now(),
+		StatusCode: 503,
+	}}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	m.Sync()
+	h := m.GetMonitor("m-ha").GetHistory()
+	if len(h) != 1 || h[0].IsUp {
+		t.Fatalf("standby should pick up the leader's checks on sync, got %+v", h)
+	}
+}
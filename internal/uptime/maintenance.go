@@ -0,0 +1,59 @@
+package uptime
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// maintenanceWindow is the period of a maintenance incident, during which its groups'
+// failures are not alerted on.
+type maintenanceWindow struct {
+	start time.Time
+	end   *time.Time // nil = until completed
+}
+
+func (w maintenanceWindow) activeAt(t time.Time) bool {
+	return t.After(w.start) && (w.end == nil || t.Before(*w.end))
+}
+
+// indexMaintenance maps every affected group to its maintenance windows, so results are
+// matched without parsing affected_groups JSON on every check. Windows with malformed
+// affected groups cover none.
+func indexMaintenance(incidents []db.Incident) map[string][]maintenanceWindow {
+	byGroup := make(map[string][]maintenanceWindow)
+	for _, inc := range incidents {
+		if inc.AffectedGroups == "" {
+			continue
+		}
+		var groups []string
+		if err := json.Unmarshal([]byte(inc.AffectedGroups), &groups); err != nil {
+			continue
+		}
+		w := maintenanceWindow{start: inc.StartTime, end: inc.EndTime}
+		for _, g := range groups {
+			byGroup[g] = append(byGroup[g], w)
+		}
+	}
+	return byGroup
+}
+
+// IsGroupInMaintenance checks if a specific group is currently in an active maintenance window
+func (m *Manager) IsGroupInMaintenance(groupID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now().UTC()
+	for _, w := range m.maintenanceByGroup[groupID] {
+		if w.activeAt(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMonitorInMaintenance checks if a monitor's group is in an active maintenance window.
+func (m *Manager) isMonitorInMaintenance(groupID string) bool {
+	return m.IsGroupInMaintenance(groupID)
+}
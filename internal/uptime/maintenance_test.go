@@ -0,0 +1,65 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestIndexMaintenance(t *testing.T) {
+	now := time.Now().UTC()
+	end := now.Add(time.Hour)
+	byGroup := indexMaintenance([]db.Incident{
+		{ID: "active", StartTime: now.Add(-time.Hour), EndTime: &end, AffectedGroups: `["g1","g2"]`},
+		{ID: "open-ended", StartTime: now.Add(-time.Minute), AffectedGroups: `["g3"]`},
+		{ID: "upcoming", StartTime: now.Add(time.Hour), AffectedGroups: `["g1"]`},
+		{ID: "malformed", StartTime: now.Add(-time.Hour), AffectedGroups: `g4`},
+		{ID: "no-groups", StartTime: now.Add(-time.Hour)},
+	})
+
+	if len(byGroup["g1"]) != 2 || len(byGroup["g2"]) != 1 {
+		t.Errorf("Expected g1 in 2 windows and g2 in 1, got %v", byGroup)
+	}
+	if len(byGroup["g4"]) != 0 || len(byGroup) != 3 {
+		t.Errorf("Expected windows without parseable groups to cover none, got %v", byGroup)
+	}
+
+	if !byGroup["g3"][0].activeAt(now.Add(24 * time.Hour)) {
+		t.Error("Expected a window without end to stay active")
+	}
+	if byGroup["g2"][0].activeAt(end.Add(time.Second)) {
+		t.Error("Expected a window to end at its end time")
+	}
+}
+
+func TestManager_IsGroupInMaintenance_UpdatedBySync(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	m := NewManager(store)
+
+	end := time.Now().Add(time.Hour)
+	if err := store.CreateIncident(db.Incident{
+		ID: "inc-maint-sync", Title: "Maintenance", Type: "maintenance", Status: "scheduled",
+		StartTime: time.Now().Add(-time.Hour), EndTime: &end, AffectedGroups: `["g1"]`,
+	}); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	m.Sync()
+	if !m.IsGroupInMaintenance("g1") || !m.isMonitorInMaintenance("g1") {
+		t.Fatal("Group g1 should be in maintenance")
+	}
+
+	if err := store.UpdateIncident(db.Incident{
+		ID: "inc-maint-sync", Title: "Maintenance", Type: "maintenance", Status: "completed",
+		StartTime: time.Now().Add(-time.Hour), EndTime: &end, AffectedGroups: `["g1"]`,
+	}); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	m.Sync()
+	if m.IsGroupInMaintenance("g1") {
+		t.Error("Group g1 should leave maintenance once the window is completed")
+	}
+}
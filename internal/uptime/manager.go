@@ -1,12 +1,15 @@
 package uptime
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/projecthelena/warden/internal/db"
@@ -16,19 +19,28 @@ import (
 type Job struct {
 	MonitorID     string
 	URL           string
+	MonitorType   string
 	RequestConfig *db.RequestConfig
+	ScheduledAt   time.Time // when the job was queued, for late-check accounting
 }
 
 type CheckResult struct {
-	MonitorID  string
-	URL        string
-	Status     bool
-	Latency    int64
-	Timestamp  time.Time
-	StatusCode int
-	Error      string
-	IsDegraded bool
-	CertExpiry *time.Time // SSL certificate NotAfter (nil if not HTTPS or unavailable)
+	MonitorID      string
+	URL            string
+	Status         bool
+	Latency        int64
+	Timestamp      time.Time
+	StatusCode     int
+	Error          string
+	IsDegraded     bool
+	CertExpiry     *time.Time         // SSL certificate NotAfter (nil if not HTTPS or unavailable)
+	Summary        string             // Overrides the default down message (external alerts, agent reporting)
+	ErrorKind      string             // Failure classification (db.ErrorKind*), empty when up unless degraded by DegradedReason
+	Evidence       *db.OutageEvidence // Response details of a failed check, kept with the outage
+	Timing         *db.CheckTiming    // Request phase breakdown (nil for non-HTTP checks or without a response)
+	DegradedReason string             // Why an up check is degraded regardless of latency (e.g. failed header assertions)
+	Replayed       bool               // Uploaded after the fact; events and outages are stamped with Timestamp
+	Region         string             // Reporting region or agent of an external result, empty for local checks
 }
 
 // SSL notification thresholds in days
@@ -49,6 +61,8 @@ type Manager struct {
 	resultQueue chan CheckResult
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
+	workers     sync.WaitGroup // Check workers, drained by Shutdown
+	resultsDone chan struct{}  // Closed once the result processor wrote its last batch
 
 	latencyThreshold int64
 
@@ -66,10 +80,70 @@ type Manager struct {
 	digestTime       string // HH:MM
 	digestEventTypes map[string]bool
 
-	// Active Maintenance Windows
-	maintenanceWindows []db.Incident
+	// Active and upcoming maintenance windows by affected group, parsed once per Sync
+	maintenanceByGroup map[string][]maintenanceWindow
+
+	// Current and upcoming silences by monitor, resolved once per Sync
+	silencesByMonitor map[string][]db.Silence
+
+	// Declared dependencies: monitor ID -> IDs of the monitors it depends on
+	dependencies map[string][]string
+
+	// Composite monitor rules, and the composites each member belongs to
+	composites         map[string]db.CompositeRule
+	compositesByMember map[string][]string
+
+	// Shadow checks still due per monitor, from its dark-launch trial
+	shadows map[string]*shadowTrial
+
+	// Path diagnostics on sustained failures (0 = disabled)
+	tracerouteAfterChecks int
+	tracer                Tracer
+	tracerouteSlots       chan struct{}
+
+	// Remediation webhooks called when an outage opens, by monitor ID
+	remediations map[string]db.MonitorRemediation
+
+	// Local fallback for check batches the database rejected (nil = drop them)
+	spool *CheckSpool
+	// Checks whose insert failed, retried with the next batch; only touched by resultProcessor
+	failedBatch    []db.CheckResult
+	failedAttempts int
+
+	// HA standby: another instance holds the leader lock and schedules checks.
+	// A standby still serves the API, mirroring monitor state from the database.
+	standby bool
+
+	// Refuse to check private network addresses (shared/public installs)
+	blockPrivateTargets bool
+
+	// Answers every check instead of the network when set (demo mode)
+	checkTransport http.RoundTripper
+
+	// Recent checks each monitor keeps in memory (and loads on Sync)
+	historySize int
+
+	// Closed and replaced after every Sync, waking SyncNotify callers
+	syncDone chan struct{}
 
 	notifier *notifications.Service
+
+	// Serializes external result ingestion so results enter the pipeline in timestamp order
+	ingestMu sync.Mutex
+
+	// Set once the first Sync has loaded every monitor with its recent history
+	hydrated atomic.Bool
+
+	// Wakes the retention worker to prune now, after the retention setting changed
+	retentionWake chan struct{}
+
+	// Wakes the watchdog worker to ping now, after the watchdog settings changed
+	watchdogWake chan struct{}
+	// Whether the last watchdog ping failed, so failures are logged once
+	watchdogFailing atomic.Bool
+
+	// Collapses mass failures into one platform event (storm dampening)
+	storm *stormDetector
 }
 
 const (
@@ -78,18 +152,29 @@ const (
 	BatchTime   = 2 * time.Second
 )
 
+// maxEventsPerMonitor caps the event history kept for each monitor by the retention worker.
+const maxEventsPerMonitor = 1000
+
 func NewManager(store *db.Store) *Manager {
 	m := &Manager{
 		store:                 store,
 		monitors:              make(map[string]*Monitor),
-		maintenanceWindows:    make([]db.Incident, 0),
+		maintenanceByGroup:    make(map[string][]maintenanceWindow),
 		jobQueue:              make(chan Job, 1000),         // Buffer for bursts
 		resultQueue:           make(chan CheckResult, 1000), // Buffer for results
 		stopCh:                make(chan struct{}),
+		resultsDone:           make(chan struct{}),
+		retentionWake:         make(chan struct{}, 1),
+		watchdogWake:          make(chan struct{}, 1),
+		storm:                 newStormDetector(),
 		latencyThreshold:      1000, // Default
 		sslNotifiedThresholds: make(map[string]*sslThresholdState),
 		notificationTimezone:  time.UTC, // Default to UTC
 		notifier:              notifications.NewService(store),
+		tracer:                systemTracer,
+		tracerouteSlots:       make(chan struct{}, maxConcurrentTraceroutes),
+		syncDone:              make(chan struct{}),
+		historySize:           DefaultHistorySize,
 		eventFilter: NotificationEventFilter{
 			DownEnabled:        true,
 			UpEnabled:          true,
@@ -97,9 +182,16 @@ func NewManager(store *db.Store) *Manager {
 			FlappingEnabled:    true,
 			StabilizedEnabled:  true,
 			SSLExpiringEnabled: true,
+			AnomalyEnabled:     true,
 		},
 	}
 
+	// Standbys leave maintenance reminders to the leader
+	m.notifier.SetLeaderCheck(m.IsLeader)
+
+	// Apply settings saved through the API without waiting for the next periodic Sync
+	store.OnSettingsChange(m.settingsChanged)
+
 	// Load settings
 	if val, err := store.GetSetting("latency_threshold"); err == nil {
 		if i, err := strconv.Atoi(val); err == nil {
@@ -113,7 +205,7 @@ func NewManager(store *db.Store) *Manager {
 func (m *Manager) Start() {
 	// Start Workers
 	for i := 0; i < WorkerCount; i++ {
-		m.wg.Add(1)
+		m.workers.Add(1)
 		go m.worker()
 	}
 
@@ -127,6 +219,15 @@ func (m *Manager) Start() {
 	// Start Digest Worker
 	go m.digestWorker()
 
+	// Start Latency SLO Worker
+	go m.sloWorker()
+
+	// Start Storm Dampening Worker
+	go m.stormWorker()
+
+	// Start Watchdog Worker
+	go m.watchdogWorker()
+
 	// Start Notification Service
 	m.notifier.Start()
 
@@ -151,6 +252,45 @@ func (m *Manager) Start() {
 
 func (m *Manager) Stop() {
 	close(m.stopCh)
+	m.notifier.Stop()
+	m.stopScheduling()
+}
+
+// Shutdown stops the manager like Stop, but first lets the checks already running
+// finish and writes their results, so a restart loses none of them. It stops waiting
+// when ctx is done and returns ctx's error.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.stopScheduling()
+
+	workersDone := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(workersDone)
+	}()
+	var err error
+	select {
+	case <-workersDone:
+		err = m.waitResultsTaken(ctx)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// The result processor writes its pending batch on the way out
+	close(m.stopCh)
+	m.notifier.Stop()
+	select {
+	case <-m.resultsDone:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// stopScheduling stops every monitor and closes the job queue, so workers exit once
+// they have run the jobs already queued.
+func (m *Manager) stopScheduling() {
 	// Stop monitors (producers)
 	m.mu.Lock()
 	for _, mon := range m.monitors {
@@ -159,8 +299,20 @@ func (m *Manager) Stop() {
 	m.mu.Unlock()
 
 	close(m.jobQueue)
-	// Wait for workers to finish
-	// m.wg.Wait() // Optional: strictly wait or just let app exit
+}
+
+// waitResultsTaken waits until the result processor has taken every queued result.
+func (m *Manager) waitResultsTaken(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for len(m.resultQueue) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
 }
 
 // Reset stops all monitors and clears the map. Used before DB reset.
@@ -175,142 +327,321 @@ func (m *Manager) Reset() {
 	m.sslNotifiedThresholds = make(map[string]*sslThresholdState)
 }
 
-func (m *Manager) worker() {
-	defer m.wg.Done()
+// IsLeader reports whether this instance schedules checks and runs background
+// jobs. It is always true unless HA mode put the manager in standby.
+func (m *Manager) IsLeader() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.standby
+}
 
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     30 * time.Second,
+// SetStandby switches between leader and standby mode. Monitors are rebuilt
+// from the database so a new leader resumes from the previous leader's state.
+func (m *Manager) SetStandby(standby bool) {
+	m.mu.Lock()
+	if m.standby == standby {
+		m.mu.Unlock()
+		return
 	}
+	m.standby = standby
+	for id, mon := range m.monitors {
+		mon.Stop()
+		delete(m.monitors, id)
+	}
+	m.sslNotifiedThresholds = make(map[string]*sslThresholdState)
+	m.mu.Unlock()
 
-	for job := range m.jobQueue {
-		cfg := job.RequestConfig
+	m.Sync()
+}
 
-		// Resolve method
-		method := "GET"
-		if cfg != nil && cfg.Method != "" {
-			method = cfg.Method
-		}
+func (m *Manager) worker() {
+	defer m.workers.Done()
 
-		// Resolve timeout
-		timeout := 5 * time.Second
-		if cfg != nil && cfg.TimeoutSeconds > 0 {
-			timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
-		}
+	transports := newCheckTransports(m.BlocksPrivateTargets())
+	transports.override = m.checkTransport
 
-		// Build per-job client wrapping the shared transport
-		client := &http.Client{
-			Timeout:   timeout,
-			Transport: transport,
+	for job := range m.jobQueue {
+		if mon := m.GetMonitor(job.MonitorID); mon != nil && !job.ScheduledAt.IsZero() {
+			mon.RecordExecution(time.Since(job.ScheduledAt))
 		}
-
-		// Redirect policy
-		if cfg != nil && cfg.FollowRedirects != nil && !*cfg.FollowRedirects {
-			client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
+		transport := transports.forConfig(job.RequestConfig)
+		c := runHTTPCheck(transport, job.URL, job.RequestConfig)
+		isUp, errMsg, errKind := c.isUp, c.errMsg, c.errKind
+
+		// Agent monitors are also down when the agent answers health checks but stops reporting cost data
+		var summary, degradedReason string
+		if isUp && job.MonitorType == db.MonitorTypeAgent {
+			if problem := m.agentReportingProblem(job.MonitorID, time.Now()); problem != "" {
+				isUp = false
+				errMsg = problem
+				errKind = db.ErrorKindAgentReporting
+				summary = problem
 			}
 		}
+		if isUp && c.headerMismatch != "" {
+			errKind = db.ErrorKindHeaderMismatch
+			degradedReason = c.headerMismatch
+		}
 
-		// Resolve retry count
-		retryCount := 0
-		if cfg != nil && cfg.RetryCount > 0 {
-			retryCount = cfg.RetryCount
+		m.resultQueue <- CheckResult{
+			MonitorID:      job.MonitorID,
+			URL:            job.URL,
+			Status:         isUp,
+			Latency:        c.latency,
+			Timestamp:      c.start,
+			StatusCode:     c.statusCode,
+			Error:          errMsg,
+			CertExpiry:     c.certExpiry,
+			Summary:        summary,
+			ErrorKind:      errKind,
+			Evidence:       c.evidence,
+			Timing:         c.timing,
+			DegradedReason: degradedReason,
 		}
 
-		// Resolve request body
-		var bodyStr string
-		if cfg != nil {
-			bodyStr = cfg.Body
+		if url, ok := m.claimShadowCheck(job.MonitorID); ok {
+			m.runShadowCheck(transport, job, url, c)
 		}
+	}
+}
 
-		var (
-			isUp       bool
-			errMsg     string
-			statusCode int
-			certExpiry *time.Time
-			latency    int64
-			start      time.Time
-		)
+// checkTransports are a worker's HTTP transports: pooled connections by default, and a
+// new connection per check for monitors with FreshConnection set, so DNS, connect and
+// TLS regressions aren't hidden behind a kept-alive connection.
+type checkTransports struct {
+	pooled   *http.Transport
+	fresh    *http.Transport
+	override http.RoundTripper // Set by SetCheckTransport, used for every check
+}
 
-		for attempt := 0; attempt <= retryCount; attempt++ {
-			if attempt > 0 {
-				time.Sleep(1 * time.Second)
-			}
+// SetCheckTransport routes every check, including previews, through rt instead of the
+// network, as demo mode does to answer checks of made-up hosts. Call before Start.
+func (m *Manager) SetCheckTransport(rt http.RoundTripper) {
+	m.checkTransport = rt
+}
 
-			// Build request
-			var bodyReader *strings.Reader
-			if bodyStr != "" {
-				bodyReader = strings.NewReader(bodyStr)
-			}
-			var req *http.Request
-			var reqErr error
-			if bodyReader != nil {
-				req, reqErr = http.NewRequest(method, job.URL, bodyReader)
-			} else {
-				req, reqErr = http.NewRequest(method, job.URL, nil)
-			}
-			if reqErr != nil {
-				isUp = false
-				errMsg = reqErr.Error()
-				break // Don't retry on request build errors
-			}
+func newCheckTransports(blockPrivate bool) checkTransports {
+	return checkTransports{
+		pooled: &http.Transport{
+			DialContext:         checkDialer(blockPrivate),
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		fresh: &http.Transport{
+			DialContext:       checkDialer(blockPrivate),
+			DisableKeepAlives: true,
+		},
+	}
+}
 
-			// Apply custom headers
-			if cfg != nil {
-				for k, v := range cfg.Headers {
-					req.Header.Set(k, v)
-				}
-			}
+func (t checkTransports) forConfig(cfg *db.RequestConfig) http.RoundTripper {
+	if t.override != nil {
+		return t.override
+	}
+	if cfg != nil && cfg.FreshConnection {
+		return t.fresh
+	}
+	return t.pooled
+}
 
-			start = time.Now().UTC()
-			resp, err := client.Do(req)
-			latency = time.Since(start).Milliseconds()
+// httpCheck is the outcome of one HTTP check, after retries.
+type httpCheck struct {
+	isUp           bool
+	errMsg         string
+	errKind        string
+	evidence       *db.OutageEvidence
+	statusCode     int
+	certExpiry     *time.Time
+	latency        int64
+	start          time.Time
+	timing         *db.CheckTiming // nil when no response was received
+	headerMismatch string          // Why an up response failed the expected header assertions
+}
 
-			isUp = true
-			errMsg = ""
-			statusCode = 0
-			certExpiry = nil
+// runHTTPCheck requests url as configured by cfg, retrying failures.
+func runHTTPCheck(transport http.RoundTripper, url string, cfg *db.RequestConfig) httpCheck {
+	// Resolve method
+	method := "GET"
+	if cfg != nil && cfg.Method != "" {
+		method = cfg.Method
+	}
 
-			if err != nil {
-				isUp = false
-				errMsg = err.Error()
-			} else {
-				_ = resp.Body.Close()
-				statusCode = resp.StatusCode
+	// Resolve timeout
+	timeout := 5 * time.Second
+	if cfg != nil && cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
 
-				// Determine if status code is accepted
-				if cfg != nil && cfg.AcceptedStatusCodes != "" {
-					isUp = isAcceptedStatus(resp.StatusCode, cfg.AcceptedStatusCodes)
-				} else {
-					if resp.StatusCode >= 400 {
-						isUp = false
-					}
-				}
+	// Build per-job client wrapping the shared transport
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	// Redirect policy
+	if cfg != nil && cfg.FollowRedirects != nil && !*cfg.FollowRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	// Resolve retry count
+	retryCount := 0
+	if cfg != nil && cfg.RetryCount > 0 {
+		retryCount = cfg.RetryCount
+	}
+
+	// Resolve request body
+	var bodyStr string
+	if cfg != nil {
+		bodyStr = cfg.Body
+	}
+
+	var (
+		isUp           bool
+		errMsg         string
+		errKind        string
+		evidence       *db.OutageEvidence
+		statusCode     int
+		certExpiry     *time.Time
+		latency        int64
+		start          time.Time
+		timing         *db.CheckTiming
+		headerMismatch string
+	)
+
+	for attempt := 0; attempt <= retryCount; attempt++ {
+		if attempt > 0 {
+			time.Sleep(1 * time.Second)
+		}
+
+		// Build request
+		var bodyReader *strings.Reader
+		if bodyStr != "" {
+			bodyReader = strings.NewReader(bodyStr)
+		}
+		var req *http.Request
+		var reqErr error
+		if bodyReader != nil {
+			req, reqErr = http.NewRequest(method, url, bodyReader)
+		} else {
+			req, reqErr = http.NewRequest(method, url, nil)
+		}
+		if reqErr != nil {
+			isUp = false
+			errMsg = reqErr.Error()
+			errKind = db.ErrorKindUnknown
+			evidence = &db.OutageEvidence{Error: errMsg}
+			break // Don't retry on request build errors
+		}
+
+		// Apply custom headers
+		if cfg != nil {
+			for k, v := range cfg.Headers {
+				req.Header.Set(k, v)
+			}
+		}
+
+		timer := &checkTimer{}
+		req = req.WithContext(timer.trace(req.Context()))
+
+		start = time.Now().UTC()
+		resp, err := client.Do(req)
+		received := time.Now()
+		latency = received.Sub(start).Milliseconds()
+
+		isUp = true
+		errMsg = ""
+		errKind = ""
+		evidence = nil
+		statusCode = 0
+		certExpiry = nil
+		timing = nil
+		headerMismatch = ""
+
+		if err != nil {
+			isUp = false
+			errMsg = err.Error()
+			errKind = ClassifyError(err, 0)
+			evidence = &db.OutageEvidence{Error: errMsg}
+		} else {
+			statusCode = resp.StatusCode
 
-				// Extract SSL certificate expiry for HTTPS URLs
-				if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
-					notAfter := resp.TLS.PeerCertificates[0].NotAfter
-					certExpiry = &notAfter
+			// Determine if status code is accepted
+			if cfg != nil && cfg.AcceptedStatusCodes != "" {
+				isUp = isAcceptedStatus(resp.StatusCode, cfg.AcceptedStatusCodes)
+			} else {
+				if resp.StatusCode >= 400 {
+					isUp = false
 				}
 			}
 
-			if isUp {
-				break // Success, no need to retry
+			if isUp && cfg != nil && len(cfg.ExpectedHeaders) > 0 {
+				headerMismatch = checkExpectedHeaders(resp.Header, cfg.ExpectedHeaders)
+			}
+
+			body := &countingBody{ReadCloser: resp.Body}
+			resp.Body = body
+			if !isUp {
+				errKind = ClassifyError(nil, statusCode)
+				evidence = captureEvidence(resp)
+			}
+			// Read the rest of the body to measure its size and download time
+			_, _ = io.Copy(io.Discard, io.LimitReader(body, MaxTimedBodyBytes-body.n))
+			timing = timer.timing(body.n, time.Since(received))
+			_ = resp.Body.Close()
+
+			// Extract SSL certificate expiry for HTTPS URLs
+			if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+				notAfter := resp.TLS.PeerCertificates[0].NotAfter
+				certExpiry = &notAfter
 			}
 		}
 
-		m.resultQueue <- CheckResult{
-			MonitorID:  job.MonitorID,
-			URL:        job.URL,
-			Status:     isUp,
-			Latency:    latency,
-			Timestamp:  start,
-			StatusCode: statusCode,
-			Error:      errMsg,
-			CertExpiry: certExpiry,
+		if isUp {
+			break // Success, no need to retry
 		}
 	}
+
+	return httpCheck{
+		isUp:           isUp,
+		errMsg:         errMsg,
+		errKind:        errKind,
+		evidence:       evidence,
+		statusCode:     statusCode,
+		certExpiry:     certExpiry,
+		latency:        latency,
+		start:          start,
+		timing:         timing,
+		headerMismatch: headerMismatch,
+	}
+}
+
+// agentReportingProblem returns why a cost agent is considered not reporting, or "" when it is healthy.
+// An agent stops reporting when its latest poll failed or when it has not been polled for
+// three poll intervals (e.g. the poller is stuck).
+func (m *Manager) agentReportingProblem(agentID string, now time.Time) string {
+	a, err := m.store.GetAgent(agentID)
+	if err != nil || !a.Active {
+		return ""
+	}
+	if a.LastError != "" {
+		return "Agent stopped reporting: " + a.LastError
+	}
+
+	interval := a.Interval
+	if interval < 30 {
+		interval = 30
+	}
+	last := a.CreatedAt
+	if a.LastPolledAt != nil {
+		last = *a.LastPolledAt
+	}
+	if now.Sub(last) > 3*time.Duration(interval)*time.Second+time.Minute {
+		return "Agent has not reported since " + last.UTC().Format(time.RFC3339)
+	}
+	return ""
 }
 
 // isAcceptedStatus checks if a status code matches the accepted status code specification.
@@ -350,30 +681,67 @@ func requestConfigChanged(a, b *db.RequestConfig) bool {
 	return string(aJSON) != string(bJSON)
 }
 
+// processDegradedHysteresis feeds an up check into the monitor's N-of-M window and handles
+// the resulting transition into or out of the degraded state.
+func (m *Manager) processDegradedHysteresis(res CheckResult, mon *Monitor, isDegraded, isMaint bool, eventFilter NotificationEventFilter, degradedMsg string) {
+	entered, left := mon.RecordDegradedSample(isDegraded)
+	if entered {
+		m.recordEvent(res, "degraded", degradedMsg)
+		m.openOutage(res, "degraded", degradedMsg, "", nil)
+		if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("degraded") && eventFilter.IsEnabled("degraded") {
+			m.enqueueOrDigest(notifications.NotificationEvent{
+				MonitorID:   res.MonitorID,
+				MonitorName: mon.GetName(),
+				MonitorURL:  mon.GetTargetURL(),
+				Type:        notifications.EventDegraded,
+				Message:     degradedMsg,
+				Time:        res.Timestamp,
+			})
+			mon.MarkNotified("degraded")
+		}
+		log.Printf("Monitor %s is DEGRADED (confirmed)", res.MonitorID)
+	} else if left {
+		m.closeOutage(res)
+		m.recordEvent(res, "recovered", "Latency normalized")
+		// Recovery notifications always send immediately (no cooldown)
+		if !isMaint && !mon.IsFlapping() && eventFilter.IsEnabled("up") {
+			m.enqueueOrDigest(notifications.NotificationEvent{
+				MonitorID:   res.MonitorID,
+				MonitorName: mon.GetName(),
+				MonitorURL:  mon.GetTargetURL(),
+				Type:        notifications.EventUp,
+				Message:     "Latency normalized",
+				Time:        res.Timestamp,
+			})
+		}
+		log.Printf("Monitor %s RECOVERED from degraded", res.MonitorID)
+	}
+}
+
 func (m *Manager) resultProcessor() {
 	defer m.wg.Done()
+	defer close(m.resultsDone)
 
 	var batch []db.CheckResult
 	timer := time.NewTicker(BatchTime)
 	defer timer.Stop()
 
-	flush := func() {
-		if len(batch) == 0 {
+	flush := func(final bool) {
+		if len(batch) == 0 && len(m.failedBatch) == 0 {
 			return
 		}
-		if err := m.store.BatchInsertChecks(batch); err != nil {
-			log.Printf("Error capturing batch stats: %v", err)
-		}
+		m.writeBatch(batch, final)
 		batch = nil
 	}
 
 	for {
 		select {
 		case <-m.stopCh:
-			flush()
+			flush(true)
 			return
 		case <-timer.C:
-			flush()
+			flush(false)
+			m.replaySpool()
 		case res := <-m.resultQueue:
 			// 1. Detect Events (State Change)
 			m.mu.RLock()
@@ -394,7 +762,7 @@ func (m *Manager) resultProcessor() {
 				// Check if monitor is in maintenance
 				isMaint := m.isMonitorInMaintenance(mon.GetGroupID())
 
-				isDegraded := res.Status && res.Latency > threshold
+				isDegraded := res.Status && (res.Latency > threshold || res.DegradedReason != "")
 				res.IsDegraded = isDegraded // Update result for storage
 
 				wasDegraded := active && lastDegraded
@@ -403,24 +771,27 @@ func (m *Manager) resultProcessor() {
 				if res.StatusCode > 0 {
 					message += " (Status: " + strconv.Itoa(res.StatusCode) + ")"
 				}
+				if res.Summary != "" {
+					message = res.Summary
+				}
 
 				degradedMsg := "High latency detected (>" + strconv.FormatInt(threshold, 10) + "ms)"
+				if res.DegradedReason != "" {
+					degradedMsg = res.DegradedReason
+				}
 
 				if !hasHistory {
 					// Handle Initial State — use confirmation logic
 					if !res.Status {
 						mon.ResetRecovery()
 						// Record the event in DB immediately
-						go func() { _ = m.store.CreateEvent(res.MonitorID, "down", message) }()
+						m.recordEvent(res, "down", message)
 
-						confirmed := mon.IncrementDown()
+						confirmed := m.confirmDown(res, mon)
 						if confirmed {
-							go func() {
-								_ = m.store.CloseOutage(res.MonitorID)
-								_ = m.store.CreateOutage(res.MonitorID, "down", message)
-							}()
+							m.openOutage(res, "down", message, res.ErrorKind, res.Evidence)
 							if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("down") && eventFilter.IsEnabled("down") {
-								m.enqueueOrDigest(notifications.NotificationEvent{
+								m.notifyDown(notifications.NotificationEvent{
 									MonitorID:   res.MonitorID,
 									MonitorName: mon.GetName(),
 									MonitorURL:  mon.GetTargetURL(),
@@ -432,15 +803,14 @@ func (m *Manager) resultProcessor() {
 							}
 							log.Printf("Monitor %s is DOWN (confirmed)", res.MonitorID)
 						}
+					} else if mon.HasDegradedHysteresis() {
+						m.processDegradedHysteresis(res, mon, isDegraded, isMaint, eventFilter, degradedMsg)
 					} else if isDegraded {
-						go func() { _ = m.store.CreateEvent(res.MonitorID, "degraded", degradedMsg) }()
+						m.recordEvent(res, "degraded", degradedMsg)
 
 						confirmed := mon.IncrementDegraded()
 						if confirmed {
-							go func() {
-								_ = m.store.CloseOutage(res.MonitorID)
-								_ = m.store.CreateOutage(res.MonitorID, "degraded", degradedMsg)
-							}()
+							m.openOutage(res, "degraded", degradedMsg, "", nil)
 							if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("degraded") && eventFilter.IsEnabled("degraded") {
 								m.enqueueOrDigest(notifications.NotificationEvent{
 									MonitorID:   res.MonitorID,
@@ -460,17 +830,14 @@ func (m *Manager) resultProcessor() {
 						// Check is DOWN — increment counter
 						mon.ResetDegraded() // can't be degraded if down
 						mon.ResetRecovery() // reset recovery confirmation
-						go func() { _ = m.store.CreateEvent(res.MonitorID, "down", message) }()
+						m.recordEvent(res, "down", message)
 
-						confirmed := mon.IncrementDown()
+						confirmed := m.confirmDown(res, mon)
 						if confirmed {
 							// Threshold met — create outage and notify
-							go func() {
-								_ = m.store.CloseOutage(res.MonitorID)
-								_ = m.store.CreateOutage(res.MonitorID, "down", message)
-							}()
+							m.openOutage(res, "down", message, res.ErrorKind, res.Evidence)
 							if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("down") && eventFilter.IsEnabled("down") {
-								m.enqueueOrDigest(notifications.NotificationEvent{
+								m.notifyDown(notifications.NotificationEvent{
 									MonitorID:   res.MonitorID,
 									MonitorName: mon.GetName(),
 									MonitorURL:  mon.GetTargetURL(),
@@ -490,10 +857,14 @@ func (m *Manager) resultProcessor() {
 							if recoveryConfirmed {
 								mon.ResetDown()
 								mon.ResetRecovery()
-								go func() { _ = m.store.CloseOutage(res.MonitorID) }()
-								go func() { _ = m.store.CreateEvent(res.MonitorID, "recovered", "Monitor recovered") }()
+								// No down notification went out while a dependency was blamed, so none for recovery either
+								wasAffected := mon.SetAffectedBy("") != ""
+								m.closeOutage(res)
+								m.recordEvent(res, "recovered", "Monitor recovered")
+								// Nor for monitors whose recovery a platform event announces
+								inStorm := m.stormRecovered(res.MonitorID)
 								// Recovery notifications always send immediately (no cooldown)
-								if !isMaint && !mon.IsFlapping() && eventFilter.IsEnabled("up") {
+								if !isMaint && !mon.IsFlapping() && !wasAffected && !inStorm && eventFilter.IsEnabled("up") {
 									m.enqueueOrDigest(notifications.NotificationEvent{
 										MonitorID:   res.MonitorID,
 										MonitorName: mon.GetName(),
@@ -512,15 +883,14 @@ func (m *Manager) resultProcessor() {
 
 						// Handle Degradation (only if not still waiting for recovery confirmation)
 						if !mon.IsConfirmedDown() {
-							if isDegraded {
-								go func() { _ = m.store.CreateEvent(res.MonitorID, "degraded", degradedMsg) }()
+							if mon.HasDegradedHysteresis() {
+								m.processDegradedHysteresis(res, mon, isDegraded, isMaint, eventFilter, degradedMsg)
+							} else if isDegraded {
+								m.recordEvent(res, "degraded", degradedMsg)
 
 								confirmed := mon.IncrementDegraded()
 								if confirmed {
-									go func() {
-										_ = m.store.CloseOutage(res.MonitorID)
-										_ = m.store.CreateOutage(res.MonitorID, "degraded", degradedMsg)
-									}()
+									m.openOutage(res, "degraded", degradedMsg, "", nil)
 									if !isMaint && !mon.IsFlapping() && mon.ShouldNotify("degraded") && eventFilter.IsEnabled("degraded") {
 										m.enqueueOrDigest(notifications.NotificationEvent{
 											MonitorID:   res.MonitorID,
@@ -537,8 +907,8 @@ func (m *Manager) resultProcessor() {
 								// Degraded -> Normal
 								wasConfirmedDeg := mon.ResetDegraded()
 								if wasConfirmedDeg {
-									go func() { _ = m.store.CloseOutage(res.MonitorID) }()
-									go func() { _ = m.store.CreateEvent(res.MonitorID, "recovered", "Latency normalized") }()
+									m.closeOutage(res)
+									m.recordEvent(res, "recovered", "Latency normalized")
 									// Recovery notifications always send immediately (no cooldown)
 									if !isMaint && !mon.IsFlapping() && eventFilter.IsEnabled("up") {
 										m.enqueueOrDigest(notifications.NotificationEvent{
@@ -560,6 +930,9 @@ func (m *Manager) resultProcessor() {
 					}
 				}
 
+				// Latency well above the monitor's usual, though below the threshold
+				m.processLatencyAnomaly(res, mon, isDegraded, isMaint, eventFilter)
+
 				// SSL Certificate Expiry Check
 				m.processSSLCheck(res, mon, isMaint)
 
@@ -570,6 +943,9 @@ func (m *Manager) resultProcessor() {
 			// Update in-memory state
 			m.updateMonitorState(res)
 
+			// Recompute composites now that this member's history is up to date
+			m.evaluateComposites(res)
+
 			// Flap detection (after history is updated)
 			if exists {
 				m.mu.RLock()
@@ -580,7 +956,7 @@ func (m *Manager) resultProcessor() {
 					isFlapping, changed := mon.ComputeFlapping()
 					if changed && !isMaint {
 						if isFlapping {
-							go func() { _ = m.store.CreateEvent(res.MonitorID, "flapping", "Monitor is flapping between states") }()
+							m.recordEvent(res, "flapping", "Monitor is flapping between states")
 							if mon.ShouldNotify("flapping") && eventFilter.IsEnabled("flapping") {
 								m.enqueueOrDigest(notifications.NotificationEvent{
 									MonitorID:   res.MonitorID,
@@ -594,7 +970,7 @@ func (m *Manager) resultProcessor() {
 							}
 							log.Printf("Monitor %s is FLAPPING", res.MonitorID)
 						} else {
-							go func() { _ = m.store.CreateEvent(res.MonitorID, "stabilized", "Monitor has stabilized") }()
+							m.recordEvent(res, "stabilized", "Monitor has stabilized")
 							if mon.ShouldNotify("stabilized") && eventFilter.IsEnabled("stabilized") {
 								m.enqueueOrDigest(notifications.NotificationEvent{
 									MonitorID:   res.MonitorID,
@@ -612,6 +988,11 @@ func (m *Manager) resultProcessor() {
 				}
 			}
 
+			if exists && !res.Status && !res.Replayed {
+				m.maybeTraceroute(mon, res)
+				m.maybeRemediate(mon, res)
+			}
+
 			// Add to batch for DB persistence
 			statusStr := "down"
 			if res.Status {
@@ -623,34 +1004,16 @@ func (m *Manager) resultProcessor() {
 				Latency:    res.Latency,
 				Timestamp:  res.Timestamp,
 				StatusCode: res.StatusCode,
+				ErrorKind:  res.ErrorKind,
+				Timing:     res.Timing,
+				Region:     res.Region,
 			})
 
 			if len(batch) >= BatchSize {
-				flush()
-			}
-		}
-	}
-}
-
-// isMonitorInMaintenance checks if a monitor's group is in an active maintenance window.
-func (m *Manager) isMonitorInMaintenance(groupID string) bool {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	now := time.Now().UTC()
-	for _, w := range m.maintenanceWindows {
-		if now.After(w.StartTime) && (w.EndTime == nil || now.Before(*w.EndTime)) {
-			if w.AffectedGroups != "" {
-				var groups []string
-				_ = json.Unmarshal([]byte(w.AffectedGroups), &groups)
-				for _, g := range groups {
-					if g == groupID {
-						return true
-					}
-				}
+				flush(false)
 			}
 		}
 	}
-	return false
 }
 
 // processSSLCheck handles SSL certificate expiry checking and notifications.
@@ -712,7 +1075,7 @@ func (m *Manager) processSSLCheck(res CheckResult, mon *Monitor, isMaint bool) {
 		} else {
 			msg = "SSL certificate expires in " + strconv.Itoa(daysUntilExpiry) + " days (" + res.CertExpiry.Format("2006-01-02") + ")"
 		}
-		go func() { _ = m.store.CreateEvent(res.MonitorID, "ssl_expiring", msg) }()
+		m.recordEvent(res, "ssl_expiring", msg)
 
 		m.mu.RLock()
 		filter := m.eventFilter
@@ -738,7 +1101,16 @@ func (m *Manager) updateMonitorState(res CheckResult) {
 	m.mu.RUnlock()
 
 	if exists {
-		mon.RecordResult(res.Status, res.Latency, res.Timestamp, res.StatusCode, res.Error, res.IsDegraded)
+		mon.RecordStatus(Status{
+			Timestamp:      res.Timestamp,
+			Latency:        res.Latency,
+			IsUp:           res.Status,
+			StatusCode:     res.StatusCode,
+			Error:          res.Error,
+			IsDegraded:     res.IsDegraded,
+			Timing:         res.Timing,
+			DegradedReason: res.DegradedReason,
+		})
 	}
 }
 
@@ -749,16 +1121,20 @@ func (m *Manager) Sync() {
 		return
 	}
 
-	// Fetch Maintenance Windows
-	var activeWindows []db.Incident
-	incidents, err := m.store.GetIncidents(time.Time{})
-	if err == nil {
-		for _, i := range incidents {
-			// Keep all scheduled/in-progress maintenance
-			if i.Type == "maintenance" && i.Status != "completed" && i.Status != "resolved" {
-				activeWindows = append(activeWindows, i)
-			}
-		}
+	// Fetch scheduled/in-progress maintenance windows
+	activeWindows, err := m.store.GetIncidentsFiltered(db.IncidentFilter{Type: "maintenance", ActiveOnly: true})
+	if err != nil {
+		log.Println("Error loading maintenance windows:", err)
+	}
+	maintenanceByGroup := indexMaintenance(activeWindows)
+
+	// A failed load keeps the current silences muting, rather than lifting them all
+	var silencesByMonitor map[string][]db.Silence
+	silences, err := m.store.GetSilences(false, time.Now())
+	if err != nil {
+		log.Println("Error loading silences:", err)
+	} else {
+		silencesByMonitor = indexSilences(silences, dbMonitors)
 	}
 
 	// Load user timezone for notifications (from first/admin user)
@@ -775,19 +1151,71 @@ func (m *Manager) Sync() {
 	// Load event filter and digest config
 	eventFilter := m.loadEventFilter()
 	digestEnabled, digestTime, digestEventTypes := m.loadDigestConfig()
+	tracerouteAfterChecks := m.loadTracerouteAfterChecks()
+	m.setStormConfig(m.loadStormConfig())
+
+	dependencies, err := m.store.GetMonitorDependencies()
+	if err != nil {
+		log.Println("Error loading monitor dependencies:", err)
+	}
+	composites, err := m.store.GetCompositeRules()
+	if err != nil {
+		log.Println("Error loading composite monitors:", err)
+	}
+	shadows, err := m.store.GetMonitorShadows()
+	if err != nil {
+		log.Println("Error loading shadow trials:", err)
+	}
+	remediations, err := m.store.GetMonitorRemediations()
+	if err != nil {
+		log.Println("Error loading remediation actions:", err)
+	}
+	exclusions, err := m.store.GetAllMonitorCheckExclusions()
+	if err != nil {
+		log.Println("Error loading check exclusions:", err)
+	}
+
+	// Load the history of the monitors this sync builds before taking the lock, so API
+	// readers are not blocked while a large fleet is hydrated
+	histories := m.loadHistories(dbMonitors)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.standby {
+		// The leader writes new checks to the database; rebuild every monitor so
+		// its hydrated history reflects them.
+		for id, mon := range m.monitors {
+			mon.Stop()
+			delete(m.monitors, id)
+		}
+	}
+
 	// Update cached settings
 	m.notificationTimezone = notifTZ
 	m.eventFilter = eventFilter
 	m.digestEnabled = digestEnabled
 	m.digestTime = digestTime
 	m.digestEventTypes = digestEventTypes
+	m.tracerouteAfterChecks = tracerouteAfterChecks
 
 	// Update maintenance windows
-	m.maintenanceWindows = activeWindows
+	m.maintenanceByGroup = maintenanceByGroup
+	if silencesByMonitor != nil {
+		m.silencesByMonitor = silencesByMonitor
+	}
+	if dependencies != nil {
+		m.dependencies = dependencies
+	}
+	if composites != nil {
+		m.setComposites(composites)
+	}
+	if shadows != nil {
+		m.setShadows(shadows)
+	}
+	if remediations != nil {
+		m.setRemediations(remediations)
+	}
 
 	activeIDs := make(map[string]bool)
 
@@ -813,6 +1241,24 @@ func (m *Manager) Sync() {
 		if dbM.NotificationCooldownMin != nil {
 			cfg.CooldownMinutes = *dbM.NotificationCooldownMin
 		}
+		if dbM.DegradedWindowChecks != nil {
+			cfg.DegradedWindowChecks = *dbM.DegradedWindowChecks
+		}
+		if dbM.DegradedThresholdChecks != nil {
+			cfg.DegradedThresholdChecks = *dbM.DegradedThresholdChecks
+		}
+		if dbM.DownBackoffInterval != nil {
+			cfg.DownBackoffInterval = time.Duration(*dbM.DownBackoffInterval) * time.Second
+		}
+		if dbM.DownBackoffAfterChecks != nil {
+			cfg.DownBackoffAfterChecks = *dbM.DownBackoffAfterChecks
+		}
+		if dbM.Type == db.MonitorTypeExternal || dbM.Type == db.MonitorTypeComposite {
+			// The external system already debounced the alert, and a composite's members
+			// confirm their own failures; act on the first signal
+			cfg.ConfirmationThreshold = 1
+			cfg.RecoveryConfirmationChecks = 1
+		}
 
 		// Determine interval
 		intervalSec := dbM.Interval
@@ -831,9 +1277,12 @@ func (m *Manager) Sync() {
 			// Always apply latest config to existing monitors
 			existing.ApplyConfig(cfg)
 			existing.SetLatencyThreshold(monLatencyThresh)
+			if exclusions != nil {
+				existing.SetCheckExclusions(checkExclusionsFor(exclusions, dbM.ID))
+			}
 
 			// Check for changes (URL, Interval, or RequestConfig)
-			needRestart := existing.GetTargetURL() != dbM.URL || existing.GetInterval() != interval
+			needRestart := existing.GetTargetURL() != dbM.URL || existing.GetInterval() != interval || existing.GetMonitorType() != monitorTypeOrDefault(dbM.Type)
 			if !needRestart && requestConfigChanged(existing.GetRequestConfig(), dbM.RequestConfig) {
 				needRestart = true
 			}
@@ -847,34 +1296,65 @@ func (m *Manager) Sync() {
 		if _, exists := m.monitors[dbM.ID]; !exists {
 			// Start new monitor
 			mon := NewMonitor(dbM.ID, dbM.GroupID, dbM.Name, dbM.URL, interval, m.jobQueue, dbM.CreatedAt, dbM.RequestConfig)
+			mon.SetHistorySize(m.historySize)
 			mon.ApplyConfig(cfg)
 			mon.SetLatencyThreshold(monLatencyThresh)
+			mon.SetCheckExclusions(checkExclusionsFor(exclusions, dbM.ID))
+			mon.SetMonitorType(monitorTypeOrDefault(dbM.Type))
 
 			// Hydrate history from DB
-			checks, err := m.store.GetMonitorChecks(dbM.ID, 50)
-			if err == nil {
-				// Checks are returned DESC (Newest first).
-				// We want to record them in order? RecordResult appends.
-				// So we should iterate from end to start (Oldest to Newest).
-				for i := len(checks) - 1; i >= 0; i-- {
-					c := checks[i]
-					isUp := c.Status == "up" // "up" or "down"
-					isDegraded := isUp && c.Latency > mon.GetLatencyThreshold()
-					mon.RecordResult(isUp, c.Latency, c.Timestamp, c.StatusCode, "", isDegraded)
+			checks, ok := histories[dbM.ID]
+			if !ok {
+				// Not preloaded: restarted after a config change, or the batch load failed
+				checks, _ = m.store.GetMonitorChecks(dbM.ID, m.historySize)
+			}
+			// Checks are returned DESC (Newest first).
+			// We want to record them in order? RecordResult appends.
+			// So we should iterate from end to start (Oldest to Newest).
+			for i := len(checks) - 1; i >= 0; i-- {
+				c := checks[i]
+				isUp := c.Status == "up" // "up" or "down"
+				var degradedReason string
+				if isUp && c.ErrorKind == db.ErrorKindHeaderMismatch {
+					degradedReason = HeaderMismatchReason
 				}
+				isDegraded := isUp && (c.Latency > mon.GetLatencyThreshold() || degradedReason != "")
+				mon.RecordStatus(Status{
+					Timestamp:      c.Timestamp,
+					Latency:        c.Latency,
+					IsUp:           isUp,
+					StatusCode:     c.StatusCode,
+					IsDegraded:     isDegraded,
+					Timing:         c.Timing,
+					DegradedReason: degradedReason,
+				})
 			}
 
 			// Hydrate confirmation state from history
 			mon.HydrateConfirmationState()
 
-			go mon.Start()
 			m.monitors[dbM.ID] = mon
+			if m.standby {
+				// Only the leader schedules checks
+				continue
+			}
+			if mon.IsExternal() {
+				// External monitors are never scheduled; results arrive via IngestExternalResult
+				log.Printf("Registered external monitor: %s", dbM.Name)
+				continue
+			}
+			if mon.IsComposite() {
+				// Composite monitors are never scheduled; results follow their members'
+				log.Printf("Registered composite monitor: %s", dbM.Name)
+				continue
+			}
+			go mon.Start()
 			log.Printf("Scheduled monitor: %s (Interval: %ds)", dbM.Name, intervalSec)
 		}
 	}
 
-	// Reconcile orphaned outages against current hydrated state
-	if activeOutages, err := m.store.GetActiveOutages(); err == nil {
+	// Reconcile orphaned outages against current hydrated state (leader only)
+	if activeOutages, err := m.store.GetActiveOutages(); err == nil && !m.standby {
 		for _, outage := range activeOutages {
 			mon, exists := m.monitors[outage.MonitorID]
 			if !exists {
@@ -888,6 +1368,8 @@ func (m *Manager) Sync() {
 			shouldClose := false
 			if outage.Type == "down" && isUp {
 				shouldClose = true
+			} else if outage.Type == "degraded" && isUp && mon.HasDegradedHysteresis() {
+				shouldClose = !mon.IsConfirmedDegraded()
 			} else if outage.Type == "degraded" && isUp && !lastDegraded {
 				shouldClose = true
 			}
@@ -910,6 +1392,110 @@ func (m *Manager) Sync() {
 			log.Printf("Stopped monitor: %s", id)
 		}
 	}
+
+	m.hydrated.Store(true)
+	close(m.syncDone)
+	m.syncDone = make(chan struct{})
+}
+
+// loadHistories fetches the recent checks of the active monitors Sync is about to
+// build: new ones, or all of them on a standby, which rebuilds every monitor.
+func (m *Manager) loadHistories(dbMonitors []db.Monitor) map[string][]db.CheckResult {
+	var ids []string
+	m.mu.RLock()
+	historySize := m.historySize
+	for _, dbM := range dbMonitors {
+		if _, exists := m.monitors[dbM.ID]; dbM.Active && (m.standby || !exists) {
+			ids = append(ids, dbM.ID)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(ids) == 0 {
+		return nil
+	}
+	histories, err := m.store.GetRecentChecksForMonitors(ids, historySize)
+	if err != nil {
+		// Sync falls back to loading each monitor's history on its own
+		log.Printf("Error loading monitor histories: %v", err)
+		return nil
+	}
+	for _, id := range ids {
+		if _, ok := histories[id]; !ok {
+			histories[id] = nil // Loaded, no checks yet
+		}
+	}
+	return histories
+}
+
+// SetHistorySize sets how many recent checks each monitor keeps in memory, and so how
+// many history points the API can return. Call it before Start; sizes below
+// DefaultHistorySize are raised to it.
+func (m *Manager) SetHistorySize(n int) {
+	if n < DefaultHistorySize {
+		n = DefaultHistorySize
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.historySize = n
+}
+
+// HistorySize returns how many recent checks each monitor keeps in memory.
+func (m *Manager) HistorySize() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.historySize
+}
+
+// Hydrated reports whether the first Sync has completed. Until then monitors have not
+// been loaded, and their live status is unknown.
+func (m *Manager) Hydrated() bool {
+	return m.hydrated.Load()
+}
+
+// SyncNotify returns a channel that is closed when the next Sync completes, i.e. after
+// monitor configuration may have changed. Call it again for later syncs.
+func (m *Manager) SyncNotify() <-chan struct{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.syncDone
+}
+
+// settingsChanged applies settings as soon as they are saved. Notification and
+// diagnostics settings are cached per monitor, so they take a Sync.
+func (m *Manager) settingsChanged(changed map[string]string) {
+	select {
+	case <-m.stopCh:
+		return
+	default:
+	}
+
+	resync := false
+	for key, val := range changed {
+		switch {
+		case key == "latency_threshold":
+			if i, err := strconv.Atoi(val); err == nil {
+				m.SetLatencyThreshold(int64(i))
+			}
+		case key == "data_retention_days":
+			select {
+			case m.retentionWake <- struct{}{}:
+			default:
+			}
+		case key == WatchdogURLKey, key == WatchdogIntervalKey:
+			select {
+			case m.watchdogWake <- struct{}{}:
+			default:
+			}
+		case key == notifications.MaintenanceReminderHoursKey:
+			// Read by the notification service itself
+		case strings.HasPrefix(key, "notification."), strings.HasPrefix(key, "diagnostics."):
+			resync = true
+		}
+	}
+	if resync {
+		m.Sync()
+	}
 }
 
 // loadNotificationConfig reads global notification fatigue settings from the database.
@@ -951,10 +1537,35 @@ func (m *Manager) loadNotificationConfig() MonitorConfig {
 			cfg.RecoveryConfirmationChecks = i
 		}
 	}
+	if val, err := m.store.GetSetting("notification.degraded_window_checks"); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 0 {
+			cfg.DegradedWindowChecks = i
+		}
+	}
+	if val, err := m.store.GetSetting("notification.degraded_threshold_checks"); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 0 {
+			cfg.DegradedThresholdChecks = i
+		}
+	}
+	if val, err := m.store.GetSetting("notification.anomaly_deviations"); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 0 {
+			cfg.AnomalyDeviations = i
+		}
+	}
 
 	return cfg
 }
 
+// loadTracerouteAfterChecks reads how many consecutive failures trigger a path diagnostic.
+func (m *Manager) loadTracerouteAfterChecks() int {
+	if val, err := m.store.GetSetting("diagnostics.traceroute_after_checks"); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 0 {
+			return i
+		}
+	}
+	return 0
+}
+
 // loadEventFilter reads per-event-type notification toggles from the database.
 func (m *Manager) loadEventFilter() NotificationEventFilter {
 	filter := NotificationEventFilter{
@@ -964,6 +1575,7 @@ func (m *Manager) loadEventFilter() NotificationEventFilter {
 		FlappingEnabled:    true,
 		StabilizedEnabled:  true,
 		SSLExpiringEnabled: true,
+		AnomalyEnabled:     true,
 	}
 
 	if val, err := m.store.GetSetting("notification.event.down.enabled"); err == nil {
@@ -984,6 +1596,9 @@ func (m *Manager) loadEventFilter() NotificationEventFilter {
 	if val, err := m.store.GetSetting("notification.event.ssl_expiring.enabled"); err == nil {
 		filter.SSLExpiringEnabled = val != "false"
 	}
+	if val, err := m.store.GetSetting("notification.event.anomaly.enabled"); err == nil {
+		filter.AnomalyEnabled = val != "false"
+	}
 
 	return filter
 }
@@ -1030,15 +1645,57 @@ func (m *Manager) shouldDigest(eventType string) bool {
 
 // enqueueOrDigest either sends a notification immediately or queues it for digest.
 func (m *Manager) enqueueOrDigest(event notifications.NotificationEvent) {
+	// Silences mute a monitor's notifications, digest included, but not its status
+	if m.IsMonitorSilenced(event.MonitorID, time.Now()) {
+		log.Printf("Notification %s for %s muted by a silence", event.Type, event.MonitorID)
+		return
+	}
 	if m.shouldDigest(string(event.Type)) {
 		if err := m.store.InsertDigestEvent(event.MonitorID, event.MonitorName, event.MonitorURL, string(event.Type), event.Message, event.Time); err != nil {
 			log.Printf("Failed to queue digest event: %v", err)
 		}
+		// Watchers get their monitor's events as they happen
+		m.notifier.EnqueueForWatchers(event)
 		return
 	}
 	m.notifier.Enqueue(event)
 }
 
+// Notify sends a non-monitor notification (e.g. cost budget alerts) through the same
+// channels and digest routing as monitor events.
+func (m *Manager) Notify(event notifications.NotificationEvent) {
+	m.enqueueOrDigest(event)
+}
+
+// NotifyIncident tells the channels of the status pages showing a public incident that
+// it opened, changed or resolved. These never wait for the digest: they are for the
+// pages' audiences, not the on-call team.
+func (m *Manager) NotifyIncident(eventType notifications.EventType, inc db.Incident, message, baseURL string) {
+	m.notifier.EnqueueIncident(eventType, inc, message, baseURL)
+}
+
+// monitorTypeOrDefault maps an empty monitor type to http.
+func monitorTypeOrDefault(t string) string {
+	if t == "" {
+		return db.MonitorTypeHTTP
+	}
+	return t
+}
+
+// checkExclusionsFor returns the monitor's check exclusion windows, or nil if it has none.
+func checkExclusionsFor(exclusions map[string]db.MonitorCheckExclusions, monitorID string) *db.MonitorCheckExclusions {
+	if e, ok := exclusions[monitorID]; ok {
+		return &e
+	}
+	return nil
+}
+
+// IngestExternalResult feeds a result for an external monitor into the result
+// pipeline, so outages, events and notifications behave as for scheduled checks.
+func (m *Manager) IngestExternalResult(monitorID string, isUp bool, summary string, ts time.Time) error {
+	return m.IngestExternalResults([]ExternalResult{{MonitorID: monitorID, IsUp: isUp, Summary: summary, Timestamp: ts}}, false)
+}
+
 // GetMonitor returns a specific monitor instance
 func (m *Manager) GetMonitor(id string) *Monitor {
 	m.mu.RLock()
@@ -1046,6 +1703,28 @@ func (m *Manager) GetMonitor(id string) *Monitor {
 	return m.monitors[id]
 }
 
+// MonitorStatus reports a monitor's live status as shown on status pages: up, degraded
+// or down, or empty when it isn't running or hasn't been checked yet.
+func (m *Manager) MonitorStatus(id string) string {
+	mon := m.GetMonitor(id)
+	if mon == nil {
+		return ""
+	}
+	history := mon.GetHistory()
+	if len(history) == 0 {
+		return ""
+	}
+	last := history[len(history)-1]
+	switch {
+	case !last.IsUp:
+		return "down"
+	case last.Latency > mon.GetLatencyThreshold() || last.DegradedReason != "":
+		return "degraded"
+	default:
+		return "up"
+	}
+}
+
 // RemoveMonitor explicitly stops and removes a monitor.
 // This is useful for immediate cleanup after deletion.
 func (m *Manager) RemoveMonitor(id string) {
@@ -1085,32 +1764,6 @@ func (m *Manager) GetAll() map[string]*Monitor {
 	return res
 }
 
-// IsGroupInMaintenance checks if a specific group is currently in an active maintenance window
-func (m *Manager) IsGroupInMaintenance(groupID string) bool {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	now := time.Now().UTC()
-	for _, w := range m.maintenanceWindows {
-		// Check time window
-		if now.After(w.StartTime) && (w.EndTime == nil || now.Before(*w.EndTime)) {
-			// Check affected groups
-			if w.AffectedGroups != "" {
-				var groups []string
-				// Optimization: could cache unmarshal or simple string contains if confident
-				if err := json.Unmarshal([]byte(w.AffectedGroups), &groups); err == nil {
-					for _, g := range groups {
-						if g == groupID {
-							return true
-						}
-					}
-				}
-			}
-		}
-	}
-	return false
-}
-
 func (m *Manager) digestWorker() {
 	m.wg.Add(1)
 	defer m.wg.Done()
@@ -1126,7 +1779,7 @@ func (m *Manager) digestWorker() {
 			return
 		case <-ticker.C:
 			m.mu.RLock()
-			enabled := m.digestEnabled
+			enabled := m.digestEnabled && !m.standby
 			digestTime := m.digestTime
 			loc := m.notificationTimezone
 			m.mu.RUnlock()
@@ -1161,6 +1814,9 @@ func (m *Manager) retentionWorker() {
 	defer m.wg.Done()
 
 	prune := func() {
+		if !m.IsLeader() {
+			return
+		}
 		days := 365 // Default
 		if val, err := m.store.GetSetting("data_retention_days"); err == nil {
 			if i, err := strconv.Atoi(val); err == nil && i > 0 {
@@ -1170,6 +1826,33 @@ func (m *Manager) retentionWorker() {
 		if err := m.store.PruneMonitorChecks(days); err != nil {
 			log.Printf("Retention error: %v", err)
 		}
+
+		now := time.Now()
+		if n, err := m.store.PruneResolvedOutages(now.AddDate(0, 0, -days)); err != nil {
+			log.Printf("Retention: failed to prune outages: %v", err)
+		} else if n > 0 {
+			log.Printf("Retention: pruned %d resolved outages", n)
+		}
+		if n, err := m.store.PruneExpiredSilences(now.AddDate(0, 0, -days)); err != nil {
+			log.Printf("Retention: failed to prune silences: %v", err)
+		} else if n > 0 {
+			log.Printf("Retention: pruned %d expired silences", n)
+		}
+		if n, err := m.store.PruneMonitorEvents(maxEventsPerMonitor); err != nil {
+			log.Printf("Retention: failed to prune events: %v", err)
+		} else if n > 0 {
+			log.Printf("Retention: pruned %d monitor events", n)
+		}
+		if n, err := m.store.DeleteExpiredSessions(now); err != nil {
+			log.Printf("Retention: failed to delete expired sessions: %v", err)
+		} else if n > 0 {
+			log.Printf("Retention: deleted %d expired sessions", n)
+		}
+		if n, err := m.store.PruneAgentResultKeys(now.Add(-db.AgentResultKeyRetention)); err != nil {
+			log.Printf("Retention: failed to prune agent result keys: %v", err)
+		} else if n > 0 {
+			log.Printf("Retention: pruned %d agent result keys", n)
+		}
 	}
 
 	// Run immediately
@@ -1184,6 +1867,8 @@ func (m *Manager) retentionWorker() {
 			return
 		case <-ticker.C:
 			prune()
+		case <-m.retentionWake:
+			prune()
 		}
 	}
 }
@@ -1,6 +1,7 @@
 package uptime
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -74,6 +75,42 @@ func TestManager_Sync(t *testing.T) {
 
 }
 
+func TestManager_Hydrated(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	m := NewManager(store)
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m-hyd", GroupID: "g-default", Name: "Batch", Type: db.MonitorTypeExternal, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := store.BatchInsertChecks([]db.CheckResult{
+		{MonitorID: "m-hyd", Status: "up", Latency: 10, Timestamp: now.Add(-2 * time.Minute)},
+		{MonitorID: "m-hyd", Status: "down", Timestamp: now.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	if m.Hydrated() {
+		t.Fatal("Expected the manager to be warming up before the first sync")
+	}
+	m.Sync()
+	if !m.Hydrated() {
+		t.Fatal("Expected the manager to be hydrated after the first sync")
+	}
+
+	mon := m.GetMonitor("m-hyd")
+	if mon == nil {
+		t.Fatal("Monitor should be loaded")
+	}
+	history := mon.GetHistory()
+	if len(history) != 2 || history[0].IsUp != true || history[1].IsUp != false {
+		t.Errorf("Expected the persisted checks oldest first, got %+v", history)
+	}
+}
+
 func TestManager_Stop(t *testing.T) {
 	m, s := newTestManager(t)
 
@@ -100,6 +137,53 @@ func TestManager_Stop(t *testing.T) {
 	// But it closes channels.
 }
 
+func TestManager_Shutdown(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:shutdown_%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+
+	// The check is still running when shutdown starts
+	started := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	m := NewManager(store)
+	m.Start()
+	if err := store.CreateMonitor(db.Monitor{ID: "m-shutdown", GroupID: "g-default", Name: "Shutdown", URL: ts.URL, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m.Sync()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the check to start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	checks, err := store.GetMonitorChecks("m-shutdown", 10)
+	if err != nil {
+		t.Fatalf("GetMonitorChecks failed: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Status != "up" {
+		t.Errorf("Expected the running check to be saved, got %+v", checks)
+	}
+}
+
 func TestManager_OutageLogic(t *testing.T) {
 	m, s := newTestManager(t)
 	if err := s.CreateGroup(db.Group{ID: "g-test", Name: "Test Group"}); err != nil {
@@ -1784,6 +1868,59 @@ func TestManager_NotifFatigue_ConfigUpdatePropagates(t *testing.T) {
 	}
 }
 
+func TestManager_SettingsChangeApplied(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	m := NewManager(store)
+
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-settings", GroupID: "g-default", Name: "Settings Test",
+		URL: "http://example.com", Active: true, Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m.Sync()
+
+	// No explicit Sync: saving is enough
+	if err := store.SetSettings(map[string]string{
+		"notification.confirmation_threshold": "1",
+		"notification.digest.enabled":         "true",
+		"latency_threshold":                   "250",
+		"data_retention_days":                 "30",
+	}); err != nil {
+		t.Fatalf("SetSettings failed: %v", err)
+	}
+
+	if got := m.GetLatencyThreshold(); got != 250 {
+		t.Errorf("Expected latency threshold 250, got %d", got)
+	}
+	if mon := m.GetMonitor("m-settings"); mon == nil || !mon.IncrementDown() {
+		t.Error("Expected confirmation after 1 failure (threshold updated to 1)")
+	}
+	m.mu.RLock()
+	digest := m.digestEnabled
+	m.mu.RUnlock()
+	if !digest {
+		t.Error("Expected the digest to be enabled")
+	}
+	select {
+	case <-m.retentionWake:
+	default:
+		t.Error("Expected the retention worker to be woken")
+	}
+
+	// A stopped manager ignores further changes
+	m.Stop()
+	if err := store.SetSetting("latency_threshold", "500"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	if got := m.GetLatencyThreshold(); got != 250 {
+		t.Errorf("Expected a stopped manager to keep 250, got %d", got)
+	}
+}
+
 // ============== REQUEST CONFIG TESTS ==============
 
 func TestIsAcceptedStatus(t *testing.T) {
@@ -2324,3 +2461,150 @@ func TestWorker_CustomTimeout(t *testing.T) {
 		t.Error("Expected short timeout monitor DOWN (1s timeout < 3s sleep)")
 	}
 }
+
+func TestManager_AgentReportingProblem(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:agentreport%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := NewManager(store)
+
+	if err := store.CreateAgent(db.Agent{ID: "a-1", Name: "prod", URL: "http://agent", Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+	mon, err := store.GetMonitor("a-1")
+	if err != nil || mon.Type != db.MonitorTypeAgent || mon.URL != "http://agent/api/health" {
+		t.Fatalf("Expected implicit agent monitor, got %+v (err %v)", mon, err)
+	}
+
+	now := time.Now()
+	if problem := m.agentReportingProblem("a-1", now); problem != "" {
+		t.Errorf("Expected freshly registered agent to be healthy, got %q", problem)
+	}
+
+	_ = store.RecordAgentPoll("a-1", now, "connection refused")
+	if problem := m.agentReportingProblem("a-1", now); problem == "" {
+		t.Error("Expected failed poll to be reported")
+	}
+
+	_ = store.RecordAgentPoll("a-1", now.Add(-10*time.Minute), "")
+	if problem := m.agentReportingProblem("a-1", now); problem == "" {
+		t.Error("Expected stale agent to be reported")
+	}
+
+	_ = store.RecordAgentPoll("a-1", now, "")
+	if problem := m.agentReportingProblem("a-1", now); problem != "" {
+		t.Errorf("Expected healthy agent, got %q", problem)
+	}
+}
+
+func TestManager_AgentMonitorDownWhenNotReporting(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:agentmon%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+
+	health := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer health.Close()
+
+	if err := store.CreateAgent(db.Agent{ID: "a-1", Name: "prod", URL: health.URL, Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateAgent failed: %v", err)
+	}
+	_ = store.RecordAgentPoll("a-1", time.Now(), "unexpected status 500")
+
+	m := NewManager(store)
+	m.Start()
+	defer m.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if mon := m.GetMonitor("a-1"); mon != nil {
+			if isUp, _, hasHistory, _ := mon.GetLastStatus(); hasHistory {
+				if isUp {
+					t.Fatal("Expected agent monitor to be down while the agent is not reporting")
+				}
+				return
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("Agent monitor was never checked")
+}
+
+func TestManager_DegradedHysteresis_OverrideAndHydrate(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	_ = store.SetSetting("latency_threshold", "100")
+	_ = store.SetSetting("notification.degraded_window_checks", "5")
+	_ = store.SetSetting("notification.degraded_threshold_checks", "3")
+
+	disabled := 0
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-hyst", GroupID: "g-default", Name: "Hysteresis", URL: "http://example.com", Active: true, Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-legacy", GroupID: "g-default", Name: "Legacy", URL: "http://example.org", Active: true, Interval: 60,
+		DegradedWindowChecks: &disabled,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	// 3 slow checks out of the last 4
+	now := time.Now()
+	var checks []db.CheckResult
+	for i, latency := range []int64{500, 20, 500, 500} {
+		checks = append(checks, db.CheckResult{MonitorID: "m-hyst", Status: "up", Latency: latency, Timestamp: now.Add(time.Duration(i-4) * time.Minute), StatusCode: 200})
+	}
+	if err := store.BatchInsertChecks(checks); err != nil {
+		t.Fatalf("BatchInsertChecks failed: %v", err)
+	}
+
+	m := NewManager(store)
+	m.Sync()
+
+	mon := m.GetMonitor("m-hyst")
+	if mon == nil || !mon.HasDegradedHysteresis() {
+		t.Fatal("Expected global hysteresis settings to apply")
+	}
+	if !mon.IsConfirmedDegraded() {
+		t.Error("Expected hydration to restore the confirmed degraded state")
+	}
+	if legacy := m.GetMonitor("m-legacy"); legacy == nil || legacy.HasDegradedHysteresis() {
+		t.Error("Expected per-monitor window 0 to disable hysteresis")
+	}
+}
+
+func TestManager_DownBackoffOverride(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	backoff := 600
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-backoff", GroupID: "g-default", Name: "Backoff", URL: "http://example.com", Active: true, Interval: 60,
+		DownBackoffInterval: &backoff,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	m := NewManager(store)
+	m.Sync()
+	defer m.Stop()
+
+	mon := m.GetMonitor("m-backoff")
+	if mon == nil {
+		t.Fatal("Monitor not running")
+	}
+	mon.mu.RLock()
+	defer mon.mu.RUnlock()
+	if mon.backoffInterval != 10*time.Minute || mon.backoffAfter != DefaultDownBackoffAfterChecks {
+		t.Errorf("Expected 10m backoff after %d failures, got %v after %d", DefaultDownBackoffAfterChecks, mon.backoffInterval, mon.backoffAfter)
+	}
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type Status struct {
-	Timestamp  time.Time `json:"timestamp"`
-	IsUp       bool      `json:"isUp"`
-	Latency    int64     `json:"latencyMs"` // milliseconds
-	StatusCode int       `json:"statusCode"`
-	Error      string    `json:"error,omitempty"`
-	IsDegraded bool      `json:"isDegraded"`
+	Timestamp      time.Time       `json:"timestamp"`
+	IsUp           bool            `json:"isUp"`
+	Latency        int64           `json:"latencyMs"` // milliseconds
+	StatusCode     int             `json:"statusCode"`
+	Error          string          `json:"error,omitempty"`
+	IsDegraded     bool            `json:"isDegraded"`
+	DegradedReason string          `json:"degradedReason,omitempty"` // Set when degraded for a reason other than latency
+	Timing         *db.CheckTiming `json:"timing,omitempty"`         // DNS/connect/TLS/TTFB/download breakdown of HTTP checks
 }
 
 type Monitor struct {
@@ -29,6 +31,8 @@ type Monitor struct {
 	stopOnce  sync.Once
 	jobQueue      chan<- Job
 	requestConfig *db.RequestConfig
+	monitorType   string // db.MonitorTypeHTTP, db.MonitorTypeExternal, db.MonitorTypeAgent or db.MonitorTypeComposite
+	historySize   int    // recent checks kept in history
 
 	// Notification fatigue state (protected by mu)
 	confirmationThreshold int   // effective threshold (resolved from per-monitor or global)
@@ -39,6 +43,10 @@ type Monitor struct {
 	consecutiveDegCount  int  // consecutive degraded checks
 	confirmedDown        bool // threshold met for down
 	confirmedDegraded    bool // threshold met for degraded
+	tracerouteStarted    bool // path diagnostic already run for the current down streak
+	remediationStarted   bool // remediation action already considered for the current down streak
+	affectedBy           string // ID of the down dependency this monitor's failures are attributed to
+	ingestWatermark      time.Time // newest external result fed into the result pipeline
 
 	lastNotifiedAt map[string]time.Time // per-event-type cooldown tracking
 	isFlapping     bool                 // current flap state
@@ -51,6 +59,29 @@ type Monitor struct {
 	// Recovery confirmation
 	recoveryConfirmationChecks int
 	consecutiveUpCount         int
+
+	// Degraded hysteresis: enter/leave degraded when N of the last M up checks agree (0 = disabled)
+	degradedThresholdChecks int
+	degradedWindowChecks    int
+	degradedSamples         []bool // latency samples of recent up checks, true = above threshold
+
+	// Latency anomaly detection: flag latency this many deviations above the learned baseline (0 = disabled)
+	anomalyDeviations int
+	latencyBaseline   latencyBaseline
+
+	// Down backoff: check every backoffInterval instead of interval after backoffAfter failures (0 = disabled)
+	backoffInterval time.Duration
+	backoffAfter    int
+	failureStreak   int       // consecutive failed checks, reset by the first success
+	lastScheduledAt time.Time // when the last check was queued
+
+	// Check exclusion windows: regular ticks inside one are skipped, e.g. during a nightly batch job
+	exclusions   []db.CheckExclusionWindow
+	exclusionLoc *time.Location
+
+	// Job queue accounting
+	jobStats         JobStats
+	consecutiveDrops int // ticks dropped in a row because the queue was full
 }
 
 // NotificationEventFilter holds per-event-type notification toggle state.
@@ -61,6 +92,7 @@ type NotificationEventFilter struct {
 	FlappingEnabled   bool
 	StabilizedEnabled bool
 	SSLExpiringEnabled bool
+	AnomalyEnabled     bool
 }
 
 // IsEnabled checks whether notifications for the given event type are enabled.
@@ -78,6 +110,8 @@ func (f *NotificationEventFilter) IsEnabled(eventType string) bool {
 		return f.StabilizedEnabled
 	case "ssl_expiring":
 		return f.SSLExpiringEnabled
+	case "anomaly":
+		return f.AnomalyEnabled
 	default:
 		return true
 	}
@@ -91,8 +125,23 @@ type MonitorConfig struct {
 	FlapWindowChecks           int
 	FlapThresholdPercent       int
 	RecoveryConfirmationChecks int
+	DegradedThresholdChecks    int           // N: slow (or normal) checks needed to enter (or leave) degraded
+	DegradedWindowChecks       int           // M: number of recent checks considered; 0 disables hysteresis
+	DownBackoffInterval        time.Duration // Slower interval used while down; 0 disables backoff
+	DownBackoffAfterChecks     int           // Consecutive failures before backing off
+	AnomalyDeviations          int           // Deviations above the latency baseline that count as anomalous; 0 disables detection
 }
 
+// DefaultDownBackoffAfterChecks is used when a backoff interval is set without a trigger.
+const DefaultDownBackoffAfterChecks = 5
+
+// DefaultHistorySize is how many recent checks a monitor keeps in memory, for heartbeat
+// bars, flap detection and the degraded hysteresis window. It is also the minimum.
+const DefaultHistorySize = 50
+
+// MaxDegradedWindowChecks bounds the hysteresis window to the in-memory history size.
+const MaxDegradedWindowChecks = DefaultHistorySize
+
 func NewMonitor(id, groupID, name, url string, interval time.Duration, jobQueue chan<- Job, createdAt time.Time, reqConfig *db.RequestConfig) *Monitor {
 	if createdAt.IsZero() {
 		createdAt = time.Now()
@@ -104,7 +153,8 @@ func NewMonitor(id, groupID, name, url string, interval time.Duration, jobQueue
 		url:                   url,
 		interval:              interval,
 		createdAt:             createdAt,
-		history:               make([]Status, 0, 50), // Keep last 50 in memory
+		history:               make([]Status, 0, DefaultHistorySize),
+		historySize:           DefaultHistorySize,
 		stopCh:                make(chan struct{}),
 		jobQueue:              jobQueue,
 		requestConfig:         reqConfig,
@@ -130,6 +180,37 @@ func (m *Monitor) ApplyConfig(cfg MonitorConfig) {
 	if cfg.RecoveryConfirmationChecks >= 1 {
 		m.recoveryConfirmationChecks = cfg.RecoveryConfirmationChecks
 	}
+	m.degradedWindowChecks, m.degradedThresholdChecks = normalizeHysteresis(cfg.DegradedWindowChecks, cfg.DegradedThresholdChecks)
+	m.backoffInterval = cfg.DownBackoffInterval
+	m.backoffAfter = cfg.DownBackoffAfterChecks
+	if m.backoffAfter <= 0 {
+		m.backoffAfter = DefaultDownBackoffAfterChecks
+	}
+	if cfg.AnomalyDeviations != m.anomalyDeviations {
+		m.anomalyDeviations = cfg.AnomalyDeviations
+		m.latencyBaseline.resetStreaks()
+	}
+	if len(m.degradedSamples) > m.degradedWindowChecks {
+		m.degradedSamples = m.degradedSamples[len(m.degradedSamples)-m.degradedWindowChecks:]
+	}
+}
+
+// normalizeHysteresis clamps the window to the history size and the threshold to the window.
+// A missing threshold defaults to a majority of the window.
+func normalizeHysteresis(window, threshold int) (int, int) {
+	if window <= 0 {
+		return 0, 0
+	}
+	if window > MaxDegradedWindowChecks {
+		window = MaxDegradedWindowChecks
+	}
+	if threshold <= 0 {
+		threshold = window/2 + 1
+	}
+	if threshold > window {
+		threshold = window
+	}
+	return window, threshold
 }
 
 // alignDelay computes the duration until the next tick aligned to createdAt.
@@ -188,21 +269,116 @@ func (m *Monitor) Stop() {
 }
 
 func (m *Monitor) schedule() {
+	now := time.Now()
+	if m.InExclusion(now) {
+		return // Skip this tick; the endpoint is expected to be offline
+	}
+	if m.InBackoff(now) {
+		return // Skip this tick; the endpoint has been down for a while
+	}
+	m.enqueue()
+}
+
+// SetCheckExclusions replaces the windows in which regular checks are skipped; nil
+// removes them.
+func (m *Monitor) SetCheckExclusions(e *db.MonitorCheckExclusions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e == nil || len(e.Windows) == 0 {
+		m.exclusions, m.exclusionLoc = nil, nil
+		return
+	}
+	m.exclusions, m.exclusionLoc = e.Windows, e.Location()
+}
+
+// InExclusion reports whether a regular tick at now falls in a check exclusion window.
+// Manual checks still run.
+func (m *Monitor) InExclusion(now time.Time) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.exclusions) == 0 {
+		return false
+	}
+	local := now.In(m.exclusionLoc)
+	for _, w := range m.exclusions {
+		if w.Covers(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// InBackoff reports whether a regular tick at now should be skipped because the monitor
+// has failed backoffAfter checks in a row and the backoff interval has not yet elapsed.
+// The first successful check resets the failure streak and restores the normal interval.
+func (m *Monitor) InBackoff(now time.Time) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.backoffInterval <= m.interval || m.failureStreak < m.backoffAfter || m.lastScheduledAt.IsZero() {
+		return false
+	}
+	// Half an interval of slack so ticker jitter does not push the check a full tick later
+	return now.Sub(m.lastScheduledAt)+m.interval/2 < m.backoffInterval
+}
+
+// CheckNow queues an immediate check outside the regular interval. It returns
+// false when the job queue is full.
+func (m *Monitor) CheckNow() bool {
+	return m.enqueue()
+}
+
+func (m *Monitor) enqueue() (queued bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			// Ignore panic on closed channel
-			_ = r
+			queued = false
 		}
 	}()
 	m.mu.RLock()
 	cfg := m.requestConfig
+	monitorType := m.monitorType
 	m.mu.RUnlock()
+	now := time.Now()
 	select {
-	case m.jobQueue <- Job{MonitorID: m.id, URL: m.url, RequestConfig: cfg}:
+	case m.jobQueue <- Job{MonitorID: m.id, URL: m.url, MonitorType: monitorType, RequestConfig: cfg, ScheduledAt: now}:
 		// Scheduled
+		m.mu.Lock()
+		m.lastScheduledAt = now
+		m.mu.Unlock()
+		m.recordEnqueue(true)
+		return true
 	default:
 		// Queue full, skip this tick to avoid blocking scheduler
+		m.recordEnqueue(false)
+		return false
+	}
+}
+
+// SetMonitorType sets the monitor type (http, external or agent).
+func (m *Monitor) SetMonitorType(t string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.monitorType = t
+}
+
+// GetMonitorType returns the monitor type, defaulting to http.
+func (m *Monitor) GetMonitorType() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.monitorType == "" {
+		return db.MonitorTypeHTTP
 	}
+	return m.monitorType
+}
+
+// IsExternal reports whether the monitor is driven by ingested alerts rather than scheduled checks.
+func (m *Monitor) IsExternal() bool {
+	return m.GetMonitorType() == db.MonitorTypeExternal
+}
+
+// IsComposite reports whether the monitor's status is computed from member monitors.
+func (m *Monitor) IsComposite() bool {
+	return m.GetMonitorType() == db.MonitorTypeComposite
 }
 
 // GetRequestConfig returns the monitor's request configuration.
@@ -214,25 +390,48 @@ func (m *Monitor) GetRequestConfig() *db.RequestConfig {
 
 // RecordResult is called by the ResultProcessor to update in-memory history
 func (m *Monitor) RecordResult(isUp bool, latency int64, ts time.Time, statusCode int, errStr string, isDegraded bool) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	status := Status{
+	m.RecordStatus(Status{
 		Timestamp:  ts,
 		Latency:    latency,
 		IsUp:       isUp,
 		StatusCode: statusCode,
 		Error:      errStr,
 		IsDegraded: isDegraded,
+	})
+}
+
+// RecordStatus appends a check to the monitor's history.
+func (m *Monitor) RecordStatus(status Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if status.IsUp {
+		m.failureStreak = 0
+	} else {
+		m.failureStreak++
 	}
 
-	// Keep last 50 checks
-	if len(m.history) >= 50 {
+	// Keep the last historySize checks
+	if len(m.history) >= m.historySize {
 		m.history = m.history[1:]
 	}
 	m.history = append(m.history, status)
 }
 
+// SetHistorySize changes how many recent checks the monitor keeps in memory. Sizes
+// below DefaultHistorySize are raised to it.
+func (m *Monitor) SetHistorySize(n int) {
+	if n < DefaultHistorySize {
+		n = DefaultHistorySize
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.historySize = n
+	if len(m.history) > n {
+		m.history = m.history[len(m.history)-n:]
+	}
+}
+
 func (m *Monitor) GetHistory() []Status {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -305,12 +504,126 @@ func (m *Monitor) ResetDown() bool {
 	wasConfirmed := m.confirmedDown
 	m.consecutiveDownCount = 0
 	m.confirmedDown = false
+	m.tracerouteStarted = false
+	m.remediationStarted = false
 	if wasConfirmed {
 		delete(m.lastNotifiedAt, "down")
 	}
 	return wasConfirmed
 }
 
+// ShouldTraceroute reports whether a path diagnostic should run now: the monitor is
+// confirmed down, has failed at least afterChecks checks in a row, and no trace has
+// run for this down streak yet. It marks the trace as started when returning true.
+func (m *Monitor) ShouldTraceroute(afterChecks int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if afterChecks <= 0 || m.tracerouteStarted || !m.confirmedDown || m.consecutiveDownCount < afterChecks {
+		return false
+	}
+	m.tracerouteStarted = true
+	return true
+}
+
+// retryTraceroute lets ShouldTraceroute return true again in this down streak, after
+// the trace could not start.
+func (m *Monitor) retryTraceroute() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracerouteStarted = false
+}
+
+// ShouldRemediate reports whether the remediation action should be considered now: the
+// monitor is confirmed down, has failed at least afterChecks checks in a row, and the
+// action wasn't considered for this down streak yet. It marks it as considered when
+// returning true.
+func (m *Monitor) ShouldRemediate(afterChecks int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.remediationStarted || !m.confirmedDown || m.consecutiveDownCount < afterChecks {
+		return false
+	}
+	m.remediationStarted = true
+	return true
+}
+
+// AdvanceIngestWatermark reports whether an external result taken at ts is newer than
+// every result already fed into the result pipeline or recorded in history, and if so
+// makes it the newest. Older results can no longer drive state transitions.
+func (m *Monitor) AdvanceIngestWatermark(ts time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !ts.After(m.ingestMarkLocked()) {
+		return false
+	}
+	m.ingestWatermark = ts
+	return true
+}
+
+// IngestWatermark returns the time of the newest result fed into the result pipeline or
+// recorded in history. Only newer external results can drive state transitions.
+func (m *Monitor) IngestWatermark() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ingestMarkLocked()
+}
+
+func (m *Monitor) ingestMarkLocked() time.Time {
+	mark := m.ingestWatermark
+	if n := len(m.history); n > 0 && m.history[n-1].Timestamp.After(mark) {
+		mark = m.history[n-1].Timestamp
+	}
+	return mark
+}
+
+// GetConfirmationThreshold returns how many consecutive failed checks confirm an outage.
+func (m *Monitor) GetConfirmationThreshold() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.confirmationThreshold
+}
+
+// HasDegradedHysteresis reports whether degraded transitions use the N-of-M window
+// instead of consecutive confirmation.
+func (m *Monitor) HasDegradedHysteresis() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.degradedWindowChecks > 0
+}
+
+// RecordDegradedSample adds an up check to the hysteresis window. The monitor enters degraded
+// once N of the last M checks are slow, and leaves it once N are normal again.
+func (m *Monitor) RecordDegradedSample(isDegraded bool) (entered, left bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.degradedWindowChecks <= 0 {
+		return false, false
+	}
+	m.degradedSamples = append(m.degradedSamples, isDegraded)
+	if len(m.degradedSamples) > m.degradedWindowChecks {
+		m.degradedSamples = m.degradedSamples[len(m.degradedSamples)-m.degradedWindowChecks:]
+	}
+
+	slow := 0
+	for _, s := range m.degradedSamples {
+		if s {
+			slow++
+		}
+	}
+	normal := len(m.degradedSamples) - slow
+
+	if !m.confirmedDegraded && slow >= m.degradedThresholdChecks {
+		m.confirmedDegraded = true
+		return true, false
+	}
+	if m.confirmedDegraded && slow < m.degradedThresholdChecks && normal >= m.degradedThresholdChecks {
+		m.confirmedDegraded = false
+		delete(m.lastNotifiedAt, "degraded")
+		return false, true
+	}
+	return false, false
+}
+
 // ResetDegraded resets the consecutive degraded counter and confirmed state. Returns
 // true if the monitor was previously confirmed degraded.
 // Also clears the "degraded" cooldown so a new degradation will always notify.
@@ -320,6 +633,7 @@ func (m *Monitor) ResetDegraded() bool {
 	wasConfirmed := m.confirmedDegraded
 	m.consecutiveDegCount = 0
 	m.confirmedDegraded = false
+	m.degradedSamples = nil
 	if wasConfirmed {
 		delete(m.lastNotifiedAt, "degraded")
 	}
@@ -333,6 +647,23 @@ func (m *Monitor) IsConfirmedDown() bool {
 	return m.confirmedDown
 }
 
+// AffectedBy returns the ID of the dependency blamed for this monitor being down, or "".
+func (m *Monitor) AffectedBy() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.affectedBy
+}
+
+// SetAffectedBy attributes the monitor's failures to a down dependency ("" clears it).
+// Returns the previous value.
+func (m *Monitor) SetAffectedBy(dependencyID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev := m.affectedBy
+	m.affectedBy = dependencyID
+	return prev
+}
+
 // IsConfirmedDegraded returns whether the monitor has met the degraded confirmation threshold.
 func (m *Monitor) IsConfirmedDegraded() bool {
 	m.mu.RLock()
@@ -499,4 +830,21 @@ func (m *Monitor) HydrateConfirmationState() {
 
 	m.consecutiveDegCount = degCount
 	m.confirmedDegraded = degCount >= m.confirmationThreshold
+
+	if m.degradedWindowChecks > 0 {
+		// Refill the hysteresis window with the up checks since the last failure
+		m.degradedSamples = nil
+		slow := 0
+		for i := len(m.history) - 1; i >= 0 && len(m.degradedSamples) < m.degradedWindowChecks; i-- {
+			s := m.history[i]
+			if !s.IsUp {
+				break
+			}
+			m.degradedSamples = append([]bool{s.IsDegraded}, m.degradedSamples...)
+			if s.IsDegraded {
+				slow++
+			}
+		}
+		m.confirmedDegraded = downCount == 0 && slow >= m.degradedThresholdChecks
+	}
 }
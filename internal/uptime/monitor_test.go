@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/projecthelena/warden/internal/db"
 )
 
 func TestMonitor_RecordResult(t *testing.T) {
@@ -36,6 +38,25 @@ func TestMonitor_RecordResult(t *testing.T) {
 	}
 }
 
+func TestMonitor_SetHistorySize(t *testing.T) {
+	m := NewMonitor("m1", "g1", "Test Monitor", "http://example.com", 60*time.Second, make(chan Job, 1), time.Now(), nil)
+	m.SetHistorySize(120)
+	for i := 0; i < 130; i++ {
+		m.RecordResult(true, int64(i), time.Now(), 200, "", false)
+	}
+	history := m.GetHistory()
+	if len(history) != 120 || history[0].Latency != 10 {
+		t.Fatalf("Expected the last 120 checks, got %d starting at %d", len(history), history[0].Latency)
+	}
+
+	// Shrinking keeps the newest checks; sizes below the default are raised to it
+	m.SetHistorySize(10)
+	history = m.GetHistory()
+	if len(history) != DefaultHistorySize || history[len(history)-1].Latency != 129 {
+		t.Errorf("Expected the newest %d checks, got %d", DefaultHistorySize, len(history))
+	}
+}
+
 func TestMonitor_GetLastStatus(t *testing.T) {
 	jobQueue := make(chan Job, 1)
 	m := NewMonitor("m1", "g1", "Test Monitor", "http://example.com", 60*time.Second, jobQueue, time.Now(), nil)
@@ -880,3 +901,124 @@ func TestMonitor_ApplyConfig(t *testing.T) {
 		// If we get here without race detector panic, test passes
 	})
 }
+
+func TestMonitor_DegradedHysteresis(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1, DegradedWindowChecks: 5, DegradedThresholdChecks: 3})
+	if !m.HasDegradedHysteresis() {
+		t.Fatal("Expected hysteresis to be enabled")
+	}
+
+	// A single slow check no longer flips the monitor
+	steps := []struct {
+		slow          bool
+		entered, left bool
+	}{
+		{true, false, false},
+		{false, false, false},
+		{true, false, false},
+		{true, true, false}, // 3 of the last 4 slow
+		{false, false, false},
+		{false, false, true}, // 3 of the last 5 normal
+		{false, false, false},
+	}
+	for i, step := range steps {
+		entered, left := m.RecordDegradedSample(step.slow)
+		if entered != step.entered || left != step.left {
+			t.Fatalf("step %d: expected entered=%v left=%v, got %v %v", i, step.entered, step.left, entered, left)
+		}
+	}
+
+	// A failure clears the window
+	m.RecordDegradedSample(true)
+	m.ResetDegraded()
+	if entered, _ := m.RecordDegradedSample(true); entered {
+		t.Error("Expected the window to restart after ResetDegraded")
+	}
+}
+
+func TestMonitor_DegradedHysteresisDisabled(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+	if m.HasDegradedHysteresis() {
+		t.Fatal("Expected hysteresis to be disabled by default")
+	}
+	if entered, left := m.RecordDegradedSample(true); entered || left {
+		t.Error("Expected no transitions without hysteresis")
+	}
+}
+
+func TestNormalizeHysteresis(t *testing.T) {
+	cases := []struct{ window, threshold, wantWindow, wantThreshold int }{
+		{0, 3, 0, 0},
+		{5, 0, 5, 3},
+		{4, 9, 4, 4},
+		{500, 10, MaxDegradedWindowChecks, 10},
+	}
+	for _, c := range cases {
+		w, th := normalizeHysteresis(c.window, c.threshold)
+		if w != c.wantWindow || th != c.wantThreshold {
+			t.Errorf("normalizeHysteresis(%d, %d) = %d, %d; want %d, %d", c.window, c.threshold, w, th, c.wantWindow, c.wantThreshold)
+		}
+	}
+}
+
+func TestMonitor_DownBackoff(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1, DownBackoffInterval: 5 * time.Minute, DownBackoffAfterChecks: 3})
+	now := time.Now()
+
+	m.schedule()
+	lastScheduled := m.lastScheduledAt
+
+	for i := 0; i < 2; i++ {
+		m.RecordResult(false, 0, now, 0, "connection refused", false)
+	}
+	if m.InBackoff(lastScheduled.Add(time.Minute)) {
+		t.Error("Expected normal interval before the failure threshold")
+	}
+
+	m.RecordResult(false, 0, now, 0, "connection refused", false)
+	if !m.InBackoff(lastScheduled.Add(time.Minute)) {
+		t.Error("Expected ticks to be skipped after 3 consecutive failures")
+	}
+	if !m.InBackoff(lastScheduled.Add(4 * time.Minute)) {
+		t.Error("Expected ticks to be skipped until the backoff interval elapses")
+	}
+	// Ticks land on interval boundaries; the one at ~5m must not be skipped by jitter
+	if m.InBackoff(lastScheduled.Add(5*time.Minute - 10*time.Millisecond)) {
+		t.Error("Expected a check once the backoff interval elapsed")
+	}
+
+	// First success restores the normal interval
+	m.RecordResult(true, 20, now, 200, "", false)
+	if m.InBackoff(lastScheduled.Add(time.Minute)) {
+		t.Error("Expected normal interval after a successful check")
+	}
+}
+
+func TestMonitor_DownBackoffDisabled(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+	m.schedule()
+	for i := 0; i < 20; i++ {
+		m.RecordResult(false, 0, time.Now(), 0, "timeout", false)
+	}
+	if m.InBackoff(time.Now().Add(time.Minute)) {
+		t.Error("Expected no backoff without a configured interval")
+	}
+}
+
+func TestMonitor_InExclusion(t *testing.T) {
+	m := NewMonitor("m1", "g1", "ETL", "http://etl", time.Minute, make(chan Job, 1), time.Now(), nil)
+	night := time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)
+	if m.InExclusion(night) {
+		t.Error("Expected no exclusion without windows")
+	}
+
+	m.SetCheckExclusions(&db.MonitorCheckExclusions{Timezone: "UTC", Windows: []db.CheckExclusionWindow{{Start: "01:00", End: "03:00"}}})
+	if !m.InExclusion(night) || m.InExclusion(night.Add(2*time.Hour)) {
+		t.Error("Expected only ticks inside the window to be skipped")
+	}
+
+	m.SetCheckExclusions(nil)
+	if m.InExclusion(night) {
+		t.Error("Expected clearing the windows to resume checks")
+	}
+}
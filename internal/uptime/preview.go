@@ -0,0 +1,138 @@
+package uptime
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// PreviewResult is the outcome of a one-off check of an unsaved monitor configuration.
+type PreviewResult struct {
+	Status         string          `json:"status"` // up, degraded or down
+	Latency        int64           `json:"latency"`
+	StatusCode     int             `json:"statusCode"`
+	ResolvedIP     string          `json:"resolvedIp,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	ErrorKind      string          `json:"errorKind,omitempty"`
+	DegradedReason string          `json:"degradedReason,omitempty"` // Why an up check is degraded besides latency
+	TLS            *PreviewTLS     `json:"tls,omitempty"`
+	Timing         *db.CheckTiming `json:"timing,omitempty"`
+	CheckedAt      time.Time       `json:"checkedAt"`
+}
+
+// PreviewTLS describes the TLS connection and leaf certificate of a previewed check.
+type PreviewTLS struct {
+	Version       string    `json:"version"`
+	CipherSuite   string    `json:"cipherSuite"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	DNSNames      []string  `json:"dnsNames"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+}
+
+// PreviewCheck runs a single check of url with cfg exactly as a monitor's check would,
+// including retries and private target blocking, without recording anything. A check
+// that is up but slower than latencyThreshold (ms) is reported as degraded.
+func (m *Manager) PreviewCheck(url string, cfg *db.RequestConfig, latencyThreshold int64) PreviewResult {
+	transport := &http.Transport{DialContext: checkDialer(m.BlocksPrivateTargets())}
+	defer transport.CloseIdleConnections()
+	trace := &previewTransport{base: transport}
+	if m.checkTransport != nil {
+		trace.base = m.checkTransport
+	}
+
+	c := runHTTPCheck(trace, url, cfg)
+
+	res := PreviewResult{
+		Status:     "down",
+		Latency:    c.latency,
+		StatusCode: c.statusCode,
+		Error:      c.errMsg,
+		ErrorKind:  c.errKind,
+		Timing:     c.timing,
+		CheckedAt:  c.start,
+	}
+	if res.CheckedAt.IsZero() {
+		res.CheckedAt = time.Now().UTC()
+	}
+	if c.isUp {
+		res.Status = "up"
+		if c.headerMismatch != "" {
+			res.Status = "degraded"
+			res.DegradedReason = c.headerMismatch
+			res.ErrorKind = db.ErrorKindHeaderMismatch
+		} else if c.latency > latencyThreshold {
+			res.Status = "degraded"
+		}
+	}
+
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	res.ResolvedIP = trace.remoteIP
+	if trace.tls != nil {
+		res.TLS = previewTLS(trace.tls, res.CheckedAt)
+	}
+	return res
+}
+
+func previewTLS(cs *tls.ConnectionState, now time.Time) *PreviewTLS {
+	t := &PreviewTLS{
+		Version:     tls.VersionName(cs.Version),
+		CipherSuite: tls.CipherSuiteName(cs.CipherSuite),
+	}
+	if len(cs.PeerCertificates) > 0 {
+		leaf := cs.PeerCertificates[0]
+		t.Subject = leaf.Subject.CommonName
+		t.Issuer = leaf.Issuer.CommonName
+		t.DNSNames = leaf.DNSNames
+		t.NotBefore = leaf.NotBefore
+		t.NotAfter = leaf.NotAfter
+		t.DaysRemaining = int(leaf.NotAfter.Sub(now).Hours() / 24)
+	}
+	return t
+}
+
+// previewTransport records the remote address and TLS state of the last response, which
+// after redirects is the final hop.
+type previewTransport struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	remoteIP string
+	tls      *tls.ConnectionState
+}
+
+func (t *previewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Dials may finish on another goroutine, so the hooks take the lock themselves
+	setIP := func(addr string) {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			t.mu.Lock()
+			t.remoteIP = host
+			t.mu.Unlock()
+		}
+	}
+	trace := &httptrace.ClientTrace{
+		// ConnectDone reports the address even if the TLS handshake then fails;
+		// GotConn covers reused connections
+		ConnectDone: func(_, addr string, err error) {
+			if err == nil {
+				setIP(addr)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) { setIP(info.Conn.RemoteAddr().String()) },
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if resp != nil {
+		t.mu.Lock()
+		t.tls = resp.TLS
+		t.mu.Unlock()
+	}
+	return resp, err
+}
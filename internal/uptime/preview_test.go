@@ -0,0 +1,74 @@
+package uptime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestPreviewCheck(t *testing.T) {
+	m := &Manager{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(30 * time.Millisecond)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	res := m.PreviewCheck(srv.URL, nil, 1000)
+	if res.Status != "up" || res.StatusCode != 200 || res.ResolvedIP != "127.0.0.1" || res.TLS != nil || res.CheckedAt.IsZero() {
+		t.Errorf("Unexpected preview %+v", res)
+	}
+
+	res = m.PreviewCheck(srv.URL+"/slow", nil, 10)
+	if res.Status != "degraded" {
+		t.Errorf("Expected degraded above the latency threshold, got %s", res.Status)
+	}
+
+	res = m.PreviewCheck(srv.URL+"/missing", nil, 1000)
+	if res.Status != "down" || res.StatusCode != 404 || res.ErrorKind == "" {
+		t.Errorf("Expected a down 404, got %+v", res)
+	}
+
+	res = m.PreviewCheck(srv.URL+"/missing", &db.RequestConfig{AcceptedStatusCodes: "404"}, 1000)
+	if res.Status != "up" {
+		t.Errorf("Expected the request config to accept 404, got %+v", res)
+	}
+
+	blocking := &Manager{blockPrivateTargets: true}
+	if res := blocking.PreviewCheck(srv.URL, nil, 1000); res.Status != "down" || res.ResolvedIP != "" {
+		t.Errorf("Expected private target blocking to apply, got %+v", res)
+	}
+}
+
+func TestPreviewCheckTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// The test certificate is self-signed, so a real preview rejects it
+	if res := (&Manager{}).PreviewCheck(srv.URL, nil, 1000); res.Status != "down" || res.TLS != nil || res.ResolvedIP != "127.0.0.1" {
+		t.Errorf("Expected an untrusted certificate to fail, got %+v", res)
+	}
+
+	// With the test server's roots, the handshake details are captured
+	trace := &previewTransport{base: srv.Client().Transport}
+	if c := runHTTPCheck(trace, srv.URL, nil); !c.isUp {
+		t.Fatalf("Expected check to pass with trusted roots: %s", c.errMsg)
+	}
+	if trace.tls == nil || trace.remoteIP != "127.0.0.1" {
+		t.Fatalf("Expected TLS state and remote IP, got %v %q", trace.tls, trace.remoteIP)
+	}
+	info := previewTLS(trace.tls, time.Now())
+	if info.Version == "" || info.CipherSuite == "" || info.NotAfter.IsZero() || info.DaysRemaining <= 0 {
+		t.Errorf("Unexpected TLS info %+v", info)
+	}
+	if len(info.DNSNames) == 0 || info.DNSNames[0] != "example.com" {
+		t.Errorf("Expected the test certificate's DNS names, got %v", info.DNSNames)
+	}
+}
@@ -0,0 +1,156 @@
+package uptime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// RemediationTimeout bounds a single remediation webhook call.
+	RemediationTimeout = 30 * time.Second
+	// maxRemediationResponseBytes caps the response body kept with the outage.
+	maxRemediationResponseBytes = 1024
+)
+
+// remediationPayload is the body sent to a remediation webhook that has none configured.
+type remediationPayload struct {
+	MonitorID   string    `json:"monitorId"`
+	MonitorName string    `json:"monitorName"`
+	URL         string    `json:"url"`
+	Message     string    `json:"message"`
+	Time        time.Time `json:"time"`
+}
+
+// maybeRemediate calls the monitor's remediation webhook once per down streak, after its
+// configured number of failed checks. It waits while a dependency is blamed for the
+// failures or the monitor is in maintenance, and skips the streak when the action ran
+// within its cooldown. The result is kept with the outage and recorded as an event.
+func (m *Manager) maybeRemediate(mon *Monitor, res CheckResult) {
+	m.mu.RLock()
+	r, ok := m.remediations[res.MonitorID]
+	m.mu.RUnlock()
+
+	if !ok || !r.Enabled || mon.AffectedBy() != "" || m.isMonitorInMaintenance(mon.GetGroupID()) {
+		return
+	}
+	if !mon.ShouldRemediate(r.AfterChecks) {
+		return
+	}
+
+	now := time.Now()
+	if r.LastRunAt != nil && now.Sub(*r.LastRunAt) < time.Duration(r.CooldownMinutes)*time.Minute {
+		log.Printf("Skipping remediation for monitor %s: last run %s ago, within its cooldown", res.MonitorID, now.Sub(*r.LastRunAt).Round(time.Second))
+		return
+	}
+
+	m.mu.Lock()
+	if cur, ok := m.remediations[res.MonitorID]; ok {
+		cur.LastRunAt = &now
+		m.remediations[res.MonitorID] = cur
+	}
+	m.mu.Unlock()
+
+	message := res.Summary
+	if message == "" {
+		message = res.Error
+	}
+	payload := remediationPayload{
+		MonitorID:   res.MonitorID,
+		MonitorName: mon.GetName(),
+		URL:         mon.GetTargetURL(),
+		Message:     message,
+		Time:        res.Timestamp,
+	}
+
+	go func() {
+		if err := m.store.MarkRemediationRun(res.MonitorID, now); err != nil {
+			log.Printf("Failed to stamp remediation run for monitor %s: %v", res.MonitorID, err)
+		}
+
+		run := m.runRemediation(r, payload)
+		if err := m.store.SetOutageRemediation(res.MonitorID, run); err != nil {
+			log.Printf("Failed to store remediation result for monitor %s: %v", res.MonitorID, err)
+		}
+		_ = m.store.CreateEvent(res.MonitorID, "remediation", remediationSummary(run))
+		log.Printf("AUDIT: [REMEDIATION] Monitor %s remediation action: %s", res.MonitorID, remediationSummary(run))
+	}()
+}
+
+// runRemediation calls the webhook and describes its answer.
+func (m *Manager) runRemediation(r db.MonitorRemediation, payload remediationPayload) db.RemediationRun {
+	run := db.RemediationRun{StartedAt: time.Now().UTC()}
+	defer func() { run.DurationMs = time.Since(run.StartedAt).Milliseconds() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), RemediationTimeout)
+	defer cancel()
+
+	body := []byte(r.Body)
+	if r.Body == "" && r.Method != http.MethodGet {
+		body, _ = json.Marshal(payload)
+	}
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, reader)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	if r.Body == "" && reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	transport := m.checkTransport
+	if transport == nil {
+		transport = &http.Transport{DialContext: checkDialer(m.BlocksPrivateTargets()), DisableKeepAlives: true}
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxRemediationResponseBytes))
+	run.StatusCode = resp.StatusCode
+	run.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	run.BodySnippet = strings.ToValidUTF8(strings.TrimSpace(string(snippet)), "")
+	return run
+}
+
+// remediationSummary describes a remediation run in one line for the event log.
+func remediationSummary(run db.RemediationRun) string {
+	if run.Error != "" {
+		return "Remediation action failed: " + run.Error
+	}
+	if !run.Success {
+		return fmt.Sprintf("Remediation action failed with HTTP %d", run.StatusCode)
+	}
+	return fmt.Sprintf("Remediation action ran: HTTP %d", run.StatusCode)
+}
+
+// setRemediations replaces the remediation actions. Callers hold m.mu.
+func (m *Manager) setRemediations(remediations map[string]db.MonitorRemediation) {
+	// Keep run times stamped since the actions were loaded, so a Sync racing a run
+	// doesn't reopen its cooldown
+	for id, r := range remediations {
+		if cur, ok := m.remediations[id]; ok && cur.LastRunAt != nil && (r.LastRunAt == nil || cur.LastRunAt.After(*r.LastRunAt)) {
+			r.LastRunAt = cur.LastRunAt
+			remediations[id] = r
+		}
+	}
+	m.remediations = remediations
+}
@@ -0,0 +1,151 @@
+package uptime
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestMonitor_ShouldRemediate(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 2})
+
+	m.IncrementDown()
+	if m.ShouldRemediate(0) {
+		t.Error("Expected no remediation before the outage is confirmed")
+	}
+	m.IncrementDown()
+	if m.ShouldRemediate(3) {
+		t.Error("Expected no remediation before the configured number of failures")
+	}
+	m.IncrementDown()
+	if !m.ShouldRemediate(3) {
+		t.Error("Expected remediation after 3 failures")
+	}
+	m.IncrementDown()
+	if m.ShouldRemediate(3) {
+		t.Error("Expected only one remediation per down streak")
+	}
+
+	m.ResetDown()
+	m.IncrementDown()
+	m.IncrementDown()
+	if !m.ShouldRemediate(0) {
+		t.Error("Expected remediation once a new outage is confirmed")
+	}
+}
+
+func TestManager_RemediationOnOutage(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:remediate%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer target.Close()
+
+	var calls atomic.Int32
+	var payload remediationPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &payload)
+		}
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("queued build #42"))
+	}))
+	defer hook.Close()
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m-fix", GroupID: "g-default", Name: "Fix me", URL: target.URL, Active: true, Interval: 1}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := store.SetMonitorRemediation(db.MonitorRemediation{
+		MonitorID: "m-fix", URL: hook.URL, Method: http.MethodPost, Headers: map[string]string{"X-Token": "secret"},
+		AfterChecks: 2, CooldownMinutes: 30, Enabled: true,
+	}); err != nil {
+		t.Fatalf("SetMonitorRemediation failed: %v", err)
+	}
+
+	m := NewManager(store)
+	m.Start()
+	defer m.Stop()
+
+	deadline := time.Now().Add(8 * time.Second)
+	for time.Now().Before(deadline) {
+		outages, _ := store.GetActiveOutages()
+		if len(outages) == 1 {
+			o, err := store.GetOutageByID(outages[0].ID)
+			if err != nil {
+				t.Fatalf("GetOutageByID failed: %v", err)
+			}
+			if o.Remediation != nil {
+				if !o.Remediation.Success || o.Remediation.StatusCode != http.StatusCreated || o.Remediation.BodySnippet != "queued build #42" {
+					t.Errorf("Unexpected remediation result: %+v", o.Remediation)
+				}
+				if payload.MonitorID != "m-fix" || payload.MonitorName != "Fix me" || payload.URL != target.URL {
+					t.Errorf("Unexpected default payload: %+v", payload)
+				}
+				// Let another failing check go through; the action must not repeat
+				time.Sleep(1500 * time.Millisecond)
+				if n := calls.Load(); n != 1 {
+					t.Errorf("Expected a single remediation per outage, got %d", n)
+				}
+				r, err := store.GetMonitorRemediation("m-fix")
+				if err != nil || r.LastRunAt == nil {
+					t.Errorf("Expected the run to be stamped, got %+v (%v)", r, err)
+				}
+				return
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("Remediation result was never attached to the outage")
+}
+
+func TestManager_RemediationCooldown(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:remediatecd%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+
+	var calls atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer hook.Close()
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m-cd", GroupID: "g-default", Name: "Cooldown", URL: "http://example.invalid", Active: false, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := store.SetMonitorRemediation(db.MonitorRemediation{MonitorID: "m-cd", URL: hook.URL, Method: http.MethodPost, CooldownMinutes: 30, Enabled: true}); err != nil {
+		t.Fatalf("SetMonitorRemediation failed: %v", err)
+	}
+	if err := store.MarkRemediationRun("m-cd", time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatalf("MarkRemediationRun failed: %v", err)
+	}
+
+	m := NewManager(store)
+	m.Sync()
+	mon := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+	mon.IncrementDown()
+	m.maybeRemediate(mon, CheckResult{MonitorID: "m-cd", Status: false, Timestamp: time.Now()})
+
+	time.Sleep(200 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Expected no remediation within the cooldown, got %d calls", n)
+	}
+}
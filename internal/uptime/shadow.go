@@ -0,0 +1,121 @@
+package uptime
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// setShadows replaces the pending shadow checks from the stored trials, keeping only
+// those with checks left to run. Callers hold m.mu.
+func (m *Manager) setShadows(trials map[string]db.MonitorShadow) {
+	m.shadows = make(map[string]*shadowTrial)
+	for id, t := range trials {
+		if remaining := t.TargetChecks - t.CompletedChecks; remaining > 0 {
+			m.shadows[id] = &shadowTrial{url: t.URL, remaining: remaining}
+		}
+	}
+}
+
+// shadowTrial is a monitor's candidate URL and how many shadow checks are still due.
+type shadowTrial struct {
+	url       string
+	remaining int
+}
+
+// claimShadowCheck reserves one shadow check for the monitor, returning the candidate
+// URL to check.
+func (m *Manager) claimShadowCheck(monitorID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.shadows[monitorID]
+	if !ok {
+		return "", false
+	}
+	t.remaining--
+	if t.remaining <= 0 {
+		delete(m.shadows, monitorID)
+	}
+	return t.url, true
+}
+
+// runShadowCheck checks the candidate URL with the job's request config and stores the
+// result next to the primary check's. Shadow results never reach the result pipeline,
+// so they cannot open outages or send notifications.
+func (m *Manager) runShadowCheck(transport http.RoundTripper, job Job, url string, primary httpCheck) {
+	shadow := runHTTPCheck(transport, url, job.RequestConfig)
+
+	ts := primary.start
+	if ts.IsZero() {
+		ts = time.Now().UTC()
+	}
+	sample := db.ShadowSample{
+		Timestamp: ts,
+		Primary:   shadowResult(primary),
+		Shadow:    shadowResult(shadow),
+	}
+	if err := m.store.AddMonitorShadowSample(job.MonitorID, sample); err != nil {
+		log.Printf("Failed to record shadow check for %s: %v", job.MonitorID, err)
+	}
+}
+
+func shadowResult(c httpCheck) db.ShadowResult {
+	return db.ShadowResult{Up: c.isUp, StatusCode: c.statusCode, Latency: c.latency, Error: c.errMsg}
+}
+
+// Shadow trial verdicts.
+const (
+	ShadowVerdictPending  = "pending"  // checks still running
+	ShadowVerdictMatch    = "match"    // every check agreed
+	ShadowVerdictMismatch = "mismatch" // at least one check disagreed
+)
+
+// ShadowComparison summarizes a shadow trial: how the candidate URL fared against the
+// current one over the same checks.
+type ShadowComparison struct {
+	Checks            int     `json:"checks"`
+	PrimaryUp         int     `json:"primaryUp"`
+	ShadowUp          int     `json:"shadowUp"`
+	Agreements        int     `json:"agreements"` // same up/down state and status code
+	PrimaryAvgLatency int64   `json:"primaryAvgLatency"`
+	ShadowAvgLatency  int64   `json:"shadowAvgLatency"`
+	AgreementRate     float64 `json:"agreementRate"` // 0-1, 0 without checks
+	Verdict           string  `json:"verdict"`
+}
+
+// CompareShadow compares a trial's paired checks. A disagreement settles the verdict as
+// a mismatch right away; a match needs the trial to have completed.
+func CompareShadow(trial db.MonitorShadow, samples []db.ShadowSample) ShadowComparison {
+	c := ShadowComparison{Checks: len(samples)}
+	var primaryLatency, shadowLatency int64
+	for _, s := range samples {
+		if s.Primary.Up {
+			c.PrimaryUp++
+		}
+		if s.Shadow.Up {
+			c.ShadowUp++
+		}
+		if s.Primary.Up == s.Shadow.Up && s.Primary.StatusCode == s.Shadow.StatusCode {
+			c.Agreements++
+		}
+		primaryLatency += s.Primary.Latency
+		shadowLatency += s.Shadow.Latency
+	}
+	if c.Checks > 0 {
+		c.PrimaryAvgLatency = primaryLatency / int64(c.Checks)
+		c.ShadowAvgLatency = shadowLatency / int64(c.Checks)
+		c.AgreementRate = float64(c.Agreements) / float64(c.Checks)
+	}
+
+	switch {
+	case c.Agreements < c.Checks:
+		c.Verdict = ShadowVerdictMismatch
+	case c.Checks < trial.TargetChecks:
+		c.Verdict = ShadowVerdictPending
+	default:
+		c.Verdict = ShadowVerdictMatch
+	}
+	return c
+}
@@ -0,0 +1,100 @@
+package uptime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestCompareShadow(t *testing.T) {
+	ok := db.ShadowResult{Up: true, StatusCode: 200, Latency: 100}
+	slowOK := db.ShadowResult{Up: true, StatusCode: 200, Latency: 300}
+	notFound := db.ShadowResult{StatusCode: 404, Latency: 50}
+	redirect := db.ShadowResult{Up: true, StatusCode: 301, Latency: 10}
+
+	trial := db.MonitorShadow{TargetChecks: 2}
+	tests := []struct {
+		name    string
+		samples []db.ShadowSample
+		verdict string
+		agree   int
+	}{
+		{"no checks yet", nil, ShadowVerdictPending, 0},
+		{"partial agreement", []db.ShadowSample{{Primary: ok, Shadow: slowOK}}, ShadowVerdictPending, 1},
+		{"all agree", []db.ShadowSample{{Primary: ok, Shadow: slowOK}, {Primary: ok, Shadow: ok}}, ShadowVerdictMatch, 2},
+		{"down on candidate", []db.ShadowSample{{Primary: ok, Shadow: notFound}}, ShadowVerdictMismatch, 0},
+		{"different status code", []db.ShadowSample{{Primary: ok, Shadow: ok}, {Primary: ok, Shadow: redirect}}, ShadowVerdictMismatch, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := CompareShadow(trial, tt.samples)
+			if c.Verdict != tt.verdict || c.Agreements != tt.agree {
+				t.Errorf("verdict=%s agreements=%d, want %s %d", c.Verdict, c.Agreements, tt.verdict, tt.agree)
+			}
+		})
+	}
+
+	c := CompareShadow(trial, []db.ShadowSample{{Primary: ok, Shadow: slowOK}, {Primary: ok, Shadow: notFound}})
+	if c.PrimaryAvgLatency != 100 || c.ShadowAvgLatency != 175 || c.PrimaryUp != 2 || c.ShadowUp != 1 || c.AgreementRate != 0.5 {
+		t.Errorf("unexpected comparison %+v", c)
+	}
+}
+
+func TestShadowChecksRunAlongsideMonitor(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	setIntegrationTestDefaults(store)
+
+	current := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer current.Close()
+	candidate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer candidate.Close()
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m-shadow", GroupID: "g-default", Name: "API", URL: current.URL, Active: true, Interval: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.StartMonitorShadow("m-shadow", candidate.URL, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(store)
+	m.Start()
+	defer m.Stop()
+	m.Sync()
+
+	deadline := time.Now().Add(5 * time.Second)
+	var samples []db.ShadowSample
+	for time.Now().Before(deadline) {
+		if samples, err = store.GetMonitorShadowSamples("m-shadow"); err == nil && len(samples) >= 2 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if len(samples) < 2 {
+		t.Fatalf("Expected 2 shadow samples, got %d", len(samples))
+	}
+	for _, s := range samples {
+		if !s.Primary.Up || s.Primary.StatusCode != 200 || s.Shadow.Up || s.Shadow.StatusCode != 503 {
+			t.Errorf("Unexpected sample %+v", s)
+		}
+	}
+
+	// The failing candidate never touches the monitor's own status
+	isUp, _, hasHistory, _ := m.GetMonitor("m-shadow").GetLastStatus()
+	if !hasHistory || !isUp {
+		t.Errorf("Expected monitor to stay up, got up=%v history=%v", isUp, hasHistory)
+	}
+
+	// Once the trial's checks are used up no more samples are recorded
+	time.Sleep(1500 * time.Millisecond)
+	if later, _ := store.GetMonitorShadowSamples("m-shadow"); len(later) != len(samples) {
+		t.Errorf("Expected shadow checks to stop at %d, got %d", len(samples), len(later))
+	}
+}
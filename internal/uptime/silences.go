@@ -0,0 +1,34 @@
+package uptime
+
+import (
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// indexSilences maps every monitor to the silences covering it, so notifications are
+// matched without resolving group and tag scopes on every event.
+func indexSilences(silences []db.Silence, monitors []db.Monitor) map[string][]db.Silence {
+	byMonitor := make(map[string][]db.Silence)
+	for _, sil := range silences {
+		for _, mon := range monitors {
+			if sil.Matches(mon) {
+				byMonitor[mon.ID] = append(byMonitor[mon.ID], sil)
+			}
+		}
+	}
+	return byMonitor
+}
+
+// IsMonitorSilenced reports whether a silence mutes the monitor's notifications at t.
+func (m *Manager) IsMonitorSilenced(monitorID string, t time.Time) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, sil := range m.silencesByMonitor[monitorID] {
+		if sil.ActiveAt(t) {
+			return true
+		}
+	}
+	return false
+}
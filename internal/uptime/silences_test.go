@@ -0,0 +1,97 @@
+package uptime
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestIndexSilences(t *testing.T) {
+	monitors := []db.Monitor{
+		{ID: "m1", GroupID: "g1", Tags: []string{"prod"}},
+		{ID: "m2", GroupID: "g1"},
+		{ID: "m3", GroupID: "g2", Tags: []string{"prod", "db"}},
+	}
+	byMonitor := indexSilences([]db.Silence{
+		{ID: "s-mon", Scope: db.SilenceScopeMonitor, Target: "m2"},
+		{ID: "s-group", Scope: db.SilenceScopeGroup, Target: "g2"},
+		{ID: "s-tag", Scope: db.SilenceScopeTag, Target: "prod"},
+		{ID: "s-none", Scope: db.SilenceScopeTag, Target: "staging"},
+	}, monitors)
+
+	want := map[string]int{"m1": 1, "m2": 1, "m3": 2}
+	for id, n := range want {
+		if len(byMonitor[id]) != n {
+			t.Errorf("Expected %s in %d silences, got %v", id, n, byMonitor[id])
+		}
+	}
+	if len(byMonitor) != 3 {
+		t.Errorf("Expected only known monitors to be indexed, got %v", byMonitor)
+	}
+}
+
+func TestManager_IsMonitorSilenced_UpdatedBySync(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	_ = store.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Web", URL: "http://web", Interval: 60, Active: false})
+	m := NewManager(store)
+
+	now := time.Now()
+	if m.IsMonitorSilenced("m1", now) {
+		t.Fatal("Expected no silence before one is created")
+	}
+	_ = store.CreateSilence(db.Silence{ID: "sil-1", Scope: db.SilenceScopeGroup, Target: "g-default", Reason: "deploy",
+		StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+	m.Sync()
+	if !m.IsMonitorSilenced("m1", now) {
+		t.Error("Expected the group silence to mute the monitor after Sync")
+	}
+	if m.IsMonitorSilenced("m1", now.Add(2*time.Hour)) {
+		t.Error("Expected the silence to end at its end time")
+	}
+
+	_ = store.ExpireSilence("sil-1", now)
+	m.Sync()
+	if m.IsMonitorSilenced("m1", now) {
+		t.Error("Expected an expired silence to stop muting the monitor")
+	}
+}
+
+func TestManager_SilencesKeptWhenLoadFails(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "silences.db")
+	store, err := db.NewStore(db.NewTestConfigWithPath(dbPath))
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	_ = store.CreateMonitor(db.Monitor{ID: "m1", GroupID: "g-default", Name: "Web", URL: "http://web", Interval: 60, Active: false})
+	now := time.Now()
+	_ = store.CreateSilence(db.Silence{ID: "sil-1", Scope: db.SilenceScopeMonitor, Target: "m1", Reason: "deploy",
+		StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+	m := NewManager(store)
+	m.Sync()
+	if !m.IsMonitorSilenced("m1", now) {
+		t.Fatal("Expected the silence to mute the monitor")
+	}
+
+	// Make the next load fail
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Exec("ALTER TABLE silences RENAME TO silences_gone"); err != nil {
+		t.Fatalf("Failed to rename table: %v", err)
+	}
+	if _, err := store.GetSilences(false, now); err == nil {
+		t.Fatal("Expected loading silences to fail")
+	}
+	m.Sync()
+	if !m.IsMonitorSilenced("m1", now) {
+		t.Error("Expected the silence to keep muting the monitor when loading silences fails")
+	}
+}
@@ -0,0 +1,190 @@
+package uptime
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// sloEvaluateInterval is how often latency SLOs are rolled up and checked for burn.
+const sloEvaluateInterval = 5 * time.Minute
+
+// sloBurnWindow pairs a long burn rate window with a short one. An alert fires when both
+// burn faster than spending BudgetFraction of the error budget within Long; the short
+// window makes the alert stop soon after the burn does.
+type sloBurnWindow struct {
+	Long, Short    time.Duration
+	BudgetFraction float64
+}
+
+// sloBurnWindows alert on a fast burn (2% of the budget in an hour) and a slow burn (5% in
+// six hours). For a 30 day window these are the usual 14.4x and 6x burn rates.
+var sloBurnWindows = []sloBurnWindow{
+	{Long: time.Hour, Short: 5 * time.Minute, BudgetFraction: 0.02},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, BudgetFraction: 0.05},
+}
+
+// SLOBurnRate is how fast an SLO's error budget is being spent over a window: 1 spends
+// exactly the budget over the SLO window, higher values exhaust it early.
+type SLOBurnRate struct {
+	Window    string  `json:"window"`
+	BurnRate  float64 `json:"burnRate"`
+	Threshold float64 `json:"threshold"` // Burn rate that alerts for this window
+	Checks    int64   `json:"checks"`
+}
+
+// LatencySLOStatus is a latency SLO's attainment over its window and current burn rates.
+type LatencySLOStatus struct {
+	Checks               int64         `json:"checks"`
+	GoodChecks           int64         `json:"goodChecks"`
+	AttainmentPercent    float64       `json:"attainmentPercent"`    // 100 while there are no checks
+	ErrorBudgetRemaining float64       `json:"errorBudgetRemaining"` // Fraction of the window's budget left, negative once the SLO is violated
+	BurnRates            []SLOBurnRate `json:"burnRates"`
+	Burning              bool          `json:"burning"`
+	Reason               string        `json:"reason,omitempty"` // Why the SLO is burning
+}
+
+// EvaluateLatencySLO computes an SLO's status at now from its rollups. Windows are aligned
+// to rollup buckets, so the most recent one may be partly filled.
+func EvaluateLatencySLO(store *db.Store, slo db.LatencySLO, now time.Time) (LatencySLOStatus, error) {
+	status := LatencySLOStatus{AttainmentPercent: 100, ErrorBudgetRemaining: 1, BurnRates: []SLOBurnRate{}}
+	window := time.Duration(slo.WindowDays) * 24 * time.Hour
+	rollups, err := store.GetLatencySLORollups(slo.MonitorID, now.Add(-window).Truncate(db.LatencySLORollupBucket))
+	if err != nil {
+		return status, err
+	}
+
+	budget := 1 - slo.TargetPercent/100
+	// count sums the rollups within d of now
+	count := func(d time.Duration) (good, total int64) {
+		since := now.Add(-d).Truncate(db.LatencySLORollupBucket)
+		for _, r := range rollups {
+			if !r.Bucket.Before(since) {
+				good += r.Good
+				total += r.Total
+			}
+		}
+		return good, total
+	}
+	burnRate := func(d time.Duration) SLOBurnRate {
+		good, total := count(d)
+		r := SLOBurnRate{Window: formatSLOWindow(d), Checks: total}
+		if total > 0 && budget > 0 {
+			r.BurnRate = float64(total-good) / float64(total) / budget
+		}
+		return r
+	}
+
+	status.GoodChecks, status.Checks = count(window)
+	if status.Checks > 0 {
+		bad := float64(status.Checks - status.GoodChecks)
+		status.AttainmentPercent = float64(status.GoodChecks) / float64(status.Checks) * 100
+		if budget > 0 {
+			status.ErrorBudgetRemaining = 1 - bad/(budget*float64(status.Checks))
+		}
+	}
+
+	for _, w := range sloBurnWindows {
+		threshold := w.BudgetFraction * window.Hours() / w.Long.Hours()
+		long, short := burnRate(w.Long), burnRate(w.Short)
+		long.Threshold, short.Threshold = threshold, threshold
+		status.BurnRates = append(status.BurnRates, short, long)
+		if !status.Burning && long.BurnRate >= threshold && short.BurnRate >= threshold {
+			status.Burning = true
+			status.Reason = fmt.Sprintf("%s burn rate %.1fx (alerts at %.1fx)", long.Window, long.BurnRate, threshold)
+		}
+	}
+	return status, nil
+}
+
+func formatSLOWindow(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
+
+// LatencySLOStatus rolls up the monitor's latest checks into its SLO and evaluates it.
+func (m *Manager) LatencySLOStatus(slo db.LatencySLO, now time.Time) (LatencySLOStatus, error) {
+	// Start from the latest bucket, which may have been partial; backfill the whole
+	// window the first time.
+	since := now.AddDate(0, 0, -slo.WindowDays)
+	if latest, ok, err := m.store.GetLatestLatencySLORollup(slo.MonitorID); err != nil {
+		return LatencySLOStatus{}, err
+	} else if ok && latest.After(since) {
+		since = latest
+	}
+	if err := m.store.RollupLatencySLO(slo, since, now); err != nil {
+		return LatencySLOStatus{}, err
+	}
+	return EvaluateLatencySLO(m.store, slo, now)
+}
+
+// evaluateLatencySLOs notifies once when an SLO starts burning, and re-arms the alert once
+// the burn stops.
+func (m *Manager) evaluateLatencySLOs(now time.Time) {
+	slos, err := m.store.GetLatencySLOs()
+	if err != nil {
+		log.Printf("SLO: failed to load latency SLOs: %v", err)
+		return
+	}
+
+	for _, slo := range slos {
+		mon := m.GetMonitor(slo.MonitorID)
+		if mon == nil {
+			continue // Paused or inactive
+		}
+		status, err := m.LatencySLOStatus(slo, now)
+		if err != nil {
+			log.Printf("SLO: failed to evaluate latency SLO of %s: %v", slo.MonitorID, err)
+			continue
+		}
+
+		switch {
+		case status.Burning && !slo.Alerting:
+			msg := fmt.Sprintf("Latency SLO burning: %s; %.2f%% of checks within %dms over %dd (target %.2f%%)",
+				status.Reason, status.AttainmentPercent, slo.ThresholdMs, slo.WindowDays, slo.TargetPercent)
+			go func(id string) { _ = m.store.CreateEvent(id, "slo_burn", msg) }(slo.MonitorID)
+			if !m.isMonitorInMaintenance(mon.GetGroupID()) {
+				m.enqueueOrDigest(notifications.NotificationEvent{
+					MonitorID:   slo.MonitorID,
+					MonitorName: mon.GetName(),
+					MonitorURL:  mon.GetTargetURL(),
+					Type:        notifications.EventSLOBurn,
+					Message:     msg,
+					Time:        now,
+				})
+			}
+			if err := m.store.SetLatencySLOAlerting(slo.MonitorID, true, now); err != nil {
+				log.Printf("SLO: failed to mark %s alerting: %v", slo.MonitorID, err)
+			}
+			log.Printf("Monitor %s latency SLO is BURNING: %s", slo.MonitorID, status.Reason)
+		case !status.Burning && slo.Alerting:
+			if err := m.store.SetLatencySLOAlerting(slo.MonitorID, false, now); err != nil {
+				log.Printf("SLO: failed to clear %s alerting: %v", slo.MonitorID, err)
+			}
+		}
+	}
+}
+
+func (m *Manager) sloWorker() {
+	m.wg.Add(1)
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(sloEvaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			if m.IsLeader() {
+				m.evaluateLatencySLOs(time.Now().UTC())
+			}
+		}
+	}
+}
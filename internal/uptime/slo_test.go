@@ -0,0 +1,115 @@
+package uptime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestEvaluateLatencySLOs(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := NewManager(store)
+
+	if err := store.CreateMonitor(db.Monitor{
+		ID: "m-slo", GroupID: "g-default", Name: "SLO", URL: "http://example.com", Active: true, Interval: 60,
+	}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if err := store.SetLatencySLO(db.LatencySLO{MonitorID: "m-slo", ThresholdMs: 300, TargetPercent: 99, WindowDays: 30}); err != nil {
+		t.Fatalf("SetLatencySLO failed: %v", err)
+	}
+	m.Sync()
+	defer m.Stop()
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	insertChecks := func(from, to time.Time, latency int64) {
+		var checks []db.CheckResult
+		for ts := from; ts.Before(to); ts = ts.Add(time.Minute) {
+			checks = append(checks, db.CheckResult{MonitorID: "m-slo", Status: "up", Latency: latency, Timestamp: ts, StatusCode: 200})
+		}
+		if err := store.BatchInsertChecks(checks); err != nil {
+			t.Fatalf("BatchInsertChecks failed: %v", err)
+		}
+	}
+	getSLO := func() *db.LatencySLO {
+		slo, err := store.GetLatencySLO("m-slo")
+		if err != nil {
+			t.Fatalf("GetLatencySLO failed: %v", err)
+		}
+		return slo
+	}
+
+	// An hour of fast checks stays within the SLO
+	insertChecks(now.Add(-2*time.Hour), now.Add(-time.Hour), 50)
+	m.evaluateLatencySLOs(now.Add(-time.Hour))
+	status, err := EvaluateLatencySLO(store, *getSLO(), now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("EvaluateLatencySLO failed: %v", err)
+	}
+	if status.Burning || status.Checks != 60 || status.AttainmentPercent != 100 || status.ErrorBudgetRemaining != 1 {
+		t.Errorf("Expected a healthy SLO, got %+v", status)
+	}
+	if getSLO().Alerting {
+		t.Error("Expected no alert for a healthy SLO")
+	}
+
+	// An hour of slow checks burns the budget about 100x faster than allowed
+	insertChecks(now.Add(-time.Hour), now, 500)
+	m.evaluateLatencySLOs(now)
+	status, err = EvaluateLatencySLO(store, *getSLO(), now)
+	if err != nil {
+		t.Fatalf("EvaluateLatencySLO failed: %v", err)
+	}
+	if !status.Burning || status.Checks != 120 || status.AttainmentPercent != 50 {
+		t.Errorf("Expected a burning SLO, got %+v", status)
+	}
+	if !strings.HasPrefix(status.Reason, "1h burn rate ") {
+		t.Errorf("Expected the fast burn window in the reason, got %q", status.Reason)
+	}
+	for _, r := range status.BurnRates {
+		if r.Window == "1h" && r.Threshold != 14.4 {
+			t.Errorf("Expected a 14.4x fast burn threshold for a 30 day window, got %v", r.Threshold)
+		}
+		if r.Window == "6h" && r.Threshold != 6 {
+			t.Errorf("Expected a 6x slow burn threshold for a 30 day window, got %v", r.Threshold)
+		}
+	}
+	slo := getSLO()
+	if !slo.Alerting || slo.LastAlertAt == nil {
+		t.Fatalf("Expected the SLO to be alerting, got %+v", slo)
+	}
+
+	// A burning SLO alerts once
+	alertedAt := *slo.LastAlertAt
+	m.evaluateLatencySLOs(now.Add(time.Minute))
+	if slo := getSLO(); !slo.LastAlertAt.Equal(alertedAt) {
+		t.Errorf("Expected no second alert, last alert moved to %v", slo.LastAlertAt)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		events, err := store.GetMonitorEvents("m-slo", 10)
+		if err != nil {
+			t.Fatalf("GetMonitorEvents failed: %v", err)
+		}
+		if len(events) == 1 && events[0].Type == "slo_burn" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected one slo_burn event, got %+v", events)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// Fast checks bring the short windows back under the threshold and re-arm the alert
+	insertChecks(now, now.Add(40*time.Minute), 50)
+	m.evaluateLatencySLOs(now.Add(40 * time.Minute))
+	if getSLO().Alerting {
+		t.Error("Expected the alert to re-arm once the burn stopped")
+	}
+}
@@ -0,0 +1,178 @@
+package uptime
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+const (
+	// MaxSpoolBytes caps the spool file so a long database outage can't fill the disk.
+	MaxSpoolBytes int64 = 64 << 20
+	// BatchWriteRetries is how many later flushes retry a failed batch insert before spooling.
+	BatchWriteRetries = 3
+)
+
+// ErrSpoolFull is returned when appending would grow the spool beyond its size limit.
+var ErrSpoolFull = errors.New("check spool is full")
+
+// CheckSpool holds check results that could not be written to the database, as
+// JSON lines in a local file, until they can be replayed.
+type CheckSpool struct {
+	path     string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// NewCheckSpool creates a spool backed by the file at path. The file is created on first use.
+func NewCheckSpool(path string) *CheckSpool {
+	return &CheckSpool{path: path, maxBytes: MaxSpoolBytes}
+}
+
+// Append writes checks to the end of the spool file.
+func (s *CheckSpool) Append(checks []db.CheckResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if info, err := f.Stat(); err == nil && info.Size() >= s.maxBytes {
+		return ErrSpoolFull
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, c := range checks {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Pending reports whether the spool holds any checks.
+func (s *CheckSpool) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.path)
+	return err == nil && info.Size() > 0
+}
+
+// Replay passes every spooled check to insert in a single call and removes the
+// spool file once insert succeeds. On failure the file is left for the next attempt.
+// Lines that fail to decode (e.g. a write cut short by a crash) are skipped.
+func (s *CheckSpool) Replay(insert func([]db.CheckResult) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var checks []db.CheckResult
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var c db.CheckResult
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			log.Printf("Check spool: skipping corrupt entry: %v", err)
+			continue
+		}
+		checks = append(checks, c)
+	}
+	scanErr := scanner.Err()
+	_ = f.Close()
+	if scanErr != nil {
+		return 0, scanErr
+	}
+
+	if len(checks) > 0 {
+		if err := insert(checks); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return len(checks), err
+	}
+	return len(checks), nil
+}
+
+// SetSpool enables spilling check batches that fail to persist to sp. Call before Start.
+func (m *Manager) SetSpool(sp *CheckSpool) {
+	m.spool = sp
+}
+
+// writeBatch persists a batch of checks. A failed insert is not waited out: its checks
+// are retried with the next batch, so a slow database never stalls result processing,
+// and fall back to the spool after BatchWriteRetries retries or on the final flush.
+func (m *Manager) writeBatch(batch []db.CheckResult, final bool) {
+	batch = append(m.failedBatch, batch...)
+	m.failedBatch = nil
+	err := m.store.BatchInsertChecks(batch)
+	if err == nil {
+		m.failedAttempts = 0
+		return
+	}
+	if !final && m.failedAttempts < BatchWriteRetries {
+		m.failedAttempts++
+		m.failedBatch = batch
+		return
+	}
+	m.failedAttempts = 0
+
+	if m.spool == nil {
+		log.Printf("Error capturing batch stats, %d checks lost: %v", len(batch), err)
+		return
+	}
+	if serr := m.spool.Append(batch); serr != nil {
+		log.Printf("Error capturing batch stats, %d checks lost (spool: %v): %v", len(batch), serr, err)
+		return
+	}
+	log.Printf("Error capturing batch stats, spooled %d checks for replay: %v", len(batch), err)
+}
+
+// replaySpool writes spooled checks back to the database once it accepts writes again.
+func (m *Manager) replaySpool() {
+	if m.spool == nil || !m.spool.Pending() {
+		return
+	}
+	n, err := m.spool.Replay(func(checks []db.CheckResult) error {
+		// Monitors deleted while their checks sat in the spool would fail the
+		// whole insert on the foreign key, so drop those checks
+		monitors, err := m.store.GetMonitors()
+		if err != nil {
+			return err
+		}
+		known := make(map[string]bool, len(monitors))
+		for _, mon := range monitors {
+			known[mon.ID] = true
+		}
+		kept := checks[:0]
+		for _, c := range checks {
+			if known[c.MonitorID] {
+				kept = append(kept, c)
+			}
+		}
+		return m.store.BatchInsertChecks(kept)
+	})
+	if err != nil {
+		return // Database still unavailable; try again on the next tick
+	}
+	log.Printf("Check spool: replayed %d checks", n)
+}
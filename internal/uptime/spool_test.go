@@ -0,0 +1,118 @@
+package uptime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestCheckSpool_AppendReplay(t *testing.T) {
+	sp := NewCheckSpool(filepath.Join(t.TempDir(), "checks.spool"))
+	if sp.Pending() {
+		t.Fatal("new spool should be empty")
+	}
+
+	ts := time.Now().UTC().Truncate(time.Second)
+	if err := sp.Append([]db.CheckResult{{MonitorID: "m1", Status: "up", Latency: 10, Timestamp: ts, StatusCode: 200}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := sp.Append([]db.CheckResult{{MonitorID: "m1", Status: "down", Timestamp: ts.Add(time.Minute), ErrorKind: "timeout"}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if !sp.Pending() {
+		t.Fatal("spool should have pending checks")
+	}
+
+	// A failed replay keeps the spool intact
+	if _, err := sp.Replay(func([]db.CheckResult) error { return errors.New("database is locked") }); err == nil {
+		t.Fatal("expected replay error")
+	}
+	if !sp.Pending() {
+		t.Fatal("spool should survive a failed replay")
+	}
+
+	var got []db.CheckResult
+	n, err := sp.Replay(func(c []db.CheckResult) error { got = c; return nil })
+	if err != nil || n != 2 {
+		t.Fatalf("Replay = %d, %v", n, err)
+	}
+	if got[0].StatusCode != 200 || !got[0].Timestamp.Equal(ts) || got[1].ErrorKind != "timeout" {
+		t.Errorf("unexpected replayed checks %+v", got)
+	}
+	if sp.Pending() {
+		t.Error("spool should be empty after replay")
+	}
+}
+
+func TestCheckSpool_SkipsCorruptLinesAndEnforcesLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.spool")
+	sp := NewCheckSpool(path)
+	sp.maxBytes = 1
+
+	if err := sp.Append([]db.CheckResult{{MonitorID: "m1", Status: "up"}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	// Simulate a write cut short by a crash
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	_, _ = f.WriteString(`{"monitorId":"m1","sta`)
+	_ = f.Close()
+
+	if err := sp.Append([]db.CheckResult{{MonitorID: "m1"}}); !errors.Is(err, ErrSpoolFull) {
+		t.Fatalf("expected ErrSpoolFull, got %v", err)
+	}
+
+	n, err := sp.Replay(func([]db.CheckResult) error { return nil })
+	if err != nil || n != 1 {
+		t.Fatalf("Replay = %d, %v; want 1 valid check", n, err)
+	}
+}
+
+func TestManager_WriteBatchSpoolsAndReplays(t *testing.T) {
+	broken, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:spool%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := NewManager(broken)
+	m.SetSpool(NewCheckSpool(filepath.Join(t.TempDir(), "checks.spool")))
+	_ = broken.Close() // every insert now fails
+
+	// A failed batch is kept for the next flushes instead of being retried in place
+	m.writeBatch([]db.CheckResult{
+		{MonitorID: "m-spool", Status: "up", Latency: 42, Timestamp: time.Now(), StatusCode: 200},
+	}, false)
+	for i := 0; i < BatchWriteRetries-1; i++ {
+		m.writeBatch([]db.CheckResult{
+			{MonitorID: "m-deleted", Status: "up", Latency: 7, Timestamp: time.Now(), StatusCode: 200},
+		}, false)
+	}
+	if m.spool.Pending() || len(m.failedBatch) != BatchWriteRetries {
+		t.Fatalf("failed checks should wait for the next flush, got %d pending and spool %v", len(m.failedBatch), m.spool.Pending())
+	}
+	m.writeBatch(nil, false)
+	if !m.spool.Pending() || len(m.failedBatch) != 0 {
+		t.Fatal("failed batch should be spooled once its retries are used up")
+	}
+
+	healthy, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:spool%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if err := healthy.CreateMonitor(db.Monitor{ID: "m-spool", GroupID: "g-default", Name: "Spool", URL: "http://example.com", Active: true, Interval: 60}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	m.store = healthy
+	m.replaySpool()
+
+	if m.spool.Pending() {
+		t.Error("spool should be drained after replay")
+	}
+	checks, err := healthy.GetMonitorChecks("m-spool", 10)
+	if err != nil || len(checks) != 1 || checks[0].Latency != 42 {
+		t.Fatalf("expected replayed check in database, got %+v (err %v)", checks, err)
+	}
+}
@@ -0,0 +1,72 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateTarget is returned when a check would connect to a private network address
+// while private targets are blocked.
+var ErrPrivateTarget = errors.New("private network targets are blocked on this server")
+
+// carrierGradeNAT is the shared address space (RFC 6598) that net.IP does not classify as private.
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPrivateTarget reports whether ip points into a network a shared instance must not probe:
+// loopback, RFC 1918 / ULA, link-local (including cloud metadata at 169.254.169.254),
+// carrier-grade NAT and the unspecified address.
+func IsPrivateTarget(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() ||
+		carrierGradeNAT.Contains(ip)
+}
+
+// rejectPrivateTargets is a net.Dialer Control hook. It runs after DNS resolution, on the
+// address actually being connected to, so a hostname that re-resolves to an internal IP
+// (DNS rebinding) or a redirect to one is refused as well.
+func rejectPrivateTargets(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateTarget(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+	}
+	return nil
+}
+
+// checkDialer returns the dial function used by check workers.
+func checkDialer(blockPrivate bool) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if blockPrivate {
+		d.Control = rejectPrivateTargets
+	}
+	return d.DialContext
+}
+
+// SetBlockPrivateTargets makes check workers refuse connections to private network
+// addresses. Must be called before Start.
+func (m *Manager) SetBlockPrivateTargets(block bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blockPrivateTargets = block
+}
+
+// BlocksPrivateTargets reports whether checks against private network addresses are refused.
+func (m *Manager) BlocksPrivateTargets() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.blockPrivateTargets
+}
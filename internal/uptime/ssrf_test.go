@@ -0,0 +1,67 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPrivateTarget(t *testing.T) {
+	tests := []struct {
+		ip      string
+		private bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700::1111", false},
+		{"100.128.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPrivateTarget(net.ParseIP(tt.ip)); got != tt.private {
+			t.Errorf("IsPrivateTarget(%s) = %v, want %v", tt.ip, got, tt.private)
+		}
+	}
+}
+
+func TestCheckDialer_BlocksPrivateTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	conn, err := checkDialer(false)(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatalf("unblocked dial failed: %v", err)
+	}
+	_ = conn.Close()
+
+	if _, err := checkDialer(true)(context.Background(), "tcp", addr); !errors.Is(err, ErrPrivateTarget) {
+		t.Fatalf("expected ErrPrivateTarget dialing %s, got %v", addr, err)
+	}
+}
+
+func TestManager_SetBlockPrivateTargets(t *testing.T) {
+	m := &Manager{}
+	if m.BlocksPrivateTargets() {
+		t.Fatal("private targets should be allowed by default")
+	}
+	m.SetBlockPrivateTargets(true)
+	if !m.BlocksPrivateTargets() {
+		t.Fatal("expected private targets to be blocked")
+	}
+}
@@ -0,0 +1,366 @@
+package uptime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// Storm dampening settings. A threshold of 0 (the default) disables dampening.
+const (
+	stormThresholdSettingKey = "notification.storm.threshold_percent"
+	stormWindowSettingKey    = "notification.storm.window_seconds"
+	stormMinSettingKey       = "notification.storm.min_monitors"
+
+	DefaultStormWindowSeconds = 60
+	DefaultStormMinMonitors   = 5
+
+	stormReleaseInterval = time.Second
+	stormSummaryNames    = 10 // monitors named in a platform event summary
+)
+
+// stormConfig decides when correlated failures count as a platform event: at least
+// minMonitors, and thresholdPercent of the fleet, confirmed down within window.
+type stormConfig struct {
+	thresholdPercent int
+	window           time.Duration
+	minMonitors      int
+}
+
+func (c stormConfig) enabled() bool {
+	return c.thresholdPercent > 0
+}
+
+// heldDown is a down notification waiting out the storm window before it is sent.
+type heldDown struct {
+	event notifications.NotificationEvent
+	at    time.Time
+}
+
+// platformEvent is an ongoing storm. Its monitors' down and recovery notifications are
+// replaced by one summary each, and it ends once all of them recovered.
+type platformEvent struct {
+	incidentID string
+	started    time.Time
+	monitors   map[string]bool // member -> still down
+}
+
+// stormDetector collapses mass failures, e.g. of the network Warden checks from, into
+// a single platform event. While dampening is enabled, down notifications are held for
+// the storm window; if enough monitors go down within it, the held notifications are
+// dropped in favour of one summary.
+type stormDetector struct {
+	mu     sync.Mutex
+	cfg    stormConfig
+	held   map[string]heldDown  // monitor -> its held down notification
+	recent map[string]time.Time // monitor -> confirmed down, within the window
+	event  *platformEvent
+}
+
+func newStormDetector() *stormDetector {
+	return &stormDetector{
+		cfg:    stormConfig{window: DefaultStormWindowSeconds * time.Second, minMonitors: DefaultStormMinMonitors},
+		held:   make(map[string]heldDown),
+		recent: make(map[string]time.Time),
+	}
+}
+
+// loadStormConfig reads the storm dampening settings from the database.
+func (m *Manager) loadStormConfig() stormConfig {
+	cfg := stormConfig{window: DefaultStormWindowSeconds * time.Second, minMonitors: DefaultStormMinMonitors}
+	if val, err := m.store.GetSetting(stormThresholdSettingKey); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 0 && i <= 100 {
+			cfg.thresholdPercent = i
+		}
+	}
+	if val, err := m.store.GetSetting(stormWindowSettingKey); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 1 {
+			cfg.window = time.Duration(i) * time.Second
+		}
+	}
+	if val, err := m.store.GetSetting(stormMinSettingKey); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && i >= 2 {
+			cfg.minMonitors = i
+		}
+	}
+	return cfg
+}
+
+// setStormConfig applies new settings. Notifications held under dampening that is now
+// disabled are sent right away.
+func (m *Manager) setStormConfig(cfg stormConfig) {
+	d := m.storm
+	d.mu.Lock()
+	d.cfg = cfg
+	var release []notifications.NotificationEvent
+	if !cfg.enabled() {
+		release = d.takeHeld(time.Time{})
+		clear(d.recent)
+	}
+	d.mu.Unlock()
+	m.sendHeld(release)
+}
+
+// notifyDown sends a down notification, or holds it while storm dampening watches for
+// a platform event. Monitors going down during a platform event join it silently.
+func (m *Manager) notifyDown(event notifications.NotificationEvent) {
+	fleet := len(m.GetAll())
+	now := time.Now()
+
+	d := m.storm
+	d.mu.Lock()
+	if d.event != nil {
+		d.event.monitors[event.MonitorID] = true
+		d.mu.Unlock()
+		log.Printf("Monitor %s is DOWN during a platform event, not notified", event.MonitorID)
+		return
+	}
+	if !d.cfg.enabled() {
+		d.mu.Unlock()
+		m.enqueueOrDigest(event)
+		return
+	}
+
+	for id, at := range d.recent {
+		if now.Sub(at) > d.cfg.window {
+			delete(d.recent, id)
+		}
+	}
+	d.recent[event.MonitorID] = now
+	d.held[event.MonitorID] = heldDown{event: event, at: now}
+
+	down := len(d.recent)
+	if down < d.cfg.minMonitors || down*100 < d.cfg.thresholdPercent*fleet {
+		d.mu.Unlock()
+		return
+	}
+
+	// A storm: every monitor confirmed down within the window is still held
+	ev := &platformEvent{incidentID: generatePlatformEventID(), started: now, monitors: make(map[string]bool, down)}
+	for id := range d.recent {
+		ev.monitors[id] = true
+	}
+	suppressed := d.takeHeld(time.Time{})
+	clear(d.recent)
+	d.event = ev
+	window := d.cfg.window
+	d.mu.Unlock()
+
+	m.startPlatformEvent(ev, suppressed, fleet, window)
+}
+
+// stormRecovered records that a monitor recovered and reports whether its recovery
+// notification is to be left out: its down notification was still held and is dropped,
+// or it belongs to a platform event, whose end is announced once for all monitors.
+func (m *Manager) stormRecovered(monitorID string) bool {
+	d := m.storm
+	d.mu.Lock()
+	delete(d.recent, monitorID)
+	if _, ok := d.held[monitorID]; ok {
+		delete(d.held, monitorID)
+		d.mu.Unlock()
+		return true
+	}
+	ev := d.event
+	if ev == nil || !ev.monitors[monitorID] {
+		d.mu.Unlock()
+		return false
+	}
+	ev.monitors[monitorID] = false
+	ended := d.endIfRecovered()
+	d.mu.Unlock()
+
+	if ended != nil {
+		m.endPlatformEvent(ended)
+	}
+	return true
+}
+
+// endIfRecovered clears the platform event once none of its monitors is down and
+// returns it. Callers hold d.mu.
+func (d *stormDetector) endIfRecovered() *platformEvent {
+	for _, down := range d.event.monitors {
+		if down {
+			return nil
+		}
+	}
+	ev := d.event
+	d.event = nil
+	return ev
+}
+
+// takeHeld removes and returns the held notifications that were held at or before
+// cutoff, oldest first; the zero cutoff takes all of them. Callers hold d.mu.
+func (d *stormDetector) takeHeld(cutoff time.Time) []notifications.NotificationEvent {
+	var due []heldDown
+	for id, h := range d.held {
+		if cutoff.IsZero() || !h.at.After(cutoff) {
+			due = append(due, h)
+			delete(d.held, id)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	events := make([]notifications.NotificationEvent, len(due))
+	for i, h := range due {
+		events[i] = h.event
+	}
+	return events
+}
+
+func (m *Manager) sendHeld(events []notifications.NotificationEvent) {
+	for _, event := range events {
+		m.enqueueOrDigest(event)
+	}
+}
+
+// stormWorker sends held down notifications once they waited out the window without a
+// storm, and lets a platform event end when its remaining monitors were removed or
+// paused rather than recovering.
+func (m *Manager) stormWorker() {
+	m.wg.Add(1)
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(stormReleaseInterval)
+	defer ticker.Stop()
+
+	d := m.storm
+	for {
+		select {
+		case <-m.stopCh:
+			// Don't lose notifications on shutdown
+			d.mu.Lock()
+			release := d.takeHeld(time.Time{})
+			d.mu.Unlock()
+			m.sendHeld(release)
+			return
+		case now := <-ticker.C:
+			m.stormTick(now)
+		}
+	}
+}
+
+// stormTick sends the notifications held since before the window and ends a platform
+// event none of whose running monitors is down.
+func (m *Manager) stormTick(now time.Time) {
+	running := m.GetAll()
+
+	d := m.storm
+	d.mu.Lock()
+	release := d.takeHeld(now.Add(-d.cfg.window))
+	var ended *platformEvent
+	if d.event != nil {
+		for id, down := range d.event.monitors {
+			if _, ok := running[id]; !ok && down {
+				d.event.monitors[id] = false
+			}
+		}
+		ended = d.endIfRecovered()
+	}
+	d.mu.Unlock()
+
+	m.sendHeld(release)
+	if ended != nil {
+		m.endPlatformEvent(ended)
+	}
+}
+
+// startPlatformEvent opens an incident for the storm's monitors and sends one summary
+// instead of their individual down notifications. The incident starts private, waiting
+// for a publication decision like other automatic incidents.
+func (m *Manager) startPlatformEvent(ev *platformEvent, suppressed []notifications.NotificationEvent, fleet int, window time.Duration) {
+	names := make([]string, 0, len(suppressed))
+	for _, e := range suppressed {
+		names = append(names, e.MonitorName)
+	}
+	groups := make(map[string]bool)
+	for id := range ev.monitors {
+		if mon := m.GetMonitor(id); mon != nil {
+			groups[mon.GetGroupID()] = true
+		}
+	}
+	groupIDs := make([]string, 0, len(groups))
+	for g := range groups {
+		groupIDs = append(groupIDs, g)
+	}
+	sort.Strings(groupIDs)
+	groupsJSON, _ := json.Marshal(groupIDs)
+
+	message := fmt.Sprintf("%d of %d monitors went down within %s: %s", len(ev.monitors), fleet, window, summarizeNames(names))
+	incident := db.Incident{
+		ID:                 ev.incidentID,
+		Title:              "Platform event: " + strconv.Itoa(len(ev.monitors)) + " monitors down",
+		Description:        message,
+		Type:               "incident",
+		Severity:           "major",
+		Status:             "investigating",
+		StartTime:          ev.started.UTC(),
+		AffectedGroups:     string(groupsJSON),
+		Source:             "auto",
+		PendingPublication: true,
+	}
+	if err := m.store.CreateIncident(incident); err != nil {
+		log.Printf("Storm: failed to create platform event incident: %v", err)
+	}
+
+	m.enqueueOrDigest(notifications.NotificationEvent{
+		MonitorName: strconv.Itoa(len(ev.monitors)) + " monitors",
+		Type:        notifications.EventPlatformDown,
+		Message:     message,
+		Time:        ev.started,
+		DedupKey:    "platform_down:" + incident.ID,
+	})
+	log.Printf("Storm: %d of %d monitors DOWN within %s, platform event %s opened", len(ev.monitors), fleet, window, incident.ID)
+}
+
+// endPlatformEvent resolves the storm's incident and announces the recovery once.
+func (m *Manager) endPlatformEvent(ev *platformEvent) {
+	now := time.Now()
+	message := fmt.Sprintf("All %d monitors of the platform event recovered after %s", len(ev.monitors), now.Sub(ev.started).Round(time.Second))
+
+	// Leave the incident alone if someone already resolved it
+	if inc, err := m.store.GetIncidentByID(ev.incidentID); err == nil && inc != nil && inc.Status != "resolved" {
+		inc.Status = "resolved"
+		end := now.UTC()
+		inc.EndTime = &end
+		if err := m.store.UpdateIncident(*inc); err != nil {
+			log.Printf("Storm: failed to resolve platform event %s: %v", ev.incidentID, err)
+		}
+		_ = m.store.CreateIncidentUpdate(ev.incidentID, "resolved", message)
+	}
+
+	m.enqueueOrDigest(notifications.NotificationEvent{
+		MonitorName: strconv.Itoa(len(ev.monitors)) + " monitors",
+		Type:        notifications.EventPlatformRecovered,
+		Message:     message,
+		Time:        now,
+		DedupKey:    "platform_recovered:" + ev.incidentID,
+	})
+	log.Printf("Storm: platform event %s RECOVERED", ev.incidentID)
+}
+
+// summarizeNames lists the first monitor names and counts the rest.
+func summarizeNames(names []string) string {
+	sort.Strings(names)
+	if len(names) <= stormSummaryNames {
+		return strings.Join(names, ", ")
+	}
+	return strings.Join(names[:stormSummaryNames], ", ") + " and " + strconv.Itoa(len(names)-stormSummaryNames) + " more"
+}
+
+func generatePlatformEventID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "inc-platform-" + time.Now().Format("20060102150405")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,187 @@
+package uptime
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+	"github.com/projecthelena/warden/internal/notifications"
+)
+
+// newStormManager returns a manager running n monitors, with a notification channel so
+// notifications are queued, and storm dampening configured by settings.
+func newStormManager(t *testing.T, n int, settings map[string]string) (*Manager, *db.Store) {
+	t.Helper()
+	store, err := db.NewStore(db.NewTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create test store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.CreateNotificationChannel(db.NotificationChannel{
+		ID: "nc-storm", Type: "webhook", Name: "Storm", Config: `{"webhookUrl":"http://127.0.0.1:1"}`, Enabled: true,
+	}); err != nil {
+		t.Fatalf("CreateNotificationChannel failed: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := store.CreateMonitor(db.Monitor{
+			ID: fmt.Sprintf("m-storm-%d", i), GroupID: "g-default", Name: fmt.Sprintf("Storm %d", i),
+			URL: "http://example.com", Active: true, Interval: 60,
+		}); err != nil {
+			t.Fatalf("CreateMonitor failed: %v", err)
+		}
+	}
+	if err := store.SetSettings(settings); err != nil {
+		t.Fatalf("SetSettings failed: %v", err)
+	}
+	m := NewManager(store)
+	m.Sync()
+	return m, store
+}
+
+func downEvent(i int) notifications.NotificationEvent {
+	return notifications.NotificationEvent{
+		MonitorID:   fmt.Sprintf("m-storm-%d", i),
+		MonitorName: fmt.Sprintf("Storm %d", i),
+		Type:        notifications.EventDown,
+		Message:     "Monitor is down",
+		Time:        time.Now(),
+	}
+}
+
+// queuedTypes takes every queued notification off the queue and returns their types.
+func queuedTypes(t *testing.T, store *db.Store) []notifications.EventType {
+	t.Helper()
+	items, err := store.ClaimDueNotifications(time.Now().Add(time.Hour), time.Minute, 1000)
+	if err != nil {
+		t.Fatalf("ClaimDueNotifications failed: %v", err)
+	}
+	var types []notifications.EventType
+	for _, item := range items {
+		var e notifications.NotificationEvent
+		if err := json.Unmarshal([]byte(item.Payload), &e); err != nil {
+			t.Fatalf("Unreadable payload: %v", err)
+		}
+		types = append(types, e.Type)
+		_ = store.DeleteQueuedNotification(item.ID)
+	}
+	return types
+}
+
+func TestStorm_DisabledByDefault(t *testing.T) {
+	m, store := newStormManager(t, 4, nil)
+
+	for i := 0; i < 4; i++ {
+		m.notifyDown(downEvent(i))
+	}
+	if got := queuedTypes(t, store); len(got) != 4 {
+		t.Errorf("Expected every down notification sent right away, got %v", got)
+	}
+}
+
+func TestStorm_CollapsesMassFailure(t *testing.T) {
+	m, store := newStormManager(t, 6, map[string]string{
+		stormThresholdSettingKey: "50",
+		stormMinSettingKey:       "3",
+	})
+
+	m.notifyDown(downEvent(0))
+	m.notifyDown(downEvent(1))
+	if got := queuedTypes(t, store); len(got) != 0 {
+		t.Fatalf("Expected down notifications held during the window, got %v", got)
+	}
+
+	// Half the fleet: one platform event instead of three notifications
+	m.notifyDown(downEvent(2))
+	if got := queuedTypes(t, store); len(got) != 1 || got[0] != notifications.EventPlatformDown {
+		t.Fatalf("Expected a single platform event notification, got %v", got)
+	}
+	m.storm.mu.Lock()
+	incidentID := m.storm.event.incidentID
+	m.storm.mu.Unlock()
+	inc, err := store.GetIncidentByID(incidentID)
+	if err != nil || inc == nil {
+		t.Fatalf("Expected the platform event incident, got %v", err)
+	}
+	if inc.Source != "auto" || inc.Status != "investigating" || inc.Public || !inc.PendingPublication {
+		t.Errorf("Expected a private automatic incident awaiting publication, got %+v", inc)
+	}
+
+	// Later failures join the event silently
+	m.notifyDown(downEvent(3))
+	if got := queuedTypes(t, store); len(got) != 0 {
+		t.Fatalf("Expected no notification for a monitor joining the event, got %v", got)
+	}
+
+	// Recoveries are announced once, when the last monitor is back
+	for i := 0; i < 4; i++ {
+		if !m.stormRecovered(fmt.Sprintf("m-storm-%d", i)) {
+			t.Errorf("Expected the recovery of monitor %d to be left to the platform event", i)
+		}
+	}
+	if got := queuedTypes(t, store); len(got) != 1 || got[0] != notifications.EventPlatformRecovered {
+		t.Fatalf("Expected a single recovery notification, got %v", got)
+	}
+	if inc, _ := store.GetIncidentByID(incidentID); inc == nil || inc.Status != "resolved" || inc.EndTime == nil {
+		t.Errorf("Expected the incident to be resolved, got %+v", inc)
+	}
+	if m.stormRecovered("m-storm-5") {
+		t.Error("Expected monitors outside the event to notify their recovery")
+	}
+}
+
+func TestStorm_ReleasesHeldAfterWindow(t *testing.T) {
+	m, store := newStormManager(t, 10, map[string]string{
+		stormThresholdSettingKey: "50",
+		stormWindowSettingKey:    "30",
+	})
+
+	m.notifyDown(downEvent(0))
+	m.notifyDown(downEvent(1))
+
+	m.stormTick(time.Now().Add(10 * time.Second))
+	if got := queuedTypes(t, store); len(got) != 0 {
+		t.Fatalf("Expected notifications held within the window, got %v", got)
+	}
+
+	// A recovery within the window drops both notifications
+	if !m.stormRecovered("m-storm-1") {
+		t.Error("Expected the recovery of a held monitor to go unannounced")
+	}
+
+	m.stormTick(time.Now().Add(31 * time.Second))
+	if got := queuedTypes(t, store); len(got) != 1 || got[0] != notifications.EventDown {
+		t.Fatalf("Expected the remaining down notification after the window, got %v", got)
+	}
+}
+
+func TestStorm_EndsWhenMonitorsRemoved(t *testing.T) {
+	m, store := newStormManager(t, 4, map[string]string{
+		stormThresholdSettingKey: "50",
+		stormMinSettingKey:       "2",
+	})
+	m.notifyDown(downEvent(0))
+	m.notifyDown(downEvent(1))
+	_ = queuedTypes(t, store)
+
+	m.stormRecovered("m-storm-0")
+	m.RemoveMonitor("m-storm-1")
+	m.stormTick(time.Now())
+
+	if got := queuedTypes(t, store); len(got) != 1 || got[0] != notifications.EventPlatformRecovered {
+		t.Fatalf("Expected the event to end without its removed monitor, got %v", got)
+	}
+}
+
+func TestStorm_DisablingReleasesHeld(t *testing.T) {
+	m, store := newStormManager(t, 10, map[string]string{stormThresholdSettingKey: "50"})
+	m.notifyDown(downEvent(0))
+
+	if err := store.SetSetting(stormThresholdSettingKey, "0"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	if got := queuedTypes(t, store); len(got) != 1 || got[0] != notifications.EventDown {
+		t.Fatalf("Expected the held notification sent once dampening is off, got %v", got)
+	}
+}
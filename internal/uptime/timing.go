@@ -0,0 +1,109 @@
+package uptime
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+// MaxTimedBodyBytes caps how much of a response body a check reads to measure its size
+// and download time; larger bodies are reported as this size.
+const MaxTimedBodyBytes = 10 << 20
+
+// checkTimer records when each phase of a request starts and ends. After redirects the
+// recorded phases are those of the final hop.
+type checkTimer struct {
+	mu sync.Mutex
+	p  requestPhases
+}
+
+type requestPhases struct {
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// trace returns ctx with hooks recording into t. Dials may finish on another goroutine,
+// so every hook takes the lock.
+func (t *checkTimer) trace(ctx context.Context) context.Context {
+	set := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// A new hop starts: forget the phases of a previous redirect
+			t.mu.Lock()
+			t.p = requestPhases{}
+			t.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { set(&t.p.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { set(&t.p.dnsDone) },
+		ConnectStart: func(_, _ string) {
+			// Dual-stack dials race several connects; the first start counts
+			t.mu.Lock()
+			if t.p.connectStart.IsZero() {
+				t.p.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				set(&t.p.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { set(&t.p.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&t.p.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { set(&t.p.wroteRequest) },
+		GotFirstResponseByte: func() { set(&t.p.firstByte) },
+	})
+}
+
+// timing returns the phase durations of a request whose body of bodyBytes took download
+// to read, or nil if no response was received.
+func (t *checkTimer) timing(bodyBytes int64, download time.Duration) *db.CheckTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.p.firstByte.IsZero() || t.p.wroteRequest.IsZero() {
+		return nil
+	}
+	return &db.CheckTiming{
+		DNS:       phaseMs(t.p.dnsStart, t.p.dnsDone),
+		Connect:   phaseMs(t.p.connectStart, t.p.connectDone),
+		TLS:       phaseMs(t.p.tlsStart, t.p.tlsDone),
+		TTFB:      phaseMs(t.p.wroteRequest, t.p.firstByte),
+		Download:  download.Milliseconds(),
+		BodyBytes: bodyBytes,
+	}
+}
+
+// phaseMs returns the duration of a phase in milliseconds, 0 if it did not run.
+func phaseMs(start, end time.Time) int64 {
+	if start.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
+
+// countingBody counts the bytes read from a response body.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,109 @@
+package uptime
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestRunHTTPCheckTiming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(30 * time.Millisecond)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(strings.Repeat("x", 3*MaxEvidenceBodyBytes)))
+	}))
+	defer srv.Close()
+
+	transport := &http.Transport{}
+	defer transport.CloseIdleConnections()
+
+	c := runHTTPCheck(transport, srv.URL+"/slow", nil)
+	if c.timing == nil {
+		t.Fatal("Expected timing for a received response")
+	}
+	if c.timing.TTFB < 30 || c.timing.BodyBytes != int64(3*MaxEvidenceBodyBytes) {
+		t.Errorf("Expected the server delay in TTFB and the full body size, got %+v", c.timing)
+	}
+
+	// Evidence capture reads part of the body; the size still covers all of it
+	c = runHTTPCheck(transport, srv.URL+"/error", nil)
+	if c.isUp || c.evidence == nil || c.timing == nil || c.timing.BodyBytes != int64(3*MaxEvidenceBodyBytes) {
+		t.Errorf("Expected evidence and the full body size of a failed check, got %+v", c.timing)
+	}
+
+	// The connection is reused, so no DNS, connect or TLS phase ran
+	if c.timing.DNS != 0 || c.timing.Connect != 0 || c.timing.TLS != 0 {
+		t.Errorf("Expected no connection phases on a reused connection, got %+v", c.timing)
+	}
+
+	srv.Close()
+	if c := runHTTPCheck(transport, srv.URL, nil); c.isUp || c.timing != nil {
+		t.Errorf("Expected no timing without a response, got %+v", c.timing)
+	}
+}
+
+func TestPhaseMs(t *testing.T) {
+	start := time.Now()
+	if got := phaseMs(start, start.Add(25*time.Millisecond)); got != 25 {
+		t.Errorf("Expected 25ms, got %d", got)
+	}
+	if got := phaseMs(time.Time{}, start); got != 0 {
+		t.Errorf("Expected a phase that did not run to be 0, got %d", got)
+	}
+	if got := phaseMs(start, time.Time{}); got != 0 {
+		t.Errorf("Expected an unfinished phase to be 0, got %d", got)
+	}
+}
+
+func TestCheckTransportsFreshConnection(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+	newConns := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := conns
+		conns = 0
+		return n
+	}
+
+	transports := newCheckTransports(false)
+	defer transports.pooled.CloseIdleConnections()
+
+	for i := 0; i < 3; i++ {
+		runHTTPCheck(transports.forConfig(nil), srv.URL, nil)
+	}
+	if n := newConns(); n != 1 {
+		t.Errorf("Expected pooled checks to share one connection, got %d", n)
+	}
+
+	cfg := &db.RequestConfig{FreshConnection: true}
+	for i := 0; i < 3; i++ {
+		c := runHTTPCheck(transports.forConfig(cfg), srv.URL, cfg)
+		if c.timing == nil || c.timing.BodyBytes != 0 {
+			t.Fatalf("Expected timing for a fresh connection check, got %+v", c.timing)
+		}
+	}
+	if n := newConns(); n != 3 {
+		t.Errorf("Expected a new connection per fresh check, got %d", n)
+	}
+}
@@ -0,0 +1,141 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// TracerouteTimeout bounds a single path diagnostic run.
+	TracerouteTimeout = 60 * time.Second
+	// MaxPathReportBytes caps the report stored with the outage.
+	MaxPathReportBytes = 8 * 1024
+	// maxConcurrentTraceroutes limits how many probes run at once during a wide outage.
+	maxConcurrentTraceroutes = 4
+)
+
+// errNoTracer is returned when neither mtr nor traceroute is installed.
+var errNoTracer = errors.New("neither mtr nor traceroute is installed on the server")
+
+// Tracer runs a path diagnostic against host and returns its textual report.
+type Tracer func(ctx context.Context, host string) (string, error)
+
+// systemTracer runs mtr in report mode when available and falls back to traceroute.
+// host comes from a monitor URL, so "--" keeps it from being read as an option.
+func systemTracer(ctx context.Context, host string) (string, error) {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("mtr"); err == nil {
+		cmd = exec.CommandContext(ctx, path, "--report", "--report-cycles", "3", "--no-dns", "--", host)
+	} else if path, err := exec.LookPath("traceroute"); err == nil {
+		cmd = exec.CommandContext(ctx, path, "-n", "-w", "2", "-q", "1", "-m", "20", "--", host)
+	} else {
+		return "", errNoTracer
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// lookupIP resolves trace targets (replaced by tests).
+var lookupIP = net.DefaultResolver.LookupIP
+
+// publicTraceTarget resolves host and returns the address to trace, or an error when
+// it resolves to a private network address. Tracing that address rather than host
+// keeps the tracer from resolving it again to an internal one (DNS rebinding).
+func publicTraceTarget(ctx context.Context, host string) (string, error) {
+	ips, err := lookupIP(ctx, "ip", host)
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("no addresses for %s", host)
+	}
+	for _, ip := range ips {
+		if IsPrivateTarget(ip) {
+			return "", fmt.Errorf("%w: %s", ErrPrivateTarget, ip)
+		}
+	}
+	return ips[0].String(), nil
+}
+
+// tracerouteTarget extracts the host to probe from a monitor URL. Hosts starting with
+// a hyphen are refused, as they aren't valid and would look like options to the tracer.
+func tracerouteTarget(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || strings.HasPrefix(u.Hostname(), "-") {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// SetTracer replaces the path diagnostic runner (used by tests).
+func (m *Manager) SetTracer(t Tracer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracer = t
+}
+
+// maybeTraceroute runs a path diagnostic once per down streak after the configured
+// number of failed checks, and attaches the report to the monitor's active outage.
+// While private targets are blocked, hosts resolving to one are not traced, so the
+// report can't map internal networks.
+func (m *Manager) maybeTraceroute(mon *Monitor, res CheckResult) {
+	m.mu.RLock()
+	afterChecks := m.tracerouteAfterChecks
+	tracer := m.tracer
+	m.mu.RUnlock()
+
+	if afterChecks <= 0 || mon.IsExternal() {
+		return
+	}
+	host := tracerouteTarget(res.URL)
+	if host == "" || !mon.ShouldTraceroute(afterChecks) {
+		return
+	}
+
+	select {
+	case m.tracerouteSlots <- struct{}{}:
+	default:
+		// Retry on the next failed check rather than skip this down streak
+		mon.retryTraceroute()
+		log.Printf("Deferring traceroute for monitor %s: too many diagnostics running", res.MonitorID)
+		return
+	}
+
+	go func() {
+		defer func() { <-m.tracerouteSlots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), TracerouteTimeout)
+		defer cancel()
+
+		target := host
+		if m.BlocksPrivateTargets() {
+			addr, err := publicTraceTarget(ctx, host)
+			if err != nil {
+				log.Printf("Skipping traceroute for monitor %s: %v", res.MonitorID, err)
+				return
+			}
+			target = addr
+		}
+
+		report, err := tracer(ctx, target)
+		report = strings.TrimSpace(report)
+		if err != nil {
+			// Keep partial output (e.g. a timed out trace), followed by the reason it stopped
+			report = strings.TrimSpace(report + "\n\ntraceroute to " + host + " failed: " + err.Error())
+		}
+		if len(report) > MaxPathReportBytes {
+			report = strings.ToValidUTF8(report[:MaxPathReportBytes], "") + "\n... (truncated)"
+		}
+
+		if err := m.store.SetOutagePathReport(res.MonitorID, report); err != nil {
+			log.Printf("Failed to store traceroute for monitor %s: %v", res.MonitorID, err)
+		}
+	}()
+}
@@ -0,0 +1,187 @@
+package uptime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestTracerouteTarget(t *testing.T) {
+	cases := map[string]string{
+		"https://api.example.com:8443/health": "api.example.com",
+		"http://10.0.0.5/":                    "10.0.0.5",
+		"http://[::1]:8080":                   "::1",
+		"http://-oProxyCommand=x/":            "",
+		"":                                    "",
+	}
+	for in, want := range cases {
+		if got := tracerouteTarget(in); got != want {
+			t.Errorf("tracerouteTarget(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMonitor_ShouldTraceroute(t *testing.T) {
+	m := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+
+	m.IncrementDown()
+	if m.ShouldTraceroute(2) {
+		t.Error("Expected no trace before the configured number of failures")
+	}
+	m.IncrementDown()
+	if m.ShouldTraceroute(0) {
+		t.Error("Expected 0 to disable traces")
+	}
+	if !m.ShouldTraceroute(2) {
+		t.Error("Expected a trace after 2 failures")
+	}
+	m.IncrementDown()
+	if m.ShouldTraceroute(2) {
+		t.Error("Expected only one trace per down streak")
+	}
+
+	m.ResetDown()
+	m.IncrementDown()
+	m.IncrementDown()
+	if !m.ShouldTraceroute(2) {
+		t.Error("Expected a new trace after recovery and a new down streak")
+	}
+}
+
+func TestManager_TracerouteOnSustainedFailure(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:trace%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	setIntegrationTestDefaults(store)
+	_ = store.SetSetting("diagnostics.traceroute_after_checks", "2")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	if err := store.CreateMonitor(db.Monitor{ID: "m-trace", GroupID: "g-default", Name: "Trace", URL: ts.URL, Active: true, Interval: 1}); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	var calls atomic.Int32
+	m := NewManager(store)
+	m.SetTracer(func(ctx context.Context, host string) (string, error) {
+		calls.Add(1)
+		return " 1. " + host + "  0.0%  0.1ms\n", nil
+	})
+	m.Start()
+	defer m.Stop()
+
+	deadline := time.Now().Add(8 * time.Second)
+	for time.Now().Before(deadline) {
+		outages, _ := store.GetActiveOutages()
+		if len(outages) == 1 {
+			o, err := store.GetOutageByID(outages[0].ID)
+			if err != nil {
+				t.Fatalf("GetOutageByID failed: %v", err)
+			}
+			if o.PathReport != "" {
+				if !strings.Contains(o.PathReport, "127.0.0.1") {
+					t.Errorf("Unexpected path report: %q", o.PathReport)
+				}
+				// Let another failing check go through; the trace must not repeat
+				time.Sleep(1500 * time.Millisecond)
+				if n := calls.Load(); n != 1 {
+					t.Errorf("Expected a single traceroute per outage, got %d", n)
+				}
+				return
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("Path report was never attached to the outage")
+}
+
+func TestManager_TracerouteSkipsPrivateTargets(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.SetBlockPrivateTargets(true)
+	m.tracerouteAfterChecks = 1
+	var traced atomic.Value
+	m.SetTracer(func(ctx context.Context, host string) (string, error) {
+		traced.Store(host)
+		return "", nil
+	})
+
+	// A public name resolving to cloud metadata, as with DNS rebinding
+	lookupIP = func(ctx context.Context, network, host string) ([]net.IP, error) {
+		switch host {
+		case "rebind.example.com":
+			return []net.IP{net.ParseIP("93.184.216.34"), net.ParseIP("169.254.169.254")}, nil
+		case "public.example.com":
+			return []net.IP{net.ParseIP("93.184.216.34")}, nil
+		}
+		return net.DefaultResolver.LookupIP(ctx, network, host)
+	}
+	defer func() { lookupIP = net.DefaultResolver.LookupIP }()
+
+	trace := func(url string) string {
+		t.Helper()
+		traced.Store("")
+		mon := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+		mon.IncrementDown()
+		m.maybeTraceroute(mon, CheckResult{MonitorID: "m-1", URL: url})
+		// The slot is released once the diagnostic finished or was skipped
+		deadline := time.Now().Add(2 * time.Second)
+		for len(m.tracerouteSlots) > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return traced.Load().(string)
+	}
+
+	for _, url := range []string{"http://10.0.0.5/", "http://127.0.0.1:8080", "https://rebind.example.com/health"} {
+		if host := trace(url); host != "" {
+			t.Errorf("%s: expected no trace with private targets blocked, traced %q", url, host)
+		}
+	}
+	// Public hosts are traced at the address they were checked against
+	if host := trace("https://public.example.com/health"); host != "93.184.216.34" {
+		t.Errorf("Expected the resolved public address to be traced, got %q", host)
+	}
+}
+
+func TestManager_TracerouteRetriedWhenSlotsBusy(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.tracerouteAfterChecks = 1
+	var calls atomic.Int32
+	m.SetTracer(func(ctx context.Context, host string) (string, error) {
+		calls.Add(1)
+		return "", nil
+	})
+	mon := newTestMonitorWithConfig(MonitorConfig{ConfirmationThreshold: 1})
+	mon.IncrementDown()
+	res := CheckResult{MonitorID: "m-1", URL: "http://93.184.216.34/"}
+
+	// Every slot is taken during a wide outage
+	for range maxConcurrentTraceroutes {
+		m.tracerouteSlots <- struct{}{}
+	}
+	m.maybeTraceroute(mon, res)
+	for range maxConcurrentTraceroutes {
+		<-m.tracerouteSlots
+	}
+
+	mon.IncrementDown()
+	m.maybeTraceroute(mon, res)
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected the deferred trace to run on the next failed check, got %d runs", n)
+	}
+}
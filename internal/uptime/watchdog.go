@@ -0,0 +1,96 @@
+package uptime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Watchdog settings. While WatchdogURLKey is set, the leader pings it every
+// WatchdogIntervalKey seconds, so a dead man's switch such as Healthchecks.io alerts
+// when Warden itself stops.
+const (
+	WatchdogURLKey      = "watchdog.url"
+	WatchdogIntervalKey = "watchdog.interval_seconds"
+
+	DefaultWatchdogInterval = 60 * time.Second
+	MinWatchdogInterval     = 10 * time.Second
+
+	watchdogTimeout = 10 * time.Second
+)
+
+// watchdogInterval is the configured time between pings.
+func (m *Manager) watchdogInterval() time.Duration {
+	if val, err := m.store.GetSetting(WatchdogIntervalKey); err == nil {
+		if i, err := strconv.Atoi(val); err == nil && time.Duration(i)*time.Second >= MinWatchdogInterval {
+			return time.Duration(i) * time.Second
+		}
+	}
+	return DefaultWatchdogInterval
+}
+
+// pingWatchdog pings the watchdog URL, if one is set and this instance is the leader.
+// Failures are logged when they start and when they stop, not on every ping.
+func (m *Manager) pingWatchdog() {
+	target, _ := m.store.GetSetting(WatchdogURLKey)
+	if target == "" || !m.IsLeader() {
+		return
+	}
+
+	err := m.sendWatchdogPing(target)
+	switch wasFailing := m.watchdogFailing.Swap(err != nil); {
+	case err != nil && !wasFailing:
+		log.Printf("Watchdog: ping failed: %v", err)
+	case err == nil && wasFailing:
+		log.Printf("Watchdog: ping succeeded again")
+	}
+}
+
+func (m *Manager) sendWatchdogPing(target string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), watchdogTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Warden-Watchdog")
+
+	transport := m.checkTransport
+	if transport == nil {
+		transport = &http.Transport{DialContext: checkDialer(m.BlocksPrivateTargets()), DisableKeepAlives: true}
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req) // #nosec G704 -- URL from admin settings
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// watchdogWorker pings the watchdog URL every interval, and right away when the
+// watchdog settings change so a new URL is tried at once.
+func (m *Manager) watchdogWorker() {
+	m.wg.Add(1)
+	defer m.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-timer.C:
+		case <-m.watchdogWake:
+		}
+		m.pingWatchdog()
+		timer.Reset(m.watchdogInterval())
+	}
+}
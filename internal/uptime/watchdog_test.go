@@ -0,0 +1,81 @@
+package uptime
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/projecthelena/warden/internal/db"
+)
+
+func TestManager_PingWatchdog(t *testing.T) {
+	store, err := db.NewStore(db.NewTestConfigWithPath(fmt.Sprintf("file:watchdog%d?mode=memory&cache=shared", testDBCounter.Add(1))))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	var pings atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	snitch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+		if r.Method != http.MethodGet || r.Header.Get("User-Agent") != "Warden-Watchdog" {
+			t.Errorf("Unexpected ping %s %q", r.Method, r.Header.Get("User-Agent"))
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer snitch.Close()
+
+	m := NewManager(store)
+
+	// Disabled until a URL is set
+	m.pingWatchdog()
+	if pings.Load() != 0 {
+		t.Fatal("Expected no ping without a watchdog URL")
+	}
+
+	_ = store.SetSetting(WatchdogURLKey, snitch.URL)
+	m.pingWatchdog()
+	if pings.Load() != 1 || m.watchdogFailing.Load() {
+		t.Fatalf("Expected a successful ping, got %d pings", pings.Load())
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	m.pingWatchdog()
+	if !m.watchdogFailing.Load() {
+		t.Error("Expected a non-2xx response to count as a failure")
+	}
+	status.Store(http.StatusOK)
+	m.pingWatchdog()
+	if m.watchdogFailing.Load() {
+		t.Error("Expected the failure to clear")
+	}
+
+	// Only the leader vouches for the cluster
+	m.SetStandby(true)
+	m.pingWatchdog()
+	if pings.Load() != 3 {
+		t.Errorf("Expected a standby not to ping, got %d pings", pings.Load())
+	}
+}
+
+func TestManager_WatchdogInterval(t *testing.T) {
+	m, store := newTestManager(t)
+
+	_ = store.SetSetting(WatchdogIntervalKey, "")
+	if got := m.watchdogInterval(); got != DefaultWatchdogInterval {
+		t.Errorf("Expected the default interval, got %v", got)
+	}
+	_ = store.SetSetting(WatchdogIntervalKey, "300")
+	if got := m.watchdogInterval(); got != 5*time.Minute {
+		t.Errorf("Expected 5m, got %v", got)
+	}
+	_ = store.SetSetting(WatchdogIntervalKey, "1")
+	if got := m.watchdogInterval(); got != DefaultWatchdogInterval {
+		t.Errorf("Expected too short an interval to be ignored, got %v", got)
+	}
+}
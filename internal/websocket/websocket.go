@@ -0,0 +1,357 @@
+// Package websocket is a minimal server-side WebSocket (RFC 6455) implementation: the
+// opening handshake, framing, fragmentation and control frames. Extensions such as
+// permessage-deflate and subprotocols are not negotiated.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1" // #nosec G505 -- SHA-1 is mandated by RFC 6455 for Sec-WebSocket-Accept
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message types (frame opcodes).
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+
+	continuationFrame = 0
+)
+
+// Close codes used by this package and its callers.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+)
+
+const (
+	acceptGUID        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	defaultReadLimit  = 1 << 20
+	maxControlPayload = 125
+	closeWriteTimeout = 5 * time.Second
+)
+
+// ErrReadLimit is returned when a message exceeds the connection's read limit.
+var ErrReadLimit = errors.New("websocket: message exceeds read limit")
+
+// CloseError is returned by ReadMessage once the peer has closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed by peer (%d %s)", e.Code, e.Reason)
+}
+
+// Conn is a server-side WebSocket connection. One goroutine may read while others
+// write; writes are serialized internally.
+type Conn struct {
+	conn      net.Conn
+	br        *bufio.Reader
+	readLimit int64
+	onPong    func()
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+// Upgrade performs the opening handshake and takes over the connection. On failure it
+// has already written an HTTP error response.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "websocket: method not allowed", http.StatusMethodNotAllowed)
+		return nil, errors.New("websocket: method not allowed")
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket: upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "websocket: unsupported version", http.StatusBadRequest)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "websocket: invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: invalid key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket: connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response writer does not support hijacking")
+	}
+	netConn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// Headers set by middleware (e.g. X-Request-ID) are carried over to the 101 response
+	var b strings.Builder
+	b.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	b.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n")
+	for name, values := range w.Header() {
+		if name == "Content-Type" || name == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			b.WriteString(name + ": " + v + "\r\n")
+		}
+	}
+	b.WriteString("\r\n")
+	if _, err := netConn.Write([]byte(b.String())); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: netConn, br: rw.Reader, readLimit: defaultReadLimit}, nil
+}
+
+func acceptKey(key string) string {
+	h := sha1.New() // #nosec G401 -- required by the protocol, not used for security
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetReadLimit caps the size of a (reassembled) message. The default is 1 MiB.
+func (c *Conn) SetReadLimit(n int64) { c.readLimit = n }
+
+// SetReadDeadline sets the deadline for the next reads; see net.Conn.
+func (c *Conn) SetReadDeadline(t time.Time) error { return c.conn.SetReadDeadline(t) }
+
+// SetPongHandler registers f to run, on the reading goroutine, whenever a pong arrives.
+func (c *Conn) SetPongHandler(f func()) { c.onPong = f }
+
+// RemoteAddr returns the peer's network address.
+func (c *Conn) RemoteAddr() net.Addr { return c.conn.RemoteAddr() }
+
+// ReadMessage returns the next text or binary message. Pings are answered and pongs
+// handed to the pong handler along the way. After the peer closes the connection it
+// returns a *CloseError.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		msgType int
+		msg     []byte
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			if errors.Is(err, ErrReadLimit) {
+				_ = c.Close(CloseMessageTooBig, "message too big")
+			} else if _, ok := err.(*protocolError); ok {
+				_ = c.Close(CloseProtocolError, err.Error())
+			}
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			if c.onPong != nil {
+				c.onPong()
+			}
+			continue
+		case CloseMessage:
+			ce := &CloseError{Code: 1005} // no status received
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			_ = c.Close(CloseNormal, "")
+			return 0, nil, ce
+		case TextMessage, BinaryMessage:
+			if msgType != 0 {
+				_ = c.Close(CloseProtocolError, "expected continuation frame")
+				return 0, nil, &protocolError{"expected continuation frame"}
+			}
+			msgType = opcode
+		case continuationFrame:
+			if msgType == 0 {
+				_ = c.Close(CloseProtocolError, "unexpected continuation frame")
+				return 0, nil, &protocolError{"unexpected continuation frame"}
+			}
+		default:
+			_ = c.Close(CloseProtocolError, "unknown opcode")
+			return 0, nil, &protocolError{fmt.Sprintf("unknown opcode %d", opcode)}
+		}
+
+		if int64(len(msg)+len(payload)) > c.readLimit {
+			_ = c.Close(CloseMessageTooBig, "message too big")
+			return 0, nil, ErrReadLimit
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msgType, msg, nil
+		}
+	}
+}
+
+// ReadJSON reads the next message and decodes it into v.
+func (c *Conn) ReadJSON(v any) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type protocolError struct{ msg string }
+
+func (e *protocolError) Error() string { return "websocket: protocol error: " + e.msg }
+
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, &protocolError{"reserved bits set"}
+	}
+	opcode = int(head[0] & 0x0f)
+	if head[1]&0x80 == 0 {
+		// RFC 6455 5.1: clients must mask every frame
+		return false, 0, nil, &protocolError{"unmasked client frame"}
+	}
+
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if opcode >= CloseMessage && (length > maxControlPayload || !fin) {
+		return false, 0, nil, &protocolError{"invalid control frame"}
+	}
+	if length > uint64(c.readLimit) {
+		return false, 0, nil, ErrReadLimit
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends data as a single frame of the given message type.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage && messageType != PingMessage && messageType != PongMessage {
+		return fmt.Errorf("websocket: cannot write message type %d", messageType)
+	}
+	return c.writeFrame(messageType, data)
+}
+
+// WriteJSON encodes v and sends it as a text message.
+func (c *Conn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(TextMessage, data)
+}
+
+// SetWriteDeadline sets the deadline for future writes; see net.Conn.
+func (c *Conn) SetWriteDeadline(t time.Time) error { return c.conn.SetWriteDeadline(t) }
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	return c.writeFrameLocked(opcode, payload)
+}
+
+func (c *Conn) writeFrameLocked(opcode int, payload []byte) error {
+	if opcode >= CloseMessage && len(payload) > maxControlPayload {
+		return errors.New("websocket: control frame payload too large")
+	}
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | byte(opcode) // FIN; server frames are never fragmented or masked
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close sends a close frame with the given code and reason, then closes the connection.
+// Calling it more than once is safe.
+func (c *Conn) Close(code int, reason string) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	if len(reason) > maxControlPayload-2 {
+		reason = reason[:maxControlPayload-2]
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	_ = c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+	_ = c.writeFrameLocked(CloseMessage, payload)
+	return c.conn.Close()
+}
@@ -0,0 +1,210 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClient speaks just enough of the client side of RFC 6455 to exercise the server.
+type testClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dial(t *testing.T, srv *httptest.Server) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + key + "\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101, got %d", resp.StatusCode)
+	}
+	// Example from RFC 6455 section 1.3
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected Sec-WebSocket-Accept %q", got)
+	}
+	return &testClient{conn: conn, br: br}
+}
+
+func (c *testClient) send(t *testing.T, fin bool, opcode int, payload []byte) {
+	t.Helper()
+	b0 := byte(opcode)
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testClient) recv(t *testing.T) (int, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	if head[1]&0x80 != 0 {
+		t.Fatal("Server frames must not be masked")
+	}
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		_, _ = io.ReadFull(c.br, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, _ = io.ReadFull(c.br, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return int(head[0] & 0x0f), payload
+}
+
+// echoServer echoes every message back and reports the error that ended the read loop.
+func echoServer(t *testing.T, limit int64) (*httptest.Server, chan error) {
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		if limit > 0 {
+			conn.SetReadLimit(limit)
+		}
+		for {
+			typ, data, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := conn.WriteMessage(typ, data); err != nil {
+				done <- err
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, done
+}
+
+func TestEchoAndFragmentation(t *testing.T) {
+	srv, done := echoServer(t, 0)
+	c := dial(t, srv)
+
+	c.send(t, true, TextMessage, []byte("hello"))
+	if typ, data := c.recv(t); typ != TextMessage || string(data) != "hello" {
+		t.Fatalf("Expected echo of hello, got %d %q", typ, data)
+	}
+
+	// A ping interleaved with a fragmented message is answered first
+	c.send(t, false, TextMessage, []byte("frag"))
+	c.send(t, true, PingMessage, []byte("p"))
+	c.send(t, true, continuationFrame, []byte("mented"))
+	if typ, data := c.recv(t); typ != PongMessage || string(data) != "p" {
+		t.Fatalf("Expected pong, got %d %q", typ, data)
+	}
+	if typ, data := c.recv(t); typ != TextMessage || string(data) != "fragmented" {
+		t.Fatalf("Expected reassembled message, got %d %q", typ, data)
+	}
+
+	big := []byte(strings.Repeat("x", 70000))
+	c.send(t, true, BinaryMessage, big)
+	if typ, data := c.recv(t); typ != BinaryMessage || len(data) != len(big) {
+		t.Fatalf("Expected %d byte echo, got %d %d", len(big), typ, len(data))
+	}
+
+	c.send(t, true, CloseMessage, binary.BigEndian.AppendUint16(nil, CloseNormal))
+	if typ, _ := c.recv(t); typ != CloseMessage {
+		t.Fatalf("Expected close frame, got %d", typ)
+	}
+	var ce *CloseError
+	if err := <-done; !errors.As(err, &ce) || ce.Code != CloseNormal {
+		t.Fatalf("Expected CloseError 1000, got %v", err)
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	srv, done := echoServer(t, 10)
+	c := dial(t, srv)
+
+	c.send(t, true, TextMessage, []byte("way more than ten bytes"))
+	typ, payload := c.recv(t)
+	if typ != CloseMessage || binary.BigEndian.Uint16(payload) != CloseMessageTooBig {
+		t.Fatalf("Expected close 1009, got %d %v", typ, payload)
+	}
+	if err := <-done; !errors.Is(err, ErrReadLimit) {
+		t.Fatalf("Expected ErrReadLimit, got %v", err)
+	}
+}
+
+func TestUnmaskedFrameRejected(t *testing.T) {
+	srv, done := echoServer(t, 0)
+	c := dial(t, srv)
+
+	if _, err := c.conn.Write([]byte{0x81, 0x02, 'h', 'i'}); err != nil {
+		t.Fatal(err)
+	}
+	typ, payload := c.recv(t)
+	if typ != CloseMessage || binary.BigEndian.Uint16(payload) != CloseProtocolError {
+		t.Fatalf("Expected close 1002, got %d %v", typ, payload)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("Expected a protocol error")
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	srv, _ := echoServer(t, 0)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("Expected 426, got %d", resp.StatusCode)
+	}
+}
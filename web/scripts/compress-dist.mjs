@@ -0,0 +1,43 @@
+import { readdirSync, readFileSync, statSync, writeFileSync } from "node:fs";
+import { extname, join, resolve } from "node:path";
+import { brotliCompressSync, constants, gzipSync } from "node:zlib";
+
+// Writes .br and .gz siblings of the compressible build output, which the Go server
+// sends to clients that accept them instead of compressing on every request.
+const distDir = resolve(process.cwd(), "dist");
+const compressible = new Set([".html", ".js", ".mjs", ".css", ".json", ".svg", ".txt", ".xml", ".webmanifest"]);
+const minSize = 1024;
+
+function* walk(dir) {
+  for (const entry of readdirSync(dir)) {
+    const path = join(dir, entry);
+    if (statSync(path).isDirectory()) {
+      yield* walk(path);
+    } else {
+      yield path;
+    }
+  }
+}
+
+let written = 0;
+for (const file of walk(distDir)) {
+  if (!compressible.has(extname(file))) {
+    continue;
+  }
+  const data = readFileSync(file);
+  if (data.length < minSize) {
+    continue;
+  }
+  const variants = [
+    [".br", brotliCompressSync(data, { params: { [constants.BROTLI_PARAM_QUALITY]: 11 } })],
+    [".gz", gzipSync(data, { level: 9 })],
+  ];
+  for (const [ext, compressed] of variants) {
+    if (compressed.length < data.length) {
+      writeFileSync(file + ext, compressed);
+      written++;
+    }
+  }
+}
+
+console.log(`Wrote ${written} precompressed assets to ${distDir}`);
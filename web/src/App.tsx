@@ -367,6 +367,11 @@ function AdminLayout() {
           </header>
           <ScrollArea className="flex-1 p-4 pt-0 h-[calc(100vh-4rem)]">
             <main className="max-w-5xl mx-auto space-y-6 py-6">
+              {user.readOnlyNotice && (
+                <div className="rounded-lg border border-yellow-500/50 bg-yellow-500/10 px-4 py-3 text-sm text-yellow-700 dark:text-yellow-400">
+                  {user.readOnlyNotice}
+                </div>
+              )}
               <Routes>
                 <Route path="/dashboard" element={<Dashboard />} />
                 <Route path="/groups/:groupId" element={<Dashboard />} />
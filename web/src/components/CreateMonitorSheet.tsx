@@ -26,7 +26,7 @@ import {
     SelectValue,
 } from "@/components/ui/select";
 import { Group, RequestConfig } from "@/lib/store";
-import { useCreateGroupMutation, useCreateMonitorMutation } from "@/hooks/useMonitors";
+import { DuplicateMonitor, DuplicateMonitorError, MonitorPreview, useCreateGroupMutation, useCreateMonitorMutation, usePreviewMonitorMutation } from "@/hooks/useMonitors";
 import { useToast } from "@/components/ui/use-toast";
 
 interface CreateMonitorSheetProps {
@@ -66,14 +66,22 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
     const [requestTimeout, setRequestTimeout] = useState<string>("");
     const [retryCount, setRetryCount] = useState("0");
     const [followRedirects, setFollowRedirects] = useState(true);
+    const [freshConnection, setFreshConnection] = useState(false);
     const [acceptedCodes, setAcceptedCodes] = useState("");
     const [customHeaders, setCustomHeaders] = useState<{ key: string; value: string }[]>([]);
+    const [expectedHeaders, setExpectedHeaders] = useState<{ name: string; value: string }[]>([]);
     const [requestBody, setRequestBody] = useState("");
 
     const [open, setOpen] = useState(false);
+    // Set when the server reports the URL is already monitored; submitting again creates it anyway
+    const [duplicateOf, setDuplicateOf] = useState<DuplicateMonitor | null>(null);
+
+    // Result of the last "Test" check; cleared when the URL changes
+    const [preview, setPreview] = useState<MonitorPreview | null>(null);
 
     const createGroup = useCreateGroupMutation();
     const createMonitor = useCreateMonitorMutation();
+    const previewMonitor = usePreviewMonitorMutation();
     const { toast } = useToast();
     const navigate = useNavigate();
 
@@ -82,6 +90,11 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
         if (urlError) setUrlError(false);
     }, [url, urlError]);
 
+    useEffect(() => {
+        setDuplicateOf(null);
+        setPreview(null);
+    }, [url]);
+
     useEffect(() => {
         if (nameError) setNameError(false);
     }, [name, nameError]);
@@ -90,6 +103,53 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
         if (groupError) setGroupError(false);
     }, [selectedGroupId, newGroupName, isNewGroup, groupError]);
 
+    // Build request config if any fields are non-default
+    const buildRequestConfig = (): RequestConfig | undefined => {
+        const headers: Record<string, string> = {};
+        for (const h of customHeaders) {
+            if (h.key.trim()) headers[h.key.trim()] = h.value.trim();
+        }
+        const assertions = expectedHeaders
+            .filter((h) => h.name.trim())
+            .map((h) => (h.value.trim() ? { name: h.name.trim(), value: h.value.trim() } : { name: h.name.trim() }));
+        const hasConfig = httpMethod !== "GET" || requestTimeout || parseInt(retryCount) > 0 ||
+            !followRedirects || freshConnection || acceptedCodes || Object.keys(headers).length > 0 || requestBody ||
+            assertions.length > 0;
+        if (!hasConfig) return undefined;
+
+        const requestConfig: RequestConfig = {};
+        if (httpMethod !== "GET") requestConfig.method = httpMethod;
+        if (requestTimeout) requestConfig.timeoutSeconds = parseInt(requestTimeout);
+        if (parseInt(retryCount) > 0) requestConfig.retryCount = parseInt(retryCount);
+        if (!followRedirects) requestConfig.followRedirects = false;
+        if (freshConnection) requestConfig.freshConnection = true;
+        if (acceptedCodes) requestConfig.acceptedStatusCodes = acceptedCodes;
+        if (Object.keys(headers).length > 0) requestConfig.headers = headers;
+        if (requestBody) requestConfig.body = requestBody;
+        if (assertions.length > 0) requestConfig.expectedHeaders = assertions;
+        return requestConfig;
+    };
+
+    const handlePreview = async () => {
+        try {
+            new URL(url);
+        } catch {
+            setUrlError(true);
+            toast({ title: "Invalid URL", description: "Please enter a valid URL (e.g. https://example.com)", variant: "destructive" });
+            return;
+        }
+        try {
+            setPreview(await previewMonitor.mutateAsync({
+                url,
+                latencyThreshold: latencyThreshold ? parseInt(latencyThreshold) : undefined,
+                requestConfig: buildRequestConfig(),
+            }));
+        } catch (err) {
+            setPreview(null);
+            toast({ title: "Test failed", description: err instanceof Error ? err.message : "Failed to run preview check", variant: "destructive" });
+        }
+    };
+
     const handleSubmit = async (e: React.FormEvent) => {
         e.preventDefault();
         if (!name || !url) return;
@@ -140,24 +200,7 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
                 }
             }
 
-            // Build request config if any fields are non-default
-            let requestConfig: RequestConfig | undefined;
-            const headers: Record<string, string> = {};
-            for (const h of customHeaders) {
-                if (h.key.trim()) headers[h.key.trim()] = h.value.trim();
-            }
-            const hasConfig = httpMethod !== "GET" || requestTimeout || parseInt(retryCount) > 0 ||
-                !followRedirects || acceptedCodes || Object.keys(headers).length > 0 || requestBody;
-            if (hasConfig) {
-                requestConfig = {};
-                if (httpMethod !== "GET") requestConfig.method = httpMethod;
-                if (requestTimeout) requestConfig.timeoutSeconds = parseInt(requestTimeout);
-                if (parseInt(retryCount) > 0) requestConfig.retryCount = parseInt(retryCount);
-                if (!followRedirects) requestConfig.followRedirects = false;
-                if (acceptedCodes) requestConfig.acceptedStatusCodes = acceptedCodes;
-                if (Object.keys(headers).length > 0) requestConfig.headers = headers;
-                if (requestBody) requestConfig.body = requestBody;
-            }
+            const requestConfig = buildRequestConfig();
 
             await createMonitor.mutateAsync({
                 name,
@@ -168,6 +211,7 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
                 notificationCooldownMinutes: cooldownMins ? parseInt(cooldownMins) : undefined,
                 latencyThreshold: latencyThreshold ? parseInt(latencyThreshold) : undefined,
                 requestConfig,
+                allowDuplicateUrl: duplicateOf !== null,
             });
 
             toast({ title: "Monitor Created", description: `Monitor "${name}" active and checking.` });
@@ -187,9 +231,13 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
             setRequestTimeout("");
             setRetryCount("0");
             setFollowRedirects(true);
+            setFreshConnection(false);
             setAcceptedCodes("");
             setCustomHeaders([]);
+            setExpectedHeaders([]);
             setRequestBody("");
+            setDuplicateOf(null);
+            setPreview(null);
             setOpen(false);
 
             // Redirect to the group page
@@ -198,6 +246,15 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
             }
 
         } catch (err) {
+            if (err instanceof DuplicateMonitorError) {
+                // A group created on this attempt already exists; don't create it again on retry
+                if (isNewGroup && finalGroupId) {
+                    setIsNewGroup(false);
+                    setSelectedGroupId(finalGroupId);
+                }
+                setDuplicateOf(err.duplicateOf);
+                return;
+            }
             console.error(err);
             toast({ title: "Error", description: "Failed to create monitor", variant: "destructive" });
         }
@@ -419,6 +476,13 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
                                         <Label className="text-xs">Follow Redirects</Label>
                                         <Switch checked={followRedirects} onCheckedChange={setFollowRedirects} />
                                     </div>
+                                    <div className="flex items-center justify-between">
+                                        <div>
+                                            <Label className="text-xs">Fresh Connection</Label>
+                                            <p className="text-[10px] text-muted-foreground">Open a new connection every check so DNS and TLS are always exercised</p>
+                                        </div>
+                                        <Switch checked={freshConnection} onCheckedChange={setFreshConnection} data-testid="create-monitor-fresh-connection-switch" />
+                                    </div>
                                     <div className="grid gap-1.5">
                                         <div className="flex items-center justify-between">
                                             <Label className="text-xs">Custom Headers</Label>
@@ -466,6 +530,57 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
                                             </div>
                                         ))}
                                     </div>
+                                    <div className="grid gap-1.5">
+                                        <div className="flex items-center justify-between">
+                                            <Label className="text-xs">Expected Response Headers</Label>
+                                            <Button
+                                                type="button"
+                                                variant="ghost"
+                                                size="sm"
+                                                className="h-6 text-xs"
+                                                onClick={() => setExpectedHeaders([...expectedHeaders, { name: "", value: "" }])}
+                                                data-testid="create-monitor-expected-header-add"
+                                            >
+                                                + Add
+                                            </Button>
+                                        </div>
+                                        {expectedHeaders.length > 0 && (
+                                            <p className="text-[10px] text-muted-foreground">Marks the monitor degraded when a header is missing or doesn't contain the value. Leave the value empty to only require the header.</p>
+                                        )}
+                                        {expectedHeaders.map((h, i) => (
+                                            <div key={i} className="flex gap-2 items-center">
+                                                <Input
+                                                    placeholder="Strict-Transport-Security"
+                                                    value={h.name}
+                                                    onChange={(e) => {
+                                                        const next = [...expectedHeaders];
+                                                        next[i] = { ...next[i], name: e.target.value };
+                                                        setExpectedHeaders(next);
+                                                    }}
+                                                    className="text-xs"
+                                                />
+                                                <Input
+                                                    placeholder="Contains (optional)"
+                                                    value={h.value}
+                                                    onChange={(e) => {
+                                                        const next = [...expectedHeaders];
+                                                        next[i] = { ...next[i], value: e.target.value };
+                                                        setExpectedHeaders(next);
+                                                    }}
+                                                    className="text-xs"
+                                                />
+                                                <Button
+                                                    type="button"
+                                                    variant="ghost"
+                                                    size="sm"
+                                                    className="h-8 w-8 p-0 shrink-0"
+                                                    onClick={() => setExpectedHeaders(expectedHeaders.filter((_, j) => j !== i))}
+                                                >
+                                                    <X className="w-3 h-3" />
+                                                </Button>
+                                            </div>
+                                        ))}
+                                    </div>
                                     {(httpMethod === "POST" || httpMethod === "PUT") && (
                                         <div className="grid gap-1.5">
                                             <Label className="text-xs">Request Body</Label>
@@ -481,12 +596,66 @@ export function CreateMonitorSheet({ groups, defaultGroup }: CreateMonitorSheetP
                             </div>
                         )}
                     </div>
+                    {duplicateOf && (
+                        <div className="mt-4 rounded-md border border-yellow-500/50 bg-yellow-500/10 p-3 text-sm" data-testid="create-monitor-duplicate-warning">
+                            <p>
+                                <span className="font-medium">{duplicateOf.name}</span> already checks this URL.
+                                A second monitor doubles the traffic to the same endpoint.
+                            </p>
+                            <Button
+                                type="button"
+                                variant="link"
+                                className="h-auto p-0 text-sm"
+                                onClick={() => {
+                                    setOpen(false);
+                                    navigate(`/groups/${duplicateOf.groupId}`);
+                                }}
+                            >
+                                Use the existing monitor
+                            </Button>
+                        </div>
+                    )}
+                    {preview && (
+                        <div
+                            className={cn(
+                                "rounded-md border p-3 text-sm grid gap-1",
+                                preview.status === "up" && "border-green-500/50 bg-green-500/10",
+                                preview.status === "degraded" && "border-yellow-500/50 bg-yellow-500/10",
+                                preview.status === "down" && "border-red-500/50 bg-red-500/10",
+                            )}
+                            data-testid="create-monitor-preview-result"
+                        >
+                            <p className="font-medium capitalize">
+                                {preview.status}
+                                {preview.statusCode > 0 && ` · HTTP ${preview.statusCode}`}
+                                {` · ${preview.latency} ms`}
+                            </p>
+                            {preview.resolvedIp && <p className="text-xs text-muted-foreground">Resolved to {preview.resolvedIp}</p>}
+                            {preview.error && <p className="text-xs break-all">{preview.error}</p>}
+                            {preview.degradedReason && <p className="text-xs break-all">{preview.degradedReason}</p>}
+                            {preview.tls && (
+                                <p className="text-xs text-muted-foreground">
+                                    {preview.tls.version}, certificate for {preview.tls.subject || preview.tls.dnsNames.join(", ")} from {preview.tls.issuer}, expires in {preview.tls.daysRemaining} days
+                                </p>
+                            )}
+                        </div>
+                    )}
                     <SheetFooter className="mt-4">
                         <SheetClose asChild>
                             <Button variant="outline" className="mr-2">Cancel</Button>
                         </SheetClose>
+                        <Button
+                            type="button"
+                            variant="secondary"
+                            className="mr-2"
+                            disabled={!url || previewMonitor.isPending}
+                            onClick={handlePreview}
+                            data-testid="create-monitor-preview-btn"
+                        >
+                            {previewMonitor.isPending ? "Testing..." : "Test"}
+                        </Button>
                         <Button type="submit" disabled={createMonitor.isPending || createGroup.isPending} data-testid="create-monitor-submit-btn">
-                            {createMonitor.isPending ? "Creating..." : "Create Monitor"}
+                            {createMonitor.isPending ? "Creating..." : duplicateOf ? "Create Anyway" : "Create Monitor"}
                         </Button>
                     </SheetFooter>
                 </form>
@@ -25,7 +25,7 @@ export function MonitorCard({ monitor, groupId }: { monitor: Monitor, groupId: s
 
     // Format just the time (e.g. 9:41 PM)
     const timeOnly = useMemo(() => {
-        if (!monitor.lastCheck || monitor.lastCheck === 'Never') return monitor.lastCheck || '';
+        if (!monitor.lastCheck) return 'Never';
         try {
             return new Intl.DateTimeFormat('en-US', {
                 hour: 'numeric',
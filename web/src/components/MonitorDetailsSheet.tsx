@@ -1,6 +1,6 @@
 import { useState, useEffect, useCallback } from "react";
 import { Monitor, RequestConfig, useMonitorStore } from "@/lib/store";
-import { formatDate } from "@/lib/utils";
+import { formatBytes, formatDate } from "@/lib/utils";
 import {
     Sheet,
     SheetContent,
@@ -43,7 +43,9 @@ interface MonitorDetailsSheetProps {
 }
 
 export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDetailsSheetProps) {
-    const { updateMonitor, deleteMonitor, pauseMonitor, resumeMonitor, user } = useMonitorStore();
+    const { updateMonitor, deleteMonitor, pauseMonitor, resumeMonitor, user, groups } = useMonitorStore();
+    const otherMonitors = groups.flatMap(g => g.monitors).filter(m => m.id !== monitor.id);
+    const affectedByName = monitor.affectedBy ? otherMonitors.find(m => m.id === monitor.affectedBy)?.name ?? monitor.affectedBy : "";
     const isPaused = monitor.status === 'paused';
     const [name, setName] = useState(monitor.name);
     const [url, setUrl] = useState(monitor.url);
@@ -57,11 +59,16 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
     const [requestTimeout, setRequestTimeout] = useState<string>(monitor.requestConfig?.timeoutSeconds?.toString() ?? "");
     const [retryCount, setRetryCount] = useState(monitor.requestConfig?.retryCount?.toString() ?? "0");
     const [followRedirects, setFollowRedirects] = useState(monitor.requestConfig?.followRedirects !== false);
+    const [freshConnection, setFreshConnection] = useState(monitor.requestConfig?.freshConnection === true);
     const [acceptedCodes, setAcceptedCodes] = useState(monitor.requestConfig?.acceptedStatusCodes ?? "");
     const [customHeaders, setCustomHeaders] = useState<{ key: string; value: string }[]>(
         Object.entries(monitor.requestConfig?.headers ?? {}).map(([key, value]) => ({ key, value }))
     );
     const [requestBody, setRequestBody] = useState(monitor.requestConfig?.body ?? "");
+    const [expectedHeaders, setExpectedHeaders] = useState<{ name: string; value: string }[]>(
+        (monitor.requestConfig?.expectedHeaders ?? []).map((h) => ({ name: h.name, value: h.value ?? "" }))
+    );
+    const [dependsOn, setDependsOn] = useState<string[]>(monitor.dependsOn ?? []);
 
     const [stats, setStats] = useState({ uptime24h: 100, uptime7d: 100, uptime30d: 100 });
     // eslint-disable-next-line @typescript-eslint/no-explicit-any
@@ -80,11 +87,16 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
             setRequestTimeout(monitor.requestConfig?.timeoutSeconds?.toString() ?? "");
             setRetryCount(monitor.requestConfig?.retryCount?.toString() ?? "0");
             setFollowRedirects(monitor.requestConfig?.followRedirects !== false);
+            setFreshConnection(monitor.requestConfig?.freshConnection === true);
             setAcceptedCodes(monitor.requestConfig?.acceptedStatusCodes ?? "");
             setCustomHeaders(
                 Object.entries(monitor.requestConfig?.headers ?? {}).map(([key, value]) => ({ key, value }))
             );
             setRequestBody(monitor.requestConfig?.body ?? "");
+            setExpectedHeaders(
+                (monitor.requestConfig?.expectedHeaders ?? []).map((h) => ({ name: h.name, value: h.value ?? "" }))
+            );
+            setDependsOn(monitor.dependsOn ?? []);
         }
     }, [open, monitor]);
 
@@ -92,8 +104,8 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
         fetch(`/api/monitors/${id}/latency?range=${range}`)
             .then(res => res.json())
             .then(data => {
-                if (data) {
-                    const sortedData = data
+                if (data?.points) {
+                    const sortedData = data.points
                         // eslint-disable-next-line @typescript-eslint/no-explicit-any
                         .map((point: any) => ({
                             ...point,
@@ -227,6 +239,9 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
         return zones;
     };
 
+    // Phase breakdown of the latest check, to tell network from backend slowness
+    const lastTiming = monitor.history?.[monitor.history.length - 1]?.timing;
+
 
     const handleSave = () => {
         // Build request config
@@ -234,8 +249,12 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
         for (const h of customHeaders) {
             if (h.key.trim()) headers[h.key.trim()] = h.value.trim();
         }
+        const assertions = expectedHeaders
+            .filter((h) => h.name.trim())
+            .map((h) => (h.value.trim() ? { name: h.name.trim(), value: h.value.trim() } : { name: h.name.trim() }));
         const hasNonDefaults = httpMethod !== "GET" || requestTimeout || parseInt(retryCount) > 0 ||
-            !followRedirects || acceptedCodes || Object.keys(headers).length > 0 || requestBody;
+            !followRedirects || freshConnection || acceptedCodes || Object.keys(headers).length > 0 || requestBody ||
+            assertions.length > 0;
 
         // Build requestConfig: if user set non-default values, include them.
         // If monitor previously had config but user cleared everything back to defaults,
@@ -247,9 +266,11 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
             if (requestTimeout) requestConfig.timeoutSeconds = parseInt(requestTimeout);
             if (parseInt(retryCount) > 0) requestConfig.retryCount = parseInt(retryCount);
             if (!followRedirects) requestConfig.followRedirects = false;
+            if (freshConnection) requestConfig.freshConnection = true;
             if (acceptedCodes) requestConfig.acceptedStatusCodes = acceptedCodes;
             if (Object.keys(headers).length > 0) requestConfig.headers = headers;
             if (requestBody) requestConfig.body = requestBody;
+            if (assertions.length > 0) requestConfig.expectedHeaders = assertions;
         } else if (monitor.requestConfig) {
             // Monitor had config before but user reset everything to defaults.
             // Send empty object so backend clears the stored config.
@@ -262,6 +283,7 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
             notificationCooldownMinutes: cooldownMins ? parseInt(cooldownMins) : undefined,
             latencyThreshold: latencyThreshold ? parseInt(latencyThreshold) : undefined,
             requestConfig,
+            dependsOn,
         });
         onOpenChange(false);
     };
@@ -306,6 +328,17 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                     <SheetDescription className="font-mono text-xs">
                         ID: {monitor.id}
                     </SheetDescription>
+                    {monitor.type === 'composite' && monitor.members && (
+                        <p className="text-xs text-muted-foreground" data-testid="monitor-composite-rule">
+                            Up while at least {monitor.minUp} of {monitor.members.length} members are up:{" "}
+                            {monitor.members.map(id => otherMonitors.find(m => m.id === id)?.name ?? id).join(", ")}
+                        </p>
+                    )}
+                    {affectedByName && (
+                        <p className="text-xs text-amber-500" data-testid="monitor-affected-by">
+                            Affected by dependency: {affectedByName} is down
+                        </p>
+                    )}
                 </SheetHeader>
 
                 <Tabs defaultValue="metrics" className="w-full">
@@ -430,6 +463,23 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                                     </AreaChart>
                                 </ResponsiveContainer>
                             </div>
+                            {lastTiming && (
+                                <div className="grid grid-cols-6 gap-2" data-testid="monitor-timing-breakdown">
+                                    {[
+                                        { label: "DNS", value: `${lastTiming.dnsMs}ms` },
+                                        { label: "Connect", value: `${lastTiming.connectMs}ms` },
+                                        { label: "TLS", value: `${lastTiming.tlsMs}ms` },
+                                        { label: "TTFB", value: `${lastTiming.ttfbMs}ms` },
+                                        { label: "Download", value: `${lastTiming.downloadMs}ms` },
+                                        { label: "Size", value: formatBytes(lastTiming.bodyBytes) },
+                                    ].map(({ label, value }) => (
+                                        <div key={label} className="bg-card border border-border rounded-lg p-2 text-center shadow-sm">
+                                            <span className="text-[10px] text-muted-foreground block">{label}</span>
+                                            <span className="text-xs font-mono">{value}</span>
+                                        </div>
+                                    ))}
+                                </div>
+                            )}
                         </div>
                     </TabsContent>
 
@@ -443,7 +493,7 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                                     <div key={event.id} className="ml-6 relative">
                                         <div className={`absolute -left-[31px] top-1 w-2.5 h-2.5 rounded-full ring-4 ring-background ${event.type === 'up' ? 'bg-emerald-500' :
                                             event.type === 'down' ? 'bg-destructive' :
-                                            event.type === 'ssl_expiring' ? 'bg-orange-500' :
+                                            event.type === 'ssl_expiring' || event.type === 'slo_burn' || event.type === 'anomaly' ? 'bg-orange-500' :
                                             event.type === 'flapping' ? 'bg-purple-500' :
                                             event.type === 'stabilized' ? 'bg-blue-500' : 'bg-yellow-500'
                                             }`} />
@@ -533,6 +583,38 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                                     </div>
                                 </div>
                             </div>
+                            <div className="pt-4 border-t border-border">
+                                <h3 className="text-sm font-medium mb-3">Dependencies</h3>
+                                <p className="text-xs text-muted-foreground mb-3">
+                                    While a monitor this one depends on is down, failures here are marked as affected by it instead of opening a separate outage.
+                                </p>
+                                <div className="space-y-2">
+                                    {dependsOn.map(id => (
+                                        <div key={id} className="flex items-center justify-between rounded-md border border-border px-3 py-1.5 text-sm">
+                                            <span>{otherMonitors.find(m => m.id === id)?.name ?? id}</span>
+                                            <Button
+                                                type="button"
+                                                variant="ghost"
+                                                size="sm"
+                                                className="h-6 w-6 p-0"
+                                                onClick={() => setDependsOn(dependsOn.filter(d => d !== id))}
+                                            >
+                                                <X className="w-3 h-3" />
+                                            </Button>
+                                        </div>
+                                    ))}
+                                    {otherMonitors.some(m => !dependsOn.includes(m.id)) && (
+                                        <Select value="" onValueChange={(id) => setDependsOn([...dependsOn, id])}>
+                                            <SelectTrigger data-testid="monitor-dependency-select"><SelectValue placeholder="Add dependency..." /></SelectTrigger>
+                                            <SelectContent>
+                                                {otherMonitors.filter(m => !dependsOn.includes(m.id)).map(m => (
+                                                    <SelectItem key={m.id} value={m.id} className="cursor-pointer">{m.name}</SelectItem>
+                                                ))}
+                                            </SelectContent>
+                                        </Select>
+                                    )}
+                                </div>
+                            </div>
                             <div className="pt-4 border-t border-border">
                                 <h3 className="text-sm font-medium mb-3">Request Configuration</h3>
                                 <p className="text-xs text-muted-foreground mb-3">
@@ -588,6 +670,13 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                                         <Label className="text-xs">Follow Redirects</Label>
                                         <Switch checked={followRedirects} onCheckedChange={setFollowRedirects} />
                                     </div>
+                                    <div className="flex items-center justify-between">
+                                        <div>
+                                            <Label className="text-xs">Fresh Connection</Label>
+                                            <p className="text-[10px] text-muted-foreground">Open a new connection every check so DNS and TLS are always exercised</p>
+                                        </div>
+                                        <Switch checked={freshConnection} onCheckedChange={setFreshConnection} data-testid="monitor-fresh-connection-switch" />
+                                    </div>
                                     <div className="grid gap-1.5">
                                         <div className="flex items-center justify-between">
                                             <Label className="text-xs">Custom Headers</Label>
@@ -635,6 +724,57 @@ export function MonitorDetailsSheet({ monitor, open, onOpenChange }: MonitorDeta
                                             </div>
                                         ))}
                                     </div>
+                                    <div className="grid gap-1.5">
+                                        <div className="flex items-center justify-between">
+                                            <Label className="text-xs">Expected Response Headers</Label>
+                                            <Button
+                                                type="button"
+                                                variant="ghost"
+                                                size="sm"
+                                                className="h-6 text-xs"
+                                                onClick={() => setExpectedHeaders([...expectedHeaders, { name: "", value: "" }])}
+                                                data-testid="monitor-expected-header-add"
+                                            >
+                                                + Add
+                                            </Button>
+                                        </div>
+                                        {expectedHeaders.length > 0 && (
+                                            <p className="text-[10px] text-muted-foreground">Marks the monitor degraded when a header is missing or doesn't contain the value. Leave the value empty to only require the header.</p>
+                                        )}
+                                        {expectedHeaders.map((h, i) => (
+                                            <div key={i} className="flex gap-2 items-center">
+                                                <Input
+                                                    placeholder="Strict-Transport-Security"
+                                                    value={h.name}
+                                                    onChange={(e) => {
+                                                        const next = [...expectedHeaders];
+                                                        next[i] = { ...next[i], name: e.target.value };
+                                                        setExpectedHeaders(next);
+                                                    }}
+                                                    className="text-xs"
+                                                />
+                                                <Input
+                                                    placeholder="Contains (optional)"
+                                                    value={h.value}
+                                                    onChange={(e) => {
+                                                        const next = [...expectedHeaders];
+                                                        next[i] = { ...next[i], value: e.target.value };
+                                                        setExpectedHeaders(next);
+                                                    }}
+                                                    className="text-xs"
+                                                />
+                                                <Button
+                                                    type="button"
+                                                    variant="ghost"
+                                                    size="sm"
+                                                    className="h-8 w-8 p-0 shrink-0"
+                                                    onClick={() => setExpectedHeaders(expectedHeaders.filter((_, j) => j !== i))}
+                                                >
+                                                    <X className="w-3 h-3" />
+                                                </Button>
+                                            </div>
+                                        ))}
+                                    </div>
                                     {(httpMethod === "POST" || httpMethod === "PUT") && (
                                         <div className="grid gap-1.5">
                                             <Label className="text-xs">Request Body</Label>
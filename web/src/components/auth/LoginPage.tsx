@@ -5,7 +5,7 @@ import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
 import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert";
-import { AlertCircle } from "lucide-react";
+import { AlertCircle, Info } from "lucide-react";
 
 function GoogleIcon({ className }: { className?: string }) {
     return (
@@ -45,6 +45,7 @@ export function LoginPage() {
     const [error, setError] = useState<string | null>(null);
     const [isLoading, setIsLoading] = useState(false);
     const [googleSSOEnabled, setGoogleSSOEnabled] = useState(false);
+    const [demoCredentials, setDemoCredentials] = useState<{ username: string; password: string } | null>(null);
 
     // Check for SSO errors in URL params
     useEffect(() => {
@@ -67,6 +68,22 @@ export function LoginPage() {
             });
     }, []);
 
+    // Demo mode publishes its sign-in so visitors can look around
+    useEffect(() => {
+        fetch("/api/setup/status")
+            .then(res => res.json())
+            .then(data => {
+                if (data.demo) {
+                    setDemoCredentials(data.demo);
+                    setUsername(data.demo.username);
+                    setPassword(data.demo.password);
+                }
+            })
+            .catch(() => {
+                setDemoCredentials(null);
+            });
+    }, []);
+
     const handleGoogleLogin = () => {
         window.location.href = "/api/auth/sso/google";
     };
@@ -103,6 +120,19 @@ export function LoginPage() {
                         </p>
                     </div>
 
+                    {/* Demo credentials */}
+                    {demoCredentials && (
+                        <Alert data-testid="login-demo-hint">
+                            <Info className="h-4 w-4" />
+                            <AlertTitle>Demo</AlertTitle>
+                            <AlertDescription>
+                                Sign in as <span className="font-mono">{demoCredentials.username}</span> with
+                                password <span className="font-mono">{demoCredentials.password}</span>. Changes are
+                                discarded when the demo resets.
+                            </AlertDescription>
+                        </Alert>
+                    )}
+
                     {/* Error alert */}
                     {error && (
                         <Alert variant="destructive" className="bg-destructive/50 text-destructive-foreground border-destructive/50">
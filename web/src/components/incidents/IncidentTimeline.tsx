@@ -11,12 +11,15 @@ import {
 import { cn, formatDate } from "@/lib/utils";
 import { IncidentUpdate } from "@/lib/store";
 import { useState } from "react";
-import { AlertCircle, CheckCircle2, Eye, Search, Clock, MessageCircle, Plus } from "lucide-react";
+import { AlertCircle, CheckCircle2, Eye, Search, Clock, MessageCircle, Plus, Pencil, Trash2 } from "lucide-react";
 
 interface IncidentTimelineProps {
     updates: IncidentUpdate[];
     incidentId?: string;
     onAddUpdate?: (status: string, message: string) => Promise<void>;
+    // Editing and deleting stay available after an incident is resolved, to fix typos
+    onEditUpdate?: (updateId: number, status: string, message: string) => Promise<void>;
+    onDeleteUpdate?: (updateId: number) => Promise<void>;
     readonly?: boolean;
     timezone?: string;
 }
@@ -59,8 +62,65 @@ function getStatusConfig(status: string) {
     );
 }
 
-function TimelineEntry({ update, timezone }: { update: IncidentUpdate; timezone?: string }) {
+function StatusSelect({ value, onChange }: { value: string; onChange: (status: string) => void }) {
+    return (
+        <Select value={value} onValueChange={onChange}>
+            <SelectTrigger className="w-[140px] bg-background border-border/50 h-9">
+                <SelectValue />
+            </SelectTrigger>
+            <SelectContent>
+                <SelectItem value="investigating">Investigating</SelectItem>
+                <SelectItem value="identified">Identified</SelectItem>
+                <SelectItem value="monitoring">Monitoring</SelectItem>
+                <SelectItem value="resolved">Resolved</SelectItem>
+            </SelectContent>
+        </Select>
+    );
+}
+
+function TimelineEntry({
+    update,
+    timezone,
+    onEdit,
+    onDelete,
+}: {
+    update: IncidentUpdate;
+    timezone?: string;
+    onEdit?: (status: string, message: string) => Promise<void>;
+    onDelete?: () => Promise<void>;
+}) {
     const config = getStatusConfig(update.status);
+    const [isEditing, setIsEditing] = useState(false);
+    const [isSaving, setIsSaving] = useState(false);
+    const [status, setStatus] = useState(update.status);
+    const [message, setMessage] = useState(update.message);
+
+    const startEditing = () => {
+        setStatus(update.status);
+        setMessage(update.message);
+        setIsEditing(true);
+    };
+
+    const handleSave = async () => {
+        if (!onEdit || !message.trim()) return;
+        setIsSaving(true);
+        try {
+            await onEdit(status, message.trim());
+            setIsEditing(false);
+        } finally {
+            setIsSaving(false);
+        }
+    };
+
+    const handleDelete = async () => {
+        if (!onDelete || !window.confirm("Delete this update from the incident timeline?")) return;
+        setIsSaving(true);
+        try {
+            await onDelete();
+        } finally {
+            setIsSaving(false);
+        }
+    };
 
     return (
         <div className="relative flex gap-4 pb-6 last:pb-0">
@@ -93,8 +153,47 @@ function TimelineEntry({ update, timezone }: { update: IncidentUpdate; timezone?
                         <Clock className="w-3 h-3" />
                         {formatDate(update.createdAt, timezone)}
                     </span>
+                    {update.editedAt && (
+                        <span className="text-xs text-muted-foreground/70 italic">edited</span>
+                    )}
+                    {!isEditing && (onEdit || onDelete) && (
+                        <div className="ml-auto flex items-center gap-1">
+                            {onEdit && (
+                                <Button variant="ghost" size="icon" className="h-6 w-6" onClick={startEditing} disabled={isSaving} aria-label="Edit update" data-testid="incident-update-edit">
+                                    <Pencil className="w-3 h-3" />
+                                </Button>
+                            )}
+                            {onDelete && (
+                                <Button variant="ghost" size="icon" className="h-6 w-6 text-muted-foreground hover:text-destructive" onClick={handleDelete} disabled={isSaving} aria-label="Delete update" data-testid="incident-update-delete">
+                                    <Trash2 className="w-3 h-3" />
+                                </Button>
+                            )}
+                        </div>
+                    )}
                 </div>
-                <p className="text-sm text-foreground/90 whitespace-pre-wrap">{update.message}</p>
+                {isEditing ? (
+                    <div className="space-y-2">
+                        <div className="flex gap-3">
+                            <StatusSelect value={status} onChange={setStatus} />
+                            <Textarea
+                                value={message}
+                                onChange={(e) => setMessage(e.target.value)}
+                                className="flex-1 min-h-[72px] bg-background border-border/50 resize-none"
+                                data-testid="incident-update-edit-message"
+                            />
+                        </div>
+                        <div className="flex justify-end gap-2">
+                            <Button variant="ghost" size="sm" onClick={() => setIsEditing(false)} disabled={isSaving}>
+                                Cancel
+                            </Button>
+                            <Button size="sm" onClick={handleSave} disabled={isSaving || !message.trim()} data-testid="incident-update-edit-save">
+                                {isSaving ? "Saving..." : "Save"}
+                            </Button>
+                        </div>
+                    </div>
+                ) : (
+                    <p className="text-sm text-foreground/90 whitespace-pre-wrap">{update.message}</p>
+                )}
             </div>
         </div>
     );
@@ -126,17 +225,7 @@ function AddUpdateForm({
                 </span>
             </div>
             <div className="flex gap-3">
-                <Select value={status} onValueChange={setStatus}>
-                    <SelectTrigger className="w-[140px] bg-background border-border/50 h-9">
-                        <SelectValue />
-                    </SelectTrigger>
-                    <SelectContent>
-                        <SelectItem value="investigating">Investigating</SelectItem>
-                        <SelectItem value="identified">Identified</SelectItem>
-                        <SelectItem value="monitoring">Monitoring</SelectItem>
-                        <SelectItem value="resolved">Resolved</SelectItem>
-                    </SelectContent>
-                </Select>
+                <StatusSelect value={status} onChange={setStatus} />
                 <Textarea
                     value={message}
                     onChange={(e) => setMessage(e.target.value)}
@@ -156,6 +245,8 @@ function AddUpdateForm({
 export function IncidentTimeline({
     updates = [],
     onAddUpdate,
+    onEditUpdate,
+    onDeleteUpdate,
     readonly = false,
     timezone,
 }: IncidentTimelineProps) {
@@ -180,7 +271,13 @@ export function IncidentTimeline({
             {updates.length > 0 && (
                 <div className="space-y-0">
                     {updates.map((update, index) => (
-                        <TimelineEntry key={update.id || index} update={update} timezone={timezone} />
+                        <TimelineEntry
+                            key={update.id || index}
+                            update={update}
+                            timezone={timezone}
+                            onEdit={onEditUpdate && update.id ? (status, message) => onEditUpdate(update.id!, status, message) : undefined}
+                            onDelete={onDeleteUpdate && update.id ? () => onDeleteUpdate(update.id!) : undefined}
+                        />
                     ))}
                 </div>
             )}
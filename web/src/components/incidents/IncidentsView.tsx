@@ -10,10 +10,12 @@ import { cn, formatDate } from "@/lib/utils";
 import { PromoteOutageDialog } from "./PromoteOutageDialog";
 import { IncidentTimeline } from "./IncidentTimeline";
 
-function IncidentCard({ incident, timezone, onAddUpdate, onToggleVisibility }: {
+function IncidentCard({ incident, timezone, onAddUpdate, onEditUpdate, onDeleteUpdate, onToggleVisibility }: {
     incident: Incident;
     timezone?: string;
     onAddUpdate?: (status: string, message: string) => Promise<void>;
+    onEditUpdate?: (updateId: number, status: string, message: string) => Promise<void>;
+    onDeleteUpdate?: (updateId: number) => Promise<void>;
     onToggleVisibility?: () => void;
 }) {
     const [isExpanded, setIsExpanded] = useState(false);
@@ -42,6 +44,11 @@ function IncidentCard({ incident, timezone, onAddUpdate, onToggleVisibility }: {
                                 Public
                             </Badge>
                         )}
+                        {incident.pendingPublication && !incident.public && (
+                            <Badge variant="outline" className="text-[10px] uppercase tracking-wider font-mono px-1.5 py-0 h-auto border-0 bg-amber-500/10 text-amber-500 shrink-0">
+                                Awaiting Approval
+                            </Badge>
+                        )}
                         {incident.source === 'auto' && (
                             <Badge variant="outline" className="text-[10px] uppercase tracking-wider font-mono px-1.5 py-0 h-auto border-0 bg-purple-500/10 text-purple-500 shrink-0">
                                 Auto
@@ -85,10 +92,38 @@ function IncidentCard({ incident, timezone, onAddUpdate, onToggleVisibility }: {
                         </div>
                     )}
 
+                    {/* Affected groups with their monitors' live status */}
+                    {incident.affectedGroupDetails && incident.affectedGroupDetails.length > 0 && (
+                        <div className="space-y-2" data-testid="incident-affected-groups">
+                            {incident.affectedGroupDetails.map(g => (
+                                <div key={g.id} className="space-y-1">
+                                    <span className="text-xs text-muted-foreground">{g.name}</span>
+                                    <div className="flex flex-wrap gap-1.5">
+                                        {g.monitors.map(m => (
+                                            <Badge key={m.id} variant="outline" className="text-xs font-normal gap-1.5 border-border/50">
+                                                <span className={cn("w-1.5 h-1.5 rounded-full",
+                                                    m.status === 'up' ? 'bg-emerald-500' :
+                                                    m.status === 'degraded' ? 'bg-yellow-500' :
+                                                    m.status === 'down' ? 'bg-destructive' : 'bg-muted-foreground'
+                                                )} />
+                                                {m.name}
+                                            </Badge>
+                                        ))}
+                                        {g.monitors.length === 0 && (
+                                            <span className="text-xs text-muted-foreground/60">No monitors</span>
+                                        )}
+                                    </div>
+                                </div>
+                            ))}
+                        </div>
+                    )}
+
                     {/* Timeline */}
                     <IncidentTimeline
                         updates={incident.updates || []}
                         onAddUpdate={isActive && onAddUpdate ? onAddUpdate : undefined}
+                        onEditUpdate={onEditUpdate}
+                        onDeleteUpdate={onDeleteUpdate}
                         readonly={!isActive}
                         timezone={timezone}
                     />
@@ -246,7 +281,7 @@ function SSLWarningRow({ warning, timezone }: { warning: SSLWarning; timezone?:
 }
 
 export function IncidentsView() {
-    const { incidents, systemEvents, fetchSystemEvents, fetchIncidents, user, promoteOutage, addIncidentUpdate, setIncidentVisibility, getIncidentWithUpdates } = useMonitorStore();
+    const { incidents, systemEvents, fetchSystemEvents, fetchIncidents, user, promoteOutage, addIncidentUpdate, editIncidentUpdate, deleteIncidentUpdate, setIncidentVisibility, getIncidentWithUpdates } = useMonitorStore();
     const timezone = user?.timezone;
     const [searchParams] = useSearchParams();
     const navigate = useNavigate();
@@ -302,15 +337,29 @@ export function IncidentsView() {
         await promoteOutage(outageId, data);
     };
 
-    const handleAddUpdate = (incidentId: string) => async (status: string, message: string) => {
-        await addIncidentUpdate(incidentId, status, message);
-        // Refresh the incident with updates
+    // Refresh the incident with updates
+    const refreshIncidentUpdates = async (incidentId: string) => {
         const full = await getIncidentWithUpdates(incidentId);
         if (full) {
             setIncidentUpdates(prev => ({ ...prev, [incidentId]: full }));
         }
     };
 
+    const handleAddUpdate = (incidentId: string) => async (status: string, message: string) => {
+        await addIncidentUpdate(incidentId, status, message);
+        await refreshIncidentUpdates(incidentId);
+    };
+
+    const handleEditUpdate = (incidentId: string) => async (updateId: number, status: string, message: string) => {
+        await editIncidentUpdate(incidentId, updateId, status, message);
+        await refreshIncidentUpdates(incidentId);
+    };
+
+    const handleDeleteUpdate = (incidentId: string) => async (updateId: number) => {
+        await deleteIncidentUpdate(incidentId, updateId);
+        await refreshIncidentUpdates(incidentId);
+    };
+
     const handleToggleVisibility = (incidentId: string, currentPublic: boolean) => async () => {
         await setIncidentVisibility(incidentId, !currentPublic);
     };
@@ -406,6 +455,8 @@ export function IncidentsView() {
                                             incident={incWithUpdates}
                                             timezone={timezone}
                                             onAddUpdate={handleAddUpdate(i.id)}
+                                            onEditUpdate={handleEditUpdate(i.id)}
+                                            onDeleteUpdate={handleDeleteUpdate(i.id)}
                                             onToggleVisibility={handleToggleVisibility(i.id, incWithUpdates.public || false)}
                                         />
                                     );
@@ -427,7 +478,15 @@ export function IncidentsView() {
                     <div className="pt-6 space-y-3">
                         {history.map(i => {
                             const incWithUpdates = incidentUpdates[i.id] || i;
-                            return <IncidentCard key={i.id} incident={incWithUpdates} timezone={timezone} />;
+                            return (
+                                <IncidentCard
+                                    key={i.id}
+                                    incident={incWithUpdates}
+                                    timezone={timezone}
+                                    onEditUpdate={handleEditUpdate(i.id)}
+                                    onDeleteUpdate={handleDeleteUpdate(i.id)}
+                                />
+                            );
                         })}
                     </div>
                 </TabsContent>
@@ -34,6 +34,7 @@ export function ChannelDetailsSheet({ channel, open, onOpenChange }: ChannelDeta
     const [name, setName] = useState(channel.name);
     const [type, setType] = useState<NotificationChannel['type']>(channel.type);
     const [webhookUrl, setWebhookUrl] = useState(channel.config.webhookUrl || "");
+    const [locale, setLocale] = useState(channel.config.locale || "en");
     const [testing, setTesting] = useState(false);
 
     // Reset state when channel changes
@@ -41,13 +42,14 @@ export function ChannelDetailsSheet({ channel, open, onOpenChange }: ChannelDeta
         setName(channel.name);
         setType(channel.type);
         setWebhookUrl(channel.config.webhookUrl || "");
+        setLocale(channel.config.locale || "en");
     }, [channel, open]);
 
     const handleSave = () => {
         updateChannel(channel.id, {
             name,
             type,
-            config: { webhookUrl },
+            config: { webhookUrl, locale },
         });
         onOpenChange(false);
     };
@@ -59,7 +61,7 @@ export function ChannelDetailsSheet({ channel, open, onOpenChange }: ChannelDeta
 
     const handleTest = async () => {
         setTesting(true);
-        await testChannel(type, { webhookUrl });
+        await testChannel(type, { webhookUrl, locale });
         setTesting(false);
     };
 
@@ -114,6 +116,25 @@ export function ChannelDetailsSheet({ channel, open, onOpenChange }: ChannelDeta
                         </p>
                     </div>
 
+                    <div className="grid gap-2">
+                        <Label>Language</Label>
+                        <Select value={locale} onValueChange={setLocale}>
+                            <SelectTrigger>
+                                <SelectValue />
+                            </SelectTrigger>
+                            <SelectContent>
+                                <SelectItem value="en">English</SelectItem>
+                                <SelectItem value="es">Español</SelectItem>
+                                <SelectItem value="fr">Français</SelectItem>
+                                <SelectItem value="de">Deutsch</SelectItem>
+                                <SelectItem value="pt">Português</SelectItem>
+                            </SelectContent>
+                        </Select>
+                        <p className="text-[0.8rem] text-muted-foreground">
+                            Language of the Slack messages and digest titles. Webhook payloads are not translated.
+                        </p>
+                    </div>
+
                     {type === 'slack' ? <SlackPreview /> : <WebhookPayloadPreview />}
                 </div>
 
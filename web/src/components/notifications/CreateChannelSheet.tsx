@@ -29,6 +29,7 @@ export function CreateChannelSheet({ onCreate }: { onCreate?: (c: any) => void }
     const [name, setName] = useState("");
     const [type, setType] = useState<NotificationChannel['type']>("slack");
     const [webhookUrl, setWebhookUrl] = useState("");
+    const [locale, setLocale] = useState("en");
     const [open, setOpen] = useState(false);
     const [testing, setTesting] = useState(false);
 
@@ -38,7 +39,7 @@ export function CreateChannelSheet({ onCreate }: { onCreate?: (c: any) => void }
         const channelData = {
             type,
             name,
-            config: { webhookUrl },
+            config: { webhookUrl, locale },
             enabled: true,
         };
 
@@ -51,11 +52,12 @@ export function CreateChannelSheet({ onCreate }: { onCreate?: (c: any) => void }
         setOpen(false);
         setName("");
         setWebhookUrl("");
+        setLocale("en");
     };
 
     const handleTest = async () => {
         setTesting(true);
-        await testChannel(type, { webhookUrl });
+        await testChannel(type, { webhookUrl, locale });
         setTesting(false);
     };
 
@@ -115,6 +117,25 @@ export function CreateChannelSheet({ onCreate }: { onCreate?: (c: any) => void }
                         </p>
                     </div>
 
+                    <div className="grid gap-2">
+                        <Label>Language</Label>
+                        <Select value={locale} onValueChange={setLocale}>
+                            <SelectTrigger data-testid="channel-locale-select">
+                                <SelectValue />
+                            </SelectTrigger>
+                            <SelectContent>
+                                <SelectItem value="en">English</SelectItem>
+                                <SelectItem value="es">Español</SelectItem>
+                                <SelectItem value="fr">Français</SelectItem>
+                                <SelectItem value="de">Deutsch</SelectItem>
+                                <SelectItem value="pt">Português</SelectItem>
+                            </SelectContent>
+                        </Select>
+                        <p className="text-[0.8rem] text-muted-foreground">
+                            Language of the Slack messages and digest titles. Webhook payloads are not translated.
+                        </p>
+                    </div>
+
                     {type === 'slack' ? <SlackPreview /> : <WebhookPayloadPreview />}
 
                     <SheetFooter className="mt-4 gap-2">
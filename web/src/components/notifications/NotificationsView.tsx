@@ -95,7 +95,11 @@ export function NotificationsView() {
                                         </span>
                                     </TableCell>
                                     <TableCell>
-                                        <Badge variant="secondary">Active</Badge>
+                                        {channel.enabled ? (
+                                            <Badge variant="secondary">Active</Badge>
+                                        ) : (
+                                            <Badge variant="destructive" title={channel.disabledReason}>Disabled</Badge>
+                                        )}
                                     </TableCell>
                                     <TableCell>
                                         <DropdownMenu>
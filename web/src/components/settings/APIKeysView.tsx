@@ -58,6 +58,7 @@ export function APIKeysView() {
                             <TableRow>
                                 <TableHead>Name</TableHead>
                                 <TableHead>Key Prefix</TableHead>
+                                <TableHead>Allowed From</TableHead>
                                 <TableHead>Created</TableHead>
                                 <TableHead className="w-[50px]"></TableHead>
                             </TableRow>
@@ -71,6 +72,9 @@ export function APIKeysView() {
                                             {key.keyPrefix}••••••••
                                         </span>
                                     </TableCell>
+                                    <TableCell className="text-xs text-muted-foreground">
+                                        {key.allowedCidrs?.length ? key.allowedCidrs.join(", ") : "Anywhere"}
+                                    </TableCell>
                                     <TableCell className="text-muted-foreground">
                                         {new Date(key.createdAt).toLocaleDateString()}
                                     </TableCell>
@@ -20,18 +20,21 @@ export function CreateAPIKeySheet() {
     const { createAPIKey, fetchAPIKeys } = useMonitorStore();
     const { toast } = useToast();
     const [newKeyName, setNewKeyName] = useState("");
+    const [allowedCidrs, setAllowedCidrs] = useState("");
     const [createdKey, setCreatedKey] = useState<string | null>(null);
     const [isOpen, setIsOpen] = useState(false);
 
     const handleCreate = async () => {
         if (!newKeyName) return;
-        const key = await createAPIKey(newKeyName);
+        const cidrs = allowedCidrs.split(",").map((c) => c.trim()).filter(Boolean);
+        const key = await createAPIKey(newKeyName, cidrs);
         if (key) {
             setCreatedKey(key);
             setNewKeyName("");
+            setAllowedCidrs("");
             fetchAPIKeys(); // Refresh list
         } else {
-            toast({ title: "Error", description: "Failed to create API Key", variant: "destructive" });
+            toast({ title: "Error", description: "Failed to create API Key. Check the allowed networks.", variant: "destructive" });
         }
     };
 
@@ -78,6 +81,18 @@ export function CreateAPIKeySheet() {
                                 data-testid="apikey-name-input"
                             />
                         </div>
+                        <div className="grid gap-2">
+                            <Label>Allowed Networks (optional)</Label>
+                            <Input
+                                value={allowedCidrs}
+                                onChange={(e) => setAllowedCidrs(e.target.value)}
+                                placeholder="e.g. 10.0.0.0/8, 203.0.113.7"
+                                data-testid="apikey-cidrs-input"
+                            />
+                            <p className="text-xs text-muted-foreground">
+                                Comma-separated CIDRs or IP addresses. Leave empty to allow the key from anywhere.
+                            </p>
+                        </div>
                         <SheetFooter>
                             <Button onClick={handleCreate} disabled={!newKeyName} data-testid="apikey-create-submit">Generate Key</Button>
                         </SheetFooter>
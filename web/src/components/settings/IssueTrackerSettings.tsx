@@ -0,0 +1,241 @@
+import { useState, useEffect, useMemo } from "react";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { Label } from "@/components/ui/label";
+import { Input } from "@/components/ui/input";
+import { Button } from "@/components/ui/button";
+import { Textarea } from "@/components/ui/textarea";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select";
+import { useToast } from "@/components/ui/use-toast";
+import { useMonitorStore } from "@/lib/store";
+import { Loader2, Copy } from "lucide-react";
+
+type Provider = "none" | "jira" | "github";
+
+export function IssueTrackerSettings() {
+    const { settings, fetchSettings, updateSettings } = useMonitorStore();
+    const { toast } = useToast();
+
+    const [provider, setProvider] = useState<Provider>("none");
+    const [titleTemplate, setTitleTemplate] = useState("");
+    const [bodyTemplate, setBodyTemplate] = useState("");
+    const [jiraBaseUrl, setJiraBaseUrl] = useState("");
+    const [jiraEmail, setJiraEmail] = useState("");
+    const [jiraProject, setJiraProject] = useState("");
+    const [jiraIssueType, setJiraIssueType] = useState("");
+    const [githubRepo, setGithubRepo] = useState("");
+    const [githubLabels, setGithubLabels] = useState("");
+    const [githubApiUrl, setGithubApiUrl] = useState("");
+    // Secrets are write-only: the API only reports whether they are configured
+    const [token, setToken] = useState("");
+    const [webhookSecret, setWebhookSecret] = useState("");
+    const [isSaving, setIsSaving] = useState(false);
+
+    useEffect(() => {
+        fetchSettings();
+    }, [fetchSettings]);
+
+    useEffect(() => {
+        if (settings) {
+            setProvider((settings["integrations.issues.provider"] || "none") as Provider);
+            setTitleTemplate(settings["integrations.issues.title_template"] || "");
+            setBodyTemplate(settings["integrations.issues.body_template"] || "");
+            setJiraBaseUrl(settings["integrations.jira.base_url"] || "");
+            setJiraEmail(settings["integrations.jira.email"] || "");
+            setJiraProject(settings["integrations.jira.project_key"] || "");
+            setJiraIssueType(settings["integrations.jira.issue_type"] || "");
+            setGithubRepo(settings["integrations.github.repo"] || "");
+            setGithubLabels(settings["integrations.github.labels"] || "");
+            setGithubApiUrl(settings["integrations.github.api_url"] || "");
+        }
+    }, [settings]);
+
+    const tokenConfigured = provider === "jira"
+        ? settings?.["integrations.jira.token_configured"] === "true"
+        : settings?.["integrations.github.token_configured"] === "true";
+    const secretConfigured = settings?.["integrations.issues.webhook_secret_configured"] === "true";
+
+    const webhookUrl = useMemo(() => `${window.location.origin}/api/integrations/issues/webhook`, []);
+
+    const handleSave = async () => {
+        setIsSaving(true);
+        try {
+            const settingsToUpdate: Record<string, string> = {
+                "integrations.issues.provider": provider === "none" ? "" : provider,
+                "integrations.issues.title_template": titleTemplate,
+                "integrations.issues.body_template": bodyTemplate,
+            };
+            if (provider === "jira") {
+                Object.assign(settingsToUpdate, {
+                    "integrations.jira.base_url": jiraBaseUrl,
+                    "integrations.jira.email": jiraEmail,
+                    "integrations.jira.project_key": jiraProject,
+                    "integrations.jira.issue_type": jiraIssueType,
+                });
+                if (token) settingsToUpdate["integrations.jira.api_token"] = token;
+            } else if (provider === "github") {
+                Object.assign(settingsToUpdate, {
+                    "integrations.github.repo": githubRepo,
+                    "integrations.github.labels": githubLabels,
+                    "integrations.github.api_url": githubApiUrl,
+                });
+                if (token) settingsToUpdate["integrations.github.token"] = token;
+            }
+            if (webhookSecret) {
+                settingsToUpdate["integrations.issues.webhook_secret"] = webhookSecret;
+            }
+
+            await updateSettings(settingsToUpdate);
+            toast({ title: "Issue Tracker Saved", description: "New incidents will open an issue with these settings." });
+            setToken("");
+            setWebhookSecret("");
+            await fetchSettings();
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save issue tracker settings", variant: "destructive" });
+        } finally {
+            setIsSaving(false);
+        }
+    };
+
+    const copyWebhookUrl = () => {
+        navigator.clipboard.writeText(webhookUrl);
+        toast({ title: "Copied", description: "Webhook URL copied to clipboard" });
+    };
+
+    return (
+        <Card>
+            <CardHeader>
+                <CardTitle>Issue Tracker</CardTitle>
+                <CardDescription>
+                    Open a Jira ticket or GitHub issue for every new incident.
+                </CardDescription>
+            </CardHeader>
+            <CardContent className="space-y-6">
+                <div className="grid gap-2">
+                    <Label>Provider</Label>
+                    <Select value={provider} onValueChange={(v) => setProvider(v as Provider)}>
+                        <SelectTrigger className="w-[200px]">
+                            <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                            <SelectItem value="none">Disabled</SelectItem>
+                            <SelectItem value="jira">Jira</SelectItem>
+                            <SelectItem value="github">GitHub</SelectItem>
+                        </SelectContent>
+                    </Select>
+                </div>
+
+                {provider === "jira" && (
+                    <div className="space-y-4 pt-4 border-t">
+                        <div className="grid gap-2">
+                            <Label htmlFor="jira-base-url">Jira URL</Label>
+                            <Input id="jira-base-url" value={jiraBaseUrl} onChange={(e) => setJiraBaseUrl(e.target.value)} placeholder="https://acme.atlassian.net" />
+                        </div>
+                        <div className="grid gap-2">
+                            <Label htmlFor="jira-email">Account Email</Label>
+                            <Input id="jira-email" value={jiraEmail} onChange={(e) => setJiraEmail(e.target.value)} placeholder="ops@example.com" />
+                            <p className="text-xs text-muted-foreground">
+                                Leave empty to send the token as a personal access token (Jira Data Center).
+                            </p>
+                        </div>
+                        <div className="grid grid-cols-2 gap-4">
+                            <div className="grid gap-2">
+                                <Label htmlFor="jira-project">Project Key</Label>
+                                <Input id="jira-project" value={jiraProject} onChange={(e) => setJiraProject(e.target.value)} placeholder="OPS" />
+                            </div>
+                            <div className="grid gap-2">
+                                <Label htmlFor="jira-issue-type">Issue Type</Label>
+                                <Input id="jira-issue-type" value={jiraIssueType} onChange={(e) => setJiraIssueType(e.target.value)} placeholder="Task" />
+                            </div>
+                        </div>
+                    </div>
+                )}
+
+                {provider === "github" && (
+                    <div className="space-y-4 pt-4 border-t">
+                        <div className="grid gap-2">
+                            <Label htmlFor="github-repo">Repository</Label>
+                            <Input id="github-repo" value={githubRepo} onChange={(e) => setGithubRepo(e.target.value)} placeholder="owner/name" />
+                        </div>
+                        <div className="grid gap-2">
+                            <Label htmlFor="github-labels">Labels</Label>
+                            <Input id="github-labels" value={githubLabels} onChange={(e) => setGithubLabels(e.target.value)} placeholder="incident" />
+                        </div>
+                        <div className="grid gap-2">
+                            <Label htmlFor="github-api-url">API URL (Optional)</Label>
+                            <Input id="github-api-url" value={githubApiUrl} onChange={(e) => setGithubApiUrl(e.target.value)} placeholder="https://api.github.com" />
+                            <p className="text-xs text-muted-foreground">
+                                Only needed for GitHub Enterprise Server.
+                            </p>
+                        </div>
+                    </div>
+                )}
+
+                {provider !== "none" && (
+                    <>
+                        <div className="grid gap-2">
+                            <Label htmlFor="issue-token">{provider === "jira" ? "API Token" : "Token"}</Label>
+                            <Input
+                                id="issue-token"
+                                type="password"
+                                value={token}
+                                onChange={(e) => setToken(e.target.value)}
+                                placeholder={tokenConfigured ? "(configured - enter new value to change)" : "Enter token"}
+                            />
+                        </div>
+
+                        <div className="space-y-4 pt-4 border-t">
+                            <h4 className="text-sm font-medium">Templates</h4>
+                            <div className="grid gap-2">
+                                <Label htmlFor="issue-title-template">Title</Label>
+                                <Input id="issue-title-template" value={titleTemplate} onChange={(e) => setTitleTemplate(e.target.value)} placeholder="[{{.Severity}}] {{.Title}}" className="font-mono text-xs" />
+                            </div>
+                            <div className="grid gap-2">
+                                <Label htmlFor="issue-body-template">Body</Label>
+                                <Textarea id="issue-body-template" value={bodyTemplate} onChange={(e) => setBodyTemplate(e.target.value)} placeholder="Leave empty for the default" rows={5} className="font-mono text-xs" />
+                                <p className="text-xs text-muted-foreground">
+                                    Go templates with .Title, .Description, .Severity, .Status, .Source, .StartTime and .AffectedGroups.
+                                </p>
+                            </div>
+                        </div>
+
+                        <div className="space-y-4 pt-4 border-t">
+                            <h4 className="text-sm font-medium">Resolution Sync (Optional)</h4>
+                            <p className="text-sm text-muted-foreground">
+                                Resolve the incident when its issue is closed. Add a webhook in {provider === "jira" ? "Jira for \"Issue updated\"" : "the repository for \"Issues\""} events with this URL and secret:
+                            </p>
+                            <div className="flex items-center gap-2 p-2 bg-muted/50 rounded border">
+                                <code className="text-xs flex-1 break-all">{webhookUrl}</code>
+                                <Button variant="ghost" size="sm" onClick={copyWebhookUrl} className="h-6 w-6 p-0">
+                                    <Copy className="h-3 w-3" />
+                                </Button>
+                            </div>
+                            <div className="grid gap-2">
+                                <Label htmlFor="issue-webhook-secret">Webhook Secret</Label>
+                                <Input
+                                    id="issue-webhook-secret"
+                                    type="password"
+                                    value={webhookSecret}
+                                    onChange={(e) => setWebhookSecret(e.target.value)}
+                                    placeholder={secretConfigured ? "(configured - enter new value to change)" : "Enter a shared secret"}
+                                />
+                            </div>
+                        </div>
+                    </>
+                )}
+
+                <div className="pt-4 border-t">
+                    <Button onClick={handleSave} disabled={isSaving}>
+                        {isSaving ? (
+                            <>
+                                <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                                Saving...
+                            </>
+                        ) : (
+                            "Save Issue Tracker"
+                        )}
+                    </Button>
+                </div>
+            </CardContent>
+        </Card>
+    );
+}
@@ -0,0 +1,210 @@
+import { useState, useEffect } from "react";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import { useMonitorStore } from "@/lib/store";
+import { useToast } from "@/components/ui/use-toast";
+import { Copy, Plus, Trash2, UserRound } from "lucide-react";
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table";
+import {
+    Sheet,
+    SheetContent,
+    SheetDescription,
+    SheetFooter,
+    SheetHeader,
+    SheetTitle,
+    SheetTrigger,
+} from "@/components/ui/sheet"
+import {
+    AlertDialog,
+    AlertDialogAction,
+    AlertDialogCancel,
+    AlertDialogContent,
+    AlertDialogDescription,
+    AlertDialogFooter,
+    AlertDialogHeader,
+    AlertDialogTitle,
+} from "@/components/ui/alert-dialog"
+
+function CreatePersonalTokenSheet() {
+    const { createPersonalToken, fetchPersonalTokens } = useMonitorStore();
+    const { toast } = useToast();
+    const [name, setName] = useState("");
+    const [createdToken, setCreatedToken] = useState<string | null>(null);
+    const [isOpen, setIsOpen] = useState(false);
+
+    const handleCreate = async () => {
+        if (!name) return;
+        const token = await createPersonalToken(name);
+        if (token) {
+            setCreatedToken(token);
+            setName("");
+            fetchPersonalTokens();
+        } else {
+            toast({ title: "Error", description: "Failed to create token", variant: "destructive" });
+        }
+    };
+
+    const copyToClipboard = (text: string) => {
+        navigator.clipboard.writeText(text);
+        toast({ title: "Copied", description: "Token copied to clipboard" });
+    };
+
+    const handleOpenChange = (open: boolean) => {
+        setIsOpen(open);
+        if (!open) {
+            setCreatedToken(null);
+        }
+    }
+
+    return (
+        <Sheet open={isOpen} onOpenChange={handleOpenChange}>
+            <SheetTrigger asChild>
+                <Button size="sm" variant="outline" data-testid="create-personal-token-trigger">
+                    <Plus className="w-4 h-4 mr-2" />
+                    Create Token
+                </Button>
+            </SheetTrigger>
+            <SheetContent>
+                <SheetHeader>
+                    <SheetTitle>Generate Personal Token</SheetTitle>
+                    <SheetDescription>
+                        Requests made with this token act as you.
+                    </SheetDescription>
+                </SheetHeader>
+
+                {!createdToken ? (
+                    <div className="grid gap-4 py-6">
+                        <div className="grid gap-2">
+                            <Label>Token Name</Label>
+                            <Input
+                                value={name}
+                                onChange={(e) => setName(e.target.value)}
+                                placeholder="e.g. Deploy script"
+                                data-testid="personal-token-name-input"
+                            />
+                        </div>
+                        <SheetFooter>
+                            <Button onClick={handleCreate} disabled={!name} data-testid="personal-token-create-submit">Generate Token</Button>
+                        </SheetFooter>
+                    </div>
+                ) : (
+                    <div className="py-6 space-y-4">
+                        <div className="space-y-2">
+                            <Label>Personal Token</Label>
+                            <div className="text-xs text-muted-foreground mb-1">
+                                Copy this now. It will not be shown again.
+                            </div>
+                            <div className="flex items-center gap-2">
+                                <code className="flex-1 p-3 bg-muted rounded border font-mono text-sm break-all">
+                                    {createdToken}
+                                </code>
+                                <Button size="icon" variant="outline" onClick={() => copyToClipboard(createdToken)}>
+                                    <Copy className="w-4 h-4" />
+                                </Button>
+                            </div>
+                        </div>
+                        <SheetFooter className="mt-4">
+                            <Button onClick={() => setIsOpen(false)} className="w-full">Done</Button>
+                        </SheetFooter>
+                    </div>
+                )}
+            </SheetContent>
+        </Sheet>
+    );
+}
+
+export function PersonalTokensView() {
+    const { personalTokens, fetchPersonalTokens, deletePersonalToken } = useMonitorStore();
+    const { toast } = useToast();
+    const [revokeId, setRevokeId] = useState<number | null>(null);
+
+    useEffect(() => {
+        fetchPersonalTokens();
+    }, [fetchPersonalTokens]);
+
+    const handleDelete = async () => {
+        if (revokeId === null) return;
+        await deletePersonalToken(revokeId);
+        await fetchPersonalTokens();
+        setRevokeId(null);
+        toast({ title: "Revoked", description: "Personal token revoked successfully." });
+    };
+
+    return (
+        <Card>
+            <CardHeader>
+                <div className="flex items-center justify-between">
+                    <div>
+                        <CardTitle>Personal Tokens</CardTitle>
+                        <CardDescription>Tokens for your own scripts. Actions taken with them are attributed to you.</CardDescription>
+                    </div>
+                    <CreatePersonalTokenSheet />
+                </div>
+            </CardHeader>
+            <CardContent>
+                {personalTokens.length > 0 ? (
+                    <Table>
+                        <TableHeader>
+                            <TableRow>
+                                <TableHead>Name</TableHead>
+                                <TableHead>Token Prefix</TableHead>
+                                <TableHead>Last Used</TableHead>
+                                <TableHead className="w-[50px]"></TableHead>
+                            </TableRow>
+                        </TableHeader>
+                        <TableBody>
+                            {personalTokens.map((token) => (
+                                <TableRow key={token.id}>
+                                    <TableCell className="font-medium">{token.name}</TableCell>
+                                    <TableCell>
+                                        <span className="font-mono text-xs text-muted-foreground">
+                                            {token.tokenPrefix}••••••••
+                                        </span>
+                                    </TableCell>
+                                    <TableCell className="text-muted-foreground">
+                                        {token.lastUsed ? new Date(token.lastUsed).toLocaleDateString() : "Never"}
+                                    </TableCell>
+                                    <TableCell>
+                                        <Button
+                                            variant="ghost"
+                                            size="icon"
+                                            className="h-8 w-8 text-destructive"
+                                            onClick={() => setRevokeId(token.id)}
+                                        >
+                                            <Trash2 className="h-4 w-4" />
+                                        </Button>
+                                    </TableCell>
+                                </TableRow>
+                            ))}
+                        </TableBody>
+                    </Table>
+                ) : (
+                    <div className="flex flex-col items-center justify-center p-12 border border-dashed border-border rounded-lg text-muted-foreground">
+                        <UserRound className="w-12 h-12 mb-4 opacity-50" />
+                        <h3 className="text-lg font-medium text-foreground mb-1">No Personal Tokens</h3>
+                        <p className="text-sm">Use a personal token instead of an API key when automation should act as you.</p>
+                    </div>
+                )}
+            </CardContent>
+
+            <AlertDialog open={revokeId !== null} onOpenChange={(open) => !open && setRevokeId(null)}>
+                <AlertDialogContent>
+                    <AlertDialogHeader>
+                        <AlertDialogTitle>Revoke this token?</AlertDialogTitle>
+                        <AlertDialogDescription>
+                            Scripts using it will lose access immediately.
+                        </AlertDialogDescription>
+                    </AlertDialogHeader>
+                    <AlertDialogFooter>
+                        <AlertDialogCancel>Cancel</AlertDialogCancel>
+                        <AlertDialogAction onClick={handleDelete} className="bg-red-600 hover:bg-red-700">
+                            Revoke Token
+                        </AlertDialogAction>
+                    </AlertDialogFooter>
+                </AlertDialogContent>
+            </AlertDialog>
+        </Card>
+    )
+}
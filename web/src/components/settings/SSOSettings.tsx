@@ -85,8 +85,8 @@ export function SSOSettings() {
 
             // Refetch to get updated state
             await fetchSettings();
-        } catch {
-            toast({ title: "Error", description: "Failed to save SSO settings", variant: "destructive" });
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save SSO settings", variant: "destructive" });
         } finally {
             setIsSaving(false);
         }
@@ -7,7 +7,9 @@ import { Switch } from "@/components/ui/switch";
 import { Tabs, TabsContent, TabsList, TabsTrigger } from "@/components/ui/tabs";
 import { SystemTab } from "./SystemTab";
 import { SSOSettings } from "./SSOSettings";
+import { IssueTrackerSettings } from "./IssueTrackerSettings";
 import { APIKeysView } from "./APIKeysView";
+import { PersonalTokensView } from "./PersonalTokensView";
 
 import { NotificationsView } from "@/components/notifications/NotificationsView";
 import { SelectTimezone } from "@/components/ui/select-timezone";
@@ -125,6 +127,8 @@ function GeneralSettings() {
     const { toast } = useToast();
     const [threshold, setThreshold] = useState(settings?.latency_threshold || "1000");
     const [retention, setRetention] = useState(settings?.data_retention_days || "30");
+    const [autoPublish, setAutoPublish] = useState(settings?.["incidents.auto_publish"] === "true");
+    const [weekStart, setWeekStart] = useState(settings?.["reports.week_start"] || "monday");
 
     // Fetch settings on mount
     useEffect(() => {
@@ -136,15 +140,23 @@ function GeneralSettings() {
         if (settings) {
             setThreshold(settings.latency_threshold || "1000");
             setRetention(settings.data_retention_days || "30");
+            setAutoPublish(settings["incidents.auto_publish"] === "true");
+            setWeekStart(settings["reports.week_start"] || "monday");
         }
     }, [settings]);
 
     const handleSave = async () => {
-        await updateSettings({
-            latency_threshold: threshold,
-            data_retention_days: retention
-        });
-        toast({ title: "Settings Saved", description: "Global settings updated." });
+        try {
+            await updateSettings({
+                latency_threshold: threshold,
+                data_retention_days: retention,
+                "incidents.auto_publish": autoPublish ? "true" : "false",
+                "reports.week_start": weekStart
+            });
+            toast({ title: "Settings Saved", description: "Global settings updated." });
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save settings", variant: "destructive" });
+        }
     };
 
     return (
@@ -180,6 +192,31 @@ function GeneralSettings() {
                         className="max-w-[200px]"
                     />
                 </div>
+                <div className="flex items-center justify-between gap-4 max-w-xl">
+                    <div className="min-w-0">
+                        <Label htmlFor="auto-publish">Publish Promoted Incidents Automatically</Label>
+                        <div className="text-sm text-muted-foreground">
+                            When off, incidents promoted from outages start private and wait for approval before appearing on status pages.
+                        </div>
+                    </div>
+                    <Switch id="auto-publish" checked={autoPublish} onCheckedChange={setAutoPublish} className="shrink-0" />
+                </div>
+                <div className="grid gap-2">
+                    <Label htmlFor="week-start">First Day of the Week</Label>
+                    <div className="text-sm text-muted-foreground mb-2">
+                        Where "this week" and "last week" start in uptime and cost reports.
+                    </div>
+                    <Select value={weekStart} onValueChange={setWeekStart}>
+                        <SelectTrigger id="week-start" className="max-w-[200px]">
+                            <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                            <SelectItem value="monday">Monday</SelectItem>
+                            <SelectItem value="sunday">Sunday</SelectItem>
+                            <SelectItem value="saturday">Saturday</SelectItem>
+                        </SelectContent>
+                    </Select>
+                </div>
                 <div className="rounded-lg border border-border/50 bg-muted/30 p-4">
                     <Label className="text-sm font-medium">SSL Certificate Warnings</Label>
                     <p className="text-sm text-muted-foreground mt-1">
@@ -192,6 +229,148 @@ function GeneralSettings() {
     );
 }
 
+function ReadOnlySettings() {
+    const { settings, fetchSettings, updateSettings, checkAuth } = useMonitorStore();
+    const { toast } = useToast();
+    const [enabled, setEnabled] = useState(settings?.["read_only.enabled"] === "true");
+    const [notice, setNotice] = useState(settings?.["read_only.notice"] || "");
+
+    useEffect(() => {
+        fetchSettings();
+    }, [fetchSettings]);
+
+    useEffect(() => {
+        if (settings) {
+            setEnabled(settings["read_only.enabled"] === "true");
+            setNotice(settings["read_only.notice"] || "");
+        }
+    }, [settings]);
+
+    const handleSave = async () => {
+        try {
+            await updateSettings({
+                "read_only.enabled": enabled ? "true" : "false",
+                "read_only.notice": notice
+            });
+            await checkAuth();
+            toast({ title: "Settings Saved", description: enabled ? "The API is now read-only." : "Changes are enabled again." });
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save settings", variant: "destructive" });
+        }
+    };
+
+    return (
+        <Card>
+            <CardHeader>
+                <CardTitle>Read-only Mode</CardTitle>
+                <CardDescription>Reject every change through the API and dashboard, e.g. during database maintenance. Monitoring keeps running.</CardDescription>
+            </CardHeader>
+            <CardContent className="space-y-4">
+                <div className="flex items-center justify-between gap-4 max-w-xl">
+                    <div className="min-w-0">
+                        <Label htmlFor="read-only">Read-only</Label>
+                        <div className="text-sm text-muted-foreground">
+                            Changes are answered with 503 and the notice below. Only this setting can still be changed.
+                        </div>
+                    </div>
+                    <Switch id="read-only" checked={enabled} onCheckedChange={setEnabled} className="shrink-0" />
+                </div>
+                <div className="grid gap-2">
+                    <Label htmlFor="read-only-notice">Notice</Label>
+                    <Input
+                        id="read-only-notice"
+                        value={notice}
+                        placeholder="Warden is in read-only mode. Changes are disabled until it is switched back."
+                        onChange={(e) => setNotice(e.target.value)}
+                        className="max-w-xl"
+                    />
+                </div>
+                <Button onClick={handleSave} className="w-fit">Save</Button>
+            </CardContent>
+        </Card>
+    );
+}
+
+function WatchdogSettings() {
+    const { settings, fetchSettings, updateSettings } = useMonitorStore();
+    const { toast } = useToast();
+    const [url, setUrl] = useState("");
+    const [intervalSeconds, setIntervalSeconds] = useState(settings?.["watchdog.interval_seconds"] || "60");
+    const configured = settings?.["watchdog.url_configured"] === "true";
+
+    useEffect(() => {
+        fetchSettings();
+    }, [fetchSettings]);
+
+    useEffect(() => {
+        if (settings) {
+            setIntervalSeconds(settings["watchdog.interval_seconds"] || "60");
+        }
+    }, [settings]);
+
+    const save = async (changes: Record<string, string>, description: string) => {
+        try {
+            await updateSettings(changes);
+            setUrl("");
+            toast({ title: "Settings Saved", description });
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save settings", variant: "destructive" });
+        }
+    };
+
+    const handleSave = () => {
+        const changes: Record<string, string> = { "watchdog.interval_seconds": intervalSeconds };
+        // The URL is write-only: leave it alone unless a new one was entered
+        if (url) {
+            changes["watchdog.url"] = url;
+        }
+        save(changes, "Watchdog updated.");
+    };
+
+    return (
+        <Card>
+            <CardHeader>
+                <CardTitle>Watchdog</CardTitle>
+                <CardDescription>Ping a dead man's switch, such as a Healthchecks.io check, so you hear about it when Warden itself stops.</CardDescription>
+            </CardHeader>
+            <CardContent className="space-y-4">
+                <div className="grid gap-2">
+                    <Label htmlFor="watchdog-url">Ping URL</Label>
+                    <div className="text-sm text-muted-foreground mb-2">
+                        {configured ? "A URL is configured. Enter a new one to replace it." : "Not configured."}
+                    </div>
+                    <Input
+                        id="watchdog-url"
+                        type="url"
+                        value={url}
+                        placeholder="https://hc-ping.com/your-uuid"
+                        onChange={(e) => setUrl(e.target.value)}
+                        className="max-w-xl"
+                    />
+                </div>
+                <div className="grid gap-2">
+                    <Label htmlFor="watchdog-interval">Interval (Seconds)</Label>
+                    <Input
+                        id="watchdog-interval"
+                        type="number"
+                        min={10}
+                        max={3600}
+                        value={intervalSeconds}
+                        onChange={(e) => setIntervalSeconds(e.target.value)}
+                        className="max-w-[200px]"
+                    />
+                </div>
+                <div className="flex gap-2">
+                    <Button onClick={handleSave} className="w-fit">Save</Button>
+                    {configured && (
+                        <Button variant="outline" onClick={() => save({ "watchdog.url": "" }, "Watchdog disabled.")}>Disable</Button>
+                    )}
+                </div>
+            </CardContent>
+        </Card>
+    );
+}
+
 const EVENT_TOGGLES = [
     { key: "notification.event.down.enabled", label: "Down", description: "Monitor is confirmed down" },
     { key: "notification.event.up.enabled", label: "Recovered", description: "Monitor recovered from down or degraded" },
@@ -199,6 +378,7 @@ const EVENT_TOGGLES = [
     { key: "notification.event.flapping.enabled", label: "Flapping", description: "Monitor oscillating between states" },
     { key: "notification.event.stabilized.enabled", label: "Stabilized", description: "Monitor stopped flapping" },
     { key: "notification.event.ssl_expiring.enabled", label: "SSL Expiring", description: "SSL certificate nearing expiry" },
+    { key: "notification.event.anomaly.enabled", label: "Latency Anomaly", description: "Latency well above the monitor's usual" },
 ] as const;
 
 const DIGEST_EVENT_OPTIONS = [
@@ -206,6 +386,7 @@ const DIGEST_EVENT_OPTIONS = [
     { value: "flapping", label: "Flapping" },
     { value: "stabilized", label: "Stabilized" },
     { value: "ssl_expiring", label: "SSL Expiring" },
+    { value: "anomaly", label: "Latency Anomaly" },
     { value: "down", label: "Down" },
     { value: "up", label: "Recovered" },
 ] as const;
@@ -221,6 +402,16 @@ function NotificationIntelligence() {
     const [flapThreshold, setFlapThreshold] = useState(settings?.["notification.flap_threshold_percent"] || "25");
     const [recoveryChecks, setRecoveryChecks] = useState(settings?.["notification.recovery_confirmation_checks"] || "1");
 
+    // Storm dampening (threshold 0 = disabled)
+    const [stormEnabled, setStormEnabled] = useState(Number(settings?.["notification.storm.threshold_percent"] || "0") > 0);
+    const [stormThreshold, setStormThreshold] = useState(settings?.["notification.storm.threshold_percent"] || "50");
+    const [stormWindow, setStormWindow] = useState(settings?.["notification.storm.window_seconds"] || "60");
+    const [stormMinMonitors, setStormMinMonitors] = useState(settings?.["notification.storm.min_monitors"] || "5");
+
+    // Latency anomaly detection (deviations 0 = disabled)
+    const [anomalyEnabled, setAnomalyEnabled] = useState(Number(settings?.["notification.anomaly_deviations"] || "0") > 0);
+    const [anomalyDeviations, setAnomalyDeviations] = useState(settings?.["notification.anomaly_deviations"] || "5");
+
     // Event type toggles
     const [eventToggles, setEventToggles] = useState<Record<string, boolean>>(() => {
         const toggles: Record<string, boolean> = {};
@@ -238,6 +429,9 @@ function NotificationIntelligence() {
         return new Set(types.split(",").map(t => t.trim()).filter(Boolean));
     });
 
+    // Maintenance reminders (empty = disabled)
+    const [reminderHours, setReminderHours] = useState(settings?.["notification.maintenance.reminder_hours"] ?? "24,1");
+
     useEffect(() => {
         fetchSettings();
     }, [fetchSettings]);
@@ -250,6 +444,14 @@ function NotificationIntelligence() {
             setFlapWindow(settings["notification.flap_window_checks"] || "21");
             setFlapThreshold(settings["notification.flap_threshold_percent"] || "25");
             setRecoveryChecks(settings["notification.recovery_confirmation_checks"] || "1");
+            const storm = settings["notification.storm.threshold_percent"] || "0";
+            setStormEnabled(Number(storm) > 0);
+            setStormThreshold(Number(storm) > 0 ? storm : "50");
+            setStormWindow(settings["notification.storm.window_seconds"] || "60");
+            setStormMinMonitors(settings["notification.storm.min_monitors"] || "5");
+            const deviations = settings["notification.anomaly_deviations"] || "0";
+            setAnomalyEnabled(Number(deviations) > 0);
+            setAnomalyDeviations(Number(deviations) > 0 ? deviations : "5");
 
             const toggles: Record<string, boolean> = {};
             EVENT_TOGGLES.forEach(({ key }) => {
@@ -261,6 +463,7 @@ function NotificationIntelligence() {
             setDigestTime(settings["notification.digest.time"] || "09:00");
             const types = settings["notification.digest.event_types"] || "degraded,flapping,stabilized,ssl_expiring";
             setDigestEventTypes(new Set(types.split(",").map(t => t.trim()).filter(Boolean)));
+            setReminderHours(settings["notification.maintenance.reminder_hours"] ?? "24,1");
         }
     }, [settings]);
 
@@ -272,17 +475,26 @@ function NotificationIntelligence() {
             "notification.flap_window_checks": flapWindow,
             "notification.flap_threshold_percent": flapThreshold,
             "notification.recovery_confirmation_checks": recoveryChecks,
+            "notification.storm.threshold_percent": stormEnabled ? stormThreshold : "0",
+            "notification.storm.window_seconds": stormWindow,
+            "notification.storm.min_monitors": stormMinMonitors,
+            "notification.anomaly_deviations": anomalyEnabled ? anomalyDeviations : "0",
             "notification.digest.enabled": digestEnabled ? "true" : "false",
             "notification.digest.time": digestTime,
             "notification.digest.event_types": Array.from(digestEventTypes).join(","),
+            "notification.maintenance.reminder_hours": reminderHours,
         };
 
         EVENT_TOGGLES.forEach(({ key }) => {
             updates[key] = eventToggles[key] ? "true" : "false";
         });
 
-        await updateSettings(updates);
-        toast({ title: "Settings Saved", description: "Notification intelligence settings updated." });
+        try {
+            await updateSettings(updates);
+            toast({ title: "Settings Saved", description: "Notification intelligence settings updated." });
+        } catch (error) {
+            toast({ title: "Error", description: error instanceof Error ? error.message : "Failed to save settings", variant: "destructive" });
+        }
     };
 
     const toggleDigestEventType = (type: string) => {
@@ -423,6 +635,98 @@ function NotificationIntelligence() {
                     </div>
                 )}
                 <Separator />
+                <div className="flex items-center justify-between">
+                    <div className="space-y-1">
+                        <Label>Latency Anomaly Detection</Label>
+                        <p className="text-sm text-muted-foreground">
+                            Learn each monitor's usual latency and alert when it stays well above it for 3 checks, even below the latency threshold.
+                        </p>
+                    </div>
+                    <Switch
+                        checked={anomalyEnabled}
+                        onCheckedChange={setAnomalyEnabled}
+                    />
+                </div>
+                {anomalyEnabled && (
+                    <div className="grid gap-2 pl-1">
+                        <Label htmlFor="anomaly-deviations">Sensitivity (deviations)</Label>
+                        <div className="text-sm text-muted-foreground mb-1">
+                            How far above the usual latency, in typical deviations, counts as anomalous. Lower is more sensitive.
+                        </div>
+                        <Input
+                            id="anomaly-deviations"
+                            type="number"
+                            min={1}
+                            max={20}
+                            value={anomalyDeviations}
+                            onChange={(e) => setAnomalyDeviations(e.target.value)}
+                            className="max-w-[160px]"
+                        />
+                    </div>
+                )}
+                <Separator />
+                <div className="flex items-center justify-between">
+                    <div className="space-y-1">
+                        <Label>Storm Dampening</Label>
+                        <p className="text-sm text-muted-foreground">
+                            When many monitors go down at once, e.g. during a network outage, send a single "platform event" alert and open one incident instead of alerting for each monitor. Down alerts are held for the window while this is on.
+                        </p>
+                    </div>
+                    <Switch
+                        checked={stormEnabled}
+                        onCheckedChange={setStormEnabled}
+                    />
+                </div>
+                {stormEnabled && (
+                    <div className="grid grid-cols-3 gap-4 pl-1">
+                        <div className="grid gap-2">
+                            <Label htmlFor="storm-threshold">Threshold (%)</Label>
+                            <div className="text-sm text-muted-foreground mb-1">
+                                Share of all monitors going down.
+                            </div>
+                            <Input
+                                id="storm-threshold"
+                                type="number"
+                                min={1}
+                                max={100}
+                                value={stormThreshold}
+                                onChange={(e) => setStormThreshold(e.target.value)}
+                                className="max-w-[160px]"
+                            />
+                        </div>
+                        <div className="grid gap-2">
+                            <Label htmlFor="storm-window">Window (seconds)</Label>
+                            <div className="text-sm text-muted-foreground mb-1">
+                                Time within which they go down.
+                            </div>
+                            <Input
+                                id="storm-window"
+                                type="number"
+                                min={1}
+                                max={3600}
+                                value={stormWindow}
+                                onChange={(e) => setStormWindow(e.target.value)}
+                                className="max-w-[160px]"
+                            />
+                        </div>
+                        <div className="grid gap-2">
+                            <Label htmlFor="storm-min-monitors">Minimum monitors</Label>
+                            <div className="text-sm text-muted-foreground mb-1">
+                                Fewest monitors that make a storm.
+                            </div>
+                            <Input
+                                id="storm-min-monitors"
+                                type="number"
+                                min={2}
+                                max={10000}
+                                value={stormMinMonitors}
+                                onChange={(e) => setStormMinMonitors(e.target.value)}
+                                className="max-w-[160px]"
+                            />
+                        </div>
+                    </div>
+                )}
+                <Separator />
                 <div className="flex items-center justify-between">
                     <div className="space-y-1">
                         <Label>Daily Digest</Label>
@@ -469,6 +773,20 @@ function NotificationIntelligence() {
                         </div>
                     </div>
                 )}
+                <Separator />
+                <div className="grid gap-2">
+                    <Label htmlFor="maintenance-reminder-hours">Maintenance Reminders</Label>
+                    <div className="text-sm text-muted-foreground mb-1">
+                        Hours before a maintenance window starts to send a reminder, comma-separated (e.g. 24,1). Leave empty to disable.
+                    </div>
+                    <Input
+                        id="maintenance-reminder-hours"
+                        value={reminderHours}
+                        onChange={(e) => setReminderHours(e.target.value)}
+                        placeholder="24,1"
+                        className="max-w-[160px]"
+                    />
+                </div>
                 <Button onClick={handleSave} className="w-fit">Save Settings</Button>
             </CardContent>
         </Card>
@@ -604,15 +922,19 @@ export function SettingsView() {
                 <TabsContent value="notifications" className="space-y-6 mt-6">
                     <NotificationsView />
                     <NotificationIntelligence />
+                    <IssueTrackerSettings />
                 </TabsContent>
 
                 <TabsContent value="security" className="space-y-6 mt-6">
                     <APIKeysView />
+                    <PersonalTokensView />
                     <SSOSettings />
                 </TabsContent>
 
                 <TabsContent value="system" className="space-y-6 mt-6">
                     <SystemTab />
+                    <ReadOnlySettings />
+                    <WatchdogSettings />
 
                     <Card className="border-destructive/50">
                         <CardHeader>
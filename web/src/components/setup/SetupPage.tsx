@@ -32,7 +32,7 @@ function PasswordRequirement({ met, label }: { met: boolean; label: string }) {
 }
 
 export function SetupPage() {
-    const { performSetup } = useMonitorStore();
+    const { performSetup, setupTokenRequired } = useMonitorStore();
 
     // States - simplified to 2 steps: Welcome (0) and Account (1)
     const [step, setStep] = useState(0);
@@ -44,6 +44,7 @@ export function SetupPage() {
         username: 'admin', // Pre-filled for convenience
         password: '',
         timezone: Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC',
+        setupToken: '',
     });
 
     // Real-time password validation
@@ -69,6 +70,10 @@ export function SetupPage() {
             setError("Please meet all password requirements.");
             return;
         }
+        if (setupTokenRequired && !formData.setupToken) {
+            setError("Please enter the setup token.");
+            return;
+        }
 
         setLoading(true);
 
@@ -146,6 +151,23 @@ export function SetupPage() {
                                 </div>
                             </div>
 
+                            {setupTokenRequired && (
+                                <div className="grid gap-2">
+                                    <Label htmlFor="setup-token">Setup Token</Label>
+                                    <Input
+                                        id="setup-token"
+                                        type="password"
+                                        value={formData.setupToken}
+                                        onChange={(e) => setFormData({ ...formData, setupToken: e.target.value })}
+                                        placeholder="WARDEN_SETUP_TOKEN"
+                                        data-testid="setup-token-input"
+                                    />
+                                    <p className="text-xs text-muted-foreground">
+                                        This server was started with a setup token. Ask whoever deployed it.
+                                    </p>
+                                </div>
+                            )}
+
                             {error && (
                                 <Alert variant="destructive" className="animate-in fade-in zoom-in-95">
                                     <AlertCircle className="h-5 w-5" />
@@ -1,13 +1,13 @@
 import { Badge } from "@/components/ui/badge";
 import { Collapsible, CollapsibleContent, CollapsibleTrigger } from "@/components/ui/collapsible";
-import { cn, formatDate } from "@/lib/utils";
+import { cn, formatDate, formatDuration } from "@/lib/utils";
 import { Incident, IncidentUpdate } from "@/lib/store";
 import { CheckCircle2, ChevronDown, Clock } from "lucide-react";
 import { useState } from "react";
 
 interface PastIncident extends Incident {
     updates?: IncidentUpdate[];
-    duration?: string;
+    durationSeconds?: number;
 }
 
 interface PastIncidentsSectionProps {
@@ -83,6 +83,9 @@ function IncidentTimeline({ updates, timezone }: { updates?: IncidentUpdate[]; t
                             <span className="text-[10px] text-muted-foreground tabular-nums">
                                 {formatDate(update.createdAt, timezone)}
                             </span>
+                            {update.editedAt && (
+                                <span className="text-[10px] text-muted-foreground/70 italic">edited</span>
+                            )}
                         </div>
                         <p className="text-xs text-muted-foreground">{update.message}</p>
                     </div>
@@ -130,10 +133,10 @@ function PastIncidentCard({ incident, timezone }: { incident: PastIncident; time
                             )}
                         </div>
                         <div className="flex items-center gap-3 shrink-0">
-                            {incident.duration && (
+                            {!!incident.durationSeconds && (
                                 <span className="text-xs text-muted-foreground tabular-nums flex items-center gap-1">
                                     <Clock className="w-3 h-3" />
-                                    {incident.duration}
+                                    {formatDuration(incident.durationSeconds)}
                                 </span>
                             )}
                             {hasUpdates && (
@@ -19,6 +19,7 @@ interface DayData {
 interface StatusMonitor extends Monitor {
     uptimeDays?: DayData[];
     overallUptime?: number;
+    statusMessage?: string;
 }
 
 interface StatusGroup extends Omit<Group, "monitors"> {
@@ -42,7 +43,7 @@ function getMaintenanceState(incidents: Incident[]) {
     return { maintenanceIncidents, activeMaintenance, maintenanceGroupIds };
 }
 
-function getOverallStatus(groups: StatusGroup[], incidents: Incident[], maintenanceGroupIds: Set<string>) {
+function getOverallStatus(groups: StatusGroup[], incidents: Incident[], maintenanceGroupIds: Set<string>, labels?: Record<string, string>) {
     const effectiveIncidents = (incidents || []).filter((i) => {
         if (i.type !== "incident" || i.status === "resolved") return false;
         if (!i.affectedGroups || i.affectedGroups.length === 0) return true;
@@ -61,7 +62,7 @@ function getOverallStatus(groups: StatusGroup[], incidents: Incident[], maintena
     if (isUnderMaintenance && !hasActiveOutage && !hasDown) {
         return {
             icon: RefreshCw,
-            label: "System Under Maintenance",
+            label: labels?.["overall.maintenance"] ?? "System Under Maintenance",
             description: "Scheduled maintenance is currently in progress.",
             color: "blue" as const,
         };
@@ -69,7 +70,7 @@ function getOverallStatus(groups: StatusGroup[], incidents: Incident[], maintena
     if (hasActiveOutage || hasDown) {
         return {
             icon: XCircle,
-            label: "System Outage",
+            label: labels?.["overall.down"] ?? "System Outage",
             description: "Some systems are experiencing issues.",
             color: "red" as const,
         };
@@ -77,14 +78,14 @@ function getOverallStatus(groups: StatusGroup[], incidents: Incident[], maintena
     if (hasDegraded) {
         return {
             icon: AlertTriangle,
-            label: "Partially Degraded Service",
+            label: labels?.["overall.degraded"] ?? "Partially Degraded Service",
             description: "Some monitors are reporting degraded performance.",
             color: "yellow" as const,
         };
     }
     return {
         icon: CheckCircle2,
-        label: "All Systems Operational",
+        label: labels?.["overall.operational"] ?? "All Systems Operational",
         description: "All monitors are running normally.",
         color: "green" as const,
     };
@@ -153,7 +154,7 @@ function StatusBanner({
     );
 }
 
-function MaintenanceCard({ incident }: { incident: Incident }) {
+function MaintenanceCard({ incident, timezone }: { incident: Incident; timezone?: string }) {
     const start = new Date(incident.startTime);
     const end = incident.endTime ? new Date(incident.endTime) : null;
     const now = new Date();
@@ -190,9 +191,9 @@ function MaintenanceCard({ incident }: { incident: Incident }) {
                 </div>
             </div>
             <div className="text-[11px] text-muted-foreground tabular-nums font-mono whitespace-nowrap hidden sm:block">
-                {formatDate(incident.startTime)}
+                {formatDate(incident.startTime, timezone)}
                 {incident.endTime && (
-                    <> &mdash; {formatDate(incident.endTime)}</>
+                    <> &mdash; {formatDate(incident.endTime, timezone)}</>
                 )}
             </div>
         </div>
@@ -285,30 +286,32 @@ function MonitorRow({
     isMaintenance,
     showUptimeBars = true,
     showUptimePercentage = true,
+    labels,
 }: {
     monitor: StatusMonitor;
     isMaintenance?: boolean;
     showUptimeBars?: boolean;
     showUptimePercentage?: boolean;
+    labels?: Record<string, string>;
 }) {
     let statusColor = "text-emerald-500";
-    let statusLabel = "Operational";
+    let statusLabel = labels?.["status.operational"] ?? "Operational";
     let StatusIcon = CheckCircle2;
     if (isMaintenance) {
         statusColor = "text-blue-500";
-        statusLabel = "Maintenance";
+        statusLabel = labels?.["status.maintenance"] ?? "Maintenance";
         StatusIcon = Wrench;
     } else if (monitor.status === "degraded") {
         statusColor = "text-yellow-500";
-        statusLabel = "Degraded";
+        statusLabel = labels?.["status.degraded"] ?? "Degraded";
         StatusIcon = AlertTriangle;
     } else if (monitor.status === "down") {
         statusColor = "text-red-500";
-        statusLabel = "Down";
+        statusLabel = labels?.["status.down"] ?? "Down";
         StatusIcon = XCircle;
     } else if (monitor.status === "paused") {
         statusColor = "text-muted-foreground/50";
-        statusLabel = "Paused";
+        statusLabel = labels?.["status.paused"] ?? "Paused";
         StatusIcon = Minus;
     }
 
@@ -332,6 +335,9 @@ function MonitorRow({
                 </div>
                 <span className="text-xs text-muted-foreground hidden sm:inline shrink-0">{statusLabel}</span>
             </div>
+            {monitor.statusMessage && (
+                <p className="text-xs text-muted-foreground mb-1">{monitor.statusMessage}</p>
+            )}
 
             {/* Uptime bar (full width, below name) */}
             {showUptimeBars && uptimeDays.length > 0 && (
@@ -347,12 +353,14 @@ function GroupSection({
     index,
     showUptimeBars = true,
     showUptimePercentage = true,
+    labels,
 }: {
     group: StatusGroup;
     incidents: Incident[];
     index: number;
     showUptimeBars?: boolean;
     showUptimePercentage?: boolean;
+    labels?: Record<string, string>;
 }) {
     const now = new Date();
     const isGroupMaintenance =
@@ -384,6 +392,7 @@ function GroupSection({
                         isMaintenance={isGroupMaintenance}
                         showUptimeBars={showUptimeBars}
                         showUptimePercentage={showUptimePercentage}
+                        labels={labels}
                     />
                 ))}
                 {group.monitors.length === 0 && (
@@ -424,6 +433,7 @@ export function StatusPage() {
         incidents: Incident[];
         pastIncidents?: Incident[];
         config?: StatusPageConfig;
+        timezone?: string;
     } | null>(null);
     const [secondsToUpdate, setSecondsToUpdate] = useState(60);
 
@@ -485,6 +495,27 @@ export function StatusPage() {
         }
     }, []);
 
+    // Apply search engine metadata (robots, description, link preview)
+    const applySeo = useCallback((config?: StatusPageConfig) => {
+        const setMeta = (attr: "name" | "property", key: string, content?: string) => {
+            let el = document.querySelector<HTMLMetaElement>(`meta[${attr}="${key}"]`);
+            if (!content) {
+                el?.remove();
+                return;
+            }
+            if (!el) {
+                el = document.createElement('meta');
+                el.setAttribute(attr, key);
+                document.head.appendChild(el);
+            }
+            el.content = content;
+        };
+        setMeta("name", "robots", config?.seo?.noindex ? "noindex, nofollow" : undefined);
+        setMeta("name", "description", config?.seo?.metaDescription);
+        setMeta("property", "og:description", config?.seo?.metaDescription);
+        setMeta("property", "og:image", config?.seo?.ogImageUrl);
+    }, []);
+
     useEffect(() => {
         let isMounted = true;
 
@@ -499,6 +530,7 @@ export function StatusPage() {
                     applyTheme(result.config);
                     applyAccentColor(result.config);
                     applyFavicon(result.config, result.title);
+                    applySeo(result.config);
                 } else {
                     setError("Status page not found or private.");
                 }
@@ -534,7 +566,7 @@ export function StatusPage() {
             if (faviconLink) faviconLink.href = '/favicon.ico';
             document.title = 'Warden';
         };
-    }, [slug, fetchPublicStatusBySlug, applyTheme, applyAccentColor, applyFavicon]);
+    }, [slug, fetchPublicStatusBySlug, applyTheme, applyAccentColor, applyFavicon, applySeo]);
 
     // Listen for system theme changes when using 'system' theme
     useEffect(() => {
@@ -553,7 +585,7 @@ export function StatusPage() {
         if (!data) return null;
         const { groups, incidents = [], pastIncidents = [], config } = data;
         const { maintenanceIncidents, maintenanceGroupIds } = getMaintenanceState(incidents);
-        const status = getOverallStatus(groups, incidents, maintenanceGroupIds);
+        const status = getOverallStatus(groups, incidents, maintenanceGroupIds, config?.labels);
 
         const incidentItems = (incidents || []).filter((i) => {
             if (i.type !== "incident" || i.status === "resolved") return false;
@@ -666,6 +698,23 @@ export function StatusPage() {
                     <StatusBanner status={status} secondsToUpdate={secondsToUpdate} />
                 </div>
 
+                {/* Uptime (SLA) Banner */}
+                {config?.showSla && config.sla && (
+                    <div className="mb-6 rounded-lg border bg-card px-4 py-3 flex items-center justify-between gap-4">
+                        <div className="min-w-0">
+                            <p className="text-sm font-medium text-foreground">
+                                {config.labels?.['label.sla'] ?? `Uptime over the last ${config.sla.days} days`}
+                            </p>
+                            <p className="text-xs text-muted-foreground mt-0.5">
+                                {config.labels?.['label.sla_methodology'] ?? config.sla.methodology}
+                            </p>
+                        </div>
+                        <span className="text-2xl font-semibold tabular-nums text-foreground shrink-0">
+                            {config.sla.uptimePercent != null ? `${config.sla.uptimePercent.toFixed(2)}%` : '—'}
+                        </span>
+                    </div>
+                )}
+
                 {/* Alerts: Maintenance & Incidents */}
                 {(maintenanceIncidents.length > 0 || incidentItems.length > 0) && (
                     <div className="mb-8 space-y-6 animate-in slide-in-from-bottom-3 duration-500 fade-in fill-mode-backwards">
@@ -680,7 +729,7 @@ export function StatusPage() {
                                 </h2>
                                 <div className="space-y-2">
                                     {maintenanceIncidents.map((i) => (
-                                        <MaintenanceCard key={i.id} incident={i} />
+                                        <MaintenanceCard key={i.id} incident={i} timezone={data?.timezone} />
                                     ))}
                                 </div>
                             </div>
@@ -715,6 +764,7 @@ export function StatusPage() {
                             index={idx}
                             showUptimeBars={showUptimeBars}
                             showUptimePercentage={showUptimePercentage}
+                            labels={config?.labels}
                         />
                     ))}
                 </div>
@@ -722,7 +772,7 @@ export function StatusPage() {
                 {/* Past Incidents */}
                 {showIncidentHistory && pastIncidents && pastIncidents.length > 0 && (
                     <div className="mt-10">
-                        <PastIncidentsSection incidents={pastIncidents} />
+                        <PastIncidentsSection incidents={pastIncidents} timezone={data?.timezone} />
                     </div>
                 )}
             </main>
@@ -741,16 +791,27 @@ export function StatusPage() {
                             Warden
                         </a>
                     </div>
-                    <a
-                        href={`/api/s/${slug}/rss`}
-                        target="_blank"
-                        rel="noopener noreferrer"
-                        className="flex items-center gap-1 hover:text-foreground transition-colors"
-                        title="Subscribe via RSS"
-                    >
-                        <Rss className="w-3 h-3" />
-                        <span>RSS</span>
-                    </a>
+                    <div className="flex items-center gap-3">
+                        <a
+                            href={`/api/s/${slug}/incidents.json`}
+                            target="_blank"
+                            rel="noopener noreferrer"
+                            className="hover:text-foreground transition-colors"
+                            title="Incident history (JSON:API)"
+                        >
+                            JSON
+                        </a>
+                        <a
+                            href={`/api/s/${slug}/rss`}
+                            target="_blank"
+                            rel="noopener noreferrer"
+                            className="flex items-center gap-1 hover:text-foreground transition-colors"
+                            title="Subscribe via RSS"
+                        >
+                            <Rss className="w-3 h-3" />
+                            <span>RSS</span>
+                        </a>
+                    </div>
                 </div>
             </footer>
         </div>
@@ -33,10 +33,15 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
     const [showUptimeBars, setShowUptimeBars] = useState(true);
     const [showUptimePercentage, setShowUptimePercentage] = useState(true);
     const [showIncidentHistory, setShowIncidentHistory] = useState(true);
+    const [showSla, setShowSla] = useState(false);
     const [uptimeDaysRange, setUptimeDaysRange] = useState(90);
     const [headerContent, setHeaderContent] = useState<'logo-title' | 'logo-only' | 'title-only'>("logo-title");
     const [headerAlignment, setHeaderAlignment] = useState<'left' | 'center' | 'right'>("center");
     const [headerArrangement, setHeaderArrangement] = useState<'stacked' | 'inline'>("inline");
+    const [locale, setLocale] = useState("en");
+    const [noindex, setNoindex] = useState(false);
+    const [metaDescription, setMetaDescription] = useState("");
+    const [ogImageUrl, setOgImageUrl] = useState("");
 
     // Preview state
     const [logoError, setLogoError] = useState(false);
@@ -54,29 +59,26 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
             setShowUptimeBars(page.showUptimeBars ?? true);
             setShowUptimePercentage(page.showUptimePercentage ?? true);
             setShowIncidentHistory(page.showIncidentHistory ?? true);
+            setShowSla(page.showSla ?? false);
             setUptimeDaysRange(page.uptimeDaysRange ?? 90);
             setHeaderContent(page.headerContent || "logo-title");
             setHeaderAlignment(page.headerAlignment || "center");
             setHeaderArrangement(page.headerArrangement || "inline");
+            setLocale(page.locale || "en");
+            setNoindex(page.noindex ?? false);
+            setMetaDescription(page.metaDescription || "");
+            setOgImageUrl(page.ogImageUrl || "");
             setLogoError(false);
             setFaviconError(false);
         }
     }, [page]);
 
-    const resolveSlug = (slug: string, title: string) => {
-        if (slug.startsWith('g-')) {
-            return title.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-+|-+$/g, '') || slug;
-        }
-        return slug;
-    };
-
     const handleSave = async () => {
         if (!page) return;
 
         try {
-            const targetSlug = resolveSlug(page.slug, title);
             await toggleMutation.mutateAsync({
-                slug: targetSlug,
+                slug: page.slug,
                 public: page.public,
                 enabled: page.enabled,
                 title,
@@ -91,10 +93,15 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
                 showUptimeBars,
                 showUptimePercentage,
                 showIncidentHistory,
+                showSla,
                 uptimeDaysRange,
                 headerContent,
                 headerAlignment,
                 headerArrangement,
+                locale,
+                noindex,
+                metaDescription,
+                ogImageUrl,
             });
             toast({
                 title: "Configuration Saved",
@@ -329,6 +336,22 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
                                 </SelectContent>
                             </Select>
                         </div>
+
+                        <div className="space-y-2">
+                            <Label htmlFor="locale">Language</Label>
+                            <Select value={locale} onValueChange={setLocale}>
+                                <SelectTrigger id="locale">
+                                    <SelectValue />
+                                </SelectTrigger>
+                                <SelectContent>
+                                    <SelectItem value="en">English</SelectItem>
+                                    <SelectItem value="es">Español</SelectItem>
+                                    <SelectItem value="fr">Français</SelectItem>
+                                    <SelectItem value="de">Deutsch</SelectItem>
+                                    <SelectItem value="pt">Português</SelectItem>
+                                </SelectContent>
+                            </Select>
+                        </div>
                     </div>
 
                     {/* Display Options Section */}
@@ -399,6 +422,61 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
                                     className="shrink-0"
                                 />
                             </div>
+
+                            <div className="flex items-center justify-between gap-4">
+                                <div className="min-w-0">
+                                    <Label htmlFor="showSla" className="cursor-pointer">Show 90-Day Uptime Banner</Label>
+                                    <p className="text-xs text-muted-foreground">Publish rolling uptime across all monitors, excluding announced maintenance</p>
+                                </div>
+                                <Switch
+                                    id="showSla"
+                                    checked={showSla}
+                                    onCheckedChange={setShowSla}
+                                    className="shrink-0"
+                                />
+                            </div>
+                        </div>
+                    </div>
+
+                    {/* Search Engines Section */}
+                    <div className="space-y-4">
+                        <h3 className="text-sm font-semibold text-muted-foreground uppercase tracking-wider">
+                            Search Engines
+                        </h3>
+
+                        <div className="flex items-center justify-between gap-4">
+                            <div className="min-w-0">
+                                <Label htmlFor="noindex" className="cursor-pointer">Hide from Search Engines</Label>
+                                <p className="text-xs text-muted-foreground">Ask crawlers not to index this page</p>
+                            </div>
+                            <Switch
+                                id="noindex"
+                                checked={noindex}
+                                onCheckedChange={setNoindex}
+                                className="shrink-0"
+                            />
+                        </div>
+
+                        <div className="space-y-2">
+                            <Label htmlFor="metaDescription">Meta Description</Label>
+                            <Textarea
+                                id="metaDescription"
+                                value={metaDescription}
+                                onChange={(e) => setMetaDescription(e.target.value)}
+                                placeholder="Defaults to the page description"
+                                maxLength={300}
+                                rows={2}
+                            />
+                        </div>
+
+                        <div className="space-y-2">
+                            <Label htmlFor="ogImageUrl">Link Preview Image</Label>
+                            <Input
+                                id="ogImageUrl"
+                                value={ogImageUrl}
+                                onChange={(e) => setOgImageUrl(e.target.value)}
+                                placeholder="https://example.com/og-image.png"
+                            />
                         </div>
                     </div>
                 </div>
@@ -409,7 +487,7 @@ export function StatusPageConfigDialog({ page, open, onOpenChange }: StatusPageC
                     </Button>
                     <Button
                         onClick={handleSave}
-                        disabled={toggleMutation.isPending || !title.trim() || !isValidImageUrl(logoUrl) || !isValidImageUrl(faviconUrl)}
+                        disabled={toggleMutation.isPending || !title.trim() || !isValidImageUrl(logoUrl) || !isValidImageUrl(faviconUrl) || (!!ogImageUrl && !/^https?:\/\//.test(ogImageUrl))}
                         className="w-full sm:w-auto"
                     >
                         {toggleMutation.isPending && <Loader2 className="w-4 h-4 mr-2 animate-spin" />}
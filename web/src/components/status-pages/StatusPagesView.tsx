@@ -20,19 +20,11 @@ export function StatusPagesView() {
         setConfigDialogOpen(true);
     };
 
-    const resolveSlug = (slug: string, title: string) => {
-        if (slug.startsWith('g-')) {
-            return title.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-+|-+$/g, '') || slug;
-        }
-        return slug;
-    };
-
     const handleToggleEnabled = async (page: typeof pages[0]) => {
         try {
             const newEnabled = !page.enabled;
-            const targetSlug = resolveSlug(page.slug, page.title);
             await toggleMutation.mutateAsync({
-                slug: targetSlug,
+                slug: page.slug,
                 public: page.public,
                 enabled: newEnabled,
                 title: page.title,
@@ -50,9 +42,8 @@ export function StatusPagesView() {
     const handleTogglePublic = async (page: typeof pages[0]) => {
         try {
             const newPublic = !page.public;
-            const targetSlug = resolveSlug(page.slug, page.title);
             await toggleMutation.mutateAsync({
-                slug: targetSlug,
+                slug: page.slug,
                 public: newPublic,
                 enabled: page.enabled,
                 title: page.title,
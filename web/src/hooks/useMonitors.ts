@@ -1,5 +1,5 @@
 import { useQuery } from "@tanstack/react-query";
-import { Group, RequestConfig, useMonitorStore } from "@/lib/store";
+import { CheckTiming, Group, RequestConfig, useMonitorStore } from "@/lib/store";
 import { computePollingInterval } from "@/lib/pollingInterval";
 
 const API_URL = import.meta.env.VITE_API_URL || "";
@@ -93,6 +93,24 @@ interface CreateMonitorPayload {
     notificationCooldownMinutes?: number;
     latencyThreshold?: number;
     requestConfig?: RequestConfig;
+    allowDuplicateUrl?: boolean;
+}
+
+export interface DuplicateMonitor {
+    id: string;
+    name: string;
+    groupId: string;
+    url: string;
+}
+
+// Thrown when another monitor already checks the same URL; resend with allowDuplicateUrl to create it anyway.
+export class DuplicateMonitorError extends Error {
+    duplicateOf: DuplicateMonitor;
+
+    constructor(message: string, duplicateOf: DuplicateMonitor) {
+        super(message);
+        this.duplicateOf = duplicateOf;
+    }
 }
 
 async function createMonitorReq(payload: CreateMonitorPayload) {
@@ -102,6 +120,10 @@ async function createMonitorReq(payload: CreateMonitorPayload) {
         body: JSON.stringify(payload),
         credentials: 'include'
     });
+    if (res.status === 409) {
+        const data = await res.json().catch(() => null);
+        if (data?.duplicateOf) throw new DuplicateMonitorError(data.error, data.duplicateOf);
+    }
     if (!res.ok) throw new Error("Failed to create monitor");
     return res.json();
 }
@@ -116,3 +138,52 @@ export function useCreateMonitorMutation() {
         },
     });
 }
+
+// Preview Monitor: a one-off check of an unsaved configuration
+interface PreviewMonitorPayload {
+    url: string;
+    latencyThreshold?: number;
+    requestConfig?: RequestConfig;
+}
+
+export interface MonitorPreviewTLS {
+    version: string;
+    cipherSuite: string;
+    subject: string;
+    issuer: string;
+    dnsNames: string[];
+    notBefore: string;
+    notAfter: string;
+    daysRemaining: number;
+}
+
+export interface MonitorPreview {
+    status: "up" | "degraded" | "down";
+    latency: number;
+    statusCode: number;
+    resolvedIp?: string;
+    error?: string;
+    errorKind?: string;
+    degradedReason?: string;
+    tls?: MonitorPreviewTLS;
+    timing?: CheckTiming;
+    checkedAt: string;
+}
+
+async function previewMonitorReq(payload: PreviewMonitorPayload): Promise<MonitorPreview> {
+    const res = await fetch(`${API_URL}/api/monitors/preview`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(payload),
+        credentials: 'include'
+    });
+    if (!res.ok) {
+        const data = await res.json().catch(() => null);
+        throw new Error(data?.error || "Failed to run preview check");
+    }
+    return res.json();
+}
+
+export function usePreviewMonitorMutation() {
+    return useMutation({ mutationFn: previewMonitorReq });
+}
@@ -28,6 +28,11 @@ export interface StatusPageUpdatePayload {
     headerContent?: 'logo-title' | 'logo-only' | 'title-only';
     headerAlignment?: 'left' | 'center' | 'right';
     headerArrangement?: 'stacked' | 'inline';
+    locale?: string;
+    noindex?: boolean;
+    metaDescription?: string;
+    ogImageUrl?: string;
+    showSla?: boolean;
 }
 
 async function toggleStatusPageReq(payload: StatusPageUpdatePayload) {
@@ -42,6 +47,37 @@ async function toggleStatusPageReq(payload: StatusPageUpdatePayload) {
     return res.json();
 }
 
+export interface StatusPageCreatePayload {
+    slug: string;
+    title: string;
+    groupId?: string;
+    public: boolean;
+    enabled: boolean;
+}
+
+async function createStatusPageReq(payload: StatusPageCreatePayload): Promise<StatusPage> {
+    const res = await fetch(`${API_URL}/api/status-pages`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(payload),
+        credentials: 'include'
+    });
+    if (!res.ok) {
+        const data = await res.json().catch(() => ({}));
+        throw new Error(data.error || "Failed to create status page");
+    }
+    return res.json();
+}
+
+async function deleteStatusPageReq(slug: string) {
+    const res = await fetch(`${API_URL}/api/status-pages/${slug}`, {
+        method: "DELETE",
+        credentials: 'include'
+    });
+    if (!res.ok) throw new Error("Failed to delete status page");
+    return res.json();
+}
+
 export function useStatusPagesQuery() {
     return useQuery({
         queryKey: ["status-pages"],
@@ -59,3 +95,25 @@ export function useToggleStatusPageMutation() {
         },
     });
 }
+
+export function useCreateStatusPageMutation() {
+    const queryClient = useQueryClient();
+
+    return useMutation({
+        mutationFn: createStatusPageReq,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ["status-pages"] });
+        },
+    });
+}
+
+export function useDeleteStatusPageMutation() {
+    const queryClient = useQueryClient();
+
+    return useMutation({
+        mutationFn: deleteStatusPageReq,
+        onSuccess: () => {
+            queryClient.invalidateQueries({ queryKey: ["status-pages"] });
+        },
+    });
+}
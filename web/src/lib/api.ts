@@ -203,11 +203,26 @@ export interface HealthResponse {
   timestamp: string;
 }
 
+// ApiError carries the machine-readable code of an API error response, e.g. "monitor_not_found",
+// and for a 422 the problem with each invalid field.
+export class ApiError extends Error {
+  constructor(message: string, readonly status: number, readonly code?: string, readonly fields?: Record<string, string>) {
+    super(message);
+    this.name = "ApiError";
+  }
+}
+
 async function request<T>(path: string): Promise<T> {
   const response = await fetch(`${API_PREFIX}${path}`);
   if (!response.ok) {
     const text = await response.text();
-    throw new Error(text || `Request failed with ${response.status}`);
+    try {
+      const body = JSON.parse(text) as { error?: string; code?: string; fields?: Record<string, string> };
+      throw new ApiError(body.error || `Request failed with ${response.status}`, response.status, body.code, body.fields);
+    } catch (e) {
+      if (e instanceof ApiError) throw e;
+      throw new ApiError(text || `Request failed with ${response.status}`, response.status);
+    }
   }
   return response.json();
 }
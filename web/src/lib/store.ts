@@ -4,7 +4,7 @@ import { queryClient } from "./queryClient";
 
 export interface MonitorEvent {
     id: string;
-    type: 'up' | 'down' | 'degraded' | 'ssl_expiring' | 'flapping' | 'stabilized';
+    type: 'up' | 'down' | 'degraded' | 'ssl_expiring' | 'flapping' | 'stabilized' | 'slo_burn' | 'anomaly';
     timestamp: string;
     message: string;
 }
@@ -15,8 +15,10 @@ export interface NotificationChannel {
     name: string;
     config: {
         webhookUrl?: string;
+        locale?: string;
     };
     enabled: boolean;
+    disabledReason?: string;
 }
 
 export interface User {
@@ -27,6 +29,16 @@ export interface User {
     isAuthenticated: boolean;
     timezone?: string;
     ssoProvider?: string;
+    readOnlyNotice?: string; // Set while the API rejects changes
+}
+
+export interface CheckTiming {
+    dnsMs: number;
+    connectMs: number;
+    tlsMs: number;
+    ttfbMs: number;
+    downloadMs: number;
+    bodyBytes: number;
 }
 
 export interface HistoryPoint {
@@ -34,6 +46,7 @@ export interface HistoryPoint {
     latency: number;
     timestamp: string;
     statusCode: number;
+    timing?: CheckTiming;
 }
 
 export interface RequestConfig {
@@ -44,6 +57,13 @@ export interface RequestConfig {
     followRedirects?: boolean;
     acceptedStatusCodes?: string;
     retryCount?: number;
+    freshConnection?: boolean;
+    expectedHeaders?: HeaderAssertion[];
+}
+
+export interface HeaderAssertion {
+    name: string;
+    value?: string;
 }
 
 export interface Monitor {
@@ -61,6 +81,11 @@ export interface Monitor {
     notificationCooldownMinutes?: number;
     latencyThreshold?: number;
     requestConfig?: RequestConfig;
+    dependsOn?: string[];
+    affectedBy?: string; // ID of the down dependency blamed for this monitor being down
+    type?: 'http' | 'external' | 'agent' | 'composite';
+    members?: string[]; // Composite monitors: member monitor IDs
+    minUp?: number; // Composite monitors: members that must be up
 }
 
 export interface Group {
@@ -74,6 +99,7 @@ export interface IncidentUpdate {
     status: string;
     message: string;
     createdAt: string;
+    editedAt?: string;
 }
 
 export interface Incident {
@@ -88,11 +114,27 @@ export interface Incident {
     affectedGroups: string[];
     source?: 'auto' | 'manual';
     outageId?: number;
+    issueKey?: string;
+    issueUrl?: string;
     public?: boolean;
+    pendingPublication?: boolean;
     updates?: IncidentUpdate[];
+    affectedGroupDetails?: IncidentAffectedGroup[];
     duration?: string;
 }
 
+export interface IncidentAffectedGroup {
+    id: string;
+    name: string;
+    monitors: {
+        id: string;
+        name: string;
+        status: 'up' | 'degraded' | 'down' | 'paused';
+        latency: number;
+        lastCheck?: string;
+    }[];
+}
+
 export interface OverviewGroup {
     id: string;
     name: string;
@@ -133,6 +175,11 @@ export interface StatusPage {
     headerContent?: 'logo-title' | 'logo-only' | 'title-only';
     headerAlignment?: 'left' | 'center' | 'right';
     headerArrangement?: 'stacked' | 'inline';
+    locale?: string;
+    noindex?: boolean;
+    metaDescription?: string;
+    ogImageUrl?: string;
+    showSla?: boolean;
 }
 
 export interface StatusPageConfig {
@@ -148,6 +195,20 @@ export interface StatusPageConfig {
     headerContent: 'logo-title' | 'logo-only' | 'title-only';
     headerAlignment: 'left' | 'center' | 'right';
     headerArrangement: 'stacked' | 'inline';
+    locale?: string;
+    labels?: Record<string, string>;
+    seo?: {
+        noindex: boolean;
+        metaDescription: string;
+        ogImageUrl: string;
+    };
+    showSla?: boolean;
+    sla?: {
+        uptimePercent: number | null;
+        days: number;
+        totalChecks: number;
+        methodology: string;
+    };
 }
 
 export interface SystemIncident {
@@ -178,6 +239,16 @@ export interface APIKey {
     id: number;
     keyPrefix: string;
     name: string;
+    allowedCidrs?: string[];
+    createdAt: string;
+    lastUsed?: string;
+}
+
+export interface PersonalToken {
+    id: number;
+    userId: number;
+    tokenPrefix: string;
+    name: string;
     createdAt: string;
     lastUsed?: string;
 }
@@ -204,8 +275,10 @@ interface MonitorStore {
     user: User | null;
     isAuthChecked: boolean;
     isSetupComplete: boolean;
+    setupTokenRequired: boolean;
 
     apiKeys: APIKey[];
+    personalTokens: PersonalToken[];
 
     // Actions
     checkAuth: () => Promise<void>;
@@ -234,6 +307,8 @@ interface MonitorStore {
     fetchIncidents: () => Promise<void>;
     promoteOutage: (outageId: string, data: { title: string; description: string; severity: string; affectedGroups: string[] }) => Promise<Incident | null>;
     addIncidentUpdate: (incidentId: string, status: string, message: string) => Promise<IncidentUpdate | null>;
+    editIncidentUpdate: (incidentId: string, updateId: number, status: string, message: string) => Promise<IncidentUpdate | null>;
+    deleteIncidentUpdate: (incidentId: string, updateId: number) => Promise<boolean>;
     setIncidentVisibility: (incidentId: string, isPublic: boolean) => Promise<boolean>;
     getIncidentWithUpdates: (incidentId: string) => Promise<Incident | null>;
     addChannel: (channel: Omit<NotificationChannel, 'id' | 'enabled'>) => Promise<void>;
@@ -252,8 +327,11 @@ interface MonitorStore {
 
     // API Keys
     fetchAPIKeys: () => Promise<APIKey[]>;
-    createAPIKey: (name: string) => Promise<string | null>;
+    createAPIKey: (name: string, allowedCidrs?: string[]) => Promise<string | null>;
     deleteAPIKey: (id: number) => Promise<void>;
+    fetchPersonalTokens: () => Promise<PersonalToken[]>;
+    createPersonalToken: (name: string) => Promise<string | null>;
+    deletePersonalToken: (id: number) => Promise<void>;
     resetDatabase: () => Promise<boolean>;
 
     // Settings
@@ -272,6 +350,7 @@ export interface SetupPayload {
     username?: string;
     password?: string;
     timezone?: string;
+    setupToken?: string;
 }
 
 export const useMonitorStore = create<MonitorStore>((set, get) => ({
@@ -283,7 +362,9 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
     user: null,
     isAuthChecked: false,
     isSetupComplete: false,
+    setupTokenRequired: false,
     apiKeys: [],
+    personalTokens: [],
     settings: null,
 
     // Sync Actions
@@ -296,7 +377,7 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
             const res = await fetch("/api/setup/status");
             if (res.ok) {
                 const data = await res.json();
-                set({ isSetupComplete: data.isSetup });
+                set({ isSetupComplete: data.isSetup, setupTokenRequired: data.setupTokenRequired === true });
                 return data.isSetup;
             }
         } catch (e) {
@@ -428,7 +509,8 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
                         avatar: data.user.avatar,
                         isAuthenticated: true,
                         timezone: data.user.timezone,
-                        ssoProvider: data.user.ssoProvider || ""
+                        ssoProvider: data.user.ssoProvider || "",
+                        readOnlyNotice: data.readOnly?.notice
                     },
                     isAuthChecked: true
                 });
@@ -922,6 +1004,47 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
         return null;
     },
 
+    editIncidentUpdate: async (incidentId, updateId, status, message) => {
+        try {
+            const res = await fetch(`/api/incidents/${incidentId}/updates/${updateId}`, {
+                method: 'PUT',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ status, message }),
+                credentials: 'include'
+            });
+            if (res.ok) {
+                const update = await res.json();
+                toast({ title: "Update Edited", description: "Incident update corrected." });
+                return update;
+            } else {
+                toast({ title: "Error", description: "Failed to edit update.", variant: "destructive" });
+            }
+        } catch (e) {
+            console.error(e);
+            toast({ title: "Error", description: "Failed to edit update.", variant: "destructive" });
+        }
+        return null;
+    },
+
+    deleteIncidentUpdate: async (incidentId, updateId) => {
+        try {
+            const res = await fetch(`/api/incidents/${incidentId}/updates/${updateId}`, {
+                method: 'DELETE',
+                credentials: 'include'
+            });
+            if (res.ok) {
+                toast({ title: "Update Deleted", description: "Incident update removed from the timeline." });
+                return true;
+            } else {
+                toast({ title: "Error", description: "Failed to delete update.", variant: "destructive" });
+            }
+        } catch (e) {
+            console.error(e);
+            toast({ title: "Error", description: "Failed to delete update.", variant: "destructive" });
+        }
+        return false;
+    },
+
     setIncidentVisibility: async (incidentId, isPublic) => {
         try {
             const res = await fetch(`/api/incidents/${incidentId}/visibility`, {
@@ -1065,12 +1188,12 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
         return [];
     },
 
-    createAPIKey: async (name: string) => {
+    createAPIKey: async (name: string, allowedCidrs?: string[]) => {
         try {
             const res = await fetch("/api/api-keys", {
                 method: "POST",
                 headers: { "Content-Type": "application/json" },
-                body: JSON.stringify({ name }),
+                body: JSON.stringify({ name, allowedCidrs }),
                 credentials: "include"
             });
             if (res.ok) {
@@ -1094,6 +1217,49 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
         }
     },
 
+    fetchPersonalTokens: async () => {
+        try {
+            const res = await fetch("/api/auth/me/tokens", { credentials: "include" });
+            if (res.ok) {
+                const data = await res.json();
+                set({ personalTokens: data.tokens || [] });
+                return data.tokens || [];
+            }
+        } catch (error) {
+            console.error("Failed to fetch personal tokens:", error);
+        }
+        return [];
+    },
+
+    createPersonalToken: async (name: string) => {
+        try {
+            const res = await fetch("/api/auth/me/tokens", {
+                method: "POST",
+                headers: { "Content-Type": "application/json" },
+                body: JSON.stringify({ name }),
+                credentials: "include"
+            });
+            if (res.ok) {
+                const data = await res.json();
+                return data.token;
+            }
+        } catch (error) {
+            console.error("Failed to create personal token:", error);
+        }
+        return null;
+    },
+
+    deletePersonalToken: async (id: number) => {
+        try {
+            await fetch(`/api/auth/me/tokens/${id}`, {
+                method: "DELETE",
+                credentials: "include"
+            });
+        } catch (error) {
+            console.error("Failed to delete personal token:", error);
+        }
+    },
+
     resetDatabase: async () => {
         try {
             const res = await fetch("/api/admin/reset", {
@@ -1124,22 +1290,27 @@ export const useMonitorStore = create<MonitorStore>((set, get) => ({
     },
 
     updateSettings: async (newSettings: Partial<Settings>) => {
-        try {
-            await fetch('/api/settings', {
-                method: 'PATCH',
-                headers: { 'Content-Type': 'application/json' },
-                body: JSON.stringify(newSettings),
-                credentials: 'include'
-            });
-            set((state) => ({
-                settings: {
-                    ...(state.settings || { latency_threshold: "1000", data_retention_days: "365" }),
-                    ...newSettings
-                }
-            }));
-        } catch (error) {
-            console.error('Failed to update settings:', error);
+        const res = await fetch('/api/settings', {
+            method: 'PATCH',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(newSettings),
+            credentials: 'include'
+        });
+        if (!res.ok) {
+            // Rejected settings come back as { error, fields: { key: reason } }
+            const data = await res.json().catch(() => null);
+            const fields = data?.fields as Record<string, string> | undefined;
+            const detail = fields
+                ? Object.entries(fields).map(([key, reason]) => `${key} ${reason}`).join("; ")
+                : data?.error;
+            throw new Error(detail || "Failed to update settings");
         }
+        set((state) => ({
+            settings: {
+                ...(state.settings || { latency_threshold: "1000", data_retention_days: "365" }),
+                ...newSettings
+            }
+        }));
     },
 
     fetchSystemStats: async () => {
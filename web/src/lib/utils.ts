@@ -141,6 +141,16 @@ export function sanitizeImageUrl(url: string | undefined): string {
 }
 
 // Timezone-aware date formatting
+// Formats a duration in seconds as e.g. "<1m", "45m", "2h" or "2h 5m".
+export const formatDuration = (seconds: number) => {
+  const mins = Math.floor(seconds / 60);
+  if (mins < 1) return '<1m';
+  const h = Math.floor(mins / 60);
+  const m = mins % 60;
+  if (h === 0) return `${m}m`;
+  return m === 0 ? `${h}h` : `${h}h ${m}m`;
+};
+
 export const formatDate = (date: string | Date | number, timezone: string = 'UTC') => {
   if (!date) return '';
   const d = new Date(date);